@@ -0,0 +1,208 @@
+package alertingrules
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngclient "github.com/grafana/grafana/pkg/services/ngalert/client"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning/client"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// Client reads and writes the alert rule groups of Grafana.
+type Client interface {
+	// ExportRuleGroups returns the rule groups of the folders, or of all folders if none is given, in the format of
+	// the provisioning files. The groups refer to their folders by UID.
+	ExportRuleGroups(ctx context.Context, orgID int64, folderUIDs []string) (definitions.AlertingFileExport, error)
+	// GetRuleGroup returns the rule group, or models.ErrAlertRuleGroupNotFound if it does not exist.
+	GetRuleGroup(ctx context.Context, orgID int64, folderUID string, group string) (models.AlertRuleGroup, error)
+	// ReplaceRuleGroup creates or replaces the rule group. The rules are provisioned, so that they cannot be edited in
+	// the UI, unless disableProvenance is set.
+	ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, disableProvenance bool) error
+}
+
+// httpClient calls the provisioning API of a running Grafana server.
+type httpClient struct {
+	cfg client.Config
+}
+
+// NewHTTPClient returns a Client that calls the provisioning API of the Grafana server at the URL, authenticated with
+// the token of a service account.
+func NewHTTPClient(requester ngclient.Requester, grafanaURL string, token string) (Client, error) {
+	u, err := url.Parse(grafanaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL of Grafana: %w", err)
+	}
+	return &httpClient{cfg: client.Config{URL: u, Token: token, Requester: requester}}, nil
+}
+
+// client returns a client of the provisioning API for the organization.
+func (c *httpClient) client(orgID int64, disableProvenance bool) (*client.Client, error) {
+	cfg := c.cfg
+	cfg.OrgID = orgID
+	cfg.DisableProvenance = disableProvenance
+	return client.New(cfg)
+}
+
+func (c *httpClient) ExportRuleGroups(ctx context.Context, orgID int64, folderUIDs []string) (definitions.AlertingFileExport, error) {
+	cl, err := c.client(orgID, false)
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+	return cl.ExportRuleGroups(ctx, folderUIDs...)
+}
+
+func (c *httpClient) GetRuleGroup(ctx context.Context, orgID int64, folderUID string, group string) (models.AlertRuleGroup, error) {
+	cl, err := c.client(orgID, false)
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	result, err := cl.GetRuleGroup(ctx, folderUID, group)
+	if client.IsNotFound(err) {
+		return models.AlertRuleGroup{}, models.ErrAlertRuleGroupNotFound.Errorf("")
+	}
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	return api.AlertRuleGroupFromApiAlertRuleGroup(result)
+}
+
+func (c *httpClient) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, disableProvenance bool) error {
+	cl, err := c.client(orgID, disableProvenance)
+	if err != nil {
+		return err
+	}
+	_, err = cl.ReplaceRuleGroup(ctx, api.ApiAlertRuleGroupFromAlertRuleGroup(group))
+	return err
+}
+
+// dbClient reads and writes the rules directly in the database of Grafana, for servers that cannot be reached.
+type dbClient struct {
+	store *store.DBstore
+	rules *provisioning.AlertRuleService
+}
+
+// NewDBClient returns a Client that reads and writes the rules in the database with the provisioning service of the
+// server. The folders of the rules are not checked, and the quotas of the organizations are not enforced.
+func NewDBClient(cfg *setting.Cfg, sqlStore db.DB, features featuremgmt.FeatureToggles) (Client, error) {
+	logger := log.New("cli.alerting")
+	dbStore := &store.DBstore{
+		Cfg:            cfg.UnifiedAlerting,
+		FeatureToggles: features,
+		SQLStore:       sqlStore,
+		Logger:         logger,
+	}
+	rules, err := provisioning.NewAlertRuleService(provisioning.AlertRuleServiceConfig{
+		RuleStore:            dbStore,
+		ProvenanceStore:      dbStore,
+		DashboardService:     dbFolders{sqlStore: sqlStore},
+		Quotas:               noQuotas{},
+		Xact:                 dbStore,
+		NotificationSettings: notifier.NewNotificationSettingsValidationService(dbStore),
+		Log:                  logger,
+		KV:                   kvstore.ProvideService(sqlStore),
+		TrashStore:           dbStore,
+		Settings:             cfg.UnifiedAlerting,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dbClient{store: dbStore, rules: rules}, nil
+}
+
+func (c *dbClient) ExportRuleGroups(ctx context.Context, orgID int64, folderUIDs []string) (definitions.AlertingFileExport, error) {
+	ruleList, err := c.store.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID, NamespaceUIDs: folderUIDs})
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+	groups := make(map[models.AlertRuleGroupKey][]models.AlertRule)
+	for _, r := range ruleList {
+		groups[r.GetGroupKey()] = append(groups[r.GetGroupKey()], *r)
+	}
+	result := make([]models.AlertRuleGroupWithFolderTitle, 0, len(groups))
+	for key, rules := range groups {
+		// The titles of the folders are not known without the folder service, so the groups refer to them by UID.
+		result = append(result, models.NewAlertRuleGroupWithFolderTitle(key, rules, ""))
+	}
+	models.SortAlertRuleGroupWithFolderTitle(result)
+	e, err := api.AlertingFileExportFromAlertRuleGroupWithFolderTitle(result)
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+	api.KeyAlertingFileExportByFolderUID(&e)
+	return e, nil
+}
+
+func (c *dbClient) GetRuleGroup(ctx context.Context, orgID int64, folderUID string, group string) (models.AlertRuleGroup, error) {
+	return c.rules.GetRuleGroup(ctx, orgID, folderUID, group)
+}
+
+func (c *dbClient) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, disableProvenance bool) error {
+	provenance := models.ProvenanceAPI
+	if disableProvenance {
+		provenance = models.ProvenanceNone
+	}
+	return c.rules.ReplaceRuleGroup(ctx, orgID, group, 0, provenance)
+}
+
+// dbFolders reads the folders of the rules in the database, as the dashboard service of the server does.
+type dbFolders struct {
+	sqlStore db.DB
+}
+
+func (f dbFolders) GetDashboard(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+	var dash dashboards.Dashboard
+	err := f.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		has, err := sess.Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Get(&dash)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return dashboards.ErrDashboardNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dash, nil
+}
+
+func (f dbFolders) GetDashboards(ctx context.Context, query *dashboards.GetDashboardsQuery) ([]*dashboards.Dashboard, error) {
+	result := make([]*dashboards.Dashboard, 0, len(query.DashboardUIDs))
+	if len(query.DashboardUIDs) == 0 {
+		return result, nil
+	}
+	err := f.sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.In("uid", query.DashboardUIDs)
+		if query.OrgID > 0 {
+			q = q.Where("org_id = ?", query.OrgID)
+		}
+		return q.Find(&result)
+	})
+	return result, err
+}
+
+// noQuotas does not enforce quotas, whose usage is computed by the services of the running server.
+type noQuotas struct{}
+
+func (noQuotas) CheckQuotaReached(context.Context, quota.TargetSrv, *quota.ScopeParameters) (bool, error) {
+	return false, nil
+}
+
+func (noQuotas) GetQuotasByScope(context.Context, quota.Scope, int64) ([]quota.QuotaDTO, error) {
+	return nil, quota.ErrDisabled
+}
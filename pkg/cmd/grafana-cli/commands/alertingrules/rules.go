@@ -0,0 +1,197 @@
+package alertingrules
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// fieldsToIgnoreInDiff are the fields of the rules that are set by the server, and that files cannot change.
+var fieldsToIgnoreInDiff = append(store.AlertRuleFieldsToIgnoreInDiff[:], "RuleGroupIndex", "BakeUntil")
+
+// ExportRules writes the rule groups of the organization, in the format of the provisioning files that the other
+// commands read.
+func ExportRules(c utils.CommandLine, client Client) error {
+	e, err := client.ExportRuleGroups(context.Background(), int64(c.Int("org-id")), c.StringSlice("folder-uid"))
+	if err != nil {
+		return fmt.Errorf("failed to export the alert rules: %w", err)
+	}
+
+	var b []byte
+	switch format := c.String("format"); format {
+	case "", "yaml":
+		b, err = yaml.Marshal(e)
+	case "json":
+		b, err = json.MarshalIndent(e, "", "  ")
+	default:
+		return fmt.Errorf("unsupported format %q, expected yaml or json", format)
+	}
+	if err != nil {
+		return err
+	}
+
+	if output := c.String("output"); output != "" {
+		if err := os.WriteFile(output, b, 0600); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		logger.Infof("Exported %d rule groups to %s\n", len(e.Groups), output)
+		return nil
+	}
+	logger.Info(string(b))
+	return nil
+}
+
+// ApplyRules replaces the rule groups of the file. The rules of the groups that are not in the file are deleted.
+func ApplyRules(c utils.CommandLine, client Client) error {
+	groups, err := readRuleGroups(c)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		if err := client.ReplaceRuleGroup(context.Background(), group.OrgID, *group.AlertRuleGroup, c.Bool("disable-provenance")); err != nil {
+			return fmt.Errorf("failed to apply rule group '%s' of folder '%s': %w", group.Title, group.FolderUID, err)
+		}
+		logger.Infof("Applied rule group '%s' of folder '%s' with %d rules\n", group.Title, group.FolderUID, len(group.Rules))
+	}
+	return nil
+}
+
+// DiffRules prints the rules that applying the file would add, update and delete.
+func DiffRules(c utils.CommandLine, client Client) error {
+	groups, err := readRuleGroups(c)
+	if err != nil {
+		return err
+	}
+	for _, group := range groups {
+		existing, err := client.GetRuleGroup(context.Background(), group.OrgID, group.FolderUID, group.Title)
+		if err != nil && !errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+			return fmt.Errorf("failed to get rule group '%s' of folder '%s': %w", group.Title, group.FolderUID, err)
+		}
+		changes := diffRuleGroup(existing, group)
+		if len(changes) == 0 {
+			logger.Infof("Rule group '%s' of folder '%s' is up to date\n", group.Title, group.FolderUID)
+			continue
+		}
+		logger.Infof("Rule group '%s' of folder '%s':\n", group.Title, group.FolderUID)
+		for _, change := range changes {
+			logger.Infof("  %s\n", change)
+		}
+	}
+	return nil
+}
+
+// ValidateRules checks the rule groups of the file like the server does before it saves them. The folders of the
+// groups are not checked.
+func ValidateRules(c utils.CommandLine) error {
+	groups, err := readRuleGroups(c)
+	if err != nil {
+		return err
+	}
+	cfg := setting.UnifiedAlertingSettings{BaseInterval: setting.SchedulerBaseInterval}
+	for _, group := range groups {
+		for _, rule := range groupRules(group) {
+			if err := rule.ValidateAlertRule(cfg); err != nil {
+				return fmt.Errorf("invalid rule '%s' of rule group '%s': %w", rule.Title, group.Title, err)
+			}
+		}
+	}
+	logger.Infof("%d rule groups are valid\n", len(groups))
+	return nil
+}
+
+// readRuleGroups reads the rule groups of the file given as argument. The commands change the rules directly, without
+// the folder service, so the groups must refer to their folders by UID.
+func readRuleGroups(c utils.CommandLine) ([]models.AlertRuleGroupWithFolderTitle, error) {
+	filename := c.Args().First()
+	if filename == "" {
+		return nil, errors.New("missing path to the file of the alert rules")
+	}
+	file, err := alerting.ReadFile(filename, c.Bool("strict"), log.New("cli.alerting"))
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range file.Groups {
+		if group.FolderUID == "" {
+			return nil, fmt.Errorf("rule group '%s' must refer to its folder with folderUid", group.Title)
+		}
+	}
+	return file.Groups, nil
+}
+
+// groupRules returns the rules of the group with the fields that the server sets from the group.
+func groupRules(group models.AlertRuleGroupWithFolderTitle) []models.AlertRule {
+	rules := make([]models.AlertRule, 0, len(group.Rules))
+	for _, r := range group.Rules {
+		rule := models.CopyRule(&r)
+		rule.OrgID = group.OrgID
+		rule.NamespaceUID = group.FolderUID
+		rule.RuleGroup = group.Title
+		rule.IntervalSeconds = group.Interval
+		rule.DataAvailabilityPeriod = group.DataAvailabilityPeriod
+		rule.DataAvailabilityDelay = group.DataAvailabilityDelay
+		rule.ShardAffinity = group.ShardAffinity
+		rule.IncidentHooks = group.IncidentHooks
+		// Errors are reported by the server when the group is applied.
+		_ = rule.SetDashboardAndPanelFromAnnotations()
+		rules = append(rules, *rule)
+	}
+	return rules
+}
+
+// diffRuleGroup returns the changes to the rules of the existing group that replacing it with the group would make,
+// one line per rule.
+func diffRuleGroup(existing models.AlertRuleGroup, group models.AlertRuleGroupWithFolderTitle) []string {
+	existingByUID := make(map[string]models.AlertRule, len(existing.Rules))
+	for _, r := range existing.Rules {
+		existingByUID[r.UID] = r
+	}
+	var changes []string
+	for _, rule := range groupRules(group) {
+		e, ok := existingByUID[rule.UID]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("+ %s (%s)", rule.UID, rule.Title))
+			continue
+		}
+		delete(existingByUID, rule.UID)
+		diff := e.Diff(&rule, fieldsToIgnoreInDiff...)
+		if len(diff) == 0 {
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("~ %s (%s): %s", rule.UID, rule.Title, strings.Join(diffFields(diff.Paths()), ", ")))
+	}
+	for _, r := range existing.Rules {
+		if _, ok := existingByUID[r.UID]; ok {
+			changes = append(changes, fmt.Sprintf("- %s (%s)", r.UID, r.Title))
+		}
+	}
+	return changes
+}
+
+// diffFields returns the top-level fields of the paths of a diff, without duplicates.
+func diffFields(paths []string) []string {
+	var fields []string
+	seen := make(map[string]struct{}, len(paths))
+	for _, p := range paths {
+		field, _, _ := strings.Cut(p, ".")
+		field, _, _ = strings.Cut(field, "[")
+		if _, ok := seen[field]; ok {
+			continue
+		}
+		seen[field] = struct{}{}
+		fields = append(fields, field)
+	}
+	return fields
+}
@@ -0,0 +1,165 @@
+package alertingrules
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const rulesFile = `apiVersion: 1
+groups:
+  - orgId: 1
+    name: group
+    folderUid: folder
+    interval: 1m
+    rules:
+      - uid: rule-a
+        title: A
+        condition: A
+        for: 0s
+        data:
+          - refId: A
+            datasourceUid: __expr__
+            model:
+              type: math
+              expression: "1 > 0"
+      - uid: rule-b
+        title: B
+        condition: A
+        for: 0s
+        data:
+          - refId: A
+            datasourceUid: __expr__
+            model:
+              type: math
+              expression: "1 > 0"
+`
+
+type fakeClient struct {
+	groups   map[string]models.AlertRuleGroup
+	replaced []models.AlertRuleGroup
+	// provenanceDisabled tells whether the groups were replaced with the provenance disabled.
+	provenanceDisabled []bool
+}
+
+func (c *fakeClient) ExportRuleGroups(context.Context, int64, []string) (definitions.AlertingFileExport, error) {
+	return definitions.AlertingFileExport{}, nil
+}
+
+func (c *fakeClient) GetRuleGroup(_ context.Context, _ int64, folderUID string, group string) (models.AlertRuleGroup, error) {
+	g, ok := c.groups[folderUID+"/"+group]
+	if !ok {
+		return models.AlertRuleGroup{}, models.ErrAlertRuleGroupNotFound.Errorf("")
+	}
+	return g, nil
+}
+
+func (c *fakeClient) ReplaceRuleGroup(_ context.Context, _ int64, group models.AlertRuleGroup, disableProvenance bool) error {
+	c.replaced = append(c.replaced, group)
+	c.provenanceDisabled = append(c.provenanceDisabled, disableProvenance)
+	return nil
+}
+
+func newCommandLine(t *testing.T, file string, flags map[string]string) utils.CommandLine {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(file), 0600))
+	flagSet := flag.NewFlagSet("test", 0)
+	for name, value := range flags {
+		flagSet.String(name, value, "")
+	}
+	require.NoError(t, flagSet.Parse([]string{path}))
+	return &utils.ContextCommandLine{Context: cli.NewContext(&cli.App{Name: "test"}, flagSet, nil)}
+}
+
+func TestApplyRules(t *testing.T) {
+	client := &fakeClient{}
+	require.NoError(t, ApplyRules(newCommandLine(t, rulesFile, map[string]string{"disable-provenance": "true"}), client))
+
+	require.Len(t, client.replaced, 1)
+	require.Equal(t, "folder", client.replaced[0].FolderUID)
+	require.Equal(t, "group", client.replaced[0].Title)
+	require.Equal(t, int64(60), client.replaced[0].Interval)
+	require.Len(t, client.replaced[0].Rules, 2)
+	require.Equal(t, []bool{true}, client.provenanceDisabled)
+}
+
+func TestApplyRulesRequiresFolderUID(t *testing.T) {
+	file := `apiVersion: 1
+groups:
+  - orgId: 1
+    name: group
+    folder: Folder
+    interval: 1m
+`
+	client := &fakeClient{}
+	require.ErrorContains(t, ApplyRules(newCommandLine(t, file, nil), client), "must refer to its folder with folderUid")
+	require.Empty(t, client.replaced)
+}
+
+func TestDiffRuleGroup(t *testing.T) {
+	groups, err := readRuleGroups(newCommandLine(t, rulesFile, nil))
+	require.NoError(t, err)
+	group := groups[0]
+
+	t.Run("all rules are added when the group does not exist", func(t *testing.T) {
+		require.Equal(t, []string{"+ rule-a (A)", "+ rule-b (B)"}, diffRuleGroup(models.AlertRuleGroup{}, group))
+	})
+
+	t.Run("unchanged group has no changes", func(t *testing.T) {
+		existing := models.AlertRuleGroup{Rules: groupRules(group)}
+		for i := range existing.Rules {
+			existing.Rules[i].ID = int64(i + 1)
+			existing.Rules[i].Version = 3
+			existing.Rules[i].RuleGroupIndex = i + 1
+		}
+		require.Empty(t, diffRuleGroup(existing, group))
+	})
+
+	t.Run("changed, added and deleted rules are listed", func(t *testing.T) {
+		rules := groupRules(group)
+		rules[0].Title = "Old A"
+		rules[0].Labels = map[string]string{"team": "a"}
+		rules[1].UID = "rule-c"
+		rules[1].Title = "C"
+		existing := models.AlertRuleGroup{Rules: rules}
+		require.Equal(t, []string{
+			"~ rule-a (A): Title, Labels",
+			"+ rule-b (B)",
+			"- rule-c (C)",
+		}, diffRuleGroup(existing, group))
+	})
+}
+
+func TestValidateRules(t *testing.T) {
+	require.NoError(t, ValidateRules(newCommandLine(t, rulesFile, nil)))
+
+	file := `apiVersion: 1
+groups:
+  - orgId: 1
+    name: group
+    folderUid: folder
+    interval: 15s
+    rules:
+      - uid: rule-a
+        title: A
+        condition: A
+        for: 0s
+        data:
+          - refId: A
+            datasourceUid: __expr__
+            model:
+              type: math
+              expression: "1 > 0"
+`
+	require.ErrorIs(t, ValidateRules(newCommandLine(t, file, nil)), models.ErrAlertRuleFailedValidation)
+}
@@ -6,9 +6,11 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/alertingrules"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/datamigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/commands/secretsmigrations"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/services"
 	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/server"
@@ -72,6 +74,30 @@ func initializeRunner(cmd *utils.ContextCommandLine) (server.Runner, error) {
 	return runner, nil
 }
 
+// runAlertingRulesCommand runs the command with a client of the provisioning API of the server at the URL of the url
+// flag, or with a client of the database of the configured server if the flag is not set, e.g. on air-gapped servers.
+func runAlertingRulesCommand(command func(commandLine utils.CommandLine, client alertingrules.Client) error) func(context *cli.Context) error {
+	return func(context *cli.Context) error {
+		cmd := &utils.ContextCommandLine{Context: context}
+		if url := cmd.String("url"); url != "" {
+			client, err := alertingrules.NewHTTPClient(&services.HttpClient, url, cmd.String("token"))
+			if err != nil {
+				return err
+			}
+			return command(cmd, client)
+		}
+		runner, err := initializeRunner(cmd)
+		if err != nil {
+			return fmt.Errorf("%v: %w", "failed to initialize runner", err)
+		}
+		client, err := alertingrules.NewDBClient(runner.Cfg, runner.SQLStore, runner.Features)
+		if err != nil {
+			return err
+		}
+		return command(cmd, client)
+	}
+}
+
 func runPluginCommand(command func(commandLine utils.CommandLine) error) func(context *cli.Context) error {
 	return func(context *cli.Context) error {
 		cmd := &utils.ContextCommandLine{Context: context}
@@ -226,6 +252,83 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var alertingRulesClientFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "url",
+		Usage:   "URL of the Grafana server. The database of the configured server is used if it is not set",
+		EnvVars: []string{"GF_ALERTING_URL"},
+	},
+	&cli.StringFlag{
+		Name:    "token",
+		Usage:   "Token of the service account used with --url",
+		EnvVars: []string{"GF_ALERTING_TOKEN"},
+	},
+}
+
+var alertingRulesFileFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "strict",
+		Usage: "Fail if the file has unknown fields instead of ignoring them",
+	},
+}
+
+var alertingCommands = []*cli.Command{
+	{
+		Name:  "rules",
+		Usage: "Manage alert rules with provisioning files",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "export",
+				Usage:  "export the rule groups of an organization to a provisioning file",
+				Action: runAlertingRulesCommand(alertingrules.ExportRules),
+				Flags: append([]cli.Flag{
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "ID of the organization",
+						Value: 1,
+					},
+					&cli.StringSliceFlag{
+						Name:  "folder-uid",
+						Usage: "UID of a folder to export. All folders are exported if it is not set",
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Format of the file, yaml or json",
+						Value: "yaml",
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "Path of the file. The rules are printed if it is not set",
+					},
+				}, alertingRulesClientFlags...),
+			},
+			{
+				Name:   "apply",
+				Usage:  "apply <file>, replaces the rule groups of the file. Groups must refer to their folders with folderUid",
+				Action: runAlertingRulesCommand(alertingrules.ApplyRules),
+				Flags: append([]cli.Flag{
+					&cli.BoolFlag{
+						Name:  "disable-provenance",
+						Usage: "Allow the rules to be edited in the UI",
+					},
+				}, append(alertingRulesFileFlags, alertingRulesClientFlags...)...),
+			},
+			{
+				Name:   "diff",
+				Usage:  "diff <file>, lists the rules that apply would add, update and delete",
+				Action: runAlertingRulesCommand(alertingrules.DiffRules),
+				Flags:  append(alertingRulesFileFlags, alertingRulesClientFlags...),
+			},
+			{
+				Name:   "validate",
+				Usage:  "validate <file>, checks the rule groups of the file without a server",
+				Action: runPluginCommand(alertingrules.ValidateRules),
+				Flags:  alertingRulesFileFlags,
+			},
+		},
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -237,4 +340,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
@@ -78,3 +78,64 @@ type FolderTitleUpdated struct {
 	UID       string    `json:"uid"`
 	OrgID     int64     `json:"org_id"`
 }
+
+type FolderMoved struct {
+	Timestamp    time.Time `json:"timestamp"`
+	UID          string    `json:"uid"`
+	OrgID        int64     `json:"org_id"`
+	NewParentUID string    `json:"new_parent_uid"`
+}
+
+// FolderPermissionsChanged is published when a permission of a user, a team or a built-in role on a folder is set or
+// removed.
+type FolderPermissionsChanged struct {
+	Timestamp time.Time `json:"timestamp"`
+	UID       string    `json:"uid"`
+	OrgID     int64     `json:"org_id"`
+}
+
+// AlertRuleCreated is published when an alert rule is created through the alerting provisioning service.
+type AlertRuleCreated struct {
+	Timestamp  time.Time `json:"timestamp"`
+	OrgID      int64     `json:"org_id"`
+	UID        string    `json:"uid"`
+	Title      string    `json:"title"`
+	FolderUID  string    `json:"folder_uid"`
+	RuleGroup  string    `json:"rule_group"`
+	Provenance string    `json:"provenance"`
+}
+
+// AlertRuleUpdated is published when an alert rule is updated through the alerting provisioning service.
+type AlertRuleUpdated struct {
+	Timestamp  time.Time `json:"timestamp"`
+	OrgID      int64     `json:"org_id"`
+	UID        string    `json:"uid"`
+	Title      string    `json:"title"`
+	FolderUID  string    `json:"folder_uid"`
+	RuleGroup  string    `json:"rule_group"`
+	Provenance string    `json:"provenance"`
+}
+
+// AlertRuleDeleted is published when an alert rule is deleted through the alerting provisioning service.
+type AlertRuleDeleted struct {
+	Timestamp  time.Time `json:"timestamp"`
+	OrgID      int64     `json:"org_id"`
+	UID        string    `json:"uid"`
+	Title      string    `json:"title"`
+	FolderUID  string    `json:"folder_uid"`
+	RuleGroup  string    `json:"rule_group"`
+	Provenance string    `json:"provenance"`
+}
+
+// AlertRuleGroupReplaced is published when an alert rule group is replaced through the alerting provisioning service.
+// The rules that are created, updated and deleted by the replacement are published as well.
+type AlertRuleGroupReplaced struct {
+	Timestamp  time.Time `json:"timestamp"`
+	OrgID      int64     `json:"org_id"`
+	FolderUID  string    `json:"folder_uid"`
+	RuleGroup  string    `json:"rule_group"`
+	Provenance string    `json:"provenance"`
+	Created    []string  `json:"created"`
+	Updated    []string  `json:"updated"`
+	Deleted    []string  `json:"deleted"`
+}
@@ -91,6 +91,27 @@ func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, pCtxProvider
 	}
 }
 
+// WithQueryDataHandler returns a copy of the service that sends the queries of the data source nodes to the handler
+// instead of the data source plugins, for example to evaluate expressions against data provided by the caller.
+func (s *Service) WithQueryDataHandler(handler backend.QueryDataHandler) *Service {
+	c := *s
+	c.dataService = handler
+	c.pCtxProvider = staticPluginContextProvider{}
+	return &c
+}
+
+// staticPluginContextProvider provides plugin contexts without looking up the plugins. It is used when the queries
+// are not sent to the plugins.
+type staticPluginContextProvider struct{}
+
+func (staticPluginContextProvider) Get(_ context.Context, pluginID string, _ identity.Requester, orgID int64) (backend.PluginContext, error) {
+	return backend.PluginContext{OrgID: orgID, PluginID: pluginID}, nil
+}
+
+func (staticPluginContextProvider) GetWithDataSource(_ context.Context, pluginID string, _ identity.Requester, ds *datasources.DataSource) (backend.PluginContext, error) {
+	return backend.PluginContext{OrgID: ds.OrgID, PluginID: pluginID}, nil
+}
+
 func (s *Service) isDisabled() bool {
 	if s.cfg == nil {
 		return true
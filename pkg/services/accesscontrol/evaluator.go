@@ -202,3 +202,70 @@ func (a anyEvaluator) GoString() string {
 
 	return fmt.Sprintf("any(%s)", strings.Join(permissions, " "))
 }
+
+// MissingPermissions returns the permissions, grouped by action, that are required by the evaluator but not granted
+// by the given permissions. When none of the alternatives of an EvalAny or of the scopes of an EvalPermission is
+// granted, all of them are returned.
+func MissingPermissions(eval Evaluator, permissions map[string][]string) map[string][]string {
+	missing, _ := MissingPermissionsFunc(eval, permissions, nil)
+	return missing
+}
+
+// MissingPermissionsFunc is the same as MissingPermissions, but the permissions that are not granted as they are, and
+// for whose action the permissions have scopes, are checked again by resolve. It allows the permissions to be checked
+// by an AccessControl, which resolves the scopes of the evaluator, e.g. to the scopes of the parent folders.
+func MissingPermissionsFunc(eval Evaluator, permissions map[string][]string, resolve func(Evaluator) (bool, error)) (map[string][]string, error) {
+	missing := make(map[string][]string)
+	if _, err := collectMissingPermissions(eval, permissions, resolve, missing); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
+// collectMissingPermissions adds the missing permissions of the evaluator to missing and returns true if it is granted.
+func collectMissingPermissions(eval Evaluator, permissions map[string][]string, resolve func(Evaluator) (bool, error), missing map[string][]string) (bool, error) {
+	if eval.Evaluate(permissions) {
+		return true, nil
+	}
+	switch e := eval.(type) {
+	case permissionEvaluator:
+		// scopes can only be resolved to other scopes, so an action without any scope is not granted.
+		if resolve != nil && len(permissions[e.Action]) > 0 {
+			granted, err := resolve(e)
+			if err != nil || granted {
+				return granted, err
+			}
+		}
+		if _, ok := missing[e.Action]; !ok {
+			missing[e.Action] = []string{}
+		}
+		missing[e.Action] = append(missing[e.Action], e.Scopes...)
+		return false, nil
+	case allEvaluator:
+		granted := true
+		for _, sub := range e.allOf {
+			ok, err := collectMissingPermissions(sub, permissions, resolve, missing)
+			if err != nil {
+				return false, err
+			}
+			granted = granted && ok
+		}
+		return granted, nil
+	case anyEvaluator:
+		anyMissing := make(map[string][]string)
+		for _, sub := range e.anyOf {
+			ok, err := collectMissingPermissions(sub, permissions, resolve, anyMissing)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		for action, scopes := range anyMissing {
+			if _, ok := missing[action]; !ok {
+				missing[action] = []string{}
+			}
+			missing[action] = append(missing[action], scopes...)
+		}
+		return false, nil
+	}
+	return false, nil
+}
@@ -410,3 +410,37 @@ func TestEval(t *testing.T) {
 		})
 	}
 }
+
+func TestMissingPermissions(t *testing.T) {
+	eval := EvalAll(
+		EvalPermission("folders:read", "folders:uid:a"),
+		EvalPermission("alert.rules:write", "folders:uid:a"),
+		EvalAny(
+			EvalPermission("datasources:query", "datasources:uid:1"),
+			EvalPermission("datasources:query", "datasources:uid:2"),
+		),
+		EvalPermission("alert.provisioning:read"),
+	)
+
+	t.Run("should return nothing when permissions are granted", func(t *testing.T) {
+		missing := MissingPermissions(eval, map[string][]string{
+			"folders:read":            {"folders:*"},
+			"alert.rules:write":       {"folders:uid:a"},
+			"datasources:query":       {"datasources:uid:2"},
+			"alert.provisioning:read": {},
+		})
+		assert.Empty(t, missing)
+	})
+
+	t.Run("should return permissions that are not granted", func(t *testing.T) {
+		missing := MissingPermissions(eval, map[string][]string{
+			"folders:read":      {"folders:*"},
+			"alert.rules:write": {"folders:uid:b"},
+		})
+		assert.Equal(t, map[string][]string{
+			"alert.rules:write":       {"folders:uid:a"},
+			"datasources:query":       {"datasources:uid:1", "datasources:uid:2"},
+			"alert.provisioning:read": {},
+		}, missing)
+	})
+}
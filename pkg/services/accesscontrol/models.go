@@ -434,6 +434,8 @@ const (
 	ActionAlertingRuleRead   = "alert.rules:read"
 	ActionAlertingRuleUpdate = "alert.rules:write"
 	ActionAlertingRuleDelete = "alert.rules:delete"
+	// ActionAlertingRuleIntervalUpdate allows changing the evaluation interval of rule groups without editing the rules.
+	ActionAlertingRuleIntervalUpdate = "alert.rules.interval:write"
 
 	// Alerting instances (+silences) actions
 	ActionAlertingInstanceCreate = "alert.instances:create"
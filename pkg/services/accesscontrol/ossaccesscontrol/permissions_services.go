@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/events"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -267,9 +269,12 @@ func ProvideFolderPermissions(
 			"Edit":  append(getDashboardEditActions(features), FolderEditActions...),
 			"Admin": append(getDashboardAdminActions(features), FolderAdminActions...),
 		},
-		ReaderRoleName: "Folder permission reader",
-		WriterRoleName: "Folder permission writer",
-		RoleGroup:      "Folders",
+		ReaderRoleName:   "Folder permission reader",
+		WriterRoleName:   "Folder permission writer",
+		RoleGroup:        "Folders",
+		OnSetUser:        onSetFolderUserPermission,
+		OnSetTeam:        onSetFolderTeamPermission,
+		OnSetBuiltInRole: onSetFolderBuiltInRolePermission,
 	}
 	srv, err := resourcepermissions.New(cfg, options, features, router, license, accesscontrol, service, sql, teamService, userService)
 	if err != nil {
@@ -278,6 +283,31 @@ func ProvideFolderPermissions(
 	return &FolderPermissionsService{srv}, nil
 }
 
+// publishFolderPermissionsChanged publishes the change of the permissions on the folder once the transaction of the
+// session is committed, so that the listeners never see the permissions from before the change.
+func publishFolderPermissionsChanged(session *db.Session, orgID int64, folderUID string) {
+	session.PublishAfterCommit(&events.FolderPermissionsChanged{
+		Timestamp: time.Now(),
+		UID:       folderUID,
+		OrgID:     orgID,
+	})
+}
+
+func onSetFolderUserPermission(session *db.Session, orgID int64, _ accesscontrol.User, folderUID, _ string) error {
+	publishFolderPermissionsChanged(session, orgID, folderUID)
+	return nil
+}
+
+func onSetFolderTeamPermission(session *db.Session, orgID, _ int64, folderUID, _ string) error {
+	publishFolderPermissionsChanged(session, orgID, folderUID)
+	return nil
+}
+
+func onSetFolderBuiltInRolePermission(session *db.Session, orgID int64, _, folderUID, _ string) error {
+	publishFolderPermissionsChanged(session, orgID, folderUID)
+	return nil
+}
+
 func ProvideDatasourcePermissionsService() *DatasourcePermissionsService {
 	return &DatasourcePermissionsService{}
 }
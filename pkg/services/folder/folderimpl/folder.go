@@ -923,6 +923,15 @@ func (s *Service) Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*fol
 			return folder.ErrInternal.Errorf("failed to move legacy folder: %w", err)
 		}
 
+		if err := s.bus.Publish(ctx, &events.FolderMoved{
+			Timestamp:    f.Updated,
+			UID:          f.UID,
+			OrgID:        cmd.OrgID,
+			NewParentUID: newParentUID,
+		}); err != nil {
+			// the move is not rolled back, listeners that missed the event can rely on their own expiration
+			s.log.FromContext(ctx).Error("failed to publish FolderMoved event", "folderUID", f.UID, "error", err)
+		}
 		return nil
 	}); err != nil {
 		return nil, err
@@ -1901,6 +1901,7 @@ func setup(t *testing.T, dashStore dashboards.Store, dashboardFolderStore folder
 		accessControl:        ac,
 		db:                   db,
 		metrics:              newFoldersMetrics(nil),
+		bus:                  bus.ProvideBus(tracing.InitializeTracerForTest()),
 	}
 }
 
@@ -63,6 +63,21 @@ var (
 		},
 	}
 
+	rulesIntervalWriterRole = accesscontrol.RoleRegistration{
+		Role: accesscontrol.RoleDTO{
+			Name:        accesscontrol.FixedRolePrefix + "alerting.rules.interval:writer",
+			DisplayName: "Rules Interval Writer",
+			Description: "Read alert rules and update the evaluation interval of rule groups in any Grafana folder",
+			Group:       AlertRolesGroup,
+			Permissions: accesscontrol.ConcatPermissions(rulesReaderRole.Role.Permissions, []accesscontrol.Permission{
+				{
+					Action: accesscontrol.ActionAlertingRuleIntervalUpdate,
+					Scope:  dashboards.ScopeFoldersAll,
+				},
+			}),
+		},
+	}
+
 	instancesReaderRole = accesscontrol.RoleRegistration{
 		Role: accesscontrol.RoleDTO{
 			Name:        accesscontrol.FixedRolePrefix + "alerting.instances:reader",
@@ -205,7 +220,7 @@ var (
 
 func DeclareFixedRoles(service accesscontrol.Service) error {
 	return service.DeclareFixedRoles(
-		rulesReaderRole, rulesWriterRole,
+		rulesReaderRole, rulesWriterRole, rulesIntervalWriterRole,
 		instancesReaderRole, instancesWriterRole,
 		notificationsReaderRole, notificationsWriterRole,
 		alertingReaderRole, alertingWriterRole, alertingProvisionerRole, alertingProvisioningReaderWithSecretsRole,
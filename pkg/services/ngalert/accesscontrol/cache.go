@@ -0,0 +1,107 @@
+package accesscontrol
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFolderAuthorizationCacheTTL = 5 * time.Minute
+	// maxFolderDecisionsPerOrg bounds the memory used by the decisions of a single org. When it is reached, the
+	// decisions of the org are dropped.
+	maxFolderDecisionsPerOrg = 100000
+)
+
+type folderDecisionKey struct {
+	identity  string
+	action    string
+	folderUID string
+}
+
+type folderDecision struct {
+	allowed     bool
+	fingerprint uint64
+	expires     time.Time
+}
+
+// FolderAuthorizationCache caches the decisions of folder-level authorization checks, i.e. whether a user is granted
+// an action on a folder. Evaluating these decisions can be costly because permissions inherited from parent folders
+// require resolving the folder tree.
+//
+// Each decision is stored along with a fingerprint of the scopes the user is granted for the action, so that a decision
+// is never used after the permissions of the user change. Decisions that depend on the folder tree or on the permissions
+// granted on folders must be invalidated by the caller when they change, see InvalidateOrg.
+type FolderAuthorizationCache struct {
+	mtx       sync.RWMutex
+	ttl       time.Duration
+	decisions map[int64]map[folderDecisionKey]folderDecision
+	now       func() time.Time
+}
+
+func NewFolderAuthorizationCache(ttl time.Duration) *FolderAuthorizationCache {
+	if ttl <= 0 {
+		ttl = defaultFolderAuthorizationCacheTTL
+	}
+	return &FolderAuthorizationCache{
+		ttl:       ttl,
+		decisions: make(map[int64]map[folderDecisionKey]folderDecision),
+		now:       time.Now,
+	}
+}
+
+// Get returns the cached decision for the user identified by the namespaced ID. The second result is false if there is
+// no valid decision in the cache.
+func (c *FolderAuthorizationCache) Get(orgID int64, identity, action, folderUID string, scopes []string) (bool, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	d, ok := c.decisions[orgID][folderDecisionKey{identity: identity, action: action, folderUID: folderUID}]
+	if !ok || c.now().After(d.expires) || d.fingerprint != fingerprintScopes(scopes) {
+		return false, false
+	}
+	return d.allowed, true
+}
+
+// Set stores the decision for the user identified by the namespaced ID, given the scopes it is granted for the action.
+func (c *FolderAuthorizationCache) Set(orgID int64, identity, action, folderUID string, scopes []string, allowed bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	org, ok := c.decisions[orgID]
+	if !ok || len(org) >= maxFolderDecisionsPerOrg {
+		org = make(map[folderDecisionKey]folderDecision)
+		c.decisions[orgID] = org
+	}
+	org[folderDecisionKey{identity: identity, action: action, folderUID: folderUID}] = folderDecision{
+		allowed:     allowed,
+		fingerprint: fingerprintScopes(scopes),
+		expires:     c.now().Add(c.ttl),
+	}
+}
+
+// InvalidateFolder removes the decisions about the folder.
+func (c *FolderAuthorizationCache) InvalidateFolder(orgID int64, folderUID string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for key := range c.decisions[orgID] {
+		if key.folderUID == folderUID {
+			delete(c.decisions[orgID], key)
+		}
+	}
+}
+
+// InvalidateOrg removes all decisions of the org. It must be called when the folder tree of the org or the permissions
+// on one of its folders change, since permissions granted on a folder are inherited by its subfolders.
+func (c *FolderAuthorizationCache) InvalidateOrg(orgID int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.decisions, orgID)
+}
+
+func fingerprintScopes(scopes []string) uint64 {
+	h := fnv.New64()
+	for _, s := range scopes {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
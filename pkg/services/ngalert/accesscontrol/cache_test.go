@@ -0,0 +1,114 @@
+package accesscontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestFolderAuthorizationCache(t *testing.T) {
+	scopes := []string{"folders:uid:test"}
+
+	t.Run("should return stored decision", func(t *testing.T) {
+		c := NewFolderAuthorizationCache(time.Minute)
+		_, ok := c.Get(1, "user:1", ruleRead, "test", scopes)
+		require.False(t, ok)
+
+		c.Set(1, "user:1", ruleRead, "test", scopes, true)
+		allowed, ok := c.Get(1, "user:1", ruleRead, "test", scopes)
+		require.True(t, ok)
+		require.True(t, allowed)
+
+		_, ok = c.Get(2, "user:1", ruleRead, "test", scopes)
+		require.False(t, ok)
+		_, ok = c.Get(1, "user:2", ruleRead, "test", scopes)
+		require.False(t, ok)
+		_, ok = c.Get(1, "user:1", ruleUpdate, "test", scopes)
+		require.False(t, ok)
+	})
+
+	t.Run("should ignore decision if permissions changed", func(t *testing.T) {
+		c := NewFolderAuthorizationCache(time.Minute)
+		c.Set(1, "user:1", ruleRead, "test", scopes, true)
+		_, ok := c.Get(1, "user:1", ruleRead, "test", nil)
+		require.False(t, ok)
+		_, ok = c.Get(1, "user:1", ruleRead, "test", append(scopes, "folders:uid:other"))
+		require.False(t, ok)
+	})
+
+	t.Run("should ignore expired decision", func(t *testing.T) {
+		c := NewFolderAuthorizationCache(time.Minute)
+		now := time.Now()
+		c.now = func() time.Time { return now }
+		c.Set(1, "user:1", ruleRead, "test", scopes, true)
+
+		now = now.Add(2 * time.Minute)
+		_, ok := c.Get(1, "user:1", ruleRead, "test", scopes)
+		require.False(t, ok)
+	})
+
+	t.Run("should invalidate decisions", func(t *testing.T) {
+		c := NewFolderAuthorizationCache(time.Minute)
+		c.Set(1, "user:1", ruleRead, "test", scopes, true)
+		c.Set(1, "user:1", ruleRead, "other", scopes, true)
+		c.Set(2, "user:1", ruleRead, "test", scopes, true)
+
+		c.InvalidateFolder(1, "test")
+		_, ok := c.Get(1, "user:1", ruleRead, "test", scopes)
+		require.False(t, ok)
+		_, ok = c.Get(1, "user:1", ruleRead, "other", scopes)
+		require.True(t, ok)
+
+		c.InvalidateOrg(1)
+		_, ok = c.Get(1, "user:1", ruleRead, "other", scopes)
+		require.False(t, ok)
+		_, ok = c.Get(2, "user:1", ruleRead, "test", scopes)
+		require.True(t, ok)
+	})
+}
+
+func TestAuthorizeRuleChangesWithCache(t *testing.T) {
+	groupKey := models.GenerateGroupKey(1)
+	rules := models.GenerateAlertRules(3, models.AlertRuleGen(models.WithGroupKey(groupKey)))
+	change := &store.GroupDelta{
+		GroupKey:       groupKey,
+		AffectedGroups: map[models.AlertRuleGroupKey]models.RulesGroup{},
+		New:            rules,
+	}
+	permissions := map[string][]string{
+		ruleCreate:              {dashboards.ScopeFoldersProvider.GetResourceScopeUID(groupKey.NamespaceUID)},
+		datasources.ActionQuery: getDatasourceScopesForRules(rules),
+	}
+	user := createUserWithPermissions(permissions)
+
+	ac := &recordingAccessControlFake{}
+	srv := NewRuleServiceWithCache(ac, NewFolderAuthorizationCache(time.Minute))
+
+	countFolderEvaluations := func() int {
+		count := 0
+		for _, r := range ac.EvaluateRecordings {
+			if r.Evaluator.String() == accesscontrol.EvalPermission(ruleCreate, dashboards.ScopeFoldersProvider.GetResourceScopeUID(groupKey.NamespaceUID)).String() {
+				count++
+			}
+		}
+		return count
+	}
+
+	require.NoError(t, srv.AuthorizeRuleChanges(context.Background(), user, change))
+	require.Equal(t, 1, countFolderEvaluations())
+
+	require.NoError(t, srv.AuthorizeRuleChanges(context.Background(), user, change))
+	require.Equal(t, 1, countFolderEvaluations(), "decision should be taken from the cache")
+
+	delete(permissions, ruleCreate)
+	require.Error(t, srv.AuthorizeRuleChanges(context.Background(), createUserWithPermissions(permissions), change))
+	require.Equal(t, 2, countFolderEvaluations(), "cached decision should not be used when permissions changed")
+}
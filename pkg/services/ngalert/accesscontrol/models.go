@@ -1,9 +1,13 @@
 package accesscontrol
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
@@ -11,7 +15,29 @@ var (
 	errAuthorizationGeneric = errutil.Forbidden("alerting.unauthorized")
 )
 
+// MissingPermission is a permission that is required to perform an action but is not granted to the user.
+type MissingPermission struct {
+	Action string `json:"action"`
+	Scope  string `json:"scope,omitempty"`
+	// FolderUID is the UID of the folder the scope refers to, if any.
+	FolderUID string `json:"folderUid,omitempty"`
+}
+
 func NewAuthorizationErrorWithPermissions(action string, eval accesscontrol.Evaluator) error {
+	return newAuthorizationError(action, eval, nil)
+}
+
+// NewAuthorizationErrorWithMissingPermissions creates an authorization error that, in addition to the required
+// permissions, lists the missing permissions, grouped by action, as returned by accesscontrol.MissingPermissions.
+func NewAuthorizationErrorWithMissingPermissions(action string, eval accesscontrol.Evaluator, missing map[string][]string) error {
+	return newAuthorizationError(action, eval, toMissingPermissions(missing))
+}
+
+func NewAuthorizationErrorGeneric(action string) error {
+	return NewAuthorizationErrorWithPermissions(action, nil)
+}
+
+func newAuthorizationError(action string, eval accesscontrol.Evaluator, missing []MissingPermission) error {
 	msg := fmt.Sprintf("user is not authorized to %s", action)
 	err := errAuthorizationGeneric.Errorf(msg)
 	err.PublicMessage = msg
@@ -19,10 +45,52 @@ func NewAuthorizationErrorWithPermissions(action string, eval accesscontrol.Eval
 		err.PublicPayload = map[string]any{
 			"permissions": eval.GoString(),
 		}
+		if len(missing) > 0 {
+			err.PublicPayload["missing"] = missing
+		}
 	}
 	return err
 }
 
-func NewAuthorizationErrorGeneric(action string) error {
-	return NewAuthorizationErrorWithPermissions(action, nil)
+// IsAuthorizationError returns true if the error is an authorization error returned by the alerting access control.
+func IsAuthorizationError(err error) bool {
+	return errors.Is(err, errAuthorizationGeneric)
+}
+
+// GetMissingPermissions returns the missing permissions listed in an authorization error, or nil if the error is not an
+// authorization error or does not list them.
+func GetMissingPermissions(err error) []MissingPermission {
+	if !IsAuthorizationError(err) {
+		return nil
+	}
+	var gfErr errutil.Error
+	if !errors.As(err, &gfErr) {
+		return nil
+	}
+	missing, _ := gfErr.PublicPayload["missing"].([]MissingPermission)
+	return missing
+}
+
+func toMissingPermissions(missing map[string][]string) []MissingPermission {
+	result := make([]MissingPermission, 0)
+	for action, scopes := range missing {
+		if len(scopes) == 0 {
+			result = append(result, MissingPermission{Action: action})
+			continue
+		}
+		for _, scope := range scopes {
+			p := MissingPermission{Action: action, Scope: scope}
+			if uid, ok := strings.CutPrefix(scope, dashboards.ScopeFoldersPrefix); ok {
+				p.FolderUID = uid
+			}
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Action != result[j].Action {
+			return result[i].Action < result[j].Action
+		}
+		return result[i].Scope < result[j].Scope
+	})
+	return result
 }
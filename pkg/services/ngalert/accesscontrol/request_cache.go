@@ -0,0 +1,96 @@
+package accesscontrol
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+type requestAuthorizationCacheKey struct{}
+
+type requestDecisionKey struct {
+	orgID    int64
+	identity string
+	// group is the sorted UIDs of the data sources that the rules of the group query. The UIDs are joined rather than
+	// hashed, so that two groups never share a decision because of a collision.
+	group string
+	// scopes is the scopes the user is granted for querying data sources, joined like the UIDs of the data sources.
+	scopes string
+}
+
+// RequestAuthorizationCache caches the decisions of the rule group read checks of a single request. The decision only
+// depends on the data sources that the rules of the group query, so the groups that query the same data sources share
+// it and a request that lists thousands of groups evaluates the permissions of the user once per distinct set of data
+// sources.
+//
+// Each decision is stored along with the scopes the user is granted for querying data sources, so that a decision is
+// never used after the permissions of the user change. The decisions are also dropped when the request changes
+// permissions, see InvalidateRequestAuthorizationCache.
+type RequestAuthorizationCache struct {
+	mtx       sync.Mutex
+	decisions map[requestDecisionKey]bool
+}
+
+// WithRequestAuthorizationCache returns a context in which RuleService caches the rule group read decisions. The
+// decisions are dropped with the context, at the end of the request. If the context already has a cache, it is
+// returned unchanged.
+func WithRequestAuthorizationCache(ctx context.Context) context.Context {
+	if requestAuthorizationCacheFromContext(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestAuthorizationCacheKey{}, &RequestAuthorizationCache{
+		decisions: make(map[requestDecisionKey]bool),
+	})
+}
+
+// InvalidateRequestAuthorizationCache removes the decisions cached in the context, if it has a cache. It must be called
+// by requests that change data sources or permissions after they read rule groups.
+func InvalidateRequestAuthorizationCache(ctx context.Context) {
+	if c := requestAuthorizationCacheFromContext(ctx); c != nil {
+		c.Invalidate()
+	}
+}
+
+func requestAuthorizationCacheFromContext(ctx context.Context) *RequestAuthorizationCache {
+	c, _ := ctx.Value(requestAuthorizationCacheKey{}).(*RequestAuthorizationCache)
+	return c
+}
+
+func (c *RequestAuthorizationCache) get(key requestDecisionKey) (bool, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	allowed, ok := c.decisions[key]
+	return allowed, ok
+}
+
+func (c *RequestAuthorizationCache) set(key requestDecisionKey, allowed bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.decisions[key] = allowed
+}
+
+// Invalidate removes all decisions.
+func (c *RequestAuthorizationCache) Invalidate() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.decisions = make(map[requestDecisionKey]bool)
+}
+
+// datasourcesKey returns the same key for the same data sources in any order.
+func datasourcesKey(uids []string) string {
+	sorted := slices.Clone(uids)
+	slices.Sort(sorted)
+	return joinKey(sorted)
+}
+
+// joinKey terminates each value with a character that UIDs and scopes cannot contain, so that different values never
+// have the same key, not even an empty list and a list of an empty value.
+func joinKey(values []string) string {
+	var b strings.Builder
+	for _, v := range values {
+		b.WriteString(v)
+		b.WriteByte(0)
+	}
+	return b.String()
+}
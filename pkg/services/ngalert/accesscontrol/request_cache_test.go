@@ -0,0 +1,116 @@
+package accesscontrol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRequestAuthorizationCache(t *testing.T) {
+	query := func(datasourceUID string) models.AlertQuery {
+		q := models.GenerateAlertQuery()
+		q.DatasourceUID = datasourceUID
+		return q
+	}
+	// The first two groups query the same data sources, in a different order.
+	both := models.RulesGroup{models.AlertRuleGen(models.WithQuery(query("ds-1"), query("ds-2")))()}
+	bothReversed := models.RulesGroup{
+		models.AlertRuleGen(models.WithQuery(query("ds-2")))(),
+		models.AlertRuleGen(models.WithQuery(query("ds-1")))(),
+	}
+	first := models.RulesGroup{models.AlertRuleGen(models.WithQuery(query("ds-1")))()}
+	permissions := map[string][]string{
+		datasources.ActionQuery: {datasources.ScopeProvider.GetResourceScopeUID("ds-1")},
+	}
+
+	t.Run("should evaluate groups that query the same data sources once", func(t *testing.T) {
+		ac := &recordingAccessControlFake{}
+		svc := RuleService{ac: ac}
+		ctx := WithRequestAuthorizationCache(context.Background())
+		usr := createUserWithPermissions(permissions)
+
+		for _, group := range []models.RulesGroup{both, bothReversed, both} {
+			ok, err := svc.HasAccessToRuleGroup(ctx, usr, group)
+			require.NoError(t, err)
+			require.False(t, ok)
+		}
+		ok, err := svc.HasAccessToRuleGroup(ctx, usr, first)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Len(t, ac.EvaluateRecordings, 2)
+
+		// denied decisions that are cached are still returned as authorization errors
+		err = svc.AuthorizeAccessToRuleGroup(ctx, usr, bothReversed)
+		require.True(t, IsAuthorizationError(err))
+		require.Equal(t, []MissingPermission{{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("ds-2")}}, GetMissingPermissions(err))
+		// only the permission that is not granted as it is is evaluated again to resolve the missing ones
+		require.Len(t, ac.EvaluateRecordings, 3)
+	})
+
+	t.Run("should not cache without a cache in the context", func(t *testing.T) {
+		ac := &recordingAccessControlFake{}
+		svc := RuleService{ac: ac}
+		usr := createUserWithPermissions(permissions)
+
+		for i := 0; i < 2; i++ {
+			_, err := svc.HasAccessToRuleGroup(context.Background(), usr, both)
+			require.NoError(t, err)
+		}
+		require.Len(t, ac.EvaluateRecordings, 2)
+	})
+
+	t.Run("should not share decisions between users or permissions", func(t *testing.T) {
+		ac := &recordingAccessControlFake{}
+		svc := RuleService{ac: ac}
+		ctx := WithRequestAuthorizationCache(context.Background())
+
+		ok, err := svc.HasAccessToRuleGroup(ctx, createUserWithPermissions(permissions), both)
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		other := &user.SignedInUser{UserID: 2, OrgID: 1, Permissions: map[int64]map[string][]string{1: permissions}}
+		ok, err = svc.HasAccessToRuleGroup(ctx, other, both)
+		require.NoError(t, err)
+		require.False(t, ok)
+		require.Len(t, ac.EvaluateRecordings, 2)
+
+		granted := createUserWithPermissions(map[string][]string{
+			datasources.ActionQuery: {datasources.ScopeProvider.GetResourceScopeUID("ds-1"), datasources.ScopeProvider.GetResourceScopeUID("ds-2")},
+		})
+		ok, err = svc.HasAccessToRuleGroup(ctx, granted, both)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Len(t, ac.EvaluateRecordings, 3)
+	})
+
+	t.Run("should evaluate again after invalidation", func(t *testing.T) {
+		ac := &recordingAccessControlFake{}
+		svc := RuleService{ac: ac}
+		ctx := WithRequestAuthorizationCache(context.Background())
+		usr := createUserWithPermissions(permissions)
+
+		_, err := svc.HasAccessToRuleGroup(ctx, usr, first)
+		require.NoError(t, err)
+		InvalidateRequestAuthorizationCache(ctx)
+		_, err = svc.HasAccessToRuleGroup(ctx, usr, first)
+		require.NoError(t, err)
+		require.Len(t, ac.EvaluateRecordings, 2)
+	})
+
+	t.Run("should reuse the cache of the context", func(t *testing.T) {
+		ctx := WithRequestAuthorizationCache(context.Background())
+		require.Same(t, requestAuthorizationCacheFromContext(ctx), requestAuthorizationCacheFromContext(WithRequestAuthorizationCache(ctx)))
+	})
+
+	t.Run("should key the decisions by the data sources rather than a hash of them", func(t *testing.T) {
+		require.Equal(t, datasourcesKey([]string{"ds-1", "ds-2"}), datasourcesKey([]string{"ds-2", "ds-1"}))
+		require.NotEqual(t, datasourcesKey([]string{"ds-1", "ds-2"}), datasourcesKey([]string{"ds-1ds-2"}))
+		require.NotEqual(t, datasourcesKey([]string{"ds-1"}), datasourcesKey([]string{"ds-1", ""}))
+		require.NotEqual(t, datasourcesKey(nil), datasourcesKey([]string{""}))
+	})
+}
@@ -2,6 +2,7 @@ package accesscontrol
 
 import (
 	"fmt"
+	"strings"
 
 	"golang.org/x/net/context"
 
@@ -19,10 +20,14 @@ const (
 	ruleRead   = accesscontrol.ActionAlertingRuleRead
 	ruleUpdate = accesscontrol.ActionAlertingRuleUpdate
 	ruleDelete = accesscontrol.ActionAlertingRuleDelete
+
+	ruleIntervalUpdate = accesscontrol.ActionAlertingRuleIntervalUpdate
 )
 
 type RuleService struct {
 	ac accesscontrol.AccessControl
+	// folderCache is optional. If set, it is used to cache folder-level authorization decisions.
+	folderCache *FolderAuthorizationCache
 }
 
 func NewRuleService(ac accesscontrol.AccessControl) *RuleService {
@@ -31,23 +36,94 @@ func NewRuleService(ac accesscontrol.AccessControl) *RuleService {
 	}
 }
 
+// NewRuleServiceWithCache creates a RuleService that caches folder-level authorization decisions in the given cache.
+func NewRuleServiceWithCache(ac accesscontrol.AccessControl, cache *FolderAuthorizationCache) *RuleService {
+	return &RuleService{
+		ac:          ac,
+		folderCache: cache,
+	}
+}
+
 // HasAccess returns true if the identity.Requester has all permissions specified by the evaluator. Returns error if access control backend could not evaluate permissions
 func (r *RuleService) HasAccess(ctx context.Context, user identity.Requester, evaluator accesscontrol.Evaluator) (bool, error) {
 	return r.ac.Evaluate(ctx, user, evaluator)
 }
 
-// HasAccessOrError returns nil if the identity.Requester has enough permissions to pass the accesscontrol.Evaluator. Otherwise, returns authorization error that contains action that was performed
+// HasAccessOrError returns nil if the identity.Requester has enough permissions to pass the accesscontrol.Evaluator. Otherwise, returns authorization error that contains action that was performed and the permissions the user is missing
 func (r *RuleService) HasAccessOrError(ctx context.Context, user identity.Requester, evaluator accesscontrol.Evaluator, action func() string) error {
 	has, err := r.HasAccess(ctx, user, evaluator)
 	if err != nil {
 		return err
 	}
 	if !has {
-		return NewAuthorizationErrorWithPermissions(action(), evaluator)
+		return r.authorizationError(ctx, user, action(), evaluator)
 	}
 	return nil
 }
 
+// authorizationError returns an authorization error that lists the permissions of the evaluator that the user is
+// missing. The permissions that are not granted as they are are checked by the access control, which resolves their
+// scopes, so that a permission granted through e.g. a parent folder is not reported as missing.
+func (r *RuleService) authorizationError(ctx context.Context, user identity.Requester, action string, evaluator accesscontrol.Evaluator) error {
+	missing, err := accesscontrol.MissingPermissionsFunc(evaluator, user.GetPermissions(), func(e accesscontrol.Evaluator) (bool, error) {
+		return r.HasAccess(ctx, user, e)
+	})
+	if err != nil {
+		return NewAuthorizationErrorWithPermissions(action, evaluator)
+	}
+	return NewAuthorizationErrorWithMissingPermissions(action, evaluator, missing)
+}
+
+// hasFolderAccess returns true if the identity.Requester is granted any of the actions on the folder.
+func (r *RuleService) hasFolderAccess(ctx context.Context, user identity.Requester, folderUID string, actions ...string) (bool, error) {
+	eval := getFolderEvaluator(folderUID, actions...)
+	if r.folderCache == nil {
+		return r.HasAccess(ctx, user, eval)
+	}
+	namespace, id := user.GetNamespacedID()
+	requester := namespace + ":" + id
+	key := strings.Join(actions, ",")
+	permissions := user.GetPermissions()
+	scopes := make([]string, 0)
+	for _, action := range actions {
+		scopes = append(scopes, permissions[action]...)
+	}
+	if allowed, ok := r.folderCache.Get(user.GetOrgID(), requester, key, folderUID, scopes); ok {
+		return allowed, nil
+	}
+	allowed, err := r.HasAccess(ctx, user, eval)
+	if err != nil {
+		return false, err
+	}
+	r.folderCache.Set(user.GetOrgID(), requester, key, folderUID, scopes, allowed)
+	return allowed, nil
+}
+
+// hasFolderAccessOrError is the same as HasAccessOrError for an evaluator that requires any of the actions on the folder.
+func (r *RuleService) hasFolderAccessOrError(ctx context.Context, user identity.Requester, folderUID string, action func() string, actions ...string) error {
+	has, err := r.hasFolderAccess(ctx, user, folderUID, actions...)
+	if err != nil {
+		return err
+	}
+	if !has {
+		return r.authorizationError(ctx, user, action(), getFolderEvaluator(folderUID, actions...))
+	}
+	return nil
+}
+
+// getFolderEvaluator constructs accesscontrol.Evaluator that checks that any of the actions is granted on the folder
+func getFolderEvaluator(folderUID string, actions ...string) accesscontrol.Evaluator {
+	scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)
+	if len(actions) == 1 {
+		return accesscontrol.EvalPermission(actions[0], scope)
+	}
+	evals := make([]accesscontrol.Evaluator, 0, len(actions))
+	for _, action := range actions {
+		evals = append(evals, accesscontrol.EvalPermission(action, scope))
+	}
+	return accesscontrol.EvalAny(evals...)
+}
+
 // getRulesReadEvaluator constructs accesscontrol.Evaluator that checks all permission required to read all provided rules
 func (r *RuleService) getRulesReadEvaluator(rules ...*models.AlertRule) accesscontrol.Evaluator {
 	return r.getRulesQueryEvaluator(rules...)
@@ -55,8 +131,14 @@ func (r *RuleService) getRulesReadEvaluator(rules ...*models.AlertRule) accessco
 
 // getRulesQueryEvaluator constructs accesscontrol.Evaluator that checks all permissions to query data sources used by the provided rules
 func (r *RuleService) getRulesQueryEvaluator(rules ...*models.AlertRule) accesscontrol.Evaluator {
+	return getDatasourcesQueryEvaluator(getRulesDatasourceUIDs(rules...))
+}
+
+// getRulesDatasourceUIDs returns the UIDs of the data sources queried by the provided rules, each once. Expressions are
+// not data sources.
+func getRulesDatasourceUIDs(rules ...*models.AlertRule) []string {
 	added := make(map[string]struct{}, 2)
-	evals := make([]accesscontrol.Evaluator, 0, 2)
+	uids := make([]string, 0, 2)
 	for _, rule := range rules {
 		for _, query := range rule.Data {
 			if query.QueryType == expr.DatasourceType || query.DatasourceUID == expr.DatasourceUID || query.
@@ -66,16 +148,51 @@ func (r *RuleService) getRulesQueryEvaluator(rules ...*models.AlertRule) accessc
 			if _, ok := added[query.DatasourceUID]; ok {
 				continue
 			}
-			evals = append(evals, accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID)))
+			uids = append(uids, query.DatasourceUID)
 			added[query.DatasourceUID] = struct{}{}
 		}
 	}
+	return uids
+}
+
+// getDatasourcesQueryEvaluator constructs accesscontrol.Evaluator that checks the permissions to query all the data sources
+func getDatasourcesQueryEvaluator(uids []string) accesscontrol.Evaluator {
+	evals := make([]accesscontrol.Evaluator, 0, len(uids))
+	for _, uid := range uids {
+		evals = append(evals, accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid)))
+	}
 	if len(evals) == 1 {
 		return evals[0]
 	}
 	return accesscontrol.EvalAll(evals...)
 }
 
+// hasRulesReadAccess returns true if the identity.Requester can read all the rules. If the context has a
+// RequestAuthorizationCache, the decision is cached in it, see WithRequestAuthorizationCache.
+func (r *RuleService) hasRulesReadAccess(ctx context.Context, user identity.Requester, rules ...*models.AlertRule) (bool, error) {
+	uids := getRulesDatasourceUIDs(rules...)
+	cache := requestAuthorizationCacheFromContext(ctx)
+	if cache == nil {
+		return r.HasAccess(ctx, user, getDatasourcesQueryEvaluator(uids))
+	}
+	namespace, id := user.GetNamespacedID()
+	key := requestDecisionKey{
+		orgID:    user.GetOrgID(),
+		identity: namespace + ":" + id,
+		group:    datasourcesKey(uids),
+		scopes:   joinKey(user.GetPermissions()[datasources.ActionQuery]),
+	}
+	if allowed, ok := cache.get(key); ok {
+		return allowed, nil
+	}
+	allowed, err := r.HasAccess(ctx, user, getDatasourcesQueryEvaluator(uids))
+	if err != nil {
+		return false, err
+	}
+	cache.set(key, allowed)
+	return allowed, nil
+}
+
 // AuthorizeDatasourceAccessForRule checks that user has access to all data sources declared by the rule
 func (r *RuleService) AuthorizeDatasourceAccessForRule(ctx context.Context, user identity.Requester, rule *models.AlertRule) error {
 	ds := r.getRulesQueryEvaluator(rule)
@@ -88,30 +205,55 @@ func (r *RuleService) AuthorizeDatasourceAccessForRule(ctx context.Context, user
 	})
 }
 
-// HasAccessToRuleGroup returns false if
+// HasAccessToRuleGroup returns false if the user cannot query the data sources of the rules of the group. The access to
+// the folder of the group is not checked, see HasAccessInFolder.
 func (r *RuleService) HasAccessToRuleGroup(ctx context.Context, user identity.Requester, rules models.RulesGroup) (bool, error) {
-	eval := r.getRulesReadEvaluator(rules...)
-	return r.HasAccess(ctx, user, eval)
+	return r.hasRulesReadAccess(ctx, user, rules...)
+}
+
+// HasAccessInFolder returns true if the user can read the alert rules of the folder.
+func (r *RuleService) HasAccessInFolder(ctx context.Context, user identity.Requester, folderUID string) (bool, error) {
+	return r.hasFolderAccess(ctx, user, folderUID, ruleRead)
 }
 
 // AuthorizeAccessToRuleGroup checks all rules against AuthorizeDatasourceAccessForRule and exits on the first negative result
 func (r *RuleService) AuthorizeAccessToRuleGroup(ctx context.Context, user identity.Requester, rules models.RulesGroup) error {
-	eval := r.getRulesReadEvaluator(rules...)
-	return r.HasAccessOrError(ctx, user, eval, func() string {
+	has, err := r.hasRulesReadAccess(ctx, user, rules...)
+	if err != nil {
+		return err
+	}
+	if !has {
 		var groupName, folderUID string
 		if len(rules) > 0 {
 			groupName = rules[0].RuleGroup
 			folderUID = rules[0].NamespaceUID
 		}
-		return fmt.Sprintf("access rule group '%s' in folder '%s'", groupName, folderUID)
-	})
+		action := fmt.Sprintf("access rule group '%s' in folder '%s'", groupName, folderUID)
+		return r.authorizationError(ctx, user, action, r.getRulesReadEvaluator(rules...))
+	}
+	return nil
+}
+
+// CanWriteAllRules returns true if the user is allowed to change all alert rules of the organization through the
+// provisioning API, regardless of the folders and data sources of the rules.
+func (r *RuleService) CanWriteAllRules(ctx context.Context, user identity.Requester) (bool, error) {
+	return r.HasAccess(ctx, user, accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningWrite))
+}
+
+// CanReadAllRules returns true if the user is allowed to read all alert rules of the organization through the
+// provisioning API, regardless of the folders and data sources of the rules.
+func (r *RuleService) CanReadAllRules(ctx context.Context, user identity.Requester) (bool, error) {
+	return r.HasAccess(ctx, user, accesscontrol.EvalAny(
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningRead),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadSecrets),
+	))
 }
 
 // AuthorizeRuleChanges analyzes changes in the rule group, and checks whether the changes are authorized.
 // NOTE: if there are rules for deletion, and the user does not have access to data sources that a rule uses, the rule is removed from the list.
 // If the user is not authorized to perform the changes the function returns ErrAuthorization with a description of what action is not authorized.
 func (r *RuleService) AuthorizeRuleChanges(ctx context.Context, user identity.Requester, change *store.GroupDelta) error {
-	namespaceScope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(change.GroupKey.NamespaceUID)
+	namespaceUID := change.GroupKey.NamespaceUID
 
 	rules, ok := change.AffectedGroups[change.GroupKey]
 	if ok { // not ok can be when user creates a new rule group or moves existing alerts to a new group
@@ -124,9 +266,9 @@ func (r *RuleService) AuthorizeRuleChanges(ctx context.Context, user identity.Re
 	}
 
 	if len(change.Delete) > 0 {
-		if err := r.HasAccessOrError(ctx, user, accesscontrol.EvalPermission(ruleDelete, namespaceScope), func() string {
+		if err := r.hasFolderAccessOrError(ctx, user, namespaceUID, func() string {
 			return fmt.Sprintf("delete alert rules that belong to folder %s", change.GroupKey.NamespaceUID)
-		}); err != nil {
+		}, ruleDelete); err != nil {
 			return err
 		}
 		for _, rule := range change.Delete {
@@ -138,11 +280,11 @@ func (r *RuleService) AuthorizeRuleChanges(ctx context.Context, user identity.Re
 		}
 	}
 
-	var addAuthorized, updateAuthorized bool // these are needed to check authorization for the rule create\update only once
+	var addAuthorized, updateAuthorized, intervalUpdateAuthorized bool // these are needed to check authorization for the rule create\update only once
 	if len(change.New) > 0 {
-		if err := r.HasAccessOrError(ctx, user, accesscontrol.EvalPermission(ruleCreate, namespaceScope), func() string {
+		if err := r.hasFolderAccessOrError(ctx, user, namespaceUID, func() string {
 			return fmt.Sprintf("create alert rules in the folder %s", change.GroupKey.NamespaceUID)
-		}); err != nil {
+		}, ruleCreate); err != nil {
 			return err
 		}
 		addAuthorized = true
@@ -164,28 +306,39 @@ func (r *RuleService) AuthorizeRuleChanges(ctx context.Context, user identity.Re
 
 		// Check if the rule is moved from one folder to the current. If yes, then the user must have the authorization to delete rules from the source folder and add rules to the target folder.
 		if rule.Existing.NamespaceUID != rule.New.NamespaceUID {
-			ev := accesscontrol.EvalPermission(ruleDelete, dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.Existing.NamespaceUID))
-			if err := r.HasAccessOrError(ctx, user, ev, func() string {
+			if err := r.hasFolderAccessOrError(ctx, user, rule.Existing.NamespaceUID, func() string {
 				return fmt.Sprintf("move alert rules from folder %s", rule.Existing.NamespaceUID)
-			}); err != nil {
+			}, ruleDelete); err != nil {
 				return err
 			}
 
 			if !addAuthorized {
-				if err := r.HasAccessOrError(ctx, user, accesscontrol.EvalPermission(ruleCreate, namespaceScope), func() string {
+				if err := r.hasFolderAccessOrError(ctx, user, namespaceUID, func() string {
 					return fmt.Sprintf("move alert rules to folder '%s'", change.GroupKey.NamespaceUID)
-				}); err != nil {
+				}, ruleCreate); err != nil {
 					return err
 				}
 				addAuthorized = true
 			}
 		} else if !updateAuthorized { // if it is false then the authorization was not checked. If it is true then the user is authorized to update rules
-			if err := r.HasAccessOrError(ctx, user, accesscontrol.EvalPermission(ruleUpdate, namespaceScope), func() string {
-				return fmt.Sprintf("update alert rules that belongs to folder '%s'", change.GroupKey.NamespaceUID)
-			}); err != nil {
-				return err
+			if rule.IsIntervalOnly() {
+				// Changing only the evaluation interval requires a narrower permission.
+				if !intervalUpdateAuthorized {
+					if err := r.hasFolderAccessOrError(ctx, user, namespaceUID, func() string {
+						return fmt.Sprintf("update the evaluation interval of alert rules that belongs to folder '%s'", change.GroupKey.NamespaceUID)
+					}, ruleUpdate, ruleIntervalUpdate); err != nil {
+						return err
+					}
+					intervalUpdateAuthorized = true
+				}
+			} else {
+				if err := r.hasFolderAccessOrError(ctx, user, namespaceUID, func() string {
+					return fmt.Sprintf("update alert rules that belongs to folder '%s'", change.GroupKey.NamespaceUID)
+				}, ruleUpdate); err != nil {
+					return err
+				}
+				updateAuthorized = true
 			}
-			updateAuthorized = true
 		}
 
 		if rule.Existing.NamespaceUID != rule.New.NamespaceUID || rule.Existing.RuleGroup != rule.New.RuleGroup {
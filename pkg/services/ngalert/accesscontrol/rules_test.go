@@ -2,6 +2,7 @@ package accesscontrol
 
 import (
 	"context"
+	"errors"
 	"math"
 	"math/rand"
 	"testing"
@@ -185,6 +186,39 @@ func TestAuthorizeRuleChanges(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "if only the interval of rules is updated it should check interval update action and access to datasource",
+			changes: func() *store.GroupDelta {
+				rules := models.GenerateAlertRules(rand.Intn(4)+1, models.AlertRuleGen(models.WithGroupKey(groupKey)))
+				updates := make([]store.RuleDelta, 0, len(rules))
+
+				for _, rule := range rules {
+					cp := models.CopyRule(rule)
+					cp.IntervalSeconds = rule.IntervalSeconds + 10
+					updates = append(updates, store.RuleDelta{
+						Existing: rule,
+						New:      cp,
+						Diff:     rule.Diff(cp, store.AlertRuleFieldsToIgnoreInDiff[:]...),
+					})
+				}
+
+				return &store.GroupDelta{
+					GroupKey: groupKey,
+					AffectedGroups: map[models.AlertRuleGroupKey]models.RulesGroup{
+						groupKey: rules,
+					},
+					Update: updates,
+				}
+			},
+			permissions: func(c *store.GroupDelta) map[string][]string {
+				return map[string][]string{
+					ruleIntervalUpdate: {
+						namespaceIdScope,
+					},
+					datasources.ActionQuery: getDatasourceScopesForRules(c.AffectedGroups[c.GroupKey]),
+				}
+			},
+		},
 		{
 			name: "if there are rules that are moved between namespaces it should check delete+add action and access to group where rules come from",
 			changes: func() *store.GroupDelta {
@@ -457,3 +491,100 @@ func Test_authorizeAccessToRuleGroup(t *testing.T) {
 		require.Error(t, result)
 	})
 }
+
+func TestHasAccessInFolder(t *testing.T) {
+	user := createUserWithPermissions(map[string][]string{
+		ruleRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("infra")},
+	})
+	svc := RuleService{
+		ac: &recordingAccessControlFake{},
+	}
+
+	has, err := svc.HasAccessInFolder(context.Background(), user, "infra")
+	require.NoError(t, err)
+	require.True(t, has)
+
+	has, err = svc.HasAccessInFolder(context.Background(), user, "platform")
+	require.NoError(t, err)
+	require.False(t, has)
+}
+
+func TestAuthorizationErrorMissingPermissions(t *testing.T) {
+	rule := models.AlertRuleGen()()
+	permissions := map[string][]string{
+		dashboards.ActionFoldersRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(rule.NamespaceUID)},
+	}
+	svc := RuleService{
+		ac: &recordingAccessControlFake{},
+	}
+
+	err := svc.AuthorizeAccessToRuleGroup(context.Background(), createUserWithPermissions(permissions), models.RulesGroup{rule})
+	require.Error(t, err)
+	require.True(t, IsAuthorizationError(err))
+
+	expected := make([]MissingPermission, 0, len(rule.Data))
+	added := make(map[string]struct{}, len(rule.Data))
+	for _, query := range rule.Data {
+		if _, ok := added[query.DatasourceUID]; ok || expr.IsDataSource(query.DatasourceUID) {
+			continue
+		}
+		added[query.DatasourceUID] = struct{}{}
+		expected = append(expected, MissingPermission{
+			Action: datasources.ActionQuery,
+			Scope:  datasources.ScopeProvider.GetResourceScopeUID(query.DatasourceUID),
+		})
+	}
+	assert.ElementsMatch(t, expected, GetMissingPermissions(err))
+
+	t.Run("should include folder of missing folder scopes", func(t *testing.T) {
+		err := NewAuthorizationErrorWithMissingPermissions("test", accesscontrol.EvalPermission(dashboards.ActionFoldersRead, dashboards.ScopeFoldersProvider.GetResourceScopeUID("test-folder")), map[string][]string{
+			dashboards.ActionFoldersRead: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("test-folder")},
+		})
+		assert.Equal(t, []MissingPermission{
+			{
+				Action:    dashboards.ActionFoldersRead,
+				Scope:     dashboards.ScopeFoldersProvider.GetResourceScopeUID("test-folder"),
+				FolderUID: "test-folder",
+			},
+		}, GetMissingPermissions(err))
+	})
+
+	t.Run("should not include permissions granted through resolved scopes", func(t *testing.T) {
+		parent := dashboards.ScopeFoldersProvider.GetResourceScopeUID("parent")
+		child := dashboards.ScopeFoldersProvider.GetResourceScopeUID("child")
+		svc := RuleService{
+			ac: &recordingAccessControlFake{
+				// the scope of the child folder resolves to the scope of its parent
+				Callback: func(user identity.Requester, evaluator accesscontrol.Evaluator) (bool, error) {
+					resolved, err := evaluator.MutateScopes(context.Background(), func(_ context.Context, scope string) ([]string, error) {
+						if scope == child {
+							return []string{child, parent}, nil
+						}
+						return []string{scope}, nil
+					})
+					if err != nil {
+						return false, err
+					}
+					return resolved.Evaluate(user.GetPermissions()), nil
+				},
+			},
+		}
+		usr := createUserWithPermissions(map[string][]string{
+			dashboards.ActionFoldersRead: {parent},
+		})
+
+		err := svc.HasAccessOrError(context.Background(), usr, accesscontrol.EvalAll(
+			accesscontrol.EvalPermission(dashboards.ActionFoldersRead, child),
+			accesscontrol.EvalPermission(accesscontrol.ActionAlertingRuleCreate, child),
+		), func() string { return "test" })
+		require.True(t, IsAuthorizationError(err))
+		assert.Equal(t, []MissingPermission{
+			{Action: accesscontrol.ActionAlertingRuleCreate, Scope: child, FolderUID: "child"},
+		}, GetMissingPermissions(err))
+	})
+
+	t.Run("should not return missing permissions for other errors", func(t *testing.T) {
+		assert.False(t, IsAuthorizationError(errors.New("test")))
+		assert.Nil(t, GetMissingPermissions(errors.New("test")))
+	})
+}
@@ -47,6 +47,11 @@ type RuleAccessControlService interface {
 	AuthorizeDatasourceAccessForRule(ctx context.Context, user identity.Requester, rule *models.AlertRule) error
 }
 
+// UserPermissionsService provides the permissions of any user of an organization.
+type UserPermissionsService interface {
+	SearchUserPermissions(ctx context.Context, orgID int64, filterOptions ac.SearchOptions) ([]ac.Permission, error)
+}
+
 // API handlers.
 type API struct {
 	Cfg                  *setting.Cfg
@@ -63,12 +68,22 @@ type API struct {
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	StateManager         *state.Manager
 	AccessControl        ac.AccessControl
+	AccessControlService UserPermissionsService
+	FolderAuthzCache     *accesscontrol.FolderAuthorizationCache
 	Policies             *provisioning.NotificationPolicyService
 	ReceiverService      *notifier.ReceiverService
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
 	AlertRules           *provisioning.AlertRuleService
+	AlertmanagerRouting  *provisioning.AlertmanagerRoutingService
+	ConfigSnapshots      *provisioning.ConfigSnapshotService
+	Bulk                 *provisioning.BulkService
+	Tags                 *provisioning.TagService
+	RuleUsage            *provisioning.RuleUsageService
+	RuleSync             *provisioning.RuleSyncService
+	RuleGroupJobs        *provisioning.RuleGroupJobService
+	ProvisioningWebhook  *provisioning.ProvisioningWebhook
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
@@ -88,7 +103,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		DataProxy: api.DataProxy,
 		ac:        api.AccessControl,
 	}
-	ruleAuthzService := accesscontrol.NewRuleService(api.AccessControl)
+	ruleAuthzService := accesscontrol.NewRuleServiceWithCache(api.AccessControl, api.FolderAuthzCache)
 
 	// Register endpoints for proxying to Alertmanager-compatible backends.
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
@@ -115,6 +130,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                logger,
 			cfg:                &api.Cfg.UnifiedAlerting,
 			authz:              ruleAuthzService,
+			permissions:        api.AccessControlService,
 			amConfigStore:      api.AlertingStore,
 			amRefresher:        api.MultiOrgAlertmanager,
 			featureManager:     api.FeatureManager,
@@ -138,6 +154,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		&ConfigSrv{
 			datasourceService:    api.DatasourceService,
 			store:                api.AdminConfigStore,
+			provenanceStore:      api.ProvenanceStore,
 			log:                  logger,
 			alertmanagerProvider: api.AlertsRouter,
 		},
@@ -150,6 +167,19 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		alertmanagerRouting: api.AlertmanagerRouting,
+		configSnapshots:     api.ConfigSnapshots,
+		bulk:                api.Bulk,
+		tags:                api.Tags,
+		ruleUsage:           api.RuleUsage,
+		ruleSync:            api.RuleSync,
+		ruleGroupJobs:       api.RuleGroupJobs,
+		webhook:             api.ProvisioningWebhook,
+		xact:                api.TransactionManager,
+		datasources:         api.DatasourceCache,
+		states:              api.StateManager,
+		deliveries:          api.MultiOrgAlertmanager,
+		exportSource:        api.Cfg.AppURL,
 	}), m)
 
 	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
@@ -3,6 +3,7 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,6 +27,12 @@ func (srv AlertmanagerSrv) provenanceGuard(currentConfig apimodels.GettableUserC
 	if err := checkContactPoints(srv.log, currentConfig.AlertmanagerConfig.Receivers, newConfig.AlertmanagerConfig.Receivers); err != nil {
 		return err
 	}
+	if err := checkDisabledIntegrations(currentConfig, newConfig); err != nil {
+		return err
+	}
+	if err := checkRateLimits(currentConfig, newConfig); err != nil {
+		return err
+	}
 	if err := checkMuteTimes(currentConfig, newConfig); err != nil {
 		return err
 	}
@@ -120,6 +127,37 @@ func checkContactPoints(l log.Logger, currReceivers []*apimodels.GettableApiRece
 	return nil
 }
 
+func checkDisabledIntegrations(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
+	for _, existingReceiver := range currentConfig.AlertmanagerConfig.Receivers {
+		for _, contactPoint := range existingReceiver.GrafanaManagedReceivers {
+			if contactPoint.Provenance == apimodels.Provenance(ngmodels.ProvenanceNone) {
+				continue
+			}
+			if slices.Contains(currentConfig.DisabledIntegrations, contactPoint.UID) != newConfig.IsIntegrationDisabled(contactPoint.UID) {
+				return fmt.Errorf("cannot enable or disable provisioned contact point '%s'", contactPoint.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func checkRateLimits(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
+	for _, existingReceiver := range currentConfig.AlertmanagerConfig.Receivers {
+		provisioned := slices.ContainsFunc(existingReceiver.GrafanaManagedReceivers, func(contactPoint *apimodels.GettableGrafanaReceiver) bool {
+			return contactPoint.Provenance != apimodels.Provenance(ngmodels.ProvenanceNone)
+		})
+		if !provisioned {
+			continue
+		}
+		currentLimit, hasCurrent := currentConfig.RateLimits[existingReceiver.Name]
+		newLimit, hasNew := newConfig.RateLimits[existingReceiver.Name]
+		if hasCurrent != hasNew || currentLimit != newLimit {
+			return fmt.Errorf("cannot change the rate limit of provisioned contact point '%s'", existingReceiver.Name)
+		}
+	}
+	return nil
+}
+
 func checkMuteTimes(currentConfig apimodels.GettableUserConfig, newConfig apimodels.PostableUserConfig) error {
 	newMTs := make(map[string]amConfig.MuteTimeInterval)
 	for _, newMuteTime := range newConfig.AlertmanagerConfig.MuteTimeIntervals {
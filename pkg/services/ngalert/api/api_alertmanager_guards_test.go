@@ -365,6 +365,119 @@ func defaultPostableReceiver(t *testing.T, uid string) *definitions.PostableApiR
 	}
 }
 
+func TestCheckDisabledIntegrations(t *testing.T) {
+	gettable := func(provenance models.Provenance, disabled ...string) definitions.GettableUserConfig {
+		cfg := definitions.GettableUserConfig{DisabledIntegrations: disabled}
+		cfg.AlertmanagerConfig.Receivers = []*definitions.GettableApiReceiver{defaultGettableReceiver(t, "123", provenance)}
+		return cfg
+	}
+	postable := func(disabled ...string) definitions.PostableUserConfig {
+		cfg := definitions.PostableUserConfig{DisabledIntegrations: disabled}
+		cfg.AlertmanagerConfig.Receivers = []*definitions.PostableApiReceiver{defaultPostableReceiver(t, "123")}
+		return cfg
+	}
+	tests := []struct {
+		name          string
+		shouldErr     bool
+		currentConfig definitions.GettableUserConfig
+		newConfig     definitions.PostableUserConfig
+	}{
+		{
+			name:          "keeping a provisioned integration disabled should not fail",
+			shouldErr:     false,
+			currentConfig: gettable(models.ProvenanceAPI, "123"),
+			newConfig:     postable("123"),
+		},
+		{
+			name:          "disabling a non provisioned integration should not fail",
+			shouldErr:     false,
+			currentConfig: gettable(models.ProvenanceNone),
+			newConfig:     postable("123"),
+		},
+		{
+			name:          "disabling a provisioned integration should fail",
+			shouldErr:     true,
+			currentConfig: gettable(models.ProvenanceAPI),
+			newConfig:     postable("123"),
+		},
+		{
+			name:          "enabling a provisioned integration should fail",
+			shouldErr:     true,
+			currentConfig: gettable(models.ProvenanceAPI, "123"),
+			newConfig:     postable(),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkDisabledIntegrations(test.currentConfig, test.newConfig)
+			if test.shouldErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckRateLimits(t *testing.T) {
+	limit := definitions.NotificationRateLimit{MaxNotifications: 10, Interval: model.Duration(time.Hour)}
+	gettable := func(provenance models.Provenance, limits map[string]definitions.NotificationRateLimit) definitions.GettableUserConfig {
+		cfg := definitions.GettableUserConfig{RateLimits: limits}
+		cfg.AlertmanagerConfig.Receivers = []*definitions.GettableApiReceiver{defaultGettableReceiver(t, "123", provenance)}
+		cfg.AlertmanagerConfig.Receivers[0].Name = "yeah"
+		return cfg
+	}
+	postable := func(limits map[string]definitions.NotificationRateLimit) definitions.PostableUserConfig {
+		cfg := definitions.PostableUserConfig{RateLimits: limits}
+		cfg.AlertmanagerConfig.Receivers = []*definitions.PostableApiReceiver{defaultPostableReceiver(t, "123")}
+		cfg.AlertmanagerConfig.Receivers[0].Name = "yeah"
+		return cfg
+	}
+	changed := limit
+	changed.MaxNotifications = 20
+	tests := []struct {
+		name          string
+		shouldErr     bool
+		currentConfig definitions.GettableUserConfig
+		newConfig     definitions.PostableUserConfig
+	}{
+		{
+			name:          "keeping the rate limit of a provisioned contact point should not fail",
+			shouldErr:     false,
+			currentConfig: gettable(models.ProvenanceAPI, map[string]definitions.NotificationRateLimit{"yeah": limit}),
+			newConfig:     postable(map[string]definitions.NotificationRateLimit{"yeah": limit}),
+		},
+		{
+			name:          "changing the rate limit of a non provisioned contact point should not fail",
+			shouldErr:     false,
+			currentConfig: gettable(models.ProvenanceNone, map[string]definitions.NotificationRateLimit{"yeah": limit}),
+			newConfig:     postable(map[string]definitions.NotificationRateLimit{"yeah": changed}),
+		},
+		{
+			name:          "changing the rate limit of a provisioned contact point should fail",
+			shouldErr:     true,
+			currentConfig: gettable(models.ProvenanceAPI, map[string]definitions.NotificationRateLimit{"yeah": limit}),
+			newConfig:     postable(map[string]definitions.NotificationRateLimit{"yeah": changed}),
+		},
+		{
+			name:          "removing the rate limit of a provisioned contact point should fail",
+			shouldErr:     true,
+			currentConfig: gettable(models.ProvenanceAPI, map[string]definitions.NotificationRateLimit{"yeah": limit}),
+			newConfig:     postable(nil),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkRateLimits(test.currentConfig, test.newConfig)
+			if test.shouldErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestCheckMuteTimes(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/util"
@@ -23,6 +24,7 @@ type ConfigSrv struct {
 	datasourceService    datasources.DataSourceService
 	alertmanagerProvider ExternalAlertmanagerProvider
 	store                store.AdminConfigurationStore
+	provenanceStore      provisioning.ProvisioningStore
 	log                  log.Logger
 }
 
@@ -75,6 +77,10 @@ func (srv ConfigSrv) RoutePostNGalertConfig(c *contextmodel.ReqContext, body api
 		return response.Error(http.StatusBadRequest, "Invalid alertmanager choice specified", err)
 	}
 
+	if err := srv.checkProvisionedRouting(c.Req.Context(), c.SignedInUser.GetOrgID()); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
 	externalAlertmanagers, err := srv.externalAlertmanagers(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Couldn't fetch the external Alertmanagers from datasources", err)
@@ -88,9 +94,19 @@ func (srv ConfigSrv) RoutePostNGalertConfig(c *contextmodel.ReqContext, body api
 		SendAlertsTo: sendAlertsTo,
 		OrgID:        c.SignedInUser.GetOrgID(),
 	}
+	// Keep splitting the alerts between the Alertmanagers if they are still sent to all of them.
+	if sendAlertsTo == ngmodels.AllAlertmanagers {
+		current, err := srv.store.GetAdminConfiguration(cfg.OrgID)
+		if err != nil && !errors.Is(err, store.ErrNoAdminConfiguration) {
+			return ErrResp(http.StatusInternalServerError, err, "failed to fetch admin configuration from the database")
+		}
+		if current != nil {
+			cfg.ExternalAlertmanagerMatchers = current.ExternalAlertmanagerMatchers
+		}
+	}
 
 	cmd := store.UpdateAdminConfigurationCmd{AdminConfiguration: cfg}
-	if err := srv.store.UpdateAdminConfiguration(cmd); err != nil {
+	if err := srv.store.UpdateAdminConfiguration(c.Req.Context(), cmd); err != nil {
 		msg := "failed to save the admin configuration to the database"
 		srv.log.Error(msg, "error", err)
 		return ErrResp(http.StatusBadRequest, err, msg)
@@ -104,7 +120,11 @@ func (srv ConfigSrv) RouteDeleteNGalertConfig(c *contextmodel.ReqContext) respon
 		return accessForbiddenResp()
 	}
 
-	err := srv.store.DeleteAdminConfiguration(c.SignedInUser.GetOrgID())
+	if err := srv.checkProvisionedRouting(c.Req.Context(), c.SignedInUser.GetOrgID()); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	err := srv.store.DeleteAdminConfiguration(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
 		srv.log.Error("Unable to delete configuration", "error", err)
 		return ErrResp(http.StatusInternalServerError, err, "")
@@ -113,6 +133,19 @@ func (srv ConfigSrv) RouteDeleteNGalertConfig(c *contextmodel.ReqContext) respon
 	return response.JSON(http.StatusOK, util.DynMap{"message": "admin configuration deleted"})
 }
 
+// checkProvisionedRouting returns an error if the routing of the alerts of the organization is managed by the
+// provisioning API or files, and therefore cannot be changed by the admin configuration API.
+func (srv ConfigSrv) checkProvisionedRouting(ctx context.Context, orgID int64) error {
+	provenance, err := srv.provenanceStore.GetProvenance(ctx, &apimodels.AlertmanagerRouting{}, orgID)
+	if err != nil {
+		return err
+	}
+	if provenance != ngmodels.ProvenanceNone {
+		return fmt.Errorf("cannot change the alertmanager routing with provenance '%s' with the admin configuration API", provenance)
+	}
+	return nil
+}
+
 // externalAlertmanagers returns the URL of any external alertmanager that is
 // configured as datasource. The URL does not contain any auth.
 func (srv ConfigSrv) externalAlertmanagers(ctx context.Context, orgID int64) ([]string, error) {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
@@ -11,7 +12,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/org"
 )
 
@@ -92,12 +95,33 @@ func TestExternalAlertmanagerChoice(t *testing.T) {
 	}
 }
 
+func TestProvisionedAlertmanagerRouting(t *testing.T) {
+	ctx := createRequestCtxInOrg(1)
+	ctx.OrgRole = org.RoleAdmin
+	sut := createAPIAdminSut(t, nil)
+	err := sut.provenanceStore.SetProvenance(context.Background(), &definitions.AlertmanagerRouting{}, 1, ngmodels.ProvenanceFile)
+	require.NoError(t, err)
+
+	t.Run("admin configuration cannot be changed", func(t *testing.T) {
+		resp := sut.RoutePostNGalertConfig(ctx, definitions.PostableNGalertConfig{
+			AlertmanagersChoice: definitions.InternalAlertmanager,
+		})
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("admin configuration cannot be deleted", func(t *testing.T) {
+		resp := sut.RouteDeleteNGalertConfig(ctx)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+}
+
 func createAPIAdminSut(t *testing.T,
 	datasources []*datasources.DataSource) ConfigSrv {
 	return ConfigSrv{
 		datasourceService: &fakeDatasources.FakeDataSourceService{
 			DataSources: datasources,
 		},
-		store: store.NewFakeAdminConfigStore(t),
+		store:           store.NewFakeAdminConfigStore(t),
+		provenanceStore: fakes.NewFakeProvisioningStore(),
 	}
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -107,8 +108,13 @@ func getPanelIDFromRequest(r *http.Request) (int64, error) {
 }
 
 func getMatchersFromRequest(r *http.Request) (labels.Matchers, error) {
+	return parseMatchers(r.URL.Query()["matcher"])
+}
+
+// parseMatchers parses JSON-encoded label matchers.
+func parseMatchers(values []string) (labels.Matchers, error) {
 	var matchers labels.Matchers
-	for _, s := range r.URL.Query()["matcher"] {
+	for _, s := range values {
 		var m labels.Matcher
 		if err := json.Unmarshal([]byte(s), &m); err != nil {
 			return nil, err
@@ -204,6 +210,8 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		NamespaceUIDs: namespaceUIDs,
 		DashboardUID:  dashboardUID,
 		PanelID:       panelID,
+		// The rules of archived groups are not evaluated, so they have no state.
+		ArchivedGroups: ngmodels.ArchivedRuleGroupsExclude,
 	}
 	ruleList, err := srv.store.ListAlertRules(c.Req.Context(), &alertRuleQuery)
 	if err != nil {
@@ -228,6 +236,8 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 		ngmodels.AlertRulesBy(ngmodels.AlertRulesByIndex).Sort(groupRules)
 	}
 
+	// The groups that query the same data sources share the decision of the authorization of the user.
+	authzCtx := accesscontrol.WithRequestAuthorizationCache(c.Req.Context())
 	rulesTotals := make(map[string]int64, len(groupedRules))
 	for groupKey, rules := range groupedRules {
 		folder := namespaceMap[groupKey.NamespaceUID]
@@ -235,7 +245,7 @@ func (srv PrometheusSrv) RouteGetRuleStatuses(c *contextmodel.ReqContext) respon
 			srv.log.Warn("Query returned rules that belong to folder the user does not have access to. All rules that belong to that namespace will not be added to the response", "folder_uid", groupKey.NamespaceUID)
 			continue
 		}
-		ok, err := srv.authz.HasAccessToRuleGroup(c.Req.Context(), c.SignedInUser, rules)
+		ok, err := srv.authz.HasAccessToRuleGroup(authzCtx, c.SignedInUser, rules)
 		if err != nil {
 			return response.ErrOrFallback(http.StatusInternalServerError, "cannot authorize access to rule group", err)
 		}
@@ -2,25 +2,42 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 const disableProvenanceHeaderName = "X-Disable-Provenance"
 
+// analyzeRulesHeaderName is the header of requests that ask for the analysis of the written rules. The warnings of the
+// analysis are returned in Warning headers of the response.
+const analyzeRulesHeaderName = "X-Analyze-Rules"
+
 type ProvisioningSrv struct {
 	log                 log.Logger
 	policies            NotificationPolicyService
@@ -28,6 +45,24 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	alertmanagerRouting AlertmanagerRoutingService
+	configSnapshots     ConfigSnapshotService
+	bulk                BulkService
+	tags                TagService
+	ruleUsage           RuleUsageService
+	ruleSync            RuleSyncService
+	ruleGroupJobs       RuleGroupJobService
+	webhook             ProvisioningWebhook
+	// xact runs the reads of exports in a snapshot of the store if consistent exports are requested.
+	xact provisioning.TransactionManager
+	// datasources is used to find the types of the data sources of rules, whose queries are estimated in cost.
+	datasources datasources.CacheService
+	// states is used to join the rules with the current state of their alerts.
+	states RuleStateReader
+	// deliveries is used to read the recent delivery attempts of contact points.
+	deliveries DeliveryAttemptReader
+	// exportSource identifies this instance in the metadata of exports.
+	exportSource string
 }
 
 type ContactPointService interface {
@@ -37,6 +72,11 @@ type ContactPointService interface {
 	DeleteContactPoint(ctx context.Context, orgID int64, uid string) error
 }
 
+// DeliveryAttemptReader reads the recent attempts to deliver notifications through the integrations of contact points.
+type DeliveryAttemptReader interface {
+	GetDeliveryAttempts(orgID int64, integrationUID string) ([]notifier.DeliveryAttempt, error)
+}
+
 type TemplateService interface {
 	GetTemplates(ctx context.Context, orgID int64) ([]definitions.NotificationTemplate, error)
 	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error)
@@ -45,10 +85,67 @@ type TemplateService interface {
 
 type NotificationPolicyService interface {
 	GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
+	GetEffectivePolicy(ctx context.Context, orgID int64, routePath []int) (definitions.EffectivePolicy, error)
 	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p alerting_models.Provenance) error
+	MergePolicyTree(ctx context.Context, orgID int64, anchor definitions.ObjectMatchers, policies []*definitions.Route, overwrite bool, p alerting_models.Provenance) (definitions.Route, error)
 	ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
 }
 
+type AlertmanagerRoutingService interface {
+	GetAlertmanagerRouting(ctx context.Context, orgID int64) (definitions.AlertmanagerRouting, error)
+	UpdateAlertmanagerRouting(ctx context.Context, orgID int64, routing definitions.AlertmanagerRouting, p alerting_models.Provenance) error
+}
+
+type ConfigSnapshotService interface {
+	GetSnapshots(ctx context.Context, orgID int64) ([]*alerting_models.ConfigSnapshot, error)
+	CreateSnapshot(ctx context.Context, orgID int64) (*alerting_models.ConfigSnapshot, error)
+	RestoreAlertingConfig(ctx context.Context, orgID int64, snapshotID int64, mode alerting_models.ConfigSnapshotRestoreMode, user identity.Requester, p alerting_models.Provenance) error
+}
+
+type BulkService interface {
+	InstallRuleGroups(ctx context.Context, orgIDs []int64, groups []alerting_models.AlertRuleGroup, userID int64, p alerting_models.Provenance) ([]provisioning.OrgResult, error)
+	UpdateContactPointSecret(ctx context.Context, orgIDs []int64, name, key, value string, p alerting_models.Provenance) ([]provisioning.OrgResult, error)
+	ApplyPolicy(ctx context.Context, orgIDs []int64, policy definitions.Route, p alerting_models.Provenance) ([]provisioning.OrgResult, error)
+}
+
+// RuleStateReader returns the current state of the alerts of alert rules.
+type RuleStateReader interface {
+	GetStatesForRuleUID(orgID int64, alertRuleUID string) []*state.State
+}
+
+type TagService interface {
+	GetTags(ctx context.Context, orgID int64, o alerting_models.Provisionable) ([]string, error)
+	SetTags(ctx context.Context, orgID int64, o alerting_models.Provisionable, tags []string) ([]string, error)
+	FilterRules(ctx context.Context, orgID int64, tag string, rules []*alerting_models.AlertRule) ([]*alerting_models.AlertRule, error)
+	FilterRuleGroups(ctx context.Context, orgID int64, tag string, groups []alerting_models.AlertRuleGroupWithFolderTitle) ([]alerting_models.AlertRuleGroupWithFolderTitle, error)
+	FilterContactPoints(ctx context.Context, orgID int64, tag string, contactPoints []definitions.EmbeddedContactPoint) ([]definitions.EmbeddedContactPoint, error)
+	SetRulesPausedByTag(ctx context.Context, user identity.Requester, orgID int64, tag string, paused bool, p alerting_models.Provenance) (int, error)
+}
+
+// RuleSyncService reports the results of the sync of the alert rules of a directory.
+type RuleSyncService interface {
+	GetStatus(ctx context.Context, orgID int64) (provisioning.RuleSyncStatus, error)
+}
+
+// RuleGroupJobService replaces rule groups in the background, for the replacements that exceed the changes limit of a
+// single update.
+type RuleGroupJobService interface {
+	StartReplaceRuleGroup(ctx context.Context, orgID int64, group alerting_models.AlertRuleGroup, user identity.Requester, provenance alerting_models.Provenance) (alerting_models.RuleGroupJob, error)
+	GetJob(ctx context.Context, orgID int64, uid string) (alerting_models.RuleGroupJob, error)
+}
+
+// ProvisioningWebhook keeps the events that could not be posted to the provisioning webhook, so that they can be
+// replayed.
+type ProvisioningWebhook interface {
+	ListDeadLetters(ctx context.Context, orgID int64) ([]alerting_models.WebhookDelivery, error)
+	ReplayDeadLetter(ctx context.Context, orgID int64, uid string) (alerting_models.WebhookDelivery, error)
+}
+
+type RuleUsageService interface {
+	GetRuleUsage(ctx context.Context, user identity.Requester, query provisioning.RuleUsageQuery) (*provisioning.RuleUsageResult, error)
+	GetRuleGroupNoiseReport(ctx context.Context, user identity.Requester, query provisioning.RuleUsageQuery, sortBy string, limit int) (*provisioning.RuleGroupNoiseReport, error)
+}
+
 type MuteTimingService interface {
 	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
 	GetMuteTiming(ctx context.Context, name string, orgID int64) (definitions.MuteTimeInterval, error)
@@ -58,17 +155,45 @@ type MuteTimingService interface {
 }
 
 type AlertRuleService interface {
-	GetAlertRules(ctx context.Context, orgID int64) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
+	GetAlertRules(ctx context.Context, user identity.Requester, query alerting_models.ListAlertRulesQuery) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
+	SearchAlertRules(ctx context.Context, query alerting_models.SearchAlertRulesQuery) (*alerting_models.SearchAlertRulesResult, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
 	CreateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance, userID int64) (alerting_models.AlertRule, error)
 	UpdateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
+	UpdateAlertRuleIfUnchanged(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
 	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance alerting_models.Provenance) error
 	GetRuleGroup(ctx context.Context, orgID int64, folder, group string) (alerting_models.AlertRuleGroup, error)
-	ReplaceRuleGroup(ctx context.Context, orgID int64, group alerting_models.AlertRuleGroup, userID int64, provenance alerting_models.Provenance) error
+	UpdateRuleGroup(ctx context.Context, user identity.Requester, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64, provenance alerting_models.Provenance) error
+	ReplaceRuleGroupWithDefaults(ctx context.Context, orgID int64, group alerting_models.AlertRuleGroup, userID int64, provenance alerting_models.Provenance) ([]provisioning.ServerDefault, error)
 	DeleteRuleGroup(ctx context.Context, orgID int64, folder, group string, provenance alerting_models.Provenance) error
 	GetAlertRuleWithFolderTitle(ctx context.Context, orgID int64, ruleUID string) (provisioning.AlertRuleWithFolderTitle, error)
 	GetAlertRuleGroupWithFolderTitle(ctx context.Context, orgID int64, folder, group string) (alerting_models.AlertRuleGroupWithFolderTitle, error)
-	GetAlertGroupsWithFolderTitle(ctx context.Context, orgID int64, folderUIDs []string) ([]alerting_models.AlertRuleGroupWithFolderTitle, error)
+	GetAlertGroupsWithFolderTitle(ctx context.Context, orgID int64, folderUIDs []string, archived alerting_models.ArchivedRuleGroupsFilter) ([]alerting_models.AlertRuleGroupWithFolderTitle, error)
+	SetAlertGroupsFolderPaths(ctx context.Context, orgID int64, groups []alerting_models.AlertRuleGroupWithFolderTitle) error
+	GetFolderAnnotations(ctx context.Context, orgID int64, folderUID string) (map[string]string, error)
+	SetFolderAnnotations(ctx context.Context, orgID int64, folderUID string, annotations map[string]string) error
+	GetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string) (bool, error)
+	SetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string, paused bool) error
+	ChangeProvenance(ctx context.Context, userID int64, orgID int64, ruleUID string, from, to alerting_models.Provenance) error
+	ChangeRuleGroupProvenance(ctx context.Context, userID int64, orgID int64, namespaceUID string, group string, from, to alerting_models.Provenance) error
+	ListDeletedRules(ctx context.Context, orgID int64) ([]*alerting_models.DeletedAlertRule, error)
+	RestoreAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance alerting_models.Provenance, userID int64) (alerting_models.AlertRule, error)
+	GenerateRuleGroup(ctx context.Context, userID int64, orgID int64, group alerting_models.AlertRuleGroup, tmpl alerting_models.AlertRule, inventory []map[string]string, provenance alerting_models.Provenance) (alerting_models.AlertRuleGroup, error)
+	PatchRuleMetadata(ctx context.Context, user identity.Requester, orgID int64, selector alerting_models.ListAlertRulesQuery, patch provisioning.RuleMetadataPatch, provenance alerting_models.Provenance) (int, error)
+	GetQuotaStatus(ctx context.Context, orgID int64) (provisioning.QuotaStatus, error)
+	GetRuleTitleUniqueness(ctx context.Context, orgID int64) (provisioning.RuleTitleUniquenessPolicy, bool, error)
+	SetRuleTitleUniqueness(ctx context.Context, orgID int64, policy provisioning.RuleTitleUniquenessPolicy) error
+	ResetRuleTitleUniqueness(ctx context.Context, orgID int64) error
+	CloneRuleGroup(ctx context.Context, user identity.Requester, srcOrgID int64, src alerting_models.AlertRuleGroupKey, dstOrgID int64, dstFolderUID string, opts provisioning.CloneRuleGroupOptions) (alerting_models.AlertRuleGroup, error)
+	ImportPrometheusRuleGroup(ctx context.Context, user identity.Requester, orgID int64, folderUID string, rulesYAML []byte, datasourceUID string, provenance alerting_models.Provenance) error
+	MoveRuleGroup(ctx context.Context, user identity.Requester, orgID int64, from, to alerting_models.AlertRuleGroupKey, provenance alerting_models.Provenance) error
+	ArchiveRuleGroup(ctx context.Context, user identity.Requester, orgID int64, namespaceUID, group string, provenance alerting_models.Provenance) error
+	UnarchiveRuleGroup(ctx context.Context, user identity.Requester, orgID int64, namespaceUID, group string, provenance alerting_models.Provenance) error
+	ListRuleTemplates(ctx context.Context, orgID int64) ([]*alerting_models.AlertRuleTemplate, error)
+	GetRuleTemplate(ctx context.Context, orgID int64, uid string) (*alerting_models.AlertRuleTemplate, error)
+	SaveRuleTemplate(ctx context.Context, tmpl alerting_models.AlertRuleTemplate) (alerting_models.AlertRuleTemplate, error)
+	DeleteRuleTemplate(ctx context.Context, orgID int64, uid string) error
+	InstantiateTemplate(ctx context.Context, user identity.Requester, templateUID string, params map[string]string, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
 }
 
 func (srv *ProvisioningSrv) RouteGetPolicyTree(c *contextmodel.ReqContext) response.Response {
@@ -84,7 +209,12 @@ func (srv *ProvisioningSrv) RouteGetPolicyTree(c *contextmodel.ReqContext) respo
 }
 
 func (srv *ProvisioningSrv) RouteGetPolicyTreeExport(c *contextmodel.ReqContext) response.Response {
-	policies, err := srv.policies.GetPolicyTree(c.Req.Context(), c.SignedInUser.GetOrgID())
+	var policies definitions.Route
+	err := srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		policies, err = srv.policies.GetPolicyTree(ctx, c.SignedInUser.GetOrgID())
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
 			return ErrResp(http.StatusNotFound, err, "")
@@ -100,6 +230,55 @@ func (srv *ProvisioningSrv) RouteGetPolicyTreeExport(c *contextmodel.ReqContext)
 	return exportResponse(c, e)
 }
 
+func (srv *ProvisioningSrv) RouteGetEffectivePolicy(c *contextmodel.ReqContext) response.Response {
+	routePath, err := parsePolicyPath(c.Query("path"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	policy, err := srv.policies.GetEffectivePolicy(c.Req.Context(), c.SignedInUser.GetOrgID(), routePath)
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) || errors.Is(err, provisioning.ErrNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	return response.JSON(http.StatusOK, policy)
+}
+
+// parsePolicyPath parses the indexes of the nested policies that lead to a policy from the root of the tree, separated
+// by dots.
+func parsePolicyPath(path string) ([]int, error) {
+	if path == "" {
+		return nil, nil
+	}
+	var routePath []int
+	for _, part := range strings.Split(path, ".") {
+		idx, err := strconv.Atoi(part)
+		if err != nil || idx < 0 {
+			return nil, fmt.Errorf("invalid policy path '%s'", path)
+		}
+		routePath = append(routePath, idx)
+	}
+	return routePath, nil
+}
+
+func (srv *ProvisioningSrv) RoutePostPolicyTreeMerge(c *contextmodel.ReqContext, fragment definitions.PolicyTreeFragment) response.Response {
+	provenance := determineProvenance(c)
+	tree, err := srv.policies.MergePolicyTree(c.Req.Context(), c.SignedInUser.GetOrgID(), fragment.Anchor, fragment.Routes, c.QueryBool("overwrite"), alerting_models.Provenance(provenance))
+	if err != nil {
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) || errors.Is(err, provisioning.ErrNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to merge notification policies", err)
+	}
+	return response.JSON(http.StatusOK, tree)
+}
+
 func (srv *ProvisioningSrv) RoutePutPolicyTree(c *contextmodel.ReqContext, tree definitions.Route) response.Response {
 	provenance := determineProvenance(c)
 	err := srv.policies.UpdatePolicyTree(c.Req.Context(), c.SignedInUser.GetOrgID(), tree, alerting_models.Provenance(provenance))
@@ -124,274 +303,990 @@ func (srv *ProvisioningSrv) RouteResetPolicyTree(c *contextmodel.ReqContext) res
 	return response.JSON(http.StatusAccepted, tree)
 }
 
-func (srv *ProvisioningSrv) RouteGetContactPoints(c *contextmodel.ReqContext) response.Response {
-	q := provisioning.ContactPointQuery{
-		Name:  c.Query("name"),
-		OrgID: c.SignedInUser.GetOrgID(),
-	}
-	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), q, nil)
+func (srv *ProvisioningSrv) RouteGetAlertmanagerRouting(c *contextmodel.ReqContext) response.Response {
+	routing, err := srv.alertmanagerRouting.GetAlertmanagerRouting(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
-		if errors.Is(err, provisioning.ErrPermissionDenied) {
-			return ErrResp(http.StatusForbidden, err, "")
-		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, cps)
+	return response.JSON(http.StatusOK, routing)
 }
 
-func (srv *ProvisioningSrv) RouteGetContactPointsExport(c *contextmodel.ReqContext) response.Response {
-	q := provisioning.ContactPointQuery{
-		Name:    c.Query("name"),
-		OrgID:   c.SignedInUser.GetOrgID(),
-		Decrypt: c.QueryBoolWithDefault("decrypt", false),
-	}
-	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), q, c.SignedInUser)
+func (srv *ProvisioningSrv) RouteGetAlertmanagerRoutingExport(c *contextmodel.ReqContext) response.Response {
+	var routing definitions.AlertmanagerRouting
+	err := srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		routing, err = srv.alertmanagerRouting.GetAlertmanagerRouting(ctx, c.SignedInUser.GetOrgID())
+		return err
+	})
 	if err != nil {
-		if errors.Is(err, provisioning.ErrPermissionDenied) {
-			return ErrResp(http.StatusForbidden, err, "")
-		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-
-	e, err := AlertingFileExportFromEmbeddedContactPoints(c.SignedInUser.GetOrgID(), cps)
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
-	}
-
-	return exportResponse(c, e)
+	return exportResponse(c, AlertingFileExportFromAlertmanagerRouting(c.SignedInUser.GetOrgID(), routing))
 }
 
-func (srv *ProvisioningSrv) RoutePostContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint) response.Response {
+func (srv *ProvisioningSrv) RoutePutAlertmanagerRouting(c *contextmodel.ReqContext, routing definitions.AlertmanagerRouting) response.Response {
 	provenance := determineProvenance(c)
-	contactPoint, err := srv.contactPointService.CreateContactPoint(c.Req.Context(), c.SignedInUser.GetOrgID(), cp, alerting_models.Provenance(provenance))
+	err := srv.alertmanagerRouting.UpdateAlertmanagerRouting(c.Req.Context(), c.SignedInUser.GetOrgID(), routing, alerting_models.Provenance(provenance))
 	if errors.Is(err, provisioning.ErrValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusAccepted, contactPoint)
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "alertmanager routing updated"})
 }
 
-func (srv *ProvisioningSrv) RoutePutContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint, UID string) response.Response {
-	cp.UID = UID
-	provenance := determineProvenance(c)
-	err := srv.contactPointService.UpdateContactPoint(c.Req.Context(), c.SignedInUser.GetOrgID(), cp, alerting_models.Provenance(provenance))
+func (srv *ProvisioningSrv) RouteGetRuleTitleUniqueness(c *contextmodel.ReqContext) response.Response {
+	return srv.ruleTitleUniquenessResponse(c)
+}
+
+func (srv *ProvisioningSrv) RoutePutRuleTitleUniqueness(c *contextmodel.ReqContext, policy definitions.RuleTitleUniqueness) response.Response {
+	err := srv.alertRules.SetRuleTitleUniqueness(c.Req.Context(), c.SignedInUser.GetOrgID(), provisioning.RuleTitleUniquenessPolicy{
+		Scope:      policy.Scope,
+		FolderUIDs: policy.FolderUIDs,
+	})
 	if errors.Is(err, provisioning.ErrValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
-	if errors.Is(err, provisioning.ErrNotFound) {
-		return ErrResp(http.StatusNotFound, err, "")
-	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return ErrResp(http.StatusInternalServerError, err, "failed to set the rule title uniqueness policy")
 	}
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint updated"})
+	return srv.ruleTitleUniquenessResponse(c)
 }
 
-func (srv *ProvisioningSrv) RouteDeleteContactPoint(c *contextmodel.ReqContext, UID string) response.Response {
-	err := srv.contactPointService.DeleteContactPoint(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+func (srv *ProvisioningSrv) RouteResetRuleTitleUniqueness(c *contextmodel.ReqContext) response.Response {
+	if err := srv.alertRules.ResetRuleTitleUniqueness(c.Req.Context(), c.SignedInUser.GetOrgID()); err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to reset the rule title uniqueness policy")
+	}
+	return srv.ruleTitleUniquenessResponse(c)
+}
+
+func (srv *ProvisioningSrv) ruleTitleUniquenessResponse(c *contextmodel.ReqContext) response.Response {
+	policy, inherited, err := srv.alertRules.GetRuleTitleUniqueness(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the rule title uniqueness policy")
 	}
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint deleted"})
+	return response.JSON(http.StatusOK, definitions.RuleTitleUniqueness{
+		Scope:      policy.Scope,
+		FolderUIDs: policy.FolderUIDs,
+		Inherited:  inherited,
+	})
 }
 
-func (srv *ProvisioningSrv) RouteGetTemplates(c *contextmodel.ReqContext) response.Response {
-	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+// RouteGetProvisioningFileSchema returns the JSON Schema of the files of file provisioning, which the files are
+// validated against when they are read.
+func (srv *ProvisioningSrv) RouteGetProvisioningFileSchema(c *contextmodel.ReqContext) response.Response {
+	return response.JSON(http.StatusOK, alerting.FileSchema())
+}
+
+func (srv *ProvisioningSrv) RouteGetConfigSnapshots(c *contextmodel.ReqContext) response.Response {
+	snapshots, err := srv.configSnapshots.GetSnapshots(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, templates)
+	return response.JSON(http.StatusOK, ConfigSnapshotsFromModels(snapshots))
 }
 
-func (srv *ProvisioningSrv) RouteGetTemplate(c *contextmodel.ReqContext, name string) response.Response {
-	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+func (srv *ProvisioningSrv) RoutePostConfigSnapshot(c *contextmodel.ReqContext) response.Response {
+	snapshot, err := srv.configSnapshots.CreateSnapshot(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	for _, tmpl := range templates {
-		if tmpl.Name == name {
-			return response.JSON(http.StatusOK, tmpl)
-		}
-	}
-	return response.Empty(http.StatusNotFound)
+	return response.JSON(http.StatusCreated, ConfigSnapshotFromModel(snapshot))
 }
 
-func (srv *ProvisioningSrv) RoutePutTemplate(c *contextmodel.ReqContext, body definitions.NotificationTemplateContent, name string) response.Response {
-	tmpl := definitions.NotificationTemplate{
-		Name:       name,
-		Template:   body.Template,
-		Provenance: determineProvenance(c),
+func (srv *ProvisioningSrv) RoutePostConfigSnapshotRestore(c *contextmodel.ReqContext, idParam string) response.Response {
+	id, err := strconv.ParseInt(idParam, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse snapshot ID")
+	}
+	mode := alerting_models.ConfigSnapshotRestoreMode(c.Query("mode"))
+	if mode == "" {
+		mode = alerting_models.ConfigSnapshotRestoreReplace
+	}
+	provenance := determineProvenance(c)
+	err = srv.configSnapshots.RestoreAlertingConfig(c.Req.Context(), c.SignedInUser.GetOrgID(), id, mode, c.SignedInUser, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return ErrResp(http.StatusForbidden, err, "")
 	}
-	modified, err := srv.templates.SetTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), tmpl)
 	if err != nil {
-		if errors.Is(err, provisioning.ErrValidation) {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to restore snapshot", err)
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "alerting configuration restored"})
+}
+
+func (srv *ProvisioningSrv) RoutePostBulkRuleGroups(c *contextmodel.ReqContext, body definitions.BulkRuleGroups) response.Response {
+	groups := make([]alerting_models.AlertRuleGroup, 0, len(body.Groups))
+	for _, group := range body.Groups {
+		groupModel, err := AlertRuleGroupFromApiAlertRuleGroup(group)
+		if err != nil {
 			return ErrResp(http.StatusBadRequest, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		groups = append(groups, groupModel)
 	}
-	return response.JSON(http.StatusAccepted, modified)
+	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	provenance := determineProvenance(c)
+	results, err := srv.bulk.InstallRuleGroups(c.Req.Context(), body.OrgIDs, groups, userID, alerting_models.Provenance(provenance))
+	return bulkResponse(results, err)
 }
 
-func (srv *ProvisioningSrv) RouteDeleteTemplate(c *contextmodel.ReqContext, name string) response.Response {
-	err := srv.templates.DeleteTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), name)
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
-	}
-	return response.JSON(http.StatusNoContent, nil)
+func (srv *ProvisioningSrv) RoutePostBulkContactPointSecret(c *contextmodel.ReqContext, body definitions.BulkContactPointSecret) response.Response {
+	provenance := determineProvenance(c)
+	results, err := srv.bulk.UpdateContactPointSecret(c.Req.Context(), body.OrgIDs, body.Name, body.Key, body.Value, alerting_models.Provenance(provenance))
+	return bulkResponse(results, err)
 }
 
-func (srv *ProvisioningSrv) RouteGetMuteTiming(c *contextmodel.ReqContext, name string) response.Response {
-	timing, err := srv.muteTimings.GetMuteTiming(c.Req.Context(), name, c.SignedInUser.GetOrgID())
+func (srv *ProvisioningSrv) RoutePostBulkPolicy(c *contextmodel.ReqContext, body definitions.BulkPolicy) response.Response {
+	provenance := determineProvenance(c)
+	results, err := srv.bulk.ApplyPolicy(c.Req.Context(), body.OrgIDs, body.Policy, alerting_models.Provenance(provenance))
+	return bulkResponse(results, err)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTags(c *contextmodel.ReqContext, UID string) response.Response {
+	rule, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
 	if err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timing by name", err)
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, timing)
+	return srv.getTags(c, &rule)
 }
 
-func (srv *ProvisioningSrv) RouteGetMuteTimingExport(c *contextmodel.ReqContext, name string) response.Response {
-	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.SignedInUser.GetOrgID())
+func (srv *ProvisioningSrv) RoutePutAlertRuleTags(c *contextmodel.ReqContext, body definitions.ResourceTags, UID string) response.Response {
+	rule, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
 	if err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timings", err)
-	}
-	for _, timing := range timings {
-		if name == timing.Name {
-			e := AlertingFileExportFromMuteTimings(c.SignedInUser.GetOrgID(), []definitions.MuteTimeInterval{timing})
-			return exportResponse(c, e)
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
 		}
+		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.Empty(http.StatusNotFound)
+	return srv.setTags(c, &rule, body)
 }
 
-func (srv *ProvisioningSrv) RouteGetMuteTimings(c *contextmodel.ReqContext) response.Response {
-	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.SignedInUser.GetOrgID())
-	if err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timings", err)
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroupTags(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
+	if _, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUID, group); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
 	}
-	return response.JSON(http.StatusOK, timings)
+	return srv.getTags(c, alerting_models.AlertRuleGroupKey{OrgID: c.SignedInUser.GetOrgID(), NamespaceUID: folderUID, RuleGroup: group})
 }
 
-func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *contextmodel.ReqContext) response.Response {
-	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.SignedInUser.GetOrgID())
-	if err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timings", err)
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroupTags(c *contextmodel.ReqContext, body definitions.ResourceTags, folderUID string, group string) response.Response {
+	if _, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUID, group); err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
 	}
-	e := AlertingFileExportFromMuteTimings(c.SignedInUser.GetOrgID(), timings)
-	return exportResponse(c, e)
+	return srv.setTags(c, alerting_models.AlertRuleGroupKey{OrgID: c.SignedInUser.GetOrgID(), NamespaceUID: folderUID, RuleGroup: group}, body)
 }
 
-func (srv *ProvisioningSrv) RoutePostMuteTiming(c *contextmodel.ReqContext, mt definitions.MuteTimeInterval) response.Response {
-	mt.Provenance = determineProvenance(c)
-	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), mt, c.SignedInUser.GetOrgID())
+func (srv *ProvisioningSrv) RoutePutAlertRuleProvenance(c *contextmodel.ReqContext, body definitions.ProvenanceTransition, UID string) response.Response {
+	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	err := srv.alertRules.ChangeProvenance(c.Req.Context(), userID, c.SignedInUser.GetOrgID(), UID, alerting_models.Provenance(body.From), alerting_models.Provenance(body.To))
+	return provenanceTransitionResponse(err)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroupProvenance(c *contextmodel.ReqContext, body definitions.ProvenanceTransition, folderUID string, group string) response.Response {
+	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	err := srv.alertRules.ChangeRuleGroupProvenance(c.Req.Context(), userID, c.SignedInUser.GetOrgID(), folderUID, group, alerting_models.Provenance(body.From), alerting_models.Provenance(body.To))
+	return provenanceTransitionResponse(err)
+}
+
+func provenanceTransitionResponse(err error) response.Response {
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	if err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create mute timing", err)
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to change the provenance", err)
 	}
-	return response.JSON(http.StatusCreated, created)
+	return response.JSON(http.StatusNoContent, "")
 }
 
-func (srv *ProvisioningSrv) RoutePutMuteTiming(c *contextmodel.ReqContext, mt definitions.MuteTimeInterval, name string) response.Response {
-	mt.Name = name
-	mt.Provenance = determineProvenance(c)
-	updated, err := srv.muteTimings.UpdateMuteTiming(c.Req.Context(), mt, c.SignedInUser.GetOrgID())
+func (srv *ProvisioningSrv) RouteGetFolderAnnotations(c *contextmodel.ReqContext, folderUID string) response.Response {
+	annotations, err := srv.alertRules.GetFolderAnnotations(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUID)
 	if err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update mute timing", err)
+		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusAccepted, updated)
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	return response.JSON(http.StatusOK, definitions.FolderAnnotations{Annotations: annotations})
 }
 
-func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, name string) response.Response {
-	err := srv.muteTimings.DeleteMuteTiming(c.Req.Context(), name, c.SignedInUser.GetOrgID())
+func (srv *ProvisioningSrv) RoutePutFolderAnnotations(c *contextmodel.ReqContext, body definitions.FolderAnnotations, folderUID string) response.Response {
+	err := srv.alertRules.SetFolderAnnotations(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUID, body.Annotations)
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 	if err != nil {
-		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete mute timing", err)
+		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusNoContent, nil)
+	return srv.RouteGetFolderAnnotations(c, folderUID)
 }
 
-func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
-	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.SignedInUser.GetOrgID())
+func (srv *ProvisioningSrv) RouteGetFolderEvaluation(c *contextmodel.ReqContext, folderUID string) response.Response {
+	paused, err := srv.alertRules.GetFolderEvaluationPaused(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUID)
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRules(rules, provenances))
+	return response.JSON(http.StatusOK, definitions.FolderEvaluation{Paused: paused})
 }
 
-func (srv *ProvisioningSrv) RouteRouteGetAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
-	rule, provenace, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+func (srv *ProvisioningSrv) RoutePutFolderEvaluation(c *contextmodel.ReqContext, body definitions.FolderEvaluation, folderUID string) response.Response {
+	err := srv.alertRules.SetFolderEvaluationPaused(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUID, body.Paused)
 	if err != nil {
-		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
-			return response.Empty(http.StatusNotFound)
-		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRule(rule, provenace))
+	return srv.RouteGetFolderEvaluation(c, folderUID)
 }
 
-func (srv *ProvisioningSrv) RoutePostAlertRule(c *contextmodel.ReqContext, ar definitions.ProvisionedAlertRule) response.Response {
-	upstreamModel, err := AlertRuleFromProvisionedAlertRule(ar)
-	upstreamModel.OrgID = c.SignedInUser.GetOrgID()
-	if err != nil {
-		return ErrResp(http.StatusBadRequest, err, "")
+func (srv *ProvisioningSrv) RouteGetContactPointTags(c *contextmodel.ReqContext, UID string) response.Response {
+	cp, resp := srv.getContactPoint(c, UID)
+	if resp != nil {
+		return resp
 	}
-	provenance := determineProvenance(c)
-	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
-	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), upstreamModel, alerting_models.Provenance(provenance), userID)
-	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+	return srv.getTags(c, cp)
+}
+
+func (srv *ProvisioningSrv) RoutePutContactPointTags(c *contextmodel.ReqContext, body definitions.ResourceTags, UID string) response.Response {
+	cp, resp := srv.getContactPoint(c, UID)
+	if resp != nil {
+		return resp
+	}
+	return srv.setTags(c, cp, body)
+}
+
+func (srv *ProvisioningSrv) RouteGetContactPointDeliveries(c *contextmodel.ReqContext, UID string) response.Response {
+	cp, resp := srv.getContactPoint(c, UID)
+	if resp != nil {
+		return resp
 	}
+	attempts, err := srv.deliveries.GetDeliveryAttempts(c.SignedInUser.GetOrgID(), cp.UID)
 	if err != nil {
-		if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
-			return ErrResp(http.StatusBadRequest, err, "")
+		if errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
+			return ErrResp(http.StatusNotFound, err, "")
 		}
-		if errors.Is(err, store.ErrOptimisticLock) {
+		if errors.Is(err, notifier.ErrAlertmanagerNotReady) {
 			return ErrResp(http.StatusConflict, err, "")
 		}
-		if errors.Is(err, alerting_models.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
-		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-
-	resp := ProvisionedAlertRuleFromAlertRule(createdAlertRule, alerting_models.Provenance(provenance))
-	return response.JSON(http.StatusCreated, resp)
+	result := definitions.ContactPointDeliveries{
+		UID:        cp.UID,
+		Name:       cp.Name,
+		Type:       cp.Type,
+		Deliveries: make([]definitions.ContactPointDelivery, 0, len(attempts)),
+	}
+	for _, attempt := range attempts {
+		delivery := definitions.ContactPointDelivery{
+			Time:     attempt.Time,
+			Status:   definitions.ContactPointDeliverySuccess,
+			Error:    attempt.Error,
+			Retry:    attempt.Retry,
+			Duration: attempt.Duration.String(),
+			Alerts:   attempt.Alerts,
+		}
+		if attempt.Error != "" {
+			delivery.Status = definitions.ContactPointDeliveryFailure
+		}
+		result.Deliveries = append(result.Deliveries, delivery)
+	}
+	return response.JSON(http.StatusOK, result)
 }
 
-func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar definitions.ProvisionedAlertRule, UID string) response.Response {
-	updated, err := AlertRuleFromProvisionedAlertRule(ar)
-	if err != nil {
-		ErrResp(http.StatusBadRequest, err, "")
-	}
-	updated.OrgID = c.SignedInUser.GetOrgID()
-	updated.UID = UID
+func (srv *ProvisioningSrv) RoutePostAlertRulesPause(c *contextmodel.ReqContext, body definitions.AlertRulesPause) response.Response {
 	provenance := determineProvenance(c)
-	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), updated, alerting_models.Provenance(provenance))
-	if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+	updated, err := srv.tags.SetRulesPausedByTag(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), body.Tag, body.IsPaused, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
-	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
-		return response.Empty(http.StatusNotFound)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update alert rules", err)
+	}
+	return response.JSON(http.StatusOK, definitions.AlertRulesPauseResult{Updated: updated})
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRulesMetadata(c *contextmodel.ReqContext, body definitions.AlertRulesMetadataPatch) response.Response {
+	selector := alerting_models.ListAlertRulesQuery{
+		NamespaceUIDs: body.FolderUIDs,
+		RuleGroup:     body.RuleGroup,
+		LabelMatchers: labels.Matchers(body.Matchers),
+	}
+	patch := provisioning.RuleMetadataPatch{
+		AddLabels:         body.AddLabels,
+		RemoveLabels:      body.RemoveLabels,
+		AddAnnotations:    body.AddAnnotations,
+		RemoveAnnotations: body.RemoveAnnotations,
+	}
+	provenance := determineProvenance(c)
+	updated, err := srv.alertRules.PatchRuleMetadata(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), selector, patch, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update alert rules", err)
+	}
+	return response.JSON(http.StatusOK, definitions.AlertRulesMetadataPatchResult{Updated: updated})
+}
+
+func (srv *ProvisioningSrv) getTags(c *contextmodel.ReqContext, o alerting_models.Provisionable) response.Response {
+	tags, err := srv.tags.GetTags(c.Req.Context(), c.SignedInUser.GetOrgID(), o)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, definitions.ResourceTags{Tags: tags})
+}
+
+func (srv *ProvisioningSrv) setTags(c *contextmodel.ReqContext, o alerting_models.Provisionable, body definitions.ResourceTags) response.Response {
+	tags, err := srv.tags.SetTags(c.Req.Context(), c.SignedInUser.GetOrgID(), o, body.Tags)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, definitions.ResourceTags{Tags: tags})
+}
+
+// getContactPoint returns the contact point with the UID, or the response to return if it cannot be found.
+func (srv *ProvisioningSrv) getContactPoint(c *contextmodel.ReqContext, UID string) (*definitions.EmbeddedContactPoint, response.Response) {
+	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), provisioning.ContactPointQuery{OrgID: c.SignedInUser.GetOrgID()}, c.SignedInUser)
+	if err != nil {
+		return nil, ErrResp(http.StatusInternalServerError, err, "")
+	}
+	for _, cp := range cps {
+		if cp.UID == UID {
+			return &cp, nil
+		}
+	}
+	return nil, ErrResp(http.StatusNotFound, fmt.Errorf("%w: contact point with UID '%s' not found", provisioning.ErrNotFound, UID), "")
+}
+
+// bulkResponse returns the result of a bulk operation in each organization. The status is 200 even if the operation
+// failed in some organizations.
+func bulkResponse(results []provisioning.OrgResult, err error) response.Response {
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	body := definitions.BulkOperationResult{Results: make([]definitions.BulkOrgResult, 0, len(results))}
+	for _, result := range results {
+		orgResult := definitions.BulkOrgResult{OrgID: result.OrgID, Status: definitions.BulkOrgResultSuccess}
+		if result.Err != nil {
+			orgResult.Status = definitions.BulkOrgResultError
+			orgResult.Error = result.Err.Error()
+		}
+		body.Results = append(body.Results, orgResult)
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+func (srv *ProvisioningSrv) RouteGetContactPoints(c *contextmodel.ReqContext) response.Response {
+	q := provisioning.ContactPointQuery{
+		Name:  c.Query("name"),
+		OrgID: c.SignedInUser.GetOrgID(),
+	}
+	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), q, nil)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrPermissionDenied) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if tag := c.Query("tag"); tag != "" {
+		cps, err = srv.tags.FilterContactPoints(c.Req.Context(), q.OrgID, tag, cps)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+	}
+	return response.JSON(http.StatusOK, cps)
+}
+
+func (srv *ProvisioningSrv) RouteGetContactPointsExport(c *contextmodel.ReqContext) response.Response {
+	q := provisioning.ContactPointQuery{
+		Name:    c.Query("name"),
+		OrgID:   c.SignedInUser.GetOrgID(),
+		Decrypt: c.QueryBoolWithDefault("decrypt", false),
+	}
+	var cps []definitions.EmbeddedContactPoint
+	err := srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		cps, err = srv.contactPointService.GetContactPoints(ctx, q, c.SignedInUser)
+		if err != nil {
+			return err
+		}
+		if tag := c.Query("tag"); tag != "" {
+			cps, err = srv.tags.FilterContactPoints(ctx, q.OrgID, tag, cps)
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, provisioning.ErrPermissionDenied) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	e, err := AlertingFileExportFromEmbeddedContactPoints(c.SignedInUser.GetOrgID(), cps)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
+
+	return exportResponse(c, e)
+}
+
+func (srv *ProvisioningSrv) RoutePostContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint) response.Response {
+	provenance := determineProvenance(c)
+	contactPoint, err := srv.contactPointService.CreateContactPoint(c.Req.Context(), c.SignedInUser.GetOrgID(), cp, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, contactPoint)
+}
+
+func (srv *ProvisioningSrv) RoutePutContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint, UID string) response.Response {
+	cp.UID = UID
+	provenance := determineProvenance(c)
+	err := srv.contactPointService.UpdateContactPoint(c.Req.Context(), c.SignedInUser.GetOrgID(), cp, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint updated"})
+}
+
+func (srv *ProvisioningSrv) RouteDeleteContactPoint(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.contactPointService.DeleteContactPoint(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint deleted"})
+}
+
+func (srv *ProvisioningSrv) RouteGetTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, templates)
+}
+
+func (srv *ProvisioningSrv) RouteGetTemplate(c *contextmodel.ReqContext, name string) response.Response {
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			return response.JSON(http.StatusOK, tmpl)
+		}
+	}
+	return response.Empty(http.StatusNotFound)
+}
+
+func (srv *ProvisioningSrv) RoutePutTemplate(c *contextmodel.ReqContext, body definitions.NotificationTemplateContent, name string) response.Response {
+	tmpl := definitions.NotificationTemplate{
+		Name:       name,
+		Template:   body.Template,
+		Provenance: determineProvenance(c),
+	}
+	modified, err := srv.templates.SetTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), tmpl)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to save template", err)
+	}
+	return response.JSON(http.StatusAccepted, modified)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteTemplate(c *contextmodel.ReqContext, name string) response.Response {
+	err := srv.templates.DeleteTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), name)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete template", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTiming(c *contextmodel.ReqContext, name string) response.Response {
+	timing, err := srv.muteTimings.GetMuteTiming(c.Req.Context(), name, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timing by name", err)
+	}
+	return response.JSON(http.StatusOK, timing)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTimingExport(c *contextmodel.ReqContext, name string) response.Response {
+	var timings []definitions.MuteTimeInterval
+	err := srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		timings, err = srv.muteTimings.GetMuteTimings(ctx, c.SignedInUser.GetOrgID())
+		return err
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timings", err)
+	}
+	for _, timing := range timings {
+		if name == timing.Name {
+			e := AlertingFileExportFromMuteTimings(c.SignedInUser.GetOrgID(), []definitions.MuteTimeInterval{timing})
+			return exportResponse(c, e)
+		}
+	}
+	return response.Empty(http.StatusNotFound)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTimings(c *contextmodel.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timings", err)
+	}
+	return response.JSON(http.StatusOK, timings)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *contextmodel.ReqContext) response.Response {
+	var timings []definitions.MuteTimeInterval
+	err := srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		timings, err = srv.muteTimings.GetMuteTimings(ctx, c.SignedInUser.GetOrgID())
+		return err
+	})
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get mute timings", err)
+	}
+	e := AlertingFileExportFromMuteTimings(c.SignedInUser.GetOrgID(), timings)
+	return exportResponse(c, e)
+}
+
+func (srv *ProvisioningSrv) RoutePostMuteTiming(c *contextmodel.ReqContext, mt definitions.MuteTimeInterval) response.Response {
+	mt.Provenance = determineProvenance(c)
+	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), mt, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to create mute timing", err)
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutMuteTiming(c *contextmodel.ReqContext, mt definitions.MuteTimeInterval, name string) response.Response {
+	mt.Name = name
+	mt.Provenance = determineProvenance(c)
+	updated, err := srv.muteTimings.UpdateMuteTiming(c.Req.Context(), mt, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update mute timing", err)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, name string) response.Response {
+	err := srv.muteTimings.DeleteMuteTiming(c.Req.Context(), name, c.SignedInUser.GetOrgID())
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete mute timing", err)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt64("limit")
+	page := c.QueryInt64("page")
+	if limit < 0 || page < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit and page must not be negative"), "")
+	}
+	matchers, err := parseMatchers(c.QueryStrings("matcher"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	fields, err := parseRuleFields(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	query := alerting_models.ListAlertRulesQuery{
+		OrgID:           c.SignedInUser.GetOrgID(),
+		NamespaceUIDs:   c.QueryStrings("folderUid"),
+		RuleGroupPrefix: c.Query("groupPrefix"),
+		LabelMatchers:   matchers,
+		Limit:           limit,
+		Page:            page,
+	}
+	query.ArchivedGroups, err = parseArchivedRuleGroupsFilter(c.Query("archived"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	for _, p := range c.QueryStrings("provenance") {
+		provenance, err := parseProvenance(p)
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		query.Provenances = append(query.Provenances, provenance)
+	}
+	tag := c.Query("tag")
+	// Rules are filtered by tag after they are read, so the page is selected after the filter in that case.
+	if tag != "" {
+		query.Limit, query.Page = 0, 0
+	}
+	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if tag != "" {
+		rules, err = srv.tags.FilterRules(c.Req.Context(), c.SignedInUser.GetOrgID(), tag, rules)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "")
+		}
+		if limit > 0 {
+			start := min(max(page-1, 0)*limit, int64(len(rules)))
+			end := min(start+limit, int64(len(rules)))
+			rules = rules[start:end]
+		}
+	}
+	result := ProvisionedAlertRuleFromAlertRules(rules, provenances)
+	if c.QueryBool("withState") {
+		srv.withRuleStates(result)
+	}
+	return response.JSON(http.StatusOK, selectRuleFields(result, fields))
+}
+
+// parseProvenance parses the provenance of a filter, where "none" stands for the rules that are not provisioned.
+func parseProvenance(s string) (alerting_models.Provenance, error) {
+	switch p := alerting_models.Provenance(s); p {
+	case alerting_models.ProvenanceAPI, alerting_models.ProvenanceFile:
+		return p, nil
+	case "none":
+		return alerting_models.ProvenanceNone, nil
+	default:
+		return "", fmt.Errorf("unknown provenance '%s', must be one of none, api or file", s)
+	}
+}
+
+// parseArchivedRuleGroupsFilter parses the filter of the rules of archived rule groups, which are excluded by default.
+func parseArchivedRuleGroupsFilter(s string) (alerting_models.ArchivedRuleGroupsFilter, error) {
+	switch s {
+	case "", "exclude":
+		return alerting_models.ArchivedRuleGroupsExclude, nil
+	case "include":
+		return alerting_models.ArchivedRuleGroupsInclude, nil
+	case "only":
+		return alerting_models.ArchivedRuleGroupsOnly, nil
+	default:
+		return "", fmt.Errorf("unknown archived filter '%s', must be one of exclude, include or only", s)
+	}
+}
+
+// parseRuleUsageQuery parses the window and the filters of the alert rules of the usage and noise report routes.
+func parseRuleUsageQuery(c *contextmodel.ReqContext) (provisioning.RuleUsageQuery, error) {
+	query := provisioning.RuleUsageQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		NamespaceUIDs: c.QueryStrings("folderUid"),
+	}
+	if from := c.QueryInt64("from"); from > 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to > 0 {
+		query.To = time.Unix(to, 0)
+	}
+	for _, p := range c.QueryStrings("provenance") {
+		provenance, err := parseProvenance(p)
+		if err != nil {
+			return provisioning.RuleUsageQuery{}, err
+		}
+		query.Provenances = append(query.Provenances, provenance)
+	}
+	return query, nil
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRulesUsage(c *contextmodel.ReqContext) response.Response {
+	query, err := parseRuleUsageQuery(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	query.NeverFired = c.QueryBool("neverFired")
+	usages, err := srv.ruleUsage.GetRuleUsage(c.Req.Context(), c.SignedInUser, query)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := definitions.AlertRulesUsage{
+		From:  usages.From,
+		To:    usages.To,
+		Rules: make([]definitions.AlertRuleUsage, 0, len(usages.Rules)),
+	}
+	for _, usage := range usages.Rules {
+		ruleUsage := definitions.AlertRuleUsage{
+			UID:                   usage.Rule.UID,
+			Title:                 usage.Rule.Title,
+			FolderUID:             usage.Rule.NamespaceUID,
+			RuleGroup:             usage.Rule.RuleGroup,
+			Provenance:            definitions.Provenance(usage.Provenance),
+			TransitionCount:       usage.TransitionCount,
+			FiringCount:           usage.FiringCount,
+			FiringOrResolvedCount: usage.FiringOrResolvedCount,
+		}
+		if !usage.LastFiredAt.IsZero() {
+			lastFiredAt := usage.LastFiredAt
+			ruleUsage.LastFiredAt = &lastFiredAt
+		}
+		result.Rules = append(result.Rules, ruleUsage)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRulesQuota(c *contextmodel.ReqContext) response.Response {
+	status, err := srv.alertRules.GetQuotaStatus(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := definitions.AlertRulesQuotaStatus{
+		Count:  status.Count,
+		Limits: make([]definitions.AlertRulesQuotaLimit, 0, len(status.Limits)),
+		Groups: make([]definitions.AlertRuleGroupCount, 0, len(status.Groups)),
+	}
+	for _, limit := range status.Limits {
+		result.Limits = append(result.Limits, quotaLimitToDefinition(limit))
+	}
+	if status.Exempt != nil {
+		exempt := quotaLimitToDefinition(*status.Exempt)
+		result.Exempt = &exempt
+	}
+	for _, group := range status.Groups {
+		result.Groups = append(result.Groups, definitions.AlertRuleGroupCount{
+			FolderUID: group.NamespaceUID,
+			RuleGroup: group.RuleGroup,
+			RuleCount: group.Count,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func quotaLimitToDefinition(limit provisioning.QuotaLimit) definitions.AlertRulesQuotaLimit {
+	return definitions.AlertRulesQuotaLimit{
+		Scope:    string(limit.Scope),
+		Limit:    limit.Limit,
+		Used:     limit.Used,
+		Headroom: limit.Headroom,
+	}
+}
+
+func (srv *ProvisioningSrv) RouteGetRuleGroupsNoiseReport(c *contextmodel.ReqContext) response.Response {
+	query, err := parseRuleUsageQuery(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	report, err := srv.ruleUsage.GetRuleGroupNoiseReport(c.Req.Context(), c.SignedInUser, query, c.Query("sortBy"), c.QueryInt("limit"))
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := definitions.RuleGroupsNoiseReport{
+		From:   report.From,
+		To:     report.To,
+		Groups: make([]definitions.RuleGroupNoise, 0, len(report.Groups)),
+	}
+	for _, group := range report.Groups {
+		result.Groups = append(result.Groups, definitions.RuleGroupNoise{
+			FolderUID:             group.NamespaceUID,
+			RuleGroup:             group.RuleGroup,
+			RuleCount:             int64(group.RuleCount),
+			TransitionCount:       group.TransitionCount,
+			Flappiness:            group.Flappiness,
+			FiringOrResolvedCount: group.FiringOrResolvedCount,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteSearchAlertRules(c *contextmodel.ReqContext) response.Response {
+	limit := c.QueryInt64("limit")
+	page := c.QueryInt64("page")
+	if limit < 0 || page < 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("limit and page must not be negative"), "")
+	}
+	if page == 0 {
+		page = 1
+	}
+	fields, err := parseRuleFields(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	result, provenances, err := srv.alertRules.SearchAlertRules(c.Req.Context(), alerting_models.SearchAlertRulesQuery{
+		OrgID:         c.SignedInUser.GetOrgID(),
+		Query:         c.Query("query"),
+		NamespaceUIDs: c.QueryStrings("folderUid"),
+		Limit:         limit,
+		Page:          page,
+	})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	searchResult := definitions.ProvisionedAlertRulesSearchResult{
+		TotalCount: result.TotalCount,
+		Page:       page,
+		Limit:      limit,
+		Rules:      ProvisionedAlertRuleFromAlertRules(result.Rules, provenances),
+	}
+	if len(fields) == 0 {
+		return response.JSON(http.StatusOK, searchResult)
+	}
+	// The rules with the selected fields shadow the rules of the embedded result.
+	return response.JSON(http.StatusOK, struct {
+		definitions.ProvisionedAlertRulesSearchResult
+		Rules any `json:"rules"`
+	}{searchResult, selectRuleFields(searchResult.Rules, fields)})
+}
+
+func (srv *ProvisioningSrv) RouteRouteGetAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
+	fields, err := parseRuleFields(c)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	rule, provenace, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := ProvisionedAlertRuleFromAlertRule(rule, provenace)
+	if len(fields) > 0 {
+		return response.JSON(http.StatusOK, ruleFields(result, fields))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRule(c *contextmodel.ReqContext, ar definitions.ProvisionedAlertRule) response.Response {
+	upstreamModel, err := AlertRuleFromProvisionedAlertRule(ar)
+	upstreamModel.OrgID = c.SignedInUser.GetOrgID()
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	provenance := determineProvenance(c)
+	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), upstreamModel, alerting_models.Provenance(provenance), userID)
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, alerting_models.ErrAlertRuleTitleNotUniqueBase) || alerting_models.IsErrAlertRuleLimitExceeded(err) {
+			return response.Err(err)
+		}
+		if errors.Is(err, store.ErrGroupLocked) || errors.Is(err, provisioning.ErrProvenanceNotAllowed) {
+			return response.Err(err)
+		}
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		if errors.Is(err, alerting_models.ErrQuotaReached) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	resp := ProvisionedAlertRuleFromAlertRule(createdAlertRule, alerting_models.Provenance(provenance))
+	return withRuleWarnings(c, response.JSON(http.StatusCreated, resp), createdAlertRule)
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar definitions.ProvisionedAlertRule, UID string) response.Response {
+	updated, err := AlertRuleFromProvisionedAlertRule(ar)
+	if err != nil {
+		ErrResp(http.StatusBadRequest, err, "")
+	}
+	updated.OrgID = c.SignedInUser.GetOrgID()
+	updated.UID = UID
+	provenance := determineProvenance(c)
+	update := srv.alertRules.UpdateAlertRule
+	// The rule is overwritten regardless of concurrent changes if the caller did not read its version.
+	if updated.Version != 0 && !c.QueryBool("force") {
+		update = srv.alertRules.UpdateAlertRuleIfUnchanged
+	}
+	updatedAlertRule, err := update(c.Req.Context(), updated, alerting_models.Provenance(provenance))
+	if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleTitleNotUniqueBase) || errors.Is(err, provisioning.ErrAlertRuleVersionConflict) ||
+		alerting_models.IsErrAlertRuleLimitExceeded(err) {
+		return response.Err(err)
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return response.Empty(http.StatusNotFound)
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrGroupLocked) || errors.Is(err, provisioning.ErrProvenanceNotAllowed) {
+			return response.Err(err)
+		}
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	resp := ProvisionedAlertRuleFromAlertRule(updatedAlertRule, alerting_models.Provenance(provenance))
+	return withRuleWarnings(c, response.JSON(http.StatusOK, resp), updatedAlertRule)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.alertRules.DeleteAlertRule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrProvenanceNotAllowed) {
+		return response.Err(err)
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func (srv *ProvisioningSrv) RouteGetDeletedAlertRules(c *contextmodel.ReqContext) response.Response {
+	rules, err := srv.alertRules.ListDeletedRules(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+	return response.JSON(http.StatusOK, DeletedAlertRulesFromModels(rules))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleSyncStatus(c *contextmodel.ReqContext) response.Response {
+	if srv.ruleSync == nil {
+		return response.JSON(http.StatusOK, definitions.AlertRuleSyncStatus{Groups: []definitions.AlertRuleGroupSyncStatus{}})
 	}
+	status, err := srv.ruleSync.GetStatus(c.Req.Context(), c.SignedInUser.GetOrgID())
 	if err != nil {
-		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
-		}
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
-
-	resp := ProvisionedAlertRuleFromAlertRule(updatedAlertRule, alerting_models.Provenance(provenance))
-	return response.JSON(http.StatusOK, resp)
+	return response.JSON(http.StatusOK, AlertRuleSyncStatusFromModel(status))
 }
 
-func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
+func (srv *ProvisioningSrv) RoutePostAlertRuleRestore(c *contextmodel.ReqContext, UID string) response.Response {
 	provenance := determineProvenance(c)
-	err := srv.alertRules.DeleteAlertRule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID, alerting_models.Provenance(provenance))
+	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	restored, err := srv.alertRules.RestoreAlertRule(c.Req.Context(), c.SignedInUser.GetOrgID(), UID, alerting_models.Provenance(provenance), userID)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) || errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to restore alert rule", err)
 	}
-	return response.JSON(http.StatusNoContent, "")
+	resp := ProvisionedAlertRuleFromAlertRule(restored, alerting_models.Provenance(provenance))
+	return response.JSON(http.StatusCreated, resp)
 }
 
 func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *contextmodel.ReqContext, folder string, group string) response.Response {
@@ -399,7 +1294,106 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *contextmodel.ReqContext, f
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "", err)
 	}
-	return response.JSON(http.StatusOK, ApiAlertRuleGroupFromAlertRuleGroup(g))
+	result := ApiAlertRuleGroupFromAlertRuleGroup(g)
+	result.Version = alerting_models.NewRuleGroupVersion(g.Rules).String()
+	if c.QueryBool("withState") {
+		srv.withRuleStates(result.Rules)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// withRuleStates sets the summary of the current state of the alerts of each rule.
+func (srv *ProvisioningSrv) withRuleStates(rules []definitions.ProvisionedAlertRule) {
+	for i := range rules {
+		rules[i].State = alertRuleStateSummary(srv.states.GetStatesForRuleUID(rules[i].OrgID, rules[i].UID))
+	}
+}
+
+// ruleFieldIndexes maps the JSON names of the fields of provisioned alert rules to the indexes of the fields.
+var ruleFieldIndexes = func() map[string]int {
+	t := reflect.TypeOf(definitions.ProvisionedAlertRule{})
+	result := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			result[name] = i
+		}
+	}
+	return result
+}()
+
+// parseRuleFields returns the JSON names of the fields of the rules that the request selects with the fields query
+// parameter, either repeated or comma-separated. It returns nil if the request selects all the fields.
+func parseRuleFields(c *contextmodel.ReqContext) ([]string, error) {
+	var fields []string
+	for _, value := range c.QueryStrings("fields") {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if _, ok := ruleFieldIndexes[field]; !ok {
+				return nil, fmt.Errorf("unknown field '%s' of alert rules", field)
+			}
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// selectRuleFields returns the rules with only the fields, so that the fields that are not selected, e.g. the queries,
+// are not serialized. It returns the rules as they are if no field is selected.
+func selectRuleFields(rules []definitions.ProvisionedAlertRule, fields []string) any {
+	if len(fields) == 0 {
+		return rules
+	}
+	result := make([]map[string]any, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, ruleFields(rule, fields))
+	}
+	return result
+}
+
+// ruleFields returns the values of the fields of the rule by their JSON names.
+func ruleFields(rule definitions.ProvisionedAlertRule, fields []string) map[string]any {
+	v := reflect.ValueOf(rule)
+	result := make(map[string]any, len(fields))
+	for _, field := range fields {
+		result[field] = v.Field(ruleFieldIndexes[field]).Interface()
+	}
+	return result
+}
+
+// alertRuleStateSummary summarizes the states of the alerts of a rule. The health of the rule is computed as in the
+// Prometheus-compatible rules API: error if any alert has an error, nodata if any alert has no data, ok otherwise.
+func alertRuleStateSummary(states []*state.State) *definitions.AlertRuleStateSummary {
+	summary := &definitions.AlertRuleStateSummary{Health: "ok"}
+	for _, s := range states {
+		switch s.State {
+		case eval.Normal:
+			summary.Normal++
+		case eval.Pending:
+			summary.Pending++
+		case eval.Alerting:
+			summary.Firing++
+		case eval.Error:
+			summary.Health = "error"
+		case eval.NoData:
+			// An error of another alert takes precedence, whatever the order of the alerts.
+			if summary.Health != "error" {
+				summary.Health = "nodata"
+			}
+		}
+		if s.Error != nil {
+			summary.Health = "error"
+			summary.LastError = s.Error.Error()
+		}
+		if !s.LastEvaluationTime.IsZero() && (summary.LastEvaluation == nil || s.LastEvaluationTime.After(*summary.LastEvaluation)) {
+			lastEvaluation := s.LastEvaluationTime
+			summary.LastEvaluation = &lastEvaluation
+		}
+	}
+	return summary
 }
 
 // RouteGetAlertRulesExport retrieves all alert rules in a format compatible with file provisioning.
@@ -423,40 +1417,57 @@ func (srv *ProvisioningSrv) RouteGetAlertRulesExport(c *contextmodel.ReqContext)
 		return srv.RouteGetAlertRuleGroupExport(c, folderUIDs[0], group)
 	}
 
-	groupsWithTitle, err := srv.alertRules.GetAlertGroupsWithFolderTitle(c.Req.Context(), c.SignedInUser.GetOrgID(), folderUIDs)
+	archived, err := parseArchivedRuleGroupsFilter(c.Query("archived"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	var groupsWithTitle []alerting_models.AlertRuleGroupWithFolderTitle
+	message := "failed to get alert rules"
+	err = srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		groupsWithTitle, err = srv.alertRules.GetAlertGroupsWithFolderTitle(ctx, c.SignedInUser.GetOrgID(), folderUIDs, archived)
+		if err != nil {
+			return err
+		}
+		if tag := c.Query("tag"); tag != "" {
+			message = "failed to filter alert rules"
+			groupsWithTitle, err = srv.tags.FilterRuleGroups(ctx, c.SignedInUser.GetOrgID(), tag, groupsWithTitle)
+		}
+		return err
+	})
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+		return ErrResp(http.StatusInternalServerError, err, message)
 	}
 	if len(groupsWithTitle) == 0 {
 		return response.Empty(http.StatusNotFound)
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groupsWithTitle)
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
-	}
-
-	return exportResponse(c, e)
+	return srv.exportRuleGroupsResponse(c, groupsWithTitle)
 }
 
 // RouteGetAlertRuleGroupExport retrieves the given alert rule group in a format compatible with file provisioning.
 func (srv *ProvisioningSrv) RouteGetAlertRuleGroupExport(c *contextmodel.ReqContext, folder string, group string) response.Response {
-	g, err := srv.alertRules.GetAlertRuleGroupWithFolderTitle(c.Req.Context(), c.SignedInUser.GetOrgID(), folder, group)
+	var g alerting_models.AlertRuleGroupWithFolderTitle
+	err := srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		g, err = srv.alertRules.GetAlertRuleGroupWithFolderTitle(ctx, c.SignedInUser.GetOrgID(), folder, group)
+		return err
+	})
 	if err != nil {
 		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule group", err)
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle([]alerting_models.AlertRuleGroupWithFolderTitle{g})
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
-	}
-
-	return exportResponse(c, e)
+	return srv.exportRuleGroupsResponse(c, []alerting_models.AlertRuleGroupWithFolderTitle{g})
 }
 
 // RouteGetAlertRuleExport retrieves the given alert rule in a format compatible with file provisioning.
 func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext, UID string) response.Response {
-	rule, err := srv.alertRules.GetAlertRuleWithFolderTitle(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	var rule provisioning.AlertRuleWithFolderTitle
+	err := srv.readExport(c, func(ctx context.Context) error {
+		var err error
+		rule, err = srv.alertRules.GetAlertRuleWithFolderTitle(ctx, c.SignedInUser.GetOrgID(), UID)
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
 			return ErrResp(http.StatusNotFound, err, "")
@@ -464,40 +1475,262 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext,
 		return ErrResp(http.StatusInternalServerError, err, "")
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle([]alerting_models.AlertRuleGroupWithFolderTitle{
+	return srv.exportRuleGroupsResponse(c, []alerting_models.AlertRuleGroupWithFolderTitle{
 		alerting_models.NewAlertRuleGroupWithFolderTitleFromRulesGroup(rule.AlertRule.GetGroupKey(), alerting_models.RulesGroup{&rule.AlertRule}, rule.FolderTitle),
 	})
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, ag definitions.AlertRuleGroup, folderUID string, group string) response.Response {
+	groupModel, resp := ruleGroupReplacementFromRequest(c, ag, folderUID, group)
+	if resp != nil {
+		return resp
+	}
+	provenance := determineProvenance(c)
+
+	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	defaults, err := srv.alertRules.ReplaceRuleGroupWithDefaults(c.Req.Context(), c.SignedInUser.GetOrgID(), groupModel, userID, alerting_models.Provenance(provenance))
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		return replaceRuleGroupErrorResponse(err)
 	}
+	ag.FolderUID = folderUID
+	ag.Title = group
+	ag.ServerDefaults = ApiServerDefaultsFromServerDefaults(defaults)
+	return withRuleWarnings(c, response.JSON(http.StatusOK, ag), groupModel.Rules...)
+}
 
-	return exportResponse(c, e)
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroupInterval(c *contextmodel.ReqContext, body definitions.RuleGroupInterval, folderUID string, group string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.alertRules.UpdateRuleGroup(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), folderUID, group, body.Interval, alerting_models.Provenance(provenance))
+	if errors.Is(err, alerting_models.ErrAlertRuleGroupNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to update the interval of the rule group", err)
+	}
+	return response.JSON(http.StatusOK, body)
 }
 
-func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, ag definitions.AlertRuleGroup, folderUID string, group string) response.Response {
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupJob(c *contextmodel.ReqContext, ag definitions.AlertRuleGroup, folderUID string, group string) response.Response {
+	groupModel, resp := ruleGroupReplacementFromRequest(c, ag, folderUID, group)
+	if resp != nil {
+		return resp
+	}
+	provenance := determineProvenance(c)
+
+	job, err := srv.ruleGroupJobs.StartReplaceRuleGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), groupModel, c.SignedInUser, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrRuleGroupJobQueueFull) {
+		return ErrResp(http.StatusServiceUnavailable, err, "")
+	}
+	if err != nil {
+		return replaceRuleGroupErrorResponse(err)
+	}
+	return response.JSON(http.StatusAccepted, ApiRuleGroupJobFromRuleGroupJob(job))
+}
+
+func (srv *ProvisioningSrv) RouteGetRuleGroupJob(c *contextmodel.ReqContext, UID string) response.Response {
+	job, err := srv.ruleGroupJobs.GetJob(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if errors.Is(err, alerting_models.ErrRuleGroupJobNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, ApiRuleGroupJobFromRuleGroupJob(job))
+}
+
+func (srv *ProvisioningSrv) RouteGetWebhookDeadLetters(c *contextmodel.ReqContext) response.Response {
+	deliveries, err := srv.webhook.ListDeadLetters(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := make(definitions.WebhookDeliveries, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, ApiWebhookDeliveryFromWebhookDelivery(d))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RoutePostWebhookDeadLetterReplay(c *contextmodel.ReqContext, UID string) response.Response {
+	delivery, err := srv.webhook.ReplayDeadLetter(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if errors.Is(err, alerting_models.ErrWebhookDeliveryNotFound) {
+		return ErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrWebhookDisabled) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, ApiWebhookDeliveryFromWebhookDelivery(delivery))
+}
+
+// ruleGroupReplacementFromRequest returns the rule group of the payload with the options of the replacement of the
+// group given in the query, or the response to return if they are not valid.
+func ruleGroupReplacementFromRequest(c *contextmodel.ReqContext, ag definitions.AlertRuleGroup, folderUID string, group string) (alerting_models.AlertRuleGroup, response.Response) {
 	ag.FolderUID = folderUID
 	ag.Title = group
 	groupModel, err := AlertRuleGroupFromApiAlertRuleGroup(ag)
 	if err != nil {
-		ErrResp(http.StatusBadRequest, err, "")
+		return alerting_models.AlertRuleGroup{}, ErrResp(http.StatusBadRequest, err, "")
 	}
-	provenance := determineProvenance(c)
+	if bakePeriod := c.Query("bakePeriod"); bakePeriod != "" {
+		d, err := model.ParseDuration(bakePeriod)
+		if err != nil {
+			return alerting_models.AlertRuleGroup{}, ErrResp(http.StatusBadRequest, fmt.Errorf("invalid bake period: %w", err), "")
+		}
+		groupModel.BakePeriod = time.Duration(d)
+	}
+	// Rules are moved from other groups unless it is rejected, as the API always moved them.
+	switch moves := alerting_models.RuleMovePolicy(c.Query("ruleMoves")); moves {
+	case "":
+		groupModel.RuleMoves = alerting_models.RuleMovesMove
+	case alerting_models.RuleMovesMove, alerting_models.RuleMovesReject:
+		groupModel.RuleMoves = moves
+	default:
+		return alerting_models.AlertRuleGroup{}, ErrResp(http.StatusBadRequest, fmt.Errorf("invalid rule moves '%s', must be '%s' or '%s'", moves, alerting_models.RuleMovesMove, alerting_models.RuleMovesReject), "")
+	}
+	groupModel.CheckVersions = !c.QueryBool("force")
+	if ag.Version != "" {
+		groupModel.ExpectedVersion, err = alerting_models.ParseRuleGroupVersion(ag.Version)
+		if err != nil {
+			return alerting_models.AlertRuleGroup{}, ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+	if baseVersion := c.Query("baseVersion"); baseVersion != "" {
+		groupModel.BaseVersion, err = alerting_models.ParseRuleGroupVersion(baseVersion)
+		if err != nil {
+			return alerting_models.AlertRuleGroup{}, ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+	return groupModel, nil
+}
 
-	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
-	err = srv.alertRules.ReplaceRuleGroup(c.Req.Context(), c.SignedInUser.GetOrgID(), groupModel, userID, alerting_models.Provenance(provenance))
+func replaceRuleGroupErrorResponse(err error) response.Response {
+	if errors.Is(err, alerting_models.ErrAlertRuleGroupTooManyChangesBase) || errors.Is(err, alerting_models.ErrAlertRuleTitleNotUniqueBase) ||
+		errors.Is(err, alerting_models.ErrAlertRuleMoveConflictBase) || errors.Is(err, provisioning.ErrAlertRuleVersionConflict) ||
+		errors.Is(err, provisioning.ErrRuleGroupMergeConflict) || alerting_models.IsErrAlertRuleLimitExceeded(err) {
+		return response.Err(err)
+	}
 	if errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
 	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
+	if errors.Is(err, store.ErrGroupLocked) || errors.Is(err, provisioning.ErrProvenanceNotAllowed) || accesscontrol.IsAuthorizationError(err) {
+		return response.Err(err)
+	}
+	if errors.Is(err, store.ErrOptimisticLock) {
+		return ErrResp(http.StatusConflict, err, "")
+	}
+	return ErrResp(http.StatusInternalServerError, err, "")
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupCostEstimate(c *contextmodel.ReqContext, ag definitions.AlertRuleGroup, folderUID string, group string) response.Response {
+	ag.FolderUID = folderUID
+	ag.Title = group
+	groupModel, err := AlertRuleGroupFromApiAlertRuleGroup(ag)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if groupModel.Interval <= 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("interval of the rule group must be positive"), "")
+	}
+	datasourceTypes := make(map[string]string)
+	for _, rule := range groupModel.Rules {
+		for _, q := range rule.Data {
+			if _, ok := datasourceTypes[q.DatasourceUID]; ok {
+				continue
+			}
+			if isExpression, _ := q.IsExpression(); isExpression {
+				continue
+			}
+			ds, err := srv.datasources.GetDatasourceByUID(c.Req.Context(), q.DatasourceUID, c.SignedInUser, false)
+			if err != nil {
+				if !errors.Is(err, datasources.ErrDataSourceNotFound) {
+					return ErrResp(http.StatusInternalServerError, err, "failed to get data source")
+				}
+				// The type in the model of the query, if any, is used instead.
+				datasourceTypes[q.DatasourceUID] = ""
+				continue
+			}
+			datasourceTypes[q.DatasourceUID] = ds.Type
+		}
+	}
+	return response.JSON(http.StatusOK, ApiRuleGroupCostEstimateFromRuleGroupCost(alerting_models.EstimateRuleGroupCost(groupModel, datasourceTypes)))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupClone(c *contextmodel.ReqContext, clone definitions.AlertRuleGroupClone, folderUID string, group string) response.Response {
+	orgID := c.SignedInUser.GetOrgID()
+	dstOrgID := clone.OrgID
+	if dstOrgID == 0 {
+		dstOrgID = orgID
+	}
+	// The permissions of the user are known only in the organization of the request.
+	if dstOrgID != orgID && !c.SignedInUser.GetIsGrafanaAdmin() {
+		return ErrResp(http.StatusForbidden, errors.New("copying a rule group to another organization requires a Grafana server admin"), "")
+	}
+	src := alerting_models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: folderUID, RuleGroup: group}
+	result, err := srv.alertRules.CloneRuleGroup(c.Req.Context(), c.SignedInUser, orgID, src, dstOrgID, clone.FolderUID, provisioning.CloneRuleGroupOptions{
+		Title:          clone.Title,
+		DatasourceUIDs: clone.DatasourceUIDs,
+		Labels:         clone.Labels,
+		Provenance:     alerting_models.Provenance(determineProvenance(c)),
+	})
 	if err != nil {
-		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+		if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) || errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+			return ErrResp(http.StatusBadRequest, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to clone rule group", err)
+	}
+	return response.JSON(http.StatusCreated, ApiAlertRuleGroupFromAlertRuleGroup(result))
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupGenerate(c *contextmodel.ReqContext, generation definitions.AlertRuleGroupGeneration, folderUID string, group string) response.Response {
+	tmpl, err := AlertRuleFromProvisionedAlertRule(generation.Template)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	inventory := make([]map[string]string, 0, len(generation.Inventory))
+	for _, row := range generation.Inventory {
+		values := make(map[string]string, len(row))
+		for name, value := range row {
+			switch value := value.(type) {
+			case string:
+				values[name] = value
+			case nil:
+				values[name] = ""
+			default:
+				// Numbers and booleans are written as in JSON, so that they can be used as they are in the models.
+				b, err := json.Marshal(value)
+				if err != nil {
+					return ErrResp(http.StatusBadRequest, err, "")
+				}
+				values[name] = string(b)
+			}
+		}
+		inventory = append(inventory, values)
+	}
+	if len(inventory) == 0 && generation.InventoryCSV != "" {
+		inventory, err = provisioning.ParseRuleInventoryCSV(strings.NewReader(generation.InventoryCSV))
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+	provenance := determineProvenance(c)
+	userID, _ := identity.UserIdentifier(c.SignedInUser.GetNamespacedID())
+	groupModel := alerting_models.AlertRuleGroup{Title: group, FolderUID: folderUID, Interval: generation.Interval}
+	result, err := srv.alertRules.GenerateRuleGroup(c.Req.Context(), userID, c.SignedInUser.GetOrgID(), groupModel, tmpl, inventory, alerting_models.Provenance(provenance))
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) || errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to generate rule group", err)
 	}
-	return response.JSON(http.StatusOK, ag)
+	return withRuleWarnings(c, response.JSON(http.StatusOK, ApiAlertRuleGroupFromAlertRuleGroup(result)), result.Rules...)
 }
 
 func (srv *ProvisioningSrv) RouteDeleteAlertRuleGroup(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
@@ -509,6 +1742,110 @@ func (srv *ProvisioningSrv) RouteDeleteAlertRuleGroup(c *contextmodel.ReqContext
 	return response.JSON(http.StatusNoContent, "")
 }
 
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupMove(c *contextmodel.ReqContext, body definitions.RuleGroupMove, folderUID string, group string) response.Response {
+	if body.FolderUID == "" || body.Title == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("folderUid and title must be set"), "")
+	}
+	provenance := determineProvenance(c)
+	from := alerting_models.AlertRuleGroupKey{NamespaceUID: folderUID, RuleGroup: group}
+	to := alerting_models.AlertRuleGroupKey{NamespaceUID: body.FolderUID, RuleGroup: body.Title}
+	err := srv.alertRules.MoveRuleGroup(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), from, to, alerting_models.Provenance(provenance))
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to move rule group", err)
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+func (srv *ProvisioningSrv) RoutePostPrometheusRuleGroupImport(c *contextmodel.ReqContext, body definitions.PrometheusRuleGroupImport, folderUID string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.alertRules.ImportPrometheusRuleGroup(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), folderUID, []byte(body.Group), body.DatasourceUID, alerting_models.Provenance(provenance))
+	if err != nil {
+		return replaceRuleGroupErrorResponse(err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupArchive(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.alertRules.ArchiveRuleGroup(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), folderUID, group, alerting_models.Provenance(provenance))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to archive rule group", err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleGroupUnarchive(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.alertRules.UnarchiveRuleGroup(c.Req.Context(), c.SignedInUser, c.SignedInUser.GetOrgID(), folderUID, group, alerting_models.Provenance(provenance))
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to unarchive rule group", err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.alertRules.ListRuleTemplates(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := make(definitions.AlertRuleTemplates, 0, len(templates))
+	for _, tmpl := range templates {
+		result = append(result, ApiAlertRuleTemplateFromAlertRuleTemplate(*tmpl))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	tmpl, err := srv.alertRules.GetRuleTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to get alert rule template", err)
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplateFromAlertRuleTemplate(*tmpl))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleTemplate(c *contextmodel.ReqContext, body definitions.AlertRuleTemplate, UID string) response.Response {
+	body.UID = UID
+	tmpl, err := AlertRuleTemplateFromApiAlertRuleTemplate(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	tmpl.OrgID = c.SignedInUser.GetOrgID()
+	saved, err := srv.alertRules.SaveRuleTemplate(c.Req.Context(), tmpl)
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to save alert rule template", err)
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleTemplateFromAlertRuleTemplate(saved))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteAlertRuleTemplate(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.alertRules.DeleteRuleTemplate(c.Req.Context(), c.SignedInUser.GetOrgID(), UID)
+	if err != nil {
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to delete alert rule template", err)
+	}
+	return response.JSON(http.StatusNoContent, "")
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTemplateInstantiate(c *contextmodel.ReqContext, body definitions.AlertRuleTemplateInstantiation, UID string) response.Response {
+	provenance := determineProvenance(c)
+	rule, err := srv.alertRules.InstantiateTemplate(c.Req.Context(), c.SignedInUser, UID, body.Parameters, alerting_models.Provenance(provenance))
+	if err != nil {
+		if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) || errors.Is(err, alerting_models.ErrAlertRuleUniqueConstraintViolation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, store.ErrGroupLocked) || errors.Is(err, provisioning.ErrProvenanceNotAllowed) {
+			return response.Err(err)
+		}
+		return response.ErrOrFallback(http.StatusInternalServerError, "failed to instantiate alert rule template", err)
+	}
+	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRule(rule, alerting_models.Provenance(provenance)))
+}
+
 func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	if _, disabled := ctx.Req.Header[disableProvenanceHeaderName]; disabled {
 		return definitions.Provenance(alerting_models.ProvenanceNone)
@@ -516,6 +1853,21 @@ func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	return definitions.Provenance(alerting_models.ProvenanceAPI)
 }
 
+// withRuleWarnings adds a Warning header to the response for every warning of the analysis of the rules, if the
+// request asks for it.
+func withRuleWarnings(c *contextmodel.ReqContext, resp *response.NormalResponse, rules ...alerting_models.AlertRule) *response.NormalResponse {
+	if _, ok := c.Req.Header[analyzeRulesHeaderName]; !ok {
+		return resp
+	}
+	for _, rule := range rules {
+		for _, warning := range alerting_models.AnalyzeRule(rule) {
+			// 199 is the code of miscellaneous warnings.
+			resp.Header().Add("Warning", fmt.Sprintf("199 - %q", fmt.Sprintf("rule '%s': %s", rule.Title, warning)))
+		}
+	}
+	return resp
+}
+
 func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryParams {
 	var format = "yaml"
 
@@ -541,10 +1893,78 @@ func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryPar
 	return params
 }
 
+// readExport runs the reads of an export. If a consistent export is requested, they all run in a snapshot of the store,
+// so that the export does not mix the states before and after concurrent changes.
+func (srv *ProvisioningSrv) readExport(c *contextmodel.ReqContext, read func(ctx context.Context) error) error {
+	if c.QueryBoolWithDefault("consistent", false) {
+		return srv.xact.InSnapshot(c.Req.Context(), read)
+	}
+	return read(c.Req.Context())
+}
+
 func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExport) response.Response {
+	return exportResponseWithETag(c, body, "")
+}
+
+// exportRuleGroupsResponse serves the export of the rule groups. Conditional requests are answered without
+// serializing the export if the groups did not change since the client got it.
+func (srv *ProvisioningSrv) exportRuleGroupsResponse(c *contextmodel.ReqContext, groups []alerting_models.AlertRuleGroupWithFolderTitle) response.Response {
+	params := extractExportRequest(c)
+	keyByUID, keyByPath := false, false
+	switch folderKey := c.Query("folderKey"); folderKey {
+	case "", "title":
+	case "uid":
+		keyByUID = true
+	case "path":
+		keyByPath = true
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid folder key '%s', expected 'title', 'uid' or 'path'", folderKey), "")
+	}
+	if keyByPath {
+		if err := srv.alertRules.SetAlertGroupsFolderPaths(c.Req.Context(), c.SignedInUser.GetOrgID(), groups); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to get folder paths")
+		}
+	}
+	withMetadata := c.QueryBoolWithDefault("metadata", false) && params.Format == "yaml"
+	withIndexes := c.QueryBoolWithDefault("ruleGroupIndex", false) && params.Format != "hcl"
+	withVersions := c.QueryBoolWithDefault("groupVersion", false) && params.Format != "hcl"
+	// The folder titles are part of the tag, so that exports are not served from caches after a folder is renamed.
+	// The tag is weak, so exports with metadata that differ only by their generation time can share it.
+	etag := provisioning.ExportETag(fmt.Sprintf("%s;download=%t;folderKeyUID=%t;folderKeyPath=%t;metadata=%t;ruleGroupIndex=%t;groupVersion=%t", params.Format, params.Download, keyByUID, keyByPath, withMetadata, withIndexes, withVersions), groups)
+	if provisioning.ETagMatches(c.Req.Header.Get("If-None-Match"), etag) {
+		return response.Empty(http.StatusNotModified).SetHeader("ETag", etag)
+	}
+
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groups)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
+	if keyByUID {
+		KeyAlertingFileExportByFolderUID(&e)
+	}
+	if keyByPath {
+		KeyAlertingFileExportByFolderPath(&e, groups)
+	}
+	if withIndexes {
+		IndexAlertingFileExportRules(&e)
+	}
+	if withVersions {
+		VersionAlertingFileExportGroups(&e, groups)
+	}
+	if withMetadata {
+		e.HeadComment = provisioning.NewExportMetadata(time.Now(), srv.exportSource, groups).Comment()
+	}
+	return exportResponseWithETag(c, e, etag)
+}
+
+func exportResponseWithETag(c *contextmodel.ReqContext, body definitions.AlertingFileExport, etag string) response.Response {
 	params := extractExportRequest(c)
 	if params.Format == "hcl" {
-		return exportHcl(params.Download, body)
+		return withETag(exportHcl(params.Download, body), etag)
+	}
+
+	if strings.Contains(c.Req.Header.Get("Accept-Encoding"), "gzip") {
+		return newGzipExportResponse(params, body, etag)
 	}
 
 	if params.Download {
@@ -552,56 +1972,73 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 		if params.Format == "yaml" {
 			r = response.YAMLDownload
 		}
-		return r(http.StatusOK, body, fmt.Sprintf("export.%s", params.Format))
+		return withETag(r(http.StatusOK, body, fmt.Sprintf("export.%s", params.Format)), etag)
 	}
 
 	r := response.JSON
 	if params.Format == "yaml" {
 		r = response.YAML
 	}
-	return r(http.StatusOK, body)
+	return withETag(r(http.StatusOK, body), etag)
+}
+
+func withETag(resp *response.NormalResponse, etag string) *response.NormalResponse {
+	if etag == "" || resp.Status() != http.StatusOK {
+		return resp
+	}
+	return resp.SetHeader("ETag", etag)
+}
+
+// contactPointExportID returns the identifier of the exported contact point, which is the UID of its first
+// integration. Contact points without integrations are identified by their name.
+func contactPointExportID(cp definitions.ContactPointExport) string {
+	if len(cp.Receivers) > 0 && cp.Receivers[0].UID != "" {
+		return cp.Receivers[0].UID
+	}
+	return cp.Name
 }
 
-func exportHcl(download bool, body definitions.AlertingFileExport) response.Response {
+func exportHcl(download bool, body definitions.AlertingFileExport) *response.NormalResponse {
 	resources := make([]hcl.Resource, 0, len(body.Groups)+len(body.ContactPoints)+len(body.Policies)+len(body.MuteTimings))
 	convertToResources := func() error {
-		for idx, group := range body.Groups {
+		for _, group := range body.Groups {
 			gr := group
 			resources = append(resources, hcl.Resource{
 				Type: "grafana_rule_group",
-				Name: fmt.Sprintf("rule_group_%04d", idx),
+				Name: provisioning.TerraformResourceName("rule_group", gr.FolderUID+"_"+gr.Name),
 				Body: &gr,
 			})
 		}
-		for idx, cp := range body.ContactPoints {
+		for _, cp := range body.ContactPoints {
 			upd, err := ContactPointFromContactPointExport(cp)
 			if err != nil {
 				return fmt.Errorf("failed to convert contact points to HCL:%w", err)
 			}
 			resources = append(resources, hcl.Resource{
 				Type: "grafana_contact_point",
-				Name: fmt.Sprintf("contact_point_%d", idx),
+				Name: provisioning.TerraformResourceName("contact_point", contactPointExportID(cp)),
 				Body: &upd,
 			})
 		}
 
-		for idx, cp := range body.Policies {
+		for _, cp := range body.Policies {
 			policy := cp.RouteExport
 			resources = append(resources, hcl.Resource{
 				Type: "grafana_notification_policy",
-				Name: fmt.Sprintf("notification_policy_%d", idx+1),
+				Name: provisioning.TerraformResourceName("notification_policy", strconv.FormatInt(cp.OrgID, 10)),
 				Body: policy,
 			})
 		}
 
-		for idx, mt := range body.MuteTimings {
+		for _, mt := range body.MuteTimings {
 			mthcl, err := MuteTimingIntervalToMuteTimeIntervalHclExport(mt)
 			if err != nil {
 				return fmt.Errorf("failed to convert mute timing [%s] to HCL:%w", mt.Name, err)
 			}
 			resources = append(resources, hcl.Resource{
 				Type: "grafana_mute_timing",
-				Name: fmt.Sprintf("mute_timing_%d", idx+1),
+				// Mute timings have no UID, they are identified by their name.
+				Name: provisioning.TerraformResourceName("mute_timing", mt.Name),
 				Body: mthcl,
 			})
 		}
@@ -1,13 +1,17 @@
 package api
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"path"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -19,18 +23,28 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/log/logtest"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	datasource_fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	secrets_fakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -55,6 +69,81 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 200, response.Status())
 		})
 
+		t.Run("effective policy", func(t *testing.T) {
+			t.Run("GET returns 200 for a known path", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = createFakeNotificationPolicyService()
+				rc := createTestRequestCtx()
+				rc.Context.Req.Form.Set("path", "0")
+
+				response := sut.RouteGetEffectivePolicy(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.JSONEq(t, `{"path": [0], "receiver": "default-receiver", "group_by": null, "group_wait": "0s", "group_interval": "0s", "repeat_interval": "0s"}`, string(response.Body()))
+			})
+
+			t.Run("GET returns 400 for an invalid path", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rc.Context.Req.Form.Set("path", "0.a")
+
+				response := sut.RouteGetEffectivePolicy(&rc)
+
+				require.Equal(t, 400, response.Status())
+			})
+
+			t.Run("GET returns 404 for an unknown path", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rc.Context.Req.Form.Set("path", "5")
+
+				response := sut.RouteGetEffectivePolicy(&rc)
+
+				require.Equal(t, 404, response.Status())
+			})
+		})
+
+		t.Run("policy tree merge", func(t *testing.T) {
+			fragment := definitions.PolicyTreeFragment{Routes: []*definitions.Route{{Receiver: "merged-receiver"}}}
+
+			t.Run("POST returns 200 with the merged tree", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = createFakeNotificationPolicyService()
+				rc := createTestRequestCtx()
+				anchored := fragment
+				anchored.Anchor = definitions.ObjectMatchers{{Type: labels.MatchEqual, Name: "foo", Value: "bar"}}
+
+				response := sut.RoutePostPolicyTreeMerge(&rc, anchored)
+
+				require.Equal(t, 200, response.Status())
+				tree := definitions.Route{}
+				require.NoError(t, json.Unmarshal(response.Body(), &tree))
+				require.Len(t, tree.Routes[0].Routes, 1)
+				require.Equal(t, "merged-receiver", tree.Routes[0].Routes[0].Receiver)
+			})
+
+			t.Run("POST returns 404 for an unknown anchor policy", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				anchored := fragment
+				anchored.Anchor = definitions.ObjectMatchers{{Type: labels.MatchEqual, Name: "foo", Value: "unknown"}}
+
+				response := sut.RoutePostPolicyTreeMerge(&rc, anchored)
+
+				require.Equal(t, 404, response.Status())
+			})
+
+			t.Run("POST returns 400 for invalid policies", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeRejectingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostPolicyTreeMerge(&rc, fragment)
+
+				require.Equal(t, 400, response.Status())
+			})
+		})
+
 		t.Run("successful PUT returns 202", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
@@ -117,37 +206,614 @@ func TestProvisioningApi(t *testing.T) {
 				sut.policies = &fakeFailingNotificationPolicyService{}
 				rc := createTestRequestCtx()
 
-				response := sut.RouteGetPolicyTree(&rc)
+				response := sut.RouteGetPolicyTree(&rc)
+
+				require.Equal(t, 500, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "something went wrong")
+			})
+
+			t.Run("PUT returns 500", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeFailingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+				tree := definitions.Route{}
+
+				response := sut.RoutePutPolicyTree(&rc, tree)
+
+				require.Equal(t, 500, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "something went wrong")
+			})
+
+			t.Run("DELETE returns 500", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeFailingNotificationPolicyService{}
+				rc := createTestRequestCtx()
+
+				response := sut.RouteResetPolicyTree(&rc)
+
+				require.Equal(t, 500, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "something went wrong")
+			})
+		})
+	})
+
+	t.Run("alertmanager routing", func(t *testing.T) {
+		t.Run("successful PUT returns 202 and the routing can be read back", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertmanagerRouting(&rc, definitions.AlertmanagerRouting{SendAlertsTo: definitions.InternalAlertmanager})
+			require.Equal(t, 202, response.Status())
+
+			response = sut.RouteGetAlertmanagerRouting(&rc)
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"sendAlertsTo": "internal"}`, string(response.Body()))
+		})
+
+		t.Run("PUT with invalid routing returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertmanagerRouting(&rc, definitions.AlertmanagerRouting{SendAlertsTo: "some"})
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("export returns the routing in provisioning file format", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Context.Req.Header.Add("Accept", "application/json")
+
+			response := sut.RouteGetAlertmanagerRoutingExport(&rc)
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"apiVersion": 1, "alertmanagerRouting": [{"orgId": 1, "sendAlertsTo": "all"}]}`, string(response.Body()))
+		})
+	})
+
+	t.Run("rule title uniqueness", func(t *testing.T) {
+		t.Run("the policy of the organization overrides the one of the server settings until it is reset", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetRuleTitleUniqueness(&rc)
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"scope": "", "inherited": true}`, string(response.Body()))
+
+			response = sut.RoutePutRuleTitleUniqueness(&rc, definitions.RuleTitleUniqueness{Scope: "folder", FolderUIDs: []string{"folder-uid"}})
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"scope": "folder", "folderUids": ["folder-uid"], "inherited": false}`, string(response.Body()))
+
+			response = sut.RouteResetRuleTitleUniqueness(&rc)
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"scope": "", "inherited": true}`, string(response.Body()))
+		})
+
+		t.Run("PUT with invalid scope returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutRuleTitleUniqueness(&rc, definitions.RuleTitleUniqueness{Scope: "org"})
+
+			require.Equal(t, 400, response.Status())
+		})
+	})
+
+	t.Run("bulk operations", func(t *testing.T) {
+		t.Run("report the result in each organization", func(t *testing.T) {
+			response := bulkResponse([]provisioning.OrgResult{
+				{OrgID: 1},
+				{OrgID: 2, Err: fmt.Errorf("%w: contact point 'webhook' does not exist", provisioning.ErrNotFound)},
+			}, nil)
+
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"results": [
+				{"orgId": 1, "status": "success"},
+				{"orgId": 2, "status": "error", "error": "object not found: contact point 'webhook' does not exist"}
+			]}`, string(response.Body()))
+		})
+
+		t.Run("return 400 if the operation is invalid", func(t *testing.T) {
+			response := bulkResponse(nil, fmt.Errorf("%w: no rule group to install", provisioning.ErrValidation))
+
+			require.Equal(t, 400, response.Status())
+		})
+	})
+
+	t.Run("resource tags", func(t *testing.T) {
+		t.Run("alert rules are filtered by tag", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+			response := sut.RoutePutAlertRuleTags(&rc, definitions.ResourceTags{Tags: []string{"canary", " canary"}}, "rule1")
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"tags": ["canary"]}`, string(response.Body()))
+
+			response = sut.RouteGetAlertRuleTags(&rc, "rule1")
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"tags": ["canary"]}`, string(response.Body()))
+
+			rc.Context.Req.Form.Set("tag", "canary")
+			response = sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 200, response.Status())
+			var rules definitions.ProvisionedAlertRules
+			require.NoError(t, json.Unmarshal(response.Body(), &rules))
+			require.Len(t, rules, 1)
+			require.Equal(t, "rule1", rules[0].UID)
+		})
+
+		t.Run("tagged rule groups are paused", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+			response := sut.RoutePutAlertRuleGroupTags(&rc, definitions.ResourceTags{Tags: []string{"canary"}}, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+
+			response = sut.RoutePostAlertRulesPause(&rc, definitions.AlertRulesPause{Tag: "canary", IsPaused: true})
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"updated": 2}`, string(response.Body()))
+
+			response = sut.RouteRouteGetAlertRule(&rc, "rule1")
+			require.Equal(t, 200, response.Status())
+			var rule definitions.ProvisionedAlertRule
+			require.NoError(t, json.Unmarshal(response.Body(), &rule))
+			require.True(t, rule.IsPaused)
+		})
+
+		t.Run("tags of missing resources return 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertRuleTags(&rc, definitions.ResourceTags{Tags: []string{"canary"}}, "does not exist")
+			require.Equal(t, 404, response.Status())
+
+			response = sut.RouteGetAlertRuleGroupTags(&rc, "folder-uid", "does not exist")
+			require.Equal(t, 404, response.Status())
+
+			response = sut.RouteGetContactPointTags(&rc, "does not exist")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("invalid tags return 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+
+			response := sut.RoutePutAlertRuleTags(&rc, definitions.ResourceTags{Tags: []string{""}}, "rule1")
+			require.Equal(t, 400, response.Status())
+		})
+	})
+
+	t.Run("folder annotations", func(t *testing.T) {
+		t.Run("are merged into written rules and stripped from exported rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetFolderAnnotations(&rc, "folder-uid")
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"annotations": {}}`, string(response.Body()))
+
+			response = sut.RoutePutFolderAnnotations(&rc, definitions.FolderAnnotations{Annotations: map[string]string{"oncall_team": "sre"}}, "folder-uid")
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"annotations": {"oncall_team": "sre"}}`, string(response.Body()))
+
+			rule := createTestAlertRule("rule1", 1)
+			rule.Annotations = map[string]string{"summary": "test"}
+			insertRule(t, sut, rule)
+
+			response = sut.RouteRouteGetAlertRule(&rc, "rule1")
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, map[string]string{"summary": "test", "oncall_team": "sre"}, deserializeRule(t, response.Body()).Annotations)
+
+			rc.Context.Req.Header.Add("Accept", "application/json")
+			response = sut.RouteGetAlertRuleExport(&rc, "rule1")
+			require.Equal(t, 200, response.Status())
+			var export definitions.AlertingFileExport
+			require.NoError(t, json.Unmarshal(response.Body(), &export))
+			require.Equal(t, &map[string]string{"summary": "test"}, export.Groups[0].Rules[0].Annotations)
+		})
+
+		t.Run("invalid annotations return 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutFolderAnnotations(&rc, definitions.FolderAnnotations{Annotations: map[string]string{"": "sre"}}, "folder-uid")
+			require.Equal(t, 400, response.Status())
+		})
+	})
+
+	t.Run("folder evaluation", func(t *testing.T) {
+		t.Run("is paused and resumed without changing the rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+
+			response := sut.RouteGetFolderEvaluation(&rc, "folder-uid")
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"paused": false}`, string(response.Body()))
+
+			response = sut.RoutePutFolderEvaluation(&rc, definitions.FolderEvaluation{Paused: true}, "folder-uid")
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"paused": true}`, string(response.Body()))
+
+			response = sut.RouteRouteGetAlertRule(&rc, "rule1")
+			require.Equal(t, 200, response.Status())
+			require.False(t, deserializeRule(t, response.Body()).IsPaused, "the rules of the folder should not be paused")
+
+			response = sut.RoutePutFolderEvaluation(&rc, definitions.FolderEvaluation{Paused: false}, "folder-uid")
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"paused": false}`, string(response.Body()))
+		})
+	})
+
+	t.Run("provenance transition", func(t *testing.T) {
+		t.Run("hands an alert rule over to another provenance", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+
+			response := sut.RoutePutAlertRuleProvenance(&rc, definitions.ProvenanceTransition{From: "", To: "api"}, "rule1")
+			require.Equal(t, 204, response.Status())
+		})
+
+		t.Run("hands the alert rules of a rule group over to another provenance", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+			response := sut.RoutePutAlertRuleGroupProvenance(&rc, definitions.ProvenanceTransition{From: "", To: "api"}, "folder-uid", "my-cool-group")
+			require.Equal(t, 204, response.Status())
+		})
+
+		t.Run("returns 409 if the alert rule does not have the expected provenance", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+
+			response := sut.RoutePutAlertRuleProvenance(&rc, definitions.ProvenanceTransition{From: "file", To: ""}, "rule1")
+			require.Equal(t, 409, response.Status())
+
+			response = sut.RoutePutAlertRuleGroupProvenance(&rc, definitions.ProvenanceTransition{From: "file", To: ""}, "folder-uid", "my-cool-group")
+			require.Equal(t, 409, response.Status())
+		})
+
+		t.Run("returns 400 for an unknown provenance", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+
+			response := sut.RoutePutAlertRuleProvenance(&rc, definitions.ProvenanceTransition{From: "", To: "terraform"}, "rule1")
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("returns 404 for missing resources", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutAlertRuleProvenance(&rc, definitions.ProvenanceTransition{From: "", To: "api"}, "does not exist")
+			require.Equal(t, 404, response.Status())
+
+			response = sut.RoutePutAlertRuleGroupProvenance(&rc, definitions.ProvenanceTransition{From: "", To: "api"}, "folder-uid", "does not exist")
+			require.Equal(t, 404, response.Status())
+		})
+	})
+
+	t.Run("alert rules usage", func(t *testing.T) {
+		getUsage := func(t *testing.T, sut ProvisioningSrv, query url.Values) response.Response {
+			t.Helper()
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form = query
+			return sut.RouteGetAlertRulesUsage(&rc)
+		}
+
+		// The usage only lists the provisioned rules, which the store finds by their provenance, so the provenance is
+		// kept in the database rather than in the mock.
+		createUsageSut := func(t *testing.T) ProvisioningSrv {
+			t.Helper()
+			env := createTestEnv(t, testConfig)
+			env.prov = env.store
+			return createProvisioningSrvSutFromEnv(t, &env)
+		}
+
+		t.Run("GET returns the usage of the provisioned alert rules", func(t *testing.T) {
+			sut := createUsageSut(t)
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+			response := getUsage(t, sut, url.Values{"from": {"1700000000"}, "to": {"1700003600"}, "neverFired": {"true"}})
+			require.Equal(t, 200, response.Status())
+			var usage definitions.AlertRulesUsage
+			require.NoError(t, json.Unmarshal(response.Body(), &usage))
+			require.Equal(t, time.Unix(1700000000, 0).UTC(), usage.From.UTC())
+			require.Equal(t, time.Unix(1700003600, 0).UTC(), usage.To.UTC())
+			require.Len(t, usage.Rules, 2)
+			for _, rule := range usage.Rules {
+				require.Zero(t, rule.FiringCount)
+				require.Zero(t, rule.FiringOrResolvedCount)
+				require.Nil(t, rule.LastFiredAt)
+			}
+
+			response = getUsage(t, sut, url.Values{"provenance": {"file"}})
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &usage))
+			require.Empty(t, usage.Rules)
+		})
+
+		t.Run("GET returns the noise report of the rule groups", func(t *testing.T) {
+			sut := createUsageSut(t)
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form = url.Values{"sortBy": {"firingOrResolved"}}
+
+			response := sut.RouteGetRuleGroupsNoiseReport(&rc)
+			require.Equal(t, 200, response.Status())
+			var report definitions.RuleGroupsNoiseReport
+			require.NoError(t, json.Unmarshal(response.Body(), &report))
+			require.Equal(t, []definitions.RuleGroupNoise{{FolderUID: "folder-uid", RuleGroup: "my-cool-group", RuleCount: 1}}, report.Groups)
+
+			rc.Context.Req.Form = url.Values{"sortBy": {"title"}}
+			response = sut.RouteGetRuleGroupsNoiseReport(&rc)
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("GET returns 400 for invalid queries", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+
+			require.Equal(t, 400, getUsage(t, sut, url.Values{"provenance": {"none"}}).Status())
+			require.Equal(t, 400, getUsage(t, sut, url.Values{"provenance": {"unknown"}}).Status())
+			require.Equal(t, 400, getUsage(t, sut, url.Values{"from": {"1700003600"}, "to": {"1700000000"}}).Status())
+		})
+	})
+
+	t.Run("rule group jobs", func(t *testing.T) {
+		t.Run("POST starts a job and returns 202", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			jobs := &fakeRuleGroupJobService{}
+			sut.ruleGroupJobs = jobs
+			rc := createTestRequestCtx()
+			group := definitions.AlertRuleGroup{
+				Interval: 60,
+				Rules:    []definitions.ProvisionedAlertRule{createTestAlertRule("rule", 1)},
+			}
+
+			response := sut.RoutePostAlertRuleGroupJob(&rc, group, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 202, response.Status())
+			var job definitions.RuleGroupJob
+			require.NoError(t, json.Unmarshal(response.Body(), &job))
+			require.Equal(t, "job-uid", job.UID)
+			require.Equal(t, "pending", job.Status)
+			require.Equal(t, "my-cool-group", jobs.started.Title)
+			require.Equal(t, "folder-uid", jobs.started.FolderUID)
+		})
+
+		t.Run("POST returns 503 if too many jobs are waiting", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.ruleGroupJobs = &fakeRuleGroupJobService{err: provisioning.ErrRuleGroupJobQueueFull}
+			rc := createTestRequestCtx()
+			group := definitions.AlertRuleGroup{Interval: 60}
+
+			response := sut.RoutePostAlertRuleGroupJob(&rc, group, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 503, response.Status())
+		})
+
+		t.Run("GET returns 404 for unknown jobs", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.ruleGroupJobs = &fakeRuleGroupJobService{}
+			rc := createTestRequestCtx()
+
+			require.Equal(t, 404, sut.RouteGetRuleGroupJob(&rc, "unknown").Status())
+			require.Equal(t, 200, sut.RouteGetRuleGroupJob(&rc, "job-uid").Status())
+		})
+	})
+
+	t.Run("provisioning webhook dead letters", func(t *testing.T) {
+		t.Run("GET returns the dead letters of the organization", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.webhook = &fakeProvisioningWebhook{}
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetWebhookDeadLetters(&rc)
+
+			require.Equal(t, 200, response.Status())
+			var deliveries definitions.WebhookDeliveries
+			require.NoError(t, json.Unmarshal(response.Body(), &deliveries))
+			require.Len(t, deliveries, 1)
+			require.Equal(t, "delivery-uid", deliveries[0].UID)
+			require.JSONEq(t, `{"uid":"rule"}`, string(deliveries[0].Event))
+		})
+
+		t.Run("POST replays a dead letter and returns 202", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			webhook := &fakeProvisioningWebhook{}
+			sut.webhook = webhook
+			rc := createTestRequestCtx()
+
+			require.Equal(t, 202, sut.RoutePostWebhookDeadLetterReplay(&rc, "delivery-uid").Status())
+			require.Equal(t, []string{"delivery-uid"}, webhook.replayed)
+			require.Equal(t, 404, sut.RoutePostWebhookDeadLetterReplay(&rc, "unknown").Status())
+		})
+
+		t.Run("POST returns 400 if the webhook is disabled", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.webhook = &fakeProvisioningWebhook{err: provisioning.ErrWebhookDisabled}
+			rc := createTestRequestCtx()
+
+			require.Equal(t, 400, sut.RoutePostWebhookDeadLetterReplay(&rc, "delivery-uid").Status())
+		})
+	})
+
+	t.Run("alert rule sync status", func(t *testing.T) {
+		t.Run("GET returns the status of the synced groups", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			lastSync := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			sut.ruleSync = &fakeRuleSyncService{status: provisioning.RuleSyncStatus{
+				Enabled:  true,
+				LastSync: lastSync,
+				Groups: []provisioning.RuleGroupSyncStatus{{
+					OrgID:     1,
+					FolderUID: "folder-uid",
+					RuleGroup: "my-cool-group",
+					File:      "rules.yaml",
+					LastSync:  lastSync,
+					Result:    provisioning.RuleGroupSynced,
+					Created:   []string{"rule1"},
+					Updated:   []string{},
+					Deleted:   []string{},
+					Drift:     true,
+				}},
+			}}
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRuleSyncStatus(&rc)
+			require.Equal(t, 200, response.Status())
+			var status definitions.AlertRuleSyncStatus
+			require.NoError(t, json.Unmarshal(response.Body(), &status))
+			require.True(t, status.Enabled)
+			require.Equal(t, lastSync, *status.LastSync)
+			require.Len(t, status.Groups, 1)
+			require.Equal(t, "rules.yaml", status.Groups[0].File)
+			require.Equal(t, "synced", status.Groups[0].Result)
+			require.Equal(t, []string{"rule1"}, status.Groups[0].Created)
+			require.True(t, status.Groups[0].Drift)
+		})
+
+		t.Run("GET returns a disabled status if no directory is synced", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRuleSyncStatus(&rc)
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"enabled": false, "groups": []}`, string(response.Body()))
+		})
+
+		t.Run("GET returns 500 if the status cannot be read", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.ruleSync = &fakeRuleSyncService{err: errors.New("database is locked")}
+			rc := createTestRequestCtx()
+
+			require.Equal(t, 500, sut.RouteGetAlertRuleSyncStatus(&rc).Status())
+		})
+	})
+
+	t.Run("alert rule trash", func(t *testing.T) {
+		t.Run("deleted alert rules are listed and can be restored", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			rc := createTestRequestCtx()
+			require.Equal(t, 204, sut.RouteDeleteAlertRule(&rc, "rule1").Status())
+
+			response := sut.RouteGetDeletedAlertRules(&rc)
+			require.Equal(t, 200, response.Status())
+			var deleted definitions.DeletedAlertRules
+			require.NoError(t, json.Unmarshal(response.Body(), &deleted))
+			require.Len(t, deleted, 1)
+			require.Equal(t, "rule1", deleted[0].UID)
+			require.Equal(t, "folder-uid", deleted[0].FolderUID)
+			require.Equal(t, "my-cool-group", deleted[0].RuleGroup)
+
+			response = sut.RoutePostAlertRuleRestore(&rc, "rule1")
+			require.Equal(t, 201, response.Status())
+			restored := deserializeRule(t, response.Body())
+			require.Equal(t, "rule1", restored.UID)
+			require.Equal(t, "rule1", restored.Title)
+
+			response = sut.RouteRouteGetAlertRule(&rc, "rule1")
+			require.Equal(t, 200, response.Status())
+		})
+
+		t.Run("restore returns 404 for rules that are not in the trash", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRuleRestore(&rc, "does not exist")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("restore returns 409 if a rule with the UID exists", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			rc := createTestRequestCtx()
+			require.Equal(t, 204, sut.RouteDeleteAlertRule(&rc, "rule1").Status())
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+
+			response := sut.RoutePostAlertRuleRestore(&rc, "rule1")
+			require.Equal(t, 409, response.Status())
+		})
+	})
+
+	t.Run("file schema", func(t *testing.T) {
+		t.Run("GET returns the JSON Schema of provisioning files", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetProvisioningFileSchema(&rc)
+
+			require.Equal(t, 200, response.Status())
+			var schema definitions.ProvisioningFileSchema
+			require.NoError(t, json.Unmarshal(response.Body(), &schema))
+			require.Equal(t, "object", schema["type"])
+			require.Contains(t, schema["properties"], "groups")
+		})
+	})
+
+	t.Run("config snapshots", func(t *testing.T) {
+		t.Run("successful POST returns 201 and the snapshot is listed", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostConfigSnapshot(&rc)
+			require.Equal(t, 201, response.Status())
+
+			response = sut.RouteGetConfigSnapshots(&rc)
+			require.Equal(t, 200, response.Status())
+			var snapshots definitions.ConfigSnapshots
+			require.NoError(t, json.Unmarshal(response.Body(), &snapshots))
+			require.Len(t, snapshots, 1)
+			require.Equal(t, models.ConfigSnapshotVersion, snapshots[0].Version)
+			require.NotEmpty(t, snapshots[0].Hash)
+		})
+
+		t.Run("restore uses the replace mode by default", func(t *testing.T) {
+			snapshots := &fakeConfigSnapshotService{}
+			sut := createProvisioningSrvSut(t)
+			sut.configSnapshots = snapshots
+			rc := createTestRequestCtx()
+			require.Equal(t, 201, sut.RoutePostConfigSnapshot(&rc).Status())
 
-				require.Equal(t, 500, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "something went wrong")
-			})
+			response := sut.RoutePostConfigSnapshotRestore(&rc, "1")
 
-			t.Run("PUT returns 500", func(t *testing.T) {
-				sut := createProvisioningSrvSut(t)
-				sut.policies = &fakeFailingNotificationPolicyService{}
-				rc := createTestRequestCtx()
-				tree := definitions.Route{}
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, models.ConfigSnapshotRestoreReplace, snapshots.restored)
+		})
 
-				response := sut.RoutePutPolicyTree(&rc, tree)
+		t.Run("restore with an unknown mode returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			require.Equal(t, 201, sut.RoutePostConfigSnapshot(&rc).Status())
+			rc.Context.Req.Form.Set("mode", "some")
 
-				require.Equal(t, 500, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "something went wrong")
-			})
+			response := sut.RoutePostConfigSnapshotRestore(&rc, "1")
 
-			t.Run("DELETE returns 500", func(t *testing.T) {
-				sut := createProvisioningSrvSut(t)
-				sut.policies = &fakeFailingNotificationPolicyService{}
-				rc := createTestRequestCtx()
+			require.Equal(t, 400, response.Status())
+		})
 
-				response := sut.RouteResetPolicyTree(&rc)
+		t.Run("restore of an unknown snapshot returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
 
-				require.Equal(t, 500, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "something went wrong")
-			})
+			response := sut.RoutePostConfigSnapshotRestore(&rc, "1")
+
+			require.Equal(t, 404, response.Status())
 		})
 	})
 
@@ -187,6 +853,34 @@ func TestProvisioningApi(t *testing.T) {
 
 			require.Equal(t, 404, response.Status())
 		})
+
+		t.Run("have delivered notifications, GET returns the attempts", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sent := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			sut.deliveries = fakeDeliveryAttemptReader{"email-uid": {
+				{Time: sent.Add(time.Minute), Duration: 2 * time.Second, Alerts: 1, Error: "connection refused", Retry: true},
+				{Time: sent, Duration: 100 * time.Millisecond, Alerts: 3},
+			}}
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetContactPointDeliveries(&rc, "email-uid")
+
+			require.Equal(t, 200, response.Status())
+			var deliveries definitions.ContactPointDeliveries
+			require.NoError(t, json.Unmarshal(response.Body(), &deliveries))
+			require.Equal(t, definitions.ContactPointDeliveries{
+				UID:  "email-uid",
+				Name: "email receiver",
+				Type: "email",
+				Deliveries: []definitions.ContactPointDelivery{
+					{Time: sent.Add(time.Minute), Status: "failure", Error: "connection refused", Retry: true, Duration: "2s", Alerts: 1},
+					{Time: sent, Status: "success", Duration: "100ms", Alerts: 3},
+				},
+			}, deliveries)
+
+			response = sut.RouteGetContactPointDeliveries(&rc, "does not exist")
+			require.Equal(t, 404, response.Status())
+		})
 	})
 
 	t.Run("templates", func(t *testing.T) {
@@ -273,6 +967,106 @@ func TestProvisioningApi(t *testing.T) {
 			})
 		})
 
+		t.Run("are paginated", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			insertRule(t, sut, createTestAlertRule("rule2", 1))
+			insertRule(t, sut, createTestAlertRule("rule3", 1))
+			getPage := func(t *testing.T, limit, page string) []string {
+				t.Helper()
+				rc := createTestRequestCtx()
+				rc.Context.Req.Form.Set("limit", limit)
+				rc.Context.Req.Form.Set("page", page)
+				response := sut.RouteGetAlertRules(&rc)
+				require.Equal(t, 200, response.Status())
+				var rules definitions.ProvisionedAlertRules
+				require.NoError(t, json.Unmarshal(response.Body(), &rules))
+				uids := make([]string, 0, len(rules))
+				for _, r := range rules {
+					uids = append(uids, r.UID)
+				}
+				return uids
+			}
+
+			first := getPage(t, "2", "1")
+			require.Len(t, first, 2)
+			second := getPage(t, "2", "2")
+			require.Len(t, second, 1)
+			require.ElementsMatch(t, []string{"rule1", "rule2", "rule3"}, append(first, second...))
+			require.Len(t, getPage(t, "0", "0"), 3, "all rules should be returned without limit")
+
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("limit", "-1")
+			response := sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are filtered", func(t *testing.T) {
+			// The store filters the rules by the provenance that is kept in the database.
+			env := createTestEnv(t, testConfig)
+			env.prov = env.store
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			dbRule := createTestAlertRuleWithFolderAndGroup("rule1", 1, "folder-uid", "db-rules")
+			dbRule.Labels = map[string]string{"team": "db"}
+			insertRule(t, sut, dbRule)
+			insertRule(t, sut, createTestAlertRuleWithFolderAndGroup("rule2", 1, "folder-uid", "web-rules"))
+			getRules := func(t *testing.T, query url.Values) []string {
+				t.Helper()
+				rc := createTestRequestCtx()
+				rc.Context.Req.Form = query
+				response := sut.RouteGetAlertRules(&rc)
+				require.Equal(t, 200, response.Status())
+				var rules definitions.ProvisionedAlertRules
+				require.NoError(t, json.Unmarshal(response.Body(), &rules))
+				uids := make([]string, 0, len(rules))
+				for _, r := range rules {
+					uids = append(uids, r.UID)
+				}
+				return uids
+			}
+
+			require.Equal(t, []string{"rule1"}, getRules(t, url.Values{"groupPrefix": {"db-"}}))
+			require.Equal(t, []string{"rule1"}, getRules(t, url.Values{"matcher": {`{"Name":"team","Value":"db","Type":0}`}}))
+			require.Empty(t, getRules(t, url.Values{"folderUid": {"other-folder"}}))
+			require.Len(t, getRules(t, url.Values{"provenance": {"api"}}), 2)
+			require.Empty(t, getRules(t, url.Values{"provenance": {"none", "file"}}))
+
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form = url.Values{"provenance": {"unknown"}}
+			response := sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are analyzed on request", func(t *testing.T) {
+			rule := createTestAlertRule("rule", 1)
+			rule.Data = append(rule.Data, definitions.AlertQuery{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         json.RawMessage(`{"type": "math", "expression": "$C > 1"}`),
+			})
+
+			t.Run("POST returns the warnings in headers", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rc.Req.Header = map[string][]string{"X-Analyze-Rules": {"true"}}
+
+				resp := sut.RoutePostAlertRule(&rc, rule)
+
+				require.Equal(t, 201, resp.Status())
+				require.Equal(t, []string{`199 - "rule 'rule': B: refers to C, which is not defined"`}, resp.(*response.NormalResponse).Header().Values("Warning"))
+			})
+
+			t.Run("POST does not analyze the rule otherwise", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				resp := sut.RoutePostAlertRule(&rc, rule)
+
+				require.Equal(t, 201, resp.Status())
+				require.Empty(t, resp.(*response.NormalResponse).Header().Values("Warning"))
+			})
+		})
+
 		t.Run("exist in non-default orgs", func(t *testing.T) {
 			t.Run("POST sets expected fields with no provenance", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -300,65 +1094,607 @@ func TestProvisioningApi(t *testing.T) {
 				rc.OrgID = 3
 				rule.OrgID = 1 // Set the org back to something wrong, we should still prefer the value from the req context.
 
-				response := sut.RoutePutAlertRule(&rc, rule, rule.UID)
+				response := sut.RoutePutAlertRule(&rc, rule, rule.UID)
+
+				require.Equal(t, 200, response.Status())
+				created := deserializeRule(t, response.Body())
+				require.Equal(t, int64(3), created.OrgID)
+				require.Equal(t, definitions.Provenance(models.ProvenanceNone), created.Provenance)
+			})
+		})
+
+		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+
+			response := sut.RoutePutAlertRule(&rc, rule, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("were changed since they were read, PUT returns 409 unless forced", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+			response := sut.RouteRouteGetAlertRule(&rc, "rule")
+			require.Equal(t, 200, response.Status())
+			read := deserializeRule(t, response.Body())
+			require.NotZero(t, read.Version)
+
+			read.Title = "changed concurrently"
+			response = sut.RoutePutAlertRule(&rc, read, read.UID)
+			require.Equal(t, 200, response.Status())
+
+			read.Title = "changed"
+			response = sut.RoutePutAlertRule(&rc, read, read.UID)
+			require.Equal(t, 409, response.Status())
+			group := definitions.AlertRuleGroup{
+				Interval: 60,
+				Rules:    []definitions.ProvisionedAlertRule{read},
+			}
+			response = sut.RoutePutAlertRuleGroup(&rc, group, "folder-uid", "my-cool-group")
+			require.Equal(t, 409, response.Status())
+
+			rc.Context.Req.Form.Set("force", "true")
+			response = sut.RoutePutAlertRule(&rc, read, read.UID)
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, "changed", deserializeRule(t, response.Body()).Title)
+		})
+
+		t.Run("were added to their group since its version was read, PUT of the group returns 409 unless forced", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			var read definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &read))
+			insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+			response = sut.RoutePutAlertRuleGroup(&rc, read, "folder-uid", "my-cool-group")
+			require.Equal(t, 409, response.Status())
+
+			invalid := read
+			invalid.Version = "invalid"
+			response = sut.RoutePutAlertRuleGroup(&rc, invalid, "folder-uid", "my-cool-group")
+			require.Equal(t, 400, response.Status())
+
+			rc.Context.Req.Form.Set("force", "true")
+			response = sut.RoutePutAlertRuleGroup(&rc, read, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+		})
+
+		t.Run("were changed since the base version of their group, PUT merges the changes", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			// The rules are read back with their pending period in seconds, which must be the one that is stored in the
+			// history of the rules for the read rules to be the same as their base version.
+			for _, uid := range []string{"rule1", "rule2"} {
+				rule := createTestAlertRule(uid, 1)
+				rule.For = definitions.DurationOrSeconds(time.Minute)
+				insertRule(t, sut, rule)
+			}
+			getGroup := func() definitions.AlertRuleGroup {
+				response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+				require.Equal(t, 200, response.Status())
+				var group definitions.AlertRuleGroup
+				require.NoError(t, json.Unmarshal(response.Body(), &group))
+				return group
+			}
+			read := getGroup()
+			require.NotEmpty(t, read.Version)
+
+			concurrent := read.Rules[1]
+			concurrent.Title = "changed concurrently"
+			response := sut.RoutePutAlertRule(&rc, concurrent, concurrent.UID)
+			require.Equal(t, 200, response.Status())
+
+			changed := read
+			changed.Rules = slices.Clone(read.Rules)
+			changed.Rules[0].Title = "changed"
+			rc.Context.Req.Form.Set("baseVersion", read.Version)
+			response = sut.RoutePutAlertRuleGroup(&rc, changed, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			merged := getGroup()
+			require.Equal(t, "changed", merged.Rules[0].Title)
+			require.Equal(t, "changed concurrently", merged.Rules[1].Title)
+
+			changed.Rules[1].Title = "changed on both sides"
+			response = sut.RoutePutAlertRuleGroup(&rc, changed, "folder-uid", "my-cool-group")
+			require.Equal(t, 409, response.Status())
+
+			rc.Context.Req.Form.Set("baseVersion", "invalid")
+			response = sut.RoutePutAlertRuleGroup(&rc, changed, "folder-uid", "my-cool-group")
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are missing, GET returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			response := sut.RouteRouteGetAlertRule(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("have reached the rule quota, POST returns 403", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			quotas := provisioning.MockQuotaChecker{}
+			quotas.EXPECT().LimitExceeded()
+			env.quotas = &quotas
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rule := createTestAlertRule("rule", 1)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostAlertRule(&rc, rule)
+
+			require.Equal(t, 403, response.Status())
+		})
+
+		t.Run("are counted against the rule quota, GET returns the headroom", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			quotas := provisioning.MockQuotaChecker{}
+			quotas.EXPECT().LimitOK()
+			quotas.EXPECT().GetQuotasByScope(mock.Anything, quota.GlobalScope, mock.Anything).Return(nil, nil)
+			quotas.EXPECT().GetQuotasByScope(mock.Anything, quota.OrgScope, int64(1)).Return([]quota.QuotaDTO{
+				{Service: string(models.QuotaTargetSrv), Target: string(models.QuotaTarget), Limit: 10, Used: 1},
+			}, nil)
+			env.quotas = &quotas
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RouteGetAlertRulesQuota(&rc)
+
+			require.Equal(t, 200, response.Status())
+			var status definitions.AlertRulesQuotaStatus
+			require.NoError(t, json.Unmarshal(response.Body(), &status))
+			require.Equal(t, definitions.AlertRulesQuotaStatus{
+				Count:  1,
+				Limits: []definitions.AlertRulesQuotaLimit{{Scope: "org", Limit: 10, Used: 1, Headroom: 9}},
+				Groups: []definitions.AlertRuleGroupCount{{FolderUID: "folder-uid", RuleGroup: "my-cool-group", RuleCount: 1}},
+			}, status)
+		})
+	})
+
+	t.Run("alert rule groups", func(t *testing.T) {
+		t.Run("are present", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			t.Run("GET returns 200", func(t *testing.T) {
+				response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+			})
+
+			t.Run("DELETE returns 204", func(t *testing.T) {
+				response := sut.RouteDeleteAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 204, response.Status())
+			})
+		})
+
+		t.Run("PUT interval updates the interval of the rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RoutePutAlertRuleGroupInterval(&rc, definitions.RuleGroupInterval{Interval: 120}, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			var group definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.EqualValues(t, 120, group.Interval)
+
+			response = sut.RoutePutAlertRuleGroupInterval(&rc, definitions.RuleGroupInterval{Interval: 15}, "folder-uid", "my-cool-group")
+			require.Equal(t, 400, response.Status())
+			response = sut.RoutePutAlertRuleGroupInterval(&rc, definitions.RuleGroupInterval{Interval: 120}, "folder-uid", "missing")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are joined with the state of their alerts, GET returns the summary of the states", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			lastEvaluation := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			sut.states = fakeRuleStateReader{"rule": {
+				{State: eval.Alerting, LastEvaluationTime: lastEvaluation},
+				{State: eval.Alerting, LastEvaluationTime: lastEvaluation},
+				{State: eval.Pending, LastEvaluationTime: lastEvaluation.Add(-time.Minute)},
+				{State: eval.Normal, LastEvaluationTime: lastEvaluation},
+			}}
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			var group definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.Nil(t, group.Rules[0].State, "the state should be returned only when it is requested")
+
+			rc.Context.Req.Form.Set("withState", "true")
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.Equal(t, &definitions.AlertRuleStateSummary{
+				Normal:         1,
+				Pending:        1,
+				Firing:         2,
+				Health:         "ok",
+				LastEvaluation: &lastEvaluation,
+			}, group.Rules[0].State)
+
+			response = sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 200, response.Status())
+			var rules definitions.ProvisionedAlertRules
+			require.NoError(t, json.Unmarshal(response.Body(), &rules))
+			require.Equal(t, int64(2), rules[0].State.Firing)
+		})
+
+		t.Run("with an error and no data, the health of the rule is error", func(t *testing.T) {
+			err := errors.New("query failed")
+			require.Equal(t, "error", alertRuleStateSummary([]*state.State{{State: eval.Error, Error: err}, {State: eval.NoData}}).Health)
+			require.Equal(t, "error", alertRuleStateSummary([]*state.State{{State: eval.NoData}, {State: eval.Error, Error: err}}).Health)
+			require.Equal(t, "nodata", alertRuleStateSummary([]*state.State{{State: eval.Normal}, {State: eval.NoData}}).Health)
+		})
+
+		t.Run("are returned with the selected fields only, GET returns the fields", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+			rc.Context.Req.Form["fields"] = []string{"uid,title", " folderUID "}
+
+			response := sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 200, response.Status())
+			var rules []map[string]any
+			require.NoError(t, json.Unmarshal(response.Body(), &rules))
+			require.Len(t, rules, 1)
+			require.Equal(t, map[string]any{"uid": "rule", "title": "rule", "folderUID": "folder-uid"}, rules[0])
+
+			response = sut.RouteRouteGetAlertRule(&rc, "rule")
+			require.Equal(t, 200, response.Status())
+			var rule map[string]any
+			require.NoError(t, json.Unmarshal(response.Body(), &rule))
+			require.Equal(t, rules[0], rule)
+
+			rc.Context.Req.Form.Set("fields", "uid,queries")
+			response = sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are archived, GET excludes their rules by default", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+			listed := func(t *testing.T, archived string) int {
+				t.Helper()
+				rc.Context.Req.Form.Set("archived", archived)
+				response := sut.RouteGetAlertRules(&rc)
+				require.Equal(t, 200, response.Status())
+				var rules definitions.ProvisionedAlertRules
+				require.NoError(t, json.Unmarshal(response.Body(), &rules))
+				return len(rules)
+			}
+
+			response := sut.RoutePostAlertRuleGroupArchive(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 204, response.Status())
+			require.Zero(t, listed(t, ""))
+			require.Equal(t, 1, listed(t, "include"))
+			require.Equal(t, 1, listed(t, "only"))
+
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status(), "archived groups should still be readable")
+			var group definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.True(t, group.Archived)
+
+			rc.Context.Req.Form.Del("archived")
+			require.Equal(t, 404, sut.RouteGetAlertRulesExport(&rc).Status(), "archived groups should not be exported by default")
+			rc.Context.Req.Form.Set("archived", "include")
+			require.Equal(t, 200, sut.RouteGetAlertRulesExport(&rc).Status())
+
+			response = sut.RoutePostAlertRuleGroupUnarchive(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 204, response.Status())
+			require.Equal(t, 1, listed(t, "exclude"))
+			require.Zero(t, listed(t, "only"))
+
+			rc.Context.Req.Form.Set("archived", "all")
+			response = sut.RouteGetAlertRules(&rc)
+			require.Equal(t, 400, response.Status())
+
+			response = sut.RoutePostAlertRuleGroupArchive(&rc, "folder-uid", "does not exist")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are renamed, POST moves the rules in place", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			insertRule(t, sut, rule)
+
+			move := definitions.RuleGroupMove{FolderUID: "folder-uid", Title: "renamed-group"}
+			response := sut.RoutePostAlertRuleGroupMove(&rc, move, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			response = sut.RouteRouteGetAlertRule(&rc, rule.UID)
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, "renamed-group", deserializeRule(t, response.Body()).RuleGroup)
+
+			response = sut.RoutePostAlertRuleGroupMove(&rc, move, "folder-uid", "my-cool-group")
+			require.Equal(t, 404, response.Status())
+
+			response = sut.RoutePostAlertRuleGroupMove(&rc, definitions.RuleGroupMove{FolderUID: "folder-uid"}, "folder-uid", "renamed-group")
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are imported from Prometheus, POST replaces the group with the converted rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			body := definitions.PrometheusRuleGroupImport{
+				DatasourceUID: "prometheus-uid",
+				Group: `
+name: imported
+rules:
+  - record: job:up:sum
+    expr: sum by (job) (up)
+  - alert: Instance down
+    expr: up == 0
+`,
+			}
+
+			response := sut.RoutePostPrometheusRuleGroupImport(&rc, body, "folder-uid")
+			require.Equal(t, 204, response.Status())
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "imported")
+			require.Equal(t, 200, response.Status())
+			var group definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.Len(t, group.Rules, 2)
+			require.NotNil(t, group.Rules[0].Record)
+			require.Equal(t, "job:up:sum", group.Rules[0].Record.Metric)
+
+			body.Group = "rules: ["
+			response = sut.RoutePostPrometheusRuleGroupImport(&rc, body, "folder-uid")
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are instantiated from a template, POST creates the rule once", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("${team} rule", 1)
+			rule.UID = ""
+			rule.RuleGroup = "${env}"
+			tmpl := definitions.AlertRuleTemplate{
+				Title: "rule per team",
+				Parameters: []definitions.AlertRuleTemplateParameter{
+					{Name: "team"},
+					{Name: "env", Default: util.Pointer("prod")},
+				},
+				Rule: rule,
+			}
+
+			response := sut.RoutePutAlertRuleTemplate(&rc, tmpl, "per-team")
+			require.Equal(t, 200, response.Status())
+
+			var created, updated definitions.ProvisionedAlertRule
+			response = sut.RoutePostAlertRuleTemplateInstantiate(&rc, definitions.AlertRuleTemplateInstantiation{Parameters: map[string]string{"team": "checkout"}}, "per-team")
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &created))
+			require.Equal(t, "checkout rule", created.Title)
+			require.Equal(t, "prod", created.RuleGroup)
+
+			response = sut.RoutePostAlertRuleTemplateInstantiate(&rc, definitions.AlertRuleTemplateInstantiation{Parameters: map[string]string{"team": "checkout"}}, "per-team")
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &updated))
+			require.Equal(t, created.UID, updated.UID)
+
+			response = sut.RoutePostAlertRuleTemplateInstantiate(&rc, definitions.AlertRuleTemplateInstantiation{}, "per-team")
+			require.Equal(t, 400, response.Status())
 
-				require.Equal(t, 200, response.Status())
-				created := deserializeRule(t, response.Body())
-				require.Equal(t, int64(3), created.OrgID)
-				require.Equal(t, definitions.Provenance(models.ProvenanceNone), created.Provenance)
-			})
+			response = sut.RoutePostAlertRuleTemplateInstantiate(&rc, definitions.AlertRuleTemplateInstantiation{}, "does-not-exist")
+			require.Equal(t, 404, response.Status())
+
+			tmpl.Rule.Title = "${service} rule"
+			response = sut.RoutePutAlertRuleTemplate(&rc, tmpl, "per-team")
+			require.Equal(t, 400, response.Status(), "placeholders should be declared parameters")
 		})
 
-		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+		t.Run("are replaced, PUT returns the values set by the server", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
 			rule := createTestAlertRule("rule", 1)
+			rule.UID = ""
+			rule.RuleGroup = ""
+			group := definitions.AlertRuleGroup{
+				Interval: 60,
+				Rules:    []definitions.ProvisionedAlertRule{rule},
+			}
 
-			response := sut.RoutePutAlertRule(&rc, rule, "does not exist")
+			response := sut.RoutePutAlertRuleGroup(&rc, group, "folder-uid", "my-cool-group")
 
-			require.Equal(t, 404, response.Status())
+			require.Equal(t, 200, response.Status())
+			var result definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.Len(t, result.ServerDefaults, 2)
+			require.Equal(t, definitions.ServerDefault{RuleIndex: 0, Field: "ruleGroup", Value: "my-cool-group"}, result.ServerDefaults[0])
+			require.Equal(t, 0, result.ServerDefaults[1].RuleIndex)
+			require.Equal(t, "uid", result.ServerDefaults[1].Field)
+			uid, ok := result.ServerDefaults[1].Value.(string)
+			require.True(t, ok)
+			require.NotEmpty(t, uid)
+
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			var stored definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &stored))
+			require.Equal(t, uid, stored.Rules[0].UID)
 		})
 
-		t.Run("are missing, GET returns 404", func(t *testing.T) {
+		t.Run("contain rules of other groups, PUT moves them unless it is rejected", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
-			response := sut.RouteRouteGetAlertRule(&rc, "does not exist")
+			rule := createTestAlertRule("rule", 1)
+			insertRule(t, sut, rule)
+			group := definitions.AlertRuleGroup{
+				Interval: 60,
+				Rules:    []definitions.ProvisionedAlertRule{rule},
+			}
 
-			require.Equal(t, 404, response.Status())
+			rc.Context.Req.Form.Set("ruleMoves", "reject")
+			response := sut.RoutePutAlertRuleGroup(&rc, group, "folder-uid", "other-group")
+
+			require.Equal(t, 409, response.Status())
+			var conflict definitions.ProvisioningError
+			require.NoError(t, json.Unmarshal(response.Body(), &conflict))
+			require.Equal(t, "alerting.alert-rule.moveConflict", conflict.MessageID)
+			require.Equal(t, map[string]any{
+				"RuleUID":            rule.UID,
+				"NamespaceUID":       "folder-uid",
+				"RuleGroup":          "my-cool-group",
+				"TargetNamespaceUID": "folder-uid",
+				"TargetRuleGroup":    "other-group",
+			}, conflict.Extra)
+
+			rc.Context.Req.Form.Del("ruleMoves")
+			response = sut.RoutePutAlertRuleGroup(&rc, group, "folder-uid", "other-group")
+			require.Equal(t, 200, response.Status())
+			response = sut.RouteRouteGetAlertRule(&rc, rule.UID)
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, "other-group", deserializeRule(t, response.Body()).RuleGroup)
+
+			rc.Context.Req.Form.Set("ruleMoves", "copy")
+			response = sut.RoutePutAlertRuleGroup(&rc, group, "folder-uid", "other-group")
+			require.Equal(t, 400, response.Status())
 		})
 
-		t.Run("have reached the rule quota, POST returns 403", func(t *testing.T) {
-			env := createTestEnv(t, testConfig)
-			quotas := provisioning.MockQuotaChecker{}
-			quotas.EXPECT().LimitExceeded()
-			env.quotas = &quotas
-			sut := createProvisioningSrvSutFromEnv(t, &env)
+		t.Run("are estimated in cost, POST returns the estimate without saving the group", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
 			rule := createTestAlertRule("rule", 1)
+			rule.Data[0].DatasourceUID = "loki-uid"
+			group := definitions.AlertRuleGroup{
+				Interval: 60,
+				Rules:    []definitions.ProvisionedAlertRule{rule},
+			}
+
+			response := sut.RoutePostAlertRuleGroupCostEstimate(&rc, group, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			var result definitions.RuleGroupCostEstimate
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.Equal(t, int64(1440), result.EvaluationsPerDay)
+			require.Equal(t, int64(1440), result.QueriesPerDay)
+			require.Len(t, result.Rules, 1)
+			require.Len(t, result.Rules[0].Queries, 1)
+			require.Equal(t, "loki", result.Rules[0].Queries[0].DatasourceType)
+			require.Equal(t, int64(60), result.Rules[0].Queries[0].DataPoints)
+			require.InDelta(t, 3.18, result.CostPerEvaluation, 0.001)
+
+			response = sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 404, response.Status())
+
+			group.Interval = 0
+			response = sut.RoutePostAlertRuleGroupCostEstimate(&rc, group, "folder-uid", "my-cool-group")
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are cloned, POST returns the new group", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+			clone := definitions.AlertRuleGroupClone{
+				FolderUID: "other-folder-uid",
+				Title:     "cloned-group",
+				Labels:    map[string]string{"team": "alerting"},
+			}
 
-			response := sut.RoutePostAlertRule(&rc, rule)
+			response := sut.RoutePostAlertRuleGroupClone(&rc, clone, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 201, response.Status())
+			var group definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.Equal(t, "cloned-group", group.Title)
+			require.Equal(t, "other-folder-uid", group.FolderUID)
+			require.Len(t, group.Rules, 1)
+			require.NotEqual(t, "rule", group.Rules[0].UID)
+			require.Equal(t, map[string]string{"team": "alerting"}, group.Rules[0].Labels)
 
+			response = sut.RoutePostAlertRuleGroupClone(&rc, clone, "folder-uid", "my-cool-group")
+			require.Equal(t, 409, response.Status())
+
+			response = sut.RoutePostAlertRuleGroupClone(&rc, clone, "folder-uid", "does not exist")
+			require.Equal(t, 404, response.Status())
+
+			clone.OrgID = 2
+			response = sut.RoutePostAlertRuleGroupClone(&rc, clone, "folder-uid", "my-cool-group")
 			require.Equal(t, 403, response.Status())
+
+			rc.SignedInUser.IsGrafanaAdmin = true
+			response = sut.RoutePostAlertRuleGroupClone(&rc, clone, "folder-uid", "my-cool-group")
+			require.Equal(t, 201, response.Status())
+			rc.OrgID = 2
+			response = sut.RouteGetAlertRuleGroup(&rc, "other-folder-uid", "cloned-group")
+			require.Equal(t, 200, response.Status())
 		})
-	})
 
-	t.Run("alert rule groups", func(t *testing.T) {
-		t.Run("are present", func(t *testing.T) {
+		t.Run("are generated from an inventory, POST returns the generated group", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
-			insertRule(t, sut, createTestAlertRule("rule", 1))
+			tmpl := createTestAlertRule("${service} is down", 1)
+			tmpl.UID = ""
+			tmpl.Labels = map[string]string{"threshold": "${threshold}"}
+			generation := definitions.AlertRuleGroupGeneration{
+				Interval:  60,
+				Template:  tmpl,
+				Inventory: []map[string]any{{"service": "checkout", "threshold": 0.5}, {"service": "search", "threshold": 2}},
+			}
 
-			t.Run("GET returns 200", func(t *testing.T) {
-				response := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			response := sut.RoutePostAlertRuleGroupGenerate(&rc, generation, "folder-uid", "services")
 
-				require.Equal(t, 200, response.Status())
-			})
+			require.Equal(t, 200, response.Status())
+			var group definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.Len(t, group.Rules, 2)
+			require.Equal(t, "checkout is down", group.Rules[0].Title)
+			require.Equal(t, map[string]string{"threshold": "0.5"}, group.Rules[0].Labels)
+
+			generation.Inventory = nil
+			generation.InventoryCSV = "service,threshold\nsearch,3\n"
+			response = sut.RoutePostAlertRuleGroupGenerate(&rc, generation, "folder-uid", "services")
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.Len(t, group.Rules, 1)
+			require.Equal(t, "search is down", group.Rules[0].Title)
 
-			t.Run("DELETE returns 204", func(t *testing.T) {
-				response := sut.RouteDeleteAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+			generation.InventoryCSV = "service\nsearch\n"
+			response = sut.RoutePostAlertRuleGroupGenerate(&rc, generation, "folder-uid", "services")
+			require.Equal(t, 400, response.Status())
+		})
 
-				require.Equal(t, 204, response.Status())
+		t.Run("have their labels and annotations changed in bulk, POST returns the number of changed rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule1", 1))
+			insertRule(t, sut, createTestAlertRuleWithFolderAndGroup("rule2", 1, "other-folder-uid", "my-cool-group"))
+
+			response := sut.RoutePostAlertRulesMetadata(&rc, definitions.AlertRulesMetadataPatch{
+				FolderUIDs:     []string{"folder-uid"},
+				AddLabels:      map[string]string{"team": "platform"},
+				AddAnnotations: map[string]string{"runbook": "https://runbooks"},
 			})
+			require.Equal(t, 200, response.Status())
+			require.JSONEq(t, `{"updated": 1}`, string(response.Body()))
+
+			response = sut.RouteRouteGetAlertRule(&rc, "rule1")
+			require.Equal(t, 200, response.Status())
+			rule := deserializeRule(t, response.Body())
+			require.Equal(t, map[string]string{"team": "platform"}, rule.Labels)
+			require.Equal(t, "https://runbooks", rule.Annotations["runbook"])
+			response = sut.RouteRouteGetAlertRule(&rc, "rule2")
+			require.Equal(t, 200, response.Status())
+			require.Empty(t, deserializeRule(t, response.Body()).Labels)
+
+			response = sut.RoutePostAlertRulesMetadata(&rc, definitions.AlertRulesMetadataPatch{FolderUIDs: []string{"folder-uid"}})
+			require.Equal(t, 400, response.Status())
 		})
 
 		t.Run("are missing", func(t *testing.T) {
@@ -433,6 +1769,193 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, 404, response.Status())
 			})
 
+			t.Run("ETag matches If-None-Match, GET returns 304", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				response.WriteTo(&rc)
+				require.Equal(t, 200, response.Status())
+				etag := rc.Context.Resp.Header().Get("ETag")
+				require.NotEmpty(t, etag)
+
+				rc = createTestRequestCtx()
+				rc.Context.Req.Header.Set("If-None-Match", etag)
+				response = sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				require.Equal(t, 304, response.Status())
+				require.Empty(t, response.Body())
+
+				t.Run("unless the group changed", func(t *testing.T) {
+					insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+					rc := createTestRequestCtx()
+					rc.Context.Req.Header.Set("If-None-Match", etag)
+					response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+					require.Equal(t, 200, response.Status())
+				})
+			})
+
+			t.Run("folder key is uid, GET returns groups keyed by folder UID", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("format", "json")
+				rc.Context.Req.Form.Set("folderKey", "uid")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+				var export definitions.AlertingFileExport
+				require.NoError(t, json.Unmarshal(response.Body(), &export))
+				require.Len(t, export.Groups, 1)
+				require.Empty(t, export.Groups[0].Folder)
+				require.Equal(t, "folder-uid", export.Groups[0].FolderUIDKey)
+				require.Equal(t, "Folder Title", export.Groups[0].FolderTitle)
+			})
+
+			t.Run("folder key is path, GET returns groups keyed by folder path", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("format", "json")
+				rc.Context.Req.Form.Set("folderKey", "path")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+				var export definitions.AlertingFileExport
+				require.NoError(t, json.Unmarshal(response.Body(), &export))
+				require.Len(t, export.Groups, 1)
+				require.Equal(t, int64(1), export.Groups[0].OrgID)
+				require.Empty(t, export.Groups[0].Folder)
+				require.Equal(t, []string{"Folder Title"}, export.Groups[0].FolderPath)
+			})
+
+			t.Run("folder key is invalid, GET returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("folderKey", "id")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 400, response.Status())
+			})
+
+			t.Run("rule group index is requested, GET returns the positions of the rules", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule1", 1))
+				insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+				rc.Context.Req.Form.Set("format", "json")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				require.Equal(t, 200, response.Status())
+				require.NotContains(t, string(response.Body()), "ruleGroupIndex")
+
+				rc.Context.Req.Form.Set("ruleGroupIndex", "true")
+				response = sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+				var export definitions.AlertingFileExport
+				require.NoError(t, json.Unmarshal(response.Body(), &export))
+				require.Len(t, export.Groups, 1)
+				require.Len(t, export.Groups[0].Rules, 2)
+				require.Equal(t, "rule1", export.Groups[0].Rules[0].UID)
+				require.Equal(t, 1, export.Groups[0].Rules[0].RuleGroupIndex)
+				require.Equal(t, "rule2", export.Groups[0].Rules[1].UID)
+				require.Equal(t, 2, export.Groups[0].Rules[1].RuleGroupIndex)
+			})
+
+			t.Run("group version is requested, GET returns the versions of the groups", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule1", 1))
+				insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+				rc.Context.Req.Form.Set("format", "json")
+				rc.Context.Req.Form.Set("groupVersion", "true")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+				var export definitions.AlertingFileExport
+				require.NoError(t, json.Unmarshal(response.Body(), &export))
+				require.Len(t, export.Groups, 1)
+				group := sut.RouteGetAlertRuleGroup(&rc, "folder-uid", "my-cool-group")
+				require.Equal(t, 200, group.Status())
+				var read definitions.AlertRuleGroup
+				require.NoError(t, json.Unmarshal(group.Body(), &read))
+				require.NotEmpty(t, export.Groups[0].Version)
+				require.Equal(t, read.Version, export.Groups[0].Version)
+			})
+
+			t.Run("metadata is requested, GET returns yaml with metadata comments", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.exportSource = "https://grafana.example.com/"
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("metadata", "true")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+				require.Regexp(t, `^# grafana-export-generated-at: \S+\n`, string(response.Body()))
+				metadata, err := provisioning.ParseExportMetadata(response.Body())
+				require.NoError(t, err)
+				require.Equal(t, "https://grafana.example.com/", metadata.Source)
+				require.Equal(t, []string{"rule"}, metadata.RuleUIDs)
+			})
+
+			t.Run("accept encoding contains gzip, GET returns compressed body", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				recorder := httptest.NewRecorder()
+				rc.Context.Resp = web.NewResponseWriter("GET", recorder)
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				expected := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				require.Equal(t, 200, expected.Status())
+
+				rc.Context.Req.Header.Set("Accept-Encoding", "gzip")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				response.WriteTo(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+				require.Equal(t, "text/yaml", recorder.Header().Get("Content-Type"))
+				gz, err := gzip.NewReader(recorder.Body)
+				require.NoError(t, err)
+				body, err := io.ReadAll(gz)
+				require.NoError(t, err)
+				require.Equal(t, string(expected.Body()), string(body))
+			})
+
+			t.Run("accept encoding contains gzip, GET returns compressed json body", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				recorder := httptest.NewRecorder()
+				rc.Context.Resp = web.NewResponseWriter("GET", recorder)
+				rc.Context.Req.Form.Set("format", "json")
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+				insertRule(t, sut, createTestAlertRule("rule2", 1))
+
+				expected := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				require.Equal(t, 200, expected.Status())
+
+				rc.Context.Req.Header.Set("Accept-Encoding", "gzip")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+				response.WriteTo(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+				gz, err := gzip.NewReader(recorder.Body)
+				require.NoError(t, err)
+				body, err := io.ReadAll(gz)
+				require.NoError(t, err)
+				require.Equal(t, string(expected.Body()), string(body))
+			})
+
 			t.Run("accept header contains yaml, GET returns text yaml", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
 				rc := createTestRequestCtx()
@@ -546,7 +2069,7 @@ func TestProvisioningApi(t *testing.T) {
 				insertRule(t, sut, rule1)
 				insertRule(t, sut, createTestAlertRule("rule2", 1))
 
-				expectedResponse := "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: my-cool-group\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule1\n          title: rule1\n          condition: A\n          data:\n            - refId: A\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n        - uid: rule2\n          title: rule2\n          condition: A\n          data:\n            - refId: A\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
+				expectedResponse := "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: my-cool-group\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule1\n          title: rule1\n          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n        - uid: rule2\n          title: rule2\n          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
 
 				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
 				require.Equal(t, 200, response.Status())
@@ -562,7 +2085,7 @@ func TestProvisioningApi(t *testing.T) {
 				insertRule(t, sut, createTestAlertRule("rule2", 1))
 
 				rc.Context.Req.Header.Add("Accept", "application/json")
-				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"my-cool-group","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false},{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
+				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"my-cool-group","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false},{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
 
 				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
 				require.Equal(t, 200, response.Status())
@@ -578,9 +2101,9 @@ func TestProvisioningApi(t *testing.T) {
 				rc.Context.Req.Header.Add("Accept", "application/yaml")
 				expectedResponse := "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: my-cool-group\n      folder" +
 					": Folder Title\n      interval: 1m\n      rules:\n        - uid: rule1\n          title: rule1\n" +
-					"          condition: A\n          data:\n            - refId: A\n              datasourceUid" +
+					"          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid" +
 					": \"\"\n              model:\n                conditions:\n                    - evaluator:\n" +
-					"                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n        - uid: rule2\n          title: rule2\n          condition: A\n          data:\n            - refId: A\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
+					"                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n        - uid: rule2\n          title: rule2\n          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
 
 				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
 
@@ -603,7 +2126,7 @@ func TestProvisioningApi(t *testing.T) {
 				insertRule(t, sut, rule1)
 				insertRule(t, sut, createTestAlertRule("rule2", 1))
 
-				expectedResponse := `resource "grafana_rule_group" "rule_group_0000" {
+				expectedResponse := `resource "grafana_rule_group" "rule_group_folder-uid_my-cool-group" {
   org_id           = 1
   name             = "my-cool-group"
   folder_uid       = "folder-uid"
@@ -617,7 +2140,7 @@ func TestProvisioningApi(t *testing.T) {
       ref_id = "A"
 
       relative_time_range {
-        from = 0
+        from = 60
         to   = 0
       }
 
@@ -644,7 +2167,7 @@ func TestProvisioningApi(t *testing.T) {
       ref_id = "A"
 
       relative_time_range {
-        from = 0
+        from = 60
         to   = 0
       }
 
@@ -798,7 +2321,7 @@ func TestProvisioningApi(t *testing.T) {
 				rc := createTestRequestCtx()
 				insertRule(t, sut, createTestAlertRule("rule1", 1))
 
-				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"my-cool-group","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
+				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"my-cool-group","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
 
 				rc.Context.Req.Header.Add("Accept", "application/json")
 				response := sut.RouteGetAlertRuleExport(&rc, "rule1")
@@ -813,7 +2336,7 @@ func TestProvisioningApi(t *testing.T) {
 				insertRule(t, sut, createTestAlertRule("rule1", 1))
 
 				rc.Context.Req.Header.Add("Accept", "application/yaml")
-				expectedResponse := "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: my-cool-group\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule1\n          title: rule1\n          condition: A\n          data:\n            - refId: A\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
+				expectedResponse := "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: my-cool-group\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule1\n          title: rule1\n          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
 
 				response := sut.RouteGetAlertRuleExport(&rc, "rule1")
 
@@ -923,7 +2446,7 @@ func TestProvisioningApi(t *testing.T) {
 				insertRule(t, sut, rule3)
 
 				rc.Context.Req.Header.Add("Accept", "application/json")
-				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Email"}}]},{"orgId":1,"name":"groupb","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title2","interval":"1m","rules":[{"uid":"rule3","title":"rule3","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
+				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Email"}}]},{"orgId":1,"name":"groupb","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title2","interval":"1m","rules":[{"uid":"rule3","title":"rule3","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
 
 				response := sut.RouteGetAlertRulesExport(&rc)
 				require.Equal(t, 200, response.Status())
@@ -943,7 +2466,7 @@ func TestProvisioningApi(t *testing.T) {
 				insertRule(t, sut, rule3)
 
 				rc.Context.Req.Header.Add("Accept", "application/yaml")
-				expectedResponse := "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: groupa\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule1\n          title: rule1\n          condition: A\n          data:\n            - refId: A\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Email\n    - orgId: 1\n      name: groupb\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule2\n          title: rule2\n          condition: A\n          data:\n            - refId: A\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n    - orgId: 1\n      name: groupb\n      folder: Folder Title2\n      interval: 1m\n      rules:\n        - uid: rule3\n          title: rule3\n          condition: A\n          data:\n            - refId: A\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
+				expectedResponse := "apiVersion: 1\ngroups:\n    - orgId: 1\n      name: groupa\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule1\n          title: rule1\n          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Email\n    - orgId: 1\n      name: groupb\n      folder: Folder Title\n      interval: 1m\n      rules:\n        - uid: rule2\n          title: rule2\n          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n    - orgId: 1\n      name: groupb\n      folder: Folder Title2\n      interval: 1m\n      rules:\n        - uid: rule3\n          title: rule3\n          condition: A\n          data:\n            - refId: A\n              relativeTimeRange:\n                from: 60\n                to: 0\n              datasourceUid: \"\"\n              model:\n                conditions:\n                    - evaluator:\n                        params:\n                            - 3\n                        type: gt\n                      operator:\n                        type: and\n                      query:\n                        params:\n                            - A\n                      reducer:\n                        type: last\n                      type: query\n                datasource:\n                    type: __expr__\n                    uid: __expr__\n                expression: 1==0\n                intervalMs: 1000\n                maxDataPoints: 43200\n                refId: A\n                type: math\n          noDataState: OK\n          execErrState: OK\n          for: 0s\n          isPaused: false\n          notification_settings:\n            receiver: Test-Receiver\n            group_by:\n                - alertname\n                - grafana_folder\n                - test\n            group_wait: 1s\n            group_interval: 5s\n            repeat_interval: 5m\n            mute_time_intervals:\n                - test-mute\n"
 
 				response := sut.RouteGetAlertRulesExport(&rc)
 				require.Equal(t, 200, response.Status())
@@ -959,7 +2482,7 @@ func TestProvisioningApi(t *testing.T) {
 
 				rc.Context.Req.Header.Add("Accept", "application/json")
 				rc.Context.Req.Form.Set("folderUid", "folder-uid")
-				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
+				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
 
 				response := sut.RouteGetAlertRulesExport(&rc)
 				require.Equal(t, 200, response.Status())
@@ -976,7 +2499,7 @@ func TestProvisioningApi(t *testing.T) {
 				rc.Context.Req.Header.Add("Accept", "application/json")
 				rc.Context.Req.Form.Set("folder_uid", "folder-uid")
 				rc.Context.Req.Form.Add("folder_uid", "folder-uid2")
-				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title2","interval":"1m","rules":[{"uid":"rule3","title":"rule3","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
+				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule2","title":"rule2","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]},{"orgId":1,"name":"groupb","folder":"Folder Title2","interval":"1m","rules":[{"uid":"rule3","title":"rule3","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
 
 				response := sut.RouteGetAlertRulesExport(&rc)
 				require.Equal(t, 200, response.Status())
@@ -994,7 +2517,7 @@ func TestProvisioningApi(t *testing.T) {
 				rc.Context.Req.Form.Set("folderUid", "folder-uid")
 				rc.Context.Req.Form.Set("group", "groupa")
 
-				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
+				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
 
 				response := sut.RouteGetAlertRulesExport(&rc)
 				require.Equal(t, 200, response.Status())
@@ -1032,7 +2555,7 @@ func TestProvisioningApi(t *testing.T) {
 				rc.Context.Req.Header.Add("Accept", "application/json")
 				rc.Context.Req.Form.Set("ruleUid", "rule1")
 
-				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":0,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
+				expectedResponse := `{"apiVersion":1,"groups":[{"orgId":1,"name":"groupa","folder":"Folder Title","interval":"1m","rules":[{"uid":"rule1","title":"rule1","condition":"A","data":[{"refId":"A","relativeTimeRange":{"from":60,"to":0},"datasourceUid":"","model":{"conditions":[{"evaluator":{"params":[3],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"},"type":"query"}],"datasource":{"type":"__expr__","uid":"__expr__"},"expression":"1==0","intervalMs":1000,"maxDataPoints":43200,"refId":"A","type":"math"}}],"noDataState":"OK","execErrState":"OK","for":"0s","isPaused":false,"notification_settings":{"receiver":"Test-Receiver","group_by":["alertname","grafana_folder","test"],"group_wait":"1s","group_interval":"5s","repeat_interval":"5m","mute_time_intervals":["test-mute"]}}]}]}`
 
 				response := sut.RouteGetAlertRulesExport(&rc)
 
@@ -1060,6 +2583,26 @@ func TestProvisioningApi(t *testing.T) {
 					require.Equal(t, 400, response.Status())
 				})
 			})
+
+			t.Run("query param consistent=true, GET reads the rules in a snapshot", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				xact := &fakeSnapshotTransactionManager{}
+				sut.xact = xact
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("consistent", "true")
+				response := sut.RouteGetAlertRulesExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, 1, xact.snapshots)
+
+				rc = createTestRequestCtx()
+				response = sut.RouteGetAlertRulesExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, 1, xact.snapshots)
+			})
 		})
 
 		t.Run("notification policies", func(t *testing.T) {
@@ -1656,13 +3199,40 @@ func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) Provisi
 	t.Helper()
 
 	receiverSvc := notifier.NewReceiverService(env.ac, env.configs, env.prov, env.secrets, env.xact, env.log)
+	alertRules, err := provisioning.NewAlertRuleService(provisioning.AlertRuleServiceConfig{
+		RuleStore:            env.store,
+		ProvenanceStore:      env.prov,
+		DashboardService:     env.dashboardService,
+		Quotas:               env.quotas,
+		Xact:                 env.xact,
+		NotificationSettings: &provisioning.NotificationSettingsValidatorProviderFake{},
+		Log:                  env.log,
+		KV:                   kvstore.NewFakeKVStore(),
+		TrashStore:           env.store,
+		Settings: setting.UnifiedAlertingSettings{
+			DefaultRuleEvaluationInterval: time.Minute,
+			BaseInterval:                  10 * time.Second,
+			RulesPerRuleGroupLimit:        100,
+			QuotaExemptRulesLimit:         -1,
+			AlertRuleTrashRetention:       time.Hour,
+		},
+	})
+	require.NoError(t, err)
 	return ProvisioningSrv{
 		log:                 env.log,
 		policies:            newFakeNotificationPolicyService(),
-		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, env.prov, env.xact, receiverSvc, env.log, env.store),
+		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, env.prov, env.xact, receiverSvc, env.log, env.store, env.store, setting.UnifiedAlertingSecretReferencesSettings{}),
 		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.log),
 		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.xact, env.log),
-		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.dashboardService, env.quotas, env.xact, 60, 10, 100, env.log, &provisioning.NotificationSettingsValidatorProviderFake{}),
+		alertmanagerRouting: provisioning.NewAlertmanagerRoutingService(store.NewFakeAdminConfigStore(t), env.prov, env.xact, env.log),
+		configSnapshots:     &fakeConfigSnapshotService{},
+		tags:                provisioning.NewTagService(env.store, env.store, env.prov, env.xact, nil, env.log),
+		ruleUsage:           provisioning.NewRuleUsageService(env.store, env.prov, historian.NewNopHistorian(), env.log),
+		xact:                env.xact,
+		alertRules:          alertRules,
+		datasources: &datasource_fakes.FakeCacheService{DataSources: []*datasources.DataSource{
+			{UID: "loki-uid", Type: "loki"},
+		}},
 	}
 }
 
@@ -1738,6 +3308,16 @@ func (f *fakeNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID
 	return result, nil
 }
 
+func (f *fakeNotificationPolicyService) GetEffectivePolicy(ctx context.Context, orgID int64, routePath []int) (definitions.EffectivePolicy, error) {
+	if orgID != 1 {
+		return definitions.EffectivePolicy{}, store.ErrNoAlertmanagerConfiguration
+	}
+	if len(routePath) > 0 && routePath[0] >= len(f.tree.Routes) {
+		return definitions.EffectivePolicy{}, fmt.Errorf("%w: notification policy at path %v does not exist", provisioning.ErrNotFound, routePath)
+	}
+	return definitions.EffectivePolicy{Path: routePath, Receiver: f.tree.Receiver}, nil
+}
+
 func (f *fakeNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
 	if orgID != 1 {
 		return store.ErrNoAlertmanagerConfiguration
@@ -1747,6 +3327,31 @@ func (f *fakeNotificationPolicyService) UpdatePolicyTree(ctx context.Context, or
 	return nil
 }
 
+func (f *fakeNotificationPolicyService) MergePolicyTree(ctx context.Context, orgID int64, anchorMatchers definitions.ObjectMatchers, policies []*definitions.Route, overwrite bool, p models.Provenance) (definitions.Route, error) {
+	if orgID != 1 {
+		return definitions.Route{}, store.ErrNoAlertmanagerConfiguration
+	}
+	matchersKey := func(matchers definitions.ObjectMatchers) string {
+		result := make([]string, 0, len(matchers))
+		for _, m := range matchers {
+			result = append(result, m.String())
+		}
+		return strings.Join(result, ", ")
+	}
+	anchor := &f.tree
+	if len(anchorMatchers) > 0 {
+		idx := slices.IndexFunc(f.tree.Routes, func(r *definitions.Route) bool {
+			return matchersKey(r.ObjectMatchers) == matchersKey(anchorMatchers)
+		})
+		if idx < 0 {
+			return definitions.Route{}, fmt.Errorf("%w: no notification policy has the matchers %s", provisioning.ErrNotFound, matchersKey(anchorMatchers))
+		}
+		anchor = f.tree.Routes[idx]
+	}
+	anchor.Routes = append(anchor.Routes, policies...)
+	return f.GetPolicyTree(ctx, orgID)
+}
+
 func (f *fakeNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	f.tree = definitions.Route{} // TODO
 	return f.tree, nil
@@ -1758,10 +3363,18 @@ func (f *fakeFailingNotificationPolicyService) GetPolicyTree(ctx context.Context
 	return definitions.Route{}, fmt.Errorf("something went wrong")
 }
 
+func (f *fakeFailingNotificationPolicyService) GetEffectivePolicy(ctx context.Context, orgID int64, routePath []int) (definitions.EffectivePolicy, error) {
+	return definitions.EffectivePolicy{}, fmt.Errorf("something went wrong")
+}
+
 func (f *fakeFailingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
 	return fmt.Errorf("something went wrong")
 }
 
+func (f *fakeFailingNotificationPolicyService) MergePolicyTree(ctx context.Context, orgID int64, anchorMatchers definitions.ObjectMatchers, policies []*definitions.Route, overwrite bool, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("something went wrong")
+}
+
 func (f *fakeFailingNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	return definitions.Route{}, fmt.Errorf("something went wrong")
 }
@@ -1772,10 +3385,18 @@ func (f *fakeRejectingNotificationPolicyService) GetPolicyTree(ctx context.Conte
 	return definitions.Route{}, nil
 }
 
+func (f *fakeRejectingNotificationPolicyService) GetEffectivePolicy(ctx context.Context, orgID int64, routePath []int) (definitions.EffectivePolicy, error) {
+	return definitions.EffectivePolicy{}, nil
+}
+
 func (f *fakeRejectingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
 	return fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
 }
 
+func (f *fakeRejectingNotificationPolicyService) MergePolicyTree(ctx context.Context, orgID int64, anchorMatchers definitions.ObjectMatchers, policies []*definitions.Route, overwrite bool, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
+}
+
 func (f *fakeRejectingNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	return definitions.Route{}, nil
 }
@@ -1839,22 +3460,22 @@ func createTestAlertRule(title string, orgID int64) definitions.ProvisionedAlert
 				RefID: "A",
 				Model: json.RawMessage(testModel),
 				RelativeTimeRange: definitions.RelativeTimeRange{
-					From: definitions.Duration(60),
+					From: definitions.Duration(60 * time.Second),
 					To:   definitions.Duration(0),
 				},
 			},
 		},
 		RuleGroup:    "my-cool-group",
 		FolderUID:    "folder-uid",
-		For:          model.Duration(60),
+		For:          definitions.DurationOrSeconds(60),
 		NoDataState:  definitions.OK,
 		ExecErrState: definitions.OkErrState,
 		NotificationSettings: &definitions.AlertRuleNotificationSettings{
 			Receiver:          "Test-Receiver",
 			GroupBy:           []string{"alertname", "grafana_folder", "test"},
-			GroupWait:         util.Pointer(model.Duration(1 * time.Second)),
-			GroupInterval:     util.Pointer(model.Duration(5 * time.Second)),
-			RepeatInterval:    util.Pointer(model.Duration(5 * time.Minute)),
+			GroupWait:         util.Pointer(definitions.DurationOrSeconds(1 * time.Second)),
+			GroupInterval:     util.Pointer(definitions.DurationOrSeconds(5 * time.Second)),
+			RepeatInterval:    util.Pointer(definitions.DurationOrSeconds(5 * time.Minute)),
 			MuteTimeIntervals: []string{"test-mute"},
 		},
 	}
@@ -2073,3 +3694,118 @@ var testContactPointConfig = `
 	}
 }
 `
+
+type fakeRuleStateReader map[string][]*state.State
+
+func (f fakeRuleStateReader) GetStatesForRuleUID(_ int64, alertRuleUID string) []*state.State {
+	return f[alertRuleUID]
+}
+
+type fakeDeliveryAttemptReader map[string][]notifier.DeliveryAttempt
+
+func (f fakeDeliveryAttemptReader) GetDeliveryAttempts(_ int64, integrationUID string) ([]notifier.DeliveryAttempt, error) {
+	return f[integrationUID], nil
+}
+
+type fakeSnapshotTransactionManager struct {
+	provisioning.NopTransactionManager
+	snapshots int
+}
+
+func (f *fakeSnapshotTransactionManager) InSnapshot(ctx context.Context, work func(ctx context.Context) error) error {
+	f.snapshots++
+	return f.NopTransactionManager.InSnapshot(ctx, work)
+}
+
+type fakeRuleGroupJobService struct {
+	started models.AlertRuleGroup
+	err     error
+}
+
+func (f *fakeRuleGroupJobService) StartReplaceRuleGroup(_ context.Context, orgID int64, group models.AlertRuleGroup, _ identity.Requester, _ models.Provenance) (models.RuleGroupJob, error) {
+	if f.err != nil {
+		return models.RuleGroupJob{}, f.err
+	}
+	f.started = group
+	return models.RuleGroupJob{UID: "job-uid", OrgID: orgID, NamespaceUID: group.FolderUID, RuleGroup: group.Title, Status: models.RuleGroupJobStatusPending}, nil
+}
+
+func (f *fakeRuleGroupJobService) GetJob(_ context.Context, orgID int64, uid string) (models.RuleGroupJob, error) {
+	if uid != "job-uid" {
+		return models.RuleGroupJob{}, models.ErrRuleGroupJobNotFound
+	}
+	return models.RuleGroupJob{UID: uid, OrgID: orgID, Status: models.RuleGroupJobStatusRunning}, nil
+}
+
+type fakeProvisioningWebhook struct {
+	replayed []string
+	err      error
+}
+
+func (f *fakeProvisioningWebhook) ListDeadLetters(_ context.Context, orgID int64) ([]models.WebhookDelivery, error) {
+	return []models.WebhookDelivery{{UID: "delivery-uid", OrgID: orgID, Type: "alert_rule_deleted", Event: json.RawMessage(`{"uid":"rule"}`), Attempts: 3}}, nil
+}
+
+func (f *fakeProvisioningWebhook) ReplayDeadLetter(_ context.Context, orgID int64, uid string) (models.WebhookDelivery, error) {
+	if f.err != nil {
+		return models.WebhookDelivery{}, f.err
+	}
+	if uid != "delivery-uid" {
+		return models.WebhookDelivery{}, models.ErrWebhookDeliveryNotFound
+	}
+	f.replayed = append(f.replayed, uid)
+	return models.WebhookDelivery{UID: uid, OrgID: orgID, Type: "alert_rule_deleted", Event: json.RawMessage(`{"uid":"rule"}`)}, nil
+}
+
+type fakeRuleSyncService struct {
+	status provisioning.RuleSyncStatus
+	err    error
+}
+
+func (f *fakeRuleSyncService) GetStatus(context.Context, int64) (provisioning.RuleSyncStatus, error) {
+	return f.status, f.err
+}
+
+type fakeConfigSnapshotService struct {
+	snapshots []*models.ConfigSnapshot
+	restored  models.ConfigSnapshotRestoreMode
+}
+
+func (f *fakeConfigSnapshotService) GetSnapshots(_ context.Context, orgID int64) ([]*models.ConfigSnapshot, error) {
+	var result []*models.ConfigSnapshot
+	for _, snapshot := range f.snapshots {
+		if snapshot.OrgID == orgID {
+			result = append(result, snapshot)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeConfigSnapshotService) CreateSnapshot(_ context.Context, orgID int64) (*models.ConfigSnapshot, error) {
+	snapshot, err := models.NewConfigSnapshot(orgID, models.ConfigSnapshotContent{}, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	snapshot.ID = int64(len(f.snapshots) + 1)
+	f.snapshots = append(f.snapshots, snapshot)
+	return snapshot, nil
+}
+
+func (f *fakeConfigSnapshotService) RestoreAlertingConfig(_ context.Context, orgID int64, snapshotID int64, mode models.ConfigSnapshotRestoreMode, _ identity.Requester, _ models.Provenance) error {
+	if mode != models.ConfigSnapshotRestoreReplace && mode != models.ConfigSnapshotRestoreMerge {
+		return fmt.Errorf("%w: unknown restore mode '%s'", provisioning.ErrValidation, mode)
+	}
+	for _, snapshot := range f.snapshots {
+		if snapshot.OrgID == orgID && snapshot.ID == snapshotID {
+			f.restored = mode
+			return nil
+		}
+	}
+	return models.ErrConfigSnapshotNotFound.Errorf("")
+}
+
+func TestContactPointExportID(t *testing.T) {
+	cp := definitions.ContactPointExport{Name: "email receiver", Receivers: []definitions.ReceiverExport{{UID: "email-uid"}, {UID: "slack-uid"}}}
+	require.Equal(t, "email-uid", contactPointExportID(cp))
+	require.Equal(t, "email receiver", contactPointExportID(definitions.ContactPointExport{Name: "email receiver"}))
+}
@@ -53,6 +53,7 @@ type RulerSrv struct {
 	cfg                *setting.UnifiedAlertingSettings
 	conditionValidator ConditionValidator
 	authz              RuleAccessControlService
+	permissions        UserPermissionsService
 
 	amConfigStore  AMConfigStore
 	amRefresher    AMRefresher
@@ -103,6 +104,9 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 				NamespaceUID: namespace.UID,
 				RuleGroup:    group,
 			}
+			if err := srv.store.LockRuleGroups(ctx, key); err != nil {
+				return err
+			}
 			rules, err := srv.getAuthorizedRuleGroup(ctx, c, key)
 			if err != nil {
 				return err
@@ -110,13 +114,20 @@ func (srv RulerSrv) RouteDeleteAlertRules(c *contextmodel.ReqContext, namespaceU
 			deletionCandidates[key] = rules
 		} else {
 			var totalGroups int
-			deletionCandidates, totalGroups, err = srv.searchAuthorizedAlertRules(ctx, c, []string{namespace.UID}, "", 0)
+			deletionCandidates, totalGroups, err = srv.searchAuthorizedAlertRules(ctx, c, []string{namespace.UID}, "", 0, ngmodels.ArchivedRuleGroupsInclude)
 			if err != nil {
 				return err
 			}
 			if totalGroups > 0 && len(deletionCandidates) == 0 {
 				return accesscontrol.NewAuthorizationErrorGeneric("delete any existing rules in the namespace")
 			}
+			keys := make([]ngmodels.AlertRuleGroupKey, 0, len(deletionCandidates))
+			for key := range deletionCandidates {
+				keys = append(keys, key)
+			}
+			if err := srv.store.LockRuleGroups(ctx, keys...); err != nil {
+				return err
+			}
 		}
 		rulesToDelete := make([]string, 0)
 		provisioned := false
@@ -168,7 +179,11 @@ func (srv RulerSrv) RouteGetNamespaceRulesConfig(c *contextmodel.ReqContext, nam
 		return toNamespaceErrorResponse(err)
 	}
 
-	ruleGroups, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), c, []string{namespace.UID}, "", 0)
+	archivedFilter, err := parseArchivedRuleGroupsFilter(c.Query("archived"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	ruleGroups, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), c, []string{namespace.UID}, "", 0, archivedFilter)
 	if err != nil {
 		return errorToResponse(err)
 	}
@@ -176,11 +191,22 @@ func (srv RulerSrv) RouteGetNamespaceRulesConfig(c *contextmodel.ReqContext, nam
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to get provenance for rule group")
 	}
+	archived, err := srv.archivedRuleGroups(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get archived rule groups")
+	}
+	managedBy, err := srv.ruleGroupsManagedBy(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get who manages the rule groups")
+	}
 
 	result := apimodels.NamespaceConfigResponse{}
 
 	for groupKey, rules := range ruleGroups {
-		result[namespace.Fullpath] = append(result[namespace.Fullpath], toGettableRuleGroupConfig(groupKey.RuleGroup, rules, provenanceRecords))
+		config := toGettableRuleGroupConfig(groupKey.RuleGroup, rules, provenanceRecords)
+		config.Archived = archived[groupKey]
+		config.ManagedBy = managedBy[groupKey]
+		result[namespace.Fullpath] = append(result[namespace.Fullpath], config)
 	}
 
 	return response.JSON(http.StatusAccepted, result)
@@ -207,10 +233,22 @@ func (srv RulerSrv) RouteGetRulesGroupConfig(c *contextmodel.ReqContext, namespa
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to get group alert rules")
 	}
+	archived, err := srv.archivedRuleGroups(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get archived rule groups")
+	}
+	managedBy, err := srv.ruleGroupsManagedBy(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get who manages the rule groups")
+	}
 
+	groupKey := ngmodels.AlertRuleGroupKey{OrgID: c.SignedInUser.GetOrgID(), NamespaceUID: namespace.UID, RuleGroup: ruleGroup}
+	config := toGettableRuleGroupConfig(ruleGroup, rules, provenanceRecords)
+	config.Archived = archived[groupKey]
+	config.ManagedBy = managedBy[groupKey]
 	result := apimodels.RuleGroupConfigResponse{
 		// nolint:staticcheck
-		GettableRuleGroupConfig: toGettableRuleGroupConfig(ruleGroup, rules, provenanceRecords),
+		GettableRuleGroupConfig: config,
 	}
 	return response.JSON(http.StatusAccepted, result)
 }
@@ -241,8 +279,12 @@ func (srv RulerSrv) RouteGetRulesConfig(c *contextmodel.ReqContext) response.Res
 	if dashboardUID == "" && panelID != 0 {
 		return ErrResp(http.StatusBadRequest, errors.New("panel_id must be set with dashboard_uid"), "")
 	}
+	archivedFilter, err := parseArchivedRuleGroupsFilter(c.Query("archived"))
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
 
-	configs, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), c, namespaceUIDs, dashboardUID, panelID)
+	configs, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), c, namespaceUIDs, dashboardUID, panelID, archivedFilter)
 	if err != nil {
 		return errorToResponse(err)
 	}
@@ -250,6 +292,14 @@ func (srv RulerSrv) RouteGetRulesConfig(c *contextmodel.ReqContext) response.Res
 	if err != nil {
 		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
 	}
+	archived, err := srv.archivedRuleGroups(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get archived rule groups")
+	}
+	managedBy, err := srv.ruleGroupsManagedBy(c.Req.Context(), c.SignedInUser.GetOrgID())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get who manages the rule groups")
+	}
 
 	for groupKey, rules := range configs {
 		folder, ok := namespaceMap[groupKey.NamespaceUID]
@@ -258,7 +308,10 @@ func (srv RulerSrv) RouteGetRulesConfig(c *contextmodel.ReqContext) response.Res
 			srv.log.Error("Namespace not visible to the user", "user", id, "userNamespace", userNamespace, "namespace", groupKey.NamespaceUID)
 			continue
 		}
-		result[folder.Fullpath] = append(result[folder.Fullpath], toGettableRuleGroupConfig(groupKey.RuleGroup, rules, provenanceRecords))
+		config := toGettableRuleGroupConfig(groupKey.RuleGroup, rules, provenanceRecords)
+		config.Archived = archived[groupKey]
+		config.ManagedBy = managedBy[groupKey]
+		result[folder.Fullpath] = append(result[folder.Fullpath], config)
 	}
 	return response.JSON(http.StatusOK, result)
 }
@@ -310,10 +363,22 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 		userNamespace, id := c.SignedInUser.GetNamespacedID()
 		logger := srv.log.New("namespace_uid", groupKey.NamespaceUID, "group",
 			groupKey.RuleGroup, "org_id", groupKey.OrgID, "user_id", id, "userNamespace", userNamespace)
+		// The group is locked before the changes are calculated, so that the concurrent changes of the group are not
+		// interleaved with them. The groups that the rules are moved from are locked once they are known.
+		if err := srv.store.LockRuleGroups(tranCtx, groupKey); err != nil {
+			return err
+		}
 		groupChanges, err := store.CalculateChanges(tranCtx, srv.store, groupKey, rules)
 		if err != nil {
 			return err
 		}
+		affected := make([]ngmodels.AlertRuleGroupKey, 0, len(groupChanges.AffectedGroups))
+		for key := range groupChanges.AffectedGroups {
+			affected = append(affected, key)
+		}
+		if err := srv.store.LockRuleGroups(tranCtx, affected...); err != nil {
+			return err
+		}
 
 		if groupChanges.IsEmpty() {
 			finalChanges = groupChanges
@@ -321,6 +386,10 @@ func (srv RulerSrv) updateAlertRulesInGroup(c *contextmodel.ReqContext, groupKey
 			return nil
 		}
 
+		if err := groupChanges.CheckSizeLimit(srv.cfg.RuleGroupChangesLimit); err != nil {
+			return err
+		}
+
 		err = srv.authz.AuthorizeRuleChanges(c.Req.Context(), c.SignedInUser, groupChanges)
 		if err != nil {
 			return err
@@ -458,6 +527,12 @@ func changesToResponse(finalChanges *store.GroupDelta) response.Response {
 		}
 		for _, r := range finalChanges.Update {
 			body.Updated = append(body.Updated, r.Existing.UID)
+			if len(r.ChangedFields) > 0 {
+				if body.ChangedFields == nil {
+					body.ChangedFields = make(map[string][]string)
+				}
+				body.ChangedFields[r.Existing.UID] = r.ChangedFields
+			}
 		}
 		for _, r := range finalChanges.Delete {
 			body.Deleted = append(body.Deleted, r.UID)
@@ -507,6 +582,9 @@ func toGettableExtendedRuleNode(r ngmodels.AlertRule, provenanceRecords map[stri
 			Provenance:           apimodels.Provenance(provenance),
 			IsPaused:             r.IsPaused,
 			NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
+
+			AdditionalNotificationSettings: AdditionalAlertRuleNotificationSettingsFromNotificationSettings(r.NotificationSettings),
+			EffectivePendingPeriod:         model.Duration(r.EffectivePendingPeriod()),
 		},
 	}
 	forDuration := model.Duration(r.For)
@@ -632,12 +710,13 @@ func (srv RulerSrv) getAuthorizedRuleGroup(ctx context.Context, c *contextmodel.
 // searchAuthorizedAlertRules fetches rules according to the filters, groups them by models.AlertRuleGroupKey and filters out groups that the current user is not authorized to access.
 // A user is authorized to access a group of rules only when it has permission to query all data sources used by all rules in this group.
 // Returns groups that user is authorized to access, and total count of groups returned by query
-func (srv RulerSrv) searchAuthorizedAlertRules(ctx context.Context, c *contextmodel.ReqContext, folderUIDs []string, dashboardUID string, panelID int64) (map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, int, error) {
+func (srv RulerSrv) searchAuthorizedAlertRules(ctx context.Context, c *contextmodel.ReqContext, folderUIDs []string, dashboardUID string, panelID int64, archived ngmodels.ArchivedRuleGroupsFilter) (map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, int, error) {
 	query := ngmodels.ListAlertRulesQuery{
-		OrgID:         c.SignedInUser.GetOrgID(),
-		NamespaceUIDs: folderUIDs,
-		DashboardUID:  dashboardUID,
-		PanelID:       panelID,
+		OrgID:          c.SignedInUser.GetOrgID(),
+		NamespaceUIDs:  folderUIDs,
+		DashboardUID:   dashboardUID,
+		PanelID:        panelID,
+		ArchivedGroups: archived,
 	}
 	rules, err := srv.store.ListAlertRules(ctx, &query)
 	if err != nil {
@@ -646,6 +725,8 @@ func (srv RulerSrv) searchAuthorizedAlertRules(ctx context.Context, c *contextmo
 
 	byGroupKey := ngmodels.GroupByAlertRuleGroupKey(rules)
 	totalGroups := len(byGroupKey)
+	// The groups that query the same data sources share the decision of the authorization of the user.
+	ctx = accesscontrol.WithRequestAuthorizationCache(ctx)
 	for groupKey, rulesGroup := range byGroupKey {
 		if ok, err := srv.authz.HasAccessToRuleGroup(ctx, c.SignedInUser, rulesGroup); !ok || err != nil {
 			if err != nil {
@@ -656,3 +737,29 @@ func (srv RulerSrv) searchAuthorizedAlertRules(ctx context.Context, c *contextmo
 	}
 	return byGroupKey, totalGroups, nil
 }
+
+// ruleGroupsManagedBy returns who manages the rule groups of the organization that have it.
+func (srv RulerSrv) ruleGroupsManagedBy(ctx context.Context, orgID int64) (map[ngmodels.AlertRuleGroupKey]*apimodels.ManagedBy, error) {
+	stored, err := srv.store.GetRuleGroupsManagedBy(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[ngmodels.AlertRuleGroupKey]*apimodels.ManagedBy, len(stored))
+	for key, managedBy := range stored {
+		result[key] = ApiManagedByFromManagedBy(&managedBy)
+	}
+	return result, nil
+}
+
+// archivedRuleGroups returns the archived rule groups of the organization.
+func (srv RulerSrv) archivedRuleGroups(ctx context.Context, orgID int64) (map[ngmodels.AlertRuleGroupKey]bool, error) {
+	keys, err := srv.store.GetArchivedRuleGroups(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[ngmodels.AlertRuleGroupKey]bool, len(keys))
+	for _, key := range keys {
+		result[key] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/authn"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const (
+	reasonFolderNotVisible = "user cannot read the folder"
+	reasonDatasourceAccess = "user cannot query all data sources used by the rule group"
+)
+
+// RouteGetRulesAccess evaluates the access of the user identified by the query parameter `userId` to all rule groups of the
+// organization as it is done when the user reads rules, and returns the groups that would be filtered out and why.
+// It is intended for administrators troubleshooting why a user cannot see some rules.
+func (srv RulerSrv) RouteGetRulesAccess(c *contextmodel.ReqContext) response.Response {
+	userID := c.QueryInt64("userId")
+	if userID <= 0 {
+		return ErrResp(http.StatusBadRequest, errors.New("userId must be a positive integer"), "")
+	}
+	ctx := c.Req.Context()
+	orgID := c.SignedInUser.GetOrgID()
+
+	target, err := srv.impersonate(ctx, orgID, userID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get permissions of the user")
+	}
+
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(ctx, orgID, target)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+
+	rules, err := srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+
+	result := apimodels.RuleGroupsAccessResponse{
+		UserID: userID,
+		Groups: make([]apimodels.RuleGroupAccess, 0),
+	}
+	// The groups that query the same data sources share the decision of the authorization of the user.
+	ctx = accesscontrol.WithRequestAuthorizationCache(ctx)
+	for groupKey, rulesGroup := range ngmodels.GroupByAlertRuleGroupKey(rules) {
+		access := apimodels.RuleGroupAccess{
+			FolderUID:  groupKey.NamespaceUID,
+			RuleGroup:  groupKey.RuleGroup,
+			Authorized: true,
+		}
+		if _, ok := namespaceMap[groupKey.NamespaceUID]; !ok {
+			access.Authorized = false
+			access.Reason = reasonFolderNotVisible
+		} else if err := srv.authz.AuthorizeAccessToRuleGroup(ctx, target, rulesGroup); err != nil {
+			if !accesscontrol.IsAuthorizationError(err) {
+				return errorToResponse(err)
+			}
+			access.Authorized = false
+			access.Reason = reasonDatasourceAccess
+			for _, p := range accesscontrol.GetMissingPermissions(err) {
+				access.MissingPermissions = append(access.MissingPermissions, apimodels.MissingPermission(p))
+			}
+		}
+		result.Groups = append(result.Groups, access)
+	}
+	sort.Slice(result.Groups, func(i, j int) bool {
+		if result.Groups[i].FolderUID != result.Groups[j].FolderUID {
+			return result.Groups[i].FolderUID < result.Groups[j].FolderUID
+		}
+		return result.Groups[i].RuleGroup < result.Groups[j].RuleGroup
+	})
+	return response.JSON(http.StatusOK, result)
+}
+
+// impersonate returns an identity of the user with the permissions it has in the organization.
+func (srv RulerSrv) impersonate(ctx context.Context, orgID, userID int64) (identity.Requester, error) {
+	permissions, err := srv.permissions.SearchUserPermissions(ctx, orgID, ac.SearchOptions{
+		NamespacedID: authn.NamespacedID(authn.NamespaceUser, userID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions of user %d: %w", userID, err)
+	}
+	return &user.SignedInUser{
+		UserID: userID,
+		OrgID:  orgID,
+		Permissions: map[int64]map[string][]string{
+			orgID: ac.GroupScopesByAction(permissions),
+		},
+	}, nil
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/authn"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+type fakeUserPermissionsService struct {
+	permissions map[string][]ac.Permission
+}
+
+func (f *fakeUserPermissionsService) SearchUserPermissions(_ context.Context, _ int64, options ac.SearchOptions) ([]ac.Permission, error) {
+	return f.permissions[options.NamespacedID], nil
+}
+
+func TestRouteGetRulesAccess(t *testing.T) {
+	orgID := rand.Int63()
+	userID := rand.Int63n(1000) + 1
+	ruleStore := fakes.NewRuleStore(t)
+	folder1 := randFolder()
+	ruleStore.Folders[orgID] = []*folder.Folder{folder1}
+
+	group1Key := models.GenerateGroupKey(orgID)
+	group1Key.NamespaceUID = folder1.UID
+	group2Key := models.GenerateGroupKey(orgID)
+	group2Key.NamespaceUID = folder1.UID
+
+	group1 := models.GenerateAlertRules(rand.Intn(4)+2, models.AlertRuleGen(withGroupKey(group1Key)))
+	group2 := models.GenerateAlertRules(rand.Intn(4)+2, models.AlertRuleGen(withGroupKey(group2Key)))
+	ruleStore.PutRule(context.Background(), append(group1, group2...)...)
+
+	// the user can query data sources of all rules but the first one of the second group
+	var permissions []ac.Permission
+	for action, scopes := range createPermissionsForRules(append(group1, group2[1:]...), orgID)[orgID] {
+		for _, scope := range scopes {
+			permissions = append(permissions, ac.Permission{Action: action, Scope: scope})
+		}
+	}
+
+	srv := createService(ruleStore)
+	srv.permissions = &fakeUserPermissionsService{
+		permissions: map[string][]ac.Permission{
+			authn.NamespacedID(authn.NamespaceUser, userID): permissions,
+		},
+	}
+
+	t.Run("should return bad request if user is not specified", func(t *testing.T) {
+		response := srv.RouteGetRulesAccess(createRequestContext(orgID, nil))
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return groups filtered out for the user and why", func(t *testing.T) {
+		request := createRequestContext(orgID, nil)
+		request.Req.Form.Set("userId", strconv.FormatInt(userID, 10))
+
+		response := srv.RouteGetRulesAccess(request)
+		require.Equal(t, http.StatusOK, response.Status())
+
+		result := apimodels.RuleGroupsAccessResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Equal(t, userID, result.UserID)
+		require.Len(t, result.Groups, 2)
+
+		byGroup := make(map[string]apimodels.RuleGroupAccess, len(result.Groups))
+		for _, g := range result.Groups {
+			require.Equal(t, folder1.UID, g.FolderUID)
+			byGroup[g.RuleGroup] = g
+		}
+
+		require.True(t, byGroup[group1Key.RuleGroup].Authorized)
+		require.Empty(t, byGroup[group1Key.RuleGroup].MissingPermissions)
+
+		denied := byGroup[group2Key.RuleGroup]
+		require.False(t, denied.Authorized)
+		require.Equal(t, reasonDatasourceAccess, denied.Reason)
+		require.NotEmpty(t, denied.MissingPermissions)
+		for _, p := range denied.MissingPermissions {
+			require.Equal(t, datasources.ActionQuery, p.Action)
+		}
+	})
+}
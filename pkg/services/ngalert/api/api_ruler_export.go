@@ -80,8 +80,11 @@ func (srv RulerSrv) ExportRules(c *contextmodel.ReqContext) response.Response {
 		}
 		groups = []ngmodels.AlertRuleGroupWithFolderTitle{rulesGroup}
 	} else {
-		var err error
-		groups, err = srv.getRulesWithFolderTitleInFolders(c, folderUIDs)
+		archived, err := parseArchivedRuleGroupsFilter(c.Query("archived"))
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		groups, err = srv.getRulesWithFolderTitleInFolders(c, folderUIDs, archived)
 		if err != nil {
 			return errorToResponse(err)
 		}
@@ -132,7 +135,7 @@ func (srv RulerSrv) getRuleGroupWithFolderTitle(c *contextmodel.ReqContext, rule
 
 // getRulesWithFolderTitleInFolders gets list of folders to which user has access, and then calls searchAuthorizedAlertRules.
 // If argument folderUIDs is not empty it intersects it with the list of folders available for user and then retrieves rules that are in those folders.
-func (srv RulerSrv) getRulesWithFolderTitleInFolders(c *contextmodel.ReqContext, folderUIDs []string) ([]ngmodels.AlertRuleGroupWithFolderTitle, error) {
+func (srv RulerSrv) getRulesWithFolderTitleInFolders(c *contextmodel.ReqContext, folderUIDs []string, archived ngmodels.ArchivedRuleGroupsFilter) ([]ngmodels.AlertRuleGroupWithFolderTitle, error) {
 	folders, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.SignedInUser.GetOrgID(), c.SignedInUser)
 	if err != nil {
 		return nil, err
@@ -156,7 +159,7 @@ func (srv RulerSrv) getRulesWithFolderTitleInFolders(c *contextmodel.ReqContext,
 		}
 	}
 
-	rulesByGroup, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), c, folderUIDs, "", 0)
+	rulesByGroup, _, err := srv.searchAuthorizedAlertRules(c.Req.Context(), c, folderUIDs, "", 0, archived)
 	if err != nil {
 		return nil, err
 	}
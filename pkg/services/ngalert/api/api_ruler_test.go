@@ -220,6 +220,72 @@ func TestRouteGetNamespaceRulesConfig(t *testing.T) {
 			assert.Emptyf(t, expectedRules, "not all expected rules were returned")
 		})
 	})
+	t.Run("should exclude the archived rule groups by default", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		archived := models.GenerateAlertRulesSmallNonEmpty(models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("archived")))
+		active := models.GenerateAlertRulesSmallNonEmpty(models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("active")))
+		ruleStore.PutRule(context.Background(), archived...)
+		ruleStore.PutRule(context.Background(), active...)
+		ruleStore.Archived = []models.AlertRuleGroupKey{archived[0].GetGroupKey()}
+		svc := createService(ruleStore)
+
+		groups := func(t *testing.T, filter string) map[string]bool {
+			t.Helper()
+			req := createRequestContext(orgID, nil)
+			req.Req.Form.Set("archived", filter)
+			response := svc.RouteGetNamespaceRulesConfig(req, folder.UID)
+			require.Equal(t, http.StatusAccepted, response.Status())
+			result := apimodels.NamespaceConfigResponse{}
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			archived := make(map[string]bool)
+			for _, group := range result[folder.Fullpath] {
+				archived[group.Name] = group.Archived
+			}
+			return archived
+		}
+
+		require.Equal(t, map[string]bool{"active": false}, groups(t, ""))
+		require.Equal(t, map[string]bool{"active": false, "archived": true}, groups(t, "include"))
+		require.Equal(t, map[string]bool{"archived": true}, groups(t, "only"))
+
+		req := createRequestContext(orgID, nil)
+		req.Req.Form.Set("archived", "all")
+		require.Equal(t, http.StatusBadRequest, svc.RouteGetNamespaceRulesConfig(req, folder.UID).Status())
+	})
+	t.Run("should return who manages the rule groups", func(t *testing.T) {
+		orgID := rand.Int63()
+		folder := randFolder()
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], folder)
+		managed := models.GenerateAlertRulesSmallNonEmpty(models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("managed")))
+		other := models.GenerateAlertRulesSmallNonEmpty(models.AlertRuleGen(withOrgID(orgID), withNamespace(folder), withGroup("other")))
+		ruleStore.PutRule(context.Background(), managed...)
+		ruleStore.PutRule(context.Background(), other...)
+		ruleStore.ManagedBy = map[models.AlertRuleGroupKey]models.ManagedBy{
+			managed[0].GetGroupKey(): {Title: "Managed by repository alerting-rules", URL: "https://github.com/example/alerting-rules"},
+		}
+		svc := createService(ruleStore)
+
+		response := svc.RouteGetNamespaceRulesConfig(createRequestContext(orgID, nil), folder.UID)
+		require.Equal(t, http.StatusAccepted, response.Status())
+		result := apimodels.NamespaceConfigResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		managedBy := make(map[string]*apimodels.ManagedBy)
+		for _, group := range result[folder.Fullpath] {
+			managedBy[group.Name] = group.ManagedBy
+		}
+		expected := &apimodels.ManagedBy{Title: "Managed by repository alerting-rules", URL: "https://github.com/example/alerting-rules"}
+		require.Equal(t, map[string]*apimodels.ManagedBy{"managed": expected, "other": nil}, managedBy)
+
+		response = svc.RouteGetRulesGroupConfig(createRequestContext(orgID, nil), folder.UID, "managed")
+		require.Equal(t, http.StatusAccepted, response.Status())
+		group := apimodels.RuleGroupConfigResponse{}
+		require.NoError(t, json.Unmarshal(response.Body(), &group))
+		require.Equal(t, expected, group.ManagedBy)
+	})
 	t.Run("should return the provenance of the alert rules", func(t *testing.T) {
 		orgID := rand.Int63()
 		folder := randFolder()
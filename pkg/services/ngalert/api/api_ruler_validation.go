@@ -9,6 +9,7 @@ import (
 
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/template"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -110,8 +111,8 @@ func validateRuleNode(
 		ExecErrState:    errorState,
 	}
 
-	if ruleNode.GrafanaManagedAlert.NotificationSettings != nil {
-		newAlertRule.NotificationSettings, err = validateNotificationSettings(ruleNode.GrafanaManagedAlert.NotificationSettings)
+	if ruleNode.GrafanaManagedAlert.NotificationSettings != nil || len(ruleNode.GrafanaManagedAlert.AdditionalNotificationSettings) > 0 {
+		newAlertRule.NotificationSettings, err = validateNotificationSettings(ruleNode.GrafanaManagedAlert.NotificationSettings, ruleNode.GrafanaManagedAlert.AdditionalNotificationSettings)
 		if err != nil {
 			return nil, err
 		}
@@ -134,6 +135,11 @@ func validateRuleNode(
 		if err != nil {
 			return nil, err
 		}
+
+		err = template.ValidateRule(newAlertRule)
+		if err != nil {
+			return nil, err
+		}
 	}
 	return &newAlertRule, nil
 }
@@ -269,20 +275,13 @@ func ValidateRuleGroup(
 	return result, nil
 }
 
-func validateNotificationSettings(n *apimodels.AlertRuleNotificationSettings) ([]ngmodels.NotificationSettings, error) {
-	s := ngmodels.NotificationSettings{
-		Receiver:          n.Receiver,
-		GroupBy:           n.GroupBy,
-		GroupWait:         n.GroupWait,
-		GroupInterval:     n.GroupInterval,
-		RepeatInterval:    n.RepeatInterval,
-		MuteTimeIntervals: n.MuteTimeIntervals,
+func validateNotificationSettings(n *apimodels.AlertRuleNotificationSettings, additional []apimodels.AlertRuleNotificationSettings) ([]ngmodels.NotificationSettings, error) {
+	settings := NotificationSettingsFromAlertRuleNotificationSettings(n, additional)
+	if n == nil {
+		return nil, errors.New("invalid notification settings: additional notification settings require notification settings")
 	}
-
-	if err := s.Validate(); err != nil {
+	if err := ngmodels.ValidateNotificationSettings(settings); err != nil {
 		return nil, fmt.Errorf("invalid notification settings: %w", err)
 	}
-	return []ngmodels.NotificationSettings{
-		s,
-	}, nil
+	return settings, nil
 }
@@ -892,6 +892,67 @@ func TestValidateRuleNodeNotificationSettings(t *testing.T) {
 	}
 }
 
+func TestValidateRuleNodeAdditionalNotificationSettings(t *testing.T) {
+	cfg := config(t)
+	settings := AlertRuleNotificationSettingsFromNotificationSettings([]models.NotificationSettings{models.NewDefaultNotificationSettings("receiver-1")})
+	additional := AdditionalAlertRuleNotificationSettingsFromNotificationSettings([]models.NotificationSettings{
+		models.NewDefaultNotificationSettings("receiver-1"),
+		models.NewDefaultNotificationSettings("receiver-2"),
+	})
+
+	t.Run("all settings are used", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.NotificationSettings = settings
+		r.GrafanaManagedAlert.AdditionalNotificationSettings = additional
+		rule, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval, rand.Int63(), randFolder().UID, RuleLimitsFromConfig(cfg))
+		require.NoError(t, err)
+		require.Len(t, rule.NotificationSettings, 2)
+		require.Equal(t, "receiver-1", rule.NotificationSettings[0].Receiver)
+		require.Equal(t, "receiver-2", rule.NotificationSettings[1].Receiver)
+	})
+
+	t.Run("fail if additional settings are set without settings", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.NotificationSettings = nil
+		r.GrafanaManagedAlert.AdditionalNotificationSettings = additional
+		_, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval, rand.Int63(), randFolder().UID, RuleLimitsFromConfig(cfg))
+		require.ErrorContains(t, err, "additional notification settings require notification settings")
+	})
+
+	t.Run("fail if a receiver is used twice", func(t *testing.T) {
+		r := validRule()
+		r.GrafanaManagedAlert.NotificationSettings = settings
+		r.GrafanaManagedAlert.AdditionalNotificationSettings = AdditionalAlertRuleNotificationSettingsFromNotificationSettings([]models.NotificationSettings{
+			models.NewDefaultNotificationSettings("receiver-1"),
+			models.NewDefaultNotificationSettings("receiver-1"),
+		})
+		_, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval, rand.Int63(), randFolder().UID, RuleLimitsFromConfig(cfg))
+		require.ErrorContains(t, err, "receiver 'receiver-1' is used by more than one notification settings")
+	})
+}
+
+func TestValidateRuleNodeTemplates(t *testing.T) {
+	cfg := config(t)
+
+	t.Run("fail if an annotation has an invalid template", func(t *testing.T) {
+		r := validRule()
+		r.ApiRuleNode.Annotations = map[string]string{
+			"summary": "{{ $labels.instance",
+		}
+		_, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval, rand.Int63(), randFolder().UID, RuleLimitsFromConfig(cfg))
+		require.ErrorContains(t, err, "invalid template in annotation 'summary'")
+	})
+
+	t.Run("fail if a label uses the value of an unknown query", func(t *testing.T) {
+		r := validRule()
+		r.ApiRuleNode.Labels = map[string]string{
+			"severity": "{{ if gt $values.UNKNOWN.Value 10.0 }}critical{{ end }}",
+		}
+		_, err := validateRuleNode(&r, util.GenerateShortUID(), cfg.BaseInterval, rand.Int63(), randFolder().UID, RuleLimitsFromConfig(cfg))
+		require.ErrorContains(t, err, "invalid template in label 'severity'")
+	})
+}
+
 func TestValidateRuleNodeReservedLabels(t *testing.T) {
 	cfg := config(t)
 
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"errors"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -94,17 +95,7 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext,
 		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate queries")
 	}
 
-	cfg := state.ManagerCfg{
-		Metrics:       nil,
-		ExternalURL:   srv.appUrl,
-		InstanceStore: nil,
-		Images:        &backtesting.NoopImageService{},
-		Clock:         clock.New(),
-		Historian:     nil,
-		Tracer:        srv.tracer,
-		Log:           log.New("ngalert.state.manager"),
-	}
-	manager := state.NewManager(cfg, state.NewNoopPersister())
+	manager := srv.newStateManager()
 	includeFolder := !srv.cfg.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel)
 	transitions := manager.ProcessEvalResults(
 		c.Req.Context(),
@@ -122,6 +113,94 @@ func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *contextmodel.ReqContext,
 	return response.JSON(http.StatusOK, alerts)
 }
 
+// RouteSimulateGrafanaRule evaluates a rule like RouteTestGrafanaRuleConfig, except that the data source queries of the
+// rule return the frames of the request instead of querying the data sources. It lets rules be tested without data
+// sources, for instance in CI.
+func (srv TestingApiSrv) RouteSimulateGrafanaRule(c *contextmodel.ReqContext, body apimodels.SimulateGrafanaRulePayload) response.Response {
+	var folderUID, folderTitle string
+	if body.NamespaceUID != "" {
+		folder, err := srv.folderService.GetNamespaceByUID(c.Req.Context(), body.NamespaceUID, c.OrgID, c.SignedInUser)
+		if err != nil {
+			return toNamespaceErrorResponse(dashboards.ErrFolderAccessDenied)
+		}
+		folderUID, folderTitle = folder.UID, folder.Fullpath
+	}
+	rule, err := validateRuleNode(
+		&body.Rule,
+		body.RuleGroup,
+		srv.cfg.BaseInterval,
+		c.SignedInUser.GetOrgID(),
+		folderUID,
+		RuleLimitsFromConfig(srv.cfg),
+	)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	evaluator, err := srv.evaluator.Create(eval.NewContextWithSyntheticData(c.Req.Context(), c.SignedInUser, body.Data), rule.GetEvalCondition())
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "Failed to build evaluator for queries and expressions")
+	}
+
+	now := body.Now
+	if now.IsZero() {
+		now = timeNow()
+	}
+	results, err := evaluator.Evaluate(c.Req.Context(), now)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "Failed to evaluate queries")
+	}
+
+	includeFolder := folderUID != "" && !srv.cfg.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel)
+	transitions := srv.newStateManager().ProcessEvalResults(
+		c.Req.Context(),
+		now,
+		rule,
+		results,
+		state.GetRuleExtraLabels(log.New("testing"), rule, folderTitle, includeFolder),
+	)
+
+	result := apimodels.SimulateGrafanaRuleResult{Instances: make([]apimodels.SimulatedAlertInstance, 0, len(transitions))}
+	for _, transition := range transitions {
+		instance := apimodels.SimulatedAlertInstance{
+			Labels:      transition.Labels,
+			State:       transition.State.State.String(),
+			StateReason: transition.StateReason,
+		}
+		if len(transition.Values) > 0 {
+			instance.Values = make(map[string]*float64, len(transition.Values))
+			for refID, value := range transition.Values {
+				if math.IsNaN(value) || math.IsInf(value, 0) {
+					instance.Values[refID] = nil
+					continue
+				}
+				instance.Values[refID] = util.Pointer(value)
+			}
+		}
+		if transition.Error != nil {
+			instance.Error = transition.Error.Error()
+		}
+		result.Instances = append(result.Instances, instance)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// newStateManager creates a state manager that only keeps the states in memory, to process the results of test
+// evaluations.
+func (srv TestingApiSrv) newStateManager() *state.Manager {
+	cfg := state.ManagerCfg{
+		Metrics:       nil,
+		ExternalURL:   srv.appUrl,
+		InstanceStore: nil,
+		Images:        &backtesting.NoopImageService{},
+		Clock:         clock.New(),
+		Historian:     nil,
+		Tracer:        srv.tracer,
+		Log:           log.New("ngalert.state.manager"),
+	}
+	return state.NewManager(cfg, state.NewNoopPersister())
+}
+
 func (srv TestingApiSrv) RouteTestRuleConfig(c *contextmodel.ReqContext, body apimodels.TestRulePayload, datasourceUID string) response.Response {
 	if body.Type() != apimodels.LoTexRulerBackend {
 		return errorToResponse(backendTypeDoesNotMatchPayloadTypeError(apimodels.LoTexRulerBackend, body.Type().String()))
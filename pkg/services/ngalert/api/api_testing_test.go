@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -240,6 +241,65 @@ func TestRouteTestGrafanaRuleConfig(t *testing.T) {
 	})
 }
 
+func TestRouteSimulateGrafanaRule(t *testing.T) {
+	rc := &contextmodel.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+
+	t.Run("should return the states of the alerts without access to the data sources", func(t *testing.T) {
+		// The results are evaluated at the time of the simulation, otherwise their states would be resolved as stale.
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().Evaluate(mock.Anything, now).Return(eval.Results{{
+			Instance:    data.Labels{"host": "a"},
+			State:       eval.Alerting,
+			EvaluatedAt: now,
+		}}, nil)
+		srv := createTestingApiSrv(t, nil, acMock.New(), eval_mocks.NewEvaluatorFactory(evaluator), &featuremgmt.FeatureManager{}, fakes2.NewRuleStore(t))
+
+		rule := validRule()
+		forDuration := model.Duration(0)
+		rule.For = &forDuration
+		response := srv.RouteSimulateGrafanaRule(rc, definitions.SimulateGrafanaRulePayload{
+			Rule: rule,
+			Data: map[string]data.Frames{"A": {data.NewFrame("")}},
+			Now:  now,
+		})
+
+		require.Equal(t, http.StatusOK, response.Status())
+		var result definitions.SimulateGrafanaRuleResult
+		require.NoError(t, json.Unmarshal(response.Body(), &result))
+		require.Len(t, result.Instances, 1)
+		require.Equal(t, "Alerting", result.Instances[0].State)
+		require.Equal(t, "a", result.Instances[0].Labels["host"])
+		require.Equal(t, "data", result.Instances[0].Labels["test-label"])
+	})
+
+	t.Run("should return Forbidden if user cannot access folder", func(t *testing.T) {
+		ruleStore := fakes2.NewRuleStore(t)
+		ruleStore.Hook = func(cmd any) error {
+			q, ok := cmd.(fakes2.GenericRecordedQuery)
+			if ok && q.Name == "GetNamespaceByUID" {
+				return dashboards.ErrFolderAccessDenied
+			}
+			return nil
+		}
+		srv := createTestingApiSrv(t, nil, acMock.New(), eval_mocks.NewEvaluatorFactory(&eval_mocks.ConditionEvaluatorMock{}), &featuremgmt.FeatureManager{}, ruleStore)
+
+		response := srv.RouteSimulateGrafanaRule(rc, definitions.SimulateGrafanaRulePayload{
+			Rule:         validRule(),
+			NamespaceUID: uuid.NewString(),
+		})
+
+		require.Equal(t, http.StatusForbidden, response.Status())
+	})
+}
+
 func TestRouteEvalQueries(t *testing.T) {
 	t.Run("when fine-grained access is enabled", func(t *testing.T) {
 		rc := &contextmodel.ReqContext{
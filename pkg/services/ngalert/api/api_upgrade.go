@@ -8,7 +8,9 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/migration"
+	migrationStore "github.com/grafana/grafana/pkg/services/ngalert/migration/store"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -53,6 +55,34 @@ func (srv *UpgradeSrv) RouteGetOrgUpgrade(c *contextmodel.ReqContext) response.R
 	return response.JSON(http.StatusOK, state)
 }
 
+func (srv *UpgradeSrv) RouteGetOrgUpgradeExport(c *contextmodel.ReqContext) response.Response {
+	export, err := srv.upgradeService.GetOrgMigrationExport(c.Req.Context(), c.OrgID, c.QueryBoolWithDefault("decrypt", false))
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "Server error")
+	}
+
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(export.Groups)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
+	if len(export.ContactPoints) > 0 {
+		cps, err := AlertingFileExportFromEmbeddedContactPoints(c.OrgID, export.ContactPoints)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		}
+		e.ContactPoints = cps.ContactPoints
+	}
+	if export.Policies != nil {
+		policies, err := AlertingFileExportFromRoute(c.OrgID, *export.Policies)
+		if err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		}
+		e.Policies = policies.Policies
+	}
+
+	return exportResponse(c, e)
+}
+
 func (srv *UpgradeSrv) RouteDeleteOrgUpgrade(c *contextmodel.ReqContext) response.Response {
 	err := srv.upgradeService.RevertOrg(c.Req.Context(), c.OrgID)
 	if err != nil {
@@ -138,3 +168,76 @@ func (srv *UpgradeSrv) RoutePostUpgradeAllChannels(c *contextmodel.ReqContext) r
 	}
 	return response.JSON(http.StatusOK, summary)
 }
+
+func (srv *UpgradeSrv) RouteGetStagedOrgUpgrades(c *contextmodel.ReqContext) response.Response {
+	staged, err := srv.upgradeService.GetStagedUpgrades(c.Req.Context())
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "Server error")
+	}
+	result := make(apimodels.StagedOrgUpgrades, 0, len(staged))
+	for _, s := range staged {
+		result = append(result, stagedOrgUpgradeToApi(s))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *UpgradeSrv) RouteGetStagedOrgUpgrade(c *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return srv.stagedOrgUpgradeResponse(orgIDParam, func(orgID int64) (*migrationStore.StagedUpgrade, error) {
+		return srv.upgradeService.GetStagedUpgrade(c.Req.Context(), orgID)
+	})
+}
+
+func (srv *UpgradeSrv) RoutePostStagedOrgUpgrade(c *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return srv.stagedOrgUpgradeResponse(orgIDParam, func(orgID int64) (*migrationStore.StagedUpgrade, error) {
+		return srv.upgradeService.EnqueueOrg(c.Req.Context(), orgID)
+	})
+}
+
+func (srv *UpgradeSrv) RoutePostStagedOrgUpgradeDryRun(c *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return srv.stagedOrgUpgradeResponse(orgIDParam, func(orgID int64) (*migrationStore.StagedUpgrade, error) {
+		return srv.upgradeService.DryRunStagedOrg(c.Req.Context(), orgID)
+	})
+}
+
+func (srv *UpgradeSrv) RoutePostStagedOrgUpgradeApproval(c *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return srv.stagedOrgUpgradeResponse(orgIDParam, func(orgID int64) (*migrationStore.StagedUpgrade, error) {
+		return srv.upgradeService.ApproveStagedOrg(c.Req.Context(), orgID)
+	})
+}
+
+func (srv *UpgradeSrv) RoutePostStagedOrgUpgradeExecution(c *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return srv.stagedOrgUpgradeResponse(orgIDParam, func(orgID int64) (*migrationStore.StagedUpgrade, error) {
+		return srv.upgradeService.ExecuteStagedOrg(c.Req.Context(), orgID)
+	})
+}
+
+// stagedOrgUpgradeResponse parses the org ID, runs the staged upgrade operation and maps its result to a response.
+func (srv *UpgradeSrv) stagedOrgUpgradeResponse(orgIDParam string, operation func(orgID int64) (*migrationStore.StagedUpgrade, error)) response.Response {
+	orgID, err := strconv.ParseInt(orgIDParam, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "failed to parse orgId")
+	}
+
+	staged, err := operation(orgID)
+	if err != nil {
+		if errors.Is(err, migration.ErrStagedUpgradeNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, migration.ErrInvalidStagedTransition) || errors.Is(err, migration.ErrUpgradeInProgress) {
+			return ErrResp(http.StatusConflict, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Server error")
+	}
+	return response.JSON(http.StatusOK, stagedOrgUpgradeToApi(staged))
+}
+
+func stagedOrgUpgradeToApi(staged *migrationStore.StagedUpgrade) apimodels.StagedOrgUpgrade {
+	return apimodels.StagedOrgUpgrade{
+		OrgID:         staged.OrgID,
+		Status:        string(staged.Status),
+		DryRunSummary: staged.DryRunSummary,
+		DryRunErrors:  staged.DryRunErrors,
+		Error:         staged.Error,
+		Updated:       staged.Updated,
+	}
+}
@@ -31,6 +31,12 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodGet + "/api/ruler/grafana/api/v1/rules",
 		http.MethodGet + "/api/ruler/grafana/api/v1/export/rules":
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodGet + "/api/ruler/grafana/api/v1/access/rules":
+		// evaluating access on behalf of another user requires reading its permissions
+		eval = ac.EvalAll(
+			ac.EvalPermission(ac.ActionAlertingRuleRead),
+			ac.EvalPermission(ac.ActionUsersPermissionsRead, ac.ScopeUsersAll),
+		)
 	case http.MethodPost + "/api/ruler/grafana/api/v1/rules/{Namespace}/export":
 		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":Namespace"))
 		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
@@ -42,6 +48,33 @@ func (api *API) authorize(method, path string) web.Handler {
 			ac.EvalPermission(ac.ActionAlertingRuleUpdate, scope),
 			ac.EvalPermission(ac.ActionAlertingRuleCreate, scope),
 			ac.EvalPermission(ac.ActionAlertingRuleDelete, scope),
+			ac.EvalPermission(ac.ActionAlertingRuleIntervalUpdate, scope),
+		)
+
+	case http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/interval":
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":FolderUID"))
+		// the permissions of the user in the folder are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate, scope),
+			ac.EvalPermission(ac.ActionAlertingRuleIntervalUpdate, scope),
+		)
+
+	case http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/move":
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":FolderUID"))
+		// the permissions of the user in both folders are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRuleDelete, scope),
+		)
+
+	case http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/prometheus-rule-groups":
+		scope := dashboards.ScopeFoldersProvider.GetResourceScopeUID(ac.Parameter(":FolderUID"))
+		// more granular permissions are enforced by the handler via "authorizeRuleChanges"
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningWrite),
+			ac.EvalPermission(ac.ActionAlertingRuleCreate, scope),
+			ac.EvalPermission(ac.ActionAlertingRuleUpdate, scope),
 		)
 
 	// Grafana rule state history paths
@@ -67,6 +100,8 @@ func (api *API) authorize(method, path string) web.Handler {
 	// Grafana unified alerting upgrade paths
 	case http.MethodGet + "/api/v1/upgrade/org":
 		return middleware.ReqOrgAdmin
+	case http.MethodGet + "/api/v1/upgrade/org/export":
+		return middleware.ReqOrgAdmin
 	case http.MethodPost + "/api/v1/upgrade/org":
 		return middleware.ReqOrgAdmin
 	case http.MethodDelete + "/api/v1/upgrade/org":
@@ -81,6 +116,14 @@ func (api *API) authorize(method, path string) web.Handler {
 		return middleware.ReqOrgAdmin
 	case http.MethodPost + "/api/v1/upgrade/channels/{ChannelID}":
 		return middleware.ReqOrgAdmin
+	case http.MethodGet + "/api/v1/upgrade/staged",
+		http.MethodGet + "/api/v1/upgrade/staged/{OrgID}",
+		http.MethodPost + "/api/v1/upgrade/staged/{OrgID}",
+		http.MethodPost + "/api/v1/upgrade/staged/{OrgID}/dry-run",
+		http.MethodPost + "/api/v1/upgrade/staged/{OrgID}/approve",
+		http.MethodPost + "/api/v1/upgrade/staged/{OrgID}/execute":
+		// the staged upgrade is managed by server admins for all organizations
+		return middleware.ReqGrafanaAdmin
 
 	// Grafana, Prometheus-compatible Paths
 	case http.MethodGet + "/api/prometheus/grafana/api/v1/rules":
@@ -97,6 +140,9 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodPost + "/api/v1/eval":
 		// additional authorization is done in the request handler
 		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
+	case http.MethodPost + "/api/v1/rule/simulate/grafana":
+		// data sources are not queried, so the access to them is not checked
+		eval = ac.EvalPermission(ac.ActionAlertingRuleRead)
 
 	// Lotex Paths
 	case http.MethodDelete + "/api/ruler/{DatasourceUID}/api/v1/rules/{Namespace}":
@@ -215,33 +261,78 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodGet + "/api/v1/ngalert/alertmanagers":
 		return middleware.ReqOrgAdmin
 
+	// Provisioning paths that change many organizations at once
+	case http.MethodPost + "/api/v1/provisioning/bulk/rule-groups",
+		http.MethodPost + "/api/v1/provisioning/bulk/contact-point-secrets",
+		http.MethodPost + "/api/v1/provisioning/bulk/policies":
+		return middleware.ReqGrafanaAdmin
+
+	// Provisioning paths that hand resources over from a provenance to another
+	case http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}/provenance",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/provenance":
+		return middleware.ReqOrgAdmin
+
 	// Grafana-only Provisioning Read Paths
 	case http.MethodGet + "/api/v1/provisioning/policies/export",
 		http.MethodGet + "/api/v1/provisioning/contact-points/export",
 		http.MethodGet + "/api/v1/provisioning/mute-timings/export",
-		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}/export":
+		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}/export",
+		http.MethodGet + "/api/v1/provisioning/alertmanager-routing/export":
 		eval = ac.EvalAny(
 			ac.EvalPermission(ac.ActionAlertingNotificationsRead),       // organization scope
 			ac.EvalPermission(ac.ActionAlertingProvisioningRead),        // organization scope
 			ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets), // organization scope
 		)
 
+	// Users who can read rules only get the rules of the groups that they can access.
+	case http.MethodGet + "/api/v1/provisioning/alert-rules":
+		eval = ac.EvalAny(
+			ac.EvalPermission(ac.ActionAlertingProvisioningRead),        // organization scope
+			ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets), // organization scope
+			ac.EvalPermission(ac.ActionAlertingRuleRead),                // folder scope
+		)
+
 	case http.MethodGet + "/api/v1/provisioning/policies",
+		http.MethodGet + "/api/v1/provisioning/policies/effective",
+		http.MethodGet + "/api/v1/provisioning/alertmanager-routing",
+		http.MethodGet + "/api/v1/provisioning/rule-title-uniqueness",
 		http.MethodGet + "/api/v1/provisioning/contact-points",
 		http.MethodGet + "/api/v1/provisioning/templates",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
 		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
-		http.MethodGet + "/api/v1/provisioning/alert-rules",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/search",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/usage",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/quota",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/noise-report",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/trash",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/sync-status",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates",
+		http.MethodGet + "/api/v1/provisioning/alert-rule-templates/{UID}",
 		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
-		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export":
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export",
+		http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/cost-estimate",
+		http.MethodGet + "/api/v1/provisioning/rule-group-jobs/{UID}",
+		http.MethodGet + "/api/v1/provisioning/webhook/dead-letters",
+		http.MethodGet + "/api/v1/provisioning/snapshots",
+		http.MethodGet + "/api/v1/provisioning/file-schema",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/tags",
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags",
+		http.MethodGet + "/api/v1/provisioning/contact-points/{UID}/tags",
+		http.MethodGet + "/api/v1/provisioning/contact-points/{UID}/deliveries",
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/annotations",
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/evaluation":
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingProvisioningRead), ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets)) // organization scope
 
 	case http.MethodPut + "/api/v1/provisioning/policies",
 		http.MethodDelete + "/api/v1/provisioning/policies",
+		http.MethodPost + "/api/v1/provisioning/policies/merge",
+		http.MethodPut + "/api/v1/provisioning/alertmanager-routing",
+		http.MethodPut + "/api/v1/provisioning/rule-title-uniqueness",
+		http.MethodDelete + "/api/v1/provisioning/rule-title-uniqueness",
 		http.MethodPost + "/api/v1/provisioning/contact-points",
 		http.MethodPut + "/api/v1/provisioning/contact-points/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/contact-points/{UID}",
@@ -253,8 +344,27 @@ func (api *API) authorize(method, path string) web.Handler {
 		http.MethodPost + "/api/v1/provisioning/alert-rules",
 		http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/alert-rules/{UID}",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/trash/{UID}/restore",
+		http.MethodPut + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/alert-rule-templates/{UID}",
+		http.MethodPost + "/api/v1/provisioning/alert-rule-templates/{UID}/instantiate",
 		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
-		http.MethodDelete + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}":
+		http.MethodDelete + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/clone",
+		http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/generate",
+		http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/jobs",
+		http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/archive",
+		http.MethodPost + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/unarchive",
+		http.MethodPost + "/api/v1/provisioning/snapshots",
+		http.MethodPost + "/api/v1/provisioning/snapshots/{ID}/restore",
+		http.MethodPost + "/api/v1/provisioning/webhook/dead-letters/{UID}/replay",
+		http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}/tags",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags",
+		http.MethodPut + "/api/v1/provisioning/contact-points/{UID}/tags",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/pause",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/metadata",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/annotations",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/evaluation":
 		eval = ac.EvalPermission(ac.ActionAlertingProvisioningWrite) // organization scope
 	case http.MethodGet + "/api/v1/notifications/time-intervals/{name}",
 		http.MethodGet + "/api/v1/notifications/time-intervals":
@@ -9,28 +9,35 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/util"
 )
 
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
 	return models.AlertRule{
-		ID:                   a.ID,
-		UID:                  a.UID,
-		OrgID:                a.OrgID,
-		NamespaceUID:         a.FolderUID,
-		RuleGroup:            a.RuleGroup,
-		Title:                a.Title,
-		Condition:            a.Condition,
-		Data:                 AlertQueriesFromApiAlertQueries(a.Data),
-		Updated:              a.Updated,
-		NoDataState:          models.NoDataState(a.NoDataState),          // TODO there must be a validation
-		ExecErrState:         models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
-		For:                  time.Duration(a.For),
-		Annotations:          a.Annotations,
-		Labels:               a.Labels,
-		IsPaused:             a.IsPaused,
-		NotificationSettings: NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings),
+		ID:                      a.ID,
+		UID:                     a.UID,
+		OrgID:                   a.OrgID,
+		NamespaceUID:            a.FolderUID,
+		RuleGroup:               a.RuleGroup,
+		Title:                   a.Title,
+		Condition:               a.Condition,
+		Data:                    AlertQueriesFromApiAlertQueries(a.Data),
+		Updated:                 a.Updated,
+		Version:                 a.Version,
+		RuleGroupIndex:          a.RuleGroupIndex,
+		NoDataState:             models.NoDataState(a.NoDataState),          // TODO there must be a validation
+		ExecErrState:            models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
+		For:                     time.Duration(a.For),
+		Annotations:             a.Annotations,
+		Labels:                  a.Labels,
+		IsPaused:                a.IsPaused,
+		NotificationSettings:    NotificationSettingsFromAlertRuleNotificationSettings(a.NotificationSettings, a.AdditionalNotificationSettings),
+		ExpiresAt:               a.ExpiresAt,
+		IntervalOverrideSeconds: a.IntervalOverride,
+		EvaluationTimeout:       time.Duration(a.EvaluationTimeout),
+		Record:                  RecordFromApiRecord(a.Record),
 	}, nil
 }
 
@@ -43,10 +50,12 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		FolderUID:            rule.NamespaceUID,
 		RuleGroup:            rule.RuleGroup,
 		Title:                rule.Title,
-		For:                  model.Duration(rule.For),
+		For:                  definitions.DurationOrSeconds(rule.For),
 		Condition:            rule.Condition,
 		Data:                 ApiAlertQueriesFromAlertQueries(rule.Data),
 		Updated:              rule.Updated,
+		Version:              rule.Version,
+		RuleGroupIndex:       rule.RuleGroupIndex,
 		NoDataState:          definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
 		ExecErrState:         definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
 		Annotations:          rule.Annotations,
@@ -54,6 +63,38 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		Provenance:           definitions.Provenance(provenance), // TODO validate enum conversion?
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
+
+		AdditionalNotificationSettings: AdditionalAlertRuleNotificationSettingsFromNotificationSettings(rule.NotificationSettings),
+		ExpiresAt:                      rule.ExpiresAt,
+		IntervalOverride:               rule.IntervalOverrideSeconds,
+		EvaluationTimeout:              definitions.DurationOrSeconds(rule.EvaluationTimeout),
+		Record:                         ApiRecordFromRecord(rule.Record),
+		EffectivePendingPeriod:         model.Duration(rule.EffectivePendingPeriod()),
+	}
+}
+
+// RecordFromApiRecord converts definitions.Record to models.Record. It returns the zero record if the rule is not a
+// recording rule.
+func RecordFromApiRecord(r *definitions.Record) models.Record {
+	if r == nil {
+		return models.Record{}
+	}
+	return models.Record{
+		Metric:              r.Metric,
+		From:                r.From,
+		TargetDatasourceUID: r.TargetDatasourceUID,
+	}
+}
+
+// ApiRecordFromRecord converts models.Record to definitions.Record. It returns nil if the rule is not a recording rule.
+func ApiRecordFromRecord(r models.Record) *definitions.Record {
+	if r == (models.Record{}) {
+		return nil
+	}
+	return &definitions.Record{
+		Metric:              r.Metric,
+		From:                r.From,
+		TargetDatasourceUID: r.TargetDatasourceUID,
 	}
 }
 
@@ -77,7 +118,7 @@ func AlertQueriesFromApiAlertQueries(queries []definitions.AlertQuery) []models.
 				From: models.Duration(q.RelativeTimeRange.From),
 				To:   models.Duration(q.RelativeTimeRange.To),
 			},
-			DatasourceUID: q.DatasourceUID,
+			DatasourceUID: models.NormalizeDatasourceUID(q.DatasourceUID),
 			Model:         q.Model,
 		})
 	}
@@ -104,9 +145,17 @@ func ApiAlertQueriesFromAlertQueries(queries []models.AlertQuery) []definitions.
 
 func AlertRuleGroupFromApiAlertRuleGroup(a definitions.AlertRuleGroup) (models.AlertRuleGroup, error) {
 	ruleGroup := models.AlertRuleGroup{
-		Title:     a.Title,
-		FolderUID: a.FolderUID,
-		Interval:  a.Interval,
+		Title:         a.Title,
+		FolderUID:     a.FolderUID,
+		Interval:      int64(a.Interval),
+		ShardAffinity: a.ShardAffinity,
+		IncidentHooks: IncidentHooksFromApiIncidentHooks(a.IncidentHooks),
+		ManagedBy:     ManagedByFromApiManagedBy(a.ManagedBy),
+		ExpiresAt:     a.ExpiresAt,
+	}
+	if a.DataAvailability != nil {
+		ruleGroup.DataAvailabilityPeriod = time.Duration(a.DataAvailability.Period)
+		ruleGroup.DataAvailabilityDelay = time.Duration(a.DataAvailability.Delay)
 	}
 	for i := range a.Rules {
 		converted, err := AlertRuleFromProvisionedAlertRule(a.Rules[i])
@@ -124,13 +173,147 @@ func ApiAlertRuleGroupFromAlertRuleGroup(d models.AlertRuleGroup) definitions.Al
 		rules = append(rules, ProvisionedAlertRuleFromAlertRule(d.Rules[i], d.Provenance))
 	}
 	return definitions.AlertRuleGroup{
-		Title:     d.Title,
-		FolderUID: d.FolderUID,
-		Interval:  d.Interval,
-		Rules:     rules,
+		Title:            d.Title,
+		FolderUID:        d.FolderUID,
+		Interval:         definitions.SecondsOrDuration(d.Interval),
+		DataAvailability: ApiDataAvailabilityFromAlertRuleGroup(d),
+		ShardAffinity:    d.ShardAffinity,
+		IncidentHooks:    ApiIncidentHooksFromIncidentHooks(d.IncidentHooks),
+		ManagedBy:        ApiManagedByFromManagedBy(d.ManagedBy),
+		Archived:         d.Archived,
+		Rules:            rules,
+	}
+}
+
+// ApiServerDefaultsFromServerDefaults creates definitions.ServerDefault DTOs from the values that the server wrote to
+// the rules of a replaced rule group.
+func ApiServerDefaultsFromServerDefaults(defaults []provisioning.ServerDefault) []definitions.ServerDefault {
+	if len(defaults) == 0 {
+		return nil
+	}
+	result := make([]definitions.ServerDefault, 0, len(defaults))
+	for _, d := range defaults {
+		result = append(result, definitions.ServerDefault{
+			RuleIndex: d.RuleIndex,
+			Field:     d.Field,
+			Value:     d.Value,
+		})
+	}
+	return result
+}
+
+// ApiRuleGroupJobFromRuleGroupJob creates a definitions.RuleGroupJob DTO from models.RuleGroupJob.
+func ApiRuleGroupJobFromRuleGroupJob(j models.RuleGroupJob) definitions.RuleGroupJob {
+	return definitions.RuleGroupJob{
+		UID:       j.UID,
+		FolderUID: j.NamespaceUID,
+		RuleGroup: j.RuleGroup,
+		Status:    string(j.Status),
+		Changes:   j.Changes,
+		Applied:   j.Applied,
+		Error:     j.Error,
+		Created:   j.Created,
+		Updated:   j.Updated,
+	}
+}
+
+// ApiWebhookDeliveryFromWebhookDelivery creates a definitions.WebhookDelivery DTO from models.WebhookDelivery.
+func ApiWebhookDeliveryFromWebhookDelivery(d models.WebhookDelivery) definitions.WebhookDelivery {
+	return definitions.WebhookDelivery{
+		UID:      d.UID,
+		Type:     d.Type,
+		Event:    definitions.RawMessage(d.Event),
+		Attempts: d.Attempts,
+		Error:    d.Error,
+		Created:  d.Created,
+		Updated:  d.Updated,
+	}
+}
+
+// ApiRuleGroupCostEstimateFromRuleGroupCost creates a definitions.RuleGroupCostEstimate DTO from models.RuleGroupCost.
+func ApiRuleGroupCostEstimateFromRuleGroupCost(c models.RuleGroupCost) definitions.RuleGroupCostEstimate {
+	rules := make([]definitions.RuleCostEstimate, 0, len(c.Rules))
+	for _, r := range c.Rules {
+		queries := make([]definitions.QueryCostEstimate, 0, len(r.Queries))
+		for _, q := range r.Queries {
+			queries = append(queries, definitions.QueryCostEstimate{
+				RefID:          q.RefID,
+				DatasourceUID:  q.DatasourceUID,
+				DatasourceType: q.DatasourceType,
+				TimeRange:      model.Duration(q.TimeRange),
+				Step:           model.Duration(q.Step),
+				DataPoints:     q.DataPoints,
+				Cost:           q.Cost,
+			})
+		}
+		rules = append(rules, definitions.RuleCostEstimate{
+			UID:      r.UID,
+			Title:    r.Title,
+			IsPaused: r.IsPaused,
+			Cost:     r.Cost,
+			Queries:  queries,
+		})
+	}
+	return definitions.RuleGroupCostEstimate{
+		CostPerEvaluation: c.CostPerEvaluation,
+		EvaluationsPerDay: c.EvaluationsPerDay,
+		QueriesPerDay:     c.QueriesPerDay,
+		Rules:             rules,
 	}
 }
 
+// ApiDataAvailabilityFromAlertRuleGroup creates a definitions.DataAvailability DTO from the data availability window
+// of the group, or returns nil if the group has none.
+func ApiDataAvailabilityFromAlertRuleGroup(d models.AlertRuleGroup) *definitions.DataAvailability {
+	if d.DataAvailabilityPeriod == 0 {
+		return nil
+	}
+	return &definitions.DataAvailability{
+		Period: model.Duration(d.DataAvailabilityPeriod),
+		Delay:  model.Duration(d.DataAvailabilityDelay),
+	}
+}
+
+// IncidentHooksFromApiIncidentHooks creates []models.IncidentHook from []definitions.IncidentHook.
+func IncidentHooksFromApiIncidentHooks(hooks []definitions.IncidentHook) []models.IncidentHook {
+	if len(hooks) == 0 {
+		return nil
+	}
+	result := make([]models.IncidentHook, 0, len(hooks))
+	for _, h := range hooks {
+		result = append(result, models.IncidentHook{Name: h.Name, URL: h.URL, AuthorizationCredentials: h.AuthorizationCredentials})
+	}
+	return result
+}
+
+// ApiIncidentHooksFromIncidentHooks creates []definitions.IncidentHook DTOs from []models.IncidentHook.
+func ApiIncidentHooksFromIncidentHooks(hooks []models.IncidentHook) []definitions.IncidentHook {
+	if len(hooks) == 0 {
+		return nil
+	}
+	result := make([]definitions.IncidentHook, 0, len(hooks))
+	for _, h := range hooks {
+		result = append(result, definitions.IncidentHook{Name: h.Name, URL: h.URL, AuthorizationCredentials: h.AuthorizationCredentials})
+	}
+	return result
+}
+
+// ManagedByFromApiManagedBy converts definitions.ManagedBy to models.ManagedBy.
+func ManagedByFromApiManagedBy(m *definitions.ManagedBy) *models.ManagedBy {
+	if m == nil {
+		return nil
+	}
+	return &models.ManagedBy{Title: m.Title, URL: m.URL}
+}
+
+// ApiManagedByFromManagedBy converts models.ManagedBy to definitions.ManagedBy.
+func ApiManagedByFromManagedBy(m *models.ManagedBy) *definitions.ManagedBy {
+	if m == nil {
+		return nil
+	}
+	return &definitions.ManagedBy{Title: m.Title, URL: m.URL}
+}
+
 // AlertingFileExportFromAlertRuleGroupWithFolderTitle creates an definitions.AlertingFileExport DTO from []models.AlertRuleGroupWithFolderTitle.
 func AlertingFileExportFromAlertRuleGroupWithFolderTitle(groups []models.AlertRuleGroupWithFolderTitle) (definitions.AlertingFileExport, error) {
 	f := definitions.AlertingFileExport{APIVersion: 1}
@@ -144,6 +327,43 @@ func AlertingFileExportFromAlertRuleGroupWithFolderTitle(groups []models.AlertRu
 	return f, nil
 }
 
+// KeyAlertingFileExportByFolderUID changes the rule groups of the export to refer to their folders by UID instead of
+// title. The title is kept as an assertion that is verified when the file is provisioned.
+func KeyAlertingFileExportByFolderUID(e *definitions.AlertingFileExport) {
+	for i := range e.Groups {
+		e.Groups[i].FolderUIDKey = e.Groups[i].FolderUID
+		e.Groups[i].FolderTitle = e.Groups[i].Folder
+		e.Groups[i].Folder = ""
+	}
+}
+
+// KeyAlertingFileExportByFolderPath changes the rule groups of the export to refer to their folders by the paths of
+// the groups, which were created from the groups, instead of title.
+func KeyAlertingFileExportByFolderPath(e *definitions.AlertingFileExport, groups []models.AlertRuleGroupWithFolderTitle) {
+	for i := range e.Groups {
+		e.Groups[i].FolderPath = groups[i].FolderPath
+		e.Groups[i].Folder = ""
+	}
+}
+
+// IndexAlertingFileExportRules sets the index of each rule of the export to its position in its rule group, so that
+// the order of the rules is kept even if the rules are reordered in the file.
+func IndexAlertingFileExportRules(e *definitions.AlertingFileExport) {
+	for i := range e.Groups {
+		for j := range e.Groups[i].Rules {
+			e.Groups[i].Rules[j].RuleGroupIndex = j + 1
+		}
+	}
+}
+
+// VersionAlertingFileExportGroups sets the version of each rule group of the export, which was created from the
+// groups.
+func VersionAlertingFileExportGroups(e *definitions.AlertingFileExport, groups []models.AlertRuleGroupWithFolderTitle) {
+	for i := range e.Groups {
+		e.Groups[i].Version = models.NewRuleGroupVersion(groups[i].Rules).String()
+	}
+}
+
 // AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle creates a definitions.AlertRuleGroupExport DTO from models.AlertRuleGroup.
 func AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle(d models.AlertRuleGroupWithFolderTitle) (definitions.AlertRuleGroupExport, error) {
 	rules := make([]definitions.AlertRuleExport, 0, len(d.Rules))
@@ -155,13 +375,16 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle(d models.AlertRuleGro
 		rules = append(rules, alert)
 	}
 	return definitions.AlertRuleGroupExport{
-		OrgID:           d.OrgID,
-		Name:            d.Title,
-		Folder:          d.FolderTitle,
-		FolderUID:       d.FolderUID,
-		Interval:        model.Duration(time.Duration(d.Interval) * time.Second),
-		IntervalSeconds: d.Interval,
-		Rules:           rules,
+		OrgID:            d.OrgID,
+		Name:             d.Title,
+		Folder:           d.FolderTitle,
+		FolderUID:        d.FolderUID,
+		Interval:         model.Duration(time.Duration(d.Interval) * time.Second),
+		IntervalSeconds:  d.Interval,
+		DataAvailability: ApiDataAvailabilityFromAlertRuleGroup(*d.AlertRuleGroup),
+		ShardAffinity:    d.ShardAffinity,
+		IncidentHooks:    ApiIncidentHooksFromIncidentHooks(d.IncidentHooks),
+		Rules:            rules,
 	}, nil
 }
 
@@ -188,6 +411,11 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		ExecErrState:         definitions.ExecutionErrorState(rule.ExecErrState),
 		IsPaused:             rule.IsPaused,
 		NotificationSettings: AlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
+
+		AdditionalNotificationSettings: AdditionalAlertRuleNotificationSettingsExportFromNotificationSettings(rule.NotificationSettings),
+		IntervalOverride:               rule.IntervalOverrideSeconds,
+		EvaluationTimeout:              model.Duration(rule.EvaluationTimeout),
+		Record:                         ApiRecordFromRecord(rule.Record),
 	}
 	if rule.For.Seconds() > 0 {
 		result.ForString = util.Pointer(model.Duration(rule.For).String())
@@ -238,6 +466,7 @@ func AlertingFileExportFromEmbeddedContactPoints(orgID int64, ecps []definitions
 			c = &definitions.ContactPointExport{
 				OrgID:     orgID,
 				Name:      ecp.Name,
+				RateLimit: ecp.RateLimit,
 				Receivers: make([]definitions.ReceiverExport, 0),
 			}
 			cache[ecp.Name] = c
@@ -268,6 +497,7 @@ func ReceiverExportFromEmbeddedContactPoint(contact definitions.EmbeddedContactP
 		Type:                  contact.Type,
 		Settings:              raw,
 		DisableResolveMessage: contact.DisableResolveMessage,
+		Disabled:              contact.Disabled,
 	}, nil
 }
 
@@ -283,6 +513,123 @@ func AlertingFileExportFromRoute(orgID int64, route definitions.Route) (definiti
 	return f, nil
 }
 
+// AlertingFileExportFromAlertmanagerRouting creates a definitions.AlertingFileExport DTO from definitions.AlertmanagerRouting.
+func AlertingFileExportFromAlertmanagerRouting(orgID int64, routing definitions.AlertmanagerRouting) definitions.AlertingFileExport {
+	routing.Provenance = ""
+	return definitions.AlertingFileExport{
+		APIVersion: 1,
+		AlertmanagerRouting: []definitions.AlertmanagerRoutingExport{{
+			OrgID:               orgID,
+			AlertmanagerRouting: routing,
+		}},
+	}
+}
+
+// ConfigSnapshotsFromModels creates definitions.ConfigSnapshots DTO from models.ConfigSnapshot.
+func ConfigSnapshotsFromModels(snapshots []*models.ConfigSnapshot) definitions.ConfigSnapshots {
+	result := make(definitions.ConfigSnapshots, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		result = append(result, ConfigSnapshotFromModel(snapshot))
+	}
+	return result
+}
+
+// ConfigSnapshotFromModel creates a definitions.ConfigSnapshot DTO from models.ConfigSnapshot.
+func ConfigSnapshotFromModel(snapshot *models.ConfigSnapshot) definitions.ConfigSnapshot {
+	return definitions.ConfigSnapshot{
+		ID:        snapshot.ID,
+		Version:   snapshot.Version,
+		Hash:      snapshot.Hash,
+		Size:      snapshot.Size,
+		CreatedAt: time.Unix(snapshot.CreatedAt, 0).UTC(),
+	}
+}
+
+// DeletedAlertRulesFromModels creates definitions.DeletedAlertRules DTO from models.DeletedAlertRule.
+func DeletedAlertRulesFromModels(rules []*models.DeletedAlertRule) definitions.DeletedAlertRules {
+	result := make(definitions.DeletedAlertRules, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, definitions.DeletedAlertRule{
+			UID:        rule.RuleUID,
+			Title:      rule.Title,
+			FolderUID:  rule.NamespaceUID,
+			RuleGroup:  rule.RuleGroup,
+			Provenance: definitions.Provenance(rule.Provenance),
+			DeletedAt:  time.Unix(rule.DeletedAt, 0).UTC(),
+		})
+	}
+	return result
+}
+
+// AlertRuleTemplateFromApiAlertRuleTemplate converts definitions.AlertRuleTemplate to models.AlertRuleTemplate.
+func AlertRuleTemplateFromApiAlertRuleTemplate(t definitions.AlertRuleTemplate) (models.AlertRuleTemplate, error) {
+	rule, err := AlertRuleFromProvisionedAlertRule(t.Rule)
+	if err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	parameters := make([]models.AlertRuleTemplateParameter, 0, len(t.Parameters))
+	for _, p := range t.Parameters {
+		parameters = append(parameters, models.AlertRuleTemplateParameter{
+			Name:        p.Name,
+			Description: p.Description,
+			Default:     p.Default,
+		})
+	}
+	return models.AlertRuleTemplate{
+		UID:        t.UID,
+		Title:      t.Title,
+		Parameters: parameters,
+		Rule:       rule,
+	}, nil
+}
+
+// ApiAlertRuleTemplateFromAlertRuleTemplate creates a definitions.AlertRuleTemplate DTO from models.AlertRuleTemplate.
+func ApiAlertRuleTemplateFromAlertRuleTemplate(t models.AlertRuleTemplate) definitions.AlertRuleTemplate {
+	parameters := make([]definitions.AlertRuleTemplateParameter, 0, len(t.Parameters))
+	for _, p := range t.Parameters {
+		parameters = append(parameters, definitions.AlertRuleTemplateParameter{
+			Name:        p.Name,
+			Description: p.Description,
+			Default:     p.Default,
+		})
+	}
+	return definitions.AlertRuleTemplate{
+		UID:        t.UID,
+		Title:      t.Title,
+		Parameters: parameters,
+		Rule:       ProvisionedAlertRuleFromAlertRule(t.Rule, models.ProvenanceNone),
+		Updated:    t.Updated,
+	}
+}
+
+// AlertRuleSyncStatusFromModel creates a definitions.AlertRuleSyncStatus DTO from provisioning.RuleSyncStatus.
+func AlertRuleSyncStatusFromModel(status provisioning.RuleSyncStatus) definitions.AlertRuleSyncStatus {
+	result := definitions.AlertRuleSyncStatus{
+		Enabled: status.Enabled,
+		Error:   status.Error,
+		Groups:  make([]definitions.AlertRuleGroupSyncStatus, 0, len(status.Groups)),
+	}
+	if !status.LastSync.IsZero() {
+		lastSync := status.LastSync.UTC()
+		result.LastSync = &lastSync
+	}
+	for _, group := range status.Groups {
+		result.Groups = append(result.Groups, definitions.AlertRuleGroupSyncStatus{
+			FolderUID: group.FolderUID,
+			RuleGroup: group.RuleGroup,
+			File:      group.File,
+			LastSync:  group.LastSync.UTC(),
+			Result:    string(group.Result),
+			Error:     group.Error,
+			Created:   group.Created,
+			Updated:   group.Updated,
+			Deleted:   group.Deleted,
+			Drift:     group.Drift,
+		})
+	}
+	return result
+}
+
 // RouteExportFromRoute creates a definitions.RouteExport DTO from definitions.Route.
 func RouteExportFromRoute(route *definitions.Route) *definitions.RouteExport {
 	toStringIfNotNil := func(d *model.Duration) *string {
@@ -377,29 +724,60 @@ func MuteTimingIntervalToMuteTimeIntervalHclExport(m definitions.MuteTimeInterva
 	return result, err
 }
 
-// AlertRuleNotificationSettingsFromNotificationSettings converts []models.NotificationSettings to definitions.AlertRuleNotificationSettings
+// AlertRuleNotificationSettingsFromNotificationSettings converts the first of []models.NotificationSettings to definitions.AlertRuleNotificationSettings
 func AlertRuleNotificationSettingsFromNotificationSettings(ns []models.NotificationSettings) *definitions.AlertRuleNotificationSettings {
 	if len(ns) == 0 {
 		return nil
 	}
-	m := ns[0]
-	return &definitions.AlertRuleNotificationSettings{
+	m := alertRuleNotificationSettingsFromNotificationSettings(ns[0])
+	return &m
+}
+
+// AdditionalAlertRuleNotificationSettingsFromNotificationSettings converts all but the first of []models.NotificationSettings to []definitions.AlertRuleNotificationSettings
+func AdditionalAlertRuleNotificationSettingsFromNotificationSettings(ns []models.NotificationSettings) []definitions.AlertRuleNotificationSettings {
+	if len(ns) < 2 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleNotificationSettings, 0, len(ns)-1)
+	for _, m := range ns[1:] {
+		result = append(result, alertRuleNotificationSettingsFromNotificationSettings(m))
+	}
+	return result
+}
+
+func alertRuleNotificationSettingsFromNotificationSettings(m models.NotificationSettings) definitions.AlertRuleNotificationSettings {
+	return definitions.AlertRuleNotificationSettings{
 		Receiver:          m.Receiver,
 		GroupBy:           m.GroupBy,
-		GroupWait:         m.GroupWait,
-		GroupInterval:     m.GroupInterval,
-		RepeatInterval:    m.RepeatInterval,
+		GroupWait:         (*definitions.DurationOrSeconds)(m.GroupWait),
+		GroupInterval:     (*definitions.DurationOrSeconds)(m.GroupInterval),
+		RepeatInterval:    (*definitions.DurationOrSeconds)(m.RepeatInterval),
 		MuteTimeIntervals: m.MuteTimeIntervals,
 	}
 }
 
-// AlertRuleNotificationSettingsFromNotificationSettings converts []models.NotificationSettings to definitions.AlertRuleNotificationSettingsExport
+// AlertRuleNotificationSettingsExportFromNotificationSettings converts the first of []models.NotificationSettings to definitions.AlertRuleNotificationSettingsExport
 func AlertRuleNotificationSettingsExportFromNotificationSettings(ns []models.NotificationSettings) *definitions.AlertRuleNotificationSettingsExport {
 	if len(ns) == 0 {
 		return nil
 	}
-	m := ns[0]
+	m := alertRuleNotificationSettingsExportFromNotificationSettings(ns[0])
+	return &m
+}
 
+// AdditionalAlertRuleNotificationSettingsExportFromNotificationSettings converts all but the first of []models.NotificationSettings to []definitions.AlertRuleNotificationSettingsExport
+func AdditionalAlertRuleNotificationSettingsExportFromNotificationSettings(ns []models.NotificationSettings) []definitions.AlertRuleNotificationSettingsExport {
+	if len(ns) < 2 {
+		return nil
+	}
+	result := make([]definitions.AlertRuleNotificationSettingsExport, 0, len(ns)-1)
+	for _, m := range ns[1:] {
+		result = append(result, alertRuleNotificationSettingsExportFromNotificationSettings(m))
+	}
+	return result
+}
+
+func alertRuleNotificationSettingsExportFromNotificationSettings(m models.NotificationSettings) definitions.AlertRuleNotificationSettingsExport {
 	toStringIfNotNil := func(d *model.Duration) *string {
 		if d == nil {
 			return nil
@@ -408,7 +786,7 @@ func AlertRuleNotificationSettingsExportFromNotificationSettings(ns []models.Not
 		return &s
 	}
 
-	return &definitions.AlertRuleNotificationSettingsExport{
+	return definitions.AlertRuleNotificationSettingsExport{
 		Receiver:          m.Receiver,
 		GroupBy:           m.GroupBy,
 		GroupWait:         toStringIfNotNil(m.GroupWait),
@@ -418,19 +796,29 @@ func AlertRuleNotificationSettingsExportFromNotificationSettings(ns []models.Not
 	}
 }
 
-// NotificationSettingsFromAlertRuleNotificationSettings converts definitions.AlertRuleNotificationSettings to []models.NotificationSettings
-func NotificationSettingsFromAlertRuleNotificationSettings(ns *definitions.AlertRuleNotificationSettings) []models.NotificationSettings {
-	if ns == nil {
+// NotificationSettingsFromAlertRuleNotificationSettings converts definitions.AlertRuleNotificationSettings and the
+// additional settings to []models.NotificationSettings
+func NotificationSettingsFromAlertRuleNotificationSettings(ns *definitions.AlertRuleNotificationSettings, additional []definitions.AlertRuleNotificationSettings) []models.NotificationSettings {
+	if ns == nil && len(additional) == 0 {
 		return nil
 	}
-	return []models.NotificationSettings{
-		{
-			Receiver:          ns.Receiver,
-			GroupBy:           ns.GroupBy,
-			GroupWait:         ns.GroupWait,
-			GroupInterval:     ns.GroupInterval,
-			RepeatInterval:    ns.RepeatInterval,
-			MuteTimeIntervals: ns.MuteTimeIntervals,
-		},
+	result := make([]models.NotificationSettings, 0, len(additional)+1)
+	if ns != nil {
+		result = append(result, notificationSettingsFromAlertRuleNotificationSettings(*ns))
+	}
+	for _, s := range additional {
+		result = append(result, notificationSettingsFromAlertRuleNotificationSettings(s))
+	}
+	return result
+}
+
+func notificationSettingsFromAlertRuleNotificationSettings(ns definitions.AlertRuleNotificationSettings) models.NotificationSettings {
+	return models.NotificationSettings{
+		Receiver:          ns.Receiver,
+		GroupBy:           ns.GroupBy,
+		GroupWait:         (*model.Duration)(ns.GroupWait),
+		GroupInterval:     (*model.Duration)(ns.GroupInterval),
+		RepeatInterval:    (*model.Duration)(ns.RepeatInterval),
+		MuteTimeIntervals: ns.MuteTimeIntervals,
 	}
 }
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// gzipExportResponse is a response that streams an export in JSON or YAML compressed with gzip.
+type gzipExportResponse struct {
+	status int
+	header http.Header
+	encode func(w io.Writer) error
+}
+
+func newGzipExportResponse(params definitions.ExportQueryParams, body definitions.AlertingFileExport, etag string) gzipExportResponse {
+	header := make(http.Header)
+	encode := func(w io.Writer) error {
+		return encodeJSONExport(w, body)
+	}
+	header.Set("Content-Type", "application/json")
+	if params.Format == "yaml" {
+		encode = func(w io.Writer) error {
+			enc := yaml.NewEncoder(w)
+			if err := enc.Encode(body); err != nil {
+				return err
+			}
+			return enc.Close()
+		}
+		// As of now, application/yaml is downloaded by default in chrome regardless of Content-Disposition, so we use text/yaml instead.
+		header.Set("Content-Type", "text/yaml")
+	}
+	if params.Download {
+		if params.Format == "yaml" {
+			header.Set("Content-Type", "application/yaml")
+		}
+		header.Set("Content-Disposition", fmt.Sprintf(`attachment;filename="export.%s"`, params.Format))
+	}
+	if etag != "" {
+		header.Set("ETag", etag)
+	}
+	return gzipExportResponse{
+		status: http.StatusOK,
+		header: header,
+		encode: encode,
+	}
+}
+
+// Status gets the response's status.
+func (r gzipExportResponse) Status() int {
+	return r.status
+}
+
+// Body gets the response's body. It is always empty because the body is streamed when the response is written.
+func (r gzipExportResponse) Body() []byte {
+	return nil
+}
+
+// WriteTo writes the response to the provided context.
+func (r gzipExportResponse) WriteTo(ctx *contextmodel.ReqContext) {
+	header := ctx.Resp.Header()
+	for k, v := range r.header {
+		header[k] = v
+	}
+	// If gzip is enabled for the whole server, the response writer already compresses the response.
+	if header.Get("Content-Encoding") != "" {
+		ctx.Resp.WriteHeader(r.status)
+		if err := r.encode(ctx.Resp); err != nil {
+			ctx.Logger.Error("Failed to write export", "error", err)
+		}
+		return
+	}
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	ctx.Resp.WriteHeader(r.status)
+	if err := provisioning.WriteGzip(ctx.Resp, r.encode); err != nil {
+		ctx.Logger.Error("Failed to write compressed export", "error", err)
+	}
+}
+
+// encodeJSONExport writes the export as json.Marshal does, but marshals the items of its lists one at a time, so that
+// a large export is never held in memory serialized as a whole.
+func encodeJSONExport(w io.Writer, body definitions.AlertingFileExport) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, `{"apiVersion":%d`, body.APIVersion); err != nil {
+		return err
+	}
+	if err := encodeJSONList(bw, "groups", body.Groups); err != nil {
+		return err
+	}
+	if err := encodeJSONList(bw, "contactPoints", body.ContactPoints); err != nil {
+		return err
+	}
+	if err := encodeJSONList(bw, "policies", body.Policies); err != nil {
+		return err
+	}
+	if err := encodeJSONList(bw, "muteTimes", body.MuteTimings); err != nil {
+		return err
+	}
+	if err := encodeJSONList(bw, "alertmanagerRouting", body.AlertmanagerRouting); err != nil {
+		return err
+	}
+	if err := bw.WriteByte('}'); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// encodeJSONList writes the list as a field of a JSON object. Empty lists are omitted.
+func encodeJSONList[T any](w *bufio.Writer, name string, items []T) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, `,"%s":[`, name); err != nil {
+		return err
+	}
+	for i := range items {
+		if i > 0 {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		b, err := json.Marshal(items[i])
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return w.WriteByte(']')
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestEncodeJSONExport(t *testing.T) {
+	bodies := map[string]definitions.AlertingFileExport{
+		"empty": {APIVersion: 1},
+		"all lists": {
+			APIVersion: 1,
+			Groups: []definitions.AlertRuleGroupExport{
+				{OrgID: 1, Name: "group-1", FolderUID: "folder-uid"},
+				{OrgID: 1, Name: "group-2", FolderUID: "folder-uid"},
+			},
+			ContactPoints: []definitions.ContactPointExport{{OrgID: 1, Name: "contact-point"}},
+			Policies:      []definitions.NotificationPolicyExport{{OrgID: 1, RouteExport: &definitions.RouteExport{Receiver: "contact-point"}}},
+			MuteTimings:   []definitions.MuteTimeIntervalExport{{OrgID: 1}},
+			AlertmanagerRouting: []definitions.AlertmanagerRoutingExport{
+				{OrgID: 1},
+			},
+			HeadComment: "not in json",
+		},
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			expected, err := json.Marshal(body)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			require.NoError(t, encodeJSONExport(&buf, body))
+			require.Equal(t, string(expected), buf.String())
+		})
+	}
+}
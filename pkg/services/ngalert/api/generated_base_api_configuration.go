@@ -16,7 +16,6 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
-	"github.com/grafana/grafana/pkg/web"
 )
 
 type ConfigurationApi interface {
@@ -42,7 +41,7 @@ func (f *ConfigurationApiHandler) RouteGetStatus(ctx *contextmodel.ReqContext) r
 func (f *ConfigurationApiHandler) RoutePostNGalertConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.PostableNGalertConfig{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePostNGalertConfig(ctx, conf)
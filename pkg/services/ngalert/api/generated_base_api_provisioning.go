@@ -22,6 +22,7 @@ import (
 type ProvisioningApi interface {
 	RouteDeleteAlertRule(*contextmodel.ReqContext) response.Response
 	RouteDeleteAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RouteDeleteAlertRuleTemplate(*contextmodel.ReqContext) response.Response
 	RouteDeleteContactpoints(*contextmodel.ReqContext) response.Response
 	RouteDeleteMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteDeleteTemplate(*contextmodel.ReqContext) response.Response
@@ -31,26 +32,79 @@ type ProvisioningApi interface {
 	RouteGetAlertRuleExport(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroupExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleGroupTags(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleSyncStatus(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTags(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleTemplates(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRules(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRulesQuota(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRulesUsage(*contextmodel.ReqContext) response.Response
+	RouteGetAlertmanagerRouting(*contextmodel.ReqContext) response.Response
+	RouteGetAlertmanagerRoutingExport(*contextmodel.ReqContext) response.Response
+	RouteGetConfigSnapshots(*contextmodel.ReqContext) response.Response
+	RouteGetContactPointDeliveries(*contextmodel.ReqContext) response.Response
+	RouteGetContactPointTags(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
 	RouteGetContactpointsExport(*contextmodel.ReqContext) response.Response
+	RouteGetDeletedAlertRules(*contextmodel.ReqContext) response.Response
+	RouteGetEffectivePolicy(*contextmodel.ReqContext) response.Response
+	RouteGetFolderAnnotations(*contextmodel.ReqContext) response.Response
+	RouteGetFolderEvaluation(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTree(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTreeExport(*contextmodel.ReqContext) response.Response
+	RouteGetProvisioningFileSchema(*contextmodel.ReqContext) response.Response
+	RouteGetRuleGroupJob(*contextmodel.ReqContext) response.Response
+	RouteGetRuleGroupsNoiseReport(*contextmodel.ReqContext) response.Response
+	RouteGetRuleTitleUniqueness(*contextmodel.ReqContext) response.Response
 	RouteGetTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetTemplates(*contextmodel.ReqContext) response.Response
+	RouteGetWebhookDeadLetters(*contextmodel.ReqContext) response.Response
 	RoutePostAlertRule(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleGroupArchive(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleGroupClone(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleGroupCostEstimate(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleGroupGenerate(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleGroupJob(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleGroupMove(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleGroupUnarchive(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleRestore(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTemplateInstantiate(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRulesMetadata(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRulesPause(*contextmodel.ReqContext) response.Response
+	RoutePostBulkContactPointSecret(*contextmodel.ReqContext) response.Response
+	RoutePostBulkPolicy(*contextmodel.ReqContext) response.Response
+	RoutePostBulkRuleGroups(*contextmodel.ReqContext) response.Response
+	RoutePostConfigSnapshot(*contextmodel.ReqContext) response.Response
+	RoutePostConfigSnapshotRestore(*contextmodel.ReqContext) response.Response
 	RoutePostContactpoints(*contextmodel.ReqContext) response.Response
 	RoutePostMuteTiming(*contextmodel.ReqContext) response.Response
+	RoutePostPolicyTreeMerge(*contextmodel.ReqContext) response.Response
+	RoutePostPrometheusRuleGroupImport(*contextmodel.ReqContext) response.Response
+	RoutePostWebhookDeadLetterReplay(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleGroupInterval(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleGroupProvenance(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleGroupTags(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleProvenance(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleTags(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleTemplate(*contextmodel.ReqContext) response.Response
+	RoutePutAlertmanagerRouting(*contextmodel.ReqContext) response.Response
+	RoutePutContactPointTags(*contextmodel.ReqContext) response.Response
 	RoutePutContactpoint(*contextmodel.ReqContext) response.Response
+	RoutePutFolderAnnotations(*contextmodel.ReqContext) response.Response
+	RoutePutFolderEvaluation(*contextmodel.ReqContext) response.Response
 	RoutePutMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutPolicyTree(*contextmodel.ReqContext) response.Response
+	RoutePutRuleTitleUniqueness(*contextmodel.ReqContext) response.Response
 	RoutePutTemplate(*contextmodel.ReqContext) response.Response
 	RouteResetPolicyTree(*contextmodel.ReqContext) response.Response
+	RouteResetRuleTitleUniqueness(*contextmodel.ReqContext) response.Response
+	RouteSearchAlertRules(*contextmodel.ReqContext) response.Response
 }
 
 func (f *ProvisioningApiHandler) RouteDeleteAlertRule(ctx *contextmodel.ReqContext) response.Response {
@@ -64,6 +118,11 @@ func (f *ProvisioningApiHandler) RouteDeleteAlertRuleGroup(ctx *contextmodel.Req
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteDeleteAlertRuleGroup(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteAlertRuleTemplate(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -109,18 +168,81 @@ func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupExport(ctx *contextmodel.
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroupExport(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupTags(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRouteGetAlertRuleGroupTags(ctx, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleSyncStatus(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRuleSyncStatus(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTags(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTags(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetAlertRuleTemplate(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRuleTemplates(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRules(ctx)
 }
 func (f *ProvisioningApiHandler) RouteGetAlertRulesExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRulesExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRulesQuota(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRulesQuota(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertRulesUsage(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertRulesUsage(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertmanagerRouting(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertmanagerRouting(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertmanagerRoutingExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertmanagerRoutingExport(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetConfigSnapshots(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetConfigSnapshots(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetContactPointDeliveries(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetContactPointDeliveries(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetContactPointTags(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetContactPointTags(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpoints(ctx)
 }
 func (f *ProvisioningApiHandler) RouteGetContactpointsExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpointsExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetDeletedAlertRules(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetDeletedAlertRules(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetEffectivePolicy(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetEffectivePolicy(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetFolderAnnotations(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	return f.handleRouteGetFolderAnnotations(ctx, folderUIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetFolderEvaluation(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	return f.handleRouteGetFolderEvaluation(ctx, folderUIDParam)
+}
 func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -135,6 +257,20 @@ func (f *ProvisioningApiHandler) RouteGetPolicyTree(ctx *contextmodel.ReqContext
 func (f *ProvisioningApiHandler) RouteGetPolicyTreeExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTreeExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetProvisioningFileSchema(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvisioningFileSchema(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetRuleGroupJob(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetRuleGroupJob(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetRuleGroupsNoiseReport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRuleGroupsNoiseReport(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetRuleTitleUniqueness(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRuleTitleUniqueness(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -143,18 +279,151 @@ func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *contextmodel.ReqContext)
 func (f *ProvisioningApiHandler) RouteGetTemplates(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetTemplates(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetWebhookDeadLetters(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetWebhookDeadLetters(ctx)
+}
 func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.ProvisionedAlertRule{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePostAlertRule(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupArchive(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRoutePostAlertRuleGroupArchive(ctx, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupClone(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleGroupClone{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleGroupClone(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupCostEstimate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleGroup{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleGroupCostEstimate(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupGenerate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleGroupGeneration{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleGroupGenerate(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupJob(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleGroup{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleGroupJob(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupMove(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.RuleGroupMove{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleGroupMove(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleGroupUnarchive(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRoutePostAlertRuleGroupUnarchive(ctx, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleRestore(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRoutePostAlertRuleRestore(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTemplateInstantiate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplateInstantiation{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleTemplateInstantiate(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRulesMetadata(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertRulesMetadataPatch{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRulesMetadata(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRulesPause(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertRulesPause{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRulesPause(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostBulkContactPointSecret(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BulkContactPointSecret{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostBulkContactPointSecret(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostBulkPolicy(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BulkPolicy{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostBulkPolicy(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostBulkRuleGroups(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BulkRuleGroups{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostBulkRuleGroups(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostConfigSnapshot(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRoutePostConfigSnapshot(ctx)
+}
+func (f *ProvisioningApiHandler) RoutePostConfigSnapshotRestore(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	iDParam := web.Params(ctx.Req)[":ID"]
+	return f.handleRoutePostConfigSnapshotRestore(ctx, iDParam)
+}
 func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EmbeddedContactPoint{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePostContactpoints(ctx, conf)
@@ -162,17 +431,40 @@ func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqCon
 func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.MuteTimeInterval{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePostMuteTiming(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostPolicyTreeMerge(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.PolicyTreeFragment{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostPolicyTreeMerge(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostPrometheusRuleGroupImport(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	// Parse Request Body
+	conf := apimodels.PrometheusRuleGroupImport{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostPrometheusRuleGroupImport(ctx, conf, folderUIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostWebhookDeadLetterReplay(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRoutePostWebhookDeadLetterReplay(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutAlertRule(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	// Parse Request Body
 	conf := apimodels.ProvisionedAlertRule{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePutAlertRule(ctx, conf, uIDParam)
@@ -183,27 +475,128 @@ func (f *ProvisioningApiHandler) RoutePutAlertRuleGroup(ctx *contextmodel.ReqCon
 	groupParam := web.Params(ctx.Req)[":Group"]
 	// Parse Request Body
 	conf := apimodels.AlertRuleGroup{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePutAlertRuleGroup(ctx, conf, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RoutePutAlertRuleGroupInterval(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.RuleGroupInterval{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleGroupInterval(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertRuleGroupProvenance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.ProvenanceTransition{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleGroupProvenance(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertRuleGroupTags(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.ResourceTags{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleGroupTags(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertRuleProvenance(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.ProvenanceTransition{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleProvenance(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertRuleTags(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.ResourceTags{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleTags(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleTemplate{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleTemplate(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertmanagerRouting(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertmanagerRouting{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertmanagerRouting(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePutContactPointTags(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.ResourceTags{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutContactPointTags(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	// Parse Request Body
 	conf := apimodels.EmbeddedContactPoint{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePutContactpoint(ctx, conf, uIDParam)
 }
+func (f *ProvisioningApiHandler) RoutePutFolderAnnotations(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	// Parse Request Body
+	conf := apimodels.FolderAnnotations{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutFolderAnnotations(ctx, conf, folderUIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutFolderEvaluation(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	// Parse Request Body
+	conf := apimodels.FolderEvaluation{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutFolderEvaluation(ctx, conf, folderUIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
 	// Parse Request Body
 	conf := apimodels.MuteTimeInterval{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePutMuteTiming(ctx, conf, nameParam)
@@ -211,17 +604,25 @@ func (f *ProvisioningApiHandler) RoutePutMuteTiming(ctx *contextmodel.ReqContext
 func (f *ProvisioningApiHandler) RoutePutPolicyTree(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.Route{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePutPolicyTree(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePutRuleTitleUniqueness(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RuleTitleUniqueness{}
+	if err := bindPayload(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutRuleTitleUniqueness(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePutTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
 	// Parse Request Body
 	conf := apimodels.NotificationTemplateContent{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePutTemplate(ctx, conf, nameParam)
@@ -229,6 +630,12 @@ func (f *ProvisioningApiHandler) RoutePutTemplate(ctx *contextmodel.ReqContext)
 func (f *ProvisioningApiHandler) RouteResetPolicyTree(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteResetPolicyTree(ctx)
 }
+func (f *ProvisioningApiHandler) RouteResetRuleTitleUniqueness(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteResetRuleTitleUniqueness(ctx)
+}
+func (f *ProvisioningApiHandler) RouteSearchAlertRules(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteSearchAlertRules(ctx)
+}
 
 func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
@@ -256,6 +663,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteAlertRuleTemplate),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -365,137 +784,761 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/alert-rules"),
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/alert-rules",
-				api.Hooks.Wrap(srv.RouteGetAlertRules),
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleGroupTags),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/alert-rules/export"),
+			toMacaronPath("/api/v1/provisioning/alert-rules/sync-status"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/sync-status"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/alert-rules/export",
-				api.Hooks.Wrap(srv.RouteGetAlertRulesExport),
+				"/api/v1/provisioning/alert-rules/sync-status",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleSyncStatus),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/contact-points"),
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/tags"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/{UID}/tags"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/contact-points",
-				api.Hooks.Wrap(srv.RouteGetContactpoints),
+				"/api/v1/provisioning/alert-rules/{UID}/tags",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTags),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/contact-points/export"),
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates/{UID}"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/contact-points/export",
-				api.Hooks.Wrap(srv.RouteGetContactpointsExport),
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplate),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/{name}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rule-templates"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/mute-timings/{name}",
-				api.Hooks.Wrap(srv.RouteGetMuteTiming),
+				"/api/v1/provisioning/alert-rule-templates",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleTemplates),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/mute-timings"),
+			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/mute-timings",
-				api.Hooks.Wrap(srv.RouteGetMuteTimings),
+				"/api/v1/provisioning/alert-rules",
+				api.Hooks.Wrap(srv.RouteGetAlertRules),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/policies"),
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/tags"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/policies"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/{UID}/tags"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/policies",
-				api.Hooks.Wrap(srv.RouteGetPolicyTree),
+				"/api/v1/provisioning/contact-points/{UID}/tags",
+				api.Hooks.Wrap(srv.RouteGetContactPointTags),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/policies/export"),
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/deliveries"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/{UID}/deliveries"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/policies/export",
-				api.Hooks.Wrap(srv.RouteGetPolicyTreeExport),
+				"/api/v1/provisioning/contact-points/{UID}/deliveries",
+				api.Hooks.Wrap(srv.RouteGetContactPointDeliveries),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/templates/{name}"),
+			toMacaronPath("/api/v1/provisioning/file-schema"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/{name}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/file-schema"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/templates/{name}",
-				api.Hooks.Wrap(srv.RouteGetTemplate),
+				"/api/v1/provisioning/file-schema",
+				api.Hooks.Wrap(srv.RouteGetProvisioningFileSchema),
 				m,
 			),
 		)
 		group.Get(
-			toMacaronPath("/api/v1/provisioning/templates"),
+			toMacaronPath("/api/v1/provisioning/rule-group-jobs/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodGet, "/api/v1/provisioning/templates"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/rule-group-jobs/{UID}"),
 			metrics.Instrument(
 				http.MethodGet,
-				"/api/v1/provisioning/templates",
-				api.Hooks.Wrap(srv.RouteGetTemplates),
+				"/api/v1/provisioning/rule-group-jobs/{UID}",
+				api.Hooks.Wrap(srv.RouteGetRuleGroupJob),
 				m,
 			),
 		)
-		group.Post(
-			toMacaronPath("/api/v1/provisioning/alert-rules"),
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/rule-title-uniqueness"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
 			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
-			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/rule-title-uniqueness"),
 			metrics.Instrument(
-				http.MethodPost,
-				"/api/v1/provisioning/alert-rules",
+				http.MethodGet,
+				"/api/v1/provisioning/rule-title-uniqueness",
+				api.Hooks.Wrap(srv.RouteGetRuleTitleUniqueness),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/webhook/dead-letters"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/webhook/dead-letters"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/webhook/dead-letters",
+				api.Hooks.Wrap(srv.RouteGetWebhookDeadLetters),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/jobs"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/jobs"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/jobs",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleGroupJob),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/move"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/move"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/move",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleGroupMove),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}/instantiate"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rule-templates/{UID}/instantiate"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rule-templates/{UID}/instantiate",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleTemplateInstantiate),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/pause"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/pause"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/pause",
+				api.Hooks.Wrap(srv.RoutePostAlertRulesPause),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/prometheus-rule-groups"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/prometheus-rule-groups"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/prometheus-rule-groups",
+				api.Hooks.Wrap(srv.RoutePostPrometheusRuleGroupImport),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/webhook/dead-letters/{UID}/replay"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/webhook/dead-letters/{UID}/replay"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/webhook/dead-letters/{UID}/replay",
+				api.Hooks.Wrap(srv.RoutePostWebhookDeadLetterReplay),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/interval"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/interval"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/interval",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleGroupInterval),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleGroupTags),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/tags"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rules/{UID}/tags"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rules/{UID}/tags",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleTags),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/provenance"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/provenance"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/provenance",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleGroupProvenance),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}/provenance"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rules/{UID}/provenance"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rules/{UID}/provenance",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleProvenance),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alert-rule-templates/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alert-rule-templates/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alert-rule-templates/{UID}",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleTemplate),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/tags"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/contact-points/{UID}/tags"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/contact-points/{UID}/tags",
+				api.Hooks.Wrap(srv.RoutePutContactPointTags),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/annotations"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/folder/{FolderUID}/annotations"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/folder/{FolderUID}/annotations",
+				api.Hooks.Wrap(srv.RouteGetFolderAnnotations),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/annotations"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/annotations"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/annotations",
+				api.Hooks.Wrap(srv.RoutePutFolderAnnotations),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/evaluation"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/folder/{FolderUID}/evaluation"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/folder/{FolderUID}/evaluation",
+				api.Hooks.Wrap(srv.RouteGetFolderEvaluation),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/evaluation"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/evaluation"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/evaluation",
+				api.Hooks.Wrap(srv.RoutePutFolderEvaluation),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/usage"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/usage"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/usage",
+				api.Hooks.Wrap(srv.RouteGetAlertRulesUsage),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/quota"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/quota"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/quota",
+				api.Hooks.Wrap(srv.RouteGetAlertRulesQuota),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/noise-report"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/noise-report"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/noise-report",
+				api.Hooks.Wrap(srv.RouteGetRuleGroupsNoiseReport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/trash"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/trash"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/trash",
+				api.Hooks.Wrap(srv.RouteGetDeletedAlertRules),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/trash/{UID}/restore"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/trash/{UID}/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/trash/{UID}/restore",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleRestore),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/merge"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/merge"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/merge",
+				api.Hooks.Wrap(srv.RoutePostPolicyTreeMerge),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/clone"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/clone"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/clone",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleGroupClone),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/generate"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/generate"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/generate",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleGroupGenerate),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/archive"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/archive"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/archive",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleGroupArchive),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/unarchive"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/unarchive"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/unarchive",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleGroupUnarchive),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/metadata"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/metadata"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/metadata",
+				api.Hooks.Wrap(srv.RoutePostAlertRulesMetadata),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/rule-title-uniqueness"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/rule-title-uniqueness"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/rule-title-uniqueness",
+				api.Hooks.Wrap(srv.RoutePutRuleTitleUniqueness),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/rule-title-uniqueness"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/rule-title-uniqueness"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/rule-title-uniqueness",
+				api.Hooks.Wrap(srv.RouteResetRuleTitleUniqueness),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/search"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/search"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/search",
+				api.Hooks.Wrap(srv.RouteSearchAlertRules),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/export",
+				api.Hooks.Wrap(srv.RouteGetAlertRulesExport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alertmanager-routing"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alertmanager-routing"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alertmanager-routing",
+				api.Hooks.Wrap(srv.RouteGetAlertmanagerRouting),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alertmanager-routing/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alertmanager-routing/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alertmanager-routing/export",
+				api.Hooks.Wrap(srv.RouteGetAlertmanagerRoutingExport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/snapshots"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/snapshots"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/snapshots",
+				api.Hooks.Wrap(srv.RouteGetConfigSnapshots),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/contact-points"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/contact-points",
+				api.Hooks.Wrap(srv.RouteGetContactpoints),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/contact-points/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/contact-points/export",
+				api.Hooks.Wrap(srv.RouteGetContactpointsExport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/effective"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/effective"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/effective",
+				api.Hooks.Wrap(srv.RouteGetEffectivePolicy),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/{name}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings/{name}",
+				api.Hooks.Wrap(srv.RouteGetMuteTiming),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings",
+				api.Hooks.Wrap(srv.RouteGetMuteTimings),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies",
+				api.Hooks.Wrap(srv.RouteGetPolicyTree),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/policies/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/policies/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/policies/export",
+				api.Hooks.Wrap(srv.RouteGetPolicyTreeExport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/templates/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/{name}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/templates/{name}",
+				api.Hooks.Wrap(srv.RouteGetTemplate),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/templates",
+				api.Hooks.Wrap(srv.RouteGetTemplates),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules",
 				api.Hooks.Wrap(srv.RoutePostAlertRule),
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/cost-estimate"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/cost-estimate"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/cost-estimate",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleGroupCostEstimate),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bulk/contact-point-secrets"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bulk/contact-point-secrets"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bulk/contact-point-secrets",
+				api.Hooks.Wrap(srv.RoutePostBulkContactPointSecret),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bulk/policies"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bulk/policies"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bulk/policies",
+				api.Hooks.Wrap(srv.RoutePostBulkPolicy),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bulk/rule-groups"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bulk/rule-groups"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bulk/rule-groups",
+				api.Hooks.Wrap(srv.RoutePostBulkRuleGroups),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/snapshots"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/snapshots"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/snapshots",
+				api.Hooks.Wrap(srv.RoutePostConfigSnapshot),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/snapshots/{ID}/restore"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/snapshots/{ID}/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/snapshots/{ID}/restore",
+				api.Hooks.Wrap(srv.RoutePostConfigSnapshotRestore),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -544,6 +1587,18 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/alertmanager-routing"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/alertmanager-routing"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/alertmanager-routing",
+				api.Hooks.Wrap(srv.RoutePutAlertmanagerRouting),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
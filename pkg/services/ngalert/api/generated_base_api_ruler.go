@@ -29,6 +29,7 @@ type RulerApi interface {
 	RouteGetNamespaceGrafanaRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteGetNamespaceRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteGetRulegGroupConfig(*contextmodel.ReqContext) response.Response
+	RouteGetRulesAccess(*contextmodel.ReqContext) response.Response
 	RouteGetRulesConfig(*contextmodel.ReqContext) response.Response
 	RouteGetRulesForExport(*contextmodel.ReqContext) response.Response
 	RoutePostNameGrafanaRulesConfig(*contextmodel.ReqContext) response.Response
@@ -87,6 +88,9 @@ func (f *RulerApiHandler) RouteGetRulegGroupConfig(ctx *contextmodel.ReqContext)
 	groupnameParam := web.Params(ctx.Req)[":Groupname"]
 	return f.handleRouteGetRulegGroupConfig(ctx, datasourceUIDParam, namespaceParam, groupnameParam)
 }
+func (f *RulerApiHandler) RouteGetRulesAccess(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRulesAccess(ctx)
+}
 func (f *RulerApiHandler) RouteGetRulesConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	datasourceUIDParam := web.Params(ctx.Req)[":DatasourceUID"]
@@ -100,7 +104,7 @@ func (f *RulerApiHandler) RoutePostNameGrafanaRulesConfig(ctx *contextmodel.ReqC
 	namespaceParam := web.Params(ctx.Req)[":Namespace"]
 	// Parse Request Body
 	conf := apimodels.PostableRuleGroupConfig{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePostNameGrafanaRulesConfig(ctx, conf, namespaceParam)
@@ -111,7 +115,7 @@ func (f *RulerApiHandler) RoutePostNameRulesConfig(ctx *contextmodel.ReqContext)
 	namespaceParam := web.Params(ctx.Req)[":Namespace"]
 	// Parse Request Body
 	conf := apimodels.PostableRuleGroupConfig{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePostNameRulesConfig(ctx, conf, datasourceUIDParam, namespaceParam)
@@ -121,7 +125,7 @@ func (f *RulerApiHandler) RoutePostRulesGroupForExport(ctx *contextmodel.ReqCont
 	namespaceParam := web.Params(ctx.Req)[":Namespace"]
 	// Parse Request Body
 	conf := apimodels.PostableRuleGroupConfig{}
-	if err := web.Bind(ctx.Req, &conf); err != nil {
+	if err := bindPayload(ctx.Req, &conf); err != nil {
 		return response.Error(http.StatusBadRequest, "bad request data", err)
 	}
 	return f.handleRoutePostRulesGroupForExport(ctx, conf, namespaceParam)
@@ -237,6 +241,18 @@ func (api *API) RegisterRulerApiEndpoints(srv RulerApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/ruler/grafana/api/v1/access/rules"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/ruler/grafana/api/v1/access/rules"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/ruler/grafana/api/v1/access/rules",
+				api.Hooks.Wrap(srv.RouteGetRulesAccess),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/ruler/{DatasourceUID}/api/v1/rules"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -21,6 +21,13 @@ import (
 type UpgradeApi interface {
 	RouteDeleteOrgUpgrade(*contextmodel.ReqContext) response.Response
 	RouteGetOrgUpgrade(*contextmodel.ReqContext) response.Response
+	RouteGetOrgUpgradeExport(*contextmodel.ReqContext) response.Response
+	RouteGetStagedOrgUpgrade(*contextmodel.ReqContext) response.Response
+	RouteGetStagedOrgUpgrades(*contextmodel.ReqContext) response.Response
+	RoutePostStagedOrgUpgrade(*contextmodel.ReqContext) response.Response
+	RoutePostStagedOrgUpgradeApproval(*contextmodel.ReqContext) response.Response
+	RoutePostStagedOrgUpgradeDryRun(*contextmodel.ReqContext) response.Response
+	RoutePostStagedOrgUpgradeExecution(*contextmodel.ReqContext) response.Response
 	RoutePostUpgradeAlert(*contextmodel.ReqContext) response.Response
 	RoutePostUpgradeAllChannels(*contextmodel.ReqContext) response.Response
 	RoutePostUpgradeAllDashboards(*contextmodel.ReqContext) response.Response
@@ -35,6 +42,37 @@ func (f *UpgradeApiHandler) RouteDeleteOrgUpgrade(ctx *contextmodel.ReqContext)
 func (f *UpgradeApiHandler) RouteGetOrgUpgrade(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetOrgUpgrade(ctx)
 }
+func (f *UpgradeApiHandler) RouteGetOrgUpgradeExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetOrgUpgradeExport(ctx)
+}
+func (f *UpgradeApiHandler) RouteGetStagedOrgUpgrade(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	orgIDParam := web.Params(ctx.Req)[":OrgID"]
+	return f.handleRouteGetStagedOrgUpgrade(ctx, orgIDParam)
+}
+func (f *UpgradeApiHandler) RouteGetStagedOrgUpgrades(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetStagedOrgUpgrades(ctx)
+}
+func (f *UpgradeApiHandler) RoutePostStagedOrgUpgrade(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	orgIDParam := web.Params(ctx.Req)[":OrgID"]
+	return f.handleRoutePostStagedOrgUpgrade(ctx, orgIDParam)
+}
+func (f *UpgradeApiHandler) RoutePostStagedOrgUpgradeApproval(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	orgIDParam := web.Params(ctx.Req)[":OrgID"]
+	return f.handleRoutePostStagedOrgUpgradeApproval(ctx, orgIDParam)
+}
+func (f *UpgradeApiHandler) RoutePostStagedOrgUpgradeDryRun(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	orgIDParam := web.Params(ctx.Req)[":OrgID"]
+	return f.handleRoutePostStagedOrgUpgradeDryRun(ctx, orgIDParam)
+}
+func (f *UpgradeApiHandler) RoutePostStagedOrgUpgradeExecution(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	orgIDParam := web.Params(ctx.Req)[":OrgID"]
+	return f.handleRoutePostStagedOrgUpgradeExecution(ctx, orgIDParam)
+}
 func (f *UpgradeApiHandler) RoutePostUpgradeAlert(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	dashboardIDParam := web.Params(ctx.Req)[":DashboardID"]
@@ -87,6 +125,90 @@ func (api *API) RegisterUpgradeApiEndpoints(srv UpgradeApi, m *metrics.API) {
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/upgrade/org/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/upgrade/org/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/upgrade/org/export",
+				api.Hooks.Wrap(srv.RouteGetOrgUpgradeExport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/upgrade/staged/{OrgID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/upgrade/staged/{OrgID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/upgrade/staged/{OrgID}",
+				api.Hooks.Wrap(srv.RouteGetStagedOrgUpgrade),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/upgrade/staged"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodGet, "/api/v1/upgrade/staged"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/upgrade/staged",
+				api.Hooks.Wrap(srv.RouteGetStagedOrgUpgrades),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/upgrade/staged/{OrgID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/upgrade/staged/{OrgID}"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/upgrade/staged/{OrgID}",
+				api.Hooks.Wrap(srv.RoutePostStagedOrgUpgrade),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/upgrade/staged/{OrgID}/approve"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/upgrade/staged/{OrgID}/approve"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/upgrade/staged/{OrgID}/approve",
+				api.Hooks.Wrap(srv.RoutePostStagedOrgUpgradeApproval),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/upgrade/staged/{OrgID}/dry-run"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/upgrade/staged/{OrgID}/dry-run"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/upgrade/staged/{OrgID}/dry-run",
+				api.Hooks.Wrap(srv.RoutePostStagedOrgUpgradeDryRun),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/upgrade/staged/{OrgID}/execute"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			requestmeta.SetSLOGroup(requestmeta.SLOGroupHighSlow),
+			api.authorize(http.MethodPost, "/api/v1/upgrade/staged/{OrgID}/execute"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/upgrade/staged/{OrgID}/execute",
+				api.Hooks.Wrap(srv.RoutePostStagedOrgUpgradeExecution),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/upgrade/dashboards/{DashboardID}/panels/{PanelID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
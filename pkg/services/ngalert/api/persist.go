@@ -15,13 +15,19 @@ type RuleStore interface {
 	GetUserVisibleNamespaces(context.Context, int64, identity.Requester) (map[string]*folder.Folder, error)
 	GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user identity.Requester) (*folder.Folder, error)
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmodels.GetAlertRulesGroupByRuleUIDQuery) ([]*ngmodels.AlertRule, error)
+	GetAlertRulesGroupsByRuleUIDs(ctx context.Context, query *ngmodels.GetAlertRulesGroupsByRuleUIDsQuery) (map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, error)
 	ListAlertRules(ctx context.Context, query *ngmodels.ListAlertRulesQuery) (ngmodels.RulesGroup, error)
+	GetArchivedRuleGroups(ctx context.Context, orgID int64) ([]ngmodels.AlertRuleGroupKey, error)
+	GetRuleGroupsManagedBy(ctx context.Context, orgID int64) (map[ngmodels.AlertRuleGroupKey]ngmodels.ManagedBy, error)
 
 	// InsertAlertRules will insert all alert rules passed into the function
 	// and return the map of uuid to id.
 	InsertAlertRules(ctx context.Context, rule []ngmodels.AlertRule) ([]ngmodels.AlertRuleKeyWithId, error)
 	UpdateAlertRules(ctx context.Context, rule []ngmodels.UpdateRule) error
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
+	// LockRuleGroups locks the rule groups until the end of the transaction, so that the concurrent changes of the
+	// groups, e.g. through the provisioning API, are applied one after the other.
+	LockRuleGroups(ctx context.Context, keys ...ngmodels.AlertRuleGroupKey) error
 
 	// IncreaseVersionForAllRulesInNamespace Increases version for all rules that have specified namespace. Returns all rules that belong to the namespace
 	IncreaseVersionForAllRulesInNamespace(ctx context.Context, orgID int64, namespaceUID string) ([]ngmodels.AlertRuleKeyWithVersion, error)
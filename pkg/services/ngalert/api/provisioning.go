@@ -24,10 +24,118 @@ func (f *ProvisioningApiHandler) handleRouteGetPolicyTreeExport(ctx *contextmode
 	return f.svc.RouteGetPolicyTreeExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetEffectivePolicy(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetEffectivePolicy(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostPolicyTreeMerge(ctx *contextmodel.ReqContext, fragment apimodels.PolicyTreeFragment) response.Response {
+	return f.svc.RoutePostPolicyTreeMerge(ctx, fragment)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePutPolicyTree(ctx *contextmodel.ReqContext, route apimodels.Route) response.Response {
 	return f.svc.RoutePutPolicyTree(ctx, route)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetAlertmanagerRouting(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertmanagerRouting(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertmanagerRoutingExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertmanagerRoutingExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertmanagerRouting(ctx *contextmodel.ReqContext, routing apimodels.AlertmanagerRouting) response.Response {
+	return f.svc.RoutePutAlertmanagerRouting(ctx, routing)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisioningFileSchema(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningFileSchema(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetConfigSnapshots(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetConfigSnapshots(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostConfigSnapshot(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RoutePostConfigSnapshot(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostConfigSnapshotRestore(ctx *contextmodel.ReqContext, id string) response.Response {
+	return f.svc.RoutePostConfigSnapshotRestore(ctx, id)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostBulkRuleGroups(ctx *contextmodel.ReqContext, body apimodels.BulkRuleGroups) response.Response {
+	return f.svc.RoutePostBulkRuleGroups(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostBulkContactPointSecret(ctx *contextmodel.ReqContext, body apimodels.BulkContactPointSecret) response.Response {
+	return f.svc.RoutePostBulkContactPointSecret(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostBulkPolicy(ctx *contextmodel.ReqContext, body apimodels.BulkPolicy) response.Response {
+	return f.svc.RoutePostBulkPolicy(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTags(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetAlertRuleTags(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleTags(ctx *contextmodel.ReqContext, body apimodels.ResourceTags, uid string) response.Response {
+	return f.svc.RoutePutAlertRuleTags(ctx, body, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroupTags(ctx *contextmodel.ReqContext, folderUID string, group string) response.Response {
+	return f.svc.RouteGetAlertRuleGroupTags(ctx, folderUID, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleProvenance(ctx *contextmodel.ReqContext, body apimodels.ProvenanceTransition, uid string) response.Response {
+	return f.svc.RoutePutAlertRuleProvenance(ctx, body, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroupProvenance(ctx *contextmodel.ReqContext, body apimodels.ProvenanceTransition, folderUID string, group string) response.Response {
+	return f.svc.RoutePutAlertRuleGroupProvenance(ctx, body, folderUID, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroupTags(ctx *contextmodel.ReqContext, body apimodels.ResourceTags, folderUID string, group string) response.Response {
+	return f.svc.RoutePutAlertRuleGroupTags(ctx, body, folderUID, group)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetFolderAnnotations(ctx *contextmodel.ReqContext, folderUID string) response.Response {
+	return f.svc.RouteGetFolderAnnotations(ctx, folderUID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutFolderAnnotations(ctx *contextmodel.ReqContext, body apimodels.FolderAnnotations, folderUID string) response.Response {
+	return f.svc.RoutePutFolderAnnotations(ctx, body, folderUID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetFolderEvaluation(ctx *contextmodel.ReqContext, folderUID string) response.Response {
+	return f.svc.RouteGetFolderEvaluation(ctx, folderUID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutFolderEvaluation(ctx *contextmodel.ReqContext, body apimodels.FolderEvaluation, folderUID string) response.Response {
+	return f.svc.RoutePutFolderEvaluation(ctx, body, folderUID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetContactPointTags(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetContactPointTags(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetContactPointDeliveries(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetContactPointDeliveries(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutContactPointTags(ctx *contextmodel.ReqContext, body apimodels.ResourceTags, uid string) response.Response {
+	return f.svc.RoutePutContactPointTags(ctx, body, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRulesMetadata(ctx *contextmodel.ReqContext, body apimodels.AlertRulesMetadataPatch) response.Response {
+	return f.svc.RoutePostAlertRulesMetadata(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRulesPause(ctx *contextmodel.ReqContext, body apimodels.AlertRulesPause) response.Response {
+	return f.svc.RoutePostAlertRulesPause(ctx, body)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetContactPoints(ctx)
 }
@@ -100,6 +208,46 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRulesExport(ctx *contextmode
 	return f.svc.RouteGetAlertRulesExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetAlertRulesUsage(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRulesUsage(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRulesQuota(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRulesQuota(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetRuleGroupsNoiseReport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetRuleGroupsNoiseReport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetRuleTitleUniqueness(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetRuleTitleUniqueness(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutRuleTitleUniqueness(ctx *contextmodel.ReqContext, policy apimodels.RuleTitleUniqueness) response.Response {
+	return f.svc.RoutePutRuleTitleUniqueness(ctx, policy)
+}
+
+func (f *ProvisioningApiHandler) handleRouteResetRuleTitleUniqueness(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteResetRuleTitleUniqueness(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetDeletedAlertRules(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetDeletedAlertRules(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleSyncStatus(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleSyncStatus(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleRestore(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RoutePostAlertRuleRestore(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteSearchAlertRules(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteSearchAlertRules(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
 	return f.svc.RoutePostAlertRule(ctx, ar)
 }
@@ -116,6 +264,26 @@ func (f *ProvisioningApiHandler) handleRouteResetPolicyTree(ctx *contextmodel.Re
 	return f.svc.RouteResetPolicyTree(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertRuleTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteGetAlertRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleTemplate(ctx *contextmodel.ReqContext, body apimodels.AlertRuleTemplate, UID string) response.Response {
+	return f.svc.RoutePutAlertRuleTemplate(ctx, body, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteAlertRuleTemplate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteAlertRuleTemplate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTemplateInstantiate(ctx *contextmodel.ReqContext, body apimodels.AlertRuleTemplateInstantiation, UID string) response.Response {
+	return f.svc.RoutePostAlertRuleTemplateInstantiate(ctx, body, UID)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroup(ctx *contextmodel.ReqContext, folder, group string) response.Response {
 	return f.svc.RouteGetAlertRuleGroup(ctx, folder, group)
 }
@@ -128,6 +296,54 @@ func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroup(ctx *contextmodel.
 	return f.svc.RoutePutAlertRuleGroup(ctx, ag, folder, group)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroupInterval(ctx *contextmodel.ReqContext, body apimodels.RuleGroupInterval, folder, group string) response.Response {
+	return f.svc.RoutePutAlertRuleGroupInterval(ctx, body, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupJob(ctx *contextmodel.ReqContext, ag apimodels.AlertRuleGroup, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupJob(ctx, ag, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetRuleGroupJob(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetRuleGroupJob(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetWebhookDeadLetters(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetWebhookDeadLetters(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostWebhookDeadLetterReplay(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RoutePostWebhookDeadLetterReplay(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupClone(ctx *contextmodel.ReqContext, clone apimodels.AlertRuleGroupClone, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupClone(ctx, clone, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupGenerate(ctx *contextmodel.ReqContext, generation apimodels.AlertRuleGroupGeneration, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupGenerate(ctx, generation, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupMove(ctx *contextmodel.ReqContext, body apimodels.RuleGroupMove, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupMove(ctx, body, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostPrometheusRuleGroupImport(ctx *contextmodel.ReqContext, body apimodels.PrometheusRuleGroupImport, folder string) response.Response {
+	return f.svc.RoutePostPrometheusRuleGroupImport(ctx, body, folder)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupArchive(ctx *contextmodel.ReqContext, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupArchive(ctx, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupUnarchive(ctx *contextmodel.ReqContext, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupUnarchive(ctx, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleGroupCostEstimate(ctx *contextmodel.ReqContext, ag apimodels.AlertRuleGroup, folder, group string) response.Response {
+	return f.svc.RoutePostAlertRuleGroupCostEstimate(ctx, ag, folder, group)
+}
+
 func (f *ProvisioningApiHandler) handleRouteExportMuteTiming(ctx *contextmodel.ReqContext, name string) response.Response {
 	return f.svc.RouteGetMuteTimingExport(ctx, name)
 }
@@ -1,7 +1,7 @@
-resource "grafana_mute_timing" "mute_timing_1" {
+resource "grafana_mute_timing" "mute_timing_interval" {
   name = "interval"
 }
-resource "grafana_mute_timing" "mute_timing_2" {
+resource "grafana_mute_timing" "mute_timing_full-interval" {
   name = "full-interval"
 
   intervals {
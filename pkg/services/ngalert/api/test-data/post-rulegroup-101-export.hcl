@@ -1,4 +1,4 @@
-resource "grafana_rule_group" "rule_group_0000" {
+resource "grafana_rule_group" "rule_group_e4584834-1a87-4dff-8913-8a4748dfca79_group101" {
   org_id           = 1
   name             = "group101"
   folder_uid       = "e4584834-1a87-4dff-8913-8a4748dfca79"
@@ -0,0 +1,216 @@
+// provisioning-spec extracts the provisioning API from the spec of the alerting API into a standalone spec, which can
+// be used to generate clients of the provisioning API in other languages.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	RefKey = "$ref"
+
+	provisioningTag = "provisioning"
+	errorModel      = "ProvisioningError"
+)
+
+func main() {
+	var input, output string
+	flag.StringVar(&input, "if", "", "input file")
+	flag.StringVar(&output, "of", "", "output file")
+
+	flag.Parse()
+
+	if input == "" || output == "" {
+		log.Fatal("no file specified, input", input, ", output", output)
+	}
+
+	//nolint
+	b, err := os.ReadFile(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	data := make(map[string]any)
+	if err := json.Unmarshal(b, &data); err != nil {
+		log.Fatal(err)
+	}
+
+	result, err := extractProvisioningSpec(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out, err := json.MarshalIndent(result, "", " ")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(output, out, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// extractProvisioningSpec returns a spec that contains only the operations tagged with the provisioning tag and the
+// definitions and responses they reference. Operations that do not document a default response get one with the
+// error model of the provisioning API.
+func extractProvisioningSpec(data map[string]any) (map[string]any, error) {
+	paths, ok := data["paths"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no paths")
+	}
+	definitions, ok := data["definitions"].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no definitions")
+	}
+	if _, ok := definitions[errorModel]; !ok {
+		return nil, fmt.Errorf("no definition of the error model %s", errorModel)
+	}
+	responses, _ := data["responses"].(map[string]any)
+
+	resultPaths := make(map[string]any)
+	for p, v := range paths {
+		operations, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid path %s", p)
+		}
+		kept := make(map[string]any)
+		for method, v := range operations {
+			op, ok := v.(map[string]any)
+			if !ok || !hasTag(op, provisioningTag) {
+				continue
+			}
+			opResponses, ok := op["responses"].(map[string]any)
+			if !ok {
+				opResponses = make(map[string]any)
+				op["responses"] = opResponses
+			}
+			if _, ok := opResponses["default"]; !ok {
+				opResponses["default"] = map[string]any{
+					"description": errorModel,
+					"schema":      map[string]any{RefKey: "#/definitions/" + errorModel},
+				}
+			}
+			kept[method] = op
+		}
+		if len(kept) > 0 {
+			resultPaths[p] = kept
+		}
+	}
+	if len(resultPaths) == 0 {
+		return nil, fmt.Errorf("no operations tagged with %s", provisioningTag)
+	}
+
+	resultDefinitions := make(map[string]any)
+	resultResponses := make(map[string]any)
+	queue := collectRefs(resultPaths, nil)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+		switch {
+		case strings.HasPrefix(ref, "#/definitions/"):
+			name := strings.TrimPrefix(ref, "#/definitions/")
+			if _, ok := resultDefinitions[name]; ok {
+				continue
+			}
+			def, ok := definitions[name]
+			if !ok {
+				return nil, fmt.Errorf("missing definition %s", name)
+			}
+			resultDefinitions[name] = def
+			queue = collectRefs(def, queue)
+		case strings.HasPrefix(ref, "#/responses/"):
+			name := strings.TrimPrefix(ref, "#/responses/")
+			if _, ok := resultResponses[name]; ok {
+				continue
+			}
+			resp, ok := responses[name]
+			if !ok {
+				return nil, fmt.Errorf("missing response %s", name)
+			}
+			resultResponses[name] = resp
+			queue = collectRefs(resp, queue)
+		default:
+			return nil, fmt.Errorf("unsupported reference %s", ref)
+		}
+	}
+
+	result := make(map[string]any, len(data))
+	for k, v := range data {
+		result[k] = v
+	}
+	info := make(map[string]any)
+	if i, ok := data["info"].(map[string]any); ok {
+		for k, v := range i {
+			info[k] = v
+		}
+	}
+	info["title"] = "Grafana Alerting Provisioning API"
+	info["description"] = "The provisioning API of Grafana Alerting manages alert rules, contact points, notification policies, mute timings and templates as code."
+	result["info"] = info
+	result["paths"] = resultPaths
+	result["definitions"] = resultDefinitions
+	if len(resultResponses) > 0 {
+		result["responses"] = resultResponses
+	} else {
+		delete(result, "responses")
+	}
+	result["tags"] = filterTags(data["tags"])
+	if result["tags"] == nil {
+		delete(result, "tags")
+	}
+	return result, nil
+}
+
+func hasTag(op map[string]any, tag string) bool {
+	tags, _ := op["tags"].([]any)
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRefs appends the references found in v to refs, in a stable order.
+func collectRefs(v any, refs []string) []string {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if ref, ok := v[k].(string); ok && k == RefKey {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = collectRefs(v[k], refs)
+		}
+	case []any:
+		for _, item := range v {
+			refs = collectRefs(item, refs)
+		}
+	}
+	return refs
+}
+
+func filterTags(v any) any {
+	tags, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	for _, t := range tags {
+		if tag, ok := t.(map[string]any); ok && tag["name"] == provisioningTag {
+			return []any{tag}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSpec = `{
+	"info": {"title": "Grafana Alerting API.", "version": "1.1.0"},
+	"paths": {
+		"/v1/provisioning/alert-rules": {
+			"get": {
+				"tags": ["provisioning"],
+				"responses": {"200": {"description": "Rules", "schema": {"$ref": "#/definitions/Rules"}}}
+			}
+		},
+		"/ruler/grafana/api/v1/rules": {
+			"get": {
+				"tags": ["ruler"],
+				"responses": {"200": {"description": "Ruler", "schema": {"$ref": "#/definitions/Ruler"}}}
+			}
+		}
+	},
+	"definitions": {
+		"Rules": {"type": "array", "items": {"$ref": "#/definitions/Rule"}},
+		"Rule": {"type": "object", "properties": {"title": {"type": "string"}}},
+		"Ruler": {"type": "object"},
+		"ProvisioningError": {"type": "object", "properties": {"message": {"type": "string"}}}
+	}
+}`
+
+func TestExtractProvisioningSpec(t *testing.T) {
+	data := make(map[string]any)
+	require.NoError(t, json.Unmarshal([]byte(testSpec), &data))
+
+	result, err := extractProvisioningSpec(data)
+	require.NoError(t, err)
+
+	paths := result["paths"].(map[string]any)
+	require.Len(t, paths, 1)
+	require.Contains(t, paths, "/v1/provisioning/alert-rules")
+
+	definitions := result["definitions"].(map[string]any)
+	require.Len(t, definitions, 3)
+	require.Contains(t, definitions, "Rules")
+	require.Contains(t, definitions, "Rule")
+	require.Contains(t, definitions, errorModel)
+
+	op := paths["/v1/provisioning/alert-rules"].(map[string]any)["get"].(map[string]any)
+	require.Equal(t, map[string]any{
+		"description": errorModel,
+		"schema":      map[string]any{RefKey: "#/definitions/" + errorModel},
+	}, op["responses"].(map[string]any)["default"])
+
+	require.Equal(t, "Grafana Alerting Provisioning API", result["info"].(map[string]any)["title"])
+}
+
+func TestExtractProvisioningSpecErrors(t *testing.T) {
+	t.Run("should fail if a definition is missing", func(t *testing.T) {
+		data := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(testSpec), &data))
+		delete(data["definitions"].(map[string]any), "Rule")
+		_, err := extractProvisioningSpec(data)
+		require.ErrorContains(t, err, "missing definition Rule")
+	})
+
+	t.Run("should fail if the error model is missing", func(t *testing.T) {
+		data := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(testSpec), &data))
+		delete(data["definitions"].(map[string]any), errorModel)
+		_, err := extractProvisioningSpec(data)
+		require.ErrorContains(t, err, errorModel)
+	})
+}
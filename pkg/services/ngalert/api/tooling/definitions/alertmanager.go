@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/go-openapi/strfmt"
@@ -570,7 +571,12 @@ type DatasourceUIDReference struct {
 type PostableUserConfig struct {
 	TemplateFiles      map[string]string         `yaml:"template_files" json:"template_files"`
 	AlertmanagerConfig PostableApiAlertingConfig `yaml:"alertmanager_config" json:"alertmanager_config"`
-	amSimple           map[string]interface{}    `yaml:"-" json:"-"`
+	// DisabledIntegrations contains the UIDs of the Grafana integrations that are kept in the configuration but do
+	// not send notifications.
+	DisabledIntegrations []string `yaml:"disabled_integrations,omitempty" json:"disabled_integrations,omitempty"`
+	// RateLimits contains the rate limits of the receivers, by name.
+	RateLimits map[string]NotificationRateLimit `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
+	amSimple   map[string]interface{}           `yaml:"-" json:"-"`
 }
 
 func (c *PostableUserConfig) UnmarshalJSON(b []byte) error {
@@ -612,6 +618,12 @@ func (c *PostableUserConfig) validate() error {
 		return fmt.Errorf("cannot have continue in root route")
 	}
 
+	for name, limit := range c.RateLimits {
+		if err := limit.Validate(); err != nil {
+			return fmt.Errorf("invalid rate limit of receiver '%s': %w", name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -630,6 +642,48 @@ func (c *PostableUserConfig) GetGrafanaReceiverMap() map[string]*PostableGrafana
 	return UIDs
 }
 
+// IsIntegrationDisabled returns true if the Grafana integration with the given UID does not send notifications.
+func (c *PostableUserConfig) IsIntegrationDisabled(uid string) bool {
+	return slices.Contains(c.DisabledIntegrations, uid)
+}
+
+// SetIntegrationDisabled disables or re-enables the Grafana integration with the given UID.
+func (c *PostableUserConfig) SetIntegrationDisabled(uid string, disabled bool) {
+	idx := slices.Index(c.DisabledIntegrations, uid)
+	if disabled && idx < 0 {
+		c.DisabledIntegrations = append(c.DisabledIntegrations, uid)
+	} else if !disabled && idx >= 0 {
+		c.DisabledIntegrations = slices.Delete(c.DisabledIntegrations, idx, idx+1)
+	}
+}
+
+// GetRateLimit returns the rate limit of the receiver with the given name, or nil if it is not limited.
+func (c *PostableUserConfig) GetRateLimit(name string) *NotificationRateLimit {
+	limit, ok := c.RateLimits[name]
+	if !ok {
+		return nil
+	}
+	return &limit
+}
+
+// SetRateLimit sets the rate limit of the receiver with the given name, or removes it if limit is nil. The rate limits
+// of the receivers that do not exist anymore are removed.
+func (c *PostableUserConfig) SetRateLimit(name string, limit *NotificationRateLimit) {
+	if limit == nil {
+		delete(c.RateLimits, name)
+	} else {
+		if c.RateLimits == nil {
+			c.RateLimits = make(map[string]NotificationRateLimit)
+		}
+		c.RateLimits[name] = *limit
+	}
+	for name := range c.RateLimits {
+		if !slices.ContainsFunc(c.AlertmanagerConfig.Receivers, func(r *PostableApiReceiver) bool { return r.Name == name }) {
+			delete(c.RateLimits, name)
+		}
+	}
+}
+
 // MarshalYAML implements yaml.Marshaller.
 func (c *PostableUserConfig) MarshalYAML() (interface{}, error) {
 	yml, err := yaml.Marshal(c.amSimple)
@@ -670,9 +724,11 @@ func (c *PostableUserConfig) UnmarshalYAML(value *yaml.Node) error {
 
 // swagger:model
 type GettableUserConfig struct {
-	TemplateFiles           map[string]string         `yaml:"template_files" json:"template_files"`
-	TemplateFileProvenances map[string]Provenance     `yaml:"template_file_provenances,omitempty" json:"template_file_provenances,omitempty"`
-	AlertmanagerConfig      GettableApiAlertingConfig `yaml:"alertmanager_config" json:"alertmanager_config"`
+	TemplateFiles           map[string]string                `yaml:"template_files" json:"template_files"`
+	TemplateFileProvenances map[string]Provenance            `yaml:"template_file_provenances,omitempty" json:"template_file_provenances,omitempty"`
+	AlertmanagerConfig      GettableApiAlertingConfig        `yaml:"alertmanager_config" json:"alertmanager_config"`
+	DisabledIntegrations    []string                         `yaml:"disabled_integrations,omitempty" json:"disabled_integrations,omitempty"`
+	RateLimits              map[string]NotificationRateLimit `yaml:"rate_limits,omitempty" json:"rate_limits,omitempty"`
 
 	// amSimple stores a map[string]interface of the decoded alertmanager config.
 	// This enables circumventing the underlying alertmanager secret type
@@ -828,6 +884,10 @@ type GettableGrafanaReceiver struct {
 	Settings              RawMessage      `json:"settings,omitempty"`
 	SecureFields          map[string]bool `json:"secureFields"`
 	Provenance            Provenance      `json:"provenance,omitempty"`
+	// Disabled is true if the integration is kept in the configuration but does not send notifications.
+	Disabled bool `json:"disabled,omitempty"`
+	// RateLimit is the rate limit of the receiver of the integration.
+	RateLimit *NotificationRateLimit `json:"rateLimit,omitempty"`
 }
 
 type GettableApiReceiver struct {
@@ -96,6 +96,10 @@ type OnCallIntegration struct {
 	AuthorizationCredentials *Secret `json:"authorization_credentials,omitempty" yaml:"authorization_credentials,omitempty" hcl:"authorization_credentials"`
 	User                     *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"basic_auth_user"`
 	Password                 *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"basic_auth_password"`
+	OAuth2TokenURL           *string `json:"oauth2_token_url,omitempty" yaml:"oauth2_token_url,omitempty" hcl:"oauth2_token_url"`
+	OAuth2ClientID           *string `json:"oauth2_client_id,omitempty" yaml:"oauth2_client_id,omitempty" hcl:"oauth2_client_id"`
+	OAuth2ClientSecret       *Secret `json:"oauth2_client_secret,omitempty" yaml:"oauth2_client_secret,omitempty" hcl:"oauth2_client_secret"`
+	OAuth2Scopes             *string `json:"oauth2_scopes,omitempty" yaml:"oauth2_scopes,omitempty" hcl:"oauth2_scopes"`
 	Title                    *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
 	Message                  *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
 }
@@ -251,6 +255,10 @@ type WebhookIntegration struct {
 	AuthorizationCredentials *Secret `json:"authorization_credentials,omitempty" yaml:"authorization_credentials,omitempty" hcl:"authorization_credentials"`
 	User                     *string `json:"username,omitempty" yaml:"username,omitempty" hcl:"basic_auth_user"`
 	Password                 *Secret `json:"password,omitempty" yaml:"password,omitempty" hcl:"basic_auth_password"`
+	OAuth2TokenURL           *string `json:"oauth2_token_url,omitempty" yaml:"oauth2_token_url,omitempty" hcl:"oauth2_token_url"`
+	OAuth2ClientID           *string `json:"oauth2_client_id,omitempty" yaml:"oauth2_client_id,omitempty" hcl:"oauth2_client_id"`
+	OAuth2ClientSecret       *Secret `json:"oauth2_client_secret,omitempty" yaml:"oauth2_client_secret,omitempty" hcl:"oauth2_client_secret"`
+	OAuth2Scopes             *string `json:"oauth2_scopes,omitempty" yaml:"oauth2_scopes,omitempty" hcl:"oauth2_scopes"`
 	Title                    *string `json:"title,omitempty" yaml:"title,omitempty" hcl:"title"`
 	Message                  *string `json:"message,omitempty" yaml:"message,omitempty" hcl:"message"`
 }
@@ -33,6 +33,18 @@ import (
 //       403: ForbiddenError
 //       404: description: Not found.
 
+// swagger:route Get /ruler/grafana/api/v1/access/rules ruler RouteGetRulesAccess
+//
+// Evaluate the access of a user to the rule groups of the organization
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleGroupsAccessResponse
+//       400: ValidationError
+//       403: ForbiddenError
+
 // swagger:route Get /ruler/{DatasourceUID}/api/v1/rules ruler RouteGetRulesConfig
 //
 // List rule groups
@@ -197,6 +209,38 @@ type PathGetRulesParams struct {
 	PanelID int64
 }
 
+// swagger:parameters RouteGetRulesAccess
+type RulesAccessParams struct {
+	// ID of the user whose access is evaluated
+	// in: query
+	// required: true
+	UserID int64 `json:"userId"`
+}
+
+// swagger:model
+type RuleGroupsAccessResponse struct {
+	UserID int64             `json:"userId"`
+	Groups []RuleGroupAccess `json:"groups"`
+}
+
+// swagger:model
+type RuleGroupAccess struct {
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// Authorized is false if the rule group is filtered out when the user reads rules.
+	Authorized bool `json:"authorized"`
+	// Reason explains why the user is not authorized to access the rule group.
+	Reason             string              `json:"reason,omitempty"`
+	MissingPermissions []MissingPermission `json:"missingPermissions,omitempty"`
+}
+
+// swagger:model
+type MissingPermission struct {
+	Action    string `json:"action"`
+	Scope     string `json:"scope,omitempty"`
+	FolderUID string `json:"folderUid,omitempty"`
+}
+
 // swagger:model
 type RuleGroupConfigResponse struct {
 	GettableRuleGroupConfig
@@ -257,6 +301,10 @@ type GettableRuleGroupConfig struct {
 	Interval      model.Duration             `yaml:"interval,omitempty" json:"interval,omitempty"`
 	SourceTenants []string                   `yaml:"source_tenants,omitempty" json:"source_tenants,omitempty"`
 	Rules         []GettableExtendedRuleNode `yaml:"rules" json:"rules"`
+	// Whether the Grafana rule group is archived, in which case its rules are not evaluated.
+	Archived bool `yaml:"archived,omitempty" json:"archived,omitempty"`
+	// Who manages the Grafana rule group if it is provisioned, so that the users can change it there.
+	ManagedBy *ManagedBy `yaml:"managedBy,omitempty" json:"managedBy,omitempty"`
 }
 
 func (c *GettableRuleGroupConfig) UnmarshalJSON(b []byte) error {
@@ -428,12 +476,12 @@ type AlertRuleNotificationSettings struct {
 	// Override how long to initially wait to send a notification for a group of alerts. Allows to wait for an
 	// inhibiting alert to arrive or collect more initial alerts for the same group. (Usually ~0s to few minutes.)
 	// example: 30s
-	GroupWait *model.Duration `json:"group_wait,omitempty"`
+	GroupWait *DurationOrSeconds `json:"group_wait,omitempty"`
 
 	// Override how long to wait before sending a notification about new alerts that are added to a group of alerts for
 	// which an initial notification has already been sent. (Usually ~5m or more.)
 	// example: 5m
-	GroupInterval *model.Duration `json:"group_interval,omitempty"`
+	GroupInterval *DurationOrSeconds `json:"group_interval,omitempty"`
 
 	// Override how long to wait before sending a notification again if it has already been sent successfully for an
 	// alert. (Usually ~3h or more).
@@ -441,7 +489,7 @@ type AlertRuleNotificationSettings struct {
 	// Notifications will be resent after either repeat_interval or the data retention period have passed, whichever
 	// occurs first. `repeat_interval` should not be less than `group_interval`.
 	// example: 4h
-	RepeatInterval *model.Duration `json:"repeat_interval,omitempty"`
+	RepeatInterval *DurationOrSeconds `json:"repeat_interval,omitempty"`
 
 	// Override the times when notifications should be muted. These must match the name of a mute time interval defined
 	// in the alertmanager configuration mute_time_intervals section. When muted it will not send any notifications, but
@@ -460,6 +508,8 @@ type PostableGrafanaRule struct {
 	ExecErrState         ExecutionErrorState            `json:"exec_err_state" yaml:"exec_err_state"`
 	IsPaused             *bool                          `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings" yaml:"notification_settings"`
+	// Settings of other receivers to send the notifications to, each with its own optional settings.
+	AdditionalNotificationSettings []AlertRuleNotificationSettings `json:"additional_notification_settings,omitempty" yaml:"additional_notification_settings,omitempty"`
 }
 
 // swagger:model
@@ -480,6 +530,11 @@ type GettableGrafanaRule struct {
 	Provenance           Provenance                     `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 	IsPaused             bool                           `json:"is_paused" yaml:"is_paused"`
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty"`
+	// Settings of other receivers to send the notifications to, each with its own optional settings.
+	AdditionalNotificationSettings []AlertRuleNotificationSettings `json:"additional_notification_settings,omitempty" yaml:"additional_notification_settings,omitempty"`
+	// How long the alerts of the rule are pending before they fire. This is the `for` duration rounded up to a whole
+	// number of evaluation intervals of the rule group.
+	EffectivePendingPeriod model.Duration `json:"effectivePendingPeriod,omitempty" yaml:"effectivePendingPeriod,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -555,4 +610,8 @@ type UpdateRuleGroupResponse struct {
 	Created []string `json:"created,omitempty"`
 	Updated []string `json:"updated,omitempty"`
 	Deleted []string `json:"deleted,omitempty"`
+	// Paths of the changed fields of the updated rules by UID, e.g. Data[1].Model if only the model of the second
+	// query is changed.
+	// example: {"bd4k6m1ra9fy8e": ["Data[1].Model", "For"]}
+	ChangedFields map[string][]string `json:"changedFields,omitempty"`
 }
@@ -1,16 +1,53 @@
 package definitions
 
+import (
+	"gopkg.in/yaml.v3"
+)
+
 // AlertingFileExport is the full provisioned file export.
 // swagger:model
 type AlertingFileExport struct {
-	APIVersion    int64                      `json:"apiVersion" yaml:"apiVersion"`
-	Groups        []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
-	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
-	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
-	MuteTimings   []MuteTimeIntervalExport   `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	APIVersion          int64                       `json:"apiVersion" yaml:"apiVersion"`
+	Groups              []AlertRuleGroupExport      `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ContactPoints       []ContactPointExport        `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
+	Policies            []NotificationPolicyExport  `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimings         []MuteTimeIntervalExport    `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	AlertmanagerRouting []AlertmanagerRoutingExport `json:"alertmanagerRouting,omitempty" yaml:"alertmanagerRouting,omitempty"`
+	// HeadComment is written as a comment at the top of YAML exports. It is not part of other formats.
+	HeadComment string `json:"-" yaml:"-"`
+}
+
+// MarshalYAML implements yaml.Marshaler to write the head comment.
+func (e AlertingFileExport) MarshalYAML() (any, error) {
+	type plain AlertingFileExport
+	if e.HeadComment == "" {
+		return plain(e), nil
+	}
+	var node yaml.Node
+	if err := node.Encode(plain(e)); err != nil {
+		return nil, err
+	}
+	node.HeadComment = e.HeadComment
+	return &node, nil
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport RouteExportMuteTimings RouteExportMuteTiming
+// ProvisioningError is the body of the error responses of the provisioning API.
+// swagger:model
+type ProvisioningError struct {
+	// Message describes the error.
+	// example: invalid alert rule
+	Message string `json:"message"`
+	// MessageID identifies the kind of the error. It is set only for some errors.
+	// example: alerting.notFound
+	MessageID string `json:"messageId,omitempty"`
+	// StatusCode is the HTTP status code of the response. It is set only for some errors.
+	StatusCode int `json:"statusCode,omitempty"`
+	// Extra contains structured data about the error. It is set only for some errors.
+	// example: {"RuleUID": "a1b2c3", "NamespaceUID": "folder-1", "RuleGroup": "group-1"}
+	Extra map[string]any `json:"extra,omitempty"`
+}
+
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetContactpointExport RoutePostRulesGroupForExport RouteExportMuteTimings RouteExportMuteTiming RouteGetOrgUpgradeExport
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
@@ -18,14 +55,25 @@ type ExportQueryParams struct {
 	// default: false
 	Download bool `json:"download"`
 
-	// Format of the downloaded file, either yaml or json. Accept header can also be used, but the query parameter will take precedence.
+	// Format of the downloaded file, either yaml, json or hcl. Accept header can also be used for yaml and json, but the query parameter will take precedence.
 	// in: query
 	// required: false
 	// default: yaml
+	// enum: yaml,json,hcl
 	Format string `json:"format"`
 }
 
-// swagger:parameters RouteGetContactpointsExport RouteGetContactpointExport
+// swagger:parameters RouteGetAlertRulesExport RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetContactpointsExport RouteGetPolicyTreeExport RouteGetAlertmanagerRoutingExport RouteExportMuteTimings RouteExportMuteTiming
+type ExportConsistencyParam struct {
+	// Whether to read everything that is exported from a single snapshot of the database, so that the export is
+	// consistent even if the exported resources are changed while it is generated.
+	// in: query
+	// required: false
+	// default: false
+	Consistent bool `json:"consistent"`
+}
+
+// swagger:parameters RouteGetContactpointsExport RouteGetContactpointExport RouteGetOrgUpgradeExport
 type DecryptQueryParams struct {
 	// Whether any contained secure settings should be decrypted or left redacted. Redacted settings will contain RedactedValue instead. Currently, only org admin can view decrypted secure settings.
 	// in: query
@@ -33,3 +81,10 @@ type DecryptQueryParams struct {
 	// default: false
 	Decrypt bool `json:"decrypt"`
 }
+
+// swagger:parameters RoutePostAlertRule RoutePutAlertRule RoutePostAlertRulesPause RoutePostAlertRulesMetadata RoutePutAlertRuleTemplate RoutePostAlertRuleTemplateInstantiate RoutePutAlertRuleTags RoutePutAlertRuleGroup RoutePostAlertRuleGroupCostEstimate RoutePostAlertRuleGroupClone RoutePostAlertRuleGroupGenerate RoutePostAlertRuleGroupJob RoutePutAlertRuleGroupInterval RoutePostAlertRuleGroupMove RoutePutAlertRuleGroupTags RoutePutAlertmanagerRouting RoutePostBulkRuleGroups RoutePostBulkContactPointSecret RoutePostBulkPolicy RoutePostContactpoints RoutePutContactpoint RoutePutContactPointTags RoutePutFolderAnnotations RoutePutFolderEvaluation RoutePutAlertRuleProvenance RoutePutAlertRuleGroupProvenance RoutePostMuteTiming RoutePutMuteTiming RoutePutPolicyTree RoutePostPolicyTreeMerge RoutePutRuleTitleUniqueness RoutePutTemplate RoutePostPrometheusRuleGroupImport
+type StrictDecodingHeader struct {
+	// Reject the payload if it has unknown fields, e.g. misspelled ones, instead of ignoring them.
+	// in:header
+	XStrictDecoding string `json:"X-Strict-Decoding"`
+}
@@ -0,0 +1,50 @@
+package definitions
+
+// swagger:route POST /v1/provisioning/alert-rules/metadata provisioning stable RoutePostAlertRulesMetadata
+//
+// Add and remove labels and annotations of all the alert rules that match a selector, in one transaction.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRulesMetadataPatchResult
+//       400: ValidationError
+//       403: ForbiddenError
+
+// swagger:parameters RoutePostAlertRulesMetadata
+type AlertRulesMetadataPatchPayload struct {
+	// in:body
+	Body AlertRulesMetadataPatch
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// AlertRulesMetadataPatch changes the labels and the annotations of the alert rules that match all of its selectors.
+// At least one selector is required.
+// swagger:model
+type AlertRulesMetadataPatch struct {
+	// Folders of the rules to change.
+	FolderUIDs []string `json:"folderUids,omitempty"`
+	// Rule group of the rules to change.
+	RuleGroup string `json:"ruleGroup,omitempty"`
+	// Matchers of the labels of the rules to change.
+	Matchers ObjectMatchers `json:"matchers,omitempty"`
+	// Labels to set, replacing the labels with the same names.
+	// example: {"team": "platform"}
+	AddLabels map[string]string `json:"addLabels,omitempty"`
+	// Names of the labels to remove.
+	// example: ["owner"]
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+	// Annotations to set, replacing the annotations with the same names.
+	AddAnnotations map[string]string `json:"addAnnotations,omitempty"`
+	// Names of the annotations to remove.
+	RemoveAnnotations []string `json:"removeAnnotations,omitempty"`
+}
+
+// swagger:model
+type AlertRulesMetadataPatchResult struct {
+	// Number of rules that were changed.
+	// example: 3
+	Updated int `json:"updated"`
+}
@@ -0,0 +1,45 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/alert-rules/sync-status provisioning stable RouteGetAlertRuleSyncStatus
+//
+// Get the result of the last sync of the rule groups of the directory configured in [unified_alerting.rule_sync].
+//
+//     Responses:
+//       200: AlertRuleSyncStatus
+
+// AlertRuleSyncStatus is the result of the last sync of the rule groups of the organization.
+// swagger:model
+type AlertRuleSyncStatus struct {
+	// Enabled is false if no directory is synced.
+	Enabled bool `json:"enabled"`
+	// LastSync is the time of the last sync, unset if the rule groups were not synced yet.
+	LastSync *time.Time `json:"lastSync,omitempty"`
+	// Error is the error of the last sync if the directory could not be read, in which case no group was synced.
+	Error  string                     `json:"error,omitempty"`
+	Groups []AlertRuleGroupSyncStatus `json:"groups"`
+}
+
+// AlertRuleGroupSyncStatus is the result of the last sync of a rule group.
+type AlertRuleGroupSyncStatus struct {
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// File is the path of the file that defines the group in the directory, unset if the group was pruned.
+	File     string    `json:"file,omitempty"`
+	LastSync time.Time `json:"lastSync"`
+	// Result is synced if the stored group is the group of the file, failed if it could not be written, and pruned if
+	// it was deleted because it was removed from the files.
+	// enum: synced,failed,pruned
+	Result string `json:"result"`
+	Error  string `json:"error,omitempty"`
+	// Created, Updated and Deleted are the UIDs of the alert rules that the last sync changed.
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Deleted []string `json:"deleted"`
+	// Drift is true if the group was changed outside of the files since it was synced before. The changes were
+	// reverted by the last sync.
+	Drift bool `json:"drift"`
+}
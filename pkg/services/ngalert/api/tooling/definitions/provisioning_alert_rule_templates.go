@@ -0,0 +1,110 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/alert-rule-templates provisioning stable RouteGetAlertRuleTemplates
+//
+// Get all the alert rule templates.
+//
+//     Responses:
+//       200: AlertRuleTemplates
+
+// swagger:route GET /v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteGetAlertRuleTemplate
+//
+// Get an alert rule template.
+//
+//     Responses:
+//       200: AlertRuleTemplate
+//       404: description: Not found.
+
+// swagger:route PUT /v1/provisioning/alert-rule-templates/{UID} provisioning stable RoutePutAlertRuleTemplate
+//
+// Create or replace an alert rule template.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleTemplate
+//       400: ValidationError
+
+// swagger:route DELETE /v1/provisioning/alert-rule-templates/{UID} provisioning stable RouteDeleteAlertRuleTemplate
+//
+// Delete an alert rule template. The alert rules instantiated from it are kept.
+//
+//     Responses:
+//       204: description: The alert rule template was deleted.
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/alert-rule-templates/{UID}/instantiate provisioning stable RoutePostAlertRuleTemplateInstantiate
+//
+// Create or update the alert rule that an alert rule template expands into with the values of its parameters.
+//
+// Instantiating the template again with the same values updates the rule: the rule is matched by UID if the template
+// has one, or by title in its rule group otherwise.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRule
+//       400: ValidationError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:parameters RouteGetAlertRuleTemplate RoutePutAlertRuleTemplate RouteDeleteAlertRuleTemplate RoutePostAlertRuleTemplateInstantiate
+type AlertRuleTemplateUIDParam struct {
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePutAlertRuleTemplate
+type AlertRuleTemplatePayload struct {
+	// in:body
+	Body AlertRuleTemplate
+}
+
+// swagger:parameters RoutePostAlertRuleTemplateInstantiate
+type AlertRuleTemplateInstantiationPayload struct {
+	// in:body
+	Body AlertRuleTemplateInstantiation
+}
+
+// AlertRuleTemplate is an alert rule whose fields contain ${name} placeholders, which are replaced with the values of
+// its parameters when a rule is instantiated from it. Write $$ for a $ that does not start a placeholder.
+// swagger:model
+type AlertRuleTemplate struct {
+	// readonly: true
+	UID string `json:"uid"`
+	// example: Disk usage per team
+	Title      string                       `json:"title"`
+	Parameters []AlertRuleTemplateParameter `json:"parameters,omitempty"`
+	// The title, the UID, the folder, the rule group, the data sources of the queries, the values of the labels and of
+	// the annotations, the strings of the models of the queries and the contact point of the rule can contain
+	// placeholders. A string of a model that is only a placeholder of a number, e.g. "${threshold}", is replaced by the
+	// number.
+	Rule ProvisionedAlertRule `json:"rule"`
+	// readonly: true
+	Updated time.Time `json:"updated"`
+}
+
+// AlertRuleTemplateParameter is a parameter of an alert rule template.
+type AlertRuleTemplateParameter struct {
+	// example: team
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Value of the parameter if the instantiation does not set one. The parameter is required if it has no default.
+	Default *string `json:"default,omitempty"`
+}
+
+// swagger:model
+type AlertRuleTemplates []AlertRuleTemplate
+
+// AlertRuleTemplateInstantiation is the values of the parameters of an alert rule template.
+// swagger:model
+type AlertRuleTemplateInstantiation struct {
+	// example: {"team":"checkout","threshold":"90"}
+	Parameters map[string]string `json:"parameters"`
+}
@@ -0,0 +1,46 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/alert-rules/trash provisioning stable RouteGetDeletedAlertRules
+//
+// Get the deleted alert rules of the organization that can be restored, the latest deleted first.
+//
+//     Responses:
+//       200: DeletedAlertRules
+
+// swagger:route POST /v1/provisioning/alert-rules/trash/{UID}/restore provisioning stable RoutePostAlertRuleRestore
+//
+// Restore a deleted alert rule as it was when it was last deleted.
+//
+//     Responses:
+//       201: ProvisionedAlertRule
+//       400: ValidationError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:parameters RoutePostAlertRuleRestore
+type DeletedAlertRuleUIDReference struct {
+	// UID of the deleted alert rule
+	// in:path
+	UID string
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type DeletedAlertRules []DeletedAlertRule
+
+// DeletedAlertRule is an alert rule in the trash, which can be restored until the retention of the trash passes.
+// swagger:model
+type DeletedAlertRule struct {
+	UID        string     `json:"uid"`
+	Title      string     `json:"title"`
+	FolderUID  string     `json:"folderUID"`
+	RuleGroup  string     `json:"ruleGroup"`
+	Provenance Provenance `json:"provenance"`
+	// DeletedAt is the time the alert rule was deleted.
+	DeletedAt time.Time `json:"deletedAt"`
+}
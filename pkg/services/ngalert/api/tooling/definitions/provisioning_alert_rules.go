@@ -1,6 +1,11 @@
 package definitions
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -8,19 +13,34 @@ import (
 
 // swagger:route GET /v1/provisioning/alert-rules provisioning stable RouteGetAlertRules
 //
-// Get all the alert rules.
+// Get all the alert rules, or a page of them. Rules are ordered by folder, rule group and position in the group.
 //
 //     Responses:
 //       200: ProvisionedAlertRules
+//       400: ValidationError
 
 // swagger:route GET /v1/provisioning/alert-rules/export provisioning stable RouteGetAlertRulesExport
 //
 // Export all alert rules in provisioning file format.
 //
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//     - application/terraform+hcl
+//
 //     Responses:
 //       200: AlertingFileExport
 //       404: description: Not found.
 
+// swagger:route GET /v1/provisioning/alert-rules/search provisioning stable RouteSearchAlertRules
+//
+// Search alert rules by title, labels and annotations.
+//
+//     Responses:
+//       200: ProvisionedAlertRulesSearchResult
+//       400: ValidationError
+
 // swagger:route GET /v1/provisioning/alert-rules/{UID} provisioning stable RouteGetAlertRule
 //
 // Get a specific alert rule by UID.
@@ -37,6 +57,7 @@ import (
 //     - application/json
 //     - application/yaml
 //     - text/yaml
+//     - application/terraform+hcl
 //
 //     Responses:
 //       200: AlertingFileExport
@@ -63,6 +84,7 @@ import (
 //     Responses:
 //       200: ProvisionedAlertRule
 //       400: ValidationError
+//       409: ProvisioningError
 
 // swagger:route DELETE /v1/provisioning/alert-rules/{UID} provisioning stable RouteDeleteAlertRule
 //
@@ -71,6 +93,49 @@ import (
 //     Responses:
 //       204: description: The alert rule was deleted successfully.
 
+// swagger:parameters RouteGetAlertRulesExport RouteGetAlertRuleGroupExport RouteGetAlertRuleExport
+type AlertRulesExportFolderKeyParam struct {
+	// How rule groups refer to their folders: by title, or by UID with the title as an assertion that is verified when
+	// the file is provisioned, or by path, the titles of the nested folders from the root folder down to the folder of
+	// the group. Keying by UID prevents confusion when folder titles differ between environments. Keying by path keeps
+	// the folder hierarchy when the file is provisioned in another installation.
+	// in:query
+	// required:false
+	// default: title
+	// enum: title,uid,path
+	FolderKey string `json:"folderKey"`
+}
+
+// swagger:parameters RouteGetAlertRulesExport RouteGetAlertRuleGroupExport RouteGetAlertRuleExport
+type AlertRulesExportMetadataParam struct {
+	// Whether to write comments with the generation time, the source instance and the UIDs of the rules at the top of
+	// YAML exports. The comments are verified when the file is provisioned.
+	// in:query
+	// required:false
+	// default: false
+	Metadata bool `json:"metadata"`
+}
+
+// swagger:parameters RouteGetAlertRulesExport RouteGetAlertRuleGroupExport RouteGetAlertRuleExport
+type AlertRulesExportGroupVersionParam struct {
+	// Whether to write the version of each rule group, which can be given as the base version when the group is
+	// replaced, so that the changes made to it since the export are merged. It is not written to HCL exports.
+	// in:query
+	// required:false
+	// default: false
+	GroupVersion bool `json:"groupVersion"`
+}
+
+// swagger:parameters RouteGetAlertRulesExport RouteGetAlertRuleGroupExport RouteGetAlertRuleExport
+type AlertRulesExportRuleGroupIndexParam struct {
+	// Whether to write the index of each rule in its rule group, so that the order of the rules is explicit. It is not
+	// written to HCL exports.
+	// in:query
+	// required:false
+	// default: false
+	RuleGroupIndex bool `json:"ruleGroupIndex"`
+}
+
 // swagger:parameters RouteGetAlertRulesExport RouteGetRulesForExport
 type AlertRulesExportParameters struct {
 	ExportQueryParams
@@ -90,6 +155,80 @@ type AlertRulesExportParameters struct {
 	RuleUID string `json:"ruleUid"`
 }
 
+// swagger:parameters RouteSearchAlertRules
+type AlertRulesSearchParameters struct {
+	// Text to search for, case-insensitively, in the title, labels and annotations of alert rules
+	// in:query
+	// required:false
+	Query string `json:"query"`
+
+	// UIDs of folders to search in
+	// in:query
+	// required:false
+	FolderUID []string `json:"folderUid"`
+
+	// Maximum number of alert rules to return. Zero means no limit.
+	// in:query
+	// required:false
+	Limit int64 `json:"limit"`
+
+	// Page of results to return, starting at 1
+	// in:query
+	// required:false
+	Page int64 `json:"page"`
+}
+
+// swagger:parameters RouteGetAlertRules
+type AlertRulesPageParameters struct {
+	// Maximum number of alert rules to return. Zero means no limit.
+	// in:query
+	// required:false
+	Limit int64 `json:"limit"`
+
+	// Page of results to return, starting at 1
+	// in:query
+	// required:false
+	Page int64 `json:"page"`
+}
+
+// swagger:parameters RouteGetAlertRules
+type AlertRulesFilterParameters struct {
+	// UIDs of the folders of the alert rules
+	// in:query
+	// required:false
+	FolderUID []string `json:"folderUid"`
+
+	// Prefix of the name of the rule groups of the alert rules
+	// in:query
+	// required:false
+	GroupPrefix string `json:"groupPrefix"`
+
+	// Label matchers that the labels of the alert rules must match, JSON-encoded like {"Name":"team","Value":"db","Type":0}
+	// in:query
+	// required:false
+	Matcher []string `json:"matcher"`
+
+	// Provenances of the alert rules, one of none, api or file
+	// in:query
+	// required:false
+	Provenance []string `json:"provenance"`
+
+	// Whether to exclude the alert rules of archived rule groups, to include them, or to return only them
+	// in:query
+	// required:false
+	// default: exclude
+	// enum: exclude,include,only
+	Archived string `json:"archived"`
+}
+
+// swagger:model
+type ProvisionedAlertRulesSearchResult struct {
+	TotalCount int64                 `json:"totalCount"`
+	Page       int64                 `json:"page"`
+	Limit      int64                 `json:"limit"`
+	Rules      ProvisionedAlertRules `json:"rules"`
+}
+
 // swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule RouteGetAlertRuleExport
 type AlertRuleUIDReference struct {
 	// Alert rule UID
@@ -103,12 +242,59 @@ type AlertRulePayload struct {
 	Body ProvisionedAlertRule
 }
 
-// swagger:parameters RoutePostAlertRule RoutePutAlertRule RouteDeleteAlertRule RoutePutAlertRuleGroup
+// swagger:parameters RoutePostAlertRule RoutePutAlertRule RouteDeleteAlertRule RoutePutAlertRuleGroup RoutePostAlertRuleGroupClone RoutePostAlertRuleGroupGenerate RoutePostAlertRuleGroupArchive RoutePostAlertRuleGroupUnarchive RoutePostAlertRuleGroupMove RoutePostAlertRuleTemplateInstantiate RoutePostAlertRuleGroupJob RoutePutAlertRuleGroupInterval RoutePostPrometheusRuleGroupImport
 type AlertRuleHeaders struct {
 	// in:header
 	XDisableProvenance string `json:"X-Disable-Provenance"`
 }
 
+// swagger:parameters RoutePostAlertRule RoutePutAlertRule RoutePutAlertRuleGroup RoutePostAlertRuleGroupGenerate
+type AlertRuleAnalysisHeaders struct {
+	// If present, the written rules are analyzed for obviously ill-formed conditions, and the warnings are returned in Warning headers of the response
+	// in:header
+	XAnalyzeRules string `json:"X-Analyze-Rules"`
+}
+
+// swagger:parameters RoutePutAlertRuleGroup RoutePostAlertRuleGroupJob
+type AlertRuleGroupBakePeriodParam struct {
+	// Duration, e.g. 1h, during which the rules created by the request are evaluated and their state is recorded, but
+	// their alerts are not sent. The alerts that started firing during the bake period are never sent, so that enabling
+	// many rules at once does not notify of conditions that already hold. The rules that already exist are not affected.
+	// in:query
+	// required:false
+	BakePeriod string `json:"bakePeriod"`
+}
+
+// swagger:parameters RoutePutAlertRuleGroup RoutePostAlertRuleGroupJob
+type AlertRuleGroupRuleMovesParam struct {
+	// What to do with the rules of the payload that belong to another rule group: move them to this group, or reject
+	// the request with a conflict that describes both locations of the first of them.
+	// in:query
+	// required:false
+	// default: move
+	// enum: move,reject
+	RuleMoves string `json:"ruleMoves"`
+}
+
+// swagger:parameters RoutePutAlertRule RoutePutAlertRuleGroup RoutePostAlertRuleGroupJob
+type AlertRuleForceParam struct {
+	// Write the rules of the payload even if they were changed since their versions were read.
+	// in:query
+	// required:false
+	Force bool `json:"force"`
+}
+
+// swagger:parameters RoutePutAlertRuleGroup RoutePostAlertRuleGroupJob
+type AlertRuleGroupBaseVersionParam struct {
+	// Version of the rule group the payload is based on, as returned when the group is read or exported. If it is
+	// set, the payload is merged with the changes made to the group since that version instead of overwriting them.
+	// Changes of the same rule or of the settings of the group on both sides are conflicts, and the group is not
+	// changed.
+	// in:query
+	// required:false
+	BaseVersion string `json:"baseVersion"`
+}
+
 // swagger:model
 type ProvisionedAlertRules []ProvisionedAlertRule
 
@@ -129,6 +315,10 @@ type ProvisionedAlertRule struct {
 	// maxLength: 190
 	// example: eval_group_1
 	RuleGroup string `json:"ruleGroup"`
+	// Position of the rule in its rule group, starting at 1. The rules of a written rule group are ordered by their
+	// indexes, which must then be 1 to the number of rules, or by their positions in the payload if none has one.
+	// example: 1
+	RuleGroupIndex int `json:"ruleGroupIndex,omitempty"`
 	// required: true
 	// minLength: 1
 	// maxLength: 190
@@ -142,12 +332,16 @@ type ProvisionedAlertRule struct {
 	Data []AlertQuery `json:"data"`
 	// readonly: true
 	Updated time.Time `json:"updated,omitempty"`
+	// Version of the rule, incremented by each change of it. If it is set when the rule is written, the write fails
+	// with a conflict if the rule was changed since this version was read, unless it is forced.
+	// example: 3
+	Version int64 `json:"version,omitempty"`
 	// required: true
 	NoDataState NoDataState `json:"noDataState"`
 	// required: true
 	ExecErrState ExecutionErrorState `json:"execErrState"`
 	// required: true
-	For model.Duration `json:"for"`
+	For DurationOrSeconds `json:"for"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -158,6 +352,84 @@ type ProvisionedAlertRule struct {
 	IsPaused bool `json:"isPaused"`
 	// example: {"receiver":"email","group_by":["alertname","grafana_folder","cluster"],"group_wait":"30s","group_interval":"1m","repeat_interval":"4d","mute_time_intervals":["Weekends","Holidays"]}
 	NotificationSettings *AlertRuleNotificationSettings `json:"notification_settings"`
+	// Settings of other receivers to send the notifications to, each with its own optional settings. A receiver can be
+	// used only once by a rule.
+	AdditionalNotificationSettings []AlertRuleNotificationSettings `json:"additional_notification_settings,omitempty"`
+	// Time at which the rule expires. Expired rules are paused or deleted, depending on the configuration of the
+	// server, and an AlertRuleExpired alert with the labels of the rule is sent.
+	// example: 2024-07-01T00:00:00Z
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Interval in seconds at which the rule is evaluated instead of the interval of its rule group. It must be a
+	// multiple of the base interval of the server. Zero or unset means that the rule uses the interval of the group.
+	// example: 300
+	IntervalOverride int64 `json:"intervalOverride,omitempty"`
+	// Timeout of the evaluation of the queries and expressions of the rule instead of the evaluation timeout of the
+	// server, e.g. for slow SQL queries. It must not be longer than the maximum evaluation timeout of the server. Zero
+	// or unset means that the rule uses the evaluation timeout of the server.
+	// example: 2m
+	EvaluationTimeout DurationOrSeconds `json:"evaluationTimeout,omitempty"`
+	// Makes the rule a recording rule, which writes the result of one of its queries or expressions as a series
+	// instead of firing alerts. The condition of a recording rule is not used and can be empty.
+	Record *Record `json:"record,omitempty"`
+	// How long the alerts of the rule are pending before they fire. This is the `for` duration rounded up to a whole
+	// number of evaluation intervals of the rule.
+	// readonly: true
+	// example: 5m
+	EffectivePendingPeriod model.Duration `json:"effectivePendingPeriod,omitempty"`
+	// Current state of the alerts of the rule. It is returned only when it is requested with the withState parameter.
+	// readonly: true
+	State *AlertRuleStateSummary `json:"state,omitempty"`
+}
+
+// Record is the series that a recording rule writes.
+// swagger:model
+type Record struct {
+	// Name of the metric of the written series. It must be a valid Prometheus metric name.
+	// required: true
+	// example: grafana_disk_usage_ratio
+	Metric string `json:"metric" yaml:"metric"`
+	// RefID of the query or expression whose result is written.
+	// required: true
+	// example: A
+	From string `json:"from" yaml:"from"`
+	// UID of the Prometheus-compatible data source the series are written to.
+	// required: true
+	// example: prometheus-1
+	TargetDatasourceUID string `json:"targetDatasourceUid" yaml:"targetDatasourceUid"`
+}
+
+// AlertRuleStateSummary is the current state of the alerts of an alert rule.
+// swagger:model
+type AlertRuleStateSummary struct {
+	// Number of alerts of the rule that are normal.
+	Normal int64 `json:"normal"`
+	// Number of alerts of the rule that are pending.
+	Pending int64 `json:"pending"`
+	// Number of alerts of the rule that are firing.
+	Firing int64 `json:"firing"`
+	// Health of the rule, either ok, error or nodata.
+	// example: ok
+	Health string `json:"health"`
+	// Error of the last evaluation, if it failed.
+	LastError string `json:"lastError,omitempty"`
+	// Time of the last evaluation. It is not set if the rule was not evaluated yet.
+	LastEvaluation *time.Time `json:"lastEvaluation,omitempty"`
+}
+
+// swagger:parameters RouteGetAlertRules RouteGetAlertRuleGroup
+type AlertRuleStateParam struct {
+	// Whether to return the current state of the alerts of each rule.
+	// in: query
+	// required: false
+	WithState bool `json:"withState"`
+}
+
+// swagger:parameters RouteGetAlertRules RouteGetAlertRule RouteSearchAlertRules
+type AlertRuleFieldsParam struct {
+	// JSON names of the fields of the alert rules to return, e.g. uid,title,labels. All the fields are returned if none is selected.
+	// in: query
+	// required: false
+	Fields []string `json:"fields"`
 }
 
 // swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -185,6 +457,7 @@ type ProvisionedAlertRule struct {
 //     - application/json
 //     - application/yaml
 //     - text/yaml
+//     - application/terraform+hcl
 //
 //     Responses:
 //       200: AlertingFileExport
@@ -200,25 +473,181 @@ type ProvisionedAlertRule struct {
 //     Responses:
 //       200: AlertRuleGroup
 //       400: ValidationError
+//       409: ProvisioningError
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport RouteDeleteAlertRuleGroup
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/cost-estimate provisioning stable RoutePostAlertRuleGroupCostEstimate
+//
+// Estimate the cost of the evaluations of a rule group without saving it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: RuleGroupCostEstimate
+//       400: ValidationError
+
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/clone provisioning stable RoutePostAlertRuleGroupClone
+//
+// Copy a rule group to another folder or organization, as new rules.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: AlertRuleGroup
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/generate provisioning stable RoutePostAlertRuleGroupGenerate
+//
+// Replace a rule group with one rule per row of an inventory, generated from a template rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       400: ValidationError
+//       409: GenericPublicError
+
+// swagger:route PUT /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/interval provisioning stable RoutePutAlertRuleGroupInterval
+//
+// Update the evaluation interval of the rules of a rule group, without changing the rules otherwise. It is allowed to
+// the users who can update the rules of the folder, or only the interval of its rule groups.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: RuleGroupInterval
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+//       409: ProvisioningError
+
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/jobs provisioning stable RoutePostAlertRuleGroupJob
+//
+// Replace a rule group in the background, in transactions of a limited number of changes. Use it for the updates that
+// exceed the limit of changes of a single update.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: RuleGroupJob
+//       400: ValidationError
+//       409: ProvisioningError
+//       503: description: Too many jobs are waiting to run.
+
+// swagger:route GET /v1/provisioning/rule-group-jobs/{UID} provisioning stable RouteGetRuleGroupJob
+//
+// Get the status of a rule group job.
+//
+//     Responses:
+//       200: RuleGroupJob
+//       404: description: Not found.
+
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport RouteDeleteAlertRuleGroup RoutePostAlertRuleGroupCostEstimate RoutePostAlertRuleGroupClone RoutePostAlertRuleGroupGenerate RoutePostAlertRuleGroupArchive RoutePostAlertRuleGroupUnarchive RoutePostAlertRuleGroupMove RoutePostAlertRuleGroupJob RoutePutAlertRuleGroupInterval RoutePostPrometheusRuleGroupImport
 type FolderUIDPathParam struct {
 	// in:path
 	FolderUID string `json:"FolderUID"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport RouteDeleteAlertRuleGroup
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport RouteDeleteAlertRuleGroup RoutePostAlertRuleGroupCostEstimate RoutePostAlertRuleGroupClone RoutePostAlertRuleGroupGenerate RoutePostAlertRuleGroupArchive RoutePostAlertRuleGroupUnarchive RoutePostAlertRuleGroupMove RoutePostAlertRuleGroupJob RoutePutAlertRuleGroupInterval
 type RuleGroupPathParam struct {
 	// in:path
 	Group string `json:"Group"`
 }
 
-// swagger:parameters RoutePutAlertRuleGroup
+// swagger:parameters RoutePutAlertRuleGroup RoutePostAlertRuleGroupCostEstimate RoutePostAlertRuleGroupJob
 type AlertRuleGroupPayload struct {
 	// in:body
 	Body AlertRuleGroup
 }
 
+// swagger:parameters RoutePutAlertRuleGroupInterval
+type RuleGroupIntervalPayload struct {
+	// in:body
+	Body RuleGroupInterval
+}
+
+// swagger:model
+type RuleGroupInterval struct {
+	// Evaluation interval of the rule group, in seconds. It must be a multiple of the base interval of the scheduler.
+	// example: 60
+	Interval int64 `json:"interval"`
+}
+
+// swagger:parameters RouteGetRuleGroupJob
+type RuleGroupJobUIDParam struct {
+	// in:path
+	UID string `json:"UID"`
+}
+
+// RuleGroupJob is a replacement of a rule group that is applied in the background.
+// swagger:model
+type RuleGroupJob struct {
+	UID       string `json:"uid"`
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// enum: pending,running,succeeded,failed
+	Status string `json:"status"`
+	// Number of rules created, updated and deleted by the job.
+	Changes int `json:"changes"`
+	// Number of changes that are committed. The changes that are committed are kept if the job fails.
+	Applied int       `json:"applied"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// swagger:parameters RoutePostAlertRuleGroupClone
+type AlertRuleGroupClonePayload struct {
+	// in:body
+	Body AlertRuleGroupClone
+}
+
+// AlertRuleGroupClone is the target of the copy of a rule group and the changes made to the copied rules.
+// swagger:model
+type AlertRuleGroupClone struct {
+	// Organization of the copy, the organization of the request if it is not set. Copying a group to another
+	// organization requires a Grafana server admin.
+	OrgID int64 `json:"orgId,omitempty"`
+	// example: my-other-folder
+	FolderUID string `json:"folderUid"`
+	// Title of the copy, the title of the copied group if it is not set.
+	Title string `json:"title,omitempty"`
+	// Data source UIDs of the copied queries mapped to the data source UIDs of the new queries. Queries of data sources
+	// that are not mapped are copied as they are.
+	DatasourceUIDs map[string]string `json:"datasourceUids,omitempty"`
+	// Labels set on the new rules, replacing the labels with the same names. Labels with an empty value are removed.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// swagger:parameters RoutePostAlertRuleGroupGenerate
+type AlertRuleGroupGenerationPayload struct {
+	// in:body
+	Body AlertRuleGroupGeneration
+}
+
+// AlertRuleGroupGeneration is a template rule and the inventory of the rules generated from it.
+// swagger:model
+type AlertRuleGroupGeneration struct {
+	// Interval of the group, the interval of the existing group or the default interval if it is not set.
+	Interval int64 `json:"interval,omitempty"`
+	// The ${name} placeholders of the template are replaced with the columns of each row of the inventory, and $$ with
+	// $. A string of a model that is only a placeholder of a number, e.g. "${threshold}", is replaced by the number.
+	// Rules of the existing group with the title of a generated rule are updated.
+	Template ProvisionedAlertRule `json:"template"`
+	// Rows of the inventory, as objects of column names and values.
+	// example: [{"service":"checkout","threshold":0.5},{"service":"search","threshold":2}]
+	Inventory []map[string]any `json:"inventory,omitempty"`
+	// Rows of the inventory in CSV format, with a header of column names. It is used if inventory is not set.
+	InventoryCSV string `json:"inventoryCsv,omitempty"`
+}
+
 // swagger:model
 type AlertRuleGroupMetadata struct {
 	Interval int64 `json:"interval"`
@@ -226,21 +655,159 @@ type AlertRuleGroupMetadata struct {
 
 // swagger:model
 type AlertRuleGroup struct {
-	Title     string                 `json:"title"`
-	FolderUID string                 `json:"folderUid"`
-	Interval  int64                  `json:"interval"`
-	Rules     []ProvisionedAlertRule `json:"rules"`
+	Title            string            `json:"title"`
+	FolderUID        string            `json:"folderUid"`
+	Interval         SecondsOrDuration `json:"interval"`
+	DataAvailability *DataAvailability `json:"dataAvailability,omitempty"`
+	// Scheduler shard the rules of the group are pinned to in sharded setups. The rules are evaluated only by the
+	// instances of this shard. If it is not set, they are evaluated by the instances that are not sharded. It must be
+	// one of the scheduler shards of the configuration.
+	// example: heavy
+	ShardAffinity string `json:"shardAffinity,omitempty"`
+	// External incident-management tools that are called when the alerts of the rules of the group start firing
+	// and when they are resolved.
+	IncidentHooks []IncidentHook `json:"incidentHooks,omitempty"`
+	// Where the rule group is managed. It is stored with the provenance of the rules, and the UI shows it to the users
+	// who cannot change the rules. Replacing the group without it removes it.
+	ManagedBy *ManagedBy `json:"managedBy,omitempty"`
+	// Whether the rule group is archived. It is returned when the group is read, and is changed by archiving or
+	// unarchiving the group.
+	// readonly: true
+	Archived bool `json:"archived,omitempty"`
+	// Expiration of the rules of the group that do not have their own. It is not returned, the rules have it.
+	// example: 2024-07-01T00:00:00Z
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Version of the rule group, returned when the group is read. If it is set when the group is replaced, the
+	// replacement fails with a conflict if the group was changed since, e.g. if a rule was added to it, unless it is
+	// forced or a base version is given. It can also be given as the base version.
+	Version string                 `json:"version,omitempty"`
+	Rules   []ProvisionedAlertRule `json:"rules"`
+	// Values that the server wrote to the rules instead of the given ones when the group was replaced, e.g. the UIDs
+	// of the new rules. Writing them back to the rules makes the next replacement with the same rules a no-op.
+	// readonly: true
+	ServerDefaults []ServerDefault `json:"serverDefaults,omitempty"`
+}
+
+// RuleGroupCostEstimate is the approximate cost of the evaluations of a rule group. Costs are relative scores, one being
+// a Prometheus query that returns at most 1000 data points per series. Expressions are not counted.
+// swagger:model
+type RuleGroupCostEstimate struct {
+	// Sum of the costs of the rules that are not paused.
+	// example: 4.6
+	CostPerEvaluation float64 `json:"costPerEvaluation"`
+	// example: 1440
+	EvaluationsPerDay int64 `json:"evaluationsPerDay"`
+	// Number of data source queries that the rules that are not paused run in a day.
+	// example: 2880
+	QueriesPerDay int64              `json:"queriesPerDay"`
+	Rules         []RuleCostEstimate `json:"rules"`
+}
+
+// RuleCostEstimate is the approximate cost of one evaluation of an alert rule.
+// swagger:model
+type RuleCostEstimate struct {
+	UID      string              `json:"uid,omitempty"`
+	Title    string              `json:"title"`
+	IsPaused bool                `json:"isPaused"`
+	Cost     float64             `json:"cost"`
+	Queries  []QueryCostEstimate `json:"queries"`
+}
+
+// QueryCostEstimate is the approximate cost of a data source query of an alert rule.
+// swagger:model
+type QueryCostEstimate struct {
+	RefID          string `json:"refId"`
+	DatasourceUID  string `json:"datasourceUid"`
+	DatasourceType string `json:"datasourceType,omitempty"`
+	// example: 10m
+	TimeRange model.Duration `json:"timeRange"`
+	// example: 15s
+	Step model.Duration `json:"step"`
+	// Number of data points per series that the query returns.
+	// example: 40
+	DataPoints int64   `json:"dataPoints"`
+	Cost       float64 `json:"cost"`
+}
+
+// ServerDefault is a value that the server wrote to a rule of a rule group instead of the given value.
+// swagger:model
+type ServerDefault struct {
+	// Position of the rule in the rules of the group.
+	// example: 0
+	RuleIndex int `json:"ruleIndex"`
+	// Name of the field of the rule.
+	// example: uid
+	Field string `json:"field"`
+	// example: bd4k6m1ra9fy8e
+	Value any `json:"value"`
+}
+
+// DataAvailability delays the evaluation time of a rule group at the start of each period, while the data of the
+// period is not complete, e.g. for data that is written by nightly batches. The rules are evaluated as of the end of
+// the previous period instead.
+// swagger:model
+type DataAvailability struct {
+	// Length of the periods. Periods are aligned on UTC.
+	// example: 1h
+	Period model.Duration `json:"period" yaml:"period"`
+	// Time after the start of each period during which the rules are evaluated as of the start of the period. It must be
+	// shorter than the period.
+	// example: 5m
+	Delay model.Duration `json:"delay" yaml:"delay"`
+}
+
+// IncidentHook is an external incident-management tool. It receives a POST request with the alert when the alert
+// starts firing, with the action "create", and when it is resolved, with the action "resolve".
+// swagger:model
+type IncidentHook struct {
+	// Name of the hook. It is unique in the group.
+	// example: opsgenie
+	Name string `json:"name" yaml:"name"`
+	// example: https://incidents.example.com/api/alerts
+	URL string `json:"url" yaml:"url"`
+	// Credentials sent as a Bearer token. They must be a reference to a secret of an external secret manager.
+	// example: ref+vault://secret/data/alerting/incidents#token
+	AuthorizationCredentials string `json:"authorizationCredentials,omitempty" yaml:"authorizationCredentials,omitempty"`
+}
+
+// ManagedBy describes the source that manages a provisioned rule group, e.g. a file in a repository.
+// swagger:model
+type ManagedBy struct {
+	// Human-readable description of the source.
+	// required: true
+	// example: Managed by repository alerting-rules, file teams/checkout.yaml
+	Title string `json:"title" yaml:"title"`
+	// Link to the source. It must be an absolute http or https URL.
+	// example: https://github.com/example/alerting-rules/blob/main/teams/checkout.yaml
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
 }
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
 type AlertRuleGroupExport struct {
-	OrgID           int64             `json:"orgId" yaml:"orgId" hcl:"org_id"`
-	Name            string            `json:"name" yaml:"name" hcl:"name"`
-	Folder          string            `json:"folder" yaml:"folder"`
-	FolderUID       string            `json:"-" yaml:"-" hcl:"folder_uid"`
-	Interval        model.Duration    `json:"interval" yaml:"interval"`
-	IntervalSeconds int64             `json:"-" yaml:"-" hcl:"interval_seconds"`
-	Rules           []AlertRuleExport `json:"rules" yaml:"rules" hcl:"rule,block"`
+	OrgID     int64  `json:"orgId" yaml:"orgId" hcl:"org_id"`
+	Name      string `json:"name" yaml:"name" hcl:"name"`
+	Folder    string `json:"folder,omitempty" yaml:"folder,omitempty"`
+	FolderUID string `json:"-" yaml:"-" hcl:"folder_uid"`
+	// FolderUIDKey refers to the folder by UID instead of Folder when groups are exported keyed by folder UID.
+	FolderUIDKey string `json:"folderUid,omitempty" yaml:"folderUid,omitempty"`
+	// FolderTitle is the title the folder is expected to have when groups are exported keyed by folder UID. It is
+	// verified when the file is provisioned.
+	FolderTitle string `json:"folderTitle,omitempty" yaml:"folderTitle,omitempty"`
+	// FolderPath refers to the folder by the titles of the nested folders from the root folder down to the folder,
+	// instead of Folder, when groups are exported keyed by folder path.
+	FolderPath      []string       `json:"folderPath,omitempty" yaml:"folderPath,omitempty"`
+	Interval        model.Duration `json:"interval" yaml:"interval"`
+	IntervalSeconds int64          `json:"-" yaml:"-" hcl:"interval_seconds"`
+	// DataAvailability is not exported for HCL because the Terraform provider does not support it.
+	DataAvailability *DataAvailability `json:"dataAvailability,omitempty" yaml:"dataAvailability,omitempty"`
+	// ShardAffinity is not exported for HCL because the Terraform provider does not support it.
+	ShardAffinity string `json:"shardAffinity,omitempty" yaml:"shardAffinity,omitempty"`
+	// IncidentHooks are not exported for HCL because the Terraform provider does not support them.
+	IncidentHooks []IncidentHook `json:"incidentHooks,omitempty" yaml:"incidentHooks,omitempty"`
+	// Version is the version of the group, which can be given as the base version when the group is replaced. It is
+	// exported only if it is requested.
+	Version string            `json:"version,omitempty" yaml:"version,omitempty"`
+	Rules   []AlertRuleExport `json:"rules" yaml:"rules" hcl:"rule,block"`
 }
 
 // AlertRuleExport is the provisioned file export of models.AlertRule.
@@ -257,11 +824,21 @@ type AlertRuleExport struct {
 	// ForString is used to:
 	// - Only export the for field for HCL if it is non-zero.
 	// - Format the Prometheus model.Duration type properly for HCL.
-	ForString            *string                              `json:"-" yaml:"-" hcl:"for"`
-	Annotations          *map[string]string                   `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
-	Labels               *map[string]string                   `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
-	IsPaused             bool                                 `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
-	NotificationSettings *AlertRuleNotificationSettingsExport `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
+	ForString                      *string                               `json:"-" yaml:"-" hcl:"for"`
+	Annotations                    *map[string]string                    `json:"annotations,omitempty" yaml:"annotations,omitempty" hcl:"annotations"`
+	Labels                         *map[string]string                    `json:"labels,omitempty" yaml:"labels,omitempty" hcl:"labels"`
+	IsPaused                       bool                                  `json:"isPaused" yaml:"isPaused" hcl:"is_paused"`
+	NotificationSettings           *AlertRuleNotificationSettingsExport  `json:"notification_settings,omitempty" yaml:"notification_settings,omitempty" hcl:"notification_settings,block"`
+	AdditionalNotificationSettings []AlertRuleNotificationSettingsExport `json:"additional_notification_settings,omitempty" yaml:"additional_notification_settings,omitempty" hcl:"additional_notification_settings,block"`
+	// RuleGroupIndex is the position of the rule in its group, starting at 1. It is exported only if it is requested.
+	RuleGroupIndex int `json:"ruleGroupIndex,omitempty" yaml:"ruleGroupIndex,omitempty"`
+	// IntervalOverride is the interval of the rule in seconds if it does not use the one of its group. Like the
+	// evaluation timeout, it is not exported for HCL.
+	IntervalOverride int64 `json:"intervalOverride,omitempty" yaml:"intervalOverride,omitempty"`
+	// EvaluationTimeout is not exported for HCL because the Terraform provider does not support it.
+	EvaluationTimeout model.Duration `json:"evaluationTimeout,omitempty" yaml:"evaluationTimeout,omitempty"`
+	// Record is not exported for HCL because the Terraform provider does not support it.
+	Record *Record `json:"record,omitempty" yaml:"record,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -289,3 +866,111 @@ type AlertRuleNotificationSettingsExport struct {
 	RepeatInterval    *string  `yaml:"repeat_interval,omitempty" json:"repeat_interval,omitempty" hcl:"repeat_interval,optional"`
 	MuteTimeIntervals []string `yaml:"mute_time_intervals,omitempty" json:"mute_time_intervals,omitempty" hcl:"mute_time_intervals"`
 }
+
+// maxDurationSeconds is the largest number of seconds that a duration can hold.
+const maxDurationSeconds = math.MaxInt64 / int64(time.Second)
+
+// ParseDurationOrSeconds parses a duration given either as a duration string, e.g. 5m or 1h30m, or as a whole number
+// of seconds, e.g. 300. Both formats are common in the rules that are imported from files and other tools.
+func ParseDurationOrSeconds(s string) (model.Duration, error) {
+	s = strings.TrimSpace(s)
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return model.ParseDuration(s)
+	}
+	if seconds < 0 || seconds > maxDurationSeconds {
+		return 0, fmt.Errorf("number of seconds %q is out of range", s)
+	}
+	return model.Duration(time.Duration(seconds) * time.Second), nil
+}
+
+// DurationOrSeconds is a duration that is marshaled as a duration string, e.g. 5m, and that is unmarshaled from either a
+// duration string, see ParseDurationOrSeconds, or a JSON number of seconds.
+// swagger:type string
+type DurationOrSeconds model.Duration
+
+func (d DurationOrSeconds) String() string {
+	return model.Duration(d).String()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d DurationOrSeconds) MarshalJSON() ([]byte, error) {
+	return model.Duration(d).MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler. A null duration is left unchanged.
+func (d *DurationOrSeconds) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalDurationOrSeconds(b)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		*d = DurationOrSeconds(*v)
+	}
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (d DurationOrSeconds) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *DurationOrSeconds) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	v, err := ParseDurationOrSeconds(s)
+	if err != nil {
+		return err
+	}
+	*d = DurationOrSeconds(v)
+	return nil
+}
+
+// SecondsOrDuration is a whole number of seconds that is marshaled as a JSON number and that is unmarshaled from either
+// a JSON number or a duration string, see ParseDurationOrSeconds.
+type SecondsOrDuration int64
+
+// UnmarshalJSON implements json.Unmarshaler. A null number of seconds is left unchanged.
+func (s *SecondsOrDuration) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalDurationOrSeconds(b)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		*s = SecondsOrDuration(time.Duration(*v) / time.Second)
+	}
+	return nil
+}
+
+// unmarshalDurationOrSeconds unmarshals a duration given either as a JSON string, see ParseDurationOrSeconds, or as a
+// JSON number of seconds. It returns nil if the duration is missing or null.
+func unmarshalDurationOrSeconds(b json.RawMessage) (*model.Duration, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	switch value := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		d, err := ParseDurationOrSeconds(value)
+		if err != nil {
+			return nil, err
+		}
+		return &d, nil
+	case float64:
+		if value < 0 || value > float64(maxDurationSeconds) {
+			return nil, fmt.Errorf("number of seconds %v is out of range", value)
+		}
+		d := model.Duration(value * float64(time.Second))
+		return &d, nil
+	default:
+		return nil, fmt.Errorf("invalid duration %v", v)
+	}
+}
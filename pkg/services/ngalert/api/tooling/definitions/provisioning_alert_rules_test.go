@@ -0,0 +1,90 @@
+package definitions
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDurationOrSeconds(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected time.Duration
+		err      bool
+	}{
+		{input: "5m", expected: 5 * time.Minute},
+		{input: "1h30m", expected: 90 * time.Minute},
+		{input: "2d", expected: 48 * time.Hour},
+		{input: "300", expected: 5 * time.Minute},
+		{input: " 60 ", expected: time.Minute},
+		{input: "0", expected: 0},
+		{input: "-60", err: true},
+		{input: "99999999999999999", err: true},
+		{input: "10x", err: true},
+		{input: "", err: true},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			d, err := ParseDurationOrSeconds(tc.input)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, time.Duration(d))
+		})
+	}
+}
+
+func TestAlertRuleGroup_UnmarshalJSON(t *testing.T) {
+	t.Run("durations can be strings or numbers of seconds", func(t *testing.T) {
+		var group AlertRuleGroup
+		err := json.Unmarshal([]byte(`{
+			"title": "group",
+			"interval": "1m30s",
+			"rules": [
+				{"title": "a", "for": 300, "notification_settings": {"receiver": "r", "group_wait": 30, "group_interval": "5m", "repeat_interval": "14400"}},
+				{"title": "b", "for": "1h30m", "evaluationTimeout": 120}
+			]
+		}`), &group)
+		require.NoError(t, err)
+		require.Equal(t, "group", group.Title)
+		require.Equal(t, SecondsOrDuration(90), group.Interval)
+		require.Len(t, group.Rules, 2)
+		require.Equal(t, "a", group.Rules[0].Title)
+		require.Equal(t, DurationOrSeconds(5*time.Minute), group.Rules[0].For)
+		require.Equal(t, DurationOrSeconds(90*time.Minute), group.Rules[1].For)
+		require.Equal(t, DurationOrSeconds(2*time.Minute), group.Rules[1].EvaluationTimeout)
+
+		ns := group.Rules[0].NotificationSettings
+		require.NotNil(t, ns)
+		require.Equal(t, "r", ns.Receiver)
+		require.Equal(t, DurationOrSeconds(30*time.Second), *ns.GroupWait)
+		require.Equal(t, DurationOrSeconds(5*time.Minute), *ns.GroupInterval)
+		require.Equal(t, DurationOrSeconds(4*time.Hour), *ns.RepeatInterval)
+	})
+
+	t.Run("missing durations are not set", func(t *testing.T) {
+		var group AlertRuleGroup
+		err := json.Unmarshal([]byte(`{"title": "group", "interval": 60, "rules": [{"title": "a", "notification_settings": {"receiver": "r", "group_wait": null}}]}`), &group)
+		require.NoError(t, err)
+		require.Equal(t, SecondsOrDuration(60), group.Interval)
+		require.Zero(t, group.Rules[0].For)
+		require.Nil(t, group.Rules[0].NotificationSettings.GroupWait)
+		require.Nil(t, group.Rules[0].NotificationSettings.GroupInterval)
+	})
+
+	t.Run("invalid durations are rejected", func(t *testing.T) {
+		for _, body := range []string{
+			`{"interval": "10x"}`,
+			`{"interval": -1}`,
+			`{"rules": [{"for": true}]}`,
+			`{"rules": [{"evaluationTimeout": "later"}]}`,
+			`{"rules": [{"notification_settings": {"receiver": "r", "repeat_interval": "forever"}}]}`,
+		} {
+			var group AlertRuleGroup
+			require.Error(t, json.Unmarshal([]byte(body), &group), body)
+		}
+	})
+}
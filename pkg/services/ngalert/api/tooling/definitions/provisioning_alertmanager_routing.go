@@ -0,0 +1,88 @@
+package definitions
+
+import (
+	"fmt"
+)
+
+// swagger:route GET /v1/provisioning/alertmanager-routing provisioning stable RouteGetAlertmanagerRouting
+//
+// Get the settings that decide which Alertmanagers handle the alerts of the organization.
+//
+//     Responses:
+//       200: AlertmanagerRouting
+
+// swagger:route PUT /v1/provisioning/alertmanager-routing provisioning stable RoutePutAlertmanagerRouting
+//
+// Set the settings that decide which Alertmanagers handle the alerts of the organization.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+
+// swagger:route GET /v1/provisioning/alertmanager-routing/export provisioning stable RouteGetAlertmanagerRoutingExport
+//
+// Export the settings that decide which Alertmanagers handle the alerts of the organization in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//
+//     Responses:
+//       200: AlertingFileExport
+
+// swagger:parameters RoutePutAlertmanagerRouting
+type AlertmanagerRoutingPayload struct {
+	// in:body
+	Body AlertmanagerRouting
+}
+
+// swagger:parameters RoutePutAlertmanagerRouting
+type AlertmanagerRoutingHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// AlertmanagerRouting decides which Alertmanagers handle the alerts of an organization.
+// swagger:model
+type AlertmanagerRouting struct {
+	// SendAlertsTo selects the internal Alertmanager, the external Alertmanagers configured as data sources or both.
+	// example: all
+	SendAlertsTo AlertmanagersChoice `json:"sendAlertsTo" yaml:"sendAlertsTo"`
+	// ExternalMatchers splits the alerts between the Alertmanagers when alerts are sent to all of them. Alerts that
+	// match all the matchers are sent to the external Alertmanagers only, the other alerts to the internal Alertmanager
+	// only.
+	ExternalMatchers ObjectMatchers `json:"externalMatchers,omitempty" yaml:"externalMatchers,omitempty"`
+	Provenance       Provenance     `json:"provenance,omitempty" yaml:"-"`
+}
+
+func (r *AlertmanagerRouting) ResourceType() string {
+	return "alertmanagerRouting"
+}
+
+func (r *AlertmanagerRouting) ResourceID() string {
+	return ""
+}
+
+func (r *AlertmanagerRouting) Validate() error {
+	switch r.SendAlertsTo {
+	case "":
+		r.SendAlertsTo = AllAlertmanagers
+	case AllAlertmanagers, InternalAlertmanager, ExternalAlertmanagers:
+	default:
+		return fmt.Errorf("invalid alertmanager choice '%s'", r.SendAlertsTo)
+	}
+	if len(r.ExternalMatchers) > 0 && r.SendAlertsTo != AllAlertmanagers {
+		return fmt.Errorf("external matchers can only be used when alerts are sent to all alertmanagers")
+	}
+	return nil
+}
+
+// AlertmanagerRoutingExport is the provisioned file export of alerting.AlertmanagerRoutingV1.
+type AlertmanagerRoutingExport struct {
+	OrgID               int64 `json:"orgId" yaml:"orgId"`
+	AlertmanagerRouting `json:",inline" yaml:",inline"`
+}
@@ -0,0 +1,110 @@
+package definitions
+
+// swagger:route POST /v1/provisioning/bulk/rule-groups provisioning stable RoutePostBulkRuleGroups
+//
+// Install rule groups in many organizations. Only instance administrators can use it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: BulkOperationResult
+//       400: ValidationError
+
+// swagger:route POST /v1/provisioning/bulk/contact-point-secrets provisioning stable RoutePostBulkContactPointSecret
+//
+// Update a secure setting of a contact point in many organizations. Only instance administrators can use it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: BulkOperationResult
+//       400: ValidationError
+
+// swagger:route POST /v1/provisioning/bulk/policies provisioning stable RoutePostBulkPolicy
+//
+// Apply a notification policy in many organizations. Only instance administrators can use it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: BulkOperationResult
+//       400: ValidationError
+
+// swagger:parameters RoutePostBulkRuleGroups
+type BulkRuleGroupsPayload struct {
+	// in:body
+	Body BulkRuleGroups
+}
+
+// swagger:parameters RoutePostBulkContactPointSecret
+type BulkContactPointSecretPayload struct {
+	// in:body
+	Body BulkContactPointSecret
+}
+
+// swagger:parameters RoutePostBulkPolicy
+type BulkPolicyPayload struct {
+	// in:body
+	Body BulkPolicy
+}
+
+// swagger:parameters RoutePostBulkRuleGroups RoutePostBulkContactPointSecret RoutePostBulkPolicy
+type BulkHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// BulkRuleGroups contains the rule groups to install in the organizations. The groups replace the groups with the same
+// folder and title. The folders must exist in every organization.
+// swagger:model
+type BulkRuleGroups struct {
+	// OrgIDs are the organizations to change, all organizations if empty.
+	OrgIDs []int64          `json:"orgIds"`
+	Groups []AlertRuleGroup `json:"groups"`
+}
+
+// BulkContactPointSecret contains the value of a secure setting to set in all the integrations of a contact point
+// that have it.
+// swagger:model
+type BulkContactPointSecret struct {
+	// OrgIDs are the organizations to change, all organizations if empty.
+	OrgIDs []int64 `json:"orgIds"`
+	// Name of the contact point.
+	// example: webhook
+	Name string `json:"name"`
+	// Key of the secure setting.
+	// example: password
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// BulkPolicy contains a notification policy to add to the root of the policy tree of the organizations. The child of
+// the root with the same object matchers is replaced.
+// swagger:model
+type BulkPolicy struct {
+	// OrgIDs are the organizations to change, all organizations if empty.
+	OrgIDs []int64 `json:"orgIds"`
+	Policy Route   `json:"policy"`
+}
+
+// BulkOperationResult contains the result of a bulk operation in each organization.
+// swagger:model
+type BulkOperationResult struct {
+	Results []BulkOrgResult `json:"results"`
+}
+
+type BulkOrgResult struct {
+	OrgID int64 `json:"orgId"`
+	// Status is success or error.
+	// example: success
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	BulkOrgResultSuccess = "success"
+	BulkOrgResultError   = "error"
+)
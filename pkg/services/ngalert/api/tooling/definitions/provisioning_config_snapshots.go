@@ -0,0 +1,69 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/snapshots provisioning stable RouteGetConfigSnapshots
+//
+// Get the snapshots of the alerting configuration of the organization, the latest first.
+//
+//     Responses:
+//       200: ConfigSnapshots
+
+// swagger:route POST /v1/provisioning/snapshots provisioning stable RoutePostConfigSnapshot
+//
+// Create a snapshot of the current alerting configuration of the organization.
+//
+//     Responses:
+//       201: ConfigSnapshot
+
+// swagger:route POST /v1/provisioning/snapshots/{ID}/restore provisioning stable RoutePostConfigSnapshotRestore
+//
+// Restore the alerting configuration of the organization from a snapshot.
+//
+//     Responses:
+//       200: Ack
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostConfigSnapshotRestore
+type ConfigSnapshotIDParam struct {
+	// ID of the snapshot.
+	// in:path
+	// required:true
+	ID int64
+}
+
+// swagger:parameters RoutePostConfigSnapshotRestore
+type ConfigSnapshotRestoreParams struct {
+	// Mode of the restore. With replace, the alerting configuration becomes the same as in the snapshot and the
+	// resources created after the snapshot are deleted. With merge, the resources of the snapshot are restored, the
+	// resources created after the snapshot and the notification policy tree are kept.
+	// in:query
+	// enum: replace,merge
+	// default: replace
+	Mode string `json:"mode"`
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// swagger:model
+type ConfigSnapshots []ConfigSnapshot
+
+// ConfigSnapshot is a saved copy of the alert rules and the Alertmanager configuration of an organization.
+// swagger:model
+type ConfigSnapshot struct {
+	// example: 1
+	ID int64 `json:"id"`
+	// Version of the format of the snapshot content.
+	// example: 1
+	Version int `json:"version"`
+	// Hash of the snapshot content. Snapshots of the same configuration have the same hash.
+	Hash string `json:"hash"`
+	// Size of the compressed snapshot content, in bytes.
+	Size int64 `json:"size"`
+	// CreatedAt is the time the snapshot was created.
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,48 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/contact-points/{UID}/deliveries provisioning stable RouteGetContactPointDeliveries
+//
+// Get the recent attempts to deliver notifications through a contact point, the most recent first.
+//
+// The attempts are kept in memory by every Grafana instance, so they are the ones of the instance that serves the
+// request, since its last restart, and not the ones of the other instances of a high availability setup. They let
+// pipelines check that a contact point still delivers notifications after it was changed, e.g. after a secret was
+// rotated. The URLs and the secure settings of the contact point are redacted from the errors. No attempts are
+// returned if the Alertmanager of the organization is remote.
+//
+//     Responses:
+//       200: ContactPointDeliveries
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:model
+type ContactPointDeliveries struct {
+	UID        string                 `json:"uid"`
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`
+	Deliveries []ContactPointDelivery `json:"deliveries"`
+}
+
+// ContactPointDelivery is an attempt to deliver a notification through a contact point.
+type ContactPointDelivery struct {
+	Time time.Time `json:"time"`
+	// Status of the attempt, success or failure
+	Status string `json:"status"`
+	// Error of the attempt if it failed, without the URLs and the secure settings of the contact point
+	Error string `json:"error,omitempty"`
+	// Whether the failed attempt is retried
+	Retry bool `json:"retry,omitempty"`
+	// Time the attempt took, e.g. 120ms
+	Duration string `json:"duration"`
+	// Number of alerts of the notification
+	Alerts int `json:"alerts"`
+}
+
+const (
+	ContactPointDeliverySuccess = "success"
+	ContactPointDeliveryFailure = "failure"
+)
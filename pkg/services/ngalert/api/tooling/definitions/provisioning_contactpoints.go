@@ -1,6 +1,11 @@
 package definitions
 
 import (
+	"errors"
+	"fmt"
+
+	"github.com/prometheus/common/model"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -15,6 +20,12 @@ import (
 //
 // Export all contact points in provisioning file format.
 //
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//     - application/terraform+hcl
+//
 //     Responses:
 //       200: AlertingFileExport
 //       403: PermissionDenied
@@ -51,7 +62,7 @@ import (
 //     Responses:
 //       202: description: The contact point was deleted successfully.
 
-// swagger:parameters RoutePutContactpoint RouteDeleteContactpoints
+// swagger:parameters RoutePutContactpoint RouteDeleteContactpoints RouteGetContactPointDeliveries
 type ContactPointUIDReference struct {
 	// UID is the contact point unique identifier
 	// in:path
@@ -105,15 +116,22 @@ type EmbeddedContactPoint struct {
 	Settings *simplejson.Json `json:"settings" binding:"required"`
 	// example: false
 	DisableResolveMessage bool `json:"disableResolveMessage"`
+	// Disabled integrations are kept in the configuration but do not send notifications.
+	// example: false
+	Disabled bool `json:"disabled"`
+	// RateLimit limits the number of notifications sent by each integration of the contact point. All the integrations
+	// with the same name share it, so writing it sets it for all of them.
+	RateLimit *NotificationRateLimit `json:"rateLimit,omitempty"`
 	// readonly: true
 	Provenance string `json:"provenance,omitempty"`
 }
 
 // ContactPointExport is the provisioned file export of alerting.ContactPointV1.
 type ContactPointExport struct {
-	OrgID     int64            `json:"orgId" yaml:"orgId"`
-	Name      string           `json:"name" yaml:"name"`
-	Receivers []ReceiverExport `json:"receivers" yaml:"receivers"`
+	OrgID     int64                  `json:"orgId" yaml:"orgId"`
+	Name      string                 `json:"name" yaml:"name"`
+	RateLimit *NotificationRateLimit `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	Receivers []ReceiverExport       `json:"receivers" yaml:"receivers"`
 }
 
 // ReceiverExport is the provisioned file export of alerting.ReceiverV1.
@@ -122,6 +140,47 @@ type ReceiverExport struct {
 	Type                  string     `json:"type" yaml:"type"`
 	Settings              RawMessage `json:"settings" yaml:"settings"`
 	DisableResolveMessage bool       `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+	Disabled              bool       `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+}
+
+const (
+	// RateLimitOverflowDrop discards the notifications that exceed the rate limit.
+	RateLimitOverflowDrop = "drop"
+	// RateLimitOverflowRetry fails the notifications that exceed the rate limit, so that they are retried later.
+	RateLimitOverflowRetry = "retry"
+)
+
+// NotificationRateLimit is the maximum number of notifications that each integration of a contact point sends in an
+// interval.
+// swagger:model
+type NotificationRateLimit struct {
+	// required: true
+	// minimum: 1
+	// example: 10
+	MaxNotifications int `json:"maxNotifications" yaml:"maxNotifications"`
+	// required: true
+	// example: 1h
+	Interval model.Duration `json:"interval" yaml:"interval"`
+	// OverflowPolicy is what happens to the notifications that exceed the limit. They are either dropped, or failed
+	// and retried later. Defaults to drop.
+	// enum: drop, retry
+	// example: drop
+	OverflowPolicy string `json:"overflowPolicy,omitempty" yaml:"overflowPolicy,omitempty"`
+}
+
+func (l NotificationRateLimit) Validate() error {
+	if l.MaxNotifications < 1 {
+		return errors.New("maximum number of notifications of the rate limit must be positive")
+	}
+	if l.Interval <= 0 {
+		return errors.New("interval of the rate limit must be positive")
+	}
+	switch l.OverflowPolicy {
+	case "", RateLimitOverflowDrop, RateLimitOverflowRetry:
+		return nil
+	default:
+		return fmt.Errorf("unknown overflow policy '%s' of the rate limit, must be '%s' or '%s'", l.OverflowPolicy, RateLimitOverflowDrop, RateLimitOverflowRetry)
+	}
 }
 
 const RedactedValue = "[REDACTED]"
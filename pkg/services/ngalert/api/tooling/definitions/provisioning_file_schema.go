@@ -0,0 +1,12 @@
+package definitions
+
+// swagger:route GET /v1/provisioning/file-schema provisioning stable RouteGetProvisioningFileSchema
+//
+// Get the JSON Schema of the files of file provisioning. Files are validated against it when they are read.
+//
+//     Responses:
+//       200: ProvisioningFileSchema
+
+// ProvisioningFileSchema is a JSON Schema document.
+// swagger:model
+type ProvisioningFileSchema map[string]any
@@ -0,0 +1,41 @@
+package definitions
+
+// swagger:route GET /v1/provisioning/folder/{FolderUID}/annotations provisioning stable RouteGetFolderAnnotations
+//
+// Get the default annotations of the alert rules of a folder.
+//
+//     Responses:
+//       200: FolderAnnotations
+
+// swagger:route PUT /v1/provisioning/folder/{FolderUID}/annotations provisioning stable RoutePutFolderAnnotations
+//
+// Replace the default annotations of the alert rules of a folder.
+//
+// They are merged into the annotations of the rules that are written afterwards through the provisioning API, and
+// removed from the exported rules.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: FolderAnnotations
+//       400: ValidationError
+
+// swagger:parameters RouteGetFolderAnnotations RoutePutFolderAnnotations
+type FolderAnnotationsReference struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+}
+
+// swagger:parameters RoutePutFolderAnnotations
+type FolderAnnotationsPayload struct {
+	// in:body
+	Body FolderAnnotations
+}
+
+// FolderAnnotations are the annotations that the alert rules of a folder get unless they set them.
+// swagger:model
+type FolderAnnotations struct {
+	// example: {"runbook_base_url": "https://runbooks.example.com", "oncall_team": "sre"}
+	Annotations map[string]string `json:"annotations"`
+}
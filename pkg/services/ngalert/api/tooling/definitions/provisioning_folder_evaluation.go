@@ -0,0 +1,41 @@
+package definitions
+
+// swagger:route GET /v1/provisioning/folder/{FolderUID}/evaluation provisioning stable RouteGetFolderEvaluation
+//
+// Get whether the evaluation of the alert rules of a folder is paused.
+//
+//     Responses:
+//       200: FolderEvaluation
+
+// swagger:route PUT /v1/provisioning/folder/{FolderUID}/evaluation provisioning stable RoutePutFolderEvaluation
+//
+// Pause or resume the evaluation of the alert rules of a folder.
+//
+// The rules of a paused folder are not evaluated, whatever their own pause status, which is not changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: FolderEvaluation
+//       400: ValidationError
+
+// swagger:parameters RouteGetFolderEvaluation RoutePutFolderEvaluation
+type FolderEvaluationReference struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+}
+
+// swagger:parameters RoutePutFolderEvaluation
+type FolderEvaluationPayload struct {
+	// in:body
+	Body FolderEvaluation
+}
+
+// FolderEvaluation is the evaluation status of the alert rules of a folder.
+// swagger:model
+type FolderEvaluation struct {
+	// Paused is true if the alert rules of the folder are not evaluated.
+	// example: true
+	Paused bool `json:"paused"`
+}
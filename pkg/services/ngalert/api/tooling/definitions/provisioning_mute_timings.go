@@ -15,6 +15,12 @@ import (
 //
 // Export all mute timings in provisioning format.
 //
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//     - application/terraform+hcl
+//
 //     Responses:
 //       200: AlertingFileExport
 //       403: PermissionDenied
@@ -31,6 +37,12 @@ import (
 //
 // Export a mute timing in provisioning format.
 //
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//     - application/terraform+hcl
+//
 //     Responses:
 //       200: AlertingFileExport
 //       403: PermissionDenied
@@ -2,6 +2,7 @@ package definitions
 
 import (
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 )
 
 // swagger:route GET /v1/provisioning/policies provisioning stable RouteGetPolicyTree
@@ -37,10 +38,74 @@ import (
 //
 // Export the notification policy tree in provisioning file format.
 //
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/yaml
+//     - application/terraform+hcl
+//
 //     Responses:
 //       200: AlertingFileExport
 //       404: NotFound
 
+// swagger:route GET /v1/provisioning/policies/effective provisioning stable RouteGetEffectivePolicy
+//
+// Get the settings of a notification policy after resolving the values it inherits from its parents.
+//
+//     Responses:
+//       200: EffectivePolicy
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RouteGetEffectivePolicy
+type EffectivePolicyParams struct {
+	// Indexes of the nested policies that lead to the policy from the root of the tree, separated by dots. The root
+	// policy is used if it is empty.
+	// in:query
+	// required:false
+	// example: 0.2
+	Path string `json:"path"`
+}
+
+// swagger:route POST /v1/provisioning/policies/merge provisioning stable RoutePostPolicyTreeMerge
+//
+// Merges notification policies into the notification policy tree, as nested policies of an anchor policy.
+//
+// Each merged policy has the provenance of the request, and the provenance of the tree does not change.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: Route
+//       400: ValidationError
+//       404: NotFound
+//       409: GenericPublicError
+
+// swagger:parameters RoutePostPolicyTreeMerge
+type PolicyTreeMergeParams struct {
+	// Whether to replace the nested policies of the anchor policy that have the same object matchers as a merged
+	// policy but a different definition, instead of failing.
+	// in:query
+	// required:false
+	Overwrite bool `json:"overwrite"`
+	// in:body
+	Body PolicyTreeFragment
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// PolicyTreeFragment contains notification policies that are merged into the notification policy tree. The policies
+// are identified by their object matchers, so that each source of policies updates its own policies when it merges
+// them again.
+// swagger:model
+type PolicyTreeFragment struct {
+	// Object matchers of the anchor policy, which must be the only policy of the tree with these matchers. The root
+	// policy is used if it is empty.
+	Anchor ObjectMatchers `json:"anchor,omitempty"`
+	Routes []*Route       `json:"routes"`
+}
+
 // swagger:parameters RoutePutPolicyTree
 type Policytree struct {
 	// The new notification routing tree to use
@@ -87,3 +152,29 @@ type MatcherExport struct {
 	Match string `yaml:"-" json:"-" hcl:"match"`
 	Value string `yaml:"-" json:"-" hcl:"value"`
 }
+
+// Names of the settings of EffectivePolicy.
+const (
+	EffectivePolicyReceiver       = "receiver"
+	EffectivePolicyGroupBy        = "group_by"
+	EffectivePolicyGroupWait      = "group_wait"
+	EffectivePolicyGroupInterval  = "group_interval"
+	EffectivePolicyRepeatInterval = "repeat_interval"
+)
+
+// EffectivePolicy contains the settings that the Alertmanager uses for a notification policy, including the ones that
+// the policy inherits from its parents.
+// swagger:model
+type EffectivePolicy struct {
+	// Path contains the indexes of the nested policies that lead to the policy from the root of the tree.
+	Path           []int          `json:"path"`
+	Receiver       string         `json:"receiver"`
+	GroupBy        []string       `json:"group_by"`
+	GroupWait      model.Duration `json:"group_wait"`
+	GroupInterval  model.Duration `json:"group_interval"`
+	RepeatInterval model.Duration `json:"repeat_interval"`
+	// InheritedFrom contains, for each of the settings that the policy inherits, the path of the policy that sets it.
+	InheritedFrom map[string][]int `json:"inherited_from,omitempty"`
+	// Defaults contains the settings that neither the policy nor its parents set, and that use the default value.
+	Defaults []string `json:"defaults,omitempty"`
+}
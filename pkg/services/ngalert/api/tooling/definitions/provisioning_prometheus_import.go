@@ -0,0 +1,35 @@
+package definitions
+
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/prometheus-rule-groups provisioning stable RoutePostPrometheusRuleGroupImport
+//
+// Import a Prometheus rule group as Grafana-managed rules.
+//
+// The rule group of the folder with the name of the Prometheus rule group is replaced with the converted rules. The
+// alerting rules fire for every series that their expressions return, and the recording rules write their series to
+// the data source. The rules of the group are matched by title, so that importing the same group again updates them.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       204: description: The rule group was imported.
+//       400: ValidationError
+//       403: ForbiddenError
+//       409: ProvisioningError
+
+// swagger:parameters RoutePostPrometheusRuleGroupImport
+type PrometheusRuleGroupImportPayload struct {
+	// in:body
+	Body PrometheusRuleGroupImport
+}
+
+// swagger:model
+type PrometheusRuleGroupImport struct {
+	// UID of the Prometheus-compatible data source that the rules query, and that recording rules write to.
+	// required: true
+	// example: prometheus
+	DatasourceUID string `json:"datasourceUid"`
+	// Prometheus rule group in YAML, as in a rule file of a Prometheus, Mimir or Loki ruler.
+	// required: true
+	Group string `json:"group"`
+}
@@ -0,0 +1,64 @@
+package definitions
+
+// swagger:route PUT /v1/provisioning/alert-rules/{UID}/provenance provisioning stable RoutePutAlertRuleProvenance
+//
+// Hand an alert rule over from a provenance to another.
+//
+// Provisioned alert rules can only be changed with the provenance they were provisioned with, e.g. the rules of
+// provisioning files cannot be changed in the UI. Handing a rule over to another provenance, e.g. none when it is
+// removed from the files, allows to change it with that provenance.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       204: description: The provenance of the alert rule was changed.
+//       400: ValidationError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:route PUT /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/provenance provisioning stable RoutePutAlertRuleGroupProvenance
+//
+// Hand all the alert rules of a rule group over from a provenance to another.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       204: description: The provenance of the alert rules was changed.
+//       400: ValidationError
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:parameters RoutePutAlertRuleProvenance
+type AlertRuleProvenanceUIDReference struct {
+	// Alert rule UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePutAlertRuleGroupProvenance
+type AlertRuleGroupProvenanceReference struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+	// in:path
+	Group string `json:"Group"`
+}
+
+// swagger:parameters RoutePutAlertRuleProvenance RoutePutAlertRuleGroupProvenance
+type ProvenanceTransitionPayload struct {
+	// in:body
+	Body ProvenanceTransition
+}
+
+// ProvenanceTransition hands resources over from a provenance to another. The provenance of the resources must be the
+// one they are handed over from, so that they are not taken from a writer that changed them in the meantime.
+// swagger:model
+type ProvenanceTransition struct {
+	// Provenance the resources have, empty if they have none.
+	// example: file
+	From Provenance `json:"from"`
+	// Provenance the resources are handed over to, empty to allow to change them by any means.
+	// example: api
+	To Provenance `json:"to"`
+}
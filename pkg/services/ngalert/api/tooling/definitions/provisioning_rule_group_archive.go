@@ -0,0 +1,36 @@
+package definitions
+
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/archive provisioning stable RoutePostAlertRuleGroupArchive
+//
+// Archive a rule group.
+//
+// The alert rules of an archived rule group are not evaluated and are left out of the listings and the exports of alert
+// rules and rule groups by default, but they are kept with their definition and provenance until the group is
+// unarchived or deleted. The user must be allowed to update the alert rules of the group.
+//
+//     Responses:
+//       204: description: The rule group was archived.
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/unarchive provisioning stable RoutePostAlertRuleGroupUnarchive
+//
+// Unarchive a rule group, whose alert rules are evaluated again.
+//
+// The user must be allowed to update the alert rules of the group.
+//
+//     Responses:
+//       204: description: The rule group was unarchived.
+//       403: ForbiddenError
+//       404: NotFound
+
+// swagger:parameters RouteGetAlertRulesExport RouteGetRulesForExport RouteGetGrafanaRulesConfig RouteGetNamespaceGrafanaRulesConfig
+type ArchivedRuleGroupsParam struct {
+	// Whether to exclude the archived rule groups, to include them, or to return only them. A single rule group or
+	// alert rule is returned whether it is archived or not.
+	// in:query
+	// required:false
+	// default: exclude
+	// enum: exclude,include,only
+	Archived string `json:"archived"`
+}
@@ -0,0 +1,36 @@
+package definitions
+
+// swagger:route POST /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/move provisioning stable RoutePostAlertRuleGroupMove
+//
+// Rename a rule group, or move it to another folder.
+//
+// The alert rules of the group keep their UIDs, and so their state history, and their provenance. The target folder
+// must exist and the target rule group must not.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: RuleGroupMove
+//       400: ValidationError
+//       403: ForbiddenError
+//       404: description: Not found.
+//       409: ProvisioningError
+
+// swagger:parameters RoutePostAlertRuleGroupMove
+type RuleGroupMovePayload struct {
+	// in:body
+	Body RuleGroupMove
+}
+
+// swagger:model
+type RuleGroupMove struct {
+	// UID of the folder to move the rule group to. It is the current folder of the group to only rename it.
+	// required: true
+	// example: project_x
+	FolderUID string `json:"folderUid"`
+	// New title of the rule group.
+	// required: true
+	// example: eval_group_1
+	Title string `json:"title"`
+}
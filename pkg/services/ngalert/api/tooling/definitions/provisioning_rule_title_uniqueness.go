@@ -0,0 +1,47 @@
+package definitions
+
+// swagger:route GET /v1/provisioning/rule-title-uniqueness provisioning stable RouteGetRuleTitleUniqueness
+//
+// Get the policy that decides in which scope the titles of the alert rules of the organization must be unique.
+//
+//     Responses:
+//       200: RuleTitleUniqueness
+
+// swagger:route PUT /v1/provisioning/rule-title-uniqueness provisioning stable RoutePutRuleTitleUniqueness
+//
+// Set the rule title uniqueness policy of the organization. It overrides the policy of the server settings.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: RuleTitleUniqueness
+//       400: ValidationError
+
+// swagger:route DELETE /v1/provisioning/rule-title-uniqueness provisioning stable RouteResetRuleTitleUniqueness
+//
+// Remove the rule title uniqueness policy of the organization, so that the policy of the server settings applies.
+//
+//     Responses:
+//       200: RuleTitleUniqueness
+
+// swagger:parameters RoutePutRuleTitleUniqueness
+type RuleTitleUniquenessPayload struct {
+	// in:body
+	Body RuleTitleUniqueness
+}
+
+// RuleTitleUniqueness decides in which scope the titles of the alert rules of an organization must be unique when
+// they are written through provisioning.
+// swagger:model
+type RuleTitleUniqueness struct {
+	// Scope is the scope in which titles must be unique. Titles do not need to be unique if it is empty.
+	// enum: folder,group
+	// example: folder
+	Scope string `json:"scope"`
+	// FolderUIDs are the folders in which the policy is enforced. It is enforced in all folders if it is empty.
+	FolderUIDs []string `json:"folderUids,omitempty"`
+	// Inherited is true if the organization has no policy, and the policy of the server settings applies.
+	// readOnly: true
+	Inherited bool `json:"inherited"`
+}
@@ -0,0 +1,144 @@
+package definitions
+
+// swagger:route GET /v1/provisioning/alert-rules/{UID}/tags provisioning stable RouteGetAlertRuleTags
+//
+// Get the tags of an alert rule.
+//
+//     Responses:
+//       200: ResourceTags
+//       404: description: Not found.
+
+// swagger:route PUT /v1/provisioning/alert-rules/{UID}/tags provisioning stable RoutePutAlertRuleTags
+//
+// Replace the tags of an alert rule.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ResourceTags
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route GET /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags provisioning stable RouteGetAlertRuleGroupTags
+//
+// Get the tags of a rule group.
+//
+//     Responses:
+//       200: ResourceTags
+//       404: description: Not found.
+
+// swagger:route PUT /v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/tags provisioning stable RoutePutAlertRuleGroupTags
+//
+// Replace the tags of a rule group.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ResourceTags
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route GET /v1/provisioning/contact-points/{UID}/tags provisioning stable RouteGetContactPointTags
+//
+// Get the tags of a contact point.
+//
+//     Responses:
+//       200: ResourceTags
+//       404: description: Not found.
+
+// swagger:route PUT /v1/provisioning/contact-points/{UID}/tags provisioning stable RoutePutContactPointTags
+//
+// Replace the tags of a contact point.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ResourceTags
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route POST /v1/provisioning/alert-rules/pause provisioning stable RoutePostAlertRulesPause
+//
+// Pause or resume the alert rules that have a tag, or whose rule group has it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRulesPauseResult
+//       400: ValidationError
+//       403: ForbiddenError
+
+// swagger:parameters RouteGetAlertRuleTags RoutePutAlertRuleTags
+type AlertRuleTagsUIDReference struct {
+	// Alert rule UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RouteGetAlertRuleGroupTags RoutePutAlertRuleGroupTags
+type AlertRuleGroupTagsReference struct {
+	// in:path
+	FolderUID string `json:"FolderUID"`
+	// in:path
+	Group string `json:"Group"`
+}
+
+// swagger:parameters RouteGetContactPointTags RoutePutContactPointTags
+type ContactPointTagsUIDReference struct {
+	// UID is the contact point unique identifier
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePutAlertRuleTags RoutePutAlertRuleGroupTags RoutePutContactPointTags
+type ResourceTagsPayload struct {
+	// in:body
+	Body ResourceTags
+}
+
+// swagger:parameters RouteGetAlertRules RouteGetAlertRulesExport RouteGetContactpoints RouteGetContactpointsExport
+type ResourceTagParams struct {
+	// Filter by tag
+	// in: query
+	// required: false
+	Tag string `json:"tag"`
+}
+
+// swagger:parameters RoutePostAlertRulesPause
+type AlertRulesPausePayload struct {
+	// in:body
+	Body AlertRulesPause
+}
+
+// swagger:parameters RoutePostAlertRulesPause
+type AlertRulesPauseHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// ResourceTags are free-form strings that help to organize provisioned resources. Unlike labels, they are not part of
+// the alerts and notifications.
+// swagger:model
+type ResourceTags struct {
+	// example: ["canary"]
+	Tags []string `json:"tags"`
+}
+
+// swagger:model
+type AlertRulesPause struct {
+	// Tag of the rules or rule groups to change.
+	// example: canary
+	Tag      string `json:"tag"`
+	IsPaused bool   `json:"isPaused"`
+}
+
+// swagger:model
+type AlertRulesPauseResult struct {
+	// Number of rules that were changed.
+	// example: 3
+	Updated int `json:"updated"`
+}
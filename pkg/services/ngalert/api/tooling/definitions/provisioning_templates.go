@@ -26,6 +26,7 @@ package definitions
 //     Responses:
 //       202: NotificationTemplate
 //       400: ValidationError
+//       409: GenericPublicError
 
 // swagger:route DELETE /v1/provisioning/templates/{name} provisioning stable RouteDeleteTemplate
 //
@@ -33,6 +34,7 @@ package definitions
 //
 //     Responses:
 //       204: description: The template was deleted successfully.
+//       409: GenericPublicError
 
 // swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate
 type RouteGetTemplateParam struct {
@@ -0,0 +1,156 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/alert-rules/usage provisioning stable RouteGetAlertRulesUsage
+//
+// Get how often the provisioned alert rules fired and were resolved over a window, from their state history.
+//
+// The rules that never fire are candidates for cleanup. The counts are the state changes of the alerts of the rules,
+// not the notifications that the notification policies sent for them.
+//
+//     Responses:
+//       200: AlertRulesUsage
+//       400: ValidationError
+
+// swagger:route GET /v1/provisioning/alert-rules/noise-report provisioning stable RouteGetRuleGroupsNoiseReport
+//
+// Get how noisy the rule groups of provisioned alert rules were over a window, from their state history.
+//
+// The flappiness of a group is the number of state changes of the alerts of its rules per day. Groups are sorted from
+// the noisiest.
+//
+//     Responses:
+//       200: RuleGroupsNoiseReport
+//       400: ValidationError
+
+// swagger:route GET /v1/provisioning/alert-rules/quota provisioning stable RouteGetAlertRulesQuota
+//
+// Get the usage of the alert rule quotas of the organization and the number of rules of each rule group.
+//
+// Automation can check a large change against the headroom of the quotas before it makes it, rather than fail when the
+// change reaches one of them.
+//
+//     Responses:
+//       200: AlertRulesQuotaStatus
+
+// swagger:parameters RouteGetAlertRulesUsage RouteGetRuleGroupsNoiseReport
+type AlertRulesUsageParameters struct {
+	// Start of the window as a Unix timestamp in seconds, a week before its end by default
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+
+	// End of the window as a Unix timestamp in seconds, now by default
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+
+	// UIDs of the folders of the alert rules
+	// in:query
+	// required:false
+	FolderUID []string `json:"folderUid"`
+
+	// Provenances of the alert rules, api or file. All the provisioned alert rules are returned by default.
+	// in:query
+	// required:false
+	Provenance []string `json:"provenance"`
+}
+
+// swagger:parameters RouteGetAlertRulesUsage
+type AlertRulesNeverFiredParameters struct {
+	// Only return the alert rules that did not fire in the window
+	// in:query
+	// required:false
+	NeverFired bool `json:"neverFired"`
+}
+
+// swagger:parameters RouteGetRuleGroupsNoiseReport
+type RuleGroupsNoiseReportParameters struct {
+	// Order of the rule groups, flappiness or firingOrResolved
+	// in:query
+	// required:false
+	// default:flappiness
+	SortBy string `json:"sortBy"`
+
+	// Maximum number of rule groups to return. Zero means no limit.
+	// in:query
+	// required:false
+	Limit int64 `json:"limit"`
+}
+
+// swagger:model
+type AlertRulesUsage struct {
+	From  time.Time        `json:"from"`
+	To    time.Time        `json:"to"`
+	Rules []AlertRuleUsage `json:"rules"`
+}
+
+// AlertRuleUsage is how often a provisioned alert rule fired and was resolved over a window.
+type AlertRuleUsage struct {
+	UID        string     `json:"uid"`
+	Title      string     `json:"title"`
+	FolderUID  string     `json:"folderUID"`
+	RuleGroup  string     `json:"ruleGroup"`
+	Provenance Provenance `json:"provenance"`
+	// Number of times the alerts of the rule changed state
+	TransitionCount int64 `json:"transitionCount"`
+	// Number of times the alerts of the rule started firing
+	FiringCount int64 `json:"firingCount"`
+	// Number of times the alerts of the rule started firing or were resolved
+	FiringOrResolvedCount int64 `json:"firingOrResolvedCount"`
+	// Last time an alert of the rule started firing in the window
+	LastFiredAt *time.Time `json:"lastFiredAt,omitempty"`
+}
+
+// swagger:model
+type RuleGroupsNoiseReport struct {
+	From   time.Time        `json:"from"`
+	To     time.Time        `json:"to"`
+	Groups []RuleGroupNoise `json:"groups"`
+}
+
+// RuleGroupNoise is how noisy the provisioned alert rules of a rule group were over a window.
+type RuleGroupNoise struct {
+	FolderUID string `json:"folderUID"`
+	RuleGroup string `json:"ruleGroup"`
+	// Number of provisioned alert rules of the group
+	RuleCount int64 `json:"ruleCount"`
+	// Number of times the alerts of the rules of the group changed state
+	TransitionCount int64 `json:"transitionCount"`
+	// Number of state changes of the alerts of the rules of the group per day
+	Flappiness float64 `json:"flappiness"`
+	// Number of times the alerts of the rules of the group started firing or were resolved
+	FiringOrResolvedCount int64 `json:"firingOrResolvedCount"`
+}
+
+// swagger:model
+type AlertRulesQuotaStatus struct {
+	// Number of alert rules of the organization, whether or not they count towards the quotas
+	Count int64 `json:"count"`
+	// Quotas of the scopes that apply to the organization, empty if quotas are disabled
+	Limits []AlertRulesQuotaLimit `json:"limits"`
+	// Limit of the alert rules whose provenance is exempt from the quotas, absent if no provenance is
+	Exempt *AlertRulesQuotaLimit `json:"exempt,omitempty"`
+	Groups []AlertRuleGroupCount `json:"groups"`
+}
+
+// AlertRulesQuotaLimit is the alert rule quota of a scope, global or org.
+type AlertRulesQuotaLimit struct {
+	Scope string `json:"scope"`
+	// Maximum number of alert rules in the scope, negative if there is none
+	Limit int64 `json:"limit"`
+	// Number of alert rules that count towards the quota in the scope
+	Used int64 `json:"used"`
+	// Number of alert rules that can still be created in the scope, negative if there is no limit
+	Headroom int64 `json:"headroom"`
+}
+
+// AlertRuleGroupCount is the number of alert rules of a rule group.
+type AlertRuleGroupCount struct {
+	FolderUID string `json:"folderUID"`
+	RuleGroup string `json:"ruleGroup"`
+	RuleCount int64  `json:"ruleCount"`
+}
@@ -0,0 +1,47 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /v1/provisioning/webhook/dead-letters provisioning stable RouteGetWebhookDeadLetters
+//
+// Get the events of the organization that could not be posted to the provisioning webhook, the oldest first.
+//
+//     Responses:
+//       200: WebhookDeliveries
+
+// swagger:route POST /v1/provisioning/webhook/dead-letters/{UID}/replay provisioning stable RoutePostWebhookDeadLetterReplay
+//
+// Post an event that could not be posted to the provisioning webhook again. The event is removed from the dead letters,
+// and added again if it fails.
+//
+//     Responses:
+//       202: WebhookDelivery
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostWebhookDeadLetterReplay
+type WebhookDeliveryUIDParam struct {
+	// in:path
+	UID string `json:"UID"`
+}
+
+// swagger:model
+type WebhookDeliveries []WebhookDelivery
+
+// WebhookDelivery is an event of the provisioning webhook.
+// swagger:model
+type WebhookDelivery struct {
+	UID string `json:"uid"`
+	// Type of the event.
+	// enum: alert_rule_created,alert_rule_updated,alert_rule_deleted,rule_group_replaced
+	Type  string     `json:"type"`
+	Event RawMessage `json:"event"`
+	// Number of times the event was posted.
+	Attempts int `json:"attempts"`
+	// Error of the last attempt.
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
@@ -68,6 +68,21 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /v1/rule/simulate/grafana testing RouteSimulateGrafanaRule
+//
+// Evaluate a rule against synthetic data instead of querying its data sources
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: SimulateGrafanaRuleResult
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -234,3 +249,43 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters RouteSimulateGrafanaRule
+type SimulateGrafanaRuleRequest struct {
+	// in:body
+	Body SimulateGrafanaRulePayload
+}
+
+// swagger:model
+type SimulateGrafanaRulePayload struct {
+	// required: true
+	Rule PostableExtendedRuleNode `json:"rule"`
+	// The folder is optional. If it is set, the alerts have the folder label.
+	// example: okrd3I0Vz
+	NamespaceUID string `json:"folderUid,omitempty"`
+	// example: eval_group_1
+	RuleGroup string `json:"ruleGroup"`
+	// Data contains the frames returned by the data source queries of the rule, by RefID. The data sources are not
+	// queried, so every data source query must have frames.
+	// required: true
+	Data map[string]data.Frames `json:"data"`
+	// Time of the evaluation, the current time if it is not set.
+	Now time.Time `json:"now,omitempty"`
+}
+
+// swagger:model
+type SimulateGrafanaRuleResult struct {
+	Instances []SimulatedAlertInstance `json:"instances"`
+}
+
+type SimulatedAlertInstance struct {
+	// Labels of the alert, including the labels of the rule.
+	Labels map[string]string `json:"labels"`
+	// State of the alert after the evaluation, e.g. Normal, Pending or Alerting.
+	// example: Alerting
+	State       string `json:"state"`
+	StateReason string `json:"stateReason,omitempty"`
+	// Values of the expressions, by RefID. The value is null if it is not a number.
+	Values map[string]*float64 `json:"values,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
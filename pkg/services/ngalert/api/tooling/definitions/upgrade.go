@@ -1,5 +1,9 @@
 package definitions
 
+import (
+	"time"
+)
+
 // swagger:route GET /v1/upgrade/org upgrade RouteGetOrgUpgrade
 //
 // Get existing alerting upgrade for the current organization.
@@ -20,6 +24,18 @@ package definitions
 //     Responses:
 //       200: OrgMigrationSummary
 
+// swagger:route GET /v1/upgrade/org/export upgrade RouteGetOrgUpgradeExport
+//
+// Export the alert rules, contact points and notification policies created by the upgrade of the current organization in provisioning file format.
+//
+//     Produces:
+//     - application/json
+//     - application/yaml
+//     - text/hcl
+//
+//     Responses:
+//       200: AlertingFileExport
+
 // swagger:route DELETE /v1/upgrade/org upgrade RouteDeleteOrgUpgrade
 //
 // Delete existing alerting upgrade for the current organization.
@@ -80,6 +96,74 @@ package definitions
 //     Responses:
 //       200: OrgMigrationSummary
 
+// swagger:route GET /v1/upgrade/staged upgrade RouteGetStagedOrgUpgrades
+//
+// Get the staged upgrades of all organizations.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StagedOrgUpgrades
+
+// swagger:route GET /v1/upgrade/staged/{OrgID} upgrade RouteGetStagedOrgUpgrade
+//
+// Get the staged upgrade of an organization.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StagedOrgUpgrade
+//       404: description: Not found.
+
+// swagger:route POST /v1/upgrade/staged/{OrgID} upgrade RoutePostStagedOrgUpgrade
+//
+// Enqueue an organization for a staged upgrade. Organizations whose upgrade completed or failed can be enqueued again.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StagedOrgUpgrade
+//       409: GenericPublicError
+
+// swagger:route POST /v1/upgrade/staged/{OrgID}/dry-run upgrade RoutePostStagedOrgUpgradeDryRun
+//
+// Upgrade an enqueued organization in a transaction that is rolled back, and store the report for review. Any previous approval is discarded.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StagedOrgUpgrade
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:route POST /v1/upgrade/staged/{OrgID}/approve upgrade RoutePostStagedOrgUpgradeApproval
+//
+// Approve the last dry-run report of an organization. Reports that contain errors cannot be approved.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StagedOrgUpgrade
+//       404: description: Not found.
+//       409: GenericPublicError
+
+// swagger:route POST /v1/upgrade/staged/{OrgID}/execute upgrade RoutePostStagedOrgUpgradeExecution
+//
+// Upgrade an organization whose dry-run report was approved.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StagedOrgUpgrade
+//       404: description: Not found.
+//       409: GenericPublicError
+
 // swagger:parameters RoutePostUpgradeOrg RoutePostUpgradeDashboard RoutePostUpgradeAllChannels
 type SkipExistingQueryParam struct {
 	// If true, legacy alert and notification channel upgrades from previous runs will be skipped. Otherwise, they will be replaced.
@@ -113,19 +197,29 @@ type ChannelParam struct {
 	ChannelID string
 }
 
+// swagger:parameters RouteGetStagedOrgUpgrade RoutePostStagedOrgUpgrade RoutePostStagedOrgUpgradeDryRun RoutePostStagedOrgUpgradeApproval RoutePostStagedOrgUpgradeExecution
+type StagedOrgParam struct {
+	// ID of the organization.
+	// in:path
+	// required:true
+	OrgID string
+}
+
 // swagger:model
 type OrgMigrationSummary struct {
-	NewDashboards int  `json:"newDashboards"`
-	NewAlerts     int  `json:"newAlerts"`
-	NewChannels   int  `json:"newChannels"`
-	Removed       bool `json:"removed"`
-	HasErrors     bool `json:"hasErrors"`
+	NewDashboards  int  `json:"newDashboards"`
+	NewAlerts      int  `json:"newAlerts"`
+	NewChannels    int  `json:"newChannels"`
+	MergedChannels int  `json:"mergedChannels"`
+	Removed        bool `json:"removed"`
+	HasErrors      bool `json:"hasErrors"`
 }
 
 func (s *OrgMigrationSummary) Add(other OrgMigrationSummary) {
 	s.NewDashboards += other.NewDashboards
 	s.NewAlerts += other.NewAlerts
 	s.NewChannels += other.NewChannels
+	s.MergedChannels += other.MergedChannels
 	s.Removed = s.Removed || other.Removed
 	s.HasErrors = s.HasErrors || other.HasErrors
 }
@@ -160,6 +254,7 @@ type AlertPair struct {
 type ContactPair struct {
 	LegacyChannel       *LegacyChannel       `json:"legacyChannel"`
 	ContactPointUpgrade *ContactPointUpgrade `json:"contactPoint"`
+	MergedIntoChannelID int64                `json:"mergedIntoChannelId,omitempty"`
 	Error               string               `json:"error,omitempty"`
 }
 
@@ -187,3 +282,20 @@ type ContactPointUpgrade struct {
 	Type          string         `json:"type"`
 	RouteMatchers ObjectMatchers `json:"routeMatchers"`
 }
+
+// swagger:model
+type StagedOrgUpgrades []StagedOrgUpgrade
+
+// swagger:model
+type StagedOrgUpgrade struct {
+	OrgID int64 `json:"orgId"`
+	// Status is one of queued, pending_approval, approved, completed or failed.
+	Status string `json:"status"`
+	// DryRunSummary is the summary of the last dry-run, if any.
+	DryRunSummary *OrgMigrationSummary `json:"dryRunSummary,omitempty"`
+	// DryRunErrors contains the errors that would fail the upgrade, as found by the last dry-run.
+	DryRunErrors []string `json:"dryRunErrors,omitempty"`
+	// Error is the error of the last execution, if it failed.
+	Error   string    `json:"error,omitempty"`
+	Updated time.Time `json:"updated"`
+}
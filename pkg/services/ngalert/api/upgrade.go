@@ -23,6 +23,10 @@ func (f *UpgradeApiHandler) handleRouteGetOrgUpgrade(ctx *contextmodel.ReqContex
 	return f.svc.RouteGetOrgUpgrade(ctx)
 }
 
+func (f *UpgradeApiHandler) handleRouteGetOrgUpgradeExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetOrgUpgradeExport(ctx)
+}
+
 func (f *UpgradeApiHandler) handleRouteDeleteOrgUpgrade(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteDeleteOrgUpgrade(ctx)
 }
@@ -46,3 +50,27 @@ func (f *UpgradeApiHandler) handleRoutePostUpgradeChannel(ctx *contextmodel.ReqC
 func (f *UpgradeApiHandler) handleRoutePostUpgradeAllChannels(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RoutePostUpgradeAllChannels(ctx)
 }
+
+func (f *UpgradeApiHandler) handleRouteGetStagedOrgUpgrades(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetStagedOrgUpgrades(ctx)
+}
+
+func (f *UpgradeApiHandler) handleRouteGetStagedOrgUpgrade(ctx *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return f.svc.RouteGetStagedOrgUpgrade(ctx, orgIDParam)
+}
+
+func (f *UpgradeApiHandler) handleRoutePostStagedOrgUpgrade(ctx *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return f.svc.RoutePostStagedOrgUpgrade(ctx, orgIDParam)
+}
+
+func (f *UpgradeApiHandler) handleRoutePostStagedOrgUpgradeDryRun(ctx *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return f.svc.RoutePostStagedOrgUpgradeDryRun(ctx, orgIDParam)
+}
+
+func (f *UpgradeApiHandler) handleRoutePostStagedOrgUpgradeApproval(ctx *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return f.svc.RoutePostStagedOrgUpgradeApproval(ctx, orgIDParam)
+}
+
+func (f *UpgradeApiHandler) handleRoutePostStagedOrgUpgradeExecution(ctx *contextmodel.ReqContext, orgIDParam string) response.Response {
+	return f.svc.RoutePostStagedOrgUpgradeExecution(ctx, orgIDParam)
+}
@@ -34,6 +34,18 @@ func toMacaronPath(path string) string {
 	}))
 }
 
+// strictDecodingHeaderName is the header of requests that ask to reject payloads with unknown fields, e.g. misspelled
+// ones, that are ignored otherwise.
+const strictDecodingHeaderName = "X-Strict-Decoding"
+
+// bindPayload decodes the JSON payload of the request into v, strictly if the request asks for it.
+func bindPayload(req *http.Request, v any) error {
+	if strict, _ := strconv.ParseBool(req.Header.Get(strictDecodingHeaderName)); strict {
+		return web.BindStrict(req, v)
+	}
+	return web.Bind(req, v)
+}
+
 func getDatasourceByUID(ctx *contextmodel.ReqContext, cache datasources.CacheService, expectedType apimodels.Backend) (*datasources.DataSource, error) {
 	datasourceUID := web.Params(ctx.Req)[":DatasourceUID"]
 	ds, err := cache.GetDatasourceByUID(ctx.Req.Context(), datasourceUID, ctx.SignedInUser, ctx.SkipDSCache)
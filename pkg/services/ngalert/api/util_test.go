@@ -3,7 +3,9 @@ package api
 import (
 	"math/rand"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +15,7 @@ import (
 	accesscontrolmock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
 	"github.com/grafana/grafana/pkg/services/auth"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	models2 "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -41,6 +44,36 @@ func TestToMacaronPath(t *testing.T) {
 	}
 }
 
+func TestBindPayload(t *testing.T) {
+	newRequestWithBody := func(body, strict string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		if strict != "" {
+			req.Header.Set(strictDecodingHeaderName, strict)
+		}
+		return req
+	}
+	newRequest := func(strict string) *http.Request {
+		return newRequestWithBody(`{"title": "rule", "for": 60, "anotations": {}}`, strict)
+	}
+
+	var rule apimodels.ProvisionedAlertRule
+	require.NoError(t, bindPayload(newRequest(""), &rule))
+	require.Equal(t, "rule", rule.Title)
+	require.Equal(t, apimodels.DurationOrSeconds(time.Minute), rule.For)
+	require.NoError(t, bindPayload(newRequest("false"), &rule))
+	require.ErrorContains(t, bindPayload(newRequest("true"), &rule), "unknown field \"anotations\"")
+
+	t.Run("rules of groups are decoded strictly too", func(t *testing.T) {
+		body := `{"title": "group", "interval": "1m", "rules": [{"title": "rule", "notification_settings": {"receiver": "r", "group_wait": 30, "group_wiat": "1m"}}]}`
+		var group apimodels.AlertRuleGroup
+		require.NoError(t, bindPayload(newRequestWithBody(body, ""), &group))
+		require.Equal(t, apimodels.SecondsOrDuration(60), group.Interval)
+		require.ErrorContains(t, bindPayload(newRequestWithBody(body, "true"), &group), "unknown field \"group_wiat\"")
+	})
+}
+
 func TestAlertingProxy_createProxyContext(t *testing.T) {
 	ctx := &contextmodel.ReqContext{
 		Context: &web.Context{
@@ -2,6 +2,7 @@ package eval
 
 import (
 	"context"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -19,6 +20,11 @@ type EvaluationContext struct {
 	Ctx                   context.Context
 	User                  identity.Requester
 	AlertingResultsReader AlertingResultsReader
+	// SyntheticData contains the frames returned by the data source queries, by RefID. If it is set, the data sources
+	// are not queried.
+	SyntheticData map[string]data.Frames
+	// EvaluationTimeout is the timeout of the evaluation instead of the one of the evaluator, if it is positive.
+	EvaluationTimeout time.Duration
 }
 
 func NewContext(ctx context.Context, user identity.Requester) EvaluationContext {
@@ -35,3 +41,16 @@ func NewContextWithPreviousResults(ctx context.Context, user identity.Requester,
 		AlertingResultsReader: reader,
 	}
 }
+
+// NewContextWithSyntheticData creates a context in which the data source queries return the given frames instead of
+// querying the data sources, for example to simulate a rule.
+func NewContextWithSyntheticData(ctx context.Context, user identity.Requester, frames map[string]data.Frames) EvaluationContext {
+	if frames == nil {
+		frames = map[string]data.Frames{}
+	}
+	return EvaluationContext{
+		Ctx:           ctx,
+		User:          user,
+		SyntheticData: frames,
+	}
+}
@@ -329,7 +329,12 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 		if !ok {
 			switch nodeType := expr.NodeTypeFromDatasourceUID(q.DatasourceUID); nodeType {
 			case expr.TypeDatasourceNode:
-				ds, err = dsCacheService.GetDatasourceByUID(ctx.Ctx, q.DatasourceUID, ctx.User, false /*skipCache*/)
+				if ctx.SyntheticData != nil {
+					// the data source is not queried, so it does not need to exist.
+					ds = syntheticDataSource(q.DatasourceUID, ctx.User.GetOrgID())
+				} else {
+					ds, err = dsCacheService.GetDatasourceByUID(ctx.Ctx, q.DatasourceUID, ctx.User, false /*skipCache*/)
+				}
 			default:
 				ds, err = expr.DataSourceModelFromNodeType(nodeType)
 			}
@@ -339,6 +344,12 @@ func getExprRequest(ctx EvaluationContext, condition models.Condition, dsCacheSe
 			datasources[q.DatasourceUID] = ds
 		}
 
+		if ctx.SyntheticData != nil && ds.Type == SyntheticDatasourceType {
+			if _, ok := ctx.SyntheticData[q.RefID]; !ok {
+				return nil, fmt.Errorf("failed to build query '%s': no synthetic data is provided", q.RefID)
+			}
+		}
+
 		// TODO rewrite the code below and remove the mutable component from AlertQuery
 
 		// if the query is command expression and it's a hysteresis, patch it with the current state
@@ -771,7 +782,7 @@ func (e *evaluatorImpl) Validate(ctx EvaluationContext, condition models.Conditi
 		return err
 	}
 	for _, query := range req.Queries {
-		if query.DataSource == nil {
+		if query.DataSource == nil || query.DataSource.Type == SyntheticDatasourceType {
 			continue
 		}
 		switch expr.NodeTypeFromDatasourceUID(query.DataSource.UID) {
@@ -791,7 +802,7 @@ func (e *evaluatorImpl) Validate(ctx EvaluationContext, condition models.Conditi
 		case expr.TypeCMDNode:
 		}
 	}
-	_, err = e.create(condition, req)
+	_, err = e.create(condition, req, e.expressionServiceFor(ctx), e.evaluationTimeoutFor(ctx))
 	return err
 }
 
@@ -806,11 +817,29 @@ func (e *evaluatorImpl) Create(ctx EvaluationContext, condition models.Condition
 	if err != nil {
 		return nil, err
 	}
-	return e.create(condition, req)
+	return e.create(condition, req, e.expressionServiceFor(ctx), e.evaluationTimeoutFor(ctx))
+}
+
+// expressionServiceFor returns the expression service that answers the data source queries with the synthetic data of
+// the context, if it has any.
+func (e *evaluatorImpl) expressionServiceFor(ctx EvaluationContext) *expr.Service {
+	if ctx.SyntheticData == nil {
+		return e.expressionService
+	}
+	return e.expressionService.WithQueryDataHandler(syntheticDataHandler(ctx.SyntheticData))
+}
+
+// evaluationTimeoutFor returns the timeout of the evaluations in the context, which is the one of the context if it
+// has one.
+func (e *evaluatorImpl) evaluationTimeoutFor(ctx EvaluationContext) time.Duration {
+	if ctx.EvaluationTimeout > 0 {
+		return ctx.EvaluationTimeout
+	}
+	return e.evaluationTimeout
 }
 
-func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (ConditionEvaluator, error) {
-	pipeline, err := e.expressionService.BuildPipeline(req)
+func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request, expressionService *expr.Service, evalTimeout time.Duration) (ConditionEvaluator, error) {
+	pipeline, err := expressionService.BuildPipeline(req)
 	if err != nil {
 		return nil, err
 	}
@@ -819,9 +848,9 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 		if node.RefID() == condition.Condition {
 			return &conditionEvaluator{
 				pipeline:          pipeline,
-				expressionService: e.expressionService,
+				expressionService: expressionService,
 				condition:         condition,
-				evalTimeout:       e.evaluationTimeout,
+				evalTimeout:       evalTimeout,
 			}, nil
 		}
 		conditions = append(conditions, node.RefID())
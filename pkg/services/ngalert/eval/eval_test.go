@@ -730,6 +730,55 @@ func TestCreate_HysteresisCommand(t *testing.T) {
 	}
 }
 
+func TestCreate_SyntheticData(t *testing.T) {
+	u := &user.SignedInUser{OrgID: 1}
+	// the data source does not exist, so the evaluation fails if it is queried.
+	cacheService := &fakes.FakeCacheService{}
+	evaluator := NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, cacheService, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil, featuremgmt.WithFeatures(featuremgmt.FlagRecoveryThreshold), nil, tracing.InitializeTracerForTest()), &pluginstore.FakePluginStore{})
+
+	condition := models.Condition{
+		Condition: "C",
+		Data: []models.AlertQuery{
+			models.CreatePrometheusQuery("A", "up", 1000, 43200, false, "prometheus"),
+			models.CreateReduceExpression("B", "A", "last"),
+			models.CreateHysteresisExpression(t, "C", "B", 10, 5),
+		},
+	}
+	now := time.Now()
+	series := func(host string, values ...float64) *data.Frame {
+		times := make([]time.Time, 0, len(values))
+		for i := range values {
+			times = append(times, now.Add(time.Duration(i-len(values))*time.Minute))
+		}
+		return data.NewFrame("",
+			data.NewField("time", nil, times),
+			data.NewField("value", data.Labels{"host": host}, values),
+		)
+	}
+
+	t.Run("evaluates the condition with the synthetic data", func(t *testing.T) {
+		ctx := NewContextWithSyntheticData(context.Background(), u, map[string]data.Frames{
+			"A": {series("a", 1, 20), series("b", 20, 1)},
+		})
+		ev, err := evaluator.Create(ctx, condition)
+		require.NoError(t, err)
+
+		results, err := ev.Evaluate(context.Background(), now)
+		require.NoError(t, err)
+		states := make(map[string]State, len(results))
+		for _, result := range results {
+			states[result.Instance["host"]] = result.State
+		}
+		require.Equal(t, map[string]State{"a": Alerting, "b": Normal}, states)
+	})
+
+	t.Run("fails if a data source query has no synthetic data", func(t *testing.T) {
+		ctx := NewContextWithSyntheticData(context.Background(), u, nil)
+		_, err := evaluator.Create(ctx, condition)
+		require.ErrorContains(t, err, "no synthetic data")
+	})
+}
+
 func TestEvaluate(t *testing.T) {
 	cases := []struct {
 		name     string
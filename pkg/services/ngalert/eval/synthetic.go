@@ -0,0 +1,39 @@
+package eval
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+)
+
+// SyntheticDatasourceType is the type of the data sources of the queries that return synthetic data. See
+// EvaluationContext.SyntheticData.
+const SyntheticDatasourceType = "__synthetic__"
+
+// syntheticDataSource returns the data source of the queries with the given data source UID, which does not need to
+// exist.
+func syntheticDataSource(uid string, orgID int64) *datasources.DataSource {
+	return &datasources.DataSource{
+		UID:            uid,
+		OrgID:          orgID,
+		Name:           uid,
+		Type:           SyntheticDatasourceType,
+		JsonData:       simplejson.New(),
+		SecureJsonData: make(map[string][]byte),
+	}
+}
+
+// syntheticDataHandler answers the data source queries with the frames of their RefID.
+type syntheticDataHandler map[string]data.Frames
+
+func (h syntheticDataHandler) QueryData(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	resp := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		resp.Responses[q.RefID] = backend.DataResponse{Frames: h[q.RefID]}
+	}
+	return resp, nil
+}
@@ -23,6 +23,7 @@ type Scheduler struct {
 	SimpleNotificationRules             *prometheus.GaugeVec
 	GroupRules                          *prometheus.GaugeVec
 	Groups                              *prometheus.GaugeVec
+	UnservedRuleGroups                  *prometheus.GaugeVec
 	SchedulePeriodicDuration            prometheus.Histogram
 	SchedulableAlertRules               prometheus.Gauge
 	SchedulableAlertRulesHash           prometheus.Gauge
@@ -120,6 +121,15 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org"},
 		),
+		UnservedRuleGroups: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "unserved_rule_groups",
+				Help:      "The number of alert rule groups pinned to a scheduler shard that is not configured, whose rules are not evaluated.",
+			},
+			[]string{"org"},
+		),
 		SchedulePeriodicDuration: promauto.With(r).NewHistogram(
 			prometheus.HistogramOpts{
 				Namespace: Namespace,
@@ -54,7 +54,7 @@ func (om *OrgMigration) migrateAlert(ctx context.Context, l log.Logger, alert *l
 	if err != nil {
 		return nil, fmt.Errorf("parse settings: %w", err)
 	}
-	cond, err := transConditions(ctx, l, parsedSettings, alert.OrgID, om.migrationStore)
+	cond, err := transConditions(ctx, l, parsedSettings, alert.OrgID, om.migrationStore, om.cfg.UnifiedAlerting.Upgrade.ExpressionConditions)
 	if err != nil {
 		return nil, fmt.Errorf("transform conditions: %w", err)
 	}
@@ -2,13 +2,16 @@ package migration
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
 	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/dispatch"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/common/model"
 
@@ -33,11 +36,33 @@ var ErrDiscontinued = errors.New("discontinued")
 func (om *OrgMigration) migrateChannels(channels []*legacymodels.AlertNotification, log log.Logger) ([]*migmodels.ContactPair, error) {
 	// Create all newly migrated receivers from legacy notification channels.
 	pairs := make([]*migmodels.ContactPair, 0, len(channels))
+
+	// Channels with identical settings, keyed by their fingerprint. Only used when channel deduplication is enabled.
+	var identical map[string]*migmodels.ContactPair
+	if om.cfg.UnifiedAlerting.Upgrade.DeduplicateChannels {
+		identical = make(map[string]*migmodels.ContactPair)
+	}
 	for _, c := range channels {
 		l := log.New("type", c.Type, "name", c.Name, "uid", c.UID)
 		pair := &migmodels.ContactPair{
 			Channel: c,
 		}
+
+		var fingerprint string
+		if identical != nil {
+			var err error
+			fingerprint, err = om.channelFingerprint(c)
+			if err != nil {
+				l.Warn("Failed to compute channel fingerprint, channel will not be deduplicated", "error", err)
+			} else if existing, ok := identical[fingerprint]; ok {
+				l.Info("Merging channel into identical channel", "mergedInto", existing.Channel.Name)
+				pair.ContactPoint = existing.ContactPoint
+				pair.MergedInto = existing.Channel
+				pairs = append(pairs, pair)
+				continue
+			}
+		}
+
 		receiver, err := om.createReceiver(c)
 		if err != nil {
 			l.Warn("Failed to create receiver", "error", err)
@@ -56,11 +81,65 @@ func (om *OrgMigration) migrateChannels(channels []*legacymodels.AlertNotificati
 		}
 		pair.Route = route
 		pairs = append(pairs, pair)
+		if fingerprint != "" {
+			identical[fingerprint] = pair
+		}
 	}
 
 	return pairs, nil
 }
 
+// channelFingerprint returns a hash of everything that affects how a legacy channel is migrated, except for its name
+// and UID. Channels with the same fingerprint are migrated to identical contact points and routes.
+func (om *OrgMigration) channelFingerprint(c *legacymodels.AlertNotification) (string, error) {
+	settings := make(map[string]any)
+	if c.Settings != nil {
+		m, err := c.Settings.Map()
+		if err != nil {
+			return "", err
+		}
+		for k, v := range m {
+			settings[k] = v
+		}
+	}
+
+	// Older channels store some secrets in plain settings, normalize them the same way as migrateSettingsToSecureSettings.
+	secureSettings := SecureJsonData(c.SecureSettings).Decrypt(om.cfg.SecretKey)
+	for _, k := range secureKeysToMigrate[c.Type] {
+		if v, ok := secureSettings[k]; ok && v != "" {
+			continue
+		}
+		if sv, ok := settings[k].(string); ok && sv != "" {
+			secureSettings[k] = sv
+			delete(settings, k)
+		}
+	}
+
+	// Maps are marshalled with sorted keys, so the result is stable.
+	b, err := json.Marshal(struct {
+		Type                  string            `json:"type"`
+		IsDefault             bool              `json:"isDefault"`
+		DisableResolveMessage bool              `json:"disableResolveMessage"`
+		SendReminder          bool              `json:"sendReminder"`
+		Frequency             time.Duration     `json:"frequency"`
+		Settings              map[string]any    `json:"settings"`
+		SecureSettings        map[string]string `json:"secureSettings"`
+	}{
+		Type:                  c.Type,
+		IsDefault:             c.IsDefault,
+		DisableResolveMessage: c.DisableResolveMessage,
+		SendReminder:          c.SendReminder,
+		Frequency:             c.Frequency,
+		Settings:              settings,
+		SecureSettings:        secureSettings,
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 // createNotifier creates a PostableGrafanaReceiver from a legacy notification channel.
 func (om *OrgMigration) createReceiver(c *legacymodels.AlertNotification) (*apimodels.PostableGrafanaReceiver, error) {
 	if c.Type == "hipchat" || c.Type == "sensu" {
@@ -139,19 +218,41 @@ func createRoute(channel *legacymodels.AlertNotification, receiverName string) (
 		mat, _ = labels.NewMatcher(labels.MatchRegexp, model.AlertNameLabel, ".+")
 	}
 
-	repeatInterval := DisabledRepeatInterval
-	if channel.SendReminder {
-		repeatInterval = model.Duration(channel.Frequency)
-	}
+	repeatInterval, groupInterval := reminderIntervals(channel)
 
 	return &apimodels.Route{
 		Receiver:       receiverName,
 		ObjectMatchers: apimodels.ObjectMatchers{mat},
 		Continue:       true, // We continue so that each sibling contact point route can separately match.
-		RepeatInterval: &repeatInterval,
+		GroupInterval:  groupInterval,
+		RepeatInterval: repeatInterval,
 	}, nil
 }
 
+// reminderIntervals maps the legacy sendReminder and frequency channel options onto the repeat_interval and
+// group_interval of the migrated route.
+//
+// Alertmanager never sends notifications for a group more often than its group_interval, so a legacy frequency shorter
+// than the default group interval also lowers the group_interval of the route. Otherwise, reminders would silently be
+// sent at the default group interval instead of the configured frequency.
+func reminderIntervals(channel *legacymodels.AlertNotification) (*model.Duration, *model.Duration) {
+	if !channel.SendReminder {
+		repeatInterval := DisabledRepeatInterval
+		return &repeatInterval, nil
+	}
+	if channel.Frequency <= 0 {
+		// Reminders are enabled without a valid frequency, inherit the default repeat interval.
+		return nil, nil
+	}
+
+	repeatInterval := model.Duration(channel.Frequency)
+	if channel.Frequency >= dispatch.DefaultRouteOpts.GroupInterval {
+		return &repeatInterval, nil
+	}
+	groupInterval := repeatInterval
+	return &repeatInterval, &groupInterval
+}
+
 // contactLabel creates a label matcher key used to route alerts to a contact point.
 func contactLabel(name string) string {
 	return ngmodels.MigratedContactLabelPrefix + name + "__"
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,8 +19,10 @@ import (
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	legacymodels "github.com/grafana/grafana/pkg/services/alerting/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	migmodels "github.com/grafana/grafana/pkg/services/ngalert/migration/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
@@ -71,6 +74,33 @@ func TestCreateRoute(t *testing.T) {
 				RepeatInterval: durationPointer(model.Duration(time.Duration(42) * time.Hour)),
 			},
 		},
+		{
+			name:    "when a channel has sendReminder=true and a frequency lower than the default group interval, the route should use the frequency in group interval",
+			channel: &legacymodels.AlertNotification{SendReminder: true, Frequency: time.Minute, UID: "uid1", Name: "recv1"},
+			recv:    createPostableGrafanaReceiver("uid1", "recv1"),
+			expected: &apimodels.Route{
+				Receiver:       "recv1",
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: contactLabel("recv1"), Value: "true"}},
+				Routes:         nil,
+				Continue:       true,
+				GroupByStr:     nil,
+				GroupInterval:  durationPointer(model.Duration(time.Minute)),
+				RepeatInterval: durationPointer(model.Duration(time.Minute)),
+			},
+		},
+		{
+			name:    "when a channel has sendReminder=true without frequency, the route should inherit the default repeat interval",
+			channel: &legacymodels.AlertNotification{SendReminder: true, UID: "uid1", Name: "recv1"},
+			recv:    createPostableGrafanaReceiver("uid1", "recv1"),
+			expected: &apimodels.Route{
+				Receiver:       "recv1",
+				ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: contactLabel("recv1"), Value: "true"}},
+				Routes:         nil,
+				Continue:       true,
+				GroupByStr:     nil,
+				RepeatInterval: nil,
+			},
+		},
 		{
 			name:    "when a channel has sendReminder=false, the route should ignore the frequency in repeat interval and use DisabledRepeatInterval",
 			channel: &legacymodels.AlertNotification{SendReminder: false, Frequency: time.Duration(42) * time.Hour, UID: "uid1", Name: "recv1"},
@@ -424,12 +454,12 @@ func TestSetupAlertmanagerConfig(t *testing.T) {
 				{
 					Channel:      createNotChannel(t, "uid1", int64(1), "notifier1", false, time.Duration(42)),
 					ContactPoint: createPostableGrafanaReceiver("uid1", "notifier1"),
-					Route:        &apimodels.Route{Receiver: "notifier1", ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: contactLabel("notifier1"), Value: "true"}}, Routes: nil, Continue: true, RepeatInterval: durationPointer(42)},
+					Route:        &apimodels.Route{Receiver: "notifier1", ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: contactLabel("notifier1"), Value: "true"}}, Routes: nil, Continue: true, GroupInterval: durationPointer(42), RepeatInterval: durationPointer(42)},
 				},
 				{
 					Channel:      createNotChannel(t, "uid2", int64(2), "notifier2", false, time.Duration(43)),
 					ContactPoint: createPostableGrafanaReceiver("uid2", "notifier2"),
-					Route:        &apimodels.Route{Receiver: "notifier2", ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: contactLabel("notifier2"), Value: "true"}}, Routes: nil, Continue: true, RepeatInterval: durationPointer(43)},
+					Route:        &apimodels.Route{Receiver: "notifier2", ObjectMatchers: apimodels.ObjectMatchers{{Type: labels.MatchEqual, Name: contactLabel("notifier2"), Value: "true"}}, Routes: nil, Continue: true, GroupInterval: durationPointer(43), RepeatInterval: durationPointer(43)},
 				},
 			},
 		},
@@ -490,3 +520,97 @@ func createPostableGrafanaReceiver(uid string, name string) *apimodels.PostableG
 func durationPointer(d model.Duration) *model.Duration {
 	return &d
 }
+
+func TestDeduplicateChannels(t *testing.T) {
+	channels := []*legacymodels.AlertNotification{
+		createAlertNotification(t, int64(1), "notifier1", "email", emailSettings, false),
+		createAlertNotification(t, int64(1), "notifier2", "email", emailSettings, false),
+		createAlertNotification(t, int64(1), "notifier3", "email", `{"addresses": "other"}`, false),
+		createAlertNotificationWithReminder(t, int64(1), "notifier4", "email", emailSettings, false, true, time.Hour),
+	}
+	// The channels are inserted at once, which does not set their IDs.
+	for i, c := range channels {
+		c.ID = int64(i + 1)
+	}
+	alerts := []*legacymodels.Alert{
+		createAlert(t, 1, 1, 1, "alert1", []string{"notifier1"}),
+		createAlert(t, 1, 1, 2, "alert2", []string{"notifier2"}),
+		createAlert(t, 1, 1, 3, "alert3", []string{"notifier3"}),
+	}
+	dashes := []*dashboards.Dashboard{
+		createDashboard(t, 1, 1, "dash1-1", "folder5-1", 5, nil),
+	}
+	folders := []*dashboards.Dashboard{
+		createFolder(t, 5, 1, "folder5-1"),
+	}
+
+	sqlStore := db.InitTestDB(t)
+	x := sqlStore.GetEngine()
+	setupLegacyAlertsTables(t, x, channels, alerts, folders, dashes)
+
+	ctx := context.Background()
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.Upgrade.DeduplicateChannels = true
+	service := NewTestMigrationService(t, sqlStore, cfg)
+
+	receiverNames := func(t *testing.T) []string {
+		t.Helper()
+		amConfig, err := service.migrationStore.GetAlertmanagerConfig(ctx, 1)
+		require.NoError(t, err)
+		names := make([]string, 0)
+		for _, r := range amConfig.AlertmanagerConfig.Receivers {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+	ruleLabels := func(t *testing.T) map[string][]string {
+		t.Helper()
+		res := make(map[string][]string)
+		for _, r := range getAlertRules(t, x, 1) {
+			for k := range r.Labels {
+				if strings.HasPrefix(k, ngmodels.MigratedContactLabelPrefix) {
+					res[r.Title] = append(res[r.Title], k)
+				}
+			}
+		}
+		return res
+	}
+
+	summary, err := service.MigrateOrg(ctx, 1, false)
+	require.NoError(t, err)
+	require.Equal(t, 4, summary.NewChannels)
+	require.Equal(t, 1, summary.MergedChannels)
+
+	t.Run("identical channels share a contact point", func(t *testing.T) {
+		require.ElementsMatch(t, []string{"autogen-contact-point-default", "notifier1", "notifier3", "notifier4"}, receiverNames(t))
+		require.Equal(t, map[string][]string{
+			"alert1": {contactLabel("notifier1")},
+			"alert2": {contactLabel("notifier1")},
+			"alert3": {contactLabel("notifier3")},
+		}, ruleLabels(t))
+
+		state, err := service.GetOrgMigrationState(ctx, 1)
+		require.NoError(t, err)
+		for _, pair := range state.MigratedChannels {
+			if pair.LegacyChannel.Name == "notifier2" {
+				require.Equal(t, channels[0].ID, pair.MergedIntoChannelID)
+				require.NotNil(t, pair.ContactPointUpgrade)
+				require.Equal(t, "notifier1", pair.ContactPointUpgrade.Name)
+			} else {
+				require.Zero(t, pair.MergedIntoChannelID)
+			}
+		}
+	})
+
+	t.Run("re-upgrading a merged channel on its own gives it its own contact point", func(t *testing.T) {
+		_, err := service.MigrateChannel(ctx, 1, channels[1].ID)
+		require.NoError(t, err)
+
+		require.ElementsMatch(t, []string{"autogen-contact-point-default", "notifier1", "notifier2", "notifier3", "notifier4"}, receiverNames(t))
+		require.Equal(t, map[string][]string{
+			"alert1": {contactLabel("notifier1")},
+			"alert2": {contactLabel("notifier2")},
+			"alert3": {contactLabel("notifier3")},
+		}, ruleLabels(t))
+	})
+}
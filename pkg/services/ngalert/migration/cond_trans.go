@@ -66,7 +66,7 @@ type evaluator struct {
 }
 
 //nolint:gocyclo
-func transConditions(ctx context.Context, l log.Logger, set dashAlertSettings, orgID int64, store migrationStore.ReadStore) (*condition, error) {
+func transConditions(ctx context.Context, l log.Logger, set dashAlertSettings, orgID int64, store migrationStore.ReadStore, useExpressions bool) (*condition, error) {
 	// TODO: needs a significant refactor to reduce complexity.
 	usr := getMigrationUser(orgID)
 
@@ -243,6 +243,23 @@ func transConditions(ctx context.Context, l log.Logger, set dashAlertSettings, o
 		}
 	}
 
+	if useExpressions {
+		exprData, condRefID, err := expressionPipeline(set.Conditions, condIdxToNewRefID, newRefIDstoCondIdx)
+		if err == nil {
+			newCond.Condition = condRefID
+			newCond.OrgID = orgID
+			newCond.Data = append(newCond.Data, exprData...)
+			sort.Slice(newCond.Data, func(i, j int) bool {
+				return newCond.Data[i].RefID < newCond.Data[j].RefID
+			})
+			return newCond, nil
+		}
+		if !errors.Is(err, errUnsupportedExpressionCondition) {
+			return nil, err
+		}
+		l.Debug("Falling back to classic condition", "reason", err)
+	}
+
 	// build the new classic condition pointing our new equivalent queries
 	conditions := make([]classicCondition, len(set.Conditions))
 	for i, cond := range set.Conditions {
@@ -295,6 +312,190 @@ func transConditions(ctx context.Context, l log.Logger, set dashAlertSettings, o
 	return newCond, nil
 }
 
+// errUnsupportedExpressionCondition is returned when legacy conditions cannot be converted to an equivalent expression
+// pipeline and must be migrated to a classic condition instead.
+var errUnsupportedExpressionCondition = errors.New("unsupported condition")
+
+// legacyReducer is the reduce expression equivalent to a legacy reducer.
+type legacyReducer struct {
+	reducer string
+	// dropNN is true if the legacy reducer ignores null and NaN values.
+	dropNN bool
+}
+
+// legacyReducers contains the legacy reducers that have an equivalent reduce expression. Reducers such as median or
+// diff have no equivalent and cannot be converted.
+var legacyReducers = map[string]legacyReducer{
+	"avg":            {reducer: "mean", dropNN: true},
+	"sum":            {reducer: "sum", dropNN: true},
+	"min":            {reducer: "min", dropNN: true},
+	"max":            {reducer: "max", dropNN: true},
+	"last":           {reducer: "last", dropNN: true},
+	"count":          {reducer: "count"},
+	"count_non_null": {reducer: "count", dropNN: true},
+}
+
+type reduceSettings struct {
+	Mode string `json:"mode"`
+}
+
+type reduceModel struct {
+	Type       string          `json:"type"`
+	RefID      string          `json:"refId"`
+	Expression string          `json:"expression"`
+	Reducer    string          `json:"reducer"`
+	Settings   *reduceSettings `json:"settings,omitempty"`
+}
+
+type thresholdCondition struct {
+	Evaluator evaluator `json:"evaluator"`
+}
+
+type thresholdModel struct {
+	Type       string               `json:"type"`
+	RefID      string               `json:"refId"`
+	Expression string               `json:"expression"`
+	Conditions []thresholdCondition `json:"conditions"`
+}
+
+type mathModel struct {
+	Type       string `json:"type"`
+	RefID      string `json:"refId"`
+	Expression string `json:"expression"`
+}
+
+// expressionPipeline converts legacy conditions to server-side expressions. Each condition becomes a reduce expression
+// over its query followed by a threshold expression. If there are several conditions, a math expression combines the
+// thresholds using the operators of the conditions, evaluated from left to right like classic conditions.
+//
+// It returns the new expressions and the RefID of the expression to use as the alert condition. New RefIDs are
+// reserved in usedRefIDs. errUnsupportedExpressionCondition is returned if any of the conditions cannot be converted,
+// in which case no RefID is reserved.
+func expressionPipeline(conds []dashAlertCondition, condIdxToNewRefID map[int]string, usedRefIDs map[string][]int) ([]ngmodels.AlertQuery, string, error) {
+	if len(conds) == 0 {
+		return nil, "", fmt.Errorf("%w: no conditions", errUnsupportedExpressionCondition)
+	}
+	for i, cond := range conds {
+		if _, ok := legacyReducers[cond.Reducer.Type]; !ok {
+			return nil, "", fmt.Errorf("%w: reducer '%s' in condition %d has no equivalent expression", errUnsupportedExpressionCondition, cond.Reducer.Type, i+1)
+		}
+		if _, err := thresholdEvaluator(cond.Evaluator); err != nil {
+			return nil, "", fmt.Errorf("%w: condition %d: %s", errUnsupportedExpressionCondition, i+1, err)
+		}
+	}
+
+	newRefID := func() (string, error) {
+		refID, err := getNewRefID(usedRefIDs)
+		if err != nil {
+			return "", err
+		}
+		usedRefIDs[refID] = nil
+		return refID, nil
+	}
+
+	data := make([]ngmodels.AlertQuery, 0, 2*len(conds)+1)
+	addExpression := func(refID string, model any) error {
+		encoded, err := json.Marshal(model)
+		if err != nil {
+			return err
+		}
+		data = append(data, ngmodels.AlertQuery{
+			RefID:         refID,
+			Model:         encoded,
+			DatasourceUID: expressionDatasourceUID,
+		})
+		return nil
+	}
+
+	var combined string
+	var lastRefID string
+	for i, cond := range conds {
+		reduceRefID, err := newRefID()
+		if err != nil {
+			return nil, "", err
+		}
+		r := legacyReducers[cond.Reducer.Type]
+		reduce := reduceModel{
+			Type:       "reduce",
+			RefID:      reduceRefID,
+			Expression: condIdxToNewRefID[i],
+			Reducer:    r.reducer,
+		}
+		if r.dropNN {
+			reduce.Settings = &reduceSettings{Mode: "dropNN"}
+		}
+		if err := addExpression(reduceRefID, reduce); err != nil {
+			return nil, "", err
+		}
+
+		thresholdRefID, err := newRefID()
+		if err != nil {
+			return nil, "", err
+		}
+		eval, _ := thresholdEvaluator(cond.Evaluator)
+		threshold := thresholdModel{
+			Type:       "threshold",
+			RefID:      thresholdRefID,
+			Expression: reduceRefID,
+			Conditions: []thresholdCondition{{Evaluator: eval}},
+		}
+		if err := addExpression(thresholdRefID, threshold); err != nil {
+			return nil, "", err
+		}
+		lastRefID = thresholdRefID
+
+		// Classic conditions treat any operator other than "or" as "and".
+		op := "&&"
+		if cond.Operator.Type == "or" {
+			op = "||"
+		}
+		switch i {
+		case 0:
+			combined = fmt.Sprintf("${%s}", thresholdRefID)
+		case 1:
+			combined = fmt.Sprintf("%s %s ${%s}", combined, op, thresholdRefID)
+		default:
+			combined = fmt.Sprintf("(%s) %s ${%s}", combined, op, thresholdRefID)
+		}
+	}
+
+	if len(conds) == 1 {
+		return data, lastRefID, nil
+	}
+
+	mathRefID, err := newRefID()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := addExpression(mathRefID, mathModel{Type: "math", RefID: mathRefID, Expression: combined}); err != nil {
+		return nil, "", err
+	}
+	return data, mathRefID, nil
+}
+
+// thresholdEvaluator returns the threshold expression evaluator equivalent to the legacy evaluator. Legacy range
+// evaluators accept their bounds in any order, while threshold expressions expect the lower bound first.
+func thresholdEvaluator(e evaluator) (evaluator, error) {
+	switch e.Type {
+	case "gt", "lt":
+		if len(e.Params) < 1 {
+			return evaluator{}, fmt.Errorf("evaluator '%s' is missing the threshold parameter", e.Type)
+		}
+		return evaluator{Type: e.Type, Params: []float64{e.Params[0]}}, nil
+	case "within_range", "outside_range":
+		if len(e.Params) != 2 {
+			return evaluator{}, fmt.Errorf("evaluator '%s' requires 2 parameters", e.Type)
+		}
+		lower, upper := e.Params[0], e.Params[1]
+		if lower > upper {
+			lower, upper = upper, lower
+		}
+		return evaluator{Type: e.Type, Params: []float64{lower, upper}}, nil
+	default:
+		return evaluator{}, fmt.Errorf("evaluator '%s' has no equivalent expression", e.Type)
+	}
+}
+
 type condition struct {
 	// Condition is the RefID of the query or expression from
 	// the Data property to get the results for.
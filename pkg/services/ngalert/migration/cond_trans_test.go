@@ -69,7 +69,7 @@ func TestCondTransMultiCondOnSingleQuery(t *testing.T) {
 	}
 
 	migrationStore := store.NewTestMigrationStore(t, db.InitTestDB(t), &setting.Cfg{})
-	c, err := transConditions(context.Background(), &logtest.Fake{}, settings, ordID, migrationStore)
+	c, err := transConditions(context.Background(), &logtest.Fake{}, settings, ordID, migrationStore, false)
 
 	require.NoError(t, err)
 	require.Equal(t, expected, c)
@@ -184,8 +184,128 @@ func TestCondTransExtended(t *testing.T) {
 	}
 
 	migrationStore := store.NewTestMigrationStore(t, db.InitTestDB(t), &setting.Cfg{})
-	c, err := transConditions(context.Background(), &logtest.Fake{}, settings, ordID, migrationStore)
+	c, err := transConditions(context.Background(), &logtest.Fake{}, settings, ordID, migrationStore, false)
 
 	require.NoError(t, err)
 	require.Equal(t, expected, c)
 }
+
+func TestCondTransExpressions(t *testing.T) {
+	ordID := int64(1)
+	migrationStore := store.NewTestMigrationStore(t, db.InitTestDB(t), &setting.Cfg{})
+
+	newCond := func(refID, reducer, evalType string, params ...float64) dashAlertCondition {
+		c := dashAlertCondition{}
+		c.Evaluator.Type = evalType
+		c.Evaluator.Params = params
+		c.Operator.Type = "and"
+		c.Query.DatasourceID = 4
+		c.Query.Model = []byte(`{"datasource":{"type":"graphite","uid":"1"},"refId":"` + refID + `","target":"my_metric"}`)
+		c.Query.Params = []string{refID, "5m", "now"}
+		c.Reducer.Type = reducer
+		return c
+	}
+	withOperator := func(c dashAlertCondition, op string) dashAlertCondition {
+		c.Operator.Type = op
+		return c
+	}
+
+	testCases := []struct {
+		name       string
+		conditions []dashAlertCondition
+		condition  string
+		expected   map[string]string
+	}{
+		{
+			name:       "gt with avg",
+			conditions: []dashAlertCondition{newCond("A", "avg", "gt", 10)},
+			condition:  "C",
+			expected: map[string]string{
+				"B": `{"type":"reduce","refId":"B","expression":"A","reducer":"mean","settings":{"mode":"dropNN"}}`,
+				"C": `{"type":"threshold","refId":"C","expression":"B","conditions":[{"evaluator":{"params":[10],"type":"gt"}}]}`,
+			},
+		},
+		{
+			name:       "lt with count",
+			conditions: []dashAlertCondition{newCond("A", "count", "lt", 1)},
+			condition:  "C",
+			expected: map[string]string{
+				"B": `{"type":"reduce","refId":"B","expression":"A","reducer":"count"}`,
+				"C": `{"type":"threshold","refId":"C","expression":"B","conditions":[{"evaluator":{"params":[1],"type":"lt"}}]}`,
+			},
+		},
+		{
+			name:       "within_range with count_non_null",
+			conditions: []dashAlertCondition{newCond("A", "count_non_null", "within_range", 1, 5)},
+			condition:  "C",
+			expected: map[string]string{
+				"B": `{"type":"reduce","refId":"B","expression":"A","reducer":"count","settings":{"mode":"dropNN"}}`,
+				"C": `{"type":"threshold","refId":"C","expression":"B","conditions":[{"evaluator":{"params":[1,5],"type":"within_range"}}]}`,
+			},
+		},
+		{
+			name:       "outside_range with reversed bounds",
+			conditions: []dashAlertCondition{newCond("A", "max", "outside_range", 100, -100)},
+			condition:  "C",
+			expected: map[string]string{
+				"B": `{"type":"reduce","refId":"B","expression":"A","reducer":"max","settings":{"mode":"dropNN"}}`,
+				"C": `{"type":"threshold","refId":"C","expression":"B","conditions":[{"evaluator":{"params":[-100,100],"type":"outside_range"}}]}`,
+			},
+		},
+		{
+			name: "multiple conditions are combined from left to right",
+			conditions: []dashAlertCondition{
+				newCond("A", "last", "gt", 10),
+				withOperator(newCond("B", "min", "within_range", 0, 1), "or"),
+				newCond("C", "sum", "lt", 3),
+			},
+			condition: "J",
+			expected: map[string]string{
+				"D": `{"type":"reduce","refId":"D","expression":"A","reducer":"last","settings":{"mode":"dropNN"}}`,
+				"E": `{"type":"threshold","refId":"E","expression":"D","conditions":[{"evaluator":{"params":[10],"type":"gt"}}]}`,
+				"F": `{"type":"reduce","refId":"F","expression":"B","reducer":"min","settings":{"mode":"dropNN"}}`,
+				"G": `{"type":"threshold","refId":"G","expression":"F","conditions":[{"evaluator":{"params":[0,1],"type":"within_range"}}]}`,
+				"H": `{"type":"reduce","refId":"H","expression":"C","reducer":"sum","settings":{"mode":"dropNN"}}`,
+				"I": `{"type":"threshold","refId":"I","expression":"H","conditions":[{"evaluator":{"params":[3],"type":"lt"}}]}`,
+				"J": `{"type":"math","refId":"J","expression":"(${E} || ${G}) \u0026\u0026 ${I}"}`,
+			},
+		},
+		{
+			name:       "median falls back to classic condition",
+			conditions: []dashAlertCondition{newCond("A", "median", "gt", 10)},
+			condition:  "B",
+			expected: map[string]string{
+				"B": `{"type":"classic_conditions","refId":"B","conditions":[{"evaluator":{"params":[10],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"median"}}]}`,
+			},
+		},
+		{
+			name: "no_value falls back to classic condition",
+			conditions: []dashAlertCondition{
+				newCond("A", "avg", "gt", 10),
+				newCond("B", "avg", "no_value"),
+			},
+			condition: "C",
+			expected: map[string]string{
+				"C": `{"type":"classic_conditions","refId":"C","conditions":[{"evaluator":{"params":[10],"type":"gt"},"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"avg"}},{"evaluator":{"params":null,"type":"no_value"},"operator":{"type":"and"},"query":{"params":["B"]},"reducer":{"type":"avg"}}]}`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := dashAlertSettings{Conditions: tc.conditions}
+			c, err := transConditions(context.Background(), &logtest.Fake{}, settings, ordID, migrationStore, true)
+			require.NoError(t, err)
+			require.Equal(t, tc.condition, c.Condition)
+
+			expressions := make(map[string]string)
+			for _, q := range c.Data {
+				if q.DatasourceUID == expr.DatasourceUID {
+					expressions[q.RefID] = string(q.Model)
+				}
+			}
+			require.Len(t, c.Data, len(tc.conditions)+len(tc.expected))
+			require.Equal(t, tc.expected, expressions)
+		})
+	}
+}
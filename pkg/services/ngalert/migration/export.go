@@ -0,0 +1,123 @@
+package migration
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	migmodels "github.com/grafana/grafana/pkg/services/ngalert/migration/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// OrgMigrationExport contains the unified alerting resources created by the migration of an org, in a form that can
+// be converted to provisioning files.
+type OrgMigrationExport struct {
+	Groups        []models.AlertRuleGroupWithFolderTitle
+	ContactPoints []definitions.EmbeddedContactPoint
+	// Policies is the full notification policy tree of the org, since the provisioning file format only supports
+	// replacing the whole tree.
+	Policies *definitions.Route
+}
+
+// GetOrgMigrationExport returns the alert rules, contact points and notification policies created by the migration of
+// the given org. Rules and contact points created outside the migration are not included. Secure settings of contact
+// points are redacted unless decrypt is true.
+func (ms *migrationService) GetOrgMigrationExport(ctx context.Context, orgID int64, decrypt bool) (*OrgMigrationExport, error) {
+	state, err := ms.migrationStore.GetOrgMigrationState(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("get org migration state: %w", err)
+	}
+
+	cfg, err := ms.migrationStore.GetAlertmanagerConfig(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("get alertmanager config: %w", err)
+	}
+	amConfig := migmodels.FromPostableUserConfig(cfg)
+
+	res := &OrgMigrationExport{
+		Groups:        make([]models.AlertRuleGroupWithFolderTitle, 0),
+		ContactPoints: make([]definitions.EmbeddedContactPoint, 0, len(state.MigratedChannels)),
+	}
+	if cfg != nil {
+		res.Policies = cfg.AlertmanagerConfig.Route
+	}
+
+	for _, pair := range state.MigratedChannels {
+		if pair.NewReceiverUID == "" {
+			continue
+		}
+		recv, ok := amConfig.GetReceiver(pair.NewReceiverUID)
+		if !ok {
+			// The contact point was deleted after the migration.
+			continue
+		}
+		cp, err := provisioning.PostableGrafanaReceiverToEmbeddedContactPoint(recv, models.ProvenanceNone, ms.decryptValueOrRedacted(ctx, decrypt))
+		if err != nil {
+			return nil, fmt.Errorf("convert contact point '%s': %w", recv.Name, err)
+		}
+		res.ContactPoints = append(res.ContactPoints, cp)
+	}
+
+	ruleUIDs := make([]string, 0)
+	for _, du := range state.MigratedDashboards {
+		for _, pair := range du.MigratedAlerts {
+			if pair.NewRuleUID != "" {
+				ruleUIDs = append(ruleUIDs, pair.NewRuleUID)
+			}
+		}
+	}
+	rules, err := ms.migrationStore.GetAlertRules(ctx, orgID, ruleUIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("get alert rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return res, nil
+	}
+
+	dashboards, err := ms.migrationStore.GetSlimDashboards(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("get folders: %w", err)
+	}
+	folderTitles := make(map[string]string, len(dashboards))
+	for _, d := range dashboards {
+		folderTitles[d.UID] = d.Title
+	}
+
+	groups := make(map[models.AlertRuleGroupKey][]models.AlertRule)
+	for _, r := range rules {
+		groups[r.GetGroupKey()] = append(groups[r.GetGroupKey()], *r)
+	}
+	for key, groupRules := range groups {
+		title, ok := folderTitles[key.NamespaceUID]
+		if !ok {
+			return nil, fmt.Errorf("cannot find title for folder with uid '%s'", key.NamespaceUID)
+		}
+		res.Groups = append(res.Groups, models.NewAlertRuleGroupWithFolderTitle(key, groupRules, title))
+	}
+	models.SortAlertRuleGroupWithFolderTitle(res.Groups)
+
+	return res, nil
+}
+
+// decryptValueOrRedacted returns a function that decodes and decrypts a secure setting of a migrated contact point.
+// If decrypt is false, definitions.RedactedValue is returned instead of the decrypted value.
+func (ms *migrationService) decryptValueOrRedacted(ctx context.Context, decrypt bool) func(v string) string {
+	return func(value string) string {
+		if !decrypt {
+			return definitions.RedactedValue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			ms.log.FromContext(ctx).Warn("Failed to decode secret value from Base64", "error", err)
+			return ""
+		}
+		decrypted, err := ms.encryptionService.Decrypt(ctx, decoded)
+		if err != nil {
+			ms.log.FromContext(ctx).Warn("Failed to decrypt secret value", "error", err)
+			return ""
+		}
+		return string(decrypted)
+	}
+}
@@ -0,0 +1,65 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	legacymodels "github.com/grafana/grafana/pkg/services/alerting/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+func TestGetOrgMigrationExport(t *testing.T) {
+	alerts := []*legacymodels.Alert{
+		createAlert(t, 1, 1, 1, "alert1", []string{"notifier1"}),
+	}
+	channels := []*legacymodels.AlertNotification{
+		createAlertNotification(t, int64(1), "notifier1", "email", emailSettings, false),
+	}
+	dashes := []*dashboards.Dashboard{
+		createDashboard(t, 1, 1, "dash1-1", "folder5-1", 5, nil),
+	}
+	folders := []*dashboards.Dashboard{
+		createFolder(t, 5, 1, "folder5-1"),
+	}
+
+	sqlStore := db.InitTestDB(t)
+	setupLegacyAlertsTables(t, sqlStore.GetEngine(), channels, alerts, folders, dashes)
+
+	ctx := context.Background()
+	service := NewTestMigrationService(t, sqlStore, nil)
+
+	t.Run("org that was not upgraded exports nothing", func(t *testing.T) {
+		export, err := service.GetOrgMigrationExport(ctx, 1, false)
+		require.NoError(t, err)
+		require.Empty(t, export.Groups)
+		require.Empty(t, export.ContactPoints)
+	})
+
+	_, err := service.MigrateOrg(ctx, 1, false)
+	require.NoError(t, err)
+
+	t.Run("upgraded org exports migrated resources", func(t *testing.T) {
+		export, err := service.GetOrgMigrationExport(ctx, 1, false)
+		require.NoError(t, err)
+
+		require.Len(t, export.Groups, 1)
+		require.Len(t, export.Groups[0].Rules, 1)
+		require.Equal(t, "alert1", export.Groups[0].Rules[0].Title)
+		require.NotEmpty(t, export.Groups[0].FolderTitle)
+
+		require.Len(t, export.ContactPoints, 1)
+		require.Equal(t, "notifier1", export.ContactPoints[0].Name)
+
+		require.NotNil(t, export.Policies)
+		receivers := make([]string, 0)
+		for _, nested := range export.Policies.Routes {
+			for _, r := range nested.Routes {
+				receivers = append(receivers, r.Receiver)
+			}
+		}
+		require.Contains(t, receivers, "notifier1")
+	})
+}
@@ -26,7 +26,9 @@ type ContactPair struct {
 	Channel      *legacymodels.AlertNotification
 	ContactPoint *apiModels.PostableGrafanaReceiver
 	Route        *apiModels.Route
-	Error        error
+	// MergedInto is the identical channel whose contact point and route are reused by this channel, if any.
+	MergedInto *legacymodels.AlertNotification
+	Error      error
 }
 
 func NewAlertPair(da *legacymodels.Alert, err error) *AlertPair {
@@ -149,6 +149,12 @@ func summaryFromDelta(delta StateDelta) apiModels.OrgMigrationSummary {
 		HasErrors:     hasErrors(delta),
 	}
 
+	for _, pair := range delta.ChannelsToAdd {
+		if pair.MergedInto != nil {
+			summary.MergedChannels++
+		}
+	}
+
 	for _, du := range delta.DashboardsToAdd {
 		summary.NewAlerts += len(du.MigratedAlerts)
 	}
@@ -221,6 +227,14 @@ func (sync *sync) handleAlertmanager(ctx context.Context, state *migrationStore.
 		}
 	}
 
+	// Receivers that are recreated by this delta. Removing them is safe even if they are shared with merged channels.
+	recreatedReceivers := make(map[string]struct{})
+	for _, pair := range delta.ChannelsToAdd {
+		if pair.ContactPoint != nil && pair.MergedInto == nil {
+			recreatedReceivers[pair.ContactPoint.UID] = struct{}{}
+		}
+	}
+
 	// Information tracked to facilitate alert rule contact point label updates.
 	ruleToAddLabels := make(map[string][]string)
 	ruleToRemoveLabels := make(map[string][]string)
@@ -228,6 +242,18 @@ func (sync *sync) handleAlertmanager(ctx context.Context, state *migrationStore.
 		delete(state.MigratedChannels, pair.LegacyID)
 		if pair.NewReceiverUID != "" {
 			label := amConfig.GetContactLabel(pair.NewReceiverUID)
+			_, recreated := recreatedReceivers[pair.NewReceiverUID]
+			sharedWith := channelsWithReceiver(state, pair.NewReceiverUID)
+			if !recreated && len(sharedWith) > 0 {
+				// The contact point is still used by other merged channels, so we keep it and only remove the label from
+				// rules that don't reference any of them.
+				for _, uid := range rulesWithChannels[pair.LegacyID] {
+					if !ruleReferencesAny(rulesWithChannels, uid, sharedWith) {
+						ruleToRemoveLabels[uid] = append(ruleToRemoveLabels[uid], label)
+					}
+				}
+				continue
+			}
 			for _, uid := range rulesWithChannels[pair.LegacyID] {
 				ruleToRemoveLabels[uid] = append(ruleToRemoveLabels[uid], label)
 			}
@@ -238,8 +264,11 @@ func (sync *sync) handleAlertmanager(ctx context.Context, state *migrationStore.
 
 	for _, pair := range delta.ChannelsToAdd {
 		state.MigratedChannels[pair.Channel.ID] = newContactPair(pair)
-		amConfig.AddReceiver(pair.ContactPoint)
-		amConfig.AddRoute(pair.Route)
+		if pair.MergedInto == nil {
+			// Merged channels reuse the receiver and route of the channel they were merged into.
+			amConfig.AddReceiver(pair.ContactPoint)
+			amConfig.AddRoute(pair.Route)
+		}
 		if pair.ContactPoint != nil {
 			for _, uid := range rulesWithChannels[pair.Channel.ID] {
 				ruleToAddLabels[uid] = append(ruleToAddLabels[uid], contactLabel(pair.ContactPoint.Name))
@@ -269,6 +298,29 @@ func (sync *sync) handleAlertmanager(ctx context.Context, state *migrationStore.
 	return amConfig, nil
 }
 
+// channelsWithReceiver returns the IDs of the migrated channels in the state that use the given receiver.
+func channelsWithReceiver(state *migrationStore.OrgMigrationState, receiverUID string) []int64 {
+	var ids []int64
+	for _, p := range state.MigratedChannels {
+		if p.NewReceiverUID == receiverUID {
+			ids = append(ids, p.LegacyID)
+		}
+	}
+	return ids
+}
+
+// ruleReferencesAny returns true if the rule with the given UID references any of the given channels.
+func ruleReferencesAny(rulesWithChannels map[int64][]string, ruleUID string, channelIDs []int64) bool {
+	for _, id := range channelIDs {
+		for _, uid := range rulesWithChannels[id] {
+			if uid == ruleUID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // replaceLabels replaces labels for the given alert rule UIDs.
 func (sync *sync) replaceLabels(ctx context.Context, ruleToAddLabels map[string][]string, ruleToRemoveLabels map[string][]string) error {
 	var ruleUIDs []string
@@ -577,6 +629,9 @@ func newContactPair(pair *migmodels.ContactPair) *migrationStore.ContactPair {
 	if pair.ContactPoint != nil {
 		p.NewReceiverUID = pair.ContactPoint.UID
 	}
+	if pair.MergedInto != nil {
+		p.MergedIntoID = pair.MergedInto.ID
+	}
 	return p
 }
 
@@ -38,6 +38,14 @@ type UpgradeService interface {
 	MigrateOrg(ctx context.Context, orgID int64, skipExisting bool) (definitions.OrgMigrationSummary, error)
 	GetOrgMigrationState(ctx context.Context, orgID int64) (*definitions.OrgMigrationState, error)
 	RevertOrg(ctx context.Context, orgID int64) error
+	GetOrgMigrationExport(ctx context.Context, orgID int64, decrypt bool) (*OrgMigrationExport, error)
+
+	EnqueueOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error)
+	GetStagedUpgrade(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error)
+	GetStagedUpgrades(ctx context.Context) ([]*migrationStore.StagedUpgrade, error)
+	DryRunStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error)
+	ApproveStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error)
+	ExecuteStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error)
 }
 
 type migrationService struct {
@@ -507,20 +515,7 @@ func (ms *migrationService) migrateAllOrgs(ctx context.Context) error {
 		}
 
 		// Check for errors, if any exist log and fail the migration.
-		errs := migmodels.ExtractErrors(dashboardUpgrades, contactPairs)
-		var migrationErr error
-		for _, e := range errs {
-			// Skip certain errors as historically they are not fatal to the migration. We can revisit these if necessary.
-			if errors.Is(e, ErrDiscontinued) {
-				// Discontinued notification type.
-				continue
-			}
-			if errors.Is(e, ErrOrphanedAlert) {
-				// Orphaned alerts.
-				continue
-			}
-			migrationErr = errors.Join(migrationErr, e)
-		}
+		migrationErr := fatalMigrationErrors(migmodels.ExtractErrors(dashboardUpgrades, contactPairs))
 		if migrationErr != nil {
 			return fmt.Errorf("migrate org %d: %w", o.ID, migrationErr)
 		}
@@ -543,6 +538,24 @@ func (ms *migrationService) migrateAllOrgs(ctx context.Context) error {
 	return nil
 }
 
+// fatalMigrationErrors joins the given migration errors, skipping those that are not fatal to the migration.
+func fatalMigrationErrors(errs []error) error {
+	var migrationErr error
+	for _, e := range errs {
+		// Skip certain errors as historically they are not fatal to the migration. We can revisit these if necessary.
+		if errors.Is(e, ErrDiscontinued) {
+			// Discontinued notification type.
+			continue
+		}
+		if errors.Is(e, ErrOrphanedAlert) {
+			// Orphaned alerts.
+			continue
+		}
+		migrationErr = errors.Join(migrationErr, e)
+	}
+	return migrationErr
+}
+
 // RevertOrg reverts the migration, deleting all unified alerting resources such as alert rules, alertmanager
 // configurations, and silence files for a single organization.
 // In addition, it will delete all folders and permissions originally created by this migration.
@@ -739,6 +752,7 @@ func (ms *migrationService) fromContactPairs(ctx context.Context, orgID int64, m
 					}
 				}
 			}
+			pair.MergedIntoChannelID = p.MergedIntoID
 			pair.Error = p.Error
 		}
 
@@ -0,0 +1,204 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	migmodels "github.com/grafana/grafana/pkg/services/ngalert/migration/models"
+	migrationStore "github.com/grafana/grafana/pkg/services/ngalert/migration/store"
+)
+
+// ErrInvalidStagedTransition is returned when a staged upgrade operation is not allowed in the current status of the org.
+var ErrInvalidStagedTransition = errors.New("invalid staged upgrade transition")
+
+// ErrStagedUpgradeNotFound is returned when an org was never enqueued for a staged upgrade.
+var ErrStagedUpgradeNotFound = errors.New("org is not enqueued for a staged upgrade")
+
+// stagedTransitions contains, for each status, the statuses an org can move to.
+var stagedTransitions = map[migrationStore.StagedUpgradeStatus][]migrationStore.StagedUpgradeStatus{
+	migrationStore.StagedUpgradeQueued:          {migrationStore.StagedUpgradePendingApproval},
+	migrationStore.StagedUpgradePendingApproval: {migrationStore.StagedUpgradePendingApproval, migrationStore.StagedUpgradeApproved},
+	migrationStore.StagedUpgradeApproved:        {migrationStore.StagedUpgradePendingApproval, migrationStore.StagedUpgradeCompleted, migrationStore.StagedUpgradeFailed},
+	migrationStore.StagedUpgradeFailed:          {migrationStore.StagedUpgradeQueued},
+	migrationStore.StagedUpgradeCompleted:       {migrationStore.StagedUpgradeQueued},
+}
+
+// canTransition returns true if an org in status from can move to status to.
+func canTransition(from, to migrationStore.StagedUpgradeStatus) bool {
+	for _, s := range stagedTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// EnqueueOrg adds the org to the staged upgrade workflow. Orgs that already completed or failed an upgrade can be
+// enqueued again, orgs that are in progress cannot.
+func (ms *migrationService) EnqueueOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	staged, err := ms.migrationStore.GetStagedUpgrade(ctx, orgID)
+	if err != nil && !errors.Is(err, migrationStore.ErrNotFound) {
+		return nil, fmt.Errorf("get staged upgrade: %w", err)
+	}
+	if staged != nil && !canTransition(staged.Status, migrationStore.StagedUpgradeQueued) {
+		return nil, fmt.Errorf("%w: org %d is already %s", ErrInvalidStagedTransition, orgID, staged.Status)
+	}
+
+	staged = &migrationStore.StagedUpgrade{
+		OrgID:   orgID,
+		Status:  migrationStore.StagedUpgradeQueued,
+		Updated: time.Now(),
+	}
+	if err := ms.migrationStore.SetStagedUpgrade(ctx, orgID, staged); err != nil {
+		return nil, fmt.Errorf("save staged upgrade: %w", err)
+	}
+	ms.log.FromContext(ctx).Info("Org enqueued for staged upgrade", "orgId", orgID)
+	return staged, nil
+}
+
+// GetStagedUpgrade returns the staged upgrade of the org.
+func (ms *migrationService) GetStagedUpgrade(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	staged, err := ms.migrationStore.GetStagedUpgrade(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, migrationStore.ErrNotFound) {
+			return nil, ErrStagedUpgradeNotFound
+		}
+		return nil, err
+	}
+	return staged, nil
+}
+
+// GetStagedUpgrades returns the staged upgrades of all enqueued orgs.
+func (ms *migrationService) GetStagedUpgrades(ctx context.Context) ([]*migrationStore.StagedUpgrade, error) {
+	return ms.migrationStore.GetStagedUpgrades(ctx)
+}
+
+// DryRunStagedOrg upgrades the org in a transaction that is always rolled back and records the resulting report for
+// review. Any previous approval is discarded, since it was given for a different report.
+func (ms *migrationService) DryRunStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	staged, err := ms.transitionStaged(ctx, orgID, migrationStore.StagedUpgradePendingApproval)
+	if err != nil {
+		return nil, err
+	}
+
+	var summary definitions.OrgMigrationSummary
+	var fatal error
+	_, err = ms.try(ctx, func(ctx context.Context) (*definitions.OrgMigrationSummary, error) {
+		om := ms.newOrgMigration(orgID)
+		dashboardUpgrades, contactPairs, err := om.migrateOrg(ctx)
+		if err != nil {
+			return nil, err
+		}
+		fatal = fatalMigrationErrors(migmodels.ExtractErrors(dashboardUpgrades, contactPairs))
+
+		summary, err = ms.newSync(orgID).syncAndSaveState(ctx, dashboardUpgrades, contactPairs, false)
+		if err != nil {
+			return nil, err
+		}
+
+		// Ensure we rollback the changes made during the dry-run.
+		return nil, ErrSuccessRollback
+	})
+	if err != nil && !errors.Is(err, ErrSuccessRollback) {
+		return nil, err
+	}
+
+	staged.DryRunSummary = &summary
+	staged.DryRunErrors = nil
+	if fatal != nil {
+		staged.DryRunErrors = unwrapJoined(fatal)
+	}
+	staged.Error = ""
+	if err := ms.migrationStore.SetStagedUpgrade(ctx, orgID, staged); err != nil {
+		return nil, fmt.Errorf("save staged upgrade: %w", err)
+	}
+	return staged, nil
+}
+
+// ApproveStagedOrg approves the last dry-run report of the org. Reports that contain errors cannot be approved.
+func (ms *migrationService) ApproveStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	staged, err := ms.GetStagedUpgrade(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if staged.Status == migrationStore.StagedUpgradePendingApproval && len(staged.DryRunErrors) > 0 {
+		return nil, fmt.Errorf("%w: dry-run of org %d has %d errors", ErrInvalidStagedTransition, orgID, len(staged.DryRunErrors))
+	}
+
+	staged, err = ms.transitionStaged(ctx, orgID, migrationStore.StagedUpgradeApproved)
+	if err != nil {
+		return nil, err
+	}
+	if err := ms.migrationStore.SetStagedUpgrade(ctx, orgID, staged); err != nil {
+		return nil, fmt.Errorf("save staged upgrade: %w", err)
+	}
+	ms.log.FromContext(ctx).Info("Staged upgrade approved", "orgId", orgID)
+	return staged, nil
+}
+
+// ExecuteStagedOrg upgrades an approved org. The outcome is recorded in the staged upgrade, a failed upgrade must be
+// enqueued again before it can be retried.
+func (ms *migrationService) ExecuteStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	staged, err := ms.GetStagedUpgrade(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if staged.Status != migrationStore.StagedUpgradeApproved {
+		return nil, fmt.Errorf("%w: org %d is %s, it must be approved before execution", ErrInvalidStagedTransition, orgID, staged.Status)
+	}
+
+	l := ms.log.FromContext(ctx)
+	_, errMigrate := ms.MigrateOrg(ctx, orgID, false)
+	if errors.Is(errMigrate, ErrUpgradeInProgress) {
+		// Nothing was executed, the org stays approved.
+		return nil, errMigrate
+	}
+
+	staged.Status = migrationStore.StagedUpgradeCompleted
+	staged.Error = ""
+	if errMigrate != nil {
+		l.Warn("Staged upgrade failed", "orgId", orgID, "error", errMigrate)
+		staged.Status = migrationStore.StagedUpgradeFailed
+		staged.Error = errMigrate.Error()
+	} else {
+		l.Info("Staged upgrade completed", "orgId", orgID)
+	}
+	staged.Updated = time.Now()
+	if err := ms.migrationStore.SetStagedUpgrade(ctx, orgID, staged); err != nil {
+		return nil, errors.Join(errMigrate, fmt.Errorf("save staged upgrade: %w", err))
+	}
+	if errMigrate != nil {
+		return nil, errMigrate
+	}
+	return staged, nil
+}
+
+// transitionStaged loads the staged upgrade of the org and moves it to the given status if the transition is allowed.
+// The result is not persisted.
+func (ms *migrationService) transitionStaged(ctx context.Context, orgID int64, to migrationStore.StagedUpgradeStatus) (*migrationStore.StagedUpgrade, error) {
+	staged, err := ms.GetStagedUpgrade(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransition(staged.Status, to) {
+		return nil, fmt.Errorf("%w: org %d cannot move from %s to %s", ErrInvalidStagedTransition, orgID, staged.Status, to)
+	}
+	staged.Status = to
+	staged.Updated = time.Now()
+	return staged, nil
+}
+
+// unwrapJoined returns the messages of each error joined in err.
+func unwrapJoined(err error) []string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		res := make([]string, 0)
+		for _, e := range joined.Unwrap() {
+			res = append(res, e.Error())
+		}
+		return res
+	}
+	return []string{err.Error()}
+}
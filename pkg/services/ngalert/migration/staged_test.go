@@ -0,0 +1,106 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	legacymodels "github.com/grafana/grafana/pkg/services/alerting/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	migrationStore "github.com/grafana/grafana/pkg/services/ngalert/migration/store"
+)
+
+func TestStagedUpgrade(t *testing.T) {
+	alerts := []*legacymodels.Alert{
+		createAlert(t, 1, 1, 1, "alert1", []string{"notifier1"}),
+	}
+	channels := []*legacymodels.AlertNotification{
+		createAlertNotification(t, int64(1), "notifier1", "email", emailSettings, false),
+	}
+	dashes := []*dashboards.Dashboard{
+		createDashboard(t, 1, 1, "dash1-1", "folder5-1", 5, nil),
+	}
+	folders := []*dashboards.Dashboard{
+		createFolder(t, 5, 1, "folder5-1"),
+	}
+
+	sqlStore := db.InitTestDB(t)
+	x := sqlStore.GetEngine()
+	setupLegacyAlertsTables(t, x, channels, alerts, folders, dashes)
+
+	ctx := context.Background()
+	service := NewTestMigrationService(t, sqlStore, nil)
+
+	countRules := func(t *testing.T) int64 {
+		t.Helper()
+		count, err := x.Table("alert_rule").Where("org_id=?", 1).Count()
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("operations on an org that is not enqueued should fail", func(t *testing.T) {
+		_, err := service.DryRunStagedOrg(ctx, 1)
+		require.ErrorIs(t, err, ErrStagedUpgradeNotFound)
+		_, err = service.ExecuteStagedOrg(ctx, 1)
+		require.ErrorIs(t, err, ErrStagedUpgradeNotFound)
+	})
+
+	staged, err := service.EnqueueOrg(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, migrationStore.StagedUpgradeQueued, staged.Status)
+
+	t.Run("org cannot be approved or executed before a dry-run", func(t *testing.T) {
+		_, err := service.ApproveStagedOrg(ctx, 1)
+		require.ErrorIs(t, err, ErrInvalidStagedTransition)
+		_, err = service.ExecuteStagedOrg(ctx, 1)
+		require.ErrorIs(t, err, ErrInvalidStagedTransition)
+	})
+
+	t.Run("dry-run records a report without persisting changes", func(t *testing.T) {
+		staged, err := service.DryRunStagedOrg(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, migrationStore.StagedUpgradePendingApproval, staged.Status)
+		require.NotNil(t, staged.DryRunSummary)
+		require.Equal(t, 1, staged.DryRunSummary.NewAlerts)
+		require.Equal(t, 1, staged.DryRunSummary.NewChannels)
+		require.Empty(t, staged.DryRunErrors)
+
+		require.Equal(t, int64(0), countRules(t))
+		checkMigrationStatus(t, ctx, service, 1, false)
+
+		stored, err := service.GetStagedUpgrade(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, staged.Status, stored.Status)
+		require.Equal(t, staged.DryRunSummary, stored.DryRunSummary)
+	})
+
+	t.Run("approved org is upgraded on execution", func(t *testing.T) {
+		staged, err := service.ApproveStagedOrg(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, migrationStore.StagedUpgradeApproved, staged.Status)
+
+		staged, err = service.ExecuteStagedOrg(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, migrationStore.StagedUpgradeCompleted, staged.Status)
+
+		require.Equal(t, int64(1), countRules(t))
+		checkMigrationStatus(t, ctx, service, 1, true)
+	})
+
+	t.Run("completed org can be enqueued again", func(t *testing.T) {
+		_, err := service.ApproveStagedOrg(ctx, 1)
+		require.ErrorIs(t, err, ErrInvalidStagedTransition)
+
+		staged, err := service.EnqueueOrg(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, migrationStore.StagedUpgradeQueued, staged.Status)
+		require.Nil(t, staged.DryRunSummary)
+
+		all, err := service.GetStagedUpgrades(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, int64(1), all[0].OrgID)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -66,7 +67,10 @@ type ReadStore interface {
 	IsMigrated(ctx context.Context, orgID int64) (bool, error)
 	GetCurrentAlertingType(ctx context.Context) (AlertingType, error)
 	GetOrgMigrationState(ctx context.Context, orgID int64) (*OrgMigrationState, error)
+	GetStagedUpgrade(ctx context.Context, orgID int64) (*StagedUpgrade, error)
+	GetStagedUpgrades(ctx context.Context) ([]*StagedUpgrade, error)
 
+	GetAlertRules(ctx context.Context, orgID int64, ruleUIDs ...string) ([]*models.AlertRule, error)
 	GetAlertRuleTitles(ctx context.Context, orgID int64, namespaceUIDs ...string) (map[string][]string, error)                 // NamespaceUID -> Titles
 	GetRuleLabels(ctx context.Context, orgID int64, ruleUIDs []string) (map[models.AlertRuleKeyWithVersion]data.Labels, error) // Rule UID -> Labels
 
@@ -92,6 +96,7 @@ type WriteStore interface {
 	SetMigrated(ctx context.Context, orgID int64, migrated bool) error
 	SetCurrentAlertingType(ctx context.Context, t AlertingType) error
 	SetOrgMigrationState(ctx context.Context, orgID int64, summary *OrgMigrationState) error
+	SetStagedUpgrade(ctx context.Context, orgID int64, staged *StagedUpgrade) error
 
 	RevertOrg(ctx context.Context, orgID int64) error
 	RevertAllOrgs(ctx context.Context) error
@@ -161,6 +166,9 @@ const migratedKey = "migrated"
 // stateKey is the kvstore key used for the OrgMigrationState.
 const stateKey = "stateKey"
 
+// stagedKey is the kvstore key used for the StagedUpgrade.
+const stagedKey = "stagedUpgrade"
+
 // typeKey is the kvstore key used for the current AlertingType.
 const typeKey = "currentAlertingType"
 
@@ -279,6 +287,62 @@ func (ms *migrationStore) SetOrgMigrationState(ctx context.Context, orgID int64,
 	return kv.Set(ctx, stateKey, string(raw))
 }
 
+// GetStagedUpgrade returns the staged upgrade of the given org. Returns ErrNotFound if the org was never enqueued.
+func (ms *migrationStore) GetStagedUpgrade(ctx context.Context, orgID int64) (*StagedUpgrade, error) {
+	kv := kvstore.WithNamespace(ms.kv, orgID, KVNamespace)
+	content, exists, err := kv.Get(ctx, stagedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	var staged StagedUpgrade
+	if err := json.Unmarshal([]byte(content), &staged); err != nil {
+		return nil, err
+	}
+	return &staged, nil
+}
+
+// GetStagedUpgrades returns the staged upgrades of all orgs, sorted by org ID.
+func (ms *migrationStore) GetStagedUpgrades(ctx context.Context) ([]*StagedUpgrade, error) {
+	all, err := ms.kv.GetAll(ctx, kvstore.AllOrganizations, KVNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*StagedUpgrade, 0, len(all))
+	for orgID, values := range all {
+		content, ok := values[stagedKey]
+		if !ok {
+			continue
+		}
+		var staged StagedUpgrade
+		if err := json.Unmarshal([]byte(content), &staged); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal staged upgrade for org %d: %w", orgID, err)
+		}
+		res = append(res, &staged)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].OrgID < res[j].OrgID
+	})
+	return res, nil
+}
+
+// SetStagedUpgrade stores the staged upgrade of the given org.
+func (ms *migrationStore) SetStagedUpgrade(ctx context.Context, orgID int64, staged *StagedUpgrade) error {
+	kv := kvstore.WithNamespace(ms.kv, orgID, KVNamespace)
+
+	raw, err := json.Marshal(staged)
+	if err != nil {
+		return err
+	}
+
+	return kv.Set(ctx, stagedKey, string(raw))
+}
+
 // SetSilences stores the given silences in the kvstore.
 func (ms *migrationStore) SetSilences(ctx context.Context, orgID int64, silences []*pb.MeshSilence) error {
 	kv := kvstore.WithNamespace(ms.kv, orgID, notifier.KVNamespace)
@@ -293,6 +357,18 @@ func (ms *migrationStore) SetSilences(ctx context.Context, orgID int64, silences
 	return kv.Set(ctx, notifier.SilencesFilename, base64.StdEncoding.EncodeToString(buf.Bytes()))
 }
 
+// GetAlertRules returns the alert rules with the given uids in the given org.
+func (ms *migrationStore) GetAlertRules(ctx context.Context, orgID int64, ruleUIDs ...string) ([]*models.AlertRule, error) {
+	rules := make([]*models.AlertRule, 0, len(ruleUIDs))
+	if len(ruleUIDs) == 0 {
+		return rules, nil
+	}
+	err := ms.store.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_rule").Where("org_id = ?", orgID).In("uid", ruleUIDs).Find(&rules)
+	})
+	return rules, err
+}
+
 // GetAlertRuleTitles returns a map of namespaceUID -> title for all alert rules in the given org and namespace uids.
 func (ms *migrationStore) GetAlertRuleTitles(ctx context.Context, orgID int64, namespaceUIDs ...string) (map[string][]string, error) {
 	res := make(map[string][]string)
@@ -359,7 +435,7 @@ func (ms *migrationStore) UpdateRuleLabels(ctx context.Context, key models.Alert
 		if affected == 0 {
 			return fmt.Errorf("rule with uid %v not found", key)
 		}
-		return nil
+		return store.WriteRuleLabels(sess, key.OrgID, key.UID, labels)
 	})
 }
 
@@ -448,6 +524,10 @@ func (ms *migrationStore) RevertOrg(ctx context.Context, orgID int64) error {
 				return err
 			}
 
+			if _, err := sess.Exec("DELETE FROM alert_rule_label WHERE org_id = ?", orgID); err != nil {
+				return err
+			}
+
 			if _, err := sess.Exec("DELETE FROM alert_rule_version WHERE rule_org_id = ?", orgID); err != nil {
 				return err
 			}
@@ -506,6 +586,10 @@ func (ms *migrationStore) RevertAllOrgs(ctx context.Context) error {
 				return err
 			}
 
+			if _, err := sess.Exec("DELETE FROM alert_rule_label"); err != nil {
+				return err
+			}
+
 			if _, err := sess.Exec("DELETE FROM alert_rule_version"); err != nil {
 				return err
 			}
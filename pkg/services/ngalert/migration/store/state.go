@@ -1,5 +1,11 @@
 package store
 
+import (
+	"time"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
 // OrgMigrationState contains information about the state of an org migration.
 type OrgMigrationState struct {
 	OrgID              int64                       `json:"orgId"`
@@ -26,5 +32,36 @@ type AlertPair struct {
 type ContactPair struct {
 	LegacyID       int64  `json:"legacyId"`
 	NewReceiverUID string `json:"newReceiverUid"`
-	Error          string `json:"error,omitempty"`
+	// MergedIntoID is the ID of the identical legacy channel that owns the contact point, if this channel was merged.
+	MergedIntoID int64  `json:"mergedIntoId,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// StagedUpgradeStatus is the status of an org in the staged upgrade workflow.
+type StagedUpgradeStatus string
+
+const (
+	// StagedUpgradeQueued means the org was enqueued but no dry-run report exists yet.
+	StagedUpgradeQueued StagedUpgradeStatus = "queued"
+	// StagedUpgradePendingApproval means a dry-run report exists and is waiting for review.
+	StagedUpgradePendingApproval StagedUpgradeStatus = "pending_approval"
+	// StagedUpgradeApproved means the dry-run report was approved and the org can be upgraded.
+	StagedUpgradeApproved StagedUpgradeStatus = "approved"
+	// StagedUpgradeCompleted means the org was upgraded.
+	StagedUpgradeCompleted StagedUpgradeStatus = "completed"
+	// StagedUpgradeFailed means the upgrade of the org was executed but failed.
+	StagedUpgradeFailed StagedUpgradeStatus = "failed"
+)
+
+// StagedUpgrade contains the state of an org in the staged upgrade workflow.
+type StagedUpgrade struct {
+	OrgID  int64               `json:"orgId"`
+	Status StagedUpgradeStatus `json:"status"`
+	// DryRunSummary is the summary of the last dry-run, if any.
+	DryRunSummary *apimodels.OrgMigrationSummary `json:"dryRunSummary,omitempty"`
+	// DryRunErrors contains the errors that would fail the upgrade, as found by the last dry-run.
+	DryRunErrors []string `json:"dryRunErrors,omitempty"`
+	// Error is the error of the last execution, if it failed.
+	Error   string    `json:"error,omitempty"`
+	Updated time.Time `json:"updated"`
 }
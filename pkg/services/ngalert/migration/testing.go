@@ -86,3 +86,38 @@ func (ms *fakeMigrationService) RevertOrg(ctx context.Context, orgID int64) erro
 	//TODO implement me
 	panic("implement me")
 }
+
+func (ms *fakeMigrationService) GetOrgMigrationExport(ctx context.Context, orgID int64, decrypt bool) (*OrgMigrationExport, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (ms *fakeMigrationService) EnqueueOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (ms *fakeMigrationService) GetStagedUpgrade(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (ms *fakeMigrationService) GetStagedUpgrades(ctx context.Context) ([]*migrationStore.StagedUpgrade, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (ms *fakeMigrationService) DryRunStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (ms *fakeMigrationService) ApproveStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	//TODO implement me
+	panic("implement me")
+}
+
+func (ms *fakeMigrationService) ExecuteStagedOrg(ctx context.Context, orgID int64) (*migrationStore.StagedUpgrade, error) {
+	//TODO implement me
+	panic("implement me")
+}
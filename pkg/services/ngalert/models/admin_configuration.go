@@ -26,6 +26,10 @@ type AdminConfiguration struct {
 	// SendAlertsTo indicates which set of alertmanagers will handle the alert.
 	SendAlertsTo AlertmanagersChoice `xorm:"send_alerts_to"`
 
+	// ExternalAlertmanagerMatchers contains the JSON encoded matchers of the alerts that are sent only to the external
+	// Alertmanagers when alerts are sent to all Alertmanagers. The other alerts are sent only to the internal one.
+	ExternalAlertmanagerMatchers string `xorm:"external_alertmanager_matchers"`
+
 	CreatedAt int64 `xorm:"created"`
 	UpdatedAt int64 `xorm:"updated"`
 }
@@ -99,6 +99,19 @@ type AlertQuery struct {
 	modelProps map[string]any
 }
 
+// legacyExpressionDatasourceName is the name of the expression data source. Files exported by older versions of
+// Grafana and Terraform states sometimes use it instead of its UID.
+const legacyExpressionDatasourceName = "Expression"
+
+// NormalizeDatasourceUID returns expr.DatasourceUID if uid is one of the historical identifiers of the expression data
+// source, such as expr.OldDatasourceUID, and uid unchanged otherwise.
+func NormalizeDatasourceUID(uid string) string {
+	if expr.IsDataSource(uid) || uid == legacyExpressionDatasourceName {
+		return expr.DatasourceUID
+	}
+	return uid
+}
+
 func (aq *AlertQuery) String() string {
 	return fmt.Sprintf("refID: %s, queryType: %s, datasourceUID: %s", aq.RefID, aq.QueryType, aq.DatasourceUID)
 }
@@ -286,6 +299,8 @@ func (aq *AlertQuery) setQueryType() error {
 // PreSave sets query's properties.
 // It should be called before being saved.
 func (aq *AlertQuery) PreSave() error {
+	aq.DatasourceUID = NormalizeDatasourceUID(aq.DatasourceUID)
+
 	if err := aq.setQueryType(); err != nil {
 		return fmt.Errorf("failed to set query type to query model: %w", err)
 	}
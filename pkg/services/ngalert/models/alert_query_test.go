@@ -360,3 +360,20 @@ func TestAlertQuery_GetQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeDatasourceUID(t *testing.T) {
+	for _, uid := range []string{expr.DatasourceUID, expr.OldDatasourceUID, "Expression"} {
+		t.Run(uid, func(t *testing.T) {
+			require.Equal(t, expr.DatasourceUID, NormalizeDatasourceUID(uid))
+		})
+	}
+	t.Run("should not change other data sources", func(t *testing.T) {
+		require.Equal(t, "prometheus", NormalizeDatasourceUID("prometheus"))
+		require.Equal(t, "expression", NormalizeDatasourceUID("expression"))
+	})
+	t.Run("PreSave should persist the canonical form", func(t *testing.T) {
+		q := AlertQuery{RefID: "B", DatasourceUID: expr.OldDatasourceUID, Model: json.RawMessage(`{"type": "math", "expression": "$A > 1"}`)}
+		require.NoError(t, q.PreSave())
+		require.Equal(t, expr.DatasourceUID, q.DatasourceUID)
+	})
+}
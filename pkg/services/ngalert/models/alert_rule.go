@@ -2,9 +2,13 @@ package models
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,6 +17,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/setting"
@@ -22,6 +27,8 @@ import (
 var (
 	// ErrAlertRuleNotFound is an error for an unknown alert rule.
 	ErrAlertRuleNotFound = fmt.Errorf("could not find alert rule")
+	// ErrAlertRuleVersionNotFound is an error for an unknown version of an alert rule.
+	ErrAlertRuleVersionNotFound = errors.New("could not find alert rule version")
 	// ErrAlertRuleFailedGenerateUniqueUID is an error for failure to generate alert rule UID
 	ErrAlertRuleFailedGenerateUniqueUID = errors.New("failed to generate alert rule UID")
 	// ErrCannotEditNamespace is an error returned if the user does not have permissions to edit the namespace
@@ -169,36 +176,102 @@ var (
 		AutogeneratedRouteReceiverNameLabel: {},
 		AutogeneratedRouteSettingsHashLabel: {},
 	}
+
+	// shardAffinityRegexp matches the names of scheduler shards.
+	shardAffinityRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
 )
 
+// shardAffinityMaxLength is the size of the shard_affinity column.
+const shardAffinityMaxLength = 40
+
+// RuleLabelNameMaxLength is the size of the label_name column of the alert_rule_label table, which indexes the labels
+// of alert rules. Labels with longer names are not indexed.
+const RuleLabelNameMaxLength = 190
+
 // AlertRuleGroup is the base model for a rule group in unified alerting.
 type AlertRuleGroup struct {
 	Title      string
 	FolderUID  string
 	Interval   int64
 	Provenance Provenance
-	Rules      []AlertRule
+	// DataAvailabilityPeriod and DataAvailabilityDelay configure the data availability window of the group. See
+	// AlertRule.IsDataAvailable.
+	DataAvailabilityPeriod time.Duration
+	DataAvailabilityDelay  time.Duration
+	// ShardAffinity is the scheduler shard the group is pinned to, empty if it is not pinned. See AlertRule.ShardAffinity.
+	ShardAffinity string
+	// IncidentHooks are called when the alerts of the rules of the group start firing and when they are resolved.
+	IncidentHooks []IncidentHook
+	// ManagedBy is stored per group rather than with the rules. Replacing the group sets it, or removes it if it is nil.
+	ManagedBy *ManagedBy
+	// Archived is set when the group is read, and ignored when it is replaced. See AlertRuleService.ArchiveRuleGroup.
+	Archived bool
+	// BakePeriod is not stored. It is the time during which the notifications of the rules created by an apply of the
+	// group are suppressed. See AlertRule.BakeUntil.
+	BakePeriod time.Duration
+	// ExpiresAt is not stored. It is set on the rules of the group that do not have their own expiration. See
+	// AlertRule.ExpiresAt.
+	ExpiresAt *time.Time
+	// RuleMoves is not stored. It tells what to do with the given rules that belong to another group when the group is
+	// replaced. Empty is the same as RuleMovesMove.
+	RuleMoves RuleMovePolicy
+	// CheckVersions is not stored. If true, the given rules that have a version and are changed by the replacement
+	// must still have that version, otherwise the replacement fails. It lets a caller that read the group detect that
+	// it was changed concurrently instead of overwriting the change.
+	CheckVersions bool
+	// BaseVersion is not stored. If it is set, it is the version of the group the given rules are based on, and the
+	// replacement is merged with the changes made to the group since that version instead of overwriting them.
+	BaseVersion RuleGroupVersion
+	// ExpectedVersion is not stored. If it is set and CheckVersions is true, the replacement fails unless the stored
+	// group still has this version, so that the rules added to or deleted from the group concurrently are not
+	// overwritten. It is ignored if BaseVersion is set, since the replacement is then merged.
+	ExpectedVersion RuleGroupVersion
+	Rules           []AlertRule
 }
 
+// RuleMovePolicy tells what to do when a rule group is replaced with a rule that belongs to another group.
+type RuleMovePolicy string
+
+const (
+	// RuleMovesMove moves the rule from its group to the replaced group.
+	RuleMovesMove RuleMovePolicy = "move"
+	// RuleMovesReject rejects the replacement with an ErrAlertRuleMoveConflict.
+	RuleMovesReject RuleMovePolicy = "reject"
+)
+
 // AlertRuleGroupWithFolderTitle extends AlertRuleGroup with orgID and folder title
 type AlertRuleGroupWithFolderTitle struct {
 	*AlertRuleGroup
 	OrgID       int64
 	FolderTitle string
+	// FolderPath is the titles of the nested folders from the root folder down to the folder of the group, which is
+	// the last one. It is set only if the group refers to its folder by path.
+	FolderPath []string
 }
 
 func NewAlertRuleGroupWithFolderTitle(groupKey AlertRuleGroupKey, rules []AlertRule, folderTitle string) AlertRuleGroupWithFolderTitle {
 	SortAlertRulesByGroupIndex(rules)
 	var interval int64
+	var period, delay time.Duration
+	var shardAffinity string
+	var incidentHooks []IncidentHook
 	if len(rules) > 0 {
 		interval = rules[0].IntervalSeconds
+		period = rules[0].DataAvailabilityPeriod
+		delay = rules[0].DataAvailabilityDelay
+		shardAffinity = rules[0].ShardAffinity
+		incidentHooks = rules[0].IncidentHooks
 	}
 	var result = AlertRuleGroupWithFolderTitle{
 		AlertRuleGroup: &AlertRuleGroup{
-			Title:     groupKey.RuleGroup,
-			FolderUID: groupKey.NamespaceUID,
-			Interval:  interval,
-			Rules:     rules,
+			Title:                  groupKey.RuleGroup,
+			FolderUID:              groupKey.NamespaceUID,
+			Interval:               interval,
+			DataAvailabilityPeriod: period,
+			DataAvailabilityDelay:  delay,
+			ShardAffinity:          shardAffinity,
+			IncidentHooks:          incidentHooks,
+			Rules:                  rules,
 		},
 		FolderTitle: folderTitle,
 		OrgID:       groupKey.OrgID,
@@ -249,6 +322,56 @@ type AlertRule struct {
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings `xorm:"notification_settings"` // we use slice to workaround xorm mapping that does not serialize a struct to JSON unless it's a slice
+	// DataAvailabilityPeriod and DataAvailabilityDelay are set on all rules of the group. See IsDataAvailable.
+	DataAvailabilityPeriod time.Duration
+	DataAvailabilityDelay  time.Duration
+	// ShardAffinity is set on all rules of the group. Only the schedulers of this shard evaluate the rule, or only the
+	// schedulers that are not sharded if it is empty.
+	ShardAffinity string
+	// IncidentHooks are set on all rules of the group. See IncidentHook.
+	IncidentHooks []IncidentHook `xorm:"incident_hooks"`
+	// BakeUntil is the end of the bake period of a new rule, nil if it has none. The rule is evaluated and its state
+	// recorded, but the alerts that start firing before the end of the bake period are never sent. See
+	// SuppressesNotificationsOf.
+	BakeUntil *time.Time `xorm:"bake_until"`
+	// ExpiresAt is the time at which the rule expires, nil if it never expires. Expired rules are paused or deleted by
+	// a background job. See IsExpired.
+	ExpiresAt *time.Time `xorm:"expires_at"`
+	// IntervalOverrideSeconds is the interval at which the rule is evaluated instead of the interval of its group, zero
+	// if it has none. See EffectiveIntervalSeconds.
+	IntervalOverrideSeconds int64 `xorm:"interval_override_seconds"`
+	// EvaluationTimeout is the timeout of the evaluation of the queries and expressions of the rule instead of the
+	// evaluation timeout of the instance, zero if it has none.
+	EvaluationTimeout time.Duration `xorm:"evaluation_timeout"`
+	// Record is set if the rule is a recording rule, zero if it is an alert rule. It is not a pointer because xorm
+	// writes a nil pointer as an empty value rather than NULL, and reads it back as a non-nil pointer. See
+	// IsRecordingRule.
+	Record Record `xorm:"record"`
+}
+
+// IsRecordingRule returns true if the rule writes series instead of firing alerts.
+func (alertRule *AlertRule) IsRecordingRule() bool {
+	return alertRule.Record != (Record{})
+}
+
+// EffectiveIntervalSeconds returns the interval at which the rule is evaluated, which is its own interval if it
+// overrides the one of its group.
+func (alertRule *AlertRule) EffectiveIntervalSeconds() int64 {
+	if alertRule.IntervalOverrideSeconds > 0 {
+		return alertRule.IntervalOverrideSeconds
+	}
+	return alertRule.IntervalSeconds
+}
+
+// IsExpired returns true if the rule has an expiration that is not after now.
+func (alertRule *AlertRule) IsExpired(now time.Time) bool {
+	return alertRule.ExpiresAt != nil && !alertRule.ExpiresAt.After(now)
+}
+
+// SuppressesNotificationsOf returns true if the notifications of an alert that started firing at startsAt are
+// suppressed because it was firing during the bake period of the rule.
+func (alertRule *AlertRule) SuppressesNotificationsOf(startsAt time.Time) bool {
+	return alertRule.BakeUntil != nil && startsAt.Before(*alertRule.BakeUntil)
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -260,6 +383,36 @@ type AlertRuleWithOptionals struct {
 	// This parameter is to know if an optional API field was sent and, therefore, patch it with the current field from
 	// DB in case it was not sent.
 	HasPause bool
+	// HasDataAvailability tells whether the data availability window was sent. If not, it is patched from the DB.
+	HasDataAvailability bool
+	// HasShardAffinity tells whether the shard affinity was sent. If not, it is patched from the DB.
+	HasShardAffinity bool
+	// HasIncidentHooks tells whether the incident hooks were sent. If not, they are patched from the DB.
+	HasIncidentHooks bool
+	// HasExpiresAt tells whether the expiration was sent. If not, it is patched from the DB.
+	HasExpiresAt bool
+	// HasIntervalOverride tells whether the interval override was sent. If not, it is patched from the DB.
+	HasIntervalOverride bool
+	// HasEvaluationTimeout tells whether the evaluation timeout was sent. If not, it is patched from the DB.
+	HasEvaluationTimeout bool
+	// HasRecord tells whether the record of a recording rule was sent. If not, it is patched from the DB.
+	HasRecord bool
+}
+
+// NewAlertRuleWithAllOptionals returns the rule with all its optional fields marked as sent, so that none of them is
+// patched from the DB. It is used for rules that are complete, e.g. the rules of a group that is replaced.
+func NewAlertRuleWithAllOptionals(rule AlertRule) *AlertRuleWithOptionals {
+	return &AlertRuleWithOptionals{
+		AlertRule:            rule,
+		HasPause:             true,
+		HasDataAvailability:  true,
+		HasShardAffinity:     true,
+		HasIncidentHooks:     true,
+		HasExpiresAt:         true,
+		HasIntervalOverride:  true,
+		HasEvaluationTimeout: true,
+		HasRecord:            true,
+	}
 }
 
 // AlertsRulesBy is a function that defines the ordering of alert rules.
@@ -333,6 +486,12 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 }
 
 func (alertRule *AlertRule) GetEvalCondition() Condition {
+	if alertRule.IsRecordingRule() {
+		return Condition{
+			Condition: alertRule.Record.From,
+			Data:      alertRule.Data,
+		}
+	}
 	return Condition{
 		Condition: alertRule.Condition,
 		Data:      alertRule.Data,
@@ -418,6 +577,14 @@ func (k AlertRuleGroupKey) String() string {
 	return fmt.Sprintf("{orgID: %d, namespaceUID: %s, groupName: %s}", k.OrgID, k.NamespaceUID, k.RuleGroup)
 }
 
+func (k AlertRuleGroupKey) ResourceType() string {
+	return "alertRuleGroup"
+}
+
+func (k AlertRuleGroupKey) ResourceID() string {
+	return k.NamespaceUID + "/" + k.RuleGroup
+}
+
 func (k AlertRuleKey) String() string {
 	return fmt.Sprintf("{orgID: %d, UID: %s}", k.OrgID, k.UID)
 }
@@ -455,6 +622,27 @@ func (alertRule *AlertRule) GetGroupKey() AlertRuleGroupKey {
 	return AlertRuleGroupKey{OrgID: alertRule.OrgID, NamespaceUID: alertRule.NamespaceUID, RuleGroup: alertRule.RuleGroup}
 }
 
+// DeterministicAlertRuleUID returns the UID of the rule derived from its organization, folder, group and title. Rules
+// created with the same values get the same UID on every instance, so that the links to them remain valid when they
+// are re-created.
+func DeterministicAlertRuleUID(orgID int64, namespaceUID, ruleGroup, title string) string {
+	// The values are separated by a NUL byte, so that moving characters from one value to the next changes the UID.
+	h := sha256.Sum256([]byte(strconv.FormatInt(orgID, 10) + "\x00" + namespaceUID + "\x00" + ruleGroup + "\x00" + title))
+	return hex.EncodeToString(h[:12])
+}
+
+// EffectivePendingPeriod returns how long the alerts of the rule are pending before they fire. Alerts fire at the first
+// evaluation that happens at least For after the evaluation that made them pending, so For is rounded up to a whole
+// number of evaluation intervals. For example, a rule with For 2m that is evaluated every 5m fires after 5m.
+func (alertRule *AlertRule) EffectivePendingPeriod() time.Duration {
+	if alertRule.For <= 0 || alertRule.EffectiveIntervalSeconds() <= 0 {
+		return alertRule.For
+	}
+	interval := time.Duration(alertRule.EffectiveIntervalSeconds()) * time.Second
+	evaluations := (alertRule.For + interval - 1) / interval
+	return evaluations * interval
+}
+
 // PreSave sets default values and loads the updated model for each alert query.
 func (alertRule *AlertRule) PreSave(timeNow func() time.Time) error {
 	for i, q := range alertRule.Data {
@@ -482,6 +670,16 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		return err
 	}
 
+	if alertRule.IntervalOverrideSeconds != 0 {
+		if err := ValidateRuleGroupInterval(alertRule.IntervalOverrideSeconds, int64(cfg.BaseInterval.Seconds())); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateRuleEvaluationTimeout(alertRule.EvaluationTimeout, cfg.MaxRuleEvaluationTimeout); err != nil {
+		return err
+	}
+
 	if alertRule.OrgID == 0 {
 		return fmt.Errorf("%w: no organisation is found", ErrAlertRuleFailedValidation)
 	}
@@ -490,16 +688,40 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		return fmt.Errorf("%w: cannot have Panel ID without a Dashboard UID", ErrAlertRuleFailedValidation)
 	}
 
-	if _, err := ErrStateFromString(string(alertRule.ExecErrState)); err != nil {
+	if alertRule.IsRecordingRule() {
+		if err := alertRule.Record.Validate(alertRule.Data); err != nil {
+			return err
+		}
+		if alertRule.For != 0 {
+			return fmt.Errorf("%w: recording rules cannot have a pending period", ErrAlertRuleFailedValidation)
+		}
+		if len(alertRule.NotificationSettings) > 0 {
+			return fmt.Errorf("%w: recording rules cannot have notification settings", ErrAlertRuleFailedValidation)
+		}
+	} else {
+		if _, err := ErrStateFromString(string(alertRule.ExecErrState)); err != nil {
+			return err
+		}
+
+		if _, err := NoDataStateFromString(string(alertRule.NoDataState)); err != nil {
+			return err
+		}
+	}
+
+	if alertRule.For < 0 {
+		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
+	}
+
+	if err := ValidateDataAvailability(alertRule.DataAvailabilityPeriod, alertRule.DataAvailabilityDelay); err != nil {
 		return err
 	}
 
-	if _, err := NoDataStateFromString(string(alertRule.NoDataState)); err != nil {
+	if err := ValidateShardAffinity(alertRule.ShardAffinity, cfg.SchedulerShards); err != nil {
 		return err
 	}
 
-	if alertRule.For < 0 {
-		return fmt.Errorf("%w: field `for` cannot be negative", ErrAlertRuleFailedValidation)
+	if err := ValidateIncidentHooks(alertRule.IncidentHooks); err != nil {
+		return err
 	}
 
 	if len(alertRule.Labels) > 0 {
@@ -510,13 +732,70 @@ func (alertRule *AlertRule) ValidateAlertRule(cfg setting.UnifiedAlertingSetting
 		}
 	}
 
-	if len(alertRule.NotificationSettings) > 0 {
-		if len(alertRule.NotificationSettings) != 1 {
-			return fmt.Errorf("%w: only one notification settings entry is allowed", ErrAlertRuleFailedValidation)
-		}
-		if err := alertRule.NotificationSettings[0].Validate(); err != nil {
-			return errors.Join(ErrAlertRuleFailedValidation, fmt.Errorf("invalid notification settings: %w", err))
-		}
+	if err := ValidateNotificationSettings(alertRule.NotificationSettings); err != nil {
+		return errors.Join(ErrAlertRuleFailedValidation, fmt.Errorf("invalid notification settings: %w", err))
+	}
+	return nil
+}
+
+// IsDataAvailable tells whether the data the rule queries is expected to be complete at the given time. Time is split
+// into windows of DataAvailabilityPeriod aligned on the Unix epoch, and the data of a window is not available during
+// its first DataAvailabilityDelay, e.g. with a period of one hour and a delay of 5 minutes, the data of an hour is not
+// available before 5 minutes past the hour. The data is always available if the rule has no period.
+func (alertRule *AlertRule) IsDataAvailable(t time.Time) bool {
+	if alertRule.DataAvailabilityPeriod <= 0 {
+		return true
+	}
+	return time.Duration(t.UnixNano())%alertRule.DataAvailabilityPeriod >= alertRule.DataAvailabilityDelay
+}
+
+// DataAvailableAt returns the latest time, not after t, up to which the data the rule queries is expected to be
+// complete. It is t if the data is available at t, and otherwise the start of the window of t, so that a rule is
+// evaluated during the delay of a window as of the end of the previous window.
+func (alertRule *AlertRule) DataAvailableAt(t time.Time) time.Time {
+	if alertRule.IsDataAvailable(t) {
+		return t
+	}
+	return t.Add(-(time.Duration(t.UnixNano()) % alertRule.DataAvailabilityPeriod))
+}
+
+// ValidateDataAvailability checks that the delay of a data availability window is positive and shorter than its
+// period, or that neither is set.
+func ValidateDataAvailability(period, delay time.Duration) error {
+	if period == 0 && delay == 0 {
+		return nil
+	}
+	if period <= 0 || delay <= 0 || delay >= period {
+		return fmt.Errorf("%w: data availability delay (%v) should be positive and shorter than the period (%v)",
+			ErrAlertRuleFailedValidation, delay, period)
+	}
+	return nil
+}
+
+// ValidateShardAffinity checks that the shard affinity is a valid shard name and that it is one of the known scheduler
+// shards. An empty affinity is valid and means that the rule is not pinned to a shard. Rules cannot be pinned to any
+// shard if no shards are known, because the rules pinned to a shard that no scheduler serves are never evaluated.
+func ValidateShardAffinity(affinity string, known []string) error {
+	if err := ValidateShardAffinityName(affinity); err != nil {
+		return err
+	}
+	if affinity == "" || slices.Contains(known, affinity) {
+		return nil
+	}
+	if len(known) == 0 {
+		return fmt.Errorf("%w: shard affinity '%s' cannot be set because no scheduler shards are configured", ErrAlertRuleFailedValidation, affinity)
+	}
+	return fmt.Errorf("%w: shard affinity '%s' is not one of the scheduler shards %v", ErrAlertRuleFailedValidation, affinity, known)
+}
+
+// ValidateShardAffinityName checks that the shard affinity is a valid shard name, regardless of the scheduler shards.
+func ValidateShardAffinityName(affinity string) error {
+	if affinity == "" {
+		return nil
+	}
+	if len(affinity) > shardAffinityMaxLength || !shardAffinityRegexp.MatchString(affinity) {
+		return fmt.Errorf("%w: shard affinity '%s' should contain only letters, digits, '-', '_' and '.' and be at most %d characters long",
+			ErrAlertRuleFailedValidation, affinity, shardAffinityMaxLength)
 	}
 	return nil
 }
@@ -566,6 +845,49 @@ type AlertRuleVersion struct {
 	Labels               map[string]string
 	IsPaused             bool
 	NotificationSettings []NotificationSettings `xorm:"notification_settings"` // we use slice to workaround xorm mapping that does not serialize a struct to JSON unless it's a slice
+	// DataAvailabilityPeriod and DataAvailabilityDelay are set on all rules of the group. See IsDataAvailable.
+	DataAvailabilityPeriod time.Duration
+	DataAvailabilityDelay  time.Duration
+	// ShardAffinity is set on all rules of the group. Only the schedulers of this shard evaluate the rule, or only the
+	// schedulers that are not sharded if it is empty.
+	ShardAffinity           string
+	IncidentHooks           []IncidentHook `xorm:"incident_hooks"`
+	ExpiresAt               *time.Time     `xorm:"expires_at"`
+	IntervalOverrideSeconds int64          `xorm:"interval_override_seconds"`
+	EvaluationTimeout       time.Duration  `xorm:"evaluation_timeout"`
+	Record                  Record         `xorm:"record"`
+}
+
+// AlertRule returns the alert rule as it was at the version.
+func (v *AlertRuleVersion) AlertRule() AlertRule {
+	return AlertRule{
+		OrgID:                   v.RuleOrgID,
+		UID:                     v.RuleUID,
+		NamespaceUID:            v.RuleNamespaceUID,
+		RuleGroup:               v.RuleGroup,
+		RuleGroupIndex:          v.RuleGroupIndex,
+		Version:                 v.Version,
+		Updated:                 v.Created,
+		Title:                   v.Title,
+		Condition:               v.Condition,
+		Data:                    v.Data,
+		IntervalSeconds:         v.IntervalSeconds,
+		NoDataState:             v.NoDataState,
+		ExecErrState:            v.ExecErrState,
+		For:                     v.For,
+		Annotations:             v.Annotations,
+		Labels:                  v.Labels,
+		IsPaused:                v.IsPaused,
+		NotificationSettings:    v.NotificationSettings,
+		DataAvailabilityPeriod:  v.DataAvailabilityPeriod,
+		DataAvailabilityDelay:   v.DataAvailabilityDelay,
+		ShardAffinity:           v.ShardAffinity,
+		IncidentHooks:           v.IncidentHooks,
+		ExpiresAt:               v.ExpiresAt,
+		IntervalOverrideSeconds: v.IntervalOverrideSeconds,
+		EvaluationTimeout:       v.EvaluationTimeout,
+		Record:                  v.Record,
+	}
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -580,6 +902,13 @@ type GetAlertRulesGroupByRuleUIDQuery struct {
 	OrgID int64
 }
 
+// GetAlertRulesGroupsByRuleUIDsQuery is the query for retrieving the groups of alerts that the rules with the UIDs
+// belong to, at once rather than one query per rule.
+type GetAlertRulesGroupsByRuleUIDsQuery struct {
+	UIDs  []string
+	OrgID int64
+}
+
 // ListAlertRulesQuery is the query for listing alert rules
 type ListAlertRulesQuery struct {
 	OrgID         int64
@@ -593,6 +922,76 @@ type ListAlertRulesQuery struct {
 	PanelID      int64
 
 	ReceiverName string
+
+	// RuleGroupPrefix, if set, keeps only the rules of the groups whose name starts with it.
+	RuleGroupPrefix string
+	// LabelMatchers keeps only the rules whose labels match all of them.
+	LabelMatchers labels.Matchers
+	// Provenances, if set, keeps only the rules that have one of them. ProvenanceNone matches the rules that are not
+	// provisioned.
+	Provenances []Provenance
+	// ExpiredAt, if set, keeps only the rules that are expired at this time. See AlertRule.IsExpired.
+	ExpiredAt *time.Time
+	// ArchivedGroups selects the rules by whether their rule group is archived. The rules of all groups are kept by
+	// default.
+	ArchivedGroups ArchivedRuleGroupsFilter
+
+	// Limit is the maximum number of rules to return. Zero means no limit.
+	Limit int64
+	// Page is the 1-based page of results to return when Limit is set.
+	Page int64
+}
+
+// ArchivedRuleGroupsFilter selects alert rules by whether their rule group is archived. The rules of archived groups are
+// kept with their definition and provenance, but are not evaluated.
+type ArchivedRuleGroupsFilter string
+
+const (
+	// ArchivedRuleGroupsInclude keeps the rules of all groups.
+	ArchivedRuleGroupsInclude ArchivedRuleGroupsFilter = ""
+	// ArchivedRuleGroupsExclude keeps only the rules of the groups that are not archived.
+	ArchivedRuleGroupsExclude ArchivedRuleGroupsFilter = "exclude"
+	// ArchivedRuleGroupsOnly keeps only the rules of the archived groups.
+	ArchivedRuleGroupsOnly ArchivedRuleGroupsFilter = "only"
+)
+
+// MatchLabels returns whether the labels of a rule match all the label matchers of the query.
+func (q *ListAlertRulesQuery) MatchLabels(lbls map[string]string) bool {
+	for _, m := range q.LabelMatchers {
+		if !m.Matches(lbls[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Offset returns the number of rules that come before the page of the query.
+func (q *ListAlertRulesQuery) Offset() int64 {
+	if q.Limit <= 0 || q.Page <= 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.Limit
+}
+
+// SearchAlertRulesQuery is the query for searching alert rules by text.
+type SearchAlertRulesQuery struct {
+	OrgID int64
+	// Query is matched, case-insensitively, against the title, labels and annotations of alert rules.
+	// Both keys and values of labels and annotations are matched.
+	Query         string
+	NamespaceUIDs []string
+
+	// Limit is the maximum number of rules to return. Zero means no limit.
+	Limit int64
+	// Page is the 1-based page of results to return when Limit is set.
+	Page int64
+}
+
+// SearchAlertRulesResult is the result of a SearchAlertRulesQuery.
+type SearchAlertRulesResult struct {
+	Rules []*AlertRule
+	// TotalCount is the number of rules that match the query, regardless of the pagination.
+	TotalCount int64
 }
 
 // CountAlertRulesQuery is the query for counting alert rules
@@ -671,7 +1070,11 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.Title == "" {
 		ruleToPatch.Title = existingRule.Title
 	}
-	if ruleToPatch.Condition == "" || len(ruleToPatch.Data) == 0 {
+	if !ruleToPatch.HasRecord {
+		ruleToPatch.Record = existingRule.Record
+	}
+	// Recording rules write one of their queries or expressions, so they do not need a condition.
+	if (ruleToPatch.Condition == "" && !ruleToPatch.IsRecordingRule()) || len(ruleToPatch.Data) == 0 {
 		ruleToPatch.Condition = existingRule.Condition
 		ruleToPatch.Data = existingRule.Data
 	}
@@ -696,6 +1099,27 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
+	if !ruleToPatch.HasDataAvailability {
+		ruleToPatch.DataAvailabilityPeriod = existingRule.DataAvailabilityPeriod
+		ruleToPatch.DataAvailabilityDelay = existingRule.DataAvailabilityDelay
+	}
+	if !ruleToPatch.HasShardAffinity {
+		ruleToPatch.ShardAffinity = existingRule.ShardAffinity
+	}
+	if !ruleToPatch.HasIncidentHooks {
+		ruleToPatch.IncidentHooks = existingRule.IncidentHooks
+	}
+	if !ruleToPatch.HasExpiresAt {
+		ruleToPatch.ExpiresAt = existingRule.ExpiresAt
+	}
+	if !ruleToPatch.HasIntervalOverride {
+		ruleToPatch.IntervalOverrideSeconds = existingRule.IntervalOverrideSeconds
+	}
+	if !ruleToPatch.HasEvaluationTimeout {
+		ruleToPatch.EvaluationTimeout = existingRule.EvaluationTimeout
+	}
+	// The bake period is set when the rule is created and cannot be changed.
+	ruleToPatch.BakeUntil = existingRule.BakeUntil
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
@@ -706,6 +1130,16 @@ func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error
 	return nil
 }
 
+// ValidateRuleEvaluationTimeout validates the evaluation timeout of a rule, which is either zero or a positive duration
+// no longer than the maximum of the instance.
+func ValidateRuleEvaluationTimeout(timeout, maxTimeout time.Duration) error {
+	if timeout < 0 || timeout > maxTimeout {
+		return fmt.Errorf("%w: evaluation timeout (%v) should not be negative nor longer than the maximum of %v",
+			ErrAlertRuleFailedValidation, timeout, maxTimeout)
+	}
+	return nil
+}
+
 type RulesGroup []*AlertRule
 
 func (g RulesGroup) SortByGroupIndex() {
@@ -726,6 +1160,39 @@ func SortAlertRulesByGroupIndex(rules []AlertRule) {
 	})
 }
 
+// SetRuleGroupIndexes sets the indexes of the rules of a rule group that is written. The given indexes must be 1 to
+// the number of rules that have one, so that the order of these rules is explicit. The rules without an index are
+// indexed after them by their position, so that rules can be added to a group that was read with its indexes.
+func SetRuleGroupIndexes(rules []AlertRule) error {
+	given := 0
+	for i := range rules {
+		if rules[i].RuleGroupIndex != 0 {
+			given++
+		}
+	}
+	seen := make(map[int]string, given)
+	for _, rule := range rules {
+		if rule.RuleGroupIndex == 0 {
+			continue
+		}
+		if rule.RuleGroupIndex < 1 || rule.RuleGroupIndex > given {
+			return fmt.Errorf("%w: index %d of rule '%s' must be between 1 and the number of rules of the group that have an index, %d", ErrAlertRuleFailedValidation, rule.RuleGroupIndex, rule.Title, given)
+		}
+		if other, ok := seen[rule.RuleGroupIndex]; ok {
+			return fmt.Errorf("%w: rules '%s' and '%s' have the same index %d", ErrAlertRuleFailedValidation, other, rule.Title, rule.RuleGroupIndex)
+		}
+		seen[rule.RuleGroupIndex] = rule.Title
+	}
+	next := given
+	for i := range rules {
+		if rules[i].RuleGroupIndex == 0 {
+			next++
+			rules[i].RuleGroupIndex = next
+		}
+	}
+	return nil
+}
+
 const (
 	QuotaTargetSrv quota.TargetSrv = "ngalert"
 	QuotaTarget    quota.Target    = "alert_rule"
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+// RuleWarning describes a part of an alert rule that is well-formed, but is very likely to be a mistake.
+type RuleWarning struct {
+	// RefID is the RefID of the query or expression that the warning is about, empty if it is about the whole rule.
+	RefID   string
+	Message string
+}
+
+func (w RuleWarning) String() string {
+	if w.RefID == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.RefID, w.Message)
+}
+
+// AnalyzeRule looks for obviously ill-formed parts of the condition of the rule, which would only be noticed when the
+// rule is evaluated. It does not replace the validation of the rule, and ignores the parts of the rule that it cannot
+// parse.
+func AnalyzeRule(rule AlertRule) []RuleWarning {
+	var warnings []RuleWarning
+	defined := make(map[string]struct{}, len(rule.Data))
+	nonNumeric := make(map[string]string)
+	for _, q := range rule.Data {
+		defined[q.RefID] = struct{}{}
+		if kind, ok := nonNumericQueryKind(q); ok {
+			nonNumeric[q.RefID] = kind
+		}
+	}
+	if _, ok := defined[rule.Condition]; rule.Condition != "" && !ok {
+		warnings = append(warnings, RuleWarning{Message: fmt.Sprintf("condition refers to %s, which is not defined", rule.Condition)})
+	}
+	for _, q := range rule.Data {
+		if isExpression, _ := q.IsExpression(); !isExpression {
+			continue
+		}
+		for _, dep := range expressionDependencies(q) {
+			if _, ok := defined[dep]; !ok {
+				warnings = append(warnings, RuleWarning{RefID: q.RefID, Message: fmt.Sprintf("refers to %s, which is not defined", dep)})
+			}
+			if kind, ok := nonNumeric[dep]; ok && !acceptsNonNumericFrames(q) {
+				warnings = append(warnings, RuleWarning{RefID: q.RefID, Message: fmt.Sprintf("refers to %s, which returns %s and no numeric data", dep, kind)})
+			}
+		}
+		warnings = append(warnings, analyzeEvaluators(q)...)
+	}
+	return warnings
+}
+
+// nonNumericResultFormats are the result formats of data source queries that never return numeric frames.
+var nonNumericResultFormats = map[string]string{
+	"logs":       "logs",
+	"trace":      "traces",
+	"traces":     "traces",
+	"nodeGraph":  "a node graph",
+	"flamegraph": "a flame graph",
+}
+
+// nonNumericQueryKind returns the kind of the frames of a data source query whose model declares a result format that
+// never contains numeric data, e.g. a logs query. Expressions and queries that do not declare such a format are
+// considered numeric, because what they return cannot be known without running them.
+func nonNumericQueryKind(q AlertQuery) (string, bool) {
+	if isExpression, _ := q.IsExpression(); isExpression {
+		return "", false
+	}
+	var model struct {
+		Format    string `json:"format"`
+		QueryType string `json:"queryType"`
+	}
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return "", false
+	}
+	if kind, ok := nonNumericResultFormats[model.Format]; ok {
+		return kind, true
+	}
+	kind, ok := nonNumericResultFormats[model.QueryType]
+	return kind, ok
+}
+
+// acceptsNonNumericFrames returns true if the expression can make use of frames without numeric data, like SQL
+// expressions that operate on tables.
+func acceptsNonNumericFrames(q AlertQuery) bool {
+	var model struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return true
+	}
+	cmdType, err := expr.ParseCommandType(model.Type)
+	return err != nil || cmdType == expr.TypeSQL
+}
+
+// analyzeEvaluators returns warnings about the ranges of threshold and classic condition expressions that can never be
+// or are always satisfied.
+func analyzeEvaluators(q AlertQuery) []RuleWarning {
+	var model struct {
+		Type       string `json:"type"`
+		Conditions []struct {
+			Evaluator expr.ConditionEvalJSON `json:"evaluator"`
+		} `json:"conditions"`
+	}
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return nil
+	}
+	if cmdType, err := expr.ParseCommandType(model.Type); err != nil || (cmdType != expr.TypeThreshold && cmdType != expr.TypeClassicConditions) {
+		return nil
+	}
+	var warnings []RuleWarning
+	for _, c := range model.Conditions {
+		if len(c.Evaluator.Params) < 2 {
+			continue
+		}
+		lower, upper := c.Evaluator.Params[0], c.Evaluator.Params[1]
+		switch c.Evaluator.Type {
+		case expr.ThresholdIsWithinRange:
+			if lower >= upper {
+				warnings = append(warnings, RuleWarning{RefID: q.RefID, Message: fmt.Sprintf("no value is within the range from %v to %v, the condition can never be met", lower, upper)})
+			}
+		case expr.ThresholdIsOutsideRange:
+			if lower > upper {
+				warnings = append(warnings, RuleWarning{RefID: q.RefID, Message: fmt.Sprintf("every value is outside the range from %v to %v, the condition is always met", lower, upper)})
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+func TestAnalyzeRule(t *testing.T) {
+	query := AlertQuery{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr": "up"}`)}
+	expression := func(refID string, model string) AlertQuery {
+		return AlertQuery{RefID: refID, DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(model)}
+	}
+
+	testCases := []struct {
+		name     string
+		rule     AlertRule
+		expected []RuleWarning
+	}{
+		{
+			name: "well-formed rule",
+			rule: AlertRule{Condition: "C", Data: []AlertQuery{
+				query,
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "threshold", "expression": "B", "conditions": [{"evaluator": {"type": "within_range", "params": [1, 2]}}]}`),
+			}},
+		},
+		{
+			name: "undefined condition",
+			rule: AlertRule{Condition: "B", Data: []AlertQuery{query}},
+			expected: []RuleWarning{
+				{Message: "condition refers to B, which is not defined"},
+			},
+		},
+		{
+			name: "undefined references",
+			rule: AlertRule{Condition: "B", Data: []AlertQuery{
+				query,
+				expression("B", `{"type": "math", "expression": "$A + $C"}`),
+			}},
+			expected: []RuleWarning{
+				{RefID: "B", Message: "refers to C, which is not defined"},
+			},
+		},
+		{
+			name: "reduce of a query that returns no numeric data",
+			rule: AlertRule{Condition: "C", Data: []AlertQuery{
+				{RefID: "A", DatasourceUID: "loki", Model: json.RawMessage(`{"expr": "{job=\"app\"}", "queryType": "logs"}`)},
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "threshold", "expression": "B", "conditions": [{"evaluator": {"type": "gt", "params": [1]}}]}`),
+			}},
+			expected: []RuleWarning{
+				{RefID: "B", Message: "refers to A, which returns logs and no numeric data"},
+			},
+		},
+		{
+			name: "SQL expression on a query that returns no numeric data",
+			rule: AlertRule{Condition: "B", Data: []AlertQuery{
+				{RefID: "A", DatasourceUID: "tempo", Model: json.RawMessage(`{"query": "{}", "format": "traces"}`)},
+				expression("B", `{"type": "sql", "expression": "SELECT COUNT(*) FROM A"}`),
+			}},
+		},
+		{
+			name: "empty range",
+			rule: AlertRule{Condition: "B", Data: []AlertQuery{
+				query,
+				expression("B", `{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "within_range", "params": [5, 1]}}]}`),
+			}},
+			expected: []RuleWarning{
+				{RefID: "B", Message: "no value is within the range from 5 to 1, the condition can never be met"},
+			},
+		},
+		{
+			name: "range that covers every value",
+			rule: AlertRule{Condition: "B", Data: []AlertQuery{
+				query,
+				expression("B", `{"type": "classic_conditions", "conditions": [{"evaluator": {"type": "outside_range", "params": [5, 1]}, "operator": {"type": "and"}, "query": {"params": ["A"]}, "reducer": {"type": "avg"}}]}`),
+			}},
+			expected: []RuleWarning{
+				{RefID: "B", Message: "every value is outside the range from 5 to 1, the condition is always met"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, AnalyzeRule(tc.rule))
+		})
+	}
+}
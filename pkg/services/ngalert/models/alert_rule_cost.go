@@ -0,0 +1,134 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+)
+
+// datasourceCostWeights is the relative cost of a query to a data source of the type, compared to a Prometheus query
+// that returns the same number of data points. Log and document stores scan the raw data of the time range, and cloud
+// APIs are billed per request.
+var datasourceCostWeights = map[string]float64{
+	"prometheus":                       1,
+	"graphite":                         1,
+	"influxdb":                         1.5,
+	"mysql":                            2,
+	"postgres":                         2,
+	"grafana-postgresql-datasource":    2,
+	"mssql":                            2,
+	"loki":                             3,
+	"elasticsearch":                    3,
+	"grafana-azure-monitor-datasource": 4,
+	"cloudwatch":                       4,
+	"stackdriver":                      4,
+	"grafana-bigquery-datasource":      4,
+	"grafana-testdata-datasource":      0,
+	"testdata":                         0,
+}
+
+// defaultDatasourceCostWeight is the weight of the data source types that are not in datasourceCostWeights.
+const defaultDatasourceCostWeight = 2
+
+// dataPointsPerCostUnit is the number of data points that a query returns for each unit of cost on top of the cost of
+// the request itself.
+const dataPointsPerCostUnit = 1000
+
+// QueryCost is the estimated cost of a data source query of an alert rule.
+type QueryCost struct {
+	RefID          string
+	DatasourceUID  string
+	DatasourceType string
+	TimeRange      time.Duration
+	Step           time.Duration
+	// DataPoints is the number of data points per series that the query returns, at most its max data points.
+	DataPoints int64
+	Cost       float64
+}
+
+// RuleCost is the estimated cost of one evaluation of an alert rule.
+type RuleCost struct {
+	UID      string
+	Title    string
+	IsPaused bool
+	Queries  []QueryCost
+	Cost     float64
+}
+
+// RuleGroupCost is the estimated cost of the evaluations of a rule group.
+type RuleGroupCost struct {
+	Rules []RuleCost
+	// CostPerEvaluation is the sum of the costs of the rules that are not paused.
+	CostPerEvaluation float64
+	EvaluationsPerDay int64
+	// QueriesPerDay is the number of data source queries of the rules that are not paused in a day.
+	QueriesPerDay int64
+}
+
+// EstimateRuleGroupCost estimates the cost of the evaluations of the group from the time ranges, the steps and the data
+// source types of the queries of its rules. datasourceTypes maps data source UIDs to their types. Queries to data
+// sources that are not in it use the type in their model, if any. Expressions are evaluated by Grafana and are not
+// counted. The cost is a relative score, one being a Prometheus query that returns at most 1000 data points per series.
+func EstimateRuleGroupCost(group AlertRuleGroup, datasourceTypes map[string]string) RuleGroupCost {
+	result := RuleGroupCost{Rules: make([]RuleCost, 0, len(group.Rules))}
+	if group.Interval > 0 {
+		result.EvaluationsPerDay = int64(24*time.Hour/time.Second) / group.Interval
+	}
+	for _, rule := range group.Rules {
+		rc := RuleCost{UID: rule.UID, Title: rule.Title, IsPaused: rule.IsPaused}
+		for _, q := range rule.Data {
+			if isExpression, _ := q.IsExpression(); isExpression {
+				continue
+			}
+			qc := estimateQueryCost(q, datasourceTypes)
+			rc.Queries = append(rc.Queries, qc)
+			rc.Cost += qc.Cost
+		}
+		if !rule.IsPaused {
+			result.CostPerEvaluation += rc.Cost
+			result.QueriesPerDay += int64(len(rc.Queries)) * result.EvaluationsPerDay
+		}
+		result.Rules = append(result.Rules, rc)
+	}
+	return result
+}
+
+func estimateQueryCost(q AlertQuery, datasourceTypes map[string]string) QueryCost {
+	qc := QueryCost{
+		RefID:          q.RefID,
+		DatasourceUID:  q.DatasourceUID,
+		DatasourceType: datasourceTypes[q.DatasourceUID],
+		TimeRange:      time.Duration(q.RelativeTimeRange.From - q.RelativeTimeRange.To),
+	}
+	if qc.DatasourceType == "" {
+		var model struct {
+			Datasource struct {
+				Type string `json:"type"`
+			} `json:"datasource"`
+		}
+		if err := json.Unmarshal(q.Model, &model); err == nil {
+			qc.DatasourceType = model.Datasource.Type
+		}
+	}
+	// Invalid models are rejected when the rule is saved, so the defaults of the evaluation are good enough here.
+	qc.Step, _ = q.GetIntervalDuration()
+	if qc.Step <= 0 {
+		qc.Step = time.Duration(defaultIntervalMS) * time.Millisecond
+	}
+	maxDataPoints, _ := q.GetMaxDatapoints()
+	if maxDataPoints <= 0 {
+		maxDataPoints = int64(defaultMaxDataPoints)
+	}
+	if qc.TimeRange > 0 {
+		qc.DataPoints = int64(math.Ceil(float64(qc.TimeRange) / float64(qc.Step)))
+	}
+	if qc.DataPoints > maxDataPoints {
+		qc.DataPoints = maxDataPoints
+	}
+	weight, ok := datasourceCostWeights[qc.DatasourceType]
+	if !ok {
+		weight = defaultDatasourceCostWeight
+	}
+	qc.Cost = weight * (1 + float64(qc.DataPoints)/dataPointsPerCostUnit)
+	return qc
+}
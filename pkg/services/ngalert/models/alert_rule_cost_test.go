@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+func TestEstimateRuleGroupCost(t *testing.T) {
+	query := func(refID, datasourceUID string, timeRange time.Duration, model string) AlertQuery {
+		return AlertQuery{
+			RefID:             refID,
+			DatasourceUID:     datasourceUID,
+			RelativeTimeRange: RelativeTimeRange{From: Duration(timeRange)},
+			Model:             json.RawMessage(model),
+		}
+	}
+	reduce := AlertQuery{RefID: "C", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "reduce", "expression": "A", "reducer": "last"}`)}
+
+	group := AlertRuleGroup{
+		Interval: 30,
+		Rules: []AlertRule{
+			{UID: "prometheus", Title: "Prometheus", Data: []AlertQuery{
+				query("A", "prom", 10*time.Minute, `{"expr": "up", "intervalMs": 15000}`),
+				reduce,
+			}},
+			{UID: "loki", Title: "Loki", Data: []AlertQuery{
+				query("A", "logs", time.Hour, `{"datasource": {"type": "loki"}, "intervalMs": 1000, "maxDataPoints": 100}`),
+			}},
+			{UID: "paused", Title: "Paused", IsPaused: true, Data: []AlertQuery{
+				query("A", "other", time.Minute, `{}`),
+			}},
+		},
+	}
+
+	cost := EstimateRuleGroupCost(group, map[string]string{"prom": "prometheus"})
+
+	require.Equal(t, int64(2880), cost.EvaluationsPerDay)
+	require.Equal(t, int64(2*2880), cost.QueriesPerDay)
+	require.Len(t, cost.Rules, 3)
+
+	prometheus := cost.Rules[0]
+	require.Len(t, prometheus.Queries, 1, "expressions should not be counted")
+	require.Equal(t, QueryCost{
+		RefID:          "A",
+		DatasourceUID:  "prom",
+		DatasourceType: "prometheus",
+		TimeRange:      10 * time.Minute,
+		Step:           15 * time.Second,
+		DataPoints:     40,
+		Cost:           1.04,
+	}, prometheus.Queries[0])
+
+	loki := cost.Rules[1]
+	require.Equal(t, "loki", loki.Queries[0].DatasourceType, "type should be read from the model")
+	require.Equal(t, int64(100), loki.Queries[0].DataPoints, "data points should be limited by max data points")
+	require.InDelta(t, 3.3, loki.Cost, 1e-9)
+
+	paused := cost.Rules[2]
+	require.True(t, paused.IsPaused)
+	require.InDelta(t, defaultDatasourceCostWeight*1.06, paused.Cost, 1e-9)
+
+	require.InDelta(t, 1.04+3.3, cost.CostPerEvaluation, 1e-9, "paused rules should not be counted")
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/classic"
+)
+
+// RuleLimits contains the limits on the size and complexity of a single alert rule. A limit of 0 means no limit.
+type RuleLimits struct {
+	// MaxSizeBytes is the maximum size of the rule serialized to JSON.
+	MaxSizeBytes int
+	// MaxQueries is the maximum number of queries and expressions of the rule.
+	MaxQueries int
+	// MaxExpressionDepth is the maximum number of expressions in a chain of expressions, where each expression
+	// depends on the result of the previous one.
+	MaxExpressionDepth int
+}
+
+// Validate returns an error if the rule exceeds any of the limits.
+func (l RuleLimits) Validate(rule AlertRule) error {
+	if l.MaxQueries > 0 && len(rule.Data) > l.MaxQueries {
+		return ErrAlertRuleTooManyQueries(rule, len(rule.Data), l.MaxQueries)
+	}
+	if l.MaxExpressionDepth > 0 {
+		if depth := ExpressionDepth(rule.Data); depth > l.MaxExpressionDepth {
+			return ErrAlertRuleExpressionTooDeep(rule, depth, l.MaxExpressionDepth)
+		}
+	}
+	if l.MaxSizeBytes > 0 {
+		b, err := json.Marshal(rule)
+		if err != nil {
+			return fmt.Errorf("failed to serialize alert rule: %w", err)
+		}
+		if len(b) > l.MaxSizeBytes {
+			return ErrAlertRuleTooLarge(rule, len(b), l.MaxSizeBytes)
+		}
+	}
+	return nil
+}
+
+// ExpressionDepth returns the length of the longest chain of expressions in the queries. Queries to data sources have a
+// depth of 0, and an expression is one level deeper than the deepest query or expression it depends on.
+//
+// Expressions that cannot be parsed, and references to unknown queries, are ignored, since they are reported by the
+// validation of the rule itself. So are dependency cycles.
+func ExpressionDepth(queries []AlertQuery) int {
+	byRefID := make(map[string]AlertQuery, len(queries))
+	for _, q := range queries {
+		byRefID[q.RefID] = q
+	}
+	depths := make(map[string]int, len(queries))
+	visiting := make(map[string]struct{})
+	var depthOf func(refID string) int
+	depthOf = func(refID string) int {
+		if d, ok := depths[refID]; ok {
+			return d
+		}
+		q, ok := byRefID[refID]
+		if !ok {
+			return 0
+		}
+		if _, ok := visiting[refID]; ok {
+			return 0
+		}
+		if isExpression, _ := q.IsExpression(); !isExpression {
+			depths[refID] = 0
+			return 0
+		}
+		visiting[refID] = struct{}{}
+		deepest := 0
+		for _, dep := range expressionDependencies(q) {
+			deepest = max(deepest, depthOf(dep))
+		}
+		delete(visiting, refID)
+		depths[refID] = deepest + 1
+		return deepest + 1
+	}
+	result := 0
+	for _, q := range queries {
+		result = max(result, depthOf(q.RefID))
+	}
+	return result
+}
+
+// expressionDependencies returns the RefIDs of the queries that the expression depends on, or nil if the expression
+// cannot be parsed.
+func expressionDependencies(q AlertQuery) []string {
+	var model map[string]any
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return nil
+	}
+	cmdType, err := expr.GetExpressionCommandType(model)
+	if err != nil {
+		return nil
+	}
+	if cmdType == expr.TypeClassicConditions {
+		cmd, err := classic.UnmarshalConditionsCmd(model, q.RefID)
+		if err != nil {
+			return nil
+		}
+		return cmd.NeedsVars()
+	}
+	expression, ok := model["expression"].(string)
+	if !ok {
+		return nil
+	}
+	switch cmdType {
+	case expr.TypeMath:
+		cmd, err := expr.NewMathCommand(q.RefID, expression)
+		if err != nil {
+			return nil
+		}
+		return cmd.NeedsVars()
+	case expr.TypeSQL:
+		cmd, err := expr.NewSQLCommand(q.RefID, expression)
+		if err != nil {
+			return nil
+		}
+		return cmd.NeedsVars()
+	default:
+		// Reduce, resample and threshold expressions refer to the query they take as input by its RefID.
+		return []string{expression}
+	}
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+)
+
+func TestExpressionDepth(t *testing.T) {
+	query := func(refID string) AlertQuery {
+		return AlertQuery{RefID: refID, DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr": "up"}`)}
+	}
+	expression := func(refID string, model string) AlertQuery {
+		return AlertQuery{RefID: refID, DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(model)}
+	}
+
+	testCases := []struct {
+		name     string
+		queries  []AlertQuery
+		expected int
+	}{
+		{
+			name:     "only data source queries",
+			queries:  []AlertQuery{query("A"), query("B")},
+			expected: 0,
+		},
+		{
+			name: "chain of expressions",
+			queries: []AlertQuery{
+				query("A"),
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "math", "expression": "$B * 2"}`),
+				expression("D", `{"type": "threshold", "expression": "C", "conditions": [{"evaluator": {"type": "gt", "params": [1]}}]}`),
+			},
+			expected: 3,
+		},
+		{
+			name: "longest of several branches",
+			queries: []AlertQuery{
+				query("A"),
+				query("B"),
+				expression("C", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("D", `{"type": "math", "expression": "$C + $B"}`),
+				expression("E", `{"type": "math", "expression": "$B > 0"}`),
+			},
+			expected: 2,
+		},
+		{
+			name: "classic conditions",
+			queries: []AlertQuery{
+				query("A"),
+				expression("B", `{"type": "classic_conditions", "conditions": [{"evaluator": {"type": "gt", "params": [1]}, "operator": {"type": "and"}, "query": {"params": ["A"]}, "reducer": {"type": "avg"}}]}`),
+			},
+			expected: 1,
+		},
+		{
+			name: "cycle",
+			queries: []AlertQuery{
+				expression("A", `{"type": "math", "expression": "$B"}`),
+				expression("B", `{"type": "math", "expression": "$A"}`),
+			},
+			expected: 2,
+		},
+		{
+			name: "invalid expression",
+			queries: []AlertQuery{
+				query("A"),
+				expression("B", `{"type": "unknown"}`),
+			},
+			expected: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ExpressionDepth(tc.queries))
+		})
+	}
+}
+
+func TestRuleLimitsValidate(t *testing.T) {
+	rule := AlertRule{
+		UID:   "rule",
+		Title: "rule",
+		Data: []AlertQuery{
+			{RefID: "A", DatasourceUID: "prometheus", Model: json.RawMessage(`{"expr": "up"}`)},
+			{RefID: "B", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "reduce", "expression": "A", "reducer": "last"}`)},
+			{RefID: "C", DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(`{"type": "math", "expression": "$B > 1"}`)},
+		},
+	}
+
+	t.Run("should accept any rule without limits", func(t *testing.T) {
+		require.NoError(t, RuleLimits{}.Validate(rule))
+	})
+
+	t.Run("should accept a rule within the limits", func(t *testing.T) {
+		require.NoError(t, RuleLimits{MaxSizeBytes: 10000, MaxQueries: 3, MaxExpressionDepth: 2}.Validate(rule))
+	})
+
+	t.Run("should reject a rule with too many queries", func(t *testing.T) {
+		err := RuleLimits{MaxQueries: 2}.Validate(rule)
+		require.ErrorIs(t, err, ErrAlertRuleTooManyQueriesBase)
+		require.True(t, IsErrAlertRuleLimitExceeded(err))
+	})
+
+	t.Run("should reject a rule with too deep expressions", func(t *testing.T) {
+		err := RuleLimits{MaxExpressionDepth: 1}.Validate(rule)
+		require.ErrorIs(t, err, ErrAlertRuleExpressionTooDeepBase)
+		require.True(t, IsErrAlertRuleLimitExceeded(err))
+	})
+
+	t.Run("should reject a rule that is too large", func(t *testing.T) {
+		large := rule
+		large.Annotations = map[string]string{"description": strings.Repeat("a", 1000)}
+		err := RuleLimits{MaxSizeBytes: 1000}.Validate(large)
+		require.ErrorIs(t, err, ErrAlertRuleTooLargeBase)
+		require.True(t, IsErrAlertRuleLimitExceeded(err))
+	})
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var ErrAlertRuleTemplateNotFound = errutil.NotFound("alerting.alert-rule-template.notFound", errutil.WithPublicMessage("alert rule template not found"))
+
+// AlertRuleTemplate is an alert rule whose fields contain ${name} placeholders, which are replaced with the values of
+// its parameters when a rule is instantiated from it. It lets one definition produce the same rule for each
+// environment or team, e.g. in the folder and with the data source of each of them.
+type AlertRuleTemplate struct {
+	OrgID      int64
+	UID        string
+	Title      string
+	Parameters []AlertRuleTemplateParameter
+	// Rule is the template of the instantiated rules. Its title, UID, folder, group, data sources, label and annotation
+	// values, query models and contact points can contain placeholders.
+	Rule    AlertRule
+	Updated time.Time
+}
+
+// AlertRuleTemplateParameter is a parameter of an AlertRuleTemplate.
+type AlertRuleTemplateParameter struct {
+	Name        string
+	Description string
+	// Default is the value of the parameter if the instantiation does not set one. The parameter is required if it has
+	// no default.
+	Default *string
+}
@@ -254,6 +254,57 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.IsPaused = true
 				},
 			},
+			{
+				name: "data availability did not come in request",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.DataAvailabilityPeriod = time.Hour
+					r.DataAvailabilityDelay = 5 * time.Minute
+				},
+			},
+			{
+				name: "shard affinity did not come in request",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.ShardAffinity = "heavy"
+				},
+			},
+			{
+				name: "incident hooks did not come in request",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.IncidentHooks = []IncidentHook{{Name: "tickets", URL: "https://example.com"}}
+				},
+			},
+			{
+				name: "expiration did not come in request",
+				mutator: func(r *AlertRuleWithOptionals) {
+					expiresAt := time.Now().Add(time.Hour)
+					r.ExpiresAt = &expiresAt
+				},
+			},
+			{
+				name: "interval override did not come in request",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.IntervalOverrideSeconds = 300
+				},
+			},
+			{
+				name: "evaluation timeout did not come in request",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.EvaluationTimeout = 2 * time.Minute
+				},
+			},
+			{
+				name: "record did not come in request",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.Record = Record{Metric: "disk_usage_ratio", From: "A", TargetDatasourceUID: "prometheus"}
+				},
+			},
+			{
+				name: "bake period is changed",
+				mutator: func(r *AlertRuleWithOptionals) {
+					bakeUntil := time.Now().Add(time.Hour)
+					r.BakeUntil = &bakeUntil
+				},
+			},
 		}
 
 		for _, testCase := range testCases {
@@ -366,6 +417,143 @@ func TestPatchPartialAlertRule(t *testing.T) {
 	})
 }
 
+func TestIsDataAvailable(t *testing.T) {
+	hour := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("data is always available without a period", func(t *testing.T) {
+		rule := AlertRule{}
+		require.True(t, rule.IsDataAvailable(hour))
+	})
+
+	t.Run("data is not available at the start of the period", func(t *testing.T) {
+		rule := AlertRule{DataAvailabilityPeriod: time.Hour, DataAvailabilityDelay: 5 * time.Minute}
+		require.False(t, rule.IsDataAvailable(hour))
+		require.False(t, rule.IsDataAvailable(hour.Add(4*time.Minute+59*time.Second)))
+		require.True(t, rule.IsDataAvailable(hour.Add(5*time.Minute)))
+		require.True(t, rule.IsDataAvailable(hour.Add(59*time.Minute)))
+		require.False(t, rule.IsDataAvailable(hour.Add(time.Hour)))
+	})
+
+	t.Run("periods are aligned on UTC", func(t *testing.T) {
+		rule := AlertRule{DataAvailabilityPeriod: 24 * time.Hour, DataAvailabilityDelay: 2 * time.Hour}
+		midnight := time.Date(2024, 3, 1, 0, 0, 0, 0, time.FixedZone("UTC+1", 3600))
+		require.True(t, rule.IsDataAvailable(midnight))
+		require.False(t, rule.IsDataAvailable(midnight.Add(time.Hour)))
+		require.True(t, rule.IsDataAvailable(midnight.Add(3*time.Hour)))
+	})
+}
+
+func TestDataAvailableAt(t *testing.T) {
+	hour := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("evaluation time is not changed without a period", func(t *testing.T) {
+		rule := AlertRule{}
+		require.Equal(t, hour.Add(time.Minute), rule.DataAvailableAt(hour.Add(time.Minute)))
+	})
+
+	t.Run("evaluation time is the start of the window during its delay", func(t *testing.T) {
+		rule := AlertRule{DataAvailabilityPeriod: time.Hour, DataAvailabilityDelay: 5 * time.Minute}
+		require.Equal(t, hour, rule.DataAvailableAt(hour))
+		require.Equal(t, hour, rule.DataAvailableAt(hour.Add(4*time.Minute)))
+		require.Equal(t, hour.Add(5*time.Minute), rule.DataAvailableAt(hour.Add(5*time.Minute)))
+		require.Equal(t, hour.Add(59*time.Minute), rule.DataAvailableAt(hour.Add(59*time.Minute)))
+	})
+}
+
+func TestValidateDataAvailability(t *testing.T) {
+	require.NoError(t, ValidateDataAvailability(0, 0))
+	require.NoError(t, ValidateDataAvailability(time.Hour, 5*time.Minute))
+	require.ErrorIs(t, ValidateDataAvailability(0, 5*time.Minute), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateDataAvailability(time.Hour, 0), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateDataAvailability(time.Hour, time.Hour), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateDataAvailability(-time.Hour, -time.Minute), ErrAlertRuleFailedValidation)
+}
+
+func TestEffectivePendingPeriod(t *testing.T) {
+	testCases := []struct {
+		name     string
+		For      time.Duration
+		interval int64
+		override int64
+		expected time.Duration
+	}{
+		{name: "no pending period", For: 0, interval: 60, expected: 0},
+		{name: "multiple of the interval", For: 5 * time.Minute, interval: 60, expected: 5 * time.Minute},
+		{name: "shorter than the interval", For: 2 * time.Minute, interval: 300, expected: 5 * time.Minute},
+		{name: "rounded up to the next evaluation", For: 7 * time.Minute, interval: 300, expected: 10 * time.Minute},
+		{name: "no interval", For: 2 * time.Minute, interval: 0, expected: 2 * time.Minute},
+		{name: "interval of the rule", For: 2 * time.Minute, interval: 60, override: 300, expected: 5 * time.Minute},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := AlertRule{For: tc.For, IntervalSeconds: tc.interval, IntervalOverrideSeconds: tc.override}
+			require.Equal(t, tc.expected, rule.EffectivePendingPeriod())
+		})
+	}
+}
+
+func TestValidateRuleEvaluationTimeout(t *testing.T) {
+	require.NoError(t, ValidateRuleEvaluationTimeout(0, 30*time.Second))
+	require.NoError(t, ValidateRuleEvaluationTimeout(10*time.Second, 30*time.Second))
+	require.NoError(t, ValidateRuleEvaluationTimeout(2*time.Minute, 2*time.Minute))
+	require.ErrorIs(t, ValidateRuleEvaluationTimeout(3*time.Minute, 2*time.Minute), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateRuleEvaluationTimeout(-time.Second, 2*time.Minute), ErrAlertRuleFailedValidation)
+}
+
+func TestRecordValidate(t *testing.T) {
+	data := []AlertQuery{{RefID: "A"}, {RefID: "B"}}
+	valid := Record{Metric: "disk_usage:ratio", From: "B", TargetDatasourceUID: "prometheus"}
+	require.NoError(t, valid.Validate(data))
+
+	invalidMetric := valid
+	invalidMetric.Metric = "disk usage"
+	require.ErrorIs(t, invalidMetric.Validate(data), ErrAlertRuleFailedValidation)
+
+	unknownFrom := valid
+	unknownFrom.From = "C"
+	require.ErrorIs(t, unknownFrom.Validate(data), ErrAlertRuleFailedValidation)
+
+	noTarget := valid
+	noTarget.TargetDatasourceUID = ""
+	require.ErrorIs(t, noTarget.Validate(data), ErrAlertRuleFailedValidation)
+}
+
+func TestValidateShardAffinity(t *testing.T) {
+	require.NoError(t, ValidateShardAffinity("", nil))
+	require.NoError(t, ValidateShardAffinity("", []string{"heavy"}))
+	require.NoError(t, ValidateShardAffinity("shard-1.eu_west", []string{"shard-1.eu_west"}))
+	require.NoError(t, ValidateShardAffinity("heavy", []string{"light", "heavy"}))
+	require.ErrorIs(t, ValidateShardAffinity("heavy", nil), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateShardAffinity("not a shard", []string{"not a shard"}), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateShardAffinity(strings.Repeat("a", 41), nil), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateShardAffinity("medium", []string{"light", "heavy"}), ErrAlertRuleFailedValidation)
+
+	require.NoError(t, ValidateShardAffinityName(""))
+	require.NoError(t, ValidateShardAffinityName("shard-1.eu_west"))
+	require.ErrorIs(t, ValidateShardAffinityName("not a shard"), ErrAlertRuleFailedValidation)
+}
+
+func TestValidateIncidentHooks(t *testing.T) {
+	hook := IncidentHook{Name: "tickets", URL: "https://incidents.example.com/api/alerts"}
+	require.NoError(t, ValidateIncidentHooks(nil))
+	require.NoError(t, ValidateIncidentHooks([]IncidentHook{hook, {Name: "pager", URL: "http://pager:8080"}}))
+	require.ErrorIs(t, ValidateIncidentHooks([]IncidentHook{{URL: hook.URL}}), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateIncidentHooks([]IncidentHook{{Name: "tickets", URL: "/api/alerts"}}), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateIncidentHooks([]IncidentHook{{Name: "tickets", URL: "ftp://incidents.example.com"}}), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, ValidateIncidentHooks([]IncidentHook{hook, hook}), ErrAlertRuleFailedValidation)
+}
+
+func TestDeterministicAlertRuleUID(t *testing.T) {
+	uid := DeterministicAlertRuleUID(1, "folder", "group", "title")
+	require.NoError(t, util.ValidateUID(uid))
+	require.Equal(t, uid, DeterministicAlertRuleUID(1, "folder", "group", "title"), "the same rule should get the same UID")
+	require.NotEqual(t, uid, DeterministicAlertRuleUID(2, "folder", "group", "title"))
+	require.NotEqual(t, uid, DeterministicAlertRuleUID(1, "other", "group", "title"))
+	require.NotEqual(t, uid, DeterministicAlertRuleUID(1, "folder", "other", "title"))
+	require.NotEqual(t, uid, DeterministicAlertRuleUID(1, "folder", "group", "other"))
+	require.NotEqual(t, uid, DeterministicAlertRuleUID(1, "folder", "groupt", "itle"))
+}
+
 func TestDiff(t *testing.T) {
 	t.Run("should return nil if there is no diff", func(t *testing.T) {
 		rule1 := AlertRuleGen()()
@@ -831,6 +1019,41 @@ func TestSortByGroupIndex(t *testing.T) {
 	})
 }
 
+func TestSetRuleGroupIndexes(t *testing.T) {
+	indexed := func(indexes ...int) []AlertRule {
+		rules := make([]AlertRule, 0, len(indexes))
+		for i, idx := range indexes {
+			rules = append(rules, AlertRule{Title: fmt.Sprintf("rule-%d", i), RuleGroupIndex: idx})
+		}
+		return rules
+	}
+	indexes := func(rules []AlertRule) []int {
+		result := make([]int, 0, len(rules))
+		for _, rule := range rules {
+			result = append(result, rule.RuleGroupIndex)
+		}
+		return result
+	}
+
+	rules := indexed(0, 0, 0)
+	require.NoError(t, SetRuleGroupIndexes(rules))
+	require.Equal(t, []int{1, 2, 3}, indexes(rules), "rules without indexes should be indexed by position")
+
+	rules = indexed(2, 3, 1)
+	require.NoError(t, SetRuleGroupIndexes(rules))
+	require.Equal(t, []int{2, 3, 1}, indexes(rules), "given indexes should be kept")
+
+	rules = indexed(0, 2, 0, 1)
+	require.NoError(t, SetRuleGroupIndexes(rules))
+	require.Equal(t, []int{3, 2, 4, 1}, indexes(rules), "rules without indexes should be indexed after the given indexes")
+
+	require.NoError(t, SetRuleGroupIndexes(nil))
+	require.ErrorIs(t, SetRuleGroupIndexes(indexed(0, 2)), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, SetRuleGroupIndexes(indexed(1, 2, 4)), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, SetRuleGroupIndexes(indexed(1, 2, 2)), ErrAlertRuleFailedValidation)
+	require.ErrorIs(t, SetRuleGroupIndexes(indexed(-1, 1)), ErrAlertRuleFailedValidation)
+}
+
 func TestTimeRangeYAML(t *testing.T) {
 	yamlRaw := "from: 600\nto: 0\n"
 	var rtr RelativeTimeRange
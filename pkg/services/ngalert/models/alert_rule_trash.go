@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var ErrDeletedAlertRuleNotFound = errutil.NotFound("alerting.alert-rule-trash.notFound", errutil.WithPublicMessage("deleted alert rule not found"))
+
+// DeletedAlertRule is an alert rule kept in the trash after it was deleted, so that it can be restored until it is
+// cleaned up.
+type DeletedAlertRule struct {
+	ID           int64      `xorm:"pk autoincr 'id'"`
+	OrgID        int64      `xorm:"org_id"`
+	RuleUID      string     `xorm:"rule_uid"`
+	Title        string     `xorm:"title"`
+	NamespaceUID string     `xorm:"namespace_uid"`
+	RuleGroup    string     `xorm:"rule_group"`
+	Provenance   Provenance `xorm:"provenance"`
+	DeletedAt    int64      `xorm:"deleted_at"`
+	// Data is the JSON encoding of the deleted rule. It is not loaded when listing deleted rules.
+	Data []byte `xorm:"data"`
+}
+
+// NewDeletedAlertRule creates the entry of the trash of the rule, deleted at the given Unix time in seconds.
+func NewDeletedAlertRule(rule *AlertRule, provenance Provenance, deletedAt int64) (*DeletedAlertRule, error) {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode deleted alert rule: %w", err)
+	}
+	return &DeletedAlertRule{
+		OrgID:        rule.OrgID,
+		RuleUID:      rule.UID,
+		Title:        rule.Title,
+		NamespaceUID: rule.NamespaceUID,
+		RuleGroup:    rule.RuleGroup,
+		Provenance:   provenance,
+		DeletedAt:    deletedAt,
+		Data:         data,
+	}, nil
+}
+
+// Rule decodes the deleted rule.
+func (d *DeletedAlertRule) Rule() (AlertRule, error) {
+	var rule AlertRule
+	if err := json.Unmarshal(d.Data, &rule); err != nil {
+		return AlertRule{}, fmt.Errorf("failed to decode deleted alert rule: %w", err)
+	}
+	return rule, nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// ConfigSnapshotVersion is the version of the format of the content of the snapshots created by this version of
+// Grafana. It must be increased whenever ConfigSnapshotContent changes in a way that older snapshots cannot be read.
+const ConfigSnapshotVersion = 1
+
+// ConfigSnapshotRestoreMode decides how the content of a snapshot is restored over the current alerting configuration.
+type ConfigSnapshotRestoreMode string
+
+const (
+	// ConfigSnapshotRestoreReplace makes the alerting configuration the same as in the snapshot. The resources created
+	// after the snapshot are deleted.
+	ConfigSnapshotRestoreReplace ConfigSnapshotRestoreMode = "replace"
+	// ConfigSnapshotRestoreMerge restores the resources of the snapshot and keeps the resources created after the
+	// snapshot. The notification policy tree is not restored.
+	ConfigSnapshotRestoreMerge ConfigSnapshotRestoreMode = "merge"
+)
+
+var ErrConfigSnapshotNotFound = errutil.NotFound("alerting.config-snapshot.notFound", errutil.WithPublicMessage("snapshot not found"))
+
+// ConfigSnapshot is a backup of the alerting configuration of an organization.
+type ConfigSnapshot struct {
+	ID      int64 `xorm:"pk autoincr 'id'"`
+	OrgID   int64 `xorm:"org_id"`
+	Version int   `xorm:"version"`
+	// Hash is the hash of the uncompressed content. Two snapshots with the same hash contain the same configuration.
+	Hash string `xorm:"hash"`
+	// Size is the size of the compressed content in bytes.
+	Size      int64 `xorm:"size"`
+	CreatedAt int64 `xorm:"created_at"`
+	// Data is the gzip compressed JSON encoding of ConfigSnapshotContent. It is not loaded when listing snapshots.
+	Data []byte `xorm:"data"`
+}
+
+// ConfigSnapshotContent is the alerting configuration of an organization saved in a snapshot.
+type ConfigSnapshotContent struct {
+	// AlertmanagerConfiguration is the configuration of the Alertmanager, which contains the contact points,
+	// notification policies, templates and mute timings. The secure settings of the contact points remain encrypted.
+	AlertmanagerConfiguration string
+	Rules                     []AlertRule
+}
+
+// NewConfigSnapshot creates a snapshot of the alerting configuration of the organization.
+func NewConfigSnapshot(orgID int64, content ConfigSnapshotContent, createdAt int64) (*ConfigSnapshot, error) {
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(raw); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress snapshot: %w", err)
+	}
+	return &ConfigSnapshot{
+		OrgID:     orgID,
+		Version:   ConfigSnapshotVersion,
+		Hash:      fmt.Sprintf("%x", sha256.Sum256(raw)),
+		Size:      int64(buf.Len()),
+		CreatedAt: createdAt,
+		Data:      buf.Bytes(),
+	}, nil
+}
+
+// Content decompresses and decodes the configuration saved in the snapshot.
+func (s *ConfigSnapshot) Content() (ConfigSnapshotContent, error) {
+	if s.Version != ConfigSnapshotVersion {
+		return ConfigSnapshotContent{}, fmt.Errorf("unsupported snapshot version %d", s.Version)
+	}
+	r, err := gzip.NewReader(bytes.NewReader(s.Data))
+	if err != nil {
+		return ConfigSnapshotContent{}, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return ConfigSnapshotContent{}, fmt.Errorf("failed to decompress snapshot: %w", err)
+	}
+	var content ConfigSnapshotContent
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return ConfigSnapshotContent{}, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return content, nil
+}
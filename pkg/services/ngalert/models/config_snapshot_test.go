@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSnapshot(t *testing.T) {
+	content := ConfigSnapshotContent{
+		AlertmanagerConfiguration: `{"alertmanager_config": {"route": {"receiver": "default"}}}`,
+		Rules: []AlertRule{
+			{OrgID: 1, UID: "rule-1", Title: "rule 1", Labels: map[string]string{"team": "ops"}},
+			{OrgID: 1, UID: "rule-2", Title: "rule 2"},
+		},
+	}
+
+	t.Run("content can be read back", func(t *testing.T) {
+		snapshot, err := NewConfigSnapshot(1, content, 100)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(snapshot.Data)), snapshot.Size)
+
+		result, err := snapshot.Content()
+		require.NoError(t, err)
+		require.Equal(t, content, result)
+	})
+
+	t.Run("snapshots of the same content have the same hash", func(t *testing.T) {
+		first, err := NewConfigSnapshot(1, content, 100)
+		require.NoError(t, err)
+		second, err := NewConfigSnapshot(1, content, 200)
+		require.NoError(t, err)
+		require.Equal(t, first.Hash, second.Hash)
+
+		changed := content
+		changed.Rules = content.Rules[:1]
+		third, err := NewConfigSnapshot(1, changed, 300)
+		require.NoError(t, err)
+		require.NotEqual(t, first.Hash, third.Hash)
+	})
+
+	t.Run("content of unknown versions is not read", func(t *testing.T) {
+		snapshot, err := NewConfigSnapshot(1, content, 100)
+		require.NoError(t, err)
+		snapshot.Version = ConfigSnapshotVersion + 1
+
+		_, err = snapshot.Content()
+		require.ErrorContains(t, err, "unsupported snapshot version")
+	})
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"errors"
+
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
@@ -9,8 +11,79 @@ var (
 	ErrAlertRuleConflictBase = errutil.Conflict("alerting.alert-rule.conflict").
 					MustTemplate(errAlertRuleConflictMsg, errutil.WithPublic(errAlertRuleConflictMsg))
 	ErrAlertRuleGroupNotFound = errutil.NotFound("alerting.alert-rule.notFound")
+
+	errAlertRuleGroupExistsMsg  = "rule group '{{ .Public.RuleGroup }}' already exists in folder '{{ .Public.NamespaceUID }}'"
+	ErrAlertRuleGroupExistsBase = errutil.Conflict("alerting.alert-rule.groupExists").
+					MustTemplate(errAlertRuleGroupExistsMsg, errutil.WithPublic(errAlertRuleGroupExistsMsg))
+
+	errAlertRuleMoveConflictMsg  = "alert rule '{{ .Public.RuleUID }}' belongs to rule group '{{ .Public.RuleGroup }}' in folder '{{ .Public.NamespaceUID }}', replacing rule group '{{ .Public.TargetRuleGroup }}' in folder '{{ .Public.TargetNamespaceUID }}' with it would move it"
+	ErrAlertRuleMoveConflictBase = errutil.Conflict("alerting.alert-rule.moveConflict").
+					MustTemplate(errAlertRuleMoveConflictMsg, errutil.WithPublic(errAlertRuleMoveConflictMsg))
+
+	errAlertRuleGroupTooManyChangesMsg  = "rule group update contains {{ .Public.Changes }} changes, which exceeds the limit of {{ .Public.Limit }} changes per update, split it into smaller updates or apply it with a rule group job"
+	ErrAlertRuleGroupTooManyChangesBase = errutil.BadRequest("alerting.alert-rule.tooManyChanges").
+						MustTemplate(errAlertRuleGroupTooManyChangesMsg, errutil.WithPublic(errAlertRuleGroupTooManyChangesMsg))
+
+	errAlertRuleTooLargeMsg  = "alert rule '{{ .Public.RuleUID }}' is {{ .Public.Size }} bytes long, which exceeds the limit of {{ .Public.Limit }} bytes"
+	ErrAlertRuleTooLargeBase = errutil.BadRequest("alerting.alert-rule.tooLarge").
+					MustTemplate(errAlertRuleTooLargeMsg, errutil.WithPublic(errAlertRuleTooLargeMsg))
+	errAlertRuleTooManyQueriesMsg  = "alert rule '{{ .Public.RuleUID }}' has {{ .Public.Queries }} queries and expressions, which exceeds the limit of {{ .Public.Limit }}"
+	ErrAlertRuleTooManyQueriesBase = errutil.BadRequest("alerting.alert-rule.tooManyQueries").
+					MustTemplate(errAlertRuleTooManyQueriesMsg, errutil.WithPublic(errAlertRuleTooManyQueriesMsg))
+	errAlertRuleExpressionTooDeepMsg  = "alert rule '{{ .Public.RuleUID }}' has a chain of {{ .Public.Depth }} expressions, which exceeds the limit of {{ .Public.Limit }}"
+	ErrAlertRuleExpressionTooDeepBase = errutil.BadRequest("alerting.alert-rule.expressionTooDeep").
+						MustTemplate(errAlertRuleExpressionTooDeepMsg, errutil.WithPublic(errAlertRuleExpressionTooDeepMsg))
+
+	errAlertRuleTitleNotUniqueMsg  = "alert rule title '{{ .Public.Title }}' of rule '{{ .Public.RuleUID }}' is already used by rule '{{ .Public.ConflictingRuleUID }}' in the same {{ .Public.Scope }}"
+	ErrAlertRuleTitleNotUniqueBase = errutil.Conflict("alerting.alert-rule.titleNotUnique").
+					MustTemplate(errAlertRuleTitleNotUniqueMsg, errutil.WithPublic(errAlertRuleTitleNotUniqueMsg))
 )
 
 func ErrAlertRuleConflict(rule AlertRule, underlying error) error {
 	return ErrAlertRuleConflictBase.Build(errutil.TemplateData{Public: map[string]any{"RuleUID": rule.UID, "Title": rule.Title, "NamespaceUID": rule.NamespaceUID, "Error": underlying.Error()}, Error: underlying})
 }
+
+// ErrAlertRuleGroupExists returns an error for a rule group that cannot be created because it already exists.
+func ErrAlertRuleGroupExists(key AlertRuleGroupKey) error {
+	return ErrAlertRuleGroupExistsBase.Build(errutil.TemplateData{Public: map[string]any{"RuleGroup": key.RuleGroup, "NamespaceUID": key.NamespaceUID}})
+}
+
+// ErrAlertRuleMoveConflict returns an error for a rule that belongs to another group than the target group it is
+// written to, when rules must not be moved between groups. Both locations of the rule are in the public payload.
+func ErrAlertRuleMoveConflict(rule AlertRule, target AlertRuleGroupKey) error {
+	return ErrAlertRuleMoveConflictBase.Build(errutil.TemplateData{Public: map[string]any{
+		"RuleUID":            rule.UID,
+		"NamespaceUID":       rule.NamespaceUID,
+		"RuleGroup":          rule.RuleGroup,
+		"TargetNamespaceUID": target.NamespaceUID,
+		"TargetRuleGroup":    target.RuleGroup,
+	}})
+}
+
+func ErrAlertRuleGroupTooManyChanges(changes int, limit int64) error {
+	return ErrAlertRuleGroupTooManyChangesBase.Build(errutil.TemplateData{Public: map[string]any{"Changes": changes, "Limit": limit}})
+}
+
+// ErrAlertRuleTitleNotUnique returns an error for a rule whose title is already used by the conflicting rule within the
+// scope of the title uniqueness policy.
+func ErrAlertRuleTitleNotUnique(rule AlertRule, conflicting AlertRule, scope string) error {
+	return ErrAlertRuleTitleNotUniqueBase.Build(errutil.TemplateData{Public: map[string]any{"RuleUID": rule.UID, "Title": rule.Title, "ConflictingRuleUID": conflicting.UID, "Scope": scope}})
+}
+
+func ErrAlertRuleTooLarge(rule AlertRule, size int, limit int) error {
+	return ErrAlertRuleTooLargeBase.Build(errutil.TemplateData{Public: map[string]any{"RuleUID": rule.UID, "Size": size, "Limit": limit}})
+}
+
+func ErrAlertRuleTooManyQueries(rule AlertRule, queries int, limit int) error {
+	return ErrAlertRuleTooManyQueriesBase.Build(errutil.TemplateData{Public: map[string]any{"RuleUID": rule.UID, "Queries": queries, "Limit": limit}})
+}
+
+func ErrAlertRuleExpressionTooDeep(rule AlertRule, depth int, limit int) error {
+	return ErrAlertRuleExpressionTooDeepBase.Build(errutil.TemplateData{Public: map[string]any{"RuleUID": rule.UID, "Depth": depth, "Limit": limit}})
+}
+
+// IsErrAlertRuleLimitExceeded returns true if the error is caused by an alert rule that exceeds the limits of its size
+// or complexity.
+func IsErrAlertRuleLimitExceeded(err error) bool {
+	return errors.Is(err, ErrAlertRuleTooLargeBase) || errors.Is(err, ErrAlertRuleTooManyQueriesBase) || errors.Is(err, ErrAlertRuleExpressionTooDeepBase)
+}
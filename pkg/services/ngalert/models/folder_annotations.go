@@ -0,0 +1,50 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateFolderAnnotations checks the default annotations of a folder. They are merged into the annotations of the
+// rules of the folder when the rules are written through the provisioning API.
+func ValidateFolderAnnotations(annotations map[string]string) error {
+	for key := range annotations {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("%w: folder annotation name must not be empty", ErrAlertRuleFailedValidation)
+		}
+	}
+	return nil
+}
+
+// MergeFolderAnnotations returns the annotations of a rule with the default annotations of its folder that the rule
+// does not set. The annotations of the rule are not modified.
+func MergeFolderAnnotations(annotations, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return annotations
+	}
+	result := make(map[string]string, len(annotations)+len(defaults))
+	for key, value := range defaults {
+		result[key] = value
+	}
+	for key, value := range annotations {
+		result[key] = value
+	}
+	return result
+}
+
+// StripFolderAnnotations returns the annotations of a rule without the ones that have the value of the default
+// annotation of its folder, so that exported rules do not repeat the defaults. The annotations of the rule are not
+// modified.
+func StripFolderAnnotations(annotations, defaults map[string]string) map[string]string {
+	if len(defaults) == 0 || len(annotations) == 0 {
+		return annotations
+	}
+	result := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		if d, ok := defaults[key]; ok && d == value {
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
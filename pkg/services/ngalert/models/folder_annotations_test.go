@@ -0,0 +1,27 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderAnnotations(t *testing.T) {
+	defaults := map[string]string{"runbook_base_url": "https://runbooks.example.com", "oncall_team": "sre"}
+	annotations := map[string]string{"summary": "high latency", "oncall_team": "db"}
+
+	merged := MergeFolderAnnotations(annotations, defaults)
+	require.Equal(t, map[string]string{
+		"summary":          "high latency",
+		"oncall_team":      "db",
+		"runbook_base_url": "https://runbooks.example.com",
+	}, merged, "annotations of the rule should take precedence over the defaults")
+	require.Len(t, annotations, 2, "annotations of the rule should not be modified")
+
+	require.Equal(t, annotations, StripFolderAnnotations(merged, defaults), "stripping should revert merging")
+	require.Equal(t, annotations, MergeFolderAnnotations(annotations, nil))
+	require.Equal(t, annotations, StripFolderAnnotations(annotations, nil))
+
+	require.NoError(t, ValidateFolderAnnotations(defaults))
+	require.ErrorIs(t, ValidateFolderAnnotations(map[string]string{" ": "value"}), ErrAlertRuleFailedValidation)
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// IncidentHookAction is the reason an incident hook is called.
+type IncidentHookAction string
+
+const (
+	// IncidentHookActionCreate is sent when an alert starts firing, e.g. to open a ticket.
+	IncidentHookActionCreate IncidentHookAction = "create"
+	// IncidentHookActionResolve is sent when a firing alert is resolved, e.g. to close its ticket.
+	IncidentHookActionResolve IncidentHookAction = "resolve"
+)
+
+// IncidentHook is an external incident-management tool that is called when the alerts of the rules of a group start
+// firing and when they are resolved. The hooks are set on all rules of the group.
+type IncidentHook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// AuthorizationCredentials are sent as a Bearer token. They must be a secret reference, e.g.
+	// ref+vault://secret/data/alerting/incidents#token, so that secrets are neither stored nor exported with the rules.
+	AuthorizationCredentials string `json:"authorizationCredentials,omitempty"`
+}
+
+// Validate checks that the hook has a name and an absolute HTTP URL.
+func (h IncidentHook) Validate() error {
+	if h.Name == "" {
+		return errors.New("name must be specified")
+	}
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL '%s' of hook '%s' must be an absolute HTTP URL", h.URL, h.Name)
+	}
+	return nil
+}
+
+// ValidateIncidentHooks checks that each of the incident hooks of a group is valid, and that their names are unique.
+func ValidateIncidentHooks(hooks []IncidentHook) error {
+	names := make(map[string]struct{}, len(hooks))
+	for _, h := range hooks {
+		if err := h.Validate(); err != nil {
+			return fmt.Errorf("%w: invalid incident hook: %w", ErrAlertRuleFailedValidation, err)
+		}
+		if _, ok := names[h.Name]; ok {
+			return fmt.Errorf("%w: incident hook '%s' is defined more than once", ErrAlertRuleFailedValidation, h.Name)
+		}
+		names[h.Name] = struct{}{}
+	}
+	return nil
+}
@@ -87,6 +87,54 @@ func (s *NotificationSettings) ToLabels() data.Labels {
 	return result
 }
 
+// NotificationSettingsToLabels converts the notification settings of a rule into data.Labels. A single setting is
+// converted by NotificationSettings.ToLabels. Alerts of rules with many settings are routed to all their receivers by
+// an autogenerated route that matches the fingerprint of all the settings, so the labels returned are:
+//   - AutogeneratedRouteLabel: "true"
+//   - AutogeneratedRouteSettingsHashLabel: NotificationSettingsFingerprint
+func NotificationSettingsToLabels(settings []NotificationSettings) data.Labels {
+	switch len(settings) {
+	case 0:
+		return nil
+	case 1:
+		return settings[0].ToLabels()
+	}
+	return data.Labels{
+		AutogeneratedRouteLabel:             "true",
+		AutogeneratedRouteSettingsHashLabel: NotificationSettingsFingerprint(settings).String(),
+	}
+}
+
+// NotificationSettingsFingerprint calculates a hash value to uniquely identify the notification settings of a rule
+// with many settings. It never equals the fingerprint of a single NotificationSettings.
+func NotificationSettingsFingerprint(settings []NotificationSettings) data.Fingerprint {
+	h := fnv.New64()
+	tmp := make([]byte, 8)
+	// use an invalid utf-8 sequence as prefix, so that the hash differs from the one of a single setting.
+	_, _ = h.Write([]byte{255, 255})
+	for _, s := range settings {
+		binary.LittleEndian.PutUint64(tmp, uint64(s.Fingerprint()))
+		_, _ = h.Write(tmp)
+	}
+	return data.Fingerprint(h.Sum64())
+}
+
+// ValidateNotificationSettings checks that each of the notification settings of a rule is valid, and that they do
+// not target the same receiver more than once.
+func ValidateNotificationSettings(settings []NotificationSettings) error {
+	receivers := make(map[string]struct{}, len(settings))
+	for _, s := range settings {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if _, ok := receivers[s.Receiver]; ok {
+			return fmt.Errorf("receiver '%s' is used by more than one notification settings", s.Receiver)
+		}
+		receivers[s.Receiver] = struct{}{}
+	}
+	return nil
+}
+
 func (s *NotificationSettings) Equals(other *NotificationSettings) bool {
 	durationEqual := func(d1, d2 *model.Duration) bool {
 		if d1 == nil || d2 == nil {
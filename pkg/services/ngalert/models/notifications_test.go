@@ -143,3 +143,28 @@ func TestNotificationSettingsLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationSettingsToLabels(t *testing.T) {
+	first := NewDefaultNotificationSettings("receiver1")
+	second := CopyNotificationSettings(NewDefaultNotificationSettings("receiver2"), NSMuts.WithGroupWait(util.Pointer(1*time.Minute)))
+
+	require.Nil(t, NotificationSettingsToLabels(nil))
+	require.Equal(t, first.ToLabels(), NotificationSettingsToLabels([]NotificationSettings{first}))
+
+	labels := NotificationSettingsToLabels([]NotificationSettings{first, second})
+	require.Equal(t, data.Labels{
+		AutogeneratedRouteLabel:             "true",
+		AutogeneratedRouteSettingsHashLabel: NotificationSettingsFingerprint([]NotificationSettings{first, second}).String(),
+	}, labels)
+	require.NotEqual(t, labels[AutogeneratedRouteSettingsHashLabel], NotificationSettingsToLabels([]NotificationSettings{second, first})[AutogeneratedRouteSettingsHashLabel])
+}
+
+func TestValidateNotificationSettings(t *testing.T) {
+	first := NewDefaultNotificationSettings("receiver1")
+	second := NewDefaultNotificationSettings("receiver2")
+
+	require.NoError(t, ValidateNotificationSettings(nil))
+	require.NoError(t, ValidateNotificationSettings([]NotificationSettings{first, second}))
+	require.ErrorContains(t, ValidateNotificationSettings([]NotificationSettings{first, first}), "more than one notification settings")
+	require.ErrorContains(t, ValidateNotificationSettings([]NotificationSettings{first, {}}), "receiver must be specified")
+}
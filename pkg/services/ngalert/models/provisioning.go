@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
 type Provenance string
 
 const (
@@ -15,3 +22,89 @@ type Provisionable interface {
 	ResourceType() string
 	ResourceID() string
 }
+
+// ProvenanceMetadata contains optional information about the source of a provisioned resource. It allows to
+// distinguish resources written with the same provenance by different sources, such as two Terraform workspaces.
+type ProvenanceMetadata struct {
+	// Source identifies the writer of the resource, e.g. a Terraform workspace or a provisioning file.
+	Source string `json:"source,omitempty"`
+	// ExternalID is the identifier of the resource in the source.
+	ExternalID string `json:"externalId,omitempty"`
+	// Hash is the hash of the payload the resource was provisioned from.
+	Hash string `json:"hash,omitempty"`
+}
+
+// ManagedBy is a human-readable description of the source that manages a provisioned resource, with a link to it.
+// The UI shows it to the users who cannot change the resource because of its provenance.
+type ManagedBy struct {
+	// Title describes the source, e.g. "Managed by repository alerting-rules, file teams/checkout.yaml".
+	Title string `json:"title"`
+	// URL links to the source, e.g. the file in the repository. It is optional.
+	URL string `json:"url,omitempty"`
+}
+
+// Equal returns true if both are nil or have the same title and URL.
+func (m *ManagedBy) Equal(other *ManagedBy) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	return *m == *other
+}
+
+const (
+	// MaxManagedByTitleLength is the maximum length of the title of a ManagedBy.
+	MaxManagedByTitleLength = 190
+	// MaxManagedByURLLength is the maximum length of the URL of a ManagedBy.
+	MaxManagedByURLLength = 2048
+)
+
+// ValidateManagedBy checks that the title is not empty and that the URL, if it is set, is an absolute HTTP or HTTPS
+// URL. A nil ManagedBy is valid.
+func ValidateManagedBy(m *ManagedBy) error {
+	if m == nil {
+		return nil
+	}
+	if strings.TrimSpace(m.Title) == "" {
+		return fmt.Errorf("%w: title of managed by is required", ErrAlertRuleFailedValidation)
+	}
+	if len(m.Title) > MaxManagedByTitleLength {
+		return fmt.Errorf("%w: title of managed by is longer than %d characters", ErrAlertRuleFailedValidation, MaxManagedByTitleLength)
+	}
+	if m.URL == "" {
+		return nil
+	}
+	if len(m.URL) > MaxManagedByURLLength {
+		return fmt.Errorf("%w: URL of managed by is longer than %d characters", ErrAlertRuleFailedValidation, MaxManagedByURLLength)
+	}
+	u, err := url.Parse(m.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: URL of managed by '%s' must be an absolute http or https URL", ErrAlertRuleFailedValidation, m.URL)
+	}
+	return nil
+}
+
+// MaxResourceTagLength is the maximum length of a tag of a provisioned resource.
+const MaxResourceTagLength = 100
+
+// NormalizeResourceTags trims the tags of a provisioned resource, and returns them sorted and without duplicates.
+// Tags are free-form strings that help to organize provisioned resources. Unlike labels, they are not part of alerts.
+func NormalizeResourceTags(tags []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("tag cannot be empty")
+		}
+		if len(tag) > MaxResourceTagLength {
+			return nil, fmt.Errorf("tag '%s' is longer than %d characters", tag, MaxResourceTagLength)
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result, nil
+}
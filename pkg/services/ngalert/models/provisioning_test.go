@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeResourceTags(t *testing.T) {
+	t.Run("tags are trimmed, sorted and deduplicated", func(t *testing.T) {
+		tags, err := NormalizeResourceTags([]string{" canary", "team-a", "canary "})
+		require.NoError(t, err)
+		require.Equal(t, []string{"canary", "team-a"}, tags)
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		tags, err := NormalizeResourceTags(nil)
+		require.NoError(t, err)
+		require.Empty(t, tags)
+	})
+
+	t.Run("empty tag is rejected", func(t *testing.T) {
+		_, err := NormalizeResourceTags([]string{"canary", " "})
+		require.Error(t, err)
+	})
+
+	t.Run("long tag is rejected", func(t *testing.T) {
+		_, err := NormalizeResourceTags([]string{strings.Repeat("a", MaxResourceTagLength+1)})
+		require.Error(t, err)
+	})
+}
+
+func TestValidateManagedBy(t *testing.T) {
+	t.Run("nil is valid", func(t *testing.T) {
+		require.NoError(t, ValidateManagedBy(nil))
+	})
+
+	t.Run("title with or without URL is valid", func(t *testing.T) {
+		require.NoError(t, ValidateManagedBy(&ManagedBy{Title: "Managed by repository alerting-rules"}))
+		require.NoError(t, ValidateManagedBy(&ManagedBy{Title: "Managed by repository alerting-rules", URL: "https://github.com/example/alerting-rules"}))
+	})
+
+	t.Run("empty title is rejected", func(t *testing.T) {
+		err := ValidateManagedBy(&ManagedBy{Title: " ", URL: "https://github.com/example/alerting-rules"})
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("long title is rejected", func(t *testing.T) {
+		err := ValidateManagedBy(&ManagedBy{Title: strings.Repeat("a", MaxManagedByTitleLength+1)})
+		require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("URL that is not an absolute http or https URL is rejected", func(t *testing.T) {
+		for _, u := range []string{"/alerting-rules", "github.com/example/alerting-rules", "javascript:alert(1)", "ftp://example.com/rules"} {
+			err := ValidateManagedBy(&ManagedBy{Title: "Managed by repository alerting-rules", URL: u})
+			require.ErrorIs(t, err, ErrAlertRuleFailedValidation, u)
+		}
+	})
+}
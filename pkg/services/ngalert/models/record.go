@@ -0,0 +1,71 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	prommodel "github.com/prometheus/common/model"
+)
+
+// Record makes an alert rule a recording rule: instead of firing alerts, the rule writes the result of one of its
+// queries or expressions as a series to a Prometheus-compatible data source. The zero Record is the record of the
+// alert rules that are not recording rules.
+type Record struct {
+	// Metric is the name of the metric of the written series.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression whose result is written.
+	From string `json:"from"`
+	// TargetDatasourceUID is the UID of the data source the series are written to.
+	TargetDatasourceUID string `json:"targetDatasourceUid"`
+}
+
+// Validate checks that the metric name is a valid Prometheus metric name, that From is one of the queries or
+// expressions of the rule, and that the record has a target data source.
+func (r *Record) Validate(data []AlertQuery) error {
+	if !prommodel.IsValidMetricName(prommodel.LabelValue(r.Metric)) {
+		return fmt.Errorf("%w: '%s' is not a valid metric name", ErrAlertRuleFailedValidation, r.Metric)
+	}
+	found := false
+	for _, q := range data {
+		if q.RefID == r.From {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: recorded query or expression '%s' is not one of the queries and expressions of the rule", ErrAlertRuleFailedValidation, r.From)
+	}
+	if r.TargetDatasourceUID == "" {
+		return fmt.Errorf("%w: target data source of the recording rule is required", ErrAlertRuleFailedValidation)
+	}
+	return nil
+}
+
+// FromDB implements the xorm conversion of the record column, which stores the record as JSON. Empty data, which xorm
+// writes for the zero Record, is the zero Record.
+func (r *Record) FromDB(data []byte) error {
+	if len(data) == 0 {
+		*r = Record{}
+		return nil
+	}
+	return json.Unmarshal(data, r)
+}
+
+// ToDB implements the xorm conversion of the record column. The zero Record is stored empty.
+func (r *Record) ToDB() ([]byte, error) {
+	if *r == (Record{}) {
+		return nil, nil
+	}
+	return json.Marshal(r)
+}
+
+// Value implements driver.Valuer, which xorm uses to write the record of the rules that it is not given a pointer to,
+// since it finds the conversion of ToDB only through a pointer. The zero Record is written as NULL.
+func (r Record) Value() (driver.Value, error) {
+	data, err := r.ToDB()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return string(data), nil
+}
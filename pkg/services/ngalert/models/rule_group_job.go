@@ -0,0 +1,40 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrRuleGroupJobNotFound is returned when a rule group job does not exist.
+var ErrRuleGroupJobNotFound = errors.New("rule group job not found")
+
+type RuleGroupJobStatus string
+
+const (
+	RuleGroupJobStatusPending   RuleGroupJobStatus = "pending"
+	RuleGroupJobStatusRunning   RuleGroupJobStatus = "running"
+	RuleGroupJobStatusSucceeded RuleGroupJobStatus = "succeeded"
+	RuleGroupJobStatusFailed    RuleGroupJobStatus = "failed"
+)
+
+// RuleGroupJob is a replacement of a rule group that is applied in the background, in several transactions of a
+// limited number of changes each, because its delta exceeds the number of changes allowed in a single update.
+type RuleGroupJob struct {
+	UID          string             `json:"uid"`
+	OrgID        int64              `json:"orgId"`
+	NamespaceUID string             `json:"namespaceUid"`
+	RuleGroup    string             `json:"ruleGroup"`
+	Status       RuleGroupJobStatus `json:"status"`
+	// Changes is the number of rules created, updated and deleted by the job, as calculated when it was started.
+	Changes int `json:"changes"`
+	// Applied is the number of changes that are committed.
+	Applied int       `json:"applied"`
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// IsDone returns true if the job does not run anymore.
+func (j RuleGroupJob) IsDone() bool {
+	return j.Status == RuleGroupJobStatusSucceeded || j.Status == RuleGroupJobStatusFailed
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const ruleGroupVersionPrefix = "v1."
+
+// RuleGroupVersion identifies the state of the rules of a rule group by the versions of its rules, keyed by their UID.
+// It is given to clients as an opaque token, so that they can tell the state of the group they based their changes on
+// when they write it back. See AlertRuleGroup.BaseVersion.
+type RuleGroupVersion map[string]int64
+
+// NewRuleGroupVersion returns the version of the group of the rules.
+func NewRuleGroupVersion(rules []AlertRule) RuleGroupVersion {
+	v := make(RuleGroupVersion, len(rules))
+	for _, r := range rules {
+		v[r.UID] = r.Version
+	}
+	return v
+}
+
+// String returns the version as a token. Tokens of equal versions are equal.
+func (v RuleGroupVersion) String() string {
+	uids := make([]string, 0, len(v))
+	for uid := range v {
+		uids = append(uids, uid)
+	}
+	slices.Sort(uids)
+	entries := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		entries = append(entries, uid+":"+strconv.FormatInt(v[uid], 10))
+	}
+	return ruleGroupVersionPrefix + base64.RawURLEncoding.EncodeToString([]byte(strings.Join(entries, ",")))
+}
+
+// ParseRuleGroupVersion parses a token returned by RuleGroupVersion.String.
+func ParseRuleGroupVersion(s string) (RuleGroupVersion, error) {
+	encoded, ok := strings.CutPrefix(s, ruleGroupVersionPrefix)
+	if !ok {
+		return nil, errors.New("invalid rule group version: unknown format")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rule group version: %w", err)
+	}
+	v := RuleGroupVersion{}
+	if len(b) == 0 {
+		return v, nil
+	}
+	for _, entry := range strings.Split(string(b), ",") {
+		uid, version, ok := strings.Cut(entry, ":")
+		if !ok || uid == "" {
+			return nil, fmt.Errorf("invalid rule group version: invalid entry '%s'", entry)
+		}
+		n, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule group version: invalid version of rule '%s': %w", uid, err)
+		}
+		v[uid] = n
+	}
+	return v, nil
+}
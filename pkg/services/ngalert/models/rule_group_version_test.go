@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleGroupVersion(t *testing.T) {
+	t.Run("should parse the token of the version", func(t *testing.T) {
+		v := NewRuleGroupVersion([]AlertRule{{UID: "b", Version: 3}, {UID: "a", Version: 1}})
+		parsed, err := ParseRuleGroupVersion(v.String())
+		require.NoError(t, err)
+		require.Equal(t, RuleGroupVersion{"a": 1, "b": 3}, parsed)
+	})
+
+	t.Run("should not depend on the order of the rules", func(t *testing.T) {
+		a := NewRuleGroupVersion([]AlertRule{{UID: "a", Version: 1}, {UID: "b", Version: 3}})
+		b := NewRuleGroupVersion([]AlertRule{{UID: "b", Version: 3}, {UID: "a", Version: 1}})
+		require.Equal(t, a.String(), b.String())
+		require.NotEqual(t, a.String(), NewRuleGroupVersion([]AlertRule{{UID: "a", Version: 2}, {UID: "b", Version: 3}}).String())
+	})
+
+	t.Run("should parse the version of an empty group", func(t *testing.T) {
+		parsed, err := ParseRuleGroupVersion(NewRuleGroupVersion(nil).String())
+		require.NoError(t, err)
+		require.Empty(t, parsed)
+	})
+
+	t.Run("should reject invalid tokens", func(t *testing.T) {
+		for _, token := range []string{"", "abc", "v1.!!", "v1.YQ", "v1.YTp4"} {
+			_, err := ParseRuleGroupVersion(token)
+			require.Errorf(t, err, "token %q", token)
+		}
+	})
+}
@@ -408,8 +408,28 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		IsPaused:        r.IsPaused,
+
+		DataAvailabilityPeriod:  r.DataAvailabilityPeriod,
+		DataAvailabilityDelay:   r.DataAvailabilityDelay,
+		ShardAffinity:           r.ShardAffinity,
+		IntervalOverrideSeconds: r.IntervalOverrideSeconds,
+		EvaluationTimeout:       r.EvaluationTimeout,
+		Record:                  r.Record,
 	}
 
+	if r.IncidentHooks != nil {
+		result.IncidentHooks = make([]IncidentHook, len(r.IncidentHooks))
+		copy(result.IncidentHooks, r.IncidentHooks)
+	}
+	if r.BakeUntil != nil {
+		bakeUntil := *r.BakeUntil
+		result.BakeUntil = &bakeUntil
+	}
+	if r.ExpiresAt != nil {
+		expiresAt := *r.ExpiresAt
+		result.ExpiresAt = &expiresAt
+	}
 	if r.DashboardUID != nil {
 		dash := *r.DashboardUID
 		result.DashboardUID = &dash
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrWebhookDeliveryNotFound is returned when a delivery of the provisioning webhook does not exist.
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+// WebhookDelivery is an event of the provisioning webhook. It is stored until it is delivered, and stored as a dead
+// letter if it cannot be delivered, so that it can be replayed.
+type WebhookDelivery struct {
+	UID   string `json:"uid"`
+	OrgID int64  `json:"orgId"`
+	// Type is the type of the event, e.g. alert_rule_created.
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+	// Attempts is the number of times the event was posted.
+	Attempts int `json:"attempts"`
+	// Error is the error of the last attempt.
+	Error   string    `json:"error,omitempty"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/annotations"
@@ -27,6 +28,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/guardian"
+	ngac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/api"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
@@ -43,6 +45,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/pluginstore"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/secrets"
@@ -147,6 +150,13 @@ type AlertNG struct {
 	folderService       folder.Service
 	dashboardService    dashboards.DashboardService
 	api                 *api.API
+	configSnapshots     *provisioning.ConfigSnapshotService
+	ruleExpiry          *provisioning.RuleExpiryService
+	ruleTrashCleanup    *provisioning.RuleTrashCleanup
+	provisioningWebhook *provisioning.ProvisioningWebhook
+	ruleSync            *provisioning.RuleSyncService
+	incidentHooks       *notifier.IncidentHookSender
+	ruleGroupJobs       *provisioning.RuleGroupJobService
 
 	// Alerting notification services
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
@@ -267,6 +277,8 @@ func (ng *AlertNG) init() error {
 
 	ng.AlertsRouter = alertsRouter
 
+	ng.incidentHooks = notifier.NewIncidentHookSender(ng.Cfg.UnifiedAlerting.SecretReferences, ng.NotificationService, moa.Peer(), log.New("ngalert.incident-hooks"))
+
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
 	schedCfg := schedule.SchedulerCfg{
 		MaxAttempts:          ng.Cfg.UnifiedAlerting.MaxAttempts,
@@ -275,11 +287,14 @@ func (ng *AlertNG) init() error {
 		MinRuleInterval:      ng.Cfg.UnifiedAlerting.MinInterval,
 		DisableGrafanaFolder: ng.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
 		JitterEvaluations:    schedule.JitterStrategyFrom(ng.Cfg.UnifiedAlerting, ng.FeatureToggles),
+		Shard:                ng.Cfg.UnifiedAlerting.SchedulerShard,
+		Shards:               ng.Cfg.UnifiedAlerting.SchedulerShards,
 		AppURL:               appUrl,
 		EvaluatorFactory:     evalFactory,
 		RuleStore:            ng.store,
 		Metrics:              ng.Metrics.GetSchedulerMetrics(),
 		AlertSender:          alertsRouter,
+		IncidentHookSender:   ng.incidentHooks,
 		Tracer:               ng.tracer,
 		Log:                  log.New("ngalert.scheduler"),
 	}
@@ -322,17 +337,52 @@ func (ng *AlertNG) init() error {
 	ng.stateManager = stateManager
 	ng.schedule = scheduler
 
+	folderAuthzCache := ngac.NewFolderAuthorizationCache(0)
+	subscribeToAuthorizationChanges(ng.Log, ng.bus, folderAuthzCache)
+
+	ruleAuthz := ngac.NewRuleServiceWithCache(ng.accesscontrol, folderAuthzCache)
 	receiverService := notifier.NewReceiverService(ng.accesscontrol, ng.store, ng.store, ng.SecretsService, ng.store, ng.Log)
 
 	// Provisioning
 	policyService := provisioning.NewNotificationPolicyService(ng.store, ng.store, ng.store, ng.Cfg.UnifiedAlerting, ng.Log)
-	contactPointService := provisioning.NewContactPointService(ng.store, ng.SecretsService, ng.store, ng.store, receiverService, ng.Log, ng.store)
+	contactPointService := provisioning.NewContactPointService(ng.store, ng.SecretsService, ng.store, ng.store, receiverService, ng.Log, ng.store, ng.store, ng.Cfg.UnifiedAlerting.SecretReferences)
 	templateService := provisioning.NewTemplateService(ng.store, ng.store, ng.store, ng.Log)
 	muteTimingService := provisioning.NewMuteTimingService(ng.store, ng.store, ng.store, ng.Log)
-	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
-		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
-		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
-		ng.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit, ng.Log, notifier.NewNotificationSettingsValidationService(ng.store))
+	alertmanagerRoutingService := provisioning.NewAlertmanagerRoutingService(ng.store, ng.store, ng.store, ng.Log)
+	alertRuleService, err := provisioning.NewAlertRuleService(provisioning.AlertRuleServiceConfig{
+		RuleStore:            ng.store,
+		ProvenanceStore:      ng.store,
+		DashboardService:     ng.dashboardService,
+		Quotas:               ng.QuotaService,
+		Xact:                 ng.store,
+		NotificationSettings: notifier.NewNotificationSettingsValidationService(ng.store),
+		Log:                  ng.Log,
+		KV:                   ng.KVStore,
+		TrashStore:           ng.store,
+		Authz:                ruleAuthz,
+		Events:               ng.bus,
+		Settings:             ng.Cfg.UnifiedAlerting,
+	})
+	if err != nil {
+		return err
+	}
+	bulkService := provisioning.NewBulkService(ng.store, alertRuleService, contactPointService, policyService, ng.store, ng.Log)
+	tagService := provisioning.NewTagService(ng.store, ng.store, ng.store, ng.store, ruleAuthz, ng.Log)
+	ruleUsageService := provisioning.NewRuleUsageService(ng.store, ng.store, history, ng.Log)
+	ng.configSnapshots = provisioning.NewConfigSnapshotService(ng.store, ng.store, ng.store, ng.store, ng.store, alertRuleService, ng.accesscontrol, ng.store,
+		serverlock.ProvideService(ng.SQLStore, ng.tracer), ng.Cfg.UnifiedAlerting, ng.Log)
+	ng.ruleTrashCleanup = provisioning.NewRuleTrashCleanup(alertRuleService, ng.Log)
+	ng.ruleGroupJobs = provisioning.NewRuleGroupJobService(alertRuleService, ng.KVStore, ng.Log)
+	ng.ruleExpiry = provisioning.NewRuleExpiryService(ng.store, ng.store, alertRuleService, alertsRouter, ng.store,
+		ng.Cfg.UnifiedAlerting, ng.Log)
+	ng.provisioningWebhook = provisioning.NewProvisioningWebhook(ng.Cfg.UnifiedAlerting.ProvisioningWebhook, ng.KVStore, ng.Log)
+	ng.provisioningWebhook.Subscribe(ng.bus)
+	var ruleSyncSource provisioning.RuleGroupSource
+	if ng.Cfg.UnifiedAlerting.RuleSync.Path != "" {
+		ruleSyncSource = alerting.NewRuleGroupDirectory(ng.Cfg.UnifiedAlerting.RuleSync.Path, ng.Cfg.UnifiedAlerting.ProvisioningStrictDecoding, ng.Log)
+	}
+	ng.ruleSync = provisioning.NewRuleSyncService(alertRuleService, ruleSyncSource, ng.KVStore,
+		serverlock.ProvideService(ng.SQLStore, ng.tracer), ng.Cfg.UnifiedAlerting.RuleSync, ng.Log)
 
 	ng.api = &api.API{
 		Cfg:                  ng.Cfg,
@@ -349,12 +399,22 @@ func (ng *AlertNG) init() error {
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
 		AccessControl:        ng.accesscontrol,
+		AccessControlService: ng.accesscontrolService,
+		FolderAuthzCache:     folderAuthzCache,
 		Policies:             policyService,
 		ReceiverService:      receiverService,
 		ContactPointService:  contactPointService,
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
+		AlertmanagerRouting:  alertmanagerRoutingService,
+		ConfigSnapshots:      ng.configSnapshots,
+		Bulk:                 bulkService,
+		Tags:                 tagService,
+		RuleUsage:            ruleUsageService,
+		RuleSync:             ng.ruleSync,
+		RuleGroupJobs:        ng.ruleGroupJobs,
+		ProvisioningWebhook:  ng.provisioningWebhook,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
 		FeatureManager:       ng.FeatureToggles,
@@ -395,6 +455,24 @@ func subscribeToFolderChanges(logger log.Logger, bus bus.Bus, dbStore api.RuleSt
 	})
 }
 
+// subscribeToAuthorizationChanges invalidates cached authorization decisions when folders are moved or their permissions
+// change, since permissions granted on a folder are inherited by its subfolders. Events are not shared between peers in
+// HA mode, their caches rely on the expiration of decisions.
+func subscribeToAuthorizationChanges(logger log.Logger, bus bus.Bus, cache *ngac.FolderAuthorizationCache) {
+	bus.AddEventListener(func(ctx context.Context, evt *events.FolderMoved) error {
+		logger.Debug("Got folder moved event. Invalidating cached authorization decisions of the org", "folderUID", evt.UID, "org", evt.OrgID)
+		cache.InvalidateOrg(evt.OrgID)
+		return nil
+	})
+	bus.AddEventListener(func(ctx context.Context, evt *events.FolderPermissionsChanged) error {
+		logger.Debug("Got folder permissions changed event. Invalidating cached authorization decisions of the org", "folderUID", evt.UID, "org", evt.OrgID)
+		cache.InvalidateOrg(evt.OrgID)
+		// The event is published with the context of the request that changed the permissions.
+		ngac.InvalidateRequestAuthorizationCache(ctx)
+		return nil
+	})
+}
+
 // shouldRun determines if AlertNG should init or run anything more than just the migration.
 func (ng *AlertNG) shouldRun() bool {
 	if ng.Cfg.UnifiedAlerting.IsEnabled() {
@@ -424,6 +502,27 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 	children.Go(func() error {
 		return ng.AlertsRouter.Run(subCtx)
 	})
+	children.Go(func() error {
+		return ng.configSnapshots.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.ruleExpiry.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.ruleTrashCleanup.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.provisioningWebhook.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.ruleSync.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.ruleGroupJobs.Run(subCtx)
+	})
+	children.Go(func() error {
+		return ng.incidentHooks.Run(subCtx)
+	})
 
 	// We explicitly check that UA is enabled here in case FlagAlertingPreviewUpgrade is enabled but UA is disabled.
 	if ng.Cfg.UnifiedAlerting.ExecuteAlerts && ng.Cfg.UnifiedAlerting.IsEnabled() {
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/folder"
+	ngac "github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
@@ -57,6 +58,37 @@ func Test_subscribeToFolderChanges(t *testing.T) {
 	}, time.Second, 10*time.Millisecond, "expected to call db store method but nothing was called")
 }
 
+func Test_subscribeToAuthorizationChanges(t *testing.T) {
+	orgID := rand.Int63()
+	testCases := map[string]bus.Msg{
+		"folder moved": &events.FolderMoved{
+			Timestamp: time.Now(),
+			UID:       util.GenerateShortUID(),
+			OrgID:     orgID,
+		},
+		"folder permissions changed": &events.FolderPermissionsChanged{
+			Timestamp: time.Now(),
+			UID:       util.GenerateShortUID(),
+			OrgID:     orgID,
+		},
+	}
+	for name, evt := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := ngac.NewFolderAuthorizationCache(time.Minute)
+			cache.Set(orgID, "user:1", "alert.rules:read", "folder", nil, true)
+
+			bus := bus.ProvideBus(tracing.InitializeTracerForTest())
+			subscribeToAuthorizationChanges(log.New("test"), bus, cache)
+
+			err := bus.Publish(context.Background(), evt)
+			require.NoError(t, err)
+
+			_, ok := cache.Get(orgID, "user:1", "alert.rules:read", "folder", nil)
+			require.False(t, ok, "expected decisions of the org to be invalidated")
+		})
+	}
+}
+
 func TestConfigureHistorianBackend(t *testing.T) {
 	t.Run("fail initialization if invalid backend", func(t *testing.T) {
 		met := metrics.NewHistorianMetrics(prometheus.NewRegistry(), metrics.Subsystem)
@@ -56,8 +56,11 @@ type alertmanager struct {
 	fileStore           *FileStore
 	NotificationService notifications.Service
 
-	decryptFn alertingNotify.GetDecryptedValueFn
-	orgID     int64
+	decryptFn      alertingNotify.GetDecryptedValueFn
+	secretResolver *secretResolver
+	// deliveries keeps the recent delivery attempts of the integrations across the applications of configurations.
+	deliveries *deliveryHistory
+	orgID      int64
 
 	withAutogen bool
 }
@@ -144,6 +147,8 @@ func NewAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		NotificationService: ns,
 		orgID:               orgID,
 		decryptFn:           decryptFn,
+		secretResolver:      newSecretResolver(cfg.UnifiedAlerting.SecretReferences),
+		deliveries:          newDeliveryHistory(deliveryHistorySize),
 		fileStore:           fileStore,
 		logger:              l,
 
@@ -333,6 +338,16 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 		return false, nil
 	}
 
+	buildReceiverIntegrations := func(r *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+		integrations, err := am.buildReceiverIntegrations(r, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		// The deliveries are recorded before the rate limits, so that only the notifications that are sent are.
+		integrations = withDeliveryHistory(r, integrations, am.deliveries, am.decryptFn)
+		return withRateLimits(r, integrations, cfg.RateLimits, am.logger), nil
+	}
+
 	am.logger.Info("Applying new configuration to Alertmanager", "configHash", fmt.Sprintf("%x", configHash))
 	err = am.Base.ApplyConfig(AlertingConfiguration{
 		rawAlertmanagerConfig:    rawConfig,
@@ -342,13 +357,21 @@ func (am *alertmanager) applyConfig(cfg *apimodels.PostableUserConfig) (bool, er
 		muteTimeIntervals:        cfg.AlertmanagerConfig.MuteTimeIntervals,
 		timeIntervals:            cfg.AlertmanagerConfig.TimeIntervals,
 		templates:                ToTemplateDefinitions(cfg),
-		receivers:                PostableApiAlertingConfigToApiReceivers(cfg.AlertmanagerConfig),
-		receiverIntegrationsFunc: am.buildReceiverIntegrations,
+		receivers:                RemoveDisabledIntegrations(PostableApiAlertingConfigToApiReceivers(cfg.AlertmanagerConfig), cfg.DisabledIntegrations),
+		receiverIntegrationsFunc: buildReceiverIntegrations,
 	})
 	if err != nil {
 		return false, err
 	}
 
+	uids := make(map[string]struct{})
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		for _, integration := range r.GrafanaManagedReceivers {
+			uids[integration.UID] = struct{}{}
+		}
+	}
+	am.deliveries.retain(uids)
+
 	am.updateConfigMetrics(cfg)
 	return true, nil
 }
@@ -371,17 +394,40 @@ func (am *alertmanager) applyAndMarkConfig(ctx context.Context, hash string, cfg
 	return nil
 }
 
+// DeliveryAttempts returns the recent attempts to deliver notifications through the integration, the most recent
+// first.
+func (am *alertmanager) DeliveryAttempts(integrationUID string) []DeliveryAttempt {
+	return am.deliveries.get(integrationUID)
+}
+
 func (am *alertmanager) AppURL() string {
 	return am.Settings.AppURL
 }
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
+// Secrets that refer to external secret managers are resolved when notifications are sent.
 func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
-	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
+	build := func(r *alertingNotify.APIReceiver, decryptFn alertingNotify.GetDecryptedValueFn) ([]*alertingNotify.Integration, error) {
+		return am.buildIntegrations(r, tmpl, decryptFn)
+	}
+	integrations, err := build(receiver, am.decryptFn)
+	if err != nil {
+		return nil, err
+	}
+	return withSecretReferences(am.orgID, receiver, integrations, am.secretResolver, am.decryptFn, build)
+}
+
+// buildIntegrations builds the integrations of the receiver, decrypting the secure settings with decryptFn.
+func (am *alertmanager) buildIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template, decryptFn alertingNotify.GetDecryptedValueFn) ([]*alertingNotify.Integration, error) {
+	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, decryptFn)
 	if err != nil {
 		return nil, err
 	}
 	s := &sender{am.NotificationService}
+	oauth2Senders, err := webhookSenders(context.Background(), receiver, s, notifications.WebhookHTTPClient(), decryptFn)
+	if err != nil {
+		return nil, err
+	}
 	img := newImageProvider(am.Store, log.New("ngalert.notifier.image-provider"))
 	integrations, err := alertingNotify.BuildReceiverIntegrations(
 		receiverCfg,
@@ -389,6 +435,9 @@ func (am *alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 		img,
 		LoggerFactory,
 		func(n receivers.Metadata) (receivers.WebhookSender, error) {
+			if ws, ok := oauth2Senders[n.UID]; ok {
+				return ws, nil
+			}
 			return s, nil
 		},
 		func(n receivers.Metadata) (receivers.EmailSender, error) {
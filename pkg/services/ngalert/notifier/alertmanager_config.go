@@ -150,6 +150,8 @@ func (moa *MultiOrgAlertmanager) gettableUserConfigFromAMConfigString(ctx contex
 		AlertmanagerConfig: definitions.GettableApiAlertingConfig{
 			Config: cfg.AlertmanagerConfig.Config,
 		},
+		DisabledIntegrations: cfg.DisabledIntegrations,
+		RateLimits:           cfg.RateLimits,
 	}
 	for _, recv := range cfg.AlertmanagerConfig.Receivers {
 		receivers := make([]*definitions.GettableGrafanaReceiver, 0, len(recv.PostableGrafanaReceivers.GrafanaManagedReceivers))
@@ -172,6 +174,8 @@ func (moa *MultiOrgAlertmanager) gettableUserConfigFromAMConfigString(ctx contex
 				DisableResolveMessage: pr.DisableResolveMessage,
 				Settings:              pr.Settings,
 				SecureFields:          secureFields,
+				Disabled:              cfg.IsIntegrationDisabled(pr.UID),
+				RateLimit:             cfg.GetRateLimit(recv.Name),
 			}
 			receivers = append(receivers, &gr)
 		}
@@ -209,6 +213,10 @@ func (moa *MultiOrgAlertmanager) SaveAndApplyAlertmanagerConfiguration(ctx conte
 		}
 	}
 
+	if err := checkSecretReferencesAllowed(config.AlertmanagerConfig.Receivers, moa.settings.UnifiedAlerting.SecretReferences.AllowedPrefixesForOrg(org)); err != nil {
+		return AlertmanagerConfigRejectedError{err}
+	}
+
 	if err := moa.Crypto.ProcessSecureSettings(ctx, org, config.AlertmanagerConfig.Receivers); err != nil {
 		return fmt.Errorf("failed to post process Alertmanager configuration: %w", err)
 	}
@@ -267,7 +275,11 @@ type provisioningStore interface {
 	GetProvenance(ctx context.Context, o models.Provisionable, org int64) (models.Provenance, error)
 	GetProvenances(ctx context.Context, org int64, resourceType string) (map[string]models.Provenance, error)
 	SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error
+	SetProvenances(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance) error
 	DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error
+	SetProvenanceWithMetadata(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata) error
+	GetProvenanceMetadata(ctx context.Context, o models.Provisionable, org int64) (*models.ProvenanceMetadata, error)
+	GetProvenancesMetadata(ctx context.Context, org int64, resourceType string) (map[string]models.ProvenanceMetadata, error)
 }
 
 func (moa *MultiOrgAlertmanager) mergeProvenance(ctx context.Context, config definitions.GettableUserConfig, org int64) (definitions.GettableUserConfig, error) {
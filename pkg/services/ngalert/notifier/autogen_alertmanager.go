@@ -54,8 +54,12 @@ func newAutogeneratedRoute[R receiver](ctx context.Context, logger log.Logger, s
 		notificationSettings[fp] = setting
 	}
 
+	// Rules with many settings send their alerts to all the receivers, they need a dedicated route.
+	multipleSettings := make(map[data.Fingerprint][]models.NotificationSettings)
+
 	validator := NewNotificationSettingsValidator(cfg)
 	for ruleKey, ruleSettings := range settings {
+		valid := make([]models.NotificationSettings, 0, len(ruleSettings))
 		for _, setting := range ruleSettings {
 			// TODO we should register this errors and somehow present to the users or make sure the config is always valid.
 			if err = validator.Validate(setting); err != nil {
@@ -65,6 +69,7 @@ func newAutogeneratedRoute[R receiver](ctx context.Context, logger log.Logger, s
 				}
 				return autogeneratedRoute{}, fmt.Errorf("invalid notification settings for rule %s: %w", ruleKey.UID, err)
 			}
+			valid = append(valid, setting)
 			fp := setting.Fingerprint()
 			// Keep only unique settings.
 			if _, ok := notificationSettings[fp]; ok {
@@ -72,11 +77,15 @@ func newAutogeneratedRoute[R receiver](ctx context.Context, logger log.Logger, s
 			}
 			notificationSettings[fp] = setting
 		}
+		if len(ruleSettings) > 1 && len(valid) > 0 {
+			// The alerts are labeled with the fingerprint of all the settings of the rule, including the invalid ones.
+			multipleSettings[models.NotificationSettingsFingerprint(ruleSettings)] = valid
+		}
 	}
 	if len(notificationSettings) == 0 {
 		return autogeneratedRoute{}, nil
 	}
-	newAutogenRoute, err := generateRouteFromSettings(cfg.GetRoute().Receiver, notificationSettings)
+	newAutogenRoute, err := generateRouteFromSettings(cfg.GetRoute().Receiver, notificationSettings, multipleSettings)
 	if err != nil {
 		return autogeneratedRoute{}, fmt.Errorf("failed to create autogenerated route: %w", err)
 	}
@@ -91,7 +100,11 @@ type autogeneratedRoute struct {
 //  1. with matcher by label models.AutogeneratedRouteLabel equals 'true'.
 //  2. with matcher by receiver name.
 //  3. with matcher by unique combination of optional settings. It is created only if there are optional settings.
-func generateRouteFromSettings(defaultReceiver string, settings map[data.Fingerprint]models.NotificationSettings) (autogeneratedRoute, error) {
+//
+// Rules with many settings get a route in the second layer that matches the fingerprint of all their settings, before
+// the routes by receiver name. It has a child route for each setting that continues to the next one, so that the
+// alerts are sent to all the receivers, each with its own settings.
+func generateRouteFromSettings(defaultReceiver string, settings map[data.Fingerprint]models.NotificationSettings, multipleSettings map[data.Fingerprint][]models.NotificationSettings) (autogeneratedRoute, error) {
 	keys := maps.Keys(settings)
 	// sort keys to make sure that the hash we calculate using it is stable
 	slices.Sort(keys)
@@ -148,6 +161,39 @@ func generateRouteFromSettings(defaultReceiver string, settings map[data.Fingerp
 		})
 	}
 
+	multipleKeys := maps.Keys(multipleSettings)
+	slices.Sort(multipleKeys)
+	multipleRoutes := make([]*definitions.Route, 0, len(multipleKeys))
+	for _, fingerprint := range multipleKeys {
+		ruleSettings := multipleSettings[fingerprint]
+		settingsMatcher, err := labels.NewMatcher(labels.MatchEqual, models.AutogeneratedRouteSettingsHashLabel, fingerprint.String())
+		if err != nil {
+			return autogeneratedRoute{}, err
+		}
+		multipleRoute := &definitions.Route{
+			Receiver:       ruleSettings[0].Receiver,
+			ObjectMatchers: definitions.ObjectMatchers{settingsMatcher},
+			Continue:       false,
+			GroupByStr:     []string{models.FolderTitleLabel, model.AlertNameLabel},
+		}
+		for _, s := range ruleSettings {
+			multipleRoute.Routes = append(multipleRoute.Routes, &definitions.Route{
+				Receiver: s.Receiver,
+				Continue: true, // Every receiver of the rule gets the alerts.
+
+				GroupByStr:        s.GroupBy,
+				MuteTimeIntervals: s.MuteTimeIntervals,
+				GroupWait:         s.GroupWait,
+				GroupInterval:     s.GroupInterval,
+				RepeatInterval:    s.RepeatInterval,
+			})
+		}
+		multipleRoutes = append(multipleRoutes, multipleRoute)
+	}
+	if len(multipleRoutes) > 0 {
+		autoGenRoot.Routes = append(multipleRoutes, autoGenRoot.Routes...)
+	}
+
 	return autogeneratedRoute{
 		Route: autoGenRoot,
 	}, nil
@@ -236,3 +236,45 @@ func TestAddAutogenConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestAddAutogenConfig_MultipleSettings(t *testing.T) {
+	orgId := int64(1)
+	cfg := &definitions.PostableApiAlertingConfig{
+		Config: definitions.Config{
+			Route: &definitions.Route{Receiver: "default"},
+		},
+		Receivers: []*definitions.PostableApiReceiver{
+			{Receiver: config.Receiver{Name: "receiver1"}},
+			{Receiver: config.Receiver{Name: "receiver2"}},
+		},
+	}
+	ruleSettings := []models.NotificationSettings{
+		models.NewDefaultNotificationSettings("receiver1"),
+		models.CopyNotificationSettings(models.NewDefaultNotificationSettings("receiver2"), models.NSMuts.WithGroupWait(util.Pointer(2*time.Minute))),
+	}
+	store := &fakeConfigStore{
+		notificationSettings: map[int64]map[models.AlertRuleKey][]models.NotificationSettings{
+			orgId: {
+				models.AlertRuleKey{OrgID: orgId, UID: util.GenerateShortUID()}: ruleSettings,
+			},
+		},
+	}
+
+	require.NoError(t, AddAutogenConfig(context.Background(), &logtest.Fake{}, store, orgId, cfg, false))
+
+	autogenRoot := cfg.Route.Routes[0]
+	// The route of the rule is before the routes by receiver, which would match the alerts otherwise.
+	multipleRoute := autogenRoot.Routes[0]
+	require.Len(t, multipleRoute.ObjectMatchers, 1)
+	require.Equal(t, models.AutogeneratedRouteSettingsHashLabel, multipleRoute.ObjectMatchers[0].Name)
+	require.Equal(t, models.NotificationSettingsToLabels(ruleSettings)[models.AutogeneratedRouteSettingsHashLabel], multipleRoute.ObjectMatchers[0].Value)
+	require.Len(t, multipleRoute.Routes, 2)
+	for i, route := range multipleRoute.Routes {
+		require.Equal(t, ruleSettings[i].Receiver, route.Receiver)
+		require.True(t, route.Continue)
+		require.Empty(t, route.ObjectMatchers)
+		require.Equal(t, ruleSettings[i].GroupWait, route.GroupWait)
+	}
+	// Routes by receiver are still created for the rules with a single setting.
+	require.Len(t, autogenRoot.Routes, 3)
+}
@@ -931,6 +931,36 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 					PropertyName: "authorization_credentials",
 					Secure:       true,
 				},
+				{
+					Label:        "OAuth2 - Token URL",
+					Description:  "URL of the token endpoint of the OAuth2 client credentials flow. If it is set, the webhook is sent with an access token, which is fetched again when it expires. It cannot be combined with HTTP Basic Authentication or Authorization Header credentials.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "oauth2_token_url",
+				},
+				{
+					Label:        "OAuth2 - Client ID",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "oauth2_client_id",
+					DependsOn:    "oauth2_token_url",
+				},
+				{
+					Label:        "OAuth2 - Client Secret",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "oauth2_client_secret",
+					Secure:       true,
+					DependsOn:    "oauth2_token_url",
+				},
+				{
+					Label:        "OAuth2 - Scopes",
+					Description:  "Comma-separated list of the scopes to request.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "oauth2_scopes",
+					DependsOn:    "oauth2_token_url",
+				},
 				{ // New in 8.0. TODO: How to enforce only numbers?
 					Label:        "Max Alerts",
 					Description:  "Max alerts to include in a notification. Remaining alerts in the same batch will be ignored above this number. 0 means no limit.",
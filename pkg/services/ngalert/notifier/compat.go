@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"encoding/json"
+	"slices"
 
 	alertingNotify "github.com/grafana/alerting/notify"
 	alertingTemplates "github.com/grafana/alerting/templates"
@@ -45,6 +46,20 @@ func PostableApiAlertingConfigToApiReceivers(c apimodels.PostableApiAlertingConf
 	return apiReceivers
 }
 
+// RemoveDisabledIntegrations removes the integrations with the given UIDs from the receivers, so that they do not send
+// notifications. Receivers left without integrations are kept, so that routes that refer to them remain valid.
+func RemoveDisabledIntegrations(receivers []*alertingNotify.APIReceiver, disabled []string) []*alertingNotify.APIReceiver {
+	if len(disabled) == 0 {
+		return receivers
+	}
+	for _, r := range receivers {
+		r.Integrations = slices.DeleteFunc(r.Integrations, func(i *alertingNotify.GrafanaIntegrationConfig) bool {
+			return slices.Contains(disabled, i.UID)
+		})
+	}
+	return receivers
+}
+
 type DecryptFn = func(value string) string
 
 func PostableToGettableGrafanaReceiver(r *apimodels.PostableGrafanaReceiver, provenance *models.Provenance, decryptFn DecryptFn, listOnly bool) (apimodels.GettableGrafanaReceiver, error) {
@@ -88,7 +103,9 @@ func PostableToGettableGrafanaReceiver(r *apimodels.PostableGrafanaReceiver, pro
 	return out, nil
 }
 
-func PostableToGettableApiReceiver(r *apimodels.PostableApiReceiver, provenances map[string]models.Provenance, decryptFn DecryptFn, listOnly bool) (apimodels.GettableApiReceiver, error) {
+// PostableToGettableApiReceiver converts the receiver of the configuration. The integrations are marked as disabled and
+// given the rate limit of the receiver according to the configuration.
+func PostableToGettableApiReceiver(r *apimodels.PostableApiReceiver, cfg *apimodels.PostableUserConfig, provenances map[string]models.Provenance, decryptFn DecryptFn, listOnly bool) (apimodels.GettableApiReceiver, error) {
 	out := apimodels.GettableApiReceiver{
 		Receiver: config.Receiver{
 			Name: r.Receiver.Name,
@@ -105,6 +122,8 @@ func PostableToGettableApiReceiver(r *apimodels.PostableApiReceiver, provenances
 		if err != nil {
 			return apimodels.GettableApiReceiver{}, err
 		}
+		gettable.Disabled = cfg.IsIntegrationDisabled(gr.UID)
+		gettable.RateLimit = cfg.GetRateLimit(r.Name)
 		out.GrafanaManagedReceivers = append(out.GrafanaManagedReceivers, &gettable)
 	}
 
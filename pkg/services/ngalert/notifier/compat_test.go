@@ -141,3 +141,34 @@ func TestPostableApiAlertingConfigToApiReceivers(t *testing.T) {
 	require.Equal(t, PostableApiReceiverToApiReceiver(c.Receivers[0]), actual[0])
 	require.Equal(t, PostableApiReceiverToApiReceiver(c.Receivers[1]), actual[1])
 }
+
+func TestRemoveDisabledIntegrations(t *testing.T) {
+	receivers := func() []*alertingNotify.APIReceiver {
+		return []*alertingNotify.APIReceiver{
+			{
+				ConfigReceiver: config.Receiver{Name: "receiver-1"},
+				GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+					Integrations: []*alertingNotify.GrafanaIntegrationConfig{{UID: "uid-1"}, {UID: "uid-2"}},
+				},
+			},
+			{
+				ConfigReceiver: config.Receiver{Name: "receiver-2"},
+				GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+					Integrations: []*alertingNotify.GrafanaIntegrationConfig{{UID: "uid-3"}},
+				},
+			},
+		}
+	}
+
+	t.Run("returns receivers unchanged when nothing is disabled", func(t *testing.T) {
+		require.Equal(t, receivers(), RemoveDisabledIntegrations(receivers(), nil))
+	})
+
+	t.Run("removes disabled integrations and keeps empty receivers", func(t *testing.T) {
+		actual := RemoveDisabledIntegrations(receivers(), []string{"uid-1", "uid-3"})
+		require.Len(t, actual, 2)
+		require.Equal(t, []*alertingNotify.GrafanaIntegrationConfig{{UID: "uid-2"}}, actual[0].Integrations)
+		require.Equal(t, "receiver-2", actual[1].Name)
+		require.Empty(t, actual[1].Integrations)
+	})
+}
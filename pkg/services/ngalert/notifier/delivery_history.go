@@ -0,0 +1,173 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// deliveryHistorySize is the number of delivery attempts that are kept per integration.
+const deliveryHistorySize = 20
+
+// deliveryErrorURL matches the URLs in the errors of the delivery attempts, e.g. the URL of a webhook in the error of
+// an HTTP request, which may contain tokens.
+var deliveryErrorURL = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// DeliveryAttempt is an attempt to deliver a notification through an integration of a contact point.
+type DeliveryAttempt struct {
+	Time     time.Time
+	Duration time.Duration
+	// Alerts is the number of alerts of the notification.
+	Alerts int
+	// Error is the error of the attempt, empty if it succeeded. The URLs and the secure settings of the integration are
+	// redacted from it.
+	Error string
+	// Retry is true if the attempt failed and the notification pipeline retries it.
+	Retry bool
+}
+
+// deliveryHistory keeps the recent delivery attempts of the integrations of an Alertmanager by their UID. It is kept
+// when the configuration is applied again, so that the attempts after a change of a contact point, e.g. the rotation
+// of a secret, can be told from the attempts before it. It is kept in memory only, so every instance has the history
+// of the notifications it sent since it started.
+type deliveryHistory struct {
+	mtx      sync.RWMutex
+	attempts map[string][]DeliveryAttempt
+	size     int
+}
+
+func newDeliveryHistory(size int) *deliveryHistory {
+	return &deliveryHistory{
+		attempts: make(map[string][]DeliveryAttempt),
+		size:     size,
+	}
+}
+
+func (h *deliveryHistory) record(uid string, attempt DeliveryAttempt) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	attempts := append(h.attempts[uid], attempt)
+	if len(attempts) > h.size {
+		attempts = attempts[len(attempts)-h.size:]
+	}
+	h.attempts[uid] = attempts
+}
+
+// get returns the recent delivery attempts of the integration, the most recent first.
+func (h *deliveryHistory) get(uid string) []DeliveryAttempt {
+	h.mtx.RLock()
+	defer h.mtx.RUnlock()
+	attempts := h.attempts[uid]
+	result := make([]DeliveryAttempt, 0, len(attempts))
+	for i := len(attempts) - 1; i >= 0; i-- {
+		result = append(result, attempts[i])
+	}
+	return result
+}
+
+// retain forgets the attempts of the integrations that are not in uids, e.g. the ones of deleted contact points.
+func (h *deliveryHistory) retain(uids map[string]struct{}) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for uid := range h.attempts {
+		if _, ok := uids[uid]; !ok {
+			delete(h.attempts, uid)
+		}
+	}
+}
+
+// historyRecordingNotifier records the delivery attempts of the next notifier in the history.
+type historyRecordingNotifier struct {
+	next    notifier
+	uid     string
+	history *deliveryHistory
+	now     func() time.Time
+	// secrets are the decrypted secure settings of the integration, which are redacted from the errors.
+	secrets []string
+}
+
+func (n *historyRecordingNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := n.now()
+	retry, err := n.next.Notify(ctx, alerts...)
+	attempt := DeliveryAttempt{
+		Time:     start,
+		Duration: n.now().Sub(start),
+		Alerts:   len(alerts),
+	}
+	if err != nil {
+		attempt.Error = redactDeliveryError(err.Error(), n.secrets)
+		attempt.Retry = retry
+	}
+	n.history.record(n.uid, attempt)
+	return retry, err
+}
+
+// withDeliveryHistory wraps the integrations of the receiver so that their delivery attempts are recorded in the
+// history. The secure settings are decrypted with decryptFn, to be redacted from the errors.
+func withDeliveryHistory(receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration, history *deliveryHistory, decryptFn alertingNotify.GetDecryptedValueFn) []*alertingNotify.Integration {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		idx := integration.Index()
+		if idx < 0 || idx >= len(receiver.Integrations) {
+			result = append(result, integration)
+			continue
+		}
+		recording := &historyRecordingNotifier{
+			next:    integration,
+			uid:     receiver.Integrations[idx].UID,
+			history: history,
+			now:     time.Now,
+			secrets: integrationSecrets(receiver.Integrations[idx], decryptFn),
+		}
+		result = append(result, alertingNotify.NewIntegration(recording, integration, integration.Name(), idx, receiver.Name))
+	}
+	return result
+}
+
+// integrationSecrets returns the decrypted values of the secure settings of the integration, the longest first so that
+// a secret that contains another one is redacted as a whole.
+func integrationSecrets(integration *alertingNotify.GrafanaIntegrationConfig, decryptFn alertingNotify.GetDecryptedValueFn) []string {
+	secrets := make([]string, 0, len(integration.SecureSettings))
+	for key, value := range integration.SecureSettings {
+		d, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		if secret := decryptFn(context.Background(), map[string][]byte{key: d}, key, ""); secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+	return secrets
+}
+
+// redactDeliveryError redacts the secrets and the URLs of the error of a delivery attempt. Only the scheme and the host
+// of the URLs are kept, since their paths, queries and user information may contain tokens.
+func redactDeliveryError(msg string, secrets []string) string {
+	for _, secret := range secrets {
+		msg = strings.ReplaceAll(msg, secret, apimodels.RedactedValue)
+	}
+	return deliveryErrorURL.ReplaceAllStringFunc(msg, func(match string) string {
+		u, err := url.Parse(match)
+		if err != nil || u.Host == "" {
+			return apimodels.RedactedValue
+		}
+		redacted := u.Scheme + "://" + u.Host
+		if u.User != nil || u.RawQuery != "" || u.Fragment != "" || strings.Trim(u.EscapedPath(), "/") != "" {
+			redacted += "/" + apimodels.RedactedValue
+		}
+		return redacted
+	})
+}
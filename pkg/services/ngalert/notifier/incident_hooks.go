@@ -0,0 +1,185 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// incidentHookTimeout is the time after which a call to an incident hook is abandoned.
+	incidentHookTimeout = 30 * time.Second
+	// incidentHookWorkers is the number of incident hooks that are called at the same time.
+	incidentHookWorkers = 4
+	// incidentHookQueueSize is the number of calls that can wait for a worker.
+	incidentHookQueueSize = 1000
+)
+
+// IncidentHookPayload is the body of the requests to the incident hooks.
+type IncidentHookPayload struct {
+	Action models.IncidentHookAction `json:"action"`
+	Hook   string                    `json:"hook"`
+	// Fingerprint identifies the alert. It is the same when the alert is created and resolved, so that the incident
+	// tool can find the ticket it opened.
+	Fingerprint string            `json:"fingerprint"`
+	RuleUID     string            `json:"ruleUID"`
+	RuleTitle   string            `json:"ruleTitle"`
+	FolderUID   string            `json:"folderUID"`
+	RuleGroup   string            `json:"ruleGroup"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      *time.Time        `json:"endsAt,omitempty"`
+}
+
+// IncidentHookSender calls the incident hooks of alert rules when their alerts start firing and when they are
+// resolved. The calls are queued and made by a fixed number of workers, so that slow incident tools do not delay the
+// evaluations, and failures are only logged. The requests are sent with the webhook sender of the notifications.
+//
+// Like the notifications, the hooks are called by a single instance of a high availability setup: the instance that
+// is first in the cluster of the Alertmanagers. The other instances evaluate the same rules and drop the calls.
+type IncidentHookSender struct {
+	sender   notifications.WebhookSender
+	resolver *secretResolver
+	peer     clusterPeer
+	queue    chan incidentHookCall
+	logger   log.Logger
+}
+
+// clusterPeer is the peer of the instance in the cluster of the Alertmanagers.
+type clusterPeer interface {
+	// Position is the position of the instance in the cluster. The instance at position 0 sends the notifications.
+	Position() int
+}
+
+type incidentHookCall struct {
+	orgID   int64
+	hook    models.IncidentHook
+	payload IncidentHookPayload
+}
+
+// NewIncidentHookSender returns an IncidentHookSender that resolves the secret references of the credentials of the
+// hooks with the given settings. The calls are made only when Run is running.
+func NewIncidentHookSender(cfg setting.UnifiedAlertingSecretReferencesSettings, sender notifications.WebhookSender, peer clusterPeer, logger log.Logger) *IncidentHookSender {
+	return &IncidentHookSender{
+		sender:   sender,
+		resolver: newSecretResolver(cfg),
+		peer:     peer,
+		queue:    make(chan incidentHookCall, incidentHookQueueSize),
+		logger:   logger,
+	}
+}
+
+// Run calls the queued incident hooks with incidentHookWorkers workers until the context is done.
+func (s *IncidentHookSender) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < incidentHookWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case c := <-s.queue:
+					s.handle(ctx, c)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+func (s *IncidentHookSender) handle(ctx context.Context, c incidentHookCall) {
+	ctx, cancel := context.WithTimeout(ctx, incidentHookTimeout)
+	defer cancel()
+	if err := s.call(ctx, c.orgID, c.hook, c.payload); err != nil {
+		s.logger.Error("Failed to call incident hook", "rule_uid", c.payload.RuleUID, "hook", c.hook.Name, "action", c.payload.Action, "error", err)
+	}
+}
+
+// Send queues the calls of the incident hooks of the rule for the alerts that started firing or were resolved in the
+// transitions. Calls are dropped if the queue is full.
+func (s *IncidentHookSender) Send(_ context.Context, rule *models.AlertRule, transitions []state.StateTransition) {
+	if s.peer.Position() != 0 {
+		return
+	}
+	for _, t := range transitions {
+		action, ok := incidentHookAction(t)
+		if !ok {
+			continue
+		}
+		for _, hook := range rule.IncidentHooks {
+			payload := IncidentHookPayload{
+				Action:      action,
+				Hook:        hook.Name,
+				Fingerprint: t.Labels.Fingerprint().String(),
+				RuleUID:     rule.UID,
+				RuleTitle:   rule.Title,
+				FolderUID:   rule.NamespaceUID,
+				RuleGroup:   rule.RuleGroup,
+				Labels:      t.Labels,
+				Annotations: t.Annotations,
+				StartsAt:    t.StartsAt,
+			}
+			if action == models.IncidentHookActionResolve {
+				endsAt := t.EndsAt
+				payload.EndsAt = &endsAt
+			}
+			select {
+			case s.queue <- incidentHookCall{orgID: rule.OrgID, hook: hook, payload: payload}:
+			default:
+				s.logger.Warn("Dropping incident hook call because the queue is full", "rule_uid", rule.UID, "hook", hook.Name, "action", action)
+			}
+		}
+	}
+}
+
+// incidentHookAction returns the action of the hooks for the transition, if any. Hooks are called once per incident:
+// when the alert fires after another state, and when the firing alert is resolved.
+func incidentHookAction(t state.StateTransition) (models.IncidentHookAction, bool) {
+	if t.State.State == eval.Alerting && t.PreviousState != eval.Alerting {
+		return models.IncidentHookActionCreate, true
+	}
+	if t.Resolved {
+		return models.IncidentHookActionResolve, true
+	}
+	return "", false
+}
+
+func (s *IncidentHookSender) call(ctx context.Context, orgID int64, hook models.IncidentHook, payload IncidentHookPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	cmd := &notifications.SendWebhookSync{
+		Url:         hook.URL,
+		Body:        string(body),
+		HttpMethod:  http.MethodPost,
+		ContentType: "application/json",
+	}
+	if hook.AuthorizationCredentials != "" {
+		credentials := hook.AuthorizationCredentials
+		ref, err := ParseSecretReference(credentials)
+		if err != nil {
+			return err
+		}
+		if ref != nil {
+			if credentials, err = s.resolver.Resolve(ctx, orgID, *ref); err != nil {
+				return err
+			}
+		}
+		cmd.HttpHeader = map[string]string{"Authorization": "Bearer " + credentials}
+	}
+	return s.sender.SendWebhookSync(ctx, cmd)
+}
@@ -0,0 +1,121 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeWebhookSender struct {
+	requests chan *notifications.SendWebhookSync
+}
+
+func (f *fakeWebhookSender) SendWebhookSync(_ context.Context, cmd *notifications.SendWebhookSync) error {
+	f.requests <- cmd
+	return nil
+}
+
+type fakeClusterPeer struct {
+	position int
+}
+
+func (f *fakeClusterPeer) Position() int {
+	return f.position
+}
+
+func TestIncidentHookSender(t *testing.T) {
+	type request struct {
+		authorization string
+		payload       IncidentHookPayload
+	}
+	sender := &fakeWebhookSender{requests: make(chan *notifications.SendWebhookSync, 10)}
+	peer := &fakeClusterPeer{}
+	s := NewIncidentHookSender(setting.UnifiedAlertingSecretReferencesSettings{AWSSecretsManagerEnabled: true, AllowedPrefixes: []string{"arn:aws:secretsmanager:eu-west-1:123456789012:secret:incidents"}}, sender, peer, log.NewNopLogger())
+	s.resolver.getAWSSecret = func(_ context.Context, _ string) (string, error) {
+		return `{"token": "s3cr3t"}`, nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	rule := &models.AlertRule{
+		UID:       "rule",
+		Title:     "Rule",
+		RuleGroup: "group",
+		IncidentHooks: []models.IncidentHook{{
+			Name:                     "tickets",
+			URL:                      "https://incidents.example.com/api/alerts",
+			AuthorizationCredentials: "ref+awssm://arn:aws:secretsmanager:eu-west-1:123456789012:secret:incidents#token",
+		}},
+	}
+	transition := func(current, previous eval.State, resolved bool) state.StateTransition {
+		return state.StateTransition{
+			State: &state.State{
+				State:    current,
+				Resolved: resolved,
+				Labels:   data.Labels{"alertname": "Rule", "instance": "a"},
+				StartsAt: time.Now(),
+				EndsAt:   time.Now(),
+			},
+			PreviousState: previous,
+		}
+	}
+	receive := func(t *testing.T) request {
+		t.Helper()
+		select {
+		case cmd := <-sender.requests:
+			require.Equal(t, "https://incidents.example.com/api/alerts", cmd.Url)
+			var payload IncidentHookPayload
+			require.NoError(t, json.Unmarshal([]byte(cmd.Body), &payload))
+			return request{authorization: cmd.HttpHeader["Authorization"], payload: payload}
+		case <-time.After(5 * time.Second):
+			t.Fatal("incident hook was not called")
+			return request{}
+		}
+	}
+
+	s.Send(context.Background(), rule, []state.StateTransition{
+		transition(eval.Alerting, eval.Pending, false),
+		transition(eval.Alerting, eval.Alerting, false),
+		transition(eval.Pending, eval.Normal, false),
+	})
+	created := receive(t)
+	require.Equal(t, "Bearer s3cr3t", created.authorization)
+	require.Equal(t, models.IncidentHookActionCreate, created.payload.Action)
+	require.Equal(t, "tickets", created.payload.Hook)
+	require.Equal(t, "rule", created.payload.RuleUID)
+	require.Nil(t, created.payload.EndsAt)
+
+	s.Send(context.Background(), rule, []state.StateTransition{transition(eval.Normal, eval.Alerting, true)})
+	resolved := receive(t)
+	require.Equal(t, models.IncidentHookActionResolve, resolved.payload.Action)
+	require.Equal(t, created.payload.Fingerprint, resolved.payload.Fingerprint, "the alert should be identified by the same fingerprint")
+	require.NotNil(t, resolved.payload.EndsAt)
+
+	require.Empty(t, sender.requests, "hooks should be called only when alerts start firing and when they are resolved")
+
+	t.Run("hooks should be called only by the first instance of the cluster", func(t *testing.T) {
+		peer.position = 1
+		t.Cleanup(func() { peer.position = 0 })
+		s.Send(context.Background(), rule, []state.StateTransition{transition(eval.Alerting, eval.Normal, false)})
+		require.Empty(t, s.queue)
+	})
+}
@@ -412,6 +412,11 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 	}
 }
 
+// Peer returns the peer of the instance in the cluster of the Alertmanagers.
+func (moa *MultiOrgAlertmanager) Peer() alertingNotify.ClusterPeer {
+	return moa.peer
+}
+
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
 // When the organization does not have an active Alertmanager, it returns a ErrNoAlertmanagerForOrg.
 // When the Alertmanager of the organization is not ready, it returns a ErrAlertmanagerNotReady.
@@ -431,6 +436,23 @@ func (moa *MultiOrgAlertmanager) AlertmanagerFor(orgID int64) (Alertmanager, err
 	return orgAM, nil
 }
 
+// GetDeliveryAttempts returns the recent attempts of this instance to deliver notifications through the integration
+// of the organization, the most recent first. It returns no attempts if the Alertmanager of the organization does not
+// record them, e.g. if it is remote.
+func (moa *MultiOrgAlertmanager) GetDeliveryAttempts(orgID int64, integrationUID string) ([]DeliveryAttempt, error) {
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return nil, err
+	}
+	recorder, ok := am.(interface {
+		DeliveryAttempts(integrationUID string) []DeliveryAttempt
+	})
+	if !ok {
+		return nil, nil
+	}
+	return recorder.DeliveryAttempts(integrationUID), nil
+}
+
 // NilPeer and NilChannel implements the Alertmanager clustering interface.
 type NilPeer struct{}
 
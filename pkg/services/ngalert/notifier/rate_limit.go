@@ -0,0 +1,84 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type notifier interface {
+	Notify(ctx context.Context, alerts ...*types.Alert) (bool, error)
+}
+
+// rateLimitedNotifier sends notifications through the next notifier until the rate limit is reached. Notifications that
+// exceed the limit are either dropped, or failed so that the notification pipeline retries them later.
+type rateLimitedNotifier struct {
+	next   notifier
+	limit  apimodels.NotificationRateLimit
+	logger log.Logger
+	now    func() time.Time
+
+	mtx  sync.Mutex
+	sent []time.Time
+}
+
+func newRateLimitedNotifier(next notifier, limit apimodels.NotificationRateLimit, logger log.Logger) *rateLimitedNotifier {
+	return &rateLimitedNotifier{
+		next:   next,
+		limit:  limit,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+func (n *rateLimitedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	if !n.allow() {
+		if n.limit.OverflowPolicy == apimodels.RateLimitOverflowRetry {
+			return true, fmt.Errorf("rate limit of %d notifications per %s exceeded", n.limit.MaxNotifications, n.limit.Interval)
+		}
+		n.logger.Warn("Dropping notification because the rate limit is exceeded", "alerts", len(alerts), "maxNotifications", n.limit.MaxNotifications, "interval", n.limit.Interval)
+		return false, nil
+	}
+	return n.next.Notify(ctx, alerts...)
+}
+
+// allow records a notification and returns true if it is within the limit.
+func (n *rateLimitedNotifier) allow() bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	now := n.now()
+	windowStart := now.Add(-time.Duration(n.limit.Interval))
+	i := 0
+	for i < len(n.sent) && !n.sent[i].After(windowStart) {
+		i++
+	}
+	n.sent = n.sent[i:]
+	if len(n.sent) >= n.limit.MaxNotifications {
+		return false
+	}
+	n.sent = append(n.sent, now)
+	return true
+}
+
+// withRateLimits wraps the integrations of the receiver if it has a rate limit. Each integration is limited on its
+// own, so that one integration reaching the limit does not silence the others. The state of the limits is not kept
+// when the configuration is applied again.
+func withRateLimits(receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration, limits map[string]apimodels.NotificationRateLimit, logger log.Logger) []*alertingNotify.Integration {
+	limit, ok := limits[receiver.Name]
+	if !ok {
+		return integrations
+	}
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		limited := newRateLimitedNotifier(integration, limit, logger.New("receiver", receiver.Name, "integration", integration.Name(), "index", integration.Index()))
+		result = append(result, alertingNotify.NewIntegration(limited, integration, integration.Name(), integration.Index(), receiver.Name))
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type countingNotifier struct {
+	calls int
+}
+
+func (n *countingNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	n.calls++
+	return false, nil
+}
+
+func TestRateLimitedNotifier(t *testing.T) {
+	setup := func(policy string) (*rateLimitedNotifier, *countingNotifier, *time.Time) {
+		next := &countingNotifier{}
+		now := time.Unix(0, 0)
+		n := newRateLimitedNotifier(next, apimodels.NotificationRateLimit{
+			MaxNotifications: 2,
+			Interval:         model.Duration(time.Minute),
+			OverflowPolicy:   policy,
+		}, log.NewNopLogger())
+		n.now = func() time.Time { return now }
+		return n, next, &now
+	}
+
+	t.Run("drops notifications over the limit", func(t *testing.T) {
+		n, next, _ := setup(apimodels.RateLimitOverflowDrop)
+		for i := 0; i < 3; i++ {
+			retry, err := n.Notify(context.Background())
+			require.NoError(t, err)
+			require.False(t, retry)
+		}
+		require.Equal(t, 2, next.calls)
+	})
+
+	t.Run("fails notifications over the limit with the retry policy", func(t *testing.T) {
+		n, next, _ := setup(apimodels.RateLimitOverflowRetry)
+		for i := 0; i < 2; i++ {
+			_, err := n.Notify(context.Background())
+			require.NoError(t, err)
+		}
+		retry, err := n.Notify(context.Background())
+		require.Error(t, err)
+		require.True(t, retry)
+		require.Equal(t, 2, next.calls)
+	})
+
+	t.Run("sends notifications again once the interval has passed", func(t *testing.T) {
+		n, next, now := setup("")
+		for i := 0; i < 3; i++ {
+			_, err := n.Notify(context.Background())
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, next.calls)
+
+		*now = now.Add(time.Minute + time.Second)
+		_, err := n.Notify(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, next.calls)
+	})
+}
@@ -82,6 +82,25 @@ func (rs *ReceiverService) shouldDecrypt(ctx context.Context, user identity.Requ
 	return decryptAccess && reqDecrypt, nil
 }
 
+// AuthorizeUpdate checks that the user can change the receivers of the organization.
+func (rs *ReceiverService) AuthorizeUpdate(ctx context.Context, user identity.Requester) error {
+	if user == nil {
+		return ErrPermissionDenied
+	}
+	eval := accesscontrol.EvalAny(
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingNotificationsWrite),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningWrite),
+	)
+	updateAccess, err := rs.ac.Evaluate(ctx, user, eval)
+	if err != nil {
+		return err
+	}
+	if !updateAccess {
+		return ErrPermissionDenied
+	}
+	return nil
+}
+
 // GetReceiver returns a receiver by name.
 // The receiver's secure settings are decrypted if requested and the user has access to do so.
 func (rs *ReceiverService) GetReceiver(ctx context.Context, q models.GetReceiverQuery, user identity.Requester) (definitions.GettableApiReceiver, error) {
@@ -114,7 +133,7 @@ func (rs *ReceiverService) GetReceiver(ctx context.Context, q models.GetReceiver
 			}
 			decryptFn := rs.decryptOrRedact(ctx, decrypt, q.Name, "")
 
-			return PostableToGettableApiReceiver(r, provenances, decryptFn, false)
+			return PostableToGettableApiReceiver(r, &cfg, provenances, decryptFn, false)
 		}
 	}
 
@@ -165,7 +184,7 @@ func (rs *ReceiverService) GetReceivers(ctx context.Context, q models.GetReceive
 		decryptFn := rs.decryptOrRedact(ctx, decrypt, r.Name, "")
 		listOnly := !decrypt && listAccess
 
-		res, err := PostableToGettableApiReceiver(r, provenances, decryptFn, listOnly)
+		res, err := PostableToGettableApiReceiver(r, &cfg, provenances, decryptFn, listOnly)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,385 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	secretReferencePrefix = "ref+"
+
+	// SecretBackendVault refers to a secret of a KV secrets engine of Vault.
+	SecretBackendVault = "vault"
+	// SecretBackendAWSSecretsManager refers to a secret of AWS Secrets Manager.
+	SecretBackendAWSSecretsManager = "awssm"
+)
+
+// SecretReference refers to a secret of an external secret manager. Secure settings of contact points hold references
+// as ref+<backend>://<path>#<key>, e.g. ref+vault://secret/data/alerting/slack#token, and the secrets are fetched
+// when notifications are sent.
+type SecretReference struct {
+	Backend string
+	// Path is the path of the secret in Vault, or its ARN in AWS Secrets Manager.
+	Path string
+	// Key is the field of the secret that holds the value. It is optional for AWS Secrets Manager, whose secrets can
+	// be plain strings.
+	Key string
+}
+
+func (r SecretReference) String() string {
+	s := secretReferencePrefix + r.Backend + "://" + r.Path
+	if r.Key != "" {
+		s += "#" + r.Key
+	}
+	return s
+}
+
+// ErrSecretReferenceNotAllowed is returned for the secret references that are not under one of the prefixes that the
+// administrator allowed for the organization.
+var ErrSecretReferenceNotAllowed = errors.New("secret reference is not allowed")
+
+// CheckAllowed returns ErrSecretReferenceNotAllowed if the path of the secret, or its ARN, does not start with one of
+// the allowed prefixes. Grafana reads the secrets with its own credentials, so the users who can write contact points
+// must not be able to refer to the other secrets that these credentials can read.
+func (r SecretReference) CheckAllowed(prefixes []string) error {
+	p := r.Path
+	if r.Backend == SecretBackendVault {
+		p = strings.TrimPrefix(p, "/")
+		// Vault cleans the paths, so relative segments could leave the allowed prefix.
+		if slices.Contains(strings.Split(p, "/"), "..") {
+			return fmt.Errorf("%w: '%s' has a relative path", ErrSecretReferenceNotAllowed, r)
+		}
+	}
+	for _, prefix := range prefixes {
+		if r.Backend == SecretBackendVault {
+			prefix = strings.TrimPrefix(prefix, "/")
+		}
+		if prefix != "" && strings.HasPrefix(p, prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: '%s' is not under one of the allowed prefixes of the organization", ErrSecretReferenceNotAllowed, r)
+}
+
+// checkSecretReferencesAllowed checks the plain-text secure settings of the posted Grafana receivers before they are
+// encrypted, so that a configuration cannot refer to the secrets outside of the allowed prefixes.
+func checkSecretReferencesAllowed(receivers []*definitions.PostableApiReceiver, prefixes []string) error {
+	for _, r := range receivers {
+		for _, gr := range r.GrafanaManagedReceivers {
+			for key, value := range gr.SecureSettings {
+				ref, err := ParseSecretReference(value)
+				if err != nil {
+					return fmt.Errorf("invalid secret '%s' of integration '%s': %w", key, gr.Name, err)
+				}
+				if ref == nil {
+					continue
+				}
+				if err := ref.CheckAllowed(prefixes); err != nil {
+					return fmt.Errorf("invalid secret '%s' of integration '%s': %w", key, gr.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ParseSecretReference parses the value of a secure setting. It returns nil if the value is not a reference.
+func ParseSecretReference(value string) (*SecretReference, error) {
+	rest, ok := strings.CutPrefix(value, secretReferencePrefix)
+	if !ok {
+		return nil, nil
+	}
+	backend, rest, ok := strings.Cut(rest, "://")
+	if !ok {
+		return nil, fmt.Errorf("secret reference '%s' should have the format ref+<backend>://<path>#<key>", value)
+	}
+	ref := SecretReference{Backend: backend, Path: rest}
+	if i := strings.LastIndex(rest, "#"); i >= 0 {
+		ref.Path, ref.Key = rest[:i], rest[i+1:]
+	}
+	if ref.Path == "" {
+		return nil, fmt.Errorf("secret reference '%s' has no path", value)
+	}
+	switch backend {
+	case SecretBackendVault:
+		if ref.Key == "" {
+			return nil, fmt.Errorf("secret reference '%s' has no key", value)
+		}
+	case SecretBackendAWSSecretsManager:
+		parsed, err := arn.Parse(ref.Path)
+		if err != nil || parsed.Service != "secretsmanager" {
+			return nil, fmt.Errorf("secret reference '%s' is not the ARN of a secret of AWS Secrets Manager", value)
+		}
+	default:
+		return nil, fmt.Errorf("secret reference '%s' has unknown backend '%s', should be one of '%s' or '%s'", value, backend, SecretBackendVault, SecretBackendAWSSecretsManager)
+	}
+	return &ref, nil
+}
+
+type cachedSecret struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// secretResolver fetches the secrets that secret references refer to. Secrets are reused for the TTL of the cache, so
+// that the secret managers are not called for every notification.
+type secretResolver struct {
+	cfg    setting.UnifiedAlertingSecretReferencesSettings
+	client *http.Client
+	now    func() time.Time
+	// getAWSSecret returns the secret string of the secret of AWS Secrets Manager with the ARN.
+	getAWSSecret func(ctx context.Context, secretARN string) (string, error)
+
+	mtx   sync.Mutex
+	cache map[SecretReference]cachedSecret
+}
+
+func newSecretResolver(cfg setting.UnifiedAlertingSecretReferencesSettings) *secretResolver {
+	return &secretResolver{
+		cfg:          cfg,
+		client:       &http.Client{Timeout: 30 * time.Second},
+		now:          time.Now,
+		getAWSSecret: getAWSSecret,
+		cache:        make(map[SecretReference]cachedSecret),
+	}
+}
+
+// Resolve returns the secret the reference refers to, if the organization is allowed to refer to it.
+func (r *secretResolver) Resolve(ctx context.Context, orgID int64, ref SecretReference) (string, error) {
+	if err := ref.CheckAllowed(r.cfg.AllowedPrefixesForOrg(orgID)); err != nil {
+		return "", err
+	}
+	r.mtx.Lock()
+	cached, ok := r.cache[ref]
+	r.mtx.Unlock()
+	if ok && r.now().Sub(cached.fetchedAt) < r.cfg.CacheTTL {
+		return cached.value, nil
+	}
+
+	var value string
+	var err error
+	switch ref.Backend {
+	case SecretBackendVault:
+		value, err = r.resolveVault(ctx, ref)
+	case SecretBackendAWSSecretsManager:
+		value, err = r.resolveAWS(ctx, ref)
+	default:
+		err = fmt.Errorf("unknown backend '%s'", ref.Backend)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret reference '%s': %w", ref, err)
+	}
+
+	r.mtx.Lock()
+	r.cache[ref] = cachedSecret{value: value, fetchedAt: r.now()}
+	r.mtx.Unlock()
+	return value, nil
+}
+
+func (r *secretResolver) resolveVault(ctx context.Context, ref SecretReference) (string, error) {
+	if r.cfg.VaultAddress == "" {
+		return "", errors.New("Vault is not configured")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.cfg.VaultAddress+"/v1/"+strings.TrimPrefix(ref.Path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", r.cfg.VaultToken)
+	if r.cfg.VaultNamespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.cfg.VaultNamespace)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Vault responded with status %d", resp.StatusCode)
+	}
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode the response of Vault: %w", err)
+	}
+	data := body.Data
+	// Version 2 of the KV secrets engine nests the secret in the data, next to its metadata.
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, ok := data[ref.Key].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no key '%s'", ref.Key)
+	}
+	return value, nil
+}
+
+func (r *secretResolver) resolveAWS(ctx context.Context, ref SecretReference) (string, error) {
+	if !r.cfg.AWSSecretsManagerEnabled {
+		return "", errors.New("AWS Secrets Manager is not enabled")
+	}
+	secret, err := r.getAWSSecret(ctx, ref.Path)
+	if err != nil {
+		return "", err
+	}
+	if ref.Key == "" {
+		return secret, nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+	value, ok := fields[ref.Key].(string)
+	if !ok {
+		return "", fmt.Errorf("secret has no key '%s'", ref.Key)
+	}
+	return value, nil
+}
+
+func getAWSSecret(ctx context.Context, secretARN string) (string, error) {
+	parsed, err := arn.Parse(secretARN)
+	if err != nil {
+		return "", err
+	}
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(parsed.Region)})
+	if err != nil {
+		return "", err
+	}
+	out, err := secretsmanager.New(sess).GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretARN),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil {
+		return "", errors.New("secret has no secret string")
+	}
+	return *out.SecretString, nil
+}
+
+// secretReferences returns the secret references in the secure settings of the integration, by secure setting.
+func secretReferences(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decryptFn alertingNotify.GetDecryptedValueFn) (map[string]SecretReference, error) {
+	refs := make(map[string]SecretReference)
+	for key, value := range integration.SecureSettings {
+		d, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the secure setting '%s': %w", key, err)
+		}
+		ref, err := ParseSecretReference(decryptFn(ctx, map[string][]byte{key: d}, key, ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid secure setting '%s': %w", key, err)
+		}
+		if ref != nil {
+			refs[key] = *ref
+		}
+	}
+	return refs, nil
+}
+
+// secretReferenceNotifier resolves the secret references of an integration when notifications are sent. The
+// integration is built again when the secrets it refers to change.
+type secretReferenceNotifier struct {
+	orgID    int64
+	refs     map[string]SecretReference
+	resolver *secretResolver
+	// build builds the integration with the resolved secrets, by secure setting.
+	build func(secrets map[string]string) (notifier, error)
+
+	mtx     sync.Mutex
+	secrets map[string]string
+	current notifier
+}
+
+func (n *secretReferenceNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	secrets := make(map[string]string, len(n.refs))
+	for key, ref := range n.refs {
+		value, err := n.resolver.Resolve(ctx, n.orgID, ref)
+		if err != nil {
+			return true, err
+		}
+		secrets[key] = value
+	}
+
+	n.mtx.Lock()
+	if n.current == nil || !maps.Equal(n.secrets, secrets) {
+		current, err := n.build(secrets)
+		if err != nil {
+			n.mtx.Unlock()
+			return false, fmt.Errorf("failed to build the integration with the resolved secrets: %w", err)
+		}
+		n.current, n.secrets = current, secrets
+	}
+	current := n.current
+	n.mtx.Unlock()
+
+	return current.Notify(ctx, alerts...)
+}
+
+// withSecretReferences wraps the integrations of the receiver whose secure settings refer to secrets of external secret
+// managers. build builds the integrations of a receiver with the given decryption function.
+func withSecretReferences(orgID int64, receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration, resolver *secretResolver, decryptFn alertingNotify.GetDecryptedValueFn, build func(*alertingNotify.APIReceiver, alertingNotify.GetDecryptedValueFn) ([]*alertingNotify.Integration, error)) ([]*alertingNotify.Integration, error) {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		idx := integration.Index()
+		if idx < 0 || idx >= len(receiver.Integrations) {
+			result = append(result, integration)
+			continue
+		}
+		cfg := receiver.Integrations[idx]
+		refs, err := secretReferences(context.Background(), cfg, decryptFn)
+		if err != nil {
+			return nil, fmt.Errorf("integration '%s': %w", cfg.Name, err)
+		}
+		if len(refs) == 0 {
+			result = append(result, integration)
+			continue
+		}
+		single := &alertingNotify.APIReceiver{
+			ConfigReceiver:      receiver.ConfigReceiver,
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{cfg}},
+		}
+		resolving := &secretReferenceNotifier{
+			orgID:    orgID,
+			refs:     refs,
+			resolver: resolver,
+			build: func(secrets map[string]string) (notifier, error) {
+				built, err := build(single, func(ctx context.Context, sjd map[string][]byte, key string, fallback string) string {
+					if v, ok := secrets[key]; ok {
+						return v
+					}
+					return decryptFn(ctx, sjd, key, fallback)
+				})
+				if err != nil {
+					return nil, err
+				}
+				if len(built) != 1 {
+					return nil, fmt.Errorf("expected 1 integration, got %d", len(built))
+				}
+				return built[0], nil
+			},
+		}
+		result = append(result, alertingNotify.NewIntegration(resolving, integration, integration.Name(), idx, receiver.Name))
+	}
+	return result, nil
+}
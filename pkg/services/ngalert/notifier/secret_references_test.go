@@ -0,0 +1,234 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		expected    *SecretReference
+		expectedErr string
+	}{{
+		name:  "literal value",
+		value: "s3cr3t",
+	}, {
+		name:     "Vault",
+		value:    "ref+vault://secret/data/alerting/slack#token",
+		expected: &SecretReference{Backend: SecretBackendVault, Path: "secret/data/alerting/slack", Key: "token"},
+	}, {
+		name:        "Vault without key",
+		value:       "ref+vault://secret/data/alerting/slack",
+		expectedErr: "has no key",
+	}, {
+		name:     "AWS Secrets Manager",
+		value:    "ref+awssm://arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting-AbCdEf",
+		expected: &SecretReference{Backend: SecretBackendAWSSecretsManager, Path: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting-AbCdEf"},
+	}, {
+		name:     "AWS Secrets Manager with key",
+		value:    "ref+awssm://arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting-AbCdEf#token",
+		expected: &SecretReference{Backend: SecretBackendAWSSecretsManager, Path: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting-AbCdEf", Key: "token"},
+	}, {
+		name:        "ARN of another service",
+		value:       "ref+awssm://arn:aws:s3:::bucket",
+		expectedErr: "not the ARN",
+	}, {
+		name:        "unknown backend",
+		value:       "ref+gcpsm://projects/p/secrets/s#token",
+		expectedErr: "unknown backend 'gcpsm'",
+	}, {
+		name:        "missing backend",
+		value:       "ref+secret/data/alerting/slack#token",
+		expectedErr: "should have the format",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ref, err := ParseSecretReference(test.value)
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, ref)
+			if ref != nil {
+				require.Equal(t, test.value, ref.String())
+			}
+		})
+	}
+}
+
+func TestCheckSecretReferencesAllowed(t *testing.T) {
+	receivers := func(secret string) []*definitions.PostableApiReceiver {
+		return []*definitions.PostableApiReceiver{{
+			PostableGrafanaReceivers: definitions.PostableGrafanaReceivers{
+				GrafanaManagedReceivers: []*definitions.PostableGrafanaReceiver{{
+					Name:           "slack",
+					SecureSettings: map[string]string{"token": secret},
+				}},
+			},
+		}}
+	}
+	prefixes := []string{"secret/data/alerting/"}
+
+	require.NoError(t, checkSecretReferencesAllowed(receivers("plain-token"), prefixes))
+	require.NoError(t, checkSecretReferencesAllowed(receivers("ref+vault://secret/data/alerting/slack#token"), prefixes))
+	require.ErrorIs(t, checkSecretReferencesAllowed(receivers("ref+vault://secret/data/database/root#password"), prefixes), ErrSecretReferenceNotAllowed)
+	require.ErrorIs(t, checkSecretReferencesAllowed(receivers("ref+vault://secret/data/alerting/slack#token"), nil), ErrSecretReferenceNotAllowed)
+	require.ErrorContains(t, checkSecretReferencesAllowed(receivers("ref+vault://secret/data/alerting/slack"), prefixes), "has no key")
+}
+
+func TestSecretResolver(t *testing.T) {
+	t.Run("Vault secrets are cached for the TTL", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			require.Equal(t, "/v1/secret/data/alerting/slack", r.URL.Path)
+			require.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+			require.Equal(t, "alerting", r.Header.Get("X-Vault-Namespace"))
+			_, _ = fmt.Fprintf(w, `{"data": {"data": {"token": "token-%d"}, "metadata": {"version": 1}}}`, requests)
+		}))
+		t.Cleanup(server.Close)
+
+		r := newSecretResolver(setting.UnifiedAlertingSecretReferencesSettings{
+			VaultAddress:    server.URL,
+			VaultToken:      "vault-token",
+			VaultNamespace:  "alerting",
+			CacheTTL:        time.Minute,
+			AllowedPrefixes: []string{"secret/data/alerting/"},
+		})
+		now := time.Now()
+		r.now = func() time.Time { return now }
+		ref := SecretReference{Backend: SecretBackendVault, Path: "secret/data/alerting/slack", Key: "token"}
+
+		value, err := r.Resolve(context.Background(), 1, ref)
+		require.NoError(t, err)
+		require.Equal(t, "token-1", value)
+
+		now = now.Add(30 * time.Second)
+		value, err = r.Resolve(context.Background(), 1, ref)
+		require.NoError(t, err)
+		require.Equal(t, "token-1", value)
+
+		now = now.Add(time.Minute)
+		value, err = r.Resolve(context.Background(), 1, ref)
+		require.NoError(t, err)
+		require.Equal(t, "token-2", value)
+		require.Equal(t, 2, requests)
+
+		_, err = r.Resolve(context.Background(), 1, SecretReference{Backend: SecretBackendVault, Path: "secret/data/alerting/slack", Key: "url"})
+		require.ErrorContains(t, err, "secret has no key 'url'")
+	})
+
+	t.Run("AWS Secrets Manager secrets are decoded by key", func(t *testing.T) {
+		r := newSecretResolver(setting.UnifiedAlertingSecretReferencesSettings{AWSSecretsManagerEnabled: true, AllowedPrefixes: []string{"arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting"}})
+		r.getAWSSecret = func(_ context.Context, secretARN string) (string, error) {
+			return `{"token": "s3cr3t"}`, nil
+		}
+
+		value, err := r.Resolve(context.Background(), 1, SecretReference{Backend: SecretBackendAWSSecretsManager, Path: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting", Key: "token"})
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", value)
+
+		value, err = r.Resolve(context.Background(), 1, SecretReference{Backend: SecretBackendAWSSecretsManager, Path: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting"})
+		require.NoError(t, err)
+		require.Equal(t, `{"token": "s3cr3t"}`, value)
+	})
+
+	t.Run("backends that are not configured fail", func(t *testing.T) {
+		r := newSecretResolver(setting.UnifiedAlertingSecretReferencesSettings{AllowedPrefixes: []string{"secret/", "arn:aws:secretsmanager:"}})
+
+		_, err := r.Resolve(context.Background(), 1, SecretReference{Backend: SecretBackendVault, Path: "secret/data/alerting/slack", Key: "token"})
+		require.ErrorContains(t, err, "Vault is not configured")
+
+		_, err = r.Resolve(context.Background(), 1, SecretReference{Backend: SecretBackendAWSSecretsManager, Path: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting"})
+		require.ErrorContains(t, err, "AWS Secrets Manager is not enabled")
+	})
+
+	t.Run("secrets outside of the allowed prefixes of the organization are not resolved", func(t *testing.T) {
+		r := newSecretResolver(setting.UnifiedAlertingSecretReferencesSettings{
+			VaultAddress:       "http://vault.invalid",
+			AllowedPrefixes:    []string{"secret/data/alerting/"},
+			OrgAllowedPrefixes: map[int64][]string{2: {"secret/data/team-b/"}},
+		})
+
+		for _, ref := range []SecretReference{
+			{Backend: SecretBackendVault, Path: "secret/data/team-b/slack", Key: "token"},
+			{Backend: SecretBackendVault, Path: "secret/data/alerting/../team-b/slack", Key: "token"},
+			{Backend: SecretBackendAWSSecretsManager, Path: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting"},
+		} {
+			_, err := r.Resolve(context.Background(), 1, ref)
+			require.ErrorIs(t, err, ErrSecretReferenceNotAllowed, ref.String())
+		}
+		require.NoError(t, SecretReference{Backend: SecretBackendVault, Path: "/secret/data/team-b/slack", Key: "token"}.CheckAllowed(r.cfg.AllowedPrefixesForOrg(2)))
+	})
+}
+
+type recordingNotifier struct {
+	secret string
+	sent   int
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	n.sent++
+	return false, nil
+}
+
+func TestSecretReferenceNotifier(t *testing.T) {
+	secret, awsErr := "first", error(nil)
+	r := newSecretResolver(setting.UnifiedAlertingSecretReferencesSettings{AWSSecretsManagerEnabled: true, AllowedPrefixes: []string{"arn:aws:secretsmanager:"}})
+	r.getAWSSecret = func(_ context.Context, _ string) (string, error) {
+		return secret, awsErr
+	}
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	var built []*recordingNotifier
+	n := &secretReferenceNotifier{
+		orgID:    1,
+		refs:     map[string]SecretReference{"token": {Backend: SecretBackendAWSSecretsManager, Path: "arn:aws:secretsmanager:eu-west-1:123456789012:secret:alerting"}},
+		resolver: r,
+		build: func(secrets map[string]string) (notifier, error) {
+			b := &recordingNotifier{secret: secrets["token"]}
+			built = append(built, b)
+			return b, nil
+		},
+	}
+
+	_, err := n.Notify(context.Background())
+	require.NoError(t, err)
+	_, err = n.Notify(context.Background())
+	require.NoError(t, err)
+	require.Len(t, built, 1)
+	require.Equal(t, "first", built[0].secret)
+	require.Equal(t, 2, built[0].sent)
+
+	// The integration is built again once the secret changes.
+	secret = "second"
+	now = now.Add(time.Hour)
+	_, err = n.Notify(context.Background())
+	require.NoError(t, err)
+	require.Len(t, built, 2)
+	require.Equal(t, "second", built[1].secret)
+
+	// Notifications are retried when the secret cannot be resolved.
+	awsErr = errors.New("unavailable")
+	now = now.Add(time.Hour)
+	retry, err := n.Notify(context.Background())
+	require.True(t, retry)
+	require.ErrorContains(t, err, "unavailable")
+	require.Len(t, built, 2)
+}
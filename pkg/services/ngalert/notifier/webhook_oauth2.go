@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// webhookOAuth2ClientSecretKey is the secure setting of webhook integrations that holds the OAuth2 client secret.
+const webhookOAuth2ClientSecretKey = "oauth2_client_secret"
+
+// WebhookOAuth2Config is the OAuth2 client credentials configuration of a webhook integration.
+type WebhookOAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// WebhookOAuth2ConfigFromIntegration returns the OAuth2 configuration of the webhook integration, or nil if it does not
+// use OAuth2. The client secret is decrypted from the secure settings, or read from the settings if it is not there.
+func WebhookOAuth2ConfigFromIntegration(ctx context.Context, integration *alertingNotify.GrafanaIntegrationConfig, decryptFn alertingNotify.GetDecryptedValueFn) (*WebhookOAuth2Config, error) {
+	if integration.Type != "webhook" {
+		return nil, nil
+	}
+	var settings struct {
+		TokenURL     string `json:"oauth2_token_url"`
+		ClientID     string `json:"oauth2_client_id"`
+		ClientSecret string `json:"oauth2_client_secret"`
+		Scopes       string `json:"oauth2_scopes"`
+		User         string `json:"username"`
+		Credentials  string `json:"authorization_credentials"`
+	}
+	if len(integration.Settings) > 0 {
+		if err := json.Unmarshal(integration.Settings, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse the settings of the webhook: %w", err)
+		}
+	}
+	secureSettings := make(map[string][]byte, len(integration.SecureSettings))
+	for k, v := range integration.SecureSettings {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the secure setting '%s' of the webhook: %w", k, err)
+		}
+		secureSettings[k] = d
+	}
+	cfg := WebhookOAuth2Config{
+		TokenURL:     settings.TokenURL,
+		ClientID:     settings.ClientID,
+		ClientSecret: decryptFn(ctx, secureSettings, webhookOAuth2ClientSecretKey, settings.ClientSecret),
+		Scopes:       util.SplitString(settings.Scopes),
+	}
+	if cfg.TokenURL == "" && cfg.ClientID == "" && cfg.ClientSecret == "" && len(cfg.Scopes) == 0 {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	// The access token is sent in the Authorization header, so it cannot be combined with the other authentications.
+	if _, ok := secureSettings["authorization_credentials"]; ok || settings.User != "" || settings.Credentials != "" {
+		return nil, errors.New("OAuth2 cannot be combined with HTTP Basic Authentication or Authorization Header credentials")
+	}
+	return &cfg, nil
+}
+
+// Validate checks that the token URL, the client ID and the client secret are set, and that the token URL is an
+// absolute HTTP URL.
+func (c WebhookOAuth2Config) Validate() error {
+	if c.TokenURL == "" {
+		return errors.New("OAuth2 token URL must be set")
+	}
+	u, err := url.Parse(c.TokenURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("OAuth2 token URL '%s' must be an absolute HTTP URL", c.TokenURL)
+	}
+	if c.ClientID == "" {
+		return errors.New("OAuth2 client ID must be set")
+	}
+	if c.ClientSecret == "" {
+		return errors.New("OAuth2 client secret must be set")
+	}
+	return nil
+}
+
+// webhookOAuth2TokenTimeout is the longest time that an access token is requested for before sending a webhook fails.
+const webhookOAuth2TokenTimeout = 30 * time.Second
+
+// oauth2WebhookSender sends webhooks with an access token of the OAuth2 client credentials flow. The token is fetched
+// when the first webhook is sent, and fetched again once it expires. Tokens are requested with the HTTP client of the
+// webhooks, within the context of the webhook that needs them.
+type oauth2WebhookSender struct {
+	next   receivers.WebhookSender
+	config clientcredentials.Config
+	client *http.Client
+
+	mtx   sync.Mutex
+	token *oauth2.Token
+}
+
+func newOAuth2WebhookSender(next receivers.WebhookSender, cfg WebhookOAuth2Config, client *http.Client) *oauth2WebhookSender {
+	return &oauth2WebhookSender{
+		next: next,
+		config: clientcredentials.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			TokenURL:     cfg.TokenURL,
+			Scopes:       cfg.Scopes,
+		},
+		client: client,
+	}
+}
+
+// accessToken returns the current access token, or requests a new one if it is missing or expired.
+func (s *oauth2WebhookSender) accessToken(ctx context.Context) (*oauth2.Token, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookOAuth2TokenTimeout)
+	defer cancel()
+	if s.client != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, s.client)
+	}
+	token, err := s.config.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+func (s *oauth2WebhookSender) SendWebhook(ctx context.Context, cmd *receivers.SendWebhookSettings) error {
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get an OAuth2 access token: %w", err)
+	}
+	// The settings are shared with the other notifications of the integration, so the headers are copied.
+	withToken := *cmd
+	withToken.HTTPHeader = make(map[string]string, len(cmd.HTTPHeader)+1)
+	for k, v := range cmd.HTTPHeader {
+		withToken.HTTPHeader[k] = v
+	}
+	withToken.HTTPHeader["Authorization"] = token.Type() + " " + token.AccessToken
+	return s.next.SendWebhook(ctx, &withToken)
+}
+
+// webhookSenders returns the senders of the webhook integrations of the receiver that use OAuth2, by integration UID.
+func webhookSenders(ctx context.Context, receiver *alertingNotify.APIReceiver, next receivers.WebhookSender, client *http.Client, decryptFn alertingNotify.GetDecryptedValueFn) (map[string]receivers.WebhookSender, error) {
+	senders := make(map[string]receivers.WebhookSender)
+	for _, integration := range receiver.Integrations {
+		cfg, err := WebhookOAuth2ConfigFromIntegration(ctx, integration, decryptFn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook '%s': %w", integration.Name, err)
+		}
+		if cfg != nil {
+			senders[integration.UID] = newOAuth2WebhookSender(next, *cfg, client)
+		}
+	}
+	return senders, nil
+}
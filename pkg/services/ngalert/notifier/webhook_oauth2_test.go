@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/grafana/alerting/receivers"
+	"github.com/stretchr/testify/require"
+)
+
+// notEncrypted is a GetDecryptedValueFn for secure settings that are only encoded.
+func notEncrypted(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+	if v, ok := sjd[key]; ok {
+		return string(v)
+	}
+	return fallback
+}
+
+type recordingWebhookSender struct {
+	sent []*receivers.SendWebhookSettings
+}
+
+func (s *recordingWebhookSender) SendWebhook(_ context.Context, cmd *receivers.SendWebhookSettings) error {
+	s.sent = append(s.sent, cmd)
+	return nil
+}
+
+func TestWebhookOAuth2ConfigFromIntegration(t *testing.T) {
+	tests := []struct {
+		name           string
+		settings       string
+		secureSettings map[string]string
+		expected       *WebhookOAuth2Config
+		expectedErr    string
+	}{{
+		name:     "webhook without OAuth2",
+		settings: `{"url": "https://example.com"}`,
+	}, {
+		name:     "client secret in the settings",
+		settings: `{"url": "https://example.com", "oauth2_token_url": "https://auth.example.com/token", "oauth2_client_id": "grafana", "oauth2_client_secret": "s3cr3t", "oauth2_scopes": "a, b"}`,
+		expected: &WebhookOAuth2Config{TokenURL: "https://auth.example.com/token", ClientID: "grafana", ClientSecret: "s3cr3t", Scopes: []string{"a", "b"}},
+	}, {
+		name:           "client secret in the secure settings",
+		settings:       `{"url": "https://example.com", "oauth2_token_url": "https://auth.example.com/token", "oauth2_client_id": "grafana"}`,
+		secureSettings: map[string]string{"oauth2_client_secret": base64.StdEncoding.EncodeToString([]byte("s3cr3t"))},
+		expected:       &WebhookOAuth2Config{TokenURL: "https://auth.example.com/token", ClientID: "grafana", ClientSecret: "s3cr3t", Scopes: []string{}},
+	}, {
+		name:        "missing client secret",
+		settings:    `{"url": "https://example.com", "oauth2_token_url": "https://auth.example.com/token", "oauth2_client_id": "grafana"}`,
+		expectedErr: "OAuth2 client secret must be set",
+	}, {
+		name:        "relative token URL",
+		settings:    `{"url": "https://example.com", "oauth2_token_url": "/token", "oauth2_client_id": "grafana", "oauth2_client_secret": "s3cr3t"}`,
+		expectedErr: "must be an absolute HTTP URL",
+	}, {
+		name:        "OAuth2 with basic authentication",
+		settings:    `{"url": "https://example.com", "username": "user", "oauth2_token_url": "https://auth.example.com/token", "oauth2_client_id": "grafana", "oauth2_client_secret": "s3cr3t"}`,
+		expectedErr: "OAuth2 cannot be combined",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			integration := &alertingNotify.GrafanaIntegrationConfig{
+				Type:           "webhook",
+				Settings:       json.RawMessage(test.settings),
+				SecureSettings: test.secureSettings,
+			}
+			cfg, err := WebhookOAuth2ConfigFromIntegration(context.Background(), integration, notEncrypted)
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, cfg)
+		})
+	}
+}
+
+func TestOAuth2WebhookSender(t *testing.T) {
+	var requests int
+	var expiresIn int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "alerts:write", r.PostForm.Get("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token": "token-%d", "token_type": "Bearer", "expires_in": %d}`, requests, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := WebhookOAuth2Config{TokenURL: server.URL, ClientID: "grafana", ClientSecret: "s3cr3t", Scopes: []string{"alerts:write"}}
+
+	t.Run("token is reused until it expires", func(t *testing.T) {
+		requests, expiresIn = 0, 3600
+		next := &recordingWebhookSender{}
+		s := newOAuth2WebhookSender(next, cfg, server.Client())
+		cmd := &receivers.SendWebhookSettings{URL: "https://example.com", HTTPHeader: map[string]string{"X-Custom": "value"}}
+
+		require.NoError(t, s.SendWebhook(context.Background(), cmd))
+		require.NoError(t, s.SendWebhook(context.Background(), cmd))
+
+		require.Equal(t, 1, requests)
+		require.Len(t, next.sent, 2)
+		require.Equal(t, "Bearer token-1", next.sent[1].HTTPHeader["Authorization"])
+		require.Equal(t, "value", next.sent[1].HTTPHeader["X-Custom"])
+		require.NotContains(t, cmd.HTTPHeader, "Authorization")
+	})
+
+	t.Run("expired token is fetched again", func(t *testing.T) {
+		requests, expiresIn = 0, 1
+		next := &recordingWebhookSender{}
+		s := newOAuth2WebhookSender(next, cfg, server.Client())
+		cmd := &receivers.SendWebhookSettings{URL: "https://example.com"}
+
+		require.NoError(t, s.SendWebhook(context.Background(), cmd))
+		require.NoError(t, s.SendWebhook(context.Background(), cmd))
+
+		require.Equal(t, 2, requests)
+		require.Equal(t, "Bearer token-2", next.sent[1].HTTPHeader["Authorization"])
+	})
+
+	t.Run("token is requested within the context of the webhook", func(t *testing.T) {
+		requests, expiresIn = 0, 3600
+		next := &recordingWebhookSender{}
+		s := newOAuth2WebhookSender(next, cfg, server.Client())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := s.SendWebhook(ctx, &receivers.SendWebhookSettings{URL: "https://example.com"})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Zero(t, requests)
+		require.Empty(t, next.sent)
+	})
+}
@@ -4,14 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/template"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -19,56 +28,176 @@ type NotificationSettingsValidatorProvider interface {
 	Validator(ctx context.Context, orgID int64) (notifier.NotificationSettingsValidator, error)
 }
 
+// RuleAccessControlService authorizes the access of users to alert rules.
+type RuleAccessControlService interface {
+	HasAccessToRuleGroup(ctx context.Context, user identity.Requester, rules models.RulesGroup) (bool, error)
+	// HasAccessInFolder returns true if the user can read the alert rules of the folder, which HasAccessToRuleGroup
+	// does not check.
+	HasAccessInFolder(ctx context.Context, user identity.Requester, folderUID string) (bool, error)
+	AuthorizeAccessToRuleGroup(ctx context.Context, user identity.Requester, rules models.RulesGroup) error
+	AuthorizeRuleChanges(ctx context.Context, user identity.Requester, change *store.GroupDelta) error
+	// CanWriteAllRules returns true if the user may change any rule of the organization, e.g. through the
+	// provisioning API, regardless of the folders and data sources of the rules.
+	CanWriteAllRules(ctx context.Context, user identity.Requester) (bool, error)
+	// CanReadAllRules returns true if the user may read any rule of the organization through the provisioning API,
+	// regardless of the folders and data sources of the rules.
+	CanReadAllRules(ctx context.Context, user identity.Requester) (bool, error)
+}
+
 type AlertRuleService struct {
 	defaultIntervalSeconds int64
 	baseIntervalSeconds    int64
 	rulesPerRuleGroupLimit int64
+	ruleGroupChangesLimit  int64
 	ruleStore              RuleStore
 	provenanceStore        ProvisioningStore
-	dashboardService       dashboards.DashboardService
+	dashboardService       DashboardReader
 	quotas                 QuotaChecker
 	xact                   TransactionManager
 	log                    log.Logger
 	nsValidatorProvider    NotificationSettingsValidatorProvider
+	// quotaExemptProvenances contains the provenances of rules that do not count towards the alert rule quota.
+	// Such rules are limited by quotaExemptRulesLimit instead.
+	quotaExemptProvenances []models.Provenance
+	quotaExemptRulesLimit  int64
+	// titleUniqueness is the policy of the server settings for the titles of written rules. It applies to the
+	// organizations that have no policy of their own in kv.
+	titleUniqueness RuleTitleUniquenessPolicy
+	kv              kvstore.KVStore
+	ruleLimits      models.RuleLimits
+	// schedulerShards contains the scheduler shards that rule groups can be pinned to.
+	schedulerShards []string
+	// secretReferences restricts the secrets that the incident hooks can refer to.
+	secretReferences setting.UnifiedAlertingSecretReferencesSettings
+	// trashStore keeps the deleted rules for trashRetention, so that they can be restored. Rules are deleted
+	// permanently if trashRetention is zero.
+	trashStore     AlertRuleTrashStore
+	trashRetention time.Duration
+	// authz authorizes the users of the methods that take one. Users are not checked if it is nil.
+	authz RuleAccessControlService
+	// events publishes the changes of the rules once they are committed. Events are not published if it is nil.
+	events EventPublisher
 }
 
-func NewAlertRuleService(ruleStore RuleStore,
-	provenanceStore ProvisioningStore,
-	dashboardService dashboards.DashboardService,
-	quotas QuotaChecker,
-	xact TransactionManager,
-	defaultIntervalSeconds int64,
-	baseIntervalSeconds int64,
-	rulesPerRuleGroupLimit int64,
-	log log.Logger,
-	ns NotificationSettingsValidatorProvider,
-) *AlertRuleService {
-	return &AlertRuleService{
-		defaultIntervalSeconds: defaultIntervalSeconds,
-		baseIntervalSeconds:    baseIntervalSeconds,
-		rulesPerRuleGroupLimit: rulesPerRuleGroupLimit,
-		ruleStore:              ruleStore,
-		provenanceStore:        provenanceStore,
-		dashboardService:       dashboardService,
-		quotas:                 quotas,
-		xact:                   xact,
-		log:                    log,
-		nsValidatorProvider:    ns,
+// DashboardReader reads the folders of the rules, which are stored as dashboards.
+type DashboardReader interface {
+	GetDashboard(ctx context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error)
+	GetDashboards(ctx context.Context, query *dashboards.GetDashboardsQuery) ([]*dashboards.Dashboard, error)
+}
+
+// AlertRuleServiceConfig holds the dependencies and the settings of an AlertRuleService. RuleStore, ProvenanceStore,
+// DashboardService, Quotas, Xact, NotificationSettings and Log are required.
+type AlertRuleServiceConfig struct {
+	RuleStore            RuleStore
+	ProvenanceStore      ProvisioningStore
+	DashboardService     DashboardReader
+	Quotas               QuotaChecker
+	Xact                 TransactionManager
+	NotificationSettings NotificationSettingsValidatorProvider
+	Log                  log.Logger
+	// KV stores the settings of the organizations. Only the server settings apply if it is nil.
+	KV kvstore.KVStore
+	// TrashStore keeps the deleted rules, so that they can be restored. It is required if the rules are kept in the
+	// trash by the settings.
+	TrashStore AlertRuleTrashStore
+	// Authz authorizes the users of the methods that take one. Users are not checked if it is nil.
+	Authz RuleAccessControlService
+	// Events publishes the changes of the rules once they are committed. Events are not published if it is nil.
+	Events EventPublisher
+	// Settings are the settings of the server for alerting.
+	Settings setting.UnifiedAlertingSettings
+}
+
+// NewAlertRuleService returns an AlertRuleService, or an error if a required dependency is missing.
+func NewAlertRuleService(cfg AlertRuleServiceConfig) (*AlertRuleService, error) {
+	required := []struct {
+		name    string
+		missing bool
+	}{
+		{"rule store", cfg.RuleStore == nil},
+		{"provenance store", cfg.ProvenanceStore == nil},
+		{"dashboard service", cfg.DashboardService == nil},
+		{"quota checker", cfg.Quotas == nil},
+		{"transaction manager", cfg.Xact == nil},
+		{"notification settings validator provider", cfg.NotificationSettings == nil},
+		{"logger", cfg.Log == nil},
+		{"trash store", cfg.TrashStore == nil && cfg.Settings.AlertRuleTrashRetention > 0},
+	}
+	for _, r := range required {
+		if r.missing {
+			return nil, fmt.Errorf("alert rule service requires a %s", r.name)
+		}
+	}
+	exempt := make([]models.Provenance, 0, len(cfg.Settings.QuotaExemptProvenances))
+	for _, p := range cfg.Settings.QuotaExemptProvenances {
+		exempt = append(exempt, models.Provenance(p))
 	}
+	return &AlertRuleService{
+		defaultIntervalSeconds: int64(cfg.Settings.DefaultRuleEvaluationInterval.Seconds()),
+		baseIntervalSeconds:    int64(cfg.Settings.BaseInterval.Seconds()),
+		rulesPerRuleGroupLimit: cfg.Settings.RulesPerRuleGroupLimit,
+		ruleGroupChangesLimit:  cfg.Settings.RuleGroupChangesLimit,
+		ruleStore:              cfg.RuleStore,
+		provenanceStore:        cfg.ProvenanceStore,
+		dashboardService:       cfg.DashboardService,
+		quotas:                 cfg.Quotas,
+		xact:                   cfg.Xact,
+		log:                    cfg.Log,
+		nsValidatorProvider:    cfg.NotificationSettings,
+		quotaExemptProvenances: exempt,
+		quotaExemptRulesLimit:  cfg.Settings.QuotaExemptRulesLimit,
+		titleUniqueness:        RuleTitleUniquenessPolicy{Scope: cfg.Settings.RuleTitleUniqueness, FolderUIDs: cfg.Settings.RuleTitleUniquenessFolders},
+		kv:                     cfg.KV,
+		ruleLimits: models.RuleLimits{
+			MaxSizeBytes:       cfg.Settings.RuleMaxSizeBytes,
+			MaxQueries:         cfg.Settings.RuleMaxQueries,
+			MaxExpressionDepth: cfg.Settings.RuleMaxExpressionDepth,
+		},
+		schedulerShards:  cfg.Settings.SchedulerShards,
+		secretReferences: cfg.Settings.SecretReferences,
+		trashStore:       cfg.TrashStore,
+		trashRetention:   cfg.Settings.AlertRuleTrashRetention,
+		authz:            cfg.Authz,
+		events:           cfg.Events,
+	}, nil
 }
 
-func (service *AlertRuleService) GetAlertRules(ctx context.Context, orgID int64) ([]*models.AlertRule, map[string]models.Provenance, error) {
-	q := models.ListAlertRulesQuery{
-		OrgID: orgID,
+// GetAlertRules returns the alert rules of the organization that match the query, ordered by folder, group and
+// position in the group, along with their provenance. All rules are returned if the limit of the query is zero.
+//
+// If the user is set and cannot read all rules, only the rules of the groups that the user can access are returned,
+// and the page is selected among them.
+func (service *AlertRuleService) GetAlertRules(ctx context.Context, user identity.Requester, query models.ListAlertRulesQuery) ([]*models.AlertRule, map[string]models.Provenance, error) {
+	trim := false
+	if service.authz != nil && user != nil {
+		canReadAll, err := service.authz.CanReadAllRules(ctx, user)
+		if err != nil {
+			return nil, nil, err
+		}
+		trim = !canReadAll
 	}
-	rules, err := service.ruleStore.ListAlertRules(ctx, &q)
+	limit, page := query.Limit, query.Page
+	if trim {
+		query.Limit, query.Page = 0, 0
+	}
+	rules, err := service.ruleStore.ListAlertRules(ctx, &query)
 	if err != nil {
 		return nil, nil, err
 	}
+	if trim {
+		if rules, err = service.filterAccessibleRules(ctx, user, rules); err != nil {
+			return nil, nil, err
+		}
+		if limit > 0 {
+			start := min(max(page-1, 0)*limit, int64(len(rules)))
+			end := min(start+limit, int64(len(rules)))
+			rules = rules[start:end]
+		}
+	}
 	provenances := make(map[string]models.Provenance)
 	if len(rules) > 0 {
 		resourceType := rules[0].ResourceType()
-		provenances, err = service.provenanceStore.GetProvenances(ctx, orgID, resourceType)
+		provenances, err = service.provenanceStore.GetProvenances(ctx, query.OrgID, resourceType)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -76,6 +205,62 @@ func (service *AlertRuleService) GetAlertRules(ctx context.Context, orgID int64)
 	return rules, provenances, nil
 }
 
+// filterAccessibleRules returns the rules of the groups that the user can access, in folders in which the user can read
+// the rules. The order of the rules is kept.
+func (service *AlertRuleService) filterAccessibleRules(ctx context.Context, user identity.Requester, rules []*models.AlertRule) ([]*models.AlertRule, error) {
+	// The groups that query the same data sources share the decision of the authorization of the user.
+	ctx = accesscontrol.WithRequestAuthorizationCache(ctx)
+	groups := make(map[models.AlertRuleGroupKey]models.RulesGroup)
+	for _, rule := range rules {
+		groups[rule.GetGroupKey()] = append(groups[rule.GetGroupKey()], rule)
+	}
+	folders := make(map[string]bool)
+	access := make(map[models.AlertRuleGroupKey]bool, len(groups))
+	for key, group := range groups {
+		inFolder, ok := folders[key.NamespaceUID]
+		if !ok {
+			var err error
+			if inFolder, err = service.authz.HasAccessInFolder(ctx, user, key.NamespaceUID); err != nil {
+				return nil, err
+			}
+			folders[key.NamespaceUID] = inFolder
+		}
+		if !inFolder {
+			continue
+		}
+		has, err := service.authz.HasAccessToRuleGroup(ctx, user, group)
+		if err != nil {
+			return nil, err
+		}
+		access[key] = has
+	}
+	result := make([]*models.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if access[rule.GetGroupKey()] {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+// SearchAlertRules returns a page of the alert rules whose title, labels or annotations contain the query text, along
+// with their provenance and the total number of matching rules.
+func (service *AlertRuleService) SearchAlertRules(ctx context.Context, query models.SearchAlertRulesQuery) (*models.SearchAlertRulesResult, map[string]models.Provenance, error) {
+	result, err := service.ruleStore.SearchAlertRules(ctx, &query)
+	if err != nil {
+		return nil, nil, err
+	}
+	provenances := make(map[string]models.Provenance)
+	if len(result.Rules) > 0 {
+		resourceType := result.Rules[0].ResourceType()
+		provenances, err = service.provenanceStore.GetProvenances(ctx, query.OrgID, resourceType)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return result, provenances, nil
+}
+
 func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (models.AlertRule, models.Provenance, error) {
 	query := &models.GetAlertRuleByUIDQuery{
 		OrgID: orgID,
@@ -107,6 +292,11 @@ func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context
 	if err != nil {
 		return AlertRuleWithFolderTitle{}, err
 	}
+	folderAnnotations, err := service.ruleStore.GetFolderAnnotations(ctx, orgID, rule.NamespaceUID)
+	if err != nil {
+		return AlertRuleWithFolderTitle{}, err
+	}
+	rule.Annotations = models.StripFolderAnnotations(rule.Annotations, folderAnnotations)
 
 	dq := dashboards.GetDashboardQuery{
 		OrgID: orgID,
@@ -128,23 +318,28 @@ func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context
 // interval that is set in the rule struct and use the already existing group
 // interval or the default one.
 func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance, userID int64) (models.AlertRule, error) {
-	if rule.UID == "" {
-		rule.UID = util.GenerateShortUID()
-	} else if err := util.ValidateUID(rule.UID); err != nil {
-		return models.AlertRule{}, errors.Join(models.ErrAlertRuleFailedValidation, fmt.Errorf("cannot create rule with UID '%s': %w", rule.UID, err))
-	}
-	interval, err := service.ruleStore.GetRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
-	// if the alert group does not exist we just use the default interval
-	if err != nil && errors.Is(err, models.ErrAlertRuleGroupNotFound) {
-		interval = service.defaultIntervalSeconds
-	} else if err != nil {
+	// The store generates the UID of a rule that has none, so that it is derived from the title of the rule when the
+	// UIDs are deterministic.
+	if rule.UID != "" {
+		if err := util.ValidateUID(rule.UID); err != nil {
+			return models.AlertRule{}, errors.Join(models.ErrAlertRuleFailedValidation, fmt.Errorf("cannot create rule with UID '%s': %w", rule.UID, err))
+		}
+	}
+	folderAnnotations, err := service.ruleStore.GetFolderAnnotations(ctx, rule.OrgID, rule.NamespaceUID)
+	if err != nil {
 		return models.AlertRule{}, err
 	}
-	rule.IntervalSeconds = interval
+	rule.Annotations = models.MergeFolderAnnotations(rule.Annotations, folderAnnotations)
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := service.ruleLimits.Validate(rule); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := template.ValidateRule(rule); err != nil {
+		return models.AlertRule{}, errors.Join(models.ErrAlertRuleFailedValidation, err)
+	}
 	rule.Updated = time.Now()
 	if len(rule.NotificationSettings) > 0 {
 		validator, err := service.nsValidatorProvider.Validator(ctx, rule.OrgID)
@@ -158,6 +353,43 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 		}
 	}
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		// The group is locked, so that rules created concurrently in the group do not get the same index.
+		if err := service.ruleStore.LockRuleGroups(ctx, rule.GetGroupKey()); err != nil {
+			return err
+		}
+		// The interval is read in the transaction, so that the rule does not get the interval of a group that was
+		// changed concurrently.
+		interval, err := service.ruleStore.GetRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+		// if the alert group does not exist we just use the default interval
+		if err != nil && errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+			interval = service.defaultIntervalSeconds
+		} else if err != nil {
+			return err
+		}
+		rule.IntervalSeconds = interval
+		// The rule gets the data availability window and the shard affinity of its group, if the group exists.
+		groupRules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         rule.OrgID,
+			NamespaceUIDs: []string{rule.NamespaceUID},
+			RuleGroup:     rule.RuleGroup,
+		})
+		if err != nil {
+			return err
+		}
+		if len(groupRules) > 0 {
+			rule.DataAvailabilityPeriod = groupRules[0].DataAvailabilityPeriod
+			rule.DataAvailabilityDelay = groupRules[0].DataAvailabilityDelay
+			rule.ShardAffinity = groupRules[0].ShardAffinity
+			rule.IncidentHooks = groupRules[0].IncidentHooks
+		}
+		// A rule without index is added after the rules of its group.
+		if rule.RuleGroupIndex == 0 {
+			for _, r := range groupRules {
+				rule.RuleGroupIndex = max(rule.RuleGroupIndex, r.RuleGroupIndex)
+			}
+			rule.RuleGroupIndex++
+		}
+
 		ids, err := service.ruleStore.InsertAlertRules(ctx, []models.AlertRule{
 			rule,
 		})
@@ -166,8 +398,9 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 		}
 		var fixed bool
 		for _, key := range ids {
-			if key.UID == rule.UID {
+			if rule.UID == "" || key.UID == rule.UID {
 				rule.ID = key.ID
+				rule.UID = key.UID
 				fixed = true
 				break
 			}
@@ -176,11 +409,19 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 			return errors.New("couldn't find newly created id")
 		}
 
-		if err = service.checkLimitsTransactionCtx(ctx, rule.OrgID, userID); err != nil {
+		if err = service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance); err != nil {
+			return err
+		}
+
+		if err := service.checkTitleUniqueness(ctx, rule.OrgID, rule); err != nil {
 			return err
 		}
 
-		return service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+		if err := service.checkLimitsTransactionCtx(ctx, rule.OrgID, userID, provenance); err != nil {
+			return err
+		}
+		service.publishAfterCommit(ctx, alertRuleCreatedEvent(time.Now(), &rule, provenance))
+		return nil
 	})
 	if err != nil {
 		return models.AlertRule{}, err
@@ -202,110 +443,675 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 		return models.AlertRuleGroup{}, models.ErrAlertRuleGroupNotFound.Errorf("")
 	}
 	res := models.AlertRuleGroup{
-		Title:     ruleList[0].RuleGroup,
-		FolderUID: ruleList[0].NamespaceUID,
-		Interval:  ruleList[0].IntervalSeconds,
-		Rules:     []models.AlertRule{},
+		Title:                  ruleList[0].RuleGroup,
+		FolderUID:              ruleList[0].NamespaceUID,
+		Interval:               ruleList[0].IntervalSeconds,
+		DataAvailabilityPeriod: ruleList[0].DataAvailabilityPeriod,
+		DataAvailabilityDelay:  ruleList[0].DataAvailabilityDelay,
+		ShardAffinity:          ruleList[0].ShardAffinity,
+		IncidentHooks:          ruleList[0].IncidentHooks,
+		Rules:                  []models.AlertRule{},
+	}
+	managedBy, err := service.ruleStore.GetRuleGroupsManagedBy(ctx, orgID)
+	if err != nil {
+		return models.AlertRuleGroup{}, err
 	}
+	if m, ok := managedBy[ruleList[0].GetGroupKey()]; ok {
+		res.ManagedBy = &m
+	}
+	archived, err := service.ruleStore.GetArchivedRuleGroups(ctx, orgID)
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	res.Archived = slices.Contains(archived, ruleList[0].GetGroupKey())
 	for _, r := range ruleList {
 		if r != nil {
 			res.Rules = append(res.Rules, *r)
 		}
 	}
+	// The rules are listed in the order of their indexes, which have gaps if rules were deleted from the group. The
+	// indexes are set to the positions of the rules, so that the group can be written back as it was read.
+	for i := range res.Rules {
+		res.Rules[i].RuleGroupIndex = i + 1
+	}
 	return res, nil
 }
 
-// UpdateRuleGroup will update the interval for all rules in the group.
-func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
+// UpdateRuleGroup will update the interval for all rules in the group. The user must be allowed to update the rules
+// of the folder, or only their interval, see accesscontrol.ActionAlertingRuleIntervalUpdate.
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, user identity.Requester, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64, provenance models.Provenance) error {
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
 		return err
 	}
+	key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: namespaceUID, RuleGroup: ruleGroup}
+	return service.updateRuleGroupRules(ctx, user, key, provenance, func(rule *models.AlertRule) {
+		rule.IntervalSeconds = intervalSeconds
+	})
+}
+
+// updateRuleGroupRules applies the change to all rules of the group, and persists the rules that are changed like
+// the replacement of the group, after the user is authorized to change them. It returns ErrAlertRuleGroupNotFound if
+// the group has no rules.
+func (service *AlertRuleService) updateRuleGroupRules(ctx context.Context, user identity.Requester, key models.AlertRuleGroupKey, provenance models.Provenance, change func(rule *models.AlertRule)) error {
+	var userID int64
+	if user != nil {
+		userID, _ = identity.UserIdentifier(user.GetNamespacedID())
+	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
-		query := &models.ListAlertRulesQuery{
-			OrgID:         orgID,
-			NamespaceUIDs: []string{namespaceUID},
-			RuleGroup:     ruleGroup,
+		// The group is locked before it is read, so that the rules are not changed concurrently before they are
+		// written.
+		if err := service.ruleStore.LockRuleGroups(ctx, key); err != nil {
+			return err
 		}
-		ruleList, err := service.ruleStore.ListAlertRules(ctx, query)
+		ruleList, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         key.OrgID,
+			NamespaceUIDs: []string{key.NamespaceUID},
+			RuleGroup:     key.RuleGroup,
+		})
 		if err != nil {
 			return fmt.Errorf("failed to list alert rules: %w", err)
 		}
-		updateRules := make([]models.UpdateRule, 0, len(ruleList))
+		if len(ruleList) == 0 {
+			return models.ErrAlertRuleGroupNotFound
+		}
+		delta := &store.GroupDelta{
+			GroupKey:       key,
+			AffectedGroups: map[models.AlertRuleGroupKey]models.RulesGroup{key: ruleList},
+		}
 		for _, rule := range ruleList {
-			if rule.IntervalSeconds == intervalSeconds {
-				continue
+			changed := models.CopyRule(rule)
+			change(changed)
+			if update := store.CalculateRuleDelta(rule, changed); len(update.Diff) > 0 {
+				delta.Update = append(delta.Update, update)
 			}
-			newRule := *rule
-			newRule.IntervalSeconds = intervalSeconds
-			updateRules = append(updateRules, models.UpdateRule{
-				Existing: rule,
-				New:      newRule,
-			})
 		}
-		return service.ruleStore.UpdateAlertRules(ctx, updateRules)
+		if delta.IsEmpty() {
+			return nil
+		}
+		if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+			return err
+		}
+		if err := service.persistDelta(ctx, key.OrgID, delta, userID, provenance); err != nil {
+			return err
+		}
+		service.publishAfterCommit(ctx, alertRuleGroupReplacedEvent(time.Now(), delta, provenance))
+		return nil
+	})
+}
+
+// authorizeRuleChanges authorizes the changes of rules by the user. The changes are not checked against the folders
+// and data sources of the rules if the user may change all rules, or if there is no user, e.g. for file provisioning.
+func (service *AlertRuleService) authorizeRuleChanges(ctx context.Context, user identity.Requester, delta *store.GroupDelta) error {
+	if service.authz == nil || user == nil {
+		return nil
+	}
+	canWriteAll, err := service.authz.CanWriteAllRules(ctx, user)
+	if err != nil {
+		return err
+	}
+	if canWriteAll {
+		return nil
+	}
+	return service.authz.AuthorizeRuleChanges(ctx, user, delta)
+}
+
+// UpdateRuleGroupDataAvailability will update the data availability window for all rules in the group.
+func (service *AlertRuleService) UpdateRuleGroupDataAvailability(ctx context.Context, user identity.Requester, orgID int64, namespaceUID string, ruleGroup string, period, delay time.Duration, provenance models.Provenance) error {
+	if err := models.ValidateDataAvailability(period, delay); err != nil {
+		return err
+	}
+	key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: namespaceUID, RuleGroup: ruleGroup}
+	return service.updateRuleGroupRules(ctx, user, key, provenance, func(rule *models.AlertRule) {
+		rule.DataAvailabilityPeriod = period
+		rule.DataAvailabilityDelay = delay
+	})
+}
+
+// UpdateRuleGroupShardAffinity will pin all rules in the group to the scheduler shard, or unpin them if it is empty.
+func (service *AlertRuleService) UpdateRuleGroupShardAffinity(ctx context.Context, user identity.Requester, orgID int64, namespaceUID string, ruleGroup string, shardAffinity string, provenance models.Provenance) error {
+	if err := models.ValidateShardAffinity(shardAffinity, service.schedulerShards); err != nil {
+		return err
+	}
+	key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: namespaceUID, RuleGroup: ruleGroup}
+	return service.updateRuleGroupRules(ctx, user, key, provenance, func(rule *models.AlertRule) {
+		rule.ShardAffinity = shardAffinity
 	})
 }
 
+// UpdateRuleGroupIncidentHooks will set the incident hooks of all rules in the group, or remove them if there are none.
+func (service *AlertRuleService) UpdateRuleGroupIncidentHooks(ctx context.Context, user identity.Requester, orgID int64, namespaceUID string, ruleGroup string, hooks []models.IncidentHook, provenance models.Provenance) error {
+	if err := validateIncidentHooks(hooks, service.secretReferences.AllowedPrefixesForOrg(orgID)); err != nil {
+		return err
+	}
+	key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: namespaceUID, RuleGroup: ruleGroup}
+	return service.updateRuleGroupRules(ctx, user, key, provenance, func(rule *models.AlertRule) {
+		rule.IncidentHooks = slices.Clone(hooks)
+	})
+}
+
+// validateIncidentHooks validates the incident hooks of a group. Their credentials must refer to secrets of an
+// external secret manager under one of the allowed prefixes, because the hooks are stored and exported with the rules.
+func validateIncidentHooks(hooks []models.IncidentHook, allowedPrefixes []string) error {
+	if err := models.ValidateIncidentHooks(hooks); err != nil {
+		return err
+	}
+	for _, h := range hooks {
+		if h.AuthorizationCredentials == "" {
+			continue
+		}
+		ref, err := notifier.ParseSecretReference(h.AuthorizationCredentials)
+		if err != nil {
+			return fmt.Errorf("%w: incident hook '%s': %w", models.ErrAlertRuleFailedValidation, h.Name, err)
+		}
+		if ref == nil {
+			return fmt.Errorf("%w: authorization credentials of incident hook '%s' must be a secret reference, e.g. ref+vault://<path>#<key>", models.ErrAlertRuleFailedValidation, h.Name)
+		}
+		if err := ref.CheckAllowed(allowedPrefixes); err != nil {
+			return fmt.Errorf("%w: incident hook '%s': %w", models.ErrAlertRuleFailedValidation, h.Name, err)
+		}
+	}
+	return nil
+}
+
+// UpdateRuleGroupManagedBy will store who manages the rule group, or remove it if managedBy is nil. The rules of the
+// group are not changed, but their provenance must allow the change with the given provenance, and if the user is set,
+// they must be allowed to update the rules of the group.
+func (service *AlertRuleService) UpdateRuleGroupManagedBy(ctx context.Context, user identity.Requester, orgID int64, namespaceUID string, ruleGroup string, managedBy *models.ManagedBy, provenance models.Provenance) error {
+	if err := models.ValidateManagedBy(managedBy); err != nil {
+		return err
+	}
+	key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: namespaceUID, RuleGroup: ruleGroup}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.ruleStore.LockRuleGroups(ctx, key); err != nil {
+			return err
+		}
+		if err := service.checkRuleGroupChange(ctx, user, key, "change who manages", provenance); err != nil {
+			return err
+		}
+		return service.setRuleGroupManagedBy(ctx, key, managedBy)
+	})
+}
+
+// setRuleGroupManagedBy stores who manages the group if it has rules and it is not stored yet, or removes it if
+// managedBy is nil.
+func (service *AlertRuleService) setRuleGroupManagedBy(ctx context.Context, key models.AlertRuleGroupKey, managedBy *models.ManagedBy) error {
+	stored, err := service.ruleStore.GetRuleGroupsManagedBy(ctx, key.OrgID)
+	if err != nil {
+		return err
+	}
+	var current *models.ManagedBy
+	if m, ok := stored[key]; ok {
+		current = &m
+	}
+	if current.Equal(managedBy) {
+		return nil
+	}
+	if managedBy != nil {
+		// A group without rules does not exist, and who manages it would be left behind.
+		ruleList, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         key.OrgID,
+			NamespaceUIDs: []string{key.NamespaceUID},
+			RuleGroup:     key.RuleGroup,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		if len(ruleList) == 0 {
+			return nil
+		}
+	}
+	return service.ruleStore.SetRuleGroupManagedBy(ctx, key, managedBy)
+}
+
 func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, userID int64, provenance models.Provenance) error {
+	_, err := service.ReplaceRuleGroupWithDefaults(ctx, orgID, group, userID, provenance)
+	return err
+}
+
+// ServerDefault is a value that the server wrote to a rule of a replaced rule group instead of the given value, e.g.
+// the UID generated for a new rule.
+type ServerDefault struct {
+	// RuleIndex is the position of the rule in the given group.
+	RuleIndex int
+	// Field is the name of the field of the rule in the provisioning API.
+	Field string
+	Value any
+}
+
+// ReplaceRuleGroupWithDefaults replaces the rule group like ReplaceRuleGroup, and returns the values that the server
+// wrote to the given rules instead of the given ones, ordered by rule. Callers can write them back to the source of
+// the group, so that replacing the group again with the same source does not change it. If the group has a base
+// version, it is merged with the changes made to the stored group since that version, see mergeRuleGroup.
+func (service *AlertRuleService) ReplaceRuleGroupWithDefaults(ctx context.Context, orgID int64, group models.AlertRuleGroup, userID int64, provenance models.Provenance) ([]ServerDefault, error) {
+	return service.replaceRuleGroup(ctx, orgID, group, nil, userID, provenance)
+}
+
+// replaceRuleGroup replaces the rule group like ReplaceRuleGroupWithDefaults, and authorizes the changes by the user if
+// it is not nil.
+func (service *AlertRuleService) replaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, user identity.Requester, userID int64, provenance models.Provenance) ([]ServerDefault, error) {
+	group, err := service.prepareRuleGroup(ctx, orgID, group)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := groupServerDefaults(group, orgID)
+	// The delta is calculated in the transaction, so that it is never applied to a group that was changed concurrently.
+	// The group is locked before, so that concurrent replacements of the group wait for each other instead of
+	// calculating their deltas from the same rules. The other groups of the delta are locked when it is persisted.
+	groupKey := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: group.FolderUID, RuleGroup: group.Title}
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		delta, err := service.calcRuleGroupDelta(ctx, orgID, group, service.ruleGroupChangesLimit)
+		if err != nil {
+			return err
+		}
+
+		if len(delta.New) == 0 && len(delta.Update) == 0 && len(delta.Delete) == 0 {
+			return service.setRuleGroupManagedBy(ctx, groupKey, group.ManagedBy)
+		}
+
+		if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+			return err
+		}
+		if err := service.persistDelta(ctx, orgID, delta, userID, provenance); err != nil {
+			return err
+		}
+		// Who manages the group is stored after the rules, so that it is not stored for a group without rules.
+		if err := service.setRuleGroupManagedBy(ctx, groupKey, group.ManagedBy); err != nil {
+			return err
+		}
+		service.publishAfterCommit(ctx, alertRuleGroupReplacedEvent(time.Now(), delta, provenance))
+		defaults = append(defaults, generatedUIDs(group, delta)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(defaults, func(i, j int) bool {
+		return defaults[i].RuleIndex < defaults[j].RuleIndex
+	})
+	return defaults, nil
+}
+
+// prepareRuleGroup validates the group that replaces a rule group, and returns it with the indexes of its rules and
+// the default annotations of its folder set. The rules of the returned group are copies, so that the rules of the
+// given group are not changed and the group can be written again, e.g. in another organization.
+func (service *AlertRuleService) prepareRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup) (models.AlertRuleGroup, error) {
+	rules := make([]models.AlertRule, 0, len(group.Rules))
+	for i := range group.Rules {
+		rules = append(rules, *models.CopyRule(&group.Rules[i]))
+	}
+	group.Rules = rules
 	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
-		return err
+		return models.AlertRuleGroup{}, err
 	}
+	if err := models.ValidateDataAvailability(group.DataAvailabilityPeriod, group.DataAvailabilityDelay); err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	if err := models.ValidateShardAffinity(group.ShardAffinity, service.schedulerShards); err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	if err := validateIncidentHooks(group.IncidentHooks, service.secretReferences.AllowedPrefixesForOrg(orgID)); err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	if group.BakePeriod < 0 {
+		return models.AlertRuleGroup{}, fmt.Errorf("%w: bake period must not be negative", models.ErrAlertRuleFailedValidation)
+	}
+	if err := models.ValidateManagedBy(group.ManagedBy); err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	if err := models.SetRuleGroupIndexes(group.Rules); err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	// The default annotations of the folder are merged before the delta is calculated, so that replacing a group with
+	// the same rules does not change them.
+	folderAnnotations, err := service.ruleStore.GetFolderAnnotations(ctx, orgID, group.FolderUID)
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	for i := range group.Rules {
+		group.Rules[i].Annotations = models.MergeFolderAnnotations(group.Rules[i].Annotations, folderAnnotations)
+	}
+	return group, nil
+}
 
+// calcRuleGroupDelta calculates the delta that replaces the rule group with the given one, and validates it. The delta
+// must not contain more than changesLimit changes, unless it is 0. The group must be prepared with prepareRuleGroup.
+// It must be called in a transaction, in which the group is locked until the delta is persisted.
+func (service *AlertRuleService) calcRuleGroupDelta(ctx context.Context, orgID int64, group models.AlertRuleGroup, changesLimit int64) (*store.GroupDelta, error) {
+	groupKey := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: group.FolderUID, RuleGroup: group.Title}
+	if err := service.ruleStore.LockRuleGroups(ctx, groupKey); err != nil {
+		return nil, err
+	}
+	if group.CheckVersions && group.ExpectedVersion != nil && group.BaseVersion == nil {
+		current, err := service.ruleGroupVersion(ctx, groupKey)
+		if err != nil {
+			return nil, err
+		}
+		if current.String() != group.ExpectedVersion.String() {
+			return nil, ErrAlertRuleVersionConflict.Errorf("rule group '%s' was changed since version %s was read", group.Title, group.ExpectedVersion)
+		}
+	}
+	if group.BaseVersion != nil {
+		merged, err := service.mergeRuleGroup(ctx, orgID, group)
+		if err != nil {
+			return nil, err
+		}
+		group = merged
+	}
 	delta, err := service.calcDelta(ctx, orgID, group)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := delta.CheckSizeLimit(changesLimit); err != nil {
+		return nil, err
+	}
+	if delta.IsEmpty() {
+		return delta, nil
+	}
+	if err := service.validateDelta(ctx, delta); err != nil {
+		return nil, err
+	}
+	// Only the new rules are baked, so that the conditions that already hold when they are created do not notify. The
+	// rules that already exist keep notifying.
+	if group.BakePeriod > 0 {
+		bakeUntil := time.Now().Add(group.BakePeriod)
+		for _, rule := range delta.New {
+			rule.BakeUntil = &bakeUntil
+		}
 	}
+	return delta, nil
+}
 
-	if len(delta.New) == 0 && len(delta.Update) == 0 && len(delta.Delete) == 0 {
+// ruleGroupVersion returns the version of the stored rules of the group.
+func (service *AlertRuleService) ruleGroupVersion(ctx context.Context, key models.AlertRuleGroupKey) (models.RuleGroupVersion, error) {
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         key.OrgID,
+		NamespaceUIDs: []string{key.NamespaceUID},
+		RuleGroup:     key.RuleGroup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	return models.NewRuleGroupVersion(withoutNilAlertRules(rules)), nil
+}
+
+// RestoreAlertRules writes the given rules, for example the rules of a snapshot, group by group like ReplaceRuleGroup.
+// Rules that do not exist anymore are created again with the same UID. If replace is true, the rules of the
+// organization that are not given are deleted, otherwise only the given rules are changed. The changes are authorized
+// for the user.
+func (service *AlertRuleService) RestoreAlertRules(ctx context.Context, orgID int64, rules []models.AlertRule, replace bool, user identity.Requester, provenance models.Provenance) error {
+	var userID int64
+	if user != nil {
+		userID, _ = identity.UserIdentifier(user.GetNamespacedID())
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		current, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		existing := make(map[string]struct{}, len(current))
+		for _, rule := range current {
+			existing[rule.UID] = struct{}{}
+		}
+
+		restored := make(map[string]struct{}, len(rules))
+		var keys []models.AlertRuleGroupKey
+		groups := make(map[models.AlertRuleGroupKey]*models.AlertRuleGroup)
+		missing := make(map[models.AlertRuleGroupKey][]*models.AlertRule)
+		for _, rule := range rules {
+			rule.ID = 0
+			rule.OrgID = orgID
+			restored[rule.UID] = struct{}{}
+			key := rule.GetGroupKey()
+			group, ok := groups[key]
+			if !ok {
+				group = &models.AlertRuleGroup{
+					Title:                  key.RuleGroup,
+					FolderUID:              key.NamespaceUID,
+					Interval:               rule.IntervalSeconds,
+					DataAvailabilityPeriod: rule.DataAvailabilityPeriod,
+					DataAvailabilityDelay:  rule.DataAvailabilityDelay,
+					ShardAffinity:          rule.ShardAffinity,
+					IncidentHooks:          rule.IncidentHooks,
+				}
+				groups[key] = group
+				keys = append(keys, key)
+			}
+			if _, ok := existing[rule.UID]; !ok {
+				missing[key] = append(missing[key], models.CopyRule(&rule))
+			}
+			group.Rules = append(group.Rules, rule)
+		}
+
+		// Rules that were created after the snapshot in the restored groups are deleted by the replacement of the groups,
+		// unless they are kept. The rules of the other groups are deleted at the end.
+		var deleted []*models.AlertRule
+		for _, rule := range current {
+			if _, ok := restored[rule.UID]; ok {
+				continue
+			}
+			group, ok := groups[rule.GetGroupKey()]
+			if !ok {
+				if replace {
+					deleted = append(deleted, rule)
+				}
+				continue
+			}
+			if !replace {
+				group.Rules = append(group.Rules, *rule)
+			}
+		}
+
+		for _, key := range keys {
+			group := groups[key]
+			sort.SliceStable(group.Rules, func(i, j int) bool {
+				return group.Rules[i].RuleGroupIndex < group.Rules[j].RuleGroupIndex
+			})
+			// The rules of the snapshot and the rules that are kept can have the same indexes.
+			for i := range group.Rules {
+				group.Rules[i].RuleGroupIndex = i + 1
+			}
+			// The delta of a group fails on rules with a UID that does not exist, so they are created first.
+			if len(missing[key]) > 0 {
+				delta := &store.GroupDelta{GroupKey: key, New: missing[key]}
+				if err := service.validateDelta(ctx, delta); err != nil {
+					return err
+				}
+				if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+					return err
+				}
+				if err := service.persistDelta(ctx, orgID, delta, userID, provenance); err != nil {
+					return err
+				}
+			}
+			if _, err := service.replaceRuleGroup(ctx, orgID, *group, user, userID, provenance); err != nil {
+				return fmt.Errorf("failed to restore rule group %s: %w", key, err)
+			}
+		}
+
+		if len(deleted) == 0 {
+			return nil
+		}
+		// The deletions are authorized group by group, against the rules of the group.
+		deletedByGroup := make(map[models.AlertRuleGroupKey][]*models.AlertRule)
+		for _, rule := range deleted {
+			deletedByGroup[rule.GetGroupKey()] = append(deletedByGroup[rule.GetGroupKey()], rule)
+		}
+		for key, rules := range deletedByGroup {
+			delta := &store.GroupDelta{
+				GroupKey:       key,
+				AffectedGroups: map[models.AlertRuleGroupKey]models.RulesGroup{key: rules},
+				Delete:         rules,
+			}
+			if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+				return err
+			}
+		}
+		return service.persistDelta(ctx, orgID, &store.GroupDelta{Delete: deleted}, userID, provenance)
+	})
+}
+
+func (service *AlertRuleService) DeleteRuleGroup(ctx context.Context, orgID int64, namespaceUID, group string, provenance models.Provenance) error {
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		// List all rules in the group.
+		q := models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespaceUID},
+			RuleGroup:     group,
+		}
+		ruleList, err := service.ruleStore.ListAlertRules(ctx, &q)
+		if err != nil {
+			return err
+		}
+		if len(ruleList) == 0 {
+			return models.ErrAlertRuleGroupNotFound.Errorf("")
+		}
+
+		// Check provenance for all rules in the group. Fail to delete if any deletions aren't allowed.
+		for _, rule := range ruleList {
+			storedProvenance, err := service.provenanceStore.GetProvenance(ctx, rule, rule.OrgID)
+			if err != nil {
+				return err
+			}
+			if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+				return provenanceNotAllowed(ctx, service.provenanceStore, "delete", rule, storedProvenance, provenance)
+			}
+		}
+
+		// Delete all rules.
+		return service.deleteRules(ctx, orgID, ruleList...)
+	})
+}
+
+// MoveRuleGroup renames the rule group, or moves it to another folder, by changing the folder and the group of its
+// rules in place. Unlike deleting the group and creating it again, the rules keep their UIDs, and so their state
+// history, and their provenance. The target folder must exist and the target group must not. If the user is set, they
+// must have access to the rules of the group and be allowed to move them out of the source folder and into the target
+// one.
+func (service *AlertRuleService) MoveRuleGroup(ctx context.Context, user identity.Requester, orgID int64, from, to models.AlertRuleGroupKey, provenance models.Provenance) error {
+	from.OrgID, to.OrgID = orgID, orgID
+	if from == to {
 		return nil
 	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.ruleStore.LockRuleGroups(ctx, from, to); err != nil {
+			return err
+		}
+		group, err := service.GetRuleGroup(ctx, orgID, from.NamespaceUID, from.RuleGroup)
+		if err != nil {
+			return err
+		}
+		if to.NamespaceUID != from.NamespaceUID {
+			if err := service.checkFolderExists(ctx, orgID, to.NamespaceUID); err != nil {
+				return err
+			}
+		}
+		existing, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{to.NamespaceUID},
+			RuleGroup:     to.RuleGroup,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		if len(existing) > 0 {
+			return models.ErrAlertRuleGroupExists(to)
+		}
 
-	newOrUpdatedNotificationSettings := delta.NewOrUpdatedNotificationSettings()
-	if len(newOrUpdatedNotificationSettings) > 0 {
-		validator, err := service.nsValidatorProvider.Validator(ctx, delta.GroupKey.OrgID)
+		// The changes are calculated for the target group, so that the rules are found in the source group by UID and
+		// updated instead of created. Only the folder, the group and the positions of the rules change, so the rules
+		// are not validated again, and the limit of the changes of a group does not apply.
+		group.FolderUID = to.NamespaceUID
+		group.Title = to.RuleGroup
+		rules := make([]*models.AlertRuleWithOptionals, 0, len(group.Rules))
+		for i, rule := range syncGroupRuleFields(&group, orgID).Rules {
+			rule.RuleGroupIndex = i + 1
+			rules = append(rules, models.NewAlertRuleWithAllOptionals(rule))
+		}
+		delta, err := store.CalculateChanges(ctx, service.ruleStore, to, rules)
+		if err != nil {
+			return fmt.Errorf("failed to calculate diff for alert rules: %w", err)
+		}
+		delta = store.UpdateCalculatedRuleFields(delta)
+		if err := service.authorizeRuleGroupMove(ctx, user, delta); err != nil {
+			return err
+		}
+
+		updates := make([]models.UpdateRule, 0, len(delta.Update))
+		moved := make([]models.AlertRule, 0, len(delta.Update))
+		for _, update := range delta.Update {
+			// check that provenance is not changed in an invalid way
+			storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.Existing, orgID)
+			if err != nil {
+				return err
+			}
+			if canUpdate := canUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
+				return provenanceNotAllowed(ctx, service.provenanceStore, "move", update.Existing, storedProvenance, provenance)
+			}
+			updates = append(updates, models.UpdateRule{
+				Existing: update.Existing,
+				New:      *update.New,
+			})
+			moved = append(moved, *update.New)
+		}
+		// An archived group stays archived under its new name, and keeps who manages it. The archive of the source
+		// group and who manages it are deleted by the store once the group is empty.
+		archived, err := service.ruleStore.GetArchivedRuleGroups(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		managedBy, err := service.ruleStore.GetRuleGroupsManagedBy(ctx, orgID)
 		if err != nil {
 			return err
 		}
-		for _, s := range newOrUpdatedNotificationSettings {
-			if err := validator.Validate(s); err != nil {
-				return errors.Join(models.ErrAlertRuleFailedValidation, err)
+		if err := service.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
+			return fmt.Errorf("failed to update alert rules: %w", err)
+		}
+		if slices.Contains(archived, from) {
+			if err := service.ruleStore.SetRuleGroupArchived(ctx, to, true); err != nil {
+				return err
+			}
+		}
+		if m, ok := managedBy[from]; ok {
+			if err := service.ruleStore.SetRuleGroupManagedBy(ctx, to, &m); err != nil {
+				return err
 			}
 		}
-	}
-
-	return service.persistDelta(ctx, orgID, delta, userID, provenance)
+		return service.checkTitleUniqueness(ctx, orgID, moved...)
+	})
 }
 
-func (service *AlertRuleService) DeleteRuleGroup(ctx context.Context, orgID int64, namespaceUID, group string, provenance models.Provenance) error {
-	// List all rules in the group.
-	q := models.ListAlertRulesQuery{
-		OrgID:         orgID,
-		NamespaceUIDs: []string{namespaceUID},
-		RuleGroup:     group,
+// authorizeRuleGroupMove authorizes the move of the rules of a group by the user. The user must have access to the
+// rules of the source group, whose changes then check that they may delete rules from the source folder and create
+// rules in the target one.
+func (service *AlertRuleService) authorizeRuleGroupMove(ctx context.Context, user identity.Requester, delta *store.GroupDelta) error {
+	if service.authz == nil || user == nil {
+		return nil
 	}
-	ruleList, err := service.ruleStore.ListAlertRules(ctx, &q)
+	canWriteAll, err := service.authz.CanWriteAllRules(ctx, user)
 	if err != nil {
 		return err
 	}
-	if len(ruleList) == 0 {
-		return models.ErrAlertRuleGroupNotFound.Errorf("")
+	if canWriteAll {
+		return nil
 	}
-
-	// Check provenance for all rules in the group. Fail to delete if any deletions aren't allowed.
-	for _, rule := range ruleList {
-		storedProvenance, err := service.provenanceStore.GetProvenance(ctx, rule, rule.OrgID)
-		if err != nil {
-			return err
-		}
-		if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
-			return fmt.Errorf("cannot delete with provided provenance '%s', needs '%s'", provenance, storedProvenance)
-		}
+	source := make(models.RulesGroup, 0, len(delta.Update))
+	for _, update := range delta.Update {
+		source = append(source, update.Existing)
+	}
+	if err := service.authz.AuthorizeAccessToRuleGroup(ctx, user, source); err != nil {
+		return err
 	}
+	return service.authz.AuthorizeRuleChanges(ctx, user, delta)
+}
 
-	// Delete all rules.
-	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
-		return service.deleteRules(ctx, orgID, ruleList...)
-	})
+// checkFolderExists returns a validation error if the folder does not exist in the organization.
+func (service *AlertRuleService) checkFolderExists(ctx context.Context, orgID int64, folderUID string) error {
+	dash, err := service.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: orgID, UID: folderUID})
+	if errors.Is(err, dashboards.ErrDashboardNotFound) || errors.Is(err, dashboards.ErrFolderNotFound) || (err == nil && !dash.IsFolder) {
+		return fmt.Errorf("%w: folder '%s' does not exist", models.ErrAlertRuleFailedValidation, folderUID)
+	}
+	return err
 }
 
 func (service *AlertRuleService) calcDelta(ctx context.Context, orgID int64, group models.AlertRuleGroup) (*store.GroupDelta, error) {
@@ -338,25 +1144,83 @@ func (service *AlertRuleService) calcDelta(ctx context.Context, orgID int64, gro
 		NamespaceUID: group.FolderUID,
 		RuleGroup:    group.Title,
 	}
-	rules := make([]*models.AlertRuleWithOptionals, len(group.Rules))
+	rules := make([]*models.AlertRuleWithOptionals, 0, len(group.Rules))
+	// The rules are copied because they are modified below, and the caller may still use them.
+	group.Rules = slices.Clone(group.Rules)
 	group = *syncGroupRuleFields(&group, orgID)
 	for i := range group.Rules {
 		if err := group.Rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
 			return nil, err
 		}
-		rules = append(rules, &models.AlertRuleWithOptionals{AlertRule: group.Rules[i], HasPause: true})
+		rules = append(rules, models.NewAlertRuleWithAllOptionals(group.Rules[i]))
 	}
 	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate diff for alert rules: %w", err)
 	}
-
+	if group.RuleMoves == models.RuleMovesReject {
+		for _, update := range delta.Update {
+			if update.Existing.GetGroupKey() != key {
+				return nil, models.ErrAlertRuleMoveConflict(*update.Existing, key)
+			}
+		}
+	}
+	if group.CheckVersions {
+		for _, update := range delta.Update {
+			if update.New.Version != 0 && update.New.Version != update.Existing.Version {
+				return nil, ErrAlertRuleVersionConflict.Errorf("alert rule '%s' has version %d, not %d", update.Existing.UID, update.Existing.Version, update.New.Version)
+			}
+		}
+	}
+	// The calculated fields are not refreshed if the group is not changed, which would write all its rules again.
+	if delta.IsEmpty() {
+		return delta, nil
+	}
 	// Refresh all calculated fields across all rules.
 	return store.UpdateCalculatedRuleFields(delta), nil
 }
 
+// validateDelta checks the limits, the templates and the notification settings of the new and updated rules of the delta.
+func (service *AlertRuleService) validateDelta(ctx context.Context, delta *store.GroupDelta) error {
+	for _, rule := range delta.New {
+		if err := service.ruleLimits.Validate(*rule); err != nil {
+			return err
+		}
+		if err := template.ValidateRule(*rule); err != nil {
+			return errors.Join(models.ErrAlertRuleFailedValidation, err)
+		}
+	}
+	for _, update := range delta.Update {
+		if err := service.ruleLimits.Validate(*update.New); err != nil {
+			return err
+		}
+		if err := template.ValidateRule(*update.New); err != nil {
+			return errors.Join(models.ErrAlertRuleFailedValidation, err)
+		}
+	}
+
+	newOrUpdatedNotificationSettings := delta.NewOrUpdatedNotificationSettings()
+	if len(newOrUpdatedNotificationSettings) > 0 {
+		validator, err := service.nsValidatorProvider.Validator(ctx, delta.GroupKey.OrgID)
+		if err != nil {
+			return err
+		}
+		for _, s := range newOrUpdatedNotificationSettings {
+			if err := validator.Validate(s); err != nil {
+				return errors.Join(models.ErrAlertRuleFailedValidation, err)
+			}
+		}
+	}
+	return nil
+}
+
 func (service *AlertRuleService) persistDelta(ctx context.Context, orgID int64, delta *store.GroupDelta, userID int64, provenance models.Provenance) error {
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		// The groups are locked until the end of the transaction, so that concurrent changes of the same groups do not
+		// interleave and corrupt the indexes of their rules.
+		if err := service.ruleStore.LockRuleGroups(ctx, deltaGroupKeys(delta)...); err != nil {
+			return err
+		}
 		// Delete first as this could prevent future unique constraint violations.
 		if len(delta.Delete) > 0 {
 			for _, del := range delta.Delete {
@@ -366,7 +1230,7 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, orgID int64,
 					return err
 				}
 				if canUpdate := canUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
-					return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
+					return provenanceNotAllowed(ctx, service.provenanceStore, "delete", del, storedProvenance, provenance)
 				}
 			}
 			if err := service.deleteRules(ctx, orgID, delta.Delete...); err != nil {
@@ -383,7 +1247,7 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, orgID int64,
 					return err
 				}
 				if canUpdate := canUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
-					return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
+					return provenanceNotAllowed(ctx, service.provenanceStore, "update", update.Existing, storedProvenance, provenance)
 				}
 				updates = append(updates, models.UpdateRule{
 					Existing: update.Existing,
@@ -393,42 +1257,107 @@ func (service *AlertRuleService) persistDelta(ctx context.Context, orgID int64,
 			if err := service.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
 				return fmt.Errorf("failed to update alert rules: %w", err)
 			}
+			updated := make([]models.Provisionable, 0, len(delta.Update))
 			for _, update := range delta.Update {
-				if err := service.provenanceStore.SetProvenance(ctx, update.New, orgID, provenance); err != nil {
-					return err
-				}
+				updated = append(updated, update.New)
 			}
+			if err := service.provenanceStore.SetProvenances(ctx, orgID, updated, provenance); err != nil {
+				return err
+			}
+		}
+
+		written := make([]models.AlertRule, 0, len(delta.New)+len(delta.Update))
+		for _, update := range delta.Update {
+			written = append(written, *update.New)
 		}
 
 		if len(delta.New) > 0 {
-			uids, err := service.ruleStore.InsertAlertRules(ctx, withoutNilAlertRules(delta.New))
+			newRules := withoutNilAlertRules(delta.New)
+			uids, err := service.ruleStore.InsertAlertRules(ctx, newRules)
 			if err != nil {
 				return fmt.Errorf("failed to insert alert rules: %w", err)
 			}
-			for _, key := range uids {
-				if err := service.provenanceStore.SetProvenance(ctx, &models.AlertRule{UID: key.UID}, orgID, provenance); err != nil {
-					return err
+			inserted := make([]models.Provisionable, 0, len(uids))
+			for i, key := range uids {
+				inserted = append(inserted, &models.AlertRule{UID: key.UID})
+				// The keys are returned in the order of the rules, and contain the UIDs generated by the store.
+				if i < len(newRules) {
+					newRules[i].UID = key.UID
+					written = append(written, newRules[i])
+				}
+			}
+			// The UIDs are also set on the rules of the delta, for the callers that report them.
+			i := 0
+			for _, rule := range delta.New {
+				if rule != nil && i < len(newRules) {
+					rule.UID = newRules[i].UID
+					i++
 				}
 			}
+			if err := service.provenanceStore.SetProvenances(ctx, orgID, inserted, provenance); err != nil {
+				return err
+			}
+		}
+
+		if err := service.checkTitleUniqueness(ctx, orgID, written...); err != nil {
+			return err
 		}
 
-		if err := service.checkLimitsTransactionCtx(ctx, orgID, userID); err != nil {
+		if err := service.checkLimitsTransactionCtx(ctx, orgID, userID, provenance); err != nil {
 			return err
 		}
 
+		now := time.Now()
+		events := make([]bus.Msg, 0, len(delta.New)+len(delta.Update))
+		for _, rule := range delta.New {
+			if rule != nil {
+				events = append(events, alertRuleCreatedEvent(now, rule, provenance))
+			}
+		}
+		for _, update := range delta.Update {
+			if len(update.Diff) > 0 {
+				events = append(events, alertRuleUpdatedEvent(now, update.New, provenance))
+			}
+		}
+		service.publishAfterCommit(ctx, events...)
 		return nil
 	})
 }
 
+// deltaGroupKeys returns the keys of the rule groups changed by the delta, including the groups the updated rules are
+// moved from.
+func deltaGroupKeys(delta *store.GroupDelta) []models.AlertRuleGroupKey {
+	keys := make([]models.AlertRuleGroupKey, 0, 1)
+	if delta.GroupKey != (models.AlertRuleGroupKey{}) {
+		keys = append(keys, delta.GroupKey)
+	}
+	for _, rule := range delta.Delete {
+		keys = append(keys, rule.GetGroupKey())
+	}
+	for _, update := range delta.Update {
+		keys = append(keys, update.Existing.GetGroupKey(), update.New.GetGroupKey())
+	}
+	for _, rule := range delta.New {
+		if rule != nil {
+			keys = append(keys, rule.GetGroupKey())
+		}
+	}
+	return keys
+}
+
 // UpdateAlertRule updates an alert rule.
 func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
-	storedRule, storedProvenance, err := service.GetAlertRule(ctx, rule.OrgID, rule.UID)
-	if err != nil {
-		return models.AlertRule{}, err
-	}
-	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
-		return models.AlertRule{}, fmt.Errorf("cannot change provenance from '%s' to '%s'", storedProvenance, provenance)
-	}
+	return service.updateAlertRule(ctx, rule, provenance, false)
+}
+
+// UpdateAlertRuleIfUnchanged updates an alert rule like UpdateAlertRule if the stored rule still has the version of the
+// given rule, i.e. the version the caller read. Otherwise, it fails with ErrAlertRuleVersionConflict, so that a
+// concurrent change of the rule is not overwritten.
+func (service *AlertRuleService) UpdateAlertRuleIfUnchanged(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
+	return service.updateAlertRule(ctx, rule, provenance, true)
+}
+
+func (service *AlertRuleService) updateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance, checkVersion bool) (models.AlertRule, error) {
 	if len(rule.NotificationSettings) > 0 {
 		validator, err := service.nsValidatorProvider.Validator(ctx, rule.OrgID)
 		if err != nil {
@@ -441,14 +1370,45 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 		}
 	}
 	rule.Updated = time.Now()
-	rule.ID = storedRule.ID
-	rule.IntervalSeconds = storedRule.IntervalSeconds
+	folderAnnotations, err := service.ruleStore.GetFolderAnnotations(ctx, rule.OrgID, rule.NamespaceUID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	rule.Annotations = models.MergeFolderAnnotations(rule.Annotations, folderAnnotations)
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	if err := service.ruleLimits.Validate(rule); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := template.ValidateRule(rule); err != nil {
+		return models.AlertRule{}, errors.Join(models.ErrAlertRuleFailedValidation, err)
+	}
+	// The stored rule is read in the transaction, so that a concurrent update is detected by optimistic locking
+	// instead of being overwritten.
 	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err := service.ruleStore.UpdateAlertRules(ctx, []models.UpdateRule{
+		storedRule, storedProvenance, err := service.GetAlertRule(ctx, rule.OrgID, rule.UID)
+		if err != nil {
+			return err
+		}
+		if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+			return provenanceNotAllowed(ctx, service.provenanceStore, "update", &storedRule, storedProvenance, provenance)
+		}
+		if checkVersion && storedRule.Version != rule.Version {
+			return ErrAlertRuleVersionConflict.Errorf("alert rule '%s' has version %d, not %d", rule.UID, storedRule.Version, rule.Version)
+		}
+		rule.ID = storedRule.ID
+		rule.IntervalSeconds = storedRule.IntervalSeconds
+		rule.DataAvailabilityPeriod = storedRule.DataAvailabilityPeriod
+		rule.DataAvailabilityDelay = storedRule.DataAvailabilityDelay
+		rule.ShardAffinity = storedRule.ShardAffinity
+		rule.IncidentHooks = storedRule.IncidentHooks
+		rule.BakeUntil = storedRule.BakeUntil
+		if rule.RuleGroupIndex == 0 {
+			rule.RuleGroupIndex = storedRule.RuleGroupIndex
+		}
+		err = service.ruleStore.UpdateAlertRules(ctx, []models.UpdateRule{
 			{
 				Existing: &storedRule,
 				New:      rule,
@@ -457,7 +1417,16 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 		if err != nil {
 			return err
 		}
-		return service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+		// The store increments the version of the rule, the returned rule has the new version.
+		rule.Version = storedRule.Version + 1
+		if err := service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance); err != nil {
+			return err
+		}
+		if err := service.checkTitleUniqueness(ctx, rule.OrgID, rule); err != nil {
+			return err
+		}
+		service.publishAfterCommit(ctx, alertRuleUpdatedEvent(time.Now(), &rule, provenance))
+		return nil
 	})
 	if err != nil {
 		return models.AlertRule{}, err
@@ -470,21 +1439,45 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int6
 		OrgID: orgID,
 		UID:   ruleUID,
 	}
-	// check that provenance is not changed in an invalid way
-	storedProvenance, err := service.provenanceStore.GetProvenance(ctx, rule, rule.OrgID)
-	if err != nil {
-		return err
-	}
-	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
-		return fmt.Errorf("cannot delete with provided provenance '%s', needs '%s'", provenance, storedProvenance)
-	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
-		return service.deleteRules(ctx, orgID, rule)
+		// check that provenance is not changed in an invalid way
+		storedProvenance, err := service.provenanceStore.GetProvenance(ctx, rule, rule.OrgID)
+		if err != nil {
+			return err
+		}
+		if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+			return provenanceNotAllowed(ctx, service.provenanceStore, "delete", rule, storedProvenance, provenance)
+		}
+		// The stored rule is read, so that the deleted event has its title and group. Deleting a rule that does not
+		// exist does nothing.
+		stored, err := service.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: orgID, UID: ruleUID})
+		if errors.Is(err, models.ErrAlertRuleNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return service.deleteRules(ctx, orgID, stored)
 	})
 }
 
 // checkLimitsTransactionCtx checks whether the current transaction (as identified by the ctx) breaches configured alert rule limits.
-func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context, orgID, userID int64) error {
+// Rules written with a provenance that is exempt from the quota are checked against the limit of exempt rules instead.
+func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context, orgID, userID int64, provenance models.Provenance) error {
+	if service.isQuotaExempt(provenance) {
+		if service.quotaExemptRulesLimit < 0 {
+			return nil
+		}
+		count, err := service.ruleStore.CountByProvenances(ctx, orgID, service.quotaExemptProvenances...)
+		if err != nil {
+			return fmt.Errorf("failed to count quota exempt alert rules: %w", err)
+		}
+		if count > service.quotaExemptRulesLimit {
+			return models.ErrQuotaReached
+		}
+		return nil
+	}
+
 	limitReached, err := service.quotas.CheckQuotaReached(ctx, models.QuotaTargetSrv, &quota.ScopeParameters{
 		OrgID:  orgID,
 		UserID: userID,
@@ -498,7 +1491,18 @@ func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context,
 	return nil
 }
 
-// deleteRules deletes a set of target rules and associated data, while checking for database consistency.
+// isQuotaExempt returns true if rules with the given provenance do not count towards the alert rule quota.
+func (service *AlertRuleService) isQuotaExempt(provenance models.Provenance) bool {
+	for _, p := range service.quotaExemptProvenances {
+		if p == provenance {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteRules deletes a set of target rules and associated data, while checking for database consistency. The rules
+// are moved to the trash if it is enabled.
 func (service *AlertRuleService) deleteRules(ctx context.Context, orgID int64, targets ...*models.AlertRule) error {
 	uids := make([]string, 0, len(targets))
 	for _, tgt := range targets {
@@ -506,9 +1510,27 @@ func (service *AlertRuleService) deleteRules(ctx context.Context, orgID int64, t
 			uids = append(uids, tgt.UID)
 		}
 	}
+	if err := service.trashRules(ctx, orgID, targets...); err != nil {
+		return err
+	}
 	if err := service.ruleStore.DeleteAlertRulesByUID(ctx, orgID, uids...); err != nil {
 		return err
 	}
+	// The events have the provenance of the deleted rules, which is read before it is deleted.
+	if service.events != nil && len(uids) > 0 {
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		events := make([]bus.Msg, 0, len(uids))
+		for _, tgt := range targets {
+			if tgt != nil {
+				events = append(events, alertRuleDeletedEvent(now, tgt, provenances[tgt.UID]))
+			}
+		}
+		service.publishAfterCommit(ctx, events...)
+	}
 	for _, uid := range uids {
 		if err := service.provenanceStore.DeleteProvenance(ctx, &models.AlertRule{UID: uid}, orgID); err != nil {
 			// We failed to clean up the record, but this doesn't break things. Log it and move on.
@@ -543,13 +1565,18 @@ func (service *AlertRuleService) GetAlertRuleGroupWithFolderTitle(ctx context.Co
 	}
 
 	res := models.NewAlertRuleGroupWithFolderTitleFromRulesGroup(ruleList[0].GetGroupKey(), ruleList, dash.Title)
+	if err := service.stripFolderAnnotations(ctx, orgID, []models.AlertRuleGroupWithFolderTitle{res}); err != nil {
+		return models.AlertRuleGroupWithFolderTitle{}, err
+	}
 	return res, nil
 }
 
 // GetAlertGroupsWithFolderTitle returns all groups with folder title in the folders identified by folderUID that have at least one alert. If argument folderUIDs is nil or empty - returns groups in all folders.
-func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Context, orgID int64, folderUIDs []string) ([]models.AlertRuleGroupWithFolderTitle, error) {
+// The archived filter selects the groups by whether they are archived.
+func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Context, orgID int64, folderUIDs []string, archived models.ArchivedRuleGroupsFilter) ([]models.AlertRuleGroupWithFolderTitle, error) {
 	q := models.ListAlertRulesQuery{
-		OrgID: orgID,
+		OrgID:          orgID,
+		ArchivedGroups: archived,
 	}
 
 	if len(folderUIDs) > 0 {
@@ -602,15 +1629,224 @@ func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Conte
 		result = append(result, models.NewAlertRuleGroupWithFolderTitle(groupKey, rules, title))
 	}
 
+	if err := service.stripFolderAnnotations(ctx, orgID, result); err != nil {
+		return nil, err
+	}
+
 	// Return results in a stable manner.
 	models.SortAlertRuleGroupWithFolderTitle(result)
 	return result, nil
 }
 
+// SetAlertGroupsFolderPaths sets the folder path of each group, which is the titles of the nested folders from the root
+// folder down to the folder of the group. Like GetAlertGroupsWithFolderTitle, it reads the folders as dashboards to
+// avoid folder:read permissions.
+func (service *AlertRuleService) SetAlertGroupsFolderPaths(ctx context.Context, orgID int64, groups []models.AlertRuleGroupWithFolderTitle) error {
+	folders := make(map[string]*dashboards.Dashboard)
+	var pending []string
+	for _, g := range groups {
+		if g.AlertRuleGroup != nil && !slices.Contains(pending, g.FolderUID) {
+			pending = append(pending, g.FolderUID)
+		}
+	}
+	// The parents are fetched level by level, starting from the folders of the groups.
+	for len(pending) > 0 {
+		dashes, err := service.dashboardService.GetDashboards(ctx, &dashboards.GetDashboardsQuery{OrgID: orgID, DashboardUIDs: pending})
+		if err != nil {
+			return err
+		}
+		pending = nil
+		for _, dash := range dashes {
+			folders[dash.UID] = dash
+		}
+		for _, dash := range dashes {
+			if _, ok := folders[dash.FolderUID]; dash.FolderUID != "" && !ok && !slices.Contains(pending, dash.FolderUID) {
+				pending = append(pending, dash.FolderUID)
+			}
+		}
+	}
+
+	for i, g := range groups {
+		if g.AlertRuleGroup == nil {
+			continue
+		}
+		var path []string
+		for uid := g.FolderUID; uid != ""; {
+			f, ok := folders[uid]
+			if !ok {
+				return fmt.Errorf("cannot find folder with uid '%s'", uid)
+			}
+			if len(path) > len(folders) {
+				return fmt.Errorf("the parents of folder with uid '%s' form a cycle", g.FolderUID)
+			}
+			path = append([]string{f.Title}, path...)
+			uid = f.FolderUID
+		}
+		groups[i].FolderPath = path
+	}
+	return nil
+}
+
+// GetFolderAnnotations returns the default annotations of the alert rules of the folder.
+func (service *AlertRuleService) GetFolderAnnotations(ctx context.Context, orgID int64, folderUID string) (map[string]string, error) {
+	return service.ruleStore.GetFolderAnnotations(ctx, orgID, folderUID)
+}
+
+// SetFolderAnnotations replaces the default annotations of the alert rules of the folder. They are merged into the
+// annotations of the rules that are written afterwards, and removed from the exported rules. The rules that are
+// already stored are not changed.
+func (service *AlertRuleService) SetFolderAnnotations(ctx context.Context, orgID int64, folderUID string, annotations map[string]string) error {
+	if err := models.ValidateFolderAnnotations(annotations); err != nil {
+		return err
+	}
+	return service.ruleStore.SetFolderAnnotations(ctx, orgID, folderUID, annotations)
+}
+
+// GetFolderEvaluationPaused returns whether the evaluation of the alert rules of the folder is paused.
+func (service *AlertRuleService) GetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string) (bool, error) {
+	return service.ruleStore.GetFolderEvaluationPaused(ctx, orgID, folderUID)
+}
+
+// SetFolderEvaluationPaused pauses or resumes the evaluation of the alert rules of the folder. The rules of a paused
+// folder are not evaluated whatever their own pause status, which is not changed, so that the rules are not modified
+// by a maintenance of the folder and get their own pause status back when the folder is resumed.
+func (service *AlertRuleService) SetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string, paused bool) error {
+	return service.ruleStore.SetFolderEvaluationPaused(ctx, orgID, folderUID, paused)
+}
+
+// ArchiveRuleGroup archives the rule group. The rules of an archived group are not evaluated and are left out of the
+// listings of rules by default, but they are kept with their definition and provenance until the group is unarchived or
+// deleted. Unlike pausing, archiving does not change the rules. If the user is set, they must be allowed to update the
+// rules of the group.
+func (service *AlertRuleService) ArchiveRuleGroup(ctx context.Context, user identity.Requester, orgID int64, namespaceUID, group string, provenance models.Provenance) error {
+	return service.setRuleGroupArchived(ctx, user, orgID, namespaceUID, group, true, provenance)
+}
+
+// UnarchiveRuleGroup unarchives the rule group, whose rules are evaluated again. If the user is set, they must be
+// allowed to update the rules of the group.
+func (service *AlertRuleService) UnarchiveRuleGroup(ctx context.Context, user identity.Requester, orgID int64, namespaceUID, group string, provenance models.Provenance) error {
+	return service.setRuleGroupArchived(ctx, user, orgID, namespaceUID, group, false, provenance)
+}
+
+func (service *AlertRuleService) setRuleGroupArchived(ctx context.Context, user identity.Requester, orgID int64, namespaceUID, group string, archived bool, provenance models.Provenance) error {
+	key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: namespaceUID, RuleGroup: group}
+	action := "unarchive"
+	if archived {
+		action = "archive"
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := service.checkRuleGroupChange(ctx, user, key, action, provenance); err != nil {
+			return err
+		}
+		return service.ruleStore.SetRuleGroupArchived(ctx, key, archived)
+	})
+}
+
+// checkRuleGroupChange checks a change of the group that is stored apart from its rules, e.g. its archive. The change
+// is authorized as an update of all the rules of the group, and the provenance of the rules must allow it with the
+// given provenance. It returns ErrAlertRuleGroupNotFound if the group has no rules.
+func (service *AlertRuleService) checkRuleGroupChange(ctx context.Context, user identity.Requester, key models.AlertRuleGroupKey, action string, provenance models.Provenance) error {
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         key.OrgID,
+		NamespaceUIDs: []string{key.NamespaceUID},
+		RuleGroup:     key.RuleGroup,
+	})
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return models.ErrAlertRuleGroupNotFound.Errorf("")
+	}
+	delta := &store.GroupDelta{
+		GroupKey:       key,
+		AffectedGroups: map[models.AlertRuleGroupKey]models.RulesGroup{key: rules},
+	}
+	for _, rule := range rules {
+		delta.Update = append(delta.Update, store.RuleDelta{Existing: rule, New: rule})
+	}
+	if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+		return err
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, key.OrgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if storedProvenance := provenances[rule.UID]; !canUpdateProvenanceInRuleGroup(storedProvenance, provenance) {
+			return provenanceNotAllowed(ctx, service.provenanceStore, action, rule, storedProvenance, provenance)
+		}
+	}
+	return nil
+}
+
+// stripFolderAnnotations removes the default annotations of the folders of the groups from the annotations of their
+// rules, so that exported rules do not repeat them.
+func (service *AlertRuleService) stripFolderAnnotations(ctx context.Context, orgID int64, groups []models.AlertRuleGroupWithFolderTitle) error {
+	byFolder := make(map[string]map[string]string)
+	for _, group := range groups {
+		folderAnnotations, ok := byFolder[group.FolderUID]
+		if !ok {
+			var err error
+			folderAnnotations, err = service.ruleStore.GetFolderAnnotations(ctx, orgID, group.FolderUID)
+			if err != nil {
+				return err
+			}
+			byFolder[group.FolderUID] = folderAnnotations
+		}
+		for i := range group.Rules {
+			group.Rules[i].Annotations = models.StripFolderAnnotations(group.Rules[i].Annotations, folderAnnotations)
+		}
+	}
+	return nil
+}
+
+// groupServerDefaults returns the fields of the rules of the group that syncGroupRuleFields overwrites with a
+// different value.
+func groupServerDefaults(group models.AlertRuleGroup, orgID int64) []ServerDefault {
+	var defaults []ServerDefault
+	for i, rule := range group.Rules {
+		if rule.OrgID != orgID {
+			defaults = append(defaults, ServerDefault{RuleIndex: i, Field: "orgID", Value: orgID})
+		}
+		if rule.NamespaceUID != group.FolderUID {
+			defaults = append(defaults, ServerDefault{RuleIndex: i, Field: "folderUID", Value: group.FolderUID})
+		}
+		if rule.RuleGroup != group.Title {
+			defaults = append(defaults, ServerDefault{RuleIndex: i, Field: "ruleGroup", Value: group.Title})
+		}
+	}
+	return defaults
+}
+
+// generatedUIDs returns the UIDs that were generated for the rules of the group that were given without one. These
+// rules are the new rules of the delta, in the same order.
+func generatedUIDs(group models.AlertRuleGroup, delta *store.GroupDelta) []ServerDefault {
+	var defaults []ServerDefault
+	created := withoutNilAlertRules(delta.New)
+	for i, rule := range group.Rules {
+		if rule.UID != "" {
+			continue
+		}
+		if len(created) == 0 {
+			break
+		}
+		defaults = append(defaults, ServerDefault{RuleIndex: i, Field: "uid", Value: created[0].UID})
+		created = created[1:]
+	}
+	return defaults
+}
+
 // syncRuleGroupFields synchronizes calculated fields across multiple rules in a group.
 func syncGroupRuleFields(group *models.AlertRuleGroup, orgID int64) *models.AlertRuleGroup {
 	for i := range group.Rules {
 		group.Rules[i].IntervalSeconds = group.Interval
+		group.Rules[i].DataAvailabilityPeriod = group.DataAvailabilityPeriod
+		group.Rules[i].DataAvailabilityDelay = group.DataAvailabilityDelay
+		group.Rules[i].ShardAffinity = group.ShardAffinity
+		group.Rules[i].IncidentHooks = group.IncidentHooks
+		if group.Rules[i].ExpiresAt == nil {
+			group.Rules[i].ExpiresAt = group.ExpiresAt
+		}
 		group.Rules[i].RuleGroup = group.Title
 		group.Rules[i].NamespaceUID = group.FolderUID
 		group.Rules[i].OrgID = orgID
@@ -628,6 +1864,63 @@ func withoutNilAlertRules(ptrs []*models.AlertRule) []models.AlertRule {
 	return result
 }
 
+// checkTitleUniqueness checks that none of the written rules has the same title as another rule in the scope of the
+// title uniqueness policy of the organization. Titles are compared case-insensitively. It must be called in the
+// transaction that writes the rules, after they are written, so that rules written by the same transaction are checked
+// against each other.
+func (service *AlertRuleService) checkTitleUniqueness(ctx context.Context, orgID int64, written ...models.AlertRule) error {
+	policy, _, err := service.GetRuleTitleUniqueness(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if policy.Scope == "" {
+		return nil
+	}
+	folders := make([]string, 0, 1)
+	for _, rule := range written {
+		if !policy.enforcedIn(rule.NamespaceUID) || slices.Contains(folders, rule.NamespaceUID) {
+			continue
+		}
+		folders = append(folders, rule.NamespaceUID)
+	}
+	if len(folders) == 0 {
+		return nil
+	}
+
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: folders,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	type titleKey struct {
+		namespaceUID string
+		group        string
+		title        string
+	}
+	keyOf := func(rule *models.AlertRule) titleKey {
+		key := titleKey{namespaceUID: rule.NamespaceUID, title: strings.ToLower(rule.Title)}
+		if policy.Scope == setting.RuleTitleUniquenessGroup {
+			key.group = rule.RuleGroup
+		}
+		return key
+	}
+	byTitle := make(map[titleKey][]*models.AlertRule, len(rules))
+	for _, rule := range rules {
+		key := keyOf(rule)
+		byTitle[key] = append(byTitle[key], rule)
+	}
+	for _, rule := range written {
+		for _, existing := range byTitle[keyOf(&rule)] {
+			if existing.UID != rule.UID {
+				return models.ErrAlertRuleTitleNotUnique(rule, *existing, policy.Scope)
+			}
+		}
+	}
+	return nil
+}
+
 func (service *AlertRuleService) checkGroupLimits(group models.AlertRuleGroup) error {
 	if service.rulesPerRuleGroupLimit > 0 && int64(len(group.Rules)) > service.rulesPerRuleGroupLimit {
 		service.log.Warn("Large rule group was edited. Large groups are discouraged and may be rejected in the future.",
@@ -3,20 +3,28 @@ package provisioning
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/util"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -47,7 +55,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, 120, models.ProvenanceNone)
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
@@ -55,6 +63,155 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, interval, rule.IntervalSeconds)
 	})
 
+	t.Run("alert rule group data availability should be updated correctly", func(t *testing.T) {
+		rule := dummyRule("test#data-availability-1", orgID)
+		rule.RuleGroup = "data-availability"
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		err = ruleService.UpdateRuleGroupDataAvailability(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, time.Hour, 5*time.Minute, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		rule, _, err = ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, rule.DataAvailabilityPeriod)
+		require.Equal(t, 5*time.Minute, rule.DataAvailabilityDelay)
+
+		// new rules get the window of their group
+		other := dummyRule("test#data-availability-2", orgID)
+		other.RuleGroup = rule.RuleGroup
+		other, err = ruleService.CreateAlertRule(context.Background(), other, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, other.DataAvailabilityPeriod)
+		require.Equal(t, 5*time.Minute, other.DataAvailabilityDelay)
+
+		err = ruleService.UpdateRuleGroupDataAvailability(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, time.Minute, time.Hour, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("alert rule group shard affinity should be updated correctly", func(t *testing.T) {
+		rule := dummyRule("test#shard-affinity-1", orgID)
+		rule.RuleGroup = "shard-affinity"
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		err = ruleService.UpdateRuleGroupShardAffinity(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, "heavy", models.ProvenanceNone)
+		require.NoError(t, err)
+
+		group, err := ruleService.GetRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, "heavy", group.ShardAffinity)
+
+		// new rules get the shard affinity of their group
+		other := dummyRule("test#shard-affinity-2", orgID)
+		other.RuleGroup = rule.RuleGroup
+		other, err = ruleService.CreateAlertRule(context.Background(), other, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.Equal(t, "heavy", other.ShardAffinity)
+
+		err = ruleService.UpdateRuleGroupShardAffinity(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, "not a shard", models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		// groups cannot be pinned to a shard that is not configured, because no scheduler would evaluate them
+		err = ruleService.UpdateRuleGroupShardAffinity(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, "medium", models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("alert rule group incident hooks should be updated correctly", func(t *testing.T) {
+		rule := dummyRule("test#incident-hooks-1", orgID)
+		rule.RuleGroup = "incident-hooks"
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		hooks := []models.IncidentHook{{
+			Name:                     "tickets",
+			URL:                      "https://incidents.example.com/api/alerts",
+			AuthorizationCredentials: "ref+vault://secret/data/alerting/incidents#token",
+		}}
+		err = ruleService.UpdateRuleGroupIncidentHooks(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, hooks, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		group, err := ruleService.GetRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, hooks, group.IncidentHooks)
+
+		// new rules get the incident hooks of their group
+		other := dummyRule("test#incident-hooks-2", orgID)
+		other.RuleGroup = rule.RuleGroup
+		other, err = ruleService.CreateAlertRule(context.Background(), other, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.Equal(t, hooks, other.IncidentHooks)
+
+		// credentials must not be stored with the rules
+		hooks[0].AuthorizationCredentials = "s3cr3t"
+		err = ruleService.UpdateRuleGroupIncidentHooks(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, hooks, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		// credentials must refer to the secrets that the organization is allowed to read
+		hooks[0].AuthorizationCredentials = "ref+vault://secret/data/database/root#password"
+		err = ruleService.UpdateRuleGroupIncidentHooks(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, hooks, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "not allowed")
+	})
+
+	t.Run("alert rule group managed by should be stored per group", func(t *testing.T) {
+		rule := dummyRule("test#managed-by-1", orgID)
+		rule.RuleGroup = "managed-by"
+		rule, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		err = ruleService.provenanceStore.SetProvenanceWithMetadata(context.Background(), &rule, orgID, models.ProvenanceAPI, &models.ProvenanceMetadata{Source: "terraform"})
+		require.NoError(t, err)
+
+		managedBy := &models.ManagedBy{Title: "Managed by repository alerting-rules", URL: "https://github.com/example/alerting-rules"}
+		err = ruleService.UpdateRuleGroupManagedBy(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, managedBy, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		group, err := ruleService.GetRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, managedBy, group.ManagedBy)
+
+		// the provenance and the rest of the metadata are kept
+		_, provenance, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+		metadata, err := ruleService.provenanceStore.GetProvenanceMetadata(context.Background(), &rule, orgID)
+		require.NoError(t, err)
+		require.Equal(t, "terraform", metadata.Source)
+
+		// writing the provenance of a rule of the group keeps it
+		err = ruleService.provenanceStore.SetProvenance(context.Background(), &rule, orgID, models.ProvenanceAPI)
+		require.NoError(t, err)
+		group, err = ruleService.GetRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, managedBy, group.ManagedBy)
+
+		// replacing the group without it removes it
+		group.ManagedBy = nil
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+		group, err = ruleService.GetRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Nil(t, group.ManagedBy)
+
+		// replacing the group with it stores it, also when the rules are changed
+		group.ManagedBy = managedBy
+		group.Rules[0].Title = "managed by changed"
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+		group, err = ruleService.GetRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, managedBy, group.ManagedBy)
+
+		err = ruleService.UpdateRuleGroupManagedBy(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, &models.ManagedBy{Title: "repo", URL: "repo/file.yaml"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		// it is deleted with the last rule of the group
+		require.NoError(t, ruleService.DeleteAlertRule(context.Background(), orgID, group.Rules[0].UID, models.ProvenanceAPI))
+		managedByGroups, err := ruleService.ruleStore.GetRuleGroupsManagedBy(context.Background(), orgID)
+		require.NoError(t, err)
+		require.NotContains(t, managedByGroups, rule.GetGroupKey())
+	})
+
 	t.Run("if a folder was renamed the interval should be fetched from the renamed folder", func(t *testing.T) {
 		var orgID int64 = 2
 		rule := dummyRule("test#1", orgID)
@@ -83,6 +240,199 @@ func TestAlertRuleService(t *testing.T) {
 		}
 	})
 
+	t.Run("group replacement should not change the rules of the given group", func(t *testing.T) {
+		group := createDummyGroup("group-test-copy", orgID)
+		group.Rules = append(group.Rules, dummyRule("group-test-copy-rule-2", orgID))
+		original := make([]models.AlertRule, 0, len(group.Rules))
+		for i := range group.Rules {
+			original = append(original, *models.CopyRule(&group.Rules[i]))
+		}
+
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, original, group.Rules)
+
+		// The same group can be written again, e.g. in another organization.
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, original, group.Rules)
+	})
+
+	t.Run("group replacement should report the values set by the server", func(t *testing.T) {
+		group := createDummyGroup("group-test-defaults", orgID)
+		group.Rules = append(group.Rules, dummyRule("group-test-defaults-rule-2", orgID))
+		group.Rules[1].RuleGroup = ""
+		group.Rules[1].NamespaceUID = ""
+
+		defaults, err := ruleService.ReplaceRuleGroupWithDefaults(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-defaults")
+		require.NoError(t, err)
+		require.Len(t, readGroup.Rules, 2)
+		require.Equal(t, []ServerDefault{
+			{RuleIndex: 0, Field: "ruleGroup", Value: "group-test-defaults"},
+			{RuleIndex: 0, Field: "uid", Value: readGroup.Rules[0].UID},
+			{RuleIndex: 1, Field: "folderUID", Value: "my-namespace"},
+			{RuleIndex: 1, Field: "ruleGroup", Value: "group-test-defaults"},
+			{RuleIndex: 1, Field: "uid", Value: readGroup.Rules[1].UID},
+		}, defaults)
+
+		// Writing the values back makes the group converge.
+		for i := range group.Rules {
+			group.Rules[i].UID = readGroup.Rules[i].UID
+			group.Rules[i].RuleGroup = group.Title
+			group.Rules[i].NamespaceUID = group.FolderUID
+		}
+		defaults, err = ruleService.ReplaceRuleGroupWithDefaults(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Empty(t, defaults)
+	})
+
+	t.Run("group replacement should bake only the new rules", func(t *testing.T) {
+		group := createDummyGroup("group-test-bake", orgID)
+		group.BakePeriod = time.Hour
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-bake")
+		require.NoError(t, err)
+		require.Len(t, readGroup.Rules, 1)
+		baked := readGroup.Rules[0]
+		require.NotNil(t, baked.BakeUntil)
+		require.WithinDuration(t, time.Now().Add(time.Hour), *baked.BakeUntil, time.Minute)
+
+		updatedGroup := readGroup
+		updatedGroup.Rules = append(updatedGroup.Rules, dummyRule("group-test-bake-rule-2", orgID))
+		updatedGroup.Rules[0].Title = "baked rule"
+		updatedGroup.Rules[0].BakeUntil = nil
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, updatedGroup, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		readGroup, err = ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-bake")
+		require.NoError(t, err)
+		require.Len(t, readGroup.Rules, 2)
+		require.Equal(t, "baked rule", readGroup.Rules[0].Title)
+		require.WithinDuration(t, *baked.BakeUntil, *readGroup.Rules[0].BakeUntil, time.Second, "the bake period of a rule should not change")
+		require.Nil(t, readGroup.Rules[1].BakeUntil)
+
+		group = createDummyGroup("group-test-bake-negative", orgID)
+		group.BakePeriod = -time.Hour
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("group expiration should be set on the rules without their own", func(t *testing.T) {
+		groupExpiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+		ruleExpiresAt := groupExpiresAt.Add(time.Hour)
+		group := createDummyGroup("group-test-expiry", orgID)
+		group.ExpiresAt = &groupExpiresAt
+		group.Rules = append(group.Rules, dummyRule("group-test-expiry-rule-2", orgID))
+		group.Rules[1].ExpiresAt = &ruleExpiresAt
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-expiry")
+		require.NoError(t, err)
+		require.Len(t, readGroup.Rules, 2)
+		require.WithinDuration(t, groupExpiresAt, *readGroup.Rules[0].ExpiresAt, time.Second)
+		require.WithinDuration(t, ruleExpiresAt, *readGroup.Rules[1].ExpiresAt, time.Second)
+
+		readGroup.Rules[0].ExpiresAt = nil
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, readGroup, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+		readGroup, err = ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-expiry")
+		require.NoError(t, err)
+		require.Nil(t, readGroup.Rules[0].ExpiresAt, "the expiration of a rule should be removable")
+	})
+
+	t.Run("rules should keep their own interval in the group", func(t *testing.T) {
+		group := createDummyGroup("group-test-interval-override", orgID)
+		group.Rules = append(group.Rules, dummyRule("group-test-interval-override-rule-2", orgID))
+		group.Rules[1].IntervalOverrideSeconds = 300
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		rule := dummyRule("group-test-interval-override-rule-3", orgID)
+		rule.RuleGroup = group.Title
+		rule.IntervalOverrideSeconds = 120
+		_, err = ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		err = ruleService.UpdateRuleGroup(context.Background(), nil, orgID, "my-namespace", group.Title, 30, models.ProvenanceAPI)
+		require.NoError(t, err)
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", group.Title)
+		require.NoError(t, err)
+		require.EqualValues(t, 30, readGroup.Interval)
+		require.Len(t, readGroup.Rules, 3)
+		require.Zero(t, readGroup.Rules[0].IntervalOverrideSeconds)
+		require.EqualValues(t, 30, readGroup.Rules[0].EffectiveIntervalSeconds())
+		require.EqualValues(t, 300, readGroup.Rules[1].IntervalOverrideSeconds)
+		require.EqualValues(t, 300, readGroup.Rules[1].EffectiveIntervalSeconds())
+		require.EqualValues(t, 120, readGroup.Rules[2].IntervalOverrideSeconds)
+
+		group.Rules[1].IntervalOverrideSeconds = 25
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation, "the interval of a rule should be a multiple of the base interval")
+	})
+
+	t.Run("rules should keep their evaluation timeout", func(t *testing.T) {
+		rule := dummyRule("test-evaluation-timeout", orgID)
+		rule.EvaluationTimeout = 2 * time.Minute
+		created, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		read, _, err := ruleService.GetAlertRule(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Minute, read.EvaluationTimeout)
+
+		read.EvaluationTimeout = 10 * time.Minute
+		_, err = ruleService.UpdateAlertRule(context.Background(), read, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation, "the evaluation timeout should not be longer than the maximum")
+
+		read.EvaluationTimeout = 0
+		_, err = ruleService.UpdateAlertRule(context.Background(), read, models.ProvenanceAPI)
+		require.NoError(t, err)
+		read, _, err = ruleService.GetAlertRule(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		require.Zero(t, read.EvaluationTimeout, "the evaluation timeout should be removable")
+	})
+
+	t.Run("recording rules should be provisioned in groups with alert rules", func(t *testing.T) {
+		recording := createTestRule("test-recording-rule", "recording", orgID, "my-namespace")
+		recording.Condition = ""
+		recording.For = 0
+		recording.Record = models.Record{Metric: "disk_usage_ratio", From: "A", TargetDatasourceUID: "prometheus"}
+		group := models.AlertRuleGroup{
+			Title:     "recording",
+			Interval:  60,
+			FolderUID: "my-namespace",
+			Rules:     []models.AlertRule{createTestRule("test-recording-alert", "recording", orgID, "my-namespace"), recording},
+		}
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "recording")
+		require.NoError(t, err)
+		require.Len(t, readGroup.Rules, 2)
+		require.False(t, readGroup.Rules[0].IsRecordingRule())
+		require.Equal(t, recording.Record, readGroup.Rules[1].Record)
+		_, provenance, err := ruleService.GetAlertRule(context.Background(), orgID, readGroup.Rules[1].UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+
+		invalid := models.CopyRule(&recording)
+		invalid.Title = "test-recording-rule-invalid"
+		invalid.Record.Metric = "disk usage"
+		_, err = ruleService.CreateAlertRule(context.Background(), *invalid, models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation, "the metric name should be validated")
+
+		invalid.Record.Metric = "disk_usage_ratio"
+		invalid.For = time.Minute
+		_, err = ruleService.CreateAlertRule(context.Background(), *invalid, models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation, "recording rules should not have a pending period")
+	})
+
 	t.Run("alert rule should get interval from existing rule group", func(t *testing.T) {
 		rule := dummyRule("test#4", orgID)
 		rule.RuleGroup = "b"
@@ -90,7 +440,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.NoError(t, err)
 
 		var interval int64 = 120
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, rule.NamespaceUID, rule.RuleGroup, 120)
+		err = ruleService.UpdateRuleGroup(context.Background(), nil, orgID, rule.NamespaceUID, rule.RuleGroup, 120, models.ProvenanceNone)
 		require.NoError(t, err)
 
 		rule = dummyRule("test#4-1", orgID)
@@ -120,7 +470,7 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, int64(1), rule.Version)
 		require.Equal(t, int64(60), rule.IntervalSeconds)
 
-		err = ruleService.UpdateRuleGroup(context.Background(), orgID, namespaceUID, ruleGroup, newInterval)
+		err = ruleService.UpdateRuleGroup(context.Background(), nil, orgID, namespaceUID, ruleGroup, newInterval, models.ProvenanceNone)
 		require.NoError(t, err)
 
 		rule, _, err = ruleService.GetAlertRule(context.Background(), orgID, ruleUID)
@@ -558,13 +908,1029 @@ func TestCreateAlertRule(t *testing.T) {
 	})
 }
 
+func TestSetAlertGroupsFolderPaths(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	ruleService.dashboardService = dashboardService
+	queriesUIDs := func(uids ...string) any {
+		return mock.MatchedBy(func(q *dashboards.GetDashboardsQuery) bool {
+			return q.OrgID == 1 && slices.Equal(q.DashboardUIDs, uids)
+		})
+	}
+	dashboardService.On("GetDashboards", mock.Anything, queriesUIDs("services", "team")).Return([]*dashboards.Dashboard{
+		{UID: "services", Title: "Services", FolderUID: "team", IsFolder: true},
+		{UID: "team", Title: "Team", IsFolder: true},
+	}, nil).Once()
+	dashboardService.On("GetDashboards", mock.Anything, queriesUIDs("checkout")).Return([]*dashboards.Dashboard{
+		{UID: "checkout", Title: "Checkout", FolderUID: "services", IsFolder: true},
+	}, nil).Once()
+	dashboardService.On("GetDashboards", mock.Anything, queriesUIDs("services")).Return([]*dashboards.Dashboard{
+		{UID: "services", Title: "Services", FolderUID: "team", IsFolder: true},
+	}, nil).Once()
+	dashboardService.On("GetDashboards", mock.Anything, queriesUIDs("team")).Return([]*dashboards.Dashboard{
+		{UID: "team", Title: "Team", IsFolder: true},
+	}, nil).Once()
+
+	groups := []models.AlertRuleGroupWithFolderTitle{
+		{AlertRuleGroup: &models.AlertRuleGroup{Title: "a", FolderUID: "services"}, OrgID: 1, FolderTitle: "Services"},
+		{AlertRuleGroup: &models.AlertRuleGroup{Title: "b", FolderUID: "team"}, OrgID: 1, FolderTitle: "Team"},
+	}
+	require.NoError(t, ruleService.SetAlertGroupsFolderPaths(context.Background(), 1, groups))
+	require.Equal(t, []string{"Team", "Services"}, groups[0].FolderPath)
+	require.Equal(t, []string{"Team"}, groups[1].FolderPath)
+
+	t.Run("should fetch the parents of the folders level by level", func(t *testing.T) {
+		groups := []models.AlertRuleGroupWithFolderTitle{
+			{AlertRuleGroup: &models.AlertRuleGroup{Title: "a", FolderUID: "checkout"}, OrgID: 1, FolderTitle: "Checkout"},
+		}
+		require.NoError(t, ruleService.SetAlertGroupsFolderPaths(context.Background(), 1, groups))
+		require.Equal(t, []string{"Team", "Services", "Checkout"}, groups[0].FolderPath)
+	})
+
+	t.Run("should fail if a folder does not exist", func(t *testing.T) {
+		dashboardService.On("GetDashboards", mock.Anything, queriesUIDs("missing")).Return([]*dashboards.Dashboard{}, nil).Once()
+		groups := []models.AlertRuleGroupWithFolderTitle{
+			{AlertRuleGroup: &models.AlertRuleGroup{Title: "a", FolderUID: "missing"}, OrgID: 1},
+		}
+		require.ErrorContains(t, ruleService.SetAlertGroupsFolderPaths(context.Background(), 1, groups), "missing")
+	})
+}
+
+func TestUpdateRuleGroupInterval(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	requester := &user.SignedInUser{OrgID: orgID}
+
+	t.Run("should authorize an interval-only change of the rules", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		group := createDummyGroup("interval-group", orgID)
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		authz := &fakeRuleAccessControl{}
+		ruleService.authz = authz
+
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, requester, orgID, group.FolderUID, group.Title, 120, models.ProvenanceAPI))
+		require.Len(t, authz.changes, 1)
+		require.Len(t, authz.changes[0].Update, 1)
+		require.True(t, authz.changes[0].Update[0].IsIntervalOnly())
+
+		authz.changeErr = errors.New("denied")
+		err := ruleService.UpdateRuleGroup(ctx, requester, orgID, group.FolderUID, group.Title, 180, models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.changeErr)
+		stored, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.EqualValues(t, 120, stored.Interval)
+	})
+
+	t.Run("should not authorize the changes of users that can write all rules", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		group := createDummyGroup("provisioning-interval-group", orgID)
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		authz := &fakeRuleAccessControl{canWriteAll: true, changeErr: errors.New("denied")}
+		ruleService.authz = authz
+
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, requester, orgID, group.FolderUID, group.Title, 120, models.ProvenanceAPI))
+		require.Empty(t, authz.changes)
+	})
+
+	t.Run("should not change the rules of another provenance", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		group := createDummyGroup("file-interval-group", orgID)
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceFile))
+
+		err := ruleService.UpdateRuleGroup(ctx, requester, orgID, group.FolderUID, group.Title, 120, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceNotAllowed)
+	})
+
+	t.Run("should fail for unknown groups", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		err := ruleService.UpdateRuleGroup(ctx, requester, orgID, "my-namespace", "missing", 120, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+}
+
+func TestUpdateRuleGroupSettings(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	requester := &user.SignedInUser{OrgID: orgID}
+
+	updates := map[string]func(service *AlertRuleService, group models.AlertRuleGroup, provenance models.Provenance) error{
+		"data availability": func(service *AlertRuleService, group models.AlertRuleGroup, provenance models.Provenance) error {
+			return service.UpdateRuleGroupDataAvailability(ctx, requester, orgID, group.FolderUID, group.Title, time.Hour, 5*time.Minute, provenance)
+		},
+		"shard affinity": func(service *AlertRuleService, group models.AlertRuleGroup, provenance models.Provenance) error {
+			return service.UpdateRuleGroupShardAffinity(ctx, requester, orgID, group.FolderUID, group.Title, "heavy", provenance)
+		},
+		"incident hooks": func(service *AlertRuleService, group models.AlertRuleGroup, provenance models.Provenance) error {
+			hooks := []models.IncidentHook{{Name: "tickets", URL: "https://incidents.example.com/api/alerts"}}
+			return service.UpdateRuleGroupIncidentHooks(ctx, requester, orgID, group.FolderUID, group.Title, hooks, provenance)
+		},
+		"managed by": func(service *AlertRuleService, group models.AlertRuleGroup, provenance models.Provenance) error {
+			managedBy := &models.ManagedBy{Title: "Managed by repository alerting-rules"}
+			return service.UpdateRuleGroupManagedBy(ctx, requester, orgID, group.FolderUID, group.Title, managedBy, provenance)
+		},
+	}
+	for name, update := range updates {
+		t.Run(name+" changes should be authorized", func(t *testing.T) {
+			ruleService := createAlertRuleService(t)
+			group := createDummyGroup("settings-group", orgID)
+			require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+			before, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+			require.NoError(t, err)
+			authz := &fakeRuleAccessControl{changeErr: errors.New("denied")}
+			ruleService.authz = authz
+
+			err = update(&ruleService, group, models.ProvenanceAPI)
+			require.ErrorIs(t, err, authz.changeErr)
+			require.Len(t, authz.changes, 1)
+			require.Len(t, authz.changes[0].Update, 1)
+			after, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+			require.NoError(t, err)
+			require.Equal(t, before, after)
+		})
+
+		t.Run(name+" changes should not change the rules of another provenance", func(t *testing.T) {
+			ruleService := createAlertRuleService(t)
+			group := createDummyGroup("file-settings-group", orgID)
+			require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceFile))
+
+			err := update(&ruleService, group, models.ProvenanceAPI)
+			require.ErrorIs(t, err, ErrProvenanceNotAllowed)
+			require.NoError(t, update(&ruleService, group, models.ProvenanceFile))
+		})
+	}
+}
+
+func TestRuleGroupChangesLimit(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	ruleService.ruleGroupChangesLimit = 2
+	var orgID int64 = 1
+
+	group := createDummyGroup("limited-group", orgID)
+	group.Rules = append(group.Rules, dummyRule("limited-group-rule-2", orgID))
+	err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+	require.NoError(t, err)
+
+	t.Run("update with more changes than the limit should fail", func(t *testing.T) {
+		group := createDummyGroup("too-large-group", orgID)
+		group.Rules = append(group.Rules,
+			dummyRule("too-large-group-rule-2", orgID),
+			dummyRule("too-large-group-rule-3", orgID),
+		)
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupTooManyChangesBase)
+
+		_, err = ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+}
+
+func TestQuotaExemptProvenances(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	quotas := MockQuotaChecker{}
+	quotas.EXPECT().LimitExceeded()
+	ruleService.quotas = &quotas
+	ruleService.quotaExemptProvenances = []models.Provenance{models.ProvenanceFile}
+	ruleService.quotaExemptRulesLimit = 2
+	var orgID int64 = 1
+
+	t.Run("rules with exempt provenance are not limited by the quota", func(t *testing.T) {
+		_, err := ruleService.CreateAlertRule(context.Background(), dummyRule("exempt#1", orgID), models.ProvenanceFile, 0)
+		require.NoError(t, err)
+
+		group := createDummyGroup("exempt-group", orgID)
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceFile)
+		require.NoError(t, err)
+	})
+
+	t.Run("rules with exempt provenance are limited by their own budget", func(t *testing.T) {
+		_, err := ruleService.CreateAlertRule(context.Background(), dummyRule("exempt#2", orgID), models.ProvenanceFile, 0)
+		require.ErrorIs(t, err, models.ErrQuotaReached)
+	})
+
+	t.Run("other rules are limited by the quota", func(t *testing.T) {
+		_, err := ruleService.CreateAlertRule(context.Background(), dummyRule("not-exempt", orgID), models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, models.ErrQuotaReached)
+	})
+}
+
+func TestRuleTitleUniqueness(t *testing.T) {
+	var orgID int64 = 1
+
+	t.Run("folder scope should reject titles that differ only by case", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		ruleService.titleUniqueness = RuleTitleUniquenessPolicy{Scope: setting.RuleTitleUniquenessFolder}
+
+		existing, err := ruleService.CreateAlertRule(context.Background(), createTestRule("Disk full", "group-1", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		rule := createTestRule("disk FULL", "group-2", orgID, "folder-1")
+		rule.UID = "duplicate"
+		_, err = ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, models.ErrAlertRuleTitleNotUniqueBase)
+		require.ErrorContains(t, err, existing.UID)
+
+		_, _, err = ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound, "the rule should not be created")
+
+		_, err = ruleService.CreateAlertRule(context.Background(), createTestRule("disk FULL", "group-2", orgID, "folder-2"), models.ProvenanceAPI, 0)
+		require.NoError(t, err, "titles should be unique only within a folder")
+
+		other, err := ruleService.CreateAlertRule(context.Background(), createTestRule("Disk almost full", "group-1", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		other.Title = "DISK FULL"
+		_, err = ruleService.UpdateAlertRule(context.Background(), other, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTitleNotUniqueBase)
+	})
+
+	t.Run("group scope should reject duplicate titles within a group", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		ruleService.titleUniqueness = RuleTitleUniquenessPolicy{Scope: setting.RuleTitleUniquenessGroup}
+
+		group := models.AlertRuleGroup{
+			Title:     "group-1",
+			FolderUID: "folder-1",
+			Interval:  60,
+			Rules: []models.AlertRule{
+				createTestRule("CPU usage", "group-1", orgID, "folder-1"),
+				createTestRule("Memory usage", "group-1", orgID, "folder-1"),
+			},
+		}
+		group.Rules[0].UID = "cpu"
+		group.Rules[1].UID = "memory"
+		createRuleGroup(t, ruleService, orgID, group, models.ProvenanceAPI)
+
+		_, err := ruleService.CreateAlertRule(context.Background(), createTestRule("cpu usage", "group-2", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err, "titles should be unique only within a group")
+
+		group.Rules = append(group.Rules, createTestRule("memory USAGE", "group-1", orgID, "folder-1"))
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTitleNotUniqueBase)
+
+		stored, err := ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.Len(t, stored.Rules, 2, "the group should not be changed")
+	})
+
+	t.Run("should be enforced only in the configured folders", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		ruleService.titleUniqueness = RuleTitleUniquenessPolicy{Scope: setting.RuleTitleUniquenessFolder, FolderUIDs: []string{"folder-1"}}
+
+		_, err := ruleService.CreateAlertRule(context.Background(), createTestRule("Latency", "group-1", orgID, "folder-2"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(context.Background(), createTestRule("latency", "group-1", orgID, "folder-2"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		_, err = ruleService.CreateAlertRule(context.Background(), createTestRule("Latency", "group-1", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(context.Background(), createTestRule("latency", "group-1", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, models.ErrAlertRuleTitleNotUniqueBase)
+	})
+
+	t.Run("the policy of the organization should override the one of the server settings", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		ruleService.titleUniqueness = RuleTitleUniquenessPolicy{Scope: setting.RuleTitleUniquenessFolder}
+		ctx := context.Background()
+
+		policy, inherited, err := ruleService.GetRuleTitleUniqueness(ctx, orgID)
+		require.NoError(t, err)
+		require.True(t, inherited)
+		require.Equal(t, setting.RuleTitleUniquenessFolder, policy.Scope)
+
+		require.ErrorIs(t, ruleService.SetRuleTitleUniqueness(ctx, orgID, RuleTitleUniquenessPolicy{Scope: "org"}), ErrValidation)
+		require.NoError(t, ruleService.SetRuleTitleUniqueness(ctx, orgID, RuleTitleUniquenessPolicy{}))
+		policy, inherited, err = ruleService.GetRuleTitleUniqueness(ctx, orgID)
+		require.NoError(t, err)
+		require.False(t, inherited)
+		require.Empty(t, policy.Scope)
+
+		_, err = ruleService.CreateAlertRule(ctx, createTestRule("Errors", "group-1", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(ctx, createTestRule("errors", "group-1", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err, "titles should not need to be unique in the organization")
+		_, err = ruleService.CreateAlertRule(ctx, createTestRule("Errors", "group-1", orgID+1, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(ctx, createTestRule("errors", "group-1", orgID+1, "folder-1"), models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, models.ErrAlertRuleTitleNotUniqueBase, "other organizations should inherit the policy of the server settings")
+
+		require.NoError(t, ruleService.ResetRuleTitleUniqueness(ctx, orgID))
+		_, inherited, err = ruleService.GetRuleTitleUniqueness(ctx, orgID)
+		require.NoError(t, err)
+		require.True(t, inherited)
+	})
+}
+
+func TestMoveRuleGroup(t *testing.T) {
+	var orgID int64 = 1
+	from := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "folder-1", RuleGroup: "group-1"}
+	to := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "folder-2", RuleGroup: "group-2"}
+	folders := dashboards.NewFakeDashboardService(t)
+	folders.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+		return q.UID == to.NamespaceUID
+	})).Return(&dashboards.Dashboard{UID: to.NamespaceUID, IsFolder: true}, nil).Maybe()
+	folders.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+	setup := func(t *testing.T, provenance models.Provenance) (AlertRuleService, models.AlertRuleGroup) {
+		ruleService := createAlertRuleService(t)
+		ruleService.dashboardService = folders
+		group := models.AlertRuleGroup{
+			Title:     from.RuleGroup,
+			FolderUID: from.NamespaceUID,
+			Interval:  60,
+			Rules: []models.AlertRule{
+				createTestRule("CPU usage", from.RuleGroup, orgID, from.NamespaceUID),
+				createTestRule("Memory usage", from.RuleGroup, orgID, from.NamespaceUID),
+			},
+		}
+		group.Rules[0].UID = "cpu"
+		group.Rules[1].UID = "memory"
+		createRuleGroup(t, ruleService, orgID, group, provenance)
+		return ruleService, group
+	}
+
+	t.Run("should move the rules in place", func(t *testing.T) {
+		ruleService, group := setup(t, models.ProvenanceFile)
+
+		require.NoError(t, ruleService.MoveRuleGroup(context.Background(), nil, orgID, from, to, models.ProvenanceFile))
+
+		_, err := ruleService.GetRuleGroup(context.Background(), orgID, from.NamespaceUID, from.RuleGroup)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+		moved, err := ruleService.GetRuleGroup(context.Background(), orgID, to.NamespaceUID, to.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, group.Interval, moved.Interval)
+		require.Len(t, moved.Rules, 2)
+		for i, rule := range moved.Rules {
+			require.Equal(t, group.Rules[i].UID, rule.UID, "the rules should keep their UIDs")
+			require.Equal(t, i+1, rule.RuleGroupIndex)
+			require.Equal(t, to.NamespaceUID, rule.NamespaceUID)
+			require.Equal(t, to.RuleGroup, rule.RuleGroup)
+			_, provenance, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceFile, provenance, "the rules should keep their provenance")
+		}
+	})
+
+	t.Run("should fail if the target group exists", func(t *testing.T) {
+		ruleService, _ := setup(t, models.ProvenanceAPI)
+		_, err := ruleService.CreateAlertRule(context.Background(), createTestRule("Disk usage", to.RuleGroup, orgID, to.NamespaceUID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		err = ruleService.MoveRuleGroup(context.Background(), nil, orgID, from, to, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupExistsBase)
+	})
+
+	t.Run("should fail if the target folder does not exist", func(t *testing.T) {
+		ruleService, _ := setup(t, models.ProvenanceAPI)
+		missing := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "missing", RuleGroup: to.RuleGroup}
+
+		err := ruleService.MoveRuleGroup(context.Background(), nil, orgID, from, missing, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should authorize the move in both folders", func(t *testing.T) {
+		ruleService, _ := setup(t, models.ProvenanceAPI)
+		authz := &fakeRuleAccessControl{}
+		ruleService.authz = authz
+		requester := &user.SignedInUser{OrgID: orgID}
+
+		authz.readErr = errors.New("no access to the group")
+		err := ruleService.MoveRuleGroup(context.Background(), requester, orgID, from, to, models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.readErr)
+
+		authz.readErr = nil
+		authz.changeErr = errors.New("may not create rules in the target folder")
+		err = ruleService.MoveRuleGroup(context.Background(), requester, orgID, from, to, models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.changeErr)
+		require.Len(t, authz.changes, 1)
+		require.Equal(t, to, authz.changes[0].GroupKey)
+		require.Len(t, authz.changes[0].Update, 2)
+		for _, update := range authz.changes[0].Update {
+			require.Equal(t, from.NamespaceUID, update.Existing.NamespaceUID)
+			require.Equal(t, to.NamespaceUID, update.New.NamespaceUID)
+		}
+
+		_, err = ruleService.GetRuleGroup(context.Background(), orgID, from.NamespaceUID, from.RuleGroup)
+		require.NoError(t, err, "the group should not be moved")
+	})
+
+	t.Run("should fail if the group does not exist", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		ruleService.dashboardService = folders
+
+		err := ruleService.MoveRuleGroup(context.Background(), nil, orgID, from, to, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should not move provisioned rules with another provenance", func(t *testing.T) {
+		ruleService, _ := setup(t, models.ProvenanceFile)
+
+		err := ruleService.MoveRuleGroup(context.Background(), nil, orgID, from, to, models.ProvenanceAPI)
+		require.Error(t, err)
+
+		_, err = ruleService.GetRuleGroup(context.Background(), orgID, from.NamespaceUID, from.RuleGroup)
+		require.NoError(t, err, "the group should not be moved")
+	})
+
+	t.Run("should keep the group archived", func(t *testing.T) {
+		ruleService, _ := setup(t, models.ProvenanceAPI)
+		require.NoError(t, ruleService.ArchiveRuleGroup(context.Background(), nil, orgID, from.NamespaceUID, from.RuleGroup, models.ProvenanceAPI))
+
+		require.NoError(t, ruleService.MoveRuleGroup(context.Background(), nil, orgID, from, to, models.ProvenanceAPI))
+
+		archived, err := ruleService.ruleStore.GetArchivedRuleGroups(context.Background(), orgID)
+		require.NoError(t, err)
+		require.Equal(t, []models.AlertRuleGroupKey{to}, archived)
+	})
+}
+
+func TestReplaceRuleGroupRuleMoves(t *testing.T) {
+	var orgID int64 = 1
+	setup := func(t *testing.T) (AlertRuleService, models.AlertRuleGroup) {
+		ruleService := createAlertRuleService(t)
+		rule := createTestRule("CPU usage", "group-1", orgID, "folder-1")
+		rule.UID = "cpu"
+		_, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		return ruleService, models.AlertRuleGroup{
+			Title:     "group-2",
+			FolderUID: "folder-2",
+			Interval:  60,
+			Rules:     []models.AlertRule{rule},
+		}
+	}
+
+	t.Run("should reject rules of other groups", func(t *testing.T) {
+		ruleService, group := setup(t)
+		group.RuleMoves = models.RuleMovesReject
+
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleMoveConflictBase)
+		require.ErrorContains(t, err, "group-1")
+		require.ErrorContains(t, err, "folder-1")
+
+		rule, _, err := ruleService.GetAlertRule(context.Background(), orgID, "cpu")
+		require.NoError(t, err)
+		require.Equal(t, "group-1", rule.RuleGroup, "the rule should not be moved")
+	})
+
+	t.Run("should move rules of other groups", func(t *testing.T) {
+		for _, moves := range []models.RuleMovePolicy{"", models.RuleMovesMove} {
+			ruleService, group := setup(t)
+			group.RuleMoves = moves
+
+			require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI))
+
+			rule, _, err := ruleService.GetAlertRule(context.Background(), orgID, "cpu")
+			require.NoError(t, err)
+			require.Equal(t, "group-2", rule.RuleGroup)
+			require.Equal(t, "folder-2", rule.NamespaceUID)
+		}
+	})
+
+	t.Run("should not reject rules of the group", func(t *testing.T) {
+		ruleService, group := setup(t)
+		group.Title = "group-1"
+		group.FolderUID = "folder-1"
+		group.RuleMoves = models.RuleMovesReject
+		group.Rules[0].Title = "CPU usage too high"
+
+		require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI))
+	})
+}
+
+func TestAlertRuleVersionConflicts(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	// setup returns the rule as it was read, and changes it concurrently afterwards.
+	setup := func(t *testing.T) (AlertRuleService, models.AlertRule) {
+		ruleService := createAlertRuleService(t)
+		rule := createTestRule("CPU usage", "group-1", orgID, "folder-1")
+		rule.UID = "cpu"
+		_, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		read, _, err := ruleService.GetAlertRule(ctx, orgID, "cpu")
+		require.NoError(t, err)
+		concurrent := read
+		concurrent.Title = "CPU usage changed concurrently"
+		_, err = ruleService.UpdateAlertRule(ctx, concurrent, models.ProvenanceAPI)
+		require.NoError(t, err)
+		return ruleService, read
+	}
+
+	t.Run("UpdateAlertRuleIfUnchanged should reject a rule changed since it was read", func(t *testing.T) {
+		ruleService, rule := setup(t)
+		rule.Title = "CPU usage too high"
+
+		_, err := ruleService.UpdateAlertRuleIfUnchanged(ctx, rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrAlertRuleVersionConflict)
+
+		current, _, err := ruleService.GetAlertRule(ctx, orgID, "cpu")
+		require.NoError(t, err)
+		require.Equal(t, "CPU usage changed concurrently", current.Title)
+
+		rule.Version = current.Version
+		updated, err := ruleService.UpdateAlertRuleIfUnchanged(ctx, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, "CPU usage too high", updated.Title)
+	})
+
+	t.Run("UpdateAlertRule should overwrite a rule changed since it was read", func(t *testing.T) {
+		ruleService, rule := setup(t)
+		rule.Title = "CPU usage too high"
+
+		_, err := ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+	})
+
+	t.Run("ReplaceRuleGroup should reject a rule changed since it was read if versions are checked", func(t *testing.T) {
+		ruleService, rule := setup(t)
+		rule.Title = "CPU usage too high"
+		group := models.AlertRuleGroup{
+			Title:         "group-1",
+			FolderUID:     "folder-1",
+			Interval:      60,
+			CheckVersions: true,
+			Rules:         []models.AlertRule{rule},
+		}
+
+		err := ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrAlertRuleVersionConflict)
+
+		// Rules without a version are not checked.
+		group.Rules[0].Version = 0
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+	})
+
+	t.Run("ReplaceRuleGroup should reject a group changed since its version was read if versions are checked", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		group := models.AlertRuleGroup{
+			Title:     "group-1",
+			FolderUID: "folder-1",
+			Interval:  60,
+			Rules:     []models.AlertRule{createTestRule("CPU usage", "group-1", orgID, "folder-1")},
+		}
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		read, err := ruleService.GetRuleGroup(ctx, orgID, "folder-1", "group-1")
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(ctx, createTestRule("Memory usage", "group-1", orgID, "folder-1"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		read.Rules[0].Title = "CPU usage too high"
+		read.CheckVersions = true
+		read.ExpectedVersion = models.NewRuleGroupVersion(read.Rules)
+		err = ruleService.ReplaceRuleGroup(ctx, orgID, read, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrAlertRuleVersionConflict)
+		current, err := ruleService.GetRuleGroup(ctx, orgID, "folder-1", "group-1")
+		require.NoError(t, err)
+		require.Len(t, current.Rules, 2, "the rule created concurrently should not be deleted")
+
+		read.ExpectedVersion = models.NewRuleGroupVersion(current.Rules)
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, read, 0, models.ProvenanceAPI))
+	})
+
+	t.Run("ReplaceRuleGroup should overwrite a rule changed since it was read if versions are not checked", func(t *testing.T) {
+		ruleService, rule := setup(t)
+		rule.Title = "CPU usage too high"
+		group := models.AlertRuleGroup{
+			Title:     "group-1",
+			FolderUID: "folder-1",
+			Interval:  60,
+			Rules:     []models.AlertRule{rule},
+		}
+
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+	})
+}
+
+func TestReplaceRuleGroupIndexes(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	newGroup := func(rules ...models.AlertRule) models.AlertRuleGroup {
+		return models.AlertRuleGroup{
+			Title:     "my-cool-group",
+			FolderUID: "my-namespace",
+			Interval:  60,
+			Rules:     rules,
+		}
+	}
+	titles := func(t *testing.T, ruleService AlertRuleService) []string {
+		group, err := ruleService.GetRuleGroup(ctx, orgID, "my-namespace", "my-cool-group")
+		require.NoError(t, err)
+		result := make([]string, 0, len(group.Rules))
+		for i, rule := range group.Rules {
+			require.Equal(t, i+1, rule.RuleGroupIndex)
+			result = append(result, rule.Title)
+		}
+		return result
+	}
+
+	t.Run("should order the rules by their positions if they have no index", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		group := newGroup(dummyRule("b", orgID), dummyRule("a", orgID), dummyRule("c", orgID))
+
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		require.Equal(t, []string{"b", "a", "c"}, titles(t, ruleService))
+	})
+
+	t.Run("should order the rules by their indexes", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		group := newGroup(dummyRule("b", orgID), dummyRule("a", orgID), dummyRule("c", orgID))
+		group.Rules[0].RuleGroupIndex = 2
+		group.Rules[1].RuleGroupIndex = 1
+		group.Rules[2].RuleGroupIndex = 3
+
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		require.Equal(t, []string{"a", "b", "c"}, titles(t, ruleService))
+	})
+
+	t.Run("should reject indexes that are not contiguous", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		group := newGroup(dummyRule("a", orgID), dummyRule("b", orgID))
+		group.Rules[0].RuleGroupIndex = 1
+		group.Rules[1].RuleGroupIndex = 3
+
+		err := ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should add a created rule after the rules of its group", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, newGroup(dummyRule("a", orgID), dummyRule("b", orgID)), 0, models.ProvenanceAPI))
+
+		created, err := ruleService.CreateAlertRule(ctx, dummyRule("c", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		require.Equal(t, 3, created.RuleGroupIndex)
+		require.Equal(t, []string{"a", "b", "c"}, titles(t, ruleService))
+	})
+}
+
+func TestRuleLimits(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	ruleService.ruleLimits = models.RuleLimits{MaxQueries: 1}
+	var orgID int64 = 1
+
+	withTwoQueries := func(rule models.AlertRule) models.AlertRule {
+		query := rule.Data[0]
+		query.RefID = "B"
+		rule.Data = append(slices.Clone(rule.Data), query)
+		return rule
+	}
+
+	t.Run("create should reject a rule that exceeds the limits", func(t *testing.T) {
+		_, err := ruleService.CreateAlertRule(context.Background(), withTwoQueries(dummyRule("too-many-queries", orgID)), models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, models.ErrAlertRuleTooManyQueriesBase)
+	})
+
+	t.Run("update should reject a rule that exceeds the limits", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule("within-limits", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		_, err = ruleService.UpdateAlertRule(context.Background(), withTwoQueries(rule), models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTooManyQueriesBase)
+	})
+
+	t.Run("replacing a group should reject rules that exceed the limits", func(t *testing.T) {
+		group := createDummyGroup("limited-rules-group", orgID)
+		group.Rules[0] = withTwoQueries(group.Rules[0])
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTooManyQueriesBase)
+
+		_, err = ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+}
+
+func TestSearchAlertRules(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 1
+
+	provisioned, err := ruleService.CreateAlertRule(context.Background(), dummyRule("search provisioned", orgID), models.ProvenanceAPI, 0)
+	require.NoError(t, err)
+	_, err = ruleService.CreateAlertRule(context.Background(), dummyRule("search not provisioned", orgID), models.ProvenanceNone, 0)
+	require.NoError(t, err)
+	_, err = ruleService.CreateAlertRule(context.Background(), dummyRule("something else", orgID), models.ProvenanceNone, 0)
+	require.NoError(t, err)
+
+	result, provenances, err := ruleService.SearchAlertRules(context.Background(), models.SearchAlertRulesQuery{OrgID: orgID, Query: "search"})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, result.TotalCount)
+	require.Len(t, result.Rules, 2)
+	require.Equal(t, models.ProvenanceAPI, provenances[provisioned.UID])
+
+	result, _, err = ruleService.SearchAlertRules(context.Background(), models.SearchAlertRulesQuery{OrgID: orgID, Query: "search", Limit: 1, Page: 2})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, result.TotalCount)
+	require.Len(t, result.Rules, 1)
+}
+
+func TestFolderAnnotations(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 1
+	defaults := map[string]string{"runbook_base_url": "https://runbooks.example.com", "oncall_team": "sre"}
+	require.NoError(t, ruleService.SetFolderAnnotations(context.Background(), orgID, "my-namespace", defaults))
+
+	t.Run("invalid annotations are rejected", func(t *testing.T) {
+		err := ruleService.SetFolderAnnotations(context.Background(), orgID, "my-namespace", map[string]string{"": "value"})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		stored, err := ruleService.GetFolderAnnotations(context.Background(), orgID, "my-namespace")
+		require.NoError(t, err)
+		require.Equal(t, defaults, stored)
+	})
+
+	t.Run("created rules get the default annotations", func(t *testing.T) {
+		rule := dummyRule("folder-annotations#1", orgID)
+		rule.Annotations = map[string]string{"oncall_team": "db"}
+		created, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"oncall_team": "db"}, rule.Annotations, "annotations of the given rule should not be modified")
+
+		stored, _, err := ruleService.GetAlertRule(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"runbook_base_url": "https://runbooks.example.com", "oncall_team": "db"}, stored.Annotations)
+
+		stored.Annotations = nil
+		_, err = ruleService.UpdateAlertRule(context.Background(), stored, models.ProvenanceAPI)
+		require.NoError(t, err)
+		stored, _, err = ruleService.GetAlertRule(context.Background(), orgID, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, defaults, stored.Annotations, "updated rules should get the default annotations")
+	})
+
+	t.Run("rules of replaced groups get the default annotations", func(t *testing.T) {
+		group := createDummyGroup("folder-annotations-group", orgID)
+		require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI))
+
+		stored, err := ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.Equal(t, defaults, stored.Rules[0].Annotations)
+
+		exported := []models.AlertRuleGroupWithFolderTitle{models.NewAlertRuleGroupWithFolderTitle(stored.Rules[0].GetGroupKey(), stored.Rules, "folder")}
+		require.NoError(t, ruleService.stripFolderAnnotations(context.Background(), orgID, exported))
+		require.Empty(t, exported[0].Rules[0].Annotations, "exported rules should not repeat the default annotations")
+	})
+}
+
+func TestArchiveRuleGroup(t *testing.T) {
+	var orgID int64 = 1
+	setup := func(t *testing.T, provenance models.Provenance) (AlertRuleService, models.AlertRuleGroup) {
+		ruleService := createAlertRuleService(t)
+		group := createDummyGroup("archived-group", orgID)
+		require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, provenance))
+		return ruleService, group
+	}
+	listed := func(t *testing.T, ruleService AlertRuleService, filter models.ArchivedRuleGroupsFilter) int {
+		t.Helper()
+		rules, _, err := ruleService.GetAlertRules(context.Background(), nil, models.ListAlertRulesQuery{OrgID: orgID, ArchivedGroups: filter})
+		require.NoError(t, err)
+		return len(rules)
+	}
+
+	t.Run("should archive and unarchive the group without changing its rules", func(t *testing.T) {
+		ruleService, group := setup(t, models.ProvenanceAPI)
+		before, err := ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+
+		require.NoError(t, ruleService.ArchiveRuleGroup(context.Background(), nil, orgID, group.FolderUID, group.Title, models.ProvenanceAPI))
+		require.Zero(t, listed(t, ruleService, models.ArchivedRuleGroupsExclude))
+		require.Equal(t, len(group.Rules), listed(t, ruleService, models.ArchivedRuleGroupsOnly))
+		after, err := ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.True(t, after.Archived)
+		after.Archived = false
+		require.Equal(t, before, after, "the rules of archived groups should be kept")
+
+		require.NoError(t, ruleService.UnarchiveRuleGroup(context.Background(), nil, orgID, group.FolderUID, group.Title, models.ProvenanceAPI))
+		require.Equal(t, len(group.Rules), listed(t, ruleService, models.ArchivedRuleGroupsExclude))
+	})
+
+	t.Run("should fail if the group does not exist", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+
+		err := ruleService.ArchiveRuleGroup(context.Background(), nil, orgID, "my-namespace", "missing", models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should not archive provisioned groups with another provenance", func(t *testing.T) {
+		ruleService, group := setup(t, models.ProvenanceFile)
+
+		err := ruleService.ArchiveRuleGroup(context.Background(), nil, orgID, group.FolderUID, group.Title, models.ProvenanceAPI)
+		require.Error(t, err)
+		require.Zero(t, listed(t, ruleService, models.ArchivedRuleGroupsOnly))
+	})
+
+	t.Run("should authorize the archive as an update of the rules of the group", func(t *testing.T) {
+		ruleService, group := setup(t, models.ProvenanceAPI)
+		authz := &fakeRuleAccessControl{}
+		ruleService.authz = authz
+		authz.changeErr = errors.New("may not update the rules of the folder")
+
+		err := ruleService.ArchiveRuleGroup(context.Background(), &user.SignedInUser{OrgID: orgID}, orgID, group.FolderUID, group.Title, models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.changeErr)
+		require.Len(t, authz.changes, 1)
+		require.Equal(t, group.FolderUID, authz.changes[0].GroupKey.NamespaceUID)
+		require.Len(t, authz.changes[0].Update, len(group.Rules))
+		require.Empty(t, authz.changes[0].New)
+		require.Empty(t, authz.changes[0].Delete)
+		require.Zero(t, listed(t, ruleService, models.ArchivedRuleGroupsOnly))
+	})
+
+	t.Run("should not archive a group that is created again after it was deleted", func(t *testing.T) {
+		ruleService, group := setup(t, models.ProvenanceAPI)
+		require.NoError(t, ruleService.ArchiveRuleGroup(context.Background(), nil, orgID, group.FolderUID, group.Title, models.ProvenanceAPI))
+		require.NoError(t, ruleService.DeleteRuleGroup(context.Background(), orgID, group.FolderUID, group.Title, models.ProvenanceAPI))
+
+		require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI))
+		require.Zero(t, listed(t, ruleService, models.ArchivedRuleGroupsOnly))
+	})
+}
+
+func TestAlertRuleServiceConcurrency(t *testing.T) {
+	var orgID int64 = 1
+	const workers = 5
+
+	createService := func(t *testing.T) (AlertRuleService, *FakeStore) {
+		t.Helper()
+		fakeStore := NewFakeStore()
+		// Make reads slow, so that concurrent calls read the same state.
+		fakeStore.Latency = func(method string) time.Duration {
+			if method == "ListAlertRules" || method == "GetAlertRuleByUID" {
+				return 10 * time.Millisecond
+			}
+			return 0
+		}
+		quotas := MockQuotaChecker{}
+		quotas.EXPECT().LimitOK()
+		return AlertRuleService{
+			ruleStore:              fakeStore,
+			provenanceStore:        fakeStore,
+			quotas:                 &quotas,
+			xact:                   fakeStore,
+			log:                    log.NewNopLogger(),
+			baseIntervalSeconds:    10,
+			defaultIntervalSeconds: 60,
+		}, fakeStore
+	}
+
+	t.Run("concurrent replacements of a group should not merge the groups", func(t *testing.T) {
+		ruleService, fakeStore := createService(t)
+
+		var wg sync.WaitGroup
+		errs := make([]error, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				group := createDummyGroup("concurrent-group", orgID)
+				group.Rules[0].Title = fmt.Sprintf("rule-%d", i)
+				errs[i] = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		require.Len(t, fakeStore.Rules(orgID), 1)
+	})
+
+	t.Run("concurrent updates of a rule should all be applied", func(t *testing.T) {
+		ruleService, fakeStore := createService(t)
+		rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule("concurrent-rule", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		errs := make([]error, workers)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				update := rule
+				update.Title = fmt.Sprintf("concurrent-rule-%d", i)
+				_, errs[i] = ruleService.UpdateAlertRule(context.Background(), update, models.ProvenanceAPI)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		rules := fakeStore.Rules(orgID)
+		require.Len(t, rules, 1)
+		require.Equal(t, rule.Version+workers, rules[0].Version)
+	})
+
+	t.Run("conflict should roll back the whole change", func(t *testing.T) {
+		ruleService, fakeStore := createService(t)
+		group := createDummyGroup("conflict-group", orgID)
+		group.Rules = append(group.Rules, dummyRule("conflict-group-rule-2", orgID))
+		require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI))
+		before := fakeStore.Rules(orgID)
+		require.Len(t, before, 2)
+
+		fakeStore.Conflict = func(method string) bool {
+			return method == "UpdateAlertRules"
+		}
+		// Delete the second rule and change the first one, which conflicts.
+		updated := *before[0]
+		updated.Title = "conflict-group-rule-1-updated"
+		group.Rules = []models.AlertRule{updated}
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, store.ErrOptimisticLock)
+
+		require.Equal(t, before, fakeStore.Rules(orgID))
+	})
+
+	t.Run("replacing a group should not modify the rules of the caller", func(t *testing.T) {
+		ruleService, _ := createService(t)
+		group := createDummyGroup("caller-group", orgID)
+		group.Rules[0].RuleGroup = "something different"
+		require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI))
+		require.Equal(t, "something different", group.Rules[0].RuleGroup)
+	})
+
+	t.Run("locked group should fail the change", func(t *testing.T) {
+		ruleService, fakeStore := createService(t)
+		group := createDummyGroup("locked-group", orgID)
+		require.NoError(t, ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI))
+		before := fakeStore.Rules(orgID)
+
+		var locked []models.AlertRuleGroupKey
+		fakeStore.Locked = func(key models.AlertRuleGroupKey) bool {
+			locked = append(locked, key)
+			return key.RuleGroup == "locked-group"
+		}
+		group.Rules[0].Title = "locked-group-rule-updated"
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, store.ErrGroupLocked)
+		require.Equal(t, before, fakeStore.Rules(orgID))
+
+		locked = nil
+		_, err = ruleService.CreateAlertRule(context.Background(), dummyRule("other-group-rule", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		require.Equal(t, []models.AlertRuleGroupKey{{OrgID: orgID, NamespaceUID: "my-namespace", RuleGroup: "my-cool-group"}}, locked, "changes of other groups should lock only them")
+	})
+}
+
+func TestDeltaGroupKeys(t *testing.T) {
+	var orgID int64 = 1
+	target := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "my-namespace", RuleGroup: "target"}
+	source := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "other-namespace", RuleGroup: "source"}
+	moved := createTestRule("moved", source.RuleGroup, orgID, source.NamespaceUID)
+	updated := createTestRule("moved", target.RuleGroup, orgID, target.NamespaceUID)
+	created := createTestRule("created", target.RuleGroup, orgID, target.NamespaceUID)
+
+	keys := deltaGroupKeys(&store.GroupDelta{
+		GroupKey: target,
+		New:      []*models.AlertRule{&created, nil},
+		Update:   []store.RuleDelta{{Existing: &moved, New: &updated}},
+	})
+	unique := make(map[models.AlertRuleGroupKey]struct{})
+	for _, key := range keys {
+		unique[key] = struct{}{}
+	}
+	require.Equal(t, map[models.AlertRuleGroupKey]struct{}{target: {}, source: {}}, unique, "the groups the rules are moved from should be locked")
+
+	require.Empty(t, deltaGroupKeys(&store.GroupDelta{}))
+}
+
+func TestNewAlertRuleService(t *testing.T) {
+	st := store.DBstore{}
+	cfg := AlertRuleServiceConfig{
+		RuleStore:            st,
+		ProvenanceStore:      st,
+		DashboardService:     dashboards.NewFakeDashboardService(t),
+		Quotas:               &MockQuotaChecker{},
+		Xact:                 newNopTransactionManager(),
+		NotificationSettings: &NotificationSettingsValidatorProviderFake{},
+		Log:                  log.NewNopLogger(),
+	}
+	service, err := NewAlertRuleService(cfg)
+	require.NoError(t, err)
+	require.NotNil(t, service)
+
+	t.Run("should fail without a required dependency", func(t *testing.T) {
+		missing := cfg
+		missing.DashboardService = nil
+		_, err := NewAlertRuleService(missing)
+		require.ErrorContains(t, err, "requires a dashboard service")
+	})
+
+	t.Run("should require a trash store if deleted rules are kept", func(t *testing.T) {
+		withTrash := cfg
+		withTrash.Settings.AlertRuleTrashRetention = time.Hour
+		_, err := NewAlertRuleService(withTrash)
+		require.ErrorContains(t, err, "requires a trash store")
+	})
+}
+
 func createAlertRuleService(t *testing.T) AlertRuleService {
 	t.Helper()
 	sqlStore := db.InitTestDB(t)
 	store := store.DBstore{
 		SQLStore: sqlStore,
 		Cfg: setting.UnifiedAlertingSettings{
-			BaseInterval: time.Second * 10,
+			BaseInterval:             time.Second * 10,
+			MaxRuleEvaluationTimeout: time.Minute * 5,
+			SchedulerShards:          []string{"heavy"},
 		},
 		Logger: log.NewNopLogger(),
 	}
@@ -574,10 +1940,15 @@ func createAlertRuleService(t *testing.T) AlertRuleService {
 		ruleStore:              store,
 		provenanceStore:        store,
 		quotas:                 &quotas,
-		xact:                   sqlStore,
+		xact:                   &store,
 		log:                    log.New("testing"),
 		baseIntervalSeconds:    10,
 		defaultIntervalSeconds: 60,
+		kv:                     kvstore.NewFakeKVStore(),
+		schedulerShards:        store.Cfg.SchedulerShards,
+		secretReferences: setting.UnifiedAlertingSecretReferencesSettings{
+			AllowedPrefixes: []string{"secret/data/alerting/"},
+		},
 	}
 }
 
@@ -585,6 +1956,17 @@ func dummyRule(title string, orgID int64) models.AlertRule {
 	return createTestRule(title, "my-cool-group", orgID, "my-namespace")
 }
 
+// createRuleGroup creates the rules of the group with their UIDs, which the replacement of a group only accepts for
+// existing rules, and then replaces the group to set its settings.
+func createRuleGroup(t *testing.T, service AlertRuleService, orgID int64, group models.AlertRuleGroup, provenance models.Provenance) {
+	t.Helper()
+	for _, rule := range group.Rules {
+		_, err := service.CreateAlertRule(context.Background(), rule, provenance, 0)
+		require.NoError(t, err)
+	}
+	require.NoError(t, service.ReplaceRuleGroup(context.Background(), orgID, group, 0, provenance))
+}
+
 func createTestRule(title string, groupTitle string, orgID int64, namespace string) models.AlertRule {
 	return models.AlertRule{
 		OrgID:           orgID,
@@ -0,0 +1,97 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// AlertmanagerRoutingService manages the settings that decide which Alertmanagers handle the alerts of an organization.
+// They are stored in the admin configuration of the organization.
+type AlertmanagerRoutingService struct {
+	adminConfigStore store.AdminConfigurationStore
+	provenanceStore  ProvisioningStore
+	xact             TransactionManager
+	log              log.Logger
+}
+
+func NewAlertmanagerRoutingService(adminConfigStore store.AdminConfigurationStore, prov ProvisioningStore,
+	xact TransactionManager, log log.Logger) *AlertmanagerRoutingService {
+	return &AlertmanagerRoutingService{
+		adminConfigStore: adminConfigStore,
+		provenanceStore:  prov,
+		xact:             xact,
+		log:              log,
+	}
+}
+
+// GetAlertmanagerRouting returns the routing of the alerts of the organization. Alerts are sent to all Alertmanagers
+// if the organization has no admin configuration.
+func (s *AlertmanagerRoutingService) GetAlertmanagerRouting(ctx context.Context, orgID int64) (definitions.AlertmanagerRouting, error) {
+	result := definitions.AlertmanagerRouting{SendAlertsTo: definitions.AllAlertmanagers}
+	cfg, err := s.adminConfigStore.GetAdminConfiguration(orgID)
+	if err != nil && !errors.Is(err, store.ErrNoAdminConfiguration) {
+		return definitions.AlertmanagerRouting{}, err
+	}
+	if cfg != nil {
+		result.SendAlertsTo = definitions.AlertmanagersChoice(cfg.SendAlertsTo.String())
+		if cfg.ExternalAlertmanagerMatchers != "" {
+			if err := json.Unmarshal([]byte(cfg.ExternalAlertmanagerMatchers), &result.ExternalMatchers); err != nil {
+				return definitions.AlertmanagerRouting{}, fmt.Errorf("failed to parse external alertmanager matchers: %w", err)
+			}
+		}
+	}
+
+	provenance, err := s.provenanceStore.GetProvenance(ctx, &result, orgID)
+	if err != nil {
+		return definitions.AlertmanagerRouting{}, err
+	}
+	result.Provenance = definitions.Provenance(provenance)
+	return result, nil
+}
+
+// UpdateAlertmanagerRouting replaces the routing of the alerts of the organization.
+func (s *AlertmanagerRoutingService) UpdateAlertmanagerRouting(ctx context.Context, orgID int64, routing definitions.AlertmanagerRouting, p models.Provenance) error {
+	if err := routing.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	sendAlertsTo, err := models.StringToAlertmanagersChoice(string(routing.SendAlertsTo))
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	cfg := &models.AdminConfiguration{
+		OrgID:        orgID,
+		SendAlertsTo: sendAlertsTo,
+	}
+	if len(routing.ExternalMatchers) > 0 {
+		matchers, err := json.Marshal(routing.ExternalMatchers)
+		if err != nil {
+			return err
+		}
+		cfg.ExternalAlertmanagerMatchers = string(matchers)
+	}
+
+	return s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.adminConfigStore.UpdateAdminConfiguration(ctx, store.UpdateAdminConfigurationCmd{AdminConfiguration: cfg}); err != nil {
+			return err
+		}
+		return s.provenanceStore.SetProvenance(ctx, &routing, orgID, p)
+	})
+}
+
+// ResetAlertmanagerRouting removes the routing of the alerts of the organization, which then sends alerts to all
+// Alertmanagers.
+func (s *AlertmanagerRoutingService) ResetAlertmanagerRouting(ctx context.Context, orgID int64) error {
+	return s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.adminConfigStore.DeleteAdminConfiguration(ctx, orgID); err != nil {
+			return err
+		}
+		return s.provenanceStore.DeleteProvenance(ctx, &definitions.AlertmanagerRouting{}, orgID)
+	})
+}
@@ -0,0 +1,84 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestAlertmanagerRoutingService(t *testing.T) {
+	orgID := int64(1)
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "ops")
+	require.NoError(t, err)
+
+	t.Run("alerts are sent to all alertmanagers without admin configuration", func(t *testing.T) {
+		sut, adminStore := createAlertmanagerRoutingServiceSut()
+		adminStore.EXPECT().GetAdminConfiguration(orgID).Return(nil, store.ErrNoAdminConfiguration)
+
+		result, err := sut.GetAlertmanagerRouting(context.Background(), orgID)
+
+		require.NoError(t, err)
+		require.Equal(t, definitions.AllAlertmanagers, result.SendAlertsTo)
+		require.Empty(t, result.ExternalMatchers)
+	})
+
+	t.Run("update stores the routing with its provenance", func(t *testing.T) {
+		sut, adminStore := createAlertmanagerRoutingServiceSut()
+		var saved *models.AdminConfiguration
+		adminStore.EXPECT().UpdateAdminConfiguration(mock.Anything, mock.Anything).Run(func(_ context.Context, cmd store.UpdateAdminConfigurationCmd) {
+			saved = cmd.AdminConfiguration
+		}).Return(nil)
+
+		err := sut.UpdateAlertmanagerRouting(context.Background(), orgID, definitions.AlertmanagerRouting{
+			ExternalMatchers: definitions.ObjectMatchers{matcher},
+		}, models.ProvenanceFile)
+		require.NoError(t, err)
+		require.Equal(t, models.AllAlertmanagers, saved.SendAlertsTo)
+		require.NotEmpty(t, saved.ExternalAlertmanagerMatchers)
+
+		adminStore.EXPECT().GetAdminConfiguration(orgID).Return(saved, nil)
+		result, err := sut.GetAlertmanagerRouting(context.Background(), orgID)
+		require.NoError(t, err)
+		require.Equal(t, definitions.AllAlertmanagers, result.SendAlertsTo)
+		require.Equal(t, definitions.ObjectMatchers{matcher}, result.ExternalMatchers)
+		require.Equal(t, definitions.Provenance(models.ProvenanceFile), result.Provenance)
+	})
+
+	t.Run("update rejects matchers when alerts are not sent to all alertmanagers", func(t *testing.T) {
+		sut, _ := createAlertmanagerRoutingServiceSut()
+
+		err := sut.UpdateAlertmanagerRouting(context.Background(), orgID, definitions.AlertmanagerRouting{
+			SendAlertsTo:     definitions.ExternalAlertmanagers,
+			ExternalMatchers: definitions.ObjectMatchers{matcher},
+		}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("update rejects an unknown alertmanager choice", func(t *testing.T) {
+		sut, _ := createAlertmanagerRoutingServiceSut()
+
+		err := sut.UpdateAlertmanagerRouting(context.Background(), orgID, definitions.AlertmanagerRouting{
+			SendAlertsTo: "some",
+		}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func createAlertmanagerRoutingServiceSut() (*AlertmanagerRoutingService, *store.AdminConfigurationStoreMock) {
+	adminStore := &store.AdminConfigurationStoreMock{}
+	return &AlertmanagerRoutingService{
+		adminConfigStore: adminStore,
+		provenanceStore:  fakes.NewFakeProvisioningStore(),
+		xact:             newNopTransactionManager(),
+		log:              log.NewNopLogger(),
+	}, adminStore
+}
@@ -0,0 +1,145 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// OrgResult is the result of a bulk operation in one organization. Err is nil if the operation succeeded.
+type OrgResult struct {
+	OrgID int64
+	Err   error
+}
+
+// BulkService runs provisioning operations in many organizations at once, for instance administrators that manage
+// the same alerting resources in all organizations. The operation runs in a separate transaction in each organization,
+// so that a failure in one organization does not prevent the others from being changed.
+type BulkService struct {
+	orgStore      store.OrgStore
+	alertRules    *AlertRuleService
+	contactPoints *ContactPointService
+	policies      *NotificationPolicyService
+	xact          TransactionManager
+	log           log.Logger
+}
+
+func NewBulkService(orgs store.OrgStore, alertRules *AlertRuleService, contactPoints *ContactPointService,
+	policies *NotificationPolicyService, xact TransactionManager, log log.Logger) *BulkService {
+	return &BulkService{
+		orgStore:      orgs,
+		alertRules:    alertRules,
+		contactPoints: contactPoints,
+		policies:      policies,
+		xact:          xact,
+		log:           log,
+	}
+}
+
+// InstallRuleGroups replaces the given rule groups in the organizations. The folders of the groups must exist in
+// every organization. Rules should have a UID, otherwise they are created again each time the groups are installed.
+func (s *BulkService) InstallRuleGroups(ctx context.Context, orgIDs []int64, groups []models.AlertRuleGroup, userID int64, provenance models.Provenance) ([]OrgResult, error) {
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("%w: no rule group to install", ErrValidation)
+	}
+	return s.run(ctx, orgIDs, func(ctx context.Context, orgID int64) error {
+		for _, group := range groups {
+			if err := s.alertRules.ReplaceRuleGroup(ctx, orgID, group, userID, provenance); err != nil {
+				return fmt.Errorf("failed to install rule group '%s' in folder '%s': %w", group.Title, group.FolderUID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateContactPointSecret sets the secure setting of all the integrations of the contact point that have it.
+func (s *BulkService) UpdateContactPointSecret(ctx context.Context, orgIDs []int64, name, key, value string, provenance models.Provenance) ([]OrgResult, error) {
+	if name == "" || key == "" {
+		return nil, fmt.Errorf("%w: contact point name and secure setting key are required", ErrValidation)
+	}
+	return s.run(ctx, orgIDs, func(ctx context.Context, orgID int64) error {
+		contactPoints, err := s.contactPoints.GetContactPoints(ctx, ContactPointQuery{Name: name, OrgID: orgID}, nil)
+		if err != nil {
+			return err
+		}
+		if len(contactPoints) == 0 {
+			return fmt.Errorf("%w: contact point '%s' does not exist", ErrNotFound, name)
+		}
+		updated := 0
+		for _, contactPoint := range contactPoints {
+			secretKeys, err := channels_config.GetSecretKeysForContactPointType(contactPoint.Type)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+			}
+			if !slices.Contains(secretKeys, key) {
+				continue
+			}
+			contactPoint.Settings.Set(key, value)
+			if err := s.contactPoints.UpdateContactPoint(ctx, orgID, contactPoint, provenance); err != nil {
+				return err
+			}
+			updated++
+		}
+		if updated == 0 {
+			return fmt.Errorf("%w: contact point '%s' has no secure setting '%s'", ErrValidation, name, key)
+		}
+		return nil
+	})
+}
+
+// ApplyPolicy adds the policy as a child of the root of the notification policy tree of the organizations. A child
+// policy with the same object matchers is replaced, so that applying the same policy again updates it.
+func (s *BulkService) ApplyPolicy(ctx context.Context, orgIDs []int64, policy definitions.Route, provenance models.Provenance) ([]OrgResult, error) {
+	if len(policy.ObjectMatchers) == 0 {
+		return nil, fmt.Errorf("%w: the policy must have object matchers", ErrValidation)
+	}
+	return s.run(ctx, orgIDs, func(ctx context.Context, orgID int64) error {
+		tree, err := s.policies.GetPolicyTree(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		tree.Provenance = ""
+		child := policy
+		idx := slices.IndexFunc(tree.Routes, func(r *definitions.Route) bool {
+			return slices.EqualFunc(r.ObjectMatchers, child.ObjectMatchers, func(a, b *labels.Matcher) bool {
+				return a.String() == b.String()
+			})
+		})
+		if idx < 0 {
+			tree.Routes = append(tree.Routes, &child)
+		} else {
+			tree.Routes[idx] = &child
+		}
+		return s.policies.UpdatePolicyTree(ctx, orgID, tree, provenance)
+	})
+}
+
+// run runs the operation in each organization, or in all organizations if none is given.
+func (s *BulkService) run(ctx context.Context, orgIDs []int64, op func(ctx context.Context, orgID int64) error) ([]OrgResult, error) {
+	if len(orgIDs) == 0 {
+		var err error
+		orgIDs, err = s.orgStore.GetOrgs(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	results := make([]OrgResult, 0, len(orgIDs))
+	for _, orgID := range orgIDs {
+		err := s.xact.InTransaction(ctx, func(ctx context.Context) error {
+			return op(ctx, orgID)
+		})
+		if err != nil {
+			s.log.Warn("Bulk provisioning operation failed in organization", "org", orgID, "error", err)
+		}
+		results = append(results, OrgResult{OrgID: orgID, Err: err})
+	}
+	return results, nil
+}
@@ -0,0 +1,84 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+)
+
+func TestBulkService(t *testing.T) {
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "ops")
+	require.NoError(t, err)
+	policy := definitions.Route{
+		Receiver:       "grafana-default-email",
+		ObjectMatchers: definitions.ObjectMatchers{matcher},
+	}
+
+	t.Run("policy is applied in all organizations", func(t *testing.T) {
+		sut := createBulkServiceSut(t, []int64{1, 2})
+
+		results, err := sut.ApplyPolicy(context.Background(), nil, policy, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+		require.Equal(t, []OrgResult{{OrgID: 1}, {OrgID: 2}}, results)
+		tree, err := sut.policies.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, tree.Routes, 2)
+		require.Equal(t, "grafana-default-email", tree.Routes[1].Receiver)
+	})
+
+	t.Run("policy with the same matchers is replaced", func(t *testing.T) {
+		sut := createBulkServiceSut(t, []int64{1})
+
+		_, err := sut.ApplyPolicy(context.Background(), nil, policy, models.ProvenanceAPI)
+		require.NoError(t, err)
+		updated := policy
+		updated.Continue = true
+		results, err := sut.ApplyPolicy(context.Background(), []int64{1}, updated, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, results[0].Err)
+
+		tree, err := sut.policies.GetPolicyTree(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, tree.Routes, 2)
+		require.True(t, tree.Routes[1].Continue)
+	})
+
+	t.Run("failures are reported by organization", func(t *testing.T) {
+		sut := createBulkServiceSut(t, []int64{1, 2})
+		invalid := policy
+		invalid.Receiver = "missing"
+
+		results, err := sut.ApplyPolicy(context.Background(), nil, invalid, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			require.ErrorIs(t, result.Err, ErrValidation)
+		}
+	})
+
+	t.Run("policy without matchers is rejected", func(t *testing.T) {
+		sut := createBulkServiceSut(t, []int64{1})
+
+		_, err := sut.ApplyPolicy(context.Background(), nil, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func createBulkServiceSut(t *testing.T, orgs []int64) *BulkService {
+	return &BulkService{
+		orgStore: notifier.NewFakeOrgStore(t, orgs),
+		policies: createNotificationPolicyServiceSut(),
+		xact:     newNopTransactionManager(),
+		log:      log.NewNopLogger(),
+	}
+}
@@ -0,0 +1,263 @@
+// Package client provides a Go client for the alert rule provisioning HTTP API of Grafana. It uses the request and
+// response models of the server, so that callers do not have to maintain their own copies of them.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngclient "github.com/grafana/grafana/pkg/services/ngalert/client"
+)
+
+const (
+	provisioningPath = "/api/v1/provisioning"
+
+	disableProvenanceHeaderName = "X-Disable-Provenance"
+	orgIDHeaderName             = "X-Grafana-Org-Id"
+)
+
+// Config contains the configuration of a Client.
+type Config struct {
+	// URL is the root URL of the Grafana instance, e.g. https://grafana.example.com/.
+	URL *url.URL
+	// Token is a service account token or an API key. If empty, basic authentication is used if User is set.
+	Token    string
+	User     string
+	Password string
+	// OrgID is the organization to manage the rules of. If zero, the current organization of the user is used.
+	OrgID int64
+	// DisableProvenance makes the rules written by the client editable in the Grafana UI.
+	DisableProvenance bool
+	// Requester executes the requests. If nil, http.DefaultClient is used.
+	Requester ngclient.Requester
+}
+
+// Client manages alert rules through the provisioning HTTP API. Its methods mirror the operations of
+// provisioning.AlertRuleService.
+type Client struct {
+	cfg Config
+}
+
+// APIError is returned when the API responds with an unexpected status code.
+type APIError struct {
+	StatusCode int
+	// MessageID identifies the kind of the error. It is empty for errors that do not have one.
+	MessageID string `json:"messageId"`
+	Message   string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("provisioning API responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("provisioning API responded with status %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound returns true if the error is an APIError caused by a resource that does not exist.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// IsConflict returns true if the error is an APIError caused by a conflict with the stored resources, e.g. a rule that
+// was changed since its version was read.
+func IsConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+func New(cfg Config) (*Client, error) {
+	if cfg.URL == nil {
+		return nil, errors.New("URL of Grafana must be specified")
+	}
+	if cfg.Requester == nil {
+		cfg.Requester = http.DefaultClient
+	}
+	return &Client{cfg: cfg}, nil
+}
+
+// GetAlertRules returns all alert rules of the organization.
+func (c *Client) GetAlertRules(ctx context.Context) (definitions.ProvisionedAlertRules, error) {
+	var result definitions.ProvisionedAlertRules
+	if err := c.do(ctx, http.MethodGet, "alert-rules", nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SearchAlertRules returns the alert rules that match the parameters.
+func (c *Client) SearchAlertRules(ctx context.Context, params definitions.AlertRulesSearchParameters) (*definitions.ProvisionedAlertRulesSearchResult, error) {
+	query := url.Values{}
+	if params.Query != "" {
+		query.Set("query", params.Query)
+	}
+	for _, uid := range params.FolderUID {
+		query.Add("folderUid", uid)
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.FormatInt(params.Limit, 10))
+	}
+	if params.Page > 0 {
+		query.Set("page", strconv.FormatInt(params.Page, 10))
+	}
+	result := &definitions.ProvisionedAlertRulesSearchResult{}
+	if err := c.do(ctx, http.MethodGet, "alert-rules/search", query, nil, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExportRuleGroups returns the rule groups of the folders, or of all folders if none is given, in the format of the
+// provisioning files. The groups refer to their folders by UID, so that the result can be provisioned again.
+func (c *Client) ExportRuleGroups(ctx context.Context, folderUIDs ...string) (definitions.AlertingFileExport, error) {
+	query := url.Values{"format": {"json"}, "folderKey": {"uid"}}
+	for _, uid := range folderUIDs {
+		query.Add("folderUid", uid)
+	}
+	var result definitions.AlertingFileExport
+	err := c.do(ctx, http.MethodGet, "alert-rules/export", query, nil, &result)
+	return result, err
+}
+
+// GetAlertRule returns the alert rule with the given UID.
+func (c *Client) GetAlertRule(ctx context.Context, uid string) (definitions.ProvisionedAlertRule, error) {
+	var result definitions.ProvisionedAlertRule
+	err := c.do(ctx, http.MethodGet, path.Join("alert-rules", url.PathEscape(uid)), nil, nil, &result)
+	return result, err
+}
+
+// CreateAlertRule creates the alert rule and returns it as it was stored.
+func (c *Client) CreateAlertRule(ctx context.Context, rule definitions.ProvisionedAlertRule) (definitions.ProvisionedAlertRule, error) {
+	var result definitions.ProvisionedAlertRule
+	err := c.do(ctx, http.MethodPost, "alert-rules", nil, rule, &result)
+	return result, err
+}
+
+// UpdateAlertRule updates the alert rule identified by the UID of the rule and returns it as it was stored. If the rule
+// has a version, e.g. because it was returned by GetAlertRule, the update fails with a conflict if the rule was changed
+// since, see IsConflict.
+func (c *Client) UpdateAlertRule(ctx context.Context, rule definitions.ProvisionedAlertRule) (definitions.ProvisionedAlertRule, error) {
+	if rule.UID == "" {
+		return definitions.ProvisionedAlertRule{}, errors.New("UID of the alert rule must be specified")
+	}
+	var result definitions.ProvisionedAlertRule
+	err := c.do(ctx, http.MethodPut, path.Join("alert-rules", url.PathEscape(rule.UID)), nil, rule, &result)
+	return result, err
+}
+
+// DeleteAlertRule deletes the alert rule with the given UID.
+func (c *Client) DeleteAlertRule(ctx context.Context, uid string) error {
+	return c.do(ctx, http.MethodDelete, path.Join("alert-rules", url.PathEscape(uid)), nil, nil, nil)
+}
+
+// GetRuleGroup returns the rule group with the given title in the folder.
+func (c *Client) GetRuleGroup(ctx context.Context, folderUID, group string) (definitions.AlertRuleGroup, error) {
+	var result definitions.AlertRuleGroup
+	err := c.do(ctx, http.MethodGet, ruleGroupPath(folderUID, group), nil, nil, &result)
+	return result, err
+}
+
+// ReplaceRuleGroup replaces the rule group identified by the folder UID and the title of the group. Rules of the
+// existing group that are not in the given group are deleted. Like UpdateAlertRule, the replacement fails with a
+// conflict if a rule that has a version was changed since, or if the group has a version, e.g. because it was returned
+// by GetRuleGroup, and a rule was added to or deleted from the group since.
+func (c *Client) ReplaceRuleGroup(ctx context.Context, group definitions.AlertRuleGroup) (definitions.AlertRuleGroup, error) {
+	if group.FolderUID == "" || group.Title == "" {
+		return definitions.AlertRuleGroup{}, errors.New("folder UID and title of the rule group must be specified")
+	}
+	var result definitions.AlertRuleGroup
+	err := c.do(ctx, http.MethodPut, ruleGroupPath(group.FolderUID, group.Title), nil, group, &result)
+	return result, err
+}
+
+// DeleteRuleGroup deletes the rule group with the given title in the folder, along with all its rules.
+func (c *Client) DeleteRuleGroup(ctx context.Context, folderUID, group string) error {
+	return c.do(ctx, http.MethodDelete, ruleGroupPath(folderUID, group), nil, nil, nil)
+}
+
+func ruleGroupPath(folderUID, group string) string {
+	return path.Join("folder", url.PathEscape(folderUID), "rule-groups", url.PathEscape(group))
+}
+
+// do executes a request against the provisioning API. If body is not nil, it is sent as JSON. If out is not nil,
+// the response is decoded into it.
+func (c *Client) do(ctx context.Context, method, p string, query url.Values, body any, out any) error {
+	endpoint := *c.cfg.URL
+	// The path is escaped by the methods, so that the UIDs and titles may contain slashes.
+	endpoint.RawPath = path.Join(endpoint.EscapedPath(), provisioningPath, p)
+	unescaped, err := url.PathUnescape(endpoint.RawPath)
+	if err != nil {
+		return err
+	}
+	endpoint.Path = unescaped
+	endpoint.RawQuery = query.Encode()
+
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		payload = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	} else if c.cfg.User != "" {
+		req.SetBasicAuth(c.cfg.User, c.cfg.Password)
+	}
+	if c.cfg.OrgID != 0 {
+		req.Header.Set(orgIDHeaderName, strconv.FormatInt(c.cfg.OrgID, 10))
+	}
+	if c.cfg.DisableProvenance && method != http.MethodGet {
+		req.Header.Set(disableProvenanceHeaderName, "true")
+	}
+
+	resp, err := c.cfg.Requester.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s %s: %w", method, req.URL.Path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode/100 != 2 {
+		apiErr := &APIError{}
+		// The body is not guaranteed to be JSON, e.g. when the request is rejected by a proxy.
+		if err := json.Unmarshal(respBody, apiErr); err != nil {
+			apiErr.Message = string(respBody)
+		}
+		apiErr.StatusCode = resp.StatusCode
+		return apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
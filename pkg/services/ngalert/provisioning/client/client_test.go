@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type recordedRequest struct {
+	Method  string
+	Path    string
+	Query   url.Values
+	Headers http.Header
+	Body    []byte
+}
+
+func newTestClient(t *testing.T, cfg Config, status int, response any) (*Client, *recordedRequest) {
+	t.Helper()
+	recorded := &recordedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		*recorded = recordedRequest{
+			Method:  r.Method,
+			Path:    r.URL.EscapedPath(),
+			Query:   r.URL.Query(),
+			Headers: r.Header,
+			Body:    body,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if response != nil {
+			require.NoError(t, json.NewEncoder(w).Encode(response))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	u, err := url.Parse(srv.URL + "/grafana")
+	require.NoError(t, err)
+	cfg.URL = u
+	c, err := New(cfg)
+	require.NoError(t, err)
+	return c, recorded
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should require URL", func(t *testing.T) {
+		_, err := New(Config{})
+		require.Error(t, err)
+	})
+
+	t.Run("GetAlertRule should request the rule and decode the response", func(t *testing.T) {
+		expected := definitions.ProvisionedAlertRule{UID: "rule/1", Title: "test"}
+		c, req := newTestClient(t, Config{Token: "token", OrgID: 2}, http.StatusOK, expected)
+
+		rule, err := c.GetAlertRule(ctx, "rule/1")
+		require.NoError(t, err)
+		require.Equal(t, expected.UID, rule.UID)
+		require.Equal(t, expected.Title, rule.Title)
+
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/grafana/api/v1/provisioning/alert-rules/rule%2F1", req.Path)
+		require.Equal(t, "Bearer token", req.Headers.Get("Authorization"))
+		require.Equal(t, "2", req.Headers.Get(orgIDHeaderName))
+	})
+
+	t.Run("SearchAlertRules should send the parameters", func(t *testing.T) {
+		c, req := newTestClient(t, Config{}, http.StatusOK, definitions.ProvisionedAlertRulesSearchResult{TotalCount: 3})
+
+		result, err := c.SearchAlertRules(ctx, definitions.AlertRulesSearchParameters{
+			Query:     "cpu",
+			FolderUID: []string{"a", "b"},
+			Limit:     10,
+			Page:      2,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 3, result.TotalCount)
+
+		require.Equal(t, "/grafana/api/v1/provisioning/alert-rules/search", req.Path)
+		require.Equal(t, url.Values{
+			"query":     {"cpu"},
+			"folderUid": {"a", "b"},
+			"limit":     {"10"},
+			"page":      {"2"},
+		}, req.Query)
+	})
+
+	t.Run("ExportRuleGroups should request the groups keyed by folder UID", func(t *testing.T) {
+		c, req := newTestClient(t, Config{}, http.StatusOK, definitions.AlertingFileExport{
+			APIVersion: 1,
+			Groups:     []definitions.AlertRuleGroupExport{{Name: "group", FolderUIDKey: "folder"}},
+		})
+
+		result, err := c.ExportRuleGroups(ctx, "folder", "other")
+		require.NoError(t, err)
+		require.Len(t, result.Groups, 1)
+		require.Equal(t, "folder", result.Groups[0].FolderUIDKey)
+
+		require.Equal(t, http.MethodGet, req.Method)
+		require.Equal(t, "/grafana/api/v1/provisioning/alert-rules/export", req.Path)
+		require.Equal(t, url.Values{
+			"format":    []string{"json"},
+			"folderKey": []string{"uid"},
+			"folderUid": []string{"folder", "other"},
+		}, req.Query)
+	})
+
+	t.Run("ReplaceRuleGroup should send the group", func(t *testing.T) {
+		group := definitions.AlertRuleGroup{Title: "group", FolderUID: "folder", Interval: 60}
+		c, req := newTestClient(t, Config{User: "admin", Password: "secret", DisableProvenance: true}, http.StatusOK, group)
+
+		result, err := c.ReplaceRuleGroup(ctx, group)
+		require.NoError(t, err)
+		require.Equal(t, group, result)
+
+		require.Equal(t, http.MethodPut, req.Method)
+		require.Equal(t, "/grafana/api/v1/provisioning/folder/folder/rule-groups/group", req.Path)
+		require.Equal(t, "true", req.Headers.Get(disableProvenanceHeaderName))
+		user, password, ok := (&http.Request{Header: req.Headers}).BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "admin", user)
+		require.Equal(t, "secret", password)
+
+		sent := definitions.AlertRuleGroup{}
+		require.NoError(t, json.Unmarshal(req.Body, &sent))
+		require.Equal(t, group, sent)
+	})
+
+	t.Run("DeleteAlertRule should accept no content", func(t *testing.T) {
+		c, req := newTestClient(t, Config{}, http.StatusNoContent, nil)
+
+		require.NoError(t, c.DeleteAlertRule(ctx, "rule"))
+		require.Equal(t, http.MethodDelete, req.Method)
+		require.Equal(t, "/grafana/api/v1/provisioning/alert-rules/rule", req.Path)
+	})
+
+	t.Run("should return API error", func(t *testing.T) {
+		c, _ := newTestClient(t, Config{}, http.StatusNotFound, map[string]string{
+			"message":   "rule not found",
+			"messageId": "alerting.notFound",
+		})
+
+		_, err := c.GetAlertRule(ctx, "missing")
+		require.True(t, IsNotFound(err))
+		apiErr := &APIError{}
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, "rule not found", apiErr.Message)
+		require.Equal(t, "alerting.notFound", apiErr.MessageID)
+	})
+}
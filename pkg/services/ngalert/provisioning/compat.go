@@ -65,5 +65,7 @@ func GettableGrafanaReceiverToEmbeddedContactPoint(r *definitions.GettableGrafan
 		DisableResolveMessage: r.DisableResolveMessage,
 		Settings:              settingJson,
 		Provenance:            string(r.Provenance),
+		Disabled:              r.Disabled,
+		RateLimit:             r.RateLimit,
 	}, nil
 }
@@ -0,0 +1,434 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"golang.org/x/exp/maps"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ConfigSnapshotStore is a store of snapshots of the alerting configuration.
+type ConfigSnapshotStore interface {
+	InsertConfigSnapshot(ctx context.Context, snapshot *models.ConfigSnapshot) error
+	GetConfigSnapshots(ctx context.Context, orgID int64) ([]*models.ConfigSnapshot, error)
+	GetConfigSnapshot(ctx context.Context, orgID int64, id int64) (*models.ConfigSnapshot, error)
+	DeleteConfigSnapshots(ctx context.Context, orgID int64, ids ...int64) error
+}
+
+// ServerLock runs a function on a single instance of an HA setup.
+type ServerLock interface {
+	LockAndExecute(ctx context.Context, actionName string, maxInterval time.Duration, fn func(ctx context.Context)) error
+}
+
+// configSnapshotLockName is the name of the server lock of the periodic snapshots.
+const configSnapshotLockName = "alerting config snapshots"
+
+// ConfigSnapshotService saves the alert rules and the Alertmanager configuration of organizations in snapshots, which
+// are a backup of their alerting configuration.
+type ConfigSnapshotService struct {
+	configStore   AMConfigStore
+	ruleStore     RuleStore
+	snapshotStore ConfigSnapshotStore
+	orgStore      store.OrgStore
+	provenances   ProvisioningStore
+	alertRules    *AlertRuleService
+	// ac authorizes the restores of contact points. They are not authorized if it is nil.
+	ac   accesscontrol.AccessControl
+	xact TransactionManager
+	// lock makes a single instance take the periodic snapshots, or every instance if it is nil.
+	lock      ServerLock
+	clock     clock.Clock
+	interval  time.Duration
+	retention time.Duration
+	log       log.Logger
+}
+
+func NewConfigSnapshotService(config AMConfigStore, rules RuleStore, snapshots ConfigSnapshotStore, orgs store.OrgStore,
+	provenances ProvisioningStore, alertRules *AlertRuleService, ac accesscontrol.AccessControl, xact TransactionManager, lock ServerLock, settings setting.UnifiedAlertingSettings, log log.Logger) *ConfigSnapshotService {
+	return &ConfigSnapshotService{
+		configStore:   config,
+		ruleStore:     rules,
+		snapshotStore: snapshots,
+		orgStore:      orgs,
+		provenances:   provenances,
+		alertRules:    alertRules,
+		ac:            ac,
+		xact:          xact,
+		lock:          lock,
+		clock:         clock.New(),
+		interval:      settings.ConfigSnapshotInterval,
+		retention:     settings.ConfigSnapshotRetention,
+		log:           log,
+	}
+}
+
+// GetSnapshots returns the snapshots of the organization without their content, the latest first.
+func (s *ConfigSnapshotService) GetSnapshots(ctx context.Context, orgID int64) ([]*models.ConfigSnapshot, error) {
+	return s.snapshotStore.GetConfigSnapshots(ctx, orgID)
+}
+
+// CreateSnapshot saves the current alerting configuration of the organization in a new snapshot.
+func (s *ConfigSnapshotService) CreateSnapshot(ctx context.Context, orgID int64) (*models.ConfigSnapshot, error) {
+	snapshot, err := s.newSnapshot(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.snapshotStore.InsertConfigSnapshot(ctx, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// RestoreAlertingConfig restores the alert rules and the Alertmanager configuration of the organization from a
+// snapshot. The rules are written like rule groups of the provisioning API, and the contact points, templates, time
+// intervals and notification policy tree like their provisioning services write them: the changes are authorized for
+// the user, and the restore fails if it changes a resource whose provenance does not allow it.
+func (s *ConfigSnapshotService) RestoreAlertingConfig(ctx context.Context, orgID int64, snapshotID int64, mode models.ConfigSnapshotRestoreMode,
+	user identity.Requester, provenance models.Provenance) error {
+	if mode != models.ConfigSnapshotRestoreReplace && mode != models.ConfigSnapshotRestoreMerge {
+		return fmt.Errorf("%w: unknown restore mode '%s'", ErrValidation, mode)
+	}
+	snapshot, err := s.snapshotStore.GetConfigSnapshot(ctx, orgID, snapshotID)
+	if err != nil {
+		return err
+	}
+	content, err := snapshot.Content()
+	if err != nil {
+		return err
+	}
+
+	return s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.alertRules.RestoreAlertRules(ctx, orgID, content.Rules, mode == models.ConfigSnapshotRestoreReplace, user, provenance); err != nil {
+			return err
+		}
+		if content.AlertmanagerConfiguration == "" {
+			return nil
+		}
+		restored, err := deserializeAlertmanagerConfig([]byte(content.AlertmanagerConfiguration))
+		if err != nil {
+			return err
+		}
+		revision, err := getLastConfiguration(ctx, orgID, s.configStore)
+		if err != nil {
+			return err
+		}
+		changes := alertmanagerResourceChanges(revision.cfg, restored, mode == models.ConfigSnapshotRestoreReplace)
+		if err := s.authorizeAlertmanagerChanges(ctx, user, changes); err != nil {
+			return err
+		}
+		if err := s.checkProvenances(ctx, orgID, changes, provenance); err != nil {
+			return err
+		}
+		if mode == models.ConfigSnapshotRestoreMerge {
+			mergeAlertmanagerConfig(revision.cfg, restored)
+		} else {
+			revision.cfg = restored
+		}
+		if err := (alertmanagerConfigStoreImpl{store: s.configStore}).Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if change.deleted {
+				err = s.provenances.DeleteProvenance(ctx, change.object, orgID)
+			} else {
+				err = s.provenances.SetProvenance(ctx, change.object, orgID, provenance)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		s.log.Info("Restored the alerting configuration from a snapshot", "org", orgID, "snapshot", snapshotID, "mode", mode, "changes", len(changes))
+		return nil
+	})
+}
+
+// alertmanagerResourceChange is a resource of the Alertmanager configuration that a restore writes or deletes.
+type alertmanagerResourceChange struct {
+	object  models.Provisionable
+	deleted bool
+}
+
+// alertmanagerResourceChanges returns the integrations of contact points, templates, time intervals and notification
+// policy tree that the restore of the configuration changes, creates or deletes. A merge replaces the contact points
+// with the same name, so it deletes their integrations that are not restored, but it deletes no other resource.
+func alertmanagerResourceChanges(current, restored *definitions.PostableUserConfig, replace bool) []alertmanagerResourceChange {
+	var changes []alertmanagerResourceChange
+
+	currentIntegrations := current.GetGrafanaReceiverMap()
+	restoredIntegrations := restored.GetGrafanaReceiverMap()
+	restoredReceivers := make(map[string]struct{}, len(restored.AlertmanagerConfig.Receivers))
+	for _, receiver := range restored.AlertmanagerConfig.Receivers {
+		restoredReceivers[receiver.Name] = struct{}{}
+	}
+	for _, uid := range sortedKeys(restoredIntegrations) {
+		if existing, ok := currentIntegrations[uid]; !ok || !reflect.DeepEqual(existing, restoredIntegrations[uid]) {
+			changes = append(changes, alertmanagerResourceChange{object: &definitions.EmbeddedContactPoint{UID: uid}})
+		}
+	}
+	for _, receiver := range current.AlertmanagerConfig.Receivers {
+		if _, ok := restoredReceivers[receiver.Name]; !ok && !replace {
+			continue
+		}
+		for _, integration := range receiver.GrafanaManagedReceivers {
+			if _, ok := restoredIntegrations[integration.UID]; !ok {
+				changes = append(changes, alertmanagerResourceChange{object: &definitions.EmbeddedContactPoint{UID: integration.UID}, deleted: true})
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(restored.TemplateFiles) {
+		if existing, ok := current.TemplateFiles[name]; !ok || existing != restored.TemplateFiles[name] {
+			changes = append(changes, alertmanagerResourceChange{object: &definitions.NotificationTemplate{Name: name}})
+		}
+	}
+	if replace {
+		for _, name := range sortedKeys(current.TemplateFiles) {
+			if _, ok := restored.TemplateFiles[name]; !ok {
+				changes = append(changes, alertmanagerResourceChange{object: &definitions.NotificationTemplate{Name: name}, deleted: true})
+			}
+		}
+	}
+
+	currentIntervals := timeIntervalsByName(current)
+	restoredIntervals := timeIntervalsByName(restored)
+	for _, name := range sortedKeys(restoredIntervals) {
+		if existing, ok := currentIntervals[name]; !ok || !reflect.DeepEqual(existing, restoredIntervals[name]) {
+			changes = append(changes, alertmanagerResourceChange{object: &definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: name}}})
+		}
+	}
+	if replace {
+		for _, name := range sortedKeys(currentIntervals) {
+			if _, ok := restoredIntervals[name]; !ok {
+				changes = append(changes, alertmanagerResourceChange{object: &definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: name}}, deleted: true})
+			}
+		}
+	}
+
+	if replace && !reflect.DeepEqual(current.AlertmanagerConfig.Route, restored.AlertmanagerConfig.Route) {
+		changes = append(changes, alertmanagerResourceChange{object: &definitions.Route{}})
+	}
+	return changes
+}
+
+// timeIntervalsByName returns the time intervals of the configuration, in both of their lists, by name.
+func timeIntervalsByName(cfg *definitions.PostableUserConfig) map[string][]timeinterval.TimeInterval {
+	result := make(map[string][]timeinterval.TimeInterval)
+	for _, interval := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+		result[interval.Name] = interval.TimeIntervals
+	}
+	for _, interval := range cfg.AlertmanagerConfig.TimeIntervals {
+		result[interval.Name] = interval.TimeIntervals
+	}
+	return result
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := maps.Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// authorizeAlertmanagerChanges checks that the user may write the contact points that the restore changes, like the
+// receiver service authorizes the access to them.
+func (s *ConfigSnapshotService) authorizeAlertmanagerChanges(ctx context.Context, user identity.Requester, changes []alertmanagerResourceChange) error {
+	if s.ac == nil || user == nil {
+		return nil
+	}
+	if !slices.ContainsFunc(changes, func(c alertmanagerResourceChange) bool {
+		_, ok := c.object.(*definitions.EmbeddedContactPoint)
+		return ok
+	}) {
+		return nil
+	}
+	eval := accesscontrol.EvalAny(
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingNotificationsWrite),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningWrite),
+	)
+	ok, err := s.ac.Evaluate(ctx, user, eval)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: the user may not write the contact points of the snapshot", ErrPermissionDenied)
+	}
+	return nil
+}
+
+// checkProvenances checks that the resources that the restore writes or deletes can be changed with its provenance,
+// like the provisioning services of the resources check it.
+func (s *ConfigSnapshotService) checkProvenances(ctx context.Context, orgID int64, changes []alertmanagerResourceChange, provenance models.Provenance) error {
+	for _, change := range changes {
+		stored, err := s.provenances.GetProvenance(ctx, change.object, orgID)
+		if err != nil {
+			return err
+		}
+		if stored != provenance && stored != models.ProvenanceNone {
+			return fmt.Errorf("%w: cannot restore %s '%s' with provenance '%s', it has provenance '%s'", ErrValidation,
+				change.object.ResourceType(), change.object.ResourceID(), provenance, stored)
+		}
+	}
+	return nil
+}
+
+// mergeAlertmanagerConfig restores the contact points, templates and time intervals of the restored configuration in
+// the current configuration, replacing those with the same name. The notification policy tree is kept.
+func mergeAlertmanagerConfig(current, restored *definitions.PostableUserConfig) {
+	for name, tmpl := range restored.TemplateFiles {
+		if current.TemplateFiles == nil {
+			current.TemplateFiles = make(map[string]string, len(restored.TemplateFiles))
+		}
+		current.TemplateFiles[name] = tmpl
+	}
+
+	for _, receiver := range restored.AlertmanagerConfig.Receivers {
+		idx := slices.IndexFunc(current.AlertmanagerConfig.Receivers, func(r *definitions.PostableApiReceiver) bool {
+			return r.Name == receiver.Name
+		})
+		if idx < 0 {
+			current.AlertmanagerConfig.Receivers = append(current.AlertmanagerConfig.Receivers, receiver)
+			continue
+		}
+		current.AlertmanagerConfig.Receivers[idx] = receiver
+	}
+
+	for _, interval := range restored.AlertmanagerConfig.MuteTimeIntervals {
+		idx := slices.IndexFunc(current.AlertmanagerConfig.MuteTimeIntervals, func(i config.MuteTimeInterval) bool {
+			return i.Name == interval.Name
+		})
+		if idx < 0 {
+			current.AlertmanagerConfig.MuteTimeIntervals = append(current.AlertmanagerConfig.MuteTimeIntervals, interval)
+			continue
+		}
+		current.AlertmanagerConfig.MuteTimeIntervals[idx] = interval
+	}
+
+	for _, interval := range restored.AlertmanagerConfig.TimeIntervals {
+		idx := slices.IndexFunc(current.AlertmanagerConfig.TimeIntervals, func(i config.TimeInterval) bool {
+			return i.Name == interval.Name
+		})
+		if idx < 0 {
+			current.AlertmanagerConfig.TimeIntervals = append(current.AlertmanagerConfig.TimeIntervals, interval)
+			continue
+		}
+		current.AlertmanagerConfig.TimeIntervals[idx] = interval
+	}
+}
+
+func (s *ConfigSnapshotService) newSnapshot(ctx context.Context, orgID int64) (*models.ConfigSnapshot, error) {
+	content := models.ConfigSnapshotContent{}
+	cfg, err := s.configStore.GetLatestAlertmanagerConfiguration(ctx, orgID)
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return nil, err
+	}
+	if cfg != nil {
+		content.AlertmanagerConfiguration = cfg.AlertmanagerConfiguration
+	}
+
+	rules, err := s.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	// Sort the rules so that the hash of snapshots of the same rules is the same.
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].UID < rules[j].UID
+	})
+	content.Rules = make([]models.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		content.Rules = append(content.Rules, *rule)
+	}
+
+	return models.NewConfigSnapshot(orgID, content, s.clock.Now().Unix())
+}
+
+// Run periodically saves the alerting configuration of every organization in a snapshot if it changed since the
+// previous snapshot, and deletes the snapshots that are older than the retention. In an HA setup, the snapshots of an
+// interval are taken by the first instance that acquires the server lock.
+func (s *ConfigSnapshotService) Run(ctx context.Context) error {
+	if s.interval <= 0 {
+		return nil
+	}
+	ticker := s.clock.Ticker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if s.lock == nil {
+				s.snapshotOrgs(ctx)
+				continue
+			}
+			if err := s.lock.LockAndExecute(ctx, configSnapshotLockName, s.interval, s.snapshotOrgs); err != nil {
+				s.log.Error("Failed to acquire the lock to snapshot the alerting configuration", "error", err)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *ConfigSnapshotService) snapshotOrgs(ctx context.Context) {
+	orgIDs, err := s.orgStore.GetOrgs(ctx)
+	if err != nil {
+		s.log.Error("Failed to get organizations to snapshot the alerting configuration of", "error", err)
+		return
+	}
+	for _, orgID := range orgIDs {
+		if err := s.snapshotOrg(ctx, orgID); err != nil {
+			s.log.Error("Failed to snapshot the alerting configuration", "org", orgID, "error", err)
+		}
+	}
+}
+
+// snapshotOrg creates a snapshot of the organization if its configuration changed since the latest snapshot, and
+// deletes the expired snapshots.
+func (s *ConfigSnapshotService) snapshotOrg(ctx context.Context, orgID int64) error {
+	snapshots, err := s.snapshotStore.GetConfigSnapshots(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	snapshot, err := s.newSnapshot(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 || snapshots[0].Hash != snapshot.Hash {
+		if err := s.snapshotStore.InsertConfigSnapshot(ctx, snapshot); err != nil {
+			return err
+		}
+		s.log.Debug("Created snapshot of the alerting configuration", "org", orgID, "snapshot", snapshot.ID, "size", snapshot.Size)
+		snapshots = append([]*models.ConfigSnapshot{snapshot}, snapshots...)
+	}
+
+	expired := expiredSnapshots(snapshots, s.clock.Now().Add(-s.retention).Unix())
+	if len(expired) == 0 {
+		return nil
+	}
+	s.log.Debug("Deleting expired snapshots of the alerting configuration", "org", orgID, "count", len(expired))
+	return s.snapshotStore.DeleteConfigSnapshots(ctx, orgID, expired...)
+}
+
+// expiredSnapshots returns the IDs of the snapshots created before the given time, except the latest snapshot.
+// Snapshots must be sorted from the latest to the oldest.
+func expiredSnapshots(snapshots []*models.ConfigSnapshot, createdBefore int64) []int64 {
+	var expired []int64
+	for i, snapshot := range snapshots {
+		if i > 0 && snapshot.CreatedAt < createdBefore {
+			expired = append(expired, snapshot.ID)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,304 @@
+package provisioning
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestConfigSnapshotService(t *testing.T) {
+	orgID := int64(1)
+	setup := func() (*ConfigSnapshotService, *fakeConfigSnapshotStore, *FakeStore, *clock.Mock) {
+		rules := NewFakeStore()
+		rules.PutRules(models.AlertRule{OrgID: orgID, UID: "rule-1", Title: "rule 1"})
+		snapshots := &fakeConfigSnapshotStore{}
+		clk := clock.NewMock()
+		clk.Set(time.Unix(1000000, 0))
+		return &ConfigSnapshotService{
+			configStore:   fakes.NewFakeAlertmanagerConfigStore(defaultAlertmanagerConfigJSON),
+			ruleStore:     rules,
+			snapshotStore: snapshots,
+			clock:         clk,
+			retention:     time.Hour,
+			log:           log.NewNopLogger(),
+		}, snapshots, rules, clk
+	}
+
+	t.Run("snapshot contains the alertmanager configuration and the rules", func(t *testing.T) {
+		sut, snapshots, _, _ := setup()
+
+		snapshot, err := sut.CreateSnapshot(context.Background(), orgID)
+		require.NoError(t, err)
+		require.Len(t, snapshots.snapshots, 1)
+
+		content, err := snapshot.Content()
+		require.NoError(t, err)
+		require.Equal(t, defaultAlertmanagerConfigJSON, content.AlertmanagerConfiguration)
+		require.Len(t, content.Rules, 1)
+		require.Equal(t, "rule-1", content.Rules[0].UID)
+	})
+
+	t.Run("periodic snapshot is only created when the configuration changed", func(t *testing.T) {
+		sut, snapshots, rules, clk := setup()
+
+		require.NoError(t, sut.snapshotOrg(context.Background(), orgID))
+		clk.Add(time.Minute)
+		require.NoError(t, sut.snapshotOrg(context.Background(), orgID))
+		require.Len(t, snapshots.snapshots, 1)
+
+		rules.PutRules(models.AlertRule{OrgID: orgID, UID: "rule-2", Title: "rule 2"})
+		clk.Add(time.Minute)
+		require.NoError(t, sut.snapshotOrg(context.Background(), orgID))
+		require.Len(t, snapshots.snapshots, 2)
+	})
+
+	t.Run("periodic snapshots are taken by the instance that acquires the lock", func(t *testing.T) {
+		for _, acquired := range []bool{false, true} {
+			sut, snapshots, _, clk := setup()
+			lock := &fakeServerLock{acquired: acquired, executed: make(chan string, 1)}
+			sut.lock = lock
+			sut.orgStore = notifier.NewFakeOrgStore(t, []int64{orgID})
+			sut.interval = time.Minute
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				_ = sut.Run(ctx)
+			}()
+			var action string
+			require.Eventually(t, func() bool {
+				clk.Add(time.Minute)
+				select {
+				case action = <-lock.executed:
+					return true
+				default:
+					return false
+				}
+			}, time.Second, 10*time.Millisecond)
+			cancel()
+			<-done
+
+			require.Equal(t, configSnapshotLockName, action)
+			if acquired {
+				require.Len(t, snapshots.snapshots, 1)
+			} else {
+				require.Empty(t, snapshots.snapshots)
+			}
+		}
+	})
+
+	t.Run("expired snapshots are deleted except the latest one", func(t *testing.T) {
+		sut, snapshots, rules, clk := setup()
+
+		require.NoError(t, sut.snapshotOrg(context.Background(), orgID))
+		rules.PutRules(models.AlertRule{OrgID: orgID, UID: "rule-2", Title: "rule 2"})
+		clk.Add(time.Minute)
+		require.NoError(t, sut.snapshotOrg(context.Background(), orgID))
+		latest := snapshots.snapshots[1].ID
+
+		clk.Add(2 * time.Hour)
+		require.NoError(t, sut.snapshotOrg(context.Background(), orgID))
+		require.Len(t, snapshots.snapshots, 1)
+		require.Equal(t, latest, snapshots.snapshots[0].ID)
+	})
+}
+
+type fakeConfigSnapshotStore struct {
+	snapshots []*models.ConfigSnapshot
+}
+
+func (f *fakeConfigSnapshotStore) InsertConfigSnapshot(_ context.Context, snapshot *models.ConfigSnapshot) error {
+	snapshot.ID = int64(len(f.snapshots) + 1)
+	if len(f.snapshots) > 0 {
+		snapshot.ID = f.snapshots[len(f.snapshots)-1].ID + 1
+	}
+	f.snapshots = append(f.snapshots, snapshot)
+	return nil
+}
+
+func (f *fakeConfigSnapshotStore) GetConfigSnapshots(_ context.Context, orgID int64) ([]*models.ConfigSnapshot, error) {
+	var result []*models.ConfigSnapshot
+	for i := len(f.snapshots) - 1; i >= 0; i-- {
+		if f.snapshots[i].OrgID == orgID {
+			result = append(result, f.snapshots[i])
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeConfigSnapshotStore) GetConfigSnapshot(_ context.Context, orgID int64, id int64) (*models.ConfigSnapshot, error) {
+	for _, snapshot := range f.snapshots {
+		if snapshot.OrgID == orgID && snapshot.ID == id {
+			return snapshot, nil
+		}
+	}
+	return nil, models.ErrConfigSnapshotNotFound.Errorf("")
+}
+
+func (f *fakeConfigSnapshotStore) DeleteConfigSnapshots(_ context.Context, orgID int64, ids ...int64) error {
+	f.snapshots = slices.DeleteFunc(f.snapshots, func(snapshot *models.ConfigSnapshot) bool {
+		return snapshot.OrgID == orgID && slices.Contains(ids, snapshot.ID)
+	})
+	return nil
+}
+
+func TestRestoreAlertingConfig(t *testing.T) {
+	orgID := int64(1)
+	setup := func(t *testing.T) (*ConfigSnapshotService, *AlertRuleService, *fakes.FakeAlertmanagerConfigStore, *models.ConfigSnapshot, []models.AlertRule) {
+		ruleService := createAlertRuleService(t)
+		configStore := fakes.NewFakeAlertmanagerConfigStore(defaultAlertmanagerConfigJSON)
+		sut := &ConfigSnapshotService{
+			configStore:   configStore,
+			ruleStore:     ruleService.ruleStore,
+			snapshotStore: &fakeConfigSnapshotStore{},
+			provenances:   ruleService.provenanceStore,
+			alertRules:    &ruleService,
+			xact:          ruleService.xact,
+			clock:         clock.NewMock(),
+			log:           log.NewNopLogger(),
+		}
+
+		var rules []models.AlertRule
+		for _, title := range []string{"rule-1", "rule-2"} {
+			rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule(title, orgID), models.ProvenanceAPI, 0)
+			require.NoError(t, err)
+			rules = append(rules, rule)
+		}
+		snapshot, err := sut.CreateSnapshot(context.Background(), orgID)
+		require.NoError(t, err)
+
+		// Change the configuration after the snapshot.
+		require.NoError(t, ruleService.DeleteAlertRule(context.Background(), orgID, rules[0].UID, models.ProvenanceAPI))
+		updated := rules[1]
+		updated.Title = "rule-2 updated"
+		_, err = ruleService.UpdateAlertRule(context.Background(), updated, models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(context.Background(), createTestRule("rule-3", "other-group", orgID, "my-namespace"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		configStore.Config.AlertmanagerConfiguration = strings.Replace(defaultAlertmanagerConfigJSON,
+			`"receivers": [`, `"receivers": [{"name": "new-receiver", "grafana_managed_receiver_configs": [{"uid": "UID3", "name": "new-receiver", "type": "email", "settings": {"addresses": "new@email.com"}}]},`, 1)
+
+		return sut, &ruleService, configStore, snapshot, rules
+	}
+
+	titles := func(t *testing.T, ruleService *AlertRuleService) []string {
+		rules, _, err := ruleService.GetAlertRules(context.Background(), nil, models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		result := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			result = append(result, rule.Title)
+		}
+		slices.Sort(result)
+		return result
+	}
+
+	t.Run("replace makes the configuration the same as in the snapshot", func(t *testing.T) {
+		sut, ruleService, configStore, snapshot, rules := setup(t)
+
+		err := sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, models.ConfigSnapshotRestoreReplace, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"rule-1", "rule-2"}, titles(t, ruleService))
+		restored, _, err := ruleService.GetAlertRule(context.Background(), orgID, rules[0].UID)
+		require.NoError(t, err)
+		require.Equal(t, "rule-1", restored.Title)
+		require.NotContains(t, configStore.Config.AlertmanagerConfiguration, "new-receiver")
+	})
+
+	t.Run("merge keeps the resources created after the snapshot", func(t *testing.T) {
+		sut, ruleService, configStore, snapshot, _ := setup(t)
+
+		err := sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, models.ConfigSnapshotRestoreMerge, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"rule-1", "rule-2", "rule-3"}, titles(t, ruleService))
+		require.Contains(t, configStore.Config.AlertmanagerConfiguration, "new-receiver")
+	})
+
+	t.Run("contact points with another provenance are not deleted", func(t *testing.T) {
+		sut, _, configStore, snapshot, _ := setup(t)
+		contactPoint := &definitions.EmbeddedContactPoint{UID: "UID3"}
+		require.NoError(t, sut.provenances.SetProvenance(context.Background(), contactPoint, orgID, models.ProvenanceFile))
+
+		err := sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, models.ConfigSnapshotRestoreReplace, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.Contains(t, configStore.Config.AlertmanagerConfiguration, "new-receiver")
+
+		// A merge keeps the contact points created after the snapshot.
+		err = sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, models.ConfigSnapshotRestoreMerge, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		provenance, err := sut.provenances.GetProvenance(context.Background(), contactPoint, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceFile, provenance)
+	})
+
+	t.Run("provenance of the deleted contact points is deleted", func(t *testing.T) {
+		sut, _, _, snapshot, _ := setup(t)
+		contactPoint := &definitions.EmbeddedContactPoint{UID: "UID3"}
+		require.NoError(t, sut.provenances.SetProvenance(context.Background(), contactPoint, orgID, models.ProvenanceAPI))
+
+		err := sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, models.ConfigSnapshotRestoreReplace, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		provenance, err := sut.provenances.GetProvenance(context.Background(), contactPoint, orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+	})
+
+	t.Run("contact points are restored only if the user may write them", func(t *testing.T) {
+		sut, _, configStore, snapshot, _ := setup(t)
+		sut.ac = actest.FakeAccessControl{ExpectedEvaluate: false}
+
+		err := sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, models.ConfigSnapshotRestoreReplace, &user.SignedInUser{OrgID: orgID}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		require.Contains(t, configStore.Config.AlertmanagerConfiguration, "new-receiver")
+
+		sut.ac = actest.FakeAccessControl{ExpectedEvaluate: true}
+		err = sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, models.ConfigSnapshotRestoreReplace, &user.SignedInUser{OrgID: orgID}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NotContains(t, configStore.Config.AlertmanagerConfiguration, "new-receiver")
+	})
+
+	t.Run("unknown mode is rejected", func(t *testing.T) {
+		sut, _, _, snapshot, _ := setup(t)
+
+		err := sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID, "some", nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("unknown snapshot is not found", func(t *testing.T) {
+		sut, _, _, snapshot, _ := setup(t)
+
+		err := sut.RestoreAlertingConfig(context.Background(), orgID, snapshot.ID+1, models.ConfigSnapshotRestoreReplace, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrConfigSnapshotNotFound)
+	})
+}
+
+type fakeServerLock struct {
+	acquired bool
+	executed chan string
+}
+
+func (f *fakeServerLock) LockAndExecute(ctx context.Context, actionName string, _ time.Duration, fn func(ctx context.Context)) error {
+	if f.acquired {
+		fn(ctx)
+	}
+	select {
+	case f.executed <- actionName:
+	default:
+	}
+	return nil
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -34,16 +35,19 @@ type ContactPointService struct {
 	notificationSettingsStore AlertRuleNotificationSettingsStore
 	xact                      TransactionManager
 	receiverService           receiverService
+	tagStore                  TagStore
+	secretReferences          setting.UnifiedAlertingSecretReferencesSettings
 	log                       log.Logger
 }
 
 type receiverService interface {
 	GetReceivers(ctx context.Context, query models.GetReceiversQuery, user identity.Requester) ([]apimodels.GettableApiReceiver, error)
+	AuthorizeUpdate(ctx context.Context, user identity.Requester) error
 }
 
 func NewContactPointService(store AMConfigStore, encryptionService secrets.Service,
 	provenanceStore ProvisioningStore, xact TransactionManager, receiverService receiverService, log log.Logger,
-	nsStore AlertRuleNotificationSettingsStore) *ContactPointService {
+	nsStore AlertRuleNotificationSettingsStore, tagStore TagStore, secretReferences setting.UnifiedAlertingSecretReferencesSettings) *ContactPointService {
 	return &ContactPointService{
 		configStore: &alertmanagerConfigStoreImpl{
 			store: store,
@@ -54,6 +58,8 @@ func NewContactPointService(store AMConfigStore, encryptionService secrets.Servi
 		xact:                      xact,
 		log:                       log,
 		notificationSettingsStore: nsStore,
+		tagStore:                  tagStore,
+		secretReferences:          secretReferences,
 	}
 }
 
@@ -129,6 +135,8 @@ func (ecp *ContactPointService) getContactPointDecrypted(ctx context.Context, or
 		if err != nil {
 			return apimodels.EmbeddedContactPoint{}, err
 		}
+		embeddedContactPoint.Disabled = revision.cfg.IsIntegrationDisabled(receiver.UID)
+		embeddedContactPoint.RateLimit = revision.cfg.GetRateLimit(receiver.Name)
 		return embeddedContactPoint, nil
 	}
 	return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: contact point with uid '%s' not found", ErrNotFound, uid)
@@ -139,6 +147,9 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 	if err := ValidateContactPoint(ctx, contactPoint, ecp.encryptionService.GetDecryptedValue); err != nil {
 		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := checkSecretReferencesAllowed(contactPoint, ecp.secretReferences.AllowedPrefixesForOrg(orgID)); err != nil {
+		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
 
 	revision, err := ecp.configStore.Get(ctx, orgID)
 	if err != nil {
@@ -205,6 +216,8 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 			},
 		})
 	}
+	revision.cfg.SetIntegrationDisabled(grafanaReceiver.UID, contactPoint.Disabled)
+	revision.cfg.SetRateLimit(grafanaReceiver.Name, contactPoint.RateLimit)
 
 	err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
 		if err := ecp.configStore.Save(ctx, revision, orgID); err != nil {
@@ -245,6 +258,9 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 	if err := ValidateContactPoint(ctx, contactPoint, ecp.encryptionService.GetDecryptedValue); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := checkSecretReferencesAllowed(contactPoint, ecp.secretReferences.AllowedPrefixesForOrg(orgID)); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
 
 	// check that provenance is not changed in an invalid way
 	storedProvenance, err := ecp.provenanceStore.GetProvenance(ctx, &contactPoint, orgID)
@@ -289,6 +305,8 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 	if !configModified {
 		return fmt.Errorf("contact point with uid '%s' not found", mergedReceiver.UID)
 	}
+	revision.cfg.SetIntegrationDisabled(mergedReceiver.UID, contactPoint.Disabled)
+	revision.cfg.SetRateLimit(mergedReceiver.Name, contactPoint.RateLimit)
 
 	err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
 		if err := ecp.configStore.Save(ctx, revision, orgID); err != nil {
@@ -337,6 +355,10 @@ func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID in
 			}
 		}
 	}
+	revision.cfg.SetIntegrationDisabled(uid, false)
+	if fullRemoval {
+		revision.cfg.SetRateLimit(name, nil)
+	}
 	if fullRemoval && isContactPointInUse(name, []*apimodels.Route{revision.cfg.AlertmanagerConfig.Route}) {
 		return fmt.Errorf("contact point '%s' is currently used by a notification policy", name)
 	}
@@ -363,10 +385,114 @@ func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID in
 		target := &apimodels.EmbeddedContactPoint{
 			UID: uid,
 		}
+		if err := ecp.tagStore.DeleteResourceTags(ctx, target, orgID); err != nil {
+			return err
+		}
 		return ecp.provenanceStore.DeleteProvenance(ctx, target, orgID)
 	})
 }
 
+// RenameContactPoint renames the contact point with all its integrations, and the references to it in the notification
+// policies and in the notification settings of the alert rules, in a single transaction. The user must be allowed to
+// change the contact points, and the integrations, the notification policies that reference the contact point and the
+// alert rules that notify it must have the provenance of the rename or none. The provenance of the integrations is
+// kept. It fails if a contact point with the new name exists.
+func (ecp *ContactPointService) RenameContactPoint(ctx context.Context, user identity.Requester, orgID int64, oldName, newName string, provenance models.Provenance) error {
+	if newName == "" {
+		return fmt.Errorf("%w: the name of the contact point must not be empty", ErrValidation)
+	}
+	if oldName == newName {
+		return fmt.Errorf("%w: the new name of the contact point is the same as the old one", ErrValidation)
+	}
+	if err := ecp.receiverService.AuthorizeUpdate(ctx, user); err != nil {
+		return convertRecSvcErr(err)
+	}
+	revision, err := ecp.configStore.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	var renamed *apimodels.PostableApiReceiver
+	for _, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
+		switch receiver.Name {
+		case oldName:
+			renamed = receiver
+		case newName:
+			return fmt.Errorf("%w: contact point '%s' already exists", ErrValidation, newName)
+		}
+	}
+	if renamed == nil {
+		return fmt.Errorf("%w: contact point '%s' does not exist", ErrNotFound, oldName)
+	}
+	if err := ecp.checkRenameProvenance(ctx, orgID, revision, renamed, provenance); err != nil {
+		return err
+	}
+	renamed.Name = newName
+	for _, integration := range renamed.GrafanaManagedReceivers {
+		integration.Name = newName
+	}
+	revision.cfg.SetRateLimit(newName, revision.cfg.GetRateLimit(oldName))
+	replaceReferences(oldName, newName, revision.cfg.AlertmanagerConfig.Route)
+
+	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := ecp.configStore.Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		affected, err := ecp.notificationSettingsStore.RenameReceiverInNotificationSettings(ctx, orgID, oldName, newName)
+		if err != nil {
+			return err
+		}
+		ecp.log.Info("Renamed contact point", "oldName", oldName, "newName", newName, "affectedSettings", affected)
+		return nil
+	})
+}
+
+// checkRenameProvenance checks that the rename of the receiver with the provenance does not change resources that are
+// provisioned with another provenance: its integrations, the notification policies when they reference it and the
+// alert rules that notify it.
+func (ecp *ContactPointService) checkRenameProvenance(ctx context.Context, orgID int64, revision *cfgRevision, receiver *apimodels.PostableApiReceiver, provenance models.Provenance) error {
+	canChange := func(stored models.Provenance) bool {
+		return stored == models.ProvenanceNone || stored == provenance
+	}
+	provenances, err := ecp.provenanceStore.GetProvenances(ctx, orgID, (&apimodels.EmbeddedContactPoint{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for _, integration := range receiver.GrafanaManagedReceivers {
+		if stored := provenances[integration.UID]; !canChange(stored) {
+			return ErrProvenanceMismatch.Errorf("cannot rename contact point '%s' provisioned with provenance '%s'", receiver.Name, stored)
+		}
+	}
+
+	route := revision.cfg.AlertmanagerConfig.Route
+	if isContactPointInUse(receiver.Name, []*apimodels.Route{route}) {
+		stored, err := ecp.provenanceStore.GetProvenance(ctx, route, orgID)
+		if err != nil {
+			return err
+		}
+		if !canChange(stored) {
+			return ErrProvenanceMismatch.Errorf("cannot rename contact point '%s' used by the notification policies provisioned with provenance '%s'", receiver.Name, stored)
+		}
+	}
+
+	used, err := ecp.notificationSettingsStore.ListNotificationSettings(ctx, models.ListNotificationSettingsQuery{OrgID: orgID, ReceiverName: receiver.Name})
+	if err != nil {
+		return fmt.Errorf("failed to query alert rules for reference to the contact point '%s': %w", receiver.Name, err)
+	}
+	if len(used) == 0 {
+		return nil
+	}
+	ruleProvenances, err := ecp.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	for key := range used {
+		if stored := ruleProvenances[key.UID]; !canChange(stored) {
+			return ErrProvenanceMismatch.Errorf("cannot rename contact point '%s' used by alert rule '%s' provisioned with provenance '%s'", receiver.Name, key.UID, stored)
+		}
+	}
+	return nil
+}
+
 func isContactPointInUse(name string, routes []*apimodels.Route) bool {
 	if len(routes) == 0 {
 		return false
@@ -509,6 +635,14 @@ func ValidateContactPoint(ctx context.Context, e apimodels.EmbeddedContactPoint,
 	if e.Settings == nil {
 		return fmt.Errorf("settings should not be empty")
 	}
+	if e.RateLimit != nil {
+		if err := e.RateLimit.Validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateSecretReferences(e); err != nil {
+		return err
+	}
 	integration, err := EmbeddedContactPointToGrafanaIntegrationConfig(e)
 	if err != nil {
 		return err
@@ -521,6 +655,46 @@ func ValidateContactPoint(ctx context.Context, e apimodels.EmbeddedContactPoint,
 	if err != nil {
 		return err
 	}
+	if _, err := notifier.WebhookOAuth2ConfigFromIntegration(ctx, &integration, decryptFunc); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateSecretReferences checks the syntax of the secrets of the contact point that refer to external secret
+// managers. The secrets are not resolved, as the secret managers might only be reachable when notifications are sent.
+func validateSecretReferences(e apimodels.EmbeddedContactPoint) error {
+	secretKeys, err := channels_config.GetSecretKeysForContactPointType(e.Type)
+	if err != nil {
+		// Unknown types are reported when the configuration is built.
+		return nil
+	}
+	for _, secretKey := range secretKeys {
+		if _, err := notifier.ParseSecretReference(e.Settings.Get(secretKey).MustString()); err != nil {
+			return fmt.Errorf("invalid secret '%s': %w", secretKey, err)
+		}
+	}
+	return nil
+}
+
+// checkSecretReferencesAllowed rejects the secure settings that refer to a secret outside of the allowed prefixes.
+func checkSecretReferencesAllowed(e apimodels.EmbeddedContactPoint, prefixes []string) error {
+	secretKeys, err := channels_config.GetSecretKeysForContactPointType(e.Type)
+	if err != nil {
+		return nil
+	}
+	for _, secretKey := range secretKeys {
+		ref, err := notifier.ParseSecretReference(e.Settings.Get(secretKey).MustString())
+		if err != nil {
+			return fmt.Errorf("invalid secret '%s': %w", secretKey, err)
+		}
+		if ref == nil {
+			continue
+		}
+		if err := ref.CheckAllowed(prefixes); err != nil {
+			return fmt.Errorf("invalid secret '%s': %w", secretKey, err)
+		}
+	}
 	return nil
 }
 
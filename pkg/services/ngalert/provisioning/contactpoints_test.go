@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -241,6 +243,174 @@ func TestContactPointService(t *testing.T) {
 		}
 	})
 
+	t.Run("integrations can be disabled and re-enabled", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		newCp := createTestContactPoint()
+		newCp.Disabled = true
+
+		created, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		revision, err := sut.configStore.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, []string{created.UID}, revision.cfg.DisabledIntegrations)
+		cps, err := sut.GetContactPoints(context.Background(), cpsQueryWithName(1, newCp.Name), nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.True(t, cps[0].Disabled)
+
+		created.Disabled = false
+		require.NoError(t, sut.UpdateContactPoint(context.Background(), 1, created, models.ProvenanceAPI))
+
+		revision, err = sut.configStore.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, revision.cfg.DisabledIntegrations)
+		cps, err = sut.GetContactPoints(context.Background(), cpsQueryWithName(1, newCp.Name), nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.False(t, cps[0].Disabled)
+	})
+
+	t.Run("tags are deleted with the contact point", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.notificationSettingsStore = &fakeNotificationSettingsStore{}
+		created, err := sut.CreateContactPoint(context.Background(), 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, sut.tagStore.SetResourceTags(context.Background(), &created, 1, []string{"canary"}))
+
+		require.NoError(t, sut.DeleteContactPoint(context.Background(), 1, created.UID))
+
+		tags, err := sut.tagStore.GetResourceTags(context.Background(), &created, 1)
+		require.NoError(t, err)
+		require.Empty(t, tags)
+	})
+
+	t.Run("rate limits are stored with the contact point", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.notificationSettingsStore = &fakeNotificationSettingsStore{}
+		sut.receiverService = notifier.NewReceiverService(actest.FakeAccessControl{ExpectedEvaluate: true}, sut.configStore.store, sut.provenanceStore, sut.encryptionService, sut.xact, log.NewNopLogger())
+		newCp := createTestContactPoint()
+		newCp.RateLimit = &definitions.NotificationRateLimit{MaxNotifications: 10, Interval: model.Duration(time.Hour)}
+
+		created, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		// The receiver service lets the contact points be renamed, and only lists them without their settings unless
+		// they are decrypted.
+		query := cpsQueryWithName(1, newCp.Name)
+		query.Decrypt = true
+		cps, err := sut.GetContactPoints(context.Background(), query, &user.SignedInUser{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, newCp.RateLimit, cps[0].RateLimit)
+
+		other := createTestContactPoint()
+		other.Name = newCp.Name
+		_, err = sut.CreateContactPoint(context.Background(), 1, other, models.ProvenanceAPI)
+		require.NoError(t, err)
+		revision, err := sut.configStore.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, revision.cfg.RateLimits, "the rate limit should be shared by the integrations of the contact point")
+
+		require.NoError(t, sut.UpdateContactPoint(context.Background(), 1, created, models.ProvenanceAPI))
+		require.NoError(t, sut.RenameContactPoint(context.Background(), &user.SignedInUser{OrgID: 1}, 1, newCp.Name, "renamed", models.ProvenanceAPI))
+		revision, err = sut.configStore.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, map[string]definitions.NotificationRateLimit{"renamed": *newCp.RateLimit}, revision.cfg.RateLimits)
+
+		created.Name = "renamed"
+		created.RateLimit = nil
+		// The update removed the secrets from the settings.
+		created.Settings = createTestContactPoint().Settings
+		require.NoError(t, sut.UpdateContactPoint(context.Background(), 1, created, models.ProvenanceAPI))
+
+		revision, err = sut.configStore.Get(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, revision.cfg.RateLimits)
+	})
+
+	t.Run("create rejects invalid rate limits", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		newCp := createTestContactPoint()
+		newCp.RateLimit = &definitions.NotificationRateLimit{MaxNotifications: 0, Interval: model.Duration(time.Hour)}
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("webhooks with OAuth2 are validated and their client secret is stored as a secret", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		settings, _ := simplejson.NewJson([]byte(`{
+			"url": "https://example.com/hook",
+			"oauth2_token_url": "https://auth.example.com/token",
+			"oauth2_client_id": "grafana",
+			"oauth2_client_secret": "s3cr3t",
+			"oauth2_scopes": "alerts:write"
+		}`))
+		newCp := definitions.EmbeddedContactPoint{Name: "oauth2-webhook", Type: "webhook", Settings: settings}
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), cpsQueryWithName(1, newCp.Name), nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, definitions.RedactedValue, cps[0].Settings.Get("oauth2_client_secret").MustString())
+		require.Equal(t, "grafana", cps[0].Settings.Get("oauth2_client_id").MustString())
+
+		invalid := createTestContactPoint()
+		invalid.Type = "webhook"
+		invalid.Settings, _ = simplejson.NewJson([]byte(`{"url": "https://example.com/hook", "oauth2_token_url": "https://auth.example.com/token"}`))
+		_, err = sut.CreateContactPoint(context.Background(), 1, invalid, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "OAuth2 client ID must be set")
+	})
+
+	t.Run("secrets that refer to external secret managers are validated and stored as secrets", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		newCp := createTestContactPoint()
+		newCp.Settings.Set("token", "ref+vault://secret/data/alerting/slack#token")
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), cpsQueryWithName(1, newCp.Name), nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, definitions.RedactedValue, cps[0].Settings.Get("token").MustString())
+
+		invalid := createTestContactPoint()
+		invalid.Settings.Set("token", "ref+vault://secret/data/alerting/slack")
+		_, err = sut.CreateContactPoint(context.Background(), 1, invalid, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "has no key")
+
+		invalid = createTestContactPoint()
+		invalid.Settings.Set("token", "ref+awssm://not-an-arn")
+		_, err = sut.CreateContactPoint(context.Background(), 1, invalid, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "not the ARN")
+	})
+
+	t.Run("secrets that refer to external secret managers outside of the allowed prefixes are rejected", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		newCp := createTestContactPoint()
+		newCp.Settings.Set("token", "ref+vault://secret/data/alerting/slack#token")
+		newCp, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		invalid := createTestContactPoint()
+		invalid.Settings.Set("token", "ref+vault://secret/data/database/root#password")
+		_, err = sut.CreateContactPoint(context.Background(), 1, invalid, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "not allowed")
+
+		newCp.Settings.Set("token", "ref+vault://secret/data/alerting/../database/root#password")
+		err = sut.UpdateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "not allowed")
+	})
+
 	t.Run("service respects concurrency token when updating", func(t *testing.T) {
 		sut := createContactPointServiceSut(t, secretsService)
 		newCp := createTestContactPoint()
@@ -321,6 +491,123 @@ func TestContactPointServiceDecryptRedact(t *testing.T) {
 	})
 }
 
+type fakeNotificationSettingsStore struct {
+	renames [][2]string
+	used    map[models.AlertRuleKey][]models.NotificationSettings
+}
+
+func (f *fakeNotificationSettingsStore) RenameReceiverInNotificationSettings(_ context.Context, _ int64, oldReceiver, newReceiver string) (int, error) {
+	f.renames = append(f.renames, [2]string{oldReceiver, newReceiver})
+	return 1, nil
+}
+
+func (f *fakeNotificationSettingsStore) ListNotificationSettings(context.Context, models.ListNotificationSettingsQuery) (map[models.AlertRuleKey][]models.NotificationSettings, error) {
+	return f.used, nil
+}
+
+func TestRenameContactPoint(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+	writer := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{
+		1: {accesscontrol.ActionAlertingNotificationsWrite: nil},
+	}}
+	createSut := func(t *testing.T) (*ContactPointService, *fakeNotificationSettingsStore) {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.receiverService = notifier.NewReceiverService(
+			acimpl.ProvideAccessControl(setting.NewCfg()),
+			sut.configStore.store,
+			sut.provenanceStore,
+			sut.encryptionService,
+			sut.xact,
+			log.NewNopLogger(),
+		)
+		nsStore := &fakeNotificationSettingsStore{}
+		sut.notificationSettingsStore = nsStore
+		return sut, nsStore
+	}
+
+	t.Run("renames the contact point and its references", func(t *testing.T) {
+		sut, nsStore := createSut(t)
+
+		require.NoError(t, sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "email", models.ProvenanceAPI))
+
+		cps, err := sut.GetContactPoints(ctx, cpsQueryWithName(1, "email"), nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "UID1", cps[0].UID)
+		revision, err := sut.configStore.Get(ctx, 1)
+		require.NoError(t, err)
+		route := revision.cfg.AlertmanagerConfig.Route
+		require.Equal(t, "email", route.Receiver)
+		require.Equal(t, "email", route.Routes[0].Receiver)
+		require.Equal(t, [][2]string{{"grafana-default-email", "email"}}, nsStore.renames)
+	})
+
+	t.Run("fails if the user cannot change the contact points", func(t *testing.T) {
+		sut, nsStore := createSut(t)
+
+		reader := &user.SignedInUser{OrgID: 1, Permissions: map[int64]map[string][]string{
+			1: {accesscontrol.ActionAlertingNotificationsRead: nil},
+		}}
+		require.ErrorIs(t, sut.RenameContactPoint(ctx, reader, 1, "grafana-default-email", "email", models.ProvenanceAPI), ErrPermissionDenied)
+		require.ErrorIs(t, sut.RenameContactPoint(ctx, nil, 1, "grafana-default-email", "email", models.ProvenanceAPI), ErrPermissionDenied)
+		require.Empty(t, nsStore.renames)
+	})
+
+	t.Run("fails if the contact point is provisioned with another provenance", func(t *testing.T) {
+		sut, nsStore := createSut(t)
+		require.NoError(t, sut.provenanceStore.SetProvenance(ctx, &definitions.EmbeddedContactPoint{UID: "UID1"}, 1, models.ProvenanceFile))
+
+		err := sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "email", models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+		require.Empty(t, nsStore.renames)
+
+		require.NoError(t, sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "email", models.ProvenanceFile))
+	})
+
+	t.Run("fails if the references are provisioned with another provenance", func(t *testing.T) {
+		sut, nsStore := createSut(t)
+		revision, err := sut.configStore.Get(ctx, 1)
+		require.NoError(t, err)
+		require.NoError(t, sut.provenanceStore.SetProvenance(ctx, revision.cfg.AlertmanagerConfig.Route, 1, models.ProvenanceFile))
+
+		err = sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "email", models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		require.NoError(t, sut.provenanceStore.SetProvenance(ctx, revision.cfg.AlertmanagerConfig.Route, 1, models.ProvenanceAPI))
+		rule := models.AlertRuleGen(models.WithOrgID(1))()
+		require.NoError(t, sut.provenanceStore.SetProvenance(ctx, rule, 1, models.ProvenanceFile))
+		nsStore.used = map[models.AlertRuleKey][]models.NotificationSettings{rule.GetKey(): {{Receiver: "grafana-default-email"}}}
+
+		err = sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "email", models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+		require.Empty(t, nsStore.renames)
+	})
+
+	t.Run("fails if the contact point does not exist", func(t *testing.T) {
+		sut, _ := createSut(t)
+
+		err := sut.RenameContactPoint(ctx, writer, 1, "unknown", "email", models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("fails if a contact point with the new name exists", func(t *testing.T) {
+		sut, nsStore := createSut(t)
+
+		err := sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "slack receiver", models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.Empty(t, nsStore.renames)
+	})
+
+	t.Run("fails for invalid names", func(t *testing.T) {
+		sut, _ := createSut(t)
+
+		require.ErrorIs(t, sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "", models.ProvenanceAPI), ErrValidation)
+		require.ErrorIs(t, sut.RenameContactPoint(ctx, writer, 1, "grafana-default-email", "grafana-default-email", models.ProvenanceAPI), ErrValidation)
+	})
+}
+
 func TestContactPointInUse(t *testing.T) {
 	result := isContactPointInUse("test", []*definitions.Route{
 		{
@@ -372,9 +659,13 @@ func createContactPointServiceSut(t *testing.T, secretService secrets.Service) *
 		configStore:       &alertmanagerConfigStoreImpl{store: store},
 		provenanceStore:   provisioningStore,
 		receiverService:   receiverService,
+		tagStore:          provisioningStore,
 		xact:              xact,
 		encryptionService: secretService,
-		log:               log.NewNopLogger(),
+		secretReferences: setting.UnifiedAlertingSecretReferencesSettings{
+			AllowedPrefixes: []string{"secret/data/alerting/"},
+		},
+		log: log.NewNopLogger(),
 	}
 }
 
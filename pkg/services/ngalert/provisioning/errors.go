@@ -3,7 +3,10 @@ package provisioning
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util/errutil"
 )
 
@@ -19,6 +22,20 @@ var (
 	ErrTimeIntervalExists   = errutil.BadRequest("alerting.notifications.time-intervals.nameExists", errutil.WithPublicMessage("Time interval with this name already exists. Use a different name or update existing one."))
 	ErrTimeIntervalInvalid  = errutil.BadRequest("alerting.notifications.time-intervals.invalidFormat").MustTemplate("Invalid format of the submitted time interval", errutil.WithPublic("Time interval is in invalid format. Correct the payload and try again."))
 	ErrTimeIntervalInUse    = errutil.Conflict("alerting.notifications.time-intervals.used", errutil.WithPublicMessage("Time interval is used by one or many notification policies"))
+
+	ErrDeletedAlertRuleExists = errutil.Conflict("alerting.provisioning.deletedRuleExists", errutil.WithPublicMessage("An alert rule with the UID of the deleted alert rule exists. Delete it and try again."))
+
+	ErrAlertRuleVersionConflict = errutil.Conflict("alerting.provisioning.ruleVersionConflict", errutil.WithPublicMessage("The alert rule was changed since it was read. Get the current version of the rule and try again, or force the change."))
+
+	ErrRuleGroupMergeConflict = errutil.Conflict("alerting.provisioning.ruleGroupMergeConflict").MustTemplate("Rule group changes conflict with concurrent changes: {{ .Public.Conflicts }}", errutil.WithPublic("The rule group was changed since the version the changes are based on, and the changes conflict: {{ .Public.Conflicts }}. Resolve the conflicts and try again."))
+
+	ErrProvenanceNotAllowed = errutil.Conflict("alerting.provisioning.provenanceNotAllowed").MustTemplate("Cannot {{ .Public.Action }} alert rule '{{ .Public.RuleUID }}' with provided provenance '{{ .Public.Provenance }}', needs '{{ .Public.StoredProvenance }}'", errutil.WithPublic("Cannot {{ .Public.Action }} alert rule '{{ .Public.RuleUID }}' with provenance '{{ .Public.Provenance }}' because it is owned by provenance '{{ .Public.StoredProvenance }}'{{ with .Public.Source }} from {{ . }}{{ end }}. {{ .Public.Resolution }}"))
+
+	ErrProvenanceMismatch = errutil.Conflict("alerting.provisioning.provenanceMismatch", errutil.WithPublicMessage("The provenance of the resource is not the expected one. Get the current provenance of the resource and try again."))
+
+	ErrPolicyConflict = errutil.Conflict("alerting.notifications.policies.conflict").MustTemplate("Notification policy with matchers {{ .Public.Matchers }} already exists", errutil.WithPublic("A different notification policy with matchers {{ .Public.Matchers }} already exists under the anchor policy. Merge with overwrite to replace it."))
+
+	ErrTemplateInUse = errutil.Conflict("alerting.notifications.templates.used").MustTemplate("Template '{{ .Public.Name }}' defines templates used by {{ .Public.UsedBy }}", errutil.WithPublic("Template '{{ .Public.Name }}' defines templates used by {{ .Public.UsedBy }}. Remove the references and try again."))
 )
 
 func makeErrBadAlertmanagerConfiguration(err error) error {
@@ -42,3 +59,78 @@ func MakeErrTimeIntervalInvalid(err error) error {
 
 	return ErrTimeIntervalInvalid.Build(data)
 }
+
+// MakeErrTemplateInUse creates an error with the ErrTemplateInUse template
+func MakeErrTemplateInUse(name string, usedBy []string) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Name":   name,
+			"UsedBy": strings.Join(usedBy, ", "),
+		},
+	}
+
+	return ErrTemplateInUse.Build(data)
+}
+
+// MakeErrRuleGroupMergeConflict creates an error with the ErrRuleGroupMergeConflict template
+func MakeErrRuleGroupMergeConflict(conflicts []string) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Conflicts": strings.Join(conflicts, "; "),
+		},
+	}
+
+	return ErrRuleGroupMergeConflict.Build(data)
+}
+
+// MakeErrProvenanceNotAllowed creates an error with the ErrProvenanceNotAllowed template. The error tells which writer
+// owns the alert rule, from the metadata of its provenance if it has some, and how to take the rule over.
+func MakeErrProvenanceNotAllowed(action string, rule *models.AlertRule, stored, provenance models.Provenance, metadata *models.ProvenanceMetadata) error {
+	public := map[string]interface{}{
+		"Action":           action,
+		"RuleUID":          rule.UID,
+		"Provenance":       provenanceName(provenance),
+		"StoredProvenance": provenanceName(stored),
+		"Resolution":       provenanceResolution(rule, stored, provenance),
+	}
+	if metadata != nil {
+		public["Source"] = metadata.Source
+		public["ExternalID"] = metadata.ExternalID
+	}
+	return ErrProvenanceNotAllowed.Build(errutil.TemplateData{Public: public})
+}
+
+// provenanceName returns the name of the provenance in messages, where the absence of provenance is named none.
+func provenanceName(p models.Provenance) string {
+	if p == models.ProvenanceNone {
+		return "none"
+	}
+	return string(p)
+}
+
+// provenanceResolution returns how to change the alert rule, which has the stored provenance, with the provenance.
+func provenanceResolution(rule *models.AlertRule, stored, provenance models.Provenance) string {
+	var owner string
+	switch stored {
+	case models.ProvenanceFile:
+		owner = "Change the rule in its provisioning file instead, or remove it from the file and take it over"
+	default:
+		owner = "Change the rule with the provisioning API instead, or take it over"
+	}
+	handOver := fmt.Sprintf(`PUT /api/v1/provisioning/alert-rules/%s/provenance with the body {"from":%q,"to":%q}`, rule.UID, stored, provenance)
+	if rule.NamespaceUID != "" && rule.RuleGroup != "" {
+		return fmt.Sprintf("%s with %s, or the whole rule group with PUT /api/v1/provisioning/folder/%s/rule-groups/%s/provenance and the same body.", owner, handOver, rule.NamespaceUID, url.PathEscape(rule.RuleGroup))
+	}
+	return fmt.Sprintf("%s with %s.", owner, handOver)
+}
+
+// MakeErrPolicyConflict creates an error with the ErrPolicyConflict template
+func MakeErrPolicyConflict(matchers string) error {
+	data := errutil.TemplateData{
+		Public: map[string]interface{}{
+			"Matchers": matchers,
+		},
+	}
+
+	return ErrPolicyConflict.Build(data)
+}
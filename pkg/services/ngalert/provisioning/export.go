@@ -0,0 +1,186 @@
+package provisioning
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ExportETag returns a weak entity tag of the export of the rule groups. Variant identifies the representation of the
+// export, e.g. its format, so that different representations get different tags.
+//
+// The tag is computed from the keys, versions and positions of the rules and from the titles of the folders, which
+// are all that can change the export. This lets the caller answer a conditional request without serializing it.
+func ExportETag(variant string, groups []models.AlertRuleGroupWithFolderTitle) string {
+	h := sha256.New()
+	write := func(values ...string) {
+		for _, v := range values {
+			_, _ = io.WriteString(h, v)
+			_, _ = h.Write([]byte{0})
+		}
+	}
+	write(variant)
+	for _, g := range groups {
+		if g.AlertRuleGroup == nil {
+			continue
+		}
+		write(strconv.FormatInt(g.OrgID, 10), g.FolderUID, g.FolderTitle, g.Title, strconv.FormatInt(g.Interval, 10), strconv.Itoa(len(g.Rules)))
+		write(strconv.Itoa(len(g.FolderPath)))
+		write(g.FolderPath...)
+		for _, r := range g.Rules {
+			write(r.UID, strconv.FormatInt(r.Version, 10), strconv.Itoa(r.RuleGroupIndex))
+		}
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// ETagMatches returns true if the value of an If-None-Match header matches the entity tag. Tags are compared with the
+// weak comparison function, as required for If-None-Match.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteGzip streams the output of encode to w, compressed with gzip. The export is compressed while it is encoded,
+// so large exports are never held in memory uncompressed.
+func WriteGzip(w io.Writer, encode func(w io.Writer) error) error {
+	gz := gzip.NewWriter(w)
+	if err := encode(gz); err != nil {
+		_ = gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+const (
+	exportMetadataGeneratedAt = "grafana-export-generated-at"
+	exportMetadataSource      = "grafana-export-source"
+	exportMetadataRuleUIDs    = "grafana-export-rule-uids"
+)
+
+// ExportMetadata describes where an export of alert rules comes from. It is written as comments at the top of YAML
+// exports, so that it is kept when the files are passed around, and it is verified when the files are provisioned.
+type ExportMetadata struct {
+	GeneratedAt time.Time
+	// Source identifies the Grafana instance the rules were exported from, usually its root URL.
+	Source   string
+	RuleUIDs []string
+}
+
+// NewExportMetadata returns the metadata of the export of the rule groups.
+func NewExportMetadata(generatedAt time.Time, source string, groups []models.AlertRuleGroupWithFolderTitle) ExportMetadata {
+	m := ExportMetadata{GeneratedAt: generatedAt.UTC().Truncate(time.Second), Source: source, RuleUIDs: []string{}}
+	for _, g := range groups {
+		if g.AlertRuleGroup == nil {
+			continue
+		}
+		for _, r := range g.Rules {
+			m.RuleUIDs = append(m.RuleUIDs, r.UID)
+		}
+	}
+	return m
+}
+
+// Comment returns the metadata as the lines of a YAML comment, without the leading #.
+func (m ExportMetadata) Comment() string {
+	lines := []string{fmt.Sprintf("%s: %s", exportMetadataGeneratedAt, m.GeneratedAt.UTC().Format(time.RFC3339))}
+	if m.Source != "" {
+		lines = append(lines, fmt.Sprintf("%s: %s", exportMetadataSource, m.Source))
+	}
+	lines = append(lines, fmt.Sprintf("%s: %s", exportMetadataRuleUIDs, strings.Join(m.RuleUIDs, ",")))
+	return strings.Join(lines, "\n")
+}
+
+// ParseExportMetadata parses the metadata from the comments at the top of a YAML file. It returns nil if the file has
+// no metadata.
+func ParseExportMetadata(data []byte) (*ExportMetadata, error) {
+	var m *ExportMetadata
+	hasRuleUIDs := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		comment, ok := strings.CutPrefix(line, "#")
+		if !ok {
+			break
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(comment), ":")
+		if !ok || !strings.HasPrefix(key, "grafana-export-") {
+			continue
+		}
+		if m == nil {
+			m = &ExportMetadata{}
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case exportMetadataGeneratedAt:
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s in export metadata: %w", key, err)
+			}
+			m.GeneratedAt = t
+		case exportMetadataSource:
+			m.Source = value
+		case exportMetadataRuleUIDs:
+			hasRuleUIDs = true
+			m.RuleUIDs = []string{}
+			for _, uid := range strings.Split(value, ",") {
+				if uid = strings.TrimSpace(uid); uid != "" {
+					m.RuleUIDs = append(m.RuleUIDs, uid)
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return nil, nil
+	}
+	if m.GeneratedAt.IsZero() || !hasRuleUIDs {
+		return nil, fmt.Errorf("export metadata should contain %s and %s", exportMetadataGeneratedAt, exportMetadataRuleUIDs)
+	}
+	return m, nil
+}
+
+// Verify checks that the file contains the rules that were exported, and no other rule. Rules that are added to or
+// removed from an exported file must be reflected in its metadata, or the metadata removed.
+func (m ExportMetadata) Verify(ruleUIDs []string) error {
+	var errs []error
+	for _, uid := range m.RuleUIDs {
+		if !slices.Contains(ruleUIDs, uid) {
+			errs = append(errs, fmt.Errorf("rule '%s' was exported but is not in the file", uid))
+		}
+	}
+	for _, uid := range ruleUIDs {
+		if !slices.Contains(m.RuleUIDs, uid) {
+			errs = append(errs, fmt.Errorf("rule '%s' is in the file but was not exported", uid))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("file does not match its export metadata: %w", errors.Join(errs...))
+	}
+	return nil
+}
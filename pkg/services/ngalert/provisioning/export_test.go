@@ -0,0 +1,120 @@
+package provisioning
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestExportETag(t *testing.T) {
+	var orgID int64 = 1
+	rule := dummyRule("rule", orgID)
+	rule.UID = "rule-uid"
+	groups := func(mutate func(*models.AlertRule), folderTitle string) []models.AlertRuleGroupWithFolderTitle {
+		r := rule
+		mutate(&r)
+		return []models.AlertRuleGroupWithFolderTitle{
+			models.NewAlertRuleGroupWithFolderTitle(r.GetGroupKey(), []models.AlertRule{r}, folderTitle),
+		}
+	}
+	noop := func(*models.AlertRule) {}
+	etag := ExportETag("yaml", groups(noop, "folder"))
+
+	require.Regexp(t, `^W/"[0-9a-f]+"$`, etag)
+	require.Equal(t, etag, ExportETag("yaml", groups(noop, "folder")), "should be stable")
+	require.NotEqual(t, etag, ExportETag("json", groups(noop, "folder")), "should depend on the variant")
+	require.NotEqual(t, etag, ExportETag("yaml", groups(noop, "renamed folder")), "should depend on the folder title")
+	require.NotEqual(t, etag, ExportETag("yaml", groups(func(r *models.AlertRule) { r.Version++ }, "folder")), "should depend on the version of rules")
+	require.NotEqual(t, etag, ExportETag("yaml", nil), "should depend on the rules")
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `W/"abc"`
+	testCases := []struct {
+		ifNoneMatch string
+		expected    bool
+	}{
+		{ifNoneMatch: "", expected: false},
+		{ifNoneMatch: `W/"abc"`, expected: true},
+		{ifNoneMatch: `"abc"`, expected: true},
+		{ifNoneMatch: `"def", W/"abc"`, expected: true},
+		{ifNoneMatch: `"def"`, expected: false},
+		{ifNoneMatch: "*", expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.ifNoneMatch, func(t *testing.T) {
+			require.Equal(t, tc.expected, ETagMatches(tc.ifNoneMatch, etag))
+		})
+	}
+}
+
+func TestWriteGzip(t *testing.T) {
+	t.Run("should compress the output of the encoder", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteGzip(&buf, func(w io.Writer) error {
+			_, err := w.Write([]byte("export"))
+			return err
+		}))
+
+		r, err := gzip.NewReader(&buf)
+		require.NoError(t, err)
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, "export", string(b))
+	})
+
+	t.Run("should return the error of the encoder", func(t *testing.T) {
+		expected := errors.New("test")
+		err := WriteGzip(io.Discard, func(w io.Writer) error {
+			return expected
+		})
+		require.ErrorIs(t, err, expected)
+	})
+}
+
+func TestExportMetadata(t *testing.T) {
+	rule := dummyRule("rule", 1)
+	rule.UID = "rule-uid"
+	groups := []models.AlertRuleGroupWithFolderTitle{
+		models.NewAlertRuleGroupWithFolderTitle(rule.GetGroupKey(), []models.AlertRule{rule}, "folder"),
+	}
+	generatedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.FixedZone("UTC+1", 3600))
+	metadata := NewExportMetadata(generatedAt, "https://grafana.example.com/", groups)
+
+	file := "# " + strings.ReplaceAll(metadata.Comment(), "\n", "\n# ") + "\napiVersion: 1\n"
+	require.Equal(t, "# grafana-export-generated-at: 2024-03-01T09:00:00Z\n"+
+		"# grafana-export-source: https://grafana.example.com/\n"+
+		"# grafana-export-rule-uids: rule-uid\n"+
+		"apiVersion: 1\n", file)
+
+	parsed, err := ParseExportMetadata([]byte(file))
+	require.NoError(t, err)
+	require.True(t, generatedAt.Equal(parsed.GeneratedAt))
+	require.Equal(t, "https://grafana.example.com/", parsed.Source)
+	require.Equal(t, []string{"rule-uid"}, parsed.RuleUIDs)
+
+	require.NoError(t, parsed.Verify([]string{"rule-uid"}))
+	require.ErrorContains(t, parsed.Verify(nil), "rule 'rule-uid' was exported but is not in the file")
+	require.ErrorContains(t, parsed.Verify([]string{"rule-uid", "other"}), "rule 'other' is in the file but was not exported")
+
+	t.Run("files without metadata have none", func(t *testing.T) {
+		parsed, err := ParseExportMetadata([]byte("# my rules\napiVersion: 1\n# grafana-export-rule-uids: a\n"))
+		require.NoError(t, err)
+		require.Nil(t, parsed)
+	})
+
+	t.Run("incomplete metadata is invalid", func(t *testing.T) {
+		_, err := ParseExportMetadata([]byte("# grafana-export-source: https://grafana.example.com/\napiVersion: 1\n"))
+		require.Error(t, err)
+		_, err = ParseExportMetadata([]byte("# grafana-export-generated-at: yesterday\n# grafana-export-rule-uids: a\n"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,866 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ErrFakeStoreConflict is returned by FakeStore when a conflict is simulated. It wraps store.ErrOptimisticLock, which
+// is what the database store returns when a concurrent write changed the same records.
+var ErrFakeStoreConflict = fmt.Errorf("%w: simulated conflict", store.ErrOptimisticLock)
+
+// FakeStore is an in-memory implementation of RuleStore, ProvisioningStore and TransactionManager. It is meant for
+// tests of code that uses AlertRuleService concurrently, and behaves like the database store where it matters for
+// such tests:
+//   - rules are copied in and out of the store, so callers never share memory with it;
+//   - updates are checked with optimistic locking on the version of the rule, and fail with store.ErrOptimisticLock;
+//   - titles of rules must be unique within a folder;
+//   - transactions are isolated from each other, and their changes are rolled back if they fail.
+//
+// Writes are serialized, which is stricter than the database: a write outside a transaction runs in its own
+// transaction, and waits for other transactions to finish. Reads outside a transaction are not, and can interleave
+// with transactions and observe their uncommitted changes. Use Latency and Conflict to make such interleavings likely.
+type FakeStore struct {
+	// Latency, if set, returns the time to wait before executing the method with the given name, e.g. "UpdateAlertRules".
+	// The wait is interrupted if the context is cancelled.
+	Latency func(method string) time.Duration
+	// Conflict, if set, is called before each write with the name of the method. If it returns true, the write fails
+	// with ErrFakeStoreConflict.
+	Conflict func(method string) bool
+	// Locked, if set, is called for each rule group that is locked. If it returns true, the group is held by a
+	// simulated concurrent change and locking fails with store.ErrGroupLocked. Other locks are always acquired since
+	// transactions are serialized.
+	Locked func(key models.AlertRuleGroupKey) bool
+
+	// txMtx serializes transactions.
+	txMtx sync.Mutex
+	// mtx guards the data below.
+	mtx         sync.Mutex
+	lastID      int64
+	rules       map[int64]map[string]*models.AlertRule
+	provenances map[int64]map[string]map[string]fakeProvenance
+	// folderAnnotations are the default annotations of folders by org and folder UID.
+	folderAnnotations map[int64]map[string]map[string]string
+	// pausedFolders are the folders whose rules are not evaluated.
+	pausedFolders map[models.FolderKey]struct{}
+	// archivedGroups are the rule groups whose rules are kept but not evaluated.
+	archivedGroups map[models.AlertRuleGroupKey]struct{}
+	// managedBy is who manages the rule groups.
+	managedBy map[models.AlertRuleGroupKey]models.ManagedBy
+	// templates are the alert rule templates by org and UID.
+	templates map[int64]map[string]*models.AlertRuleTemplate
+}
+
+type fakeProvenance struct {
+	provenance models.Provenance
+	metadata   *models.ProvenanceMetadata
+}
+
+type fakeStoreState struct {
+	lastID            int64
+	rules             map[int64]map[string]*models.AlertRule
+	provenances       map[int64]map[string]map[string]fakeProvenance
+	folderAnnotations map[int64]map[string]map[string]string
+	pausedFolders     map[models.FolderKey]struct{}
+	archivedGroups    map[models.AlertRuleGroupKey]struct{}
+	managedBy         map[models.AlertRuleGroupKey]models.ManagedBy
+	templates         map[int64]map[string]*models.AlertRuleTemplate
+}
+
+type fakeStoreTxKey struct{}
+
+type fakeStoreTx struct {
+	afterCommit []func()
+}
+
+var (
+	_ RuleStore          = (*FakeStore)(nil)
+	_ ProvisioningStore  = (*FakeStore)(nil)
+	_ TransactionManager = (*FakeStore)(nil)
+)
+
+func NewFakeStore() *FakeStore {
+	return &FakeStore{
+		rules:             make(map[int64]map[string]*models.AlertRule),
+		provenances:       make(map[int64]map[string]map[string]fakeProvenance),
+		folderAnnotations: make(map[int64]map[string]map[string]string),
+		pausedFolders:     make(map[models.FolderKey]struct{}),
+		archivedGroups:    make(map[models.AlertRuleGroupKey]struct{}),
+		managedBy:         make(map[models.AlertRuleGroupKey]models.ManagedBy),
+		templates:         make(map[int64]map[string]*models.AlertRuleTemplate),
+	}
+}
+
+// PutRules stores copies of the rules as they are, without checks. Rules without ID get one.
+func (f *FakeStore) PutRules(rules ...models.AlertRule) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	for _, r := range rules {
+		if r.ID == 0 {
+			f.lastID++
+			r.ID = f.lastID
+		} else if r.ID > f.lastID {
+			f.lastID = r.ID
+		}
+		f.putRule(&r)
+	}
+}
+
+// Rules returns copies of all rules of the org, ordered like the database store does.
+func (f *FakeStore) Rules(orgID int64) []*models.AlertRule {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.filterRules(orgID, func(*models.AlertRule) bool { return true })
+}
+
+// InTransaction runs work in a transaction. Transactions of the store are serialized. If work is called with a
+// context that is already in a transaction of the store, it joins that transaction.
+func (f *FakeStore) InTransaction(ctx context.Context, work func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(fakeStoreTxKey{}).(*fakeStoreTx); ok {
+		return work(ctx)
+	}
+	f.txMtx.Lock()
+	snapshot := f.snapshot()
+	tx := &fakeStoreTx{}
+	err := work(context.WithValue(ctx, fakeStoreTxKey{}, tx))
+	if err != nil {
+		f.restore(snapshot)
+	}
+	f.txMtx.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, fn := range tx.afterCommit {
+		fn()
+	}
+	return nil
+}
+
+// InSavepoint runs work in a savepoint of the transaction in the context, or in a new transaction if there is none.
+func (f *FakeStore) InSavepoint(ctx context.Context, work func(ctx context.Context) error) error {
+	outer, ok := ctx.Value(fakeStoreTxKey{}).(*fakeStoreTx)
+	if !ok {
+		return f.InTransaction(ctx, work)
+	}
+	snapshot := f.snapshot()
+	tx := &fakeStoreTx{}
+	if err := work(context.WithValue(ctx, fakeStoreTxKey{}, tx)); err != nil {
+		f.restore(snapshot)
+		return err
+	}
+	outer.afterCommit = append(outer.afterCommit, tx.afterCommit...)
+	return nil
+}
+
+// InSnapshot runs work in a transaction. Transactions of the store are serialized, so its reads are not interleaved
+// with the changes of other transactions.
+func (f *FakeStore) InSnapshot(ctx context.Context, work func(ctx context.Context) error) error {
+	return f.InTransaction(ctx, work)
+}
+
+// AfterCommit registers fn to be called after the transaction in the context is committed. If there is no
+// transaction, fn is called immediately.
+func (f *FakeStore) AfterCommit(ctx context.Context, fn func()) {
+	if tx, ok := ctx.Value(fakeStoreTxKey{}).(*fakeStoreTx); ok {
+		tx.afterCommit = append(tx.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+func (f *FakeStore) GetAlertRuleByUID(ctx context.Context, query *models.GetAlertRuleByUIDQuery) (*models.AlertRule, error) {
+	var result *models.AlertRule
+	err := f.read(ctx, "GetAlertRuleByUID", func() error {
+		r, ok := f.rules[query.OrgID][query.UID]
+		if !ok {
+			return models.ErrAlertRuleNotFound
+		}
+		result = models.CopyRule(r)
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error) {
+	var result models.RulesGroup
+	err := f.read(ctx, "ListAlertRules", func() error {
+		result = f.filterRules(query.OrgID, func(r *models.AlertRule) bool {
+			if len(query.NamespaceUIDs) > 0 && !slices.Contains(query.NamespaceUIDs, r.NamespaceUID) {
+				return false
+			}
+			if query.RuleGroup != "" && r.RuleGroup != query.RuleGroup {
+				return false
+			}
+			if !strings.HasPrefix(r.RuleGroup, query.RuleGroupPrefix) || !query.MatchLabels(r.Labels) {
+				return false
+			}
+			if len(query.Provenances) > 0 && !slices.Contains(query.Provenances, f.provenances[r.OrgID][r.ResourceType()][r.ResourceID()].provenance) {
+				return false
+			}
+			if query.ExpiredAt != nil && !r.IsExpired(*query.ExpiredAt) {
+				return false
+			}
+			if _, archived := f.archivedGroups[r.GetGroupKey()]; (archived && query.ArchivedGroups == models.ArchivedRuleGroupsExclude) || (!archived && query.ArchivedGroups == models.ArchivedRuleGroupsOnly) {
+				return false
+			}
+			if query.DashboardUID != "" {
+				if r.DashboardUID == nil || *r.DashboardUID != query.DashboardUID {
+					return false
+				}
+				if query.PanelID != 0 && (r.PanelID == nil || *r.PanelID != query.PanelID) {
+					return false
+				}
+			}
+			if query.ReceiverName != "" {
+				return slices.ContainsFunc(r.NotificationSettings, func(s models.NotificationSettings) bool {
+					return s.Receiver == query.ReceiverName
+				})
+			}
+			return true
+		})
+		if query.Limit > 0 {
+			start := min(query.Offset(), int64(len(result)))
+			end := min(start+query.Limit, int64(len(result)))
+			result = result[start:end]
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) SearchAlertRules(ctx context.Context, query *models.SearchAlertRulesQuery) (*models.SearchAlertRulesResult, error) {
+	result := &models.SearchAlertRulesResult{}
+	err := f.read(ctx, "SearchAlertRules", func() error {
+		text := strings.ToLower(query.Query)
+		matches := func(values map[string]string) bool {
+			for k, v := range values {
+				if strings.Contains(strings.ToLower(k), text) || strings.Contains(strings.ToLower(v), text) {
+					return true
+				}
+			}
+			return false
+		}
+		rules := f.filterRules(query.OrgID, func(r *models.AlertRule) bool {
+			if len(query.NamespaceUIDs) > 0 && !slices.Contains(query.NamespaceUIDs, r.NamespaceUID) {
+				return false
+			}
+			return strings.Contains(strings.ToLower(r.Title), text) || matches(r.Labels) || matches(r.Annotations)
+		})
+		result.TotalCount = int64(len(rules))
+		if query.Limit > 0 {
+			page := query.Page
+			if page < 1 {
+				page = 1
+			}
+			start := min((page-1)*query.Limit, int64(len(rules)))
+			end := min(start+query.Limit, int64(len(rules)))
+			rules = rules[start:end]
+		}
+		result.Rules = rules
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error) {
+	var interval int64
+	err := f.read(ctx, "GetRuleGroupInterval", func() error {
+		for _, r := range f.rules[orgID] {
+			if r.NamespaceUID == namespaceUID && r.RuleGroup == ruleGroup {
+				interval = r.IntervalSeconds
+				return nil
+			}
+		}
+		return models.ErrAlertRuleGroupNotFound.Errorf("")
+	})
+	return interval, err
+}
+
+func (f *FakeStore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error) {
+	var result []*models.AlertRule
+	err := f.read(ctx, "GetAlertRulesGroupByRuleUID", func() error {
+		rule, ok := f.rules[query.OrgID][query.UID]
+		if !ok {
+			result = []*models.AlertRule{}
+			return nil
+		}
+		key := rule.GetGroupKey()
+		result = f.filterRules(query.OrgID, func(r *models.AlertRule) bool {
+			return r.GetGroupKey() == key
+		})
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) GetAlertRulesGroupsByRuleUIDs(ctx context.Context, query *models.GetAlertRulesGroupsByRuleUIDsQuery) (map[models.AlertRuleGroupKey]models.RulesGroup, error) {
+	result := make(map[models.AlertRuleGroupKey]models.RulesGroup)
+	err := f.read(ctx, "GetAlertRulesGroupsByRuleUIDs", func() error {
+		selected := make(map[models.AlertRuleGroupKey]struct{})
+		for _, uid := range query.UIDs {
+			if rule, ok := f.rules[query.OrgID][uid]; ok {
+				selected[rule.GetGroupKey()] = struct{}{}
+			}
+		}
+		for _, rule := range f.filterRules(query.OrgID, func(r *models.AlertRule) bool {
+			_, ok := selected[r.GetGroupKey()]
+			return ok
+		}) {
+			result[rule.GetGroupKey()] = append(result[rule.GetGroupKey()], rule)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// GetAlertRuleVersions returns the current version of the rule only, as the store does not keep the history of rules.
+func (f *FakeStore) GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) ([]*models.AlertRuleVersion, error) {
+	versions := make([]*models.AlertRuleVersion, 0, 1)
+	err := f.read(ctx, "GetAlertRuleVersions", func() error {
+		rule, ok := f.rules[orgID][ruleUID]
+		if !ok {
+			return nil
+		}
+		r := models.CopyRule(rule)
+		versions = append(versions, &models.AlertRuleVersion{
+			ID:                      r.ID,
+			RuleOrgID:               r.OrgID,
+			RuleUID:                 r.UID,
+			RuleNamespaceUID:        r.NamespaceUID,
+			RuleGroup:               r.RuleGroup,
+			RuleGroupIndex:          r.RuleGroupIndex,
+			Version:                 r.Version,
+			Created:                 r.Updated,
+			Title:                   r.Title,
+			Condition:               r.Condition,
+			Data:                    r.Data,
+			IntervalSeconds:         r.IntervalSeconds,
+			NoDataState:             r.NoDataState,
+			ExecErrState:            r.ExecErrState,
+			For:                     r.For,
+			Annotations:             r.Annotations,
+			Labels:                  r.Labels,
+			IsPaused:                r.IsPaused,
+			NotificationSettings:    r.NotificationSettings,
+			DataAvailabilityPeriod:  r.DataAvailabilityPeriod,
+			DataAvailabilityDelay:   r.DataAvailabilityDelay,
+			ShardAffinity:           r.ShardAffinity,
+			IncidentHooks:           r.IncidentHooks,
+			ExpiresAt:               r.ExpiresAt,
+			IntervalOverrideSeconds: r.IntervalOverrideSeconds,
+			EvaluationTimeout:       r.EvaluationTimeout,
+			Record:                  r.Record,
+		})
+		return nil
+	})
+	return versions, err
+}
+
+func (f *FakeStore) CountByProvenances(ctx context.Context, orgID int64, provenances ...models.Provenance) (int64, error) {
+	var count int64
+	err := f.read(ctx, "CountByProvenances", func() error {
+		stored := f.provenances[orgID][(&models.AlertRule{}).ResourceType()]
+		for uid := range f.rules[orgID] {
+			p, ok := stored[uid]
+			if !ok {
+				p.provenance = models.ProvenanceNone
+			}
+			if slices.Contains(provenances, p.provenance) {
+				count++
+			}
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (f *FakeStore) InsertAlertRules(ctx context.Context, rules []models.AlertRule) ([]models.AlertRuleKeyWithId, error) {
+	ids := make([]models.AlertRuleKeyWithId, 0, len(rules))
+	err := f.write(ctx, "InsertAlertRules", func() error {
+		for _, r := range rules {
+			if r.UID == "" {
+				r.UID = util.GenerateShortUID()
+			} else if _, ok := f.rules[r.OrgID][r.UID]; ok {
+				return fmt.Errorf("failed to create new rules: rule with UID %s already exists", r.UID)
+			}
+			if err := f.checkUniqueTitle(r); err != nil {
+				return err
+			}
+			f.lastID++
+			r.ID = f.lastID
+			r.Version = 1
+			f.putRule(&r)
+			ids = append(ids, models.AlertRuleKeyWithId{AlertRuleKey: r.GetKey(), ID: r.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (f *FakeStore) UpdateAlertRules(ctx context.Context, rules []models.UpdateRule) error {
+	return f.write(ctx, "UpdateAlertRules", func() error {
+		for _, r := range rules {
+			stored, ok := f.rules[r.Existing.OrgID][r.Existing.UID]
+			if !ok || stored.Version != r.Existing.Version {
+				return fmt.Errorf("%w: alert rule UID %s version %d", store.ErrOptimisticLock, r.Existing.UID, r.Existing.Version)
+			}
+			updated := r.New
+			updated.ID = stored.ID
+			updated.Version = stored.Version + 1
+			delete(f.rules[stored.OrgID], stored.UID)
+			f.putRule(&updated)
+		}
+		// The constraint is checked at the end because the database store avoids intermediate violations.
+		for _, r := range rules {
+			if err := f.checkUniqueTitle(r.New); err != nil {
+				return err
+			}
+			f.deleteEmptyGroups(r.New.OrgID)
+		}
+		return nil
+	})
+}
+
+func (f *FakeStore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error {
+	return f.write(ctx, "DeleteAlertRulesByUID", func() error {
+		for _, uid := range ruleUID {
+			delete(f.rules[orgID], uid)
+		}
+		f.deleteEmptyGroups(orgID)
+		return nil
+	})
+}
+
+func (f *FakeStore) GetProvenance(ctx context.Context, o models.Provisionable, org int64) (models.Provenance, error) {
+	result := models.ProvenanceNone
+	err := f.read(ctx, "GetProvenance", func() error {
+		if p, ok := f.provenances[org][o.ResourceType()][o.ResourceID()]; ok {
+			result = p.provenance
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) GetProvenances(ctx context.Context, org int64, resourceType string) (map[string]models.Provenance, error) {
+	result := make(map[string]models.Provenance)
+	err := f.read(ctx, "GetProvenances", func() error {
+		for id, p := range f.provenances[org][resourceType] {
+			result[id] = p.provenance
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) GetProvenanceMetadata(ctx context.Context, o models.Provisionable, org int64) (*models.ProvenanceMetadata, error) {
+	var result *models.ProvenanceMetadata
+	err := f.read(ctx, "GetProvenanceMetadata", func() error {
+		if p, ok := f.provenances[org][o.ResourceType()][o.ResourceID()]; ok && p.metadata != nil {
+			m := *p.metadata
+			result = &m
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) GetProvenancesMetadata(ctx context.Context, org int64, resourceType string) (map[string]models.ProvenanceMetadata, error) {
+	result := make(map[string]models.ProvenanceMetadata)
+	err := f.read(ctx, "GetProvenancesMetadata", func() error {
+		for id, p := range f.provenances[org][resourceType] {
+			if p.metadata != nil {
+				result[id] = *p.metadata
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error {
+	return f.write(ctx, "SetProvenance", func() error {
+		f.putProvenance(org, o, p, nil)
+		return nil
+	})
+}
+
+func (f *FakeStore) SetProvenances(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance) error {
+	return f.write(ctx, "SetProvenances", func() error {
+		for _, o := range objects {
+			f.putProvenance(org, o, p, nil)
+		}
+		return nil
+	})
+}
+
+func (f *FakeStore) SetProvenanceWithMetadata(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata) error {
+	return f.write(ctx, "SetProvenanceWithMetadata", func() error {
+		f.putProvenance(org, o, p, metadata)
+		return nil
+	})
+}
+
+func (f *FakeStore) DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error {
+	return f.write(ctx, "DeleteProvenance", func() error {
+		delete(f.provenances[org][o.ResourceType()], o.ResourceID())
+		return nil
+	})
+}
+
+func (f *FakeStore) GetFolderAnnotations(ctx context.Context, orgID int64, folderUID string) (map[string]string, error) {
+	var result map[string]string
+	err := f.read(ctx, "GetFolderAnnotations", func() error {
+		annotations := f.folderAnnotations[orgID][folderUID]
+		if len(annotations) > 0 {
+			result = maps.Clone(annotations)
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) SetFolderAnnotations(ctx context.Context, orgID int64, folderUID string, annotations map[string]string) error {
+	return f.write(ctx, "SetFolderAnnotations", func() error {
+		if len(annotations) == 0 {
+			delete(f.folderAnnotations[orgID], folderUID)
+			return nil
+		}
+		if f.folderAnnotations[orgID] == nil {
+			f.folderAnnotations[orgID] = make(map[string]map[string]string)
+		}
+		f.folderAnnotations[orgID][folderUID] = maps.Clone(annotations)
+		return nil
+	})
+}
+
+func (f *FakeStore) GetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string) (bool, error) {
+	var paused bool
+	err := f.read(ctx, "GetFolderEvaluationPaused", func() error {
+		_, paused = f.pausedFolders[models.FolderKey{OrgID: orgID, UID: folderUID}]
+		return nil
+	})
+	return paused, err
+}
+
+func (f *FakeStore) SetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string, paused bool) error {
+	return f.write(ctx, "SetFolderEvaluationPaused", func() error {
+		key := models.FolderKey{OrgID: orgID, UID: folderUID}
+		if paused {
+			f.pausedFolders[key] = struct{}{}
+		} else {
+			delete(f.pausedFolders, key)
+		}
+		return nil
+	})
+}
+
+func (f *FakeStore) GetArchivedRuleGroups(ctx context.Context, orgID int64) ([]models.AlertRuleGroupKey, error) {
+	var result []models.AlertRuleGroupKey
+	err := f.read(ctx, "GetArchivedRuleGroups", func() error {
+		for key := range f.archivedGroups {
+			if key.OrgID == orgID {
+				result = append(result, key)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) SetRuleGroupArchived(ctx context.Context, key models.AlertRuleGroupKey, archived bool) error {
+	return f.write(ctx, "SetRuleGroupArchived", func() error {
+		if archived {
+			f.archivedGroups[key] = struct{}{}
+		} else {
+			delete(f.archivedGroups, key)
+		}
+		return nil
+	})
+}
+
+func (f *FakeStore) GetRuleGroupsManagedBy(ctx context.Context, orgID int64) (map[models.AlertRuleGroupKey]models.ManagedBy, error) {
+	result := make(map[models.AlertRuleGroupKey]models.ManagedBy)
+	err := f.read(ctx, "GetRuleGroupsManagedBy", func() error {
+		for key, managedBy := range f.managedBy {
+			if key.OrgID == orgID {
+				result[key] = managedBy
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) SetRuleGroupManagedBy(ctx context.Context, key models.AlertRuleGroupKey, managedBy *models.ManagedBy) error {
+	return f.write(ctx, "SetRuleGroupManagedBy", func() error {
+		if managedBy != nil {
+			f.managedBy[key] = *managedBy
+		} else {
+			delete(f.managedBy, key)
+		}
+		return nil
+	})
+}
+
+func (f *FakeStore) LockRuleGroups(ctx context.Context, keys ...models.AlertRuleGroupKey) error {
+	if err := f.wait(ctx, "LockRuleGroups"); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if f.Locked != nil && f.Locked(key) {
+			return store.ErrGroupLocked.Errorf("rule group %s is locked by a simulated concurrent change", key)
+		}
+	}
+	return nil
+}
+
+func (f *FakeStore) ListAlertRuleTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, error) {
+	result := make([]*models.AlertRuleTemplate, 0)
+	err := f.read(ctx, "ListAlertRuleTemplates", func() error {
+		for _, t := range f.templates[orgID] {
+			result = append(result, copyAlertRuleTemplate(t))
+		}
+		slices.SortFunc(result, func(a, b *models.AlertRuleTemplate) int {
+			if c := strings.Compare(a.Title, b.Title); c != 0 {
+				return c
+			}
+			return strings.Compare(a.UID, b.UID)
+		})
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	var result *models.AlertRuleTemplate
+	err := f.read(ctx, "GetAlertRuleTemplate", func() error {
+		t, ok := f.templates[orgID][uid]
+		if !ok {
+			return models.ErrAlertRuleTemplateNotFound.Errorf("")
+		}
+		result = copyAlertRuleTemplate(t)
+		return nil
+	})
+	return result, err
+}
+
+func (f *FakeStore) SaveAlertRuleTemplate(ctx context.Context, template models.AlertRuleTemplate) error {
+	return f.write(ctx, "SaveAlertRuleTemplate", func() error {
+		if f.templates[template.OrgID] == nil {
+			f.templates[template.OrgID] = make(map[string]*models.AlertRuleTemplate)
+		}
+		f.templates[template.OrgID][template.UID] = copyAlertRuleTemplate(&template)
+		return nil
+	})
+}
+
+func (f *FakeStore) DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return f.write(ctx, "DeleteAlertRuleTemplate", func() error {
+		if _, ok := f.templates[orgID][uid]; !ok {
+			return models.ErrAlertRuleTemplateNotFound.Errorf("")
+		}
+		delete(f.templates[orgID], uid)
+		return nil
+	})
+}
+
+func copyAlertRuleTemplate(t *models.AlertRuleTemplate) *models.AlertRuleTemplate {
+	result := *t
+	result.Parameters = slices.Clone(t.Parameters)
+	result.Rule = *models.CopyRule(&t.Rule)
+	return &result
+}
+
+// deleteEmptyGroups unarchives the groups of the org that have no rules anymore and deletes who manages them, like the
+// database store does.
+func (f *FakeStore) deleteEmptyGroups(orgID int64) {
+	groups := make(map[models.AlertRuleGroupKey]struct{})
+	for _, r := range f.rules[orgID] {
+		groups[r.GetGroupKey()] = struct{}{}
+	}
+	for key := range f.archivedGroups {
+		if _, ok := groups[key]; !ok && key.OrgID == orgID {
+			delete(f.archivedGroups, key)
+		}
+	}
+	for key := range f.managedBy {
+		if _, ok := groups[key]; !ok && key.OrgID == orgID {
+			delete(f.managedBy, key)
+		}
+	}
+}
+
+// read waits for the latency of the method and calls fn with the data locked.
+func (f *FakeStore) read(ctx context.Context, method string, fn func() error) error {
+	if err := f.wait(ctx, method); err != nil {
+		return err
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return fn()
+}
+
+// write waits for the latency of the method, simulates a conflict if requested, and calls fn with the data locked.
+// Outside a transaction, fn is executed in its own transaction, like a single statement would be.
+func (f *FakeStore) write(ctx context.Context, method string, fn func() error) error {
+	if err := f.wait(ctx, method); err != nil {
+		return err
+	}
+	if f.Conflict != nil && f.Conflict(method) {
+		return ErrFakeStoreConflict
+	}
+	return f.InTransaction(ctx, func(ctx context.Context) error {
+		f.mtx.Lock()
+		defer f.mtx.Unlock()
+		return fn()
+	})
+}
+
+func (f *FakeStore) wait(ctx context.Context, method string) error {
+	if f.Latency == nil {
+		return nil
+	}
+	d := f.Latency(method)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func (f *FakeStore) putRule(r *models.AlertRule) {
+	org, ok := f.rules[r.OrgID]
+	if !ok {
+		org = make(map[string]*models.AlertRule)
+		f.rules[r.OrgID] = org
+	}
+	org[r.UID] = models.CopyRule(r)
+}
+
+func (f *FakeStore) putProvenance(org int64, o models.Provisionable, p models.Provenance, metadata *models.ProvenanceMetadata) {
+	byType, ok := f.provenances[org]
+	if !ok {
+		byType = make(map[string]map[string]fakeProvenance)
+		f.provenances[org] = byType
+	}
+	byID, ok := byType[o.ResourceType()]
+	if !ok {
+		byID = make(map[string]fakeProvenance)
+		byType[o.ResourceType()] = byID
+	}
+	record := fakeProvenance{provenance: p}
+	if metadata != nil {
+		m := *metadata
+		record.metadata = &m
+	}
+	byID[o.ResourceID()] = record
+}
+
+func (f *FakeStore) checkUniqueTitle(rule models.AlertRule) error {
+	for _, r := range f.rules[rule.OrgID] {
+		if r.UID != rule.UID && r.NamespaceUID == rule.NamespaceUID && strings.EqualFold(r.Title, rule.Title) {
+			return models.ErrAlertRuleConflict(rule, models.ErrAlertRuleUniqueConstraintViolation)
+		}
+	}
+	return nil
+}
+
+// filterRules returns copies of the rules of the org that match the predicate, ordered like the database store does.
+func (f *FakeStore) filterRules(orgID int64, match func(r *models.AlertRule) bool) []*models.AlertRule {
+	result := make([]*models.AlertRule, 0)
+	for _, r := range f.rules[orgID] {
+		if match(r) {
+			result = append(result, models.CopyRule(r))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.NamespaceUID != b.NamespaceUID {
+			return a.NamespaceUID < b.NamespaceUID
+		}
+		if a.RuleGroup != b.RuleGroup {
+			return a.RuleGroup < b.RuleGroup
+		}
+		if a.RuleGroupIndex != b.RuleGroupIndex {
+			return a.RuleGroupIndex < b.RuleGroupIndex
+		}
+		return a.ID < b.ID
+	})
+	return result
+}
+
+func (f *FakeStore) snapshot() fakeStoreState {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	state := fakeStoreState{
+		lastID:            f.lastID,
+		rules:             make(map[int64]map[string]*models.AlertRule, len(f.rules)),
+		provenances:       make(map[int64]map[string]map[string]fakeProvenance, len(f.provenances)),
+		folderAnnotations: make(map[int64]map[string]map[string]string, len(f.folderAnnotations)),
+		pausedFolders:     maps.Clone(f.pausedFolders),
+		archivedGroups:    maps.Clone(f.archivedGroups),
+		managedBy:         maps.Clone(f.managedBy),
+		templates:         make(map[int64]map[string]*models.AlertRuleTemplate, len(f.templates)),
+	}
+	for orgID, rules := range f.rules {
+		// Stored rules are never modified in place, so it is enough to copy the maps.
+		state.rules[orgID] = make(map[string]*models.AlertRule, len(rules))
+		for uid, r := range rules {
+			state.rules[orgID][uid] = r
+		}
+	}
+	for orgID, byType := range f.provenances {
+		state.provenances[orgID] = make(map[string]map[string]fakeProvenance, len(byType))
+		for resourceType, byID := range byType {
+			state.provenances[orgID][resourceType] = make(map[string]fakeProvenance, len(byID))
+			for id, p := range byID {
+				state.provenances[orgID][resourceType][id] = p
+			}
+		}
+	}
+	for orgID, byFolder := range f.folderAnnotations {
+		// Annotations of folders are replaced as a whole, so it is enough to copy the maps of folders.
+		state.folderAnnotations[orgID] = make(map[string]map[string]string, len(byFolder))
+		for folderUID, annotations := range byFolder {
+			state.folderAnnotations[orgID][folderUID] = annotations
+		}
+	}
+	for orgID, byUID := range f.templates {
+		// Templates are replaced as a whole, so it is enough to copy the maps of templates.
+		state.templates[orgID] = maps.Clone(byUID)
+	}
+	return state
+}
+
+func (f *FakeStore) restore(state fakeStoreState) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.lastID = state.lastID
+	f.rules = state.rules
+	f.provenances = state.provenances
+	f.folderAnnotations = state.folderAnnotations
+	f.pausedFolders = state.pausedFolders
+	f.archivedGroups = state.archivedGroups
+	f.managedBy = state.managedBy
+	f.templates = state.templates
+}
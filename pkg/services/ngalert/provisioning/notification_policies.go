@@ -1,8 +1,17 @@
 package provisioning
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -54,16 +63,108 @@ func (nps *NotificationPolicyService) GetPolicyTree(ctx context.Context, orgID i
 	return result, nil
 }
 
-func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
-	err := tree.Validate()
+// GetEffectivePolicy returns the settings of the notification policy at routePath, which contains the indexes of the
+// nested policies that lead to it from the root, after resolving the values that it inherits from its parents.
+func (nps *NotificationPolicyService) GetEffectivePolicy(ctx context.Context, orgID int64, routePath []int) (definitions.EffectivePolicy, error) {
+	rev, err := nps.configStore.Get(ctx, orgID)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+		return definitions.EffectivePolicy{}, err
+	}
+	if rev.cfg.AlertmanagerConfig.Config.Route == nil {
+		return definitions.EffectivePolicy{}, fmt.Errorf("no route present in current alertmanager config")
+	}
+	return effectivePolicy(rev.cfg.AlertmanagerConfig.Config.Route, routePath)
+}
+
+func effectivePolicy(root *definitions.Route, routePath []int) (definitions.EffectivePolicy, error) {
+	result := definitions.EffectivePolicy{
+		Path:           slices.Clone(routePath),
+		GroupWait:      model.Duration(dispatch.DefaultRouteOpts.GroupWait),
+		GroupInterval:  model.Duration(dispatch.DefaultRouteOpts.GroupInterval),
+		RepeatInterval: model.Duration(dispatch.DefaultRouteOpts.RepeatInterval),
+		InheritedFrom:  map[string][]int{},
+	}
+	if result.Path == nil {
+		result.Path = []int{}
 	}
+	// sources contains the path of the policy that sets each of the settings, absent for the default values.
+	sources := map[string][]int{}
+	route := root
+	for depth := 0; ; depth++ {
+		current := routePath[:depth]
+		if route.Receiver != "" {
+			result.Receiver = route.Receiver
+			sources[definitions.EffectivePolicyReceiver] = current
+		}
+		if route.GroupByStr != nil {
+			result.GroupBy = route.GroupByStr
+			sources[definitions.EffectivePolicyGroupBy] = current
+		}
+		if route.GroupWait != nil {
+			result.GroupWait = *route.GroupWait
+			sources[definitions.EffectivePolicyGroupWait] = current
+		}
+		if route.GroupInterval != nil {
+			result.GroupInterval = *route.GroupInterval
+			sources[definitions.EffectivePolicyGroupInterval] = current
+		}
+		if route.RepeatInterval != nil {
+			result.RepeatInterval = *route.RepeatInterval
+			sources[definitions.EffectivePolicyRepeatInterval] = current
+		}
+		if depth == len(routePath) {
+			break
+		}
+		idx := routePath[depth]
+		if idx < 0 || idx >= len(route.Routes) {
+			return definitions.EffectivePolicy{}, fmt.Errorf("%w: notification policy at path %v does not exist", ErrNotFound, routePath[:depth+1])
+		}
+		route = route.Routes[idx]
+	}
+
+	for _, setting := range []string{
+		definitions.EffectivePolicyReceiver,
+		definitions.EffectivePolicyGroupBy,
+		definitions.EffectivePolicyGroupWait,
+		definitions.EffectivePolicyGroupInterval,
+		definitions.EffectivePolicyRepeatInterval,
+	} {
+		source, ok := sources[setting]
+		switch {
+		case !ok:
+			result.Defaults = append(result.Defaults, setting)
+		case len(source) < len(routePath):
+			result.InheritedFrom[setting] = slices.Clone(source)
+		}
+	}
+	return result, nil
+}
 
+func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
 	revision, err := nps.configStore.Get(ctx, orgID)
 	if err != nil {
 		return err
 	}
+	if err := nps.validatePolicyTree(revision, &tree); err != nil {
+		return err
+	}
+
+	revision.cfg.AlertmanagerConfig.Config.Route = &tree
+
+	return nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := nps.configStore.Save(ctx, revision, orgID); err != nil {
+			return err
+		}
+		return nps.provenanceStore.SetProvenance(ctx, &tree, orgID, p)
+	})
+}
+
+// validatePolicyTree checks that the tree is valid and only uses the receivers and mute timings of the revision.
+func (nps *NotificationPolicyService) validatePolicyTree(revision *cfgRevision, tree *definitions.Route) error {
+	err := tree.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
 
 	receivers, err := nps.receiversToMap(revision.cfg.AlertmanagerConfig.Receivers)
 	if err != nil {
@@ -83,15 +184,175 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	return nil
+}
 
-	revision.cfg.AlertmanagerConfig.Config.Route = &tree
+// MergePolicyTree adds the policies as nested policies of the anchor policy, so that several sources can each manage
+// their own policies of the tree. The anchor is the only policy of the tree with the anchor object matchers, or the root
+// policy if they are empty, so that it does not depend on the position of the policies that other sources add. The
+// merged policies are identified by their object matchers. A nested policy of the anchor with the same matchers as a
+// merged one is kept if both are equal, and replaced only if overwrite is set. Otherwise, the merge fails with
+// ErrPolicyConflict.
+//
+// Each merged policy has its own provenance, so that no source owns the whole tree: the provenance of the tree does not
+// change, and a merged policy can only be replaced with the provenance it was merged with. It returns the merged tree.
+func (nps *NotificationPolicyService) MergePolicyTree(ctx context.Context, orgID int64, anchorMatchers definitions.ObjectMatchers, policies []*definitions.Route, overwrite bool, p models.Provenance) (definitions.Route, error) {
+	if len(policies) == 0 {
+		return definitions.Route{}, fmt.Errorf("%w: no policy to merge", ErrValidation)
+	}
+	keys := make(map[string]struct{}, len(policies))
+	for _, policy := range policies {
+		if policy == nil || len(policy.ObjectMatchers) == 0 {
+			return definitions.Route{}, fmt.Errorf("%w: the merged policies must have object matchers", ErrValidation)
+		}
+		key := objectMatchersKey(policy.ObjectMatchers)
+		if _, ok := keys[key]; ok {
+			return definitions.Route{}, fmt.Errorf("%w: more than one merged policy has the matchers %s", ErrValidation, key)
+		}
+		keys[key] = struct{}{}
+	}
 
-	return nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+	var tree definitions.Route
+	err := nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+		revision, err := nps.configStore.Get(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		if revision.cfg.AlertmanagerConfig.Config.Route == nil {
+			return fmt.Errorf("no route present in current alertmanager config")
+		}
+		anchor, err := findAnchorPolicy(revision.cfg.AlertmanagerConfig.Config.Route, anchorMatchers)
+		if err != nil {
+			return err
+		}
+		provenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, mergedPolicy{}.ResourceType())
+		if err != nil {
+			return err
+		}
+		anchorKey := ""
+		if len(anchorMatchers) > 0 {
+			anchorKey = objectMatchersKey(anchorMatchers)
+		}
+		merged := make([]models.Provisionable, 0, len(policies))
+		for _, policy := range policies {
+			key := objectMatchersKey(policy.ObjectMatchers)
+			resource := mergedPolicy{anchor: anchorKey, matchers: key}
+			merged = append(merged, resource)
+			idx := slices.IndexFunc(anchor.Routes, func(r *definitions.Route) bool {
+				return objectMatchersKey(r.ObjectMatchers) == key
+			})
+			if idx < 0 {
+				anchor.Routes = append(anchor.Routes, policy)
+				continue
+			}
+			if stored := provenances[resource.ResourceID()]; stored != models.ProvenanceNone && stored != p {
+				return ErrProvenanceMismatch.Errorf("notification policy with matchers %s has provenance '%s', not '%s'", key, stored, p)
+			}
+			equal, err := equalPolicies(anchor.Routes[idx], policy)
+			if err != nil {
+				return err
+			}
+			if !equal && !overwrite {
+				return MakeErrPolicyConflict(key)
+			}
+			anchor.Routes[idx] = policy
+		}
+		if err := nps.validatePolicyTree(revision, revision.cfg.AlertmanagerConfig.Config.Route); err != nil {
+			return err
+		}
 		if err := nps.configStore.Save(ctx, revision, orgID); err != nil {
 			return err
 		}
-		return nps.provenanceStore.SetProvenance(ctx, &tree, orgID, p)
+		if err := nps.provenanceStore.SetProvenances(ctx, orgID, merged, p); err != nil {
+			return err
+		}
+		tree = *revision.cfg.AlertmanagerConfig.Config.Route
+		return nil
 	})
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	return tree, nil
+}
+
+// mergedPolicy is a policy that was merged into the policy tree. It is identified by the object matchers of its anchor
+// policy and its own, which are hashed to fit in the key of its provenance.
+type mergedPolicy struct {
+	anchor   string
+	matchers string
+}
+
+func (p mergedPolicy) ResourceType() string {
+	return "mergedNotificationPolicy"
+}
+
+func (p mergedPolicy) ResourceID() string {
+	sum := sha256.Sum256([]byte(p.anchor + "/" + p.matchers))
+	return hex.EncodeToString(sum[:])
+}
+
+// findAnchorPolicy returns the only policy of the tree with the object matchers, or the root if they are empty.
+func findAnchorPolicy(root *definitions.Route, matchers definitions.ObjectMatchers) (*definitions.Route, error) {
+	if len(matchers) == 0 {
+		return root, nil
+	}
+	key := objectMatchersKey(matchers)
+	var found []*definitions.Route
+	var walk func(route *definitions.Route)
+	walk = func(route *definitions.Route) {
+		for _, child := range route.Routes {
+			if objectMatchersKey(child.ObjectMatchers) == key {
+				found = append(found, child)
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("%w: no notification policy has the matchers %s", ErrNotFound, key)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d notification policies have the matchers %s, the anchor policy must be the only one", ErrValidation, len(found), key)
+	}
+}
+
+// objectMatchersKey returns the object matchers as a string that does not depend on their order.
+func objectMatchersKey(matchers definitions.ObjectMatchers) string {
+	result := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		result = append(result, m.String())
+	}
+	slices.Sort(result)
+	return "{" + strings.Join(result, ", ") + "}"
+}
+
+// equalPolicies returns true if the policies have the same definition. The object matchers are compared in any order,
+// as they are sorted when the policies are stored.
+func equalPolicies(a, b *definitions.Route) (bool, error) {
+	rawA, err := storedPolicy(a)
+	if err != nil {
+		return false, err
+	}
+	rawB, err := storedPolicy(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(rawA, rawB), nil
+}
+
+// storedPolicy returns the policy as it is stored, with sorted object matchers.
+func storedPolicy(policy *definitions.Route) ([]byte, error) {
+	raw, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	var stored definitions.Route
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&stored)
 }
 
 func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
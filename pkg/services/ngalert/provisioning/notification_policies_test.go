@@ -3,8 +3,10 @@ package provisioning
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/timeinterval"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
@@ -215,6 +217,164 @@ func TestNotificationPolicyService(t *testing.T) {
 	})
 }
 
+func TestEffectivePolicy(t *testing.T) {
+	duration := func(d time.Duration) *model.Duration {
+		md := model.Duration(d)
+		return &md
+	}
+	root := &definitions.Route{
+		Receiver:   "root",
+		GroupByStr: []string{"alertname"},
+		GroupWait:  duration(time.Minute),
+		Routes: []*definitions.Route{
+			{Receiver: "first"},
+			{
+				GroupInterval: duration(10 * time.Minute),
+				Routes: []*definitions.Route{
+					{GroupByStr: []string{"..."}, RepeatInterval: duration(time.Hour)},
+				},
+			},
+		},
+	}
+
+	t.Run("root policy", func(t *testing.T) {
+		policy, err := effectivePolicy(root, nil)
+		require.NoError(t, err)
+		require.Equal(t, definitions.EffectivePolicy{
+			Path:           []int{},
+			Receiver:       "root",
+			GroupBy:        []string{"alertname"},
+			GroupWait:      model.Duration(time.Minute),
+			GroupInterval:  model.Duration(5 * time.Minute),
+			RepeatInterval: model.Duration(4 * time.Hour),
+			InheritedFrom:  map[string][]int{},
+			Defaults:       []string{definitions.EffectivePolicyGroupInterval, definitions.EffectivePolicyRepeatInterval},
+		}, policy)
+	})
+
+	t.Run("nested policy", func(t *testing.T) {
+		policy, err := effectivePolicy(root, []int{1, 0})
+		require.NoError(t, err)
+		require.Equal(t, definitions.EffectivePolicy{
+			Path:           []int{1, 0},
+			Receiver:       "root",
+			GroupBy:        []string{"..."},
+			GroupWait:      model.Duration(time.Minute),
+			GroupInterval:  model.Duration(10 * time.Minute),
+			RepeatInterval: model.Duration(time.Hour),
+			InheritedFrom: map[string][]int{
+				definitions.EffectivePolicyReceiver:      {},
+				definitions.EffectivePolicyGroupWait:     {},
+				definitions.EffectivePolicyGroupInterval: {1},
+			},
+		}, policy)
+	})
+
+	t.Run("unknown policy", func(t *testing.T) {
+		_, err := effectivePolicy(root, []int{0, 0})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestMergePolicyTree(t *testing.T) {
+	ctx := context.Background()
+	policy := func(t *testing.T, receiver string, matchers ...string) *definitions.Route {
+		t.Helper()
+		route := &definitions.Route{Receiver: receiver}
+		for i := 0; i < len(matchers); i += 2 {
+			matcher, err := labels.NewMatcher(labels.MatchEqual, matchers[i], matchers[i+1])
+			require.NoError(t, err)
+			route.ObjectMatchers = append(route.ObjectMatchers, matcher)
+		}
+		return route
+	}
+
+	t.Run("merges the policies under the anchor policy", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		_, err := sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "team", "a")}, false, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		// the anchor is found by its matchers, wherever other sources insert their policies.
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "team", "b")}, false, models.ProvenanceFile)
+		require.NoError(t, err)
+		stored, err := sut.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		stored.Routes = append([]*definitions.Route{policy(t, "grafana-default-email", "team", "c")}, stored.Routes...)
+		require.NoError(t, sut.UpdatePolicyTree(ctx, 1, stored, models.ProvenanceNone))
+
+		anchor := policy(t, "", "team", "a").ObjectMatchers
+		merged, err := sut.MergePolicyTree(ctx, 1, anchor, []*definitions.Route{policy(t, "grafana-default-email", "team", "a", "env", "prod")}, false, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Empty(t, merged.Provenance, "the provenance of the tree should not change")
+
+		stored, err = sut.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, stored.Routes, 4)
+		require.Equal(t, `{team="a"}`, objectMatchersKey(stored.Routes[2].ObjectMatchers))
+		require.Len(t, stored.Routes[2].Routes, 1)
+		require.Equal(t, `{env="prod", team="a"}`, objectMatchersKey(stored.Routes[2].Routes[0].ObjectMatchers))
+	})
+
+	t.Run("tracks the provenance of each merged policy", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		_, err := sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "team", "a")}, false, models.ProvenanceFile)
+		require.NoError(t, err)
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "team", "b")}, false, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		changed := policy(t, "grafana-default-email", "team", "a")
+		changed.Continue = true
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{changed}, true, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{changed}, true, models.ProvenanceFile)
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps equal policies and fails on conflicting ones", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		_, err := sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "team", "a", "env", "prod")}, false, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		// the policies are identified and compared by their matchers in any order.
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "env", "prod", "team", "a")}, false, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "team", "a", "env", "prod")}, false, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		changed := policy(t, "grafana-default-email", "team", "a", "env", "prod")
+		changed.Continue = true
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{changed}, false, models.ProvenanceAPI)
+		require.Truef(t, ErrPolicyConflict.Base.Is(err), "expected ErrPolicyConflict but got %s", err)
+
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{changed}, true, models.ProvenanceAPI)
+		require.NoError(t, err)
+		stored, err := sut.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, stored.Routes, 2)
+		require.True(t, stored.Routes[1].Continue)
+	})
+
+	t.Run("fails for invalid policies", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		_, err := sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email")}, false, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "grafana-default-email", "team", "a"), policy(t, "other", "team", "a")}, false, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		_, err = sut.MergePolicyTree(ctx, 1, nil, []*definitions.Route{policy(t, "not-existing", "team", "a")}, false, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("fails for unknown anchor policy", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		_, err := sut.MergePolicyTree(ctx, 1, policy(t, "", "team", "unknown").ObjectMatchers, []*definitions.Route{policy(t, "grafana-default-email", "team", "a")}, false, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
 func createNotificationPolicyServiceSut() *NotificationPolicyService {
 	return &NotificationPolicyService{
 		configStore:     &alertmanagerConfigStoreImpl{store: fakes.NewFakeAlertmanagerConfigStore(defaultAlertmanagerConfigJSON)},
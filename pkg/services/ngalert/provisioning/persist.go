@@ -25,23 +25,65 @@ type ProvisioningStore interface {
 	GetProvenance(ctx context.Context, o models.Provisionable, org int64) (models.Provenance, error)
 	GetProvenances(ctx context.Context, org int64, resourceType string) (map[string]models.Provenance, error)
 	SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error
+	// SetProvenances sets the same provenance to many objects at once.
+	SetProvenances(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance) error
 	DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error
+	// SetProvenanceWithMetadata behaves like SetProvenance but also stores metadata identifying the source of the object.
+	SetProvenanceWithMetadata(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata) error
+	GetProvenanceMetadata(ctx context.Context, o models.Provisionable, org int64) (*models.ProvenanceMetadata, error)
+	GetProvenancesMetadata(ctx context.Context, org int64, resourceType string) (map[string]models.ProvenanceMetadata, error)
 }
 
 // TransactionManager represents the ability to issue and close transactions through contexts.
 type TransactionManager interface {
 	InTransaction(ctx context.Context, work func(ctx context.Context) error) error
+	// InSavepoint runs work in a nested savepoint of the transaction in the context. If work fails, only its changes
+	// are rolled back and the outer transaction can still be committed.
+	InSavepoint(ctx context.Context, work func(ctx context.Context) error) error
+	// InSnapshot runs work in a transaction whose reads all see the same state of the store, so that the results of
+	// several reads are consistent with each other even if the store is changed concurrently. If the context has a
+	// transaction, work runs in it.
+	InSnapshot(ctx context.Context, work func(ctx context.Context) error) error
+	// AfterCommit registers fn to be called once the transaction in the context is committed. Use it for side effects,
+	// such as cache invalidation or notifications, that must not happen if the transaction is rolled back.
+	AfterCommit(ctx context.Context, fn func())
 }
 
 // RuleStore represents the ability to persist and query alert rules.
 type RuleStore interface {
 	GetAlertRuleByUID(ctx context.Context, query *models.GetAlertRuleByUIDQuery) (*models.AlertRule, error)
 	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
+	SearchAlertRules(ctx context.Context, query *models.SearchAlertRulesQuery) (*models.SearchAlertRulesResult, error)
 	GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error)
 	InsertAlertRules(ctx context.Context, rule []models.AlertRule) ([]models.AlertRuleKeyWithId, error)
 	UpdateAlertRules(ctx context.Context, rule []models.UpdateRule) error
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
+	GetAlertRulesGroupsByRuleUIDs(ctx context.Context, query *models.GetAlertRulesGroupsByRuleUIDsQuery) (map[models.AlertRuleGroupKey]models.RulesGroup, error)
+	GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) ([]*models.AlertRuleVersion, error)
+	CountByProvenances(ctx context.Context, orgID int64, provenances ...models.Provenance) (int64, error)
+	GetFolderAnnotations(ctx context.Context, orgID int64, folderUID string) (map[string]string, error)
+	SetFolderAnnotations(ctx context.Context, orgID int64, folderUID string, annotations map[string]string) error
+	GetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string) (bool, error)
+	SetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string, paused bool) error
+	GetArchivedRuleGroups(ctx context.Context, orgID int64) ([]models.AlertRuleGroupKey, error)
+	SetRuleGroupArchived(ctx context.Context, key models.AlertRuleGroupKey, archived bool) error
+	GetRuleGroupsManagedBy(ctx context.Context, orgID int64) (map[models.AlertRuleGroupKey]models.ManagedBy, error)
+	SetRuleGroupManagedBy(ctx context.Context, key models.AlertRuleGroupKey, managedBy *models.ManagedBy) error
+	LockRuleGroups(ctx context.Context, keys ...models.AlertRuleGroupKey) error
+	ListAlertRuleTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, error)
+	GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error)
+	SaveAlertRuleTemplate(ctx context.Context, template models.AlertRuleTemplate) error
+	DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error
+}
+
+// AlertRuleTrashStore is a store of deleted alert rules.
+type AlertRuleTrashStore interface {
+	InsertDeletedAlertRules(ctx context.Context, rules ...*models.DeletedAlertRule) error
+	GetDeletedAlertRules(ctx context.Context, orgID int64) ([]*models.DeletedAlertRule, error)
+	GetDeletedAlertRule(ctx context.Context, orgID int64, ruleUID string) (*models.DeletedAlertRule, error)
+	DeleteDeletedAlertRules(ctx context.Context, orgID int64, ruleUIDs ...string) error
+	DeleteDeletedAlertRulesBefore(ctx context.Context, deletedBefore int64) (int64, error)
 }
 
 // QuotaChecker represents the ability to evaluate whether quotas are met.
@@ -49,6 +91,7 @@ type RuleStore interface {
 //go:generate mockery --name QuotaChecker --structname MockQuotaChecker --inpackage --filename quota_checker_mock.go --with-expecter
 type QuotaChecker interface {
 	CheckQuotaReached(ctx context.Context, target quota.TargetSrv, scopeParams *quota.ScopeParameters) (bool, error)
+	GetQuotasByScope(ctx context.Context, scope quota.Scope, id int64) ([]quota.QuotaDTO, error)
 }
 
 // PersistConfig validates to config before eventually persisting it if no error occurs
@@ -0,0 +1,347 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// PrometheusExportErrorAnnotation is the annotation of the exported rules whose condition cannot be converted to a
+// Prometheus expression. It describes why, and the expression of such rules is empty, so that the rule file cannot be
+// loaded before they are converted by hand.
+const PrometheusExportErrorAnnotation = "grafana_export_error"
+
+// prometheusRuleFile is a Prometheus rule file, as loaded by Prometheus, Mimir and Loki rulers.
+type prometheusRuleFile struct {
+	Groups []prometheusRuleGroup `yaml:"groups"`
+}
+
+type prometheusRuleGroup struct {
+	Name     string             `yaml:"name"`
+	Interval prommodel.Duration `yaml:"interval,omitempty"`
+	Rules    []prometheusRule   `yaml:"rules"`
+}
+
+type prometheusRule struct {
+	// Record is the name of the series of recording rules, which are not exported but can be in imported files.
+	Record      string             `yaml:"record,omitempty"`
+	Alert       string             `yaml:"alert,omitempty"`
+	Expr        string             `yaml:"expr"`
+	For         prommodel.Duration `yaml:"for,omitempty"`
+	Labels      map[string]string  `yaml:"labels,omitempty"`
+	Annotations map[string]string  `yaml:"annotations,omitempty"`
+}
+
+// ExportRuleGroup renders the rule group as a Prometheus rule file, so that it can be migrated to a Mimir or Loki
+// ruler. See toPrometheusExpr for the conditions that can be converted.
+func (service *AlertRuleService) ExportRuleGroup(ctx context.Context, orgID int64, namespaceUID, group string) ([]byte, error) {
+	g, err := service.GetRuleGroup(ctx, orgID, namespaceUID, group)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*models.AlertRule, 0, len(g.Rules))
+	for i := range g.Rules {
+		rules = append(rules, &g.Rules[i])
+	}
+	return yaml.Marshal(prometheusRuleFile{Groups: []prometheusRuleGroup{toPrometheusRuleGroup(g.Title, g.Interval, rules)}})
+}
+
+// ExportAllRules renders the rules of the organization as Prometheus rule files, like ExportRuleGroup. There is one
+// file per folder, keyed by the UID of the folder, as rulers load the rule groups of a namespace from one file.
+func (service *AlertRuleService) ExportAllRules(ctx context.Context, orgID int64) (map[string][]byte, error) {
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*prometheusRuleFile)
+	for key, group := range models.GroupByAlertRuleGroupKey(rules) {
+		file, ok := files[key.NamespaceUID]
+		if !ok {
+			file = &prometheusRuleFile{}
+			files[key.NamespaceUID] = file
+		}
+		file.Groups = append(file.Groups, toPrometheusRuleGroup(key.RuleGroup, group[0].IntervalSeconds, group))
+	}
+	result := make(map[string][]byte, len(files))
+	for folderUID, file := range files {
+		slices.SortFunc(file.Groups, func(a, b prometheusRuleGroup) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		b, err := yaml.Marshal(file)
+		if err != nil {
+			return nil, err
+		}
+		result[folderUID] = b
+	}
+	return result, nil
+}
+
+func toPrometheusRuleGroup(name string, intervalSeconds int64, rules models.RulesGroup) prometheusRuleGroup {
+	rules.SortByGroupIndex()
+	group := prometheusRuleGroup{
+		Name:     name,
+		Interval: prommodel.Duration(time.Duration(intervalSeconds) * time.Second),
+		Rules:    make([]prometheusRule, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		promRule := prometheusRule{
+			Alert:       rule.Title,
+			For:         prommodel.Duration(rule.For),
+			Labels:      rule.Labels,
+			Annotations: rule.Annotations,
+		}
+		e, err := toPrometheusExpr(*rule)
+		if err != nil {
+			promRule.Annotations = make(map[string]string, len(rule.Annotations)+1)
+			for k, v := range rule.Annotations {
+				promRule.Annotations[k] = v
+			}
+			promRule.Annotations[PrometheusExportErrorAnnotation] = err.Error()
+		}
+		promRule.Expr = e
+		group.Rules = append(group.Rules, promRule)
+	}
+	return group
+}
+
+// mathVariable matches the references to other queries and expressions in math expressions, e.g. $A or ${A}.
+var mathVariable = regexp.MustCompile(`\$\{([^}]+)\}|\$([A-Za-z0-9_]+)`)
+
+// toPrometheusExpr reconstructs the expression of the condition of the rule. The queries must be Prometheus or Loki
+// queries, and the expressions must be the ones that have an equivalent in PromQL: reductions to the last value or
+// over the range of a query, thresholds, classic conditions with a single condition, and math expressions that use
+// only arithmetic and comparisons.
+func toPrometheusExpr(rule models.AlertRule) (string, error) {
+	c := prometheusExprConverter{
+		queries: make(map[string]models.AlertQuery, len(rule.Data)),
+	}
+	for _, q := range rule.Data {
+		c.queries[q.RefID] = q
+	}
+	e, err := c.convert(rule.Condition, len(rule.Data))
+	if err != nil {
+		return "", err
+	}
+	// The alerts of math expressions fire when they are not zero, and the alerts of PromQL expressions when they
+	// return a value, which comparisons do only when they are true.
+	if q := c.queries[rule.Condition]; c.commandType(q) == expr.TypeMath && !strings.ContainsAny(e, "<>=") {
+		e = fmt.Sprintf("(%s) != 0", e)
+	}
+	return e, nil
+}
+
+type prometheusExprConverter struct {
+	queries map[string]models.AlertQuery
+	// datasourceType is the type of the data source of the queries, which must all be of the same type.
+	datasourceType string
+}
+
+func (c *prometheusExprConverter) commandType(q models.AlertQuery) expr.CommandType {
+	var model map[string]any
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return expr.TypeUnknown
+	}
+	cmdType, err := expr.GetExpressionCommandType(model)
+	if err != nil {
+		return expr.TypeUnknown
+	}
+	return cmdType
+}
+
+// convert returns the PromQL expression of the query or expression. Depth is the number of references that can still
+// be followed, so that a cycle of references is not followed forever.
+func (c *prometheusExprConverter) convert(refID string, depth int) (string, error) {
+	if depth <= 0 {
+		return "", fmt.Errorf("%s is part of a cycle of references", refID)
+	}
+	q, ok := c.queries[refID]
+	if !ok {
+		return "", fmt.Errorf("%s is not defined", refID)
+	}
+	if isExpression, _ := q.IsExpression(); !isExpression {
+		return c.convertQuery(q)
+	}
+
+	switch cmdType := c.commandType(q); cmdType {
+	case expr.TypeReduce:
+		var model struct {
+			Expression string `json:"expression"`
+			Reducer    string `json:"reducer"`
+			Settings   *struct {
+				Mode string `json:"mode"`
+			} `json:"settings"`
+		}
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return "", fmt.Errorf("failed to parse reduce expression %s: %w", refID, err)
+		}
+		if model.Settings != nil && model.Settings.Mode != "" {
+			return "", fmt.Errorf("reduce expression %s has mode %s, which has no equivalent in PromQL", refID, model.Settings.Mode)
+		}
+		return c.reduce(refID, model.Expression, model.Reducer, depth)
+	case expr.TypeThreshold:
+		var model struct {
+			Expression string `json:"expression"`
+			Conditions []struct {
+				Evaluator       expr.ConditionEvalJSON  `json:"evaluator"`
+				UnloadEvaluator *expr.ConditionEvalJSON `json:"unloadEvaluator"`
+			} `json:"conditions"`
+		}
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return "", fmt.Errorf("failed to parse threshold expression %s: %w", refID, err)
+		}
+		if len(model.Conditions) != 1 {
+			return "", fmt.Errorf("threshold expression %s must have one condition", refID)
+		}
+		if model.Conditions[0].UnloadEvaluator != nil {
+			return "", fmt.Errorf("threshold expression %s has a recovery threshold, which has no equivalent in PromQL", refID)
+		}
+		input, err := c.convert(model.Expression, depth-1)
+		if err != nil {
+			return "", err
+		}
+		return compareToThreshold(refID, input, model.Conditions[0].Evaluator.Type, model.Conditions[0].Evaluator.Params)
+	case expr.TypeClassicConditions:
+		var model struct {
+			Conditions []classic.ConditionJSON `json:"conditions"`
+		}
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return "", fmt.Errorf("failed to parse classic condition %s: %w", refID, err)
+		}
+		if len(model.Conditions) != 1 || len(model.Conditions[0].Query.Params) == 0 {
+			return "", fmt.Errorf("classic condition %s must have one condition", refID)
+		}
+		cond := model.Conditions[0]
+		input, err := c.reduce(refID, cond.Query.Params[0], cond.Reducer.Type, depth)
+		if err != nil {
+			return "", err
+		}
+		return compareToThreshold(refID, input, expr.ThresholdType(cond.Evaluator.Type), cond.Evaluator.Params)
+	case expr.TypeMath:
+		var model struct {
+			Expression string `json:"expression"`
+		}
+		if err := json.Unmarshal(q.Model, &model); err != nil {
+			return "", fmt.Errorf("failed to parse math expression %s: %w", refID, err)
+		}
+		if strings.Contains(model.Expression, "&&") || strings.Contains(model.Expression, "||") || strings.Contains(strings.ReplaceAll(model.Expression, "!=", ""), "!") {
+			return "", fmt.Errorf("math expression %s has logical operators, which have no equivalent in PromQL", refID)
+		}
+		var errs []error
+		e := mathVariable.ReplaceAllStringFunc(model.Expression, func(ref string) string {
+			name := strings.Trim(ref, "${}")
+			input, err := c.convert(name, depth-1)
+			if err != nil {
+				errs = append(errs, err)
+				return ref
+			}
+			return "(" + input + ")"
+		})
+		if len(errs) > 0 {
+			return "", errors.Join(errs...)
+		}
+		return e, nil
+	default:
+		return "", fmt.Errorf("expression %s has no equivalent in PromQL", refID)
+	}
+}
+
+// convertQuery returns the expression of a Prometheus or Loki query.
+func (c *prometheusExprConverter) convertQuery(q models.AlertQuery) (string, error) {
+	var model struct {
+		Expr       *string `json:"expr"`
+		Datasource struct {
+			Type string `json:"type"`
+		} `json:"datasource"`
+	}
+	if err := json.Unmarshal(q.Model, &model); err != nil {
+		return "", fmt.Errorf("failed to parse query %s: %w", q.RefID, err)
+	}
+	if model.Expr == nil {
+		return "", fmt.Errorf("query %s is not a Prometheus or Loki query", q.RefID)
+	}
+	switch model.Datasource.Type {
+	case "":
+	case "prometheus", "loki":
+		if c.datasourceType != "" && c.datasourceType != model.Datasource.Type {
+			return "", fmt.Errorf("query %s is a %s query, and the rule has %s queries", q.RefID, model.Datasource.Type, c.datasourceType)
+		}
+		c.datasourceType = model.Datasource.Type
+	default:
+		return "", fmt.Errorf("query %s is a %s query, not a Prometheus or Loki query", q.RefID, model.Datasource.Type)
+	}
+	return *model.Expr, nil
+}
+
+// prometheusOverTimeFunctions are the PromQL functions that reduce the values of a series over a range like the
+// reducers of Grafana.
+var prometheusOverTimeFunctions = map[string]string{
+	"avg":   "avg_over_time",
+	"mean":  "avg_over_time",
+	"min":   "min_over_time",
+	"max":   "max_over_time",
+	"sum":   "sum_over_time",
+	"count": "count_over_time",
+}
+
+// reduce returns the expression of the reduction of the input. The last value is the value of an instant query, and
+// the other reducers are converted to functions over the range of the input, which must be a query.
+func (c *prometheusExprConverter) reduce(refID string, inputRefID string, reducer string, depth int) (string, error) {
+	input, err := c.convert(inputRefID, depth-1)
+	if err != nil {
+		return "", err
+	}
+	if reducer == "last" {
+		return input, nil
+	}
+	fn, ok := prometheusOverTimeFunctions[reducer]
+	if !ok {
+		return "", fmt.Errorf("reducer %s of %s has no equivalent in PromQL", reducer, refID)
+	}
+	q := c.queries[inputRefID]
+	if isExpression, _ := q.IsExpression(); isExpression || q.RelativeTimeRange.To != 0 || q.RelativeTimeRange.From <= 0 {
+		return "", fmt.Errorf("reducer %s of %s must reduce a query over a range that ends now", reducer, refID)
+	}
+	return fmt.Sprintf("%s((%s)[%s:])", fn, input, prommodel.Duration(q.RelativeTimeRange.From)), nil
+}
+
+// compareToThreshold returns the expression of the comparison of the input to a threshold, which returns the values
+// that meet the threshold like the threshold and classic condition expressions.
+func compareToThreshold(refID string, input string, thresholdType expr.ThresholdType, params []float64) (string, error) {
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	switch thresholdType {
+	case expr.ThresholdIsAbove, expr.ThresholdIsBelow:
+		if len(params) < 1 {
+			return "", fmt.Errorf("threshold of %s has no value", refID)
+		}
+		op := ">"
+		if thresholdType == expr.ThresholdIsBelow {
+			op = "<"
+		}
+		return fmt.Sprintf("(%s) %s %s", input, op, format(params[0])), nil
+	case expr.ThresholdIsWithinRange, expr.ThresholdIsOutsideRange:
+		if len(params) < 2 {
+			return "", fmt.Errorf("threshold of %s must have two values", refID)
+		}
+		if thresholdType == expr.ThresholdIsWithinRange {
+			return fmt.Sprintf("(%s) > %s < %s", input, format(params[0]), format(params[1])), nil
+		}
+		return fmt.Sprintf("(%s) < %s or (%s) > %s", input, format(params[0]), input, format(params[1])), nil
+	default:
+		return "", fmt.Errorf("threshold %s of %s has no equivalent in PromQL", thresholdType, refID)
+	}
+}
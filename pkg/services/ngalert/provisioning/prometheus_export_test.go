@@ -0,0 +1,221 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	prommodel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestToPrometheusExpr(t *testing.T) {
+	query := func(refID, model string) models.AlertQuery {
+		return models.AlertQuery{
+			RefID:             refID,
+			DatasourceUID:     "prometheus-uid",
+			Model:             json.RawMessage(model),
+			RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(5 * time.Minute)},
+		}
+	}
+	expression := func(refID, model string) models.AlertQuery {
+		return models.AlertQuery{RefID: refID, DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(model)}
+	}
+	promQuery := query("A", `{"expr": "rate(errors_total[5m])", "datasource": {"type": "prometheus"}}`)
+
+	testCases := []struct {
+		name      string
+		condition string
+		data      []models.AlertQuery
+		expected  string
+		err       string
+	}{
+		{
+			name:      "threshold over the last value of a query",
+			condition: "C",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "threshold", "expression": "B", "conditions": [{"evaluator": {"type": "gt", "params": [0.5]}}]}`),
+			},
+			expected: "(rate(errors_total[5m])) > 0.5",
+		},
+		{
+			name:      "threshold over the mean of a query",
+			condition: "C",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "mean"}`),
+				expression("C", `{"type": "threshold", "expression": "B", "conditions": [{"evaluator": {"type": "outside_range", "params": [1, 10]}}]}`),
+			},
+			expected: "(avg_over_time((rate(errors_total[5m]))[5m:])) < 1 or (avg_over_time((rate(errors_total[5m]))[5m:])) > 10",
+		},
+		{
+			name:      "classic condition",
+			condition: "B",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "classic_conditions", "conditions": [{"evaluator": {"type": "lt", "params": [3]}, "query": {"params": ["A"]}, "reducer": {"type": "max"}}]}`),
+			},
+			expected: "(max_over_time((rate(errors_total[5m]))[5m:])) < 3",
+		},
+		{
+			name:      "math expression",
+			condition: "C",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "math", "expression": "${B} * 100 >= 5"}`),
+			},
+			expected: "(rate(errors_total[5m])) * 100 >= 5",
+		},
+		{
+			name:      "math expression without comparison",
+			condition: "B",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "math", "expression": "$A - 1"}`),
+			},
+			expected: "((rate(errors_total[5m])) - 1) != 0",
+		},
+		{
+			name:      "math expression with logical operators",
+			condition: "B",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "math", "expression": "$A > 1 && $A < 5"}`),
+			},
+			err: "logical operators",
+		},
+		{
+			name:      "query of another data source",
+			condition: "B",
+			data: []models.AlertQuery{
+				query("A", `{"rawSql": "SELECT 1", "datasource": {"type": "mysql"}}`),
+				expression("B", `{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [0]}}]}`),
+			},
+			err: "not a Prometheus or Loki query",
+		},
+		{
+			name:      "threshold with a recovery threshold",
+			condition: "B",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [5]}, "unloadEvaluator": {"type": "lt", "params": [3]}}]}`),
+			},
+			err: "recovery threshold",
+		},
+		{
+			name:      "SQL expression",
+			condition: "B",
+			data: []models.AlertQuery{
+				promQuery,
+				expression("B", `{"type": "sql", "expression": "SELECT * FROM A"}`),
+			},
+			err: "no equivalent in PromQL",
+		},
+		{
+			name:      "cycle of references",
+			condition: "B",
+			data: []models.AlertQuery{
+				expression("A", `{"type": "math", "expression": "$B"}`),
+				expression("B", `{"type": "math", "expression": "$A"}`),
+			},
+			err: "cycle of references",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e, err := toPrometheusExpr(models.AlertRule{Condition: tc.condition, Data: tc.data})
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, e)
+		})
+	}
+}
+
+func TestExportRuleGroupAsPrometheusRules(t *testing.T) {
+	var orgID int64 = 1
+	ruleService := createAlertRuleService(t)
+	convertible := createTestRule("High error rate", "group", orgID, "folder")
+	convertible.UID = "convertible"
+	convertible.Labels = map[string]string{"severity": "critical"}
+	convertible.Annotations = map[string]string{"summary": "Too many errors"}
+	convertible.Data = []models.AlertQuery{
+		{
+			RefID:             "A",
+			DatasourceUID:     "prometheus-uid",
+			Model:             json.RawMessage(`{"expr": "rate(errors_total[5m])"}`),
+			RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(5 * time.Minute)},
+		},
+		{
+			RefID:         "B",
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(`{"type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [1]}}]}`),
+		},
+	}
+	convertible.Condition = "B"
+	unconvertible := createTestRule("No query", "group", orgID, "folder")
+	unconvertible.UID = "unconvertible"
+	createRuleGroup(t, ruleService, orgID, models.AlertRuleGroup{
+		Title:     "group",
+		FolderUID: "folder",
+		Interval:  120,
+		Rules:     []models.AlertRule{convertible, unconvertible},
+	}, models.ProvenanceNone)
+
+	expected := prometheusRuleFile{
+		Groups: []prometheusRuleGroup{
+			{
+				Name:     "group",
+				Interval: prommodel.Duration(2 * time.Minute),
+				Rules: []prometheusRule{
+					{
+						Alert:       "High error rate",
+						Expr:        "(rate(errors_total[5m])) > 1",
+						For:         prommodel.Duration(time.Minute),
+						Labels:      map[string]string{"severity": "critical"},
+						Annotations: map[string]string{"summary": "Too many errors"},
+					},
+					{
+						Alert:       "No query",
+						For:         prommodel.Duration(time.Minute),
+						Annotations: map[string]string{PrometheusExportErrorAnnotation: "expression A has no equivalent in PromQL"},
+					},
+				},
+			},
+		},
+	}
+	assertFile := func(t *testing.T, b []byte) {
+		t.Helper()
+		var file prometheusRuleFile
+		require.NoError(t, yaml.Unmarshal(b, &file))
+		require.Equal(t, expected, file)
+	}
+
+	t.Run("should export the rule group", func(t *testing.T) {
+		b, err := ruleService.ExportRuleGroup(context.Background(), orgID, "folder", "group")
+		require.NoError(t, err)
+		assertFile(t, b)
+	})
+
+	t.Run("should export the rules of each folder", func(t *testing.T) {
+		files, err := ruleService.ExportAllRules(context.Background(), orgID)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assertFile(t, files["folder"])
+	})
+
+	t.Run("should fail when the rule group does not exist", func(t *testing.T) {
+		_, err := ruleService.ExportRuleGroup(context.Background(), orgID, "folder", "missing")
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+}
@@ -0,0 +1,171 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// prometheusQueryTimeRange is the time range of the queries of imported rules. The queries are instant queries, so
+// the range only bounds the lookback of the selectors, like the lookback delta of Prometheus.
+const prometheusQueryTimeRange = 10 * time.Minute
+
+// ImportPrometheusRuleGroup converts a Prometheus rule group, e.g. one of the groups of a rule file of a Mimir or Loki
+// ruler, and replaces the rule group of the folder with the converted rules. The expressions of the rules are queries
+// of the data source, and the alerting rules fire for every series that the queries return, like in Prometheus. The
+// recording rules write the series that their queries return to the same data source. The rules of the existing group
+// are matched by title, so that importing the same group again updates them rather than replacing them with new rules.
+// If the user is set, the changes of the group are authorized like the other changes of rule groups.
+func (service *AlertRuleService) ImportPrometheusRuleGroup(ctx context.Context, user identity.Requester, orgID int64, folderUID string, rulesYAML []byte, datasourceUID string, provenance models.Provenance) error {
+	if datasourceUID == "" {
+		return fmt.Errorf("%w: data source UID is empty", models.ErrAlertRuleFailedValidation)
+	}
+	var promGroup prometheusRuleGroup
+	if err := yaml.Unmarshal(rulesYAML, &promGroup); err != nil {
+		return fmt.Errorf("%w: invalid Prometheus rule group: %s", models.ErrAlertRuleFailedValidation, err)
+	}
+	if promGroup.Name == "" {
+		return fmt.Errorf("%w: Prometheus rule group has no name", models.ErrAlertRuleFailedValidation)
+	}
+
+	group := models.AlertRuleGroup{
+		Title:     promGroup.Name,
+		FolderUID: folderUID,
+		Interval:  int64(time.Duration(promGroup.Interval).Seconds()),
+		Rules:     make([]models.AlertRule, 0, len(promGroup.Rules)),
+	}
+	if group.Interval == 0 {
+		group.Interval = service.defaultIntervalSeconds
+	}
+	existing, err := service.GetRuleGroup(ctx, orgID, folderUID, promGroup.Name)
+	if err != nil && !errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+		return err
+	}
+	// The settings of the group that Prometheus rule groups do not have are kept.
+	group.DataAvailabilityPeriod = existing.DataAvailabilityPeriod
+	group.DataAvailabilityDelay = existing.DataAvailabilityDelay
+	group.ShardAffinity = existing.ShardAffinity
+	group.IncidentHooks = existing.IncidentHooks
+	uids := make(map[string]string, len(existing.Rules))
+	for _, rule := range existing.Rules {
+		uids[rule.Title] = rule.UID
+	}
+
+	for i, promRule := range promGroup.Rules {
+		var rule models.AlertRule
+		var err error
+		if promRule.Record != "" {
+			rule, err = fromPrometheusRecordingRule(promRule, datasourceUID)
+		} else {
+			rule, err = fromPrometheusRule(promRule, datasourceUID)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: rule %d of Prometheus rule group %s: %s", models.ErrAlertRuleFailedValidation, i, promGroup.Name, err)
+		}
+		rule.UID = uids[rule.Title]
+		// Prometheus allows several rules with the same name, only the first one keeps the UID.
+		delete(uids, rule.Title)
+		group.Rules = append(group.Rules, rule)
+	}
+	var userID int64
+	if user != nil {
+		userID, _ = identity.UserIdentifier(user.GetNamespacedID())
+	}
+	_, err = service.replaceRuleGroup(ctx, orgID, group, user, userID, provenance)
+	return err
+}
+
+// fromPrometheusRule converts a Prometheus alerting rule to an alert rule that queries the data source. The condition
+// of the rule is an expression that is true for every value of the query, as Prometheus alerts on every series that
+// the expression returns.
+func fromPrometheusRule(promRule prometheusRule, datasourceUID string) (models.AlertRule, error) {
+	if promRule.Alert == "" {
+		return models.AlertRule{}, errors.New("rule has no name")
+	}
+	if promRule.Expr == "" {
+		return models.AlertRule{}, fmt.Errorf("rule %s has no expression", promRule.Alert)
+	}
+	query, err := prometheusQuery(promRule.Expr, datasourceUID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	condition, err := json.Marshal(map[string]any{
+		"refId":      "B",
+		"type":       expr.TypeMath.String(),
+		"expression": "is_number($A) || is_nan($A) || is_inf($A)",
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return models.AlertRule{
+		Title:     promRule.Alert,
+		Condition: "B",
+		Data: []models.AlertQuery{
+			query,
+			{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         condition,
+			},
+		},
+		For:         time.Duration(promRule.For),
+		Labels:      promRule.Labels,
+		Annotations: promRule.Annotations,
+		// Prometheus does not alert when the expression returns nothing, and alerts on the failures of the rule
+		// evaluations through its own metrics.
+		NoDataState:  models.OK,
+		ExecErrState: models.ErrorErrState,
+	}, nil
+}
+
+// fromPrometheusRecordingRule converts a Prometheus recording rule to a recording rule that queries the data source and
+// writes the series of the query back to it under the name of the record.
+func fromPrometheusRecordingRule(promRule prometheusRule, datasourceUID string) (models.AlertRule, error) {
+	if promRule.Expr == "" {
+		return models.AlertRule{}, fmt.Errorf("rule %s has no expression", promRule.Record)
+	}
+	if promRule.For != 0 {
+		return models.AlertRule{}, fmt.Errorf("recording rule %s has a pending period", promRule.Record)
+	}
+	query, err := prometheusQuery(promRule.Expr, datasourceUID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	return models.AlertRule{
+		Title:  promRule.Record,
+		Data:   []models.AlertQuery{query},
+		Labels: promRule.Labels,
+		Record: models.Record{
+			Metric:              promRule.Record,
+			From:                query.RefID,
+			TargetDatasourceUID: datasourceUID,
+		},
+	}, nil
+}
+
+// prometheusQuery returns the instant query of the expression, with the relative time range of imported rules.
+func prometheusQuery(expression string, datasourceUID string) (models.AlertQuery, error) {
+	model, err := json.Marshal(map[string]any{
+		"refId":   "A",
+		"expr":    expression,
+		"instant": true,
+		"range":   false,
+	})
+	if err != nil {
+		return models.AlertQuery{}, err
+	}
+	return models.AlertQuery{
+		RefID:             "A",
+		DatasourceUID:     datasourceUID,
+		Model:             model,
+		RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(prometheusQueryTimeRange)},
+	}, nil
+}
@@ -0,0 +1,159 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestImportPrometheusRuleGroup(t *testing.T) {
+	var orgID int64 = 1
+	ruleService := createAlertRuleService(t)
+	rulesYAML := []byte(`
+name: group
+interval: 2m
+rules:
+  - alert: High error rate
+    expr: rate(errors_total[5m]) > 1
+    for: 5m
+    labels:
+      severity: critical
+    annotations:
+      summary: Too many errors
+  - alert: Instance down
+    expr: up == 0
+`)
+
+	t.Run("should import the rule group", func(t *testing.T) {
+		err := ruleService.ImportPrometheusRuleGroup(context.Background(), nil, orgID, "folder", rulesYAML, "prometheus-uid", models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		group, err := ruleService.GetRuleGroup(context.Background(), orgID, "folder", "group")
+		require.NoError(t, err)
+		require.Equal(t, int64(120), group.Interval)
+		require.Len(t, group.Rules, 2)
+
+		rule := group.Rules[0]
+		require.Equal(t, "High error rate", rule.Title)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, map[string]string{"severity": "critical"}, rule.Labels)
+		require.Equal(t, map[string]string{"summary": "Too many errors"}, rule.Annotations)
+		require.Equal(t, models.OK, rule.NoDataState)
+		require.Equal(t, models.ErrorErrState, rule.ExecErrState)
+		require.Equal(t, "B", rule.Condition)
+		require.Len(t, rule.Data, 2)
+		require.Equal(t, "prometheus-uid", rule.Data[0].DatasourceUID)
+		require.Equal(t, models.RelativeTimeRange{From: models.Duration(10 * time.Minute)}, rule.Data[0].RelativeTimeRange)
+		require.JSONEq(t, `{"refId": "A", "expr": "rate(errors_total[5m]) > 1", "instant": true, "range": false, "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[0].Model))
+		require.Equal(t, expr.DatasourceUID, rule.Data[1].DatasourceUID)
+
+		rule = group.Rules[1]
+		require.Equal(t, "Instance down", rule.Title)
+		require.Zero(t, rule.For)
+	})
+
+	t.Run("should keep the UIDs of the rules when importing the group again", func(t *testing.T) {
+		before, err := ruleService.GetRuleGroup(context.Background(), orgID, "folder", "group")
+		require.NoError(t, err)
+
+		err = ruleService.ImportPrometheusRuleGroup(context.Background(), nil, orgID, "folder", rulesYAML, "prometheus-uid", models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		after, err := ruleService.GetRuleGroup(context.Background(), orgID, "folder", "group")
+		require.NoError(t, err)
+		require.Len(t, after.Rules, 2)
+		require.Equal(t, before.Rules[0].UID, after.Rules[0].UID)
+		require.Equal(t, before.Rules[1].UID, after.Rules[1].UID)
+	})
+
+	t.Run("should use the default interval when the group has none", func(t *testing.T) {
+		err := ruleService.ImportPrometheusRuleGroup(context.Background(), nil, orgID, "folder", []byte(`
+name: no-interval
+rules:
+  - alert: Instance unreachable
+    expr: up == 0
+`), "prometheus-uid", models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		group, err := ruleService.GetRuleGroup(context.Background(), orgID, "folder", "no-interval")
+		require.NoError(t, err)
+		require.Equal(t, ruleService.defaultIntervalSeconds, group.Interval)
+	})
+
+	t.Run("should convert recording rules", func(t *testing.T) {
+		err := ruleService.ImportPrometheusRuleGroup(context.Background(), nil, orgID, "folder", []byte(`
+name: recording
+rules:
+  - record: job:errors:rate5m
+    expr: sum by (job) (rate(errors_total[5m]))
+    labels:
+      team: platform
+  - alert: High job error rate
+    expr: job:errors:rate5m > 1
+`), "prometheus-uid", models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		group, err := ruleService.GetRuleGroup(context.Background(), orgID, "folder", "recording")
+		require.NoError(t, err)
+		require.Len(t, group.Rules, 2)
+		rule := group.Rules[0]
+		require.Equal(t, "job:errors:rate5m", rule.Title)
+		require.Equal(t, models.Record{Metric: "job:errors:rate5m", From: "A", TargetDatasourceUID: "prometheus-uid"}, rule.Record)
+		require.Equal(t, map[string]string{"team": "platform"}, rule.Labels)
+		require.Len(t, rule.Data, 1)
+		require.JSONEq(t, `{"refId": "A", "expr": "sum by (job) (rate(errors_total[5m]))", "instant": true, "range": false, "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[0].Model))
+		require.False(t, group.Rules[1].IsRecordingRule())
+	})
+
+	t.Run("should reject recording rules with a pending period", func(t *testing.T) {
+		err := ruleService.ImportPrometheusRuleGroup(context.Background(), nil, orgID, "folder", []byte(`
+name: recording-for
+rules:
+  - record: job:errors:rate5m
+    expr: sum by (job) (rate(errors_total[5m]))
+    for: 5m
+`), "prometheus-uid", models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should authorize the changes by the user", func(t *testing.T) {
+		authz := &fakeRuleAccessControl{changeErr: errors.New("denied")}
+		ruleService := createAlertRuleService(t)
+		ruleService.authz = authz
+
+		err := ruleService.ImportPrometheusRuleGroup(context.Background(), &user.SignedInUser{OrgID: orgID}, orgID, "folder", rulesYAML, "prometheus-uid", models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.changeErr)
+		require.Len(t, authz.changes, 1)
+		require.Len(t, authz.changes[0].New, 2)
+
+		_, err = ruleService.GetRuleGroup(context.Background(), orgID, "folder", "group")
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should reject rules without expression", func(t *testing.T) {
+		err := ruleService.ImportPrometheusRuleGroup(context.Background(), nil, orgID, "folder", []byte(`
+name: invalid
+rules:
+  - alert: Nothing
+`), "prometheus-uid", models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
+
+func TestFromPrometheusRule(t *testing.T) {
+	rule, err := fromPrometheusRule(prometheusRule{Alert: "Instance down", Expr: "up == 0"}, "prometheus-uid")
+	require.NoError(t, err)
+
+	var condition map[string]any
+	require.NoError(t, json.Unmarshal(rule.Data[1].Model, &condition))
+	require.Equal(t, "math", condition["type"])
+	require.Equal(t, "is_number($A) || is_nan($A) || is_inf($A)", condition["expression"])
+}
@@ -1,6 +1,9 @@
 package provisioning
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -11,3 +14,86 @@ func canUpdateProvenanceInRuleGroup(storedProvenance, provenance models.Provenan
 		storedProvenance == models.ProvenanceNone ||
 		(storedProvenance == models.ProvenanceAPI && provenance == models.ProvenanceNone)
 }
+
+// provenanceNotAllowed returns the error of an action on the alert rule with a provenance that cannot change it, see
+// MakeErrProvenanceNotAllowed.
+func provenanceNotAllowed(ctx context.Context, provenanceStore ProvisioningStore, action string, rule *models.AlertRule, stored, provenance models.Provenance) error {
+	// The metadata only helps to find the owner of the rule, so the error is returned without it if it cannot be read.
+	metadata, _ := provenanceStore.GetProvenanceMetadata(ctx, rule, rule.OrgID)
+	return MakeErrProvenanceNotAllowed(action, rule, stored, provenance, metadata)
+}
+
+// ChangeProvenance hands the alert rule over from a provenance to another, which canUpdateProvenanceInRuleGroup does
+// not allow when the rule is written. E.g. a rule that is removed from the provisioning files can be handed over to
+// the UI so that it can be edited again. It fails with ErrProvenanceMismatch if the rule does not have the provenance
+// it is handed over from, so that it is not taken from a writer that changed it in the meantime. The change is logged
+// for auditing.
+func (service *AlertRuleService) ChangeProvenance(ctx context.Context, userID int64, orgID int64, ruleUID string, from, to models.Provenance) error {
+	if err := validateProvenance(to); err != nil {
+		return err
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		rule, err := service.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: orgID, UID: ruleUID})
+		if err != nil {
+			return err
+		}
+		stored, err := service.provenanceStore.GetProvenance(ctx, rule, orgID)
+		if err != nil {
+			return err
+		}
+		if stored != from {
+			return ErrProvenanceMismatch.Errorf("alert rule '%s' has provenance '%s', not '%s'", ruleUID, stored, from)
+		}
+		if err := service.provenanceStore.SetProvenance(ctx, rule, orgID, to); err != nil {
+			return err
+		}
+		service.log.Info("Changed the provenance of an alert rule", "org", orgID, "rule", ruleUID, "from", from, "to", to, "user", userID)
+		return nil
+	})
+}
+
+// ChangeRuleGroupProvenance hands all the alert rules of the rule group over from a provenance to another, like
+// ChangeProvenance. No rule is changed if one of them does not have the provenance they are handed over from.
+func (service *AlertRuleService) ChangeRuleGroupProvenance(ctx context.Context, userID int64, orgID int64, namespaceUID string, group string, from, to models.Provenance) error {
+	if err := validateProvenance(to); err != nil {
+		return err
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespaceUID},
+			RuleGroup:     group,
+		})
+		if err != nil {
+			return err
+		}
+		if len(rules) == 0 {
+			return models.ErrAlertRuleGroupNotFound.Errorf("")
+		}
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		objects := make([]models.Provisionable, 0, len(rules))
+		for _, rule := range rules {
+			if stored := provenances[rule.UID]; stored != from {
+				return ErrProvenanceMismatch.Errorf("alert rule '%s' of the rule group has provenance '%s', not '%s'", rule.UID, stored, from)
+			}
+			objects = append(objects, rule)
+		}
+		if err := service.provenanceStore.SetProvenances(ctx, orgID, objects, to); err != nil {
+			return err
+		}
+		service.log.Info("Changed the provenance of the alert rules of a rule group", "org", orgID, "folder", namespaceUID, "group", group, "rules", len(rules), "from", from, "to", to, "user", userID)
+		return nil
+	})
+}
+
+// validateProvenance checks that the provenance is one that resources can be written with.
+func validateProvenance(p models.Provenance) error {
+	switch p {
+	case models.ProvenanceNone, models.ProvenanceAPI, models.ProvenanceFile:
+		return nil
+	}
+	return fmt.Errorf("%w: unknown provenance '%s'", ErrValidation, p)
+}
@@ -0,0 +1,139 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+func TestChangeProvenance(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	t.Run("should hand a rule over to another provenance", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("handed-over", orgID), models.ProvenanceFile, 0)
+		require.NoError(t, err)
+		_, err = ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceNone)
+		require.Error(t, err, "rules provisioned with files should not be changed without provenance")
+
+		require.NoError(t, ruleService.ChangeProvenance(ctx, 0, orgID, rule.UID, models.ProvenanceFile, models.ProvenanceNone))
+
+		rule, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+		_, err = ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceNone)
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail if the rule does not have the expected provenance", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("mismatch", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		err = ruleService.ChangeProvenance(ctx, 0, orgID, rule.UID, models.ProvenanceFile, models.ProvenanceNone)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		_, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+	})
+
+	t.Run("should fail if the rule does not exist", func(t *testing.T) {
+		err := ruleService.ChangeProvenance(ctx, 0, orgID, "missing", models.ProvenanceFile, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+
+	t.Run("should fail with an unknown provenance", func(t *testing.T) {
+		err := ruleService.ChangeProvenance(ctx, 0, orgID, "missing", models.ProvenanceFile, "terraform")
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should hand the rules of a group over to another provenance", func(t *testing.T) {
+		group := createDummyGroup("handed-over-group", orgID)
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceFile))
+
+		require.NoError(t, ruleService.ChangeRuleGroupProvenance(ctx, 0, orgID, group.FolderUID, group.Title, models.ProvenanceFile, models.ProvenanceAPI))
+
+		readGroup, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.NotEmpty(t, readGroup.Rules)
+		for _, rule := range readGroup.Rules {
+			_, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceAPI, provenance)
+		}
+	})
+
+	t.Run("should not change the group if one of its rules does not have the expected provenance", func(t *testing.T) {
+		group := createDummyGroup("mixed-group", orgID)
+		group.Rules = append(group.Rules, dummyRule("mixed-group-rule-2", orgID))
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceFile))
+		readGroup, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.NoError(t, ruleService.ChangeProvenance(ctx, 0, orgID, readGroup.Rules[0].UID, models.ProvenanceFile, models.ProvenanceAPI))
+
+		err = ruleService.ChangeRuleGroupProvenance(ctx, 0, orgID, group.FolderUID, group.Title, models.ProvenanceFile, models.ProvenanceNone)
+		require.ErrorIs(t, err, ErrProvenanceMismatch)
+
+		for i, rule := range readGroup.Rules {
+			_, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+			require.NoError(t, err)
+			if i == 0 {
+				require.Equal(t, models.ProvenanceAPI, provenance)
+			} else {
+				require.Equal(t, models.ProvenanceFile, provenance)
+			}
+		}
+	})
+
+	t.Run("should fail if the group does not exist", func(t *testing.T) {
+		err := ruleService.ChangeRuleGroupProvenance(ctx, 0, orgID, "my-namespace", "missing", models.ProvenanceFile, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+}
+
+func TestProvenanceNotAllowed(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	rule := dummyRule("owned", orgID)
+	rule.UID = "owned"
+	rule, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceFile, 0)
+	require.NoError(t, err)
+	metadata := &models.ProvenanceMetadata{Source: "alerting/rules.yaml", ExternalID: "owned"}
+	require.NoError(t, ruleService.provenanceStore.SetProvenanceWithMetadata(ctx, &rule, orgID, models.ProvenanceFile, metadata))
+
+	publicError := func(t *testing.T, err error) errutil.PublicError {
+		t.Helper()
+		require.ErrorIs(t, err, ErrProvenanceNotAllowed)
+		var e errutil.Error
+		require.True(t, errors.As(err, &e))
+		return e.Public()
+	}
+
+	t.Run("should tell the owner of the rule and how to take it over", func(t *testing.T) {
+		_, err := ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceAPI)
+		public := publicError(t, err)
+
+		require.Equal(t, 409, public.StatusCode)
+		require.Equal(t, "alerting.provisioning.provenanceNotAllowed", public.MessageID)
+		require.Contains(t, public.Message, "Cannot update alert rule 'owned' with provenance 'api' because it is owned by provenance 'file' from alerting/rules.yaml.")
+		require.Contains(t, public.Message, `PUT /api/v1/provisioning/alert-rules/owned/provenance with the body {"from":"file","to":"api"}`)
+		require.Contains(t, public.Message, "PUT /api/v1/provisioning/folder/my-namespace/rule-groups/my-cool-group/provenance")
+		require.Equal(t, "owned", public.Extra["ExternalID"])
+	})
+
+	t.Run("should tell the action that is not allowed", func(t *testing.T) {
+		err := ruleService.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceNone)
+		public := publicError(t, err)
+
+		require.Contains(t, public.Message, "Cannot delete alert rule 'owned' with provenance 'none'")
+		require.Contains(t, public.Message, `{"from":"file","to":""}`)
+	})
+}
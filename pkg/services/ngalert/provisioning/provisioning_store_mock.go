@@ -120,6 +120,62 @@ func (_c *MockProvisioningStore_GetProvenance_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// GetProvenanceMetadata provides a mock function with given fields: ctx, o, org
+func (_m *MockProvisioningStore) GetProvenanceMetadata(ctx context.Context, o models.Provisionable, org int64) (*models.ProvenanceMetadata, error) {
+	ret := _m.Called(ctx, o, org)
+
+	var r0 *models.ProvenanceMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Provisionable, int64) (*models.ProvenanceMetadata, error)); ok {
+		return rf(ctx, o, org)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.Provisionable, int64) *models.ProvenanceMetadata); ok {
+		r0 = rf(ctx, o, org)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ProvenanceMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.Provisionable, int64) error); ok {
+		r1 = rf(ctx, o, org)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockProvisioningStore_GetProvenanceMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProvenanceMetadata'
+type MockProvisioningStore_GetProvenanceMetadata_Call struct {
+	*mock.Call
+}
+
+// GetProvenanceMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - o models.Provisionable
+//   - org int64
+func (_e *MockProvisioningStore_Expecter) GetProvenanceMetadata(ctx interface{}, o interface{}, org interface{}) *MockProvisioningStore_GetProvenanceMetadata_Call {
+	return &MockProvisioningStore_GetProvenanceMetadata_Call{Call: _e.mock.On("GetProvenanceMetadata", ctx, o, org)}
+}
+
+func (_c *MockProvisioningStore_GetProvenanceMetadata_Call) Run(run func(ctx context.Context, o models.Provisionable, org int64)) *MockProvisioningStore_GetProvenanceMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Provisionable), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockProvisioningStore_GetProvenanceMetadata_Call) Return(_a0 *models.ProvenanceMetadata, _a1 error) *MockProvisioningStore_GetProvenanceMetadata_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockProvisioningStore_GetProvenanceMetadata_Call) RunAndReturn(run func(context.Context, models.Provisionable, int64) (*models.ProvenanceMetadata, error)) *MockProvisioningStore_GetProvenanceMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetProvenances provides a mock function with given fields: ctx, org, resourceType
 func (_m *MockProvisioningStore) GetProvenances(ctx context.Context, org int64, resourceType string) (map[string]models.Provenance, error) {
 	ret := _m.Called(ctx, org, resourceType)
@@ -176,6 +232,62 @@ func (_c *MockProvisioningStore_GetProvenances_Call) RunAndReturn(run func(conte
 	return _c
 }
 
+// GetProvenancesMetadata provides a mock function with given fields: ctx, org, resourceType
+func (_m *MockProvisioningStore) GetProvenancesMetadata(ctx context.Context, org int64, resourceType string) (map[string]models.ProvenanceMetadata, error) {
+	ret := _m.Called(ctx, org, resourceType)
+
+	var r0 map[string]models.ProvenanceMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (map[string]models.ProvenanceMetadata, error)); ok {
+		return rf(ctx, org, resourceType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) map[string]models.ProvenanceMetadata); ok {
+		r0 = rf(ctx, org, resourceType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]models.ProvenanceMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, org, resourceType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockProvisioningStore_GetProvenancesMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetProvenancesMetadata'
+type MockProvisioningStore_GetProvenancesMetadata_Call struct {
+	*mock.Call
+}
+
+// GetProvenancesMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - org int64
+//   - resourceType string
+func (_e *MockProvisioningStore_Expecter) GetProvenancesMetadata(ctx interface{}, org interface{}, resourceType interface{}) *MockProvisioningStore_GetProvenancesMetadata_Call {
+	return &MockProvisioningStore_GetProvenancesMetadata_Call{Call: _e.mock.On("GetProvenancesMetadata", ctx, org, resourceType)}
+}
+
+func (_c *MockProvisioningStore_GetProvenancesMetadata_Call) Run(run func(ctx context.Context, org int64, resourceType string)) *MockProvisioningStore_GetProvenancesMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockProvisioningStore_GetProvenancesMetadata_Call) Return(_a0 map[string]models.ProvenanceMetadata, _a1 error) *MockProvisioningStore_GetProvenancesMetadata_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockProvisioningStore_GetProvenancesMetadata_Call) RunAndReturn(run func(context.Context, int64, string) (map[string]models.ProvenanceMetadata, error)) *MockProvisioningStore_GetProvenancesMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetProvenance provides a mock function with given fields: ctx, o, org, p
 func (_m *MockProvisioningStore) SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error {
 	ret := _m.Called(ctx, o, org, p)
@@ -221,6 +333,97 @@ func (_c *MockProvisioningStore_SetProvenance_Call) RunAndReturn(run func(contex
 	return _c
 }
 
+// SetProvenances provides a mock function with given fields: ctx, org, objects, p
+func (_m *MockProvisioningStore) SetProvenances(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance) error {
+	ret := _m.Called(ctx, org, objects, p)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []models.Provisionable, models.Provenance) error); ok {
+		r0 = rf(ctx, org, objects, p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockProvisioningStore_SetProvenances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetProvenances'
+type MockProvisioningStore_SetProvenances_Call struct {
+	*mock.Call
+}
+
+// SetProvenances is a helper method to define mock.On call
+//   - ctx context.Context
+//   - org int64
+//   - objects []models.Provisionable
+//   - p models.Provenance
+func (_e *MockProvisioningStore_Expecter) SetProvenances(ctx interface{}, org interface{}, objects interface{}, p interface{}) *MockProvisioningStore_SetProvenances_Call {
+	return &MockProvisioningStore_SetProvenances_Call{Call: _e.mock.On("SetProvenances", ctx, org, objects, p)}
+}
+
+func (_c *MockProvisioningStore_SetProvenances_Call) Run(run func(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance)) *MockProvisioningStore_SetProvenances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].([]models.Provisionable), args[3].(models.Provenance))
+	})
+	return _c
+}
+
+func (_c *MockProvisioningStore_SetProvenances_Call) Return(_a0 error) *MockProvisioningStore_SetProvenances_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockProvisioningStore_SetProvenances_Call) RunAndReturn(run func(context.Context, int64, []models.Provisionable, models.Provenance) error) *MockProvisioningStore_SetProvenances_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetProvenanceWithMetadata provides a mock function with given fields: ctx, o, org, p, metadata
+func (_m *MockProvisioningStore) SetProvenanceWithMetadata(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata) error {
+	ret := _m.Called(ctx, o, org, p, metadata)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.Provisionable, int64, models.Provenance, *models.ProvenanceMetadata) error); ok {
+		r0 = rf(ctx, o, org, p, metadata)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockProvisioningStore_SetProvenanceWithMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetProvenanceWithMetadata'
+type MockProvisioningStore_SetProvenanceWithMetadata_Call struct {
+	*mock.Call
+}
+
+// SetProvenanceWithMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - o models.Provisionable
+//   - org int64
+//   - p models.Provenance
+//   - metadata *models.ProvenanceMetadata
+func (_e *MockProvisioningStore_Expecter) SetProvenanceWithMetadata(ctx interface{}, o interface{}, org interface{}, p interface{}, metadata interface{}) *MockProvisioningStore_SetProvenanceWithMetadata_Call {
+	return &MockProvisioningStore_SetProvenanceWithMetadata_Call{Call: _e.mock.On("SetProvenanceWithMetadata", ctx, o, org, p, metadata)}
+}
+
+func (_c *MockProvisioningStore_SetProvenanceWithMetadata_Call) Run(run func(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata)) *MockProvisioningStore_SetProvenanceWithMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(models.Provisionable), args[2].(int64), args[3].(models.Provenance), args[4].(*models.ProvenanceMetadata))
+	})
+	return _c
+}
+
+func (_c *MockProvisioningStore_SetProvenanceWithMetadata_Call) Return(_a0 error) *MockProvisioningStore_SetProvenanceWithMetadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockProvisioningStore_SetProvenanceWithMetadata_Call) RunAndReturn(run func(context.Context, models.Provisionable, int64, models.Provenance, *models.ProvenanceMetadata) error) *MockProvisioningStore_SetProvenanceWithMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockProvisioningStore creates a new instance of MockProvisioningStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockProvisioningStore(t interface {
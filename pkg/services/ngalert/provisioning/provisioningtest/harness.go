@@ -0,0 +1,182 @@
+// Package provisioningtest provides a harness to test provisioning flows against a real database without running
+// Grafana.
+package provisioningtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// HarnessConfig configures the alert rule service of a harness. The intervals default to the ones of Grafana if they
+// are zero, and the other zero values disable the corresponding limits.
+type HarnessConfig struct {
+	DefaultInterval        time.Duration
+	BaseInterval           time.Duration
+	RulesPerRuleGroupLimit int64
+	RuleGroupChangesLimit  int64
+	// QuotaExemptProvenances are the provenances of the rules that do not count towards the quotas. Such rules are
+	// limited by QuotaExemptRulesLimit instead, negative for no limit.
+	QuotaExemptProvenances []string
+	QuotaExemptRulesLimit  int64
+	RuleLimits             models.RuleLimits
+	// SchedulerShards are the scheduler shards that rule groups can be pinned to.
+	SchedulerShards []string
+	// SecretReferences restricts the secrets that the incident hooks can refer to.
+	SecretReferences setting.UnifiedAlertingSecretReferencesSettings
+	// TrashRetention is how long deleted rules can be restored. Rules are deleted permanently if it is zero.
+	TrashRetention time.Duration
+	// QuotaReached makes the quota checks fail, as if the quotas were reached.
+	QuotaReached bool
+}
+
+// Harness is an alert rule service wired against an SQLite store, or the database that GRAFANA_TEST_DB selects, with
+// fake quotas and access control.
+type Harness struct {
+	Service *provisioning.AlertRuleService
+	// Store stores the rules and their provenance.
+	Store  *store.DBstore
+	Quotas *quotatest.FakeQuotaService
+	Authz  *FakeRuleAccessControl
+}
+
+// NewHarness returns a harness with a new database, which is cleaned up when the test ends.
+func NewHarness(t testing.TB, cfg HarnessConfig) *Harness {
+	t.Helper()
+	if cfg.DefaultInterval == 0 {
+		cfg.DefaultInterval = setting.DefaultRuleEvaluationInterval
+	}
+	if cfg.BaseInterval == 0 {
+		cfg.BaseInterval = setting.SchedulerBaseInterval
+	}
+	st := &store.DBstore{
+		SQLStore: db.InitTestDB(t),
+		Cfg: setting.UnifiedAlertingSettings{
+			BaseInterval:                  cfg.BaseInterval,
+			DefaultRuleEvaluationInterval: cfg.DefaultInterval,
+			RulesPerRuleGroupLimit:        cfg.RulesPerRuleGroupLimit,
+			RuleGroupChangesLimit:         cfg.RuleGroupChangesLimit,
+			QuotaExemptProvenances:        cfg.QuotaExemptProvenances,
+			QuotaExemptRulesLimit:         cfg.QuotaExemptRulesLimit,
+			RuleMaxSizeBytes:              cfg.RuleLimits.MaxSizeBytes,
+			RuleMaxQueries:                cfg.RuleLimits.MaxQueries,
+			RuleMaxExpressionDepth:        cfg.RuleLimits.MaxExpressionDepth,
+			SchedulerShards:               cfg.SchedulerShards,
+			SecretReferences:              cfg.SecretReferences,
+			AlertRuleTrashRetention:       cfg.TrashRetention,
+		},
+		FeatureToggles: featuremgmt.WithFeatures(),
+		Logger:         log.NewNopLogger(),
+	}
+	h := &Harness{
+		Store:  st,
+		Quotas: quotatest.New(cfg.QuotaReached, nil),
+		Authz:  &FakeRuleAccessControl{},
+	}
+	var err error
+	h.Service, err = provisioning.NewAlertRuleService(provisioning.AlertRuleServiceConfig{
+		RuleStore:            st,
+		ProvenanceStore:      st,
+		DashboardService:     FakeFolders{},
+		Quotas:               h.Quotas,
+		Xact:                 st,
+		NotificationSettings: &provisioning.NotificationSettingsValidatorProviderFake{},
+		Log:                  log.NewNopLogger(),
+		TrashStore:           st,
+		Authz:                h.Authz,
+		Settings:             st.Cfg,
+	})
+	if err != nil {
+		t.Fatalf("failed to create the alert rule service: %v", err)
+	}
+	return h
+}
+
+// Rules returns all the stored rules of the organization, read from the store rather than through the service.
+func (h *Harness) Rules(t testing.TB, orgID int64) []*models.AlertRule {
+	t.Helper()
+	rules, err := h.Store.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		t.Fatalf("failed to list alert rules: %v", err)
+	}
+	return rules
+}
+
+// FakeFolders reads folders whose title is their UID. Every folder exists.
+type FakeFolders struct{}
+
+func (FakeFolders) GetDashboard(_ context.Context, query *dashboards.GetDashboardQuery) (*dashboards.Dashboard, error) {
+	return &dashboards.Dashboard{OrgID: query.OrgID, UID: query.UID, Title: query.UID, IsFolder: true}, nil
+}
+
+func (FakeFolders) GetDashboards(_ context.Context, query *dashboards.GetDashboardsQuery) ([]*dashboards.Dashboard, error) {
+	result := make([]*dashboards.Dashboard, 0, len(query.DashboardUIDs))
+	for _, uid := range query.DashboardUIDs {
+		result = append(result, &dashboards.Dashboard{OrgID: query.OrgID, UID: uid, Title: uid, IsFolder: true})
+	}
+	return result, nil
+}
+
+var _ provisioning.DashboardReader = FakeFolders{}
+
+// FakeRuleAccessControl records the changes of rules that it authorizes. It allows everything unless its errors are
+// set.
+type FakeRuleAccessControl struct {
+	// ReadErr is returned by the authorization of the access to rule groups.
+	ReadErr error
+	// ChangeErr is returned by the authorization of the changes of rules.
+	ChangeErr error
+	Changes   []*store.GroupDelta
+	// CanWriteAll allows the changes of all rules without authorizing them.
+	CanWriteAll bool
+	// CanReadAll allows reading all rules without authorizing the access to their groups.
+	CanReadAll bool
+	// HasAccess decides the access to rule groups if it is set, instead of ReadErr.
+	HasAccess func(rules models.RulesGroup) bool
+	// HasFolderAccess decides the access to the rules of folders if it is set, instead of ReadErr.
+	HasFolderAccess func(folderUID string) bool
+}
+
+func (f *FakeRuleAccessControl) HasAccessToRuleGroup(_ context.Context, _ identity.Requester, rules models.RulesGroup) (bool, error) {
+	if f.HasAccess != nil {
+		return f.HasAccess(rules), nil
+	}
+	return f.ReadErr == nil, nil
+}
+
+func (f *FakeRuleAccessControl) HasAccessInFolder(_ context.Context, _ identity.Requester, folderUID string) (bool, error) {
+	if f.HasFolderAccess != nil {
+		return f.HasFolderAccess(folderUID), nil
+	}
+	return f.ReadErr == nil, nil
+}
+
+func (f *FakeRuleAccessControl) AuthorizeAccessToRuleGroup(context.Context, identity.Requester, models.RulesGroup) error {
+	return f.ReadErr
+}
+
+func (f *FakeRuleAccessControl) AuthorizeRuleChanges(_ context.Context, _ identity.Requester, change *store.GroupDelta) error {
+	f.Changes = append(f.Changes, change)
+	return f.ChangeErr
+}
+
+func (f *FakeRuleAccessControl) CanWriteAllRules(context.Context, identity.Requester) (bool, error) {
+	return f.CanWriteAll, nil
+}
+
+func (f *FakeRuleAccessControl) CanReadAllRules(context.Context, identity.Requester) (bool, error) {
+	return f.CanReadAll, nil
+}
+
+var _ provisioning.RuleAccessControlService = &FakeRuleAccessControl{}
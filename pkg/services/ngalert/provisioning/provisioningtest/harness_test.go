@@ -0,0 +1,151 @@
+package provisioningtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tests/testsuite"
+)
+
+func TestMain(m *testing.M) {
+	testsuite.Run(m)
+}
+
+func TestHarness(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	group := models.AlertRuleGroup{
+		Title:     "platform",
+		FolderUID: "infra",
+		Interval:  60,
+		Rules:     []models.AlertRule{testRule("CPU usage", orgID), testRule("Memory usage", orgID)},
+	}
+
+	t.Run("should store the rule groups in the database", func(t *testing.T) {
+		h := NewHarness(t, HarnessConfig{})
+
+		require.NoError(t, h.Service.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+
+		rules := h.Rules(t, orgID)
+		require.Len(t, rules, 2)
+		for _, rule := range rules {
+			require.Equal(t, int64(60), rule.IntervalSeconds)
+		}
+		stored, err := h.Service.GetRuleGroup(ctx, orgID, "infra", "platform")
+		require.NoError(t, err)
+		require.Len(t, stored.Rules, 2)
+	})
+
+	t.Run("should record the changes that are authorized", func(t *testing.T) {
+		h := NewHarness(t, HarnessConfig{})
+		require.NoError(t, h.Service.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		selector := models.ListAlertRulesQuery{NamespaceUIDs: []string{"infra"}}
+
+		_, err := h.Service.PatchRuleLabels(ctx, &user.SignedInUser{OrgID: orgID}, orgID, selector, map[string]string{"team": "platform"}, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Len(t, h.Authz.Changes, 1)
+		require.Len(t, h.Authz.Changes[0].Update, 2)
+
+		h.Authz.ChangeErr = errors.New("denied")
+		_, err = h.Service.PatchRuleLabels(ctx, &user.SignedInUser{OrgID: orgID}, orgID, selector, map[string]string{"team": "ops"}, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, h.Authz.ChangeErr)
+	})
+
+	t.Run("should return only the rules of the groups that the user can access", func(t *testing.T) {
+		h := NewHarness(t, HarnessConfig{})
+		require.NoError(t, h.Service.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		other := models.AlertRuleGroup{Title: "db", FolderUID: "infra", Interval: 60, Rules: []models.AlertRule{testRule("Disk usage", orgID)}}
+		require.NoError(t, h.Service.ReplaceRuleGroup(ctx, orgID, other, 0, models.ProvenanceAPI))
+		requester := &user.SignedInUser{OrgID: orgID}
+		query := models.ListAlertRulesQuery{OrgID: orgID}
+
+		h.Authz.HasAccess = func(rules models.RulesGroup) bool {
+			return rules[0].RuleGroup == "platform"
+		}
+		rules, _, err := h.Service.GetAlertRules(ctx, requester, query)
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		for _, rule := range rules {
+			require.Equal(t, "platform", rule.RuleGroup)
+		}
+
+		query.Limit, query.Page = 1, 2
+		rules, _, err = h.Service.GetAlertRules(ctx, requester, query)
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, "platform", rules[0].RuleGroup)
+
+		h.Authz.CanReadAll = true
+		rules, _, err = h.Service.GetAlertRules(ctx, requester, models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, rules, 3)
+	})
+
+	t.Run("should return only the rules of the folders in which the user can read the rules", func(t *testing.T) {
+		h := NewHarness(t, HarnessConfig{})
+		require.NoError(t, h.Service.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		other := models.AlertRuleGroup{Title: "db", FolderUID: "storage", Interval: 60, Rules: []models.AlertRule{testRule("Disk usage", orgID)}}
+		require.NoError(t, h.Service.ReplaceRuleGroup(ctx, orgID, other, 0, models.ProvenanceAPI))
+
+		h.Authz.HasFolderAccess = func(folderUID string) bool {
+			return folderUID == "infra"
+		}
+		rules, _, err := h.Service.GetAlertRules(ctx, &user.SignedInUser{OrgID: orgID}, models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		for _, rule := range rules {
+			require.Equal(t, "infra", rule.NamespaceUID)
+		}
+	})
+
+	t.Run("should reject the writes if the quota is reached", func(t *testing.T) {
+		h := NewHarness(t, HarnessConfig{QuotaReached: true})
+
+		err := h.Service.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrQuotaReached)
+		require.Empty(t, h.Rules(t, orgID))
+	})
+
+	t.Run("should keep the deleted rules for the trash retention", func(t *testing.T) {
+		h := NewHarness(t, HarnessConfig{TrashRetention: time.Hour})
+		require.NoError(t, h.Service.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+
+		require.NoError(t, h.Service.DeleteRuleGroup(ctx, orgID, "infra", "platform", models.ProvenanceAPI))
+
+		deleted, err := h.Service.ListDeletedRules(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+	})
+}
+
+func testRule(title string, orgID int64) models.AlertRule {
+	return models.AlertRule{
+		OrgID:        orgID,
+		Title:        title,
+		NamespaceUID: "infra",
+		RuleGroup:    "platform",
+		Condition:    "A",
+		Data: []models.AlertQuery{
+			{
+				RefID:         "A",
+				Model:         json.RawMessage("{}"),
+				DatasourceUID: expr.DatasourceUID,
+				RelativeTimeRange: models.RelativeTimeRange{
+					From: models.Duration(60),
+					To:   models.Duration(0),
+				},
+			},
+		},
+		For:          time.Minute,
+		NoDataState:  models.OK,
+		ExecErrState: models.OkErrState,
+	}
+}
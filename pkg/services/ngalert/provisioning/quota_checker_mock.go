@@ -76,6 +76,62 @@ func (_c *MockQuotaChecker_CheckQuotaReached_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// GetQuotasByScope provides a mock function with given fields: ctx, scope, id
+func (_m *MockQuotaChecker) GetQuotasByScope(ctx context.Context, scope quota.Scope, id int64) ([]quota.QuotaDTO, error) {
+	ret := _m.Called(ctx, scope, id)
+
+	var r0 []quota.QuotaDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, quota.Scope, int64) ([]quota.QuotaDTO, error)); ok {
+		return rf(ctx, scope, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, quota.Scope, int64) []quota.QuotaDTO); ok {
+		r0 = rf(ctx, scope, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]quota.QuotaDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, quota.Scope, int64) error); ok {
+		r1 = rf(ctx, scope, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockQuotaChecker_GetQuotasByScope_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetQuotasByScope'
+type MockQuotaChecker_GetQuotasByScope_Call struct {
+	*mock.Call
+}
+
+// GetQuotasByScope is a helper method to define mock.On call
+//   - ctx context.Context
+//   - scope quota.Scope
+//   - id int64
+func (_e *MockQuotaChecker_Expecter) GetQuotasByScope(ctx interface{}, scope interface{}, id interface{}) *MockQuotaChecker_GetQuotasByScope_Call {
+	return &MockQuotaChecker_GetQuotasByScope_Call{Call: _e.mock.On("GetQuotasByScope", ctx, scope, id)}
+}
+
+func (_c *MockQuotaChecker_GetQuotasByScope_Call) Run(run func(ctx context.Context, scope quota.Scope, id int64)) *MockQuotaChecker_GetQuotasByScope_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(quota.Scope), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockQuotaChecker_GetQuotasByScope_Call) Return(_a0 []quota.QuotaDTO, _a1 error) *MockQuotaChecker_GetQuotasByScope_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockQuotaChecker_GetQuotasByScope_Call) RunAndReturn(run func(context.Context, quota.Scope, int64) ([]quota.QuotaDTO, error)) *MockQuotaChecker_GetQuotasByScope_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockQuotaChecker creates a new instance of MockQuotaChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockQuotaChecker(t interface {
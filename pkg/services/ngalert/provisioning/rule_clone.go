@@ -0,0 +1,185 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// CloneRuleGroupOptions are the changes that CloneRuleGroup makes to the rules of the cloned group.
+type CloneRuleGroupOptions struct {
+	// Title is the title of the clone, the title of the source group if it is empty.
+	Title string
+	// DatasourceUIDs maps the UIDs of the data sources of the source rules to the UIDs of the data sources of the
+	// clones. The queries of the data sources that are not mapped are kept as they are.
+	DatasourceUIDs map[string]string
+	// Labels are set on the clones, replacing the labels with the same names. A label with an empty value is removed.
+	Labels map[string]string
+	// Provenance is the provenance of the clones.
+	Provenance models.Provenance
+}
+
+// CloneRuleGroup copies the rule group src of the organization srcOrgID to the folder dstFolderUID of the organization
+// dstOrgID, in one transaction. The clones are new rules with new UIDs, changed according to the options. The target
+// group must not exist. The clones lose the dashboard and panel of the source rules if the organization changes.
+//
+// The user must be able to read the source group and to create the clones. The permissions of the user are checked
+// only in the organization of the user, so the caller must authorize the access to other organizations.
+func (service *AlertRuleService) CloneRuleGroup(ctx context.Context, user identity.Requester, srcOrgID int64, src models.AlertRuleGroupKey, dstOrgID int64, dstFolderUID string, opts CloneRuleGroupOptions) (models.AlertRuleGroup, error) {
+	if dstFolderUID == "" {
+		return models.AlertRuleGroup{}, fmt.Errorf("%w: folder of the clone is required", ErrValidation)
+	}
+	dst := models.AlertRuleGroupKey{OrgID: dstOrgID, NamespaceUID: dstFolderUID, RuleGroup: opts.Title}
+	if dst.RuleGroup == "" {
+		dst.RuleGroup = src.RuleGroup
+	}
+	src.OrgID = srcOrgID
+	if src == dst {
+		return models.AlertRuleGroup{}, fmt.Errorf("%w: a rule group cannot be cloned to itself", ErrValidation)
+	}
+	var userID int64
+	if user != nil {
+		userID, _ = identity.UserIdentifier(user.GetNamespacedID())
+	}
+
+	var result models.AlertRuleGroup
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		group, err := service.GetRuleGroup(ctx, srcOrgID, src.NamespaceUID, src.RuleGroup)
+		if err != nil {
+			return err
+		}
+		if service.authz != nil && user != nil && user.GetOrgID() == srcOrgID {
+			rules := make(models.RulesGroup, 0, len(group.Rules))
+			for i := range group.Rules {
+				rules = append(rules, &group.Rules[i])
+			}
+			if err := service.authz.AuthorizeAccessToRuleGroup(ctx, user, rules); err != nil {
+				return err
+			}
+		}
+		existing, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         dstOrgID,
+			NamespaceUIDs: []string{dst.NamespaceUID},
+			RuleGroup:     dst.RuleGroup,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		if len(existing) > 0 {
+			return models.ErrAlertRuleGroupExists(dst)
+		}
+
+		clone := group
+		clone.Title = dst.RuleGroup
+		clone.FolderUID = dst.NamespaceUID
+		clone.Rules = make([]models.AlertRule, 0, len(group.Rules))
+		for i := range group.Rules {
+			rule, err := cloneRule(&group.Rules[i], dstOrgID != srcOrgID, opts)
+			if err != nil {
+				return err
+			}
+			clone.Rules = append(clone.Rules, rule)
+		}
+
+		if service.authz != nil && user != nil && user.GetOrgID() == dstOrgID {
+			delta := &store.GroupDelta{GroupKey: dst}
+			for i := range clone.Rules {
+				delta.New = append(delta.New, &clone.Rules[i])
+			}
+			if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+				return err
+			}
+		}
+		if err := service.ReplaceRuleGroup(ctx, dstOrgID, clone, userID, opts.Provenance); err != nil {
+			return err
+		}
+		if result, err = service.GetRuleGroup(ctx, dstOrgID, dst.NamespaceUID, dst.RuleGroup); err != nil {
+			return err
+		}
+		// The read group has no provenance, the clones have the provenance they were created with.
+		result.Provenance = opts.Provenance
+		return nil
+	})
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	service.log.Info("Cloned rule group", "org", srcOrgID, "folder", src.NamespaceUID, "group", src.RuleGroup, "target_org", dstOrgID, "target_folder", dst.NamespaceUID, "target_group", dst.RuleGroup)
+	return result, nil
+}
+
+// cloneRule returns a copy of the rule without identity, so that it is created as a new rule, changed according to
+// the options.
+func cloneRule(rule *models.AlertRule, otherOrg bool, opts CloneRuleGroupOptions) (models.AlertRule, error) {
+	clone := *models.CopyRule(rule)
+	clone.ID = 0
+	clone.UID = ""
+	clone.Version = 0
+	clone.BakeUntil = nil
+	for i, q := range clone.Data {
+		uid, ok := opts.DatasourceUIDs[q.DatasourceUID]
+		if !ok {
+			continue
+		}
+		model, err := setModelDatasourceUID(q.Model, uid)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("failed to map the data source of query %s of rule %s: %w", q.RefID, rule.UID, err)
+		}
+		clone.Data[i].DatasourceUID = uid
+		clone.Data[i].Model = model
+	}
+	if len(opts.Labels) > 0 {
+		labels := maps.Clone(clone.Labels)
+		if labels == nil {
+			labels = make(map[string]string, len(opts.Labels))
+		}
+		for name, value := range opts.Labels {
+			if value == "" {
+				delete(labels, name)
+				continue
+			}
+			labels[name] = value
+		}
+		clone.Labels = labels
+	}
+	// Dashboards belong to an organization, so the clones cannot be linked to the same one in another organization.
+	if otherOrg {
+		clone.DashboardUID = nil
+		clone.PanelID = nil
+		delete(clone.Annotations, models.DashboardUIDAnnotation)
+		delete(clone.Annotations, models.PanelIDAnnotation)
+	}
+	return clone, nil
+}
+
+// setModelDatasourceUID returns a copy of the model of a query whose data source, if the model has one, has the given
+// UID. The other properties of the data source, such as its type, are kept.
+func setModelDatasourceUID(model json.RawMessage, uid string) (json.RawMessage, error) {
+	if len(model) == 0 {
+		return model, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(model, &fields); err != nil {
+		return nil, err
+	}
+	ref, ok := fields["datasource"]
+	if !ok {
+		return model, nil
+	}
+	var datasource map[string]any
+	if err := json.Unmarshal(ref, &datasource); err != nil || datasource == nil {
+		// The data source is referenced by name in old models, which the UID of the query takes precedence over.
+		return model, nil
+	}
+	datasource["uid"] = uid
+	ref, err := json.Marshal(datasource)
+	if err != nil {
+		return nil, err
+	}
+	fields["datasource"] = ref
+	return json.Marshal(fields)
+}
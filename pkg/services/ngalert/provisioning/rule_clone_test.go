@@ -0,0 +1,131 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestCloneRuleGroup(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	requester := &user.SignedInUser{OrgID: orgID, UserID: 1}
+	src := models.AlertRuleGroupKey{NamespaceUID: "my-namespace", RuleGroup: "my-group"}
+
+	setup := func(t *testing.T) (AlertRuleService, models.AlertRuleGroup) {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		group := models.AlertRuleGroup{
+			Title:     src.RuleGroup,
+			FolderUID: src.NamespaceUID,
+			Interval:  120,
+			Rules: []models.AlertRule{
+				createTestRule("CPU usage", src.RuleGroup, orgID, src.NamespaceUID),
+				createTestRule("Memory usage", src.RuleGroup, orgID, src.NamespaceUID),
+			},
+		}
+		group.Rules[0].UID = "cpu"
+		group.Rules[0].Labels = map[string]string{"team": "platform", "env": "prod"}
+		group.Rules[0].DashboardUID = util.Pointer("dashboard")
+		group.Rules[0].PanelID = util.Pointer(int64(1))
+		group.Rules[0].Annotations = map[string]string{models.DashboardUIDAnnotation: "dashboard", models.PanelIDAnnotation: "1", "summary": "CPU"}
+		group.Rules[0].Data[0].Model = json.RawMessage(`{"datasource":{"type":"__expr__","uid":"__expr__"},"refId":"A"}`)
+		group.Rules[1].UID = "memory"
+		// The queries of the clones are mapped to a data source, whose queries must have a time range, which is stored in
+		// seconds.
+		for i := range group.Rules {
+			group.Rules[i].Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+		}
+		createRuleGroup(t, ruleService, orgID, group, models.ProvenanceNone)
+		return ruleService, group
+	}
+
+	t.Run("should create a copy of the group with new rules", func(t *testing.T) {
+		ruleService, group := setup(t)
+
+		clone, err := ruleService.CloneRuleGroup(ctx, requester, orgID, src, orgID, "other-namespace", CloneRuleGroupOptions{
+			Title:          "cloned",
+			DatasourceUIDs: map[string]string{expr.DatasourceUID: "mapped"},
+			Labels:         map[string]string{"env": "", "team": "alerting"},
+			Provenance:     models.ProvenanceAPI,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "cloned", clone.Title)
+		require.Equal(t, "other-namespace", clone.FolderUID)
+		require.Equal(t, group.Interval, clone.Interval)
+		require.Len(t, clone.Rules, 2)
+		for i, rule := range clone.Rules {
+			require.NotEqual(t, group.Rules[i].UID, rule.UID, "the clones should be new rules")
+			require.Equal(t, group.Rules[i].Title, rule.Title)
+			require.Equal(t, "mapped", rule.Data[0].DatasourceUID)
+			require.Equal(t, map[string]string{"team": "alerting"}, rule.Labels)
+		}
+		require.JSONEq(t, `{"datasource":{"type":"__expr__","uid":"mapped"},"intervalMs":1000,"maxDataPoints":43200,"refId":"A"}`, string(clone.Rules[0].Data[0].Model), "the data source of the query models should be mapped")
+		require.Equal(t, models.ProvenanceAPI, clone.Provenance)
+		require.Equal(t, util.Pointer("dashboard"), clone.Rules[0].DashboardUID, "the clones should keep the dashboard in the same organization")
+
+		original, err := ruleService.GetRuleGroup(ctx, orgID, src.NamespaceUID, src.RuleGroup)
+		require.NoError(t, err)
+		require.Equal(t, expr.DatasourceUID, original.Rules[0].Data[0].DatasourceUID, "the source rules should not change")
+		require.Equal(t, map[string]string{"team": "platform", "env": "prod"}, original.Rules[0].Labels)
+	})
+
+	t.Run("should unlink the dashboards of the clones in another organization", func(t *testing.T) {
+		ruleService, _ := setup(t)
+
+		clone, err := ruleService.CloneRuleGroup(ctx, requester, orgID, src, 2, src.NamespaceUID, CloneRuleGroupOptions{})
+		require.NoError(t, err)
+		require.Equal(t, src.RuleGroup, clone.Title)
+		require.Len(t, clone.Rules, 2)
+		require.EqualValues(t, 2, clone.Rules[0].OrgID)
+		require.Nil(t, clone.Rules[0].DashboardUID)
+		require.Nil(t, clone.Rules[0].PanelID)
+		require.Equal(t, map[string]string{"summary": "CPU"}, clone.Rules[0].Annotations)
+	})
+
+	t.Run("should fail if the target group exists", func(t *testing.T) {
+		ruleService, _ := setup(t)
+		_, err := ruleService.CreateAlertRule(ctx, createTestRule("Disk usage", src.RuleGroup, orgID, "other-namespace"), models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		_, err = ruleService.CloneRuleGroup(ctx, requester, orgID, src, orgID, "other-namespace", CloneRuleGroupOptions{})
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupExistsBase)
+	})
+
+	t.Run("should fail to clone the group to itself", func(t *testing.T) {
+		ruleService, _ := setup(t)
+
+		_, err := ruleService.CloneRuleGroup(ctx, requester, orgID, src, orgID, src.NamespaceUID, CloneRuleGroupOptions{})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should fail if the group does not exist", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+
+		_, err := ruleService.CloneRuleGroup(ctx, requester, orgID, src, orgID, "other-namespace", CloneRuleGroupOptions{})
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("should authorize the user", func(t *testing.T) {
+		ruleService, _ := setup(t)
+		authz := &fakeRuleAccessControl{changeErr: errors.New("create denied")}
+		ruleService.authz = authz
+
+		_, err := ruleService.CloneRuleGroup(ctx, requester, orgID, src, orgID, "other-namespace", CloneRuleGroupOptions{})
+		require.ErrorIs(t, err, authz.changeErr)
+		require.Len(t, authz.changes, 1)
+		require.Len(t, authz.changes[0].New, 2)
+
+		_, err = ruleService.GetRuleGroup(ctx, orgID, "other-namespace", src.RuleGroup)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound, "the group should not be cloned")
+	})
+}
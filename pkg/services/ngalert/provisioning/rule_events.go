@@ -0,0 +1,100 @@
+package provisioning
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// EventPublisher publishes the events of the changes of alert rules, usually to the event bus.
+type EventPublisher interface {
+	Publish(ctx context.Context, msg bus.Msg) error
+}
+
+// publishAfterCommit publishes the events once the transaction in the context is committed, so that the changes that
+// are rolled back are never published. The changes are committed when the events are published, so failures are only
+// logged.
+func (service *AlertRuleService) publishAfterCommit(ctx context.Context, msgs ...bus.Msg) {
+	if service.events == nil || len(msgs) == 0 {
+		return
+	}
+	service.xact.AfterCommit(ctx, func() {
+		// The context of the transaction may be done when the transaction is committed.
+		ctx := context.WithoutCancel(ctx)
+		for _, msg := range msgs {
+			if err := service.events.Publish(ctx, msg); err != nil {
+				service.log.Warn("Failed to publish alert rule event", "event", msg, "error", err)
+			}
+		}
+	})
+}
+
+func alertRuleCreatedEvent(now time.Time, rule *models.AlertRule, provenance models.Provenance) *events.AlertRuleCreated {
+	return &events.AlertRuleCreated{
+		Timestamp:  now,
+		OrgID:      rule.OrgID,
+		UID:        rule.UID,
+		Title:      rule.Title,
+		FolderUID:  rule.NamespaceUID,
+		RuleGroup:  rule.RuleGroup,
+		Provenance: string(provenance),
+	}
+}
+
+func alertRuleUpdatedEvent(now time.Time, rule *models.AlertRule, provenance models.Provenance) *events.AlertRuleUpdated {
+	return &events.AlertRuleUpdated{
+		Timestamp:  now,
+		OrgID:      rule.OrgID,
+		UID:        rule.UID,
+		Title:      rule.Title,
+		FolderUID:  rule.NamespaceUID,
+		RuleGroup:  rule.RuleGroup,
+		Provenance: string(provenance),
+	}
+}
+
+func alertRuleDeletedEvent(now time.Time, rule *models.AlertRule, provenance models.Provenance) *events.AlertRuleDeleted {
+	return &events.AlertRuleDeleted{
+		Timestamp:  now,
+		OrgID:      rule.OrgID,
+		UID:        rule.UID,
+		Title:      rule.Title,
+		FolderUID:  rule.NamespaceUID,
+		RuleGroup:  rule.RuleGroup,
+		Provenance: string(provenance),
+	}
+}
+
+// alertRuleGroupReplacedEvent returns the event of the replacement of the group of the delta, once it is persisted.
+func alertRuleGroupReplacedEvent(now time.Time, delta *store.GroupDelta, provenance models.Provenance) *events.AlertRuleGroupReplaced {
+	e := &events.AlertRuleGroupReplaced{
+		Timestamp:  now,
+		OrgID:      delta.GroupKey.OrgID,
+		FolderUID:  delta.GroupKey.NamespaceUID,
+		RuleGroup:  delta.GroupKey.RuleGroup,
+		Provenance: string(provenance),
+		Created:    []string{},
+		Updated:    []string{},
+		Deleted:    []string{},
+	}
+	for _, rule := range delta.New {
+		if rule != nil {
+			e.Created = append(e.Created, rule.UID)
+		}
+	}
+	for _, update := range delta.Update {
+		if len(update.Diff) > 0 {
+			e.Updated = append(e.Updated, update.New.UID)
+		}
+	}
+	for _, rule := range delta.Delete {
+		if rule != nil {
+			e.Deleted = append(e.Deleted, rule.UID)
+		}
+	}
+	return e
+}
@@ -0,0 +1,125 @@
+package provisioning
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeEventPublisher struct {
+	mtx  sync.Mutex
+	msgs []bus.Msg
+}
+
+func (f *fakeEventPublisher) Publish(_ context.Context, msg bus.Msg) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.msgs = append(f.msgs, msg)
+	return nil
+}
+
+// take returns the published events and forgets them.
+func (f *fakeEventPublisher) take() []bus.Msg {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	msgs := f.msgs
+	f.msgs = nil
+	return msgs
+}
+
+func TestAlertRuleEvents(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	setup := func(t *testing.T) (AlertRuleService, *fakeEventPublisher) {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		publisher := &fakeEventPublisher{}
+		ruleService.events = publisher
+		return ruleService, publisher
+	}
+
+	t.Run("should publish the created, updated and deleted rules", func(t *testing.T) {
+		ruleService, publisher := setup(t)
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("a", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		msgs := publisher.take()
+		require.Len(t, msgs, 1)
+		created, ok := msgs[0].(*events.AlertRuleCreated)
+		require.True(t, ok)
+		require.Equal(t, rule.UID, created.UID)
+		require.Equal(t, "a", created.Title)
+		require.Equal(t, "my-namespace", created.FolderUID)
+		require.Equal(t, "my-cool-group", created.RuleGroup)
+		require.Equal(t, string(models.ProvenanceAPI), created.Provenance)
+
+		rule.Title = "b"
+		_, err = ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+		msgs = publisher.take()
+		require.Len(t, msgs, 1)
+		updated, ok := msgs[0].(*events.AlertRuleUpdated)
+		require.True(t, ok)
+		require.Equal(t, rule.UID, updated.UID)
+		require.Equal(t, "b", updated.Title)
+
+		require.NoError(t, ruleService.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceAPI))
+		msgs = publisher.take()
+		require.Len(t, msgs, 1)
+		deleted, ok := msgs[0].(*events.AlertRuleDeleted)
+		require.True(t, ok)
+		require.Equal(t, rule.UID, deleted.UID)
+		require.Equal(t, "b", deleted.Title)
+		require.Equal(t, string(models.ProvenanceAPI), deleted.Provenance)
+
+		require.NoError(t, ruleService.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceAPI))
+		require.Empty(t, publisher.take())
+	})
+
+	t.Run("should publish the replacement of a group with the changes of its rules", func(t *testing.T) {
+		ruleService, publisher := setup(t)
+		group := createDummyGroup("my-cool-group", orgID)
+		group.Rules = []models.AlertRule{dummyRule("a", orgID), dummyRule("b", orgID)}
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		msgs := publisher.take()
+		require.Len(t, msgs, 3)
+		replaced, ok := msgs[2].(*events.AlertRuleGroupReplaced)
+		require.True(t, ok)
+		require.Len(t, replaced.Created, 2)
+		require.Empty(t, replaced.Updated)
+		require.Empty(t, replaced.Deleted)
+
+		stored, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		stored.Rules[0].Title = "a2"
+		stored.Rules = stored.Rules[:1]
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, stored, 0, models.ProvenanceAPI))
+		msgs = publisher.take()
+		require.Len(t, msgs, 3)
+		require.IsType(t, &events.AlertRuleDeleted{}, msgs[0])
+		require.IsType(t, &events.AlertRuleUpdated{}, msgs[1])
+		replaced, ok = msgs[2].(*events.AlertRuleGroupReplaced)
+		require.True(t, ok)
+		require.Empty(t, replaced.Created)
+		require.Equal(t, []string{stored.Rules[0].UID}, replaced.Updated)
+		require.Len(t, replaced.Deleted, 1)
+	})
+
+	t.Run("should not publish the changes that fail", func(t *testing.T) {
+		ruleService, publisher := setup(t)
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("a", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		publisher.take()
+
+		rule.Version++
+		_, err = ruleService.UpdateAlertRuleIfUnchanged(ctx, rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrAlertRuleVersionConflict)
+		require.Empty(t, publisher.take())
+	})
+}
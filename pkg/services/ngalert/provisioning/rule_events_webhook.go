@@ -0,0 +1,417 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// The types of the events posted to the provisioning webhook.
+const (
+	WebhookEventAlertRuleCreated  = setting.ProvisioningWebhookEventAlertRuleCreated
+	WebhookEventAlertRuleUpdated  = setting.ProvisioningWebhookEventAlertRuleUpdated
+	WebhookEventAlertRuleDeleted  = setting.ProvisioningWebhookEventAlertRuleDeleted
+	WebhookEventRuleGroupReplaced = setting.ProvisioningWebhookEventRuleGroupReplaced
+)
+
+const (
+	// webhookQueueSize is the number of events that wait to be stored and posted. Events are dropped when the queue is
+	// full, so that the changes of the rules are never slowed down by the webhook.
+	webhookQueueSize = 1000
+	// webhookInitialBackoff is the time to wait before the second attempt to post an event, doubled for every attempt.
+	webhookInitialBackoff = time.Second
+	// webhookMaxBackoff is the longest time to wait between two attempts.
+	webhookMaxBackoff = time.Minute
+	// webhookPendingNamespace stores the events that are not delivered yet.
+	webhookPendingNamespace = "alerting.provisioning_webhook.pending"
+	// webhookDeadLetterNamespace stores the events that could not be delivered.
+	webhookDeadLetterNamespace = "alerting.provisioning_webhook.dead_letters"
+	// webhookRecoveryInterval is the interval at which the stored events that are not being posted are queued again.
+	webhookRecoveryInterval = time.Minute
+	// webhookDeliveryStaleAfter is the time after which an event that is not delivered and not attempted anymore is
+	// queued again, e.g. because the instance that posted it was stopped. It is longer than the time between two
+	// attempts.
+	webhookDeliveryStaleAfter = 5 * time.Minute
+	// webhookDeadLetterRetention is the time for which the events that could not be delivered are kept.
+	webhookDeadLetterRetention = 7 * 24 * time.Hour
+)
+
+// ErrWebhookDisabled is returned when a delivery is replayed while the provisioning webhook is disabled.
+var ErrWebhookDisabled = errors.New("the provisioning webhook is disabled")
+
+// WebhookPayload is the body of the requests of the provisioning webhook.
+type WebhookPayload struct {
+	Type  string `json:"type"`
+	Event any    `json:"event"`
+}
+
+// ProvisioningWebhook posts the events of the changes of alert rules to the webhook configured in the settings. The
+// events are posted in order, one at a time, and retried with exponential backoff if the webhook fails.
+//
+// Events are stored before they are posted and until they are delivered, so that the events of an instance that is
+// stopped are posted by the other instances. Events that cannot be delivered are stored as dead letters, which can be
+// listed and replayed. Events are delivered at least once: receivers use the X-Grafana-Delivery header to ignore the
+// events that they already received.
+type ProvisioningWebhook struct {
+	settings setting.UnifiedAlertingProvisioningWebhookSettings
+	client   *http.Client
+	kv       kvstore.KVStore
+	queue    chan models.WebhookDelivery
+	backoff  time.Duration
+	clock    clock.Clock
+	log      log.Logger
+
+	mtx sync.Mutex
+	// queued are the UIDs of the events in the queue, which are not queued again by the recovery.
+	queued map[string]struct{}
+}
+
+func NewProvisioningWebhook(settings setting.UnifiedAlertingProvisioningWebhookSettings, kv kvstore.KVStore, log log.Logger) *ProvisioningWebhook {
+	return &ProvisioningWebhook{
+		settings: settings,
+		client:   &http.Client{},
+		kv:       kv,
+		queue:    make(chan models.WebhookDelivery, webhookQueueSize),
+		backoff:  webhookInitialBackoff,
+		clock:    clock.New(),
+		log:      log,
+		queued:   make(map[string]struct{}),
+	}
+}
+
+// Subscribe queues the events of the bus that are posted to the webhook. It does nothing if the webhook is disabled.
+func (w *ProvisioningWebhook) Subscribe(b bus.Bus) {
+	if w.settings.URL == "" {
+		return
+	}
+	b.AddEventListener(func(ctx context.Context, e *events.AlertRuleCreated) error {
+		w.enqueue(WebhookEventAlertRuleCreated, e.OrgID, e)
+		return nil
+	})
+	b.AddEventListener(func(ctx context.Context, e *events.AlertRuleUpdated) error {
+		w.enqueue(WebhookEventAlertRuleUpdated, e.OrgID, e)
+		return nil
+	})
+	b.AddEventListener(func(ctx context.Context, e *events.AlertRuleDeleted) error {
+		w.enqueue(WebhookEventAlertRuleDeleted, e.OrgID, e)
+		return nil
+	})
+	b.AddEventListener(func(ctx context.Context, e *events.AlertRuleGroupReplaced) error {
+		w.enqueue(WebhookEventRuleGroupReplaced, e.OrgID, e)
+		return nil
+	})
+}
+
+func (w *ProvisioningWebhook) enqueue(eventType string, orgID int64, event any) {
+	if len(w.settings.Events) > 0 && !slices.Contains(w.settings.Events, eventType) {
+		return
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		w.log.Error("Failed to marshal provisioning webhook event", "type", eventType, "error", err)
+		return
+	}
+	now := w.clock.Now()
+	delivery := models.WebhookDelivery{
+		UID:     util.GenerateShortUID(),
+		OrgID:   orgID,
+		Type:    eventType,
+		Event:   body,
+		Created: now,
+		Updated: now,
+	}
+	if !w.tryQueue(delivery) {
+		w.log.Warn("Dropped provisioning webhook event, too many events are waiting to be posted", "type", eventType)
+	}
+}
+
+// tryQueue queues the delivery unless it is already queued, and returns false if the queue is full.
+func (w *ProvisioningWebhook) tryQueue(delivery models.WebhookDelivery) bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if _, ok := w.queued[delivery.UID]; ok {
+		return true
+	}
+	select {
+	case w.queue <- delivery:
+		w.queued[delivery.UID] = struct{}{}
+		return true
+	default:
+		return false
+	}
+}
+
+// Run stores and posts the queued events until the context is done, and periodically queues the stored events that
+// are not being posted again. It returns immediately if the webhook is disabled.
+func (w *ProvisioningWebhook) Run(ctx context.Context) error {
+	if w.settings.URL == "" {
+		return nil
+	}
+	w.recover(ctx)
+	ticker := w.clock.Ticker(webhookRecoveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case delivery := <-w.queue:
+			w.process(ctx, delivery)
+		case <-ticker.C:
+			w.recover(ctx)
+		}
+	}
+}
+
+// process delivers the event that was taken from the queue, after which it can be queued again.
+func (w *ProvisioningWebhook) process(ctx context.Context, delivery models.WebhookDelivery) {
+	w.deliver(ctx, delivery)
+	w.mtx.Lock()
+	delete(w.queued, delivery.UID)
+	w.mtx.Unlock()
+}
+
+// ListDeadLetters returns the events of the organization that could not be delivered, oldest first.
+func (w *ProvisioningWebhook) ListDeadLetters(ctx context.Context, orgID int64) ([]models.WebhookDelivery, error) {
+	result, err := w.list(ctx, webhookDeadLetterNamespace, orgID)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(result, func(a, b models.WebhookDelivery) int {
+		return a.Created.Compare(b.Created)
+	})
+	return result, nil
+}
+
+// ReplayDeadLetter posts the event that could not be delivered again, with as many attempts as a new event. The event
+// is removed from the dead letters, and stored as a dead letter again if it fails.
+func (w *ProvisioningWebhook) ReplayDeadLetter(ctx context.Context, orgID int64, uid string) (models.WebhookDelivery, error) {
+	if w.settings.URL == "" {
+		return models.WebhookDelivery{}, ErrWebhookDisabled
+	}
+	delivery, err := w.get(ctx, webhookDeadLetterNamespace, orgID, uid)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	delivery.Attempts = 0
+	delivery.Error = ""
+	delivery.Updated = w.clock.Now()
+	if err := w.save(ctx, webhookPendingNamespace, delivery); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	if err := kvstore.WithNamespace(w.kv, orgID, webhookDeadLetterNamespace).Del(ctx, uid); err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	// If the queue is full, the event is stored and queued by the recovery once it is stale.
+	w.tryQueue(delivery)
+	return delivery, nil
+}
+
+// deliver stores the event, and posts it until it succeeds, it fails permanently, or it was attempted
+// settings.MaxAttempts times in total. The event is then deleted, or stored as a dead letter if it failed. It stays
+// stored if the context is done, and is posted again by the recovery.
+func (w *ProvisioningWebhook) deliver(ctx context.Context, delivery models.WebhookDelivery) {
+	logger := w.log.New("type", delivery.Type, "delivery", delivery.UID, "org", delivery.OrgID)
+	body, err := json.Marshal(WebhookPayload{Type: delivery.Type, Event: delivery.Event})
+	if err != nil {
+		logger.Error("Failed to marshal provisioning webhook event", "error", err)
+		return
+	}
+	backoff := w.backoff
+	for {
+		delivery.Updated = w.clock.Now()
+		if err := w.save(ctx, webhookPendingNamespace, delivery); err != nil {
+			logger.Warn("Failed to store provisioning webhook event", "error", err)
+		}
+		delivery.Attempts++
+		retry, err := w.send(ctx, delivery, body)
+		if err == nil {
+			w.delete(ctx, webhookPendingNamespace, delivery, logger)
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		delivery.Error = err.Error()
+		if !retry || delivery.Attempts >= w.settings.MaxAttempts {
+			logger.Error("Failed to post provisioning webhook event, storing it as a dead letter", "attempts", delivery.Attempts, "error", err)
+			delivery.Updated = w.clock.Now()
+			if err := w.save(ctx, webhookDeadLetterNamespace, delivery); err != nil {
+				logger.Error("Failed to store provisioning webhook event as a dead letter", "error", err)
+				return
+			}
+			w.delete(ctx, webhookPendingNamespace, delivery, logger)
+			return
+		}
+		logger.Debug("Retrying provisioning webhook event", "attempt", delivery.Attempts, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.clock.After(backoff):
+		}
+		backoff = min(2*backoff, webhookMaxBackoff)
+	}
+}
+
+// recover queues the stored events that are not attempted anymore, and deletes the expired dead letters.
+func (w *ProvisioningWebhook) recover(ctx context.Context) {
+	orgIDs, err := w.orgs(ctx, webhookPendingNamespace)
+	if err != nil {
+		w.log.Error("Failed to read provisioning webhook events", "error", err)
+		return
+	}
+	for _, orgID := range orgIDs {
+		deliveries, err := w.list(ctx, webhookPendingNamespace, orgID)
+		if err != nil {
+			w.log.Error("Failed to read provisioning webhook events", "org", orgID, "error", err)
+			continue
+		}
+		for _, delivery := range deliveries {
+			if w.clock.Since(delivery.Updated) < webhookDeliveryStaleAfter {
+				continue
+			}
+			// The event is claimed, so that the other instances do not queue it as well.
+			delivery.Updated = w.clock.Now()
+			if err := w.save(ctx, webhookPendingNamespace, delivery); err != nil {
+				w.log.Warn("Failed to store provisioning webhook event", "org", orgID, "delivery", delivery.UID, "error", err)
+				continue
+			}
+			if !w.tryQueue(delivery) {
+				return
+			}
+		}
+	}
+
+	orgIDs, err = w.orgs(ctx, webhookDeadLetterNamespace)
+	if err != nil {
+		w.log.Error("Failed to read provisioning webhook dead letters", "error", err)
+		return
+	}
+	for _, orgID := range orgIDs {
+		deliveries, err := w.list(ctx, webhookDeadLetterNamespace, orgID)
+		if err != nil {
+			w.log.Error("Failed to read provisioning webhook dead letters", "org", orgID, "error", err)
+			continue
+		}
+		for _, delivery := range deliveries {
+			if w.clock.Since(delivery.Updated) >= webhookDeadLetterRetention {
+				w.delete(ctx, webhookDeadLetterNamespace, delivery, w.log)
+			}
+		}
+	}
+}
+
+// orgs returns the organizations that have events stored in the namespace.
+func (w *ProvisioningWebhook) orgs(ctx context.Context, namespace string) ([]int64, error) {
+	items, err := kvstore.WithNamespace(w.kv, kvstore.AllOrganizations, namespace).GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int64, 0, len(items))
+	for orgID := range items {
+		result = append(result, orgID)
+	}
+	return result, nil
+}
+
+func (w *ProvisioningWebhook) list(ctx context.Context, namespace string, orgID int64) ([]models.WebhookDelivery, error) {
+	keys, err := kvstore.WithNamespace(w.kv, orgID, namespace).Keys(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.WebhookDelivery, 0, len(keys))
+	for _, key := range keys {
+		delivery, err := w.get(ctx, namespace, orgID, key.Key)
+		if errors.Is(err, models.ErrWebhookDeliveryNotFound) {
+			// The event was delivered or deleted in the meantime.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, delivery)
+	}
+	return result, nil
+}
+
+func (w *ProvisioningWebhook) get(ctx context.Context, namespace string, orgID int64, uid string) (models.WebhookDelivery, error) {
+	value, ok, err := kvstore.WithNamespace(w.kv, orgID, namespace).Get(ctx, uid)
+	if err != nil {
+		return models.WebhookDelivery{}, err
+	}
+	if !ok {
+		return models.WebhookDelivery{}, models.ErrWebhookDeliveryNotFound
+	}
+	var delivery models.WebhookDelivery
+	if err := json.Unmarshal([]byte(value), &delivery); err != nil {
+		return models.WebhookDelivery{}, fmt.Errorf("failed to unmarshal webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
+func (w *ProvisioningWebhook) save(ctx context.Context, namespace string, delivery models.WebhookDelivery) error {
+	value, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	return kvstore.WithNamespace(w.kv, delivery.OrgID, namespace).Set(ctx, delivery.UID, string(value))
+}
+
+func (w *ProvisioningWebhook) delete(ctx context.Context, namespace string, delivery models.WebhookDelivery, logger log.Logger) {
+	if err := kvstore.WithNamespace(w.kv, delivery.OrgID, namespace).Del(ctx, delivery.UID); err != nil {
+		logger.Warn("Failed to delete provisioning webhook event", "namespace", namespace, "delivery", delivery.UID, "error", err)
+	}
+}
+
+// send posts the body once. It returns whether the request can be retried if it fails, i.e. if the webhook could not
+// be reached, or it responded with a server error or too many requests.
+func (w *ProvisioningWebhook) send(ctx context.Context, delivery models.WebhookDelivery, body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, w.settings.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.settings.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Grafana-Event", delivery.Type)
+	req.Header.Set("X-Grafana-Delivery", delivery.UID)
+	if w.settings.Secret != "" {
+		req.Header.Set("X-Grafana-Signature", "sha256="+WebhookSignature(w.settings.Secret, body))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 == 2 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+}
+
+// WebhookSignature returns the hex encoded HMAC-SHA256 of the body with the secret, that the receivers of the webhook
+// compare with the X-Grafana-Signature header, without its "sha256=" prefix.
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,216 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func TestProvisioningWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	// setup starts a webhook that posts to a server that responds with the given status codes in order, then with 200.
+	setup := func(t *testing.T, settings setting.UnifiedAlertingProvisioningWebhookSettings, codes ...int) (bus.Bus, <-chan webhookRequest) {
+		t.Helper()
+		requests := make(chan webhookRequest, 10)
+		var mtx sync.Mutex
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests <- webhookRequest{header: r.Header, body: body}
+			mtx.Lock()
+			defer mtx.Unlock()
+			if len(codes) > 0 {
+				w.WriteHeader(codes[0])
+				codes = codes[1:]
+			}
+		}))
+		t.Cleanup(srv.Close)
+
+		settings.URL = srv.URL
+		if settings.MaxAttempts == 0 {
+			settings.MaxAttempts = 3
+		}
+		settings.Timeout = time.Second
+		webhook := NewProvisioningWebhook(settings, kvstore.NewFakeKVStore(), log.NewNopLogger())
+		webhook.backoff = time.Millisecond
+		b := bus.ProvideBus(tracing.InitializeTracerForTest())
+		webhook.Subscribe(b)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = webhook.Run(runCtx)
+		}()
+		t.Cleanup(func() {
+			cancel()
+			<-done
+		})
+		return b, requests
+	}
+	receive := func(t *testing.T, requests <-chan webhookRequest) webhookRequest {
+		t.Helper()
+		select {
+		case r := <-requests:
+			return r
+		case <-time.After(5 * time.Second):
+			t.Fatal("the webhook was not called")
+			return webhookRequest{}
+		}
+	}
+
+	t.Run("should post signed events", func(t *testing.T) {
+		b, requests := setup(t, setting.UnifiedAlertingProvisioningWebhookSettings{Secret: "secret"})
+		require.NoError(t, b.Publish(ctx, &events.AlertRuleCreated{OrgID: 1, UID: "rule"}))
+
+		r := receive(t, requests)
+		require.Equal(t, "application/json", r.header.Get("Content-Type"))
+		require.Equal(t, WebhookEventAlertRuleCreated, r.header.Get("X-Grafana-Event"))
+		require.NotEmpty(t, r.header.Get("X-Grafana-Delivery"))
+		require.Equal(t, "sha256="+WebhookSignature("secret", r.body), r.header.Get("X-Grafana-Signature"))
+		var payload struct {
+			Type  string                  `json:"type"`
+			Event events.AlertRuleCreated `json:"event"`
+		}
+		require.NoError(t, json.Unmarshal(r.body, &payload))
+		require.Equal(t, WebhookEventAlertRuleCreated, payload.Type)
+		require.Equal(t, "rule", payload.Event.UID)
+	})
+
+	t.Run("should retry the events that fail with a server error", func(t *testing.T) {
+		b, requests := setup(t, setting.UnifiedAlertingProvisioningWebhookSettings{}, http.StatusInternalServerError, http.StatusTooManyRequests)
+		require.NoError(t, b.Publish(ctx, &events.AlertRuleDeleted{OrgID: 1, UID: "rule"}))
+
+		first := receive(t, requests)
+		require.Empty(t, first.header.Get("X-Grafana-Signature"))
+		require.Equal(t, first.body, receive(t, requests).body)
+		require.Equal(t, first.body, receive(t, requests).body)
+	})
+
+	t.Run("should not retry the events that fail with a client error", func(t *testing.T) {
+		b, requests := setup(t, setting.UnifiedAlertingProvisioningWebhookSettings{}, http.StatusBadRequest)
+		require.NoError(t, b.Publish(ctx, &events.AlertRuleDeleted{OrgID: 1, UID: "first"}))
+		require.NoError(t, b.Publish(ctx, &events.AlertRuleDeleted{OrgID: 1, UID: "second"}))
+
+		require.Contains(t, string(receive(t, requests).body), `"first"`)
+		require.Contains(t, string(receive(t, requests).body), `"second"`)
+	})
+
+	t.Run("should post only the configured events", func(t *testing.T) {
+		b, requests := setup(t, setting.UnifiedAlertingProvisioningWebhookSettings{Events: []string{WebhookEventRuleGroupReplaced}})
+		require.NoError(t, b.Publish(ctx, &events.AlertRuleUpdated{OrgID: 1, UID: "rule"}))
+		require.NoError(t, b.Publish(ctx, &events.AlertRuleGroupReplaced{OrgID: 1, RuleGroup: "group"}))
+
+		require.Equal(t, WebhookEventRuleGroupReplaced, receive(t, requests).header.Get("X-Grafana-Event"))
+	})
+
+	t.Run("should do nothing if the URL is not set", func(t *testing.T) {
+		webhook := NewProvisioningWebhook(setting.UnifiedAlertingProvisioningWebhookSettings{}, kvstore.NewFakeKVStore(), log.NewNopLogger())
+		webhook.Subscribe(bus.ProvideBus(tracing.InitializeTracerForTest()))
+		require.NoError(t, webhook.Run(ctx))
+	})
+	t.Run("should store the events that cannot be delivered as dead letters that can be replayed", func(t *testing.T) {
+		var mtx sync.Mutex
+		status := http.StatusInternalServerError
+		attempts := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			attempts++
+			w.WriteHeader(status)
+		}))
+		t.Cleanup(srv.Close)
+
+		kv := kvstore.NewFakeKVStore()
+		webhook := NewProvisioningWebhook(setting.UnifiedAlertingProvisioningWebhookSettings{URL: srv.URL, MaxAttempts: 2, Timeout: time.Second}, kv, log.NewNopLogger())
+		webhook.backoff = time.Millisecond
+		webhook.enqueue(WebhookEventAlertRuleDeleted, 1, &events.AlertRuleDeleted{OrgID: 1, UID: "rule"})
+		webhook.process(ctx, <-webhook.queue)
+
+		require.Equal(t, 2, attempts)
+		deadLetters, err := webhook.ListDeadLetters(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, deadLetters, 1)
+		require.Equal(t, WebhookEventAlertRuleDeleted, deadLetters[0].Type)
+		require.Equal(t, 2, deadLetters[0].Attempts)
+		require.Contains(t, deadLetters[0].Error, "500")
+		uid := deadLetters[0].UID
+		pending, err := kvstore.WithNamespace(kv, 1, webhookPendingNamespace).Keys(ctx, "")
+		require.NoError(t, err)
+		require.Empty(t, pending)
+
+		deadLetters, err = webhook.ListDeadLetters(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+		_, err = webhook.ReplayDeadLetter(ctx, 2, "unknown")
+		require.ErrorIs(t, err, models.ErrWebhookDeliveryNotFound)
+
+		mtx.Lock()
+		status = http.StatusOK
+		mtx.Unlock()
+		replayed, err := webhook.ReplayDeadLetter(ctx, 1, uid)
+		require.NoError(t, err)
+		require.Zero(t, replayed.Attempts)
+		webhook.process(ctx, <-webhook.queue)
+
+		require.Equal(t, 3, attempts)
+		deadLetters, err = webhook.ListDeadLetters(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, deadLetters)
+		pending, err = kvstore.WithNamespace(kv, 1, webhookPendingNamespace).Keys(ctx, "")
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+
+	t.Run("should post the stored events that are not attempted anymore", func(t *testing.T) {
+		requests := make(chan webhookRequest, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests <- webhookRequest{header: r.Header, body: body}
+		}))
+		t.Cleanup(srv.Close)
+
+		kv := kvstore.NewFakeKVStore()
+		webhook := NewProvisioningWebhook(setting.UnifiedAlertingProvisioningWebhookSettings{URL: srv.URL, MaxAttempts: 1, Timeout: time.Second}, kv, log.NewNopLogger())
+		mock := clock.NewMock()
+		webhook.clock = mock
+		stale := models.WebhookDelivery{UID: "stale", OrgID: 1, Type: WebhookEventAlertRuleDeleted, Event: json.RawMessage(`{}`), Attempts: 1, Created: mock.Now(), Updated: mock.Now()}
+		require.NoError(t, webhook.save(ctx, webhookPendingNamespace, stale))
+		mock.Add(webhookDeliveryStaleAfter / 2)
+		recent := models.WebhookDelivery{UID: "recent", OrgID: 1, Type: WebhookEventAlertRuleDeleted, Event: json.RawMessage(`{}`), Created: mock.Now(), Updated: mock.Now()}
+		require.NoError(t, webhook.save(ctx, webhookPendingNamespace, recent))
+		mock.Add(webhookDeliveryStaleAfter / 2)
+
+		webhook.recover(ctx)
+		require.Len(t, webhook.queue, 1)
+		webhook.process(ctx, <-webhook.queue)
+
+		r := receive(t, requests)
+		require.Equal(t, "stale", r.header.Get("X-Grafana-Delivery"))
+		_, err := webhook.get(ctx, webhookPendingNamespace, 1, "stale")
+		require.ErrorIs(t, err, models.ErrWebhookDeliveryNotFound)
+		_, err = webhook.get(ctx, webhookPendingNamespace, 1, "recent")
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,166 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/go-openapi/strfmt"
+	alertingModels "github.com/grafana/alerting/models"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ExpiredRuleAlertName is the name of the alert sent when an alert rule expires.
+const ExpiredRuleAlertName = "AlertRuleExpired"
+
+// expiredRuleAlertDuration is the time after which the alert sent when a rule expires is resolved.
+const expiredRuleAlertDuration = time.Hour
+
+// AlertsSender sends alerts to the Alertmanagers of the organization of the rule.
+type AlertsSender interface {
+	Send(ctx context.Context, key models.AlertRuleKey, alerts definitions.PostableAlerts)
+}
+
+// RuleExpiryService pauses or deletes the alert rules whose expiration passed, so that temporary rules do not
+// accumulate, and notifies their owners.
+type RuleExpiryService struct {
+	ruleStore  RuleStore
+	orgStore   store.OrgStore
+	alertRules *AlertRuleService
+	sender     AlertsSender
+	xact       TransactionManager
+	clock      clock.Clock
+	interval   time.Duration
+	action     string
+	log        log.Logger
+}
+
+func NewRuleExpiryService(rules RuleStore, orgs store.OrgStore, alertRules *AlertRuleService, sender AlertsSender,
+	xact TransactionManager, settings setting.UnifiedAlertingSettings, log log.Logger) *RuleExpiryService {
+	return &RuleExpiryService{
+		ruleStore:  rules,
+		orgStore:   orgs,
+		alertRules: alertRules,
+		sender:     sender,
+		xact:       xact,
+		clock:      clock.New(),
+		interval:   settings.ExpiredRuleCheckInterval,
+		action:     settings.ExpiredRuleAction,
+		log:        log,
+	}
+}
+
+// Run periodically expires the alert rules of every organization whose expiration passed.
+func (s *RuleExpiryService) Run(ctx context.Context) error {
+	if s.interval <= 0 {
+		return nil
+	}
+	ticker := s.clock.Ticker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.expireOrgs(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (s *RuleExpiryService) expireOrgs(ctx context.Context) {
+	orgIDs, err := s.orgStore.GetOrgs(ctx)
+	if err != nil {
+		s.log.Error("Failed to get organizations to expire the alert rules of", "error", err)
+		return
+	}
+	for _, orgID := range orgIDs {
+		if err := s.expireOrg(ctx, orgID); err != nil {
+			s.log.Error("Failed to expire alert rules", "org", orgID, "error", err)
+		}
+	}
+}
+
+// expireOrg pauses or deletes the expired rules of the organization, and sends an alert for each of them. Expired rules
+// that are already paused are skipped, so a rule that is resumed is paused again until its expiration is changed.
+func (s *RuleExpiryService) expireOrg(ctx context.Context, orgID int64) error {
+	now := s.clock.Now()
+	rules, err := s.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID, ExpiredAt: &now})
+	if err != nil {
+		return err
+	}
+	var expired []*models.AlertRule
+	for _, rule := range rules {
+		if s.action == setting.ExpiredRuleActionPause && rule.IsPaused {
+			continue
+		}
+		expired = append(expired, rule)
+	}
+	if len(expired) == 0 {
+		return nil
+	}
+
+	err = s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if s.action == setting.ExpiredRuleActionDelete {
+			return s.alertRules.deleteRules(ctx, orgID, expired...)
+		}
+		updates := make([]models.UpdateRule, 0, len(expired))
+		for _, rule := range expired {
+			paused := models.CopyRule(rule)
+			paused.IsPaused = true
+			paused.Updated = now
+			updates = append(updates, models.UpdateRule{Existing: rule, New: *paused})
+		}
+		return s.ruleStore.UpdateAlertRules(ctx, updates)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range expired {
+		s.log.Info("Alert rule expired", "org", orgID, "rule_uid", rule.UID, "action", s.action)
+		s.sender.Send(ctx, rule.GetKey(), definitions.PostableAlerts{
+			PostableAlerts: []amv2.PostableAlert{expiredRuleAlert(rule, s.action, now)},
+		})
+	}
+	return nil
+}
+
+// expiredRuleAlert is the alert sent when an alert rule expires. It has the labels of the rule, so that it is routed
+// to the owners of the rule like its own alerts. Like the DatasourceNoData alert, it is defined as:
+// { alertname=AlertRuleExpired rulename=title } + { rule labelset }
+func expiredRuleAlert(rule *models.AlertRule, action string, now time.Time) amv2.PostableAlert {
+	labels := maps.Clone(rule.Labels)
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	maps.Copy(labels, models.NotificationSettingsToLabels(rule.NotificationSettings))
+	labels[model.AlertNameLabel] = ExpiredRuleAlertName
+	labels[state.Rulename] = rule.Title
+	labels[alertingModels.RuleUIDLabel] = rule.UID
+	labels[alertingModels.NamespaceUIDLabel] = rule.NamespaceUID
+
+	verb := "paused"
+	if action == setting.ExpiredRuleActionDelete {
+		verb = "deleted"
+	}
+	return amv2.PostableAlert{
+		Annotations: amv2.LabelSet{
+			"summary": fmt.Sprintf("The alert rule %q expired at %s and was %s.", rule.Title, rule.ExpiresAt.UTC().Format(time.RFC3339), verb),
+		},
+		StartsAt: strfmt.DateTime(now),
+		EndsAt:   strfmt.DateTime(now.Add(expiredRuleAlertDuration)),
+		Alert: amv2.Alert{
+			Labels: amv2.LabelSet(labels),
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeAlertsSender struct {
+	sent []definitions.PostableAlerts
+}
+
+func (s *fakeAlertsSender) Send(_ context.Context, _ models.AlertRuleKey, alerts definitions.PostableAlerts) {
+	s.sent = append(s.sent, alerts)
+}
+
+func TestRuleExpiryService(t *testing.T) {
+	orgID := int64(1)
+	setup := func(action string) (*RuleExpiryService, *FakeStore, *fakeAlertsSender) {
+		rules := NewFakeStore()
+		clk := clock.NewMock()
+		clk.Set(time.Unix(1000000, 0))
+		expired, notExpired := clk.Now().Add(-time.Minute), clk.Now().Add(time.Minute)
+		rules.PutRules(
+			models.AlertRule{OrgID: orgID, UID: "expired", Title: "expired", Labels: map[string]string{"team": "db"}, ExpiresAt: &expired},
+			models.AlertRule{OrgID: orgID, UID: "not-expired", Title: "not expired", ExpiresAt: &notExpired},
+			models.AlertRule{OrgID: orgID, UID: "permanent", Title: "permanent"},
+		)
+		sender := &fakeAlertsSender{}
+		return &RuleExpiryService{
+			ruleStore: rules,
+			alertRules: &AlertRuleService{
+				ruleStore:       rules,
+				provenanceStore: rules,
+				xact:            rules,
+				log:             log.NewNopLogger(),
+			},
+			sender: sender,
+			xact:   rules,
+			clock:  clk,
+			action: action,
+			log:    log.NewNopLogger(),
+		}, rules, sender
+	}
+
+	paused := func(rules []*models.AlertRule) map[string]bool {
+		result := make(map[string]bool, len(rules))
+		for _, r := range rules {
+			result[r.UID] = r.IsPaused
+		}
+		return result
+	}
+
+	t.Run("expired rules are paused once", func(t *testing.T) {
+		sut, rules, sender := setup(setting.ExpiredRuleActionPause)
+
+		require.NoError(t, sut.expireOrg(context.Background(), orgID))
+		require.NoError(t, sut.expireOrg(context.Background(), orgID))
+
+		require.Equal(t, map[string]bool{"expired": true, "not-expired": false, "permanent": false}, paused(rules.Rules(orgID)))
+		require.Len(t, sender.sent, 1, "the owners should be notified only once")
+	})
+
+	t.Run("expired rules are deleted", func(t *testing.T) {
+		sut, rules, sender := setup(setting.ExpiredRuleActionDelete)
+
+		require.NoError(t, sut.expireOrg(context.Background(), orgID))
+
+		require.Equal(t, map[string]bool{"not-expired": false, "permanent": false}, paused(rules.Rules(orgID)))
+		require.Len(t, sender.sent, 1)
+	})
+
+	t.Run("alert has the labels of the rule", func(t *testing.T) {
+		sut, _, sender := setup(setting.ExpiredRuleActionPause)
+
+		require.NoError(t, sut.expireOrg(context.Background(), orgID))
+
+		require.Len(t, sender.sent, 1)
+		require.Len(t, sender.sent[0].PostableAlerts, 1)
+		labels := sender.sent[0].PostableAlerts[0].Labels
+		require.Equal(t, ExpiredRuleAlertName, labels["alertname"])
+		require.Equal(t, "expired", labels["rulename"])
+		require.Equal(t, "db", labels["team"])
+		require.Contains(t, sender.sent[0].PostableAlerts[0].Annotations["summary"], "was paused")
+	})
+}
@@ -0,0 +1,108 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// GenerateRuleGroup replaces the rule group with one rule per row of the inventory, generated from the template rule.
+// The ${name} placeholders of the template are replaced with the columns of the row, as the placeholders of the alert
+// rule templates are with their parameters, e.g. "${service} is down". A string of a model that is only a placeholder,
+// e.g. "${threshold}", is replaced by a number if the value is one, so that thresholds can be set in the expressions.
+// The Go templates of the labels and of the annotations, e.g. "{{ $labels.instance }}", are kept for the evaluation.
+//
+// The rules of the existing group are matched by title, so that generating the group again from the same inventory
+// updates them rather than replacing them with new rules, and the rules of the rows that were removed are deleted.
+func (service *AlertRuleService) GenerateRuleGroup(ctx context.Context, userID int64, orgID int64, group models.AlertRuleGroup, tmpl models.AlertRule, inventory []map[string]string, provenance models.Provenance) (models.AlertRuleGroup, error) {
+	if len(inventory) == 0 {
+		return models.AlertRuleGroup{}, fmt.Errorf("%w: inventory is empty", models.ErrAlertRuleFailedValidation)
+	}
+	var result models.AlertRuleGroup
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		existing, err := service.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		if err != nil && !errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+			return err
+		}
+		if group.Interval == 0 {
+			group.Interval = existing.Interval
+		}
+		if group.Interval == 0 {
+			group.Interval = service.defaultIntervalSeconds
+		}
+		// The settings of the group that the template does not have are kept.
+		group.DataAvailabilityPeriod = existing.DataAvailabilityPeriod
+		group.DataAvailabilityDelay = existing.DataAvailabilityDelay
+		group.ShardAffinity = existing.ShardAffinity
+		group.IncidentHooks = existing.IncidentHooks
+		uids := make(map[string]string, len(existing.Rules))
+		for _, rule := range existing.Rules {
+			uids[rule.Title] = rule.UID
+		}
+
+		rows := make(map[string]int, len(inventory))
+		group.Rules = make([]models.AlertRule, 0, len(inventory))
+		for i, row := range inventory {
+			rule, err := expandRuleTemplate(tmpl, func(name string) (string, error) {
+				value, ok := row[name]
+				if !ok {
+					return "", fmt.Errorf("column '%s' is not in the inventory", name)
+				}
+				return value, nil
+			})
+			if err != nil {
+				return fmt.Errorf("%w: row %d of the inventory: %s", models.ErrAlertRuleFailedValidation, i+1, err)
+			}
+			if other, ok := rows[rule.Title]; ok {
+				return fmt.Errorf("%w: rows %d and %d of the inventory generate rules with the same title '%s'", models.ErrAlertRuleFailedValidation, other, i+1, rule.Title)
+			}
+			rows[rule.Title] = i + 1
+			if rule.UID == "" {
+				rule.UID = uids[rule.Title]
+			}
+			group.Rules = append(group.Rules, rule)
+		}
+		if err := service.ReplaceRuleGroup(ctx, orgID, group, userID, provenance); err != nil {
+			return err
+		}
+		result, err = service.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		return err
+	})
+	if err != nil {
+		return models.AlertRuleGroup{}, err
+	}
+	return result, nil
+}
+
+// ParseRuleInventoryCSV parses an inventory in CSV format for GenerateRuleGroup. The first record is the header with
+// the names of the columns.
+func ParseRuleInventoryCSV(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: inventory has no header", models.ErrAlertRuleFailedValidation)
+		}
+		return nil, fmt.Errorf("%w: invalid inventory: %s", models.ErrAlertRuleFailedValidation, err)
+	}
+	var inventory []map[string]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return inventory, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid inventory: %s", models.ErrAlertRuleFailedValidation, err)
+		}
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			row[name] = record[i]
+		}
+		inventory = append(inventory, row)
+	}
+}
@@ -0,0 +1,96 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestGenerateRuleGroup(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	group := models.AlertRuleGroup{Title: "services", FolderUID: "folder"}
+	tmpl := models.AlertRule{
+		Title:     "${service} error rate",
+		Condition: "B",
+		Data: []models.AlertQuery{
+			{
+				RefID:             "A",
+				DatasourceUID:     "prometheus-uid",
+				Model:             json.RawMessage(`{"refId": "A", "expr": "rate(errors_total{service=\"${service}\"}[5m])"}`),
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(600)},
+			},
+			{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         json.RawMessage(`{"refId": "B", "type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": ["${threshold}"]}}]}`),
+			},
+		},
+		Labels:       map[string]string{"team": "${team}"},
+		Annotations:  map[string]string{"summary": "Errors of ${service} are above ${threshold}", "description": "{{ $labels.instance }} is at {{ $value }}"},
+		NoDataState:  models.OK,
+		ExecErrState: models.OkErrState,
+	}
+
+	t.Run("should generate one rule per row of the inventory", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		inventory, err := ParseRuleInventoryCSV(strings.NewReader("service,team,threshold\ncheckout,payments,0.5\nsearch, discovery, 2\n"))
+		require.NoError(t, err)
+
+		generated, err := ruleService.GenerateRuleGroup(ctx, 0, orgID, group, tmpl, inventory, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, ruleService.defaultIntervalSeconds, generated.Interval)
+		require.Len(t, generated.Rules, 2)
+		rule := generated.Rules[1]
+		require.Equal(t, "search error rate", rule.Title)
+		require.Equal(t, map[string]string{"team": "discovery"}, rule.Labels)
+		require.Equal(t, "Errors of search are above 2", rule.Annotations["summary"])
+		require.Equal(t, "{{ $labels.instance }} is at {{ $value }}", rule.Annotations["description"], "the templates of the annotations should be kept")
+		require.JSONEq(t, `{"refId": "A", "expr": "rate(errors_total{service=\"search\"}[5m])", "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[0].Model))
+		require.JSONEq(t, `{"refId": "B", "type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [2]}}], "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[1].Model))
+	})
+
+	t.Run("should update the rules of the rows that are generated again", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		generated, err := ruleService.GenerateRuleGroup(ctx, 0, orgID, group, tmpl, []map[string]string{
+			{"service": "checkout", "team": "payments", "threshold": "0.5"},
+			{"service": "search", "team": "discovery", "threshold": "2"},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		regenerated, err := ruleService.GenerateRuleGroup(ctx, 0, orgID, group, tmpl, []map[string]string{
+			{"service": "checkout", "team": "payments", "threshold": "1"},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Len(t, regenerated.Rules, 1, "the rules of the removed rows should be deleted")
+		require.Equal(t, generated.Rules[0].UID, regenerated.Rules[0].UID)
+		require.Contains(t, string(regenerated.Rules[0].Data[1].Model), `"params":[1]`)
+	})
+
+	t.Run("should fail for an invalid inventory", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+
+		_, err := ruleService.GenerateRuleGroup(ctx, 0, orgID, group, tmpl, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		_, err = ruleService.GenerateRuleGroup(ctx, 0, orgID, group, tmpl, []map[string]string{{"service": "checkout"}}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "row 1")
+
+		_, err = ruleService.GenerateRuleGroup(ctx, 0, orgID, group, tmpl, []map[string]string{
+			{"service": "checkout", "team": "payments", "threshold": "1"},
+			{"service": "checkout", "team": "payments", "threshold": "2"},
+		}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "same title")
+
+		_, err = ParseRuleInventoryCSV(strings.NewReader("service,team\ncheckout\n"))
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+}
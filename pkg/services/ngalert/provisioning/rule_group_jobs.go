@@ -0,0 +1,309 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	ruleGroupJobsNamespace = "alerting.rule_group_jobs"
+	// ruleGroupJobWorkers is the number of jobs that run at the same time on an instance.
+	ruleGroupJobWorkers = 2
+	// ruleGroupJobQueueSize is the number of jobs that wait for a worker on an instance. Jobs are rejected if the queue
+	// is full.
+	ruleGroupJobQueueSize = 32
+	// ruleGroupJobStaleAfter is the time after which a job that is not done and whose progress is not updated is
+	// considered interrupted, e.g. because the instance that ran it was stopped.
+	ruleGroupJobStaleAfter = 15 * time.Minute
+	// ruleGroupJobRetention is the time for which the jobs are kept after they are done.
+	ruleGroupJobRetention = 24 * time.Hour
+)
+
+// ErrRuleGroupJobQueueFull is returned when a job cannot be started because too many jobs are waiting.
+var ErrRuleGroupJobQueueFull = errors.New("too many rule group jobs are waiting to run, try again later")
+
+type ruleGroupJobTask struct {
+	job   models.RuleGroupJob
+	group models.AlertRuleGroup
+	delta *store.GroupDelta
+	// version is the version of the group that the delta was calculated from.
+	version    models.RuleGroupVersion
+	user       identity.Requester
+	provenance models.Provenance
+}
+
+// RuleGroupJobService replaces rule groups whose deltas exceed the changes limit of a single update. The delta is
+// applied in the background, in transactions of at most the changes limit each, and the progress of the job is stored
+// so that it can be read from any instance. The rules changed by each transaction are checked to be the rules the
+// delta was calculated from, so a job fails with ErrAlertRuleVersionConflict if the group is changed concurrently
+// while it runs. A job that fails leaves the changes that are already committed in place, replacing the group again
+// completes it.
+//
+// Only the progress of the jobs is stored. The delta of a job is kept in the memory of the instance that started it,
+// so a job is not resumed if that instance is stopped, and is reported as failed once its progress is not updated for
+// ruleGroupJobStaleAfter.
+type RuleGroupJobService struct {
+	alertRules *AlertRuleService
+	kv         kvstore.KVStore
+	queue      chan ruleGroupJobTask
+	clock      clock.Clock
+	log        log.Logger
+}
+
+func NewRuleGroupJobService(alertRules *AlertRuleService, kv kvstore.KVStore, log log.Logger) *RuleGroupJobService {
+	return &RuleGroupJobService{
+		alertRules: alertRules,
+		kv:         kv,
+		queue:      make(chan ruleGroupJobTask, ruleGroupJobQueueSize),
+		clock:      clock.New(),
+		log:        log,
+	}
+}
+
+// Run runs the started jobs until the context is done, and deletes the jobs that are done after their retention.
+func (s *RuleGroupJobService) Run(ctx context.Context) error {
+	for i := 0; i < ruleGroupJobWorkers; i++ {
+		go func() {
+			for {
+				select {
+				case task := <-s.queue:
+					s.runJob(ctx, task)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	ticker := s.clock.Ticker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.deleteExpiredJobs(ctx)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// StartReplaceRuleGroup validates the replacement of the rule group and calculates its delta like
+// ReplaceRuleGroup, authorizes the changes by the user, and starts a job that applies the delta. The delta is not
+// limited in size.
+func (s *RuleGroupJobService) StartReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, user identity.Requester, provenance models.Provenance) (models.RuleGroupJob, error) {
+	group, err := s.alertRules.prepareRuleGroup(ctx, orgID, group)
+	if err != nil {
+		return models.RuleGroupJob{}, err
+	}
+	var delta *store.GroupDelta
+	var version models.RuleGroupVersion
+	err = s.alertRules.xact.InTransaction(ctx, func(ctx context.Context) error {
+		delta, err = s.alertRules.calcRuleGroupDelta(ctx, orgID, group, 0)
+		if err != nil {
+			return err
+		}
+		if err := s.alertRules.authorizeRuleChanges(ctx, user, delta); err != nil {
+			return err
+		}
+		version, err = s.alertRules.ruleGroupVersion(ctx, models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: group.FolderUID, RuleGroup: group.Title})
+		return err
+	})
+	if err != nil {
+		return models.RuleGroupJob{}, err
+	}
+
+	now := s.clock.Now()
+	job := models.RuleGroupJob{
+		UID:          util.GenerateShortUID(),
+		OrgID:        orgID,
+		NamespaceUID: group.FolderUID,
+		RuleGroup:    group.Title,
+		Status:       models.RuleGroupJobStatusPending,
+		Changes:      delta.Size(),
+		Created:      now,
+		Updated:      now,
+	}
+	if err := s.saveJob(ctx, job); err != nil {
+		return models.RuleGroupJob{}, err
+	}
+	select {
+	case s.queue <- ruleGroupJobTask{job: job, group: group, delta: delta, version: version, user: user, provenance: provenance}:
+	default:
+		if err := s.kvStore(orgID).Del(ctx, job.UID); err != nil {
+			s.log.Warn("Failed to delete rule group job that could not be started", "org", orgID, "job", job.UID, "error", err)
+		}
+		return models.RuleGroupJob{}, ErrRuleGroupJobQueueFull
+	}
+	return job, nil
+}
+
+// GetJob returns the rule group job of the organization with the given UID.
+func (s *RuleGroupJobService) GetJob(ctx context.Context, orgID int64, uid string) (models.RuleGroupJob, error) {
+	value, ok, err := s.kvStore(orgID).Get(ctx, uid)
+	if err != nil {
+		return models.RuleGroupJob{}, err
+	}
+	if !ok {
+		return models.RuleGroupJob{}, models.ErrRuleGroupJobNotFound
+	}
+	var job models.RuleGroupJob
+	if err := json.Unmarshal([]byte(value), &job); err != nil {
+		return models.RuleGroupJob{}, fmt.Errorf("failed to unmarshal rule group job: %w", err)
+	}
+	if !job.IsDone() && s.clock.Since(job.Updated) > ruleGroupJobStaleAfter {
+		job.Status = models.RuleGroupJobStatusFailed
+		job.Error = "the job was interrupted"
+	}
+	return job, nil
+}
+
+func (s *RuleGroupJobService) runJob(ctx context.Context, task ruleGroupJobTask) {
+	job := task.job
+	logger := s.log.New("org", job.OrgID, "job", job.UID, "folder_uid", job.NamespaceUID, "group", job.RuleGroup)
+	save := func() {
+		job.Updated = s.clock.Now()
+		if err := s.saveJob(ctx, job); err != nil {
+			logger.Error("Failed to save the progress of rule group job", "error", err)
+		}
+	}
+	job.Status = models.RuleGroupJobStatusRunning
+	save()
+
+	err := s.applyDelta(ctx, task, func(applied int) {
+		job.Applied += applied
+		save()
+	})
+	if err != nil {
+		logger.Error("Rule group job failed", "applied", job.Applied, "changes", job.Changes, "error", err)
+		job.Status = models.RuleGroupJobStatusFailed
+		job.Error = err.Error()
+	} else {
+		logger.Info("Rule group job succeeded", "changes", job.Changes)
+		job.Status = models.RuleGroupJobStatusSucceeded
+	}
+	save()
+}
+
+// applyDelta persists the delta of the task in chunks of at most the changes limit, each in its own transaction. The
+// groups of a chunk are locked before it is checked to apply to the rules the delta was calculated from, so that the
+// changes committed between the chunks are not overwritten.
+func (s *RuleGroupJobService) applyDelta(ctx context.Context, task ruleGroupJobTask, progress func(applied int)) error {
+	service := s.alertRules
+	orgID := task.job.OrgID
+	var userID int64
+	if task.user != nil {
+		userID, _ = identity.UserIdentifier(task.user.GetNamespacedID())
+	}
+	groupKey := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: task.group.FolderUID, RuleGroup: task.group.Title}
+	version := task.version
+	for rest := task.delta; rest != nil; {
+		var chunk *store.GroupDelta
+		chunk, rest = rest.Split(int(service.ruleGroupChangesLimit))
+		err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+			if err := service.ruleStore.LockRuleGroups(ctx, append(deltaGroupKeys(chunk), groupKey)...); err != nil {
+				return err
+			}
+			if err := s.checkChunkUnchanged(ctx, groupKey, version, chunk); err != nil {
+				return err
+			}
+			if err := service.persistDelta(ctx, orgID, chunk, userID, task.provenance); err != nil {
+				return err
+			}
+			var err error
+			if version, err = service.ruleGroupVersion(ctx, groupKey); err != nil {
+				return err
+			}
+			if rest != nil {
+				return nil
+			}
+			// Who manages the group is stored after the rules, so that it is not stored for a group without rules.
+			if err := service.setRuleGroupManagedBy(ctx, groupKey, task.group.ManagedBy); err != nil {
+				return err
+			}
+			service.publishAfterCommit(ctx, alertRuleGroupReplacedEvent(s.clock.Now(), task.delta, task.provenance))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		progress(chunk.Size())
+	}
+	return nil
+}
+
+// checkChunkUnchanged returns ErrAlertRuleVersionConflict if the group was changed since it had the given version, or
+// if a rule that the chunk updates or deletes was changed since the delta was calculated, e.g. a rule of another group
+// that is moved to the group. It must be called in a transaction in which the groups of the chunk are locked.
+func (s *RuleGroupJobService) checkChunkUnchanged(ctx context.Context, groupKey models.AlertRuleGroupKey, version models.RuleGroupVersion, chunk *store.GroupDelta) error {
+	current, err := s.alertRules.ruleGroupVersion(ctx, groupKey)
+	if err != nil {
+		return err
+	}
+	if current.String() != version.String() {
+		return ErrAlertRuleVersionConflict.Errorf("rule group '%s' was changed while the job was running", groupKey.RuleGroup)
+	}
+	existing := make([]*models.AlertRule, 0, len(chunk.Delete)+len(chunk.Update))
+	existing = append(existing, chunk.Delete...)
+	for _, update := range chunk.Update {
+		existing = append(existing, update.Existing)
+	}
+	for _, rule := range existing {
+		stored, err := s.alertRules.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: groupKey.OrgID, UID: rule.UID})
+		if err != nil {
+			if errors.Is(err, models.ErrAlertRuleNotFound) {
+				return ErrAlertRuleVersionConflict.Errorf("alert rule '%s' was deleted while the job was running", rule.UID)
+			}
+			return err
+		}
+		if stored.Version != rule.Version {
+			return ErrAlertRuleVersionConflict.Errorf("alert rule '%s' has version %d, not %d", rule.UID, stored.Version, rule.Version)
+		}
+	}
+	return nil
+}
+
+func (s *RuleGroupJobService) deleteExpiredJobs(ctx context.Context) {
+	all, err := kvstore.WithNamespace(s.kv, kvstore.AllOrganizations, ruleGroupJobsNamespace).GetAll(ctx)
+	if err != nil {
+		s.log.Error("Failed to read rule group jobs", "error", err)
+		return
+	}
+	for orgID, jobs := range all {
+		for uid, value := range jobs {
+			var job models.RuleGroupJob
+			if err := json.Unmarshal([]byte(value), &job); err != nil {
+				s.log.Warn("Failed to unmarshal rule group job", "org", orgID, "job", uid, "error", err)
+				continue
+			}
+			if s.clock.Since(job.Updated) < ruleGroupJobRetention {
+				continue
+			}
+			if err := s.kvStore(orgID).Del(ctx, uid); err != nil {
+				s.log.Warn("Failed to delete expired rule group job", "org", orgID, "job", uid, "error", err)
+			}
+		}
+	}
+}
+
+func (s *RuleGroupJobService) saveJob(ctx context.Context, job models.RuleGroupJob) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.kvStore(job.OrgID).Set(ctx, job.UID, string(value))
+}
+
+func (s *RuleGroupJobService) kvStore(orgID int64) *kvstore.NamespacedKVStore {
+	return kvstore.WithNamespace(s.kv, orgID, ruleGroupJobsNamespace)
+}
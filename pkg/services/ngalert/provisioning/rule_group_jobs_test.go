@@ -0,0 +1,140 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRuleGroupJobService(t *testing.T) {
+	var orgID int64 = 1
+
+	createService := func(t *testing.T) (*RuleGroupJobService, *AlertRuleService) {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		ruleService.ruleGroupChangesLimit = 2
+		return NewRuleGroupJobService(&ruleService, kvstore.NewFakeKVStore(), log.NewNopLogger()), &ruleService
+	}
+
+	waitForJob := func(t *testing.T, jobs *RuleGroupJobService, uid string) models.RuleGroupJob {
+		t.Helper()
+		var job models.RuleGroupJob
+		require.Eventually(t, func() bool {
+			var err error
+			job, err = jobs.GetJob(context.Background(), orgID, uid)
+			require.NoError(t, err)
+			return job.IsDone()
+		}, 5*time.Second, 10*time.Millisecond)
+		return job
+	}
+
+	t.Run("should apply a delta larger than the changes limit in chunks", func(t *testing.T) {
+		jobs, ruleService := createService(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go func() {
+			_ = jobs.Run(ctx)
+		}()
+
+		group := createDummyGroup("job-group", orgID)
+		group.Rules = append(group.Rules,
+			dummyRule("job-group-rule-2", orgID),
+			dummyRule("job-group-rule-3", orgID),
+			dummyRule("job-group-rule-4", orgID),
+			dummyRule("job-group-rule-5", orgID),
+		)
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupTooManyChangesBase)
+
+		job, err := jobs.StartReplaceRuleGroup(context.Background(), orgID, group, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, models.RuleGroupJobStatusPending, job.Status)
+		require.Equal(t, 5, job.Changes)
+
+		job = waitForJob(t, jobs, job.UID)
+		require.Equal(t, models.RuleGroupJobStatusSucceeded, job.Status)
+		require.Equal(t, 5, job.Applied)
+		require.Empty(t, job.Error)
+
+		stored, err := ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.Len(t, stored.Rules, 5)
+	})
+
+	t.Run("should reject an invalid group before the job is started", func(t *testing.T) {
+		jobs, _ := createService(t)
+		group := createDummyGroup("invalid-job-group", orgID)
+		group.Interval = 1
+
+		_, err := jobs.StartReplaceRuleGroup(context.Background(), orgID, group, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should authorize the changes by the user before the job is started", func(t *testing.T) {
+		jobs, ruleService := createService(t)
+		authz := &fakeRuleAccessControl{changeErr: errors.New("change denied")}
+		ruleService.authz = authz
+		group := createDummyGroup("denied-job-group", orgID)
+
+		_, err := jobs.StartReplaceRuleGroup(context.Background(), orgID, group, &user.SignedInUser{OrgID: orgID}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.changeErr)
+		require.Len(t, authz.changes, 1)
+		require.Empty(t, jobs.queue)
+	})
+
+	t.Run("should fail if the group is changed while the job is running", func(t *testing.T) {
+		jobs, ruleService := createService(t)
+		group := createDummyGroup("changed-job-group", orgID)
+		group.Rules = append(group.Rules,
+			dummyRule("changed-job-group-rule-2", orgID),
+			dummyRule("changed-job-group-rule-3", orgID),
+		)
+		for i := range group.Rules {
+			group.Rules[i].RuleGroup = group.Title
+		}
+		_, err := jobs.StartReplaceRuleGroup(context.Background(), orgID, group, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		task := <-jobs.queue
+
+		// The rule is created between the first and the second chunk of the delta.
+		concurrent := dummyRule("concurrent-rule", orgID)
+		concurrent.RuleGroup = group.Title
+		err = jobs.applyDelta(context.Background(), task, func(int) {
+			_, err := ruleService.CreateAlertRule(context.Background(), concurrent, models.ProvenanceAPI, 0)
+			require.NoError(t, err)
+		})
+		require.ErrorIs(t, err, ErrAlertRuleVersionConflict)
+
+		stored, err := ruleService.GetRuleGroup(context.Background(), orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.Len(t, stored.Rules, 3)
+	})
+
+	t.Run("should report a job that is not updated anymore as failed", func(t *testing.T) {
+		jobs, _ := createService(t)
+		mock := clock.NewMock()
+		jobs.clock = mock
+		job := models.RuleGroupJob{UID: "stale", OrgID: orgID, Status: models.RuleGroupJobStatusRunning, Updated: mock.Now()}
+		require.NoError(t, jobs.saveJob(context.Background(), job))
+
+		mock.Add(ruleGroupJobStaleAfter + time.Second)
+		job, err := jobs.GetJob(context.Background(), orgID, "stale")
+		require.NoError(t, err)
+		require.Equal(t, models.RuleGroupJobStatusFailed, job.Status)
+	})
+
+	t.Run("should return not found for unknown jobs", func(t *testing.T) {
+		jobs, _ := createService(t)
+		_, err := jobs.GetJob(context.Background(), orgID, "unknown")
+		require.ErrorIs(t, err, models.ErrRuleGroupJobNotFound)
+	})
+}
@@ -0,0 +1,177 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RuleMetadataPatch is a change of the labels and of the annotations of alert rules. The entries to add replace the
+// entries with the same names.
+type RuleMetadataPatch struct {
+	AddLabels         map[string]string
+	RemoveLabels      []string
+	AddAnnotations    map[string]string
+	RemoveAnnotations []string
+}
+
+// PatchRuleMetadata applies the patch to all the rules that the selector matches, in one transaction. The organization
+// of the selector is ignored, and so are its limit and page, and the selector must select the rules by folder, group,
+// dashboard, contact point or labels. It returns the number of rules that were changed. Rules are changed only if the
+// provenance of their group allows it, and the user must be able to update every changed rule. The changed rules are
+// validated as the rules of the rule groups that are written.
+func (service *AlertRuleService) PatchRuleMetadata(ctx context.Context, user identity.Requester, orgID int64, selector models.ListAlertRulesQuery, patch RuleMetadataPatch, provenance models.Provenance) (int, error) {
+	if len(patch.AddLabels) == 0 && len(patch.RemoveLabels) == 0 && len(patch.AddAnnotations) == 0 && len(patch.RemoveAnnotations) == 0 {
+		return 0, fmt.Errorf("%w: nothing to add or remove", ErrValidation)
+	}
+	if err := validateStringMapPatch("label", patch.AddLabels, patch.RemoveLabels); err != nil {
+		return 0, err
+	}
+	for name := range patch.AddLabels {
+		if name == "" {
+			return 0, fmt.Errorf("%w: label name cannot be empty", ErrValidation)
+		}
+		if _, ok := models.LabelsUserCannotSpecify[name]; ok {
+			return 0, fmt.Errorf("%w: system reserved label %s cannot be defined", ErrValidation, name)
+		}
+	}
+	if err := validateStringMapPatch("annotation", patch.AddAnnotations, patch.RemoveAnnotations); err != nil {
+		return 0, err
+	}
+	if len(selector.NamespaceUIDs) == 0 && selector.RuleGroup == "" && selector.RuleGroupPrefix == "" && selector.DashboardUID == "" &&
+		selector.ReceiverName == "" && len(selector.LabelMatchers) == 0 {
+		return 0, fmt.Errorf("%w: the rules to change must be selected", ErrValidation)
+	}
+	updated, err := service.patchRules(ctx, user, orgID, selector, provenance, func(rule *models.AlertRule) error {
+		rule.Labels = patchStringMap(rule.Labels, patch.AddLabels, patch.RemoveLabels)
+		rule.Annotations = patchStringMap(rule.Annotations, patch.AddAnnotations, patch.RemoveAnnotations)
+		if err := rule.SetDashboardAndPanelFromAnnotations(); err != nil {
+			return fmt.Errorf("%w: %s", ErrValidation, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	service.log.Info("Patched the labels and annotations of alert rules", "org", orgID, "count", updated)
+	return updated, nil
+}
+
+// PatchRuleLabels adds and removes labels of all the rules that the selector matches. See PatchRuleMetadata.
+func (service *AlertRuleService) PatchRuleLabels(ctx context.Context, user identity.Requester, orgID int64, selector models.ListAlertRulesQuery, add map[string]string, remove []string, provenance models.Provenance) (int, error) {
+	return service.PatchRuleMetadata(ctx, user, orgID, selector, RuleMetadataPatch{AddLabels: add, RemoveLabels: remove}, provenance)
+}
+
+// PatchRuleAnnotations adds and removes annotations of all the rules that the selector matches. See PatchRuleMetadata.
+func (service *AlertRuleService) PatchRuleAnnotations(ctx context.Context, user identity.Requester, orgID int64, selector models.ListAlertRulesQuery, add map[string]string, remove []string, provenance models.Provenance) (int, error) {
+	return service.PatchRuleMetadata(ctx, user, orgID, selector, RuleMetadataPatch{AddAnnotations: add, RemoveAnnotations: remove}, provenance)
+}
+
+// patchRules applies the patch to copies of the rules that the selector matches and updates the rules that it changes.
+// The changes are validated and authorized per rule group, like the changes of the rule groups themselves.
+func (service *AlertRuleService) patchRules(ctx context.Context, user identity.Requester, orgID int64, selector models.ListAlertRulesQuery, provenance models.Provenance, patch func(rule *models.AlertRule) error) (int, error) {
+	selector.OrgID = orgID
+	selector.Limit, selector.Page = 0, 0
+	updated := 0
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		rules, err := service.ruleStore.ListAlertRules(ctx, &selector)
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+
+		// The groups are authorized in the order of the rules, so that the first denied change is always the same.
+		var keys []models.AlertRuleGroupKey
+		deltas := make(map[models.AlertRuleGroupKey]*store.GroupDelta)
+		updates := make([]models.UpdateRule, 0, len(rules))
+		for _, rule := range rules {
+			changed := models.CopyRule(rule)
+			if err := patch(changed); err != nil {
+				return err
+			}
+			delta := store.CalculateRuleDelta(rule, changed)
+			if len(delta.Diff) == 0 {
+				continue
+			}
+			if storedProvenance := provenances[rule.UID]; !canUpdateProvenanceInRuleGroup(storedProvenance, provenance) {
+				return provenanceNotAllowed(ctx, service.provenanceStore, "update", rule, storedProvenance, provenance)
+			}
+			changed.Updated = time.Now()
+			key := rule.GetGroupKey()
+			if _, ok := deltas[key]; !ok {
+				keys = append(keys, key)
+				deltas[key] = &store.GroupDelta{GroupKey: key}
+			}
+			deltas[key].Update = append(deltas[key].Update, delta)
+			updates = append(updates, models.UpdateRule{Existing: rule, New: *changed})
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		for _, key := range keys {
+			if err := service.validateDelta(ctx, deltas[key]); err != nil {
+				return err
+			}
+		}
+
+		if service.authz != nil {
+			for _, key := range keys {
+				delta := deltas[key]
+				group, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+					OrgID:         orgID,
+					NamespaceUIDs: []string{key.NamespaceUID},
+					RuleGroup:     key.RuleGroup,
+				})
+				if err != nil {
+					return fmt.Errorf("failed to list alert rules: %w", err)
+				}
+				delta.AffectedGroups = map[models.AlertRuleGroupKey]models.RulesGroup{key: group}
+				if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+					return err
+				}
+			}
+		}
+		if err := service.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
+			return fmt.Errorf("failed to update alert rules: %w", err)
+		}
+		updated = len(updates)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+func validateStringMapPatch(kind string, add map[string]string, remove []string) error {
+	for _, name := range remove {
+		if _, ok := add[name]; ok {
+			return fmt.Errorf("%w: %s '%s' is both added and removed", ErrValidation, kind, name)
+		}
+	}
+	return nil
+}
+
+// patchStringMap returns a copy of the map with the entries to add set and the keys to remove deleted.
+func patchStringMap(m map[string]string, add map[string]string, remove []string) map[string]string {
+	if m == nil && len(add) == 0 {
+		return nil
+	}
+	result := maps.Clone(m)
+	if result == nil {
+		result = make(map[string]string, len(add))
+	}
+	maps.Copy(result, add)
+	for _, key := range remove {
+		delete(result, key)
+	}
+	return result
+}
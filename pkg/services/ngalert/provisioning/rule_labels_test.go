@@ -0,0 +1,170 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestPatchRuleLabels(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	requester := &user.SignedInUser{OrgID: orgID}
+
+	setup := func(t *testing.T) AlertRuleService {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		for _, rule := range []models.AlertRule{
+			createTestRule("CPU usage", "platform", orgID, "infra"),
+			createTestRule("Memory usage", "platform", orgID, "infra"),
+			createTestRule("Error rate", "checkout", orgID, "services"),
+		} {
+			rule.Labels = map[string]string{"owner": "ops", "severity": "warning"}
+			rule.Annotations = map[string]string{"runbook": "https://runbooks"}
+			_, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceAPI, 0)
+			require.NoError(t, err)
+		}
+		return ruleService
+	}
+	all := models.ListAlertRulesQuery{NamespaceUIDs: []string{"infra", "services"}}
+	matcher := func(t *testing.T, name, value string) labels.Matchers {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+		require.NoError(t, err)
+		return labels.Matchers{m}
+	}
+
+	t.Run("should change the labels of the matching rules", func(t *testing.T) {
+		ruleService := setup(t)
+
+		updated, err := ruleService.PatchRuleLabels(ctx, requester, orgID, models.ListAlertRulesQuery{NamespaceUIDs: []string{"infra"}},
+			map[string]string{"team": "platform"}, []string{"owner"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, 2, updated)
+
+		rules, _, err := ruleService.GetAlertRules(ctx, nil, models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		for _, rule := range rules {
+			if rule.NamespaceUID == "infra" {
+				require.Equal(t, map[string]string{"team": "platform", "severity": "warning"}, rule.Labels)
+			} else {
+				require.Equal(t, map[string]string{"owner": "ops", "severity": "warning"}, rule.Labels)
+			}
+		}
+
+		updated, err = ruleService.PatchRuleLabels(ctx, requester, orgID, models.ListAlertRulesQuery{LabelMatchers: matcher(t, "team", "platform")},
+			map[string]string{"team": "platform"}, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Zero(t, updated, "rules that already have the labels should not be updated")
+	})
+
+	t.Run("should change the annotations of the matching rules", func(t *testing.T) {
+		ruleService := setup(t)
+
+		updated, err := ruleService.PatchRuleAnnotations(ctx, requester, orgID, models.ListAlertRulesQuery{RuleGroup: "checkout"},
+			map[string]string{"runbook": "https://runbooks/checkout"}, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, 1, updated)
+
+		rules, _, err := ruleService.GetAlertRules(ctx, nil, models.ListAlertRulesQuery{OrgID: orgID, RuleGroup: "checkout"})
+		require.NoError(t, err)
+		require.Equal(t, "https://runbooks/checkout", rules[0].Annotations["runbook"])
+	})
+
+	t.Run("should fail for an empty patch", func(t *testing.T) {
+		ruleService := setup(t)
+
+		_, err := ruleService.PatchRuleLabels(ctx, requester, orgID, models.ListAlertRulesQuery{}, nil, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.PatchRuleLabels(ctx, requester, orgID, models.ListAlertRulesQuery{}, map[string]string{"team": "a"}, []string{"team"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should fail for an empty selector", func(t *testing.T) {
+		ruleService := setup(t)
+
+		_, err := ruleService.PatchRuleLabels(ctx, requester, orgID, models.ListAlertRulesQuery{}, map[string]string{"team": "platform"}, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should validate the changed rules", func(t *testing.T) {
+		ruleService := setup(t)
+
+		_, err := ruleService.PatchRuleLabels(ctx, requester, orgID, all, map[string]string{models.AutogeneratedRouteLabel: "true"}, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.PatchRuleLabels(ctx, requester, orgID, all, map[string]string{"": "empty"}, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = ruleService.PatchRuleAnnotations(ctx, requester, orgID, all, map[string]string{"summary": "{{ .Invalid"}, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		ruleService.ruleLimits.MaxSizeBytes = 10
+		_, err = ruleService.PatchRuleLabels(ctx, requester, orgID, all, map[string]string{"team": "platform"}, nil, models.ProvenanceAPI)
+		require.Error(t, err)
+
+		rules, _, err := ruleService.GetAlertRules(ctx, nil, models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		for _, rule := range rules {
+			require.NotContains(t, rule.Annotations, "summary")
+			require.NotContains(t, rule.Labels, "team")
+		}
+	})
+
+	t.Run("should set the dashboard and panel of the rules from their annotations", func(t *testing.T) {
+		ruleService := setup(t)
+
+		_, err := ruleService.PatchRuleAnnotations(ctx, requester, orgID, models.ListAlertRulesQuery{RuleGroup: "checkout"},
+			map[string]string{models.DashboardUIDAnnotation: "dashboard", models.PanelIDAnnotation: "2"}, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		rules, _, err := ruleService.GetAlertRules(ctx, nil, models.ListAlertRulesQuery{OrgID: orgID, RuleGroup: "checkout"})
+		require.NoError(t, err)
+		require.Equal(t, "dashboard", *rules[0].DashboardUID)
+		require.Equal(t, int64(2), *rules[0].PanelID)
+
+		_, err = ruleService.PatchRuleAnnotations(ctx, requester, orgID, models.ListAlertRulesQuery{RuleGroup: "checkout"},
+			map[string]string{models.PanelIDAnnotation: "not a number"}, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should not change rules with another provenance", func(t *testing.T) {
+		ruleService := setup(t)
+
+		_, err := ruleService.PatchRuleLabels(ctx, requester, orgID, all, map[string]string{"team": "platform"}, nil, models.ProvenanceFile)
+		require.Error(t, err)
+
+		rules, _, err := ruleService.GetAlertRules(ctx, nil, models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		for _, rule := range rules {
+			require.NotContains(t, rule.Labels, "team")
+		}
+	})
+
+	t.Run("should authorize the changes of every group in one transaction", func(t *testing.T) {
+		ruleService := setup(t)
+		authz := &fakeRuleAccessControl{}
+		ruleService.authz = authz
+
+		_, err := ruleService.PatchRuleLabels(ctx, requester, orgID, all, map[string]string{"team": "platform"}, nil, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Len(t, authz.changes, 2)
+		for _, delta := range authz.changes {
+			require.Contains(t, delta.AffectedGroups, delta.GroupKey)
+			require.NotEmpty(t, delta.Update)
+		}
+
+		authz.changeErr = errors.New("update denied")
+		_, err = ruleService.PatchRuleLabels(ctx, requester, orgID, all, map[string]string{"team": "alerting"}, nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.changeErr)
+
+		rules, _, err := ruleService.GetAlertRules(ctx, nil, models.ListAlertRulesQuery{OrgID: orgID})
+		require.NoError(t, err)
+		for _, rule := range rules {
+			require.Equal(t, "platform", rule.Labels["team"], "no rule should be changed")
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ruleMergeIgnoredFields are the fields that are not compared when rules are merged: the fields that are set by the
+// server, the fields that are set on the whole group and merged separately, and the index of the rule, as the merged
+// rules are in the given order.
+var ruleMergeIgnoredFields = append(store.AlertRuleFieldsToIgnoreInDiff[:],
+	"OrgID", "NamespaceUID", "RuleGroup", "RuleGroupIndex", "DashboardUID", "PanelID", "BakeUntil",
+	"IntervalSeconds", "DataAvailabilityPeriod", "DataAvailabilityDelay", "ShardAffinity", "IncidentHooks",
+)
+
+// mergeRuleGroup returns the group that results from a three-way merge of the given group, the stored group, and the
+// group at the base version of the given one. The versions of the rules at the base version are read from their
+// history.
+//
+// A rule keeps the changes of the side that changed it: the given rule if it was not changed concurrently, the stored
+// rule if the given one is the same as at the base version. The rules added concurrently are kept after the given
+// rules, and the rules deleted on either side are deleted if the other side did not change them, except the rules
+// deleted concurrently, whose versions are deleted with them and which stay deleted. The settings of the
+// group are merged the same way. Changes of the same rule or of the settings of the group on both sides, which are not
+// the same, are conflicts, and the merge fails with ErrRuleGroupMergeConflict listing all of them.
+func (service *AlertRuleService) mergeRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup) (models.AlertRuleGroup, error) {
+	stored, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{group.FolderUID},
+		RuleGroup:     group.Title,
+	})
+	if err != nil {
+		return models.AlertRuleGroup{}, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	stored.SortByGroupIndex()
+	storedByUID := make(map[string]*models.AlertRule, len(stored))
+	for _, r := range stored {
+		storedByUID[r.UID] = r
+	}
+	// A group without rules changes only the settings of the group, see calcDelta.
+	if group.Rules == nil {
+		group.Rules = withoutNilAlertRules(stored)
+	}
+	base := group.BaseVersion
+	getBaseRule := func(uid string) (*models.AlertRule, error) {
+		versions, err := service.ruleStore.GetAlertRuleVersions(ctx, orgID, uid)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range versions {
+			if v.Version == base[uid] {
+				rule := v.AlertRule()
+				return &rule, nil
+			}
+		}
+		return nil, nil
+	}
+
+	var conflicts []string
+	merged := make([]models.AlertRule, 0, len(group.Rules))
+	given := make(map[string]struct{}, len(group.Rules))
+	// The indexes of the given rules are set and validated before the merge, and the merged rules are renumbered.
+	rules := slices.Clone(group.Rules)
+	models.SortAlertRulesByGroupIndex(rules)
+	for _, rule := range rules {
+		if rule.UID != "" {
+			given[rule.UID] = struct{}{}
+		}
+		baseVersion, inBase := base[rule.UID]
+		current, inStored := storedByUID[rule.UID]
+		switch {
+		case rule.UID == "" || (!inBase && !inStored):
+			merged = append(merged, rule)
+		case !inBase:
+			if !sameRuleContent(*current, rule) {
+				conflicts = append(conflicts, fmt.Sprintf("alert rule '%s' was added concurrently", rule.UID))
+				continue
+			}
+			merged = append(merged, rule)
+		case inStored && current.Version == baseVersion:
+			merged = append(merged, rule)
+		default:
+			baseRule, err := getBaseRule(rule.UID)
+			if err != nil {
+				return models.AlertRuleGroup{}, err
+			}
+			if baseRule == nil && !inStored {
+				// The versions of a rule are deleted with it, so the rule deleted concurrently stays deleted even if it
+				// was changed.
+				continue
+			}
+			if baseRule == nil {
+				conflicts = append(conflicts, fmt.Sprintf("version %d of alert rule '%s' is not stored anymore", baseVersion, rule.UID))
+				continue
+			}
+			changed := !sameRuleContent(*baseRule, rule)
+			switch {
+			case !inStored && changed:
+				conflicts = append(conflicts, fmt.Sprintf("alert rule '%s' was changed but it was deleted from the group concurrently", rule.UID))
+			case !inStored:
+				// The rule was deleted concurrently, and it stays deleted.
+			case !changed:
+				merged = append(merged, *current)
+			case sameRuleContent(*baseRule, *current) || sameRuleContent(*current, rule):
+				// Only the settings of the group were changed concurrently, which are merged below, or both sides
+				// made the same changes.
+				merged = append(merged, rule)
+			default:
+				conflicts = append(conflicts, fmt.Sprintf("alert rule '%s' was changed concurrently", rule.UID))
+			}
+		}
+	}
+
+	deleted := make([]string, 0, len(base))
+	for uid := range base {
+		if _, ok := given[uid]; !ok {
+			deleted = append(deleted, uid)
+		}
+	}
+	slices.Sort(deleted)
+	for _, uid := range deleted {
+		current, ok := storedByUID[uid]
+		if !ok || current.Version == base[uid] {
+			continue
+		}
+		baseRule, err := getBaseRule(uid)
+		if err != nil {
+			return models.AlertRuleGroup{}, err
+		}
+		if baseRule == nil || !sameRuleContent(*baseRule, *current) {
+			conflicts = append(conflicts, fmt.Sprintf("alert rule '%s' was deleted but it was changed concurrently", uid))
+		}
+	}
+	for _, current := range stored {
+		_, inBase := base[current.UID]
+		_, isGiven := given[current.UID]
+		if !inBase && !isGiven {
+			merged = append(merged, *current)
+		}
+	}
+
+	if len(stored) > 0 {
+		givenSettings := groupSettings(models.AlertRule{
+			IntervalSeconds:        group.Interval,
+			DataAvailabilityPeriod: group.DataAvailabilityPeriod,
+			DataAvailabilityDelay:  group.DataAvailabilityDelay,
+			ShardAffinity:          group.ShardAffinity,
+			IncidentHooks:          group.IncidentHooks,
+		})
+		storedSettings := groupSettings(*stored[0])
+		if !sameGroupSettings(givenSettings, storedSettings) {
+			// The settings are the same on all the rules of the group, so they are read from any rule of the base.
+			var baseRule *models.AlertRule
+			uids := make([]string, 0, len(base))
+			for uid := range base {
+				uids = append(uids, uid)
+			}
+			slices.Sort(uids)
+			for _, uid := range uids {
+				if baseRule, err = getBaseRule(uid); err != nil {
+					return models.AlertRuleGroup{}, err
+				}
+				if baseRule != nil {
+					break
+				}
+			}
+			switch {
+			case baseRule != nil && sameGroupSettings(givenSettings, *baseRule):
+				group.Interval = storedSettings.IntervalSeconds
+				group.DataAvailabilityPeriod = storedSettings.DataAvailabilityPeriod
+				group.DataAvailabilityDelay = storedSettings.DataAvailabilityDelay
+				group.ShardAffinity = storedSettings.ShardAffinity
+				group.IncidentHooks = storedSettings.IncidentHooks
+			case baseRule != nil && sameGroupSettings(storedSettings, *baseRule):
+				// Only the given group changed the settings.
+			default:
+				conflicts = append(conflicts, "the settings of the group were changed concurrently")
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return models.AlertRuleGroup{}, MakeErrRuleGroupMergeConflict(conflicts)
+	}
+	for i := range merged {
+		merged[i].RuleGroupIndex = i + 1
+	}
+	group.Rules = merged
+	// The versions of the rules are resolved by the merge.
+	group.BaseVersion = nil
+	group.CheckVersions = false
+	return group, nil
+}
+
+// sameRuleContent returns true if the rules are the same, apart from the fields that are not merged.
+func sameRuleContent(a, b models.AlertRule) bool {
+	return len(a.Diff(&b, ruleMergeIgnoredFields...)) == 0
+}
+
+// groupSettings returns a rule that has only the settings of the group of the given rule.
+func groupSettings(r models.AlertRule) models.AlertRule {
+	return models.AlertRule{
+		IntervalSeconds:        r.IntervalSeconds,
+		DataAvailabilityPeriod: r.DataAvailabilityPeriod,
+		DataAvailabilityDelay:  r.DataAvailabilityDelay,
+		ShardAffinity:          r.ShardAffinity,
+		IncidentHooks:          r.IncidentHooks,
+	}
+}
+
+// sameGroupSettings returns true if the rules have the same settings of their group.
+func sameGroupSettings(a, b models.AlertRule) bool {
+	a, b = groupSettings(a), groupSettings(b)
+	return len(a.Diff(&b)) == 0
+}
@@ -0,0 +1,109 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestMergeRuleGroup(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	// setup creates a group with the rules a, b and c, and returns it as it is read, with its version as base version.
+	setup := func(t *testing.T) (AlertRuleService, models.AlertRuleGroup) {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		group := createDummyGroup("my-cool-group", orgID)
+		group.Rules = []models.AlertRule{dummyRule("a", orgID), dummyRule("b", orgID), dummyRule("c", orgID)}
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		read, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		read.BaseVersion = models.NewRuleGroupVersion(read.Rules)
+		return ruleService, read
+	}
+	updateConcurrently := func(t *testing.T, ruleService AlertRuleService, rule models.AlertRule, update func(*models.AlertRule)) {
+		t.Helper()
+		update(&rule)
+		_, err := ruleService.UpdateAlertRule(ctx, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+	}
+	titles := func(t *testing.T, ruleService AlertRuleService) []string {
+		t.Helper()
+		group, err := ruleService.GetRuleGroup(ctx, orgID, "my-namespace", "my-cool-group")
+		require.NoError(t, err)
+		result := make([]string, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			result = append(result, rule.Title)
+		}
+		return result
+	}
+
+	t.Run("should keep the concurrent changes of the other rules", func(t *testing.T) {
+		ruleService, group := setup(t)
+		updateConcurrently(t, ruleService, group.Rules[1], func(r *models.AlertRule) { r.Title = "b2" })
+		_, err := ruleService.CreateAlertRule(ctx, dummyRule("d", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		group.Rules[0].Title = "a2"
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		require.Equal(t, []string{"a2", "b2", "c", "d"}, titles(t, ruleService))
+	})
+
+	t.Run("should keep the rules deleted concurrently deleted", func(t *testing.T) {
+		ruleService, group := setup(t)
+		require.NoError(t, ruleService.DeleteAlertRule(ctx, orgID, group.Rules[2].UID, models.ProvenanceAPI))
+
+		group.Rules[0].Title = "a2"
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		require.Equal(t, []string{"a2", "b"}, titles(t, ruleService))
+	})
+
+	t.Run("should delete the rules that were not changed concurrently", func(t *testing.T) {
+		ruleService, group := setup(t)
+		updateConcurrently(t, ruleService, group.Rules[1], func(r *models.AlertRule) { r.Title = "b2" })
+
+		group.Rules = group.Rules[1:2]
+		group.Rules[0].RuleGroupIndex = 1
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		require.Equal(t, []string{"b2"}, titles(t, ruleService))
+	})
+
+	t.Run("should keep the settings of the group changed concurrently", func(t *testing.T) {
+		ruleService, group := setup(t)
+		require.NoError(t, ruleService.UpdateRuleGroup(ctx, nil, orgID, group.FolderUID, group.Title, 120, models.ProvenanceAPI))
+
+		group.Rules[0].Title = "a2"
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		stored, err := ruleService.GetRuleGroup(ctx, orgID, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.EqualValues(t, 120, stored.Interval)
+		require.Equal(t, "a2", stored.Rules[0].Title)
+	})
+
+	t.Run("should report the conflicting changes", func(t *testing.T) {
+		ruleService, group := setup(t)
+		updateConcurrently(t, ruleService, group.Rules[0], func(r *models.AlertRule) { r.Title = "a2" })
+		updateConcurrently(t, ruleService, group.Rules[2], func(r *models.AlertRule) { r.Labels = map[string]string{"team": "a"} })
+
+		group.Rules[0].Title = "a3"
+		group.Rules = group.Rules[:2]
+		err := ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrRuleGroupMergeConflict)
+		require.ErrorContains(t, err, "alert rule '"+group.Rules[0].UID+"' was changed concurrently")
+		require.ErrorContains(t, err, "was deleted but it was changed concurrently")
+		require.Equal(t, []string{"a2", "b", "c"}, titles(t, ruleService))
+	})
+
+	t.Run("should not report the same changes on both sides", func(t *testing.T) {
+		ruleService, group := setup(t)
+		updateConcurrently(t, ruleService, group.Rules[0], func(r *models.AlertRule) { r.Title = "a2" })
+
+		group.Rules[0].Title = "a2"
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI))
+		require.Equal(t, []string{"a2", "b", "c"}, titles(t, ruleService))
+	})
+}
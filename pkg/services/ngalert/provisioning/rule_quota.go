@@ -0,0 +1,103 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+// QuotaLimit is the alert rule quota of a scope.
+type QuotaLimit struct {
+	Scope quota.Scope
+	// Limit is the maximum number of rules in the scope, negative if there is none.
+	Limit int64
+	// Used is the number of rules that count towards the quota in the scope.
+	Used int64
+	// Headroom is the number of rules that can still be created in the scope, negative if there is no limit.
+	Headroom int64
+}
+
+// RuleGroupCount is the number of rules of a rule group.
+type RuleGroupCount struct {
+	models.AlertRuleGroupKey
+	Count int64
+}
+
+// QuotaStatus is the usage of the alert rule quotas of an organization.
+type QuotaStatus struct {
+	// Count is the number of rules of the organization, whether or not they count towards the quotas.
+	Count int64
+	// Limits are the quotas of the scopes that apply to the organization. It is empty if quotas are disabled.
+	Limits []QuotaLimit
+	// Exempt is the limit of the rules whose provenance is exempt from the quotas. It is nil if no provenance is.
+	Exempt *QuotaLimit
+	// Groups are the numbers of rules of the rule groups of the organization, ordered by folder and group.
+	Groups []RuleGroupCount
+}
+
+// GetQuotaStatus returns the usage of the alert rule quotas of the organization, so that a large change can be checked
+// against the quotas before it is made rather than fail when it reaches one of them.
+func (service *AlertRuleService) GetQuotaStatus(ctx context.Context, orgID int64) (QuotaStatus, error) {
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return QuotaStatus{}, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	counts := make(map[models.AlertRuleGroupKey]int64)
+	for _, rule := range rules {
+		counts[rule.GetGroupKey()]++
+	}
+	status := QuotaStatus{
+		Count:  int64(len(rules)),
+		Groups: make([]RuleGroupCount, 0, len(counts)),
+	}
+	for key, count := range counts {
+		status.Groups = append(status.Groups, RuleGroupCount{AlertRuleGroupKey: key, Count: count})
+	}
+	sort.Slice(status.Groups, func(i, j int) bool {
+		if status.Groups[i].NamespaceUID != status.Groups[j].NamespaceUID {
+			return status.Groups[i].NamespaceUID < status.Groups[j].NamespaceUID
+		}
+		return status.Groups[i].RuleGroup < status.Groups[j].RuleGroup
+	})
+
+	for _, scope := range []struct {
+		scope quota.Scope
+		id    int64
+	}{{quota.GlobalScope, 0}, {quota.OrgScope, orgID}} {
+		quotas, err := service.quotas.GetQuotasByScope(ctx, scope.scope, scope.id)
+		if err != nil {
+			if errors.Is(err, quota.ErrDisabled) {
+				break
+			}
+			return QuotaStatus{}, fmt.Errorf("failed to get the %s alert rule quota: %w", scope.scope, err)
+		}
+		for _, q := range quotas {
+			if q.Service != string(models.QuotaTargetSrv) || q.Target != string(models.QuotaTarget) {
+				continue
+			}
+			status.Limits = append(status.Limits, newQuotaLimit(scope.scope, q.Limit, q.Used))
+		}
+	}
+
+	if len(service.quotaExemptProvenances) > 0 {
+		count, err := service.ruleStore.CountByProvenances(ctx, orgID, service.quotaExemptProvenances...)
+		if err != nil {
+			return QuotaStatus{}, fmt.Errorf("failed to count quota exempt alert rules: %w", err)
+		}
+		exempt := newQuotaLimit(quota.OrgScope, service.quotaExemptRulesLimit, count)
+		status.Exempt = &exempt
+	}
+	return status, nil
+}
+
+func newQuotaLimit(scope quota.Scope, limit, used int64) QuotaLimit {
+	headroom := int64(-1)
+	if limit >= 0 {
+		headroom = max(limit-used, 0)
+	}
+	return QuotaLimit{Scope: scope, Limit: limit, Used: used, Headroom: headroom}
+}
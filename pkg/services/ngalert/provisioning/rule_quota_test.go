@@ -0,0 +1,84 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+func TestGetQuotaStatus(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	setup := func(t *testing.T, quotas *MockQuotaChecker) AlertRuleService {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		quotas.EXPECT().LimitOK()
+		ruleService.quotas = quotas
+		for _, rule := range []models.AlertRule{
+			createTestRule("CPU usage", "platform", orgID, "infra"),
+			createTestRule("Memory usage", "platform", orgID, "infra"),
+			createTestRule("Error rate", "checkout", orgID, "services"),
+		} {
+			_, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceAPI, 0)
+			require.NoError(t, err)
+		}
+		_, err := ruleService.CreateAlertRule(ctx, createTestRule("Other org", "platform", 2, "infra"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		return ruleService
+	}
+
+	t.Run("should return the limits of the scopes and the counts of the groups", func(t *testing.T) {
+		quotas := &MockQuotaChecker{}
+		quotas.EXPECT().GetQuotasByScope(mock.Anything, quota.GlobalScope, int64(0)).Return([]quota.QuotaDTO{
+			{Service: string(models.QuotaTargetSrv), Target: string(models.QuotaTarget), Limit: -1, Used: 4},
+			{Service: "dashboards", Target: "dashboard", Limit: 10, Used: 1},
+		}, nil)
+		quotas.EXPECT().GetQuotasByScope(mock.Anything, quota.OrgScope, orgID).Return([]quota.QuotaDTO{
+			{Service: string(models.QuotaTargetSrv), Target: string(models.QuotaTarget), Limit: 5, Used: 3},
+		}, nil)
+		ruleService := setup(t, quotas)
+
+		status, err := ruleService.GetQuotaStatus(ctx, orgID)
+		require.NoError(t, err)
+		require.Equal(t, QuotaStatus{
+			Count: 3,
+			Limits: []QuotaLimit{
+				{Scope: quota.GlobalScope, Limit: -1, Used: 4, Headroom: -1},
+				{Scope: quota.OrgScope, Limit: 5, Used: 3, Headroom: 2},
+			},
+			Groups: []RuleGroupCount{
+				{AlertRuleGroupKey: models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "infra", RuleGroup: "platform"}, Count: 2},
+				{AlertRuleGroupKey: models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: "services", RuleGroup: "checkout"}, Count: 1},
+			},
+		}, status)
+	})
+
+	t.Run("should return no limits if quotas are disabled", func(t *testing.T) {
+		quotas := &MockQuotaChecker{}
+		quotas.EXPECT().GetQuotasByScope(mock.Anything, mock.Anything, mock.Anything).Return(nil, quota.ErrDisabled)
+		ruleService := setup(t, quotas)
+
+		status, err := ruleService.GetQuotaStatus(ctx, orgID)
+		require.NoError(t, err)
+		require.Empty(t, status.Limits)
+		require.Equal(t, int64(3), status.Count)
+	})
+
+	t.Run("should return the limit of the rules exempt from the quotas", func(t *testing.T) {
+		quotas := &MockQuotaChecker{}
+		quotas.EXPECT().GetQuotasByScope(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+		ruleService := setup(t, quotas)
+		ruleService.quotaExemptProvenances = []models.Provenance{models.ProvenanceAPI}
+		ruleService.quotaExemptRulesLimit = 10
+
+		status, err := ruleService.GetQuotaStatus(ctx, orgID)
+		require.NoError(t, err)
+		require.Equal(t, &QuotaLimit{Scope: quota.OrgScope, Limit: 10, Used: 3, Headroom: 7}, status.Exempt)
+	})
+}
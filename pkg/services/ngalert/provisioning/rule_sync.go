@@ -0,0 +1,480 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// ruleSyncNamespace stores the state of the sync, so that every instance reports the same status, and knows the
+	// groups that were synced and their versions whichever instance synced them.
+	ruleSyncNamespace = "alerting.rule_sync"
+	// ruleSyncStateKey is the key of the state of the sync of an organization.
+	ruleSyncStateKey = "state"
+	// ruleSyncSourceKey is the key of the result of the last read of the source, which is stored without organization.
+	ruleSyncSourceKey = "source"
+	// ruleSyncLockName is the name of the server lock of the sync.
+	ruleSyncLockName = "alerting rule sync"
+	// ruleSyncChangeInterval is the shortest time between a sync and a sync triggered by a change of the source, so that
+	// the instances that see the same change do not all sync it.
+	ruleSyncChangeInterval = 10 * time.Second
+)
+
+// RuleGroupSource reads the rule groups that RuleSyncService keeps in the rule store, e.g. the files of a directory.
+type RuleGroupSource interface {
+	ReadRuleGroups(ctx context.Context) ([]SourceRuleGroup, error)
+}
+
+// RuleGroupWatcher is implemented by the sources that tell when their rule groups may have changed, so that they are
+// synced without waiting for the next interval. The channel receives a value after each change, and is closed when
+// the context is done.
+type RuleGroupWatcher interface {
+	Watch(ctx context.Context) (<-chan struct{}, error)
+}
+
+// SourceRuleGroup is a rule group read by a RuleGroupSource, with the file it is defined in.
+type SourceRuleGroup struct {
+	File  string
+	Group models.AlertRuleGroupWithFolderTitle
+}
+
+// RuleGroupSyncResult is the result of the sync of a rule group.
+type RuleGroupSyncResult string
+
+const (
+	// RuleGroupSynced means that the stored group is the group of the source.
+	RuleGroupSynced RuleGroupSyncResult = "synced"
+	// RuleGroupSyncFailed means that the group of the source could not be written, see the error of the status.
+	RuleGroupSyncFailed RuleGroupSyncResult = "failed"
+	// RuleGroupPruned means that the group was deleted because it is not in the source anymore.
+	RuleGroupPruned RuleGroupSyncResult = "pruned"
+)
+
+// RuleGroupSyncStatus is the result of the last sync of a rule group.
+type RuleGroupSyncStatus struct {
+	OrgID     int64  `json:"orgId"`
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	// File is the file of the source that defines the group, empty if the group was pruned.
+	File     string              `json:"file,omitempty"`
+	LastSync time.Time           `json:"lastSync"`
+	Result   RuleGroupSyncResult `json:"result"`
+	Error    string              `json:"error,omitempty"`
+	// Created, Updated and Deleted are the UIDs of the rules that the last sync changed.
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Deleted []string `json:"deleted"`
+	// Drift is true if the stored group was changed by something else than the sync since the sync before the last one.
+	// The changes are reverted by the last sync.
+	Drift bool `json:"drift,omitempty"`
+}
+
+// RuleSyncStatus is the result of the last sync of an organization.
+type RuleSyncStatus struct {
+	// Enabled is false if there is no source to sync.
+	Enabled  bool
+	LastSync time.Time
+	// Error is the error of the last sync if the source could not be read, in which case the groups were not synced.
+	Error  string
+	Groups []RuleGroupSyncStatus
+}
+
+// ruleSyncSourceState is the result of the last read of the source.
+type ruleSyncSourceState struct {
+	LastSync time.Time `json:"lastSync"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// ruleSyncOrgState is the state of the sync of an organization.
+type ruleSyncOrgState struct {
+	// Groups are the results of the last sync, ordered by folder and title.
+	Groups []RuleGroupSyncStatus `json:"groups"`
+	// Synced are the groups that the sync wrote, with their versions after it last wrote them.
+	Synced []syncedRuleGroup `json:"synced"`
+}
+
+// syncedRuleGroup is a rule group that the sync wrote. Only these groups are pruned, and a group whose version is not
+// the version it had when it was written was changed by something else than the sync.
+type syncedRuleGroup struct {
+	FolderUID string `json:"folderUid"`
+	RuleGroup string `json:"ruleGroup"`
+	Version   string `json:"version"`
+}
+
+// RuleSyncService continuously syncs the rule groups of a source, usually a checkout of a Git repository, into the rule
+// store, with the file provenance. The groups of the source must refer to their folders by UID. The groups that were
+// synced and that are not in the source anymore are deleted if pruning is enabled, unless rules were added to them by
+// other means. The groups are synced periodically, and when the source changes if it is a RuleGroupWatcher.
+//
+// The state of the sync is stored, so that the instances of an HA setup share it, and a single instance syncs at a
+// time.
+type RuleSyncService struct {
+	alertRules *AlertRuleService
+	source     RuleGroupSource
+	kv         kvstore.KVStore
+	// lock makes a single instance sync the rule groups, or every instance if it is nil.
+	lock     ServerLock
+	clock    clock.Clock
+	interval time.Duration
+	prune    bool
+	log      log.Logger
+
+	mtx sync.Mutex
+}
+
+func NewRuleSyncService(alertRules *AlertRuleService, source RuleGroupSource, kv kvstore.KVStore, lock ServerLock, settings setting.UnifiedAlertingRuleSyncSettings, log log.Logger) *RuleSyncService {
+	return &RuleSyncService{
+		alertRules: alertRules,
+		source:     source,
+		kv:         kv,
+		lock:       lock,
+		clock:      clock.New(),
+		interval:   settings.Interval,
+		prune:      settings.Prune,
+		log:        log,
+	}
+}
+
+// Run syncs the rule groups of the source immediately, then periodically and when the source changes, until the
+// context is done. It returns immediately if there is no source.
+func (s *RuleSyncService) Run(ctx context.Context) error {
+	if s.source == nil {
+		return nil
+	}
+	var changes <-chan struct{}
+	if watcher, ok := s.source.(RuleGroupWatcher); ok {
+		var err error
+		if changes, err = watcher.Watch(ctx); err != nil {
+			s.log.Warn("Failed to watch the rule groups, they are synced periodically only", "error", err)
+		}
+	}
+	s.syncOnce(ctx, s.interval)
+	ticker := s.clock.Ticker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.syncOnce(ctx, s.interval)
+		case _, ok := <-changes:
+			if !ok {
+				changes = nil
+				continue
+			}
+			s.syncOnce(ctx, ruleSyncChangeInterval)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// syncOnce syncs the rule groups on the instance that acquires the server lock, unless they were synced less than
+// minInterval ago. A change that is not synced because of it is synced at the next interval.
+func (s *RuleSyncService) syncOnce(ctx context.Context, minInterval time.Duration) {
+	sync := func(ctx context.Context) {
+		if err := s.Sync(ctx); err != nil {
+			s.log.Error("Failed to sync alert rules", "error", err)
+		}
+	}
+	if s.lock == nil {
+		sync(ctx)
+		return
+	}
+	if err := s.lock.LockAndExecute(ctx, ruleSyncLockName, minInterval, sync); err != nil {
+		s.log.Error("Failed to acquire the lock to sync alert rules", "error", err)
+	}
+}
+
+// Sync replaces the stored rule groups with the groups of the source, and prunes the groups that are not in the source
+// anymore. It fails only if the source cannot be read or the state of the sync cannot be stored, the result of each
+// group is in its status.
+func (s *RuleSyncService) Sync(ctx context.Context) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := s.clock.Now()
+	groups, readErr := s.source.ReadRuleGroups(ctx)
+	source := ruleSyncSourceState{LastSync: now}
+	if readErr != nil {
+		source.Error = readErr.Error()
+	}
+	if err := s.saveState(ctx, 0, ruleSyncSourceKey, source); err != nil {
+		return err
+	}
+	if readErr != nil {
+		return fmt.Errorf("failed to read rule groups: %w", readErr)
+	}
+
+	byOrg := make(map[int64][]SourceRuleGroup)
+	for _, g := range groups {
+		byOrg[g.Group.OrgID] = append(byOrg[g.Group.OrgID], g)
+	}
+	// The organizations that were synced before are synced again, so that their groups are pruned if they are not in
+	// the source anymore.
+	states, err := kvstore.WithNamespace(s.kv, kvstore.AllOrganizations, ruleSyncNamespace).GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the state of the rule sync: %w", err)
+	}
+	for orgID, values := range states {
+		if _, ok := values[ruleSyncStateKey]; ok && orgID != 0 {
+			if _, ok := byOrg[orgID]; !ok {
+				byOrg[orgID] = nil
+			}
+		}
+	}
+	for orgID, orgGroups := range byOrg {
+		if err := s.syncOrg(ctx, now, orgID, orgGroups); err != nil {
+			s.log.Error("Failed to sync alert rule groups", "org", orgID, "error", err)
+		}
+	}
+	return nil
+}
+
+// syncOrg syncs the groups of the source of the organization and prunes its groups that are not in the source anymore.
+func (s *RuleSyncService) syncOrg(ctx context.Context, now time.Time, orgID int64, groups []SourceRuleGroup) error {
+	var state ruleSyncOrgState
+	if err := s.loadState(ctx, orgID, ruleSyncStateKey, &state); err != nil {
+		return err
+	}
+	synced := make(map[models.AlertRuleGroupKey]models.RuleGroupVersion, len(state.Synced))
+	for _, g := range state.Synced {
+		key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: g.FolderUID, RuleGroup: g.RuleGroup}
+		version, err := models.ParseRuleGroupVersion(g.Version)
+		if err != nil {
+			s.log.Warn("Ignoring the invalid version of a synced rule group", "org", orgID, "folder", g.FolderUID, "group", g.RuleGroup, "error", err)
+			version = models.RuleGroupVersion{}
+		}
+		synced[key] = version
+	}
+
+	// The status is rebuilt, so that the groups that are not in the source anymore are not reported anymore, unless
+	// they are pruned.
+	status := make(map[models.AlertRuleGroupKey]RuleGroupSyncStatus, len(groups))
+	for _, g := range groups {
+		key := models.AlertRuleGroupKey{OrgID: orgID, NamespaceUID: g.Group.FolderUID, RuleGroup: g.Group.Title}
+		if _, ok := status[key]; ok {
+			groupStatus := newRuleGroupSyncStatus(key, g.File, now)
+			groupStatus.Error = "the rule group is defined more than once"
+			status[key] = groupStatus
+			continue
+		}
+		status[key] = s.syncGroup(ctx, now, key, g, synced)
+	}
+	if s.prune {
+		s.pruneOrg(ctx, now, synced, status)
+	}
+
+	state = ruleSyncOrgState{
+		Groups: make([]RuleGroupSyncStatus, 0, len(status)),
+		Synced: make([]syncedRuleGroup, 0, len(synced)),
+	}
+	for _, groupStatus := range status {
+		state.Groups = append(state.Groups, groupStatus)
+	}
+	sort.Slice(state.Groups, func(i, j int) bool {
+		if state.Groups[i].FolderUID != state.Groups[j].FolderUID {
+			return state.Groups[i].FolderUID < state.Groups[j].FolderUID
+		}
+		return state.Groups[i].RuleGroup < state.Groups[j].RuleGroup
+	})
+	// The groups that are not in the source anymore and that are not pruned are not synced anymore.
+	for key, version := range synced {
+		if _, ok := status[key]; ok {
+			state.Synced = append(state.Synced, syncedRuleGroup{FolderUID: key.NamespaceUID, RuleGroup: key.RuleGroup, Version: version.String()})
+		}
+	}
+	return s.saveState(ctx, orgID, ruleSyncStateKey, state)
+}
+
+// syncGroup replaces the stored group with the group of the source, and returns its status. The version of the
+// written group is set in synced.
+func (s *RuleSyncService) syncGroup(ctx context.Context, now time.Time, key models.AlertRuleGroupKey, g SourceRuleGroup, synced map[models.AlertRuleGroupKey]models.RuleGroupVersion) RuleGroupSyncStatus {
+	status := newRuleGroupSyncStatus(key, g.File, now)
+	if key.NamespaceUID == "" {
+		status.Error = "the rule group must refer to its folder with folderUid"
+		return status
+	}
+	before, err := s.alertRules.ruleGroupVersion(ctx, key)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	if last, ok := synced[key]; ok && last.String() != before.String() {
+		status.Drift = true
+		s.log.Warn("Alert rule group was changed outside of the synced files, the changes are reverted", "org", key.OrgID, "folder", key.NamespaceUID, "group", key.RuleGroup)
+	}
+	err = s.alertRules.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := s.createMissingRules(ctx, key, g.Group.Rules); err != nil {
+			return err
+		}
+		return s.alertRules.ReplaceRuleGroup(ctx, key.OrgID, *g.Group.AlertRuleGroup, 0, models.ProvenanceFile)
+	})
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	after, err := s.alertRules.ruleGroupVersion(ctx, key)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	synced[key] = after
+	status.Result = RuleGroupSynced
+	status.Created, status.Updated, status.Deleted = diffRuleGroupVersions(before, after)
+	return status
+}
+
+// createMissingRules creates the rules of the group with a UID that does not exist, which the replacement of the group
+// only accepts for existing rules. The rules are created in the group, and the replacement of the group then sets
+// their indexes and the settings of the group.
+func (s *RuleSyncService) createMissingRules(ctx context.Context, key models.AlertRuleGroupKey, rules []models.AlertRule) error {
+	for _, rule := range rules {
+		if rule.UID == "" {
+			continue
+		}
+		_, _, err := s.alertRules.GetAlertRule(ctx, key.OrgID, rule.UID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, models.ErrAlertRuleNotFound) {
+			return err
+		}
+		rule.OrgID = key.OrgID
+		rule.NamespaceUID = key.NamespaceUID
+		rule.RuleGroup = key.RuleGroup
+		if _, err := s.alertRules.CreateAlertRule(ctx, rule, models.ProvenanceFile, 0); err != nil {
+			return fmt.Errorf("failed to create alert rule %s: %w", rule.UID, err)
+		}
+	}
+	return nil
+}
+
+// pruneOrg deletes the synced groups that are not in the status of the sync, to which it adds their status. A group
+// is not deleted if it has rules that the sync did not write, so that the rules created by other means are never
+// deleted.
+func (s *RuleSyncService) pruneOrg(ctx context.Context, now time.Time, synced map[models.AlertRuleGroupKey]models.RuleGroupVersion, status map[models.AlertRuleGroupKey]RuleGroupSyncStatus) {
+	for key, version := range synced {
+		if _, ok := status[key]; ok {
+			continue
+		}
+		groupStatus := newRuleGroupSyncStatus(key, "", now)
+		before, err := s.alertRules.ruleGroupVersion(ctx, key)
+		if err == nil {
+			for uid := range before {
+				if _, ok := version[uid]; !ok {
+					err = fmt.Errorf("the rule group has the rule '%s' that was not synced", uid)
+					break
+				}
+			}
+		}
+		if err == nil && len(before) > 0 {
+			err = s.alertRules.DeleteRuleGroup(ctx, key.OrgID, key.NamespaceUID, key.RuleGroup, models.ProvenanceFile)
+		}
+		if err != nil && !errors.Is(err, models.ErrAlertRuleGroupNotFound) {
+			groupStatus.Error = err.Error()
+			s.log.Warn("Failed to prune alert rule group", "org", key.OrgID, "folder", key.NamespaceUID, "group", key.RuleGroup, "error", err)
+		} else {
+			groupStatus.Result = RuleGroupPruned
+			_, _, groupStatus.Deleted = diffRuleGroupVersions(before, nil)
+			delete(synced, key)
+		}
+		status[key] = groupStatus
+	}
+}
+
+// newRuleGroupSyncStatus returns the status of a group whose sync failed, until its result is known.
+func newRuleGroupSyncStatus(key models.AlertRuleGroupKey, file string, now time.Time) RuleGroupSyncStatus {
+	return RuleGroupSyncStatus{
+		OrgID:     key.OrgID,
+		FolderUID: key.NamespaceUID,
+		RuleGroup: key.RuleGroup,
+		File:      file,
+		LastSync:  now,
+		Result:    RuleGroupSyncFailed,
+		Created:   []string{},
+		Updated:   []string{},
+		Deleted:   []string{},
+	}
+}
+
+// GetStatus returns the result of the last sync of the organization, with its groups ordered by folder and title.
+func (s *RuleSyncService) GetStatus(ctx context.Context, orgID int64) (RuleSyncStatus, error) {
+	result := RuleSyncStatus{
+		Enabled: s.source != nil,
+		Groups:  []RuleGroupSyncStatus{},
+	}
+	var source ruleSyncSourceState
+	if err := s.loadState(ctx, 0, ruleSyncSourceKey, &source); err != nil {
+		return RuleSyncStatus{}, err
+	}
+	result.LastSync = source.LastSync
+	result.Error = source.Error
+	var state ruleSyncOrgState
+	if err := s.loadState(ctx, orgID, ruleSyncStateKey, &state); err != nil {
+		return RuleSyncStatus{}, err
+	}
+	if len(state.Groups) > 0 {
+		result.Groups = state.Groups
+	}
+	return result, nil
+}
+
+// loadState reads the stored value of the key into v, which is left unchanged if there is none.
+func (s *RuleSyncService) loadState(ctx context.Context, orgID int64, key string, v any) error {
+	value, ok, err := kvstore.WithNamespace(s.kv, orgID, ruleSyncNamespace).Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read the state of the rule sync: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return fmt.Errorf("failed to unmarshal the state of the rule sync: %w", err)
+	}
+	return nil
+}
+
+func (s *RuleSyncService) saveState(ctx context.Context, orgID int64, key string, v any) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := kvstore.WithNamespace(s.kv, orgID, ruleSyncNamespace).Set(ctx, key, string(value)); err != nil {
+		return fmt.Errorf("failed to store the state of the rule sync: %w", err)
+	}
+	return nil
+}
+
+// diffRuleGroupVersions returns the UIDs of the rules created, updated and deleted between the versions of a group,
+// sorted.
+func diffRuleGroupVersions(before, after models.RuleGroupVersion) (created, updated, deleted []string) {
+	created, updated, deleted = []string{}, []string{}, []string{}
+	for uid, version := range after {
+		v, ok := before[uid]
+		switch {
+		case !ok:
+			created = append(created, uid)
+		case v != version:
+			updated = append(updated, uid)
+		}
+	}
+	for uid := range before {
+		if _, ok := after[uid]; !ok {
+			deleted = append(deleted, uid)
+		}
+	}
+	sort.Strings(created)
+	sort.Strings(updated)
+	sort.Strings(deleted)
+	return created, updated, deleted
+}
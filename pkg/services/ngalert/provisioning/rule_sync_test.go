@@ -0,0 +1,216 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeRuleGroupSource struct {
+	groups []SourceRuleGroup
+	err    error
+}
+
+func (f *fakeRuleGroupSource) ReadRuleGroups(context.Context) ([]SourceRuleGroup, error) {
+	return f.groups, f.err
+}
+
+func TestRuleSyncService(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	sourceGroup := func(title string, uids ...string) SourceRuleGroup {
+		group := createDummyGroup(title, orgID)
+		group.Rules = nil
+		for _, uid := range uids {
+			rule := dummyRule(uid, orgID)
+			rule.UID = uid
+			rule.RuleGroup = title
+			// The range is stored in seconds, the rule would be changed by every sync with a shorter one.
+			rule.Data[0].RelativeTimeRange.From = models.Duration(time.Minute)
+			group.Rules = append(group.Rules, rule)
+		}
+		return SourceRuleGroup{
+			File:  title + ".yaml",
+			Group: models.AlertRuleGroupWithFolderTitle{AlertRuleGroup: &group, OrgID: orgID},
+		}
+	}
+	setup := func(t *testing.T, groups ...SourceRuleGroup) (*RuleSyncService, *fakeRuleGroupSource) {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		source := &fakeRuleGroupSource{groups: groups}
+		return NewRuleSyncService(&ruleService, source, kvstore.NewFakeKVStore(), nil, setting.UnifiedAlertingRuleSyncSettings{Prune: true}, log.NewNopLogger()), source
+	}
+	getStatus := func(t *testing.T, s *RuleSyncService) RuleSyncStatus {
+		t.Helper()
+		status, err := s.GetStatus(ctx, orgID)
+		require.NoError(t, err)
+		return status
+	}
+	groupStatus := func(t *testing.T, s *RuleSyncService, title string) RuleGroupSyncStatus {
+		t.Helper()
+		for _, status := range getStatus(t, s).Groups {
+			if status.RuleGroup == title {
+				return status
+			}
+		}
+		require.Failf(t, "missing status", "rule group %s", title)
+		return RuleGroupSyncStatus{}
+	}
+
+	t.Run("should sync the groups with the file provenance", func(t *testing.T) {
+		s, _ := setup(t, sourceGroup("a", "a-1", "a-2"))
+		require.NoError(t, s.Sync(ctx))
+
+		status := groupStatus(t, s, "a")
+		require.Equal(t, RuleGroupSynced, status.Result)
+		require.Equal(t, "a.yaml", status.File)
+		require.Equal(t, []string{"a-1", "a-2"}, status.Created)
+		require.False(t, status.Drift)
+		_, provenance, err := s.alertRules.GetAlertRule(ctx, orgID, "a-1")
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceFile, provenance)
+
+		require.NoError(t, s.Sync(ctx))
+		status = groupStatus(t, s, "a")
+		require.Equal(t, RuleGroupSynced, status.Result)
+		require.Empty(t, status.Created)
+		require.Empty(t, status.Updated)
+		require.Empty(t, status.Deleted)
+	})
+
+	t.Run("should revert and report the drift of the groups", func(t *testing.T) {
+		s, _ := setup(t, sourceGroup("a", "a-1"))
+		require.NoError(t, s.Sync(ctx))
+
+		rule, _, err := s.alertRules.GetAlertRule(ctx, orgID, "a-1")
+		require.NoError(t, err)
+		rule.Title = "changed"
+		_, err = s.alertRules.UpdateAlertRule(ctx, rule, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		require.NoError(t, s.Sync(ctx))
+		status := groupStatus(t, s, "a")
+		require.True(t, status.Drift)
+		require.Equal(t, []string{"a-1"}, status.Updated)
+		rule, _, err = s.alertRules.GetAlertRule(ctx, orgID, "a-1")
+		require.NoError(t, err)
+		require.Equal(t, "a-1", rule.Title)
+	})
+
+	t.Run("should prune the groups removed from the source", func(t *testing.T) {
+		s, source := setup(t, sourceGroup("a", "a-1"), sourceGroup("b", "b-1"))
+		require.NoError(t, s.Sync(ctx))
+		other := dummyRule("other", orgID)
+		other.RuleGroup = "other"
+		_, err := s.alertRules.CreateAlertRule(ctx, other, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		source.groups = source.groups[:1]
+		require.NoError(t, s.Sync(ctx))
+		status := groupStatus(t, s, "b")
+		require.Equal(t, RuleGroupPruned, status.Result)
+		require.Equal(t, []string{"b-1"}, status.Deleted)
+		_, err = s.alertRules.GetRuleGroup(ctx, orgID, "my-namespace", "b")
+		require.ErrorIs(t, err, models.ErrAlertRuleGroupNotFound)
+		_, err = s.alertRules.GetRuleGroup(ctx, orgID, "my-namespace", "other")
+		require.NoError(t, err)
+
+		require.NoError(t, s.Sync(ctx))
+		require.Len(t, getStatus(t, s).Groups, 1)
+	})
+
+	t.Run("should not prune the groups with the file provenance that were not synced", func(t *testing.T) {
+		s, source := setup(t, sourceGroup("a", "a-1"))
+		other := dummyRule("other", orgID)
+		other.RuleGroup = "other"
+		_, err := s.alertRules.CreateAlertRule(ctx, other, models.ProvenanceFile, 0)
+		require.NoError(t, err)
+		require.NoError(t, s.Sync(ctx))
+
+		source.groups = nil
+		require.NoError(t, s.Sync(ctx))
+		require.Equal(t, RuleGroupPruned, groupStatus(t, s, "a").Result)
+		_, err = s.alertRules.GetRuleGroup(ctx, orgID, "my-namespace", "other")
+		require.NoError(t, err)
+	})
+
+	t.Run("should not prune the synced groups to which rules were added", func(t *testing.T) {
+		s, source := setup(t, sourceGroup("a", "a-1"))
+		require.NoError(t, s.Sync(ctx))
+		added := dummyRule("added", orgID)
+		added.RuleGroup = "a"
+		_, err := s.alertRules.CreateAlertRule(ctx, added, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		source.groups = nil
+		require.NoError(t, s.Sync(ctx))
+		status := groupStatus(t, s, "a")
+		require.Equal(t, RuleGroupSyncFailed, status.Result)
+		require.Contains(t, status.Error, "was not synced")
+		group, err := s.alertRules.GetRuleGroup(ctx, orgID, "my-namespace", "a")
+		require.NoError(t, err)
+		require.Len(t, group.Rules, 2)
+	})
+
+	t.Run("should share the state of the sync between the instances", func(t *testing.T) {
+		s, source := setup(t, sourceGroup("a", "a-1"), sourceGroup("b", "b-1"))
+		require.NoError(t, s.Sync(ctx))
+		other := NewRuleSyncService(s.alertRules, source, s.kv, nil, setting.UnifiedAlertingRuleSyncSettings{Prune: true}, log.NewNopLogger())
+		require.Equal(t, getStatus(t, s), getStatus(t, other))
+
+		require.NoError(t, other.Sync(ctx))
+		require.False(t, groupStatus(t, other, "a").Drift, "the groups synced by another instance should not be reported as drifted")
+		require.Equal(t, getStatus(t, other), getStatus(t, s))
+
+		source.groups = source.groups[:1]
+		require.NoError(t, other.Sync(ctx))
+		require.Equal(t, RuleGroupPruned, groupStatus(t, s, "b").Result, "the groups synced by another instance should be pruned")
+	})
+
+	t.Run("should not prune the groups if pruning is disabled", func(t *testing.T) {
+		s, source := setup(t, sourceGroup("a", "a-1"), sourceGroup("b", "b-1"))
+		s.prune = false
+		require.NoError(t, s.Sync(ctx))
+
+		source.groups = source.groups[:1]
+		require.NoError(t, s.Sync(ctx))
+		_, err := s.alertRules.GetRuleGroup(ctx, orgID, "my-namespace", "b")
+		require.NoError(t, err)
+		require.Len(t, getStatus(t, s).Groups, 1)
+	})
+
+	t.Run("should report the groups that fail", func(t *testing.T) {
+		noFolder := sourceGroup("b", "b-1")
+		noFolder.Group.FolderUID = ""
+		s, _ := setup(t, sourceGroup("a", "a-1"), sourceGroup("a", "a-2"), noFolder)
+		require.NoError(t, s.Sync(ctx))
+
+		require.Equal(t, RuleGroupSyncFailed, groupStatus(t, s, "a").Result)
+		require.Contains(t, groupStatus(t, s, "a").Error, "more than once")
+		require.Equal(t, RuleGroupSyncFailed, groupStatus(t, s, "b").Result)
+		require.Contains(t, groupStatus(t, s, "b").Error, "folderUid")
+	})
+
+	t.Run("should not change the groups if the source cannot be read", func(t *testing.T) {
+		s, source := setup(t, sourceGroup("a", "a-1"))
+		require.NoError(t, s.Sync(ctx))
+
+		source.err = errors.New("invalid file")
+		require.ErrorContains(t, s.Sync(ctx), "invalid file")
+		status := getStatus(t, s)
+		require.True(t, status.Enabled)
+		require.Equal(t, "invalid file", status.Error)
+		require.Len(t, status.Groups, 1)
+		_, err := s.alertRules.GetRuleGroup(ctx, orgID, "my-namespace", "a")
+		require.NoError(t, err)
+	})
+}
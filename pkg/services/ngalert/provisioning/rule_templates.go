@@ -0,0 +1,325 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var (
+	// templateVariable matches the ${name} placeholders of the alert rule templates, and $$, which escapes a $.
+	templateVariable = regexp.MustCompile(`\$\$|\$\{([^}]*)\}`)
+	// templateParameterName matches the valid names of the parameters of the alert rule templates.
+	templateParameterName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ListRuleTemplates returns the alert rule templates of the organization ordered by title.
+func (service *AlertRuleService) ListRuleTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, error) {
+	return service.ruleStore.ListAlertRuleTemplates(ctx, orgID)
+}
+
+// GetRuleTemplate returns the alert rule template of the organization with the UID. It returns
+// models.ErrAlertRuleTemplateNotFound if it does not exist.
+func (service *AlertRuleService) GetRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	return service.ruleStore.GetAlertRuleTemplate(ctx, orgID, uid)
+}
+
+// SaveRuleTemplate creates the alert rule template, or replaces the template of its organization with the same UID.
+// Every placeholder of the template must be one of its parameters, and the template must have a folder and a group.
+func (service *AlertRuleService) SaveRuleTemplate(ctx context.Context, tmpl models.AlertRuleTemplate) (models.AlertRuleTemplate, error) {
+	if err := util.ValidateUID(tmpl.UID); err != nil {
+		return models.AlertRuleTemplate{}, fmt.Errorf("%w: invalid UID of the template: %s", models.ErrAlertRuleFailedValidation, err)
+	}
+	if strings.TrimSpace(tmpl.Title) == "" {
+		return models.AlertRuleTemplate{}, fmt.Errorf("%w: title of the template is required", models.ErrAlertRuleFailedValidation)
+	}
+	declared := make(map[string]struct{}, len(tmpl.Parameters))
+	for _, p := range tmpl.Parameters {
+		if !templateParameterName.MatchString(p.Name) {
+			return models.AlertRuleTemplate{}, fmt.Errorf("%w: invalid name of parameter '%s', it must start with a letter or an underscore and contain only letters, digits and underscores", models.ErrAlertRuleFailedValidation, p.Name)
+		}
+		if _, ok := declared[p.Name]; ok {
+			return models.AlertRuleTemplate{}, fmt.Errorf("%w: parameter '%s' is declared more than once", models.ErrAlertRuleFailedValidation, p.Name)
+		}
+		declared[p.Name] = struct{}{}
+	}
+	if tmpl.Rule.NamespaceUID == "" || tmpl.Rule.RuleGroup == "" {
+		return models.AlertRuleTemplate{}, fmt.Errorf("%w: the rule of the template must have a folder and a group", models.ErrAlertRuleFailedValidation)
+	}
+	_, err := expandRuleTemplate(tmpl.Rule, func(name string) (string, error) {
+		if _, ok := declared[name]; !ok {
+			return "", fmt.Errorf("parameter '%s' is not declared", name)
+		}
+		return "", nil
+	})
+	if err != nil {
+		return models.AlertRuleTemplate{}, fmt.Errorf("%w: %s", models.ErrAlertRuleFailedValidation, err)
+	}
+
+	tmpl.Rule.ID = 0
+	tmpl.Rule.OrgID = tmpl.OrgID
+	tmpl.Updated = time.Now()
+	if err := service.ruleStore.SaveAlertRuleTemplate(ctx, tmpl); err != nil {
+		return models.AlertRuleTemplate{}, err
+	}
+	return tmpl, nil
+}
+
+// DeleteRuleTemplate deletes the alert rule template of the organization with the UID. The rules instantiated from it
+// are kept. It returns models.ErrAlertRuleTemplateNotFound if it does not exist.
+func (service *AlertRuleService) DeleteRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return service.ruleStore.DeleteAlertRuleTemplate(ctx, orgID, uid)
+}
+
+// InstantiateTemplate writes the rule that the alert rule template with the UID expands into with the values of its
+// parameters, in the organization of the user. The placeholders of the template are replaced with the values of
+// params, or with the defaults of the parameters that params does not set. A string of a query model that is only a
+// placeholder, e.g. "${threshold}", is replaced by a number if the value is one.
+//
+// Instantiating the template again with the same values updates the rule rather than creating another one: the rule
+// is matched by UID if the template has one, or by title in its group otherwise. The user must be able to change the
+// group of the rule.
+func (service *AlertRuleService) InstantiateTemplate(ctx context.Context, user identity.Requester, templateUID string, params map[string]string, provenance models.Provenance) (models.AlertRule, error) {
+	orgID := user.GetOrgID()
+	userID, _ := identity.UserIdentifier(user.GetNamespacedID())
+	var result models.AlertRule
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		tmpl, err := service.ruleStore.GetAlertRuleTemplate(ctx, orgID, templateUID)
+		if err != nil {
+			return err
+		}
+		rule, err := instantiateRuleTemplate(tmpl, params)
+		if err != nil {
+			return err
+		}
+		group, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{rule.NamespaceUID},
+			RuleGroup:     rule.RuleGroup,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		var existing *models.AlertRule
+		for _, r := range group {
+			if (rule.UID != "" && r.UID == rule.UID) || (rule.UID == "" && r.Title == rule.Title) {
+				existing = r
+				break
+			}
+		}
+
+		if existing != nil {
+			rule.UID = existing.UID
+		}
+		if service.authz != nil {
+			key := rule.GetGroupKey()
+			delta := &store.GroupDelta{
+				GroupKey:       key,
+				AffectedGroups: map[models.AlertRuleGroupKey]models.RulesGroup{key: group},
+			}
+			if existing != nil {
+				delta.Update = []store.RuleDelta{store.CalculateRuleDelta(existing, &rule)}
+			} else {
+				delta.New = []*models.AlertRule{&rule}
+			}
+			if err := service.authorizeRuleChanges(ctx, user, delta); err != nil {
+				return err
+			}
+		}
+
+		if existing == nil {
+			result, err = service.CreateAlertRule(ctx, rule, provenance, userID)
+			return err
+		}
+		result, err = service.UpdateAlertRule(ctx, rule, provenance)
+		return err
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	service.log.Info("Instantiated alert rule template", "org", orgID, "template_uid", templateUID, "rule_uid", result.UID)
+	return result, nil
+}
+
+// instantiateRuleTemplate returns the rule that the template expands into with the values of the parameters.
+func instantiateRuleTemplate(tmpl *models.AlertRuleTemplate, params map[string]string) (models.AlertRule, error) {
+	values := make(map[string]string, len(tmpl.Parameters))
+	var missing []string
+	for _, p := range tmpl.Parameters {
+		if v, ok := params[p.Name]; ok {
+			values[p.Name] = v
+		} else if p.Default != nil {
+			values[p.Name] = *p.Default
+		} else {
+			missing = append(missing, p.Name)
+		}
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, ok := values[name]; !ok {
+			return models.AlertRule{}, fmt.Errorf("%w: template '%s' has no parameter '%s'", models.ErrAlertRuleFailedValidation, tmpl.UID, name)
+		}
+	}
+	if len(missing) > 0 {
+		return models.AlertRule{}, fmt.Errorf("%w: missing values of the parameters %s of template '%s'", models.ErrAlertRuleFailedValidation, strings.Join(missing, ", "), tmpl.UID)
+	}
+
+	rule, err := expandRuleTemplate(tmpl.Rule, func(name string) (string, error) {
+		v, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("parameter '%s' is not declared", name)
+		}
+		return v, nil
+	})
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("%w: template '%s': %s", models.ErrAlertRuleFailedValidation, tmpl.UID, err)
+	}
+	if rule.NamespaceUID == "" || rule.RuleGroup == "" {
+		return models.AlertRule{}, fmt.Errorf("%w: template '%s' expands into a rule without folder or group", models.ErrAlertRuleFailedValidation, tmpl.UID)
+	}
+	rule.ID = 0
+	rule.OrgID = tmpl.OrgID
+	rule.Version = 0
+	return rule, nil
+}
+
+// expandRuleTemplate returns a copy of the template rule with the placeholders of its fields replaced with the values
+// that lookup returns for their names.
+func expandRuleTemplate(tmpl models.AlertRule, lookup func(name string) (string, error)) (models.AlertRule, error) {
+	rule := *models.CopyRule(&tmpl)
+	var err error
+	for _, f := range []struct {
+		name  string
+		value *string
+	}{
+		{"title", &rule.Title},
+		{"uid", &rule.UID},
+		{"folder", &rule.NamespaceUID},
+		{"group", &rule.RuleGroup},
+	} {
+		if *f.value, err = expandTemplateVariables(*f.value, lookup); err != nil {
+			return models.AlertRule{}, fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	for _, m := range []map[string]string{rule.Labels, rule.Annotations} {
+		for name, value := range maps.Clone(m) {
+			if m[name], err = expandTemplateVariables(value, lookup); err != nil {
+				return models.AlertRule{}, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	for i, q := range rule.Data {
+		if rule.Data[i].DatasourceUID, err = expandTemplateVariables(q.DatasourceUID, lookup); err != nil {
+			return models.AlertRule{}, fmt.Errorf("data source of query %s: %w", q.RefID, err)
+		}
+		if !bytes.Contains(q.Model, []byte("$")) {
+			continue
+		}
+		rule.Data[i].Model, err = mapModelStrings(q.RefID, q.Model, func(v string) (any, error) {
+			expanded, err := expandTemplateVariables(v, lookup)
+			if err != nil {
+				return nil, fmt.Errorf("query %s: %w", q.RefID, err)
+			}
+			if isTemplateVariable(v) {
+				var number json.Number
+				if err := json.Unmarshal([]byte(expanded), &number); err == nil {
+					return number, nil
+				}
+			}
+			return expanded, nil
+		})
+		if err != nil {
+			return models.AlertRule{}, err
+		}
+	}
+	for i, ns := range rule.NotificationSettings {
+		if rule.NotificationSettings[i].Receiver, err = expandTemplateVariables(ns.Receiver, lookup); err != nil {
+			return models.AlertRule{}, fmt.Errorf("contact point: %w", err)
+		}
+	}
+	return rule, nil
+}
+
+// expandTemplateVariables replaces the ${name} placeholders of the text with the values that lookup returns for their
+// names, and $$ with $.
+func expandTemplateVariables(text string, lookup func(name string) (string, error)) (string, error) {
+	if !strings.Contains(text, "$") {
+		return text, nil
+	}
+	var err error
+	expanded := templateVariable.ReplaceAllStringFunc(text, func(match string) string {
+		if err != nil {
+			return ""
+		}
+		if match == "$$" {
+			return "$"
+		}
+		var value string
+		value, err = lookup(match[2 : len(match)-1])
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
+
+// isTemplateVariable returns true if the text is a single ${name} placeholder.
+func isTemplateVariable(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasPrefix(text, "${") && templateVariable.FindString(text) == text
+}
+
+// mapModelStrings replaces the strings of the model of a query, at any depth, with what fn returns for them.
+func mapModelStrings(refID string, model json.RawMessage, fn func(v string) (any, error)) (json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(model))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid model of query %s: %w", refID, err)
+	}
+	var render func(v any) (any, error)
+	render = func(v any) (any, error) {
+		switch v := v.(type) {
+		case map[string]any:
+			for key, item := range v {
+				rendered, err := render(item)
+				if err != nil {
+					return nil, err
+				}
+				v[key] = rendered
+			}
+		case []any:
+			for i, item := range v {
+				rendered, err := render(item)
+				if err != nil {
+					return nil, err
+				}
+				v[i] = rendered
+			}
+		case string:
+			return fn(v)
+		}
+		return v, nil
+	}
+	value, err := render(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,134 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestAlertRuleTemplates(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	requester := &user.SignedInUser{OrgID: orgID, UserID: 1}
+
+	newTemplate := func() models.AlertRuleTemplate {
+		// The titles of the rules are unique in their folder, so the title depends on the group.
+		rule := createTestRule("Disk usage of ${team} in ${env}", "${env}", orgID, "${team}-folder")
+		rule.Labels = map[string]string{"team": "${team}", "cost": "$$5"}
+		rule.Data[0].Model = json.RawMessage(`{"refId": "A", "expression": "disk{team=\"${team}\"}", "threshold": "${threshold}"}`)
+		return models.AlertRuleTemplate{
+			OrgID: orgID,
+			UID:   "disk-usage",
+			Title: "Disk usage per team",
+			Parameters: []models.AlertRuleTemplateParameter{
+				{Name: "team"},
+				{Name: "env", Default: util.Pointer("prod")},
+				{Name: "threshold", Default: util.Pointer("90")},
+			},
+			Rule: rule,
+		}
+	}
+
+	setup := func(t *testing.T) AlertRuleService {
+		t.Helper()
+		ruleService := createAlertRuleService(t)
+		_, err := ruleService.SaveRuleTemplate(ctx, newTemplate())
+		require.NoError(t, err)
+		return ruleService
+	}
+
+	t.Run("should save and replace templates", func(t *testing.T) {
+		ruleService := setup(t)
+
+		tmpl := newTemplate()
+		tmpl.Title = "Disk usage"
+		_, err := ruleService.SaveRuleTemplate(ctx, tmpl)
+		require.NoError(t, err)
+
+		templates, err := ruleService.ListRuleTemplates(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, templates, 1)
+		require.Equal(t, "Disk usage", templates[0].Title)
+		require.Equal(t, tmpl.Parameters, templates[0].Parameters)
+		require.Equal(t, tmpl.Rule.Title, templates[0].Rule.Title)
+
+		require.NoError(t, ruleService.DeleteRuleTemplate(ctx, orgID, tmpl.UID))
+		_, err = ruleService.GetRuleTemplate(ctx, orgID, tmpl.UID)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+
+	t.Run("should reject invalid templates", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+
+		undeclared := newTemplate()
+		undeclared.Rule.Annotations = map[string]string{"runbook": "${runbook}"}
+		_, err := ruleService.SaveRuleTemplate(ctx, undeclared)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "runbook")
+
+		invalidName := newTemplate()
+		invalidName.Parameters = append(invalidName.Parameters, models.AlertRuleTemplateParameter{Name: "1st"})
+		_, err = ruleService.SaveRuleTemplate(ctx, invalidName)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		duplicate := newTemplate()
+		duplicate.Parameters = append(duplicate.Parameters, models.AlertRuleTemplateParameter{Name: "team"})
+		_, err = ruleService.SaveRuleTemplate(ctx, duplicate)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should create a rule and update it when instantiated again", func(t *testing.T) {
+		ruleService := setup(t)
+
+		rule, err := ruleService.InstantiateTemplate(ctx, requester, "disk-usage", map[string]string{"team": "storage"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NotEmpty(t, rule.UID)
+		require.Equal(t, "Disk usage of storage in prod", rule.Title)
+		require.Equal(t, "storage-folder", rule.NamespaceUID)
+		require.Equal(t, "prod", rule.RuleGroup)
+		require.Equal(t, map[string]string{"team": "storage", "cost": "$5"}, rule.Labels)
+		require.JSONEq(t, `{"refId": "A", "expression": "disk{team=\"storage\"}", "threshold": 90, "intervalMs": 1000, "maxDataPoints": 43200}`, string(rule.Data[0].Model))
+
+		updated, err := ruleService.InstantiateTemplate(ctx, requester, "disk-usage", map[string]string{"team": "storage", "threshold": "95"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, rule.UID, updated.UID, "the rule should be updated rather than created again")
+		require.Contains(t, string(updated.Data[0].Model), `"threshold":95`)
+
+		other, err := ruleService.InstantiateTemplate(ctx, requester, "disk-usage", map[string]string{"team": "storage", "env": "dev"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NotEqual(t, rule.UID, other.UID, "a rule of another group should be created")
+	})
+
+	t.Run("should fail for missing or unknown parameters", func(t *testing.T) {
+		ruleService := setup(t)
+
+		_, err := ruleService.InstantiateTemplate(ctx, requester, "disk-usage", nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "team")
+
+		_, err = ruleService.InstantiateTemplate(ctx, requester, "disk-usage", map[string]string{"team": "storage", "region": "eu"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		require.ErrorContains(t, err, "region")
+
+		_, err = ruleService.InstantiateTemplate(ctx, requester, "unknown", nil, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleTemplateNotFound)
+	})
+
+	t.Run("should authorize the user", func(t *testing.T) {
+		ruleService := setup(t)
+		authz := &fakeRuleAccessControl{changeErr: errors.New("create denied")}
+		ruleService.authz = authz
+
+		_, err := ruleService.InstantiateTemplate(ctx, requester, "disk-usage", map[string]string{"team": "storage"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, authz.changeErr)
+		require.Len(t, authz.changes, 1)
+		require.Len(t, authz.changes[0].New, 1)
+	})
+}
@@ -0,0 +1,92 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	ruleTitleUniquenessNamespace = "alerting.rule_title_uniqueness"
+	ruleTitleUniquenessKey       = "policy"
+)
+
+// RuleTitleUniquenessPolicy is the scope in which the titles of the alert rules of an organization must be unique
+// when they are written through provisioning.
+type RuleTitleUniquenessPolicy struct {
+	// Scope is setting.RuleTitleUniquenessFolder or setting.RuleTitleUniquenessGroup, or empty if titles do not need
+	// to be unique.
+	Scope string `json:"scope"`
+	// FolderUIDs are the folders in which the policy is enforced. It is enforced in all folders if it is empty.
+	FolderUIDs []string `json:"folderUids,omitempty"`
+}
+
+func (p RuleTitleUniquenessPolicy) validate() error {
+	switch p.Scope {
+	case "", setting.RuleTitleUniquenessFolder, setting.RuleTitleUniquenessGroup:
+		return nil
+	}
+	return fmt.Errorf("%w: invalid rule title uniqueness scope '%s', expected '%s', '%s' or empty", ErrValidation, p.Scope, setting.RuleTitleUniquenessFolder, setting.RuleTitleUniquenessGroup)
+}
+
+// enforcedIn returns true if the policy applies to the folder.
+func (p RuleTitleUniquenessPolicy) enforcedIn(namespaceUID string) bool {
+	if p.Scope == "" {
+		return false
+	}
+	if len(p.FolderUIDs) == 0 {
+		return true
+	}
+	return slices.Contains(p.FolderUIDs, namespaceUID)
+}
+
+// GetRuleTitleUniqueness returns the rule title uniqueness policy of the organization. If the organization has no
+// policy, the policy of the server settings is returned and inherited is true.
+func (service *AlertRuleService) GetRuleTitleUniqueness(ctx context.Context, orgID int64) (policy RuleTitleUniquenessPolicy, inherited bool, err error) {
+	if service.kv != nil {
+		value, ok, err := service.titleUniquenessStore(orgID).Get(ctx, ruleTitleUniquenessKey)
+		if err != nil {
+			return RuleTitleUniquenessPolicy{}, false, err
+		}
+		if ok {
+			if err := json.Unmarshal([]byte(value), &policy); err != nil {
+				return RuleTitleUniquenessPolicy{}, false, fmt.Errorf("failed to unmarshal rule title uniqueness policy: %w", err)
+			}
+			return policy, false, nil
+		}
+	}
+	return service.titleUniqueness, true, nil
+}
+
+// SetRuleTitleUniqueness sets the rule title uniqueness policy of the organization, which overrides the policy of the
+// server settings. The titles of the rules that already exist are not checked.
+func (service *AlertRuleService) SetRuleTitleUniqueness(ctx context.Context, orgID int64, policy RuleTitleUniquenessPolicy) error {
+	if err := policy.validate(); err != nil {
+		return err
+	}
+	if service.kv == nil {
+		return fmt.Errorf("rule title uniqueness policies of organizations are not supported")
+	}
+	value, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	return service.titleUniquenessStore(orgID).Set(ctx, ruleTitleUniquenessKey, string(value))
+}
+
+// ResetRuleTitleUniqueness removes the rule title uniqueness policy of the organization, so that the policy of the
+// server settings applies again.
+func (service *AlertRuleService) ResetRuleTitleUniqueness(ctx context.Context, orgID int64) error {
+	if service.kv == nil {
+		return nil
+	}
+	return service.titleUniquenessStore(orgID).Del(ctx, ruleTitleUniquenessKey)
+}
+
+func (service *AlertRuleService) titleUniquenessStore(orgID int64) *kvstore.NamespacedKVStore {
+	return kvstore.WithNamespace(service.kv, orgID, ruleTitleUniquenessNamespace)
+}
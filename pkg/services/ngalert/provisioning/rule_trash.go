@@ -0,0 +1,124 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleTrashCleanupInterval is the interval at which the rules whose retention in the trash passed are removed.
+const ruleTrashCleanupInterval = time.Hour
+
+// trashRules moves the rules to the trash, with their provenance, if the trash is enabled.
+func (service *AlertRuleService) trashRules(ctx context.Context, orgID int64, rules ...*models.AlertRule) error {
+	if service.trashRetention <= 0 || len(rules) == 0 {
+		return nil
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	deletedAt := time.Now().Unix()
+	deleted := make([]*models.DeletedAlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		d, err := models.NewDeletedAlertRule(rule, provenances[rule.UID], deletedAt)
+		if err != nil {
+			return err
+		}
+		deleted = append(deleted, d)
+	}
+	return service.trashStore.InsertDeletedAlertRules(ctx, deleted...)
+}
+
+// ListDeletedRules returns the rules of the organization that are in the trash, without their content, the latest
+// deleted first. A rule that was deleted several times is listed once per deletion.
+func (service *AlertRuleService) ListDeletedRules(ctx context.Context, orgID int64) ([]*models.DeletedAlertRule, error) {
+	return service.trashStore.GetDeletedAlertRules(ctx, orgID)
+}
+
+// RestoreAlertRule creates the rule with the UID again as it was when it was last deleted, with the provenance, and
+// removes it from the trash. It returns models.ErrDeletedAlertRuleNotFound if the rule is not in the trash, and
+// ErrDeletedAlertRuleExists if a rule with the UID exists.
+func (service *AlertRuleService) RestoreAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance, userID int64) (models.AlertRule, error) {
+	var restored models.AlertRule
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := service.trashStore.GetDeletedAlertRule(ctx, orgID, ruleUID)
+		if err != nil {
+			return err
+		}
+		rule, err := deleted.Rule()
+		if err != nil {
+			return err
+		}
+		_, err = service.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: orgID, UID: ruleUID})
+		if err == nil {
+			return ErrDeletedAlertRuleExists.Errorf("alert rule %s exists", ruleUID)
+		}
+		if !errors.Is(err, models.ErrAlertRuleNotFound) {
+			return err
+		}
+		rule.ID = 0
+		restored, err = service.CreateAlertRule(ctx, rule, provenance, userID)
+		if err != nil {
+			return err
+		}
+		return service.trashStore.DeleteDeletedAlertRules(ctx, orgID, ruleUID)
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	service.log.Info("Restored deleted alert rule", "org", orgID, "rule_uid", ruleUID, "provenance", provenance, "user", userID)
+	return restored, nil
+}
+
+// CleanupTrash removes the rules of all the organizations whose retention in the trash passed at the time.
+func (service *AlertRuleService) CleanupTrash(ctx context.Context, now time.Time) (int64, error) {
+	return service.trashStore.DeleteDeletedAlertRulesBefore(ctx, now.Add(-service.trashRetention).Unix())
+}
+
+// RuleTrashCleanup periodically removes the alert rules whose retention in the trash passed.
+type RuleTrashCleanup struct {
+	alertRules *AlertRuleService
+	clock      clock.Clock
+	log        log.Logger
+}
+
+func NewRuleTrashCleanup(alertRules *AlertRuleService, log log.Logger) *RuleTrashCleanup {
+	return &RuleTrashCleanup{
+		alertRules: alertRules,
+		clock:      clock.New(),
+		log:        log,
+	}
+}
+
+// Run periodically cleans up the trash until the context is done. It returns immediately if the trash is disabled.
+func (c *RuleTrashCleanup) Run(ctx context.Context) error {
+	if c.alertRules.trashRetention <= 0 {
+		return nil
+	}
+	ticker := c.clock.Ticker(ruleTrashCleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			removed, err := c.alertRules.CleanupTrash(ctx, c.clock.Now())
+			if err != nil {
+				c.log.Error("Failed to clean up the trash of alert rules", "error", err)
+				continue
+			}
+			if removed > 0 {
+				c.log.Info("Removed deleted alert rules from the trash", "count", removed)
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
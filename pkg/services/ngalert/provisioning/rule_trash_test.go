@@ -0,0 +1,120 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func createAlertRuleServiceWithTrash(t *testing.T) AlertRuleService {
+	t.Helper()
+	ruleService := createAlertRuleService(t)
+	ruleService.trashStore = ruleService.ruleStore.(AlertRuleTrashStore)
+	ruleService.trashRetention = time.Hour
+	return ruleService
+}
+
+func TestAlertRuleTrash(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	t.Run("deleted rules should be listed and restored with their provenance", func(t *testing.T) {
+		ruleService := createAlertRuleServiceWithTrash(t)
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("deleted", orgID), models.ProvenanceFile, 0)
+		require.NoError(t, err)
+		require.NoError(t, ruleService.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceFile))
+
+		deleted, err := ruleService.ListDeletedRules(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, rule.UID, deleted[0].RuleUID)
+		require.Equal(t, rule.Title, deleted[0].Title)
+		require.Equal(t, models.ProvenanceFile, deleted[0].Provenance)
+		require.Empty(t, deleted[0].Data)
+
+		restored, err := ruleService.RestoreAlertRule(ctx, orgID, rule.UID, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		require.Equal(t, rule.UID, restored.UID)
+		require.Equal(t, rule.Title, restored.Title)
+		require.Equal(t, rule.RuleGroup, restored.RuleGroup)
+
+		_, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+
+		deleted, err = ruleService.ListDeletedRules(ctx, orgID)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+
+	t.Run("deleted rule groups should go to the trash", func(t *testing.T) {
+		ruleService := createAlertRuleServiceWithTrash(t)
+		_, err := ruleService.CreateAlertRule(ctx, dummyRule("first", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(ctx, dummyRule("second", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		require.NoError(t, ruleService.DeleteRuleGroup(ctx, orgID, "my-namespace", "my-cool-group", models.ProvenanceAPI))
+
+		deleted, err := ruleService.ListDeletedRules(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, deleted, 2)
+	})
+
+	t.Run("restoring a rule that is not in the trash should fail", func(t *testing.T) {
+		ruleService := createAlertRuleServiceWithTrash(t)
+		_, err := ruleService.RestoreAlertRule(ctx, orgID, "missing", models.ProvenanceNone, 0)
+		require.ErrorIs(t, err, models.ErrDeletedAlertRuleNotFound)
+	})
+
+	t.Run("restoring a rule whose UID exists should fail", func(t *testing.T) {
+		ruleService := createAlertRuleServiceWithTrash(t)
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("deleted", orgID), models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.NoError(t, ruleService.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceNone))
+		recreated := dummyRule("recreated", orgID)
+		recreated.UID = rule.UID
+		_, err = ruleService.CreateAlertRule(ctx, recreated, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		_, err = ruleService.RestoreAlertRule(ctx, orgID, rule.UID, models.ProvenanceNone, 0)
+		require.ErrorIs(t, err, ErrDeletedAlertRuleExists)
+
+		deleted, err := ruleService.ListDeletedRules(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+	})
+
+	t.Run("cleaning up the trash should remove the rules whose retention passed", func(t *testing.T) {
+		ruleService := createAlertRuleServiceWithTrash(t)
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("deleted", orgID), models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.NoError(t, ruleService.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceNone))
+
+		removed, err := ruleService.CleanupTrash(ctx, time.Now())
+		require.NoError(t, err)
+		require.Zero(t, removed)
+
+		removed, err = ruleService.CleanupTrash(ctx, time.Now().Add(2*time.Hour))
+		require.NoError(t, err)
+		require.EqualValues(t, 1, removed)
+
+		_, err = ruleService.RestoreAlertRule(ctx, orgID, rule.UID, models.ProvenanceNone, 0)
+		require.ErrorIs(t, err, models.ErrDeletedAlertRuleNotFound)
+	})
+
+	t.Run("deleted rules should not be kept when the trash is disabled", func(t *testing.T) {
+		ruleService := createAlertRuleServiceWithTrash(t)
+		ruleService.trashRetention = 0
+		rule, err := ruleService.CreateAlertRule(ctx, dummyRule("deleted", orgID), models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		require.NoError(t, ruleService.DeleteAlertRule(ctx, orgID, rule.UID, models.ProvenanceNone))
+
+		deleted, err := ruleService.ListDeletedRules(ctx, orgID)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+}
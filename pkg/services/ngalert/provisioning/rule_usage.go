@@ -0,0 +1,337 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// defaultRuleUsageWindow is the window that the usage of rules is aggregated over when the query does not start it.
+const defaultRuleUsageWindow = 7 * 24 * time.Hour
+
+// ruleUsageHistoryPageSize is the number of state changes of a rule that are read from the state history at once.
+const ruleUsageHistoryPageSize = 1000
+
+// StateHistory queries the state history of alert rules.
+type StateHistory interface {
+	Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error)
+}
+
+// RuleUsageQuery selects the provisioned alert rules whose usage is aggregated, and the window it is aggregated over.
+type RuleUsageQuery struct {
+	OrgID         int64
+	NamespaceUIDs []string
+	// Provenances, if set, keeps only the rules that have one of them. All the provisioned rules are kept otherwise.
+	Provenances []models.Provenance
+	From        time.Time
+	To          time.Time
+	// NeverFired keeps only the rules that did not fire in the window.
+	NeverFired bool
+}
+
+// RuleUsage is the usage of a provisioned alert rule over a window.
+type RuleUsage struct {
+	Rule       *models.AlertRule
+	Provenance models.Provenance
+	// TransitionCount is the number of times the alerts of the rule changed state.
+	TransitionCount int64
+	// FiringCount is the number of times the alerts of the rule started firing.
+	FiringCount int64
+	// FiringOrResolvedCount is the number of times the alerts of the rule started firing or were resolved, which are
+	// the state changes that are sent to the notification policies. It is not the number of notifications that the
+	// policies sent, as they group, inhibit and silence the alerts.
+	FiringOrResolvedCount int64
+	// LastFiredAt is the last time an alert of the rule started firing, zero if none did in the window.
+	LastFiredAt time.Time
+}
+
+// RuleUsageResult is the usage of the provisioned alert rules over a window.
+type RuleUsageResult struct {
+	From  time.Time
+	To    time.Time
+	Rules []RuleUsage
+}
+
+// RuleUsageService aggregates the state history of provisioned alert rules, so that the rules that never fire can be
+// found and cleaned up.
+type RuleUsageService struct {
+	ruleStore       RuleStore
+	provenanceStore ProvisioningStore
+	history         StateHistory
+	log             log.Logger
+}
+
+func NewRuleUsageService(rules RuleStore, provenances ProvisioningStore, history StateHistory, log log.Logger) *RuleUsageService {
+	return &RuleUsageService{
+		ruleStore:       rules,
+		provenanceStore: provenances,
+		history:         history,
+		log:             log,
+	}
+}
+
+// GetRuleUsage returns the usage of the provisioned alert rules that match the query, ordered by folder, group and
+// position in the group. The window ends now and starts a week before it if the query does not set them.
+func (s *RuleUsageService) GetRuleUsage(ctx context.Context, user identity.Requester, query RuleUsageQuery) (*RuleUsageResult, error) {
+	if query.To.IsZero() {
+		query.To = time.Now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-defaultRuleUsageWindow)
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: the start of the window must be before its end", ErrValidation)
+	}
+	provenances := query.Provenances
+	for _, p := range provenances {
+		if p == models.ProvenanceNone {
+			return nil, fmt.Errorf("%w: the usage is only available for provisioned alert rules", ErrValidation)
+		}
+	}
+	if len(provenances) == 0 {
+		provenances = []models.Provenance{models.ProvenanceAPI, models.ProvenanceFile}
+	}
+	rules, err := s.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         query.OrgID,
+		NamespaceUIDs: query.NamespaceUIDs,
+		Provenances:   provenances,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &RuleUsageResult{From: query.From, To: query.To, Rules: make([]RuleUsage, 0, len(rules))}
+	if len(rules) == 0 {
+		return result, nil
+	}
+	ruleProvenances, err := s.provenanceStore.GetProvenances(ctx, query.OrgID, rules[0].ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		usage, err := s.queryRuleUsage(ctx, user, rule, query.From, query.To)
+		if err != nil {
+			return nil, err
+		}
+		if query.NeverFired && usage.FiringCount > 0 {
+			continue
+		}
+		usage.Rule = rule
+		usage.Provenance = ruleProvenances[rule.UID]
+		result.Rules = append(result.Rules, usage)
+	}
+	return result, nil
+}
+
+// queryRuleUsage aggregates the state history of the rule over the window. The backends return at most the limit of
+// the query, starting from the most recent state changes, so the window is read in pages that each end where the
+// previous one reached until a page is not full.
+func (s *RuleUsageService) queryRuleUsage(ctx context.Context, user identity.Requester, rule *models.AlertRule, from, to time.Time) (RuleUsage, error) {
+	var usage RuleUsage
+	end := to
+	for !end.Before(from) {
+		// The end of the page is moved a bit so that the state changes at its end are read whether the backend
+		// includes it or not, and the ones after it, which were counted with the previous page, are skipped.
+		frame, err := s.history.Query(ctx, models.HistoryQuery{
+			RuleUID:      rule.UID,
+			OrgID:        rule.OrgID,
+			From:         from,
+			To:           end.Add(time.Millisecond),
+			Limit:        ruleUsageHistoryPageSize,
+			SignedInUser: user,
+		})
+		if err != nil {
+			return usage, fmt.Errorf("failed to query the state history of alert rule %s: %w", rule.UID, err)
+		}
+		times := stateHistoryTimes(frame)
+		if len(times) < ruleUsageHistoryPageSize {
+			addStateHistory(&usage, frame, func(t time.Time) bool { return !t.After(end) })
+			return usage, nil
+		}
+		oldest := slices.MinFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+		if !oldest.Before(end) {
+			// The whole page happened at the end of the previous one. The state changes that did not fit in the page
+			// cannot be read, so they are skipped rather than read again forever.
+			s.log.Warn("Too many state changes of the alert rule at the same time, some are not counted", "rule_uid", rule.UID, "time", end)
+			addStateHistory(&usage, frame, func(t time.Time) bool { return !t.After(end) })
+			end = end.Add(-time.Nanosecond)
+			continue
+		}
+		// The state changes at the oldest time might not all fit in the page, they are counted with the next one.
+		addStateHistory(&usage, frame, func(t time.Time) bool { return t.After(oldest) && !t.After(end) })
+		end = oldest
+	}
+	return usage, nil
+}
+
+// Sort orders of the noise report of rule groups. Groups are sorted from the noisiest in both cases.
+const (
+	NoiseReportSortFlappiness       = "flappiness"
+	NoiseReportSortFiringOrResolved = "firingOrResolved"
+)
+
+// RuleGroupNoise is the noise of the provisioned alert rules of a rule group over a window.
+type RuleGroupNoise struct {
+	NamespaceUID string
+	RuleGroup    string
+	RuleCount    int
+	// TransitionCount is the number of times the alerts of the rules of the group changed state.
+	TransitionCount int64
+	// FiringOrResolvedCount is the number of times the alerts of the rules of the group started firing or were
+	// resolved.
+	FiringOrResolvedCount int64
+	// Flappiness is the number of state changes of the alerts of the rules of the group per day.
+	Flappiness float64
+}
+
+// RuleGroupNoiseReport is the noise of the rule groups of provisioned alert rules over a window.
+type RuleGroupNoiseReport struct {
+	From   time.Time
+	To     time.Time
+	Groups []RuleGroupNoise
+}
+
+// GetRuleGroupNoiseReport aggregates the usage of the provisioned alert rules that match the query by rule group, and
+// returns the groups sorted from the noisiest by the sort order. NeverFired is ignored, as the noise of a group is the
+// noise of all its rules. All the groups are returned if the limit is zero.
+func (s *RuleUsageService) GetRuleGroupNoiseReport(ctx context.Context, user identity.Requester, query RuleUsageQuery, sortBy string, limit int) (*RuleGroupNoiseReport, error) {
+	if sortBy == "" {
+		sortBy = NoiseReportSortFlappiness
+	}
+	if sortBy != NoiseReportSortFlappiness && sortBy != NoiseReportSortFiringOrResolved {
+		return nil, fmt.Errorf("%w: unknown sort order '%s', must be one of %s or %s", ErrValidation, sortBy, NoiseReportSortFlappiness, NoiseReportSortFiringOrResolved)
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("%w: the limit must be positive", ErrValidation)
+	}
+	query.NeverFired = false
+	usage, err := s.GetRuleUsage(ctx, user, query)
+	if err != nil {
+		return nil, err
+	}
+
+	days := usage.To.Sub(usage.From).Hours() / 24
+	groups := make([]RuleGroupNoise, 0)
+	index := map[models.AlertRuleGroupKey]int{}
+	for _, rule := range usage.Rules {
+		key := rule.Rule.GetGroupKey()
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, RuleGroupNoise{NamespaceUID: key.NamespaceUID, RuleGroup: key.RuleGroup})
+		}
+		groups[i].RuleCount++
+		groups[i].TransitionCount += rule.TransitionCount
+		groups[i].FiringOrResolvedCount += rule.FiringOrResolvedCount
+	}
+	for i := range groups {
+		groups[i].Flappiness = float64(groups[i].TransitionCount) / days
+	}
+	// Groups are read ordered by folder and name, which the stable sort keeps for groups that are as noisy.
+	sort.SliceStable(groups, func(i, j int) bool {
+		if sortBy == NoiseReportSortFiringOrResolved && groups[i].FiringOrResolvedCount != groups[j].FiringOrResolvedCount {
+			return groups[i].FiringOrResolvedCount > groups[j].FiringOrResolvedCount
+		}
+		return groups[i].TransitionCount > groups[j].TransitionCount
+	})
+	if limit > 0 && len(groups) > limit {
+		groups = groups[:limit]
+	}
+	return &RuleGroupNoiseReport{From: usage.From, To: usage.To, Groups: groups}, nil
+}
+
+// aggregateStateHistory counts the state changes of the frames that the state history backends return.
+func aggregateStateHistory(frame *data.Frame) RuleUsage {
+	var usage RuleUsage
+	addStateHistory(&usage, frame, func(time.Time) bool { return true })
+	return usage
+}
+
+// stateHistoryTimes returns the times of the state changes of a frame that a state history backend returns.
+func stateHistoryTimes(frame *data.Frame) []time.Time {
+	if frame == nil {
+		return nil
+	}
+	field, _ := frame.FieldByName("time")
+	if field == nil {
+		return nil
+	}
+	times := make([]time.Time, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		if t, ok := field.At(i).(time.Time); ok {
+			times = append(times, t)
+		}
+	}
+	return times
+}
+
+// addStateHistory adds the state changes of the frame at the times that are kept to the usage. The frames of the
+// annotation backend have the previous and next states in the prev and next fields, the ones of the Loki backend have
+// them in the JSON entries of the line field.
+func addStateHistory(usage *RuleUsage, frame *data.Frame, keep func(time.Time) bool) {
+	if frame == nil {
+		return
+	}
+	times, _ := frame.FieldByName("time")
+	if times == nil {
+		return
+	}
+	prev, _ := frame.FieldByName("prev")
+	next, _ := frame.FieldByName("next")
+	lines, _ := frame.FieldByName("line")
+	for i := 0; i < times.Len(); i++ {
+		t, _ := times.At(i).(time.Time)
+		if !keep(t) {
+			continue
+		}
+		var previous, current string
+		switch {
+		case prev != nil && next != nil:
+			previous, _ = prev.At(i).(string)
+			current, _ = next.At(i).(string)
+		case lines != nil:
+			line, _ := lines.At(i).(json.RawMessage)
+			var entry struct {
+				Previous string `json:"previous"`
+				Current  string `json:"current"`
+			}
+			if err := json.Unmarshal(line, &entry); err != nil {
+				continue
+			}
+			previous, current = entry.Previous, entry.Current
+		default:
+			return
+		}
+		from, _, err := state.ParseFormattedState(previous)
+		if err != nil {
+			continue
+		}
+		to, _, err := state.ParseFormattedState(current)
+		if err != nil {
+			continue
+		}
+		usage.TransitionCount++
+		switch {
+		case to == eval.Alerting && from != eval.Alerting:
+			usage.FiringCount++
+			usage.FiringOrResolvedCount++
+			if t.After(usage.LastFiredAt) {
+				usage.LastFiredAt = t
+			}
+		case from == eval.Alerting && to == eval.Normal:
+			usage.FiringOrResolvedCount++
+		}
+	}
+}
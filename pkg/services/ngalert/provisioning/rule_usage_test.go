@@ -0,0 +1,265 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeStateHistory struct {
+	frames  map[string]*data.Frame
+	queries []models.HistoryQuery
+}
+
+func (h *fakeStateHistory) Query(_ context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	h.queries = append(h.queries, query)
+	if frame, ok := h.frames[query.RuleUID]; ok {
+		return frame, nil
+	}
+	return data.NewFrame("states"), nil
+}
+
+type stateHistoryEntry struct {
+	time              time.Time
+	previous, current string
+}
+
+// pagedStateHistory returns the most recent state changes of the window, at most the limit of the query, like the
+// state history backends do.
+type pagedStateHistory struct {
+	entries map[string][]stateHistoryEntry
+	queries []models.HistoryQuery
+}
+
+func (h *pagedStateHistory) Query(_ context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	h.queries = append(h.queries, query)
+	var times []time.Time
+	var prev, next []string
+	for _, entry := range h.entries[query.RuleUID] {
+		if entry.time.Before(query.From) || entry.time.After(query.To) {
+			continue
+		}
+		if query.Limit > 0 && len(times) == query.Limit {
+			break
+		}
+		times = append(times, entry.time)
+		prev = append(prev, entry.previous)
+		next = append(next, entry.current)
+	}
+	return annotationStatesFrame(times, prev, next), nil
+}
+
+func annotationStatesFrame(times []time.Time, prev, next []string) *data.Frame {
+	return data.NewFrame("states",
+		data.NewField("time", nil, times),
+		data.NewField("text", nil, make([]string, len(times))),
+		data.NewField("prev", nil, prev),
+		data.NewField("next", nil, next),
+		data.NewField("data", nil, make([]string, len(times))),
+	)
+}
+
+func TestGetRuleUsage(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	fired, err := ruleService.CreateAlertRule(ctx, dummyRule("fired", orgID), models.ProvenanceFile, 0)
+	require.NoError(t, err)
+	dead, err := ruleService.CreateAlertRule(ctx, dummyRule("dead", orgID), models.ProvenanceAPI, 0)
+	require.NoError(t, err)
+	_, err = ruleService.CreateAlertRule(ctx, dummyRule("not provisioned", orgID), models.ProvenanceNone, 0)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	history := &fakeStateHistory{frames: map[string]*data.Frame{
+		fired.UID: annotationStatesFrame(
+			[]time.Time{now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now.Add(-time.Hour)},
+			[]string{"Normal", "Alerting", "Pending"},
+			[]string{"Alerting", "Normal", "Alerting"},
+		),
+	}}
+	usageService := NewRuleUsageService(ruleService.ruleStore, ruleService.provenanceStore, history, log.NewNopLogger())
+
+	t.Run("should aggregate the state history of the provisioned rules", func(t *testing.T) {
+		result, err := usageService.GetRuleUsage(ctx, nil, RuleUsageQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Equal(t, defaultRuleUsageWindow, result.To.Sub(result.From))
+		require.Len(t, result.Rules, 2)
+
+		usages := map[string]RuleUsage{}
+		for _, usage := range result.Rules {
+			usages[usage.Rule.UID] = usage
+		}
+		require.Equal(t, models.ProvenanceFile, usages[fired.UID].Provenance)
+		require.EqualValues(t, 3, usages[fired.UID].TransitionCount)
+		require.EqualValues(t, 2, usages[fired.UID].FiringCount)
+		require.EqualValues(t, 3, usages[fired.UID].FiringOrResolvedCount)
+		require.Equal(t, now.Add(-time.Hour), usages[fired.UID].LastFiredAt)
+		require.Equal(t, models.ProvenanceAPI, usages[dead.UID].Provenance)
+		require.Zero(t, usages[dead.UID].FiringCount)
+		require.True(t, usages[dead.UID].LastFiredAt.IsZero())
+	})
+
+	t.Run("should query the state history over the window", func(t *testing.T) {
+		history.queries = nil
+		from, to := now.Add(-time.Hour), now
+		_, err := usageService.GetRuleUsage(ctx, nil, RuleUsageQuery{OrgID: orgID, From: from, To: to})
+		require.NoError(t, err)
+		require.Len(t, history.queries, 2)
+		for _, query := range history.queries {
+			require.Equal(t, orgID, query.OrgID)
+			require.Equal(t, from, query.From)
+			require.Equal(t, to.Add(time.Millisecond), query.To)
+			require.Equal(t, ruleUsageHistoryPageSize, query.Limit)
+		}
+	})
+
+	t.Run("should page through the state history of the rules", func(t *testing.T) {
+		// One and a half pages of state changes that end an hour ago, two per second but the most recent one, so that
+		// the two state changes of a second are split between the pages.
+		var entries []stateHistoryEntry
+		for i := 0; i < ruleUsageHistoryPageSize*3/2; i++ {
+			entry := stateHistoryEntry{time: now.Add(-time.Hour - time.Duration((i+1)/2)*time.Second), previous: "Normal", current: "Alerting"}
+			if i%2 == 1 {
+				entry.previous, entry.current = "Alerting", "Normal"
+			}
+			entries = append(entries, entry)
+		}
+		paged := &pagedStateHistory{entries: map[string][]stateHistoryEntry{fired.UID: entries}}
+		pagedService := NewRuleUsageService(ruleService.ruleStore, ruleService.provenanceStore, paged, log.NewNopLogger())
+
+		result, err := pagedService.GetRuleUsage(ctx, nil, RuleUsageQuery{OrgID: orgID, Provenances: []models.Provenance{models.ProvenanceFile}})
+		require.NoError(t, err)
+		require.Len(t, result.Rules, 1)
+		usage := result.Rules[0]
+		require.EqualValues(t, len(entries), usage.TransitionCount)
+		require.EqualValues(t, len(entries)/2, usage.FiringCount)
+		require.EqualValues(t, len(entries), usage.FiringOrResolvedCount)
+		require.Equal(t, now.Add(-time.Hour), usage.LastFiredAt)
+		require.Len(t, paged.queries, 2)
+	})
+
+	t.Run("should only return the rules that never fired", func(t *testing.T) {
+		result, err := usageService.GetRuleUsage(ctx, nil, RuleUsageQuery{OrgID: orgID, NeverFired: true})
+		require.NoError(t, err)
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, dead.UID, result.Rules[0].Rule.UID)
+	})
+
+	t.Run("should filter the rules by provenance", func(t *testing.T) {
+		result, err := usageService.GetRuleUsage(ctx, nil, RuleUsageQuery{OrgID: orgID, Provenances: []models.Provenance{models.ProvenanceFile}})
+		require.NoError(t, err)
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, fired.UID, result.Rules[0].Rule.UID)
+	})
+
+	t.Run("should reject rules that are not provisioned", func(t *testing.T) {
+		_, err := usageService.GetRuleUsage(ctx, nil, RuleUsageQuery{OrgID: orgID, Provenances: []models.Provenance{models.ProvenanceNone}})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("should reject a window that ends before it starts", func(t *testing.T) {
+		_, err := usageService.GetRuleUsage(ctx, nil, RuleUsageQuery{OrgID: orgID, From: now, To: now.Add(-time.Hour)})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func TestGetRuleGroupNoiseReport(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 1
+	ctx := context.Background()
+
+	flapping, err := ruleService.CreateAlertRule(ctx, createTestRule("flapping", "flapping-group", orgID, "my-namespace"), models.ProvenanceAPI, 0)
+	require.NoError(t, err)
+	notifying, err := ruleService.CreateAlertRule(ctx, createTestRule("notifying", "notifying-group", orgID, "my-namespace"), models.ProvenanceAPI, 0)
+	require.NoError(t, err)
+	_, err = ruleService.CreateAlertRule(ctx, createTestRule("quiet", "notifying-group", orgID, "my-namespace"), models.ProvenanceFile, 0)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Second)
+	times := func(n int) []time.Time {
+		result := make([]time.Time, n)
+		for i := range result {
+			result[i] = now.Add(-time.Duration(i+1) * time.Minute)
+		}
+		return result
+	}
+	history := &fakeStateHistory{frames: map[string]*data.Frame{
+		flapping.UID: annotationStatesFrame(times(4),
+			[]string{"Normal", "Pending", "Normal", "Pending"},
+			[]string{"Pending", "Normal", "Pending", "Normal"},
+		),
+		notifying.UID: annotationStatesFrame(times(2),
+			[]string{"Normal", "Alerting"},
+			[]string{"Alerting", "Normal"},
+		),
+	}}
+	usageService := NewRuleUsageService(ruleService.ruleStore, ruleService.provenanceStore, history, log.NewNopLogger())
+	query := RuleUsageQuery{OrgID: orgID, From: now.Add(-48 * time.Hour), To: now}
+
+	t.Run("should sort the groups by flappiness", func(t *testing.T) {
+		report, err := usageService.GetRuleGroupNoiseReport(ctx, nil, query, "", 0)
+		require.NoError(t, err)
+		require.Equal(t, []RuleGroupNoise{
+			{NamespaceUID: "my-namespace", RuleGroup: "flapping-group", RuleCount: 1, TransitionCount: 4, Flappiness: 2},
+			{NamespaceUID: "my-namespace", RuleGroup: "notifying-group", RuleCount: 2, TransitionCount: 2, FiringOrResolvedCount: 2, Flappiness: 1},
+		}, report.Groups)
+	})
+
+	t.Run("should sort the groups by the number of alerts that fired or were resolved", func(t *testing.T) {
+		report, err := usageService.GetRuleGroupNoiseReport(ctx, nil, query, NoiseReportSortFiringOrResolved, 1)
+		require.NoError(t, err)
+		require.Len(t, report.Groups, 1)
+		require.Equal(t, "notifying-group", report.Groups[0].RuleGroup)
+	})
+
+	t.Run("should reject unknown sort orders", func(t *testing.T) {
+		_, err := usageService.GetRuleGroupNoiseReport(ctx, nil, query, "title", 0)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func TestAggregateStateHistory(t *testing.T) {
+	now := time.Now()
+
+	t.Run("should count the state changes of Loki entries", func(t *testing.T) {
+		line := func(previous, current string) json.RawMessage {
+			return json.RawMessage(`{"schemaVersion": 1, "previous": "` + previous + `", "current": "` + current + `"}`)
+		}
+		frame := data.NewFrame("states",
+			data.NewField("time", nil, []time.Time{now.Add(-2 * time.Minute), now.Add(-time.Minute), now}),
+			data.NewField("line", nil, []json.RawMessage{
+				line("Normal", "Alerting (NoData)"),
+				line("Alerting (NoData)", "Normal"),
+				line("Normal", "Pending"),
+			}),
+			data.NewField("labels", nil, []json.RawMessage{json.RawMessage(`{}`), json.RawMessage(`{}`), json.RawMessage(`{}`)}),
+		)
+
+		usage := aggregateStateHistory(frame)
+		require.EqualValues(t, 1, usage.FiringCount)
+		require.EqualValues(t, 2, usage.FiringOrResolvedCount)
+		require.Equal(t, now.Add(-2*time.Minute), usage.LastFiredAt)
+	})
+
+	t.Run("should ignore the states that cannot be parsed", func(t *testing.T) {
+		frame := annotationStatesFrame([]time.Time{now}, []string{"Unknown"}, []string{"Alerting"})
+
+		usage := aggregateStateHistory(frame)
+		require.Zero(t, usage.FiringCount)
+		require.Zero(t, usage.FiringOrResolvedCount)
+	})
+
+	t.Run("should return no usage for empty frames", func(t *testing.T) {
+		require.Zero(t, aggregateStateHistory(data.NewFrame("states")).FiringOrResolvedCount)
+		require.Zero(t, aggregateStateHistory(nil).FiringOrResolvedCount)
+	})
+}
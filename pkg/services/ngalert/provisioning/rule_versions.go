@@ -0,0 +1,107 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// AlertRuleRevision is a version of an alert rule, with the fields that were changed from the version it was created
+// from.
+type AlertRuleRevision struct {
+	Rule          models.AlertRule
+	ParentVersion int64
+	// ChangedFields are the paths of the fields of the rule that were changed from the parent version, sorted. It is
+	// empty for the first version of the rule, or if the parent version is not stored anymore.
+	ChangedFields []string
+}
+
+// GetAlertRuleVersions returns the versions of the rule with the UID, the latest first. The latest version is the
+// current rule. The user must be able to read the rule.
+func (service *AlertRuleService) GetAlertRuleVersions(ctx context.Context, user identity.Requester, orgID int64, ruleUID string) ([]AlertRuleRevision, error) {
+	rule, _, err := service.GetAlertRule(ctx, orgID, ruleUID)
+	if err != nil {
+		return nil, err
+	}
+	if service.authz != nil {
+		if err := service.authz.AuthorizeAccessToRuleGroup(ctx, user, models.RulesGroup{&rule}); err != nil {
+			return nil, err
+		}
+	}
+	versions, err := service.ruleStore.GetAlertRuleVersions(ctx, orgID, ruleUID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make(map[int64]models.AlertRule, len(versions))
+	for _, v := range versions {
+		rules[v.Version] = v.AlertRule()
+	}
+	result := make([]AlertRuleRevision, 0, len(versions))
+	for _, v := range versions {
+		revision := AlertRuleRevision{Rule: rules[v.Version], ParentVersion: v.ParentVersion}
+		if parent, ok := rules[v.ParentVersion]; ok && v.ParentVersion != v.Version {
+			revision.ChangedFields = store.CalculateRuleDelta(&parent, &revision.Rule).ChangedFields
+		}
+		result = append(result, revision)
+	}
+	return result, nil
+}
+
+// RestoreAlertRuleVersion updates the rule with the UID to what it was at the version, which creates a new version. The
+// rule stays in its group, with the properties of the group, and is neither paused nor resumed. The provenance of the
+// rule must be the given one, unless it is not provisioned, and the user must be able to update the rule. It returns
+// models.ErrAlertRuleVersionNotFound if the version is not stored.
+func (service *AlertRuleService) RestoreAlertRuleVersion(ctx context.Context, user identity.Requester, orgID int64, ruleUID string, version int64, provenance models.Provenance) (models.AlertRule, error) {
+	var restored models.AlertRule
+	err := service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		current, storedProvenance, err := service.GetAlertRule(ctx, orgID, ruleUID)
+		if err != nil {
+			return err
+		}
+		if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+			return provenanceNotAllowed(ctx, service.provenanceStore, "restore", &current, storedProvenance, provenance)
+		}
+		versions, err := service.ruleStore.GetAlertRuleVersions(ctx, orgID, ruleUID)
+		if err != nil {
+			return err
+		}
+		idx := slices.IndexFunc(versions, func(v *models.AlertRuleVersion) bool {
+			return v.Version == version
+		})
+		if idx < 0 {
+			return fmt.Errorf("%w: version %d of alert rule %s", models.ErrAlertRuleVersionNotFound, version, ruleUID)
+		}
+		rule := versions[idx].AlertRule()
+		rule.NamespaceUID = current.NamespaceUID
+		rule.RuleGroup = current.RuleGroup
+		rule.RuleGroupIndex = current.RuleGroupIndex
+		rule.IsPaused = current.IsPaused
+		rule.Version = current.Version
+		if service.authz != nil {
+			group, err := service.ruleStore.GetAlertRulesGroupByRuleUID(ctx, &models.GetAlertRulesGroupByRuleUIDQuery{OrgID: orgID, UID: ruleUID})
+			if err != nil {
+				return err
+			}
+			key := current.GetGroupKey()
+			err = service.authorizeRuleChanges(ctx, user, &store.GroupDelta{
+				GroupKey:       key,
+				AffectedGroups: map[models.AlertRuleGroupKey]models.RulesGroup{key: group},
+				Update:         []store.RuleDelta{store.CalculateRuleDelta(&current, &rule)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+		restored, err = service.UpdateAlertRule(ctx, rule, provenance)
+		return err
+	})
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	service.log.Info("Restored alert rule version", "org", orgID, "rule_uid", ruleUID, "version", version)
+	return restored, nil
+}
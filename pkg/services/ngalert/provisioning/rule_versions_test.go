@@ -0,0 +1,151 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type fakeRuleAccessControl struct {
+	readErr     error
+	changeErr   error
+	changes     []*store.GroupDelta
+	canWriteAll bool
+}
+
+func (f *fakeRuleAccessControl) HasAccessToRuleGroup(context.Context, identity.Requester, models.RulesGroup) (bool, error) {
+	return f.readErr == nil, nil
+}
+
+func (f *fakeRuleAccessControl) HasAccessInFolder(context.Context, identity.Requester, string) (bool, error) {
+	return f.readErr == nil, nil
+}
+
+func (f *fakeRuleAccessControl) AuthorizeAccessToRuleGroup(context.Context, identity.Requester, models.RulesGroup) error {
+	return f.readErr
+}
+
+func (f *fakeRuleAccessControl) AuthorizeRuleChanges(_ context.Context, _ identity.Requester, change *store.GroupDelta) error {
+	f.changes = append(f.changes, change)
+	return f.changeErr
+}
+
+func (f *fakeRuleAccessControl) CanWriteAllRules(context.Context, identity.Requester) (bool, error) {
+	return f.canWriteAll, nil
+}
+
+func (f *fakeRuleAccessControl) CanReadAllRules(context.Context, identity.Requester) (bool, error) {
+	return f.readErr == nil, nil
+}
+
+func TestAlertRuleVersions(t *testing.T) {
+	var orgID int64 = 1
+	ctx := context.Background()
+	requester := &user.SignedInUser{OrgID: orgID}
+
+	createRuleWithVersions := func(t *testing.T, ruleService *AlertRuleService, provenance models.Provenance) models.AlertRule {
+		t.Helper()
+		rule := dummyRule("first", orgID)
+		rule.Labels = map[string]string{"team": "platform"}
+		rule, err := ruleService.CreateAlertRule(ctx, rule, provenance, 0)
+		require.NoError(t, err)
+		rule.Title = "second"
+		rule, err = ruleService.UpdateAlertRule(ctx, rule, provenance)
+		require.NoError(t, err)
+		rule.Labels = map[string]string{"team": "alerting"}
+		rule, err = ruleService.UpdateAlertRule(ctx, rule, provenance)
+		require.NoError(t, err)
+		return rule
+	}
+
+	t.Run("should return the versions of the rule with the changed fields", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		rule := createRuleWithVersions(t, &ruleService, models.ProvenanceNone)
+
+		versions, err := ruleService.GetAlertRuleVersions(ctx, requester, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+		require.EqualValues(t, 3, versions[0].Rule.Version)
+		require.EqualValues(t, 2, versions[0].ParentVersion)
+		require.Equal(t, []string{"Labels[team]"}, versions[0].ChangedFields)
+		require.Equal(t, "second", versions[1].Rule.Title)
+		require.Equal(t, []string{"Title"}, versions[1].ChangedFields)
+		require.Equal(t, "first", versions[2].Rule.Title)
+		require.Empty(t, versions[2].ChangedFields)
+	})
+
+	t.Run("should restore a version of the rule", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		rule := createRuleWithVersions(t, &ruleService, models.ProvenanceAPI)
+
+		restored, err := ruleService.RestoreAlertRuleVersion(ctx, requester, orgID, rule.UID, 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, "first", restored.Title)
+
+		stored, _, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, "first", stored.Title)
+		require.Equal(t, map[string]string{"team": "platform"}, stored.Labels)
+
+		versions, err := ruleService.GetAlertRuleVersions(ctx, requester, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Len(t, versions, 4)
+		require.Equal(t, []string{"Labels[team]", "Title"}, versions[0].ChangedFields)
+	})
+
+	t.Run("should fail to restore a version that is not stored", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		rule := createRuleWithVersions(t, &ruleService, models.ProvenanceNone)
+
+		_, err := ruleService.RestoreAlertRuleVersion(ctx, requester, orgID, rule.UID, 10, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleVersionNotFound)
+	})
+
+	t.Run("should fail to restore a version of a rule with another provenance", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		rule := createRuleWithVersions(t, &ruleService, models.ProvenanceFile)
+
+		_, err := ruleService.RestoreAlertRuleVersion(ctx, requester, orgID, rule.UID, 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrProvenanceNotAllowed)
+
+		stored, _, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, "second", stored.Title)
+	})
+
+	t.Run("should fail for unknown rules", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+
+		_, err := ruleService.GetAlertRuleVersions(ctx, requester, orgID, "missing")
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+		_, err = ruleService.RestoreAlertRuleVersion(ctx, requester, orgID, "missing", 1, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+
+	t.Run("should authorize the user", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		rule := createRuleWithVersions(t, &ruleService, models.ProvenanceNone)
+		authz := &fakeRuleAccessControl{readErr: errors.New("read denied"), changeErr: errors.New("update denied")}
+		ruleService.authz = authz
+
+		_, err := ruleService.GetAlertRuleVersions(ctx, requester, orgID, rule.UID)
+		require.ErrorIs(t, err, authz.readErr)
+
+		_, err = ruleService.RestoreAlertRuleVersion(ctx, requester, orgID, rule.UID, 1, models.ProvenanceNone)
+		require.ErrorIs(t, err, authz.changeErr)
+		require.Len(t, authz.changes, 1)
+		require.Len(t, authz.changes[0].Update, 1)
+		require.Equal(t, "first", authz.changes[0].Update[0].New.Title)
+
+		stored, _, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, "second", stored.Title)
+	})
+}
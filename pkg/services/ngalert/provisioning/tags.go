@@ -0,0 +1,244 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// TagStore is a store of the tags of provisioned resources.
+type TagStore interface {
+	GetResourceTags(ctx context.Context, o models.Provisionable, org int64) ([]string, error)
+	// GetTaggedResources returns the IDs of the resources of the given type that have the tag.
+	GetTaggedResources(ctx context.Context, org int64, resourceType string, tag string) ([]string, error)
+	SetResourceTags(ctx context.Context, o models.Provisionable, org int64, tags []string) error
+	DeleteResourceTags(ctx context.Context, o models.Provisionable, org int64) error
+}
+
+// TagService manages the tags of provisioned alert rules, rule groups and contact points. Tags are free-form strings
+// that help to organize provisioned resources, e.g. to select the rules of a canary rollout. Unlike labels, they are
+// not part of the alerts and notifications.
+type TagService struct {
+	tagStore        TagStore
+	ruleStore       RuleStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	// authz authorizes the changes of the rules. Users are not checked if it is nil.
+	authz RuleAccessControlService
+	log   log.Logger
+}
+
+func NewTagService(tags TagStore, rules RuleStore, provenances ProvisioningStore, xact TransactionManager, authz RuleAccessControlService, log log.Logger) *TagService {
+	return &TagService{
+		tagStore:        tags,
+		ruleStore:       rules,
+		provenanceStore: provenances,
+		xact:            xact,
+		authz:           authz,
+		log:             log,
+	}
+}
+
+// GetTags returns the tags of the resource, sorted.
+func (s *TagService) GetTags(ctx context.Context, orgID int64, o models.Provisionable) ([]string, error) {
+	return s.tagStore.GetResourceTags(ctx, o, orgID)
+}
+
+// SetTags replaces the tags of the resource and returns them as they are stored.
+func (s *TagService) SetTags(ctx context.Context, orgID int64, o models.Provisionable, tags []string) ([]string, error) {
+	tags, err := models.NormalizeResourceTags(tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if err := s.tagStore.SetResourceTags(ctx, o, orgID, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// FilterRules returns the rules that have the tag, or whose rule group has it.
+func (s *TagService) FilterRules(ctx context.Context, orgID int64, tag string, rules []*models.AlertRule) ([]*models.AlertRule, error) {
+	match, err := s.ruleMatcher(ctx, orgID, tag)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if match(rule) {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+// FilterRuleGroups returns the rule groups with only the rules that have the tag, or all their rules if the group has
+// it. Groups without such rules are left out.
+func (s *TagService) FilterRuleGroups(ctx context.Context, orgID int64, tag string, groups []models.AlertRuleGroupWithFolderTitle) ([]models.AlertRuleGroupWithFolderTitle, error) {
+	match, err := s.ruleMatcher(ctx, orgID, tag)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.AlertRuleGroupWithFolderTitle, 0, len(groups))
+	for _, group := range groups {
+		rules := make([]models.AlertRule, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			if match(&rule) {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		filtered := *group.AlertRuleGroup
+		filtered.Rules = rules
+		group.AlertRuleGroup = &filtered
+		result = append(result, group)
+	}
+	return result, nil
+}
+
+// FilterContactPoints returns the contact points that have the tag.
+func (s *TagService) FilterContactPoints(ctx context.Context, orgID int64, tag string, contactPoints []definitions.EmbeddedContactPoint) ([]definitions.EmbeddedContactPoint, error) {
+	tagged, err := s.taggedResources(ctx, orgID, (&definitions.EmbeddedContactPoint{}).ResourceType(), tag)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.EmbeddedContactPoint, 0, len(contactPoints))
+	for _, contactPoint := range contactPoints {
+		if _, ok := tagged[contactPoint.UID]; ok {
+			result = append(result, contactPoint)
+		}
+	}
+	return result, nil
+}
+
+// SetRulesPausedByTag pauses or resumes the evaluation of the rules that have the tag, or whose rule group has it. It
+// returns the number of rules that were changed. Rules are changed only if the provenance of their group allows it, and
+// if the user may change the rules in their folders.
+func (s *TagService) SetRulesPausedByTag(ctx context.Context, user identity.Requester, orgID int64, tag string, paused bool, provenance models.Provenance) (int, error) {
+	if tag == "" {
+		return 0, fmt.Errorf("%w: tag is required", ErrValidation)
+	}
+	updated := 0
+	err := s.xact.InTransaction(ctx, func(ctx context.Context) error {
+		rules, err := s.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+		if err != nil {
+			return err
+		}
+		rules, err = s.FilterRules(ctx, orgID, tag, rules)
+		if err != nil {
+			return err
+		}
+		provenances, err := s.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+
+		changes := make([]models.UpdateRule, 0, len(rules))
+		for _, rule := range rules {
+			if rule.IsPaused == paused {
+				continue
+			}
+			if storedProvenance := provenances[rule.UID]; !canUpdateProvenanceInRuleGroup(storedProvenance, provenance) {
+				return provenanceNotAllowed(ctx, s.provenanceStore, "update", rule, storedProvenance, provenance)
+			}
+			changed := models.CopyRule(rule)
+			changed.IsPaused = paused
+			changed.Updated = time.Now()
+			changes = append(changes, models.UpdateRule{
+				Existing: rule,
+				New:      *changed,
+			})
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := s.authorizeChanges(ctx, user, changes); err != nil {
+			return err
+		}
+		if err := s.ruleStore.UpdateAlertRules(ctx, changes); err != nil {
+			return err
+		}
+		updated = len(changes)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.log.Info("Changed the paused state of tagged alert rules", "org", orgID, "tag", tag, "paused", paused, "count", updated)
+	return updated, nil
+}
+
+// authorizeChanges checks that the user may change the rules, group by group, so that the user needs access to the
+// folders of the rules only.
+func (s *TagService) authorizeChanges(ctx context.Context, user identity.Requester, changes []models.UpdateRule) error {
+	if s.authz == nil || user == nil {
+		return nil
+	}
+	canWriteAll, err := s.authz.CanWriteAllRules(ctx, user)
+	if err != nil {
+		return err
+	}
+	if canWriteAll {
+		return nil
+	}
+	deltas := make(map[models.AlertRuleGroupKey]*store.GroupDelta)
+	keys := make([]models.AlertRuleGroupKey, 0)
+	for i := range changes {
+		change := &changes[i]
+		key := change.Existing.GetGroupKey()
+		delta, ok := deltas[key]
+		if !ok {
+			delta = &store.GroupDelta{GroupKey: key}
+			deltas[key] = delta
+			keys = append(keys, key)
+		}
+		delta.Update = append(delta.Update, store.RuleDelta{
+			Existing: change.Existing,
+			New:      &change.New,
+		})
+	}
+	for _, key := range keys {
+		if err := s.authz.AuthorizeRuleChanges(ctx, user, deltas[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ruleMatcher returns a function that tells whether a rule has the tag, or its rule group has it.
+func (s *TagService) ruleMatcher(ctx context.Context, orgID int64, tag string) (func(rule *models.AlertRule) bool, error) {
+	taggedRules, err := s.taggedResources(ctx, orgID, (&models.AlertRule{}).ResourceType(), tag)
+	if err != nil {
+		return nil, err
+	}
+	taggedGroups, err := s.taggedResources(ctx, orgID, models.AlertRuleGroupKey{}.ResourceType(), tag)
+	if err != nil {
+		return nil, err
+	}
+	return func(rule *models.AlertRule) bool {
+		if _, ok := taggedRules[rule.UID]; ok {
+			return true
+		}
+		_, ok := taggedGroups[rule.GetGroupKey().ResourceID()]
+		return ok
+	}, nil
+}
+
+func (s *TagService) taggedResources(ctx context.Context, orgID int64, resourceType string, tag string) (map[string]struct{}, error) {
+	ids, err := s.tagStore.GetTaggedResources(ctx, orgID, resourceType, tag)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		result[id] = struct{}{}
+	}
+	return result, nil
+}
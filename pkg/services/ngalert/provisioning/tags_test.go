@@ -0,0 +1,148 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestTagService(t *testing.T) {
+	orgID := int64(1)
+	rules := []models.AlertRule{
+		{OrgID: orgID, UID: "rule-1", Title: "rule 1", NamespaceUID: "folder", RuleGroup: "group-1"},
+		{OrgID: orgID, UID: "rule-2", Title: "rule 2", NamespaceUID: "folder", RuleGroup: "group-1"},
+		{OrgID: orgID, UID: "rule-3", Title: "rule 3", NamespaceUID: "folder", RuleGroup: "group-2"},
+		{OrgID: orgID, UID: "rule-4", Title: "rule 4", NamespaceUID: "folder", RuleGroup: "group-3"},
+	}
+
+	t.Run("tags are normalized", func(t *testing.T) {
+		sut, _ := createTagServiceSut()
+		rule := &models.AlertRule{UID: "rule-1"}
+
+		tags, err := sut.SetTags(context.Background(), orgID, rule, []string{"team-a", " canary", "canary"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"canary", "team-a"}, tags)
+
+		stored, err := sut.GetTags(context.Background(), orgID, rule)
+		require.NoError(t, err)
+		require.Equal(t, tags, stored)
+	})
+
+	t.Run("invalid tags are rejected", func(t *testing.T) {
+		sut, _ := createTagServiceSut()
+
+		_, err := sut.SetTags(context.Background(), orgID, &models.AlertRule{UID: "rule-1"}, []string{""})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("rules are filtered by their tags and the tags of their group", func(t *testing.T) {
+		sut, store := createTagServiceSut()
+		store.PutRules(rules...)
+		_, err := sut.SetTags(context.Background(), orgID, &models.AlertRule{UID: "rule-1"}, []string{"canary"})
+		require.NoError(t, err)
+		_, err = sut.SetTags(context.Background(), orgID, rules[2].GetGroupKey(), []string{"canary"})
+		require.NoError(t, err)
+
+		filtered, err := sut.FilterRules(context.Background(), orgID, "canary", store.Rules(orgID))
+		require.NoError(t, err)
+		require.Equal(t, []string{"rule-1", "rule-3"}, ruleUIDs(filtered))
+	})
+
+	t.Run("contact points are filtered by their tags", func(t *testing.T) {
+		sut, _ := createTagServiceSut()
+		contactPoints := []definitions.EmbeddedContactPoint{{UID: "cp-1"}, {UID: "cp-2"}}
+		_, err := sut.SetTags(context.Background(), orgID, &contactPoints[1], []string{"canary"})
+		require.NoError(t, err)
+
+		filtered, err := sut.FilterContactPoints(context.Background(), orgID, "canary", contactPoints)
+		require.NoError(t, err)
+		require.Equal(t, []definitions.EmbeddedContactPoint{{UID: "cp-2"}}, filtered)
+	})
+
+	t.Run("tagged rules are paused and resumed", func(t *testing.T) {
+		sut, store := createTagServiceSut()
+		store.PutRules(rules...)
+		_, err := sut.SetTags(context.Background(), orgID, rules[0].GetGroupKey(), []string{"canary"})
+		require.NoError(t, err)
+
+		count, err := sut.SetRulesPausedByTag(context.Background(), nil, orgID, "canary", true, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		for _, rule := range store.Rules(orgID) {
+			require.Equal(t, rule.RuleGroup == "group-1", rule.IsPaused, rule.UID)
+		}
+
+		count, err = sut.SetRulesPausedByTag(context.Background(), nil, orgID, "canary", true, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Zero(t, count)
+
+		count, err = sut.SetRulesPausedByTag(context.Background(), nil, orgID, "canary", false, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("rules provisioned from files are not paused through the API", func(t *testing.T) {
+		sut, store := createTagServiceSut()
+		store.PutRules(rules...)
+		require.NoError(t, store.SetProvenance(context.Background(), &rules[3], orgID, models.ProvenanceFile))
+		_, err := sut.SetTags(context.Background(), orgID, &rules[3], []string{"canary"})
+		require.NoError(t, err)
+
+		_, err = sut.SetRulesPausedByTag(context.Background(), nil, orgID, "canary", true, models.ProvenanceAPI)
+		require.Error(t, err)
+		require.False(t, store.Rules(orgID)[3].IsPaused)
+	})
+
+	t.Run("the pause is authorized group by group", func(t *testing.T) {
+		sut, store := createTagServiceSut()
+		authz := &fakeRuleAccessControl{}
+		sut.authz = authz
+		store.PutRules(rules...)
+		_, err := sut.SetTags(context.Background(), orgID, rules[0].GetGroupKey(), []string{"canary"})
+		require.NoError(t, err)
+		_, err = sut.SetTags(context.Background(), orgID, &rules[2], []string{"canary"})
+		require.NoError(t, err)
+
+		_, err = sut.SetRulesPausedByTag(context.Background(), &user.SignedInUser{OrgID: orgID}, orgID, "canary", true, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Len(t, authz.changes, 2)
+		require.Equal(t, rules[0].GetGroupKey(), authz.changes[0].GroupKey)
+		require.Len(t, authz.changes[0].Update, 2)
+		require.Equal(t, rules[2].GetGroupKey(), authz.changes[1].GroupKey)
+		require.Len(t, authz.changes[1].Update, 1)
+		require.True(t, authz.changes[1].Update[0].New.IsPaused)
+	})
+
+	t.Run("rules are not paused if the user may not change them", func(t *testing.T) {
+		sut, store := createTagServiceSut()
+		sut.authz = &fakeRuleAccessControl{changeErr: errors.New("denied")}
+		store.PutRules(rules...)
+		_, err := sut.SetTags(context.Background(), orgID, &rules[0], []string{"canary"})
+		require.NoError(t, err)
+
+		_, err = sut.SetRulesPausedByTag(context.Background(), &user.SignedInUser{OrgID: orgID}, orgID, "canary", true, models.ProvenanceAPI)
+		require.Error(t, err)
+		require.False(t, store.Rules(orgID)[0].IsPaused)
+	})
+}
+
+func createTagServiceSut() (*TagService, *FakeStore) {
+	store := NewFakeStore()
+	return NewTagService(fakes.NewFakeProvisioningStore(), store, store, store, nil, log.NewNopLogger()), store
+}
+
+func ruleUIDs(rules []*models.AlertRule) []string {
+	uids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		uids = append(uids, rule.UID)
+	}
+	return uids
+}
@@ -3,6 +3,11 @@ package provisioning
 import (
 	"context"
 	"fmt"
+	"slices"
+	tmpltext "text/template"
+	"text/template/parse"
+
+	"github.com/prometheus/alertmanager/template"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
@@ -59,6 +64,10 @@ func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl def
 		return definitions.NotificationTemplate{}, err
 	}
 
+	if err := checkTemplateDependents(revision.cfg.TemplateFiles, tmpl.Name, tmpl.Template); err != nil {
+		return definitions.NotificationTemplate{}, err
+	}
+
 	if revision.cfg.TemplateFiles == nil {
 		revision.cfg.TemplateFiles = map[string]string{}
 	}
@@ -83,6 +92,10 @@ func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name
 		return err
 	}
 
+	if err := checkTemplateDependents(revision.cfg.TemplateFiles, name, ""); err != nil {
+		return err
+	}
+
 	delete(revision.cfg.TemplateFiles, name)
 
 	return t.xact.InTransaction(ctx, func(ctx context.Context) error {
@@ -95,3 +108,94 @@ func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name
 		return t.provenanceStore.DeleteProvenance(ctx, &tgt, orgID)
 	})
 }
+
+// checkTemplateDependents returns ErrTemplateInUse if replacing the content of the template file with the given name,
+// or deleting it when content is empty, removes templates that are used by other template files. Templates that are
+// defined by several files can be removed from any of them.
+func checkTemplateDependents(files map[string]string, name string, content string) error {
+	current, ok := files[name]
+	if !ok {
+		return nil
+	}
+	removed, _ := templateDependencies(name, current)
+	if content != "" {
+		defined, _ := templateDependencies(name, content)
+		removed = slices.DeleteFunc(removed, func(n string) bool { return slices.Contains(defined, n) })
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	// Templates that are also defined by other files are still available after the change.
+	for other, otherContent := range files {
+		if other == name {
+			continue
+		}
+		defined, _ := templateDependencies(other, otherContent)
+		removed = slices.DeleteFunc(removed, func(n string) bool { return slices.Contains(defined, n) })
+	}
+
+	var usedBy []string
+	for other, otherContent := range files {
+		if other == name {
+			continue
+		}
+		_, refers := templateDependencies(other, otherContent)
+		if slices.ContainsFunc(refers, func(n string) bool { return slices.Contains(removed, n) }) {
+			usedBy = append(usedBy, other)
+		}
+	}
+	if len(usedBy) == 0 {
+		return nil
+	}
+	slices.Sort(usedBy)
+	return MakeErrTemplateInUse(name, usedBy)
+}
+
+// templateDependencies returns the names of the templates defined by the template file, and the names of the
+// templates it refers to without defining them. A template file that cannot be parsed has no dependencies.
+func templateDependencies(name, content string) (defines []string, refers []string) {
+	t, err := tmpltext.New(name).Option("missingkey=zero").Funcs(tmpltext.FuncMap(template.DefaultFuncs)).Parse(content)
+	if err != nil {
+		return nil, nil
+	}
+	references := map[string]struct{}{}
+	for _, tmpl := range t.Templates() {
+		if tmpl.Tree == nil || (tmpl.Name() == name && parse.IsEmptyTree(tmpl.Tree.Root)) {
+			continue
+		}
+		defines = append(defines, tmpl.Name())
+		collectTemplateReferences(tmpl.Tree.Root, references)
+	}
+	for ref := range references {
+		if !slices.Contains(defines, ref) {
+			refers = append(refers, ref)
+		}
+	}
+	slices.Sort(defines)
+	slices.Sort(refers)
+	return defines, refers
+}
+
+func collectTemplateReferences(node parse.Node, references map[string]struct{}) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectTemplateReferences(child, references)
+		}
+	case *parse.TemplateNode:
+		references[n.Name] = struct{}{}
+	case *parse.IfNode:
+		collectTemplateReferences(n.List, references)
+		collectTemplateReferences(n.ElseList, references)
+	case *parse.RangeNode:
+		collectTemplateReferences(n.List, references)
+		collectTemplateReferences(n.ElseList, references)
+	case *parse.WithNode:
+		collectTemplateReferences(n.List, references)
+		collectTemplateReferences(n.ElseList, references)
+	}
+}
@@ -378,9 +378,78 @@ func TestTemplateService(t *testing.T) {
 
 			require.NoError(t, err)
 		})
+
+		t.Run("rejects deleting templates used by other templates", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.configStore.store.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithSharedTemplates,
+				})
+
+			err := sut.DeleteTemplate(context.Background(), 1, "footer")
+
+			require.Truef(t, ErrTemplateInUse.Base.Is(err), "expected ErrTemplateInUse but got %s", err)
+		})
+
+		t.Run("deletes templates that use other templates", func(t *testing.T) {
+			sut := createTemplateServiceSut()
+			sut.configStore.store.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithSharedTemplates,
+				})
+			sut.configStore.store.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.provenanceStore.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			err := sut.DeleteTemplate(context.Background(), 1, "message")
+
+			require.NoError(t, err)
+		})
+	})
+}
+
+func TestCheckTemplateDependents(t *testing.T) {
+	files := map[string]string{
+		"footer":  `{{ define "footer" }}Runbook{{ end }}{{ define "links" }}Links{{ end }}`,
+		"message": `{{ define "message" }}Hello {{ if .Alerts }}{{ template "footer" . }}{{ end }}{{ end }}`,
+		"title":   `{{ define "title" }}{{ template "footer" . }}{{ template "__subject" . }}{{ end }}`,
+		"links":   `{{ define "links" }}Other links{{ end }}`,
+	}
+
+	t.Run("rejects removing templates that are used", func(t *testing.T) {
+		err := checkTemplateDependents(files, "footer", "")
+		require.Truef(t, ErrTemplateInUse.Base.Is(err), "expected ErrTemplateInUse but got %s", err)
+		require.ErrorContains(t, err, "message, title")
+	})
+
+	t.Run("rejects changes that remove templates that are used", func(t *testing.T) {
+		err := checkTemplateDependents(files, "footer", `{{ define "links" }}Links{{ end }}`)
+		require.Truef(t, ErrTemplateInUse.Base.Is(err), "expected ErrTemplateInUse but got %s", err)
+	})
+
+	t.Run("accepts changes that keep templates that are used", func(t *testing.T) {
+		require.NoError(t, checkTemplateDependents(files, "footer", `{{ define "footer" }}New runbook{{ end }}`))
+	})
+
+	t.Run("accepts removing templates that are defined by other files", func(t *testing.T) {
+		require.NoError(t, checkTemplateDependents(files, "links", ""))
+	})
+
+	t.Run("accepts removing templates that are not used", func(t *testing.T) {
+		require.NoError(t, checkTemplateDependents(files, "title", ""))
+		require.NoError(t, checkTemplateDependents(files, "does not exist", ""))
 	})
 }
 
+func TestTemplateDependencies(t *testing.T) {
+	defines, refers := templateDependencies("message", `{{ define "message" }}{{ template "footer" . }}{{ template "body" . }}{{ end }}{{ define "body" }}{{ range .Alerts }}{{ template "alert" . }}{{ end }}{{ end }}`)
+	require.Equal(t, []string{"body", "message"}, defines)
+	require.Equal(t, []string{"alert", "footer"}, refers)
+
+	defines, refers = templateDependencies("invalid", `{{ define "invalid" }}`)
+	require.Empty(t, defines)
+	require.Empty(t, refers)
+}
+
 func createTemplateServiceSut() *TemplateService {
 	return &TemplateService{
 		configStore:     &alertmanagerConfigStoreImpl{store: &MockAMConfigStore{}},
@@ -424,6 +493,32 @@ var configWithTemplates = `
 }
 `
 
+var configWithSharedTemplates = `
+{
+	"template_files": {
+		"footer": "{{ define \"footer\" }}Runbook{{ end }}",
+		"message": "{{ define \"message\" }}Hello {{ template \"footer\" . }}{{ end }}"
+	},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "grafana-default-email"
+		},
+		"receivers": [{
+			"name": "grafana-default-email",
+			"grafana_managed_receiver_configs": [{
+				"uid": "",
+				"name": "email receiver",
+				"type": "email",
+				"isDefault": true,
+				"settings": {
+					"addresses": "<example@email.com>"
+				}
+			}]
+		}]
+	}
+}
+`
+
 var brokenConfig = `
 	"alertmanager_config": {
 		"route": {
@@ -0,0 +1,32 @@
+package provisioning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// terraformNameHashLength is the number of hexadecimal digits of the hash of the identifier that is added to the
+// names that are not the identifier as it is.
+const terraformNameHashLength = 8
+
+// TerraformResourceName derives the name of a resource of a Terraform export from the identifier of the exported
+// resource, e.g. its UID. The name only depends on the identifier, so exporting the resource again gives it the same
+// name whatever the other exported resources and their order, and the export can be used to import the existing
+// resources into the Terraform state. The characters of the identifier that are not valid in Terraform names are
+// replaced with underscores, and a hash of the identifier is then added, so that the identifiers that only differ by
+// these characters still have different names.
+func TerraformResourceName(prefix, id string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, id)
+	name := prefix + "_" + sanitized
+	if sanitized != id {
+		sum := sha256.Sum256([]byte(id))
+		name += "_" + hex.EncodeToString(sum[:])[:terraformNameHashLength]
+	}
+	return name
+}
@@ -0,0 +1,29 @@
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerraformResourceName(t *testing.T) {
+	t.Run("names should be derived from the identifiers", func(t *testing.T) {
+		require.Equal(t, "contact_point_email-receiver", TerraformResourceName("contact_point", "email-receiver"))
+		require.Equal(t, "rule_group_folder_uid_my_group", TerraformResourceName("rule_group", "folder_uid_my_group"))
+	})
+
+	t.Run("invalid characters should be replaced and the identifier hashed", func(t *testing.T) {
+		name := TerraformResourceName("mute_timing", "Week ends (UTC)")
+		require.Regexp(t, `^mute_timing_Week_ends__UTC__[0-9a-f]{8}$`, name)
+		require.Equal(t, name, TerraformResourceName("mute_timing", "Week ends (UTC)"), "names should be stable")
+	})
+
+	t.Run("identifiers that differ by invalid characters should have different names", func(t *testing.T) {
+		names := map[string]struct{}{}
+		for _, id := range []string{"a.b", "a b", "a_b"} {
+			names[TerraformResourceName("contact_point", id)] = struct{}{}
+		}
+		require.Len(t, names, 3)
+		require.Contains(t, names, "contact_point_a_b")
+	})
+}
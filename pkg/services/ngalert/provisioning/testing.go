@@ -67,6 +67,18 @@ func (n *NopTransactionManager) InTransaction(ctx context.Context, work func(ctx
 	return work(context.WithValue(ctx, NopTransactionManager{}, struct{}{}))
 }
 
+func (n *NopTransactionManager) InSavepoint(ctx context.Context, work func(ctx context.Context) error) error {
+	return work(context.WithValue(ctx, NopTransactionManager{}, struct{}{}))
+}
+
+func (n *NopTransactionManager) InSnapshot(ctx context.Context, work func(ctx context.Context) error) error {
+	return work(context.WithValue(ctx, NopTransactionManager{}, struct{}{}))
+}
+
+func (n *NopTransactionManager) AfterCommit(_ context.Context, fn func()) {
+	fn()
+}
+
 func (m *MockAMConfigStore_Expecter) GetsConfig(ac models.AlertConfiguration) *MockAMConfigStore_Expecter {
 	m.GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).Return(&ac, nil)
 	return m
@@ -89,11 +101,15 @@ func (m *MockAMConfigStore_Expecter) SaveSucceedsIntercept(intercepted *models.S
 func (m *MockProvisioningStore_Expecter) GetReturns(p models.Provenance) *MockProvisioningStore_Expecter {
 	m.GetProvenance(mock.Anything, mock.Anything, mock.Anything).Return(p, nil)
 	m.GetProvenances(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	m.GetProvenanceMetadata(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	m.GetProvenancesMetadata(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
 	return m
 }
 
 func (m *MockProvisioningStore_Expecter) SaveSucceeds() *MockProvisioningStore_Expecter {
 	m.SetProvenance(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	m.SetProvenances(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	m.SetProvenanceWithMetadata(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	m.DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	return m
 }
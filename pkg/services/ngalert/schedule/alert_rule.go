@@ -47,6 +47,7 @@ func newRuleFactory(
 	disableGrafanaFolder bool,
 	maxAttempts int64,
 	sender AlertsSender,
+	incidentHookSender IncidentHookSender,
 	stateManager *state.Manager,
 	evalFactory eval.EvaluatorFactory,
 	ruleProvider ruleProvider,
@@ -64,6 +65,7 @@ func newRuleFactory(
 			disableGrafanaFolder,
 			maxAttempts,
 			sender,
+			incidentHookSender,
 			stateManager,
 			evalFactory,
 			ruleProvider,
@@ -94,11 +96,13 @@ type alertRule struct {
 	disableGrafanaFolder bool
 	maxAttempts          int64
 
-	clock        clock.Clock
-	sender       AlertsSender
-	stateManager *state.Manager
-	evalFactory  eval.EvaluatorFactory
-	ruleProvider ruleProvider
+	clock  clock.Clock
+	sender AlertsSender
+	// incidentHookSender is nil if the incident hooks are not called.
+	incidentHookSender IncidentHookSender
+	stateManager       *state.Manager
+	evalFactory        eval.EvaluatorFactory
+	ruleProvider       ruleProvider
 
 	// Event hooks that are only used in tests.
 	evalAppliedHook evalAppliedFunc
@@ -115,6 +119,7 @@ func newAlertRule(
 	disableGrafanaFolder bool,
 	maxAttempts int64,
 	sender AlertsSender,
+	incidentHookSender IncidentHookSender,
 	stateManager *state.Manager,
 	evalFactory eval.EvaluatorFactory,
 	ruleProvider ruleProvider,
@@ -136,6 +141,7 @@ func newAlertRule(
 		maxAttempts:          maxAttempts,
 		clock:                clock,
 		sender:               sender,
+		incidentHookSender:   incidentHookSender,
 		stateManager:         stateManager,
 		evalFactory:          evalFactory,
 		ruleProvider:         ruleProvider,
@@ -322,6 +328,7 @@ func (a *alertRule) evaluate(ctx context.Context, key ngmodels.AlertRuleKey, f f
 	start := a.clock.Now()
 
 	evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), a.newLoadedMetricsReader(e.rule))
+	evalCtx.EvaluationTimeout = e.rule.EvaluationTimeout
 	ruleEval, err := a.evalFactory.Create(evalCtx, e.rule.GetEvalCondition())
 	var results eval.Results
 	var dur time.Duration
@@ -395,7 +402,8 @@ func (a *alertRule) evaluate(ctx context.Context, key ngmodels.AlertRuleKey, f f
 	processDuration.Observe(a.clock.Now().Sub(start).Seconds())
 
 	start = a.clock.Now()
-	alerts := state.FromStateTransitionToPostableAlerts(processedStates, a.stateManager, a.appURL)
+	notified := withoutBakedAlerts(e.rule, processedStates)
+	alerts := state.FromStateTransitionToPostableAlerts(notified, a.stateManager, a.appURL)
 	span.AddEvent("results processed", trace.WithAttributes(
 		attribute.Int64("state_transitions", int64(len(processedStates))),
 		attribute.Int64("alerts_to_send", int64(len(alerts.PostableAlerts))),
@@ -403,11 +411,30 @@ func (a *alertRule) evaluate(ctx context.Context, key ngmodels.AlertRuleKey, f f
 	if len(alerts.PostableAlerts) > 0 {
 		a.sender.Send(ctx, key, alerts)
 	}
+	if a.incidentHookSender != nil && len(e.rule.IncidentHooks) > 0 {
+		a.incidentHookSender.Send(ctx, e.rule, notified)
+	}
 	sendDuration.Observe(a.clock.Now().Sub(start).Seconds())
 
 	return nil
 }
 
+// withoutBakedAlerts removes the state transitions of the alerts whose notifications are suppressed by the bake period
+// of the rule. Their state is recorded but they are never sent, neither when they fire nor when they are resolved.
+func withoutBakedAlerts(rule *ngmodels.AlertRule, transitions []state.StateTransition) []state.StateTransition {
+	if rule.BakeUntil == nil {
+		return transitions
+	}
+	result := make([]state.StateTransition, 0, len(transitions))
+	for _, t := range transitions {
+		if rule.SuppressesNotificationsOf(t.StartsAt) {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
 func (a *alertRule) notify(ctx context.Context, key ngmodels.AlertRuleKey, states []state.StateTransition) {
 	expiredAlerts := state.FromAlertsStateToStoppedAlert(states, a.appURL, a.clock)
 	if len(expiredAlerts.PostableAlerts) > 0 {
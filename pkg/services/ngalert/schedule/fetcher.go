@@ -18,12 +18,21 @@ func (sch *schedule) updateSchedulableAlertRules(ctx context.Context) (diff, err
 			time.Since(start).Seconds())
 	}()
 
+	folders, err := sch.ruleStore.GetPausedFoldersForScheduling(ctx)
+	if err != nil {
+		return diff{}, fmt.Errorf("failed to get paused folders: %w", err)
+	}
+	pausedFolders := make(map[models.FolderKey]struct{}, len(folders))
+	for _, key := range folders {
+		pausedFolders[key] = struct{}{}
+	}
+
 	if !sch.schedulableAlertRules.isEmpty() {
 		keys, err := sch.ruleStore.GetAlertRulesKeysForScheduling(ctx)
 		if err != nil {
 			return diff{}, err
 		}
-		if !sch.schedulableAlertRules.needsUpdate(keys) {
+		if !sch.schedulableAlertRules.needsUpdate(keys) && !sch.schedulableAlertRules.pausedFoldersChanged(pausedFolders) {
 			sch.log.Debug("No changes detected. Skip updating")
 			return diff{}, nil
 		}
@@ -35,7 +44,35 @@ func (sch *schedule) updateSchedulableAlertRules(ctx context.Context) (diff, err
 	if err := sch.ruleStore.GetAlertRulesForScheduling(ctx, &q); err != nil {
 		return diff{}, fmt.Errorf("failed to get alert rules: %w", err)
 	}
+	// Recording rules are not scheduled: there is no writer for the series that they record yet, and they must not be
+	// evaluated as alert rules.
+	schedulable := q.ResultRules[:0]
+	for _, rule := range q.ResultRules {
+		if !rule.IsRecordingRule() {
+			schedulable = append(schedulable, rule)
+		}
+	}
+	q.ResultRules = schedulable
+	// The rules of paused folders are paused like the rules that are paused themselves, but only in the registry, so
+	// that their pause status is restored when the folder is resumed.
+	for i, rule := range q.ResultRules {
+		if _, ok := pausedFolders[rule.GetFolderKey()]; ok && !rule.IsPaused {
+			paused := models.CopyRule(rule)
+			paused.IsPaused = true
+			q.ResultRules[i] = paused
+		}
+	}
+	// The rules that override the interval of their group are scheduled at their own interval. It replaces the interval
+	// of the group in the registry only, so that the evaluations and the states of the rule use it as well.
+	for i, rule := range q.ResultRules {
+		if rule.IntervalOverrideSeconds > 0 && rule.IntervalSeconds != rule.IntervalOverrideSeconds {
+			overridden := models.CopyRule(rule)
+			overridden.IntervalSeconds = rule.IntervalOverrideSeconds
+			q.ResultRules[i] = overridden
+		}
+	}
 	d := sch.schedulableAlertRules.set(q.ResultRules, q.ResultFoldersTitles)
+	sch.schedulableAlertRules.setPausedFolders(pausedFolders)
 	sch.log.Debug("Alert rules fetched", "rulesCount", len(q.ResultRules), "foldersCount", len(q.ResultFoldersTitles), "updatedRules", len(d.updated))
 	return d, nil
 }
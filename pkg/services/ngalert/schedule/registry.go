@@ -89,7 +89,9 @@ type Evaluation struct {
 type alertRulesRegistry struct {
 	rules        map[models.AlertRuleKey]*models.AlertRule
 	folderTitles map[models.FolderKey]string
-	mu           sync.Mutex
+	// pausedFolders are the folders whose rules are not evaluated. The rules of these folders are paused in the registry.
+	pausedFolders map[models.FolderKey]struct{}
+	mu            sync.Mutex
 }
 
 // all returns all rules in the registry.
@@ -150,6 +152,28 @@ func (r *alertRulesRegistry) isEmpty() bool {
 	return len(r.rules) == 0
 }
 
+// setPausedFolders replaces the paused folders in the registry.
+func (r *alertRulesRegistry) setPausedFolders(folders map[models.FolderKey]struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pausedFolders = folders
+}
+
+// pausedFoldersChanged returns true if the folders are not the paused folders in the registry.
+func (r *alertRulesRegistry) pausedFoldersChanged(folders map[models.FolderKey]struct{}) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.pausedFolders) != len(folders) {
+		return true
+	}
+	for key := range folders {
+		if _, ok := r.pausedFolders[key]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *alertRulesRegistry) needsUpdate(keys []models.AlertRuleKeyWithVersion) bool {
 	if len(r.rules) != len(keys) {
 		return true
@@ -172,14 +196,15 @@ func (d diff) IsEmpty() bool {
 }
 
 // getDiff calculates difference between the list of rules fetched previously and provided keys. Returns diff where
-// updated - a list of keys that exist in the registry but with different version,
+// updated - a list of keys that exist in the registry but with different version or pause status, which changes
+// without a new version when the folder of the rule is paused or resumed.
 func (r *alertRulesRegistry) getDiff(rules map[models.AlertRuleKey]*models.AlertRule) diff {
 	result := diff{
 		updated: map[models.AlertRuleKey]struct{}{},
 	}
 	for key, newRule := range rules {
 		oldRule, ok := r.rules[key]
-		if !ok || newRule.Version == oldRule.Version {
+		if !ok || (newRule.Version == oldRule.Version && newRule.IsPaused == oldRule.IsPaused) {
 			// a new rule or not updated
 			continue
 		}
@@ -283,6 +308,11 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeLabels(rule.Labels)
 	writeString(rule.Condition)
 	writeQuery()
+	if rule.IsRecordingRule() {
+		writeString(rule.Record.Metric)
+		writeString(rule.Record.From)
+		writeString(rule.Record.TargetDatasourceUID)
+	}
 
 	if rule.IsPaused {
 		writeInt(1)
@@ -300,6 +330,8 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.ID)
 	writeInt(rule.OrgID)
 	writeInt(rule.IntervalSeconds)
+	writeInt(rule.IntervalOverrideSeconds)
+	writeInt(int64(rule.EvaluationTimeout))
 	writeInt(int64(rule.For))
 	writeLabels(rule.Annotations)
 	if rule.DashboardUID != nil {
@@ -312,5 +344,19 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(int64(rule.RuleGroupIndex))
 	writeString(string(rule.NoDataState))
 	writeString(string(rule.ExecErrState))
+	writeInt(int64(rule.DataAvailabilityPeriod))
+	writeInt(int64(rule.DataAvailabilityDelay))
+	writeString(rule.ShardAffinity)
+	if rule.BakeUntil != nil {
+		writeInt(rule.BakeUntil.UnixNano())
+	}
+	if rule.ExpiresAt != nil {
+		writeInt(rule.ExpiresAt.UnixNano())
+	}
+	for _, hook := range rule.IncidentHooks {
+		writeString(hook.Name)
+		writeString(hook.URL)
+		writeString(hook.AuthorizationCredentials)
+	}
 	return fingerprint(sum.Sum64())
 }
@@ -125,6 +125,34 @@ func TestSchedulableAlertRulesRegistry_set(t *testing.T) {
 		require.Falsef(t, diff.IsEmpty(), "Diff is empty but should not be")
 		require.Equal(t, expectedUpdated, diff.updated)
 	})
+	t.Run("should return key in diff if pause status changes", func(t *testing.T) {
+		current, _ := r.all()
+		newRules := make([]*models.AlertRule, 0, len(current))
+		expectedUpdated := map[models.AlertRuleKey]struct{}{}
+		for i, rule := range current {
+			cp := models.CopyRule(rule)
+			if i%2 == 0 {
+				cp.IsPaused = !cp.IsPaused
+				expectedUpdated[cp.GetKey()] = struct{}{}
+			}
+			newRules = append(newRules, cp)
+		}
+
+		diff := r.set(newRules, map[models.FolderKey]string{})
+		require.Equal(t, expectedUpdated, diff.updated)
+	})
+}
+
+func TestSchedulableAlertRulesRegistry_pausedFoldersChanged(t *testing.T) {
+	r := alertRulesRegistry{}
+	folder := models.FolderKey{OrgID: 1, UID: "folder"}
+	require.False(t, r.pausedFoldersChanged(nil))
+	require.True(t, r.pausedFoldersChanged(map[models.FolderKey]struct{}{folder: {}}))
+
+	r.setPausedFolders(map[models.FolderKey]struct{}{folder: {}})
+	require.False(t, r.pausedFoldersChanged(map[models.FolderKey]struct{}{folder: {}}))
+	require.True(t, r.pausedFoldersChanged(map[models.FolderKey]struct{}{{OrgID: 2, UID: "folder"}: {}}))
+	require.True(t, r.pausedFoldersChanged(map[models.FolderKey]struct{}{}))
 }
 
 func TestRuleWithFolderFingerprint(t *testing.T) {
@@ -199,6 +227,13 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NotificationSettings: []models.NotificationSettings{
 				models.NotificationSettingsGen()(),
 			},
+			DataAvailabilityPeriod: time.Hour,
+			DataAvailabilityDelay:  5 * time.Minute,
+			ShardAffinity:          "shard-1",
+			BakeUntil:              func(t time.Time) *time.Time { return &t }(time.Now()),
+			IncidentHooks:          []models.IncidentHook{{Name: "hook-1", URL: "https://example.com/1"}},
+			EvaluationTimeout:      time.Minute,
+			Record:                 models.Record{Metric: "metric_1", From: "1", TargetDatasourceUID: "prometheus-1"},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -237,6 +272,15 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NotificationSettings: []models.NotificationSettings{
 				models.NotificationSettingsGen()(),
 			},
+			DataAvailabilityPeriod:  24 * time.Hour,
+			DataAvailabilityDelay:   time.Hour,
+			ShardAffinity:           "shard-2",
+			BakeUntil:               func(t time.Time) *time.Time { return &t }(time.Now().Add(time.Hour)),
+			IncidentHooks:           []models.IncidentHook{{Name: "hook-2", URL: "https://example.com/2"}},
+			EvaluationTimeout:       5 * time.Minute,
+			Record:                  models.Record{Metric: "metric_2", From: "2", TargetDatasourceUID: "prometheus-2"},
+			ExpiresAt:               func(t time.Time) *time.Time { return &t }(time.Now().Add(24 * time.Hour)),
+			IntervalOverrideSeconds: 60,
 		}
 
 		excludedFields := map[string]struct{}{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"slices"
 	"time"
 
 	"github.com/benbjohnson/clock"
@@ -37,10 +38,16 @@ type AlertsSender interface {
 	Send(ctx context.Context, key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
 }
 
+// IncidentHookSender calls the incident hooks of the rule when its alerts start firing and when they are resolved.
+type IncidentHookSender interface {
+	Send(ctx context.Context, rule *ngmodels.AlertRule, transitions []state.StateTransition)
+}
+
 // RulesStore is a store that provides alert rules for scheduling
 type RulesStore interface {
 	GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error)
 	GetAlertRulesForScheduling(ctx context.Context, query *ngmodels.GetAlertRulesForSchedulingQuery) error
+	GetPausedFoldersForScheduling(ctx context.Context) ([]ngmodels.FolderKey, error)
 }
 
 type schedule struct {
@@ -75,11 +82,18 @@ type schedule struct {
 	appURL               *url.URL
 	disableGrafanaFolder bool
 	jitterEvaluations    JitterStrategy
+	shard                string
+	// shards are the scheduler shards that rule groups can be pinned to, which are served by the schedulers of other
+	// instances.
+	shards []string
+	// unservedGroups contains the rule groups pinned to a shard that no scheduler serves, as of the previous tick.
+	unservedGroups map[ngmodels.AlertRuleGroupKey]struct{}
 
 	metrics *metrics.Scheduler
 
-	alertsSender    AlertsSender
-	minRuleInterval time.Duration
+	alertsSender       AlertsSender
+	incidentHookSender IncidentHookSender
+	minRuleInterval    time.Duration
 
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
@@ -99,12 +113,20 @@ type SchedulerCfg struct {
 	DisableGrafanaFolder bool
 	AppURL               *url.URL
 	JitterEvaluations    JitterStrategy
-	EvaluatorFactory     eval.EvaluatorFactory
-	RuleStore            RulesStore
-	Metrics              *metrics.Scheduler
-	AlertSender          AlertsSender
-	Tracer               tracing.Tracer
-	Log                  log.Logger
+	// Shard is the scheduler shard of this instance. Only the rules pinned to this shard are evaluated, or only the rules
+	// that are not pinned to any shard if it is empty.
+	Shard string
+	// Shards are the scheduler shards that rule groups can be pinned to. The rule groups pinned to other shards are
+	// reported as unserved.
+	Shards           []string
+	EvaluatorFactory eval.EvaluatorFactory
+	RuleStore        RulesStore
+	Metrics          *metrics.Scheduler
+	AlertSender      AlertsSender
+	// IncidentHookSender calls the incident hooks of the rule groups. It is optional.
+	IncidentHookSender IncidentHookSender
+	Tracer             tracing.Tracer
+	Log                log.Logger
 }
 
 // NewScheduler returns a new scheduler.
@@ -127,10 +149,14 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		appURL:                cfg.AppURL,
 		disableGrafanaFolder:  cfg.DisableGrafanaFolder,
 		jitterEvaluations:     cfg.JitterEvaluations,
+		shard:                 cfg.Shard,
+		shards:                cfg.Shards,
+		unservedGroups:        make(map[ngmodels.AlertRuleGroupKey]struct{}),
 		stateManager:          stateManager,
 		minRuleInterval:       cfg.MinRuleInterval,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		incidentHookSender:    cfg.IncidentHookSender,
 		tracer:                cfg.Tracer,
 	}
 
@@ -138,7 +164,7 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 }
 
 func (sch *schedule) Run(ctx context.Context) error {
-	sch.log.Info("Starting scheduler", "tickInterval", sch.baseInterval, "maxAttempts", sch.maxAttempts)
+	sch.log.Info("Starting scheduler", "tickInterval", sch.baseInterval, "maxAttempts", sch.maxAttempts, "shard", sch.shard)
 	t := ticker.New(sch.clock, sch.baseInterval, sch.metrics.Ticker)
 	defer t.Stop()
 
@@ -206,6 +232,37 @@ type readyToRunItem struct {
 	Evaluation
 }
 
+// updateUnservedRuleGroups reports the rule groups pinned to a shard that is neither the shard of this scheduler nor
+// one of the configured shards. No scheduler evaluates their rules, e.g. because the shard was removed from the
+// configuration after the groups were pinned to it.
+func (sch *schedule) updateUnservedRuleGroups(alertRules []*ngmodels.AlertRule) {
+	unserved := make(map[ngmodels.AlertRuleGroupKey]struct{})
+	perOrg := make(map[int64]int)
+	for _, rule := range alertRules {
+		if rule.ShardAffinity == "" || rule.ShardAffinity == sch.shard || slices.Contains(sch.shards, rule.ShardAffinity) {
+			continue
+		}
+		key := rule.GetGroupKey()
+		if _, ok := unserved[key]; ok {
+			continue
+		}
+		unserved[key] = struct{}{}
+		perOrg[key.OrgID]++
+		if _, ok := sch.unservedGroups[key]; !ok {
+			sch.log.Warn("Rule group is pinned to a scheduler shard that is not configured, its rules are not evaluated", "org_id", key.OrgID, "namespace_uid", key.NamespaceUID, "rule_group", key.RuleGroup, "shard", rule.ShardAffinity)
+		}
+	}
+	for key := range sch.unservedGroups {
+		if _, ok := perOrg[key.OrgID]; !ok {
+			sch.metrics.UnservedRuleGroups.WithLabelValues(fmt.Sprint(key.OrgID)).Set(0)
+		}
+	}
+	for orgID, count := range perOrg {
+		sch.metrics.UnservedRuleGroups.WithLabelValues(fmt.Sprint(orgID)).Set(float64(count))
+	}
+	sch.unservedGroups = unserved
+}
+
 // TODO refactor to accept a callback for tests that will be called with things that are returned currently, and return nothing.
 // Returns a slice of rules that were scheduled for evaluation, map of stopped rules, and a slice of updated rules
 func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.Group, tick time.Time) ([]readyToRunItem, map[ngmodels.AlertRuleKey]struct{}, []ngmodels.AlertRuleKeyWithVersion) {
@@ -231,6 +288,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	registeredDefinitions := sch.registry.keyMap()
 
 	sch.updateRulesMetrics(alertRules)
+	sch.updateUnservedRuleGroups(alertRules)
 
 	readyToRun := make([]readyToRunItem, 0)
 	updatedRules := make([]ngmodels.AlertRuleKeyWithVersion, 0, len(updated)) // this is needed for tests only
@@ -240,6 +298,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		sch.disableGrafanaFolder,
 		sch.maxAttempts,
 		sch.alertsSender,
+		sch.incidentHookSender,
 		sch.stateManager,
 		sch.evaluatorFactory,
 		&sch.schedulableAlertRules,
@@ -252,6 +311,11 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 	)
 	for _, item := range alertRules {
 		key := item.GetKey()
+		if item.ShardAffinity != sch.shard {
+			// the rule is evaluated by the schedulers of another shard. If it was pinned to another shard since the
+			// previous tick, its routine is stopped below as if it was deleted.
+			continue
+		}
 		ruleRoutine, newRoutine := sch.registry.getOrCreate(ctx, key, ruleFactory)
 
 		// enforce minimum evaluation interval
@@ -278,6 +342,12 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
 		offset := jitterOffsetInTicks(item, sch.baseInterval, sch.jitterEvaluations)
 		isReadyToRun := item.IntervalSeconds != 0 && (tickNum%itemFrequency)-offset == 0
+		evaluatedAt := item.DataAvailableAt(tick)
+		if isReadyToRun && !evaluatedAt.Equal(tick) {
+			// the data of the current window of the rule group is not complete yet, so the rule is evaluated as of the
+			// end of the previous window.
+			sch.log.Debug("Rule evaluation time delayed because the data is not available yet", append(key.LogContext(), "tick", tickNum, "evaluatedAt", evaluatedAt, "dataAvailabilityPeriod", item.DataAvailabilityPeriod, "dataAvailabilityDelay", item.DataAvailabilityDelay)...)
+		}
 
 		var folderTitle string
 		if !sch.disableGrafanaFolder {
@@ -292,7 +362,7 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		if isReadyToRun {
 			sch.log.Debug("Rule is ready to run on the current tick", "uid", item.UID, "tick", tickNum, "frequency", itemFrequency, "offset", offset)
 			readyToRun = append(readyToRun, readyToRunItem{ruleRoutine: ruleRoutine, Evaluation: Evaluation{
-				scheduledAt: tick,
+				scheduledAt: evaluatedAt,
 				rule:        item,
 				folderTitle: folderTitle,
 			}})
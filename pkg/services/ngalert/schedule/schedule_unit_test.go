@@ -24,6 +24,7 @@ import (
 	datasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
@@ -356,6 +357,141 @@ func TestProcessTicks(t *testing.T) {
 	})
 }
 
+func TestProcessTicksShardAffinity(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sched.shard = "shard-1"
+
+	pinned := models.AlertRuleGen(models.WithInterval(sched.baseInterval), models.WithTitle("pinned"))()
+	pinned.ShardAffinity = "shard-1"
+	unpinned := models.AlertRuleGen(models.WithInterval(sched.baseInterval), models.WithTitle("unpinned"))()
+	unpinned.ShardAffinity = ""
+	other := models.AlertRuleGen(models.WithInterval(sched.baseInterval), models.WithTitle("other"))()
+	other.ShardAffinity = "shard-2"
+	ruleStore.PutRule(ctx, pinned, unpinned, other)
+
+	tick := time.Time{}.Add(sched.baseInterval)
+	scheduled, stopped, _ := sched.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, 1)
+	require.Equal(t, pinned.GetKey(), scheduled[0].rule.GetKey())
+	require.Empty(t, stopped)
+
+	// pinning the rule to another shard stops its evaluation on this shard.
+	moved := models.CopyRule(pinned)
+	moved.Version++
+	moved.ShardAffinity = "shard-2"
+	ruleStore.PutRule(ctx, moved)
+
+	tick = tick.Add(sched.baseInterval)
+	scheduled, stopped, _ = sched.processTick(ctx, dispatcherGroup, tick)
+	require.Empty(t, scheduled)
+	require.Len(t, stopped, 1)
+	require.Contains(t, stopped, pinned.GetKey())
+}
+
+func TestProcessTicksUnservedRuleGroups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	// The served rules are evaluated after the tick, which is not tested here.
+	evaluator := &eval_mocks.ConditionEvaluatorMock{}
+	evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(eval.Results{}, nil)
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, eval_mocks.NewEvaluatorFactory(evaluator))
+	sched.shard = "shard-1"
+	sched.shards = []string{"shard-1", "shard-2"}
+
+	served := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(sched.baseInterval), models.WithTitle("served"))()
+	served.ShardAffinity = "shard-2"
+	unserved := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(sched.baseInterval), models.WithTitle("unserved"))()
+	unserved.ShardAffinity = "shard-3"
+	ruleStore.PutRule(ctx, served, unserved)
+
+	tick := time.Time{}.Add(sched.baseInterval)
+	_, _, _ = sched.processTick(ctx, dispatcherGroup, tick)
+	require.Equal(t, 1.0, testutil.ToFloat64(sched.metrics.UnservedRuleGroups.WithLabelValues("1")))
+
+	// the group is served again once it is pinned to a configured shard.
+	moved := models.CopyRule(unserved)
+	moved.Version++
+	moved.ShardAffinity = "shard-1"
+	ruleStore.PutRule(ctx, moved)
+
+	tick = tick.Add(sched.baseInterval)
+	_, _, _ = sched.processTick(ctx, dispatcherGroup, tick)
+	require.Equal(t, 0.0, testutil.ToFloat64(sched.metrics.UnservedRuleGroups.WithLabelValues("1")))
+}
+
+func TestProcessTicksIntervalOverride(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sched.jitterEvaluations = JitterNever
+
+	inherited := models.AlertRuleGen(models.WithInterval(sched.baseInterval), models.WithTitle("inherited"))()
+	overridden := models.AlertRuleGen(models.WithInterval(sched.baseInterval), models.WithTitle("overridden"))()
+	overridden.IntervalOverrideSeconds = 3 * int64(sched.baseInterval.Seconds())
+	ruleStore.PutRule(ctx, inherited, overridden)
+
+	evaluated := map[string]int{}
+	tick := time.Unix(0, 0).UTC()
+	for i := 0; i < 6; i++ {
+		tick = tick.Add(sched.baseInterval)
+		scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+		for _, item := range scheduled {
+			evaluated[item.rule.Title]++
+			if item.rule.Title == overridden.Title {
+				require.Equal(t, overridden.IntervalOverrideSeconds, item.rule.IntervalSeconds)
+			}
+		}
+	}
+	require.Equal(t, 6, evaluated[inherited.Title])
+	require.Equal(t, 2, evaluated[overridden.Title])
+	require.Equal(t, int64(sched.baseInterval.Seconds()), overridden.IntervalSeconds, "the stored rule should keep the interval of its group")
+}
+
+func TestProcessTicksFolderEvaluationPause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+
+	ruleStore := newFakeRulesStore()
+	sched := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+
+	rule := models.AlertRuleGen(models.WithInterval(sched.baseInterval), models.WithTitle("rule"))()
+	rule.IsPaused = false
+	ruleStore.PutRule(ctx, rule)
+
+	tick := time.Time{}.Add(sched.baseInterval)
+	scheduled, _, _ := sched.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, 1)
+	require.False(t, scheduled[0].rule.IsPaused)
+
+	// pausing the folder pauses the evaluation of the rule without a new version of the rule.
+	ruleStore.pausedFolders = []models.FolderKey{rule.GetFolderKey()}
+
+	tick = tick.Add(sched.baseInterval)
+	scheduled, _, _ = sched.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, 1)
+	require.True(t, scheduled[0].rule.IsPaused)
+	require.False(t, rule.IsPaused, "the stored rule should not be paused")
+
+	ruleStore.pausedFolders = nil
+
+	tick = tick.Add(sched.baseInterval)
+	scheduled, _, _ = sched.processTick(ctx, dispatcherGroup, tick)
+	require.Len(t, scheduled, 1)
+	require.False(t, scheduled[0].rule.IsPaused)
+}
+
 func TestSchedule_deleteAlertRule(t *testing.T) {
 	t.Run("when rule exists", func(t *testing.T) {
 		t.Run("it should stop evaluation loop and remove the controller from registry", func(t *testing.T) {
@@ -40,7 +40,8 @@ func waitForErrChannel(t *testing.T, ch chan error) error {
 }
 
 type fakeRulesStore struct {
-	rules map[string]*models.AlertRule
+	rules         map[string]*models.AlertRule
+	pausedFolders []models.FolderKey
 }
 
 func newFakeRulesStore() *fakeRulesStore {
@@ -70,6 +71,10 @@ func (f *fakeRulesStore) GetAlertRulesForScheduling(ctx context.Context, query *
 	return nil
 }
 
+func (f *fakeRulesStore) GetPausedFoldersForScheduling(ctx context.Context) ([]models.FolderKey, error) {
+	return f.pausedFolders, nil
+}
+
 func (f *fakeRulesStore) PutRule(_ context.Context, rules ...*models.AlertRule) {
 	for _, r := range rules {
 		f.rules[r.UID] = r
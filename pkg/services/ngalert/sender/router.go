@@ -3,6 +3,7 @@ package sender
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
@@ -36,6 +37,7 @@ type AlertsRouter struct {
 	// externalAlertmanagers help us send alerts to external Alertmanagers.
 	adminConfigMtx               sync.RWMutex
 	sendAlertsTo                 map[int64]models.AlertmanagersChoice
+	externalMatchers             map[int64]definitions.ObjectMatchers
 	externalAlertmanagers        map[int64]*ExternalAlertmanager
 	externalAlertmanagersCfgHash map[int64]string
 
@@ -61,6 +63,7 @@ func NewAlertsRouter(multiOrgNotifier *notifier.MultiOrgAlertmanager, store stor
 		externalAlertmanagers:        map[int64]*ExternalAlertmanager{},
 		externalAlertmanagersCfgHash: map[int64]string{},
 		sendAlertsTo:                 map[int64]models.AlertmanagersChoice{},
+		externalMatchers:             map[int64]definitions.ObjectMatchers{},
 
 		multiOrgNotifier: multiOrgNotifier,
 
@@ -94,6 +97,7 @@ func (d *AlertsRouter) SyncAndApplyConfigFromDatabase() error {
 
 		// Update the Alertmanagers choice for the organization.
 		d.sendAlertsTo[cfg.OrgID] = cfg.SendAlertsTo
+		d.externalMatchers[cfg.OrgID] = d.parseExternalMatchers(cfg)
 
 		orgsFound[cfg.OrgID] = struct{}{} // keep track of the which externalAlertmanagers we need to keep.
 
@@ -188,6 +192,20 @@ func (d *AlertsRouter) SyncAndApplyConfigFromDatabase() error {
 	return nil
 }
 
+// parseExternalMatchers returns the matchers of the alerts that are sent only to the external Alertmanagers. If they
+// cannot be parsed, all alerts are sent to all Alertmanagers.
+func (d *AlertsRouter) parseExternalMatchers(cfg *models.AdminConfiguration) definitions.ObjectMatchers {
+	if cfg.ExternalAlertmanagerMatchers == "" || cfg.SendAlertsTo != models.AllAlertmanagers {
+		return nil
+	}
+	var matchers definitions.ObjectMatchers
+	if err := json.Unmarshal([]byte(cfg.ExternalAlertmanagerMatchers), &matchers); err != nil {
+		d.logger.Error("Failed to parse the matchers of the alerts for the external alertmanagers, alerts will be sent to all alertmanagers", "org", cfg.OrgID, "error", err)
+		return nil
+	}
+	return matchers
+}
+
 func buildRedactedAMs(l log.Logger, alertmanagers []ExternalAMcfg, ordId int64) []string {
 	var redactedAMs []string
 	for _, am := range alertmanagers {
@@ -294,18 +312,31 @@ func (d *AlertsRouter) Send(ctx context.Context, key models.AlertRuleKey, alerts
 		logger.Info("No alerts to notify about")
 		return
 	}
+	// Split the alerts between the internal and the external Alertmanager(s) if the organization
+	// sends only some of them to the external ones, and they have been discovered.
+	internalAlerts, externalAlerts := alerts, alerts
+	d.adminConfigMtx.RLock()
+	matchers := d.externalMatchers[key.OrgID]
+	_, hasExternal := d.externalAlertmanagers[key.OrgID]
+	d.adminConfigMtx.RUnlock()
+	if len(matchers) > 0 && hasExternal {
+		internalAlerts, externalAlerts = splitAlerts(alerts, matchers)
+	}
+
 	// Send alerts to local notifier if they need to be handled internally
 	// or if no external AMs have been discovered yet.
 	var localNotifierExist, externalNotifierExist bool
 	if d.sendAlertsTo[key.OrgID] == models.ExternalAlertmanagers && len(d.AlertmanagersFor(key.OrgID)) > 0 {
 		logger.Debug("All alerts for the given org should be routed to external notifiers only. skipping the internal notifier.")
+	} else if len(internalAlerts.PostableAlerts) == 0 {
+		logger.Debug("All alerts match the external matchers. skipping the internal notifier.")
 	} else {
-		logger.Info("Sending alerts to local notifier", "count", len(alerts.PostableAlerts))
+		logger.Info("Sending alerts to local notifier", "count", len(internalAlerts.PostableAlerts))
 		n, err := d.multiOrgNotifier.AlertmanagerFor(key.OrgID)
 		if err == nil {
 			localNotifierExist = true
-			if err := n.PutAlerts(ctx, alerts); err != nil {
-				logger.Error("Failed to put alerts in the local notifier", "count", len(alerts.PostableAlerts), "error", err)
+			if err := n.PutAlerts(ctx, internalAlerts); err != nil {
+				logger.Error("Failed to put alerts in the local notifier", "count", len(internalAlerts.PostableAlerts), "error", err)
 			}
 		} else {
 			if errors.Is(err, notifier.ErrNoAlertmanagerForOrg) {
@@ -322,9 +353,13 @@ func (d *AlertsRouter) Send(ctx context.Context, key models.AlertRuleKey, alerts
 	defer d.adminConfigMtx.RUnlock()
 	s, ok := d.externalAlertmanagers[key.OrgID]
 	if ok && d.sendAlertsTo[key.OrgID] != models.InternalAlertmanager {
-		logger.Info("Sending alerts to external notifier", "count", len(alerts.PostableAlerts))
-		s.SendAlerts(alerts)
 		externalNotifierExist = true
+		if len(externalAlerts.PostableAlerts) == 0 {
+			logger.Debug("No alerts match the external matchers. skipping the external notifier.")
+		} else {
+			logger.Info("Sending alerts to external notifier", "count", len(externalAlerts.PostableAlerts))
+			s.SendAlerts(externalAlerts)
+		}
 	}
 
 	if !localNotifierExist && !externalNotifierExist {
@@ -332,6 +367,26 @@ func (d *AlertsRouter) Send(ctx context.Context, key models.AlertRuleKey, alerts
 	}
 }
 
+// splitAlerts returns the alerts that do not match all the matchers, which are sent to the internal Alertmanager, and
+// the ones that do, which are sent to the external Alertmanager(s).
+func splitAlerts(alerts definitions.PostableAlerts, matchers definitions.ObjectMatchers) (internal, external definitions.PostableAlerts) {
+	for _, alert := range alerts.PostableAlerts {
+		matches := true
+		for _, m := range matchers {
+			if !m.Matches(alert.Labels[m.Name]) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			external.PostableAlerts = append(external.PostableAlerts, alert)
+		} else {
+			internal.PostableAlerts = append(internal.PostableAlerts, alert)
+		}
+	}
+	return internal, external
+}
+
 // AlertmanagersFor returns all the discovered Alertmanager(s) for a particular organization.
 func (d *AlertsRouter) AlertmanagersFor(orgID int64) []*url.URL {
 	d.adminConfigMtx.RLock()
@@ -11,6 +11,7 @@ import (
 	"github.com/benbjohnson/clock"
 	"github.com/go-openapi/strfmt"
 	models2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
@@ -602,3 +603,20 @@ func TestAlertManagers_buildRedactedAMs(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitAlerts(t *testing.T) {
+	alert := func(name, team string) *models2.PostableAlert {
+		return &models2.PostableAlert{Alert: models2.Alert{Labels: models2.LabelSet{"alertname": name, "team": team}}}
+	}
+	matcher, err := labels.NewMatcher(labels.MatchRegexp, "team", "ops|sre")
+	require.NoError(t, err)
+
+	alerts := definitions.PostableAlerts{PostableAlerts: []models2.PostableAlert{
+		*alert("a", "ops"),
+		*alert("b", "dev"),
+		*alert("c", "sre"),
+	}}
+	internal, external := splitAlerts(alerts, definitions.ObjectMatchers{matcher})
+	require.Equal(t, []models2.PostableAlert{*alert("b", "dev")}, internal.PostableAlerts)
+	require.Equal(t, []models2.PostableAlert{*alert("a", "ops"), *alert("c", "sre")}, external.PostableAlerts)
+}
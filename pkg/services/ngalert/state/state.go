@@ -2,7 +2,6 @@ package state
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -557,13 +556,7 @@ func GetRuleExtraLabels(l log.Logger, rule *models.AlertRule, folderTitle string
 	}
 
 	if len(rule.NotificationSettings) > 0 {
-		// Notification settings are defined as a slice to workaround xorm behavior.
-		// Any items past the first should not exist so we ignore them.
-		if len(rule.NotificationSettings) > 1 {
-			ignored, _ := json.Marshal(rule.NotificationSettings[1:])
-			l.Error("Detected multiple notification settings, which is not supported. Only the first will be applied", "ignored_settings", string(ignored))
-		}
-		return mergeLabels(extraLabels, rule.NotificationSettings[0].ToLabels())
+		return mergeLabels(extraLabels, models.NotificationSettingsToLabels(rule.NotificationSettings))
 	}
 	return extraLabels
 }
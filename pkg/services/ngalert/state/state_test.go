@@ -715,6 +715,7 @@ func TestGetRuleExtraLabels(t *testing.T) {
 		GroupBy:   []string{"alertname"},
 		GroupWait: util.Pointer(model.Duration(1 * time.Second)),
 	}
+	ns2 := ngmodels.NewDefaultNotificationSettings("Test2")
 
 	testCases := map[string]struct {
 		rule          *ngmodels.AlertRule
@@ -755,10 +756,10 @@ func TestGetRuleExtraLabels(t *testing.T) {
 				ngmodels.AutogeneratedRouteSettingsHashLabel: ns.Fingerprint().String(),
 			},
 		},
-		"ignore_multiple_notifications": {
+		"with_multiple_notifications": {
 			rule: func() *ngmodels.AlertRule {
 				r := ngmodels.CopyRule(rule)
-				r.NotificationSettings = []ngmodels.NotificationSettings{ns, ngmodels.NotificationSettingsGen()(), ngmodels.NotificationSettingsGen()()}
+				r.NotificationSettings = []ngmodels.NotificationSettings{ns, ns2}
 				return r
 			}(),
 			expected: map[string]string{
@@ -766,8 +767,7 @@ func TestGetRuleExtraLabels(t *testing.T) {
 				model.AlertNameLabel:                         rule.Title,
 				models.RuleUIDLabel:                          rule.UID,
 				ngmodels.AutogeneratedRouteLabel:             "true",
-				ngmodels.AutogeneratedRouteReceiverNameLabel: ns.Receiver,
-				ngmodels.AutogeneratedRouteSettingsHashLabel: ns.Fingerprint().String(),
+				ngmodels.AutogeneratedRouteSettingsHashLabel: ngmodels.NotificationSettingsFingerprint([]ngmodels.NotificationSettings{ns, ns2}).String(),
 			},
 		},
 	}
@@ -0,0 +1,144 @@
+package template
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template/parse"
+	"unicode"
+
+	"github.com/prometheus/prometheus/template"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ValidateRule checks that the templates in the annotations and labels of the rule can be expanded, so that mistakes
+// are reported when the rule is saved instead of when its alerts are. The error names the offending annotation or label.
+func ValidateRule(rule models.AlertRule) error {
+	refIDs := make([]string, 0, len(rule.Data))
+	for _, q := range rule.Data {
+		refIDs = append(refIDs, q.RefID)
+	}
+	for _, key := range sortedKeys(rule.Annotations) {
+		if err := Validate(key, rule.Annotations[key], refIDs); err != nil {
+			return fmt.Errorf("invalid template in annotation '%s': %w", key, err)
+		}
+	}
+	for _, key := range sortedKeys(rule.Labels) {
+		if err := Validate(key, rule.Labels[key], refIDs); err != nil {
+			return fmt.Errorf("invalid template in label '%s': %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the template can be parsed with the functions that are available when it is expanded. If refIDs
+// is not nil, it also checks that the template uses only the values of these queries and expressions in $values.
+func Validate(name, tmpl string, refIDs []string) error {
+	if !strings.Contains(tmpl, "{{") { // If it is not a template, it is not expanded.
+		return nil
+	}
+
+	// Parse the template as it is expanded, with the same variables and functions.
+	name = "__alert_" + name
+	tmpl = "{{- $labels := .Labels -}}{{- $values := .Values -}}{{- $value := .Value -}}" + tmpl
+	expander := template.NewTemplateExpander(context.Background(), tmpl, name, nil, 0, nil, nil, []string{"missingkey=invalid"})
+	expander.Funcs(defaultFuncs)
+	if err := expander.ParseTest(); err != nil {
+		return err
+	}
+	if refIDs == nil {
+		return nil
+	}
+
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+	trees := map[string]*parse.Tree{}
+	if _, err := tree.Parse(tmpl, "", "", trees); err != nil {
+		return err
+	}
+	for _, t := range trees {
+		for _, value := range usedValues(t.Root) {
+			if !isValueOf(value, refIDs) {
+				return fmt.Errorf("$values.%s does not refer to a query or expression of the rule", value)
+			}
+		}
+	}
+	return nil
+}
+
+// isValueOf returns true if the value is captured for one of the queries or expressions. Classic conditions capture a
+// value per condition, named after the RefID and the index of the condition.
+func isValueOf(value string, refIDs []string) bool {
+	for _, refID := range refIDs {
+		if value == refID {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(value, refID); ok && strings.IndexFunc(suffix, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// usedValues returns the names of the fields of $values that are used in the node, e.g. B for {{ $values.B.Value }}.
+func usedValues(node parse.Node) []string {
+	var result []string
+	var walk func(node parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.IfNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.RangeNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.WithNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.ChainNode:
+			walk(n.Node)
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$values" {
+				result = append(result, n.Ident[1])
+			}
+		}
+	}
+	walk(node)
+	return result
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
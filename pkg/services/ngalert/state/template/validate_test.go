@@ -0,0 +1,86 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		refIDs      []string
+		expectedErr string
+	}{{
+		name: "text without template is valid",
+		text: "this is not a template",
+	}, {
+		name:   "labels and values are valid",
+		text:   "{{ $labels.instance }} is {{ $values.B.Value }} ({{ $value }})",
+		refIDs: []string{"A", "B"},
+	}, {
+		name:   "alerting and prometheus functions are valid",
+		text:   "{{ humanize $values.B.Value }} {{ tableLink \"{}\" }}",
+		refIDs: []string{"A", "B"},
+	}, {
+		name:   "values of classic conditions are valid",
+		text:   "{{ $values.B0 }} {{ $values.B1 }}",
+		refIDs: []string{"A", "B"},
+	}, {
+		name:        "syntax errors are invalid",
+		text:        "{{ $labels.instance ",
+		expectedErr: "unclosed action",
+	}, {
+		name:        "unknown functions are invalid",
+		text:        "{{ unknown $labels }}",
+		expectedErr: "function \"unknown\" not defined",
+	}, {
+		name:        "undefined variables are invalid",
+		text:        "{{ $label.instance }}",
+		expectedErr: "undefined variable \"$label\"",
+	}, {
+		name:        "values of unknown queries are invalid",
+		text:        "{{ if true }}{{ $values.C.Value }}{{ end }}",
+		refIDs:      []string{"A", "B"},
+		expectedErr: "$values.C does not refer to a query or expression of the rule",
+	}, {
+		name: "values are not checked without refIDs",
+		text: "{{ $values.C.Value }}",
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate("test", test.text, test.refIDs)
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	rule := models.AlertRule{
+		Data: []models.AlertQuery{{RefID: "A"}, {RefID: "B"}},
+		Annotations: map[string]string{
+			"summary":     "{{ $labels.instance }} is {{ $values.B }}",
+			"description": "{{ $values.C }}",
+		},
+		Labels: map[string]string{
+			"severity": "{{ if gt $values.B.Value 10.0 }}critical{{ else }}warning{{ end }}",
+		},
+	}
+	err := ValidateRule(rule)
+	assert.ErrorContains(t, err, "invalid template in annotation 'description'")
+
+	rule.Annotations["description"] = "{{ $values.A }}"
+	require.NoError(t, ValidateRule(rule))
+
+	rule.Labels["team"] = "{{ $labels.team"
+	assert.ErrorContains(t, ValidateRule(rule), "invalid template in label 'team'")
+}
@@ -21,8 +21,8 @@ type UpdateAdminConfigurationCmd struct {
 type AdminConfigurationStore interface {
 	GetAdminConfiguration(orgID int64) (*ngmodels.AdminConfiguration, error)
 	GetAdminConfigurations() ([]*ngmodels.AdminConfiguration, error)
-	DeleteAdminConfiguration(orgID int64) error
-	UpdateAdminConfiguration(UpdateAdminConfigurationCmd) error
+	DeleteAdminConfiguration(ctx context.Context, orgID int64) error
+	UpdateAdminConfiguration(ctx context.Context, cmd UpdateAdminConfigurationCmd) error
 }
 
 func (st *DBstore) GetAdminConfiguration(orgID int64) (*ngmodels.AdminConfiguration, error) {
@@ -64,8 +64,8 @@ func (st DBstore) GetAdminConfigurations() ([]*ngmodels.AdminConfiguration, erro
 	return cfg, nil
 }
 
-func (st DBstore) DeleteAdminConfiguration(orgID int64) error {
-	return st.SQLStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+func (st DBstore) DeleteAdminConfiguration(ctx context.Context, orgID int64) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
 		_, err := sess.Exec("DELETE FROM ngalert_configuration WHERE org_id = ?", orgID)
 		if err != nil {
 			return err
@@ -75,8 +75,8 @@ func (st DBstore) DeleteAdminConfiguration(orgID int64) error {
 	})
 }
 
-func (st DBstore) UpdateAdminConfiguration(cmd UpdateAdminConfigurationCmd) error {
-	return st.SQLStore.WithTransactionalDbSession(context.Background(), func(sess *db.Session) error {
+func (st DBstore) UpdateAdminConfiguration(ctx context.Context, cmd UpdateAdminConfigurationCmd) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		has, err := sess.Table("ngalert_configuration").Where("org_id = ?", cmd.AdminConfiguration.OrgID).Exist()
 		if err != nil {
 			return err
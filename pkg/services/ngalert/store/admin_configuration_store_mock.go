@@ -3,6 +3,8 @@
 package store
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -21,13 +23,13 @@ func (_m *AdminConfigurationStoreMock) EXPECT() *AdminConfigurationStoreMock_Exp
 	return &AdminConfigurationStoreMock_Expecter{mock: &_m.Mock}
 }
 
-// DeleteAdminConfiguration provides a mock function with given fields: orgID
-func (_m *AdminConfigurationStoreMock) DeleteAdminConfiguration(orgID int64) error {
-	ret := _m.Called(orgID)
+// DeleteAdminConfiguration provides a mock function with given fields: ctx, orgID
+func (_m *AdminConfigurationStoreMock) DeleteAdminConfiguration(ctx context.Context, orgID int64) error {
+	ret := _m.Called(ctx, orgID)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(orgID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, orgID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -41,14 +43,15 @@ type AdminConfigurationStoreMock_DeleteAdminConfiguration_Call struct {
 }
 
 // DeleteAdminConfiguration is a helper method to define mock.On call
+//   - ctx context.Context
 //   - orgID int64
-func (_e *AdminConfigurationStoreMock_Expecter) DeleteAdminConfiguration(orgID any) *AdminConfigurationStoreMock_DeleteAdminConfiguration_Call {
-	return &AdminConfigurationStoreMock_DeleteAdminConfiguration_Call{Call: _e.mock.On("DeleteAdminConfiguration", orgID)}
+func (_e *AdminConfigurationStoreMock_Expecter) DeleteAdminConfiguration(ctx any, orgID any) *AdminConfigurationStoreMock_DeleteAdminConfiguration_Call {
+	return &AdminConfigurationStoreMock_DeleteAdminConfiguration_Call{Call: _e.mock.On("DeleteAdminConfiguration", ctx, orgID)}
 }
 
-func (_c *AdminConfigurationStoreMock_DeleteAdminConfiguration_Call) Run(run func(orgID int64)) *AdminConfigurationStoreMock_DeleteAdminConfiguration_Call {
+func (_c *AdminConfigurationStoreMock_DeleteAdminConfiguration_Call) Run(run func(ctx context.Context, orgID int64)) *AdminConfigurationStoreMock_DeleteAdminConfiguration_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(int64))
+		run(args[0].(context.Context), args[1].(int64))
 	})
 	return _c
 }
@@ -149,13 +152,13 @@ func (_c *AdminConfigurationStoreMock_GetAdminConfigurations_Call) Return(_a0 []
 	return _c
 }
 
-// UpdateAdminConfiguration provides a mock function with given fields: _a0
-func (_m *AdminConfigurationStoreMock) UpdateAdminConfiguration(_a0 UpdateAdminConfigurationCmd) error {
-	ret := _m.Called(_a0)
+// UpdateAdminConfiguration provides a mock function with given fields: ctx, cmd
+func (_m *AdminConfigurationStoreMock) UpdateAdminConfiguration(ctx context.Context, cmd UpdateAdminConfigurationCmd) error {
+	ret := _m.Called(ctx, cmd)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(UpdateAdminConfigurationCmd) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, UpdateAdminConfigurationCmd) error); ok {
+		r0 = rf(ctx, cmd)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -169,14 +172,15 @@ type AdminConfigurationStoreMock_UpdateAdminConfiguration_Call struct {
 }
 
 // UpdateAdminConfiguration is a helper method to define mock.On call
-//   - _a0 UpdateAdminConfigurationCmd
-func (_e *AdminConfigurationStoreMock_Expecter) UpdateAdminConfiguration(_a0 any) *AdminConfigurationStoreMock_UpdateAdminConfiguration_Call {
-	return &AdminConfigurationStoreMock_UpdateAdminConfiguration_Call{Call: _e.mock.On("UpdateAdminConfiguration", _a0)}
+//   - ctx context.Context
+//   - cmd UpdateAdminConfigurationCmd
+func (_e *AdminConfigurationStoreMock_Expecter) UpdateAdminConfiguration(ctx any, cmd any) *AdminConfigurationStoreMock_UpdateAdminConfiguration_Call {
+	return &AdminConfigurationStoreMock_UpdateAdminConfiguration_Call{Call: _e.mock.On("UpdateAdminConfiguration", ctx, cmd)}
 }
 
-func (_c *AdminConfigurationStoreMock_UpdateAdminConfiguration_Call) Run(run func(_a0 UpdateAdminConfigurationCmd)) *AdminConfigurationStoreMock_UpdateAdminConfiguration_Call {
+func (_c *AdminConfigurationStoreMock_UpdateAdminConfiguration_Call) Run(run func(ctx context.Context, cmd UpdateAdminConfigurationCmd)) *AdminConfigurationStoreMock_UpdateAdminConfiguration_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(UpdateAdminConfigurationCmd))
+		run(args[0].(context.Context), args[1].(UpdateAdminConfigurationCmd))
 	})
 	return _c
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"golang.org/x/exp/maps"
@@ -53,12 +54,37 @@ func getAlertRuleByUID(sess *db.Session, alertRuleUID string, orgID int64) (*ngm
 func (st DBstore) DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error {
 	logger := st.Logger.New("org_id", orgID, "rule_uids", ruleUID)
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		groups, err := ruleGroupKeys(sess, orgID, ruleUID...)
+		if err != nil {
+			return err
+		}
+
 		rows, err := sess.Table("alert_rule").Where("org_id = ?", orgID).In("uid", ruleUID).Delete(ngmodels.AlertRule{})
 		if err != nil {
 			return err
 		}
 		logger.Debug("Deleted alert rules", "count", rows)
 
+		if err := deleteRuleLabels(sess, orgID, ruleUID...); err != nil {
+			return err
+		}
+
+		if err := deleteRuleTags(sess, orgID, groups, ruleUID...); err != nil {
+			return err
+		}
+
+		if err := deleteEmptyRuleGroupArchives(sess, orgID); err != nil {
+			return err
+		}
+
+		if err := deleteEmptyRuleGroupsManagedBy(sess, orgID); err != nil {
+			return err
+		}
+
+		if err := deleteEmptyRuleGroupLocks(sess, groups); err != nil {
+			return err
+		}
+
 		rows, err = sess.Table("alert_rule_version").Where("rule_org_id = ?", orgID).In("rule_uid", ruleUID).Delete(ngmodels.AlertRule{})
 		if err != nil {
 			return err
@@ -119,8 +145,66 @@ func (st DBstore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmode
 	return result, err
 }
 
+// GetAlertRulesGroupsByRuleUIDs is a handler for retrieving the groups of alert rules that the rules with the UIDs
+// belong to, keyed by group. The UIDs are queried in batches, so that there is one round-trip per batch rather than per
+// rule. The UIDs of rules that do not exist are ignored.
+func (st DBstore) GetAlertRulesGroupsByRuleUIDs(ctx context.Context, query *ngmodels.GetAlertRulesGroupsByRuleUIDsQuery) (map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup, error) {
+	result := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+	if len(query.UIDs) == 0 {
+		return result, nil
+	}
+	opts := sqlstore.NativeSettingsForDialect(st.SQLStore.GetDialect())
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sqlstore.InBatches(query.UIDs, opts, func(batch any) error {
+			uids := batch.([]string)
+			args := make([]any, 0, len(uids)+1)
+			args = append(args, query.OrgID)
+			in := make([]string, 0, len(uids))
+			for _, uid := range uids {
+				args = append(args, uid)
+				in = append(in, "?")
+			}
+			var rules []*ngmodels.AlertRule
+			err := sess.Table("alert_rule").Where(fmt.Sprintf(
+				"EXISTS (SELECT 1 FROM alert_rule AS b WHERE b.org_id = ? AND b.namespace_uid = alert_rule.namespace_uid AND b.rule_group = alert_rule.rule_group AND b.uid IN (%s))",
+				strings.Join(in, ","),
+			), args...).Where("alert_rule.org_id = ?", query.OrgID).Asc("namespace_uid", "rule_group", "rule_group_idx", "id").Find(&rules)
+			if err != nil {
+				return err
+			}
+			groups := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+			for _, rule := range rules {
+				key := rule.GetGroupKey()
+				groups[key] = append(groups[key], rule)
+			}
+			// A group is read again by every batch that has the UID of one of its rules.
+			for key, group := range groups {
+				if _, ok := result[key]; !ok {
+					result[key] = group
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetAlertRuleVersions returns the versions of the alert rule with the UID that are stored, the latest first.
+func (st DBstore) GetAlertRuleVersions(ctx context.Context, orgID int64, ruleUID string) ([]*ngmodels.AlertRuleVersion, error) {
+	versions := make([]*ngmodels.AlertRuleVersion, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_rule_version").Where("rule_org_id = ? AND rule_uid = ?", orgID, ruleUID).Desc("version", "id").Find(&versions)
+	})
+	return versions, err
+}
+
 // InsertAlertRules is a handler for creating/updating alert rules.
 // Returns the UID and ID of rules that were created in the same order as the input rules.
+// Any number of rules can be inserted, rules and their versions are inserted in batches that stay within the limits of
+// the database.
 func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRule) ([]ngmodels.AlertRuleKeyWithId, error) {
 	ids := make([]ngmodels.AlertRuleKeyWithId, 0, len(rules))
 	return ids, st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
@@ -128,7 +212,13 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 		ruleVersions := make([]ngmodels.AlertRuleVersion, 0, len(rules))
 		for i := range rules {
 			r := rules[i]
-			if r.UID == "" {
+			if r.UID == "" && st.Cfg.DeterministicRuleUIDs {
+				uid, err := deterministicAlertRuleUID(sess, r, newRules)
+				if err != nil {
+					return err
+				}
+				r.UID = uid
+			} else if r.UID == "" {
 				uid, err := GenerateNewAlertRuleUID(sess, r.OrgID, r.Title)
 				if err != nil {
 					return fmt.Errorf("failed to generate UID for alert rule %q: %w", r.Title, err)
@@ -148,6 +238,7 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				RuleOrgID:            r.OrgID,
 				RuleNamespaceUID:     r.NamespaceUID,
 				RuleGroup:            r.RuleGroup,
+				RuleGroupIndex:       r.RuleGroupIndex,
 				ParentVersion:        0,
 				Version:              r.Version,
 				Created:              r.Updated,
@@ -161,18 +252,38 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				Annotations:          r.Annotations,
 				Labels:               r.Labels,
 				NotificationSettings: r.NotificationSettings,
+
+				DataAvailabilityPeriod:  r.DataAvailabilityPeriod,
+				DataAvailabilityDelay:   r.DataAvailabilityDelay,
+				ShardAffinity:           r.ShardAffinity,
+				IncidentHooks:           r.IncidentHooks,
+				ExpiresAt:               r.ExpiresAt,
+				IntervalOverrideSeconds: r.IntervalOverrideSeconds,
+				EvaluationTimeout:       r.EvaluationTimeout,
+				Record:                  r.Record,
 			})
 		}
+		opts := sqlstore.NativeSettingsForDialect(st.SQLStore.GetDialect())
 		if len(newRules) > 0 {
-			// we have to insert the rules one by one as otherwise we are
-			// not able to fetch the inserted id as it's not supported by xorm
-			for i := range newRules {
-				if _, err := sess.Insert(&newRules[i]); err != nil {
-					if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
-						return ngmodels.ErrAlertRuleConflict(newRules[i], ngmodels.ErrAlertRuleUniqueConstraintViolation)
-					}
-					return fmt.Errorf("failed to create new rules: %w", err)
+			if err := findAlertRulesConflict(sess, opts, newRules); err != nil {
+				return err
+			}
+			// Rules are inserted in batches to stay below the limit of parameters per statement of the database.
+			if _, err := sess.BulkInsert("alert_rule", newRules, opts); err != nil {
+				if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+					// A conflicting rule was created after the check above.
+					return fmt.Errorf("failed to create new rules: %w", ngmodels.ErrAlertRuleUniqueConstraintViolation)
 				}
+				return fmt.Errorf("failed to create new rules: %w", err)
+			}
+			// The IDs of rows inserted by a single statement are not returned by xorm, so they are read back.
+			if err := readAlertRuleIDs(sess, opts, newRules); err != nil {
+				return err
+			}
+			if err := insertRuleLabels(sess, opts, newRules); err != nil {
+				return err
+			}
+			for i := range newRules {
 				ids = append(ids, ngmodels.AlertRuleKeyWithId{
 					AlertRuleKey: newRules[i].GetKey(),
 					ID:           newRules[i].ID,
@@ -181,7 +292,8 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 		}
 
 		if len(ruleVersions) > 0 {
-			if _, err := sess.Insert(&ruleVersions); err != nil {
+			// Versions are inserted in batches to stay below the limit of parameters per statement of the database.
+			if _, err := sess.BulkInsert("alert_rule_version", ruleVersions, opts); err != nil {
 				return fmt.Errorf("failed to create new rule versions: %w", err)
 			}
 		}
@@ -189,6 +301,84 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 	})
 }
 
+// findAlertRulesConflict returns an error for the first of the new rules that has the UID, or the title in the folder,
+// of another new rule or of a rule in the database.
+func findAlertRulesConflict(sess *db.Session, opts sqlstore.BulkOpSettings, newRules []ngmodels.AlertRule) error {
+	type titleKey struct {
+		orgID        int64
+		namespaceUID string
+		title        string
+	}
+	uids := make(map[ngmodels.AlertRuleKey]struct{}, len(newRules))
+	titles := make(map[titleKey]struct{}, len(newRules))
+	err := sqlstore.InBatches(newRules, opts, func(batch any) error {
+		rules := batch.([]ngmodels.AlertRule)
+		cond := make([]string, 0, len(rules))
+		args := make([]any, 0, 5*len(rules))
+		for _, r := range rules {
+			cond = append(cond, "(org_id = ? AND uid = ?) OR (org_id = ? AND namespace_uid = ? AND title = ?)")
+			args = append(args, r.OrgID, r.UID, r.OrgID, r.NamespaceUID, r.Title)
+		}
+		var existing []ngmodels.AlertRule
+		if err := sess.Table("alert_rule").Cols("org_id", "uid", "namespace_uid", "title").Where(strings.Join(cond, " OR "), args...).Find(&existing); err != nil {
+			return fmt.Errorf("failed to find conflicting rules: %w", err)
+		}
+		for _, r := range existing {
+			uids[r.GetKey()] = struct{}{}
+			titles[titleKey{orgID: r.OrgID, namespaceUID: r.NamespaceUID, title: r.Title}] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range newRules {
+		tk := titleKey{orgID: r.OrgID, namespaceUID: r.NamespaceUID, title: r.Title}
+		_, uidExists := uids[r.GetKey()]
+		_, titleExists := titles[tk]
+		if uidExists || titleExists {
+			return ngmodels.ErrAlertRuleConflict(r, ngmodels.ErrAlertRuleUniqueConstraintViolation)
+		}
+		uids[r.GetKey()] = struct{}{}
+		titles[tk] = struct{}{}
+	}
+	return nil
+}
+
+// readAlertRuleIDs sets the IDs of the rules from the database.
+func readAlertRuleIDs(sess *db.Session, opts sqlstore.BulkOpSettings, rules []ngmodels.AlertRule) error {
+	ids := make(map[ngmodels.AlertRuleKey]int64, len(rules))
+	err := sqlstore.InBatches(rules, opts, func(batch any) error {
+		batchRules := batch.([]ngmodels.AlertRule)
+		cond := make([]string, 0, len(batchRules))
+		args := make([]any, 0, 2*len(batchRules))
+		for _, r := range batchRules {
+			cond = append(cond, "(org_id = ? AND uid = ?)")
+			args = append(args, r.OrgID, r.UID)
+		}
+		var inserted []ngmodels.AlertRule
+		if err := sess.Table("alert_rule").Cols("id", "org_id", "uid").Where(strings.Join(cond, " OR "), args...).Find(&inserted); err != nil {
+			return fmt.Errorf("failed to read the IDs of new rules: %w", err)
+		}
+		for _, r := range inserted {
+			ids[r.GetKey()] = r.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for i := range rules {
+		id, ok := ids[rules[i].GetKey()]
+		if !ok {
+			return fmt.Errorf("failed to read the ID of new rule %s", rules[i].UID)
+		}
+		rules[i].ID = id
+	}
+	return nil
+}
+
 // UpdateAlertRules is a handler for updating alert rules.
 func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateRule) error {
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
@@ -209,7 +399,10 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				return err
 			}
 			// no way to update multiple rules at once
-			if updated, err := sess.ID(r.Existing.ID).AllCols().Update(r.New); err != nil || updated == 0 {
+			// xorm converts the struct fields of the rule, such as Record, only if it is given a pointer, and it
+			// increments the version of the rule it is given, so it updates a copy.
+			updatedRule := r.New
+			if updated, err := sess.ID(r.Existing.ID).AllCols().Update(&updatedRule); err != nil || updated == 0 {
 				if err != nil {
 					if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
 						return ngmodels.ErrAlertRuleConflict(r.New, ngmodels.ErrAlertRuleUniqueConstraintViolation)
@@ -218,6 +411,9 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				}
 				return fmt.Errorf("%w: alert rule UID %s version %d", ErrOptimisticLock, r.New.UID, r.New.Version)
 			}
+			if err := WriteRuleLabels(sess, r.New.OrgID, r.New.UID, r.New.Labels); err != nil {
+				return err
+			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
 				RuleOrgID:            r.New.OrgID,
@@ -238,6 +434,15 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				Annotations:          r.New.Annotations,
 				Labels:               r.New.Labels,
 				NotificationSettings: r.New.NotificationSettings,
+
+				DataAvailabilityPeriod:  r.New.DataAvailabilityPeriod,
+				DataAvailabilityDelay:   r.New.DataAvailabilityDelay,
+				ShardAffinity:           r.New.ShardAffinity,
+				IncidentHooks:           r.New.IncidentHooks,
+				ExpiresAt:               r.New.ExpiresAt,
+				IntervalOverrideSeconds: r.New.IntervalOverrideSeconds,
+				EvaluationTimeout:       r.New.EvaluationTimeout,
+				Record:                  r.New.Record,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -245,7 +450,26 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				return fmt.Errorf("failed to create new rule versions: %w", err)
 			}
 		}
-		return nil
+		// Rules moved to other groups can leave their groups empty.
+		var movedFrom []ngmodels.AlertRuleGroupKey
+		for _, r := range rules {
+			if r.Existing.GetGroupKey() != r.New.GetGroupKey() {
+				movedFrom = append(movedFrom, r.Existing.GetGroupKey())
+			}
+		}
+		if len(movedFrom) == 0 {
+			return nil
+		}
+		if err := deleteRuleTags(sess, movedFrom[0].OrgID, movedFrom); err != nil {
+			return err
+		}
+		if err := deleteEmptyRuleGroupArchives(sess, movedFrom[0].OrgID); err != nil {
+			return err
+		}
+		if err := deleteEmptyRuleGroupsManagedBy(sess, movedFrom[0].OrgID); err != nil {
+			return err
+		}
+		return deleteEmptyRuleGroupLocks(sess, movedFrom)
 	})
 }
 
@@ -370,6 +594,37 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 			q = q.Where("rule_group = ?", query.RuleGroup)
 		}
 
+		if query.RuleGroupPrefix != "" {
+			q = q.Where("rule_group LIKE ? ESCAPE '!'", escapeLike(query.RuleGroupPrefix)+"%")
+		}
+
+		if len(query.Provenances) > 0 {
+			cond, args := provenancesCondition(query.Provenances)
+			q = q.Where(cond, args...)
+		}
+
+		// Label matchers are evaluated with the label index when possible, the others after the rules are read.
+		unindexedMatchers := query.LabelMatchers
+		if len(query.LabelMatchers) > 0 {
+			var cond string
+			var args []any
+			cond, args, unindexedMatchers = labelMatchersCondition(query.LabelMatchers)
+			if cond != "" {
+				q = q.Where(cond, args...)
+			}
+		}
+
+		if query.ExpiredAt != nil {
+			q = q.Where("expires_at IS NOT NULL AND expires_at <= ?", query.ExpiredAt.UTC())
+		}
+
+		switch query.ArchivedGroups {
+		case ngmodels.ArchivedRuleGroupsExclude:
+			q = q.Where("NOT " + archivedRuleGroupCondition)
+		case ngmodels.ArchivedRuleGroupsOnly:
+			q = q.Where(archivedRuleGroupCondition)
+		}
+
 		if query.ReceiverName != "" {
 			q, err = st.filterByReceiverName(query.ReceiverName, q)
 			if err != nil {
@@ -379,6 +634,14 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 
 		q = q.Asc("namespace_uid", "rule_group", "rule_group_idx", "id")
 
+		// The filter by receiver has false positives, and some label matchers are evaluated after the rules are read, so
+		// the page is selected while reading the rows instead of in the query in these cases.
+		skip := query.Offset()
+		if query.Limit > 0 && query.ReceiverName == "" && len(unindexedMatchers) == 0 {
+			q = q.Limit(int(query.Limit), int(skip))
+			skip = 0
+		}
+
 		alertRules := make([]*ngmodels.AlertRule, 0)
 		rule := new(ngmodels.AlertRule)
 		rows, err := q.Rows(rule)
@@ -391,6 +654,9 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 
 		// Deserialize each rule separately in case any of them contain invalid JSON.
 		for rows.Next() {
+			if query.Limit > 0 && int64(len(alertRules)) >= query.Limit {
+				break
+			}
 			rule := new(ngmodels.AlertRule)
 			err = rows.Scan(rule)
 			if err != nil {
@@ -404,6 +670,13 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 					continue
 				}
 			}
+			if len(unindexedMatchers) > 0 && !query.MatchLabels(rule.Labels) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
 			alertRules = append(alertRules, rule)
 		}
 
@@ -413,8 +686,168 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 	return result, err
 }
 
+// SearchAlertRules returns a page of the alert rules of an organization whose title, labels or annotations contain the
+// query text, along with the total number of matching rules.
+func (st DBstore) SearchAlertRules(ctx context.Context, query *ngmodels.SearchAlertRulesQuery) (*ngmodels.SearchAlertRulesResult, error) {
+	// LOWER changes the case of ASCII letters only in SQLite, so queries with other letters are matched in memory.
+	if !isASCII(query.Query) {
+		return st.searchAlertRulesInMemory(ctx, query)
+	}
+
+	cond, condArgs, err := st.searchCondition(query.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ngmodels.SearchAlertRulesResult{Rules: make([]*ngmodels.AlertRule, 0)}
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		// The session is reset after each query, so the conditions are applied for both the count and the select.
+		filter := func() *xorm.Session {
+			q := sess.Table("alert_rule").Where("org_id = ?", query.OrgID)
+			if len(query.NamespaceUIDs) > 0 {
+				args := make([]any, 0, len(query.NamespaceUIDs))
+				for _, namespaceUID := range query.NamespaceUIDs {
+					args = append(args, namespaceUID)
+				}
+				q = q.Where(fmt.Sprintf("namespace_uid IN (%s)", strings.Repeat("?,", len(query.NamespaceUIDs)-1)+"?"), args...)
+			}
+			if cond != "" {
+				q = q.Where(cond, condArgs...)
+			}
+			return q
+		}
+
+		count, err := filter().Count()
+		if err != nil {
+			return err
+		}
+		result.TotalCount = count
+		if count == 0 {
+			return nil
+		}
+
+		q := filter().Asc("namespace_uid", "rule_group", "rule_group_idx", "id")
+		if query.Limit > 0 {
+			page := query.Page
+			if page < 1 {
+				page = 1
+			}
+			q = q.Limit(int(query.Limit), int((page-1)*query.Limit))
+		}
+
+		rows, err := q.Rows(new(ngmodels.AlertRule))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = rows.Close()
+		}()
+
+		// Deserialize each rule separately in case any of them contain invalid JSON.
+		for rows.Next() {
+			rule := new(ngmodels.AlertRule)
+			err = rows.Scan(rule)
+			if err != nil {
+				st.Logger.Error("Invalid rule found in DB store, ignoring it", "func", "SearchAlertRules", "error", err)
+				continue
+			}
+			result.Rules = append(result.Rules, rule)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// searchAlertRulesInMemory is like SearchAlertRules but it reads all rules of the folders and matches them in memory.
+func (st DBstore) searchAlertRulesInMemory(ctx context.Context, query *ngmodels.SearchAlertRulesQuery) (*ngmodels.SearchAlertRulesResult, error) {
+	rules, err := st.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+		OrgID:         query.OrgID,
+		NamespaceUIDs: query.NamespaceUIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.ToLower(strings.TrimSpace(query.Query))
+	matching := make([]*ngmodels.AlertRule, 0)
+	for _, rule := range rules {
+		if ruleContainsText(rule, text) {
+			matching = append(matching, rule)
+		}
+	}
+
+	result := &ngmodels.SearchAlertRulesResult{Rules: matching, TotalCount: int64(len(matching))}
+	if query.Limit > 0 {
+		page := query.Page
+		if page < 1 {
+			page = 1
+		}
+		start := min((page-1)*query.Limit, int64(len(matching)))
+		end := min(start+query.Limit, int64(len(matching)))
+		result.Rules = matching[start:end]
+	}
+	return result, nil
+}
+
+// ruleContainsText returns true if the title, or a key or value of the labels or annotations of the rule contains the
+// given lower case text, ignoring case.
+func ruleContainsText(rule *ngmodels.AlertRule, text string) bool {
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), text)
+	}
+	if contains(rule.Title) {
+		return true
+	}
+	for _, kv := range []map[string]string{rule.Labels, rule.Annotations} {
+		for k, v := range kv {
+			if contains(k) || contains(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// searchCondition returns an SQL condition that matches rules whose title, labels or annotations contain the given
+// text, ignoring case. It returns an empty condition if text is empty.
+func (st DBstore) searchCondition(text string) (string, []any, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return "", nil, nil
+	}
+	// Labels and annotations are stored as JSON, so the text is escaped according to JSON rules to match them.
+	b, err := json.Marshal(text)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshall search query: %w", err)
+	}
+	jsonText := string(b[1 : len(b)-1])
+
+	// An explicit escape character is used because backslashes are not escape characters in SQLite.
+	like := st.SQLStore.GetDialect().LikeStr()
+	cond := fmt.Sprintf("(LOWER(title) %[1]s ? ESCAPE '!' OR LOWER(labels) %[1]s ? ESCAPE '!' OR LOWER(annotations) %[1]s ? ESCAPE '!')", like)
+	pattern, jsonPattern := "%"+escapeLike(text)+"%", "%"+escapeLike(jsonText)+"%"
+	return cond, []any{pattern, jsonPattern, jsonPattern}, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern, using '!' as escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
+
 // Count returns either the number of the alert rules under a specific org (if orgID is not zero)
-// or the number of all the alert rules
+// or the number of all the alert rules. Rules with a provenance that is exempt from the quota are not counted.
 func (st DBstore) Count(ctx context.Context, orgID int64) (int64, error) {
 	type result struct {
 		Count int64
@@ -423,11 +856,20 @@ func (st DBstore) Count(ctx context.Context, orgID int64) (int64, error) {
 	r := result{}
 	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
 		rawSQL := "SELECT COUNT(*) as count from alert_rule"
+		filters := make([]string, 0, 2)
 		args := make([]any, 0)
 		if orgID != 0 {
-			rawSQL += " WHERE org_id=?"
+			filters = append(filters, "org_id=?")
 			args = append(args, orgID)
 		}
+		if len(st.Cfg.QuotaExemptProvenances) > 0 {
+			cond, condArgs := provenanceCondition(st.Cfg.QuotaExemptProvenances)
+			filters = append(filters, "NOT "+cond)
+			args = append(args, condArgs...)
+		}
+		if len(filters) > 0 {
+			rawSQL += " WHERE " + strings.Join(filters, " AND ")
+		}
 		if _, err := sess.SQL(rawSQL, args...).Get(&r); err != nil {
 			return err
 		}
@@ -436,6 +878,63 @@ func (st DBstore) Count(ctx context.Context, orgID int64) (int64, error) {
 	return r.Count, err
 }
 
+// CountByProvenances returns the number of alert rules of the org that have one of the given provenances.
+func (st DBstore) CountByProvenances(ctx context.Context, orgID int64, provenances ...ngmodels.Provenance) (int64, error) {
+	if len(provenances) == 0 {
+		return 0, nil
+	}
+	values := make([]string, 0, len(provenances))
+	for _, p := range provenances {
+		values = append(values, string(p))
+	}
+
+	var count int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *sqlstore.DBSession) error {
+		cond, args := provenanceCondition(values)
+		var err error
+		count, err = sess.Table("alert_rule").Where("org_id = ?", orgID).And(cond, args...).Count()
+		return err
+	})
+	return count, err
+}
+
+// provenanceCondition returns a condition on the alert_rule table that matches rules with one of the given provenances.
+func provenanceCondition(provenances []string) (string, []any) {
+	args := make([]any, 0, len(provenances)+1)
+	args = append(args, (&ngmodels.AlertRule{}).ResourceType())
+	for _, p := range provenances {
+		args = append(args, p)
+	}
+	cond := fmt.Sprintf("EXISTS (SELECT 1 FROM provenance_type p WHERE p.record_type = ? AND p.record_key = alert_rule.uid AND p.org_id = alert_rule.org_id AND p.provenance IN (%s))", strings.Repeat("?,", len(provenances)-1)+"?")
+	return cond, args
+}
+
+// provenancesCondition returns a condition on the alert_rule table that matches rules with one of the given
+// provenances. ProvenanceNone matches the rules without provenance.
+func provenancesCondition(provenances []ngmodels.Provenance) (string, []any) {
+	var values []string
+	var none bool
+	for _, p := range provenances {
+		if p == ngmodels.ProvenanceNone {
+			none = true
+			continue
+		}
+		values = append(values, string(p))
+	}
+	var conds []string
+	var args []any
+	if len(values) > 0 {
+		cond, condArgs := provenanceCondition(values)
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+	if none {
+		conds = append(conds, "NOT EXISTS (SELECT 1 FROM provenance_type p WHERE p.record_type = ? AND p.record_key = alert_rule.uid AND p.org_id = alert_rule.org_id AND p.provenance <> '')")
+		args = append(args, (&ngmodels.AlertRule{}).ResourceType())
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
 func (st DBstore) GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error) {
 	var interval int64 = 0
 	return interval, st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -485,7 +984,8 @@ func (st DBstore) GetNamespaceByUID(ctx context.Context, uid string, orgID int64
 func (st DBstore) GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error) {
 	var result []ngmodels.AlertRuleKeyWithVersion
 	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
-		alertRulesSql := sess.Table("alert_rule").Select("org_id, uid, version")
+		// The rules of archived groups are not scheduled.
+		alertRulesSql := sess.Table("alert_rule").Select("org_id, uid, version").Where("NOT " + archivedRuleGroupCondition)
 		var disabledOrgs []int64
 
 		for orgID := range st.Cfg.DisabledOrgs {
@@ -514,7 +1014,7 @@ func (st DBstore) GetAlertRulesForScheduling(ctx context.Context, query *ngmodel
 			disabledOrgs = append(disabledOrgs, orgID)
 		}
 
-		alertRulesSql := sess.Table("alert_rule")
+		alertRulesSql := sess.Table("alert_rule").Where("NOT " + archivedRuleGroupCondition)
 		if len(disabledOrgs) > 0 {
 			alertRulesSql.NotIn("org_id", disabledOrgs)
 		}
@@ -649,6 +1149,26 @@ var GenerateNewAlertRuleUID = func(sess *db.Session, orgID int64, ruleTitle stri
 	return "", ngmodels.ErrAlertRuleFailedGenerateUniqueUID
 }
 
+// deterministicAlertRuleUID returns the UID derived from the organization, folder, group and title of the rule. It
+// fails if the UID is taken by a stored rule or by one of the rules inserted with it, which means that a rule with the
+// same title already exists in the group.
+func deterministicAlertRuleUID(sess *db.Session, rule ngmodels.AlertRule, inserted []ngmodels.AlertRule) (string, error) {
+	uid := ngmodels.DeterministicAlertRuleUID(rule.OrgID, rule.NamespaceUID, rule.RuleGroup, rule.Title)
+	exists := slices.ContainsFunc(inserted, func(r ngmodels.AlertRule) bool { return r.UID == uid })
+	if !exists {
+		var err error
+		exists, err = sess.Where("org_id=? AND uid=?", rule.OrgID, uid).Exist(&ngmodels.AlertRule{})
+		if err != nil {
+			return "", err
+		}
+	}
+	if exists {
+		rule.UID = uid
+		return "", ngmodels.ErrAlertRuleConflict(rule, fmt.Errorf("rule with UID %s, derived from its title, already exists", uid))
+	}
+	return uid, nil
+}
+
 // validateAlertRule validates the alert rule including db-level restrictions on field lengths.
 func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if err := alertRule.ValidateAlertRule(st.Cfg); err != nil {
@@ -692,7 +1212,7 @@ func (st DBstore) ListNotificationSettings(ctx context.Context, q ngmodels.ListN
 		var ns []ngmodels.NotificationSettings
 		if q.ReceiverName != "" { // if filter by receiver name is specified, perform fine filtering on client to avoid false-positives
 			for _, setting := range rule.NotificationSettings {
-				if q.ReceiverName == setting.Receiver { // currently, there can be only one setting. If in future there are more, we will return all settings of a rule that has a setting with receiver
+				if q.ReceiverName == setting.Receiver { // return all settings of a rule that has a setting with receiver
 					ns = rule.NotificationSettings
 					break
 				}
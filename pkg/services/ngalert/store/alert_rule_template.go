@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type alertRuleTemplateRecord struct {
+	ID      int64     `xorm:"pk autoincr 'id'"`
+	OrgID   int64     `xorm:"'org_id'"`
+	UID     string    `xorm:"'uid'"`
+	Title   string    `xorm:"'title'"`
+	Updated time.Time `xorm:"'updated'"`
+	// Data is the JSON encoding of alertRuleTemplateData.
+	Data []byte `xorm:"'data'"`
+}
+
+func (r alertRuleTemplateRecord) TableName() string {
+	return "alert_rule_template"
+}
+
+type alertRuleTemplateData struct {
+	Parameters []models.AlertRuleTemplateParameter
+	Rule       models.AlertRule
+}
+
+func (r alertRuleTemplateRecord) toModel() (*models.AlertRuleTemplate, error) {
+	var data alertRuleTemplateData
+	if err := json.Unmarshal(r.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode alert rule template %s: %w", r.UID, err)
+	}
+	return &models.AlertRuleTemplate{
+		OrgID:      r.OrgID,
+		UID:        r.UID,
+		Title:      r.Title,
+		Parameters: data.Parameters,
+		Rule:       data.Rule,
+		Updated:    r.Updated,
+	}, nil
+}
+
+// ListAlertRuleTemplates returns the alert rule templates of the organization ordered by title.
+func (st DBstore) ListAlertRuleTemplates(ctx context.Context, orgID int64) ([]*models.AlertRuleTemplate, error) {
+	templates := make([]*models.AlertRuleTemplate, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var records []alertRuleTemplateRecord
+		if err := sess.Where("org_id = ?", orgID).Asc("title", "uid").Find(&records); err != nil {
+			return fmt.Errorf("failed to query for alert rule templates: %w", err)
+		}
+		for _, r := range records {
+			t, err := r.toModel()
+			if err != nil {
+				return err
+			}
+			templates = append(templates, t)
+		}
+		return nil
+	})
+	return templates, err
+}
+
+// GetAlertRuleTemplate returns the alert rule template of the organization with the UID. It returns
+// models.ErrAlertRuleTemplateNotFound if it does not exist.
+func (st DBstore) GetAlertRuleTemplate(ctx context.Context, orgID int64, uid string) (*models.AlertRuleTemplate, error) {
+	var template *models.AlertRuleTemplate
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var record alertRuleTemplateRecord
+		ok, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Get(&record)
+		if err != nil {
+			return fmt.Errorf("failed to query for alert rule template: %w", err)
+		}
+		if !ok {
+			return models.ErrAlertRuleTemplateNotFound.Errorf("")
+		}
+		template, err = record.toModel()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// SaveAlertRuleTemplate creates the alert rule template, or replaces the template of its organization with the same UID.
+func (st DBstore) SaveAlertRuleTemplate(ctx context.Context, template models.AlertRuleTemplate) error {
+	data, err := json.Marshal(alertRuleTemplateData{Parameters: template.Parameters, Rule: template.Rule})
+	if err != nil {
+		return fmt.Errorf("failed to encode alert rule template: %w", err)
+	}
+	record := alertRuleTemplateRecord{
+		OrgID:   template.OrgID,
+		UID:     template.UID,
+		Title:   template.Title,
+		Updated: template.Updated,
+		Data:    data,
+	}
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", record.OrgID, record.UID).Exist(&alertRuleTemplateRecord{})
+		if err != nil {
+			return fmt.Errorf("failed to query for alert rule template: %w", err)
+		}
+		if exists {
+			if _, err := sess.Where("org_id = ? AND uid = ?", record.OrgID, record.UID).Cols("title", "updated", "data").Update(&record); err != nil {
+				return fmt.Errorf("failed to update alert rule template: %w", err)
+			}
+			return nil
+		}
+		if _, err := sess.Insert(&record); err != nil {
+			return fmt.Errorf("failed to insert alert rule template: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteAlertRuleTemplate deletes the alert rule template of the organization with the UID. It returns
+// models.ErrAlertRuleTemplateNotFound if it does not exist.
+func (st DBstore) DeleteAlertRuleTemplate(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		deleted, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&alertRuleTemplateRecord{})
+		if err != nil {
+			return fmt.Errorf("failed to delete alert rule template: %w", err)
+		}
+		if deleted == 0 {
+			return models.ErrAlertRuleTemplateNotFound.Errorf("")
+		}
+		return nil
+	})
+}
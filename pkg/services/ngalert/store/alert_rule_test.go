@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/grafana/grafana/pkg/bus"
@@ -546,6 +548,98 @@ func TestIntegration_DeleteInFolder(t *testing.T) {
 	})
 }
 
+func TestIntegrationDeleteAlertRulesDeletesTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+	}
+	// The key of the group is longer than the keys of other provisioned resources.
+	groupKey := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: strings.Repeat("g", 190)}
+	gen := models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithGroupKey(groupKey))
+	rule := createRule(t, store, gen)
+	other := createRule(t, store, gen)
+	ctx := context.Background()
+	for _, o := range []models.Provisionable{rule, other, rule.GetGroupKey()} {
+		require.NoError(t, store.SetResourceTags(ctx, o, rule.OrgID, []string{"canary"}))
+	}
+
+	require.NoError(t, store.DeleteAlertRulesByUID(ctx, rule.OrgID, rule.UID))
+	tags, err := store.GetResourceTags(ctx, rule, rule.OrgID)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+	tags, err = store.GetResourceTags(ctx, rule.GetGroupKey(), rule.OrgID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"canary"}, tags, "the group still has a rule")
+
+	require.NoError(t, store.DeleteAlertRulesByUID(ctx, other.OrgID, other.UID))
+	tags, err = store.GetResourceTags(ctx, other.GetGroupKey(), other.OrgID)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+}
+
+func TestIntegrationAlertRulesRecord(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+	ctx := context.Background()
+	gen := models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval))
+	get := func(t *testing.T, rule *models.AlertRule) *models.AlertRule {
+		t.Helper()
+		result, err := store.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID})
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("alert rules are read back without a record", func(t *testing.T) {
+		rule := get(t, createRule(t, store, gen))
+		require.False(t, rule.IsRecordingRule())
+
+		updated := models.CopyRule(rule)
+		updated.Title = "updated"
+		require.NoError(t, store.UpdateAlertRules(ctx, []models.UpdateRule{{Existing: rule, New: *updated}}))
+		require.False(t, get(t, rule).IsRecordingRule())
+	})
+
+	t.Run("the record of recording rules is read back", func(t *testing.T) {
+		record := models.Record{Metric: "disk_usage_ratio", TargetDatasourceUID: "prometheus"}
+		rule := get(t, createRule(t, store, models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), func(r *models.AlertRule) {
+			record.From = r.Data[0].RefID
+			r.For = 0
+			r.NotificationSettings = nil
+			r.Record = record
+		})))
+		require.Equal(t, record, rule.Record)
+
+		updated := models.CopyRule(rule)
+		updated.Record.Metric = "disk_free_ratio"
+		require.NoError(t, store.UpdateAlertRules(ctx, []models.UpdateRule{{Existing: rule, New: *updated}}))
+		require.Equal(t, updated.Record, get(t, rule).Record)
+
+		rule = get(t, rule)
+		updated = models.CopyRule(rule)
+		updated.Record = models.Record{}
+		require.NoError(t, store.UpdateAlertRules(ctx, []models.UpdateRule{{Existing: rule, New: *updated}}))
+		require.False(t, get(t, rule).IsRecordingRule(), "the record should be removed")
+	})
+}
+
 func TestIntegration_GetNamespaceByUID(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
@@ -650,6 +744,161 @@ func TestIntegrationInsertAlertRules(t *testing.T) {
 	require.ErrorContains(t, err, deref[0].UID)
 	require.ErrorContains(t, err, deref[0].Title)
 	require.ErrorContains(t, err, deref[0].NamespaceUID)
+
+	t.Run("should reject new rules with the same title in a folder", func(t *testing.T) {
+		first := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))()
+		first.ID = 0
+		second := models.CopyRule(first)
+		second.UID = ""
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*first, *second})
+		require.ErrorIs(t, err, models.ErrAlertRuleUniqueConstraintViolation)
+
+		dbRules, err := store.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, dbRules, len(rules))
+	})
+}
+
+func TestIntegrationInsertAlertRulesLargeGroup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	// A single statement inserting the versions of that many rules would exceed the parameter limit of every database.
+	const count = 2000
+	groupKey := models.GenerateGroupKey(1)
+	rules := models.GenerateAlertRules(count, models.AlertRuleGen(
+		models.WithGroupKey(groupKey),
+		models.WithUniqueUID(&sync.Map{}),
+		models.WithUniqueTitle(&sync.Map{}),
+		models.WithSequentialGroupIndex(),
+		withIntervalMatching(store.Cfg.BaseInterval),
+	))
+	deref := make([]models.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		r := *rule
+		r.ID = 0
+		deref = append(deref, r)
+	}
+
+	ids, err := store.InsertAlertRules(context.Background(), deref)
+	require.NoError(t, err)
+	require.Len(t, ids, count)
+	for i, id := range ids {
+		require.Equal(t, deref[i].UID, id.UID)
+		require.NotZero(t, id.ID)
+	}
+
+	dbRules, err := store.ListAlertRules(context.Background(), &models.ListAlertRulesQuery{OrgID: 1})
+	require.NoError(t, err)
+	require.Len(t, dbRules, count)
+	byUID := make(map[string]int64, len(dbRules))
+	for _, r := range dbRules {
+		byUID[r.UID] = r.ID
+	}
+	for _, id := range ids {
+		require.Equal(t, byUID[id.UID], id.ID)
+	}
+
+	var versions int64
+	err = sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+		versions, err = sess.Table("alert_rule_version").Where("rule_org_id = ?", 1).Count()
+		return err
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, count, versions)
+}
+
+func TestIntegrationInsertAlertRulesDeterministicUIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	cfg.UnifiedAlerting.DeterministicRuleUIDs = true
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	rule := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))()
+	rule.ID = 0
+	rule.UID = ""
+	withUID := models.CopyRule(rule)
+	withUID.UID = "given"
+	withUID.Title = "other title"
+
+	ids, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule, *withUID})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	require.Equal(t, models.DeterministicAlertRuleUID(1, rule.NamespaceUID, rule.RuleGroup, rule.Title), ids[0].UID)
+	require.Equal(t, "given", ids[1].UID, "given UIDs should be kept")
+
+	t.Run("should fail if a rule with the same title exists in the group", func(t *testing.T) {
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+		require.ErrorIs(t, err, models.ErrAlertRuleConflictBase)
+	})
+
+	t.Run("should fail if inserted rules have the same title", func(t *testing.T) {
+		other := models.CopyRule(rule)
+		other.RuleGroup = "other group"
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*other, *other})
+		require.ErrorIs(t, err, models.ErrAlertRuleConflictBase)
+	})
+}
+
+func TestIntegrationCountQuotaExemptProvenances(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	cfg.UnifiedAlerting.QuotaExemptProvenances = []string{string(models.ProvenanceFile)}
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	gen := models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithOrgID(1))
+	rules := []models.AlertRule{*gen(), *gen(), *gen()}
+	_, err := store.InsertAlertRules(context.Background(), rules)
+	require.NoError(t, err)
+	require.NoError(t, store.SetProvenance(context.Background(), &rules[0], 1, models.ProvenanceFile))
+	require.NoError(t, store.SetProvenance(context.Background(), &rules[1], 1, models.ProvenanceAPI))
+
+	count, err := store.Count(context.Background(), 1)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = store.Count(context.Background(), 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
+
+	count, err = store.CountByProvenances(context.Background(), 1, models.ProvenanceFile)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, count)
+
+	count, err = store.CountByProvenances(context.Background(), 1, models.ProvenanceFile, models.ProvenanceAPI)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, count)
 }
 
 func TestIntegrationAlertRulesNotificationSettings(t *testing.T) {
@@ -814,6 +1063,323 @@ func TestIntegrationListNotificationSettings(t *testing.T) {
 	})
 }
 
+func TestIntegrationSearchAlertRules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	uids := &sync.Map{}
+	gen := func(mutators ...models.AlertRuleMutator) models.AlertRule {
+		mutators = append([]models.AlertRuleMutator{
+			models.WithOrgID(1),
+			models.WithUniqueUID(uids),
+			models.WithLabels(map[string]string{"env": "prod"}),
+			models.WithAnnotations(map[string]string{"summary": "something"}),
+			withIntervalMatching(store.Cfg.BaseInterval),
+		}, mutators...)
+		r := models.AlertRuleGen(mutators...)()
+		r.ID = 0
+		return *r
+	}
+
+	byTitle := gen(models.WithTitle("Disk NEEDLE usage"))
+	byLabel := gen(models.WithTitle("by label"), models.WithLabels(map[string]string{"team": "needle-team"}))
+	byAnnotation := gen(models.WithTitle("by annotation"), models.WithAnnotations(map[string]string{"summary": `100% <needle> "quoted"`}))
+	inOtherOrg := gen(models.WithTitle("needle"), models.WithOrgID(2))
+	other := gen(models.WithTitle("100 other"))
+	nonASCII := gen(models.WithTitle("Échec de sauvegarde"), models.WithAnnotations(map[string]string{"summary": "Ошибка диска"}))
+
+	_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{byTitle, byLabel, byAnnotation, inOtherOrg, other, nonASCII})
+	require.NoError(t, err)
+
+	search := func(t *testing.T, q models.SearchAlertRulesQuery) (int64, []string) {
+		t.Helper()
+		q.OrgID = 1
+		result, err := store.SearchAlertRules(context.Background(), &q)
+		require.NoError(t, err)
+		uids := make([]string, 0, len(result.Rules))
+		for _, r := range result.Rules {
+			uids = append(uids, r.UID)
+		}
+		return result.TotalCount, uids
+	}
+
+	t.Run("should match title, labels and annotations ignoring case", func(t *testing.T) {
+		total, actual := search(t, models.SearchAlertRulesQuery{Query: "Needle"})
+		require.EqualValues(t, 3, total)
+		require.ElementsMatch(t, []string{byTitle.UID, byLabel.UID, byAnnotation.UID}, actual)
+	})
+
+	t.Run("should match label keys", func(t *testing.T) {
+		total, actual := search(t, models.SearchAlertRulesQuery{Query: "team"})
+		require.EqualValues(t, 1, total)
+		require.Equal(t, []string{byLabel.UID}, actual)
+	})
+
+	t.Run("should treat wildcards and JSON special characters literally", func(t *testing.T) {
+		_, actual := search(t, models.SearchAlertRulesQuery{Query: "100%"})
+		require.Equal(t, []string{byAnnotation.UID}, actual)
+		_, actual = search(t, models.SearchAlertRulesQuery{Query: "<needle>"})
+		require.Equal(t, []string{byAnnotation.UID}, actual)
+		_, actual = search(t, models.SearchAlertRulesQuery{Query: `"quoted"`})
+		require.Equal(t, []string{byAnnotation.UID}, actual)
+		_, actual = search(t, models.SearchAlertRulesQuery{Query: "ne_dle"})
+		require.Empty(t, actual)
+	})
+
+	t.Run("should match non-ASCII text ignoring case", func(t *testing.T) {
+		total, actual := search(t, models.SearchAlertRulesQuery{Query: "échec"})
+		require.EqualValues(t, 1, total)
+		require.Equal(t, []string{nonASCII.UID}, actual)
+		_, actual = search(t, models.SearchAlertRulesQuery{Query: "ОШИБКА"})
+		require.Equal(t, []string{nonASCII.UID}, actual)
+		total, actual = search(t, models.SearchAlertRulesQuery{Query: "échec", Limit: 1, Page: 2})
+		require.EqualValues(t, 1, total)
+		require.Empty(t, actual)
+	})
+
+	t.Run("should return all rules of the org if query is empty", func(t *testing.T) {
+		total, actual := search(t, models.SearchAlertRulesQuery{})
+		require.EqualValues(t, 5, total)
+		require.Len(t, actual, 5)
+	})
+
+	t.Run("should filter by folder", func(t *testing.T) {
+		total, actual := search(t, models.SearchAlertRulesQuery{Query: "needle", NamespaceUIDs: []string{byLabel.NamespaceUID}})
+		require.EqualValues(t, 1, total)
+		require.Equal(t, []string{byLabel.UID}, actual)
+	})
+
+	t.Run("should paginate results", func(t *testing.T) {
+		total, first := search(t, models.SearchAlertRulesQuery{Query: "needle", Limit: 2, Page: 1})
+		require.EqualValues(t, 3, total)
+		require.Len(t, first, 2)
+		total, second := search(t, models.SearchAlertRulesQuery{Query: "needle", Limit: 2, Page: 2})
+		require.EqualValues(t, 3, total)
+		require.Len(t, second, 1)
+		require.ElementsMatch(t, []string{byTitle.UID, byLabel.UID, byAnnotation.UID}, append(first, second...))
+		total, third := search(t, models.SearchAlertRulesQuery{Query: "needle", Limit: 2, Page: 3})
+		require.EqualValues(t, 3, total)
+		require.Empty(t, third)
+	})
+}
+
+func TestIntegrationListAlertRulesPagination(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	receiverName := "receiver"
+	uids := &sync.Map{}
+	gen := models.AlertRuleGen(
+		models.WithOrgID(1),
+		models.WithUniqueUID(uids),
+		withIntervalMatching(store.Cfg.BaseInterval),
+	)
+	rules := make([]models.AlertRule, 0, 7)
+	for i := 0; i < 7; i++ {
+		r := gen()
+		r.ID = 0
+		r.NotificationSettings = nil
+		if i%2 == 0 {
+			r.NotificationSettings = []models.NotificationSettings{models.NotificationSettingsGen(models.NSMuts.WithReceiver(receiverName))()}
+		}
+		rules = append(rules, *r)
+	}
+	_, err := store.InsertAlertRules(context.Background(), rules)
+	require.NoError(t, err)
+
+	list := func(t *testing.T, q models.ListAlertRulesQuery) []string {
+		t.Helper()
+		q.OrgID = 1
+		result, err := store.ListAlertRules(context.Background(), &q)
+		require.NoError(t, err)
+		uids := make([]string, 0, len(result))
+		for _, r := range result {
+			uids = append(uids, r.UID)
+		}
+		return uids
+	}
+
+	t.Run("should return consecutive pages of all rules", func(t *testing.T) {
+		all := list(t, models.ListAlertRulesQuery{})
+		require.Len(t, all, 7)
+		var paged []string
+		for page := int64(1); page <= 3; page++ {
+			uids := list(t, models.ListAlertRulesQuery{Limit: 3, Page: page})
+			require.LessOrEqual(t, len(uids), 3)
+			paged = append(paged, uids...)
+		}
+		require.Equal(t, all, paged)
+		require.Empty(t, list(t, models.ListAlertRulesQuery{Limit: 3, Page: 4}))
+	})
+
+	t.Run("should paginate rules filtered by receiver", func(t *testing.T) {
+		all := list(t, models.ListAlertRulesQuery{ReceiverName: receiverName})
+		require.Len(t, all, 4)
+		first := list(t, models.ListAlertRulesQuery{ReceiverName: receiverName, Limit: 3, Page: 1})
+		second := list(t, models.ListAlertRulesQuery{ReceiverName: receiverName, Limit: 3, Page: 2})
+		require.Len(t, first, 3)
+		require.Equal(t, all, append(first, second...))
+	})
+}
+
+func TestIntegrationGetAlertRulesGroupsByRuleUIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	// The first group has more rules than a batch of UIDs on SQLite, so that it is read by several batches.
+	groupSizes := []int{12, 2, 1}
+	groups := make([]models.AlertRuleGroupKey, 0, len(groupSizes))
+	uidsByGroup := make(map[models.AlertRuleGroupKey][]string, len(groupSizes))
+	var rules []models.AlertRule
+	for _, size := range groupSizes {
+		key := models.GenerateGroupKey(1)
+		groups = append(groups, key)
+		gen := models.AlertRuleGen(models.WithGroupKey(key), withIntervalMatching(store.Cfg.BaseInterval))
+		for i := 0; i < size; i++ {
+			r := gen()
+			r.ID = 0
+			r.RuleGroupIndex = i + 1
+			rules = append(rules, *r)
+			uidsByGroup[key] = append(uidsByGroup[key], r.UID)
+		}
+	}
+	_, err := store.InsertAlertRules(context.Background(), rules)
+	require.NoError(t, err)
+
+	uids := append(slices.Clone(uidsByGroup[groups[0]]), uidsByGroup[groups[1]][1], "unknown")
+	result, err := store.GetAlertRulesGroupsByRuleUIDs(context.Background(), &models.GetAlertRulesGroupsByRuleUIDsQuery{OrgID: 1, UIDs: uids})
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	for _, key := range groups[:2] {
+		got := make([]string, 0, len(result[key]))
+		for _, r := range result[key] {
+			got = append(got, r.UID)
+		}
+		require.Equal(t, uidsByGroup[key], got, "group %s should have all its rules once, in order", key)
+	}
+
+	result, err = store.GetAlertRulesGroupsByRuleUIDs(context.Background(), &models.GetAlertRulesGroupsByRuleUIDsQuery{OrgID: 2, UIDs: uids})
+	require.NoError(t, err)
+	require.Empty(t, result, "rules of other organizations should not be returned")
+}
+
+func TestIntegrationListAlertRulesFilters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	uids := &sync.Map{}
+	gen := func(group string, team string) models.AlertRule {
+		r := models.AlertRuleGen(
+			models.WithOrgID(1),
+			models.WithUniqueUID(uids),
+			models.WithLabels(map[string]string{"team": team}),
+			withIntervalMatching(store.Cfg.BaseInterval),
+		)()
+		r.ID = 0
+		r.RuleGroup = group
+		return *r
+	}
+	dbTeam := gen("db_1", "db")
+	dbTeamOtherGroup := gen("db_2", "db")
+	webTeam := gen("web", "web")
+	wildcardGroup := gen("dbx", "web")
+	expired, notExpired := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	dbTeam.ExpiresAt = &expired
+	webTeam.ExpiresAt = &notExpired
+	_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{dbTeam, dbTeamOtherGroup, webTeam, wildcardGroup})
+	require.NoError(t, err)
+	require.NoError(t, store.SetProvenance(context.Background(), &dbTeam, 1, models.ProvenanceFile))
+	require.NoError(t, store.SetProvenance(context.Background(), &webTeam, 1, models.ProvenanceAPI))
+	require.NoError(t, store.SetProvenance(context.Background(), &wildcardGroup, 1, models.ProvenanceNone))
+
+	list := func(t *testing.T, q models.ListAlertRulesQuery) []string {
+		t.Helper()
+		q.OrgID = 1
+		result, err := store.ListAlertRules(context.Background(), &q)
+		require.NoError(t, err)
+		uids := make([]string, 0, len(result))
+		for _, r := range result {
+			uids = append(uids, r.UID)
+		}
+		return uids
+	}
+
+	t.Run("should filter by prefix of the group", func(t *testing.T) {
+		require.ElementsMatch(t, []string{dbTeam.UID, dbTeamOtherGroup.UID}, list(t, models.ListAlertRulesQuery{RuleGroupPrefix: "db_"}))
+	})
+
+	t.Run("should filter by labels", func(t *testing.T) {
+		matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "db")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{dbTeam.UID, dbTeamOtherGroup.UID}, list(t, models.ListAlertRulesQuery{LabelMatchers: labels.Matchers{matcher}}))
+		require.Len(t, list(t, models.ListAlertRulesQuery{LabelMatchers: labels.Matchers{matcher}, Limit: 1, Page: 2}), 1)
+
+		notMatcher, err := labels.NewMatcher(labels.MatchNotEqual, "team", "db")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{webTeam.UID, wildcardGroup.UID}, list(t, models.ListAlertRulesQuery{LabelMatchers: labels.Matchers{notMatcher}}))
+		regexMatcher, err := labels.NewMatcher(labels.MatchRegexp, "team", "d.*")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{dbTeam.UID}, list(t, models.ListAlertRulesQuery{LabelMatchers: labels.Matchers{matcher, regexMatcher}, RuleGroup: "db_1"}))
+	})
+
+	t.Run("should filter by provenance", func(t *testing.T) {
+		require.ElementsMatch(t, []string{dbTeam.UID}, list(t, models.ListAlertRulesQuery{Provenances: []models.Provenance{models.ProvenanceFile}}))
+		require.ElementsMatch(t, []string{dbTeam.UID, webTeam.UID}, list(t, models.ListAlertRulesQuery{Provenances: []models.Provenance{models.ProvenanceFile, models.ProvenanceAPI}}))
+		require.ElementsMatch(t, []string{dbTeamOtherGroup.UID, wildcardGroup.UID}, list(t, models.ListAlertRulesQuery{Provenances: []models.Provenance{models.ProvenanceNone}}))
+	})
+
+	t.Run("should filter expired rules", func(t *testing.T) {
+		now := time.Now()
+		require.ElementsMatch(t, []string{dbTeam.UID}, list(t, models.ListAlertRulesQuery{ExpiredAt: &now}))
+		later := now.Add(2 * time.Hour)
+		require.ElementsMatch(t, []string{dbTeam.UID, webTeam.UID}, list(t, models.ListAlertRulesQuery{ExpiredAt: &later}))
+	})
+}
+
 // createAlertRule creates an alert rule in the database and returns it.
 // If a generator is not specified, uniqueness of primary key is not guaranteed.
 func createRule(t *testing.T, store *DBstore, generate func() *models.AlertRule) *models.AlertRule {
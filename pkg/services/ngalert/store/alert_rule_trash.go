@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// InsertDeletedAlertRules stores deleted alert rules in the trash.
+func (st DBstore) InsertDeletedAlertRules(ctx context.Context, rules ...*models.DeletedAlertRule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		for _, rule := range rules {
+			if _, err := sess.Table("alert_rule_trash").Insert(rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetDeletedAlertRules returns the deleted alert rules of the organization without their content, the latest deleted
+// first.
+func (st DBstore) GetDeletedAlertRules(ctx context.Context, orgID int64) ([]*models.DeletedAlertRule, error) {
+	rules := make([]*models.DeletedAlertRule, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_rule_trash").Omit("data").Where("org_id = ?", orgID).Desc("id").Find(&rules)
+	})
+	return rules, err
+}
+
+// GetDeletedAlertRule returns the latest deleted alert rule of the organization with the UID, with its content. It
+// returns models.ErrDeletedAlertRuleNotFound if there is none.
+func (st DBstore) GetDeletedAlertRule(ctx context.Context, orgID int64, ruleUID string) (*models.DeletedAlertRule, error) {
+	rule := &models.DeletedAlertRule{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		ok, err := sess.Table("alert_rule_trash").Where("org_id = ? AND rule_uid = ?", orgID, ruleUID).Desc("id").Get(rule)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrDeletedAlertRuleNotFound.Errorf("")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteDeletedAlertRules removes the alert rules of the organization with the UIDs from the trash.
+func (st DBstore) DeleteDeletedAlertRules(ctx context.Context, orgID int64, ruleUIDs ...string) error {
+	if len(ruleUIDs) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Table("alert_rule_trash").Where("org_id = ?", orgID).In("rule_uid", ruleUIDs).Delete(&models.DeletedAlertRule{})
+		return err
+	})
+}
+
+// DeleteDeletedAlertRulesBefore removes the alert rules of all the organizations that were deleted before the Unix
+// time in seconds from the trash, and returns their number.
+func (st DBstore) DeleteDeletedAlertRulesBefore(ctx context.Context, deletedBefore int64) (int64, error) {
+	var rows int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		rows, err = sess.Table("alert_rule_trash").Where("deleted_at < ?", deletedBefore).Delete(&models.DeletedAlertRule{})
+		return err
+	})
+	return rows, err
+}
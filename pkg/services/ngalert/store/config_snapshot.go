@@ -0,0 +1,56 @@
+package store
+
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// InsertConfigSnapshot stores a new snapshot of the alerting configuration of an organization and sets its ID.
+func (st DBstore) InsertConfigSnapshot(ctx context.Context, snapshot *models.ConfigSnapshot) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Table("alert_configuration_snapshot").Insert(snapshot)
+		return err
+	})
+}
+
+// GetConfigSnapshots returns the snapshots of the organization without their content, the latest first.
+func (st DBstore) GetConfigSnapshots(ctx context.Context, orgID int64) ([]*models.ConfigSnapshot, error) {
+	snapshots := make([]*models.ConfigSnapshot, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_configuration_snapshot").Omit("data").Where("org_id = ?", orgID).Desc("id").Find(&snapshots)
+	})
+	return snapshots, err
+}
+
+// GetConfigSnapshot returns the snapshot of the organization with its content. It returns
+// models.ErrConfigSnapshotNotFound if it does not exist.
+func (st DBstore) GetConfigSnapshot(ctx context.Context, orgID int64, id int64) (*models.ConfigSnapshot, error) {
+	snapshot := &models.ConfigSnapshot{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		ok, err := sess.Table("alert_configuration_snapshot").Where("org_id = ? AND id = ?", orgID, id).Get(snapshot)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return models.ErrConfigSnapshotNotFound.Errorf("")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// DeleteConfigSnapshots deletes the snapshots of the organization with the given IDs.
+func (st DBstore) DeleteConfigSnapshots(ctx context.Context, orgID int64, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Table("alert_configuration_snapshot").Where("org_id = ?", orgID).In("id", ids).Delete(&models.ConfigSnapshot{})
+		return err
+	})
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -15,6 +16,39 @@ type RuleDelta struct {
 	Existing *models.AlertRule
 	New      *models.AlertRule
 	Diff     cmputil.DiffReport
+	// ChangedFields are the paths of the fields of the rule that are changed, each once and sorted, so that consumers
+	// can tell what is changed without comparing the rules, e.g. Data[1].Model if only the threshold is changed.
+	ChangedFields []string
+}
+
+func newRuleDelta(existing, updated *models.AlertRule, diff cmputil.DiffReport) RuleDelta {
+	return RuleDelta{
+		Existing:      existing,
+		New:           updated,
+		Diff:          diff,
+		ChangedFields: changedFields(diff),
+	}
+}
+
+// CalculateRuleDelta returns the changes from the existing rule to the updated one.
+func CalculateRuleDelta(existing, updated *models.AlertRule) RuleDelta {
+	return newRuleDelta(existing, updated, existing.Diff(updated, AlertRuleFieldsToIgnoreInDiff[:]...))
+}
+
+// changedFields returns the paths of the diff, without duplicates, as collections of different lengths are reported
+// once per missing element.
+func changedFields(diff cmputil.DiffReport) []string {
+	if len(diff) == 0 {
+		return nil
+	}
+	paths := diff.Paths()
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// IsIntervalOnly returns true if the evaluation interval is the only field of the rule that is changed.
+func (d RuleDelta) IsIntervalOnly() bool {
+	return len(d.Diff) > 0 && len(d.Diff.GetDiffsForField("IntervalSeconds")) == len(d.Diff)
 }
 
 type GroupDelta struct {
@@ -28,7 +62,40 @@ type GroupDelta struct {
 }
 
 func (c *GroupDelta) IsEmpty() bool {
-	return len(c.Update)+len(c.New)+len(c.Delete) == 0
+	return c.Size() == 0
+}
+
+// Size returns the number of rules created, updated and deleted by the delta.
+func (c *GroupDelta) Size() int {
+	return len(c.Update) + len(c.New) + len(c.Delete)
+}
+
+// CheckSizeLimit returns an error if the delta contains more changes than the limit. A limit of 0 means no limit.
+func (c *GroupDelta) CheckSizeLimit(limit int64) error {
+	if limit > 0 && int64(c.Size()) > limit {
+		return models.ErrAlertRuleGroupTooManyChanges(c.Size(), limit)
+	}
+	return nil
+}
+
+// Split splits the delta into a delta with at most size changes and a delta with the other changes, which is nil if
+// there are none. The deleted rules come first, then the updated rules and the new rules, so that the rules are
+// deleted before the rules that may take their titles are written.
+func (c *GroupDelta) Split(size int) (*GroupDelta, *GroupDelta) {
+	if size <= 0 || c.Size() <= size {
+		return c, nil
+	}
+	head := &GroupDelta{GroupKey: c.GroupKey, AffectedGroups: c.AffectedGroups}
+	rest := &GroupDelta{GroupKey: c.GroupKey, AffectedGroups: c.AffectedGroups}
+	n := min(size, len(c.Delete))
+	head.Delete, rest.Delete = c.Delete[:n], c.Delete[n:]
+	size -= n
+	n = min(size, len(c.Update))
+	head.Update, rest.Update = c.Update[:n], c.Update[n:]
+	size -= n
+	n = min(size, len(c.New))
+	head.New, rest.New = c.New[:n], c.New[n:]
+	return head, rest
 }
 
 // NewOrUpdatedNotificationSettings returns a list of notification settings that are either new or updated in the group.
@@ -54,7 +121,7 @@ func (c *GroupDelta) NewOrUpdatedNotificationSettings() []models.NotificationSet
 
 type RuleReader interface {
 	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
-	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
+	GetAlertRulesGroupsByRuleUIDs(ctx context.Context, query *models.GetAlertRulesGroupsByRuleUIDsQuery) (map[models.AlertRuleGroupKey]models.RulesGroup, error)
 }
 
 // CalculateChanges calculates the difference between rules in the group in the database and the submitted rules. If a submitted rule has UID it tries to find it in the database (in other groups).
@@ -79,11 +146,34 @@ func CalculateChanges(ctx context.Context, ruleReader RuleReader, groupKey model
 		existingGroupRulesUIDs[r.UID] = r
 	}
 
+	// The rules that are moved from other groups or namespaces, and their groups, are queried at once.
+	var movedUIDs []string
+	for _, r := range submittedRules {
+		if r == nil || r.UID == "" {
+			continue
+		}
+		if _, ok := existingGroupRulesUIDs[r.UID]; !ok {
+			movedUIDs = append(movedUIDs, r.UID)
+		}
+	}
+	loadedRulesByUID := map[string]*models.AlertRule{}
+	loadedGroups := map[models.AlertRuleGroupKey]models.RulesGroup{}
+	if len(movedUIDs) > 0 {
+		loadedGroups, err = ruleReader.GetAlertRulesGroupsByRuleUIDs(ctx, &models.GetAlertRulesGroupsByRuleUIDsQuery{OrgID: groupKey.OrgID, UIDs: movedUIDs})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query database for groups of alert rules: %w", err)
+		}
+		for _, group := range loadedGroups {
+			for _, rule := range group {
+				loadedRulesByUID[rule.UID] = rule
+			}
+		}
+	}
+
 	//nolint:prealloc // difficult logic
 	var toAdd []*models.AlertRule
 	//nolint:prealloc // difficult logic
 	var toUpdate []RuleDelta
-	loadedRulesByUID := map[string]*models.AlertRule{} // auxiliary cache to avoid unnecessary queries if there are multiple moves from the same group
 	for _, r := range submittedRules {
 		if r == nil {
 			continue
@@ -94,27 +184,26 @@ func CalculateChanges(ctx context.Context, ruleReader RuleReader, groupKey model
 				existing = existingGroupRule
 				// remove the rule from existingGroupRulesUIDs
 				delete(existingGroupRulesUIDs, r.UID)
-			} else if existing, ok = loadedRulesByUID[r.UID]; !ok { // check the "cache" and if there is no hit, query the database
+			} else if existing, ok = loadedRulesByUID[r.UID]; ok {
 				// Rule can be from other group or namespace
-				q := &models.GetAlertRulesGroupByRuleUIDQuery{OrgID: groupKey.OrgID, UID: r.UID}
-				ruleList, err := ruleReader.GetAlertRulesGroupByRuleUID(ctx, q)
-				if err != nil {
-					return nil, fmt.Errorf("failed to query database for a group of alert rules: %w", err)
-				}
-				for _, rule := range ruleList {
-					if rule.UID == r.UID {
-						existing = rule
-					}
-					loadedRulesByUID[rule.UID] = rule
-				}
-				if existing == nil {
-					return nil, fmt.Errorf("failed to update rule with UID %s because %w", r.UID, models.ErrAlertRuleNotFound)
-				}
-				affectedGroups[existing.GetGroupKey()] = ruleList
+				affectedGroups[existing.GetGroupKey()] = loadedGroups[existing.GetGroupKey()]
+			} else {
+				return nil, fmt.Errorf("failed to update rule with UID %s because %w", r.UID, models.ErrAlertRuleNotFound)
 			}
 		}
 
 		if existing == nil {
+			// the data availability window is set on the group, so new rules get the window of the rules already in it.
+			if !r.HasDataAvailability && len(existingGroupRules) > 0 {
+				r.DataAvailabilityPeriod = existingGroupRules[0].DataAvailabilityPeriod
+				r.DataAvailabilityDelay = existingGroupRules[0].DataAvailabilityDelay
+			}
+			if !r.HasShardAffinity && len(existingGroupRules) > 0 {
+				r.ShardAffinity = existingGroupRules[0].ShardAffinity
+			}
+			if !r.HasIncidentHooks && len(existingGroupRules) > 0 {
+				r.IncidentHooks = existingGroupRules[0].IncidentHooks
+			}
 			toAdd = append(toAdd, &r.AlertRule)
 			continue
 		}
@@ -126,11 +215,7 @@ func CalculateChanges(ctx context.Context, ruleReader RuleReader, groupKey model
 			continue
 		}
 
-		toUpdate = append(toUpdate, RuleDelta{
-			Existing: existing,
-			New:      &r.AlertRule,
-			Diff:     diff,
-		})
+		toUpdate = append(toUpdate, newRuleDelta(existing, &r.AlertRule, diff))
 		continue
 	}
 
@@ -174,9 +259,9 @@ func UpdateCalculatedRuleFields(ch *GroupDelta) *GroupDelta {
 			}
 			if groupKey != ch.GroupKey {
 				if rule.RuleGroupIndex != idx {
-					upd.New = models.CopyRule(rule)
-					upd.New.RuleGroupIndex = idx
-					upd.Diff = rule.Diff(upd.New, AlertRuleFieldsToIgnoreInDiff[:]...)
+					changed := models.CopyRule(rule)
+					changed.RuleGroupIndex = idx
+					upd = newRuleDelta(rule, changed, rule.Diff(changed, AlertRuleFieldsToIgnoreInDiff[:]...))
 				}
 				idx++
 			}
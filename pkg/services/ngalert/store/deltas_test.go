@@ -102,6 +102,30 @@ func TestCalculateChanges(t *testing.T) {
 		require.Equal(t, models.RulesGroup(inDatabase), changes.AffectedGroups[groupKey])
 	})
 
+	t.Run("should list the changed fields of updated rules", func(t *testing.T) {
+		groupKey := models.GenerateGroupKey(orgId)
+		_, inDatabase := models.GenerateUniqueAlertRules(1, models.AlertRuleGen(withGroupKey(groupKey)))
+		existing := inDatabase[0]
+		// The generated rule can have no labels, whose changes are reported as a change of all the labels.
+		existing.Labels = map[string]string{"team": "a"}
+		submittedRule := models.CopyRule(existing)
+		submittedRule.For += time.Minute
+		submittedRule.Labels = map[string]string{"changed": "label"}
+		submittedRule.Data[0].Model = []byte(`{"changed": "model"}`)
+
+		fakeStore := fakes.NewRuleStore(t)
+		fakeStore.PutRule(context.Background(), inDatabase...)
+
+		changes, err := CalculateChanges(context.Background(), fakeStore, groupKey, []*models.AlertRuleWithOptionals{{AlertRule: *submittedRule}})
+		require.NoError(t, err)
+
+		require.Len(t, changes.Update, 1)
+		require.Contains(t, changes.Update[0].ChangedFields, "Data[0].Model")
+		require.Contains(t, changes.Update[0].ChangedFields, "For")
+		require.Contains(t, changes.Update[0].ChangedFields, "Labels[changed]")
+		require.IsIncreasing(t, changes.Update[0].ChangedFields, "changed fields should be sorted without duplicates")
+	})
+
 	t.Run("should include only if there are changes ignoring specific fields", func(t *testing.T) {
 		groupKey := models.GenerateGroupKey(orgId)
 		_, inDatabase := models.GenerateUniqueAlertRules(rand.Intn(5)+1, models.AlertRuleGen(withGroupKey(groupKey)))
@@ -232,6 +256,14 @@ func TestCalculateChanges(t *testing.T) {
 		require.NotContains(t, changes.AffectedGroups, groupKey) // because there is no such group in database yet
 
 		require.Len(t, changes.AffectedGroups[sourceGroupKey], len(inDatabase))
+
+		// the groups of the moved rules are queried at once
+		queries := fakeStore.GetRecordedCommands(func(cmd any) (any, bool) {
+			q, ok := cmd.(models.GetAlertRulesGroupsByRuleUIDsQuery)
+			return q, ok
+		})
+		require.Len(t, queries, 1)
+		require.Len(t, queries[0].(models.GetAlertRulesGroupsByRuleUIDsQuery).UIDs, len(submitted))
 	})
 
 	t.Run("should fail when submitted rule has UID that does not exist in db", func(t *testing.T) {
@@ -267,7 +299,7 @@ func TestCalculateChanges(t *testing.T) {
 		expectedErr := errors.New("TEST ERROR")
 		fakeStore.Hook = func(cmd any) error {
 			switch cmd.(type) {
-			case models.GetAlertRulesGroupByRuleUIDQuery:
+			case models.GetAlertRulesGroupsByRuleUIDsQuery:
 				return expectedErr
 			}
 			return nil
@@ -281,6 +313,48 @@ func TestCalculateChanges(t *testing.T) {
 	})
 }
 
+func TestGroupDeltaCheckSizeLimit(t *testing.T) {
+	gen := models.AlertRuleGen()
+	delta := &GroupDelta{
+		New:    []*models.AlertRule{gen(), gen()},
+		Update: []RuleDelta{{Existing: gen(), New: gen()}},
+		Delete: []*models.AlertRule{gen()},
+	}
+	require.Equal(t, 4, delta.Size())
+
+	require.NoError(t, delta.CheckSizeLimit(0))
+	require.NoError(t, delta.CheckSizeLimit(4))
+	require.ErrorIs(t, delta.CheckSizeLimit(3), models.ErrAlertRuleGroupTooManyChangesBase)
+}
+
+func TestGroupDeltaSplit(t *testing.T) {
+	gen := models.AlertRuleGen()
+	delta := &GroupDelta{
+		New:    []*models.AlertRule{gen(), gen()},
+		Update: []RuleDelta{{Existing: gen(), New: gen()}},
+		Delete: []*models.AlertRule{gen(), gen()},
+	}
+
+	for _, size := range []int{0, 5} {
+		head, rest := delta.Split(size)
+		require.Same(t, delta, head)
+		require.Nil(t, rest)
+	}
+
+	head, rest := delta.Split(1)
+	require.Equal(t, delta.Delete[:1], head.Delete)
+	require.Empty(t, head.Update)
+	require.Empty(t, head.New)
+	require.Equal(t, 4, rest.Size())
+
+	head, rest = rest.Split(3)
+	require.Equal(t, delta.Delete[1:], head.Delete)
+	require.Equal(t, delta.Update, head.Update)
+	require.Equal(t, delta.New[:1], head.New)
+	require.Equal(t, delta.New[1:], rest.New)
+	require.Equal(t, 1, rest.Size())
+}
+
 func TestCalculateAutomaticChanges(t *testing.T) {
 	orgID := rand.Int63()
 
@@ -409,6 +483,7 @@ func TestCalculateAutomaticChanges(t *testing.T) {
 				require.Lenf(t, diff, 1, fmt.Sprintf("the rule in affected group should be re-indexed to %d but it still has index %d. Moved rule with index %d", expectedIdx, upd.Existing.RuleGroupIndex, movedIndex))
 				require.Equal(t, "RuleGroupIndex", diff[0].Path)
 				require.Equal(t, expectedIdx, upd.New.RuleGroupIndex)
+				require.Equal(t, []string{"RuleGroupIndex"}, upd.ChangedFields)
 			} else {
 				require.Empty(t, diff)
 			}
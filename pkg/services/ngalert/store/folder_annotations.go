@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+)
+
+type folderAnnotationRecord struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"'org_id'"`
+	FolderUID string `xorm:"'folder_uid'"`
+	Key       string `xorm:"'annotation_key'"`
+	Value     string `xorm:"'annotation_value'"`
+}
+
+func (r folderAnnotationRecord) TableName() string {
+	return "alert_folder_annotation"
+}
+
+// GetFolderAnnotations gets the default annotations of the alert rules of the folder, nil if it has none.
+func (st DBstore) GetFolderAnnotations(ctx context.Context, orgID int64, folderUID string) (map[string]string, error) {
+	var annotations map[string]string
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var records []folderAnnotationRecord
+		if err := sess.Where("org_id = ? AND folder_uid = ?", orgID, folderUID).Find(&records); err != nil {
+			return fmt.Errorf("failed to query for folder annotations: %w", err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		annotations = make(map[string]string, len(records))
+		for _, r := range records {
+			annotations[r.Key] = r.Value
+		}
+		return nil
+	})
+	return annotations, err
+}
+
+// SetFolderAnnotations replaces the default annotations of the alert rules of the folder. The folder has no default
+// annotation anymore if annotations is empty.
+func (st DBstore) SetFolderAnnotations(ctx context.Context, orgID int64, folderUID string, annotations map[string]string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND folder_uid = ?", orgID, folderUID).Delete(folderAnnotationRecord{}); err != nil {
+			return fmt.Errorf("failed to delete pre-existing folder annotations: %w", err)
+		}
+		if len(annotations) == 0 {
+			return nil
+		}
+		records := make([]folderAnnotationRecord, 0, len(annotations))
+		for key, value := range annotations {
+			records = append(records, folderAnnotationRecord{
+				OrgID:     orgID,
+				FolderUID: folderUID,
+				Key:       key,
+				Value:     value,
+			})
+		}
+		if _, err := sess.Insert(records); err != nil {
+			return fmt.Errorf("failed to store folder annotations: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationFolderAnnotations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	_, dbstore := tests.SetupTestEnv(t, testAlertingIntervalSeconds)
+	ctx := context.Background()
+	orgID := int64(1)
+
+	t.Run("folder without annotations has none", func(t *testing.T) {
+		annotations, err := dbstore.GetFolderAnnotations(ctx, orgID, "folder")
+		require.NoError(t, err)
+		require.Nil(t, annotations)
+	})
+
+	t.Run("annotations are replaced", func(t *testing.T) {
+		require.NoError(t, dbstore.SetFolderAnnotations(ctx, orgID, "folder", map[string]string{"oncall_team": "sre", "runbook_base_url": "https://runbooks"}))
+		require.NoError(t, dbstore.SetFolderAnnotations(ctx, orgID, "folder", map[string]string{"oncall_team": "db"}))
+		require.NoError(t, dbstore.SetFolderAnnotations(ctx, orgID, "other", map[string]string{"oncall_team": "web"}))
+
+		annotations, err := dbstore.GetFolderAnnotations(ctx, orgID, "folder")
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"oncall_team": "db"}, annotations)
+
+		annotations, err = dbstore.GetFolderAnnotations(ctx, orgID+1, "folder")
+		require.NoError(t, err)
+		require.Nil(t, annotations, "annotations of other organizations should not be returned")
+	})
+
+	t.Run("annotations are removed", func(t *testing.T) {
+		require.NoError(t, dbstore.SetFolderAnnotations(ctx, orgID, "folder", nil))
+
+		annotations, err := dbstore.GetFolderAnnotations(ctx, orgID, "folder")
+		require.NoError(t, err)
+		require.Nil(t, annotations)
+	})
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// folderEvaluationPauseRecord is a folder whose alert rules are not evaluated, whatever their own pause status.
+type folderEvaluationPauseRecord struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	OrgID     int64  `xorm:"'org_id'"`
+	FolderUID string `xorm:"'folder_uid'"`
+}
+
+func (r folderEvaluationPauseRecord) TableName() string {
+	return "alert_folder_evaluation_pause"
+}
+
+// GetFolderEvaluationPaused returns whether the evaluation of the alert rules of the folder is paused.
+func (st DBstore) GetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string) (bool, error) {
+	var paused bool
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		paused, err = sess.Where("org_id = ? AND folder_uid = ?", orgID, folderUID).Exist(&folderEvaluationPauseRecord{})
+		if err != nil {
+			return fmt.Errorf("failed to query for folder evaluation pause: %w", err)
+		}
+		return nil
+	})
+	return paused, err
+}
+
+// SetFolderEvaluationPaused pauses or resumes the evaluation of the alert rules of the folder. The pause status of the
+// rules is not modified.
+func (st DBstore) SetFolderEvaluationPaused(ctx context.Context, orgID int64, folderUID string, paused bool) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Where("org_id = ? AND folder_uid = ?", orgID, folderUID).Delete(folderEvaluationPauseRecord{}); err != nil {
+			return fmt.Errorf("failed to delete pre-existing folder evaluation pause: %w", err)
+		}
+		if !paused {
+			return nil
+		}
+		if _, err := sess.Insert(folderEvaluationPauseRecord{OrgID: orgID, FolderUID: folderUID}); err != nil {
+			return fmt.Errorf("failed to store folder evaluation pause: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetPausedFoldersForScheduling returns the folders whose alert rules are not evaluated, except those that belong to
+// an excluded list of organizations.
+func (st DBstore) GetPausedFoldersForScheduling(ctx context.Context) ([]ngmodels.FolderKey, error) {
+	var result []ngmodels.FolderKey
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var disabledOrgs []int64
+		for orgID := range st.Cfg.DisabledOrgs {
+			disabledOrgs = append(disabledOrgs, orgID)
+		}
+		q := sess.Table("alert_folder_evaluation_pause")
+		if len(disabledOrgs) > 0 {
+			q = q.NotIn("org_id", disabledOrgs)
+		}
+		var records []folderEvaluationPauseRecord
+		if err := q.Find(&records); err != nil {
+			return fmt.Errorf("failed to query for paused folders: %w", err)
+		}
+		result = make([]ngmodels.FolderKey, 0, len(records))
+		for _, r := range records {
+			result = append(result, ngmodels.FolderKey{OrgID: r.OrgID, UID: r.FolderUID})
+		}
+		return nil
+	})
+	return result, err
+}
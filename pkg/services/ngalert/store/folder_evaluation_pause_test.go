@@ -0,0 +1,67 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationFolderEvaluationPause(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	_, dbstore := tests.SetupTestEnv(t, testAlertingIntervalSeconds)
+	ctx := context.Background()
+	orgID := int64(1)
+
+	t.Run("folders are not paused by default", func(t *testing.T) {
+		paused, err := dbstore.GetFolderEvaluationPaused(ctx, orgID, "folder")
+		require.NoError(t, err)
+		require.False(t, paused)
+
+		folders, err := dbstore.GetPausedFoldersForScheduling(ctx)
+		require.NoError(t, err)
+		require.Empty(t, folders)
+	})
+
+	t.Run("folders are paused", func(t *testing.T) {
+		require.NoError(t, dbstore.SetFolderEvaluationPaused(ctx, orgID, "folder", true))
+		require.NoError(t, dbstore.SetFolderEvaluationPaused(ctx, orgID, "folder", true), "pausing twice should succeed")
+		require.NoError(t, dbstore.SetFolderEvaluationPaused(ctx, orgID+1, "other", true))
+
+		paused, err := dbstore.GetFolderEvaluationPaused(ctx, orgID, "folder")
+		require.NoError(t, err)
+		require.True(t, paused)
+
+		paused, err = dbstore.GetFolderEvaluationPaused(ctx, orgID+1, "folder")
+		require.NoError(t, err)
+		require.False(t, paused, "folders of other organizations should not be paused")
+
+		folders, err := dbstore.GetPausedFoldersForScheduling(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []models.FolderKey{{OrgID: orgID, UID: "folder"}, {OrgID: orgID + 1, UID: "other"}}, folders)
+	})
+
+	t.Run("folders of disabled organizations are not scheduled", func(t *testing.T) {
+		dbstore.Cfg.DisabledOrgs = map[int64]struct{}{orgID + 1: {}}
+		t.Cleanup(func() {
+			dbstore.Cfg.DisabledOrgs = nil
+		})
+
+		folders, err := dbstore.GetPausedFoldersForScheduling(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []models.FolderKey{{OrgID: orgID, UID: "folder"}}, folders)
+	})
+
+	t.Run("folders are resumed", func(t *testing.T) {
+		require.NoError(t, dbstore.SetFolderEvaluationPaused(ctx, orgID, "folder", false))
+
+		paused, err := dbstore.GetFolderEvaluationPaused(ctx, orgID, "folder")
+		require.NoError(t, err)
+		require.False(t, paused)
+	})
+}
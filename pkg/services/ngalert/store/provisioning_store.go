@@ -2,10 +2,12 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
 )
 
 type provenanceRecord struct {
@@ -14,6 +16,7 @@ type provenanceRecord struct {
 	RecordKey  string
 	RecordType string
 	Provenance models.Provenance
+	Metadata   string
 }
 
 func (pr provenanceRecord) TableName() string {
@@ -61,11 +64,26 @@ func (st DBstore) GetProvenances(ctx context.Context, org int64, resourceType st
 	return resultMap, err
 }
 
-// SetProvenance changes the provenance status for a provisionable object.
+// SetProvenance changes the provenance status for a provisionable object. Any metadata of the previous record is removed.
 func (st DBstore) SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error {
+	return st.SetProvenanceWithMetadata(ctx, o, org, p, nil)
+}
+
+// SetProvenanceWithMetadata changes the provenance status for a provisionable object and stores the given metadata
+// alongside. The metadata can be nil.
+func (st DBstore) SetProvenanceWithMetadata(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata) error {
 	recordType := o.ResourceType()
 	recordKey := o.ResourceID()
 
+	var rawMetadata string
+	if metadata != nil {
+		b, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal provenance metadata: %w", err)
+		}
+		rawMetadata = string(b)
+	}
+
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		// TODO: Add a unit-of-work pattern, so updating objects + provenance will happen consistently with rollbacks across stores.
 		// TODO: Need to make sure that writing a record where our concurrency key fails will also fail the whole transaction. That way, this gets rolled back too. can't just check that 0 updates happened inmemory. Check with jp. If not possible, we need our own concurrency key.
@@ -82,6 +100,7 @@ func (st DBstore) SetProvenance(ctx context.Context, o models.Provisionable, org
 			RecordType: recordType,
 			Provenance: p,
 			OrgID:      org,
+			Metadata:   rawMetadata,
 		}
 
 		if _, err := sess.Insert(record); err != nil {
@@ -92,6 +111,109 @@ func (st DBstore) SetProvenance(ctx context.Context, o models.Provisionable, org
 	})
 }
 
+// SetProvenances changes the provenance status of many provisionable objects at once. Pre-existing records, and
+// their metadata, are replaced. Records are deleted and inserted with one statement per batch rather than per object.
+// Objects that are given more than once get a single record.
+func (st DBstore) SetProvenances(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	type recordID struct {
+		recordType string
+		recordKey  string
+	}
+	seen := make(map[recordID]struct{}, len(objects))
+	keysByType := make(map[string][]string)
+	records := make([]provenanceRecord, 0, len(objects))
+	for _, o := range objects {
+		id := recordID{recordType: o.ResourceType(), recordKey: o.ResourceID()}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		keysByType[o.ResourceType()] = append(keysByType[o.ResourceType()], o.ResourceID())
+		records = append(records, provenanceRecord{
+			RecordKey:  o.ResourceID(),
+			RecordType: o.ResourceType(),
+			Provenance: p,
+			OrgID:      org,
+		})
+	}
+
+	opts := sqlstore.NativeSettingsForDialect(st.SQLStore.GetDialect())
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for recordType, keys := range keysByType {
+			err := sqlstore.InBatches(keys, opts, func(batch any) error {
+				_, err := sess.Table(provenanceRecord{}).Where("record_type = ? AND org_id = ?", recordType, org).In("record_key", batch).Delete(provenanceRecord{})
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete pre-existing provisioning status: %w", err)
+			}
+		}
+
+		if _, err := sess.BulkInsert(provenanceRecord{}, records, opts); err != nil {
+			return fmt.Errorf("failed to store provisioning status: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetProvenanceMetadata gets the provenance metadata of a provisionable object. It returns nil if the object has no
+// provenance or its provenance was stored without metadata.
+func (st DBstore) GetProvenanceMetadata(ctx context.Context, o models.Provisionable, org int64) (*models.ProvenanceMetadata, error) {
+	var result *models.ProvenanceMetadata
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		filter := "record_key = ? AND record_type = ? AND org_id = ?"
+		rawData, err := sess.Table(provenanceRecord{}).Where(filter, o.ResourceID(), o.ResourceType(), org).Desc("id").Limit(1).Cols("metadata").QueryString()
+		if err != nil {
+			return fmt.Errorf("failed to query for existing provenance metadata: %w", err)
+		}
+		if len(rawData) == 0 {
+			return nil
+		}
+		result, err = parseProvenanceMetadata(rawData[0]["metadata"])
+		return err
+	})
+	return result, err
+}
+
+// GetProvenancesMetadata gets the provenance metadata of all objects of the given type that have some, keyed by
+// resource ID.
+func (st DBstore) GetProvenancesMetadata(ctx context.Context, org int64, resourceType string) (map[string]models.ProvenanceMetadata, error) {
+	resultMap := make(map[string]models.ProvenanceMetadata)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		filter := "record_type = ? AND org_id = ?"
+		rawData, err := sess.Table(provenanceRecord{}).Where(filter, resourceType, org).Desc("id").Cols("record_key", "metadata").QueryString()
+		if err != nil {
+			return fmt.Errorf("failed to query for existing provenance metadata: %w", err)
+		}
+		for _, data := range rawData {
+			metadata, err := parseProvenanceMetadata(data["metadata"])
+			if err != nil {
+				return err
+			}
+			if metadata != nil {
+				resultMap[data["record_key"]] = *metadata
+			}
+		}
+		return nil
+	})
+	return resultMap, err
+}
+
+func parseProvenanceMetadata(raw string) (*models.ProvenanceMetadata, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	var metadata models.ProvenanceMetadata
+	if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to parse provenance metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
 // DeleteProvenance deletes the provenance record from the table
 func (st DBstore) DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error {
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
@@ -2,15 +2,19 @@ package store_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/ngalert"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 )
 
 const testAlertingIntervalSeconds = 10
@@ -19,7 +23,8 @@ func TestIntegrationProvisioningStore(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping integration test")
 	}
-	store := createProvisioningStoreSut(tests.SetupTestEnv(t, testAlertingIntervalSeconds))
+	_, dbstore := tests.SetupTestEnv(t, testAlertingIntervalSeconds)
+	store := createProvisioningStoreSut(nil, dbstore)
 
 	t.Run("Default provenance of a known type is None", func(t *testing.T) {
 		rule := models.AlertRule{
@@ -127,6 +132,190 @@ func TestIntegrationProvisioningStore(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, models.ProvenanceNone, p)
 	})
+
+	t.Run("Store sets provenance of many records at once", func(t *testing.T) {
+		const orgID = 2345
+		existing := models.AlertRule{UID: "existing", OrgID: orgID}
+		err := store.SetProvenance(context.Background(), &existing, orgID, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		objects := []models.Provisionable{&existing}
+		for i := 0; i < 25; i++ {
+			objects = append(objects, &models.AlertRule{UID: fmt.Sprintf("bulk-%d", i), OrgID: orgID})
+		}
+		objects = append(objects, &definitions.EmbeddedContactPoint{UID: "bulk-contact-point"})
+
+		err = store.SetProvenances(context.Background(), orgID, objects, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		p, err := store.GetProvenances(context.Background(), orgID, existing.ResourceType())
+		require.NoError(t, err)
+		require.Len(t, p, 26)
+		for _, prov := range p {
+			require.Equal(t, models.ProvenanceAPI, prov)
+		}
+		cp, err := store.GetProvenance(context.Background(), objects[len(objects)-1], orgID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, cp)
+	})
+
+	t.Run("Store sets a single record for objects given more than once", func(t *testing.T) {
+		const orgID = 3456
+		rule := models.AlertRule{UID: "duplicate", OrgID: orgID}
+		err := store.SetProvenances(context.Background(), orgID, []models.Provisionable{&rule, &rule}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		var count int64
+		err = dbstore.SQLStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			count, err = sess.Table("provenance_type").Where("org_id = ? AND record_key = ?", orgID, rule.UID).Count()
+			return err
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, count)
+	})
+
+	t.Run("Store returns saved provenance metadata", func(t *testing.T) {
+		testProvenanceMetadata(t, store)
+	})
+}
+
+// provenanceMetadataStore is the part of the provisioning store that is shared by the database store and its fakes.
+type provenanceMetadataStore interface {
+	GetProvenance(ctx context.Context, o models.Provisionable, org int64) (models.Provenance, error)
+	SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error
+	SetProvenances(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance) error
+	SetProvenanceWithMetadata(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata) error
+	GetProvenanceMetadata(ctx context.Context, o models.Provisionable, org int64) (*models.ProvenanceMetadata, error)
+	GetProvenancesMetadata(ctx context.Context, org int64, resourceType string) (map[string]models.ProvenanceMetadata, error)
+}
+
+// TestFakeProvisioningStoreMetadata checks that the fake stores handle metadata like the database store.
+func TestFakeProvisioningStoreMetadata(t *testing.T) {
+	t.Run("FakeProvisioningStore", func(t *testing.T) {
+		testProvenanceMetadata(t, fakes.NewFakeProvisioningStore())
+	})
+	t.Run("FakeStore", func(t *testing.T) {
+		testProvenanceMetadata(t, provisioning.NewFakeStore())
+	})
+}
+
+func testProvenanceMetadata(t *testing.T, store provenanceMetadataStore) {
+	const orgID = 12345
+	withMetadata := models.AlertRule{
+		UID:   "with-metadata",
+		OrgID: orgID,
+	}
+	withoutMetadata := models.AlertRule{
+		UID:   "without-metadata",
+		OrgID: orgID,
+	}
+	metadata := models.ProvenanceMetadata{
+		Source:     "workspace-a",
+		ExternalID: "grafana_rule_group.test",
+		Hash:       "abcdef",
+	}
+	err := store.SetProvenanceWithMetadata(context.Background(), &withMetadata, orgID, models.ProvenanceAPI, &metadata)
+	require.NoError(t, err)
+	err = store.SetProvenance(context.Background(), &withoutMetadata, orgID, models.ProvenanceAPI)
+	require.NoError(t, err)
+
+	p, err := store.GetProvenance(context.Background(), &withMetadata, orgID)
+	require.NoError(t, err)
+	require.Equal(t, models.ProvenanceAPI, p)
+
+	m, err := store.GetProvenanceMetadata(context.Background(), &withMetadata, orgID)
+	require.NoError(t, err)
+	require.Equal(t, &metadata, m)
+
+	m, err = store.GetProvenanceMetadata(context.Background(), &withoutMetadata, orgID)
+	require.NoError(t, err)
+	require.Nil(t, m)
+
+	all, err := store.GetProvenancesMetadata(context.Background(), orgID, withMetadata.ResourceType())
+	require.NoError(t, err)
+	require.Equal(t, map[string]models.ProvenanceMetadata{withMetadata.UID: metadata}, all)
+
+	t.Run("metadata is removed when provenance is set without it", func(t *testing.T) {
+		err := store.SetProvenance(context.Background(), &withMetadata, orgID, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		m, err := store.GetProvenanceMetadata(context.Background(), &withMetadata, orgID)
+		require.NoError(t, err)
+		require.Nil(t, m)
+	})
+
+	t.Run("metadata is removed when provenance is set in bulk", func(t *testing.T) {
+		err := store.SetProvenanceWithMetadata(context.Background(), &withMetadata, orgID, models.ProvenanceAPI, &metadata)
+		require.NoError(t, err)
+		err = store.SetProvenances(context.Background(), orgID, []models.Provisionable{&withMetadata}, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		m, err := store.GetProvenanceMetadata(context.Background(), &withMetadata, orgID)
+		require.NoError(t, err)
+		require.Nil(t, m)
+		all, err := store.GetProvenancesMetadata(context.Background(), orgID, withMetadata.ResourceType())
+		require.NoError(t, err)
+		require.Empty(t, all)
+	})
+
+	t.Run("metadata is replaced when provenance is set with other metadata", func(t *testing.T) {
+		other := models.ProvenanceMetadata{Source: "workspace-b"}
+		err := store.SetProvenanceWithMetadata(context.Background(), &withMetadata, orgID, models.ProvenanceAPI, &metadata)
+		require.NoError(t, err)
+		err = store.SetProvenanceWithMetadata(context.Background(), &withMetadata, orgID, models.ProvenanceAPI, &other)
+		require.NoError(t, err)
+
+		m, err := store.GetProvenanceMetadata(context.Background(), &withMetadata, orgID)
+		require.NoError(t, err)
+		require.Equal(t, &other, m)
+	})
+}
+
+func TestIntegrationProvisioningTags(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	_, dbstore := tests.SetupTestEnv(t, testAlertingIntervalSeconds)
+	orgID := int64(1)
+	rule := models.AlertRule{UID: "tagged"}
+	group := models.AlertRuleGroupKey{NamespaceUID: "folder", RuleGroup: "tagged"}
+
+	t.Run("object without tags has no tags", func(t *testing.T) {
+		tags, err := dbstore.GetResourceTags(context.Background(), &rule, orgID)
+		require.NoError(t, err)
+		require.Empty(t, tags)
+	})
+
+	t.Run("tags are replaced", func(t *testing.T) {
+		require.NoError(t, dbstore.SetResourceTags(context.Background(), &rule, orgID, []string{"canary", "team-a"}))
+		require.NoError(t, dbstore.SetResourceTags(context.Background(), &rule, orgID, []string{"canary", "team-b"}))
+		require.NoError(t, dbstore.SetResourceTags(context.Background(), group, orgID, []string{"canary"}))
+
+		tags, err := dbstore.GetResourceTags(context.Background(), &rule, orgID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"canary", "team-b"}, tags)
+
+		tagged, err := dbstore.GetTaggedResources(context.Background(), orgID, rule.ResourceType(), "canary")
+		require.NoError(t, err)
+		require.Equal(t, []string{rule.UID}, tagged)
+		tagged, err = dbstore.GetTaggedResources(context.Background(), orgID, group.ResourceType(), "canary")
+		require.NoError(t, err)
+		require.Equal(t, []string{group.ResourceID()}, tagged)
+	})
+
+	t.Run("tags of other organizations are not returned", func(t *testing.T) {
+		tagged, err := dbstore.GetTaggedResources(context.Background(), orgID+1, rule.ResourceType(), "canary")
+		require.NoError(t, err)
+		require.Empty(t, tagged)
+	})
+
+	t.Run("tags are removed", func(t *testing.T) {
+		require.NoError(t, dbstore.SetResourceTags(context.Background(), &rule, orgID, nil))
+
+		tags, err := dbstore.GetResourceTags(context.Background(), &rule, orgID)
+		require.NoError(t, err)
+		require.Empty(t, tags)
+	})
 }
 
 func createProvisioningStoreSut(_ *ngalert.AlertNG, db *store.DBstore) provisioning.ProvisioningStore {
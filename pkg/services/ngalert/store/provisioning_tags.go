@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type provisioningTagRecord struct {
+	ID         int64  `xorm:"pk autoincr 'id'"`
+	OrgID      int64  `xorm:"'org_id'"`
+	RecordType string `xorm:"'record_type'"`
+	RecordKey  string `xorm:"'record_key'"`
+	Tag        string `xorm:"'tag'"`
+}
+
+func (r provisioningTagRecord) TableName() string {
+	return "provisioning_tag"
+}
+
+// GetResourceTags gets the tags of a provisionable object, sorted.
+func (st DBstore) GetResourceTags(ctx context.Context, o models.Provisionable, org int64) ([]string, error) {
+	tags := make([]string, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		filter := "record_key = ? AND record_type = ? AND org_id = ?"
+		if err := sess.Table(provisioningTagRecord{}).Where(filter, o.ResourceID(), o.ResourceType(), org).Asc("tag").Cols("tag").Find(&tags); err != nil {
+			return fmt.Errorf("failed to query for resource tags: %w", err)
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// GetTaggedResources gets the IDs of the objects of the given type that have the tag.
+func (st DBstore) GetTaggedResources(ctx context.Context, org int64, resourceType string, tag string) ([]string, error) {
+	keys := make([]string, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		filter := "record_type = ? AND tag = ? AND org_id = ?"
+		if err := sess.Table(provisioningTagRecord{}).Where(filter, resourceType, tag, org).Cols("record_key").Find(&keys); err != nil {
+			return fmt.Errorf("failed to query for tagged resources: %w", err)
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// SetResourceTags replaces the tags of a provisionable object. The object has no tag anymore if tags is empty.
+func (st DBstore) SetResourceTags(ctx context.Context, o models.Provisionable, org int64, tags []string) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		filter := "record_key = ? AND record_type = ? AND org_id = ?"
+		if _, err := sess.Table(provisioningTagRecord{}).Where(filter, o.ResourceID(), o.ResourceType(), org).Delete(provisioningTagRecord{}); err != nil {
+			return fmt.Errorf("failed to delete pre-existing resource tags: %w", err)
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		records := make([]provisioningTagRecord, 0, len(tags))
+		for _, tag := range tags {
+			records = append(records, provisioningTagRecord{
+				OrgID:      org,
+				RecordType: o.ResourceType(),
+				RecordKey:  o.ResourceID(),
+				Tag:        tag,
+			})
+		}
+		if _, err := sess.Insert(records); err != nil {
+			return fmt.Errorf("failed to store resource tags: %w", err)
+		}
+		return nil
+	})
+}
+
+// DeleteResourceTags deletes the tags of a provisionable object.
+func (st DBstore) DeleteResourceTags(ctx context.Context, o models.Provisionable, org int64) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		filter := "record_key = ? AND record_type = ? AND org_id = ?"
+		if _, err := sess.Table(provisioningTagRecord{}).Where(filter, o.ResourceID(), o.ResourceType(), org).Delete(provisioningTagRecord{}); err != nil {
+			return fmt.Errorf("failed to delete resource tags: %w", err)
+		}
+		return nil
+	})
+}
+
+// deleteRuleTags deletes the tags of the rules, and the tags of the given rule groups that have no rule anymore. It
+// must be called once the rules are deleted or moved to other groups.
+func deleteRuleTags(sess *db.Session, orgID int64, groups []models.AlertRuleGroupKey, ruleUIDs ...string) error {
+	if len(ruleUIDs) > 0 {
+		ruleType := (&models.AlertRule{}).ResourceType()
+		if _, err := sess.Where("org_id = ? AND record_type = ?", orgID, ruleType).In("record_key", ruleUIDs).Delete(provisioningTagRecord{}); err != nil {
+			return fmt.Errorf("failed to delete the tags of rules: %w", err)
+		}
+	}
+	for _, group := range groups {
+		exists, err := sess.Table("alert_rule").Where("org_id = ? AND namespace_uid = ? AND rule_group = ?", orgID, group.NamespaceUID, group.RuleGroup).Exist()
+		if err != nil {
+			return fmt.Errorf("failed to check whether the rule group exists: %w", err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := sess.Where("org_id = ? AND record_type = ? AND record_key = ?", orgID, group.ResourceType(), group.ResourceID()).Delete(provisioningTagRecord{}); err != nil {
+			return fmt.Errorf("failed to delete the tags of the rule group: %w", err)
+		}
+	}
+	return nil
+}
+
+// ruleGroupKeys returns the keys of the rule groups of the rules, each once.
+func ruleGroupKeys(sess *db.Session, orgID int64, ruleUIDs ...string) ([]models.AlertRuleGroupKey, error) {
+	var rules []models.AlertRule
+	if err := sess.Table("alert_rule").Where("org_id = ?", orgID).In("uid", ruleUIDs).Cols("org_id", "namespace_uid", "rule_group").Find(&rules); err != nil {
+		return nil, fmt.Errorf("failed to query the rule groups of rules: %w", err)
+	}
+	seen := make(map[models.AlertRuleGroupKey]struct{}, len(rules))
+	keys := make([]models.AlertRuleGroupKey, 0, len(rules))
+	for _, rule := range rules {
+		key := rule.GetGroupKey()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleGroupArchiveRecord is a rule group whose alert rules are kept but not evaluated.
+type ruleGroupArchiveRecord struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"'org_id'"`
+	NamespaceUID string `xorm:"'namespace_uid'"`
+	RuleGroup    string `xorm:"'rule_group'"`
+}
+
+func (r ruleGroupArchiveRecord) TableName() string {
+	return "alert_rule_group_archive"
+}
+
+// archivedRuleGroupCondition matches the alert rules whose rule group is archived.
+const archivedRuleGroupCondition = "EXISTS (SELECT 1 FROM alert_rule_group_archive a WHERE a.org_id = alert_rule.org_id AND a.namespace_uid = alert_rule.namespace_uid AND a.rule_group = alert_rule.rule_group)"
+
+// GetArchivedRuleGroups returns the archived rule groups of the organization.
+func (st DBstore) GetArchivedRuleGroups(ctx context.Context, orgID int64) ([]ngmodels.AlertRuleGroupKey, error) {
+	var result []ngmodels.AlertRuleGroupKey
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var records []ruleGroupArchiveRecord
+		if err := sess.Where("org_id = ?", orgID).Asc("namespace_uid", "rule_group").Find(&records); err != nil {
+			return fmt.Errorf("failed to query for archived rule groups: %w", err)
+		}
+		result = make([]ngmodels.AlertRuleGroupKey, 0, len(records))
+		for _, r := range records {
+			result = append(result, ngmodels.AlertRuleGroupKey{OrgID: r.OrgID, NamespaceUID: r.NamespaceUID, RuleGroup: r.RuleGroup})
+		}
+		return nil
+	})
+	return result, err
+}
+
+// SetRuleGroupArchived archives or unarchives the rule group. The alert rules of the group are not modified.
+func (st DBstore) SetRuleGroupArchived(ctx context.Context, key ngmodels.AlertRuleGroupKey, archived bool) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND namespace_uid = ? AND rule_group = ?", key.OrgID, key.NamespaceUID, key.RuleGroup).Delete(ruleGroupArchiveRecord{})
+		if err != nil {
+			return fmt.Errorf("failed to delete pre-existing rule group archive: %w", err)
+		}
+		if !archived {
+			return nil
+		}
+		if _, err := sess.Insert(ruleGroupArchiveRecord{OrgID: key.OrgID, NamespaceUID: key.NamespaceUID, RuleGroup: key.RuleGroup}); err != nil {
+			return fmt.Errorf("failed to store rule group archive: %w", err)
+		}
+		return nil
+	})
+}
+
+// deleteEmptyRuleGroupArchives deletes the archives of the rule groups of the organization that have no alert rules
+// anymore, so that a group that is created again with the same name is not archived.
+func deleteEmptyRuleGroupArchives(sess *db.Session, orgID int64) error {
+	_, err := sess.Exec("DELETE FROM alert_rule_group_archive WHERE org_id = ? AND NOT EXISTS (SELECT 1 FROM alert_rule r WHERE r.org_id = alert_rule_group_archive.org_id AND r.namespace_uid = alert_rule_group_archive.namespace_uid AND r.rule_group = alert_rule_group_archive.rule_group)", orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete the archives of empty rule groups: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationRuleGroupArchive(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+	ctx := context.Background()
+
+	uids := &sync.Map{}
+	gen := func(group string) models.AlertRule {
+		r := models.AlertRuleGen(
+			models.WithOrgID(1),
+			models.WithUniqueUID(uids),
+			withIntervalMatching(store.Cfg.BaseInterval),
+		)()
+		r.ID = 0
+		r.NamespaceUID = "folder"
+		r.RuleGroup = group
+		return *r
+	}
+	archived := gen("archived")
+	active := gen("active")
+	_, err := store.InsertAlertRules(ctx, []models.AlertRule{archived, active})
+	require.NoError(t, err)
+
+	list := func(t *testing.T, filter models.ArchivedRuleGroupsFilter) []string {
+		t.Helper()
+		result, err := store.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: 1, ArchivedGroups: filter})
+		require.NoError(t, err)
+		uids := make([]string, 0, len(result))
+		for _, r := range result {
+			uids = append(uids, r.UID)
+		}
+		return uids
+	}
+	scheduled := func(t *testing.T) []string {
+		t.Helper()
+		keys, err := store.GetAlertRulesKeysForScheduling(ctx)
+		require.NoError(t, err)
+		uids := make([]string, 0, len(keys))
+		for _, k := range keys {
+			uids = append(uids, k.UID)
+		}
+		return uids
+	}
+
+	t.Run("rule groups are not archived by default", func(t *testing.T) {
+		groups, err := store.GetArchivedRuleGroups(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, groups)
+		require.ElementsMatch(t, []string{archived.UID, active.UID}, list(t, models.ArchivedRuleGroupsExclude))
+	})
+
+	t.Run("rules of archived groups are filtered and not scheduled", func(t *testing.T) {
+		require.NoError(t, store.SetRuleGroupArchived(ctx, archived.GetGroupKey(), true))
+		require.NoError(t, store.SetRuleGroupArchived(ctx, archived.GetGroupKey(), true), "archiving twice should succeed")
+
+		groups, err := store.GetArchivedRuleGroups(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []models.AlertRuleGroupKey{archived.GetGroupKey()}, groups)
+
+		require.ElementsMatch(t, []string{archived.UID, active.UID}, list(t, models.ArchivedRuleGroupsInclude))
+		require.ElementsMatch(t, []string{active.UID}, list(t, models.ArchivedRuleGroupsExclude))
+		require.ElementsMatch(t, []string{archived.UID}, list(t, models.ArchivedRuleGroupsOnly))
+		require.ElementsMatch(t, []string{active.UID}, scheduled(t))
+	})
+
+	t.Run("rule groups are unarchived", func(t *testing.T) {
+		require.NoError(t, store.SetRuleGroupArchived(ctx, archived.GetGroupKey(), false))
+		require.Empty(t, list(t, models.ArchivedRuleGroupsOnly))
+		require.ElementsMatch(t, []string{archived.UID, active.UID}, scheduled(t))
+	})
+
+	t.Run("archives of deleted groups are deleted", func(t *testing.T) {
+		require.NoError(t, store.SetRuleGroupArchived(ctx, archived.GetGroupKey(), true))
+		require.NoError(t, store.DeleteAlertRulesByUID(ctx, 1, archived.UID))
+
+		groups, err := store.GetArchivedRuleGroups(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, groups)
+	})
+}
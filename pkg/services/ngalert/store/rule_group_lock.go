@@ -0,0 +1,116 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+// ErrGroupLocked is returned when a rule group cannot be locked because a concurrent change of the group did not finish
+// in time.
+var ErrGroupLocked = errutil.Conflict("alerting.alert-rule.groupLocked", errutil.WithPublicMessage("The rule group is being changed by another request. Try again later."))
+
+// LockRuleGroups locks the rule groups until the end of the transaction of the context, so that the concurrent changes
+// of the groups are applied one after the other. It waits for the transactions that hold the locks for at most
+// RuleGroupLockTimeout, and fails with ErrGroupLocked after that. Locking a group again in the same transaction does not
+// wait. If the context has no transaction, the groups are unlocked right away.
+func (st DBstore) LockRuleGroups(ctx context.Context, keys ...ngmodels.AlertRuleGroupKey) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	// The groups are locked in the same order by all transactions, so that they do not deadlock.
+	keys = slices.Clone(keys)
+	slices.SortFunc(keys, func(a, b ngmodels.AlertRuleGroupKey) int {
+		if c := cmp.Compare(a.OrgID, b.OrgID); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.NamespaceUID, b.NamespaceUID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.RuleGroup, b.RuleGroup)
+	})
+	keys = slices.Compact(keys)
+
+	dialect := st.SQLStore.GetDialect()
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) (err error) {
+		if err := setLockTimeout(sess, dialect.DriverName(), st.Cfg.RuleGroupLockTimeout); err != nil {
+			return err
+		}
+		// The timeout of MySQL is set for the session, so it is reset even if a group cannot be locked, otherwise the
+		// pooled connection would keep it for the unrelated statements it runs later.
+		defer func() {
+			if resetErr := setLockTimeout(sess, dialect.DriverName(), 0); resetErr != nil && err == nil {
+				err = resetErr
+			}
+		}()
+		// Upserting the row of a group locks it until the end of the transaction, and waits for the transaction that
+		// inserted or updated it if it is not finished. SQLite has no row locks, but its writes are serialized.
+		upsertSQL := dialect.UpsertSQL(
+			"alert_rule_group_lock",
+			[]string{"org_id", "namespace_uid", "rule_group"},
+			[]string{"org_id", "namespace_uid", "rule_group", "locked_at"},
+		)
+		now := time.Now().Unix()
+		for _, key := range keys {
+			if _, err := sess.Exec(upsertSQL, key.OrgID, key.NamespaceUID, key.RuleGroup, now); err != nil {
+				if dialect.IsLockTimeout(err) {
+					return ErrGroupLocked.Errorf("rule group %s is locked by a concurrent change: %w", key, err)
+				}
+				return fmt.Errorf("failed to lock rule group %s: %w", key, err)
+			}
+		}
+		return nil
+	})
+}
+
+// setLockTimeout sets how long the next statements of the session wait for a lock, 0 for the default of the database.
+func setLockTimeout(sess *db.Session, driverName string, timeout time.Duration) error {
+	var stmt string
+	switch driverName {
+	case migrator.Postgres:
+		stmt = "SET LOCAL lock_timeout = DEFAULT"
+		if timeout > 0 {
+			stmt = fmt.Sprintf("SET LOCAL lock_timeout = %d", max(timeout.Milliseconds(), 1))
+		}
+	case migrator.MySQL:
+		// The timeout of MySQL is in seconds.
+		stmt = "SET SESSION innodb_lock_wait_timeout = DEFAULT"
+		if timeout > 0 {
+			stmt = fmt.Sprintf("SET SESSION innodb_lock_wait_timeout = %d", int64(math.Ceil(timeout.Seconds())))
+		}
+	default:
+		return nil
+	}
+	if _, err := sess.Exec(stmt); err != nil {
+		return fmt.Errorf("failed to set the lock timeout: %w", err)
+	}
+	return nil
+}
+
+// deleteEmptyRuleGroupLocks deletes the lock rows of the given rule groups that have no alert rules anymore, so that
+// the table does not keep a row for every group that ever existed. A group that is created again gets a new row when it
+// is locked. Only the given groups are checked, which the transaction changed, so that the rows locked by concurrent
+// changes of other groups are not waited for.
+func deleteEmptyRuleGroupLocks(sess *db.Session, groups []ngmodels.AlertRuleGroupKey) error {
+	for _, group := range groups {
+		exists, err := sess.Table("alert_rule").Where("org_id = ? AND namespace_uid = ? AND rule_group = ?", group.OrgID, group.NamespaceUID, group.RuleGroup).Exist()
+		if err != nil {
+			return fmt.Errorf("failed to check whether the rule group exists: %w", err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := sess.Exec("DELETE FROM alert_rule_group_lock WHERE org_id = ? AND namespace_uid = ? AND rule_group = ?", group.OrgID, group.NamespaceUID, group.RuleGroup); err != nil {
+			return fmt.Errorf("failed to delete the lock of the rule group: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationLockRuleGroups(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	cfg.UnifiedAlerting.RuleGroupLockTimeout = 100 * time.Millisecond
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+	ctx := context.Background()
+	group := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "group"}
+	other := models.AlertRuleGroupKey{OrgID: 1, NamespaceUID: "folder", RuleGroup: "other"}
+
+	t.Run("groups can be locked again in the same transaction", func(t *testing.T) {
+		err := store.InTransaction(ctx, func(ctx context.Context) error {
+			if err := store.LockRuleGroups(ctx, group, other, group); err != nil {
+				return err
+			}
+			return store.LockRuleGroups(ctx, group)
+		})
+		require.NoError(t, err)
+
+		count, err := sqlStore.GetEngine().Table("alert_rule_group_lock").Count()
+		require.NoError(t, err)
+		require.Equal(t, int64(2), count, "each group should have a single lock")
+	})
+
+	t.Run("groups locked by another transaction cannot be locked until it finishes", func(t *testing.T) {
+		if sqlStore.GetDialect().DriverName() == migrator.SQLite {
+			t.Skip("the writes of SQLite are serialized")
+		}
+		locked := make(chan struct{})
+		release := make(chan struct{})
+		done := make(chan error)
+		go func() {
+			done <- store.InTransaction(ctx, func(ctx context.Context) error {
+				err := store.LockRuleGroups(ctx, group)
+				close(locked)
+				if err != nil {
+					return err
+				}
+				<-release
+				return nil
+			})
+		}()
+		<-locked
+
+		err := store.InTransaction(ctx, func(ctx context.Context) error {
+			return store.LockRuleGroups(ctx, group)
+		})
+		require.ErrorIs(t, err, ErrGroupLocked)
+		err = store.InTransaction(ctx, func(ctx context.Context) error {
+			return store.LockRuleGroups(ctx, other)
+		})
+		require.NoError(t, err, "other groups should not be locked")
+
+		close(release)
+		require.NoError(t, <-done)
+		err = store.InTransaction(ctx, func(ctx context.Context) error {
+			return store.LockRuleGroups(ctx, group)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("locks of groups without rules are deleted with their last rule", func(t *testing.T) {
+		rule := models.AlertRuleGen(models.WithOrgID(1), withIntervalMatching(store.Cfg.BaseInterval))()
+		rule.ID = 0
+		_, err := store.InsertAlertRules(ctx, []models.AlertRule{*rule})
+		require.NoError(t, err)
+		key := rule.GetGroupKey()
+
+		locked := func(t *testing.T) bool {
+			t.Helper()
+			exists, err := sqlStore.GetEngine().Table("alert_rule_group_lock").
+				Where("org_id = ? AND namespace_uid = ? AND rule_group = ?", key.OrgID, key.NamespaceUID, key.RuleGroup).Exist()
+			require.NoError(t, err)
+			return exists
+		}
+		err = store.InTransaction(ctx, func(ctx context.Context) error {
+			return store.LockRuleGroups(ctx, key)
+		})
+		require.NoError(t, err)
+		require.True(t, locked(t))
+
+		err = store.InTransaction(ctx, func(ctx context.Context) error {
+			if err := store.LockRuleGroups(ctx, key); err != nil {
+				return err
+			}
+			return store.DeleteAlertRulesByUID(ctx, rule.OrgID, rule.UID)
+		})
+		require.NoError(t, err)
+		require.False(t, locked(t))
+	})
+}
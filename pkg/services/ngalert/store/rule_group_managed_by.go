@@ -0,0 +1,77 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ruleGroupManagedByRecord is who manages a provisioned rule group. It is stored per group rather than in the
+// provenance metadata of the rules, so that it is not removed when the provenance of one of the rules is written.
+type ruleGroupManagedByRecord struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"'org_id'"`
+	NamespaceUID string `xorm:"'namespace_uid'"`
+	RuleGroup    string `xorm:"'rule_group'"`
+	Title        string `xorm:"'title'"`
+	URL          string `xorm:"'url'"`
+}
+
+func (r ruleGroupManagedByRecord) TableName() string {
+	return "alert_rule_group_managed_by"
+}
+
+// GetRuleGroupsManagedBy returns who manages the rule groups of the organization, for the groups that have it.
+func (st DBstore) GetRuleGroupsManagedBy(ctx context.Context, orgID int64) (map[ngmodels.AlertRuleGroupKey]ngmodels.ManagedBy, error) {
+	var result map[ngmodels.AlertRuleGroupKey]ngmodels.ManagedBy
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var records []ruleGroupManagedByRecord
+		if err := sess.Where("org_id = ?", orgID).Find(&records); err != nil {
+			return fmt.Errorf("failed to query for who manages the rule groups: %w", err)
+		}
+		result = make(map[ngmodels.AlertRuleGroupKey]ngmodels.ManagedBy, len(records))
+		for _, r := range records {
+			key := ngmodels.AlertRuleGroupKey{OrgID: r.OrgID, NamespaceUID: r.NamespaceUID, RuleGroup: r.RuleGroup}
+			result[key] = ngmodels.ManagedBy{Title: r.Title, URL: r.URL}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// SetRuleGroupManagedBy stores who manages the rule group, or removes it if managedBy is nil. The alert rules of the
+// group and their provenance are not modified.
+func (st DBstore) SetRuleGroupManagedBy(ctx context.Context, key ngmodels.AlertRuleGroupKey, managedBy *ngmodels.ManagedBy) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND namespace_uid = ? AND rule_group = ?", key.OrgID, key.NamespaceUID, key.RuleGroup).Delete(ruleGroupManagedByRecord{})
+		if err != nil {
+			return fmt.Errorf("failed to delete who manages the rule group: %w", err)
+		}
+		if managedBy == nil {
+			return nil
+		}
+		record := ruleGroupManagedByRecord{
+			OrgID:        key.OrgID,
+			NamespaceUID: key.NamespaceUID,
+			RuleGroup:    key.RuleGroup,
+			Title:        managedBy.Title,
+			URL:          managedBy.URL,
+		}
+		if _, err := sess.Insert(record); err != nil {
+			return fmt.Errorf("failed to store who manages the rule group: %w", err)
+		}
+		return nil
+	})
+}
+
+// deleteEmptyRuleGroupsManagedBy deletes who manages the rule groups of the organization that have no alert rules
+// anymore, so that a group that is created again with the same name does not link to the source of the old one.
+func deleteEmptyRuleGroupsManagedBy(sess *db.Session, orgID int64) error {
+	_, err := sess.Exec("DELETE FROM alert_rule_group_managed_by WHERE org_id = ? AND NOT EXISTS (SELECT 1 FROM alert_rule r WHERE r.org_id = alert_rule_group_managed_by.org_id AND r.namespace_uid = alert_rule_group_managed_by.namespace_uid AND r.rule_group = alert_rule_group_managed_by.rule_group)", orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete who manages the empty rule groups: %w", err)
+	}
+	return nil
+}
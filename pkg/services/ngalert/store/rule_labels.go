@@ -0,0 +1,113 @@
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+)
+
+// ruleLabelRecord is a label of an alert rule in the label index, which is kept in sync with the labels column of the
+// alert_rule table when rules are written so that label matchers can be evaluated by the database.
+type ruleLabelRecord struct {
+	ID      int64  `xorm:"pk autoincr 'id'"`
+	OrgID   int64  `xorm:"'org_id'"`
+	RuleUID string `xorm:"'rule_uid'"`
+	Name    string `xorm:"'label_name'"`
+	Value   string `xorm:"'label_value'"`
+}
+
+func (r ruleLabelRecord) TableName() string {
+	return "alert_rule_label"
+}
+
+// WriteRuleLabels replaces the labels of the rule in the label index. It must be called in the session that writes the
+// labels of the rule. Labels with an empty value are not indexed, as they are the same as missing labels for matchers.
+func WriteRuleLabels(sess *db.Session, orgID int64, ruleUID string, lbls map[string]string) error {
+	if err := deleteRuleLabels(sess, orgID, ruleUID); err != nil {
+		return err
+	}
+	records := ruleLabelRecords(orgID, ruleUID, lbls)
+	if len(records) == 0 {
+		return nil
+	}
+	if _, err := sess.Insert(records); err != nil {
+		return fmt.Errorf("failed to index the labels of rule %s: %w", ruleUID, err)
+	}
+	return nil
+}
+
+// insertRuleLabels adds the labels of new rules to the label index in batches.
+func insertRuleLabels(sess *db.Session, opts sqlstore.BulkOpSettings, rules []ngmodels.AlertRule) error {
+	records := make([]ruleLabelRecord, 0)
+	for _, r := range rules {
+		records = append(records, ruleLabelRecords(r.OrgID, r.UID, r.Labels)...)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	if _, err := sess.BulkInsert(ruleLabelRecord{}.TableName(), records, opts); err != nil {
+		return fmt.Errorf("failed to index the labels of rules: %w", err)
+	}
+	return nil
+}
+
+func ruleLabelRecords(orgID int64, ruleUID string, lbls map[string]string) []ruleLabelRecord {
+	records := make([]ruleLabelRecord, 0, len(lbls))
+	for name, value := range lbls {
+		if value == "" || len(name) > ngmodels.RuleLabelNameMaxLength {
+			continue
+		}
+		records = append(records, ruleLabelRecord{OrgID: orgID, RuleUID: ruleUID, Name: name, Value: value})
+	}
+	return records
+}
+
+func deleteRuleLabels(sess *db.Session, orgID int64, ruleUIDs ...string) error {
+	if _, err := sess.Where("org_id = ?", orgID).In("rule_uid", ruleUIDs).Delete(ruleLabelRecord{}); err != nil {
+		return fmt.Errorf("failed to delete the indexed labels of rules: %w", err)
+	}
+	return nil
+}
+
+// labelMatchersCondition returns a condition on the alert_rule table for the matchers that can be evaluated with the
+// label index, and the matchers that cannot, which are the regular expressions and the matchers on labels that are
+// not indexed.
+func labelMatchersCondition(matchers labels.Matchers) (string, []any, labels.Matchers) {
+	var conds []string
+	var args []any
+	var rest labels.Matchers
+	for _, m := range matchers {
+		if len(m.Name) > ngmodels.RuleLabelNameMaxLength {
+			rest = append(rest, m)
+			continue
+		}
+		// A label with an empty value is the same as a missing label, and is not indexed.
+		var exists bool
+		switch m.Type {
+		case labels.MatchEqual:
+			exists = m.Value != ""
+		case labels.MatchNotEqual:
+			exists = m.Value == ""
+		default:
+			rest = append(rest, m)
+			continue
+		}
+		cond := "EXISTS (SELECT 1 FROM alert_rule_label l WHERE l.org_id = alert_rule.org_id AND l.rule_uid = alert_rule.uid AND l.label_name = ?"
+		args = append(args, m.Name)
+		if m.Value != "" {
+			cond += " AND l.label_value = ?"
+			args = append(args, m.Value)
+		}
+		cond += ")"
+		if !exists {
+			cond = "NOT " + cond
+		}
+		conds = append(conds, cond)
+	}
+	return strings.Join(conds, " AND "), args, rest
+}
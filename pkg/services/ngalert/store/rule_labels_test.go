@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLabelMatchersCondition(t *testing.T) {
+	matcher := func(typ labels.MatchType, name, value string) *labels.Matcher {
+		m, err := labels.NewMatcher(typ, name, value)
+		require.NoError(t, err)
+		return m
+	}
+
+	t.Run("equality matchers are evaluated with the index", func(t *testing.T) {
+		cond, args, rest := labelMatchersCondition(labels.Matchers{
+			matcher(labels.MatchEqual, "team", "db"),
+			matcher(labels.MatchNotEqual, "env", "prod"),
+			matcher(labels.MatchEqual, "severity", ""),
+			matcher(labels.MatchNotEqual, "owner", ""),
+		})
+		require.Empty(t, rest)
+		require.Equal(t, []any{"team", "db", "env", "prod", "severity", "owner"}, args)
+		require.Equal(t, 4, strings.Count(cond, "EXISTS ("))
+		require.Equal(t, 2, strings.Count(cond, "NOT EXISTS ("), "the matchers on the absence of labels should be negated")
+	})
+
+	t.Run("regular expressions and long names are not evaluated with the index", func(t *testing.T) {
+		regex := matcher(labels.MatchRegexp, "team", "db|web")
+		long := matcher(labels.MatchEqual, strings.Repeat("a", models.RuleLabelNameMaxLength+1), "value")
+		cond, args, rest := labelMatchersCondition(labels.Matchers{regex, long})
+		require.Empty(t, cond)
+		require.Empty(t, args)
+		require.Equal(t, labels.Matchers{regex, long}, rest)
+	})
+}
+
+func TestIntegrationRuleLabelIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	uids := &sync.Map{}
+	rule := models.AlertRuleGen(
+		models.WithOrgID(1),
+		models.WithUniqueUID(uids),
+		models.WithLabels(map[string]string{"team": "db", "empty": ""}),
+		withIntervalMatching(store.Cfg.BaseInterval),
+	)()
+	rule.ID = 0
+	_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{*rule})
+	require.NoError(t, err)
+
+	indexed := func(t *testing.T) map[string]string {
+		t.Helper()
+		var records []ruleLabelRecord
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			return sess.Where("org_id = ? AND rule_uid = ?", rule.OrgID, rule.UID).Find(&records)
+		})
+		require.NoError(t, err)
+		result := make(map[string]string, len(records))
+		for _, r := range records {
+			result[r.Name] = r.Value
+		}
+		return result
+	}
+
+	t.Run("labels of new rules are indexed without the empty ones", func(t *testing.T) {
+		require.Equal(t, map[string]string{"team": "db"}, indexed(t))
+	})
+
+	t.Run("labels of updated rules are replaced", func(t *testing.T) {
+		existing, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID})
+		require.NoError(t, err)
+		updated := models.CopyRule(existing)
+		updated.Labels = map[string]string{"team": "web", "env": "prod"}
+		require.NoError(t, store.UpdateAlertRules(context.Background(), []models.UpdateRule{{Existing: existing, New: *updated}}))
+		require.Equal(t, map[string]string{"team": "web", "env": "prod"}, indexed(t))
+	})
+
+	t.Run("labels of deleted rules are removed", func(t *testing.T) {
+		require.NoError(t, store.DeleteAlertRulesByUID(context.Background(), rule.OrgID, rule.UID))
+		require.Empty(t, indexed(t))
+	})
+}
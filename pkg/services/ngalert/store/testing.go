@@ -115,13 +115,13 @@ func (f *FakeAdminConfigStore) GetAdminConfigurations() ([]*models.AdminConfigur
 	return acs, nil
 }
 
-func (f *FakeAdminConfigStore) DeleteAdminConfiguration(orgID int64) error {
+func (f *FakeAdminConfigStore) DeleteAdminConfiguration(_ context.Context, orgID int64) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
 	delete(f.Configs, orgID)
 	return nil
 }
-func (f *FakeAdminConfigStore) UpdateAdminConfiguration(cmd UpdateAdminConfigurationCmd) error {
+func (f *FakeAdminConfigStore) UpdateAdminConfiguration(_ context.Context, cmd UpdateAdminConfigurationCmd) error {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
 	f.Configs[cmd.AdminConfiguration.OrgID] = cmd.AdminConfiguration
@@ -1,7 +1,95 @@
 package store
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// savepointDepthKey is used to store the number of savepoints opened in the current transaction in the context.
+type savepointDepthKey struct{}
+
+// savepointHooksKey is used to store the after commit hooks registered in the current savepoint in the context.
+type savepointHooksKey struct{}
 
 func (st *DBstore) InTransaction(ctx context.Context, f func(ctx context.Context) error) error {
 	return st.SQLStore.InTransaction(ctx, f)
 }
+
+// InSavepoint runs f in a savepoint of the transaction stored in the context. If f returns an error, only the changes
+// made since the savepoint are rolled back and the transaction can still be committed. Savepoints can be nested.
+// If the context has no transaction, f runs in a new transaction.
+func (st *DBstore) InSavepoint(ctx context.Context, f func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(sqlstore.ContextSessionKey{}).(*sqlstore.DBSession); !ok {
+		return st.InTransaction(ctx, f)
+	}
+
+	depth, _ := ctx.Value(savepointDepthKey{}).(int)
+	depth++
+	name := fmt.Sprintf("ngalert_sp_%d", depth)
+
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Exec("SAVEPOINT " + name); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		// Hooks registered in the savepoint are handed over to the outer scope only if the savepoint is kept.
+		hooks := make([]func(), 0)
+		spCtx := context.WithValue(context.WithValue(ctx, savepointDepthKey{}, depth), savepointHooksKey{}, &hooks)
+		err := f(spCtx)
+		if err != nil {
+			if _, rollErr := sess.Exec("ROLLBACK TO SAVEPOINT " + name); rollErr != nil {
+				return errors.Join(err, fmt.Errorf("failed to roll back to savepoint: %w", rollErr))
+			}
+		}
+		if _, relErr := sess.Exec("RELEASE SAVEPOINT " + name); relErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to release savepoint: %w", relErr))
+		}
+		if err == nil {
+			for _, hook := range hooks {
+				st.AfterCommit(ctx, hook)
+			}
+		}
+		return err
+	})
+}
+
+// InSnapshot runs f in a transaction whose reads all see the same snapshot of the database. Transactions of SQLite are
+// serializable, and those of MySQL read from the snapshot taken by their first read at the default isolation level, but
+// each statement of a transaction of PostgreSQL reads from a new snapshot at its default isolation level, so it is
+// raised to repeatable read. If the context has a transaction, f runs in it.
+func (st *DBstore) InSnapshot(ctx context.Context, f func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(sqlstore.ContextSessionKey{}).(*sqlstore.DBSession); ok {
+		return f(ctx)
+	}
+	return st.InTransaction(ctx, func(ctx context.Context) error {
+		if st.SQLStore.GetDialect().DriverName() == migrator.Postgres {
+			err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+				_, err := sess.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ")
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set the isolation level of the transaction: %w", err)
+			}
+		}
+		return f(ctx)
+	})
+}
+
+// AfterCommit registers fn to be called once the transaction in the context is committed. It is not called if the
+// transaction, or the savepoint it was registered in, is rolled back. If the context has no transaction, fn is called
+// immediately.
+func (st *DBstore) AfterCommit(ctx context.Context, fn func()) {
+	if hooks, ok := ctx.Value(savepointHooksKey{}).(*[]func()); ok {
+		*hooks = append(*hooks, fn)
+		return
+	}
+	if sess, ok := ctx.Value(sqlstore.ContextSessionKey{}).(*sqlstore.DBSession); ok {
+		sess.AfterCommit(fn)
+		return
+	}
+	fn()
+}
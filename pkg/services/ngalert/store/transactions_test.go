@@ -0,0 +1,190 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationInSavepoint(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	gen := models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithOrgID(1))
+	newRule := func() models.AlertRule {
+		r := gen()
+		r.ID = 0
+		return *r
+	}
+	insert := func(ctx context.Context, rule models.AlertRule) error {
+		_, err := store.InsertAlertRules(ctx, []models.AlertRule{rule})
+		return err
+	}
+	exists := func(t *testing.T, uid string) bool {
+		t.Helper()
+		_, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: uid})
+		if errors.Is(err, models.ErrAlertRuleNotFound) {
+			return false
+		}
+		require.NoError(t, err)
+		return true
+	}
+	errFailed := errors.New("failed")
+
+	t.Run("failed savepoint is rolled back while the transaction is committed", func(t *testing.T) {
+		committed, rolledBack := newRule(), newRule()
+		err := store.InTransaction(context.Background(), func(ctx context.Context) error {
+			require.NoError(t, store.InSavepoint(ctx, func(ctx context.Context) error {
+				return insert(ctx, committed)
+			}))
+			err := store.InSavepoint(ctx, func(ctx context.Context) error {
+				require.NoError(t, insert(ctx, rolledBack))
+				return errFailed
+			})
+			require.ErrorIs(t, err, errFailed)
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.True(t, exists(t, committed.UID))
+		require.False(t, exists(t, rolledBack.UID))
+	})
+
+	t.Run("nested savepoints are rolled back independently", func(t *testing.T) {
+		outer, inner := newRule(), newRule()
+		err := store.InTransaction(context.Background(), func(ctx context.Context) error {
+			return store.InSavepoint(ctx, func(ctx context.Context) error {
+				require.NoError(t, insert(ctx, outer))
+				err := store.InSavepoint(ctx, func(ctx context.Context) error {
+					require.NoError(t, insert(ctx, inner))
+					return errFailed
+				})
+				require.ErrorIs(t, err, errFailed)
+				return nil
+			})
+		})
+		require.NoError(t, err)
+
+		require.True(t, exists(t, outer.UID))
+		require.False(t, exists(t, inner.UID))
+	})
+
+	t.Run("savepoint without transaction runs in a new transaction", func(t *testing.T) {
+		rule := newRule()
+		err := store.InSavepoint(context.Background(), func(ctx context.Context) error {
+			require.NoError(t, insert(ctx, rule))
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+		require.False(t, exists(t, rule.UID))
+	})
+
+	t.Run("after commit hooks run only if the transaction is committed", func(t *testing.T) {
+		called := make([]string, 0)
+		hook := func(name string) func() {
+			return func() { called = append(called, name) }
+		}
+
+		err := store.InTransaction(context.Background(), func(ctx context.Context) error {
+			store.AfterCommit(ctx, hook("transaction"))
+			require.NoError(t, store.InSavepoint(ctx, func(ctx context.Context) error {
+				store.AfterCommit(ctx, hook("savepoint"))
+				return nil
+			}))
+			require.ErrorIs(t, store.InSavepoint(ctx, func(ctx context.Context) error {
+				store.AfterCommit(ctx, hook("rolled back savepoint"))
+				return errFailed
+			}), errFailed)
+			require.Empty(t, called)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"transaction", "savepoint"}, called)
+
+		called = called[:0]
+		err = store.InTransaction(context.Background(), func(ctx context.Context) error {
+			store.AfterCommit(ctx, hook("rolled back transaction"))
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+		require.Empty(t, called)
+
+		store.AfterCommit(context.Background(), hook("no transaction"))
+		require.Equal(t, []string{"no transaction"}, called)
+	})
+}
+
+func TestIntegrationInSnapshot(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting.BaseInterval = 1 * time.Second
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg, featuremgmt.WithFeatures()),
+		Logger:        log.New("test-dbstore"),
+		Cfg:           cfg.UnifiedAlerting,
+	}
+
+	gen := models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithOrgID(1))
+	newRule := func() models.AlertRule {
+		r := gen()
+		r.ID = 0
+		return *r
+	}
+	get := func(ctx context.Context, uid string) error {
+		_, err := store.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: uid})
+		return err
+	}
+
+	t.Run("reads run in a transaction", func(t *testing.T) {
+		rule := newRule()
+		_, err := store.InsertAlertRules(context.Background(), []models.AlertRule{rule})
+		require.NoError(t, err)
+
+		err = store.InSnapshot(context.Background(), func(ctx context.Context) error {
+			require.NotNil(t, ctx.Value(sqlstore.ContextSessionKey{}))
+			return get(ctx, rule.UID)
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("reads join the transaction in the context", func(t *testing.T) {
+		rule := newRule()
+		errFailed := errors.New("failed")
+		err := store.InTransaction(context.Background(), func(ctx context.Context) error {
+			_, err := store.InsertAlertRules(ctx, []models.AlertRule{rule})
+			require.NoError(t, err)
+			require.NoError(t, store.InSnapshot(ctx, func(ctx context.Context) error {
+				return get(ctx, rule.UID)
+			}))
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+		require.ErrorIs(t, get(context.Background(), rule.UID), models.ErrAlertRuleNotFound)
+	})
+}
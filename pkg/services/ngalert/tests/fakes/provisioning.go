@@ -2,18 +2,24 @@ package fakes
 
 import (
 	"context"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 type FakeProvisioningStore struct {
-	Records map[int64]map[string]models.Provenance
+	Records  map[int64]map[string]models.Provenance
+	Metadata map[int64]map[string]models.ProvenanceMetadata
+	Tags     map[int64]map[string][]string
 }
 
 func NewFakeProvisioningStore() *FakeProvisioningStore {
 	return &FakeProvisioningStore{
-		Records: map[int64]map[string]models.Provenance{},
+		Records:  map[int64]map[string]models.Provenance{},
+		Metadata: map[int64]map[string]models.ProvenanceMetadata{},
+		Tags:     map[int64]map[string][]string{},
 	}
 }
 
@@ -38,18 +44,94 @@ func (f *FakeProvisioningStore) GetProvenances(ctx context.Context, orgID int64,
 	return results, nil
 }
 
+// SetProvenance sets the provenance of the object. Like the database store, it removes the metadata of the object.
 func (f *FakeProvisioningStore) SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error {
+	return f.SetProvenanceWithMetadata(ctx, o, org, p, nil)
+}
+
+func (f *FakeProvisioningStore) SetProvenances(ctx context.Context, org int64, objects []models.Provisionable, p models.Provenance) error {
+	for _, o := range objects {
+		_ = f.SetProvenance(ctx, o, org, p)
+	}
+	return nil
+}
+
+func (f *FakeProvisioningStore) SetProvenanceWithMetadata(ctx context.Context, o models.Provisionable, org int64, p models.Provenance, metadata *models.ProvenanceMetadata) error {
 	if _, ok := f.Records[org]; !ok {
 		f.Records[org] = map[string]models.Provenance{}
 	}
-	_ = f.DeleteProvenance(ctx, o, org) // delete old entries first
+	_ = f.DeleteProvenance(ctx, o, org) // delete old entries, and their metadata, first
 	f.Records[org][o.ResourceID()+o.ResourceType()] = p
+	if metadata != nil {
+		if _, ok := f.Metadata[org]; !ok {
+			f.Metadata[org] = map[string]models.ProvenanceMetadata{}
+		}
+		f.Metadata[org][o.ResourceID()+o.ResourceType()] = *metadata
+	}
 	return nil
 }
 
+func (f *FakeProvisioningStore) GetProvenanceMetadata(ctx context.Context, o models.Provisionable, org int64) (*models.ProvenanceMetadata, error) {
+	if val, ok := f.Metadata[org]; ok {
+		if metadata, ok := val[o.ResourceID()+o.ResourceType()]; ok {
+			return &metadata, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *FakeProvisioningStore) GetProvenancesMetadata(ctx context.Context, orgID int64, resourceType string) (map[string]models.ProvenanceMetadata, error) {
+	results := make(map[string]models.ProvenanceMetadata)
+	if val, ok := f.Metadata[orgID]; ok {
+		for k, v := range val {
+			if strings.HasSuffix(k, resourceType) {
+				results[strings.TrimSuffix(k, resourceType)] = v
+			}
+		}
+	}
+	return results, nil
+}
+
 func (f *FakeProvisioningStore) DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error {
 	if val, ok := f.Records[org]; ok {
 		delete(val, o.ResourceID()+o.ResourceType())
 	}
+	if val, ok := f.Metadata[org]; ok {
+		delete(val, o.ResourceID()+o.ResourceType())
+	}
+	return nil
+}
+
+func (f *FakeProvisioningStore) GetResourceTags(ctx context.Context, o models.Provisionable, org int64) ([]string, error) {
+	return append([]string{}, f.Tags[org][o.ResourceID()+o.ResourceType()]...), nil
+}
+
+func (f *FakeProvisioningStore) GetTaggedResources(ctx context.Context, org int64, resourceType string, tag string) ([]string, error) {
+	var results []string
+	for k, tags := range f.Tags[org] {
+		if strings.HasSuffix(k, resourceType) && slices.Contains(tags, tag) {
+			results = append(results, strings.TrimSuffix(k, resourceType))
+		}
+	}
+	sort.Strings(results)
+	return results, nil
+}
+
+func (f *FakeProvisioningStore) SetResourceTags(ctx context.Context, o models.Provisionable, org int64, tags []string) error {
+	if _, ok := f.Tags[org]; !ok {
+		f.Tags[org] = map[string][]string{}
+	}
+	if len(tags) == 0 {
+		delete(f.Tags[org], o.ResourceID()+o.ResourceType())
+		return nil
+	}
+	f.Tags[org][o.ResourceID()+o.ResourceType()] = tags
+	return nil
+}
+
+func (f *FakeProvisioningStore) DeleteResourceTags(ctx context.Context, o models.Provisionable, org int64) error {
+	if val, ok := f.Tags[org]; ok {
+		delete(val, o.ResourceID()+o.ResourceType())
+	}
 	return nil
 }
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -25,6 +27,10 @@ type RuleStore struct {
 	Hook        func(cmd any) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []any
 	Folders     map[int64][]*folder.Folder
+	// Archived are the archived rule groups.
+	Archived []models.AlertRuleGroupKey
+	// ManagedBy is who manages the rule groups.
+	ManagedBy map[models.AlertRuleGroupKey]models.ManagedBy
 }
 
 type GenericRecordedQuery struct {
@@ -174,6 +180,32 @@ func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.Get
 	return ruleList, nil
 }
 
+func (f *RuleStore) GetAlertRulesGroupsByRuleUIDs(_ context.Context, q *models.GetAlertRulesGroupsByRuleUIDsQuery) (map[models.AlertRuleGroupKey]models.RulesGroup, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return nil, err
+	}
+	uids := make(map[string]struct{}, len(q.UIDs))
+	for _, uid := range q.UIDs {
+		uids[uid] = struct{}{}
+	}
+	selected := make(map[models.AlertRuleGroupKey]struct{})
+	for _, rule := range f.Rules[q.OrgID] {
+		if _, ok := uids[rule.UID]; ok {
+			selected[rule.GetGroupKey()] = struct{}{}
+		}
+	}
+	result := make(map[models.AlertRuleGroupKey]models.RulesGroup, len(selected))
+	for _, rule := range f.Rules[q.OrgID] {
+		if _, ok := selected[rule.GetGroupKey()]; ok {
+			result[rule.GetGroupKey()] = append(result[rule.GetGroupKey()], rule)
+		}
+	}
+	return result, nil
+}
+
 func (f *RuleStore) ListAlertRules(_ context.Context, q *models.ListAlertRulesQuery) (models.RulesGroup, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -224,12 +256,67 @@ func (f *RuleStore) ListAlertRules(_ context.Context, q *models.ListAlertRulesQu
 		if q.RuleGroup != "" && r.RuleGroup != q.RuleGroup {
 			continue
 		}
+		if !strings.HasPrefix(r.RuleGroup, q.RuleGroupPrefix) || !q.MatchLabels(r.Labels) {
+			continue
+		}
+		if q.ExpiredAt != nil && !r.IsExpired(*q.ExpiredAt) {
+			continue
+		}
+		switch archived := slices.Contains(f.Archived, r.GetGroupKey()); q.ArchivedGroups {
+		case models.ArchivedRuleGroupsExclude:
+			if archived {
+				continue
+			}
+		case models.ArchivedRuleGroupsOnly:
+			if !archived {
+				continue
+			}
+		}
 		ruleList = append(ruleList, r)
 	}
+	if q.Limit > 0 {
+		start := min(q.Offset(), int64(len(ruleList)))
+		end := min(start+q.Limit, int64(len(ruleList)))
+		ruleList = ruleList[start:end]
+	}
 
 	return ruleList, nil
 }
 
+func (f *RuleStore) LockRuleGroups(_ context.Context, keys ...models.AlertRuleGroupKey) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, GenericRecordedQuery{
+		Name:   "LockRuleGroups",
+		Params: []any{keys},
+	})
+	return nil
+}
+
+func (f *RuleStore) GetArchivedRuleGroups(_ context.Context, orgID int64) ([]models.AlertRuleGroupKey, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	var result []models.AlertRuleGroupKey
+	for _, key := range f.Archived {
+		if key.OrgID == orgID {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}
+
+func (f *RuleStore) GetRuleGroupsManagedBy(_ context.Context, orgID int64) (map[models.AlertRuleGroupKey]models.ManagedBy, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	result := make(map[models.AlertRuleGroupKey]models.ManagedBy)
+	for key, managedBy := range f.ManagedBy {
+		if key.OrgID == orgID {
+			result[key] = managedBy
+		}
+	}
+	return result, nil
+}
+
 func (f *RuleStore) GetUserVisibleNamespaces(_ context.Context, orgID int64, _ identity.Requester) (map[string]*folder.Folder, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -293,6 +380,18 @@ func (f *RuleStore) InTransaction(ctx context.Context, fn func(c context.Context
 	return fn(ctx)
 }
 
+func (f *RuleStore) InSavepoint(ctx context.Context, fn func(c context.Context) error) error {
+	return fn(ctx)
+}
+
+func (f *RuleStore) InSnapshot(ctx context.Context, fn func(c context.Context) error) error {
+	return fn(ctx)
+}
+
+func (f *RuleStore) AfterCommit(_ context.Context, fn func()) {
+	fn()
+}
+
 func (f *RuleStore) GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -42,10 +42,17 @@ var netTransport = &http.Transport{
 	}).Dial,
 	TLSHandshakeTimeout: 5 * time.Second,
 }
-var netClient WebhookClient = &http.Client{
+var netHTTPClient = &http.Client{
 	Timeout:   time.Second * 30,
 	Transport: netTransport,
 }
+var netClient WebhookClient = netHTTPClient
+
+// WebhookHTTPClient returns the HTTP client of the webhooks, for the other requests of the webhook integrations, e.g.
+// the requests of their OAuth2 access tokens, which then go through the same proxy and have the same timeouts.
+func WebhookHTTPClient() *http.Client {
+	return netHTTPClient
+}
 
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
 	if webhook.HttpMethod == "" {
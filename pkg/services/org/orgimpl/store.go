@@ -236,6 +236,7 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			"DELETE FROM alert_notification WHERE org_id = ?",
 			"DELETE FROM alert_notification_state WHERE org_id = ?",
 			"DELETE FROM alert_rule WHERE org_id = ?",
+			"DELETE FROM alert_rule_label WHERE org_id = ?",
 			"DELETE FROM alert_rule_tag WHERE EXISTS (SELECT 1 FROM alert WHERE alert.org_id = ? AND alert.id = alert_rule_tag.alert_id)",
 			"DELETE FROM alert_rule_version WHERE rule_org_id = ?",
 			"DELETE FROM alert WHERE org_id = ?",
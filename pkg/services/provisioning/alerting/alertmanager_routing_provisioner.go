@@ -0,0 +1,55 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type AlertmanagerRoutingProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultAlertmanagerRoutingProvisioner struct {
+	logger                     log.Logger
+	alertmanagerRoutingService provisioning.AlertmanagerRoutingService
+}
+
+func NewAlertmanagerRoutingProvisioner(logger log.Logger,
+	alertmanagerRoutingService provisioning.AlertmanagerRoutingService) AlertmanagerRoutingProvisioner {
+	return &defaultAlertmanagerRoutingProvisioner{
+		logger:                     logger,
+		alertmanagerRoutingService: alertmanagerRoutingService,
+	}
+}
+
+func (c *defaultAlertmanagerRoutingProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, routing := range file.AlertmanagerRouting {
+			err := c.alertmanagerRoutingService.UpdateAlertmanagerRouting(ctx, routing.OrgID,
+				routing.Routing, models.ProvenanceFile)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.Filename, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultAlertmanagerRoutingProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, orgID := range file.ResetAlertmanagerRouting {
+			err := c.alertmanagerRoutingService.ResetAlertmanagerRouting(ctx, int64(orgID))
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.Filename, err)
+			}
+		}
+	}
+	return nil
+}
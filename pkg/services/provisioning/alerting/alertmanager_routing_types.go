@@ -0,0 +1,27 @@
+package alerting
+
+import (
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+type AlertmanagerRoutingV1 struct {
+	OrgID   values.Int64Value               `json:"orgId" yaml:"orgId"`
+	Routing definitions.AlertmanagerRouting `json:",inline" yaml:",inline"`
+}
+
+func (v1 *AlertmanagerRoutingV1) mapToModel() AlertmanagerRouting {
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	return AlertmanagerRouting{
+		OrgID:   orgID,
+		Routing: v1.Routing,
+	}
+}
+
+type AlertmanagerRouting struct {
+	OrgID   int64
+	Routing definitions.AlertmanagerRouting
+}
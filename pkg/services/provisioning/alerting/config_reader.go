@@ -11,15 +11,23 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 )
 
+// currentFileVersion is the apiVersion of the format that AlertingFileV1 decodes. The files without apiVersion are
+// read as files of this version, as they always were.
+const currentFileVersion = 1
+
 type rulesConfigReader struct {
-	log log.Logger
+	// strict makes the files with unknown fields invalid. Unknown fields are only logged otherwise.
+	strict bool
+	log    log.Logger
 }
 
-func newRulesConfigReader(logger log.Logger) rulesConfigReader {
+func newRulesConfigReader(strict bool, logger log.Logger) rulesConfigReader {
 	return rulesConfigReader{
-		log: logger,
+		strict: strict,
+		log:    logger,
 	}
 }
 
@@ -55,6 +63,25 @@ func (cr *rulesConfigReader) readConfig(ctx context.Context, path string) ([]*Al
 	return alertFiles, nil
 }
 
+// ReadFile reads a single alerting provisioning file, with the same validation as the files of the provisioning
+// directory. If strict is true, the file must not have unknown fields.
+func ReadFile(filename string, strict bool, logger log.Logger) (*AlertingFile, error) {
+	cr := newRulesConfigReader(strict, logger)
+	alertFileV1, err := cr.parseFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failure to parse file %s: %w", filename, err)
+	}
+	if alertFileV1 == nil {
+		return &AlertingFile{Filename: filepath.Base(filename)}, nil
+	}
+	alertFileV1.Filename = filepath.Base(filename)
+	alertFile, err := alertFileV1.MapToModel()
+	if err != nil {
+		return nil, fmt.Errorf("failure to map file %s: %w", alertFileV1.Filename, err)
+	}
+	return &alertFile, nil
+}
+
 func (cr *rulesConfigReader) isYAML(file string) bool {
 	return strings.HasSuffix(file, ".yaml") || strings.HasSuffix(file, ".yml")
 }
@@ -65,16 +92,63 @@ func (cr *rulesConfigReader) isJSON(file string) bool {
 
 func (cr *rulesConfigReader) parseConfig(path string, file fs.DirEntry) (*AlertingFileV1, error) {
 	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+	return cr.parseFile(filename)
+}
+
+func (cr *rulesConfigReader) parseFile(filename string) (*AlertingFileV1, error) {
 	// nolint:gosec
-	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath or is
+	// given to the CLI by the operator
 	yamlFile, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
+	// Unknown fields are reported with their path instead of being silently dropped by the decoder.
+	var raw any
+	if err := yaml.Unmarshal(yamlFile, &raw); err != nil {
+		return nil, err
+	}
+	var version configVersion
+	if err := yaml.Unmarshal(yamlFile, &version); err != nil {
+		return nil, err
+	}
+	if err := checkFileVersion(version.APIVersion.Value()); err != nil {
+		return nil, err
+	}
+	if err := FileSchema().Validate(raw); err != nil {
+		if cr.strict {
+			return nil, err
+		}
+		// Files that worked before unknown fields were reported must keep working.
+		cr.log.Warn("Alerting provisioning file has unknown fields, which are ignored", "file", filepath.Base(filename), "error", err)
+	}
 	var cfg *AlertingFileV1
 	err = yaml.Unmarshal(yamlFile, &cfg)
 	if err != nil {
 		return nil, err
 	}
+	// Files exported with metadata must still contain the exported rules.
+	metadata, err := provisioning.ParseExportMetadata(yamlFile)
+	if err != nil {
+		return nil, err
+	}
+	if metadata != nil && cfg != nil {
+		if err := metadata.Verify(cfg.ruleUIDs()); err != nil {
+			return nil, err
+		}
+		cr.log.Info("Alerting provisioning file was exported from another instance", "file", filepath.Base(filename), "source", metadata.Source, "generatedAt", metadata.GeneratedAt)
+	}
 	return cfg, nil
 }
+
+// checkFileVersion fails if the apiVersion of a file is newer than the version that this Grafana version supports,
+// so that the fields of newer formats are not silently ignored.
+func checkFileVersion(version int64) error {
+	if version > currentFileVersion {
+		return fmt.Errorf("unsupported apiVersion %d, the latest supported version is %d", version, currentFileVersion)
+	}
+	if version < 0 {
+		return fmt.Errorf("invalid apiVersion %d", version)
+	}
+	return nil
+}
@@ -14,10 +14,15 @@ const (
 	testFileEmptyFile                   = "./testdata/common/empty-file"
 	testFileEmptyFolder                 = "./testdata/common/empty-folder"
 	testFileSupportedFiletypes          = "./testdata/common/supported-filetypes"
+	testFileUnknownField                = "./testdata/common/unknown-field"
+	testFileMissingAPIVersion           = "./testdata/common/missing-api-version"
+	testFileUnsupportedAPIVersion       = "./testdata/common/unsupported-api-version"
 	testFileCorrectProperties           = "./testdata/alert_rules/correct-properties"
 	testFileCorrectPropertiesWithOrg    = "./testdata/alert_rules/correct-properties-with-org"
 	testFileMultipleRules               = "./testdata/alert_rules/multiple-rules"
 	testFileMultipleFiles               = "./testdata/alert_rules/multiple-files"
+	testFileExportMetadata              = "./testdata/alert_rules/export-metadata"
+	testFileExportMetadataMismatch      = "./testdata/alert_rules/export-metadata-mismatch"
 	testFileCorrectProperties_cp        = "./testdata/contact_points/correct-properties"
 	testFileCorrectPropertiesWithOrg_cp = "./testdata/contact_points/correct-properties-with-org"
 	testFileEmptyUID                    = "./testdata/contact_points/empty-uid"
@@ -36,7 +41,7 @@ const (
 )
 
 func TestConfigReader(t *testing.T) {
-	configReader := newRulesConfigReader(log.NewNopLogger())
+	configReader := newRulesConfigReader(false, log.NewNopLogger())
 	ctx := context.Background()
 	t.Run("a broken YAML file should error", func(t *testing.T) {
 		_, err := configReader.readConfig(ctx, testFileBrokenYAML)
@@ -79,6 +84,35 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, ruleFiles, 3)
 	})
+	t.Run("a file with an unknown field should not error by default", func(t *testing.T) {
+		ruleFiles, err := configReader.readConfig(ctx, testFileUnknownField)
+		require.NoError(t, err)
+		require.Len(t, ruleFiles, 1)
+	})
+	t.Run("a file with an unknown field should error with the path of the field in strict mode", func(t *testing.T) {
+		strictReader := newRulesConfigReader(true, log.NewNopLogger())
+		_, err := strictReader.readConfig(ctx, testFileUnknownField)
+		require.ErrorContains(t, err, "unknown field 'lables' at path '$.groups[0].rules[0]'")
+	})
+	t.Run("a file without apiVersion should be read as a file of the current version", func(t *testing.T) {
+		ruleFiles, err := configReader.readConfig(ctx, testFileMissingAPIVersion)
+		require.NoError(t, err)
+		require.Len(t, ruleFiles[0].Groups, 1)
+		require.Equal(t, "my_first_rule", ruleFiles[0].Groups[0].Rules[0].UID)
+	})
+	t.Run("a file with a newer apiVersion should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileUnsupportedAPIVersion)
+		require.ErrorContains(t, err, "unsupported apiVersion 2")
+	})
+	t.Run("a rule file with export metadata that matches its rules should not error", func(t *testing.T) {
+		ruleFiles, err := configReader.readConfig(ctx, testFileExportMetadata)
+		require.NoError(t, err)
+		require.Len(t, ruleFiles[0].Groups, 1)
+	})
+	t.Run("a rule file with export metadata that does not match its rules should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileExportMetadataMismatch)
+		require.ErrorContains(t, err, "rule 'my_second_rule' was exported but is not in the file")
+	})
 	t.Run("a contact point file with correct properties should not error", func(t *testing.T) {
 		file, err := configReader.readConfig(ctx, testFileCorrectProperties_cp)
 		require.NoError(t, err)
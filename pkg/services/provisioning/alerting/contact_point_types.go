@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -36,6 +38,7 @@ type DeleteContactPoint struct {
 type ContactPointV1 struct {
 	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
 	Name      values.StringValue `json:"name" yaml:"name"`
+	RateLimit *RateLimitV1       `json:"rateLimit" yaml:"rateLimit"`
 	Receivers []ReceiverV1       `json:"receivers" yaml:"receivers"`
 }
 
@@ -50,11 +53,20 @@ func (cpV1 *ContactPointV1) MapToModel() (ContactPoint, error) {
 	if name == "" {
 		return ContactPoint{}, fmt.Errorf("no name is set")
 	}
+	var rateLimit *definitions.NotificationRateLimit
+	if cpV1.RateLimit != nil {
+		var err error
+		rateLimit, err = cpV1.RateLimit.mapToModel()
+		if err != nil {
+			return ContactPoint{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
 	for _, receiverV1 := range cpV1.Receivers {
 		embeddedCP, err := receiverV1.mapToModel(name)
 		if err != nil {
 			return ContactPoint{}, fmt.Errorf("%s: %w", name, err)
 		}
+		embeddedCP.RateLimit = rateLimit
 		contactPoint.ContactPoints = append(contactPoint.ContactPoints, embeddedCP)
 	}
 	return contactPoint, nil
@@ -70,6 +82,29 @@ type ReceiverV1 struct {
 	Type                  values.StringValue `json:"type" yaml:"type"`
 	Settings              values.JSONValue   `json:"settings" yaml:"settings"`
 	DisableResolveMessage values.BoolValue   `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+	Disabled              values.BoolValue   `json:"disabled" yaml:"disabled"`
+}
+
+type RateLimitV1 struct {
+	MaxNotifications values.IntValue    `json:"maxNotifications" yaml:"maxNotifications"`
+	Interval         values.StringValue `json:"interval" yaml:"interval"`
+	OverflowPolicy   values.StringValue `json:"overflowPolicy" yaml:"overflowPolicy"`
+}
+
+func (config *RateLimitV1) mapToModel() (*definitions.NotificationRateLimit, error) {
+	interval, err := model.ParseDuration(config.Interval.Value())
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval of the rate limit: %w", err)
+	}
+	limit := &definitions.NotificationRateLimit{
+		MaxNotifications: config.MaxNotifications.Value(),
+		Interval:         interval,
+		OverflowPolicy:   strings.TrimSpace(config.OverflowPolicy.Value()),
+	}
+	if err := limit.Validate(); err != nil {
+		return nil, err
+	}
+	return limit, nil
 }
 
 func (config *ReceiverV1) mapToModel(name string) (definitions.EmbeddedContactPoint, error) {
@@ -90,6 +125,7 @@ func (config *ReceiverV1) mapToModel(name string) (definitions.EmbeddedContactPo
 		Name:                  name,
 		Type:                  cpType,
 		DisableResolveMessage: config.DisableResolveMessage.Value(),
+		Disabled:              config.Disabled.Value(),
 		Provenance:            string(models.ProvenanceFile),
 		Settings:              settings,
 	}
@@ -2,10 +2,13 @@ package alerting
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/provisioning/values"
 )
 
@@ -15,6 +18,16 @@ func TestReceivers(t *testing.T) {
 		_, err := cp.mapToModel("test")
 		require.NoError(t, err)
 	})
+	t.Run("Disabled flag should be mapped", func(t *testing.T) {
+		cp := validReceiverV1(t)
+		var disabled values.BoolValue
+		err := yaml.Unmarshal([]byte("true"), &disabled)
+		require.NoError(t, err)
+		cp.Disabled = disabled
+		mapped, err := cp.mapToModel("test")
+		require.NoError(t, err)
+		require.True(t, mapped.Disabled)
+	})
 	t.Run("Invalid config should error on mapping", func(t *testing.T) {
 		cp := validReceiverV1(t)
 		var settings values.JSONValue
@@ -62,6 +75,43 @@ func TestReceivers(t *testing.T) {
 	})
 }
 
+func TestContactPoints(t *testing.T) {
+	t.Run("Rate limit should be mapped to every receiver", func(t *testing.T) {
+		var cp ContactPointV1
+		err := yaml.Unmarshal([]byte(`
+name: test
+rateLimit:
+  maxNotifications: 10
+  interval: 1h
+  overflowPolicy: retry
+`), &cp)
+		require.NoError(t, err)
+		first, second := validReceiverV1(t), validReceiverV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("my_other_uid"), &second.UID))
+		cp.Receivers = []ReceiverV1{first, second}
+
+		mapped, err := cp.MapToModel()
+		require.NoError(t, err)
+		require.Len(t, mapped.ContactPoints, 2)
+		for _, receiver := range mapped.ContactPoints {
+			require.Equal(t, &definitions.NotificationRateLimit{
+				MaxNotifications: 10,
+				Interval:         model.Duration(time.Hour),
+				OverflowPolicy:   "retry",
+			}, receiver.RateLimit)
+		}
+	})
+	t.Run("Invalid rate limit should error on mapping", func(t *testing.T) {
+		var cp ContactPointV1
+		err := yaml.Unmarshal([]byte("name: test\nrateLimit:\n  maxNotifications: 0\n  interval: 1h"), &cp)
+		require.NoError(t, err)
+		cp.Receivers = []ReceiverV1{validReceiverV1(t)}
+
+		_, err = cp.MapToModel()
+		require.Error(t, err)
+	})
+}
+
 func validReceiverV1(t *testing.T) ReceiverV1 {
 	t.Helper()
 	var (
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/team"
 )
 
 type ProvisionerConfig struct {
@@ -18,11 +21,17 @@ type ProvisionerConfig struct {
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
 	TemplateService            provisioning.TemplateService
+	AlertmanagerRoutingService provisioning.AlertmanagerRoutingService
+	TeamService                team.Service
+	FolderPermissionsService   accesscontrol.FolderPermissionsService
+	KVStore                    kvstore.KVStore
+	// StrictDecoding makes the files with unknown fields invalid. Unknown fields are only logged otherwise.
+	StrictDecoding bool
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	logger := log.New("provisioning.alerting")
-	cfgReader := newRulesConfigReader(logger)
+	cfgReader := newRulesConfigReader(cfg.StrictDecoding, logger)
 	files, err := cfgReader.readConfig(ctx, cfg.Path)
 	if err != nil {
 		return err
@@ -49,6 +58,15 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("notification policies: %w", err)
 	}
+	amrProvisioner := NewAlertmanagerRoutingProvisioner(logger, cfg.AlertmanagerRoutingService)
+	err = amrProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("alertmanager routing: %w", err)
+	}
+	err = amrProvisioner.Unprovision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("alertmanager routing: %w", err)
+	}
 	err = npProvisioner.Unprovision(ctx, files)
 	if err != nil {
 		return fmt.Errorf("notification policies: %w", err)
@@ -70,6 +88,12 @@ func Provision(ctx context.Context, cfg ProvisionerConfig) error {
 	if err != nil {
 		return fmt.Errorf("alert rules: %w", err)
 	}
+	ownershipProvisioner := NewRuleOwnershipProvisioner(logger, &cfg.RuleService, cfg.TeamService,
+		cfg.FolderPermissionsService, cfg.KVStore)
+	err = ownershipProvisioner.Provision(ctx, files)
+	if err != nil {
+		return fmt.Errorf("rule ownership: %w", err)
+	}
 	err = cpProvisioner.Unprovision(ctx, files) // Unprovision contact points after rules to make sure all references in rules are updated
 	if err != nil {
 		return fmt.Errorf("contact points: %w", err)
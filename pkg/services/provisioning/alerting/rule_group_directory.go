@@ -0,0 +1,141 @@
+package alerting
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// ruleGroupDirectoryDebounce is the time without changes after which the changes of the directory are notified, so that
+// a checkout that writes many files is synced once.
+const ruleGroupDirectoryDebounce = time.Second
+
+// RuleGroupDirectory reads the rule groups of the alerting provisioning files of a directory and of its subdirectories,
+// e.g. a checkout of a Git repository, for the rule sync. Hidden files and directories, like .git, are skipped.
+type RuleGroupDirectory struct {
+	path   string
+	strict bool
+	log    log.Logger
+}
+
+func NewRuleGroupDirectory(path string, strict bool, logger log.Logger) *RuleGroupDirectory {
+	return &RuleGroupDirectory{
+		path:   path,
+		strict: strict,
+		log:    logger,
+	}
+}
+
+// ReadRuleGroups returns the rule groups of the files, with their path relative to the directory. It fails if any file
+// is invalid, so that the groups of the file are not pruned because they are missing.
+func (d *RuleGroupDirectory) ReadRuleGroups(ctx context.Context) ([]provisioning.SourceRuleGroup, error) {
+	cr := newRulesConfigReader(d.strict, d.log)
+	var groups []provisioning.SourceRuleGroup
+	err := filepath.WalkDir(d.path, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != d.path && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || (!cr.isYAML(entry.Name()) && !cr.isJSON(entry.Name())) {
+			return nil
+		}
+		file, err := ReadFile(path, d.strict, d.log)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(d.path, path)
+		if err != nil {
+			return err
+		}
+		for _, group := range file.Groups {
+			groups = append(groups, provisioning.SourceRuleGroup{File: filepath.ToSlash(rel), Group: group})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// Watch notifies the changes of the directory and of its subdirectories, including the directories created after it is
+// called, until the context is done.
+func (d *RuleGroupDirectory) Watch(ctx context.Context) (<-chan struct{}, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.watchDir(watcher, d.path); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		defer func() { _ = watcher.Close() }()
+		debounce := time.NewTimer(ruleGroupDirectoryDebounce)
+		debounce.Stop()
+		defer debounce.Stop()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if strings.HasPrefix(filepath.Base(event.Name), ".") {
+					continue
+				}
+				if event.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						if err := d.watchDir(watcher, event.Name); err != nil {
+							d.log.Warn("Failed to watch the rule group directory", "path", event.Name, "error", err)
+						}
+					}
+				}
+				debounce.Reset(ruleGroupDirectoryDebounce)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				d.log.Warn("Failed to watch the rule group directory", "path", d.path, "error", err)
+			case <-debounce.C:
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// watchDir adds the directory and its subdirectories to the watcher, without the hidden ones.
+func (d *RuleGroupDirectory) watchDir(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		if path != d.path && strings.HasPrefix(entry.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
@@ -0,0 +1,96 @@
+package alerting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+const ruleGroupFile = `apiVersion: 1
+groups:
+  - orgId: 1
+    name: group
+    folderUid: folder
+    interval: 1m
+    rules:
+      - uid: rule-a
+        title: A
+        condition: A
+        for: 0s
+        data:
+          - refId: A
+            datasourceUid: __expr__
+            model:
+              type: math
+              expression: "1 > 0"
+`
+
+func TestRuleGroupDirectory(t *testing.T) {
+	writeFile := func(t *testing.T, path string, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	t.Run("should read the groups of the files of the subdirectories", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "team-a", "rules.yaml"), ruleGroupFile)
+		writeFile(t, filepath.Join(dir, ".git", "rules.yaml"), "invalid")
+		writeFile(t, filepath.Join(dir, "README.md"), "# Alert rules")
+
+		groups, err := NewRuleGroupDirectory(dir, false, log.NewNopLogger()).ReadRuleGroups(context.Background())
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, "team-a/rules.yaml", groups[0].File)
+		require.Equal(t, "group", groups[0].Group.Title)
+		require.Equal(t, "folder", groups[0].Group.FolderUID)
+		require.Len(t, groups[0].Group.Rules, 1)
+	})
+
+	t.Run("should fail if a file is invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "rules.yaml"), ruleGroupFile)
+		writeFile(t, filepath.Join(dir, "broken.yaml"), "groups: [")
+
+		_, err := NewRuleGroupDirectory(dir, false, log.NewNopLogger()).ReadRuleGroups(context.Background())
+		require.Error(t, err)
+	})
+	t.Run("should notify the changes of the subdirectories", func(t *testing.T) {
+		dir := t.TempDir()
+		ctx, cancel := context.WithCancel(context.Background())
+		changes, err := NewRuleGroupDirectory(dir, false, log.NewNopLogger()).Watch(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "team-a"), 0o750))
+		require.Eventually(t, func() bool {
+			select {
+			case <-changes:
+				return true
+			default:
+				return false
+			}
+		}, 5*time.Second, 50*time.Millisecond)
+
+		writeFile(t, filepath.Join(dir, "team-a", "rules.yaml"), ruleGroupFile)
+		require.Eventually(t, func() bool {
+			select {
+			case <-changes:
+				return true
+			default:
+				return false
+			}
+		}, 5*time.Second, 50*time.Millisecond, "the changes of the created directories should be notified")
+
+		cancel()
+		require.Eventually(t, func() bool {
+			_, ok := <-changes
+			return !ok
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
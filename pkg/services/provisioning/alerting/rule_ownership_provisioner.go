@@ -0,0 +1,249 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	alert_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+type RuleOwnershipProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+}
+
+type alertRuleLister interface {
+	GetAlertRules(ctx context.Context, user identity.Requester, query alert_models.ListAlertRulesQuery) ([]*alert_models.AlertRule, map[string]alert_models.Provenance, error)
+}
+
+type teamSearcher interface {
+	SearchTeams(ctx context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error)
+}
+
+// ruleOwnershipNamespace is the namespace of the key-value store in which the folder permissions that the sync granted
+// are recorded, so that it never removes the permissions that were granted in another way.
+const ruleOwnershipNamespace = "alerting.rule_ownership"
+
+// ruleOwnershipKey is the key of the permissions granted for the rule label, by folder UID and team ID.
+func ruleOwnershipKey(label string) string {
+	return "granted_permissions." + label
+}
+
+type grantedPermissions map[string]map[int64]string
+
+type defaultRuleOwnershipProvisioner struct {
+	logger            log.Logger
+	ruleService       alertRuleLister
+	teamService       teamSearcher
+	folderPermissions accesscontrol.FolderPermissionsService
+	kvStore           kvstore.KVStore
+}
+
+func NewRuleOwnershipProvisioner(logger log.Logger,
+	ruleService alertRuleLister,
+	teamService teamSearcher,
+	folderPermissions accesscontrol.FolderPermissionsService,
+	kvStore kvstore.KVStore) RuleOwnershipProvisioner {
+	return &defaultRuleOwnershipProvisioner{
+		logger:            logger,
+		ruleService:       ruleService,
+		teamService:       teamService,
+		folderPermissions: folderPermissions,
+		kvStore:           kvStore,
+	}
+}
+
+func (prov *defaultRuleOwnershipProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		for _, ownership := range file.RuleOwnership {
+			if err := prov.sync(ctx, ownership); err != nil {
+				return fmt.Errorf("%s: %w", file.Filename, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sync gives the owner teams of the rules of each folder the permission of the policy on the folder, and removes the
+// permissions that it granted before to the teams that no longer own rules of the folder. It runs only when the
+// alerting provisioning files are applied, that is when Grafana starts and when the provisioning is reloaded, so the
+// changes of rules and teams in between are picked up at the next run.
+//
+// Only the permissions that the sync granted are managed: a team that already has a permission on the folder keeps
+// it, and a permission that was changed by hand after the sync granted it is no longer managed.
+func (prov *defaultRuleOwnershipProvisioner) sync(ctx context.Context, ownership RuleOwnership) error {
+	rules, _, err := prov.ruleService.GetAlertRules(ctx, nil, alert_models.ListAlertRulesQuery{OrgID: ownership.OrgID})
+	if err != nil {
+		return err
+	}
+	ownersByFolder := make(map[string]map[string]struct{})
+	teamIDs := make(map[string]int64)
+	for _, rule := range rules {
+		name := rule.Labels[ownership.Label]
+		if name == "" {
+			continue
+		}
+		owners, ok := ownersByFolder[rule.NamespaceUID]
+		if !ok {
+			owners = make(map[string]struct{})
+			ownersByFolder[rule.NamespaceUID] = owners
+		}
+		owners[name] = struct{}{}
+		teamIDs[name] = 0
+	}
+
+	store := kvstore.WithNamespace(prov.kvStore, ownership.OrgID, ruleOwnershipNamespace)
+	granted := make(grantedPermissions)
+	raw, ok, err := store.Get(ctx, ruleOwnershipKey(ownership.Label))
+	if err != nil {
+		return fmt.Errorf("failed to get the permissions granted to the owner teams: %w", err)
+	}
+	if ok {
+		if err := json.Unmarshal([]byte(raw), &granted); err != nil {
+			return fmt.Errorf("failed to decode the permissions granted to the owner teams: %w", err)
+		}
+	}
+	if len(teamIDs) == 0 && len(granted) == 0 {
+		return nil
+	}
+
+	user := accesscontrol.BackgroundUser("alerting_provisioning", ownership.OrgID, org.RoleAdmin, []accesscontrol.Permission{
+		{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+		{Action: dashboards.ActionFoldersPermissionsRead, Scope: dashboards.ScopeFoldersAll},
+	})
+	for name := range teamIDs {
+		result, err := prov.teamService.SearchTeams(ctx, &team.SearchTeamsQuery{
+			OrgID:        ownership.OrgID,
+			Name:         name,
+			Limit:        1,
+			SignedInUser: user,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find team '%s': %w", name, err)
+		}
+		if len(result.Teams) == 0 {
+			prov.logger.Warn("team that owns alert rules does not exist", "org", ownership.OrgID, "label", ownership.Label, "team", name)
+			delete(teamIDs, name)
+			continue
+		}
+		teamIDs[name] = result.Teams[0].ID
+	}
+
+	// desired holds the permission of the owner teams, by folder UID and team ID.
+	desired := make(grantedPermissions)
+	for folderUID, owners := range ownersByFolder {
+		for name := range owners {
+			if teamID, ok := teamIDs[name]; ok {
+				desired.set(folderUID, teamID, ownership.Permission)
+			}
+		}
+	}
+
+	folderUIDs := make([]string, 0, len(desired)+len(granted))
+	for folderUID := range desired {
+		folderUIDs = append(folderUIDs, folderUID)
+	}
+	for folderUID := range granted {
+		if _, ok := desired[folderUID]; !ok {
+			folderUIDs = append(folderUIDs, folderUID)
+		}
+	}
+	sort.Strings(folderUIDs)
+
+	nextGranted := make(grantedPermissions)
+	var syncErr error
+	for i, folderUID := range folderUIDs {
+		if syncErr = prov.syncFolder(ctx, user, ownership, folderUID, desired[folderUID], granted[folderUID], nextGranted); syncErr == nil {
+			continue
+		}
+		// Keep the record of the permissions that were not synced, so that the next run still manages them.
+		for _, uid := range folderUIDs[i:] {
+			for teamID, permission := range granted[uid] {
+				if _, ok := nextGranted[uid][teamID]; !ok {
+					nextGranted.set(uid, teamID, permission)
+				}
+			}
+		}
+		break
+	}
+
+	value, err := json.Marshal(nextGranted)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(ctx, ruleOwnershipKey(ownership.Label), string(value)); err != nil {
+		return fmt.Errorf("failed to record the permissions granted to the owner teams: %w", err)
+	}
+	return syncErr
+}
+
+// syncFolder sets the permissions of the owner teams on the folder and records the permissions that are managed by the
+// sync in next.
+func (prov *defaultRuleOwnershipProvisioner) syncFolder(ctx context.Context, user identity.Requester, ownership RuleOwnership,
+	folderUID string, desired map[int64]string, granted map[int64]string, next grantedPermissions) error {
+	permissions, err := prov.folderPermissions.GetPermissions(ctx, user, folderUID)
+	if err != nil {
+		return fmt.Errorf("failed to get the permissions of folder '%s': %w", folderUID, err)
+	}
+	current := make(map[int64]string)
+	for _, p := range permissions {
+		if p.TeamId != 0 && !p.IsInherited {
+			current[p.TeamId] = prov.folderPermissions.MapActions(p)
+		}
+	}
+
+	teamIDs := make([]int64, 0, len(desired)+len(granted))
+	for teamID := range desired {
+		teamIDs = append(teamIDs, teamID)
+	}
+	for teamID := range granted {
+		if _, ok := desired[teamID]; !ok {
+			teamIDs = append(teamIDs, teamID)
+		}
+	}
+	slices.Sort(teamIDs)
+
+	for _, teamID := range teamIDs {
+		permission, owner := desired[teamID]
+		previous, managed := granted[teamID]
+		if managed && current[teamID] != previous {
+			// The permission was changed or removed by hand, it is no longer managed by the sync.
+			prov.logger.Debug("folder permission of team that owns alert rules was changed by hand", "org", ownership.OrgID, "folderUID", folderUID, "teamID", teamID)
+			continue
+		}
+		if !managed && current[teamID] != "" {
+			// The team has a permission that was not granted by the sync.
+			continue
+		}
+		if !owner {
+			permission = ""
+		}
+		if current[teamID] != permission {
+			prov.logger.Debug("setting folder permission of team that owns alert rules", "org", ownership.OrgID, "folderUID", folderUID, "teamID", teamID, "permission", permission)
+			if _, err := prov.folderPermissions.SetTeamPermission(ctx, ownership.OrgID, teamID, folderUID, permission); err != nil {
+				return fmt.Errorf("failed to set the permission of team %d on folder '%s': %w", teamID, folderUID, err)
+			}
+		}
+		if permission != "" {
+			next.set(folderUID, teamID, permission)
+		}
+	}
+	return nil
+}
+
+func (g grantedPermissions) set(folderUID string, teamID int64, permission string) {
+	if g[folderUID] == nil {
+		g[folderUID] = make(map[int64]string)
+	}
+	g[folderUID][teamID] = permission
+}
@@ -0,0 +1,155 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/auth/identity"
+	alert_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/team"
+)
+
+func TestRuleOwnership(t *testing.T) {
+	t.Run("permission should default to Edit", func(t *testing.T) {
+		var v1 RuleOwnershipV1
+		require.NoError(t, yaml.Unmarshal([]byte("label: team"), &v1))
+		ownership, err := v1.mapToModel()
+		require.NoError(t, err)
+		require.Equal(t, RuleOwnership{OrgID: 1, Label: "team", Permission: "Edit"}, ownership)
+	})
+	t.Run("missing label should error", func(t *testing.T) {
+		var v1 RuleOwnershipV1
+		require.NoError(t, yaml.Unmarshal([]byte("permission: View"), &v1))
+		_, err := v1.mapToModel()
+		require.ErrorContains(t, err, "missing label")
+	})
+	t.Run("invalid permission should error", func(t *testing.T) {
+		var v1 RuleOwnershipV1
+		require.NoError(t, yaml.Unmarshal([]byte("label: team\npermission: Write"), &v1))
+		_, err := v1.mapToModel()
+		require.ErrorContains(t, err, "invalid permission 'Write'")
+	})
+}
+
+type fakeRuleLister struct {
+	rules []*alert_models.AlertRule
+}
+
+func (f *fakeRuleLister) GetAlertRules(_ context.Context, _ identity.Requester, _ alert_models.ListAlertRulesQuery) ([]*alert_models.AlertRule, map[string]alert_models.Provenance, error) {
+	return f.rules, nil, nil
+}
+
+type fakeTeamSearcher struct {
+	teams map[string]int64
+}
+
+func (f *fakeTeamSearcher) SearchTeams(_ context.Context, query *team.SearchTeamsQuery) (team.SearchTeamQueryResult, error) {
+	if id, ok := f.teams[query.Name]; ok {
+		return team.SearchTeamQueryResult{Teams: []*team.TeamDTO{{ID: id, Name: query.Name}}}, nil
+	}
+	return team.SearchTeamQueryResult{}, nil
+}
+
+type setTeamPermission struct {
+	TeamID     int64
+	FolderUID  string
+	Permission string
+}
+
+// fakeFolderPermissions holds the permissions of teams on folders, by folder and team.
+type fakeFolderPermissions struct {
+	actest.FakePermissionsService
+	permissions map[string]map[int64]string
+	set         []setTeamPermission
+}
+
+func (f *fakeFolderPermissions) GetPermissions(_ context.Context, _ identity.Requester, folderUID string) ([]accesscontrol.ResourcePermission, error) {
+	var result []accesscontrol.ResourcePermission
+	for teamID, permission := range f.permissions[folderUID] {
+		result = append(result, accesscontrol.ResourcePermission{TeamId: teamID, Actions: []string{permission}, IsManaged: true})
+	}
+	return result, nil
+}
+
+func (f *fakeFolderPermissions) MapActions(permission accesscontrol.ResourcePermission) string {
+	return permission.Actions[0]
+}
+
+func (f *fakeFolderPermissions) SetTeamPermission(_ context.Context, _ int64, teamID int64, folderUID, permission string) (*accesscontrol.ResourcePermission, error) {
+	f.set = append(f.set, setTeamPermission{TeamID: teamID, FolderUID: folderUID, Permission: permission})
+	if f.permissions[folderUID] == nil {
+		f.permissions[folderUID] = make(map[int64]string)
+	}
+	if permission == "" {
+		delete(f.permissions[folderUID], teamID)
+	} else {
+		f.permissions[folderUID][teamID] = permission
+	}
+	return nil, nil
+}
+
+func TestRuleOwnershipProvisioner(t *testing.T) {
+	rule := func(folderUID, owner string) *alert_models.AlertRule {
+		r := &alert_models.AlertRule{NamespaceUID: folderUID, Labels: map[string]string{}}
+		if owner != "" {
+			r.Labels["team"] = owner
+		}
+		return r
+	}
+	rules := &fakeRuleLister{rules: []*alert_models.AlertRule{
+		rule("infra-folder", "infra"),
+		rule("infra-folder", "unknown"),
+		rule("shared-folder", "infra"),
+		rule("shared-folder", "payments"),
+		rule("payments-folder", "payments"),
+		rule("unowned-folder", ""),
+	}}
+	teams := &fakeTeamSearcher{teams: map[string]int64{"infra": 1, "payments": 2, "other": 3}}
+	permissions := &fakeFolderPermissions{permissions: map[string]map[int64]string{
+		// infra had the permission before the sync, and the sync granted payments a permission when it owned rules of
+		// the folder.
+		"infra-folder": {1: "Edit", 2: "Edit"},
+		// payments has a permission that is maintained by hand.
+		"payments-folder": {2: "View"},
+		// The permissions of the teams that do not own any rule are maintained by hand.
+		"unowned-folder": {2: "Admin", 3: "View"},
+		// The permission that the sync granted was changed by hand.
+		"old-folder": {1: "Admin"},
+	}}
+	kvStore := kvstore.NewFakeKVStore()
+	require.NoError(t, kvStore.Set(context.Background(), 1, ruleOwnershipNamespace, ruleOwnershipKey("team"),
+		`{"infra-folder":{"2":"Edit"},"old-folder":{"1":"Edit"}}`))
+
+	prov := NewRuleOwnershipProvisioner(log.NewNopLogger(), rules, teams, permissions, kvStore)
+	files := []*AlertingFile{{
+		RuleOwnership: []RuleOwnership{{OrgID: 1, Label: "team", Permission: "Edit"}},
+	}}
+	require.NoError(t, prov.Provision(context.Background(), files))
+
+	require.ElementsMatch(t, []setTeamPermission{
+		{TeamID: 2, FolderUID: "infra-folder", Permission: ""},
+		{TeamID: 1, FolderUID: "shared-folder", Permission: "Edit"},
+		{TeamID: 2, FolderUID: "shared-folder", Permission: "Edit"},
+	}, permissions.set)
+	granted, ok, err := kvStore.Get(context.Background(), 1, ruleOwnershipNamespace, ruleOwnershipKey("team"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.JSONEq(t, `{"shared-folder":{"1":"Edit","2":"Edit"}}`, granted)
+
+	t.Run("permissions granted by the sync are removed when the team no longer owns rules of the folder", func(t *testing.T) {
+		permissions.set = nil
+		rules.rules = []*alert_models.AlertRule{rule("shared-folder", "infra")}
+		require.NoError(t, prov.Provision(context.Background(), files))
+
+		require.Equal(t, []setTeamPermission{{TeamID: 2, FolderUID: "shared-folder", Permission: ""}}, permissions.set)
+		require.Equal(t, map[int64]string{1: "Edit"}, permissions.permissions["shared-folder"])
+		require.Equal(t, map[int64]string{2: "View"}, permissions.permissions["payments-folder"])
+	})
+}
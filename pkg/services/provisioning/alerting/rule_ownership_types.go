@@ -0,0 +1,53 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// defaultRuleOwnershipPermission is the folder permission that owner teams get if the policy does not set one.
+const defaultRuleOwnershipPermission = "Edit"
+
+var ruleOwnershipPermissions = []string{"View", "Edit", "Admin"}
+
+type RuleOwnershipV1 struct {
+	OrgID values.Int64Value `json:"orgId" yaml:"orgId"`
+	// Label is the label of the rules whose value is the name of the team that owns them.
+	Label      values.StringValue `json:"label" yaml:"label"`
+	Permission values.StringValue `json:"permission" yaml:"permission"`
+}
+
+func (v1 *RuleOwnershipV1) mapToModel() (RuleOwnership, error) {
+	orgID := v1.OrgID.Value()
+	if orgID < 1 {
+		orgID = 1
+	}
+	label := strings.TrimSpace(v1.Label.Value())
+	if label == "" {
+		return RuleOwnership{}, errors.New("rule ownership missing label")
+	}
+	permission := v1.Permission.Value()
+	if permission == "" {
+		permission = defaultRuleOwnershipPermission
+	}
+	if !slices.Contains(ruleOwnershipPermissions, permission) {
+		return RuleOwnership{}, fmt.Errorf("rule ownership has invalid permission '%s', should be one of %s", permission, strings.Join(ruleOwnershipPermissions, ", "))
+	}
+	return RuleOwnership{
+		OrgID:      orgID,
+		Label:      label,
+		Permission: permission,
+	}, nil
+}
+
+// RuleOwnership is a policy that gives the teams that own alert rules a permission on the folders of their rules. The
+// owner of a rule is the team whose name is the value of the label of the rule.
+type RuleOwnership struct {
+	OrgID      int64
+	Label      string
+	Permission string
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/metrics"
@@ -42,7 +43,7 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
 		for _, group := range file.Groups {
-			folderUID, err := prov.getOrCreateFolderUID(ctx, group.FolderTitle, group.OrgID)
+			folderUID, err := prov.getFolderUID(ctx, group)
 			if err != nil {
 				return err
 			}
@@ -54,12 +55,29 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 			for _, rule := range group.Rules {
 				rule.NamespaceUID = folderUID
 				rule.RuleGroup = group.Title
-				err = prov.provisionRule(ctx, group.OrgID, rule)
+				err = prov.provisionRule(ctx, group.OrgID, rule, group.BakePeriod)
 				if err != nil {
 					return err
 				}
 			}
-			err = prov.ruleService.UpdateRuleGroup(ctx, group.OrgID, folderUID, group.Title, group.Interval)
+			err = prov.ruleService.UpdateRuleGroup(ctx, nil, group.OrgID, folderUID, group.Title, group.Interval, alert_models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+			err = prov.ruleService.UpdateRuleGroupDataAvailability(ctx, nil, group.OrgID, folderUID, group.Title,
+				group.DataAvailabilityPeriod, group.DataAvailabilityDelay, alert_models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+			err = prov.ruleService.UpdateRuleGroupShardAffinity(ctx, nil, group.OrgID, folderUID, group.Title, group.ShardAffinity, alert_models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+			err = prov.ruleService.UpdateRuleGroupIncidentHooks(ctx, nil, group.OrgID, folderUID, group.Title, group.IncidentHooks, alert_models.ProvenanceFile)
+			if err != nil {
+				return err
+			}
+			err = prov.ruleService.UpdateRuleGroupManagedBy(ctx, nil, group.OrgID, folderUID, group.Title, group.ManagedBy, alert_models.ProvenanceFile)
 			if err != nil {
 				return err
 			}
@@ -78,13 +96,18 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 func (prov *defaultAlertRuleProvisioner) provisionRule(
 	ctx context.Context,
 	orgID int64,
-	rule alert_models.AlertRule) error {
+	rule alert_models.AlertRule,
+	bakePeriod time.Duration) error {
 	prov.logger.Debug("provisioning alert rule", "uid", rule.UID, "org", rule.OrgID)
 	_, _, err := prov.ruleService.GetAlertRule(ctx, orgID, rule.UID)
 	if err != nil && !errors.Is(err, alert_models.ErrAlertRuleNotFound) {
 		return err
 	} else if err != nil {
 		prov.logger.Debug("creating rule", "uid", rule.UID, "org", rule.OrgID)
+		if bakePeriod > 0 {
+			bakeUntil := time.Now().Add(bakePeriod)
+			rule.BakeUntil = &bakeUntil
+		}
 		// 0 is passed as userID as then the quota logic will only check for
 		// the organization quota, as we don't have any user scope here.
 		_, err = prov.ruleService.CreateAlertRule(ctx, rule, alert_models.ProvenanceFile, 0)
@@ -95,6 +118,77 @@ func (prov *defaultAlertRuleProvisioner) provisionRule(
 	return err
 }
 
+// getFolderUID returns the UID of the folder of the rule group. A group that refers to its folder by UID requires the
+// folder to exist and to have the title that the group declares, if any, so that folders whose titles drifted between
+// environments are not confused. The folders of the path of a group that refers to its folder by path are created if
+// they do not exist.
+func (prov *defaultAlertRuleProvisioner) getFolderUID(ctx context.Context, group alert_models.AlertRuleGroupWithFolderTitle) (string, error) {
+	if len(group.FolderPath) > 0 {
+		parentUID := ""
+		for _, title := range group.FolderPath {
+			uid, err := prov.getOrCreateNestedFolderUID(ctx, title, parentUID, group.OrgID)
+			if err != nil {
+				return "", err
+			}
+			parentUID = uid
+		}
+		return parentUID, nil
+	}
+	if group.FolderUID == "" {
+		return prov.getOrCreateFolderUID(ctx, group.FolderTitle, group.OrgID)
+	}
+	result, err := prov.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{
+		UID:   group.FolderUID,
+		OrgID: group.OrgID,
+	})
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return "", fmt.Errorf("folder with UID '%s' of rule group '%s' does not exist", group.FolderUID, group.Title)
+		}
+		return "", err
+	}
+	if !result.IsFolder {
+		return "", fmt.Errorf("got invalid response. expected folder, found dashboard")
+	}
+	if group.FolderTitle != "" && result.Title != group.FolderTitle {
+		return "", fmt.Errorf("folder with UID '%s' of rule group '%s' has title '%s', expected '%s'", group.FolderUID, group.Title, result.Title, group.FolderTitle)
+	}
+	return result.UID, nil
+}
+
+// getOrCreateNestedFolderUID returns the UID of the folder with the title in the parent folder, which is created if it
+// does not exist. An empty parentUID is the root folder.
+func (prov *defaultAlertRuleProvisioner) getOrCreateNestedFolderUID(
+	ctx context.Context, folderName string, parentUID string, orgID int64) (string, error) {
+	if parentUID == "" {
+		return prov.getOrCreateFolderUID(ctx, folderName, orgID)
+	}
+	result, err := prov.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{
+		Title:     &folderName,
+		FolderUID: &parentUID,
+		OrgID:     orgID,
+	})
+	if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return "", err
+	}
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		created, err := prov.dashboardProvService.SaveFolderForProvisionedDashboards(ctx, &folder.CreateFolderCommand{
+			OrgID:     orgID,
+			UID:       util.GenerateShortUID(),
+			Title:     folderName,
+			ParentUID: parentUID,
+		})
+		if err != nil {
+			return "", err
+		}
+		return created.UID, nil
+	}
+	if !result.IsFolder {
+		return "", fmt.Errorf("got invalid response. expected folder, found dashboard")
+	}
+	return result.UID, nil
+}
+
 func (prov *defaultAlertRuleProvisioner) getOrCreateFolderUID(
 	ctx context.Context, folderName string, orgID int64) (string, error) {
 	metrics.MFolderIDsServiceCount.WithLabelValues(metrics.Provisioning).Inc()
@@ -9,6 +9,7 @@ import (
 
 	"github.com/prometheus/common/model"
 
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/provisioning/values"
 	"github.com/grafana/grafana/pkg/util"
@@ -25,11 +26,41 @@ type RuleDeleteV1 struct {
 }
 
 type AlertRuleGroupV1 struct {
-	OrgID    values.Int64Value  `json:"orgId" yaml:"orgId"`
-	Name     values.StringValue `json:"name" yaml:"name"`
-	Folder   values.StringValue `json:"folder" yaml:"folder"`
-	Interval values.StringValue `json:"interval" yaml:"interval"`
-	Rules    []AlertRuleV1      `json:"rules" yaml:"rules"`
+	OrgID  values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name   values.StringValue `json:"name" yaml:"name"`
+	Folder values.StringValue `json:"folder" yaml:"folder"`
+	// FolderUID refers to the folder by UID instead of Folder. The folder must exist.
+	FolderUID values.StringValue `json:"folderUid" yaml:"folderUid"`
+	// FolderTitle is the title that the folder referred to by FolderUID must have, if set.
+	FolderTitle values.StringValue `json:"folderTitle" yaml:"folderTitle"`
+	// FolderPath refers to the folder by the titles of the nested folders from the root folder down to the folder,
+	// instead of Folder. The folders that do not exist are created.
+	FolderPath       []values.StringValue `json:"folderPath" yaml:"folderPath"`
+	Interval         values.StringValue   `json:"interval" yaml:"interval"`
+	DataAvailability *DataAvailabilityV1  `json:"dataAvailability,omitempty" yaml:"dataAvailability"`
+	ShardAffinity    values.StringValue   `json:"shardAffinity" yaml:"shardAffinity"`
+	// BakePeriod is the time during which the notifications of the rules that the file creates are suppressed.
+	BakePeriod    values.StringValue `json:"bakePeriod" yaml:"bakePeriod"`
+	IncidentHooks []IncidentHookV1   `json:"incidentHooks" yaml:"incidentHooks"`
+	// ManagedBy tells the users of the UI where the rule group is managed, e.g. the repository and the file.
+	ManagedBy *ManagedByV1  `json:"managedBy,omitempty" yaml:"managedBy"`
+	Rules     []AlertRuleV1 `json:"rules" yaml:"rules"`
+}
+
+type ManagedByV1 struct {
+	Title values.StringValue `json:"title" yaml:"title"`
+	URL   values.StringValue `json:"url" yaml:"url"`
+}
+
+type IncidentHookV1 struct {
+	Name                     values.StringValue `json:"name" yaml:"name"`
+	URL                      values.StringValue `json:"url" yaml:"url"`
+	AuthorizationCredentials values.StringValue `json:"authorizationCredentials" yaml:"authorizationCredentials"`
+}
+
+type DataAvailabilityV1 struct {
+	Period values.StringValue `json:"period" yaml:"period"`
+	Delay  values.StringValue `json:"delay" yaml:"delay"`
 }
 
 func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFolderTitle, error) {
@@ -42,13 +73,77 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 	if ruleGroup.OrgID < 1 {
 		ruleGroup.OrgID = 1
 	}
-	interval, err := model.ParseDuration(ruleGroupV1.Interval.Value())
+	interval, err := definitions.ParseDurationOrSeconds(ruleGroupV1.Interval.Value())
 	if err != nil {
 		return models.AlertRuleGroupWithFolderTitle{}, err
 	}
 	ruleGroup.Interval = int64(time.Duration(interval).Seconds())
+	if ruleGroupV1.DataAvailability != nil {
+		period, err := definitions.ParseDurationOrSeconds(ruleGroupV1.DataAvailability.Period.Value())
+		if err != nil {
+			return models.AlertRuleGroupWithFolderTitle{}, fmt.Errorf("invalid data availability period: %w", err)
+		}
+		delay, err := definitions.ParseDurationOrSeconds(ruleGroupV1.DataAvailability.Delay.Value())
+		if err != nil {
+			return models.AlertRuleGroupWithFolderTitle{}, fmt.Errorf("invalid data availability delay: %w", err)
+		}
+		if err := models.ValidateDataAvailability(time.Duration(period), time.Duration(delay)); err != nil {
+			return models.AlertRuleGroupWithFolderTitle{}, err
+		}
+		ruleGroup.DataAvailabilityPeriod = time.Duration(period)
+		ruleGroup.DataAvailabilityDelay = time.Duration(delay)
+	}
+	ruleGroup.ShardAffinity = ruleGroupV1.ShardAffinity.Value()
+	if err := models.ValidateShardAffinityName(ruleGroup.ShardAffinity); err != nil {
+		return models.AlertRuleGroupWithFolderTitle{}, err
+	}
+	if bakePeriod := ruleGroupV1.BakePeriod.Value(); bakePeriod != "" {
+		d, err := definitions.ParseDurationOrSeconds(bakePeriod)
+		if err != nil {
+			return models.AlertRuleGroupWithFolderTitle{}, fmt.Errorf("invalid bake period: %w", err)
+		}
+		ruleGroup.BakePeriod = time.Duration(d)
+	}
+	for _, hookV1 := range ruleGroupV1.IncidentHooks {
+		ruleGroup.IncidentHooks = append(ruleGroup.IncidentHooks, models.IncidentHook{
+			Name:                     hookV1.Name.Value(),
+			URL:                      hookV1.URL.Value(),
+			AuthorizationCredentials: hookV1.AuthorizationCredentials.Value(),
+		})
+	}
+	if err := models.ValidateIncidentHooks(ruleGroup.IncidentHooks); err != nil {
+		return models.AlertRuleGroupWithFolderTitle{}, err
+	}
+	if ruleGroupV1.ManagedBy != nil {
+		ruleGroup.ManagedBy = &models.ManagedBy{
+			Title: ruleGroupV1.ManagedBy.Title.Value(),
+			URL:   ruleGroupV1.ManagedBy.URL.Value(),
+		}
+		if err := models.ValidateManagedBy(ruleGroup.ManagedBy); err != nil {
+			return models.AlertRuleGroupWithFolderTitle{}, err
+		}
+	}
 	ruleGroup.FolderTitle = ruleGroupV1.Folder.Value()
-	if strings.TrimSpace(ruleGroup.FolderTitle) == "" {
+	if len(ruleGroupV1.FolderPath) > 0 {
+		if ruleGroup.FolderTitle != "" || ruleGroupV1.FolderUID.Value() != "" || ruleGroupV1.FolderTitle.Value() != "" {
+			return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group cannot have folderPath set together with folder, folderUid or folderTitle")
+		}
+		for _, title := range ruleGroupV1.FolderPath {
+			if strings.TrimSpace(title.Value()) == "" {
+				return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group has an empty title in its folderPath")
+			}
+			ruleGroup.FolderPath = append(ruleGroup.FolderPath, title.Value())
+		}
+		ruleGroup.FolderTitle = ruleGroup.FolderPath[len(ruleGroup.FolderPath)-1]
+	} else if folderUID := ruleGroupV1.FolderUID.Value(); folderUID != "" {
+		if ruleGroup.FolderTitle != "" {
+			return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group cannot have both folder and folderUid set")
+		}
+		ruleGroup.FolderUID = folderUID
+		ruleGroup.FolderTitle = ruleGroupV1.FolderTitle.Value()
+	} else if ruleGroupV1.FolderTitle.Value() != "" {
+		return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group can have folderTitle set only with folderUid")
+	} else if strings.TrimSpace(ruleGroup.FolderTitle) == "" {
 		return models.AlertRuleGroupWithFolderTitle{}, errors.New("rule group has no folder set")
 	}
 	for _, ruleV1 := range ruleGroupV1.Rules {
@@ -58,6 +153,9 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 		}
 		ruleGroup.Rules = append(ruleGroup.Rules, rule)
 	}
+	if err := models.SetRuleGroupIndexes(ruleGroup.Rules); err != nil {
+		return models.AlertRuleGroupWithFolderTitle{}, fmt.Errorf("rule group '%s' failed to parse: %w", ruleGroup.Title, err)
+	}
 	return ruleGroup, nil
 }
 
@@ -75,6 +173,16 @@ type AlertRuleV1 struct {
 	Labels               values.StringMapValue   `json:"labels" yaml:"labels"`
 	IsPaused             values.BoolValue        `json:"isPaused" yaml:"isPaused"`
 	NotificationSettings *NotificationSettingsV1 `json:"notification_settings" yaml:"notification_settings"`
+	// RuleGroupIndex is the position of the rule in its group. The rules are ordered as in the file if none has one.
+	RuleGroupIndex values.IntValue `json:"ruleGroupIndex" yaml:"ruleGroupIndex"`
+	// AdditionalNotificationSettings are the settings of the other receivers the alerts of the rule are sent to.
+	AdditionalNotificationSettings []NotificationSettingsV1 `json:"additional_notification_settings" yaml:"additional_notification_settings"`
+	// IntervalOverride is the interval of the rule in seconds instead of the one of its group, if it is set.
+	IntervalOverride values.Int64Value `json:"intervalOverride" yaml:"intervalOverride"`
+	// EvaluationTimeout is the timeout of the evaluation of the rule instead of the one of the server, if it is set.
+	EvaluationTimeout values.StringValue `json:"evaluationTimeout" yaml:"evaluationTimeout"`
+	// Record makes the rule a recording rule, which does not need a condition.
+	Record *RecordV1 `json:"record" yaml:"record"`
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -88,7 +196,7 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no UID set", alertRule.Title)
 	}
 	alertRule.OrgID = orgID
-	duration, err := model.ParseDuration(rule.For.Value())
+	duration, err := definitions.ParseDurationOrSeconds(rule.For.Value())
 	if err != nil {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
 	}
@@ -116,11 +224,15 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	}
 	alertRule.NoDataState = noDataState
 	alertRule.Condition = rule.Condition.Value()
-	if alertRule.Condition == "" {
+	if rule.Record != nil {
+		alertRule.Record = rule.Record.mapToModel()
+	}
+	if alertRule.Condition == "" && !alertRule.IsRecordingRule() {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no condition set", alertRule.Title)
 	}
 	alertRule.Annotations = rule.Annotations.Raw
 	alertRule.Labels = rule.Labels.Value()
+	alertRule.RuleGroupIndex = rule.RuleGroupIndex.Value()
 	for _, queryV1 := range rule.Data {
 		query, err := queryV1.mapToModel()
 		if err != nil {
@@ -132,6 +244,14 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	alertRule.IntervalOverrideSeconds = rule.IntervalOverride.Value()
+	if timeout := rule.EvaluationTimeout.Value(); timeout != "" {
+		d, err := definitions.ParseDurationOrSeconds(timeout)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse evaluation timeout: %w", alertRule.Title, err)
+		}
+		alertRule.EvaluationTimeout = time.Duration(d)
+	}
 	if rule.NotificationSettings != nil {
 		ns, err := rule.NotificationSettings.mapToModel()
 		if err != nil {
@@ -139,6 +259,21 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		}
 		alertRule.NotificationSettings = append(alertRule.NotificationSettings, ns)
 	}
+	if len(rule.AdditionalNotificationSettings) > 0 {
+		if rule.NotificationSettings == nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: additional notification settings require notification settings", alertRule.Title)
+		}
+		for _, nsV1 := range rule.AdditionalNotificationSettings {
+			ns, err := nsV1.mapToModel()
+			if err != nil {
+				return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+			}
+			alertRule.NotificationSettings = append(alertRule.NotificationSettings, ns)
+		}
+		if err := models.ValidateNotificationSettings(alertRule.NotificationSettings); err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+	}
 	return alertRule, nil
 }
 
@@ -173,12 +308,26 @@ func (queryV1 *QueryV1) mapToModel() (models.AlertQuery, error) {
 	return models.AlertQuery{
 		RefID:             queryV1.RefID.Value(),
 		QueryType:         queryV1.QueryType.Value(),
-		DatasourceUID:     queryV1.DatasourceUID.Value(),
+		DatasourceUID:     models.NormalizeDatasourceUID(queryV1.DatasourceUID.Value()),
 		RelativeTimeRange: queryV1.RelativeTimeRange,
 		Model:             rawMessage,
 	}, nil
 }
 
+type RecordV1 struct {
+	Metric              values.StringValue `json:"metric" yaml:"metric"`
+	From                values.StringValue `json:"from" yaml:"from"`
+	TargetDatasourceUID values.StringValue `json:"targetDatasourceUid" yaml:"targetDatasourceUid"`
+}
+
+func (recordV1 *RecordV1) mapToModel() models.Record {
+	return models.Record{
+		Metric:              recordV1.Metric.Value(),
+		From:                recordV1.From.Value(),
+		TargetDatasourceUID: recordV1.TargetDatasourceUID.Value(),
+	}
+}
+
 type NotificationSettingsV1 struct {
 	Receiver          values.StringValue   `json:"receiver" yaml:"receiver"`
 	GroupBy           []values.StringValue `json:"group_by,omitempty" yaml:"group_by"`
@@ -194,21 +343,21 @@ func (nsV1 *NotificationSettingsV1) mapToModel() (models.NotificationSettings, e
 	}
 	var gw, gi, ri *model.Duration
 	if nsV1.GroupWait.Value() != "" {
-		dur, err := model.ParseDuration(nsV1.GroupWait.Value())
+		dur, err := definitions.ParseDurationOrSeconds(nsV1.GroupWait.Value())
 		if err != nil {
 			return models.NotificationSettings{}, fmt.Errorf("failed to parse group wait: %w", err)
 		}
 		gw = util.Pointer(dur)
 	}
 	if nsV1.GroupInterval.Value() != "" {
-		dur, err := model.ParseDuration(nsV1.GroupInterval.Value())
+		dur, err := definitions.ParseDurationOrSeconds(nsV1.GroupInterval.Value())
 		if err != nil {
 			return models.NotificationSettings{}, fmt.Errorf("failed to parse group interval: %w", err)
 		}
 		gi = util.Pointer(dur)
 	}
 	if nsV1.RepeatInterval.Value() != "" {
-		dur, err := model.ParseDuration(nsV1.RepeatInterval.Value())
+		dur, err := definitions.ParseDurationOrSeconds(nsV1.RepeatInterval.Value())
 		if err != nil {
 			return models.NotificationSettings{}, fmt.Errorf("failed to parse repeat interval: %w", err)
 		}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/provisioning/values"
 	"github.com/grafana/grafana/pkg/util"
@@ -37,6 +38,74 @@ func TestRuleGroup(t *testing.T) {
 		_, err = rg.MapToModel()
 		require.Error(t, err)
 	})
+	t.Run("a rule group keyed by folder UID should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.Folder = values.StringValue{}
+		rg.FolderUID = stringToStringValue("folder-uid")
+		rg.FolderTitle = stringToStringValue("Folder")
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, "folder-uid", rgMapped.FolderUID)
+		require.Equal(t, "Folder", rgMapped.FolderTitle)
+	})
+	t.Run("a rule group should order its rules by their indexes", func(t *testing.T) {
+		index := func(s string) values.IntValue {
+			var v values.IntValue
+			require.NoError(t, yaml.Unmarshal([]byte(s), &v))
+			return v
+		}
+		rg := validRuleGroupV1(t)
+		rg.Rules = []AlertRuleV1{validRuleV1(t), validRuleV1(t)}
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, 1, rgMapped.Rules[0].RuleGroupIndex, "rules without indexes should be ordered as in the file")
+		require.Equal(t, 2, rgMapped.Rules[1].RuleGroupIndex)
+
+		rg.Rules[0].RuleGroupIndex = index("2")
+		rg.Rules[1].RuleGroupIndex = index("1")
+		rgMapped, err = rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, 2, rgMapped.Rules[0].RuleGroupIndex)
+		require.Equal(t, 1, rgMapped.Rules[1].RuleGroupIndex)
+
+		rg.Rules[0].RuleGroupIndex = index("3")
+		_, err = rg.MapToModel()
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+	t.Run("a rule group with both a folder and a folder UID should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.FolderUID = stringToStringValue("folder-uid")
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule group with a folder title but no folder UID should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.FolderTitle = stringToStringValue("Folder")
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule group keyed by folder path should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.Folder = values.StringValue{}
+		rg.FolderPath = []values.StringValue{stringToStringValue("Team"), stringToStringValue("Services")}
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, []string{"Team", "Services"}, rgMapped.FolderPath)
+		require.Equal(t, "Services", rgMapped.FolderTitle)
+	})
+	t.Run("a rule group with both a folder and a folder path should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.FolderPath = []values.StringValue{stringToStringValue("Team")}
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule group with an empty title in its folder path should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.Folder = values.StringValue{}
+		rg.FolderPath = []values.StringValue{stringToStringValue("Team"), {}}
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
 	t.Run("a rule group with out an interval should error", func(t *testing.T) {
 		rg := validRuleGroupV1(t)
 		var interval values.StringValue
@@ -65,6 +134,100 @@ func TestRuleGroup(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, int64(48*time.Hour/time.Second), rgMapped.Interval)
 	})
+	t.Run("a rule group with an interval in seconds should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		var interval values.StringValue
+		err := yaml.Unmarshal([]byte("90"), &interval)
+		require.NoError(t, err)
+		rg.Interval = interval
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, int64(90), rgMapped.Interval)
+	})
+	t.Run("a rule group with a data availability window should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.DataAvailability = &DataAvailabilityV1{}
+		require.NoError(t, yaml.Unmarshal([]byte("1h"), &rg.DataAvailability.Period))
+		require.NoError(t, yaml.Unmarshal([]byte("5m"), &rg.DataAvailability.Delay))
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, time.Hour, rgMapped.DataAvailabilityPeriod)
+		require.Equal(t, 5*time.Minute, rgMapped.DataAvailabilityDelay)
+	})
+	t.Run("a rule group with a data availability delay longer than the period should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		rg.DataAvailability = &DataAvailabilityV1{}
+		require.NoError(t, yaml.Unmarshal([]byte("5m"), &rg.DataAvailability.Period))
+		require.NoError(t, yaml.Unmarshal([]byte("1h"), &rg.DataAvailability.Delay))
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule group with a shard affinity should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("heavy"), &rg.ShardAffinity))
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, "heavy", rgMapped.ShardAffinity)
+	})
+	t.Run("a rule group with an invalid shard affinity should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("not a shard"), &rg.ShardAffinity))
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule group with a bake period should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("2h"), &rg.BakePeriod))
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Hour, rgMapped.BakePeriod)
+	})
+	t.Run("a rule group with an invalid bake period should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte("soon"), &rg.BakePeriod))
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule group with incident hooks should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte(`
+- name: tickets
+  url: https://incidents.example.com/api/alerts
+  authorizationCredentials: ref+vault://secret/data/alerting/incidents#token
+`), &rg.IncidentHooks))
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, []models.IncidentHook{{
+			Name:                     "tickets",
+			URL:                      "https://incidents.example.com/api/alerts",
+			AuthorizationCredentials: "ref+vault://secret/data/alerting/incidents#token",
+		}}, rgMapped.IncidentHooks)
+	})
+	t.Run("a rule group with an invalid incident hook should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte(`[{name: tickets, url: /api/alerts}]`), &rg.IncidentHooks))
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
+	t.Run("a rule group managed by a repository should work", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte(`
+title: Managed by repository alerting-rules, file teams/checkout.yaml
+url: https://github.com/example/alerting-rules/blob/main/teams/checkout.yaml
+`), &rg.ManagedBy))
+		rgMapped, err := rg.MapToModel()
+		require.NoError(t, err)
+		require.Equal(t, &models.ManagedBy{
+			Title: "Managed by repository alerting-rules, file teams/checkout.yaml",
+			URL:   "https://github.com/example/alerting-rules/blob/main/teams/checkout.yaml",
+		}, rgMapped.ManagedBy)
+	})
+	t.Run("a rule group managed by an invalid URL should error", func(t *testing.T) {
+		rg := validRuleGroupV1(t)
+		require.NoError(t, yaml.Unmarshal([]byte(`{title: alerting-rules, url: teams/checkout.yaml}`), &rg.ManagedBy))
+		_, err := rg.MapToModel()
+		require.Error(t, err)
+	})
 	t.Run("a rule group with an empty org id should default to 1", func(t *testing.T) {
 		rg := validRuleGroupV1(t)
 		rg.OrgID = values.Int64Value{}
@@ -127,12 +290,74 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule with a for duration in seconds should work", func(t *testing.T) {
+		rule := validRuleV1(t)
+		forDuration := values.StringValue{}
+		err := yaml.Unmarshal([]byte("300"), &forDuration)
+		rule.For = forDuration
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.For)
+	})
+	t.Run("a rule with a for duration with several units should work", func(t *testing.T) {
+		rule := validRuleV1(t)
+		forDuration := values.StringValue{}
+		err := yaml.Unmarshal([]byte("1h30m"), &forDuration)
+		rule.For = forDuration
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 90*time.Minute, ruleMapped.For)
+	})
+	t.Run("a rule with an interval override should work", func(t *testing.T) {
+		rule := validRuleV1(t)
+		err := yaml.Unmarshal([]byte("300"), &rule.IntervalOverride)
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, int64(300), ruleMapped.IntervalOverrideSeconds)
+	})
+	t.Run("a rule with an evaluation timeout should work", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.EvaluationTimeout = stringToStringValue("2m")
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Minute, ruleMapped.EvaluationTimeout)
+	})
+	t.Run("a rule with an invalid evaluation timeout should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.EvaluationTimeout = stringToStringValue("soon")
+		_, err := rule.mapToModel(1)
+		require.ErrorContains(t, err, "evaluation timeout")
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
 		_, err := rule.mapToModel(1)
 		require.Error(t, err)
 	})
+	t.Run("a recording rule without a condition should work", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		rule.Record = &RecordV1{
+			Metric:              stringToStringValue("disk_usage_ratio"),
+			From:                stringToStringValue("A"),
+			TargetDatasourceUID: stringToStringValue("prometheus"),
+		}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, models.Record{Metric: "disk_usage_ratio", From: "A", TargetDatasourceUID: "prometheus"}, ruleMapped.Record)
+	})
+	t.Run("a rule with a legacy expression data source UID should be normalized", func(t *testing.T) {
+		rule := validRuleV1(t)
+		var uid values.StringValue
+		require.NoError(t, yaml.Unmarshal([]byte("-100"), &uid))
+		rule.Data[0].DatasourceUID = uid
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, expr.DatasourceUID, ruleMapped.Data[0].DatasourceUID)
+	})
 	t.Run("a rule with out data should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Data = []QueryV1{}
@@ -199,6 +424,29 @@ func TestRules(t *testing.T) {
 		require.Len(t, ruleMapped.NotificationSettings, 1)
 		require.Equal(t, models.NotificationSettings{Receiver: "test-receiver"}, ruleMapped.NotificationSettings[0])
 	})
+	t.Run("a rule with additional notification settings should map all of them", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.NotificationSettings = &NotificationSettingsV1{
+			Receiver: stringToStringValue("test-receiver"),
+		}
+		rule.AdditionalNotificationSettings = []NotificationSettingsV1{
+			{Receiver: stringToStringValue("other-receiver")},
+		}
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []models.NotificationSettings{
+			{Receiver: "test-receiver"},
+			{Receiver: "other-receiver"},
+		}, ruleMapped.NotificationSettings)
+	})
+	t.Run("a rule with additional notification settings but no notification settings should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.AdditionalNotificationSettings = []NotificationSettingsV1{
+			{Receiver: stringToStringValue("other-receiver")},
+		}
+		_, err := rule.mapToModel(1)
+		require.Error(t, err)
+	})
 }
 
 func TestNotificationsSettingsV1MapToModel(t *testing.T) {
@@ -227,6 +475,21 @@ func TestNotificationsSettingsV1MapToModel(t *testing.T) {
 				MuteTimeIntervals: []string{"test-mute"},
 			},
 		},
+		{
+			name: "Durations in seconds",
+			input: NotificationSettingsV1{
+				Receiver:       stringToStringValue("test-receiver"),
+				GroupWait:      stringToStringValue("30"),
+				GroupInterval:  stringToStringValue("300"),
+				RepeatInterval: stringToStringValue("14400"),
+			},
+			expected: models.NotificationSettings{
+				Receiver:       "test-receiver",
+				GroupWait:      util.Pointer(model.Duration(30 * time.Second)),
+				GroupInterval:  util.Pointer(model.Duration(5 * time.Minute)),
+				RepeatInterval: util.Pointer(model.Duration(4 * time.Hour)),
+			},
+		},
 		{
 			name: "Skips empty elements in group_by",
 			input: NotificationSettingsV1{
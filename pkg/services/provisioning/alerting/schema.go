@@ -0,0 +1,220 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is the subset of JSON Schema that is needed to describe the format of alerting provisioning files.
+type JSONSchema struct {
+	Schema string `json:"$schema,omitempty"`
+	Title  string `json:"title,omitempty"`
+	// Type is either a single type or a list of types.
+	Type       any                    `json:"type,omitempty"`
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+	// AdditionalProperties is either a boolean or the schema of the values of the properties that are not listed.
+	AdditionalProperties any         `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema `json:"items,omitempty"`
+}
+
+var (
+	fileSchema     *JSONSchema
+	fileSchemaOnce sync.Once
+)
+
+// FileSchema returns the JSON Schema of alerting provisioning files. It is generated from the types the files are
+// decoded into, so that it cannot get out of sync with them. The returned schema must not be changed.
+func FileSchema() *JSONSchema {
+	fileSchemaOnce.Do(func() {
+		fileSchema = schemaOf(reflect.TypeOf(AlertingFileV1{}))
+		fileSchema.Schema = jsonSchemaDraft
+		fileSchema.Title = "Grafana alerting provisioning file"
+	})
+	return fileSchema
+}
+
+// scalarSchemas are the schemas of the types that decode their own values. Values can be strings with environment
+// variables to interpolate, so numbers and booleans are accepted as strings as well.
+var scalarSchemas = map[reflect.Type]*JSONSchema{
+	reflect.TypeOf(values.StringValue{}): {Type: "string"},
+	reflect.TypeOf(values.IntValue{}):    {Type: []string{"integer", "string"}},
+	reflect.TypeOf(values.Int64Value{}):  {Type: []string{"integer", "string"}},
+	reflect.TypeOf(values.BoolValue{}):   {Type: []string{"boolean", "string"}},
+	reflect.TypeOf(values.JSONValue{}):   {Type: "object", AdditionalProperties: true},
+	reflect.TypeOf(values.StringMapValue{}): {
+		Type:                 "object",
+		AdditionalProperties: &JSONSchema{Type: "string"},
+	},
+	reflect.TypeOf(values.JSONSliceValue{}): {
+		Type:  "array",
+		Items: &JSONSchema{Type: "object", AdditionalProperties: true},
+	},
+	// The policy tree is decoded as free-form JSON next to the organization.
+	reflect.TypeOf(NotificiationPolicyV1{}): {
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"orgId": {Type: []string{"integer", "string"}},
+		},
+		AdditionalProperties: true,
+	},
+}
+
+// schemaOf returns the schema of the values that YAML decodes into the type.
+func schemaOf(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if s, ok := scalarSchemas[t]; ok {
+		return s
+	}
+	ptr := reflect.PointerTo(t)
+	if _, ok := ptr.MethodByName("UnmarshalYAML"); ok {
+		// The type decodes its value itself, its format cannot be inferred.
+		return &JSONSchema{}
+	}
+	if _, ok := ptr.MethodByName("UnmarshalText"); ok {
+		return &JSONSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"}
+		}
+		return &JSONSchema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaOf(t.Elem())}
+	case reflect.Struct:
+		s := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}, AdditionalProperties: false}
+		addStructProperties(s, t)
+		return s
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// addStructProperties adds the fields of the struct to the properties of the schema, following the rules of the YAML
+// decoder for field names and inlined structs.
+func addStructProperties(s *JSONSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if opts == "inline" {
+			addStructProperties(s, field.Type)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		s.Properties[name] = schemaOf(field.Type)
+	}
+}
+
+// Validate checks that the value, as decoded from YAML or JSON into generic maps and slices, has the format described
+// by the schema. Only the structure is checked: it reports unknown fields and values that are not objects or arrays
+// where they are expected, with the path of the value.
+func (s *JSONSchema) Validate(value any) error {
+	return errors.Join(s.validate("$", value)...)
+}
+
+func (s *JSONSchema) validate(path string, value any) []error {
+	if value == nil {
+		return nil
+	}
+	switch {
+	case s.hasType("object"):
+		return s.validateObject(path, value)
+	case s.hasType("array"):
+		items, ok := value.([]any)
+		if !ok {
+			return []error{fmt.Errorf("expected an array at path '%s'", path)}
+		}
+		if s.Items == nil {
+			return nil
+		}
+		var errs []error
+		for i, item := range items {
+			errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+		}
+		return errs
+	case s.Type != nil:
+		switch value.(type) {
+		case map[string]any, map[any]any, []any:
+			return []error{fmt.Errorf("expected a value of type %v at path '%s'", s.Type, path)}
+		}
+	}
+	return nil
+}
+
+func (s *JSONSchema) validateObject(path string, value any) []error {
+	fields := map[string]any{}
+	switch v := value.(type) {
+	case map[string]any:
+		fields = v
+	case map[any]any:
+		for key, field := range v {
+			fields[fmt.Sprint(key)] = field
+		}
+	default:
+		return []error{fmt.Errorf("expected an object at path '%s'", path)}
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		fieldPath := path + "." + key
+		if property, ok := s.Properties[key]; ok {
+			errs = append(errs, property.validate(fieldPath, fields[key])...)
+			continue
+		}
+		switch additional := s.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				errs = append(errs, fmt.Errorf("unknown field '%s' at path '%s'", key, path))
+			}
+		case *JSONSchema:
+			errs = append(errs, additional.validate(fieldPath, fields[key])...)
+		}
+	}
+	return errs
+}
+
+func (s *JSONSchema) hasType(typ string) bool {
+	switch t := s.Type.(type) {
+	case string:
+		return t == typ
+	case []string:
+		return slices.Contains(t, typ)
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFileSchema(t *testing.T) {
+	schema := FileSchema()
+
+	t.Run("describes the fields of the file", func(t *testing.T) {
+		require.Contains(t, schema.Properties, "apiVersion")
+		require.NotContains(t, schema.Properties, "filename")
+		rule := schema.Properties["groups"].Items.Properties["rules"].Items
+		require.Contains(t, rule.Properties, "dashboardUid")
+		require.Equal(t, "string", rule.Properties["title"].Type)
+		require.Equal(t, []string{"boolean", "string"}, rule.Properties["isPaused"].Type)
+		require.Contains(t, schema.Properties["templates"].Items.Properties, "template")
+	})
+
+	testCases := []struct {
+		name     string
+		file     string
+		expected []string
+	}{
+		{
+			name: "valid file",
+			file: `
+apiVersion: 1
+groups:
+  - name: group
+    interval: 1m
+    rules:
+      - uid: rule
+        labels:
+          team: infra
+        data:
+          - refId: A
+            model:
+              anything: true
+policies:
+  - orgId: 1
+    receiver: default
+    routes: []
+`,
+		},
+		{
+			name: "unknown fields",
+			file: `
+apiVersion: 1
+contactPoint: []
+groups:
+  - name: group
+    rules:
+      - uid: rule
+        labels:
+          team: infra
+        data:
+          - refId: A
+            datasourceUID: prometheus
+`,
+			expected: []string{
+				"unknown field 'contactPoint' at path '$'",
+				"unknown field 'datasourceUID' at path '$.groups[0].rules[0].data[0]'",
+			},
+		},
+		{
+			name: "unexpected structure",
+			file: `
+groups:
+  name: group
+templates:
+  - name: [a, b]
+`,
+			expected: []string{
+				"expected an array at path '$.groups'",
+				"expected a value of type string at path '$.templates[0].name'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var raw any
+			require.NoError(t, yaml.Unmarshal([]byte(tc.file), &raw))
+
+			err := schema.Validate(raw)
+			if len(tc.expected) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			for _, expected := range tc.expected {
+				require.ErrorContains(t, err, expected)
+			}
+		})
+	}
+}
@@ -15,32 +15,38 @@ type OrgID int64
 
 type AlertingFile struct {
 	configVersion
-	Filename            string
-	Groups              []models.AlertRuleGroupWithFolderTitle
-	DeleteRules         []RuleDelete
-	ContactPoints       []ContactPoint
-	DeleteContactPoints []DeleteContactPoint
-	Policies            []NotificiationPolicy
-	ResetPolicies       []OrgID
-	MuteTimes           []MuteTime
-	DeleteMuteTimes     []DeleteMuteTime
-	Templates           []Template
-	DeleteTemplates     []DeleteTemplate
+	Filename                 string
+	Groups                   []models.AlertRuleGroupWithFolderTitle
+	DeleteRules              []RuleDelete
+	ContactPoints            []ContactPoint
+	DeleteContactPoints      []DeleteContactPoint
+	Policies                 []NotificiationPolicy
+	ResetPolicies            []OrgID
+	MuteTimes                []MuteTime
+	DeleteMuteTimes          []DeleteMuteTime
+	Templates                []Template
+	DeleteTemplates          []DeleteTemplate
+	AlertmanagerRouting      []AlertmanagerRouting
+	ResetAlertmanagerRouting []OrgID
+	RuleOwnership            []RuleOwnership
 }
 
 type AlertingFileV1 struct {
-	configVersion
-	Filename            string
-	Groups              []AlertRuleGroupV1      `json:"groups" yaml:"groups"`
-	DeleteRules         []RuleDeleteV1          `json:"deleteRules" yaml:"deleteRules"`
-	ContactPoints       []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
-	DeleteContactPoints []DeleteContactPointV1  `json:"deleteContactPoints" yaml:"deleteContactPoints"`
-	Policies            []NotificiationPolicyV1 `json:"policies" yaml:"policies"`
-	ResetPolicies       []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
-	MuteTimes           []MuteTimeV1            `json:"muteTimes" yaml:"muteTimes"`
-	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
-	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
-	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	configVersion            `yaml:",inline"`
+	Filename                 string                  `json:"-" yaml:"-"`
+	Groups                   []AlertRuleGroupV1      `json:"groups" yaml:"groups"`
+	DeleteRules              []RuleDeleteV1          `json:"deleteRules" yaml:"deleteRules"`
+	ContactPoints            []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
+	DeleteContactPoints      []DeleteContactPointV1  `json:"deleteContactPoints" yaml:"deleteContactPoints"`
+	Policies                 []NotificiationPolicyV1 `json:"policies" yaml:"policies"`
+	ResetPolicies            []values.Int64Value     `json:"resetPolicies" yaml:"resetPolicies"`
+	MuteTimes                []MuteTimeV1            `json:"muteTimes" yaml:"muteTimes"`
+	DeleteMuteTimes          []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
+	Templates                []TemplateV1            `json:"templates" yaml:"templates"`
+	DeleteTemplates          []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	AlertmanagerRouting      []AlertmanagerRoutingV1 `json:"alertmanagerRouting" yaml:"alertmanagerRouting"`
+	ResetAlertmanagerRouting []values.Int64Value     `json:"resetAlertmanagerRouting" yaml:"resetAlertmanagerRouting"`
+	RuleOwnership            []RuleOwnershipV1       `json:"ruleOwnership" yaml:"ruleOwnership"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
@@ -61,9 +67,33 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	fileV1.mapAlertmanagerRouting(&alertingFile)
+	if err := fileV1.mapRuleOwnership(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing rule ownership: %w", err)
+	}
 	return alertingFile, nil
 }
 
+func (fileV1 *AlertingFileV1) mapRuleOwnership(alertingFile *AlertingFile) error {
+	for _, ownershipV1 := range fileV1.RuleOwnership {
+		ownership, err := ownershipV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.RuleOwnership = append(alertingFile.RuleOwnership, ownership)
+	}
+	return nil
+}
+
+func (fileV1 *AlertingFileV1) mapAlertmanagerRouting(alertingFile *AlertingFile) {
+	for _, routingV1 := range fileV1.AlertmanagerRouting {
+		alertingFile.AlertmanagerRouting = append(alertingFile.AlertmanagerRouting, routingV1.mapToModel())
+	}
+	for _, orgIDV1 := range fileV1.ResetAlertmanagerRouting {
+		alertingFile.ResetAlertmanagerRouting = append(alertingFile.ResetAlertmanagerRouting, OrgID(orgIDV1.Value()))
+	}
+}
+
 func (fileV1 *AlertingFileV1) mapTemplates(alertingFile *AlertingFile) error {
 	for _, ttV1 := range fileV1.Templates {
 		alertingFile.Templates = append(alertingFile.Templates, ttV1.mapToModel())
@@ -120,6 +150,17 @@ func (fileV1 *AlertingFileV1) mapContactPoint(alertingFile *AlertingFile) error
 	return nil
 }
 
+// ruleUIDs returns the UIDs of the rules of the rule groups of the file.
+func (fileV1 *AlertingFileV1) ruleUIDs() []string {
+	var uids []string
+	for _, groupV1 := range fileV1.Groups {
+		for _, ruleV1 := range groupV1.Rules {
+			uids = append(uids, ruleV1.UID.Value())
+		}
+	}
+	return uids
+}
+
 func (fileV1 *AlertingFileV1) mapRules(alertingFile *AlertingFile) error {
 	for _, groupV1 := range fileV1.Groups {
 		group, err := groupV1.MapToModel()
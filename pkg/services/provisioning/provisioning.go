@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -31,6 +32,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/team"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -52,6 +54,8 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	teamService team.Service,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -77,6 +81,8 @@ func ProvideService(
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
 		folderService:                folderService,
+		teamService:                  teamService,
+		folderPermissionsService:     folderPermissionsService,
 	}
 	return s, nil
 }
@@ -148,6 +154,8 @@ type ProvisioningServiceImpl struct {
 	quotaService                 quota.Service
 	secretService                secrets.Service
 	folderService                folder.Service
+	teamService                  team.Service
+	folderPermissionsService     accesscontrol.FolderPermissionsService
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -272,23 +280,29 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		FolderService:    nil, // we don't use it yet
 		DashboardService: ps.dashboardService,
 	}
-	ruleService := provisioning.NewAlertRuleService(
-		st,
-		st,
-		ps.dashboardService,
-		ps.quotaService,
-		ps.SQLStore,
-		int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
-		int64(ps.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
-		ps.Cfg.UnifiedAlerting.RulesPerRuleGroupLimit,
-		ps.log, notifier.NewCachedNotificationSettingsValidationService(&st))
+	ruleService, err := provisioning.NewAlertRuleService(provisioning.AlertRuleServiceConfig{
+		RuleStore:            st,
+		ProvenanceStore:      st,
+		DashboardService:     ps.dashboardService,
+		Quotas:               ps.quotaService,
+		Xact:                 &st,
+		NotificationSettings: notifier.NewCachedNotificationSettingsValidationService(&st),
+		Log:                  ps.log,
+		KV:                   kvstore.ProvideService(ps.SQLStore),
+		TrashStore:           st,
+		Settings:             ps.Cfg.UnifiedAlerting,
+	})
+	if err != nil {
+		return err
+	}
 	receiverSvc := notifier.NewReceiverService(ps.ac, &st, st, ps.secretService, ps.SQLStore, ps.log)
 	contactPointService := provisioning.NewContactPointService(&st, ps.secretService,
-		st, ps.SQLStore, receiverSvc, ps.log, &st)
+		st, &st, receiverSvc, ps.log, &st, st, ps.Cfg.UnifiedAlerting.SecretReferences)
 	notificationPolicyService := provisioning.NewNotificationPolicyService(&st,
-		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
+		st, &st, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
 	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
+	alertmanagerRoutingService := provisioning.NewAlertmanagerRoutingService(&st, st, &st, ps.log)
 	cfg := prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
@@ -298,6 +312,11 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
+		AlertmanagerRoutingService: *alertmanagerRoutingService,
+		TeamService:                ps.teamService,
+		FolderPermissionsService:   ps.folderPermissionsService,
+		KVStore:                    kvstore.ProvideService(ps.SQLStore),
+		StrictDecoding:             ps.Cfg.UnifiedAlerting.ProvisioningStrictDecoding,
 	}
 	return ps.provisionAlerting(ctx, cfg)
 }
@@ -118,7 +118,46 @@ func (oss *OSSMigrations) AddMigration(mg *Migrator) {
 
 	ualert.AddRuleNotificationSettingsColumns(mg)
 
+	ualert.AddProvenanceMetadataColumn(mg)
+
 	accesscontrol.AddAlertingScopeRemovalMigration(mg)
+
+	ualert.AddExternalAlertmanagerMatchersColumn(mg)
+
+	ualert.AddConfigSnapshotMigrations(mg)
+
+	ualert.AddProvisioningTagMigrations(mg)
+
+	ualert.AddRuleDataAvailabilityColumns(mg)
+
+	ualert.AddRuleShardAffinityColumn(mg)
+
+	ualert.AddRuleBakeUntilColumn(mg)
+
+	ualert.AddRuleIncidentHooksColumns(mg)
+
+	ualert.AddFolderAnnotationMigrations(mg)
+
+	ualert.AddRuleExpiresAtColumn(mg)
+
+	ualert.AddRuleLabelIndexMigrations(mg)
+
+	ualert.AddFolderEvaluationPauseMigrations(mg)
+
+	ualert.AddAlertRuleTrashMigrations(mg)
+
+	ualert.AddRuleIntervalOverrideColumn(mg)
+
+	ualert.AddRuleGroupArchiveMigrations(mg)
+	ualert.AddRuleGroupLockMigrations(mg)
+
+	ualert.AddAlertRuleTemplateMigrations(mg)
+
+	ualert.AddRuleEvaluationTimeoutColumn(mg)
+
+	ualert.AddRuleRecordColumn(mg)
+
+	ualert.AddRuleGroupManagedByMigrations(mg)
 }
 
 func addStarMigrations(mg *Migrator) {
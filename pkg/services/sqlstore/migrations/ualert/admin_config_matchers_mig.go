@@ -0,0 +1,15 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddExternalAlertmanagerMatchersColumn adds the column that stores the matchers of the alerts that are sent only to the
+// external Alertmanagers.
+func AddExternalAlertmanagerMatchersColumn(mg *migrator.Migrator) {
+	mg.AddMigration("add external_alertmanager_matchers column to ngalert_configuration table", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name:     "external_alertmanager_matchers",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
@@ -0,0 +1,32 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddAlertRuleTrashMigrations creates the table that stores the deleted alert rules until they are restored or
+// cleaned up.
+func AddAlertRuleTrashMigrations(mg *migrator.Migrator) {
+	trashTable := migrator.Table{
+		Name: "alert_rule_trash",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "provenance", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "deleted_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "data", Type: migrator.DB_LongText, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid"}, Type: migrator.IndexType},
+			{Cols: []string{"deleted_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_trash table", migrator.NewAddTableMigration(trashTable))
+	mg.AddMigration("add index in alert_rule_trash on org_id and rule_uid columns", migrator.NewAddIndexMigration(trashTable, trashTable.Indices[0]))
+	mg.AddMigration("add index in alert_rule_trash on deleted_at column", migrator.NewAddIndexMigration(trashTable, trashTable.Indices[1]))
+}
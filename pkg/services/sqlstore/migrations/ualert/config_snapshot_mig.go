@@ -0,0 +1,28 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddConfigSnapshotMigrations creates the table that stores the snapshots of the alerting configuration of the
+// organizations.
+func AddConfigSnapshotMigrations(mg *migrator.Migrator) {
+	snapshotTable := migrator.Table{
+		Name: "alert_configuration_snapshot",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "version", Type: migrator.DB_Int, Nullable: false},
+			{Name: "hash", Type: migrator.DB_NVarchar, Length: 64, Nullable: false},
+			{Name: "size", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "created_at", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "data", Type: migrator.DB_LongBlob, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created_at"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_configuration_snapshot table", migrator.NewAddTableMigration(snapshotTable))
+	mg.AddMigration("add index in alert_configuration_snapshot on org_id and created_at columns", migrator.NewAddIndexMigration(snapshotTable, snapshotTable.Indices[0]))
+}
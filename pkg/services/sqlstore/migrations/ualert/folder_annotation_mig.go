@@ -0,0 +1,25 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddFolderAnnotationMigrations creates the table that stores the default annotations of the alert rules of folders.
+func AddFolderAnnotationMigrations(mg *migrator.Migrator) {
+	annotationTable := migrator.Table{
+		Name: "alert_folder_annotation",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "annotation_key", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "annotation_value", Type: migrator.DB_Text, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "folder_uid", "annotation_key"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_folder_annotation table", migrator.NewAddTableMigration(annotationTable))
+	mg.AddMigration("add unique index in alert_folder_annotation on org_id, folder_uid and annotation_key columns", migrator.NewAddIndexMigration(annotationTable, annotationTable.Indices[0]))
+}
@@ -0,0 +1,23 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddFolderEvaluationPauseMigrations creates the table that stores the folders whose alert rules are not evaluated.
+func AddFolderEvaluationPauseMigrations(mg *migrator.Migrator) {
+	pauseTable := migrator.Table{
+		Name: "alert_folder_evaluation_pause",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "folder_uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_folder_evaluation_pause table", migrator.NewAddTableMigration(pauseTable))
+	mg.AddMigration("add unique index in alert_folder_evaluation_pause on org_id and folder_uid columns", migrator.NewAddIndexMigration(pauseTable, pauseTable.Indices[0]))
+}
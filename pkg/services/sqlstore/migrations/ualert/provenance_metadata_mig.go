@@ -0,0 +1,14 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddProvenanceMetadataColumn adds a column to store optional metadata about the source of provisioned resources.
+func AddProvenanceMetadataColumn(mg *migrator.Migrator) {
+	mg.AddMigration("add metadata column to provenance_type table", migrator.NewAddColumnMigration(migrator.Table{Name: "provenance_type"}, &migrator.Column{
+		Name:     "metadata",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
@@ -0,0 +1,32 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddProvisioningTagMigrations creates the table that stores the tags of provisioned resources.
+func AddProvisioningTagMigrations(mg *migrator.Migrator) {
+	tagTable := migrator.Table{
+		Name: "provisioning_tag",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "record_type", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "record_key", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "tag", Type: migrator.DB_NVarchar, Length: 100, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "record_type", "record_key", "tag"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "record_type", "tag"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create provisioning_tag table", migrator.NewAddTableMigration(tagTable))
+	mg.AddMigration("add unique index in provisioning_tag on org_id, record_type, record_key and tag columns", migrator.NewAddIndexMigration(tagTable, tagTable.Indices[0]))
+	mg.AddMigration("add index in provisioning_tag on org_id, record_type and tag columns", migrator.NewAddIndexMigration(tagTable, tagTable.Indices[1]))
+
+	// The key of a rule group is the UID of its folder and its title, which can be longer than 190 characters.
+	mg.AddMigration("increase provisioning_tag.record_key length to 255", migrator.NewRawSQLMigration("").
+		Mysql("ALTER TABLE provisioning_tag MODIFY record_key VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL;").
+		Postgres("ALTER TABLE provisioning_tag ALTER COLUMN record_key TYPE VARCHAR(255);"))
+}
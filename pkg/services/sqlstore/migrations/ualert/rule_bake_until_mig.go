@@ -0,0 +1,15 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleBakeUntilColumn creates the bake_until column in the alert_rule table. It is not versioned because it is set
+// only when a rule is created.
+func AddRuleBakeUntilColumn(mg *migrator.Migrator) {
+	mg.AddMigration("add bake_until column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "bake_until",
+		Type:     migrator.DB_DateTime,
+		Nullable: true,
+	}))
+}
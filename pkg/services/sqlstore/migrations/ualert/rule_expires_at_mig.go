@@ -0,0 +1,20 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleExpiresAtColumn creates the expires_at column in the alert_rule and alert_rule_version tables.
+func AddRuleExpiresAtColumn(mg *migrator.Migrator) {
+	mg.AddMigration("add expires_at column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "expires_at",
+		Type:     migrator.DB_DateTime,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add expires_at column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "expires_at",
+		Type:     migrator.DB_DateTime,
+		Nullable: true,
+	}))
+}
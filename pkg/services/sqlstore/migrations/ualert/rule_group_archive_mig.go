@@ -0,0 +1,25 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleGroupArchiveMigrations creates the table that stores the archived rule groups, whose alert rules are kept but
+// not evaluated.
+func AddRuleGroupArchiveMigrations(mg *migrator.Migrator) {
+	archiveTable := migrator.Table{
+		Name: "alert_rule_group_archive",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "namespace_uid", "rule_group"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_group_archive table", migrator.NewAddTableMigration(archiveTable))
+	mg.AddMigration("add unique index in alert_rule_group_archive on org_id, namespace_uid and rule_group columns", migrator.NewAddIndexMigration(archiveTable, archiveTable.Indices[0]))
+}
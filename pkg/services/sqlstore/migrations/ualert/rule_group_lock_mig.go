@@ -0,0 +1,26 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleGroupLockMigrations creates the table whose rows are locked by the transactions that change a rule group, so
+// that concurrent changes of the same group are applied one after the other.
+func AddRuleGroupLockMigrations(mg *migrator.Migrator) {
+	lockTable := migrator.Table{
+		Name: "alert_rule_group_lock",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "locked_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "namespace_uid", "rule_group"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_group_lock table", migrator.NewAddTableMigration(lockTable))
+	mg.AddMigration("add unique index in alert_rule_group_lock on org_id, namespace_uid and rule_group columns", migrator.NewAddIndexMigration(lockTable, lockTable.Indices[0]))
+}
@@ -0,0 +1,27 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleGroupManagedByMigrations creates the table that stores who manages the provisioned rule groups, so that the UI
+// can link the users to the source of a group.
+func AddRuleGroupManagedByMigrations(mg *migrator.Migrator) {
+	managedByTable := migrator.Table{
+		Name: "alert_rule_group_managed_by",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "namespace_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: 190, Nullable: false},
+			{Name: "url", Type: migrator.DB_Text, Nullable: true},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "namespace_uid", "rule_group"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_group_managed_by table", migrator.NewAddTableMigration(managedByTable))
+	mg.AddMigration("add unique index in alert_rule_group_managed_by on org_id, namespace_uid and rule_group columns", migrator.NewAddIndexMigration(managedByTable, managedByTable.Indices[0]))
+}
@@ -0,0 +1,21 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleIncidentHooksColumns creates a column for the incident hooks of the rule group in the alert_rule and
+// alert_rule_version tables.
+func AddRuleIncidentHooksColumns(mg *migrator.Migrator) {
+	mg.AddMigration("add incident_hooks column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "incident_hooks",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+
+	mg.AddMigration("add incident_hooks column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "incident_hooks",
+		Type:     migrator.DB_Text,
+		Nullable: true,
+	}))
+}
@@ -0,0 +1,23 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleIntervalOverrideColumn creates the interval_override_seconds column in the alert_rule and alert_rule_version
+// tables.
+func AddRuleIntervalOverrideColumn(mg *migrator.Migrator) {
+	mg.AddMigration("add interval_override_seconds column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "interval_override_seconds",
+		Type:     migrator.DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
+
+	mg.AddMigration("add interval_override_seconds column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "interval_override_seconds",
+		Type:     migrator.DB_BigInt,
+		Nullable: false,
+		Default:  "0",
+	}))
+}
@@ -0,0 +1,80 @@
+package ualert
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleLabelIndexMigrations creates the alert_rule_label table, which indexes the labels of alert rules so that
+// rules can be filtered by labels in the database, and fills it with the labels of the existing rules.
+func AddRuleLabelIndexMigrations(mg *migrator.Migrator) {
+	labelTable := migrator.Table{
+		Name: "alert_rule_label",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "label_name", Type: migrator.DB_NVarchar, Length: ngmodels.RuleLabelNameMaxLength, Nullable: false},
+			{Name: "label_value", Type: migrator.DB_Text, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "label_name"}, Type: migrator.UniqueIndex},
+			{Cols: []string{"org_id", "label_name"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_label table", migrator.NewAddTableMigration(labelTable))
+	mg.AddMigration("add unique index in alert_rule_label on org_id, rule_uid and label_name columns", migrator.NewAddIndexMigration(labelTable, labelTable.Indices[0]))
+	mg.AddMigration("add index in alert_rule_label on org_id and label_name columns", migrator.NewAddIndexMigration(labelTable, labelTable.Indices[1]))
+	mg.AddMigration("fill alert_rule_label table with the labels of alert rules", &fillRuleLabelIndex{})
+}
+
+type fillRuleLabelIndex struct {
+	migrator.MigrationBase
+}
+
+func (c fillRuleLabelIndex) SQL(migrator.Dialect) string {
+	return codeMigration
+}
+
+type ruleLabels struct {
+	OrgID  int64             `xorm:"org_id"`
+	UID    string            `xorm:"uid"`
+	Labels map[string]string `xorm:"labels"`
+}
+
+type ruleLabel struct {
+	OrgID   int64  `xorm:"org_id"`
+	RuleUID string `xorm:"rule_uid"`
+	Name    string `xorm:"label_name"`
+	Value   string `xorm:"label_value"`
+}
+
+func (c fillRuleLabelIndex) Exec(sess *xorm.Session, mg *migrator.Migrator) error {
+	var rules []ruleLabels
+	if err := sess.Table("alert_rule").Cols("org_id", "uid", "labels").Find(&rules); err != nil {
+		return fmt.Errorf("failed to read the labels of alert rules: %w", err)
+	}
+	for _, rule := range rules {
+		records := make([]ruleLabel, 0, len(rule.Labels))
+		for name, value := range rule.Labels {
+			// Labels with an empty value are the same as missing labels, and long names are not indexed.
+			if value == "" || len(name) > ngmodels.RuleLabelNameMaxLength {
+				continue
+			}
+			records = append(records, ruleLabel{OrgID: rule.OrgID, RuleUID: rule.UID, Name: name, Value: value})
+		}
+		if len(records) == 0 {
+			continue
+		}
+		if _, err := sess.Table("alert_rule_label").Insert(records); err != nil {
+			return fmt.Errorf("failed to index the labels of alert rule %s: %w", rule.UID, err)
+		}
+	}
+	mg.Logger.Debug("Indexed the labels of alert rules", "count", len(rules))
+	return nil
+}
@@ -0,0 +1,24 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddRuleShardAffinityColumn creates the shard_affinity column in the alert_rule and alert_rule_version tables.
+func AddRuleShardAffinityColumn(mg *migrator.Migrator) {
+	mg.AddMigration("add shard_affinity column to alert_rule table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name:     "shard_affinity",
+		Type:     migrator.DB_NVarchar,
+		Length:   40,
+		Nullable: false,
+		Default:  "''",
+	}))
+
+	mg.AddMigration("add shard_affinity column to alert_rule_version table", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name:     "shard_affinity",
+		Type:     migrator.DB_NVarchar,
+		Length:   40,
+		Nullable: false,
+		Default:  "''",
+	}))
+}
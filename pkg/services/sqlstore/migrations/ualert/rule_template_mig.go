@@ -0,0 +1,26 @@
+package ualert
+
+import (
+	"github.com/grafana/grafana/pkg/services/sqlstore/migrator"
+)
+
+// AddAlertRuleTemplateMigrations creates the table that stores the templates from which alert rules are instantiated.
+func AddAlertRuleTemplateMigrations(mg *migrator.Migrator) {
+	templateTable := migrator.Table{
+		Name: "alert_rule_template",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+			{Name: "data", Type: migrator.DB_LongText, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_rule_template table", migrator.NewAddTableMigration(templateTable))
+	mg.AddMigration("add unique index in alert_rule_template on org_id and uid columns", migrator.NewAddIndexMigration(templateTable, templateTable.Indices[0]))
+}
@@ -72,6 +72,9 @@ type Dialect interface {
 	IsUniqueConstraintViolation(err error) bool
 	ErrorMessage(err error) string
 	IsDeadlock(err error) bool
+	// IsLockTimeout returns true if the statement failed because it waited too long for a lock held by another
+	// transaction.
+	IsLockTimeout(err error) bool
 	Lock(LockCfg) error
 	Unlock(LockCfg) error
 
@@ -154,6 +154,10 @@ func (db *SQLite3) IsDeadlock(err error) bool {
 	return false // No deadlock
 }
 
+func (db *SQLite3) IsLockTimeout(err error) bool {
+	return false // Writes are serialized, and the transactions that wait for them are retried
+}
+
 // UpsertSQL returns the upsert sql statement for SQLite dialect
 func (db *SQLite3) UpsertSQL(tableName string, keyCols, updateCols []string) string {
 	str, _ := db.UpsertMultipleSQL(tableName, keyCols, updateCols, 1)
@@ -26,6 +26,7 @@ type DBSession struct {
 	*xorm.Session
 	transactionOpen bool
 	events          []any
+	afterCommit     []func()
 }
 
 type DBTransactionFunc func(sess *DBSession) error
@@ -38,6 +39,12 @@ func (sess *DBSession) PublishAfterCommit(msg any) {
 	sess.events = append(sess.events, msg)
 }
 
+// AfterCommit registers fn to be called once the transaction of the session is committed.
+// Nothing is called if the transaction is rolled back.
+func (sess *DBSession) AfterCommit(fn func()) {
+	sess.afterCommit = append(sess.afterCommit, fn)
+}
+
 func startSessionOrUseExisting(ctx context.Context, engine *xorm.Engine, beginTran bool, tracer tracing.Tracer) (*DBSession, bool, trace.Span, error) {
 	value := ctx.Value(ContextSessionKey{})
 	var sess *DBSession
@@ -91,5 +91,9 @@ func (ss *SQLStore) inTransactionWithRetryCtx(ctx context.Context, engine *xorm.
 		}
 	}
 
+	for _, fn := range sess.afterCommit {
+		fn()
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package setting
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -60,6 +61,16 @@ const (
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
 	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
 	stateHistoryDefaultEnabled    = true
+
+	// RuleTitleUniquenessFolder requires unique rule titles within a folder.
+	RuleTitleUniquenessFolder = "folder"
+	// RuleTitleUniquenessGroup requires unique rule titles within a rule group.
+	RuleTitleUniquenessGroup = "group"
+
+	// ExpiredRuleActionPause pauses the alert rules that expired.
+	ExpiredRuleActionPause = "pause"
+	// ExpiredRuleActionDelete deletes the alert rules that expired.
+	ExpiredRuleActionDelete = "delete"
 )
 
 type UnifiedAlertingSettings struct {
@@ -97,10 +108,62 @@ type UnifiedAlertingSettings struct {
 	StateHistory                  UnifiedAlertingStateHistorySettings
 	RemoteAlertmanager            RemoteAlertmanagerSettings
 	Upgrade                       UnifiedAlertingUpgradeSettings
+	SecretReferences              UnifiedAlertingSecretReferencesSettings
+	ProvisioningWebhook           UnifiedAlertingProvisioningWebhookSettings
+	RuleSync                      UnifiedAlertingRuleSyncSettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency   int
 	StatePeriodicSaveInterval time.Duration
 	RulesPerRuleGroupLimit    int64
+	// RuleGroupChangesLimit is the maximum number of rules created, updated and deleted by a single rule group update,
+	// 0 for no limit. Rule group jobs apply larger updates in transactions of at most this number of changes.
+	RuleGroupChangesLimit int64
+	// RuleGroupLockTimeout is how long a change of a rule group waits for the concurrent changes of the same group to
+	// finish before it fails, 0 to wait as long as the database does.
+	RuleGroupLockTimeout time.Duration
+	// QuotaExemptProvenances contains the provenances of alert rules that do not count towards the alert rule quota.
+	QuotaExemptProvenances []string
+	// QuotaExemptRulesLimit is the maximum number of rules with an exempt provenance per organization, -1 for no limit.
+	QuotaExemptRulesLimit int64
+	// RuleTitleUniqueness is the scope in which the titles of provisioned alert rules must be unique, one of
+	// RuleTitleUniquenessFolder and RuleTitleUniquenessGroup, or empty if titles do not need to be unique.
+	RuleTitleUniqueness string
+	// RuleTitleUniquenessFolders contains the UIDs of the folders in which RuleTitleUniqueness is enforced,
+	// empty for all folders.
+	RuleTitleUniquenessFolders []string
+	// DeterministicRuleUIDs tells whether the UIDs of the alert rules created without a UID are derived from their
+	// organization, folder, group and title instead of being random. See models.DeterministicAlertRuleUID.
+	DeterministicRuleUIDs bool
+	// ProvisioningStrictDecoding tells whether the alerting provisioning files with unknown fields are invalid. Unknown
+	// fields are only logged otherwise.
+	ProvisioningStrictDecoding bool
+	// RuleMaxSizeBytes is the maximum size of a provisioned alert rule serialized to JSON, 0 for no limit.
+	RuleMaxSizeBytes int
+	// RuleMaxQueries is the maximum number of queries and expressions of a provisioned alert rule, 0 for no limit.
+	RuleMaxQueries int
+	// RuleMaxExpressionDepth is the maximum length of a chain of expressions of a provisioned alert rule, 0 for no limit.
+	RuleMaxExpressionDepth int
+	// MaxRuleEvaluationTimeout is the longest evaluation timeout that an alert rule can have instead of
+	// EvaluationTimeout.
+	MaxRuleEvaluationTimeout time.Duration
+	// ConfigSnapshotInterval is the interval at which the alerting configuration of each organization is saved in a
+	// snapshot, 0 to disable the snapshots.
+	ConfigSnapshotInterval time.Duration
+	// ConfigSnapshotRetention is the age after which snapshots are deleted. The latest snapshot of an organization is
+	// always kept.
+	ConfigSnapshotRetention time.Duration
+	// ExpiredRuleCheckInterval is the interval at which the expired alert rules are looked for, 0 to never expire rules.
+	ExpiredRuleCheckInterval time.Duration
+	// ExpiredRuleAction is what is done to the expired alert rules, ExpiredRuleActionPause or ExpiredRuleActionDelete.
+	ExpiredRuleAction string
+	// AlertRuleTrashRetention is the age after which deleted alert rules are removed from the trash, 0 to delete rules
+	// permanently.
+	AlertRuleTrashRetention time.Duration
+	// SchedulerShard is the shard of the scheduler of this instance. The scheduler evaluates only the rule groups whose
+	// shard affinity is this shard, which is empty for the groups that are not pinned to a shard.
+	SchedulerShard string
+	// SchedulerShards contains the shards that rule groups can be pinned to. Rule groups cannot be pinned if it is empty.
+	SchedulerShards []string
 }
 
 // RemoteAlertmanagerSettings contains the configuration needed
@@ -143,6 +206,76 @@ type UnifiedAlertingStateHistorySettings struct {
 type UnifiedAlertingUpgradeSettings struct {
 	// CleanUpgrade controls whether the upgrade process should clean up UA data when upgrading from legacy alerting.
 	CleanUpgrade bool
+	// ExpressionConditions controls whether legacy alert conditions are upgraded to reduce, threshold and math
+	// expressions instead of a single classic condition, when all of their reducers and evaluators have an equivalent.
+	ExpressionConditions bool
+	// DeduplicateChannels controls whether legacy channels with identical settings are upgraded to a single contact point.
+	DeduplicateChannels bool
+}
+
+// UnifiedAlertingSecretReferencesSettings configures the external secret managers that the secure settings of contact
+// points can refer to instead of holding the secrets.
+type UnifiedAlertingSecretReferencesSettings struct {
+	// VaultAddress is the address of the Vault server, empty if secrets cannot refer to Vault.
+	VaultAddress   string
+	VaultToken     string
+	VaultNamespace string
+	// AWSSecretsManagerEnabled controls whether secrets can refer to AWS Secrets Manager. The credentials are found
+	// with the default credential chain of the AWS SDK.
+	AWSSecretsManagerEnabled bool
+	// CacheTTL is how long a resolved secret is reused before it is fetched again.
+	CacheTTL time.Duration
+	// AllowedPrefixes contains the prefixes of the Vault paths and of the AWS ARNs of the secrets that the contact points
+	// of all organizations can refer to. Secrets cannot be referred to if there are no allowed prefixes.
+	AllowedPrefixes []string
+	// OrgAllowedPrefixes contains the prefixes that the contact points of an organization can refer to in addition to
+	// AllowedPrefixes, by organization ID.
+	OrgAllowedPrefixes map[int64][]string
+}
+
+// AllowedPrefixesForOrg returns the prefixes of the secrets that the contact points of the organization can refer to.
+func (s UnifiedAlertingSecretReferencesSettings) AllowedPrefixesForOrg(orgID int64) []string {
+	return append(slices.Clone(s.AllowedPrefixes), s.OrgAllowedPrefixes[orgID]...)
+}
+
+// The types of the events posted to the provisioning webhook.
+const (
+	ProvisioningWebhookEventAlertRuleCreated  = "alert_rule_created"
+	ProvisioningWebhookEventAlertRuleUpdated  = "alert_rule_updated"
+	ProvisioningWebhookEventAlertRuleDeleted  = "alert_rule_deleted"
+	ProvisioningWebhookEventRuleGroupReplaced = "rule_group_replaced"
+)
+
+var provisioningWebhookEvents = []string{
+	ProvisioningWebhookEventAlertRuleCreated,
+	ProvisioningWebhookEventAlertRuleUpdated,
+	ProvisioningWebhookEventAlertRuleDeleted,
+	ProvisioningWebhookEventRuleGroupReplaced,
+}
+
+// UnifiedAlertingProvisioningWebhookSettings configures the webhook that is called when alert rules are provisioned.
+type UnifiedAlertingProvisioningWebhookSettings struct {
+	// URL is the URL the events are posted to, empty if the webhook is disabled.
+	URL string
+	// Secret signs the events with HMAC-SHA256 if it is not empty.
+	Secret string
+	// Events are the types of the events that are posted, all of them if it is empty.
+	Events []string
+	// MaxAttempts is the number of times an event is posted before it is stored as a dead letter.
+	MaxAttempts int
+	// Timeout is the timeout of each attempt.
+	Timeout time.Duration
+}
+
+// UnifiedAlertingRuleSyncSettings configures the sync of the alert rules of a directory, e.g. a checkout of a Git
+// repository, into the rule store.
+type UnifiedAlertingRuleSyncSettings struct {
+	// Path is the directory of the rule group files, empty if the sync is disabled.
+	Path string
+	// Interval is the time between two syncs.
+	Interval time.Duration
+	// Prune controls whether the groups that are removed from the files are deleted, only if they were synced from them.
+	Prune bool
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -261,6 +394,14 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.EvaluationTimeout = uaEvaluationTimeout
 
+	uaCfg.MaxRuleEvaluationTimeout, err = gtime.ParseDuration(valueAsString(ua, "max_rule_evaluation_timeout", uaCfg.EvaluationTimeout.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.MaxRuleEvaluationTimeout < 0 {
+		return fmt.Errorf("value of setting 'max_rule_evaluation_timeout' should not be negative")
+	}
+
 	uaCfg.MaxAttempts = ua.Key("max_attempts").MustInt64(schedulerDefaultMaxAttempts)
 
 	uaCfg.BaseInterval = SchedulerBaseInterval
@@ -318,6 +459,9 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	quotas := iniFile.Section("quota")
 	uaCfg.RulesPerRuleGroupLimit = quotas.Key("alerting_rule_group_rules").MustInt64(100)
+	uaCfg.RuleGroupChangesLimit = quotas.Key("alerting_rule_group_changes").MustInt64(0)
+	uaCfg.QuotaExemptProvenances = util.SplitString(quotas.Key("alerting_rule_exempt_provenances").MustString(""))
+	uaCfg.QuotaExemptRulesLimit = quotas.Key("alerting_rule_exempt_limit").MustInt64(-1)
 
 	remoteAlertmanager := iniFile.Section("remote.alertmanager")
 	uaCfgRemoteAM := RemoteAlertmanagerSettings{
@@ -374,6 +518,68 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	uaCfg.RuleTitleUniqueness = strings.ToLower(strings.TrimSpace(ua.Key("rule_title_uniqueness").MustString("")))
+	switch uaCfg.RuleTitleUniqueness {
+	case "", RuleTitleUniquenessFolder, RuleTitleUniquenessGroup:
+	default:
+		return fmt.Errorf("value of setting 'rule_title_uniqueness' should be one of '%s', '%s' or empty, got '%s'", RuleTitleUniquenessFolder, RuleTitleUniquenessGroup, uaCfg.RuleTitleUniqueness)
+	}
+	uaCfg.RuleTitleUniquenessFolders = util.SplitString(ua.Key("rule_title_uniqueness_folders").MustString(""))
+	uaCfg.DeterministicRuleUIDs = ua.Key("deterministic_rule_uids").MustBool(false)
+	uaCfg.ProvisioningStrictDecoding = ua.Key("provisioning_strict_decoding").MustBool(false)
+
+	uaCfg.RuleMaxSizeBytes = ua.Key("rule_max_size_bytes").MustInt(0)
+	uaCfg.RuleMaxQueries = ua.Key("rule_max_queries").MustInt(0)
+	uaCfg.RuleMaxExpressionDepth = ua.Key("rule_max_expression_depth").MustInt(0)
+	if uaCfg.RuleMaxSizeBytes < 0 || uaCfg.RuleMaxQueries < 0 || uaCfg.RuleMaxExpressionDepth < 0 {
+		return fmt.Errorf("values of settings 'rule_max_size_bytes', 'rule_max_queries' and 'rule_max_expression_depth' should not be negative")
+	}
+
+	uaCfg.SchedulerShard = strings.TrimSpace(ua.Key("scheduler_shard").MustString(""))
+	uaCfg.SchedulerShards = util.SplitString(ua.Key("scheduler_shards").MustString(""))
+
+	uaCfg.ConfigSnapshotInterval, err = gtime.ParseDuration(valueAsString(ua, "config_snapshot_interval", "0s"))
+	if err != nil {
+		return err
+	}
+	uaCfg.ConfigSnapshotRetention, err = gtime.ParseDuration(valueAsString(ua, "config_snapshot_retention", (7 * 24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.ConfigSnapshotInterval < 0 || uaCfg.ConfigSnapshotRetention < 0 {
+		return fmt.Errorf("values of settings 'config_snapshot_interval' and 'config_snapshot_retention' should not be negative")
+	}
+
+	uaCfg.ExpiredRuleCheckInterval, err = gtime.ParseDuration(valueAsString(ua, "expired_rule_check_interval", time.Minute.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.ExpiredRuleCheckInterval < 0 {
+		return fmt.Errorf("value of setting 'expired_rule_check_interval' should not be negative")
+	}
+	uaCfg.ExpiredRuleAction = ua.Key("expired_rule_action").MustString(ExpiredRuleActionPause)
+	switch uaCfg.ExpiredRuleAction {
+	case ExpiredRuleActionPause, ExpiredRuleActionDelete:
+	default:
+		return fmt.Errorf("value of setting 'expired_rule_action' should be '%s' or '%s', got '%s'", ExpiredRuleActionPause, ExpiredRuleActionDelete, uaCfg.ExpiredRuleAction)
+	}
+
+	uaCfg.AlertRuleTrashRetention, err = gtime.ParseDuration(valueAsString(ua, "alert_rule_trash_retention", (7 * 24 * time.Hour).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.AlertRuleTrashRetention < 0 {
+		return fmt.Errorf("value of setting 'alert_rule_trash_retention' should not be negative")
+	}
+
+	uaCfg.RuleGroupLockTimeout, err = gtime.ParseDuration(valueAsString(ua, "rule_group_lock_timeout", (10 * time.Second).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.RuleGroupLockTimeout < 0 {
+		return fmt.Errorf("value of setting 'rule_group_lock_timeout' should not be negative")
+	}
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
 	uaCfg.StatePeriodicSaveInterval, err = gtime.ParseDuration(valueAsString(ua, "state_periodic_save_interval", (time.Minute * 5).String()))
@@ -383,10 +589,83 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 
 	upgrade := iniFile.Section("unified_alerting.upgrade")
 	uaCfgUpgrade := UnifiedAlertingUpgradeSettings{
-		CleanUpgrade: upgrade.Key("clean_upgrade").MustBool(false),
+		CleanUpgrade:         upgrade.Key("clean_upgrade").MustBool(false),
+		ExpressionConditions: upgrade.Key("expression_conditions").MustBool(false),
+		DeduplicateChannels:  upgrade.Key("deduplicate_channels").MustBool(false),
 	}
 	uaCfg.Upgrade = uaCfgUpgrade
 
+	secretReferences := iniFile.Section("unified_alerting.secret_references")
+	uaCfg.SecretReferences = UnifiedAlertingSecretReferencesSettings{
+		VaultAddress:             strings.TrimSuffix(secretReferences.Key("vault_address").MustString(""), "/"),
+		VaultToken:               secretReferences.Key("vault_token").MustString(""),
+		VaultNamespace:           secretReferences.Key("vault_namespace").MustString(""),
+		AWSSecretsManagerEnabled: secretReferences.Key("aws_secrets_manager_enabled").MustBool(false),
+	}
+	uaCfg.SecretReferences.CacheTTL, err = gtime.ParseDuration(valueAsString(secretReferences, "cache_ttl", (5 * time.Minute).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.SecretReferences.CacheTTL < 0 {
+		return fmt.Errorf("value of setting 'cache_ttl' should not be negative")
+	}
+	uaCfg.SecretReferences.AllowedPrefixes = util.SplitString(secretReferences.Key("allowed_prefixes").MustString(""))
+	uaCfg.SecretReferences.OrgAllowedPrefixes = make(map[int64][]string)
+	for _, key := range secretReferences.Keys() {
+		// The prefixes of an organization are set with org_<org ID>_allowed_prefixes.
+		orgIDString, ok := strings.CutPrefix(key.Name(), "org_")
+		if !ok {
+			continue
+		}
+		if orgIDString, ok = strings.CutSuffix(orgIDString, "_allowed_prefixes"); !ok {
+			continue
+		}
+		orgID, err := strconv.ParseInt(orgIDString, 10, 64)
+		if err != nil {
+			return fmt.Errorf("setting '%s' should have the ID of an organization: %w", key.Name(), err)
+		}
+		uaCfg.SecretReferences.OrgAllowedPrefixes[orgID] = util.SplitString(key.MustString(""))
+	}
+
+	provisioningWebhook := iniFile.Section("unified_alerting.provisioning_webhook")
+	uaCfg.ProvisioningWebhook = UnifiedAlertingProvisioningWebhookSettings{
+		URL:         provisioningWebhook.Key("url").MustString(""),
+		Secret:      provisioningWebhook.Key("secret").MustString(""),
+		MaxAttempts: provisioningWebhook.Key("max_attempts").MustInt(3),
+	}
+	for _, event := range splitTrim(provisioningWebhook.Key("events").MustString(""), ",") {
+		if event == "" {
+			continue
+		}
+		if !slices.Contains(provisioningWebhookEvents, event) {
+			return fmt.Errorf("value '%s' of setting 'events' should be one of %s", event, strings.Join(provisioningWebhookEvents, ", "))
+		}
+		uaCfg.ProvisioningWebhook.Events = append(uaCfg.ProvisioningWebhook.Events, event)
+	}
+	if uaCfg.ProvisioningWebhook.MaxAttempts < 1 {
+		return fmt.Errorf("value of setting 'max_attempts' should be greater than 0")
+	}
+	uaCfg.ProvisioningWebhook.Timeout, err = gtime.ParseDuration(valueAsString(provisioningWebhook, "timeout", (10 * time.Second).String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.ProvisioningWebhook.Timeout <= 0 {
+		return fmt.Errorf("value of setting 'timeout' should be greater than 0")
+	}
+
+	ruleSync := iniFile.Section("unified_alerting.rule_sync")
+	uaCfg.RuleSync = UnifiedAlertingRuleSyncSettings{
+		Path:  ruleSync.Key("path").MustString(""),
+		Prune: ruleSync.Key("prune").MustBool(false),
+	}
+	uaCfg.RuleSync.Interval, err = gtime.ParseDuration(valueAsString(ruleSync, "interval", time.Minute.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.RuleSync.Interval <= 0 {
+		return fmt.Errorf("value of setting 'interval' should be greater than 0")
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
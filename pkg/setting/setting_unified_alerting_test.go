@@ -72,6 +72,128 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 			require.Equal(t, SchedulerBaseInterval, cfg.UnifiedAlerting.BaseInterval)
 		})
 	})
+
+	t.Run("should read 'rule_title_uniqueness'", func(t *testing.T) {
+		require.Empty(t, cfg.UnifiedAlerting.RuleTitleUniqueness)
+
+		s, err := cfg.Raw.NewSection("unified_alerting")
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			s.DeleteKey("rule_title_uniqueness")
+			s.DeleteKey("rule_title_uniqueness_folders")
+		})
+		_, err = s.NewKey("rule_title_uniqueness", "Folder")
+		require.NoError(t, err)
+		_, err = s.NewKey("rule_title_uniqueness_folders", "folder1, folder2")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, RuleTitleUniquenessFolder, cfg.UnifiedAlerting.RuleTitleUniqueness)
+		require.Equal(t, []string{"folder1", "folder2"}, cfg.UnifiedAlerting.RuleTitleUniquenessFolders)
+
+		t.Run("and fail if it is wrong", func(t *testing.T) {
+			_, err = s.NewKey("rule_title_uniqueness", "dashboard")
+			require.NoError(t, err)
+
+			require.ErrorContains(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw), "rule_title_uniqueness")
+		})
+	})
+
+	t.Run("should read 'unified_alerting.provisioning_webhook'", func(t *testing.T) {
+		require.Empty(t, cfg.UnifiedAlerting.ProvisioningWebhook.URL)
+		require.Equal(t, 3, cfg.UnifiedAlerting.ProvisioningWebhook.MaxAttempts)
+		require.Equal(t, 10*time.Second, cfg.UnifiedAlerting.ProvisioningWebhook.Timeout)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.provisioning_webhook")
+		require.NoError(t, err)
+		_, err = s.NewKey("url", "https://hooks.example.com/alerting")
+		require.NoError(t, err)
+		_, err = s.NewKey("events", "alert_rule_created, alert_rule_deleted,")
+		require.NoError(t, err)
+		_, err = s.NewKey("max_attempts", "5")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, "https://hooks.example.com/alerting", cfg.UnifiedAlerting.ProvisioningWebhook.URL)
+		require.Equal(t, []string{"alert_rule_created", "alert_rule_deleted"}, cfg.UnifiedAlerting.ProvisioningWebhook.Events)
+		require.Equal(t, 5, cfg.UnifiedAlerting.ProvisioningWebhook.MaxAttempts)
+
+		t.Run("and fail if an event type is unknown", func(t *testing.T) {
+			_, err = s.NewKey("events", "alert_rule_created, alert_rule_create")
+			require.NoError(t, err)
+
+			require.ErrorContains(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw), "alert_rule_create'")
+			_, err = s.NewKey("events", "")
+			require.NoError(t, err)
+		})
+
+		t.Run("and fail if the number of attempts is not positive", func(t *testing.T) {
+			_, err = s.NewKey("max_attempts", "0")
+			require.NoError(t, err)
+
+			require.ErrorContains(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw), "max_attempts")
+		})
+		cfg.Raw.DeleteSection("unified_alerting.provisioning_webhook")
+	})
+
+	t.Run("should read 'unified_alerting.rule_sync'", func(t *testing.T) {
+		require.Empty(t, cfg.UnifiedAlerting.RuleSync.Path)
+		require.Equal(t, time.Minute, cfg.UnifiedAlerting.RuleSync.Interval)
+		require.False(t, cfg.UnifiedAlerting.RuleSync.Prune)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.rule_sync")
+		require.NoError(t, err)
+		_, err = s.NewKey("path", "/var/lib/grafana/rules")
+		require.NoError(t, err)
+		_, err = s.NewKey("interval", "30s")
+		require.NoError(t, err)
+		_, err = s.NewKey("prune", "true")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, "/var/lib/grafana/rules", cfg.UnifiedAlerting.RuleSync.Path)
+		require.Equal(t, 30*time.Second, cfg.UnifiedAlerting.RuleSync.Interval)
+		require.True(t, cfg.UnifiedAlerting.RuleSync.Prune)
+
+		t.Run("and fail if the interval is not positive", func(t *testing.T) {
+			_, err = s.NewKey("interval", "0s")
+			require.NoError(t, err)
+
+			require.ErrorContains(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw), "interval")
+		})
+		cfg.Raw.DeleteSection("unified_alerting.rule_sync")
+	})
+
+	t.Run("should read 'unified_alerting.secret_references'", func(t *testing.T) {
+		require.Equal(t, 5*time.Minute, cfg.UnifiedAlerting.SecretReferences.CacheTTL)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.secret_references")
+		require.NoError(t, err)
+		_, err = s.NewKey("vault_address", "https://vault.example.com/")
+		require.NoError(t, err)
+		_, err = s.NewKey("aws_secrets_manager_enabled", "true")
+		require.NoError(t, err)
+		_, err = s.NewKey("cache_ttl", "1m")
+		require.NoError(t, err)
+		_, err = s.NewKey("allowed_prefixes", "secret/data/alerting/")
+		require.NoError(t, err)
+		_, err = s.NewKey("org_2_allowed_prefixes", "secret/data/team-b/, arn:aws:secretsmanager:eu-west-1:123456789012:secret:team-b/")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, "https://vault.example.com", cfg.UnifiedAlerting.SecretReferences.VaultAddress)
+		require.True(t, cfg.UnifiedAlerting.SecretReferences.AWSSecretsManagerEnabled)
+		require.Equal(t, time.Minute, cfg.UnifiedAlerting.SecretReferences.CacheTTL)
+		require.Equal(t, []string{"secret/data/alerting/"}, cfg.UnifiedAlerting.SecretReferences.AllowedPrefixesForOrg(1))
+		require.Equal(t, []string{"secret/data/alerting/", "secret/data/team-b/", "arn:aws:secretsmanager:eu-west-1:123456789012:secret:team-b/"}, cfg.UnifiedAlerting.SecretReferences.AllowedPrefixesForOrg(2))
+
+		t.Run("and fail if the cache TTL is negative", func(t *testing.T) {
+			_, err = s.NewKey("cache_ttl", "-1m")
+			require.NoError(t, err)
+
+			require.ErrorContains(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw), "cache_ttl")
+		})
+	})
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
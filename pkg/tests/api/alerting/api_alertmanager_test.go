@@ -770,6 +770,7 @@ func TestIntegrationDeleteFolderWithRules(t *testing.T) {
 								],
 								"updated": "2021-05-19T19:47:55Z",
 								"intervalSeconds": 60,
+								"effectivePendingPeriod": "2m",
 								"is_paused": false,
 								"version": 1,
 								"uid": "",
@@ -1247,6 +1248,7 @@ func TestIntegrationAlertRuleCRUD(t *testing.T) {
 						  ],
 						  "updated":"2021-02-21T01:10:30Z",
 						  "intervalSeconds":60,
+						  "effectivePendingPeriod":"1m",
 						  "is_paused": false,
 						  "version":1,
 						  "uid":"uid",
@@ -1591,6 +1593,7 @@ func TestIntegrationAlertRuleCRUD(t *testing.T) {
 		                  ],
 		                  "updated":"2021-02-21T01:10:30Z",
 		                  "intervalSeconds":60,
+		                  "effectivePendingPeriod":"1m",
 		                  "is_paused": false,
 		                  "version":2,
 		                  "uid":"uid",
@@ -1700,6 +1703,7 @@ func TestIntegrationAlertRuleCRUD(t *testing.T) {
 					  ],
 					  "updated":"2021-02-21T01:10:30Z",
 					  "intervalSeconds":60,
+					  "effectivePendingPeriod":"1m",
 					  "is_paused":false,
 					  "version":3,
 					  "uid":"uid",
@@ -1788,6 +1792,7 @@ func TestIntegrationAlertRuleCRUD(t *testing.T) {
 					  ],
 					  "updated":"2021-02-21T01:10:30Z",
 					  "intervalSeconds":60,
+					  "effectivePendingPeriod":"1m",
 					  "is_paused":false,
 					  "version":3,
 					  "uid":"uid",
@@ -2243,6 +2248,7 @@ func TestIntegrationQuota(t *testing.T) {
 						  ],
 						  "updated":"2021-02-21T01:10:30Z",
 						  "intervalSeconds":60,
+						  "effectivePendingPeriod":"2m",
 						  "is_paused": false,
 						  "version":2,
 						  "uid":"uid",
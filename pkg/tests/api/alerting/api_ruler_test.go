@@ -1015,6 +1015,7 @@ func TestIntegrationRulerRulesFilterByDashboard(t *testing.T) {
 				}],
 				"updated": "2021-02-21T01:10:30Z",
 				"intervalSeconds": 60,
+				"effectivePendingPeriod": "1m",
 				"is_paused": false,
 				"version": 1,
 				"uid": "uid",
@@ -1093,6 +1094,7 @@ func TestIntegrationRulerRulesFilterByDashboard(t *testing.T) {
 				}],
 				"updated": "2021-02-21T01:10:30Z",
 				"intervalSeconds": 60,
+				"effectivePendingPeriod": "1m",
 				"is_paused": false,
 				"version": 1,
 				"uid": "uid",
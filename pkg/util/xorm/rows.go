@@ -109,6 +109,8 @@ func (rows *Rows) Scan(bean any) error {
 
 // Close session if session.IsAutoClose is true, and claimed any opened resources
 func (rows *Rows) Close() error {
+	// The conditions of the query must not be applied to the next queries of the session, like after Find.
+	defer rows.session.resetStatement()
 	if rows.session.isAutoClose {
 		defer rows.session.Close()
 	}
@@ -12,6 +12,16 @@ import (
 
 // Bind deserializes JSON payload from the request
 func Bind(req *http.Request, v any) error {
+	return bind(req, v, false)
+}
+
+// BindStrict deserializes JSON payload from the request like Bind, but fails if the payload has fields that v does not
+// have.
+func BindStrict(req *http.Request, v any) error {
+	return bind(req, v, true)
+}
+
+func bind(req *http.Request, v any, strict bool) error {
 	if req.Body != nil {
 		m, _, err := mime.ParseMediaType(req.Header.Get("Content-type"))
 		if err != nil {
@@ -21,7 +31,11 @@ func Bind(req *http.Request, v any) error {
 			return errors.New("bad content type")
 		}
 		defer func() { _ = req.Body.Close() }()
-		err = json.NewDecoder(req.Body).Decode(v)
+		decoder := json.NewDecoder(req.Body)
+		if strict {
+			decoder.DisallowUnknownFields()
+		}
+		err = decoder.Decode(v)
 		if err != nil && !errors.Is(err, io.EOF) {
 			return err
 		}
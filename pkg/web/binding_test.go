@@ -2,6 +2,8 @@ package web
 
 import (
 	"errors"
+	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -116,3 +118,25 @@ func TestValidationFailure(t *testing.T) {
 		}
 	}
 }
+
+func TestBindStrict(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	var v StructWithInt
+	if err := Bind(newRequest(`{"A": 1, "B": 2}`), &v); err != nil {
+		t.Error("Bind should ignore unknown fields:", err)
+	}
+	if err := BindStrict(newRequest(`{"A": 1}`), &v); err != nil {
+		t.Error("BindStrict failed:", err)
+	}
+	if err := BindStrict(newRequest(`{"A": 1, "B": 2}`), &v); err == nil {
+		t.Error("BindStrict should fail on unknown fields")
+	}
+}
@@ -14,6 +14,7 @@ export interface OrgMigrationSummary {
   newDashboards: number;
   newAlerts: number;
   newChannels: number;
+  mergedChannels: number;
   removed: boolean;
   hasErrors: boolean;
 }
@@ -52,6 +53,7 @@ export interface AlertPair {
 export interface ContactPair {
   legacyChannel: LegacyChannel;
   contactPoint?: ContactPointUpgrade;
+  mergedIntoChannelId?: number;
   provisioned: boolean;
   error?: string;
 